@@ -5,33 +5,25 @@ import (
 	"log"
 	"time"
 
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
-	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
-	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
 )
 
 func main() {
 	fmt.Println("🔐 BBS+ Age Verification Demo (18+ without revealing exact age/DOB)")
 	fmt.Println("==================================================================")
 
-	// Initialize services
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
-	bbsService := bbs.NewService()
-	credRepo := vc.NewInMemoryCredentialRepository()
-	presRepo := vc.NewInMemoryPresentationRepository()
-	vcService := vc.NewService(bbsService, credRepo, presRepo)
-
-	// Initialize use cases
-	issuerUC := issuer.NewUseCase(didService, vcService, bbsService)
-	holderUC := holder.NewUseCase(didService, vcService, credRepo)
-	verifierUC := verifier.NewUseCase(didService, vcService, presRepo)
+	// Each role keeps its own storage; the holder only sees the credentials handed to it
+	stack, err := sdk.NewStack(sdk.Config{})
+	if err != nil {
+		log.Fatalf("Failed to initialize services: %v", err)
+	}
 
 	// Demo scenario
-	if err := runAgeVerificationDemo(issuerUC, holderUC, verifierUC); err != nil {
+	if err := runAgeVerificationDemo(stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase); err != nil {
 		log.Fatalf("Age verification demo failed: %v", err)
 	}
 
@@ -123,7 +115,10 @@ func runAgeVerificationDemo(issuerUC *issuer.UseCase, holderUC *holder.UseCase,
 	fmt.Println("  - Verify nationality for regional content")
 	fmt.Println("  - Does NOT need: exact age, birth date, name, address, ID number")
 
-	verificationNonce := fmt.Sprintf("gaming-age-verification-%d", time.Now().UnixMilli())
+	verificationNonce, err := verifierUC.GenerateNonce()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification nonce: %w", err)
+	}
 	fmt.Printf("  Generated verification nonce: %s\n", verificationNonce)
 
 	// Step 7: Citizen creates selective disclosure presentation (Privacy-Preserving)
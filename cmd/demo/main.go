@@ -6,33 +6,25 @@ import (
 	"log"
 	"time"
 
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
-	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
-	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
 )
 
 func main() {
 	fmt.Println("🔐 BBS+ Selective Disclosure Demo")
 	fmt.Println("=====================================")
 
-	// Initialize services
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
-	bbsService := bbs.NewService()
-	credRepo := vc.NewInMemoryCredentialRepository()
-	presRepo := vc.NewInMemoryPresentationRepository()
-	vcService := vc.NewService(bbsService, credRepo, presRepo)
-
-	// Initialize use cases
-	issuerUC := issuer.NewUseCase(didService, vcService, bbsService)
-	holderUC := holder.NewUseCase(didService, vcService, credRepo)
-	verifierUC := verifier.NewUseCase(didService, vcService, presRepo)
+	// Each role keeps its own storage; the holder only sees the credentials handed to it
+	stack, err := sdk.NewStack(sdk.Config{})
+	if err != nil {
+		log.Fatalf("Failed to initialize services: %v", err)
+	}
 
 	// Demo scenario
-	if err := runDemo(issuerUC, holderUC, verifierUC); err != nil {
+	if err := runDemo(stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase); err != nil {
 		log.Fatalf("Demo failed: %v", err)
 	}
 
@@ -104,7 +96,10 @@ func runDemo(issuerUC *issuer.UseCase, holderUC *holder.UseCase, verifierUC *ver
 	fmt.Println("  - Does NOT need: firstName, lastName, address, idNumber")
 
 	// Generate verification nonce
-	verificationNonce := "cinema-verification-" + fmt.Sprintf("%d", time.Now().UnixMilli())
+	verificationNonce, err := verifierUC.GenerateNonce()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification nonce: %w", err)
+	}
 	fmt.Printf("  Generated verification nonce: %s\n", verificationNonce)
 
 	// Step 7: Holder creates selective disclosure presentation
@@ -203,19 +198,10 @@ func getClaimKeys(claims []vc.Claim) []string {
 }
 
 func calculateAge(dateOfBirth string) int {
-	// Parse date of birth (format: YYYY-MM-DD)
-	birthTime, err := time.Parse("2006-01-02", dateOfBirth)
+	policy := vc.DatePolicy{}
+	birthDate, err := policy.ParseDate(dateOfBirth)
 	if err != nil {
 		return 0
 	}
-
-	now := time.Now()
-	age := now.Year() - birthTime.Year()
-
-	// Adjust if birthday hasn't occurred this year
-	if now.YearDay() < birthTime.YearDay() {
-		age--
-	}
-
-	return age
+	return policy.AgeAt(birthDate, time.Now())
 }
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+)
+
+const defaultServer = "http://localhost:8089"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "split":
+		err = runSplit(os.Args[2:])
+	case "open":
+		err = runOpen(os.Args[2:])
+	case "recover":
+		err = runRecover(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func usage() {
+	fmt.Println("BBS+ issuer key escrow tool")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  escrow split -custodians <did,did,...> -threshold <k> [-issuer <did>] [-out <dir>] [-server <url>]")
+	fmt.Println("  escrow open -in <share file> -wallet <backup file> -out <file> [-password <password>]")
+	fmt.Println("  escrow recover [-server <url>] <opened share file>...")
+	fmt.Println()
+	fmt.Println("split writes escrow.json and one encrypted share file per custodian. Each custodian")
+	fmt.Println("opens its own share locally with its wallet backup; any threshold of opened shares")
+	fmt.Println("recover the issuer's key. The password may also be supplied via WALLET_PASSWORD.")
+}
+
+// escrowShare is a custodian's encrypted share as returned by the server
+type escrowShare struct {
+	Custodian string          `json:"custodian"`
+	Index     int             `json:"index"`
+	Envelope  json.RawMessage `json:"envelope"`
+}
+
+// runSplit escrows the issuer's key and writes one share file per custodian
+func runSplit(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	server := fs.String("server", defaultServer, "Issuer server URL")
+	issuerDID := fs.String("issuer", "", "Issuer DID (may be omitted when the server has one issuer)")
+	custodians := fs.String("custodians", "", "Comma-separated custodian DIDs")
+	threshold := fs.Int("threshold", 0, "Number of shares needed to recover the key")
+	out := fs.String("out", ".", "Output directory")
+	fs.Parse(args)
+
+	if *custodians == "" || *threshold == 0 {
+		return fmt.Errorf("-custodians and -threshold are required")
+	}
+
+	body, err := post(*server+"/api/issuer/keys/escrow", map[string]interface{}{
+		"issuerDid":  *issuerDID,
+		"custodians": strings.Split(*custodians, ","),
+		"threshold":  *threshold,
+	})
+	if err != nil {
+		return fmt.Errorf("escrow failed: %w", err)
+	}
+
+	var escrow struct {
+		IssuerDID string        `json:"issuerDid"`
+		KeyID     string        `json:"keyId"`
+		Threshold int           `json:"threshold"`
+		Shares    []escrowShare `json:"shares"`
+	}
+	if err := json.Unmarshal(body, &escrow); err != nil {
+		return fmt.Errorf("invalid server response: %w", err)
+	}
+
+	if err := os.MkdirAll(*out, 0o700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(*out, "escrow.json"), body, 0o600); err != nil {
+		return fmt.Errorf("failed to write escrow: %w", err)
+	}
+	for _, share := range escrow.Shares {
+		data, err := json.MarshalIndent(share, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(*out, fmt.Sprintf("share-%d.json", share.Index))
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return fmt.Errorf("failed to write share: %w", err)
+		}
+		fmt.Printf("🔐 Share %d for %s written to %s\n", share.Index, share.Custodian, path)
+	}
+
+	fmt.Printf("✅ Key %s split into %d shares, %d needed to recover\n", escrow.KeyID, len(escrow.Shares), escrow.Threshold)
+	return nil
+}
+
+// runOpen decrypts a custodian's share locally with the key agreement key in the custodian's
+// wallet backup, so neither the share nor the key is sent anywhere
+func runOpen(args []string) error {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	in := fs.String("in", "", "Encrypted share file")
+	wallet := fs.String("wallet", "", "The custodian's wallet backup, as written by wallet export")
+	password := fs.String("password", "", "Backup password (default $WALLET_PASSWORD)")
+	out := fs.String("out", "", "Opened share file")
+	fs.Parse(args)
+
+	if *in == "" || *wallet == "" || *out == "" {
+		return fmt.Errorf("-in, -wallet and -out are required")
+	}
+
+	var share issuer.EscrowShare
+	if err := readJSON(*in, &share); err != nil {
+		return fmt.Errorf("failed to read share: %w", err)
+	}
+	if share.Envelope == nil {
+		return fmt.Errorf("share has no envelope")
+	}
+
+	var archive holder.BackupArchive
+	if err := readJSON(*wallet, &archive); err != nil {
+		return fmt.Errorf("failed to read wallet backup: %w", err)
+	}
+
+	pw := *password
+	if pw == "" {
+		pw = os.Getenv("WALLET_PASSWORD")
+	}
+	if pw == "" {
+		return fmt.Errorf("a password is required (-password or WALLET_PASSWORD)")
+	}
+
+	agreementKey, err := archive.AgreementKey(pw, share.Envelope.RecipientKID)
+	if err != nil {
+		return err
+	}
+	opened, err := issuer.OpenEscrowShare(&share, agreementKey)
+	if err != nil {
+		return fmt.Errorf("open failed: %w", err)
+	}
+
+	data, err := json.MarshalIndent(opened, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write opened share: %w", err)
+	}
+
+	fmt.Printf("✅ Share opened to %s; keep it secret until recovery\n", *out)
+	return nil
+}
+
+// readJSON decodes a JSON file
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// runRecover rebuilds the issuer's key from opened shares
+func runRecover(args []string) error {
+	fs := flag.NewFlagSet("recover", flag.ExitOnError)
+	server := fs.String("server", defaultServer, "Issuer server URL")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("at least one opened share file is required")
+	}
+
+	shares := make([]json.RawMessage, 0, fs.NArg())
+	for _, path := range fs.Args() {
+		share, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read opened share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	body, err := post(*server+"/api/issuer/keys/recover", map[string]interface{}{"shares": shares})
+	if err != nil {
+		return fmt.Errorf("recovery failed: %w", err)
+	}
+
+	var result struct {
+		IssuerDID  string `json:"issuerDid"`
+		KeyID      string `json:"keyId"`
+		SharesUsed int    `json:"sharesUsed"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("invalid server response: %w", err)
+	}
+
+	fmt.Printf("✅ Recovered %s for %s from %d shares\n", result.KeyID, result.IssuerDID, result.SharesUsed)
+	return nil
+}
+
+// post sends a JSON request and returns the response body of a successful call
+func post(url string, payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error   string `json:"error"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("%s: %s", errResp.Error, errResp.Details)
+		}
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	return body, nil
+}
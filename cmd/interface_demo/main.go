@@ -178,7 +178,11 @@ func demonstrateBasicOperations(service bbs.BBSInterface) error {
 	// 6. Create selective disclosure proof
 	// Reveal only name and location, hide other attributes
 	revealedIndices := []int{0, 4} // Name and Location
-	nonce := []byte("demo-proof-2024")
+	generatedNonce, err := bbs.GenerateNonce()
+	if err != nil {
+		return err
+	}
+	nonce := []byte(generatedNonce)
 
 	fmt.Println("4. Creating selective disclosure proof...")
 	fmt.Printf("   Revealing: Name (%s) and Location (%s)\n",
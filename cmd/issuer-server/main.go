@@ -0,0 +1,71 @@
+// Command issuer-server runs the issuer on its own: it exposes only the issuer endpoints, status
+// lists and discovery documents, and publishes its issuers' DID documents for wallets and verifiers
+// running as separate services.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/cli"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+func main() {
+	port := flag.String("port", "8081", "Server port")
+	peers := flag.String("peers", os.Getenv("PEERS"), "Comma-separated base URLs of the wallet and verifier servers, whose DIDs are resolved there, e.g. http://wallet:8082,http://verifier:8083")
+	resolutionCacheTTL := flag.Duration("resolution-cache-ttl", fetch.DefaultTTL, "Cache DID documents resolved at peers for this long (0 disables)")
+	requireApproval := flag.Bool("require-approval", false, "Hold credentials requested through the issuance API until an approver approves them")
+	batchWorkers := flag.Int("batch-workers", issuer.DefaultBatchWorkers, "How many credentials batch issuance jobs sign concurrently")
+	apiTokens := flag.String("api-tokens", os.Getenv("API_TOKENS"), "Comma-separated bearer tokens the API requires, or a secret holding them such as env:API_TOKENS, file:/run/secrets/api_tokens or vault:bbs/server#apiTokens (empty leaves the API open)")
+	webDomain := flag.String("web-domain", "", "Domain this server is reached at, e.g. example.com or localhost:8089; did:web DIDs are then generated under it and served at /users/{id}/did.json (empty serves did:web documents for the request's host)")
+	privateWebhooks := flag.Bool("private-webhooks", false, "Deliver status notifications to wallets at loopback and private network addresses, for wallets on the local network")
+	flag.Parse()
+
+	resolver := cli.SecretsResolver()
+
+	log.Println("🏛️  Initializing BBS+ issuer server")
+
+	stack, err := sdk.NewStack(sdk.Config{
+		ResolutionCacheTTL: *resolutionCacheTTL,
+		WebDomain:          *webDomain,
+		PrivateWebhooks:    *privateWebhooks,
+		ValidateContexts:   true,
+		Logger:             log.Default(),
+		APITokens:          cli.SplitList(*apiTokens),
+		Secrets:            resolver,
+		Roles:              []api.Role{api.RoleIssuer},
+		Peers:              cli.SplitList(*peers),
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	stack.Issuer.SetApprovalRequired(*requireApproval)
+	if err := stack.Issuer.SetBatchWorkers(*batchWorkers); err != nil {
+		log.Fatalf("❌ Invalid batch issuance configuration: %v", err)
+	}
+
+	server, err := httpServer.NewStackServer(stack, *port)
+	if err != nil {
+		log.Fatalf("❌ Invalid API configuration: %v", err)
+	}
+
+	readiness := health.NewReadiness()
+	if err := readiness.Go("generators", bbs.Warmup); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	server.SetReadiness(readiness)
+
+	if err := server.Start(); err != nil {
+		log.Printf("❌ Server failed to start: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// client calls the server's JSON API
+type client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// newClient returns a client whose connection pool is large enough that simulated wallets
+// do not queue behind each other for connections
+func newClient(baseURL string, timeout time.Duration) *client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 0
+	transport.MaxIdleConnsPerHost = 1024
+
+	return &client{
+		baseURL: baseURL,
+		http:    &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// post sends a JSON request, decodes a successful response into out when it is not nil,
+// and returns how long the call took
+func (c *client) post(ctx context.Context, path string, payload, out interface{}) (time.Duration, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return time.Since(start), err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	elapsed := time.Since(start)
+	if err != nil {
+		return elapsed, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp struct {
+			Error   string `json:"error"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return elapsed, fmt.Errorf("%s: %s", errResp.Error, errResp.Details)
+		}
+		return elapsed, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return elapsed, fmt.Errorf("invalid server response: %w", err)
+		}
+	}
+
+	return elapsed, nil
+}
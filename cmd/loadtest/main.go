@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/cli"
+)
+
+const defaultServer = "http://localhost:8089"
+
+// wallet is a simulated holder and the credential it presents
+type wallet struct {
+	holderDID    string
+	credentialID string
+}
+
+func main() {
+	server := flag.String("server", defaultServer, "Server URL to load")
+	holders := flag.Int("holders", 20, "Number of simulated holders")
+	concurrency := flag.Int("concurrency", 8, "Number of concurrent presentation/verification flows")
+	rate := flag.Float64("rate", 0, "Flows started per second across all workers (0 runs as fast as possible)")
+	duration := flag.Duration("duration", 30*time.Second, "How long to run the load phase")
+	reveal := flag.String("reveal", "ageOver18", "Comma-separated claims each presentation reveals")
+	timeout := flag.Duration("timeout", 30*time.Second, "Per-request timeout")
+	flag.Parse()
+
+	if *holders < 1 || *concurrency < 1 {
+		log.Fatalf("❌ -holders and -concurrency must be at least 1")
+	}
+	if *rate < 0 {
+		log.Fatalf("❌ -rate cannot be negative")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := newClient(*server, *timeout)
+
+	log.Printf("🔧 Setting up an issuer and %d holders at %s", *holders, *server)
+	issuerDID, wallets, err := setup(ctx, c, *holders, *concurrency)
+	if err != nil {
+		log.Fatalf("❌ Setup failed: %v", err)
+	}
+	log.Printf("✅ Issued %d credentials from %s", len(wallets), issuerDID)
+
+	log.Printf("🚀 Running %d concurrent flows for %s", *concurrency, *duration)
+	report := run(ctx, c, wallets, issuerDID, cli.SplitList(*reveal), *concurrency, *rate, *duration)
+	report.Print(os.Stdout)
+
+	// Every flow presents a valid credential, so a failed verification under concurrent load
+	// means state shared between requests
+	if failures := report.Errors("verify"); failures > 0 && *concurrency > 1 {
+		log.Printf("❌ %d verifications failed at concurrency %d", failures, *concurrency)
+		os.Exit(1)
+	}
+}
+
+// setup creates an issuer and the simulated holders, and issues each holder a credential
+func setup(ctx context.Context, c *client, holders, concurrency int) (string, []wallet, error) {
+	var issuer dto.SetupIssuerResponse
+	if _, err := c.post(ctx, "/api/issuer/setup", dto.SetupIssuerRequest{Method: "example"}, &issuer); err != nil {
+		return "", nil, fmt.Errorf("issuer setup: %w", err)
+	}
+
+	wallets := make([]wallet, holders)
+	errs := make(chan error, holders)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range wallets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			w, err := enroll(ctx, c, issuer.DID, i)
+			if err != nil {
+				errs <- fmt.Errorf("holder %d: %w", i, err)
+				return
+			}
+			wallets[i] = w
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return "", nil, err
+	}
+
+	return issuer.DID, wallets, nil
+}
+
+// enroll creates a holder and stores a freshly issued credential in its wallet
+func enroll(ctx context.Context, c *client, issuerDID string, i int) (wallet, error) {
+	var holder dto.SetupHolderResponse
+	if _, err := c.post(ctx, "/api/holder/setup", dto.SetupHolderRequest{Method: "example"}, &holder); err != nil {
+		return wallet{}, fmt.Errorf("setup: %w", err)
+	}
+
+	var issued dto.IssueCredentialResponse
+	if _, err := c.post(ctx, "/api/issuer/credentials", dto.IssueCredentialRequest{
+		IssuerDID:  issuerDID,
+		SubjectDID: holder.DID,
+		Claims: []dto.ClaimDTO{
+			{Key: "firstName", Value: fmt.Sprintf("Holder%d", i)},
+			{Key: "lastName", Value: "Loadtest"},
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+		},
+	}, &issued); err != nil {
+		return wallet{}, fmt.Errorf("issue: %w", err)
+	}
+
+	if _, err := c.post(ctx, "/api/holder/credentials", dto.StoreCredentialRequest{Credential: issued.Credential}, nil); err != nil {
+		return wallet{}, fmt.Errorf("store: %w", err)
+	}
+
+	return wallet{holderDID: holder.DID, credentialID: issued.CredentialID}, nil
+}
+
+// run drives presentation/verification flows against the server until the duration elapses
+func run(ctx context.Context, c *client, wallets []wallet, issuerDID string, reveal []string, concurrency int, rate float64, duration time.Duration) *Report {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	report := NewReport("present", "verify", "flow")
+
+	// Workers take a token per flow; without a rate the channel is fed as fast as they drain it
+	tokens := make(chan struct{})
+	go func() {
+		defer close(tokens)
+
+		var tick <-chan time.Time
+		if rate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			if tick != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-tick:
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case tokens <- struct{}{}:
+			}
+		}
+	}()
+
+	var next atomic.Uint64
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range tokens {
+				w := wallets[next.Add(1)%uint64(len(wallets))]
+				flow(ctx, c, w, issuerDID, reveal, report)
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Elapsed = time.Since(start)
+	return report
+}
+
+// flow has one holder present its credential with a fresh nonce and the server verify it
+func flow(ctx context.Context, c *client, w wallet, issuerDID string, reveal []string, report *Report) {
+	nonce := uuid.New().String()
+	flowStart := time.Now()
+
+	var presentation dto.CreatePresentationResponse
+	elapsed, err := c.post(ctx, "/api/holder/presentations", dto.CreatePresentationRequest{
+		HolderDID:     w.holderDID,
+		CredentialIDs: []string{w.credentialID},
+		SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+			{CredentialID: w.credentialID, RevealedAttributes: reveal},
+		},
+		Nonce: nonce,
+	}, &presentation)
+	if ctx.Err() != nil {
+		// Flows cut short by the end of the run are not counted
+		return
+	}
+	report.Record("present", elapsed, err)
+	if err != nil {
+		report.Record("flow", time.Since(flowStart), err)
+		return
+	}
+
+	var result dto.VerifyPresentationResponse
+	elapsed, err = c.post(ctx, "/api/verifier/verify", dto.VerifyPresentationRequest{
+		Presentation:      presentation.Presentation,
+		RequiredClaims:    reveal,
+		TrustedIssuers:    []string{issuerDID},
+		VerificationNonce: nonce,
+	}, &result)
+	if ctx.Err() != nil {
+		return
+	}
+	if err == nil && !result.Valid {
+		err = fmt.Errorf("presentation rejected: %s", strings.Join(result.Errors, "; "))
+	}
+	report.Record("verify", elapsed, err)
+	report.Record("flow", time.Since(flowStart), err)
+}
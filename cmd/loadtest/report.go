@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// maxErrorSamples bounds how many distinct error messages a report keeps per operation
+const maxErrorSamples = 5
+
+// Report collects latencies and errors per operation
+type Report struct {
+	Elapsed time.Duration
+
+	mu         sync.Mutex
+	operations []string
+	latencies  map[string][]time.Duration
+	errors     map[string]int
+	samples    map[string][]string
+}
+
+// NewReport returns a report for the given operations, printed in that order
+func NewReport(operations ...string) *Report {
+	return &Report{
+		operations: operations,
+		latencies:  make(map[string][]time.Duration),
+		errors:     make(map[string]int),
+		samples:    make(map[string][]string),
+	}
+}
+
+// Record adds the outcome of one call
+func (r *Report) Record(operation string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.errors[operation]++
+		if len(r.samples[operation]) < maxErrorSamples && !containsString(r.samples[operation], err.Error()) {
+			r.samples[operation] = append(r.samples[operation], err.Error())
+		}
+		return
+	}
+	r.latencies[operation] = append(r.latencies[operation], latency)
+}
+
+// Errors returns how many calls of an operation failed
+func (r *Report) Errors(operation string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.errors[operation]
+}
+
+// Print writes throughput and latency percentiles per operation
+func (r *Report) Print(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fmt.Fprintf(w, "\n📊 Load test finished in %s\n\n", r.Elapsed.Round(time.Millisecond))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\tok\terrors\treq/s\tp50\tp90\tp99\tmax\t")
+	for _, op := range r.operations {
+		latencies := r.latencies[op]
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		throughput := 0.0
+		if r.Elapsed > 0 {
+			throughput = float64(len(latencies)) / r.Elapsed.Seconds()
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n", op, len(latencies), r.errors[op], throughput,
+			percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99), percentile(latencies, 100))
+	}
+	tw.Flush()
+
+	for _, op := range r.operations {
+		for _, sample := range r.samples[op] {
+			fmt.Fprintf(w, "⚠️  %s: %s\n", op, sample)
+		}
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Microsecond)
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
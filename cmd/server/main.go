@@ -1,44 +1,382 @@
 package main
 
 import (
+	"context"
+	"encoding/base64"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/cli"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/anchor"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
-	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/secrets"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
 )
 
+const serviceName = "bbs-selective-disclosure"
+
+// predicateProviders create optional predicate providers; files behind build tags add to it.
+// They are registered ahead of the built-in range provider, so holders prefer them.
+var predicateProviders []func() (predicate.Provider, error)
+
 func main() {
 	// Parse command line flags
 	port := flag.String("port", "8089", "Server port")
+	maxPresentationAge := flag.Duration("max-presentation-age", 5*time.Minute, "Reject presentations older than this (0 disables)")
+	clockSkew := flag.Duration("clock-skew", verifier.DefaultClockSkew, "Tolerated clock difference for presentation timestamps")
+	maxStatusAge := flag.Duration("max-status-age", verifier.DefaultMaxStatusAge, "Accept status snapshots embedded in presentations up to this age (0 always checks the status registry)")
+	anchorBackend := flag.String("anchor-backend", "", "Anchor issuer keys and status lists to: mock, ethereum or timestamp (empty disables)")
+	anchorURL := flag.String("anchor-url", "", "Ethereum JSON-RPC or timestamping service URL")
+	anchorFrom := flag.String("anchor-from", "", "Unlocked Ethereum account that sends anchoring transactions")
+	anchorInterval := flag.Duration("anchor-interval", time.Hour, "How often issuer key sets and status lists are anchored")
+	statusRefreshInterval := flag.Duration("status-refresh-interval", holder.DefaultStatusRefreshInterval, "How often wallets refresh the status snapshots of their credentials (0 disables)")
+	corsOrigins := flag.String("cors-origins", "", "Comma-separated origins allowed to call the API cross-origin, e.g. https://wallet.example.com,https://*.example.org (\"*\" allows any; empty allows none)")
+	corsMethods := flag.String("cors-methods", "GET,POST,OPTIONS", "Comma-separated methods allowed in cross-origin requests")
+	corsHeaders := flag.String("cors-headers", "Content-Type,Authorization", "Comma-separated request headers allowed in cross-origin requests")
+	corsCredentials := flag.Bool("cors-credentials", false, "Allow cross-origin requests with cookies or HTTP authentication")
+	corsMaxAge := flag.Duration("cors-max-age", 10*time.Minute, "How long browsers may cache preflight responses")
+	verifyAnchors := flag.Bool("verify-anchors", false, "Reject credentials whose issuer keys or status lists do not match their anchors")
+	batchWorkers := flag.Int("batch-workers", issuer.DefaultBatchWorkers, "How many credentials batch issuance jobs sign concurrently")
+	issuanceWorkers := flag.Int("issuance-workers", issuer.DefaultIssuanceWorkers, "How many workers issue the credentials queued at /api/issuer/issuance-jobs")
+	issuanceAttempts := flag.Int("issuance-attempts", issuer.DefaultIssuanceAttempts, "How many times a queued issuance is attempted when signing fails for a transient reason, such as an unreachable KMS")
+	issuanceBackoff := flag.Duration("issuance-backoff", issuer.DefaultIssuanceBackoff, "Wait before the first retry of a queued issuance, doubled for each further retry")
+	otlpEndpoint := flag.String("otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "OTLP/HTTP collector base URL traces are exported to, e.g. http://localhost:4318 (empty disables tracing)")
+	sessionTTL := flag.Duration("session-ttl", verifier.DefaultSessionTTL, "How long cross-device presentation sessions wait for the holder's presentation")
+	healthTimeout := flag.Duration("health-timeout", health.DefaultTimeout, "How long each /health component check may run")
+	healthCache := flag.Duration("health-cache", 5*time.Second, "Reuse /health check results for this long (0 runs the checks on every request)")
+	dateTimezone := flag.String("date-timezone", "UTC", "IANA timezone the current date is taken in when computing ages from dates of birth, e.g. Europe/Berlin")
+	validateContexts := flag.Bool("validate-contexts", true, "Reject credentials and presentations that do not expand under their JSON-LD @context")
+	serveContexts := flag.Bool("serve-contexts", false, "Serve the bundled JSON-LD contexts under /contexts for offline deployments")
+	strictVerification := flag.Bool("strict-verification", false, "Report lint findings (weak nonces, extra disclosures, missing expiry, non-canonical JSON) as warnings on every verification")
+	replayCache := flag.Bool("replay-cache", true, "Reject presentations whose proofs were already accepted, for as long as they would pass the freshness check")
+	replayCacheSize := flag.Int("replay-cache-size", replay.DefaultCapacity, "How many accepted presentations the replay cache remembers")
+	replayCacheFile := flag.String("replay-cache-file", "", "Persist the replay cache to this file so it survives restarts (empty keeps it in memory)")
+	resolutionCacheTTL := flag.Duration("resolution-cache-ttl", fetch.DefaultTTL, "Cache resolved DID documents, fetched status lists and trusted domains' DID configurations for this long; remote documents are then revalidated with their ETag (0 disables)")
+	tombstoneRetention := flag.Duration("tombstone-retention", 0, "Keep deleted credentials and deactivated DID documents, with who deleted them, when and why, for this long before purging them (0 keeps them)")
+	traceSampleRatio := flag.Float64("trace-sample-ratio", 1, "Fraction of new traces recorded, between 0 and 1")
+	storageBackend := flag.String("storage", "memory", "Where credentials, presentations, DID documents, sessions and the replay cache are kept: memory or redis")
+	redisAddr := flag.String("redis-addr", "localhost:6379", "Redis server address when -storage=redis")
+	redisPassword := flag.String("redis-password", os.Getenv("REDIS_PASSWORD"), "Redis password, if the server requires one, or a secret holding it such as file:/run/secrets/redis_password")
+	redisDB := flag.Int("redis-db", 0, "Redis database number")
+	redisPrefix := flag.String("redis-prefix", "bbs:", "Prefix for every Redis key, so several deployments can share a server")
+	requireApproval := flag.Bool("require-approval", false, "Hold credentials requested through the issuance API until an approver approves them")
+	proofDiagnostics := flag.Bool("proof-diagnostics", false, "Serve POST /api/bbs/proofs/diagnose, which explains failed proof verifications step by step (echoes revealed messages; for debugging only)")
+	bbsProvider := flag.String("bbs-provider", string(bbs.ProviderProduction), "BBS+ implementation credentials are signed and verified with: production, simple or aries")
+	ariesKMSURL := flag.String("aries-kms-url", "", "Remote KMS the aries provider keeps keys in (empty uses a local KMS)")
+	ariesAuthToken := flag.String("aries-auth-token", "env:ARIES_AUTH_TOKEN", "Secret holding the remote KMS token, such as env:ARIES_AUTH_TOKEN or vault:bbs/aries#token")
+	apiTokens := flag.String("api-tokens", os.Getenv("API_TOKENS"), "Comma-separated bearer tokens the API requires, or a secret holding them such as env:API_TOKENS, file:/run/secrets/api_tokens or vault:bbs/server#apiTokens (empty leaves the API open)")
+	maxProofsPerRequest := flag.Int("max-proofs-per-request", 0, "Reject verification requests carrying more proofs than this with 413 (0 allows any number)")
+	proofsPerMinute := flag.Int("proofs-per-minute", 0, "Proofs each client address may have checked per minute before getting 429 (0 is unlimited)")
+	totalProofsPerMinute := flag.Int("total-proofs-per-minute", 0, "Proofs all clients together may have checked per minute before getting 429 (0 is unlimited)")
+	claimKeys := flag.String("claim-keys", "", "Comma-separated id=key key-encryption keys the claims of stored credentials are encrypted under, the last one current; each key is a base64 AES-256 key or a secret holding one, such as 2025=vault:bbs/claims#2025 (requires -storage=redis; empty stores claims in the clear)")
+	rewrapClaimKeys := flag.Bool("rewrap-claim-keys", false, "On startup, rewrap every stored credential's data key under the current claim key and encrypt claims stored in the clear, so earlier claim keys can be dropped")
+	stateless := flag.Bool("stateless", false, "Also keep private keys, issuer and holder state and status lists in the shared store, so any instance behind a load balancer can serve any request (requires -storage=redis)")
+	webDomain := flag.String("web-domain", "", "Domain this server is reached at, e.g. example.com or localhost:8089; did:web DIDs are then generated under it and served at /users/{id}/did.json (empty serves did:web documents for the request's host)")
+	privateWebhooks := flag.Bool("private-webhooks", false, "Deliver status notifications and forwarded session outcomes to webhooks at loopback and private network addresses, for wallets and relying parties on the local network")
 	flag.Parse()
 
+	resolver := cli.SecretsResolver()
+
 	log.Println("🔐 Initializing BBS+ Selective Disclosure API Server")
 
-	// Initialize services (same as in demo)
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
-	bbsService := bbs.NewService()
-	credRepo := vc.NewInMemoryCredentialRepository()
-	presRepo := vc.NewInMemoryPresentationRepository()
-	vcService := vc.NewService(bbsService, credRepo, presRepo)
+	if *otlpEndpoint != "" {
+		provider, err := tracing.NewProvider(tracing.ProviderConfig{
+			ServiceName: serviceName,
+			Exporter:    tracing.NewOTLPExporter(*otlpEndpoint, serviceName, nil),
+			SampleRatio: *traceSampleRatio,
+		})
+		if err != nil {
+			log.Fatalf("❌ Invalid tracing configuration: %v", err)
+		}
+		tracing.SetProvider(provider)
+		log.Printf("🔭 Exporting traces to %s", *otlpEndpoint)
+	}
+
+	// A shared store lets several server instances serve the same credentials, presentations,
+	// DID documents and sessions; nil keeps everything in this process
+	var kv storage.KVStore
+	var redisStore *storage.RedisStore
+	switch *storageBackend {
+	case "memory":
+	case "redis":
+		password, err := resolver.Resolve(context.Background(), *redisPassword)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		redisStore, err = storage.NewRedisStore(storage.RedisConfig{
+			Addr:     *redisAddr,
+			Password: password,
+			DB:       *redisDB,
+		})
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		defer redisStore.Close()
+		kv = storage.Prefixed(redisStore, *redisPrefix)
+		log.Printf("🗄️  Storing state in Redis at %s (db %d, prefix %q)", *redisAddr, *redisDB, *redisPrefix)
+	default:
+		log.Fatalf("❌ Unknown storage backend %q (expected memory or redis)", *storageBackend)
+	}
+	if *stateless {
+		if kv == nil {
+			log.Fatalf("❌ -stateless requires -storage=redis")
+		}
+		if *replayCacheFile != "" {
+			log.Fatalf("❌ -stateless keeps the replay cache in the shared store; drop -replay-cache-file")
+		}
+		log.Printf("🔓 Stateless mode: private keys are kept in the shared store")
+	}
+	var claimKeyring *vc.Keyring
+	if *claimKeys != "" {
+		if kv == nil {
+			log.Fatalf("❌ -claim-keys requires -storage=redis")
+		}
+		keyring, err := parseClaimKeys(resolver, *claimKeys)
+		if err != nil {
+			log.Fatalf("❌ Invalid claim keys: %v", err)
+		}
+		claimKeyring = keyring
+		log.Printf("🔏 Encrypting stored claims under claim key %s", claimKeyring.Current())
+	}
+	// Every time-based check reads this clock, so a trusted time source can be swapped in here
+	var serverClock clock.Clock = clock.System{}
+
+	var providers []predicate.Provider
+	for _, create := range predicateProviders {
+		provider, err := create()
+		if err != nil {
+			log.Fatalf("❌ Failed to load predicate provider: %v", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	var ariesConfig *bbs.AriesConfig
+	if *ariesKMSURL != "" {
+		ariesConfig = bbs.DefaultConfig().AriesConfig
+		ariesConfig.KMSType = "remote"
+		ariesConfig.RemoteKMSURL = *ariesKMSURL
+		ariesConfig.AuthToken = *ariesAuthToken
+	}
+
+	// Each role keeps its own storage and credential service. Only the issuer's service holds
+	// signing keys, the holder's wallet only receives credentials delivered through the holder
+	// API, and only the verifier sees the presentations it was sent.
+	stack, err := sdk.NewStack(sdk.Config{
+		Store:              kv,
+		Stateless:          *stateless,
+		Clock:              serverClock,
+		ResolutionCacheTTL: *resolutionCacheTTL,
+		WebDomain:          *webDomain,
+		PrivateWebhooks:    *privateWebhooks,
+		TombstoneRetention: *tombstoneRetention,
+		ClaimKeys:          keyEncryptionKeys(claimKeyring),
+		ValidateContexts:   *validateContexts,
+		Predicates:         providers,
+		Provider:           bbs.Provider(*bbsProvider),
+		Aries:              ariesConfig,
+		Logger:             log.Default(),
+		APITokens:          cli.SplitList(*apiTokens),
+		Secrets:            resolver,
+		CostPolicy: api.CostPolicy{
+			MaxProofsPerRequest:  *maxProofsPerRequest,
+			ProofsPerMinute:      *proofsPerMinute,
+			TotalProofsPerMinute: *totalProofsPerMinute,
+		},
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if *rewrapClaimKeys {
+		if claimKeyring == nil {
+			log.Fatalf("❌ -rewrap-claim-keys requires -claim-keys")
+		}
+		rewritten, err := stack.RewrapClaimKeys()
+		if err != nil {
+			log.Fatalf("❌ Failed to rewrap claim keys: %v", err)
+		}
+		log.Printf("🔏 Rewrapped %d stored credentials under claim key %s", rewritten, claimKeyring.Current())
+	}
+	if *resolutionCacheTTL > 0 {
+		log.Printf("🗃️  Caching DID resolutions and status lists for %s", *resolutionCacheTTL)
+	}
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerUC.SetApprovalRequired(*requireApproval)
+
+	log.Printf("🧮 Predicate providers: %s", strings.Join(stack.Predicates.Names(), ", "))
+
+	datePolicy, err := vc.NewDatePolicy(*dateTimezone)
+	if err != nil {
+		log.Fatalf("❌ Invalid date policy: %v", err)
+	}
+	issuerUC.SetDatePolicy(datePolicy)
+
+	if err := issuerUC.SetBatchWorkers(*batchWorkers); err != nil {
+		log.Fatalf("❌ Invalid batch issuance configuration: %v", err)
+	}
+
+	// Queued issuance jobs are kept with the issuer's state, so instances share the queue only
+	// when they share that state
+	if err := issuerUC.SetIssuanceRetries(*issuanceAttempts, *issuanceBackoff); err != nil {
+		log.Fatalf("❌ Invalid issuance queue configuration: %v", err)
+	}
+	if *stateless {
+		issuerUC.SetIssuanceQueue(storage.NewRedisQueue(redisStore, *redisPrefix+"issuance-queue"))
+	}
+	go issuerUC.RunIssuanceQueue(context.Background(), *issuanceWorkers)
+
+	if err := verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{
+		MaxPresentationAge: *maxPresentationAge,
+		ClockSkew:          *clockSkew,
+	}); err != nil {
+		log.Fatalf("❌ Invalid freshness policy: %v", err)
+	}
+
+	if err := verifierUC.SetMaxStatusAge(*maxStatusAge); err != nil {
+		log.Fatalf("❌ Invalid status snapshot policy: %v", err)
+	}
+
+	verifierUC.SetStrictMode(*strictVerification)
+
+	var replayStore interface{}
+	if *replayCache {
+		if *replayCacheFile != "" {
+			fileCache, err := replay.OpenFileCache(*replayCacheFile, *replayCacheSize)
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			defer fileCache.Close()
+			verifierUC.SetReplayCache(fileCache)
+			replayStore = fileCache
+			log.Printf("🔁 Replay cache persisted to %s (%d entries loaded)", *replayCacheFile, fileCache.Len())
+		} else if kv != nil {
+			kvCache := replay.NewKVCache(storage.Prefixed(kv, "replay:"))
+			verifierUC.SetReplayCache(kvCache)
+			replayStore = kvCache
+		} else {
+			memoryCache := replay.NewMemoryCache(*replayCacheSize)
+			verifierUC.SetReplayCache(memoryCache)
+			replayStore = memoryCache
+		}
+	}
+
+	if err := verifierUC.SetSessionTTL(*sessionTTL); err != nil {
+		log.Fatalf("❌ Invalid session configuration: %v", err)
+	}
+	// Keep finished sessions around for a while so late polls still see the result
+	go verifierUC.RunSessionCleanup(context.Background(), time.Minute, *sessionTTL)
+
+	// Contexts are bundled, so serving them never reaches the network
+	contextLoader, err := jsonld.NewBundledLoader()
+	if err != nil {
+		log.Fatalf("❌ Failed to load JSON-LD contexts: %v", err)
+	}
+
+	if *anchorBackend != "" {
+		var backend anchor.Backend
+		switch *anchorBackend {
+		case "mock":
+			backend = anchor.NewMockBackend()
+		case "ethereum":
+			backend = anchor.NewEthereumBackend(*anchorURL, *anchorFrom)
+		case "timestamp":
+			backend = anchor.NewTimestampBackend(*anchorURL)
+		default:
+			log.Fatalf("❌ Unknown anchor backend: %s", *anchorBackend)
+		}
 
-	// Initialize BBS factory for multi-provider support
-	bbsFactory := bbs.NewFactory()
+		anchorService := anchor.NewService(backend)
+		issuerUC.SetAnchorService(anchorService)
+		if *verifyAnchors {
+			verifierUC.SetAnchorService(anchorService)
+		}
 
-	// Initialize use cases
-	issuerUC := issuer.NewUseCase(didService, vcService, bbsService)
-	holderUC := holder.NewUseCase(didService, vcService, credRepo)
-	verifierUC := verifier.NewUseCase(didService, vcService, presRepo)
+		go issuerUC.RunAnchoring(context.Background(), *anchorInterval)
+		log.Printf("⚓ Anchoring to %s every %s", backend.Name(), *anchorInterval)
+	} else if *verifyAnchors {
+		log.Fatalf("❌ -verify-anchors requires -anchor-backend")
+	}
+
+	if *statusRefreshInterval > 0 {
+		go holderUC.RunStatusRefresher(context.Background(), *statusRefreshInterval)
+		log.Printf("🔄 Refreshing wallet credential status every %s", *statusRefreshInterval)
+	}
 
 	// Create and start HTTP server
-	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbsFactory, *port)
+	server, err := httpServer.NewStackServer(stack, *port)
+	if err != nil {
+		log.Fatalf("❌ Invalid API configuration: %v", err)
+	}
+	server.SetDatePolicy(datePolicy)
+	if *proofDiagnostics {
+		server.SetProofDiagnostics(true)
+		log.Printf("🩺 Proof diagnostics enabled at /api/bbs/proofs/diagnose")
+	}
+	if *apiTokens != "" {
+		log.Printf("🔒 API requests require a bearer token")
+	}
+
+	corsPolicy := httpServer.DefaultCORSPolicy(httpServer.CORSConfig{
+		AllowedOrigins:   cli.SplitList(*corsOrigins),
+		AllowedMethods:   cli.SplitList(*corsMethods),
+		AllowedHeaders:   cli.SplitList(*corsHeaders),
+		AllowCredentials: *corsCredentials,
+		MaxAge:           *corsMaxAge,
+	})
+	if err := server.SetCORSPolicy(corsPolicy); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if *serveContexts {
+		server.SetContextLoader(contextLoader)
+		log.Printf("📚 Serving %d JSON-LD contexts under /contexts", len(contextLoader.Contexts()))
+	}
+
+	checker, err := newHealthChecker(health.Config{
+		Timeout:       *healthTimeout,
+		SlowThreshold: health.DefaultSlowThreshold,
+		CacheFor:      *healthCache,
+	}, stack.BBSService, map[string]interface{}{
+		"storage.dids":          stack.DIDRepository,
+		"storage.credentials":   stack.Holder.Credentials,
+		"storage.presentations": stack.Verifier.Presentations,
+		"storage.statusLists":   stack.StatusRegistry,
+		"storage.replayCache":   replayStore,
+	})
+	if err != nil {
+		log.Fatalf("❌ Invalid health check configuration: %v", err)
+	}
+	server.SetHealthChecker(checker)
+
+	// The server listens right away; /ready answers 503 until the startup tasks finish
+	readiness := health.NewReadiness()
+	if err := readiness.Go("generators", bbs.Warmup); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if err := readiness.Go("crypto.selfTest", func() error {
+		return bbs.SelfTest(stack.BBSService)
+	}); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	server.SetReadiness(readiness)
 
 	log.Printf("✅ All services initialized successfully")
 
@@ -48,3 +386,83 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newHealthChecker checks the credential signing provider with a self-test and pings every
+// store that supports it
+func newHealthChecker(config health.Config, bbsService bbs.BBSService, stores map[string]interface{}) (*health.Checker, error) {
+	checker, err := health.NewChecker(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checker.Register("crypto.selfTest", true, func(ctx context.Context) error {
+		return bbs.SelfTest(bbsService)
+	}); err != nil {
+		return nil, err
+	}
+	// Signatures that verify over altered messages degrade the service without taking it out of rotation
+	if err := checker.Register("crypto.signatureBinding", false, func(ctx context.Context) error {
+		return bbs.SignatureBindingTest(bbsService)
+	}); err != nil {
+		return nil, err
+	}
+	// Linkable proofs leak the holder's privacy but not the credentials' integrity
+	if err := checker.Register("crypto.unlinkability", false, func(ctx context.Context) error {
+		return bbs.UnlinkabilityTest(bbsService)
+	}); err != nil {
+		return nil, err
+	}
+
+	for name, store := range stores {
+		pinger, ok := store.(health.Pinger)
+		if !ok {
+			continue
+		}
+		if err := checker.Register(name, true, health.PingCheck(pinger)); err != nil {
+			return nil, err
+		}
+	}
+
+	return checker, nil
+}
+
+// parseClaimKeys builds the claim keyring from comma-separated id=key pairs, the last one current.
+// Keys are base64 and may be secret references.
+func parseClaimKeys(resolver *secrets.Resolver, value string) (*vc.Keyring, error) {
+	var keyring *vc.Keyring
+	for _, pair := range cli.SplitList(value) {
+		id, reference, ok := strings.Cut(pair, "=")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("%q is not of the form id=key", pair)
+		}
+		encoded, err := resolver.Resolve(context.Background(), reference)
+		if err != nil {
+			return nil, err
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("claim key %s is not base64: %w", id, err)
+		}
+
+		if keyring == nil {
+			keyring, err = vc.NewKeyring(id, key)
+		} else {
+			err = keyring.Rotate(id, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if keyring == nil {
+		return nil, fmt.Errorf("no claim keys")
+	}
+	return keyring, nil
+}
+
+// keyEncryptionKeys keeps a nil keyring a nil interface, which stores claims in the clear
+func keyEncryptionKeys(keyring *vc.Keyring) vc.KeyEncryptionKeys {
+	if keyring == nil {
+		return nil
+	}
+	return keyring
+}
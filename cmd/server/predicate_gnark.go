@@ -0,0 +1,18 @@
+//go:build gnark
+
+package main
+
+import (
+	"flag"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate/gnark"
+)
+
+var gnarkKeyDir = flag.String("gnark-key-dir", "keys/gnark", "Directory holding the Groth16 range circuit keys (created with a development setup when empty)")
+
+func init() {
+	predicateProviders = append(predicateProviders, func() (predicate.Provider, error) {
+		return gnark.NewProvider(*gnarkKeyDir)
+	})
+}
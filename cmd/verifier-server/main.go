@@ -0,0 +1,85 @@
+// Command verifier-server runs a verifier on its own: it exposes only the verifier endpoints and
+// resolves issuers' and holders' DIDs and issuers' status lists at its peers.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/cli"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+func main() {
+	port := flag.String("port", "8083", "Server port")
+	peers := flag.String("peers", os.Getenv("PEERS"), "Comma-separated base URLs of the issuer and wallet servers, whose DIDs and status lists are resolved there, e.g. http://issuer:8081,http://wallet:8082")
+	resolutionCacheTTL := flag.Duration("resolution-cache-ttl", fetch.DefaultTTL, "Cache DID documents and status lists fetched from peers for this long (0 revalidates on every verification)")
+	sessionTTL := flag.Duration("session-ttl", verifier.DefaultSessionTTL, "How long cross-device presentation sessions wait for the holder's presentation")
+	replayCacheSize := flag.Int("replay-cache-size", replay.DefaultCapacity, "How many accepted presentations the replay cache remembers (0 disables it)")
+	maxProofsPerRequest := flag.Int("max-proofs-per-request", 0, "Reject verification requests carrying more proofs than this with 413 (0 allows any number)")
+	proofsPerMinute := flag.Int("proofs-per-minute", 0, "Proofs each client address may have checked per minute before getting 429 (0 is unlimited)")
+	totalProofsPerMinute := flag.Int("total-proofs-per-minute", 0, "Proofs all clients together may have checked per minute before getting 429 (0 is unlimited)")
+	apiTokens := flag.String("api-tokens", os.Getenv("API_TOKENS"), "Comma-separated bearer tokens the API requires, or a secret holding them such as env:API_TOKENS, file:/run/secrets/api_tokens or vault:bbs/server#apiTokens (empty leaves the API open)")
+	webDomain := flag.String("web-domain", "", "Domain this server is reached at, e.g. example.com or localhost:8089; did:web DIDs are then generated under it and served at /users/{id}/did.json (empty serves did:web documents for the request's host)")
+	privateWebhooks := flag.Bool("private-webhooks", false, "Forward session outcomes to relying parties at loopback and private network addresses, for relying parties on the local network")
+	flag.Parse()
+
+	resolver := cli.SecretsResolver()
+
+	log.Println("🔍 Initializing BBS+ verifier server")
+
+	stack, err := sdk.NewStack(sdk.Config{
+		ResolutionCacheTTL: *resolutionCacheTTL,
+		WebDomain:          *webDomain,
+		PrivateWebhooks:    *privateWebhooks,
+		ValidateContexts:   true,
+		Logger:             log.Default(),
+		APITokens:          cli.SplitList(*apiTokens),
+		Secrets:            resolver,
+		Roles:              []api.Role{api.RoleVerifier},
+		Peers:              cli.SplitList(*peers),
+		CostPolicy: api.CostPolicy{
+			MaxProofsPerRequest:  *maxProofsPerRequest,
+			ProofsPerMinute:      *proofsPerMinute,
+			TotalProofsPerMinute: *totalProofsPerMinute,
+		},
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if *replayCacheSize > 0 {
+		stack.Verifier.SetReplayCache(replay.NewMemoryCache(*replayCacheSize))
+	}
+	if err := stack.Verifier.SetSessionTTL(*sessionTTL); err != nil {
+		log.Fatalf("❌ Invalid session configuration: %v", err)
+	}
+	// Keep finished sessions around for a while so late polls still see the result
+	go stack.Verifier.RunSessionCleanup(context.Background(), time.Minute, *sessionTTL)
+
+	server, err := httpServer.NewStackServer(stack, *port)
+	if err != nil {
+		log.Fatalf("❌ Invalid API configuration: %v", err)
+	}
+
+	readiness := health.NewReadiness()
+	if err := readiness.Go("generators", bbs.Warmup); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	server.SetReadiness(readiness)
+
+	if err := server.Start(); err != nil {
+		log.Printf("❌ Server failed to start: %v", err)
+		os.Exit(1)
+	}
+}
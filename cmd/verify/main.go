@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/barcode"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// Exit codes: the presentation verified, it did not, or the input could not be read
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitUsage   = 2
+)
+
+// TrustConfig is the verifier's policy. Its fields have the names of the
+// POST /api/verifier/verify request, so a policy can be shared between the API and the tool.
+type TrustConfig struct {
+	Template                  string                        `json:"template,omitempty"`
+	TrustedIssuers            []string                      `json:"trustedIssuers"`
+	TrustedDomains            []string                      `json:"trustedDomains,omitempty"`
+	RequiredClaims            []string                      `json:"requiredClaims,omitempty"`
+	OptionalClaims            []string                      `json:"optionalClaims,omitempty"`
+	AllowedClaims             []string                      `json:"allowedClaims,omitempty"`
+	MaxRevealedClaims         int                           `json:"maxRevealedClaims,omitempty"`
+	OverDisclosure            string                        `json:"overDisclosure,omitempty"`
+	ClaimConstraints          map[string]vc.ClaimConstraint `json:"claimConstraints,omitempty"`
+	RequiredPredicates        []vc.PredicateStatement       `json:"requiredPredicates,omitempty"`
+	AbsentClaims              []string                      `json:"absentClaims,omitempty"`
+	MaxPresentationAgeSeconds int                           `json:"maxPresentationAgeSeconds,omitempty"`
+	MaxStatusAgeSeconds       int                           `json:"maxStatusAgeSeconds,omitempty"`
+	// DIDDocuments are resolved without a network, for DIDs that live in another service's store
+	DIDDocuments []*did.DIDDocument `json:"didDocuments,omitempty"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run verifies the presentation and writes the JSON report, returning the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	trustFile := fs.String("trust", "", "Trust configuration file (required)")
+	nonce := fs.String("nonce", "", "Nonce the presentation must be bound to")
+	domain := fs.String("domain", "", "Domain the holder's proof must name")
+	strict := fs.Bool("strict", false, "Report lint findings about the presentation as warnings")
+	validateContexts := fs.Bool("validate-contexts", false, "Reject presentations that do not expand under their JSON-LD @context")
+	barcodeInput := fs.Bool("barcode", false, "Read the presentation as scanned QR code payloads, one per line")
+	cacheTTL := fs.Duration("resolution-cache-ttl", time.Minute, "How long fetched did:web documents and DID configurations are reused; zero disables did:web")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "BBS+ presentation verifier")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Usage:")
+		fmt.Fprintln(stderr, "  verify -trust <file> [-nonce <nonce>] [-domain <domain>] [-barcode] [presentation.json | -]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "The presentation is read from stdin when no file or \"-\" is given. A JSON report is")
+		fmt.Fprintln(stderr, "written to stdout; the exit code is 0 when it verifies, 1 when it does not and 2 on bad input.")
+		fmt.Fprintln(stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *trustFile == "" || fs.NArg() > 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	fail := func(err error) int {
+		writeReport(stdout, &verifier.VerificationResult{Errors: []string{err.Error()}})
+		return exitUsage
+	}
+
+	trust, err := loadTrustConfig(*trustFile)
+	if err != nil {
+		return fail(err)
+	}
+	overDisclosure := verifier.OverDisclosurePolicy(trust.OverDisclosure)
+	if err := overDisclosure.Validate(); err != nil {
+		return fail(err)
+	}
+
+	raw, err := readPresentation(fs.Arg(0), stdin)
+	if err != nil {
+		return fail(err)
+	}
+	var presentation vc.VerifiablePresentation
+	if *barcodeInput {
+		decoded, document, err := barcode.DecodePresentation(strings.Fields(string(raw)))
+		if err != nil {
+			return fail(err)
+		}
+		presentation, raw = *decoded, document
+	} else if err := json.Unmarshal(raw, &presentation); err != nil {
+		return fail(fmt.Errorf("invalid presentation JSON: %w", err))
+	}
+
+	stack, err := sdk.NewStack(sdk.Config{ResolutionCacheTTL: *cacheTTL, ValidateContexts: *validateContexts})
+	if err != nil {
+		return fail(err)
+	}
+	for _, document := range trust.DIDDocuments {
+		if err := stack.DIDRepository.Create(document); err != nil {
+			return fail(fmt.Errorf("DID document %s: %w", document.ID, err))
+		}
+	}
+
+	result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+		Presentation:       &presentation,
+		Template:           trust.Template,
+		RequiredClaims:     trust.RequiredClaims,
+		OptionalClaims:     trust.OptionalClaims,
+		AllowedClaims:      trust.AllowedClaims,
+		MaxRevealedClaims:  trust.MaxRevealedClaims,
+		OverDisclosure:     overDisclosure,
+		TrustedIssuers:     trust.TrustedIssuers,
+		TrustedDomains:     trust.TrustedDomains,
+		ClaimConstraints:   trust.ClaimConstraints,
+		RequiredPredicates: trust.RequiredPredicates,
+		AbsentClaims:       trust.AbsentClaims,
+		VerificationNonce:  *nonce,
+		Domain:             *domain,
+		MaxPresentationAge: time.Duration(trust.MaxPresentationAgeSeconds) * time.Second,
+		MaxStatusAge:       time.Duration(trust.MaxStatusAgeSeconds) * time.Second,
+		Strict:             *strict,
+		RawPresentation:    raw,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	writeReport(stdout, result)
+	if !result.Valid {
+		return exitInvalid
+	}
+	return exitValid
+}
+
+// loadTrustConfig reads the trust configuration, rejecting unknown fields so typos do not
+// silently loosen the policy
+func loadTrustConfig(path string) (*TrustConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust configuration: %w", err)
+	}
+	defer file.Close()
+
+	var config TrustConfig
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid trust configuration: %w", err)
+	}
+	if len(config.TrustedIssuers) == 0 && len(config.TrustedDomains) == 0 {
+		return nil, fmt.Errorf("trust configuration names no trusted issuers or domains")
+	}
+	if config.MaxRevealedClaims < 0 || config.MaxPresentationAgeSeconds < 0 || config.MaxStatusAgeSeconds < 0 {
+		return nil, fmt.Errorf("trust configuration limits cannot be negative")
+	}
+	return &config, nil
+}
+
+// readPresentation reads the presentation file, or stdin for "" and "-"
+func readPresentation(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read presentation from stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presentation: %w", err)
+	}
+	return data, nil
+}
+
+// writeReport writes the verification result as indented JSON
+func writeReport(w io.Writer, result *verifier.VerificationResult) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(result)
+}
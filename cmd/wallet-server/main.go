@@ -0,0 +1,62 @@
+// Command wallet-server runs a holder wallet on its own: it exposes only the holder endpoints and
+// resolves the DIDs of the issuers whose credentials it holds at its peers.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/cli"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+func main() {
+	port := flag.String("port", "8082", "Server port")
+	peers := flag.String("peers", os.Getenv("PEERS"), "Comma-separated base URLs of the issuer and verifier servers, whose DIDs are resolved there, e.g. http://issuer:8081,http://verifier:8083")
+	resolutionCacheTTL := flag.Duration("resolution-cache-ttl", fetch.DefaultTTL, "Cache DID documents resolved at peers for this long (0 disables)")
+	tombstoneRetention := flag.Duration("tombstone-retention", 0, "Keep deleted credentials, with who deleted them, when and why, for this long before purging them (0 keeps them)")
+	apiTokens := flag.String("api-tokens", os.Getenv("API_TOKENS"), "Comma-separated bearer tokens the API requires, or a secret holding them such as env:API_TOKENS, file:/run/secrets/api_tokens or vault:bbs/server#apiTokens (empty leaves the API open)")
+	webDomain := flag.String("web-domain", "", "Domain this server is reached at, e.g. example.com or localhost:8089; did:web DIDs are then generated under it and served at /users/{id}/did.json (empty serves did:web documents for the request's host)")
+	flag.Parse()
+
+	resolver := cli.SecretsResolver()
+
+	log.Println("👛 Initializing BBS+ wallet server")
+
+	stack, err := sdk.NewStack(sdk.Config{
+		ResolutionCacheTTL: *resolutionCacheTTL,
+		WebDomain:          *webDomain,
+		TombstoneRetention: *tombstoneRetention,
+		ValidateContexts:   true,
+		Logger:             log.Default(),
+		APITokens:          cli.SplitList(*apiTokens),
+		Secrets:            resolver,
+		Roles:              []api.Role{api.RoleHolder},
+		Peers:              cli.SplitList(*peers),
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	server, err := httpServer.NewStackServer(stack, *port)
+	if err != nil {
+		log.Fatalf("❌ Invalid API configuration: %v", err)
+	}
+
+	readiness := health.NewReadiness()
+	if err := readiness.Go("generators", bbs.Warmup); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	server.SetReadiness(readiness)
+
+	if err := server.Start(); err != nil {
+		log.Printf("❌ Server failed to start: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const defaultServer = "http://localhost:8089"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "redline":
+		err = runRedline(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func usage() {
+	fmt.Println("BBS+ wallet tool")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  wallet export -holder <did> -out <file> [-server <url>] [-password <password>]")
+	fmt.Println("  wallet import -in <file> [-server <url>] [-password <password>]")
+	fmt.Println("  wallet redline -holder <did> -credential <id> -reveal <claim,...> [-purpose <purpose>] [-server <url>]")
+	fmt.Println()
+	fmt.Println("The password may also be supplied via the WALLET_PASSWORD environment variable.")
+}
+
+// runExport downloads an encrypted backup of a holder's wallet
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	server := fs.String("server", defaultServer, "Wallet server URL")
+	holderDID := fs.String("holder", "", "Holder DID to export")
+	out := fs.String("out", "wallet-backup.json", "Output file")
+	password := fs.String("password", "", "Backup password (default $WALLET_PASSWORD)")
+	fs.Parse(args)
+
+	if *holderDID == "" {
+		return fmt.Errorf("-holder is required")
+	}
+
+	pw, err := resolvePassword(*password)
+	if err != nil {
+		return err
+	}
+
+	archive, err := post(*server+"/api/holder/backup", map[string]string{
+		"holderDid": *holderDID,
+		"password":  pw,
+	})
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if err := os.WriteFile(*out, archive, 0o600); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	fmt.Printf("✅ Wallet for %s exported to %s\n", *holderDID, *out)
+	return nil
+}
+
+// runImport restores a wallet from an encrypted backup
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	server := fs.String("server", defaultServer, "Wallet server URL")
+	in := fs.String("in", "wallet-backup.json", "Backup file")
+	password := fs.String("password", "", "Backup password (default $WALLET_PASSWORD)")
+	fs.Parse(args)
+
+	archive, err := os.ReadFile(*in)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	pw, err := resolvePassword(*password)
+	if err != nil {
+		return err
+	}
+
+	body, err := post(*server+"/api/holder/restore", map[string]interface{}{
+		"archive":  json.RawMessage(archive),
+		"password": pw,
+	})
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	var result struct {
+		HolderDID    string `json:"holderDid"`
+		Credentials  int    `json:"credentials"`
+		PairwiseDIDs int    `json:"pairwiseDids"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("invalid server response: %w", err)
+	}
+
+	fmt.Printf("✅ Restored wallet for %s (%d credentials, %d pairwise DIDs)\n",
+		result.HolderDID, result.Credentials, result.PairwiseDIDs)
+	return nil
+}
+
+// runRedline shows which of a credential's attributes a presentation would reveal, so the holder
+// can consent before creating it
+func runRedline(args []string) error {
+	fs := flag.NewFlagSet("redline", flag.ExitOnError)
+	server := fs.String("server", defaultServer, "Wallet server URL")
+	holderDID := fs.String("holder", "", "Holder DID")
+	credentialID := fs.String("credential", "", "Credential ID")
+	reveal := fs.String("reveal", "", "Comma-separated claims to reveal")
+	purpose := fs.String("purpose", "", "Purpose of the presentation, e.g. age-over-18")
+	fs.Parse(args)
+
+	if *holderDID == "" || *credentialID == "" {
+		return fmt.Errorf("-holder and -credential are required")
+	}
+
+	var revealed []string
+	for _, claim := range strings.Split(*reveal, ",") {
+		if claim = strings.TrimSpace(claim); claim != "" {
+			revealed = append(revealed, claim)
+		}
+	}
+
+	body, err := post(*server+"/api/holder/credentials/"+url.PathEscape(*credentialID)+"/redline", map[string]interface{}{
+		"holderDid":          *holderDID,
+		"revealedAttributes": revealed,
+		"purpose":            *purpose,
+	})
+	if err != nil {
+		return fmt.Errorf("redline failed: %w", err)
+	}
+
+	var redline struct {
+		Issuer     string `json:"issuer"`
+		Attributes []struct {
+			Name        string      `json:"name"`
+			Value       interface{} `json:"value"`
+			Disclosure  string      `json:"disclosure"`
+			Always      bool        `json:"always"`
+			Level       string      `json:"level"`
+			Unnecessary bool        `json:"unnecessary"`
+			Substitute  string      `json:"substitute"`
+		} `json:"attributes"`
+		Score        int      `json:"score"`
+		MinimalScore int      `json:"minimalScore"`
+		Warnings     []string `json:"warnings"`
+		Refused      string   `json:"refused"`
+	}
+	if err := json.Unmarshal(body, &redline); err != nil {
+		return fmt.Errorf("invalid server response: %w", err)
+	}
+
+	fmt.Printf("Credential %s from %s\n\n", *credentialID, redline.Issuer)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTRIBUTE\tDISCLOSURE\tVALUE\tSENSITIVITY\tNOTE")
+	for _, attribute := range redline.Attributes {
+		var notes []string
+		if attribute.Always {
+			notes = append(notes, "always shared")
+		}
+		if attribute.Unnecessary {
+			notes = append(notes, "not needed")
+		}
+		if attribute.Substitute != "" {
+			notes = append(notes, "use "+attribute.Substitute+" instead")
+		}
+
+		value := fmt.Sprint(attribute.Value)
+		if attribute.Disclosure != "revealed" {
+			value = "(hidden)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", attribute.Name, attribute.Disclosure, value, attribute.Level, strings.Join(notes, ", "))
+	}
+	tw.Flush()
+
+	fmt.Printf("\nPrivacy score %d/100 (best for this purpose: %d)\n", redline.Score, redline.MinimalScore)
+	for _, warning := range redline.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	if redline.Refused != "" {
+		fmt.Printf("❌ %s\n", redline.Refused)
+	}
+	return nil
+}
+
+// resolvePassword returns the flag value or falls back to $WALLET_PASSWORD
+func resolvePassword(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if env := os.Getenv("WALLET_PASSWORD"); env != "" {
+		return env, nil
+	}
+	return "", fmt.Errorf("a password is required (-password or WALLET_PASSWORD)")
+}
+
+// post sends a JSON request and returns the response body of a successful call
+func post(url string, payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error   string `json:"error"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("%s: %s", errResp.Error, errResp.Details)
+		}
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	return body, nil
+}
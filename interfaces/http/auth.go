@@ -0,0 +1,86 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+)
+
+// SetAPITokens requires API requests to carry one of the tokens as "Authorization: Bearer <token>".
+// Discovery documents, DID documents, status lists, signed status notifications and the wallet
+// side of verifier sessions stay open, since the parties fetching them hold no token. No tokens
+// leaves the API open.
+func (s *Server) SetAPITokens(tokens []string) error {
+	digests := make([][sha256.Size]byte, 0, len(tokens))
+	for _, token := range tokens {
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("API tokens cannot be empty")
+		}
+		digests = append(digests, sha256.Sum256([]byte(token)))
+	}
+
+	s.apiTokens = digests
+	return nil
+}
+
+// authMiddleware rejects API requests without a valid bearer token when tokens are configured
+func authMiddleware(tokens [][sha256.Size]byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(tokens) == 0 || r.Method == http.MethodOptions || !requiresToken(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !validBearerToken(tokens, r.Header.Get("Authorization")) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(dto.ErrorResponse{
+				Error: "Missing or invalid API token",
+				Code:  http.StatusUnauthorized,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requiresToken reports whether a path is part of the token-protected API
+func requiresToken(path string) bool {
+	if !strings.HasPrefix(path, "/api/") {
+		return false
+	}
+	if strings.HasPrefix(path, "/api/status-lists/") || strings.HasPrefix(path, "/api/schemas/") || strings.HasPrefix(path, "/api/dids/") {
+		return false
+	}
+	// Notifications are signed by the issuer, which holds no token for the holder's API
+	if path == "/api/holder/status-notifications" {
+		return false
+	}
+	// Wallets reach a session through its unguessable ID
+	if strings.HasPrefix(path, "/api/verifier/sessions/") &&
+		(strings.HasSuffix(path, "/request") || strings.HasSuffix(path, "/presentation")) {
+		return false
+	}
+	return true
+}
+
+// validBearerToken compares the presented token against every configured token in constant time
+func validBearerToken(tokens [][sha256.Size]byte, header string) bool {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(token))
+	valid := 0
+	for _, expected := range tokens {
+		valid |= subtle.ConstantTimeCompare(digest[:], expected[:])
+	}
+	return valid == 1
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig controls which cross-origin browser requests are allowed
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins such as https://wallet.example.com, patterns with a
+	// single leading wildcard label such as https://*.example.com, or "*" for any origin.
+	// An empty list disables cross-origin access.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight results; zero omits the header
+	MaxAge time.Duration
+}
+
+// CORSRoute overrides the default configuration for paths starting with Prefix
+type CORSRoute struct {
+	Prefix string
+	Config CORSConfig
+}
+
+// CORSPolicy is the default CORS configuration plus per-route overrides.
+// The override with the longest matching prefix wins.
+type CORSPolicy struct {
+	Default CORSConfig
+	Routes  []CORSRoute
+}
+
+// DefaultCORSConfig allows no cross-origin access; origins must be configured explicitly
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// PublicCORSConfig allows read-only access from any origin, for documents every
+// wallet and verifier must be able to fetch such as issuer metadata and status lists
+func PublicCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodOptions},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         24 * time.Hour,
+	}
+}
+
+// DefaultCORSPolicy uses the given configuration for the API and public read-only
+// access for discovery documents, status lists and schema display metadata
+func DefaultCORSPolicy(config CORSConfig) CORSPolicy {
+	return CORSPolicy{
+		Default: config,
+		Routes: []CORSRoute{
+			{Prefix: "/.well-known/", Config: PublicCORSConfig()},
+			{Prefix: "/api/status-lists/", Config: PublicCORSConfig()},
+			{Prefix: "/api/schemas/", Config: PublicCORSConfig()},
+		},
+	}
+}
+
+// Validate rejects configurations browsers would refuse or that are unsafe
+func (c CORSConfig) Validate() error {
+	if c.AllowCredentials && c.allowsAnyOrigin() {
+		return fmt.Errorf("credentials cannot be allowed for any origin")
+	}
+
+	for _, origin := range c.AllowedOrigins {
+		if origin != "*" && !strings.Contains(origin, "://") {
+			return fmt.Errorf("invalid origin %q: expected scheme://host[:port]", origin)
+		}
+	}
+	return nil
+}
+
+// Validate checks the default configuration and every route override
+func (p CORSPolicy) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return err
+	}
+
+	for _, route := range p.Routes {
+		if !strings.HasPrefix(route.Prefix, "/") {
+			return fmt.Errorf("invalid CORS route prefix %q", route.Prefix)
+		}
+		if err := route.Config.Validate(); err != nil {
+			return fmt.Errorf("CORS route %s: %w", route.Prefix, err)
+		}
+	}
+	return nil
+}
+
+// configFor returns the configuration applying to a request path
+func (p CORSPolicy) configFor(path string) CORSConfig {
+	config := p.Default
+	longest := -1
+	for _, route := range p.Routes {
+		if strings.HasPrefix(path, route.Prefix) && len(route.Prefix) > longest {
+			config = route.Config
+			longest = len(route.Prefix)
+		}
+	}
+	return config
+}
+
+// allowsOrigin reports whether the origin matches one of the allowed origins
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+
+		// https://*.example.com matches https://wallet.example.com but not https://example.com
+		if scheme, host, ok := strings.Cut(allowed, "://*."); ok {
+			prefix := scheme + "://"
+			suffix := "." + host
+			if len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+				strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) &&
+				!strings.Contains(origin[len(prefix):len(origin)-len(suffix)], "/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowsAnyOrigin reports whether the configuration is a wildcard
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsMethod reports whether preflights may request the method
+func (c CORSConfig) allowsMethod(method string) bool {
+	for _, allowed := range c.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsHeaders reports whether every requested header is allowed
+func (c CORSConfig) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+		for _, h := range c.AllowedHeaders {
+			if h == "*" || strings.EqualFold(h, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// corsMiddleware applies the CORS policy and answers preflight requests itself
+func corsMiddleware(policy CORSPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Not a cross-origin browser request
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		config := policy.configFor(r.URL.Path)
+		w.Header().Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !config.allowsOrigin(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			// Serve the request without CORS headers; the browser withholds the response
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if config.allowsAnyOrigin() {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestedHeaders := r.Header.Get("Access-Control-Request-Headers")
+		if !config.allowsMethod(r.Header.Get("Access-Control-Request-Method")) || !config.allowsHeaders(requestedHeaders) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
+		if len(config.AllowedHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+		}
+		if config.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
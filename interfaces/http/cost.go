@@ -0,0 +1,284 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/barcode"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// maxTrackedClients bounds the per-client budgets kept in memory; past it, clients whose budget
+// has refilled completely are forgotten
+const maxTrackedClients = 10000
+
+// SetCostPolicy limits the proofs the verification endpoints check per request and per minute.
+// Requests over a budget are answered 429 with a Retry-After header.
+func (s *Server) SetCostPolicy(policy api.CostPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid cost policy: %w", err)
+	}
+
+	if !policy.Enabled() {
+		s.costLimiter = nil
+		return nil
+	}
+	s.costLimiter = newCostLimiter(policy, clock.OrSystem(s.clock))
+	return nil
+}
+
+// budget is a token bucket holding up to perMinute units
+type budget struct {
+	units   float64
+	updated time.Time
+}
+
+// costLimiter tracks the shared and per-client budgets
+type costLimiter struct {
+	policy  api.CostPolicy
+	clock   clock.Clock
+	mu      sync.Mutex
+	total   budget
+	clients map[string]*budget
+}
+
+func newCostLimiter(policy api.CostPolicy, c clock.Clock) *costLimiter {
+	now := c.Now()
+	return &costLimiter{
+		policy:  policy,
+		clock:   c,
+		total:   budget{units: float64(policy.TotalProofsPerMinute), updated: now},
+		clients: make(map[string]*budget),
+	}
+}
+
+// refill adds the units earned since the budget was last updated
+func (b *budget) refill(perMinute int, now time.Time) {
+	b.units = math.Min(float64(perMinute), b.units+now.Sub(b.updated).Minutes()*float64(perMinute))
+	b.updated = now
+}
+
+// wait is how long the budget takes to hold cost units
+func (b *budget) wait(perMinute int, cost int) time.Duration {
+	missing := float64(cost) - b.units
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / float64(perMinute) * float64(time.Minute))
+}
+
+// charge takes cost units from the client's and the shared budget, or reports how long to wait
+// until both hold them. Nothing is taken from either when one falls short.
+func (l *costLimiter) charge(client string, cost int) (time.Duration, error) {
+	if l.policy.MaxProofsPerRequest > 0 && cost > l.policy.MaxProofsPerRequest {
+		return 0, fmt.Errorf("request carries %d proofs; at most %d are checked per request", cost, l.policy.MaxProofsPerRequest)
+	}
+	for _, perMinute := range []int{l.policy.ProofsPerMinute, l.policy.TotalProofsPerMinute} {
+		if perMinute > 0 && cost > perMinute {
+			return 0, fmt.Errorf("request carries %d proofs; at most %d are checked per minute", cost, perMinute)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+
+	var wait time.Duration
+	var clientBudget *budget
+	if l.policy.ProofsPerMinute > 0 {
+		clientBudget = l.client(client, now)
+		clientBudget.refill(l.policy.ProofsPerMinute, now)
+		wait = clientBudget.wait(l.policy.ProofsPerMinute, cost)
+	}
+	if l.policy.TotalProofsPerMinute > 0 {
+		l.total.refill(l.policy.TotalProofsPerMinute, now)
+		if totalWait := l.total.wait(l.policy.TotalProofsPerMinute, cost); totalWait > wait {
+			wait = totalWait
+		}
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+
+	if clientBudget != nil {
+		clientBudget.units -= float64(cost)
+	}
+	if l.policy.TotalProofsPerMinute > 0 {
+		l.total.units -= float64(cost)
+	}
+	return 0, nil
+}
+
+// client returns a client's budget, starting it full
+func (l *costLimiter) client(key string, now time.Time) *budget {
+	if b, ok := l.clients[key]; ok {
+		return b
+	}
+
+	if len(l.clients) >= maxTrackedClients {
+		for k, b := range l.clients {
+			b.refill(l.policy.ProofsPerMinute, now)
+			if b.units >= float64(l.policy.ProofsPerMinute) {
+				delete(l.clients, k)
+			}
+		}
+	}
+	b := &budget{units: float64(l.policy.ProofsPerMinute), updated: now}
+	l.clients[key] = b
+	return b
+}
+
+// costMiddleware charges requests to the proof checking endpoints against the limiter's budgets
+func costMiddleware(limiter *costLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limiter == nil || r.Method != http.MethodPost || !checksProofs(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeCostError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		wait, err := limiter.charge(clientAddress(r), requestCost(r.URL.Path, body))
+		if err != nil {
+			writeCostError(w, http.StatusRequestEntityTooLarge, "Too many proofs in one request", err.Error())
+			return
+		}
+		if wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeCostError(w, http.StatusTooManyRequests, "Proof verification budget exhausted", fmt.Sprintf("retry in %s", wait.Round(time.Second)))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checksProofs reports whether a path's handler checks proofs from the request
+func checksProofs(path string) bool {
+	switch path {
+	case "/api/verifier/verify", "/api/issuer/verify",
+		"/api/bbs/test", "/api/bbs/benchmark", "/api/bbs/proofs/diagnose":
+		return true
+	}
+	if strings.HasPrefix(path, "/api/verifier/sessions/") && strings.HasSuffix(path, "/presentation") {
+		return true
+	}
+	if strings.HasPrefix(path, "/api/verifier/negotiations/") && strings.HasSuffix(path, "/verify") {
+		return true
+	}
+	if strings.HasPrefix(path, "/api/verifier/presentation-tokens/") && strings.HasSuffix(path, "/redeem") {
+		return true
+	}
+	return strings.HasPrefix(path, "/api/age-verification/requests/") && strings.HasSuffix(path, "/verify")
+}
+
+// requestCost counts the proofs a request asks the server to check, and at least one
+func requestCost(path string, body []byte) int {
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		// The handler rejects the body without checking anything
+		return 1
+	}
+
+	switch path {
+	case "/api/bbs/test", "/api/bbs/proofs/diagnose":
+		return 1
+	case "/api/bbs/benchmark":
+		// Each provider signs, verifies and proves once
+		var req struct {
+			Providers []string `json:"providers"`
+		}
+		_ = json.Unmarshal(body, &req)
+		return max(1, len(req.Providers))
+	}
+
+	proofs := countProofs(document)
+	if object, ok := document.(map[string]interface{}); ok {
+		if payloads, ok := object["barcode"].([]interface{}); ok {
+			proofs += barcodeProofs(payloads)
+		}
+	}
+	return max(1, proofs)
+}
+
+// barcodeProofs counts the proofs in the presentation scanned QR code payloads encode
+func barcodeProofs(payloads []interface{}) int {
+	chunks := make([]string, 0, len(payloads))
+	for _, payload := range payloads {
+		if chunk, ok := payload.(string); ok {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	_, document, err := barcode.Decode(chunks)
+	if err != nil {
+		return 0
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(document, &decoded); err != nil {
+		return 0
+	}
+	return countProofs(decoded)
+}
+
+// countProofs counts the values of "proof" members in a JSON document; a proof set counts each
+// of its proofs
+func countProofs(value interface{}) int {
+	count := 0
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, member := range v {
+			if key != "proof" {
+				count += countProofs(member)
+				continue
+			}
+			switch proof := member.(type) {
+			case map[string]interface{}:
+				count++
+			case []interface{}:
+				count += len(proof)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			count += countProofs(item)
+		}
+	}
+	return count
+}
+
+// clientAddress is the host of the request's remote address. Forwarding headers are ignored,
+// since clients could set them to draw on other clients' budgets.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeCostError writes an error response from the cost middleware
+func writeCostError(w http.ResponseWriter, status int, message, details string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto.ErrorResponse{
+		Error:   message,
+		Code:    status,
+		Details: details,
+	})
+}
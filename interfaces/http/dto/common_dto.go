@@ -1,10 +1,29 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+)
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    int    `json:"code"`
 	Details string `json:"details,omitempty"`
+	// Fields lists the request fields that failed validation
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError explains why one request field was rejected
+type FieldError struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+	// Position is the byte offset of the offending character in Value, or -1 when the value as a
+	// whole is at fault
+	Position int `json:"position"`
 }
 
 // SuccessResponse represents a success response
@@ -24,6 +43,30 @@ type TestBBSProviderResponse struct {
 	Available   bool   `json:"available"`
 	Performance string `json:"performance,omitempty"`
 	Message     string `json:"message,omitempty"`
+	// Signature is a test signature in the versioned base64 encoding credential proofs use
+	Signature string `json:"signature,omitempty"`
+}
+
+// DiagnoseProofRequest asks for a step-by-step account of a proof verification
+type DiagnoseProofRequest struct {
+	// Provider verifies the proof; empty uses the production provider
+	Provider string `json:"provider,omitempty"`
+	// PublicKey is the issuer's BBS+ public key, in base64 or as the publicKeyMultibase of its
+	// Bls12381G2Key2020 verification method
+	PublicKey  string `json:"publicKey"`
+	ProofValue string `json:"proofValue"`
+	// RevealedMessages are the revealed signed messages in base64, in the proof's index order
+	RevealedMessages []string `json:"revealedMessages"`
+	// Nonce is the proof nonce in base64. PresentationNonce is the nonce a presentation request
+	// named instead, hashed into the proof nonce as holders do.
+	Nonce             string `json:"nonce,omitempty"`
+	PresentationNonce string `json:"presentationNonce,omitempty"`
+}
+
+// DiagnoseProofResponse reports each check a proof verification ran
+type DiagnoseProofResponse struct {
+	Provider string `json:"provider"`
+	*bbs.ProofDiagnostics
 }
 
 // BenchmarkBBSProvidersRequest represents the request to benchmark BBS providers
@@ -51,7 +94,33 @@ type BenchmarkBBSProvidersResponse struct {
 
 // HealthResponse represents a health check response
 type HealthResponse struct {
-	Status  string `json:"status"`
-	Service string `json:"service"`
-	Version string `json:"version"`
+	Status     string             `json:"status"`
+	Service    string             `json:"service"`
+	Version    string             `json:"version"`
+	Components []health.Component `json:"components,omitempty"`
+	CheckedAt  *time.Time         `json:"checkedAt,omitempty"`
+}
+
+// ReadinessResponse represents a readiness probe response
+type ReadinessResponse struct {
+	Status string        `json:"status"`
+	Tasks  []health.Task `json:"tasks,omitempty"`
+}
+
+// LivenessResponse represents a liveness probe response
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// ContextDTO describes a JSON-LD context served locally
+type ContextDTO struct {
+	// URL is the context's published URL, as used in @context
+	URL string `json:"url"`
+	// LocalURL is where this server serves the same document
+	LocalURL string `json:"localUrl"`
+}
+
+// ListContextsResponse lists the JSON-LD contexts served locally
+type ListContextsResponse struct {
+	Contexts []ContextDTO `json:"contexts"`
 }
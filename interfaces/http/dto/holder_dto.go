@@ -1,6 +1,13 @@
 package dto
 
-import "github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/privacy"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
 
 // SetupHolderRequest represents the request to setup a holder
 type SetupHolderRequest struct {
@@ -24,31 +31,272 @@ type StoreCredentialResponse struct {
 	Status string `json:"status"`
 }
 
+// DeleteCredentialResponse represents the response from deleting a credential
+type DeleteCredentialResponse struct {
+	Status string `json:"status"`
+}
+
+// ImportCredentialRequest represents the request to import a credential issued outside this service
+type ImportCredentialRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+	// Credential is the JSON-LD credential as the issuer delivered it
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+// ImportCredentialResponse represents the response from importing a credential
+type ImportCredentialResponse struct {
+	Credential *vc.VerifiableCredential `json:"credential"`
+}
+
+// DeriveCredentialRequest represents the request to derive a selective disclosure proof from a
+// held credential
+type DeriveCredentialRequest struct {
+	HolderDID          string   `json:"holderDid" validate:"required"`
+	RevealedAttributes []string `json:"revealedAttributes" validate:"required,min=1"`
+	Nonce              string   `json:"nonce" validate:"required"`
+}
+
+// DeriveCredentialResponse represents the response from deriving a credential
+type DeriveCredentialResponse struct {
+	Credential *vc.DerivedCredential `json:"credential"`
+}
+
+// ProveContinuityRequest represents the request to prove two held credentials hold the same
+// hidden claim value
+type ProveContinuityRequest struct {
+	HolderDID            string `json:"holderDid" validate:"required"`
+	PreviousCredentialID string `json:"previousCredentialId" validate:"required"`
+	CurrentCredentialID  string `json:"currentCredentialId" validate:"required"`
+	// Claim is the committed claim proven unchanged; empty means the subject identifier
+	Claim string `json:"claim,omitempty"`
+	Nonce string `json:"nonce" validate:"required"`
+}
+
+// ProveContinuityResponse represents the response from proving continuity
+type ProveContinuityResponse struct {
+	Proof *vc.ContinuityProof `json:"proof"`
+}
+
+// FetchLatestVersionRequest represents the request to fetch the latest version of a held credential
+type FetchLatestVersionRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+}
+
+// FetchLatestVersionResponse represents the latest version of a held credential
+type FetchLatestVersionResponse struct {
+	Credential *vc.VerifiableCredential `json:"credential"`
+	// Amended is set when the credential is a newer version than the one asked about
+	Amended bool `json:"amended"`
+}
+
 // CreatePresentationRequest represents the request to create a presentation
 type CreatePresentationRequest struct {
-	HolderDID           string                          `json:"holderDid" validate:"required"`
-	CredentialIDs       []string                        `json:"credentialIds" validate:"required,min=1"`
-	SelectiveDisclosure []SelectiveDisclosureRequestDTO `json:"selectiveDisclosure" validate:"required,min=1"`
-	Nonce               string                          `json:"nonce,omitempty"`
-	BBSProvider         string                          `json:"bbsProvider,omitempty"`
+	HolderDID              string                          `json:"holderDid" validate:"required"`
+	CredentialIDs          []string                        `json:"credentialIds" validate:"required,min=1"`
+	SelectiveDisclosure    []SelectiveDisclosureRequestDTO `json:"selectiveDisclosure" validate:"required,min=1"`
+	Nonce                  string                          `json:"nonce,omitempty"`
+	BBSProvider            string                          `json:"bbsProvider,omitempty"`
+	VerifierDID            string                          `json:"verifierDid,omitempty"`
+	UsePairwiseDID         bool                            `json:"usePairwiseDid,omitempty"`
+	ValidForSeconds        int                             `json:"validForSeconds,omitempty"`
+	IncludeStatusSnapshots bool                            `json:"includeStatusSnapshots,omitempty"`
+	Purpose                string                          `json:"purpose,omitempty"`
+	RetentionDays          int                             `json:"retentionDays,omitempty"`
+	Verifier               *vc.VerifierIdentity            `json:"verifier,omitempty"`
+	Accreditation          *vc.VerifiableCredential        `json:"accreditation,omitempty"`
+	Format                 vc.ExportFormat                 `json:"format,omitempty"`
+	Domain                 string                          `json:"domain,omitempty"`
+	// Barcode also returns the presentation as QR code payloads
+	Barcode bool `json:"barcode,omitempty"`
 }
 
 // SelectiveDisclosureRequestDTO represents a selective disclosure request
 type SelectiveDisclosureRequestDTO struct {
-	CredentialID       string   `json:"credentialId" validate:"required"`
-	RevealedAttributes []string `json:"revealedAttributes" validate:"required,min=1"`
-	Nonce              string   `json:"nonce,omitempty"`
+	CredentialID       string                  `json:"credentialId" validate:"required"`
+	RevealedAttributes []string                `json:"revealedAttributes" validate:"required,min=1"`
+	Nonce              string                  `json:"nonce,omitempty"`
+	HideIssuer         bool                    `json:"hideIssuer,omitempty"`
+	IncludeCommitments bool                    `json:"includeCommitments,omitempty"`
+	Predicates         []vc.PredicateStatement `json:"predicates,omitempty"`
+	IncludeEvidence    bool                    `json:"includeEvidence,omitempty"`
+	ProveAbsent        []string                `json:"proveAbsent,omitempty"`
 }
 
 // CreatePresentationResponse represents the response from creating a presentation
 type CreatePresentationResponse struct {
 	PresentationID string                     `json:"presentationId"`
 	Presentation   *vc.VerifiablePresentation `json:"presentation"`
+	Privacy        *PrivacyReportDTO          `json:"privacy,omitempty"`
+	// Barcode holds the presentation's QR code payloads, one per code, when requested
+	Barcode []string `json:"barcode,omitempty"`
+}
+
+// PrivacyReportDTO represents the disclosure analysis of a presentation
+type PrivacyReportDTO struct {
+	Purpose     string                     `json:"purpose,omitempty"`
+	Score       int                        `json:"score"`
+	Warnings    []string                   `json:"warnings,omitempty"`
+	Credentials map[string]*privacy.Advice `json:"credentials"`
 }
 
 // ListCredentialsResponse represents the response from listing credentials
 type ListCredentialsResponse struct {
 	Credentials []*vc.VerifiableCredential `json:"credentials"`
+	// StatusNotices are the latest status notifications received for the listed credentials
+	StatusNotices []*vc.StatusNotification `json:"statusNotices,omitempty"`
+	// Statuses holds the wallet's latest known status of each listed credential with status
+	// entries, keyed by credential ID
+	Statuses map[string]CredentialStatusDTO `json:"statuses,omitempty"`
+}
+
+// CredentialStatusDTO is the wallet's latest known status of a credential
+type CredentialStatusDTO struct {
+	// State is active, suspended, revoked or unchecked
+	State     string     `json:"state"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+}
+
+// SubscribeToStatusRequest represents the request to receive an issuer's status notifications
+type SubscribeToStatusRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+	IssuerDID string `json:"issuerDid" validate:"required"`
+	// Endpoint is the webhook the issuer posts notifications to, e.g. this server's
+	// /api/holder/status-notifications
+	Endpoint string `json:"endpoint" validate:"required"`
+}
+
+// MatchCredentialsRequest represents the request to find wallet credentials for a verification request
+type MatchCredentialsRequest struct {
+	HolderDID       string   `json:"holderDid" validate:"required"`
+	RequiredClaims  []string `json:"requiredClaims" validate:"required,min=1"`
+	TrustedIssuers  []string `json:"trustedIssuers,omitempty"`
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+}
+
+// CredentialMatchDTO represents a candidate credential and its suggested disclosure
+type CredentialMatchDTO struct {
+	CredentialID        string                        `json:"credentialId"`
+	Issuer              string                        `json:"issuer"`
+	Types               []string                      `json:"types"`
+	Score               MatchScoreDTO                 `json:"score"`
+	MatchedClaims       []string                      `json:"matchedClaims"`
+	MissingClaims       []string                      `json:"missingClaims,omitempty"`
+	SelectiveDisclosure SelectiveDisclosureRequestDTO `json:"selectiveDisclosure"`
+}
+
+// MatchScoreDTO represents the score breakdown of a candidate credential
+type MatchScoreDTO struct {
+	Total      float64 `json:"total"`
+	Coverage   float64 `json:"coverage"`
+	Freshness  float64 `json:"freshness"`
+	Trust      float64 `json:"trust"`
+	Minimality float64 `json:"minimality"`
+}
+
+// MatchCredentialsResponse represents the response from matching credentials
+type MatchCredentialsResponse struct {
+	Matches []CredentialMatchDTO `json:"matches"`
+}
+
+// ConsentRecordDTO represents a record of data shared with a verifier
+type ConsentRecordDTO struct {
+	ID             string                  `json:"id"`
+	PresenterDID   string                  `json:"presenterDid"`
+	VerifierDID    string                  `json:"verifierDid,omitempty"`
+	PresentationID string                  `json:"presentationId"`
+	RevealedClaims map[string][]string     `json:"revealedClaims"`
+	Purpose        string                  `json:"purpose,omitempty"`
+	RetentionDays  int                     `json:"retentionDays,omitempty"`
+	Verifier       *vc.VerifierIdentity    `json:"verifier,omitempty"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	Receipt        *vc.VerificationReceipt `json:"receipt,omitempty"`
+}
+
+// StoreReceiptRequest represents the request to store a verifier's receipt
+type StoreReceiptRequest struct {
+	HolderDID string                  `json:"holderDid" validate:"required"`
+	Receipt   *vc.VerificationReceipt `json:"receipt" validate:"required"`
+}
+
+// RefreshStatusSnapshotsRequest represents the request to fetch fresh status snapshots for a wallet
+type RefreshStatusSnapshotsRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+}
+
+// RefreshStatusSnapshotsResponse represents the response from refreshing status snapshots
+type RefreshStatusSnapshotsResponse struct {
+	Refreshed int `json:"refreshed"`
+}
+
+// PrecomputeProofsRequest represents the request to precompute proof templates for a wallet's credentials
+type PrecomputeProofsRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+	// CredentialIDs defaults to every credential a proof can be derived from
+	CredentialIDs []string `json:"credentialIds,omitempty"`
+	// Count is the number of templates to keep per credential
+	Count int `json:"count"`
+}
+
+// PrecomputeProofsResponse represents the response from precomputing proof templates
+type PrecomputeProofsResponse struct {
+	Computed int `json:"computed"`
+}
+
+// ExportCommitmentsRequest represents the request to export attribute commitments for an external prover
+type ExportCommitmentsRequest struct {
+	HolderDID    string   `json:"holderDid" validate:"required"`
+	CredentialID string   `json:"credentialId" validate:"required"`
+	Claims       []string `json:"claims"`
+}
+
+// GrantEvidenceAccessRequest represents the request to let a verifier retrieve an evidence document
+type GrantEvidenceAccessRequest struct {
+	HolderDID    string `json:"holderDid" validate:"required"`
+	CredentialID string `json:"credentialId" validate:"required"`
+	EvidenceID   string `json:"evidenceId" validate:"required"`
+	VerifierDID  string `json:"verifierDid" validate:"required"`
+	// TTLSeconds is how long the grant lasts; zero means one hour
+	TTLSeconds int `json:"ttlSeconds,omitempty"`
+}
+
+// RequestAddendumRequest represents the request for a threshold addendum to a held credential
+type RequestAddendumRequest struct {
+	HolderDID    string   `json:"holderDid" validate:"required"`
+	CredentialID string   `json:"credentialId" validate:"required"`
+	Thresholds   []string `json:"thresholds" validate:"required,min=1"`
+}
+
+// ListConsentsResponse represents the response from listing consent records
+type ListConsentsResponse struct {
+	Consents []ConsentRecordDTO `json:"consents"`
+}
+
+// ExportBackupRequest represents the request to export an encrypted wallet backup
+type ExportBackupRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+	Password  string `json:"password" validate:"required,min=8"`
+}
+
+// RestoreBackupRequest represents the request to restore a wallet from a backup
+type RestoreBackupRequest struct {
+	Archive  json.RawMessage `json:"archive" validate:"required"`
+	Password string          `json:"password" validate:"required"`
+}
+
+// RestoreBackupResponse represents the response from restoring a wallet
+type RestoreBackupResponse struct {
+	HolderDID    string `json:"holderDid"`
+	Credentials  int    `json:"credentials"`
+	PairwiseDIDs int    `json:"pairwiseDids"`
+	Status       string `json:"status"`
+}
+
+// CounterOfferRequest represents the request to answer a proof request with the claims the holder will prove
+type CounterOfferRequest struct {
+	HolderDID string                `json:"holderDid" validate:"required"`
+	Request   exchange.ProofRequest `json:"request" validate:"required"`
+	Withhold  []string              `json:"withhold,omitempty"`
+	Reason    string                `json:"reason,omitempty"`
 }
 
 // ToVCSelectiveDisclosure converts DTO to vc.SelectiveDisclosureRequest slice
@@ -59,7 +307,70 @@ func ToVCSelectiveDisclosure(dtos []SelectiveDisclosureRequestDTO) []vc.Selectiv
 			CredentialID:       dto.CredentialID,
 			RevealedAttributes: dto.RevealedAttributes,
 			Nonce:              dto.Nonce,
+			HideIssuer:         dto.HideIssuer,
+			IncludeCommitments: dto.IncludeCommitments,
+			Predicates:         dto.Predicates,
+			IncludeEvidence:    dto.IncludeEvidence,
+			ProveAbsent:        dto.ProveAbsent,
 		}
 	}
 	return vcReqs
 }
+
+// RedlineCredentialRequest represents the request to preview what a selective disclosure would
+// reveal of a credential
+type RedlineCredentialRequest struct {
+	HolderDID          string                  `json:"holderDid" validate:"required"`
+	RevealedAttributes []string                `json:"revealedAttributes"`
+	HideIssuer         bool                    `json:"hideIssuer,omitempty"`
+	IncludeCommitments bool                    `json:"includeCommitments,omitempty"`
+	Predicates         []vc.PredicateStatement `json:"predicates,omitempty"`
+	ProveAbsent        []string                `json:"proveAbsent,omitempty"`
+	Purpose            string                  `json:"purpose,omitempty"`
+}
+
+// AccreditationPolicyRequest represents the request to set a holder's accreditation policy
+type AccreditationPolicyRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+	// Policy is the requirement verifiers must meet; null removes it
+	Policy *vc.AccreditationPolicy `json:"policy"`
+}
+
+// MintPresentationTokenRequest represents the request to derive a presentation now and hand it out
+// once, later, through a token
+type MintPresentationTokenRequest struct {
+	HolderDID              string                          `json:"holderDid" validate:"required"`
+	CredentialIDs          []string                        `json:"credentialIds" validate:"required,min=1"`
+	SelectiveDisclosure    []SelectiveDisclosureRequestDTO `json:"selectiveDisclosure" validate:"required,min=1"`
+	Nonce                  string                          `json:"nonce,omitempty"`
+	VerifierDID            string                          `json:"verifierDid,omitempty"`
+	UsePairwiseDID         bool                            `json:"usePairwiseDid,omitempty"`
+	IncludeStatusSnapshots bool                            `json:"includeStatusSnapshots,omitempty"`
+	Purpose                string                          `json:"purpose,omitempty"`
+	RetentionDays          int                             `json:"retentionDays,omitempty"`
+	Verifier               *vc.VerifierIdentity            `json:"verifier,omitempty"`
+	Accreditation          *vc.VerifiableCredential        `json:"accreditation,omitempty"`
+	Domain                 string                          `json:"domain,omitempty"`
+	// TTLSeconds is how long the token can be redeemed and the presentation accepted
+	TTLSeconds int `json:"ttlSeconds,omitempty"`
+}
+
+// PresentationTokenResponse represents a minted token. The secret is shown only once.
+type PresentationTokenResponse struct {
+	TokenID   string    `json:"tokenId"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// RedeemPresentationTokenRequest represents a verifier redeeming a token at its URL
+type RedeemPresentationTokenRequest struct {
+	Secret string `json:"secret" validate:"required"`
+}
+
+// RedeemPresentationTokenResponse represents a redeemed token's presentation and the nonce it was
+// derived with
+type RedeemPresentationTokenResponse struct {
+	Presentation *vc.VerifiablePresentation `json:"presentation"`
+	Nonce        string                     `json:"nonce"`
+}
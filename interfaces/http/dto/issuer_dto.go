@@ -1,6 +1,13 @@
 package dto
 
-import "github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+import (
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/transparency"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
 
 // SetupIssuerRequest represents the request to setup an issuer
 type SetupIssuerRequest struct {
@@ -16,16 +23,63 @@ type SetupIssuerResponse struct {
 
 // IssueCredentialRequest represents the request to issue a credential
 type IssueCredentialRequest struct {
-	IssuerDID   string     `json:"issuerDid" validate:"required"`
-	SubjectDID  string     `json:"subjectDid" validate:"required"`
-	Claims      []ClaimDTO `json:"claims" validate:"required,min=1"`
-	BBSProvider string     `json:"bbsProvider,omitempty"`
+	IssuerDID        string     `json:"issuerDid" validate:"required"`
+	SubjectDID       string     `json:"subjectDid" validate:"required"`
+	Claims           []ClaimDTO `json:"claims" validate:"required,min=1"`
+	TemplateID       string     `json:"templateId,omitempty"`
+	BBSProvider      string     `json:"bbsProvider,omitempty"`
+	AnonymitySet     []string   `json:"anonymitySet,omitempty"`
+	CommitAttributes []string   `json:"commitAttributes,omitempty"`
+	// Version is the data model to issue under, "1.1" (default) or "2.0"
+	Version    string     `json:"version,omitempty"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+	// IssuerName and IssuerImage make the credential's issuer an object with a display name and image
+	IssuerName  string `json:"issuerName,omitempty"`
+	IssuerImage string `json:"issuerImage,omitempty"`
+	// BindToHolder embeds the subject's authentication key as a cnf claim
+	BindToHolder bool `json:"bindToHolder,omitempty"`
+	// RequestedBy names who asked for the credential, for reviewers when issuance requires approval
+	RequestedBy string `json:"requestedBy,omitempty"`
+	// Evidence lists documents the issuer relied on, described in the credential's evidence section
+	Evidence []EvidenceDocumentDTO `json:"evidence,omitempty"`
+	// CommitClaimKeys lets the holder prove claims absent from the credential
+	CommitClaimKeys bool `json:"commitClaimKeys,omitempty"`
+	// Generalizations are coarser claim values the holder can reveal instead, as claim.level
+	Generalizations []vc.Generalization `json:"generalizations,omitempty"`
+	// ContentAddressedID derives the credential ID from its issuer, subject and claims, so a
+	// duplicate issuance is rejected with 409
+	ContentAddressedID bool `json:"contentAddressedId,omitempty"`
+	// Reissue replaces and revokes a credential issued before with the same content-addressed ID
+	Reissue bool `json:"reissue,omitempty"`
+}
+
+// EvidenceDocumentDTO represents a document attached to a credential as evidence
+type EvidenceDocumentDTO struct {
+	Type        []string `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+	MediaType   string   `json:"mediaType" validate:"required"`
+	// Document is the base64-encoded document
+	Document []byte `json:"document" validate:"required"`
+	// Encrypt stores the document sealed, so only verifiers the holder grants access can read it
+	Encrypt bool `json:"encrypt,omitempty"`
 }
 
 // ClaimDTO represents a claim in the credential
 type ClaimDTO struct {
 	Key   string      `json:"key" validate:"required"`
 	Value interface{} `json:"value" validate:"required"`
+	// Type is one of string, integer, boolean, date or decimal; inferred from value when empty
+	Type string `json:"type,omitempty"`
+	// Disclosure is not-alone or never to restrict how holders may reveal the claim
+	Disclosure string `json:"disclosure,omitempty"`
+}
+
+// AmendCredentialRequest represents the request to issue the next version of a credential
+type AmendCredentialRequest struct {
+	// Claims replace the previous version's claims with the same keys; the others are added
+	Claims []ClaimDTO `json:"claims,omitempty"`
+	// Remove lists claims of the previous version the new one leaves out
+	Remove []string `json:"remove,omitempty"`
 }
 
 // IssueCredentialResponse represents the response from issuing a credential
@@ -34,13 +88,172 @@ type IssueCredentialResponse struct {
 	Credential   *vc.VerifiableCredential `json:"credential"`
 }
 
+// BatchIssueRequest represents the request to issue credentials to many subjects asynchronously
+type BatchIssueRequest struct {
+	IssuerDID  string           `json:"issuerDid" validate:"required"`
+	TemplateID string           `json:"templateId,omitempty"`
+	Items      []BatchIssueItem `json:"items" validate:"required,min=1"`
+}
+
+// BatchIssueItem represents one subject and claim set in a batch
+type BatchIssueItem struct {
+	SubjectDID string     `json:"subjectDid" validate:"required"`
+	Claims     []ClaimDTO `json:"claims" validate:"required,min=1"`
+	TemplateID string     `json:"templateId,omitempty"`
+}
+
+// CredentialStatusResponse represents the revocation and suspension state of a credential
+type CredentialStatusResponse struct {
+	CredentialID string         `json:"credentialId"`
+	IssuerDID    string         `json:"issuerDid"`
+	Revoked      bool           `json:"revoked"`
+	Suspended    bool           `json:"suspended"`
+	Entries      []status.Entry `json:"credentialStatus"`
+}
+
+// UpdateCredentialStatusRequest is the optional body of a revoke, suspend or unsuspend request,
+// reported to the holder in the status notification
+type UpdateCredentialStatusRequest struct {
+	// Reason is an RFC 5280 reason code such as keyCompromise or superseded
+	Reason        string     `json:"reason,omitempty"`
+	EffectiveDate *time.Time `json:"effectiveDate,omitempty"`
+}
+
+// IssuedCredentialDTO represents the issuer's record of a credential it issued
+type IssuedCredentialDTO struct {
+	CredentialID string                    `json:"credentialId"`
+	SubjectDID   string                    `json:"subjectDid"`
+	Types        []string                  `json:"types"`
+	IssuedAt     time.Time                 `json:"issuedAt"`
+	ExpiresAt    *time.Time                `json:"expiresAt,omitempty"`
+	Status       *CredentialStatusResponse `json:"status,omitempty"`
+	Credential   *vc.VerifiableCredential  `json:"credential"`
+}
+
+// ListIssuedCredentialsResponse represents the response from listing issued credentials
+type ListIssuedCredentialsResponse struct {
+	Credentials []IssuedCredentialDTO `json:"credentials"`
+}
+
+// StatusSnapshotsResponse represents issuer-signed snapshots of a credential's status
+type StatusSnapshotsResponse struct {
+	CredentialID string               `json:"credentialId"`
+	Snapshots    []*vc.StatusSnapshot `json:"snapshots"`
+}
+
+// VerifyInclusionProofRequest represents the request to check an issuance log inclusion proof
+type VerifyInclusionProofRequest struct {
+	Proof    *transparency.InclusionProof `json:"proof" validate:"required"`
+	TreeHead *transparency.SignedTreeHead `json:"treeHead" validate:"required"`
+}
+
+// AnchorIssuerRequest represents the request to anchor an issuer's key set and status lists now
+type AnchorIssuerRequest struct {
+	IssuerDID string `json:"issuerDid,omitempty"`
+}
+
+// RotateKeysRequest represents the request to rotate an issuer's signing keys
+type RotateKeysRequest struct {
+	IssuerDID string `json:"issuerDid,omitempty"`
+}
+
+// EscrowKeyRequest represents the request to split an issuer's BBS+ private key among custodians
+type EscrowKeyRequest struct {
+	IssuerDID  string   `json:"issuerDid,omitempty"`
+	Custodians []string `json:"custodians" validate:"required,min=2"`
+	Threshold  int      `json:"threshold" validate:"required,min=2"`
+}
+
+// OpenedShareDTO represents a custodian's decrypted share
+type OpenedShareDTO struct {
+	EscrowID  string `json:"escrowId"`
+	IssuerDID string `json:"issuerDid"`
+	KeyID     string `json:"keyId"`
+	Threshold int    `json:"threshold"`
+	Index     int    `json:"index"`
+	Value     []byte `json:"value"`
+}
+
+// RecoverKeyRequest represents the request to rebuild an issuer's key from opened shares
+type RecoverKeyRequest struct {
+	Shares []OpenedShareDTO `json:"shares" validate:"required,min=2"`
+}
+
+// CoSigningPolicyRequest represents the request to require co-signatures for an issuer's credentials
+type CoSigningPolicyRequest struct {
+	IssuerDID string `json:"issuerDid" validate:"required"`
+	// TemplateID limits the policy to one template's credentials
+	TemplateID string `json:"templateId,omitempty"`
+	Threshold  int    `json:"threshold"`
+	// Signers is the list of co-signer DIDs; an empty list removes the policy
+	Signers []string `json:"signers"`
+}
+
+// StartSigningSessionRequest represents the request to collect co-signatures for an issuance request
+type StartSigningSessionRequest struct {
+	Request IssueCredentialRequest `json:"request" validate:"required"`
+	// Threshold and Signers default to the co-signing policy covering the request
+	Threshold  int      `json:"threshold,omitempty"`
+	Signers    []string `json:"signers,omitempty"`
+	TTLSeconds int      `json:"ttlSeconds,omitempty"`
+}
+
+// CoSignRequest represents a co-signer's signature of a signing session's statement
+type CoSignRequest struct {
+	VerificationMethod string `json:"verificationMethod" validate:"required"`
+	// Signature is the multibase Ed25519 signature; when empty the server signs with the
+	// co-signer's key, which it must hold
+	Signature string `json:"signature,omitempty"`
+}
+
+// AuthorizeIssuerRequest represents the request to authorize a sub-issuer
+type AuthorizeIssuerRequest struct {
+	IssuerDID    string `json:"issuerDid" validate:"required"`
+	SubIssuerDID string `json:"subIssuerDid" validate:"required"`
+	// CredentialTypes limits the types the sub-issuer may issue; empty allows any
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+	// MayDelegate lets the sub-issuer authorize sub-issuers of its own
+	MayDelegate bool       `json:"mayDelegate,omitempty"`
+	ValidUntil  *time.Time `json:"validUntil,omitempty"`
+}
+
+// ApprovalDecisionRequest represents an approver's decision on a held issuance request
+type ApprovalDecisionRequest struct {
+	Approver string `json:"approver" validate:"required"`
+	// Comment is required when rejecting
+	Comment string `json:"comment,omitempty"`
+}
+
+// ApprovalCommentRequest represents a reviewer's comment on a held issuance request
+type ApprovalCommentRequest struct {
+	Author string `json:"author" validate:"required"`
+	Text   string `json:"text" validate:"required"`
+}
+
+// RegisterThresholdRequest represents a verifier's request for an issuer to evaluate a threshold
+type RegisterThresholdRequest struct {
+	IssuerDID   string                 `json:"issuerDid,omitempty"`
+	VerifierDID string                 `json:"verifierDid,omitempty"`
+	Threshold   vc.ThresholdDefinition `json:"threshold" validate:"required"`
+}
+
+// LinkDomainRequest represents the request to link an issuer DID to a domain it is published on
+type LinkDomainRequest struct {
+	IssuerDID string `json:"issuerDid,omitempty"`
+	Origin    string `json:"origin" validate:"required"`
+	// ValidDays defaults to a year
+	ValidDays int `json:"validDays,omitempty"`
+}
+
 // ToVCClaims converts ClaimDTO slice to vc.Claim slice
 func ToVCClaims(claims []ClaimDTO) []vc.Claim {
 	vcClaims := make([]vc.Claim, len(claims))
 	for i, claim := range claims {
 		vcClaims[i] = vc.Claim{
-			Key:   claim.Key,
-			Value: claim.Value,
+			Key:        claim.Key,
+			Value:      claim.Value,
+			Type:       schema.ClaimType(claim.Type),
+			Disclosure: schema.DisclosureRestriction(claim.Disclosure),
 		}
 	}
 	return vcClaims
@@ -1,6 +1,13 @@
 package dto
 
-import "github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+import (
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
 
 // SetupVerifierRequest represents the request to setup a verifier
 type SetupVerifierRequest struct {
@@ -16,38 +23,324 @@ type SetupVerifierResponse struct {
 
 // VerifyPresentationRequest represents the request to verify a presentation
 type VerifyPresentationRequest struct {
-	Presentation      *vc.VerifiablePresentation `json:"presentation" validate:"required"`
-	RequiredClaims    []string                   `json:"requiredClaims"`
-	TrustedIssuers    []string                   `json:"trustedIssuers"`
-	VerificationNonce string                     `json:"verificationNonce"`
-	BBSProvider       string                     `json:"bbsProvider,omitempty"`
+	Presentation *vc.VerifiablePresentation `json:"presentation"`
+	// Barcode holds the scanned QR code payloads of the presentation, in place of presentation
+	Barcode                   []string                      `json:"barcode,omitempty"`
+	Template                  string                        `json:"template,omitempty"`
+	RequiredClaims            []string                      `json:"requiredClaims"`
+	OptionalClaims            []string                      `json:"optionalClaims,omitempty"`
+	AllowedClaims             []string                      `json:"allowedClaims,omitempty"`
+	MaxRevealedClaims         int                           `json:"maxRevealedClaims,omitempty"`
+	OverDisclosure            string                        `json:"overDisclosure,omitempty"`
+	TrustedIssuers            []string                      `json:"trustedIssuers"`
+	TrustedDomains            []string                      `json:"trustedDomains,omitempty"`
+	TrustedRoots              []string                      `json:"trustedRoots,omitempty"`
+	ClaimConstraints          map[string]vc.ClaimConstraint `json:"claimConstraints,omitempty"`
+	RequiredPredicates        []vc.PredicateStatement       `json:"requiredPredicates,omitempty"`
+	AbsentClaims              []string                      `json:"absentClaims,omitempty"`
+	MinCoSigners              int                           `json:"minCoSigners,omitempty"`
+	HolderBinding             bool                          `json:"holderBinding,omitempty"`
+	VerificationNonce         string                        `json:"verificationNonce"`
+	Domain                    string                        `json:"domain,omitempty"`
+	MaxPresentationAgeSeconds int                           `json:"maxPresentationAgeSeconds,omitempty"`
+	MaxStatusAgeSeconds       int                           `json:"maxStatusAgeSeconds,omitempty"`
+	Purpose                   string                        `json:"purpose,omitempty"`
+	RetentionDays             int                           `json:"retentionDays,omitempty"`
+	Verifier                  *vc.VerifierIdentity          `json:"verifier,omitempty"`
+	VerifierDID               string                        `json:"verifierDid,omitempty"`
+	BBSProvider               string                        `json:"bbsProvider,omitempty"`
+	// Strict reports lint findings about the presentation as warnings
+	Strict bool `json:"strict,omitempty"`
 }
 
 // VerifyPresentationResponse represents the response from verifying a presentation
 type VerifyPresentationResponse struct {
-	Valid           bool                   `json:"valid"`
-	Errors          []string               `json:"errors,omitempty"`
-	RevealedClaims  map[string]interface{} `json:"revealedClaims,omitempty"`
-	HolderDID       string                 `json:"holderDid"`
-	IssuerDIDs      []string               `json:"issuerDids"`
-	CredentialTypes []string               `json:"credentialTypes"`
+	Valid                  bool                      `json:"valid"`
+	Errors                 []string                  `json:"errors,omitempty"`
+	Indeterminate          bool                      `json:"indeterminate,omitempty"`
+	RevealedClaims         map[string]interface{}    `json:"revealedClaims,omitempty"`
+	HolderDID              string                    `json:"holderDid"`
+	IssuerDIDs             []string                  `json:"issuerDids"`
+	IssuerSets             [][]string                `json:"issuerSets,omitempty"`
+	CredentialTypes        []string                  `json:"credentialTypes"`
+	ProvidedOptionalClaims []string                  `json:"providedOptionalClaims,omitempty"`
+	MissingOptionalClaims  []string                  `json:"missingOptionalClaims,omitempty"`
+	Commitments            []*vc.CommitmentBundle    `json:"commitments,omitempty"`
+	Evidence               []*vc.EvidenceBundle      `json:"evidence,omitempty"`
+	CoSignatures           []*vc.CoSignatureEnvelope `json:"coSignatures,omitempty"`
+	DelegationChains       [][]string                `json:"delegationChains,omitempty"`
+	ProvenPredicates       []vc.PredicateStatement   `json:"provenPredicates,omitempty"`
+	AbsentClaims           []string                  `json:"absentClaims,omitempty"`
+	OverDisclosedClaims    []string                  `json:"overDisclosedClaims,omitempty"`
+	Purpose                string                    `json:"purpose,omitempty"`
+	RetentionDays          int                       `json:"retentionDays,omitempty"`
+	Verifier               *vc.VerifierIdentity      `json:"verifier,omitempty"`
+	Receipt                *vc.VerificationReceipt   `json:"receipt,omitempty"`
+	ReportID               string                    `json:"reportId,omitempty"`
+	Warnings               []lint.Finding            `json:"warnings,omitempty"`
 }
 
 // CreateVerificationRequestRequest represents the request to create a verification request
 type CreateVerificationRequestRequest struct {
-	RequiredClaims    []string `json:"requiredClaims" validate:"required,min=1"`
-	TrustedIssuers    []string `json:"trustedIssuers"`
-	VerificationNonce string   `json:"verificationNonce"`
+	Template                  string                        `json:"template,omitempty"`
+	RequiredClaims            []string                      `json:"requiredClaims"`
+	OptionalClaims            []string                      `json:"optionalClaims,omitempty"`
+	TrustedIssuers            []string                      `json:"trustedIssuers"`
+	ClaimConstraints          map[string]vc.ClaimConstraint `json:"claimConstraints,omitempty"`
+	VerificationNonce         string                        `json:"verificationNonce"`
+	OverDisclosure            string                        `json:"overDisclosure,omitempty"`
+	MaxPresentationAgeSeconds int                           `json:"maxPresentationAgeSeconds,omitempty"`
+	Purpose                   string                        `json:"purpose,omitempty"`
+	RetentionDays             int                           `json:"retentionDays,omitempty"`
+	Verifier                  *vc.VerifierIdentity          `json:"verifier,omitempty"`
+	Accreditation             *vc.VerifiableCredential      `json:"accreditation,omitempty"`
 }
 
 // CreateVerificationRequestResponse represents the response from creating a verification request
 type CreateVerificationRequestResponse struct {
-	RequiredClaims    []string `json:"requiredClaims"`
-	TrustedIssuers    []string `json:"trustedIssuers"`
-	VerificationNonce string   `json:"verificationNonce"`
+	Template                  string                        `json:"template,omitempty"`
+	RequiredClaims            []string                      `json:"requiredClaims"`
+	OptionalClaims            []string                      `json:"optionalClaims,omitempty"`
+	TrustedIssuers            []string                      `json:"trustedIssuers"`
+	ClaimConstraints          map[string]vc.ClaimConstraint `json:"claimConstraints,omitempty"`
+	VerificationNonce         string                        `json:"verificationNonce"`
+	OverDisclosure            string                        `json:"overDisclosure,omitempty"`
+	MaxPresentationAgeSeconds int                           `json:"maxPresentationAgeSeconds,omitempty"`
+	Purpose                   string                        `json:"purpose,omitempty"`
+	RetentionDays             int                           `json:"retentionDays,omitempty"`
+	Verifier                  *vc.VerifierIdentity          `json:"verifier,omitempty"`
+	Accreditation             *vc.VerifiableCredential      `json:"accreditation,omitempty"`
+}
+
+// RequestTemplateDTO describes a built-in verification request template
+type RequestTemplateDTO struct {
+	Name                      string                        `json:"name"`
+	Description               string                        `json:"description"`
+	RequiredClaims            []string                      `json:"requiredClaims"`
+	OptionalClaims            []string                      `json:"optionalClaims,omitempty"`
+	ClaimConstraints          map[string]vc.ClaimConstraint `json:"claimConstraints,omitempty"`
+	OverDisclosure            string                        `json:"overDisclosure,omitempty"`
+	MaxPresentationAgeSeconds int                           `json:"maxPresentationAgeSeconds,omitempty"`
+	Purpose                   string                        `json:"purpose,omitempty"`
+	RetentionDays             int                           `json:"retentionDays,omitempty"`
+}
+
+// ListRequestTemplatesResponse lists the built-in verification request templates
+type ListRequestTemplatesResponse struct {
+	Templates []RequestTemplateDTO `json:"templates"`
+}
+
+// StartNegotiationRequest represents the request to open a negotiable proof request
+type StartNegotiationRequest struct {
+	VerifierDID    string               `json:"verifierDid,omitempty"`
+	RequiredClaims []string             `json:"requiredClaims" validate:"required,min=1"`
+	OptionalClaims []string             `json:"optionalClaims,omitempty"`
+	TrustedIssuers []string             `json:"trustedIssuers,omitempty"`
+	Nonce          string               `json:"nonce,omitempty"`
+	Purpose        string               `json:"purpose,omitempty"`
+	RetentionDays  int                  `json:"retentionDays,omitempty"`
+	Verifier       *vc.VerifierIdentity `json:"verifier,omitempty"`
+}
+
+// RespondToNegotiationRequest represents a holder's counter-offer to a negotiation
+type RespondToNegotiationRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+	exchange.CounterOffer
+}
+
+// RespondToNegotiationResponse represents the verifier's decision and the updated negotiation
+type RespondToNegotiationResponse struct {
+	Decision    *exchange.Message     `json:"decision"`
+	Negotiation *exchange.Negotiation `json:"negotiation"`
+}
+
+// VerifyNegotiatedPresentationRequest represents the request to verify a presentation of the agreed claims
+type VerifyNegotiatedPresentationRequest struct {
+	Presentation *vc.VerifiablePresentation `json:"presentation" validate:"required"`
+}
+
+// CreateSessionRequest represents the request to open a cross-device presentation session
+type CreateSessionRequest struct {
+	VerifierDID    string               `json:"verifierDid,omitempty"`
+	RequiredClaims []string             `json:"requiredClaims" validate:"required,min=1"`
+	OptionalClaims []string             `json:"optionalClaims,omitempty"`
+	TrustedIssuers []string             `json:"trustedIssuers,omitempty"`
+	Purpose        string               `json:"purpose,omitempty"`
+	RetentionDays  int                  `json:"retentionDays,omitempty"`
+	Verifier       *vc.VerifierIdentity `json:"verifier,omitempty"`
+	// TTLSeconds overrides the server's session lifetime
+	TTLSeconds int `json:"ttlSeconds,omitempty"`
+	// CallbackURL makes the session forward a signed assertion of its outcome to the relying
+	// party, and RedirectURI is where the holder's device is then sent
+	CallbackURL string `json:"callbackUrl,omitempty"`
+	RedirectURI string `json:"redirectUri,omitempty"`
+}
+
+// CreateSessionResponse represents an opened session and the links the holder's device follows
+type CreateSessionResponse struct {
+	SessionID   string    `json:"sessionId"`
+	State       string    `json:"state"`
+	Nonce       string    `json:"nonce"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	RequestURI  string    `json:"requestUri"`
+	ResponseURI string    `json:"responseUri"`
+	// QRPayload is the text to render as a QR code on the verifier's screen
+	QRPayload string `json:"qrPayload"`
+}
+
+// SessionRequestResponse represents a session's proof request as fetched by the holder's device
+type SessionRequestResponse struct {
+	SessionID   string                `json:"sessionId"`
+	Request     exchange.ProofRequest `json:"request"`
+	ResponseURI string                `json:"responseUri"`
+	ExpiresAt   time.Time             `json:"expiresAt"`
+}
+
+// SubmitSessionPresentationRequest represents the holder's answer to a session
+type SubmitSessionPresentationRequest struct {
+	Presentation *vc.VerifiablePresentation `json:"presentation" validate:"required"`
+}
+
+// SubmitSessionPresentationResponse tells the holder's device how its presentation was received
+type SubmitSessionPresentationResponse struct {
+	State  string   `json:"state"`
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// SessionForwardAck is all the holder's device is told about a forwarding session's presentation:
+// the outcome goes to the relying party
+type SessionForwardAck struct {
+	State       string `json:"state"`
+	RedirectURI string `json:"redirectUri,omitempty"`
+}
+
+// VerifyPresentationTokenRequest represents the request to redeem a holder's presentation token and
+// verify its presentation in a session
+type VerifyPresentationTokenRequest struct {
+	Secret         string               `json:"secret" validate:"required"`
+	VerifierDID    string               `json:"verifierDid,omitempty"`
+	RequiredClaims []string             `json:"requiredClaims" validate:"required,min=1"`
+	OptionalClaims []string             `json:"optionalClaims,omitempty"`
+	TrustedIssuers []string             `json:"trustedIssuers,omitempty"`
+	Purpose        string               `json:"purpose,omitempty"`
+	RetentionDays  int                  `json:"retentionDays,omitempty"`
+	Verifier       *vc.VerifierIdentity `json:"verifier,omitempty"`
+}
+
+// GenerateNonceResponse represents a server-generated proof nonce
+type GenerateNonceResponse struct {
+	Nonce string `json:"nonce"`
+}
+
+// CacheStatsDTO represents how a resolution cache answered lookups
+type CacheStatsDTO struct {
+	Hits          uint64  `json:"hits"`
+	Revalidations uint64  `json:"revalidations"`
+	Misses        uint64  `json:"misses"`
+	Errors        uint64  `json:"errors"`
+	HitRate       float64 `json:"hitRate"`
+}
+
+// CacheStatsResponse represents the verifier's DID and status list cache statistics
+type CacheStatsResponse struct {
+	Caches map[string]CacheStatsDTO `json:"caches"`
+}
+
+// FromFetchStats converts cache counters to a DTO
+func FromFetchStats(stats fetch.Stats) CacheStatsDTO {
+	return CacheStatsDTO{
+		Hits:          stats.Hits,
+		Revalidations: stats.Revalidations,
+		Misses:        stats.Misses,
+		Errors:        stats.Errors,
+		HitRate:       stats.HitRate(),
+	}
+}
+
+// PresentationRecordDTO represents a presentation a verifier received and how it verified
+type PresentationRecordDTO struct {
+	PresentationID string                     `json:"presentationId"`
+	HolderDID      string                     `json:"holderDid"`
+	VerifierDID    string                     `json:"verifierDid"`
+	VerifiedAt     time.Time                  `json:"verifiedAt"`
+	Valid          bool                       `json:"valid"`
+	Errors         []string                   `json:"errors,omitempty"`
+	Presentation   *vc.VerifiablePresentation `json:"presentation"`
 }
 
 // ListPresentationsResponse represents the response from listing presentations
 type ListPresentationsResponse struct {
-	Presentations []*vc.VerifiablePresentation `json:"presentations"`
+	Presentations []PresentationRecordDTO `json:"presentations"`
+}
+
+// AuditEntryDTO represents a verification audit log entry
+type AuditEntryDTO struct {
+	Timestamp      time.Time            `json:"timestamp"`
+	PresentationID string               `json:"presentationId"`
+	HolderDID      string               `json:"holderDid"`
+	Valid          bool                 `json:"valid"`
+	RevealedClaims []string             `json:"revealedClaims,omitempty"`
+	Purpose        string               `json:"purpose,omitempty"`
+	RetentionDays  int                  `json:"retentionDays,omitempty"`
+	Verifier       *vc.VerifierIdentity `json:"verifier,omitempty"`
+	RetainUntil    *time.Time           `json:"retainUntil,omitempty"`
+}
+
+// ListAuditLogResponse represents the response from listing the audit log
+type ListAuditLogResponse struct {
+	Entries []AuditEntryDTO `json:"entries"`
+}
+
+// ValidateReceiptRequest represents the request to validate a verification receipt
+type ValidateReceiptRequest struct {
+	Receipt      *vc.VerificationReceipt    `json:"receipt" validate:"required"`
+	Presentation *vc.VerifiablePresentation `json:"presentation,omitempty"`
+}
+
+// ValidateReceiptResponse represents the response from validating a verification receipt
+type ValidateReceiptResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// ValidateAssertionRequest represents the request to validate a forwarded assertion
+type ValidateAssertionRequest struct {
+	Assertion *vc.VerificationAssertion `json:"assertion" validate:"required"`
+	// Audience is the callback URL the assertion must have been forwarded to
+	Audience string `json:"audience,omitempty"`
+}
+
+// ValidateAssertionResponse represents the response from validating a forwarded assertion
+type ValidateAssertionResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// VerifyContinuityRequest represents the request to verify a continuity proof
+type VerifyContinuityRequest struct {
+	Proof *vc.ContinuityProof `json:"proof" validate:"required"`
+	Nonce string              `json:"nonce" validate:"required"`
+}
+
+// VerifyContinuityResponse represents the response from verifying a continuity proof
+type VerifyContinuityResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// OpenEvidenceRequest represents the request to check and unseal an evidence document the issuer released
+type OpenEvidenceRequest struct {
+	// Evidence is the evidence section presented with the credential
+	Evidence *vc.EvidenceBundle `json:"evidence" validate:"required"`
+	Grant    *vc.EvidenceGrant  `json:"grant" validate:"required"`
+	// Document is the base64-encoded document as released by the issuer
+	Document []byte `json:"document" validate:"required"`
+}
+
+// OpenEvidenceResponse represents a checked evidence document
+type OpenEvidenceResponse struct {
+	EvidenceID string `json:"evidenceId"`
+	MediaType  string `json:"mediaType"`
+	Document   []byte `json:"document"`
 }
@@ -2,53 +2,132 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/scenario"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
 )
 
+// maxDemoStepDelayMs caps the pause between demo steps
+const maxDemoStepDelayMs = 10000
+
+// AgeVerificationHandler serves the age verification flow. It issues through the issuer directly
+// and reaches the holder's wallet and the verifier only over the event bus.
 type AgeVerificationHandler struct {
-	issuerUC   *issuer.UseCase
-	holderUC   *holder.UseCase
-	verifierUC *verifier.UseCase
+	issuerUC *issuer.UseCase
+	events   *events.Bus
+	runner   *scenario.Runner
+
+	// clock dates age credentials and computes ages
+	clock clock.Clock
+	// datePolicy reads dates of birth and decides the day ages are computed on
+	datePolicy vc.DatePolicy
+
+	// checks remembers the service and age each open age check session was created for, keyed
+	// by session ID
+	checks storage.KVStore
+}
+
+// ageCheck is what the verifier asked for when it opened an age check session
+type ageCheck struct {
+	ServiceType string
+	MinAge      int
+	ClaimKey    string
 }
 
-func NewAgeVerificationHandler(
-	issuerUC *issuer.UseCase,
-	holderUC *holder.UseCase,
-	verifierUC *verifier.UseCase,
-) *AgeVerificationHandler {
+// NewAgeVerificationHandler creates an age verification handler sending requests over bus, to
+// which the holder and verifier must be connected, and running demos with runner
+func NewAgeVerificationHandler(issuerUC *issuer.UseCase, bus *events.Bus, runner *scenario.Runner) *AgeVerificationHandler {
 	return &AgeVerificationHandler{
-		issuerUC:   issuerUC,
-		holderUC:   holderUC,
-		verifierUC: verifierUC,
+		issuerUC: issuerUC,
+		events:   bus,
+		runner:   runner,
+		clock:    clock.System{},
+		checks:   storage.NewMemoryStore(),
+	}
+}
+
+// SetStore keeps open age checks in store, so a check opened on one server instance can be
+// answered on another. Nil restores an in-memory store.
+func (h *AgeVerificationHandler) SetStore(store storage.KVStore) {
+	if store == nil {
+		store = storage.NewMemoryStore()
 	}
+	h.checks = store
+}
+
+// SetClock sets the clock ages and credential dates are computed from; nil restores the system clock
+func (h *AgeVerificationHandler) SetClock(c clock.Clock) {
+	h.clock = clock.OrSystem(c)
+}
+
+// SetDatePolicy sets the timezone ages and credential dates are computed in
+func (h *AgeVerificationHandler) SetDatePolicy(policy vc.DatePolicy) {
+	h.datePolicy = policy
+	h.runner.SetDatePolicy(policy)
 }
 
 // AgeCredentialRequest represents the request to issue an age verification credential
 type AgeCredentialRequest struct {
-	IssuerDID   string `json:"issuerDid"`
-	SubjectDID  string `json:"subjectDid"`
-	FirstName   string `json:"firstName"`
-	LastName    string `json:"lastName"`
-	DateOfBirth string `json:"dateOfBirth"`
-	Nationality string `json:"nationality"`
-	Address     string `json:"address"`
-	IDNumber    string `json:"idNumber"`
+	IssuerDID  string `json:"issuerDid"`
+	SubjectDID string `json:"subjectDid"`
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName"`
+	// DateOfBirth is a YYYY-MM-DD date, an RFC 3339 time or Unix epoch seconds
+	DateOfBirth interface{} `json:"dateOfBirth"`
+	Nationality string      `json:"nationality"`
+	Address     string      `json:"address"`
+	IDNumber    string      `json:"idNumber"`
 }
 
-// AgeVerificationRequest represents a request for age verification
+// AgeVerificationRequest represents a verifier's request to open an age check
 type AgeVerificationRequest struct {
-	HolderDID      string   `json:"holderDid"`
-	CredentialID   string   `json:"credentialId"`
+	VerifierDID    string   `json:"verifierDid,omitempty"`
 	MinAge         int      `json:"minAge"`
 	RequiredClaims []string `json:"requiredClaims"`
+	TrustedIssuers []string `json:"trustedIssuers,omitempty"`
 	ServiceType    string   `json:"serviceType"` // gaming, cinema, alcohol, etc.
+	TTLSeconds     int      `json:"ttlSeconds,omitempty"`
+}
+
+// AgeVerificationRequestResponse represents an opened age check and the proof request the
+// verifier hands to the holder's wallet
+type AgeVerificationRequestResponse struct {
+	SessionID   string                `json:"sessionId"`
+	ServiceType string                `json:"serviceType"`
+	MinAge      int                   `json:"minAge"`
+	Request     exchange.ProofRequest `json:"request"`
+	ExpiresAt   time.Time             `json:"expiresAt"`
+	VerifyURI   string                `json:"verifyUri"`
+}
+
+// AgePresentationRequest represents the holder wallet's request to answer a received age check
+type AgePresentationRequest struct {
+	HolderDID    string                `json:"holderDid"`
+	CredentialID string                `json:"credentialId"`
+	Request      exchange.ProofRequest `json:"request"`
+}
+
+// AgePresentationResponse carries the presentation the holder sends back to the verifier
+type AgePresentationResponse struct {
+	Presentation     *vc.VerifiablePresentation `json:"presentation"`
+	RevealedClaims   []string                   `json:"revealedClaims"`
+	WithheldClaims   []string                   `json:"withheldClaims,omitempty"`
+	HiddenAttributes []string                   `json:"hiddenAttributes"`
+}
+
+// AgeVerificationSubmission represents the presentation submitted to the verifier for an age check
+type AgeVerificationSubmission struct {
+	Presentation *vc.VerifiablePresentation `json:"presentation"`
 }
 
 // AgeVerificationResponse represents the response from age verification
@@ -68,20 +147,25 @@ type AgeVerificationResponse struct {
 // POST /api/age-verification/credential - Issue enhanced age verification credential
 func (h *AgeVerificationHandler) IssueAgeCredential(w http.ResponseWriter, r *http.Request) {
 	var req AgeCredentialRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	// Parse birth date to calculate age-related claims
-	birthTime, err := time.Parse("2006-01-02", req.DateOfBirth)
+	birthDate, err := h.datePolicy.ParseDate(req.DateOfBirth)
 	if err != nil {
-		writeErrorResponse(w, "Invalid date format. Use YYYY-MM-DD", http.StatusBadRequest, err.Error())
+		writeErrorResponse(w, "Invalid dateOfBirth. Use YYYY-MM-DD, an RFC 3339 time or epoch seconds", http.StatusBadRequest, err.Error())
 		return
 	}
 
-	currentAge := calculateAge(birthTime)
-	birthYear := birthTime.Year()
+	now := h.clock.Now()
+	today := h.datePolicy.Today(now)
+	if birthDate.After(today) {
+		writeErrorResponse(w, "Invalid dateOfBirth", http.StatusBadRequest, "date of birth is in the future")
+		return
+	}
+	currentAge := h.datePolicy.AgeAt(birthDate, now)
+	birthYear := birthDate.Year()
 
 	// Create enhanced claims with age verification
 	claims := []vc.Claim{
@@ -89,7 +173,7 @@ func (h *AgeVerificationHandler) IssueAgeCredential(w http.ResponseWriter, r *ht
 		{Key: "firstName", Value: req.FirstName},
 		{Key: "lastName", Value: req.LastName},
 		{Key: "fullName", Value: fmt.Sprintf("%s %s", req.FirstName, req.LastName)},
-		{Key: "dateOfBirth", Value: req.DateOfBirth},
+		{Key: "dateOfBirth", Value: birthDate.Format(vc.DateLayout)},
 		{Key: "nationality", Value: req.Nationality},
 		{Key: "address", Value: req.Address},
 		{Key: "idNumber", Value: req.IDNumber},
@@ -106,11 +190,11 @@ func (h *AgeVerificationHandler) IssueAgeCredential(w http.ResponseWriter, r *ht
 		{Key: "birthYear", Value: birthYear},
 		{Key: "ageCategory", Value: getAgeCategory(currentAge)},
 		{Key: "documentType", Value: "national_id"},
-		{Key: "issuedAt", Value: time.Now().Format("2006-01-02")},
-		{Key: "validUntil", Value: time.Now().AddDate(10, 0, 0).Format("2006-01-02")},
+		{Key: "issuedAt", Value: today.Format(vc.DateLayout)},
+		{Key: "validUntil", Value: today.AddDate(10, 0, 0).Format(vc.DateLayout)},
 	}
 
-	credential, err := h.issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+	credential, err := h.issuerUC.IssueCredentialContext(r.Context(), issuer.IssueCredentialRequest{
 		IssuerDID:  req.IssuerDID,
 		SubjectDID: req.SubjectDID,
 		Claims:     claims,
@@ -120,12 +204,8 @@ func (h *AgeVerificationHandler) IssueAgeCredential(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Automatically store the credential for the holder
-	if err := h.holderUC.StoreCredential(credential); err != nil {
-		writeErrorResponse(w, "Failed to store credential", http.StatusInternalServerError, err.Error())
-		return
-	}
-
+	// The issuer does not write to the holder's wallet; the holder stores the credential it
+	// receives through POST /api/holder/credentials
 	response := map[string]interface{}{
 		"success":    true,
 		"credential": credential,
@@ -138,23 +218,26 @@ func (h *AgeVerificationHandler) IssueAgeCredential(w http.ResponseWriter, r *ht
 			"ageOver25": currentAge >= 25,
 			"ageOver65": currentAge >= 65,
 		},
-		"message": fmt.Sprintf("Enhanced age verification credential issued and stored for %d-year-old citizen", currentAge),
+		"message": fmt.Sprintf("Enhanced age verification credential issued for %d-year-old citizen", currentAge),
 	}
 
 	writeJSONResponse(w, http.StatusCreated, response)
 }
 
-// POST /api/age-verification/verify - Verify age with privacy preservation
-func (h *AgeVerificationHandler) VerifyAge(w http.ResponseWriter, r *http.Request) {
-	var req AgeVerificationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+// POST /api/age-verification/requests - Verifier opens an age check for the holder's wallet to answer
+func (h *AgeVerificationHandler) CreateAgeRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	// Validate input
-	if req.HolderDID == "" || req.CredentialID == "" {
-		writeErrorResponse(w, "Missing required fields: holderDid and credentialId", http.StatusBadRequest, "")
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req AgeVerificationRequest
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -165,89 +248,177 @@ func (h *AgeVerificationHandler) VerifyAge(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Create selective disclosure - only reveal age verification and required claims
-	revealedAttributes := []string{ageClaimKey, "nationality", "documentType"}
-	if req.RequiredClaims != nil {
-		revealedAttributes = append(revealedAttributes, req.RequiredClaims...)
+	if req.TTLSeconds < 0 {
+		writeErrorResponse(w, "Invalid ttlSeconds", http.StatusBadRequest, "ttlSeconds cannot be negative")
+		return
 	}
 
-	// Remove duplicates
-	revealedAttributes = removeDuplicates(revealedAttributes)
+	// Only the age claim must be disclosed; nationality and document type are asked for but may
+	// be withheld by the holder
+	requiredClaims := removeDuplicates(append([]string{ageClaimKey}, req.RequiredClaims...))
+	var optionalClaims []string
+	for _, claim := range []string{"nationality", "documentType"} {
+		if !containsString(requiredClaims, claim) {
+			optionalClaims = append(optionalClaims, claim)
+		}
+	}
 
-	selectiveDisclosure := []vc.SelectiveDisclosureRequest{
-		{
-			CredentialID:       req.CredentialID,
-			RevealedAttributes: revealedAttributes,
+	reply, err := h.events.Request(r.Context(), events.New(events.RequestPresentation, events.RequestPresentationData{
+		VerifierDID: req.VerifierDID,
+		Request: exchange.ProofRequest{
+			RequiredClaims: requiredClaims,
+			OptionalClaims: optionalClaims,
+			TrustedIssuers: req.TrustedIssuers,
+			RequestMetadata: vc.RequestMetadata{
+				Purpose: fmt.Sprintf("Age verification (%d+) for %s", req.MinAge, req.ServiceType),
+			},
 		},
+		TTL: time.Duration(req.TTLSeconds) * time.Second,
+	}))
+	if err != nil {
+		writeErrorResponse(w, "Failed to create age verification request", http.StatusBadRequest, err.Error())
+		return
 	}
+	session := reply.(events.PresentationRequestedData)
 
-	// Generate verification nonce
-	verificationNonce := fmt.Sprintf("%s-age-verification-%d", req.ServiceType, time.Now().UnixMilli())
+	check := ageCheck{ServiceType: req.ServiceType, MinAge: req.MinAge, ClaimKey: ageClaimKey}
+	if err := storage.SetJSON(h.checks, session.SessionID, &check, 0); err != nil {
+		writeErrorResponse(w, "Failed to create age verification request", http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	// Create presentation with error handling
-	presentation, err := h.holderUC.CreatePresentation(holder.PresentationRequest{
-		HolderDID:           req.HolderDID,
-		CredentialIDs:       []string{req.CredentialID},
-		SelectiveDisclosure: selectiveDisclosure,
-		Nonce:               verificationNonce,
+	writeJSONResponse(w, http.StatusCreated, AgeVerificationRequestResponse{
+		SessionID:   session.SessionID,
+		ServiceType: req.ServiceType,
+		MinAge:      req.MinAge,
+		Request:     session.Request,
+		ExpiresAt:   session.ExpiresAt,
+		VerifyURI:   "/api/age-verification/requests/" + session.SessionID + "/verify",
 	})
+}
+
+// POST /api/age-verification/presentations - Holder wallet answers a received age check.
+// The wallet only sees the proof request; it never talks to the verifier's session here.
+func (h *AgeVerificationHandler) CreateAgePresentation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req AgePresentationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || req.CredentialID == "" {
+		writeErrorResponse(w, "Missing required fields: holderDid and credentialId", http.StatusBadRequest, "")
+		return
+	}
+
+	if err := req.Request.Validate(); err != nil {
+		writeErrorResponse(w, "Invalid proof request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Request.Nonce == "" {
+		writeErrorResponse(w, "Invalid proof request", http.StatusBadRequest, "the verifier's nonce is missing")
+		return
+	}
+
+	// The wallet reveals every required claim and the optional ones the credential carries
+	reply, err := h.events.Request(r.Context(), events.New(events.CreatePresentation, events.CreatePresentationData{
+		HolderDID:    req.HolderDID,
+		CredentialID: req.CredentialID,
+		Request:      req.Request,
+	}))
+	if errors.Is(err, holder.ErrCredentialNotFound) {
+		writeErrorResponse(w, "Credential not found", http.StatusNotFound, err.Error())
+		return
+	}
 	if err != nil {
-		writeErrorResponse(w, "Failed to create presentation", http.StatusInternalServerError,
+		writeErrorResponse(w, "Failed to create presentation", http.StatusBadRequest,
 			fmt.Sprintf("Could not create presentation for credential %s. Error: %v", req.CredentialID, err))
 		return
 	}
+	answer := reply.(events.PresentationCreatedData)
 
-	// For demo purposes, we'll simulate the verifier part here
-	// In a real scenario, this would be done by the verifier service
-	// We need to get the issuer DID from the credential for trusted issuers list
-	var trustedIssuers []string
-	if len(presentation.VerifiableCredential) > 0 {
-		if credMap, ok := presentation.VerifiableCredential[0].(map[string]interface{}); ok {
-			if issuer, exists := credMap["issuer"]; exists {
-				if issuerStr, ok := issuer.(string); ok {
-					trustedIssuers = []string{issuerStr}
-				}
-			}
-		}
+	writeJSONResponse(w, http.StatusCreated, AgePresentationResponse{
+		Presentation:     answer.Presentation,
+		RevealedClaims:   answer.RevealedClaims,
+		WithheldClaims:   answer.WithheldClaims,
+		HiddenAttributes: answer.HiddenClaims,
+	})
+}
+
+// POST /api/age-verification/requests/{id}/verify - Verifier checks the presentation answering its age check
+func (h *AgeVerificationHandler) VerifyAge(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
 	}
 
-	verificationResult, err := h.verifierUC.VerifyPresentation(verifier.VerificationRequest{
-		Presentation:      presentation,
-		RequiredClaims:    []string{ageClaimKey},
-		TrustedIssuers:    trustedIssuers,
-		VerificationNonce: verificationNonce,
-	})
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req AgeVerificationSubmission
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	id := r.PathValue("id")
+	var check ageCheck
+	if err := storage.GetJSON(h.checks, id, &check); errors.Is(err, storage.ErrNotFound) {
+		writeErrorResponse(w, "Age verification request not found", http.StatusNotFound, fmt.Sprintf("no age check for session %s", id))
+		return
+	} else if err != nil {
+		writeErrorResponse(w, "Failed to load age verification request", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The session checks the nonce, required claims and trusted issuers of its proof request
+	// and accepts a single presentation
+	reply, err := h.events.Request(r.Context(), events.New(events.SubmitPresentation, events.SubmitPresentationData{
+		SessionID:    id,
+		Presentation: req.Presentation,
+	}))
 	if err != nil {
-		writeErrorResponse(w, "Failed to verify presentation", http.StatusInternalServerError, err.Error())
+		writeErrorResponse(w, "Failed to verify presentation", http.StatusBadRequest, err.Error())
 		return
 	}
+	verificationResult := reply.(events.PresentationVerifiedData)
+
+	// The session accepts no further presentation, so the check is done with either way
+	h.checks.Delete(id)
 
 	// Check age verification result
 	var ageVerified bool
-	var accessGranted bool
-	if ageValue, ok := verificationResult.RevealedClaims[ageClaimKey].(bool); ok {
+	if ageValue, ok := verificationResult.RevealedClaims[check.ClaimKey].(bool); ok {
 		ageVerified = ageValue
-		accessGranted = ageValue
 	}
+	accessGranted := verificationResult.Valid && ageVerified
 
 	// Identify hidden attributes (privacy-protected information)
 	hiddenAttributes := []string{
 		"firstName", "lastName", "fullName", "dateOfBirth", "address", "idNumber", "birthYear",
 	}
 
-	// Generate appropriate message
-	message := generateAgeVerificationMessage(req.ServiceType, req.MinAge, accessGranted)
-
 	response := AgeVerificationResponse{
 		Success:          verificationResult.Valid,
 		AccessGranted:    accessGranted,
-		ServiceType:      req.ServiceType,
-		MinAgeRequired:   req.MinAge,
+		ServiceType:      check.ServiceType,
+		MinAgeRequired:   check.MinAge,
 		AgeVerified:      ageVerified,
 		RevealedClaims:   verificationResult.RevealedClaims,
 		HiddenAttributes: hiddenAttributes,
 		PrivacyProtected: true,
-		Message:          message,
+		Message:          generateAgeVerificationMessage(check.ServiceType, check.MinAge, accessGranted),
 	}
 
 	if !verificationResult.Valid {
@@ -321,9 +492,21 @@ func (h *AgeVerificationHandler) GetAgeScenarios(w http.ResponseWriter, r *http.
 
 // POST /api/age-verification/demo - Run complete age verification demo
 func (h *AgeVerificationHandler) RunAgeDemo(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
 	var req struct {
 		ServiceType string `json:"serviceType"`
 		MinAge      int    `json:"minAge"`
+		DateOfBirth string `json:"dateOfBirth"`
+		StepDelayMs int    `json:"stepDelayMs"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -331,58 +514,34 @@ func (h *AgeVerificationHandler) RunAgeDemo(w http.ResponseWriter, r *http.Reque
 		req.MinAge = 18
 	}
 
-	// This would run the complete demo flow
-	// For now, return a demo execution plan
+	if req.StepDelayMs < 0 || req.StepDelayMs > maxDemoStepDelayMs {
+		writeErrorResponse(w, "Invalid step delay", http.StatusBadRequest, fmt.Sprintf("stepDelayMs must be between 0 and %d", maxDemoStepDelayMs))
+		return
+	}
+
+	run, err := h.runner.StartAgeDemo(scenario.AgeDemoParams{
+		ServiceType: req.ServiceType,
+		MinAge:      req.MinAge,
+		DateOfBirth: req.DateOfBirth,
+		StepDelay:   time.Duration(req.StepDelayMs) * time.Millisecond,
+	})
+	if err != nil {
+		writeErrorResponse(w, "Failed to start demo", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	minAge := req.MinAge
+	if minAge == 0 {
+		minAge = 18
+	}
+
 	response := map[string]interface{}{
-		"demo_plan": []map[string]string{
-			{
-				"step":        "1",
-				"title":       "Setup Government Authority",
-				"description": "Initialize digital ID issuer with BBS+ keys",
-				"status":      "ready",
-			},
-			{
-				"step":        "2",
-				"title":       "Setup Citizen",
-				"description": "Create citizen DID for credential holder",
-				"status":      "ready",
-			},
-			{
-				"step":        "3",
-				"title":       fmt.Sprintf("Setup %s Service", req.ServiceType),
-				"description": fmt.Sprintf("Initialize %s platform for age verification", req.ServiceType),
-				"status":      "ready",
-			},
-			{
-				"step":        "4",
-				"title":       "Issue Enhanced ID",
-				"description": "Government issues digital ID with age verification claims",
-				"status":      "ready",
-			},
-			{
-				"step":        "5",
-				"title":       "Age Verification Request",
-				"description": fmt.Sprintf("Service requests %d+ age verification", req.MinAge),
-				"status":      "ready",
-			},
-			{
-				"step":        "6",
-				"title":       "Privacy-Preserving Presentation",
-				"description": "Citizen creates selective disclosure presentation",
-				"status":      "ready",
-			},
-			{
-				"step":        "7",
-				"title":       "Verification",
-				"description": "Service verifies age without seeing personal details",
-				"status":      "ready",
-			},
-		},
-		"service_type": req.ServiceType,
-		"min_age":      req.MinAge,
+		"run":        run,
+		"status_url": "/api/age-verification/demo/" + run.ID,
+		"events_url": "/api/age-verification/demo/" + run.ID + "/events",
 		"privacy_protection": map[string]interface{}{
 			"revealed": []string{
-				fmt.Sprintf("ageOver%d (boolean)", req.MinAge),
+				fmt.Sprintf("ageOver%d (boolean)", minAge),
 				"nationality",
 				"documentType",
 			},
@@ -393,20 +552,93 @@ func (h *AgeVerificationHandler) RunAgeDemo(w http.ResponseWriter, r *http.Reque
 		},
 	}
 
-	writeJSONResponse(w, http.StatusOK, response)
+	writeJSONResponse(w, http.StatusAccepted, response)
 }
 
-// Helper functions
+// GET /api/age-verification/demo/{id} - Poll the progress of a demo run
+func (h *AgeVerificationHandler) GetDemoRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
 
-func calculateAge(birthTime time.Time) int {
-	now := time.Now()
-	age := now.Year() - birthTime.Year()
-	if now.YearDay() < birthTime.YearDay() {
-		age--
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	run, err := h.runner.GetRun(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Demo run not found", http.StatusNotFound, err.Error())
+		return
 	}
-	return age
+
+	writeSuccessResponse(w, run)
 }
 
+// GET /api/age-verification/demo/{id}/events - Stream the progress of a demo run as server-sent events
+func (h *AgeVerificationHandler) StreamDemoRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorResponse(w, "Streaming not supported", http.StatusInternalServerError, "")
+		return
+	}
+
+	id := r.PathValue("id")
+	updates, stop, err := h.runner.Watch(id)
+	if err != nil {
+		writeErrorResponse(w, "Demo run not found", http.StatusNotFound, err.Error())
+		return
+	}
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// Send the current state first, then every change until the run finishes
+	for {
+		run, err := h.runner.GetRun(id)
+		if err != nil {
+			return
+		}
+
+		event := "progress"
+		if run.Done() {
+			event = "done"
+		}
+		data, err := json.Marshal(run)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+
+		if run.Done() {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-updates:
+		}
+	}
+}
+
+// Helper functions
+
 func getAgeCategory(age int) string {
 	switch {
 	case age < 13:
@@ -457,3 +689,12 @@ func removeDuplicates(slice []string) []string {
 	}
 	return result
 }
+
+func containsString(slice []string, value string) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,18 +1,22 @@
 package handlers
 
 import (
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
 )
 
 // BBSHandler handles BBS provider testing and benchmarking
 type BBSHandler struct {
-	factory bbs.BBSServiceFactory
+	factory            bbs.BBSServiceFactory
+	diagnosticsEnabled bool
 }
 
 // NewBBSHandler creates a new BBS handler
@@ -24,8 +28,6 @@ func NewBBSHandler(factory bbs.BBSServiceFactory) *BBSHandler {
 
 // TestProvider handles POST /api/bbs/test
 func (h *BBSHandler) TestProvider(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -36,8 +38,7 @@ func (h *BBSHandler) TestProvider(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.TestBBSProviderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -53,10 +54,101 @@ func (h *BBSHandler) TestProvider(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, response)
 }
 
+// SetProofDiagnostics enables POST /api/bbs/proofs/diagnose. Reports echo revealed messages and
+// challenge inputs, so it is meant for debugging deployments.
+func (h *BBSHandler) SetProofDiagnostics(enabled bool) {
+	h.diagnosticsEnabled = enabled
+}
+
+// DiagnoseProof handles POST /api/bbs/proofs/diagnose
+func (h *BBSHandler) DiagnoseProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if !h.diagnosticsEnabled {
+		writeErrorResponse(w, "Proof diagnostics are disabled", http.StatusNotFound, "start the server with -proof-diagnostics")
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.DiagnoseProofRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	provider := bbs.ProviderProduction
+	if req.Provider != "" {
+		parsed, err := bbs.ParseProvider(req.Provider)
+		if err != nil {
+			writeErrorResponse(w, "Invalid provider", http.StatusBadRequest, err.Error())
+			return
+		}
+		provider = parsed
+	}
+	service, err := h.factory.CreateService(provider, nil)
+	if err != nil {
+		writeErrorResponse(w, "Provider unavailable", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	publicKey, err := decodeBBSPublicKey(req.PublicKey)
+	if err != nil {
+		writeErrorResponse(w, "Invalid public key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	proof, err := bbs.DecodeProof(req.ProofValue)
+	if err != nil {
+		writeErrorResponse(w, "Invalid proof value", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	revealed := make([][]byte, len(req.RevealedMessages))
+	for i, message := range req.RevealedMessages {
+		if revealed[i], err = base64.StdEncoding.DecodeString(message); err != nil {
+			writeErrorResponse(w, "Invalid revealed message", http.StatusBadRequest, fmt.Sprintf("message %d: %v", i, err))
+			return
+		}
+	}
+
+	var nonce []byte
+	switch {
+	case req.Nonce != "" && req.PresentationNonce != "":
+		writeErrorResponse(w, "Invalid nonce", http.StatusBadRequest, "give either nonce or presentationNonce")
+		return
+	case req.PresentationNonce != "":
+		if nonce, err = vc.ProofNonce(req.PresentationNonce); err != nil {
+			writeErrorResponse(w, "Invalid nonce", http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		if nonce, err = base64.StdEncoding.DecodeString(req.Nonce); err != nil {
+			writeErrorResponse(w, "Invalid nonce", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	writeSuccessResponse(w, dto.DiagnoseProofResponse{
+		Provider:         provider.String(),
+		ProofDiagnostics: bbs.DiagnoseProof(service, publicKey, proof, revealed, nonce),
+	})
+}
+
+// decodeBBSPublicKey accepts a BBS+ public key in base64 or as a DID document's publicKeyMultibase
+func decodeBBSPublicKey(encoded string) ([]byte, error) {
+	if strings.HasPrefix(encoded, "z") {
+		return did.DecodeBBSPublicKeyMultibase(encoded)
+	}
+	return base64.StdEncoding.DecodeString(encoded)
+}
+
 // BenchmarkProviders handles POST /api/bbs/benchmark
 func (h *BBSHandler) BenchmarkProviders(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -67,8 +159,7 @@ func (h *BBSHandler) BenchmarkProviders(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req dto.BenchmarkBBSProvidersRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -131,7 +222,7 @@ func (h *BBSHandler) testSingleProvider(provider bbs.Provider) dto.TestBBSProvid
 	start := time.Now()
 
 	// Test key generation
-	_, err = service.GenerateKeyPair()
+	keyPair, err := service.GenerateKeyPair()
 	if err != nil {
 		return dto.TestBBSProviderResponse{
 			Provider:  provider.String(),
@@ -142,11 +233,37 @@ func (h *BBSHandler) testSingleProvider(provider bbs.Provider) dto.TestBBSProvid
 
 	elapsed := time.Since(start)
 
+	// Test that a signature survives its wire encoding
+	messages := [][]byte{[]byte("test message for BBS+ provider check")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	if err != nil {
+		return dto.TestBBSProviderResponse{
+			Provider:  provider.String(),
+			Available: false,
+			Message:   fmt.Sprintf("Signing failed: %v", err),
+		}
+	}
+	encoded, err := bbs.EncodeSignature(signature)
+	if err == nil {
+		signature, err = bbs.DecodeSignature(encoded)
+	}
+	if err == nil {
+		err = service.Verify(keyPair.PublicKey, signature, messages)
+	}
+	if err != nil {
+		return dto.TestBBSProviderResponse{
+			Provider:  provider.String(),
+			Available: false,
+			Message:   fmt.Sprintf("Encoded signature check failed: %v", err),
+		}
+	}
+
 	return dto.TestBBSProviderResponse{
 		Provider:    provider.String(),
 		Available:   true,
 		Performance: fmt.Sprintf("Key generation: %.2fms", float64(elapsed.Nanoseconds())/1e6),
 		Message:     "Provider is working correctly",
+		Signature:   encoded,
 	}
 }
 
@@ -210,7 +327,15 @@ func (h *BBSHandler) benchmarkSingleProvider(provider bbs.Provider, messageCount
 		revealedIndices[i] = i
 	}
 
-	nonce := []byte("test-nonce-for-benchmarking")
+	generatedNonce, err := bbs.GenerateNonce()
+	if err != nil {
+		return dto.BenchmarkResult{
+			Provider:  provider.String(),
+			Available: false,
+			Message:   fmt.Sprintf("Nonce generation failed: %v", err),
+		}
+	}
+	nonce := []byte(generatedNonce)
 	start = time.Now()
 	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 	if err != nil {
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+// ContextHandler serves bundled JSON-LD contexts, so JSON-LD processors in offline deployments
+// can map context URLs to this server instead of the internet
+type ContextHandler struct {
+	loader *jsonld.BundledLoader
+}
+
+// NewContextHandler creates a new context handler
+func NewContextHandler(loader *jsonld.BundledLoader) *ContextHandler {
+	return &ContextHandler{loader: loader}
+}
+
+// ListContexts handles GET /contexts
+func (h *ContextHandler) ListContexts(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	response := dto.ListContextsResponse{Contexts: []dto.ContextDTO{}}
+	for _, context := range h.loader.Contexts() {
+		response.Contexts = append(response.Contexts, dto.ContextDTO{
+			URL:      context.URL,
+			LocalURL: scheme + "://" + r.Host + "/contexts/" + url.PathEscape(context.Name) + ".jsonld",
+		})
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// GetContext handles GET /contexts/{name}
+func (h *ContextHandler) GetContext(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	context, ok := h.loader.Lookup(strings.TrimSuffix(r.PathValue("name"), ".jsonld"))
+	if !ok {
+		writeErrorResponse(w, "Context not found", http.StatusNotFound, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/ld+json")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write(context.Document)
+}
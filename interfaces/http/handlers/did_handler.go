@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+)
+
+// DIDHandler publishes the DID documents a server stores, so services running the other roles
+// can resolve its issuers', holders' and verifiers' DIDs. It serves only the repository's own
+// documents, never ones resolved at peers.
+type DIDHandler struct {
+	didRepo did.DIDRepository
+	// webDomain is the domain did:web documents are served for; empty takes the request's host
+	webDomain string
+}
+
+// NewDIDHandler creates a new DID handler
+func NewDIDHandler(didRepo did.DIDRepository) *DIDHandler {
+	return &DIDHandler{didRepo: didRepo}
+}
+
+// SetWebDomain serves did:web documents for domain whatever host a request was sent to, such
+// as behind a reverse proxy; empty takes the request's host
+func (h *DIDHandler) SetWebDomain(domain string) {
+	h.webDomain = domain
+}
+
+// ServeWebDID handles GET /.well-known/did.json and GET /users/{id}/did.json, publishing the
+// repository's did:web documents where did:web resolvers look for them. Documents are read from
+// the repository on every request, so key rotations and deactivations show at once.
+func (h *DIDHandler) ServeWebDID(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	domain := h.webDomain
+	if domain == "" {
+		domain = r.Host
+	}
+	didString, err := did.WebDIDForPath(domain, r.URL.Path)
+	if err != nil {
+		writeErrorResponse(w, "DID not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	doc, err := h.didRepo.Resolve(didString)
+	switch {
+	case errors.Is(err, did.ErrDeactivated):
+		writeErrorResponse(w, "DID deactivated", http.StatusGone, err.Error())
+		return
+	case errors.Is(err, did.ErrNotFound):
+		writeErrorResponse(w, "DID not found", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, "Failed to resolve DID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, doc)
+}
+
+// ResolveDID handles GET /api/dids/{did}. Clients accepting did.ResolutionMediaType receive a
+// DID Resolution result, with the document's metadata or the error code resolution failed with.
+func (h *DIDHandler) ResolveDID(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	didString := r.PathValue("did")
+	if strings.Contains(r.Header.Get("Accept"), "https://w3id.org/did-resolution") {
+		var result *did.ResolutionResult
+		if _, err := did.ParseDID(didString); err != nil {
+			result = did.NewResolutionResult(didString, nil, err)
+		} else {
+			doc, err := h.didRepo.Resolve(didString)
+			result = did.NewResolutionResult(didString, doc, err)
+		}
+		w.Header().Set("Content-Type", did.ResolutionMediaType)
+		w.WriteHeader(result.HTTPStatus())
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	if !checkDIDParam(w, "did", didString) {
+		return
+	}
+	doc, err := h.didRepo.Resolve(didString)
+	switch {
+	case errors.Is(err, did.ErrDeactivated):
+		writeErrorResponse(w, "DID deactivated", http.StatusGone, err.Error())
+		return
+	case errors.Is(err, did.ErrNotFound):
+		writeErrorResponse(w, "DID not found", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, "Failed to resolve DID", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, doc)
+}
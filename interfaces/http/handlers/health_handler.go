@@ -4,20 +4,35 @@ import (
 	"net/http"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
 )
 
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	// checker is nil unless component checks are configured
+	checker *health.Checker
+	// readiness is nil unless startup tasks are tracked
+	readiness *health.Readiness
+}
 
 // NewHealthHandler creates a new health handler
 func NewHealthHandler() *HealthHandler {
 	return &HealthHandler{}
 }
 
-// Health handles GET /health
-func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
+// SetChecker makes /health run component checks
+func (h *HealthHandler) SetChecker(checker *health.Checker) {
+	h.checker = checker
+}
+
+// SetReadiness makes /ready wait for the tracked startup tasks
+func (h *HealthHandler) SetReadiness(readiness *health.Readiness) {
+	h.readiness = readiness
+}
 
+// Health handles GET /health. It answers 200 while the service is healthy or degraded and
+// 503 once a critical component fails, so it can back load balancer and orchestrator probes.
+func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -28,10 +43,68 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := dto.HealthResponse{
-		Status:  "healthy",
+		Status:  string(health.StatusHealthy),
 		Service: "BBS+ Selective Disclosure API",
 		Version: "1.0.0",
 	}
 
+	if h.checker == nil {
+		writeSuccessResponse(w, response)
+		return
+	}
+
+	report := h.checker.Run(r.Context())
+	response.Status = string(report.Status)
+	response.Components = report.Components
+	response.CheckedAt = &report.CheckedAt
+
+	statusCode := http.StatusOK
+	if report.Status == health.StatusUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	writeJSONResponse(w, statusCode, response)
+}
+
+// Ready handles GET /ready. It answers 503 until every startup task is done, so orchestrators
+// only route traffic to a warmed-up instance.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	response := dto.ReadinessResponse{Status: "ready"}
+	if h.readiness == nil {
+		writeSuccessResponse(w, response)
+		return
+	}
+
+	ready, tasks := h.readiness.Ready()
+	response.Tasks = tasks
+	if !ready {
+		response.Status = "not ready"
+		writeJSONResponse(w, http.StatusServiceUnavailable, response)
+		return
+	}
+
 	writeSuccessResponse(w, response)
 }
+
+// Live handles GET /live. It runs no checks: answering at all shows the process is alive.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	writeSuccessResponse(w, dto.LivenessResponse{Status: "alive"})
+}
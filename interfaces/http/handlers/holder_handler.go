@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/barcode"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
 )
 
 // HolderHandler handles holder-related HTTP requests
@@ -22,8 +28,6 @@ func NewHolderHandler(holderUC *holder.UseCase) *HolderHandler {
 
 // SetupHolder handles POST /api/holder/setup
 func (h *HolderHandler) SetupHolder(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -34,8 +38,10 @@ func (h *HolderHandler) SetupHolder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SetupHolderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !checkMethodName(w, req.Method) {
 		return
 	}
 
@@ -56,8 +62,6 @@ func (h *HolderHandler) SetupHolder(w http.ResponseWriter, r *http.Request) {
 
 // StoreCredential handles POST /api/holder/credentials
 func (h *HolderHandler) StoreCredential(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -68,13 +72,17 @@ func (h *HolderHandler) StoreCredential(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req dto.StoreCredentialRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	// Store credential
-	if err := h.holderUC.StoreCredential(req.Credential); err != nil {
+	err := h.holderUC.StoreCredential(req.Credential)
+	if errors.Is(err, vc.ErrCredentialDeleted) {
+		writeErrorResponse(w, "Credential was deleted", http.StatusGone, err.Error())
+		return
+	}
+	if err != nil {
 		writeErrorResponse(w, "Failed to store credential", http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -86,10 +94,256 @@ func (h *HolderHandler) StoreCredential(w http.ResponseWriter, r *http.Request)
 	writeSuccessResponse(w, response)
 }
 
+// DeleteCredential handles DELETE /api/holder/credentials/{id}?deletedBy={who}&reason={why}
+func (h *HolderHandler) DeleteCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodDelete {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	query := r.URL.Query()
+	err := h.holderUC.DeleteCredential(r.PathValue("id"), storage.Tombstone{
+		DeletedBy: query.Get("deletedBy"),
+		Reason:    query.Get("reason"),
+	})
+	switch {
+	case errors.Is(err, vc.ErrCredentialNotFound):
+		writeErrorResponse(w, "Credential not found", http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, vc.ErrCredentialDeleted):
+		writeErrorResponse(w, "Credential was deleted", http.StatusGone, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, "Failed to delete credential", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.DeleteCredentialResponse{Status: "deleted"})
+}
+
+// DeriveCredential handles POST /api/holder/credentials/{id}/derive
+func (h *HolderHandler) DeriveCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.DeriveCredentialRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || len(req.RevealedAttributes) == 0 || req.Nonce == "" {
+		writeErrorResponse(w, "holderDid, revealedAttributes and nonce are required", http.StatusBadRequest, "")
+		return
+	}
+
+	derived, err := h.holderUC.DeriveCredential(r.Context(), req.HolderDID, r.PathValue("id"), req.RevealedAttributes, req.Nonce)
+	switch {
+	case errors.Is(err, vc.ErrCredentialNotFound):
+		writeErrorResponse(w, "Credential not found", http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, vc.ErrCredentialDeleted):
+		writeErrorResponse(w, "Credential was deleted", http.StatusGone, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, "Failed to derive credential", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.DeriveCredentialResponse{Credential: derived})
+}
+
+// RedlineCredential handles POST /api/holder/credentials/{id}/redline
+func (h *HolderHandler) RedlineCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RedlineCredentialRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	redline, err := h.holderUC.RedlineCredential(req.HolderDID, vc.SelectiveDisclosureRequest{
+		CredentialID:       r.PathValue("id"),
+		RevealedAttributes: req.RevealedAttributes,
+		HideIssuer:         req.HideIssuer,
+		IncludeCommitments: req.IncludeCommitments,
+		Predicates:         req.Predicates,
+		ProveAbsent:        req.ProveAbsent,
+	}, req.Purpose)
+	if err != nil {
+		writeRedlineError(w, err)
+		return
+	}
+
+	writeSuccessResponse(w, redline)
+}
+
+// RedlinePresentation handles POST /api/holder/presentations/redline, previewing what the
+// presentation request would reveal of each credential without creating the presentation
+func (h *HolderHandler) RedlinePresentation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.CreatePresentationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	report, err := h.holderUC.Redline(holder.PresentationRequest{
+		HolderDID:           req.HolderDID,
+		CredentialIDs:       req.CredentialIDs,
+		SelectiveDisclosure: dto.ToVCSelectiveDisclosure(req.SelectiveDisclosure),
+		RequestMetadata:     vc.RequestMetadata{Purpose: req.Purpose},
+	})
+	if err != nil {
+		writeRedlineError(w, err)
+		return
+	}
+
+	writeSuccessResponse(w, report)
+}
+
+// writeRedlineError maps a redline failure to its status code
+func writeRedlineError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, vc.ErrCredentialNotFound):
+		writeErrorResponse(w, "Credential not found", http.StatusNotFound, err.Error())
+	case errors.Is(err, vc.ErrCredentialDeleted):
+		writeErrorResponse(w, "Credential was deleted", http.StatusGone, err.Error())
+	default:
+		writeErrorResponse(w, "Failed to preview disclosure", http.StatusBadRequest, err.Error())
+	}
+}
+
+// FetchLatestVersion handles POST /api/holder/credentials/{id}/latest
+func (h *HolderHandler) FetchLatestVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.FetchLatestVersionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" {
+		writeErrorResponse(w, "holderDid is required", http.StatusBadRequest, "")
+		return
+	}
+
+	credentialID := r.PathValue("id")
+	latest, err := h.holderUC.FetchLatestVersion(req.HolderDID, credentialID)
+	switch {
+	case errors.Is(err, vc.ErrCredentialNotFound):
+		writeErrorResponse(w, "Credential not found", http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, vc.ErrCredentialDeleted):
+		writeErrorResponse(w, "Credential was deleted", http.StatusGone, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, "Failed to fetch latest version", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.FetchLatestVersionResponse{Credential: latest, Amended: latest.ID != credentialID})
+}
+
+// ProveContinuity handles POST /api/holder/continuity-proofs
+func (h *HolderHandler) ProveContinuity(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ProveContinuityRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || req.PreviousCredentialID == "" || req.CurrentCredentialID == "" || req.Nonce == "" {
+		writeErrorResponse(w, "holderDid, previousCredentialId, currentCredentialId and nonce are required", http.StatusBadRequest, "")
+		return
+	}
+
+	proof, err := h.holderUC.ProveContinuity(req.HolderDID, req.PreviousCredentialID, req.CurrentCredentialID, req.Claim, req.Nonce)
+	switch {
+	case errors.Is(err, vc.ErrCredentialNotFound):
+		writeErrorResponse(w, "Credential not found", http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, vc.ErrCredentialDeleted):
+		writeErrorResponse(w, "Credential was deleted", http.StatusGone, err.Error())
+		return
+	case err != nil:
+		writeErrorResponse(w, "Failed to prove continuity", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.ProveContinuityResponse{Proof: proof})
+}
+
+// ImportCredential handles POST /api/holder/credentials/import
+func (h *HolderHandler) ImportCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ImportCredentialRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || len(req.Credential) == 0 {
+		writeErrorResponse(w, "holderDid and credential are required", http.StatusBadRequest, "")
+		return
+	}
+
+	credential, err := h.holderUC.ImportCredential(req.HolderDID, req.Credential)
+	if err != nil {
+		writeErrorResponse(w, "Failed to import credential", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.ImportCredentialResponse{Credential: credential})
+}
+
 // CreatePresentation handles POST /api/holder/presentations
 func (h *HolderHandler) CreatePresentation(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -100,8 +354,12 @@ func (h *HolderHandler) CreatePresentation(w http.ResponseWriter, r *http.Reques
 	}
 
 	var req dto.CreatePresentationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := req.Format.Validate(); err != nil {
+		writeErrorResponse(w, "Invalid format", http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -116,14 +374,34 @@ func (h *HolderHandler) CreatePresentation(w http.ResponseWriter, r *http.Reques
 	}
 
 	ucReq := holder.PresentationRequest{
-		HolderDID:           req.HolderDID,
-		CredentialIDs:       req.CredentialIDs,
-		SelectiveDisclosure: selectiveDisclosure,
-		Nonce:               req.Nonce,
+		HolderDID:              req.HolderDID,
+		CredentialIDs:          req.CredentialIDs,
+		SelectiveDisclosure:    selectiveDisclosure,
+		Nonce:                  req.Nonce,
+		VerifierDID:            req.VerifierDID,
+		UsePairwiseDID:         req.UsePairwiseDID,
+		ValidFor:               time.Duration(req.ValidForSeconds) * time.Second,
+		IncludeStatusSnapshots: req.IncludeStatusSnapshots,
+		Format:                 req.Format,
+		Domain:                 req.Domain,
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       req.Purpose,
+			RetentionDays: req.RetentionDays,
+			Verifier:      req.Verifier,
+			Accreditation: req.Accreditation,
+		},
 	}
 
 	// Create presentation
-	presentation, err := h.holderUC.CreatePresentation(ucReq)
+	presentation, err := h.holderUC.CreatePresentationContext(r.Context(), ucReq)
+	if errors.Is(err, holder.ErrVerifierNotAccredited) {
+		writeErrorResponse(w, "Verifier not accredited", http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, vc.ErrDisclosureRestricted) {
+		writeErrorResponse(w, "Disclosure restricted by issuer", http.StatusForbidden, err.Error())
+		return
+	}
 	if err != nil {
 		writeErrorResponse(w, "Failed to create presentation", http.StatusInternalServerError, err.Error())
 		return
@@ -134,13 +412,77 @@ func (h *HolderHandler) CreatePresentation(w http.ResponseWriter, r *http.Reques
 		Presentation:   presentation,
 	}
 
+	if req.Barcode {
+		payloads, err := barcode.EncodePresentation(presentation, 0)
+		if err != nil {
+			writeErrorResponse(w, "Presentation does not fit in barcodes", http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Barcode = payloads
+	}
+
+	// Attach the privacy analysis; it is advisory, so failures do not fail the request
+	if report, err := h.holderUC.AnalyzeDisclosure(ucReq); err == nil {
+		response.Privacy = &dto.PrivacyReportDTO{
+			Purpose:     report.Purpose,
+			Score:       report.Score,
+			Warnings:    report.Warnings,
+			Credentials: report.Credentials,
+		}
+	}
+
 	writeSuccessResponse(w, response)
 }
 
-// ListCredentials handles GET /api/holder/credentials?holderDid={did}
-func (h *HolderHandler) ListCredentials(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
+// MatchCredentials handles POST /api/holder/credentials/match
+func (h *HolderHandler) MatchCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.MatchCredentialsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	matches, err := h.holderUC.FindMatchingCredentials(req.HolderDID, holder.CredentialQuery{
+		RequiredClaims:  req.RequiredClaims,
+		TrustedIssuers:  req.TrustedIssuers,
+		CredentialTypes: req.CredentialTypes,
+	})
+	if err != nil {
+		writeErrorResponse(w, "Failed to match credentials", http.StatusBadRequest, err.Error())
+		return
+	}
 
+	response := dto.MatchCredentialsResponse{
+		Matches: make([]dto.CredentialMatchDTO, len(matches)),
+	}
+	for i, match := range matches {
+		response.Matches[i] = dto.CredentialMatchDTO{
+			CredentialID:  match.Credential.ID,
+			Issuer:        match.Credential.Issuer(),
+			Types:         match.Credential.Type,
+			Score:         dto.MatchScoreDTO(match.Score),
+			MatchedClaims: match.MatchedClaims,
+			MissingClaims: match.MissingClaims,
+			SelectiveDisclosure: dto.SelectiveDisclosureRequestDTO{
+				CredentialID:       match.Disclosure.CredentialID,
+				RevealedAttributes: match.Disclosure.RevealedAttributes,
+			},
+		}
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// ListConsents handles GET /api/holder/consents?holderDid={did}
+func (h *HolderHandler) ListConsents(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -151,21 +493,557 @@ func (h *HolderHandler) ListCredentials(w http.ResponseWriter, r *http.Request)
 	}
 
 	holderDID := r.URL.Query().Get("holderDid")
+	if !checkDIDParam(w, "holderDid", holderDID) {
+		return
+	}
 	if holderDID == "" {
 		writeErrorResponse(w, "holderDid parameter is required", http.StatusBadRequest, "")
 		return
 	}
 
-	// List credentials
-	credentials, err := h.holderUC.ListCredentials(holderDID)
+	records, err := h.holderUC.ListConsentRecords(holderDID)
 	if err != nil {
-		writeErrorResponse(w, "Failed to list credentials", http.StatusInternalServerError, err.Error())
+		writeErrorResponse(w, "Failed to list consent records", http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response := dto.ListCredentialsResponse{
-		Credentials: credentials,
+	response := dto.ListConsentsResponse{
+		Consents: make([]dto.ConsentRecordDTO, len(records)),
+	}
+	for i, record := range records {
+		response.Consents[i] = dto.ConsentRecordDTO{
+			ID:             record.ID,
+			PresenterDID:   record.PresenterDID,
+			VerifierDID:    record.VerifierDID,
+			PresentationID: record.PresentationID,
+			RevealedClaims: record.RevealedClaims,
+			Purpose:        record.Purpose,
+			RetentionDays:  record.RetentionDays,
+			Verifier:       record.Verifier,
+			CreatedAt:      record.CreatedAt,
+			Receipt:        record.Receipt,
+		}
 	}
 
 	writeSuccessResponse(w, response)
 }
+
+// AccreditationPolicy handles POST /api/holder/accreditation-policy and GET /api/holder/accreditation-policy?holderDid={did}
+func (h *HolderHandler) AccreditationPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		holderDID := r.URL.Query().Get("holderDid")
+		if !checkDIDParam(w, "holderDid", holderDID) {
+			return
+		}
+		if holderDID == "" {
+			writeErrorResponse(w, "holderDid parameter is required", http.StatusBadRequest, "")
+			return
+		}
+
+		policy, err := h.holderUC.AccreditationPolicy(holderDID)
+		if err != nil {
+			writeErrorResponse(w, "Failed to load accreditation policy", http.StatusInternalServerError, err.Error())
+			return
+		}
+		if policy == nil {
+			writeErrorResponse(w, "No accreditation policy", http.StatusNotFound, "")
+			return
+		}
+
+		writeSuccessResponse(w, policy)
+	case http.MethodPost:
+		var req dto.AccreditationPolicyRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		if err := h.holderUC.SetAccreditationPolicy(req.HolderDID, req.Policy); err != nil {
+			writeErrorResponse(w, "Failed to set accreditation policy", http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeSuccessResponse(w, req.Policy)
+	default:
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+	}
+}
+
+// MintPresentationToken handles POST /api/holder/presentation-tokens
+func (h *HolderHandler) MintPresentationToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.MintPresentationTokenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	token, err := h.holderUC.MintPresentationToken(r.Context(), holder.PresentationTokenRequest{
+		PresentationRequest: holder.PresentationRequest{
+			HolderDID:              req.HolderDID,
+			CredentialIDs:          req.CredentialIDs,
+			SelectiveDisclosure:    dto.ToVCSelectiveDisclosure(req.SelectiveDisclosure),
+			Nonce:                  req.Nonce,
+			VerifierDID:            req.VerifierDID,
+			UsePairwiseDID:         req.UsePairwiseDID,
+			IncludeStatusSnapshots: req.IncludeStatusSnapshots,
+			Domain:                 req.Domain,
+			RequestMetadata: vc.RequestMetadata{
+				Purpose:       req.Purpose,
+				RetentionDays: req.RetentionDays,
+				Verifier:      req.Verifier,
+				Accreditation: req.Accreditation,
+			},
+		},
+		TTL: time.Duration(req.TTLSeconds) * time.Second,
+	})
+	if errors.Is(err, holder.ErrVerifierNotAccredited) {
+		writeErrorResponse(w, "Verifier not accredited", http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, vc.ErrDisclosureRestricted) {
+		writeErrorResponse(w, "Disclosure restricted by issuer", http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Failed to mint presentation token", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	writeJSONResponse(w, http.StatusCreated, dto.PresentationTokenResponse{
+		TokenID:   token.ID,
+		URL:       scheme + "://" + r.Host + "/api/holder/presentation-tokens/" + url.PathEscape(token.ID) + "/redeem",
+		Secret:    token.Secret,
+		ExpiresAt: token.ExpiresAt,
+	})
+}
+
+// RedeemPresentationToken handles POST /api/holder/presentation-tokens/{id}/redeem, called by the verifier
+func (h *HolderHandler) RedeemPresentationToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RedeemPresentationTokenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	presentation, nonce, err := h.holderUC.RedeemPresentationToken(r.PathValue("id"), req.Secret)
+	if err != nil {
+		writeTokenError(w, err)
+		return
+	}
+
+	writeSuccessResponse(w, dto.RedeemPresentationTokenResponse{Presentation: presentation, Nonce: nonce})
+}
+
+// writeTokenError maps presentation token errors to HTTP statuses
+func writeTokenError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, holder.ErrPresentationTokenNotFound):
+		writeErrorResponse(w, "Presentation token not found", http.StatusNotFound, err.Error())
+	case errors.Is(err, holder.ErrPresentationTokenRedeemed):
+		writeErrorResponse(w, "Presentation token already redeemed", http.StatusGone, err.Error())
+	default:
+		writeErrorResponse(w, "Failed to redeem presentation token", http.StatusBadRequest, err.Error())
+	}
+}
+
+// StoreReceipt handles POST /api/holder/receipts
+func (h *HolderHandler) StoreReceipt(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.StoreReceiptRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if _, err := h.holderUC.StoreReceipt(req.HolderDID, req.Receipt); err != nil {
+		writeErrorResponse(w, "Failed to store receipt", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := dto.StoreCredentialResponse{
+		Status: "success",
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// RefreshStatusSnapshots handles POST /api/holder/status-snapshots
+func (h *HolderHandler) RefreshStatusSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RefreshStatusSnapshotsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" {
+		writeErrorResponse(w, "holderDid is required", http.StatusBadRequest, "")
+		return
+	}
+
+	refreshed, err := h.holderUC.RefreshStatusSnapshots(req.HolderDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to refresh status snapshots", http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.RefreshStatusSnapshotsResponse{Refreshed: refreshed})
+}
+
+// PrecomputeProofs handles POST /api/holder/proof-templates
+func (h *HolderHandler) PrecomputeProofs(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.PrecomputeProofsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" {
+		writeErrorResponse(w, "holderDid is required", http.StatusBadRequest, "")
+		return
+	}
+
+	computed, err := h.holderUC.PrecomputeProofs(req.HolderDID, req.CredentialIDs, req.Count)
+	if err != nil {
+		writeErrorResponse(w, "Failed to precompute proofs", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.PrecomputeProofsResponse{Computed: computed})
+}
+
+// SubscribeToStatus handles POST /api/holder/status-subscriptions
+func (h *HolderHandler) SubscribeToStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.SubscribeToStatusRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || req.IssuerDID == "" || req.Endpoint == "" {
+		writeErrorResponse(w, "holderDid, issuerDid and endpoint are required", http.StatusBadRequest, "")
+		return
+	}
+
+	subscription, err := h.holderUC.SubscribeToStatus(req.HolderDID, req.IssuerDID, req.Endpoint)
+	if err != nil {
+		writeErrorResponse(w, "Failed to subscribe to status notifications", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, subscription)
+}
+
+// ReceiveStatusNotification handles POST /api/holder/status-notifications, the webhook issuers
+// deliver status notifications to
+func (h *HolderHandler) ReceiveStatusNotification(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var notification vc.StatusNotification
+	if !decodeRequest(w, r, &notification) {
+		return
+	}
+
+	if err := h.holderUC.ReceiveStatusNotification(&notification); err != nil {
+		writeErrorResponse(w, "Status notification rejected", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportCommitments handles POST /api/holder/commitments
+func (h *HolderHandler) ExportCommitments(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ExportCommitmentsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || req.CredentialID == "" {
+		writeErrorResponse(w, "holderDid and credentialId are required", http.StatusBadRequest, "")
+		return
+	}
+
+	export, err := h.holderUC.ExportCommitments(req.HolderDID, req.CredentialID, req.Claims)
+	if err != nil {
+		writeErrorResponse(w, "Failed to export commitments", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, export)
+}
+
+// GrantEvidenceAccess handles POST /api/holder/evidence/grants
+func (h *HolderHandler) GrantEvidenceAccess(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.GrantEvidenceAccessRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || req.CredentialID == "" || req.EvidenceID == "" {
+		writeErrorResponse(w, "holderDid, credentialId and evidenceId are required", http.StatusBadRequest, "")
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	grant, err := h.holderUC.GrantEvidenceAccess(req.HolderDID, req.CredentialID, req.EvidenceID, req.VerifierDID, ttl)
+	if err != nil {
+		writeErrorResponse(w, "Failed to grant evidence access", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, grant)
+}
+
+// RequestAddendum handles POST /api/holder/addenda
+func (h *HolderHandler) RequestAddendum(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RequestAddendumRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.HolderDID == "" || req.CredentialID == "" {
+		writeErrorResponse(w, "holderDid and credentialId are required", http.StatusBadRequest, "")
+		return
+	}
+
+	addendum, err := h.holderUC.RequestAddendum(req.HolderDID, req.CredentialID, req.Thresholds)
+	if err != nil {
+		writeErrorResponse(w, "Failed to obtain addendum", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, addendum)
+}
+
+// ExportBackup handles POST /api/holder/backup
+func (h *HolderHandler) ExportBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ExportBackupRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	archive, err := h.holderUC.ExportBackup(req.HolderDID, req.Password)
+	if err != nil {
+		writeErrorResponse(w, "Failed to export backup", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, archive)
+}
+
+// RestoreBackup handles POST /api/holder/restore
+func (h *HolderHandler) RestoreBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RestoreBackupRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	var archive holder.BackupArchive
+	if err := json.Unmarshal(req.Archive, &archive); err != nil {
+		writeErrorResponse(w, "Invalid backup archive", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.holderUC.RestoreBackup(&archive, req.Password)
+	if err != nil {
+		writeErrorResponse(w, "Failed to restore backup", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := dto.RestoreBackupResponse{
+		HolderDID:    result.HolderDID,
+		Credentials:  result.Credentials,
+		PairwiseDIDs: result.PairwiseDIDs,
+		Status:       "success",
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// ListCredentials handles GET /api/holder/credentials?holderDid={did}
+func (h *HolderHandler) ListCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	holderDID := r.URL.Query().Get("holderDid")
+	if !checkDIDParam(w, "holderDid", holderDID) {
+		return
+	}
+	if holderDID == "" {
+		writeErrorResponse(w, "holderDid parameter is required", http.StatusBadRequest, "")
+		return
+	}
+
+	// List credentials
+	credentials, err := h.holderUC.ListCredentials(holderDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to list credentials", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	notices, err := h.holderUC.ListStatusNotices(holderDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to list status notifications", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	statuses, err := h.holderUC.CredentialStatuses(holderDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to compute credential statuses", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := dto.ListCredentialsResponse{
+		Credentials:   credentials,
+		StatusNotices: notices,
+	}
+	if len(statuses) > 0 {
+		response.Statuses = make(map[string]dto.CredentialStatusDTO, len(statuses))
+		for id, credentialStatus := range statuses {
+			response.Statuses[id] = dto.CredentialStatusDTO{
+				State:     string(credentialStatus.State),
+				CheckedAt: credentialStatus.CheckedAt,
+			}
+		}
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// PrepareCounterOffer handles POST /api/holder/counter-offers
+func (h *HolderHandler) PrepareCounterOffer(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.CounterOfferRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	offer, err := h.holderUC.PrepareCounterOffer(req.HolderDID, req.Request, req.Withhold, req.Reason)
+	if err != nil {
+		writeErrorResponse(w, "Failed to prepare counter-offer", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, offer)
+}
@@ -2,10 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
 )
 
 // IssuerHandler handles issuer-related HTTP requests
@@ -22,8 +29,6 @@ func NewIssuerHandler(issuerUC *issuer.UseCase) *IssuerHandler {
 
 // SetupIssuer handles POST /api/issuer/setup
 func (h *IssuerHandler) SetupIssuer(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -34,8 +39,10 @@ func (h *IssuerHandler) SetupIssuer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req dto.SetupIssuerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !checkMethodName(w, req.Method) {
 		return
 	}
 
@@ -54,34 +61,52 @@ func (h *IssuerHandler) SetupIssuer(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponse(w, response)
 }
 
-// IssueCredential handles POST /api/issuer/credentials
+// IssueCredential handles POST /api/issuer/credentials; GET lists issued credentials
 func (h *IssuerHandler) IssueCredential(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
 
+	if r.Method == http.MethodGet {
+		h.ListIssuedCredentials(w, r)
+		return
+	}
+
 	if r.Method != http.MethodPost {
 		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
 		return
 	}
 
 	var req dto.IssueCredentialRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
-	// Convert DTO to use case request
-	ucReq := issuer.IssueCredentialRequest{
-		IssuerDID:  req.IssuerDID,
-		SubjectDID: req.SubjectDID,
-		Claims:     dto.ToVCClaims(req.Claims),
+	ucReq := toIssueCredentialRequest(req)
+
+	// Hold the request for review when issuance requires approval
+	if h.issuerUC.ApprovalRequired() {
+		approval, err := h.issuerUC.SubmitIssuance(ucReq, req.RequestedBy)
+		if err != nil {
+			writeErrorResponse(w, "Failed to submit issuance request", http.StatusBadRequest, err.Error())
+			return
+		}
+
+		w.Header().Set("Location", "/api/issuer/approvals/"+approval.ID)
+		writeJSONResponse(w, http.StatusAccepted, approval)
+		return
 	}
 
 	// Issue credential
-	credential, err := h.issuerUC.IssueCredential(ucReq)
+	credential, err := h.issuerUC.IssueCredentialContext(r.Context(), ucReq)
+	if errors.Is(err, issuer.ErrCoSigningRequired) {
+		writeErrorResponse(w, "Issuance requires co-signatures", http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, issuer.ErrDuplicateCredential) {
+		writeErrorResponse(w, "Credential already issued", http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		writeErrorResponse(w, "Failed to issue credential", http.StatusInternalServerError, err.Error())
 		return
@@ -95,10 +120,51 @@ func (h *IssuerHandler) IssueCredential(w http.ResponseWriter, r *http.Request)
 	writeSuccessResponse(w, response)
 }
 
-// VerifyCredential handles POST /api/issuer/verify
-func (h *IssuerHandler) VerifyCredential(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
+// toIssueCredentialRequest converts an issuance DTO to the use case request
+func toIssueCredentialRequest(req dto.IssueCredentialRequest) issuer.IssueCredentialRequest {
+	return issuer.IssueCredentialRequest{
+		IssuerDID:        req.IssuerDID,
+		SubjectDID:       req.SubjectDID,
+		Claims:           dto.ToVCClaims(req.Claims),
+		TemplateID:       req.TemplateID,
+		AnonymitySet:     req.AnonymitySet,
+		CommitAttributes: req.CommitAttributes,
+		Version:          vc.Version(req.Version),
+		ValidUntil:       req.ValidUntil,
+		IssuerName:       req.IssuerName,
+		IssuerImage:      req.IssuerImage,
+		BindToHolder:     req.BindToHolder,
+		Evidence:         toEvidenceDocuments(req.Evidence),
+		CommitClaimKeys:  req.CommitClaimKeys,
+		Generalizations:  req.Generalizations,
+
+		ContentAddressedID: req.ContentAddressedID,
+		Reissue:            req.Reissue,
+	}
+}
+
+// toEvidenceDocuments converts evidence DTOs to the use case's evidence documents
+func toEvidenceDocuments(dtos []dto.EvidenceDocumentDTO) []issuer.EvidenceDocument {
+	if len(dtos) == 0 {
+		return nil
+	}
 
+	documents := make([]issuer.EvidenceDocument, len(dtos))
+	for i, document := range dtos {
+		documents[i] = issuer.EvidenceDocument{
+			Type:        document.Type,
+			Description: document.Description,
+			MediaType:   document.MediaType,
+			Document:    document.Document,
+			Encrypt:     document.Encrypt,
+		}
+	}
+	return documents
+}
+
+// ReleaseEvidence handles POST /api/issuer/evidence/release, releasing an evidence document to the
+// verifier named in the holder's grant
+func (h *IssuerHandler) ReleaseEvidence(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -108,21 +174,1164 @@ func (h *IssuerHandler) VerifyCredential(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var credential map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&credential); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	var grant vc.EvidenceGrant
+	if !decodeRequest(w, r, &grant) {
 		return
 	}
 
-	// For simplicity, we'll return success for now
-	// In a real implementation, you'd convert the map to VerifiableCredential and verify
-	response := dto.SuccessResponse{
-		Message: "Credential verification completed",
-		Data: map[string]interface{}{
-			"valid":  true,
-			"status": "verified",
-		},
+	release, err := h.issuerUC.RetrieveEvidence(&grant)
+	if err != nil {
+		writeErrorResponse(w, "Evidence not released", http.StatusForbidden, err.Error())
+		return
 	}
 
-	writeSuccessResponse(w, response)
+	writeSuccessResponse(w, release)
+}
+
+// IssueCredentialBatch handles POST /api/issuer/credentials/batch
+func (h *IssuerHandler) IssueCredentialBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.BatchIssueRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	ucReq := issuer.BatchIssueRequest{
+		IssuerDID:  req.IssuerDID,
+		TemplateID: req.TemplateID,
+		Items:      make([]issuer.BatchItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		ucReq.Items[i] = issuer.BatchItem{
+			SubjectDID: item.SubjectDID,
+			Claims:     dto.ToVCClaims(item.Claims),
+			TemplateID: item.TemplateID,
+		}
+	}
+
+	job, err := h.issuerUC.StartBatchIssuance(ucReq)
+	if err != nil {
+		writeErrorResponse(w, "Failed to start batch issuance", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/issuer/batches/"+job.ID)
+	writeJSONResponse(w, http.StatusAccepted, job)
+}
+
+// EnqueueIssuance handles POST /api/issuer/issuance-jobs
+func (h *IssuerHandler) EnqueueIssuance(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.IssueCredentialRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	job, err := h.issuerUC.EnqueueIssuance(r.Context(), toIssueCredentialRequest(req))
+	if err != nil {
+		writeErrorResponse(w, "Failed to queue issuance", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/issuer/issuance-jobs/"+job.ID)
+	writeJSONResponse(w, http.StatusAccepted, job)
+}
+
+// GetIssuanceJob handles GET /api/issuer/issuance-jobs/{id}
+func (h *IssuerHandler) GetIssuanceJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	job, err := h.issuerUC.GetIssuanceJob(r.PathValue("id"))
+	if errors.Is(err, issuer.ErrIssuanceJobNotFound) {
+		writeErrorResponse(w, "Issuance job not found", http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Failed to get issuance job", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, job)
+}
+
+// ListApprovals handles GET /api/issuer/approvals, optionally filtered by ?status=
+func (h *IssuerHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	approvals, err := h.issuerUC.ListApprovals(issuer.ApprovalStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeErrorResponse(w, "Failed to list approvals", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, approvals)
+}
+
+// GetApproval handles GET /api/issuer/approvals/{id}
+func (h *IssuerHandler) GetApproval(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	approval, err := h.issuerUC.GetApproval(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Approval not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, approval)
+}
+
+// ApproveIssuance handles POST /api/issuer/approvals/{id}/approve
+func (h *IssuerHandler) ApproveIssuance(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, func(id string, req dto.ApprovalDecisionRequest) (*issuer.IssuanceApproval, error) {
+		return h.issuerUC.ApproveIssuance(r.Context(), id, req.Approver, req.Comment)
+	}, "Failed to approve issuance request")
+}
+
+// RejectIssuance handles POST /api/issuer/approvals/{id}/reject
+func (h *IssuerHandler) RejectIssuance(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, func(id string, req dto.ApprovalDecisionRequest) (*issuer.IssuanceApproval, error) {
+		return h.issuerUC.RejectIssuance(id, req.Approver, req.Comment)
+	}, "Failed to reject issuance request")
+}
+
+func (h *IssuerHandler) decideApproval(w http.ResponseWriter, r *http.Request, decide func(string, dto.ApprovalDecisionRequest) (*issuer.IssuanceApproval, error), failure string) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	approval, err := decide(r.PathValue("id"), req)
+	if err != nil {
+		writeErrorResponse(w, failure, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, approval)
+}
+
+// CommentOnApproval handles POST /api/issuer/approvals/{id}/comments
+func (h *IssuerHandler) CommentOnApproval(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ApprovalCommentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	approval, err := h.issuerUC.CommentOnApproval(r.PathValue("id"), req.Author, req.Text)
+	if err != nil {
+		writeErrorResponse(w, "Failed to comment on issuance request", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, approval)
+}
+
+// SetCoSigningPolicy handles POST /api/issuer/cosigning/policies
+func (h *IssuerHandler) SetCoSigningPolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.CoSigningPolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	policy := issuer.CoSigningPolicy{
+		IssuerDID:  req.IssuerDID,
+		TemplateID: req.TemplateID,
+		Threshold:  req.Threshold,
+		Signers:    req.Signers,
+	}
+	if err := h.issuerUC.SetCoSigningPolicy(policy); err != nil {
+		writeErrorResponse(w, "Failed to set co-signing policy", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, policy)
+}
+
+// StartSigningSession handles POST /api/issuer/signing-sessions
+func (h *IssuerHandler) StartSigningSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.StartSigningSessionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	session, err := h.issuerUC.StartSigningSession(issuer.StartSigningRequest{
+		Request:   toIssueCredentialRequest(req.Request),
+		Threshold: req.Threshold,
+		Signers:   req.Signers,
+		TTL:       time.Duration(req.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		writeErrorResponse(w, "Failed to start signing session", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/issuer/signing-sessions/"+session.ID)
+	writeJSONResponse(w, http.StatusCreated, session)
+}
+
+// GetSigningSession handles GET /api/issuer/signing-sessions/{id}
+func (h *IssuerHandler) GetSigningSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	session, err := h.issuerUC.GetSigningSession(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Signing session not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, session)
+}
+
+// CoSign handles POST /api/issuer/signing-sessions/{id}/signatures
+func (h *IssuerHandler) CoSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.CoSignRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	var session *issuer.SigningSession
+	var err error
+	if req.Signature == "" {
+		session, err = h.issuerUC.SignSession(r.Context(), r.PathValue("id"), req.VerificationMethod)
+	} else {
+		var signature []byte
+		signature, err = did.DecodeSignatureMultibase(req.Signature)
+		if err == nil {
+			session, err = h.issuerUC.CoSign(r.Context(), r.PathValue("id"), req.VerificationMethod, signature)
+		}
+	}
+	if err != nil {
+		writeErrorResponse(w, "Failed to co-sign", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, session)
+}
+
+// Authorizations handles POST /api/issuer/authorizations and GET /api/issuer/authorizations?issuerDid={did}
+func (h *IssuerHandler) Authorizations(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		issuerDID := r.URL.Query().Get("issuerDid")
+		if !checkDIDParam(w, "issuerDid", issuerDID) {
+			return
+		}
+		if issuerDID == "" {
+			writeErrorResponse(w, "Missing issuer DID", http.StatusBadRequest, "issuerDid query parameter is required")
+			return
+		}
+
+		authorizations, err := h.issuerUC.Authorizations(issuerDID)
+		if err != nil {
+			writeErrorResponse(w, "Failed to list authorizations", http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		writeSuccessResponse(w, authorizations)
+	case http.MethodPost:
+		var req dto.AuthorizeIssuerRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		authorization, err := h.issuerUC.AuthorizeIssuer(r.Context(), issuer.AuthorizeIssuerRequest{
+			IssuerDID:       req.IssuerDID,
+			SubIssuerDID:    req.SubIssuerDID,
+			CredentialTypes: req.CredentialTypes,
+			MayDelegate:     req.MayDelegate,
+			ValidUntil:      req.ValidUntil,
+		})
+		if err != nil {
+			writeErrorResponse(w, "Failed to authorize issuer", http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeJSONResponse(w, http.StatusCreated, authorization)
+	default:
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+	}
+}
+
+// AcceptAuthorization handles POST /api/issuer/authorizations/accept
+func (h *IssuerHandler) AcceptAuthorization(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var authorization vc.VerifiableCredential
+	if !decodeRequest(w, r, &authorization) {
+		return
+	}
+
+	held, err := h.issuerUC.AcceptAuthorization(&authorization)
+	if err != nil {
+		writeErrorResponse(w, "Failed to accept authorization", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, held)
+}
+
+// GetBatchJob handles GET /api/issuer/batches/{id}
+func (h *IssuerHandler) GetBatchJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	job, err := h.issuerUC.GetBatchJob(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Batch job not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, job)
+}
+
+// GetIssuerMetadata handles GET /.well-known/openid-credential-issuer
+func (h *IssuerHandler) GetIssuerMetadata(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	issuerDID := r.URL.Query().Get("issuerDid")
+	if !checkDIDParam(w, "issuerDid", issuerDID) {
+		return
+	}
+	metadata, err := h.issuerUC.GetIssuerMetadata(issuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to get issuer metadata", http.StatusNotFound, err.Error())
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	metadata.CredentialEndpoint = scheme + "://" + r.Host + "/api/issuer/credentials"
+
+	writeSuccessResponse(w, metadata)
+}
+
+// GetSchemaDisplay handles GET /api/schemas/{id}/display
+func (h *IssuerHandler) GetSchemaDisplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		locale = r.Header.Get("Accept-Language")
+	}
+
+	display, err := h.issuerUC.GetTemplateDisplay(r.PathValue("id"), locale)
+	if err != nil {
+		writeErrorResponse(w, "Failed to get schema display", http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Language", display.Locale)
+	writeSuccessResponse(w, display)
+}
+
+// VerifyCredential handles POST /api/issuer/verify
+func (h *IssuerHandler) VerifyCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var credential map[string]interface{}
+	if !decodeRequest(w, r, &credential) {
+		return
+	}
+
+	// For simplicity, we'll return success for now
+	// In a real implementation, you'd convert the map to VerifiableCredential and verify
+	response := dto.SuccessResponse{
+		Message: "Credential verification completed",
+		Data: map[string]interface{}{
+			"valid":  true,
+			"status": "verified",
+		},
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// ListIssuedCredentials handles GET /api/issuer/credentials?issuerDid={did}
+func (h *IssuerHandler) ListIssuedCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := issuer.IssuedCredentialFilter{
+		IssuerDID:  query.Get("issuerDid"),
+		SubjectDID: query.Get("subjectDid"),
+		Type:       query.Get("type"),
+	}
+	if filter.IssuerDID == "" {
+		writeErrorResponse(w, "issuerDid parameter is required", http.StatusBadRequest, "")
+		return
+	}
+
+	var err error
+	if filter.IssuedAfter, err = parseTimeParam(query.Get("issuedAfter"), false); err != nil {
+		writeErrorResponse(w, "Invalid issuedAfter parameter", http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.IssuedBefore, err = parseTimeParam(query.Get("issuedBefore"), true); err != nil {
+		writeErrorResponse(w, "Invalid issuedBefore parameter", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	issued, err := h.issuerUC.ListIssuedCredentials(filter)
+	if err != nil {
+		writeErrorResponse(w, "Failed to list issued credentials", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := dto.ListIssuedCredentialsResponse{
+		Credentials: make([]dto.IssuedCredentialDTO, len(issued)),
+	}
+	for i, record := range issued {
+		response.Credentials[i] = toIssuedCredentialDTO(record)
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// GetIssuedCredential handles GET /api/issuer/credentials/{id}
+func (h *IssuerHandler) GetIssuedCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	issued, err := h.issuerUC.GetIssuedCredential(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Issued credential not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, toIssuedCredentialDTO(issued))
+}
+
+// AmendCredential handles POST /api/issuer/credentials/{id}/amend
+func (h *IssuerHandler) AmendCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.AmendCredentialRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	credential, err := h.issuerUC.AmendCredential(r.Context(), issuer.AmendCredentialRequest{
+		CredentialID: r.PathValue("id"),
+		Claims:       dto.ToVCClaims(req.Claims),
+		Remove:       req.Remove,
+	})
+	if errors.Is(err, issuer.ErrCredentialAmended) {
+		writeErrorResponse(w, "Credential already amended", http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, issuer.ErrCoSigningRequired) {
+		writeErrorResponse(w, "Issuance requires co-signatures", http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Failed to amend credential", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.IssueCredentialResponse{
+		CredentialID: credential.ID,
+		Credential:   credential,
+	})
+}
+
+// GetLatestVersion handles GET /api/issuer/credentials/{id}/latest
+func (h *IssuerHandler) GetLatestVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	credential, err := h.issuerUC.LatestVersion(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Issued credential not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.IssueCredentialResponse{
+		CredentialID: credential.ID,
+		Credential:   credential,
+	})
+}
+
+// parseTimeParam parses an RFC 3339 time or a YYYY-MM-DD date. A date stands for the start of the
+// day, or its last instant when endOfDay is set, so date bounds include the whole day.
+func parseTimeParam(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(vc.DateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected an RFC 3339 time or a YYYY-MM-DD date, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// toIssuedCredentialDTO converts an issuance registry record to its DTO
+func toIssuedCredentialDTO(issued *issuer.IssuedCredential) dto.IssuedCredentialDTO {
+	credential := issued.Credential
+	subjectDID, _ := credential.CredentialSubject["id"].(string)
+
+	record := dto.IssuedCredentialDTO{
+		CredentialID: credential.ID,
+		SubjectDID:   subjectDID,
+		Types:        credential.Type,
+		IssuedAt:     credential.IssuedAt(),
+		ExpiresAt:    credential.ExpiresAt(),
+		Credential:   credential,
+	}
+	if issued.Status != nil {
+		credentialStatus := toCredentialStatusResponse(issued.Status)
+		record.Status = &credentialStatus
+	}
+	return record
+}
+
+// GetCredentialStatus handles GET /api/issuer/credentials/{id}/status
+func (h *IssuerHandler) GetCredentialStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	credentialStatus, err := h.issuerUC.GetCredentialStatus(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Failed to get credential status", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, toCredentialStatusResponse(credentialStatus))
+}
+
+// GetStatusSnapshots handles GET /api/issuer/credentials/{id}/status/snapshots
+func (h *IssuerHandler) GetStatusSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	credentialID := r.PathValue("id")
+	snapshots, err := h.issuerUC.IssueStatusSnapshots(credentialID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to issue status snapshots", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.StatusSnapshotsResponse{
+		CredentialID: credentialID,
+		Snapshots:    snapshots,
+	})
+}
+
+// RevokeCredential handles POST /api/issuer/credentials/{id}/revoke
+func (h *IssuerHandler) RevokeCredential(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentialStatus(w, r, h.issuerUC.RevokeCredential, "Failed to revoke credential")
+}
+
+// SuspendCredential handles POST /api/issuer/credentials/{id}/suspend
+func (h *IssuerHandler) SuspendCredential(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentialStatus(w, r, h.issuerUC.SuspendCredential, "Failed to suspend credential")
+}
+
+// UnsuspendCredential handles POST /api/issuer/credentials/{id}/unsuspend
+func (h *IssuerHandler) UnsuspendCredential(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentialStatus(w, r, h.issuerUC.UnsuspendCredential, "Failed to unsuspend credential")
+}
+
+// SubscribeToStatus handles POST /api/issuer/status-subscriptions with a holder-signed subscription
+func (h *IssuerHandler) SubscribeToStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var subscription vc.StatusSubscription
+	if !decodeRequest(w, r, &subscription) {
+		return
+	}
+
+	if err := h.issuerUC.SubscribeToStatus(&subscription); err != nil {
+		writeErrorResponse(w, "Failed to subscribe to status notifications", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, &subscription)
+}
+
+// GetStatusList handles GET /api/status-lists/{id}
+func (h *IssuerHandler) GetStatusList(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	listCredential, err := h.issuerUC.GetStatusList(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Status list not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, listCredential)
+}
+
+// updateCredentialStatus runs a status change for the credential in the request path
+func (h *IssuerHandler) updateCredentialStatus(w http.ResponseWriter, r *http.Request, update func(string, issuer.StatusChange) (*issuer.CredentialStatus, error), failure string) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	// The body is optional; without one the change gets the default reason and takes effect now
+	var req dto.UpdateCredentialStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	credentialStatus, err := update(r.PathValue("id"), issuer.StatusChange{
+		Reason:        vc.StatusReason(req.Reason),
+		EffectiveDate: req.EffectiveDate,
+	})
+	if err != nil {
+		writeErrorResponse(w, failure, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, toCredentialStatusResponse(credentialStatus))
+}
+
+// toCredentialStatusResponse converts a use case credential status to its DTO
+func toCredentialStatusResponse(credentialStatus *issuer.CredentialStatus) dto.CredentialStatusResponse {
+	return dto.CredentialStatusResponse{
+		CredentialID: credentialStatus.CredentialID,
+		IssuerDID:    credentialStatus.IssuerDID,
+		Revoked:      credentialStatus.Revoked,
+		Suspended:    credentialStatus.Suspended,
+		Entries:      credentialStatus.Entries,
+	}
+}
+
+// GetSignedTreeHead handles GET /api/issuer/log/sth
+func (h *IssuerHandler) GetSignedTreeHead(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	issuerDID := r.URL.Query().Get("issuerDid")
+	if !checkDIDParam(w, "issuerDid", issuerDID) {
+		return
+	}
+	head, err := h.issuerUC.GetSignedTreeHead(issuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to get signed tree head", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, head)
+}
+
+// GetInclusionProof handles GET /api/issuer/log/proof
+func (h *IssuerHandler) GetInclusionProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	query := r.URL.Query()
+	credentialHash := query.Get("credentialHash")
+	if credentialHash == "" {
+		writeErrorResponse(w, "credentialHash is required", http.StatusBadRequest, "")
+		return
+	}
+
+	var treeSize uint64
+	if value := query.Get("treeSize"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeErrorResponse(w, "Invalid treeSize", http.StatusBadRequest, err.Error())
+			return
+		}
+		treeSize = parsed
+	}
+
+	proof, err := h.issuerUC.GetInclusionProof(query.Get("issuerDid"), credentialHash, treeSize)
+	if err != nil {
+		writeErrorResponse(w, "Failed to get inclusion proof", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, proof)
+}
+
+// VerifyInclusionProof handles POST /api/issuer/log/verify
+func (h *IssuerHandler) VerifyInclusionProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.VerifyInclusionProofRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.issuerUC.VerifyInclusionProof(req.Proof, req.TreeHead); err != nil {
+		writeErrorResponse(w, "Inclusion proof verification failed", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, map[string]interface{}{
+		"valid":          true,
+		"credentialHash": req.Proof.Entry.CredentialHash,
+		"issuedAt":       req.Proof.Entry.Timestamp,
+	})
+}
+
+// AnchorIssuer handles POST /api/issuer/anchor
+func (h *IssuerHandler) AnchorIssuer(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.AnchorIssuerRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	anchors, err := h.issuerUC.AnchorIssuer(req.IssuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to anchor issuer", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, anchors)
+}
+
+// RotateKeys handles POST /api/issuer/keys/rotate
+func (h *IssuerHandler) RotateKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RotateKeysRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	event, err := h.issuerUC.RotateKeys(req.IssuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to rotate keys", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, event)
+}
+
+// ListKeyRotations handles GET /api/issuer/keys/rotations
+func (h *IssuerHandler) ListKeyRotations(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	issuerDID := r.URL.Query().Get("issuerDid")
+	if !checkDIDParam(w, "issuerDid", issuerDID) {
+		return
+	}
+	rotations, err := h.issuerUC.ListKeyRotations(issuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to list key rotations", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, rotations)
+}
+
+// EscrowKey handles POST /api/issuer/keys/escrow
+func (h *IssuerHandler) EscrowKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.EscrowKeyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	escrow, err := h.issuerUC.EscrowKey(issuer.EscrowKeyRequest{
+		IssuerDID:  req.IssuerDID,
+		Custodians: req.Custodians,
+		Threshold:  req.Threshold,
+	})
+	if err != nil {
+		writeErrorResponse(w, "Failed to escrow key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, escrow)
+}
+
+// RecoverKey handles POST /api/issuer/keys/recover
+func (h *IssuerHandler) RecoverKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RecoverKeyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	shares := make([]*issuer.OpenedShare, len(req.Shares))
+	for i, share := range req.Shares {
+		shares[i] = &issuer.OpenedShare{
+			EscrowID:  share.EscrowID,
+			IssuerDID: share.IssuerDID,
+			KeyID:     share.KeyID,
+			Threshold: share.Threshold,
+			Index:     share.Index,
+			Value:     share.Value,
+		}
+	}
+
+	recovered, err := h.issuerUC.RecoverKey(shares)
+	if err != nil {
+		writeErrorResponse(w, "Failed to recover key", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, recovered)
+}
+
+// RegisterThreshold handles POST /api/issuer/thresholds
+func (h *IssuerHandler) RegisterThreshold(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RegisterThresholdRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	registration, err := h.issuerUC.RegisterThreshold(req.IssuerDID, req.VerifierDID, req.Threshold)
+	if err != nil {
+		writeErrorResponse(w, "Failed to register threshold", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, registration)
+}
+
+// LinkDomain handles POST /api/issuer/domains
+func (h *IssuerHandler) LinkDomain(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.LinkDomainRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.ValidDays < 0 {
+		writeErrorResponse(w, "Invalid validity period", http.StatusBadRequest, "validDays cannot be negative")
+		return
+	}
+
+	credential, err := h.issuerUC.LinkDomain(req.IssuerDID, req.Origin, time.Duration(req.ValidDays)*24*time.Hour)
+	if err != nil {
+		writeErrorResponse(w, "Failed to link domain", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, credential)
+}
+
+// GetDIDConfiguration handles GET /.well-known/did-configuration.json
+func (h *IssuerHandler) GetDIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	writeSuccessResponse(w, h.issuerUC.DIDConfiguration())
+}
+
+// ListThresholds handles GET /api/issuer/thresholds/list
+func (h *IssuerHandler) ListThresholds(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	issuerDID := r.URL.Query().Get("issuerDid")
+	if !checkDIDParam(w, "issuerDid", issuerDID) {
+		return
+	}
+	thresholds, err := h.issuerUC.ListThresholds(issuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to list thresholds", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, thresholds)
+}
+
+// GetAnchors handles GET /api/issuer/anchors
+func (h *IssuerHandler) GetAnchors(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	issuerDID := r.URL.Query().Get("issuerDid")
+	if !checkDIDParam(w, "issuerDid", issuerDID) {
+		return
+	}
+	anchors, err := h.issuerUC.GetAnchors(issuerDID)
+	if err != nil {
+		writeErrorResponse(w, "Failed to get anchors", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, anchors)
 }
@@ -36,10 +36,3 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{})
 
 	json.NewEncoder(w).Encode(data)
 }
-
-// enableCORS enables CORS for the response
-func enableCORS(w http.ResponseWriter) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+)
+
+// didListFields are request fields listing DIDs whose JSON names do not end in "Dids"
+var didListFields = map[string]bool{
+	"trustedIssuers": true,
+	"trustedRoots":   true,
+	"signers":        true,
+	"custodians":     true,
+	"anonymitySet":   true,
+}
+
+// decodeRequest decodes a JSON request body into req and checks the DIDs it names, writing a
+// 400 response and returning false when either fails
+func decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+		return false
+	}
+	return checkRequestDIDs(w, req)
+}
+
+// checkRequestDIDs checks the DIDs a decoded request names, writing a 400 response and returning
+// false when any is malformed
+func checkRequestDIDs(w http.ResponseWriter, req interface{}) bool {
+	if fields := checkDIDs(req); len(fields) > 0 {
+		writeValidationError(w, fields)
+		return false
+	}
+	return true
+}
+
+// checkDIDParam checks a DID passed as a query or path parameter, writing a 400 response and
+// returning false when it is malformed. An empty value is left to the handler.
+func checkDIDParam(w http.ResponseWriter, name, value string) bool {
+	if value == "" {
+		return true
+	}
+	if _, err := did.ParseDID(value); err != nil {
+		writeValidationError(w, []dto.FieldError{fieldError(name, value, err)})
+		return false
+	}
+	return true
+}
+
+// checkMethodName checks the DID method a setup request generates its DID under, writing a 400
+// response and returning false when it is malformed
+func checkMethodName(w http.ResponseWriter, method string) bool {
+	if err := did.ValidateMethodName(method); err != nil {
+		writeValidationError(w, []dto.FieldError{fieldError("method", method, err)})
+		return false
+	}
+	return true
+}
+
+// checkDIDs checks the DID fields of a decoded request: strings named "did" or ending in "Did",
+// string lists ending in "Dids" or named in didListFields, and verification method references.
+// Nested request DTOs are checked too, with their fields reported by dotted path.
+func checkDIDs(req interface{}) []dto.FieldError {
+	var fields []dto.FieldError
+	checkStruct(reflect.ValueOf(req), "", &fields)
+	return fields
+}
+
+func checkStruct(v reflect.Value, prefix string, fields *[]dto.FieldError) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type().PkgPath() != reflect.TypeOf(dto.ErrorResponse{}).PkgPath() {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		value := v.Field(i)
+
+		switch {
+		case value.Kind() == reflect.String && (name == "did" || strings.HasSuffix(name, "Did")):
+			checkDID(path, value.String(), fields)
+		case value.Kind() == reflect.String && name == "verificationMethod":
+			checkVerificationMethod(path, value.String(), fields)
+		case value.Type() == reflect.TypeOf([]string(nil)) && (strings.HasSuffix(name, "Dids") || didListFields[name]):
+			for j := 0; j < value.Len(); j++ {
+				checkDID(fmt.Sprintf("%s[%d]", path, j), value.Index(j).String(), fields)
+			}
+		case value.Kind() == reflect.Slice:
+			for j := 0; j < value.Len(); j++ {
+				checkStruct(value.Index(j), fmt.Sprintf("%s[%d].", path, j), fields)
+			}
+		default:
+			checkStruct(value, path+".", fields)
+		}
+	}
+}
+
+// checkDID checks an optional DID
+func checkDID(path, value string, fields *[]dto.FieldError) {
+	if value == "" {
+		return
+	}
+	if _, err := did.ParseDID(value); err != nil {
+		*fields = append(*fields, fieldError(path, value, err))
+	}
+}
+
+// checkVerificationMethod checks an optional verification method reference, a DID URL with a
+// fragment
+func checkVerificationMethod(path, value string, fields *[]dto.FieldError) {
+	if value == "" {
+		return
+	}
+	u, err := did.ParseDIDURL(value)
+	if err != nil {
+		*fields = append(*fields, fieldError(path, value, err))
+		return
+	}
+	if u.Fragment == "" {
+		*fields = append(*fields, dto.FieldError{Field: path, Value: value, Reason: "verification method reference has no fragment", Position: -1})
+	}
+}
+
+// fieldError describes a DID parse error for a request field. Oversized values are cut short so
+// the response does not echo them back.
+func fieldError(field, value string, err error) dto.FieldError {
+	fieldErr := dto.FieldError{Field: field, Value: value, Reason: err.Error(), Position: -1}
+	var parseErr *did.ParseError
+	if errors.As(err, &parseErr) {
+		fieldErr.Reason = parseErr.Reason
+		fieldErr.Position = parseErr.Position
+	}
+	if len(fieldErr.Value) > 64 {
+		fieldErr.Value = fieldErr.Value[:64] + "..."
+	}
+	return fieldErr
+}
+
+// writeValidationError writes a 400 response listing the rejected fields
+func writeValidationError(w http.ResponseWriter, fields []dto.FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	details := make([]string, len(fields))
+	for i, field := range fields {
+		details[i] = fmt.Sprintf("%s: %s", field.Field, field.Reason)
+	}
+	json.NewEncoder(w).Encode(dto.ErrorResponse{
+		Error:   "Invalid DID",
+		Code:    http.StatusBadRequest,
+		Details: strings.Join(details, "; "),
+		Fields:  fields,
+	})
+}
@@ -1,11 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/barcode"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
 )
 
 // VerifierHandler handles verifier-related HTTP requests
@@ -22,8 +31,6 @@ func NewVerifierHandler(verifierUC *verifier.UseCase) *VerifierHandler {
 
 // SetupVerifier handles POST /api/verifier/setup
 func (h *VerifierHandler) SetupVerifier(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -34,8 +41,10 @@ func (h *VerifierHandler) SetupVerifier(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req dto.SetupVerifierRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if !checkMethodName(w, req.Method) {
 		return
 	}
 
@@ -56,8 +65,6 @@ func (h *VerifierHandler) SetupVerifier(w http.ResponseWriter, r *http.Request)
 
 // VerifyPresentation handles POST /api/verifier/verify
 func (h *VerifierHandler) VerifyPresentation(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -67,34 +74,136 @@ func (h *VerifierHandler) VerifyPresentation(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var req dto.VerifyPresentationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
 		return
 	}
+	if !checkRequestDIDs(w, &req) {
+		return
+	}
+
+	// Keep the presentation as received for strict mode's encoding checks
+	var raw struct {
+		Presentation json.RawMessage `json:"presentation"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Barcode) > 0 {
+		if req.Presentation != nil {
+			writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, "give either presentation or barcode, not both")
+			return
+		}
+		presentation, document, err := barcode.DecodePresentation(req.Barcode)
+		if err != nil {
+			writeErrorResponse(w, "Invalid barcode", http.StatusBadRequest, err.Error())
+			return
+		}
+		req.Presentation = presentation
+		raw.Presentation = document
+	}
 
 	// Convert DTO to use case request
+	if req.MaxPresentationAgeSeconds < 0 {
+		writeErrorResponse(w, "Invalid maximum presentation age", http.StatusBadRequest, "maxPresentationAgeSeconds cannot be negative")
+		return
+	}
+
+	if req.MaxRevealedClaims < 0 {
+		writeErrorResponse(w, "Invalid maximum revealed claims", http.StatusBadRequest, "maxRevealedClaims cannot be negative")
+		return
+	}
+
+	overDisclosure := verifier.OverDisclosurePolicy(req.OverDisclosure)
+	if err := overDisclosure.Validate(); err != nil {
+		writeErrorResponse(w, "Invalid over-disclosure policy", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, domain := range req.TrustedDomains {
+		if _, err := vc.NormalizeOrigin(domain); err != nil {
+			writeErrorResponse(w, "Invalid trusted domain", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if req.Template != "" {
+		if _, err := verifier.GetRequestTemplate(req.Template); err != nil {
+			writeErrorResponse(w, "Invalid template", http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	ucReq := verifier.VerificationRequest{
-		Presentation:      req.Presentation,
-		RequiredClaims:    req.RequiredClaims,
-		TrustedIssuers:    req.TrustedIssuers,
-		VerificationNonce: req.VerificationNonce,
+		Presentation:       req.Presentation,
+		Template:           req.Template,
+		RequiredClaims:     req.RequiredClaims,
+		OptionalClaims:     req.OptionalClaims,
+		AllowedClaims:      req.AllowedClaims,
+		MaxRevealedClaims:  req.MaxRevealedClaims,
+		OverDisclosure:     overDisclosure,
+		TrustedIssuers:     req.TrustedIssuers,
+		TrustedDomains:     req.TrustedDomains,
+		TrustedRoots:       req.TrustedRoots,
+		ClaimConstraints:   req.ClaimConstraints,
+		RequiredPredicates: req.RequiredPredicates,
+		AbsentClaims:       req.AbsentClaims,
+		MinCoSigners:       req.MinCoSigners,
+		HolderBinding:      req.HolderBinding,
+		VerificationNonce:  req.VerificationNonce,
+		Domain:             req.Domain,
+		MaxPresentationAge: time.Duration(req.MaxPresentationAgeSeconds) * time.Second,
+		MaxStatusAge:       time.Duration(req.MaxStatusAgeSeconds) * time.Second,
+		VerifierDID:        req.VerifierDID,
+		Strict:             req.Strict,
+		RawPresentation:    raw.Presentation,
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       req.Purpose,
+			RetentionDays: req.RetentionDays,
+			Verifier:      req.Verifier,
+		},
 	}
 
 	// Verify presentation
-	result, err := h.verifierUC.VerifyPresentation(ucReq)
+	result, err := h.verifierUC.VerifyPresentationContext(r.Context(), ucReq)
 	if err != nil {
 		writeErrorResponse(w, "Failed to verify presentation", http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	response := dto.VerifyPresentationResponse{
-		Valid:           result.Valid,
-		Errors:          result.Errors,
-		RevealedClaims:  result.RevealedClaims,
-		HolderDID:       result.HolderDID,
-		IssuerDIDs:      result.IssuerDIDs,
-		CredentialTypes: result.CredentialTypes,
+		Valid:                  result.Valid,
+		Errors:                 result.Errors,
+		Indeterminate:          result.Indeterminate,
+		RevealedClaims:         result.RevealedClaims,
+		HolderDID:              result.HolderDID,
+		IssuerDIDs:             result.IssuerDIDs,
+		IssuerSets:             result.IssuerSets,
+		CredentialTypes:        result.CredentialTypes,
+		ProvidedOptionalClaims: result.ProvidedOptionalClaims,
+		MissingOptionalClaims:  result.MissingOptionalClaims,
+		Commitments:            result.Commitments,
+		Evidence:               result.Evidence,
+		CoSignatures:           result.CoSignatures,
+		DelegationChains:       result.DelegationChains,
+		ProvenPredicates:       result.ProvenPredicates,
+		AbsentClaims:           result.AbsentClaims,
+		OverDisclosedClaims:    result.OverDisclosedClaims,
+		Purpose:                result.Purpose,
+		RetentionDays:          result.RetentionDays,
+		Verifier:               result.Verifier,
+		Receipt:                result.Receipt,
+		ReportID:               result.ReportID,
+		Warnings:               result.Warnings,
 	}
 
 	writeSuccessResponse(w, response)
@@ -102,8 +211,6 @@ func (h *VerifierHandler) VerifyPresentation(w http.ResponseWriter, r *http.Requ
 
 // CreateVerificationRequest handles POST /api/verifier/verification-request
 func (h *VerifierHandler) CreateVerificationRequest(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -114,38 +221,499 @@ func (h *VerifierHandler) CreateVerificationRequest(w http.ResponseWriter, r *ht
 	}
 
 	var req dto.CreateVerificationRequestRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErrorResponse(w, "Invalid request body", http.StatusBadRequest, err.Error())
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	// Convert DTO to use case request
 	params := verifier.CreateVerificationRequestParams{
-		RequiredClaims:    req.RequiredClaims,
-		TrustedIssuers:    req.TrustedIssuers,
-		VerificationNonce: req.VerificationNonce,
+		Template:           req.Template,
+		RequiredClaims:     req.RequiredClaims,
+		OptionalClaims:     req.OptionalClaims,
+		TrustedIssuers:     req.TrustedIssuers,
+		ClaimConstraints:   req.ClaimConstraints,
+		VerificationNonce:  req.VerificationNonce,
+		OverDisclosure:     verifier.OverDisclosurePolicy(req.OverDisclosure),
+		MaxPresentationAge: time.Duration(req.MaxPresentationAgeSeconds) * time.Second,
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       req.Purpose,
+			RetentionDays: req.RetentionDays,
+			Verifier:      req.Verifier,
+			Accreditation: req.Accreditation,
+		},
 	}
 
 	// Create verification request
 	result, err := h.verifierUC.CreateVerificationRequest(params)
 	if err != nil {
-		writeErrorResponse(w, "Failed to create verification request", http.StatusInternalServerError, err.Error())
+		writeErrorResponse(w, "Failed to create verification request", http.StatusBadRequest, err.Error())
 		return
 	}
 
 	response := dto.CreateVerificationRequestResponse{
-		RequiredClaims:    result.RequiredClaims,
-		TrustedIssuers:    result.TrustedIssuers,
-		VerificationNonce: result.VerificationNonce,
+		Template:                  result.Template,
+		RequiredClaims:            result.RequiredClaims,
+		OptionalClaims:            result.OptionalClaims,
+		TrustedIssuers:            result.TrustedIssuers,
+		ClaimConstraints:          result.ClaimConstraints,
+		VerificationNonce:         result.VerificationNonce,
+		OverDisclosure:            string(result.OverDisclosure),
+		MaxPresentationAgeSeconds: int(result.MaxPresentationAge / time.Second),
+		Purpose:                   result.Purpose,
+		RetentionDays:             result.RetentionDays,
+		Verifier:                  result.Verifier,
+		Accreditation:             result.Accreditation,
 	}
 
 	writeSuccessResponse(w, response)
 }
 
+// ListRequestTemplates handles GET /api/verifier/request-templates
+func (h *VerifierHandler) ListRequestTemplates(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	response := dto.ListRequestTemplatesResponse{Templates: []dto.RequestTemplateDTO{}}
+	for _, template := range verifier.RequestTemplates() {
+		response.Templates = append(response.Templates, dto.RequestTemplateDTO{
+			Name:                      template.Name,
+			Description:               template.Description,
+			RequiredClaims:            template.RequiredClaims,
+			OptionalClaims:            template.OptionalClaims,
+			ClaimConstraints:          template.ClaimConstraints,
+			OverDisclosure:            string(template.OverDisclosure),
+			MaxPresentationAgeSeconds: int(template.MaxPresentationAge / time.Second),
+			Purpose:                   template.Purpose,
+			RetentionDays:             template.RetentionDays,
+		})
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// StartNegotiation handles POST /api/verifier/negotiations
+func (h *VerifierHandler) StartNegotiation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.StartNegotiationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	negotiation, err := h.verifierUC.StartNegotiation(req.VerifierDID, exchange.ProofRequest{
+		RequiredClaims: req.RequiredClaims,
+		OptionalClaims: req.OptionalClaims,
+		TrustedIssuers: req.TrustedIssuers,
+		Nonce:          req.Nonce,
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       req.Purpose,
+			RetentionDays: req.RetentionDays,
+			Verifier:      req.Verifier,
+		},
+	})
+	if err != nil {
+		writeErrorResponse(w, "Failed to start negotiation", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSONResponse(w, http.StatusCreated, negotiation)
+}
+
+// GetNegotiation handles GET /api/verifier/negotiations/{id}
+func (h *VerifierHandler) GetNegotiation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	negotiation, err := h.verifierUC.GetNegotiation(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Negotiation not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, negotiation)
+}
+
+// RespondToNegotiation handles POST /api/verifier/negotiations/{id}/respond
+func (h *VerifierHandler) RespondToNegotiation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.RespondToNegotiationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	decision, negotiation, err := h.verifierUC.RespondToNegotiation(r.PathValue("id"), req.HolderDID, req.CounterOffer)
+	if err != nil {
+		writeErrorResponse(w, "Failed to respond to negotiation", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.RespondToNegotiationResponse{
+		Decision:    decision,
+		Negotiation: negotiation,
+	})
+}
+
+// VerifyNegotiatedPresentation handles POST /api/verifier/negotiations/{id}/verify
+func (h *VerifierHandler) VerifyNegotiatedPresentation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.VerifyNegotiatedPresentationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	result, err := h.verifierUC.VerifyNegotiatedPresentation(r.Context(), r.PathValue("id"), req.Presentation)
+	if err != nil {
+		writeErrorResponse(w, "Failed to verify presentation", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := dto.VerifyPresentationResponse{
+		Valid:                  result.Valid,
+		Errors:                 result.Errors,
+		Indeterminate:          result.Indeterminate,
+		RevealedClaims:         result.RevealedClaims,
+		HolderDID:              result.HolderDID,
+		IssuerDIDs:             result.IssuerDIDs,
+		IssuerSets:             result.IssuerSets,
+		CredentialTypes:        result.CredentialTypes,
+		ProvidedOptionalClaims: result.ProvidedOptionalClaims,
+		MissingOptionalClaims:  result.MissingOptionalClaims,
+		Commitments:            result.Commitments,
+		Evidence:               result.Evidence,
+		CoSignatures:           result.CoSignatures,
+		DelegationChains:       result.DelegationChains,
+		ProvenPredicates:       result.ProvenPredicates,
+		AbsentClaims:           result.AbsentClaims,
+		OverDisclosedClaims:    result.OverDisclosedClaims,
+		Purpose:                result.Purpose,
+		RetentionDays:          result.RetentionDays,
+		Verifier:               result.Verifier,
+		Receipt:                result.Receipt,
+		ReportID:               result.ReportID,
+		Warnings:               result.Warnings,
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// maxSessionWait bounds how long a session long-poll holds the connection
+const maxSessionWait = 60 * time.Second
+
+// CreateSession handles POST /api/verifier/sessions
+func (h *VerifierHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.CreateSessionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	request := exchange.ProofRequest{
+		RequiredClaims: req.RequiredClaims,
+		OptionalClaims: req.OptionalClaims,
+		TrustedIssuers: req.TrustedIssuers,
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       req.Purpose,
+			RetentionDays: req.RetentionDays,
+			Verifier:      req.Verifier,
+		},
+	}
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+
+	var session *verifier.Session
+	var err error
+	if req.CallbackURL != "" {
+		session, err = h.verifierUC.CreateForwardingSession(req.VerifierDID, request, ttl, req.CallbackURL, req.RedirectURI)
+	} else if req.RedirectURI != "" {
+		err = fmt.Errorf("redirectUri requires callbackUrl")
+	} else {
+		session, err = h.verifierUC.CreateSession(req.VerifierDID, request, ttl)
+	}
+	if err != nil {
+		writeErrorResponse(w, "Failed to create session", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requestURI := sessionURL(r, session.ID, "request")
+	writeJSONResponse(w, http.StatusCreated, dto.CreateSessionResponse{
+		SessionID:   session.ID,
+		State:       string(session.State),
+		Nonce:       session.Request.Nonce,
+		ExpiresAt:   session.ExpiresAt,
+		RequestURI:  requestURI,
+		ResponseURI: sessionURL(r, session.ID, "presentation"),
+		QRPayload:   "bbs-sd://present?request_uri=" + url.QueryEscape(requestURI),
+	})
+}
+
+// GetSession handles GET /api/verifier/sessions/{id}?state={state}&wait={seconds}.
+// With wait set, the call long-polls until the session leaves state (its current state by default).
+func (h *VerifierHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	id := r.PathValue("id")
+	session, err := h.verifierUC.GetSession(id)
+	if err != nil {
+		writeErrorResponse(w, "Session not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	if waitParam := r.URL.Query().Get("wait"); waitParam != "" {
+		seconds, err := strconv.Atoi(waitParam)
+		if err != nil || seconds < 0 {
+			writeErrorResponse(w, "Invalid wait parameter", http.StatusBadRequest, "wait must be a non-negative number of seconds")
+			return
+		}
+
+		wait := min(time.Duration(seconds)*time.Second, maxSessionWait)
+		state := session.State
+		if s := r.URL.Query().Get("state"); s != "" {
+			state = verifier.SessionState(s)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), wait)
+		defer cancel()
+
+		session, err = h.verifierUC.WaitForSession(ctx, id, state)
+		if err != nil {
+			writeErrorResponse(w, "Session not found", http.StatusNotFound, err.Error())
+			return
+		}
+	}
+
+	writeSuccessResponse(w, session)
+}
+
+// StreamSession handles GET /api/verifier/sessions/{id}/events as server-sent events
+func (h *VerifierHandler) StreamSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorResponse(w, "Streaming not supported", http.StatusInternalServerError, "")
+		return
+	}
+
+	id := r.PathValue("id")
+	updates, stop, err := h.verifierUC.WatchSession(id)
+	if err != nil {
+		writeErrorResponse(w, "Session not found", http.StatusNotFound, err.Error())
+		return
+	}
+	defer stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// Send the current state first, then every change until the session finishes
+	for {
+		session, err := h.verifierUC.GetSession(id)
+		if err != nil {
+			return
+		}
+
+		event := "state"
+		if session.State.Done() {
+			event = "done"
+		}
+		data, err := json.Marshal(session)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+
+		if session.State.Done() {
+			return
+		}
+
+		// Nothing signals an unanswered session when it expires, so wake up at its expiry
+		timer := time.NewTimer(time.Until(session.ExpiresAt))
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		case <-updates:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// GetSessionRequest handles GET /api/verifier/sessions/{id}/request, called by the holder's device
+func (h *VerifierHandler) GetSessionRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	session, err := h.verifierUC.FetchSessionRequest(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Session is not available", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.SessionRequestResponse{
+		SessionID:   session.ID,
+		Request:     session.Request,
+		ResponseURI: sessionURL(r, session.ID, "presentation"),
+		ExpiresAt:   session.ExpiresAt,
+	})
+}
+
+// SubmitSessionPresentation handles POST /api/verifier/sessions/{id}/presentation, called by the holder's device
+func (h *VerifierHandler) SubmitSessionPresentation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.SubmitSessionPresentationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	id := r.PathValue("id")
+	result, err := h.verifierUC.SubmitSessionPresentation(r.Context(), id, req.Presentation)
+	if err != nil {
+		writeErrorResponse(w, "Failed to submit presentation", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err := h.verifierUC.GetSession(id)
+	if err != nil {
+		writeErrorResponse(w, "Session not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	if session.Forward != nil {
+		writeSuccessResponse(w, dto.SessionForwardAck{
+			State:       string(session.State),
+			RedirectURI: session.Forward.RedirectURI,
+		})
+		return
+	}
+
+	writeSuccessResponse(w, dto.SubmitSessionPresentationResponse{
+		State:  string(session.State),
+		Valid:  result.Valid,
+		Errors: result.Errors,
+	})
+}
+
+// VerifyPresentationToken handles POST /api/verifier/presentation-tokens/{id}/redeem, redeeming a
+// holder's presentation token and verifying its presentation in a session
+func (h *VerifierHandler) VerifyPresentationToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.VerifyPresentationTokenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	session, err := h.verifierUC.RedeemPresentationToken(r.Context(), r.PathValue("id"), req.Secret, req.VerifierDID, exchange.ProofRequest{
+		RequiredClaims: req.RequiredClaims,
+		OptionalClaims: req.OptionalClaims,
+		TrustedIssuers: req.TrustedIssuers,
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       req.Purpose,
+			RetentionDays: req.RetentionDays,
+			Verifier:      req.Verifier,
+		},
+	})
+	if err != nil {
+		writeTokenError(w, err)
+		return
+	}
+
+	writeSuccessResponse(w, session)
+}
+
+// sessionURL returns the absolute URL of a session endpoint as seen by the caller
+func sessionURL(r *http.Request, id, endpoint string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/api/verifier/sessions/" + url.PathEscape(id) + "/" + endpoint
+}
+
 // ListPresentations handles GET /api/verifier/presentations?verifierDid={did}
 func (h *VerifierHandler) ListPresentations(w http.ResponseWriter, r *http.Request) {
-	enableCORS(w)
-
 	if r.Method == http.MethodOptions {
 		return
 	}
@@ -155,21 +723,282 @@ func (h *VerifierHandler) ListPresentations(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	verifierDID := r.URL.Query().Get("verifierDid")
-	if verifierDID == "" {
+	query := r.URL.Query()
+	filter := vc.PresentationFilter{
+		VerifierDID: query.Get("verifierDid"),
+		HolderDID:   query.Get("holderDid"),
+	}
+	if filter.VerifierDID == "" {
 		writeErrorResponse(w, "verifierDid parameter is required", http.StatusBadRequest, "")
 		return
 	}
 
-	// List presentations
-	presentations, err := h.verifierUC.ListVerifiedPresentations(verifierDID)
+	if value := query.Get("valid"); value != "" {
+		valid, err := strconv.ParseBool(value)
+		if err != nil {
+			writeErrorResponse(w, "Invalid valid parameter", http.StatusBadRequest, err.Error())
+			return
+		}
+		filter.Valid = &valid
+	}
+
+	var err error
+	if filter.VerifiedAfter, err = parseTimeParam(query.Get("verifiedAfter"), false); err != nil {
+		writeErrorResponse(w, "Invalid verifiedAfter parameter", http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.VerifiedBefore, err = parseTimeParam(query.Get("verifiedBefore"), true); err != nil {
+		writeErrorResponse(w, "Invalid verifiedBefore parameter", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	records, err := h.verifierUC.ListVerifiedPresentations(filter)
 	if err != nil {
-		writeErrorResponse(w, "Failed to list presentations", http.StatusInternalServerError, err.Error())
+		writeErrorResponse(w, "Failed to list presentations", http.StatusBadRequest, err.Error())
 		return
 	}
 
 	response := dto.ListPresentationsResponse{
-		Presentations: presentations,
+		Presentations: make([]dto.PresentationRecordDTO, len(records)),
+	}
+	for i, record := range records {
+		response.Presentations[i] = toPresentationRecordDTO(record)
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// toPresentationRecordDTO converts a stored presentation record to its DTO
+func toPresentationRecordDTO(record *vc.PresentationRecord) dto.PresentationRecordDTO {
+	return dto.PresentationRecordDTO{
+		PresentationID: record.Presentation.ID,
+		HolderDID:      record.HolderDID(),
+		VerifierDID:    record.VerifierDID,
+		VerifiedAt:     record.VerifiedAt,
+		Valid:          record.Valid,
+		Errors:         record.Errors,
+		Presentation:   record.Presentation,
+	}
+}
+
+// GenerateNonce handles GET /api/verifier/nonce
+func (h *VerifierHandler) GenerateNonce(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	nonce, err := h.verifierUC.GenerateNonce()
+	if err != nil {
+		writeErrorResponse(w, "Failed to generate nonce", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, dto.GenerateNonceResponse{Nonce: nonce})
+}
+
+// GetCacheStats handles GET /api/verifier/cache-stats
+func (h *VerifierHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	response := dto.CacheStatsResponse{Caches: make(map[string]dto.CacheStatsDTO)}
+	for name, stats := range h.verifierUC.CacheStats() {
+		response.Caches[name] = dto.FromFetchStats(stats)
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// ListAuditLog handles GET /api/verifier/audit
+func (h *VerifierHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	entries, err := h.verifierUC.ListAuditLog()
+	if err != nil {
+		writeErrorResponse(w, "Failed to list audit log", http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := dto.ListAuditLogResponse{
+		Entries: make([]dto.AuditEntryDTO, len(entries)),
+	}
+	for i, entry := range entries {
+		response.Entries[i] = dto.AuditEntryDTO{
+			Timestamp:      entry.Timestamp,
+			PresentationID: entry.PresentationID,
+			HolderDID:      entry.HolderDID,
+			Valid:          entry.Valid,
+			RevealedClaims: entry.RevealedClaims,
+			Purpose:        entry.Purpose,
+			RetentionDays:  entry.RetentionDays,
+			Verifier:       entry.Verifier,
+			RetainUntil:    entry.RetainUntil,
+		}
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// OpenEvidence handles POST /api/verifier/evidence/open, checking an evidence document released by
+// the issuer against the presented evidence section
+func (h *VerifierHandler) OpenEvidence(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.OpenEvidenceRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	document, err := h.verifierUC.OpenEvidence(req.Evidence, req.Grant, req.Document)
+	if err != nil {
+		writeErrorResponse(w, "Failed to open evidence", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// OpenEvidence found the grant's evidence in the bundle
+	evidence, _ := req.Evidence.Find(req.Grant.EvidenceID)
+	writeSuccessResponse(w, dto.OpenEvidenceResponse{
+		EvidenceID: evidence.ID,
+		MediaType:  evidence.MediaType,
+		Document:   document,
+	})
+}
+
+// GetReceipt handles GET /api/verifier/receipts/{id}
+func (h *VerifierHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	receipt, err := h.verifierUC.GetReceipt(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Receipt not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, receipt)
+}
+
+// GetReport handles GET /api/verifier/results/{id}
+func (h *VerifierHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	report, err := h.verifierUC.GetReport(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, "Report not found", http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeSuccessResponse(w, report)
+}
+
+// ValidateReceipt handles POST /api/verifier/receipts/validate
+func (h *VerifierHandler) ValidateReceipt(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ValidateReceiptRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	response := dto.ValidateReceiptResponse{Valid: true}
+	if err := h.verifierUC.ValidateReceipt(req.Receipt, req.Presentation); err != nil {
+		response.Valid = false
+		response.Error = err.Error()
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// ValidateAssertion handles POST /api/verifier/assertions/validate, called by relying parties
+func (h *VerifierHandler) ValidateAssertion(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.ValidateAssertionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	response := dto.ValidateAssertionResponse{Valid: true}
+	if err := h.verifierUC.ValidateAssertion(req.Assertion, req.Audience); err != nil {
+		response.Valid = false
+		response.Error = err.Error()
+	}
+
+	writeSuccessResponse(w, response)
+}
+
+// VerifyContinuity handles POST /api/verifier/continuity-proofs/verify
+func (h *VerifierHandler) VerifyContinuity(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed, "")
+		return
+	}
+
+	var req dto.VerifyContinuityRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	response := dto.VerifyContinuityResponse{Valid: true}
+	if err := h.verifierUC.VerifyContinuityProof(req.Proof, req.Nonce); err != nil {
+		response.Valid = false
+		response.Error = err.Error()
 	}
 
 	writeSuccessResponse(w, response)
@@ -1,15 +1,26 @@
 package http
 
 import (
+	"crypto/sha256"
+	"fmt"
 	"log"
 	"net/http"
 	"path/filepath"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/handlers"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/scenario"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
 )
 
 // Server represents the HTTP server
@@ -20,79 +31,321 @@ type Server struct {
 	ageVerificationHandler *handlers.AgeVerificationHandler
 	healthHandler          *handlers.HealthHandler
 	bbsHandler             *handlers.BBSHandler
+	contextHandler         *handlers.ContextHandler
+	didHandler             *handlers.DIDHandler
+	roles                  map[api.Role]bool
+	corsPolicy             CORSPolicy
+	apiTokens              [][sha256.Size]byte
+	costLimiter            *costLimiter
+	clock                  clock.Clock
 	port                   string
 }
 
-// NewServer creates a new HTTP server
+// NewServer creates a new HTTP server. The holder and verifier are connected to a new event bus,
+// which the age verification endpoints reach them over; use NewServerWithEventBus when they are
+// connected already.
 func NewServer(
 	issuerUC *issuer.UseCase,
 	holderUC *holder.UseCase,
 	verifierUC *verifier.UseCase,
 	bbsFactory bbs.BBSServiceFactory,
 	port string,
+) *Server {
+	// A new bus has no handlers yet, so connecting cannot fail
+	bus := events.NewBus()
+	_ = holderUC.SetEventBus(bus)
+	_ = verifierUC.SetEventBus(bus)
+
+	return NewServerWithEventBus(issuerUC, holderUC, verifierUC, bus, bbsFactory, port)
+}
+
+// NewServerWithEventBus creates a new HTTP server whose age verification endpoints reach the
+// holder and verifier over bus, to which they must be connected
+func NewServerWithEventBus(
+	issuerUC *issuer.UseCase,
+	holderUC *holder.UseCase,
+	verifierUC *verifier.UseCase,
+	bus *events.Bus,
+	bbsFactory bbs.BBSServiceFactory,
+	port string,
 ) *Server {
 	return &Server{
 		issuerHandler:          handlers.NewIssuerHandler(issuerUC),
 		holderHandler:          handlers.NewHolderHandler(holderUC),
 		verifierHandler:        handlers.NewVerifierHandler(verifierUC),
-		ageVerificationHandler: handlers.NewAgeVerificationHandler(issuerUC, holderUC, verifierUC),
+		ageVerificationHandler: handlers.NewAgeVerificationHandler(issuerUC, bus, scenario.NewRunner(issuerUC, holderUC, verifierUC)),
 		healthHandler:          handlers.NewHealthHandler(),
 		bbsHandler:             handlers.NewBBSHandler(bbsFactory),
+		corsPolicy:             DefaultCORSPolicy(DefaultCORSConfig()),
 		port:                   port,
 	}
 }
 
+// SetRoles limits the API to the roles' endpoints, so the issuer, wallet and verifier can run as
+// separate services. The age verification endpoints span every role and are served only by a
+// server exposing all of them. No roles exposes every role.
+func (s *Server) SetRoles(roles ...api.Role) error {
+	if len(roles) == 0 {
+		s.roles = nil
+		return nil
+	}
+
+	enabled := make(map[api.Role]bool, len(roles))
+	for _, role := range roles {
+		if err := role.Validate(); err != nil {
+			return err
+		}
+		enabled[role] = true
+	}
+
+	s.roles = enabled
+	return nil
+}
+
+// SetDIDRepository publishes the repository's DID documents under /api/dids, so services running
+// the other roles can resolve them
+func (s *Server) SetDIDRepository(repo did.DIDRepository) {
+	s.didHandler = handlers.NewDIDHandler(repo)
+}
+
+// SetWebDomain serves the repository's did:web documents for domain, whatever host requests were
+// sent to; by default they are served for the request's host. Call it after SetDIDRepository.
+func (s *Server) SetWebDomain(domain string) {
+	if s.didHandler != nil {
+		s.didHandler.SetWebDomain(domain)
+	}
+}
+
+// serves reports whether the server exposes a role's endpoints
+func (s *Server) serves(role api.Role) bool {
+	return s.roles == nil || s.roles[role]
+}
+
+// SetCORSPolicy replaces the CORS policy applied to all routes
+func (s *Server) SetCORSPolicy(policy CORSPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid CORS policy: %w", err)
+	}
+
+	s.corsPolicy = policy
+	return nil
+}
+
+// SetHealthChecker makes /health run the checker's component checks
+func (s *Server) SetHealthChecker(checker *health.Checker) {
+	s.healthHandler.SetChecker(checker)
+}
+
+// SetReadiness makes /ready wait for the tracked startup tasks
+func (s *Server) SetReadiness(readiness *health.Readiness) {
+	s.healthHandler.SetReadiness(readiness)
+}
+
+// SetClock sets the clock the age verification endpoints compute ages and credential dates from,
+// and that proof verification budgets refill by
+func (s *Server) SetClock(c clock.Clock) {
+	s.ageVerificationHandler.SetClock(c)
+	s.clock = c
+	if s.costLimiter != nil {
+		s.costLimiter.clock = clock.OrSystem(c)
+	}
+}
+
+// SetDatePolicy sets the timezone the age verification endpoints compute ages and credential dates in
+func (s *Server) SetDatePolicy(policy vc.DatePolicy) {
+	s.ageVerificationHandler.SetDatePolicy(policy)
+}
+
+// SetStore keeps the age verification endpoints' open checks in store, so any server instance sharing
+// it can answer them
+func (s *Server) SetStore(store storage.KVStore) {
+	s.ageVerificationHandler.SetStore(store)
+}
+
+// SetProofDiagnostics enables the proof diagnostics endpoint, which echoes revealed messages and
+// challenge inputs for debugging
+func (s *Server) SetProofDiagnostics(enabled bool) {
+	s.bbsHandler.SetProofDiagnostics(enabled)
+}
+
+// SetContextLoader serves the loader's JSON-LD contexts under /contexts
+func (s *Server) SetContextLoader(loader *jsonld.BundledLoader) {
+	s.contextHandler = handlers.NewContextHandler(loader)
+}
+
 // Start starts the HTTP server
 func (s *Server) Start() error {
+	addr := ":" + s.port
+	log.Printf("🚀 BBS+ Selective Disclosure API Server starting on http://localhost%s", addr)
+	log.Printf("📱 Web UI available at: http://localhost%s", addr)
+	log.Printf("🏥 Health check: http://localhost%s/health (probes: /ready, /live)", addr)
+	log.Printf("📖 API Documentation:")
+	if s.serves(api.RoleIssuer) {
+		log.Printf("   Issuer API: http://localhost%s/api/issuer/*", addr)
+	}
+	if s.serves(api.RoleHolder) {
+		log.Printf("   Holder API: http://localhost%s/api/holder/*", addr)
+	}
+	if s.serves(api.RoleVerifier) {
+		log.Printf("   Verifier API: http://localhost%s/api/verifier/*", addr)
+	}
+
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// Handler builds the routes wrapped in the CORS and logging middleware
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	// Health endpoint
 	mux.HandleFunc("/health", s.healthHandler.Health)
+	mux.HandleFunc("/ready", s.healthHandler.Ready)
+	mux.HandleFunc("/live", s.healthHandler.Live)
+
+	// JSON-LD contexts for offline deployments
+	if s.contextHandler != nil {
+		mux.HandleFunc("/contexts", s.contextHandler.ListContexts)
+		mux.HandleFunc("/contexts/{name}", s.contextHandler.GetContext)
+	}
+
+	// DID documents for services running the other roles
+	if s.didHandler != nil {
+		mux.HandleFunc("/api/dids/{did}", s.didHandler.ResolveDID)
+		// did:web documents where did:web resolvers look for them
+		mux.HandleFunc("/.well-known/did.json", s.didHandler.ServeWebDID)
+		mux.HandleFunc("/users/{id}/did.json", s.didHandler.ServeWebDID)
+	}
 
 	// Issuer endpoints
-	mux.HandleFunc("/api/issuer/setup", s.issuerHandler.SetupIssuer)
-	mux.HandleFunc("/api/issuer/credentials", s.issuerHandler.IssueCredential)
-	mux.HandleFunc("/api/issuer/verify", s.issuerHandler.VerifyCredential)
+	if s.serves(api.RoleIssuer) {
+		mux.HandleFunc("/api/issuer/setup", s.issuerHandler.SetupIssuer)
+		mux.HandleFunc("/api/issuer/credentials", s.issuerHandler.IssueCredential)
+		mux.HandleFunc("/api/issuer/credentials/batch", s.issuerHandler.IssueCredentialBatch)
+		mux.HandleFunc("/api/issuer/credentials/{id}", s.issuerHandler.GetIssuedCredential)
+		mux.HandleFunc("/api/issuer/batches/{id}", s.issuerHandler.GetBatchJob)
+		mux.HandleFunc("/api/issuer/issuance-jobs", s.issuerHandler.EnqueueIssuance)
+		mux.HandleFunc("/api/issuer/issuance-jobs/{id}", s.issuerHandler.GetIssuanceJob)
+		mux.HandleFunc("/api/issuer/evidence/release", s.issuerHandler.ReleaseEvidence)
+		mux.HandleFunc("/api/issuer/approvals", s.issuerHandler.ListApprovals)
+		mux.HandleFunc("/api/issuer/approvals/{id}", s.issuerHandler.GetApproval)
+		mux.HandleFunc("/api/issuer/approvals/{id}/approve", s.issuerHandler.ApproveIssuance)
+		mux.HandleFunc("/api/issuer/approvals/{id}/reject", s.issuerHandler.RejectIssuance)
+		mux.HandleFunc("/api/issuer/approvals/{id}/comments", s.issuerHandler.CommentOnApproval)
+		mux.HandleFunc("/api/issuer/cosigning/policies", s.issuerHandler.SetCoSigningPolicy)
+		mux.HandleFunc("/api/issuer/signing-sessions", s.issuerHandler.StartSigningSession)
+		mux.HandleFunc("/api/issuer/signing-sessions/{id}", s.issuerHandler.GetSigningSession)
+		mux.HandleFunc("/api/issuer/signing-sessions/{id}/signatures", s.issuerHandler.CoSign)
+		mux.HandleFunc("/api/issuer/authorizations", s.issuerHandler.Authorizations)
+		mux.HandleFunc("/api/issuer/authorizations/accept", s.issuerHandler.AcceptAuthorization)
+		mux.HandleFunc("/api/issuer/verify", s.issuerHandler.VerifyCredential)
+		mux.HandleFunc("/api/issuer/credentials/{id}/status", s.issuerHandler.GetCredentialStatus)
+		mux.HandleFunc("/api/issuer/credentials/{id}/status/snapshots", s.issuerHandler.GetStatusSnapshots)
+		mux.HandleFunc("/api/issuer/credentials/{id}/revoke", s.issuerHandler.RevokeCredential)
+		mux.HandleFunc("/api/issuer/credentials/{id}/suspend", s.issuerHandler.SuspendCredential)
+		mux.HandleFunc("/api/issuer/credentials/{id}/unsuspend", s.issuerHandler.UnsuspendCredential)
+		mux.HandleFunc("/api/issuer/credentials/{id}/amend", s.issuerHandler.AmendCredential)
+		mux.HandleFunc("/api/issuer/credentials/{id}/latest", s.issuerHandler.GetLatestVersion)
+		mux.HandleFunc("/api/issuer/status-subscriptions", s.issuerHandler.SubscribeToStatus)
+		mux.HandleFunc("/api/status-lists/{id}", s.issuerHandler.GetStatusList)
+		mux.HandleFunc("/api/issuer/log/sth", s.issuerHandler.GetSignedTreeHead)
+		mux.HandleFunc("/api/issuer/log/proof", s.issuerHandler.GetInclusionProof)
+		mux.HandleFunc("/api/issuer/log/verify", s.issuerHandler.VerifyInclusionProof)
+		mux.HandleFunc("/api/issuer/keys/rotate", s.issuerHandler.RotateKeys)
+		mux.HandleFunc("/api/issuer/keys/rotations", s.issuerHandler.ListKeyRotations)
+		mux.HandleFunc("/api/issuer/keys/escrow", s.issuerHandler.EscrowKey)
+		mux.HandleFunc("/api/issuer/keys/recover", s.issuerHandler.RecoverKey)
+		mux.HandleFunc("/api/issuer/anchor", s.issuerHandler.AnchorIssuer)
+		mux.HandleFunc("/api/issuer/anchors", s.issuerHandler.GetAnchors)
+		mux.HandleFunc("/api/issuer/thresholds", s.issuerHandler.RegisterThreshold)
+		mux.HandleFunc("/api/issuer/thresholds/list", s.issuerHandler.ListThresholds)
+		mux.HandleFunc("/api/issuer/domains", s.issuerHandler.LinkDomain)
+		mux.HandleFunc("/.well-known/openid-credential-issuer", s.issuerHandler.GetIssuerMetadata)
+		mux.HandleFunc("/.well-known/did-configuration.json", s.issuerHandler.GetDIDConfiguration)
+		mux.HandleFunc("/api/schemas/{id}/display", s.issuerHandler.GetSchemaDisplay)
+	}
 
 	// Holder endpoints
-	mux.HandleFunc("/api/holder/setup", s.holderHandler.SetupHolder)
-	mux.HandleFunc("/api/holder/credentials", s.holderHandler.StoreCredential)
-	mux.HandleFunc("/api/holder/credentials/list", s.holderHandler.ListCredentials)
-	mux.HandleFunc("/api/holder/presentations", s.holderHandler.CreatePresentation)
+	if s.serves(api.RoleHolder) {
+		mux.HandleFunc("/api/holder/setup", s.holderHandler.SetupHolder)
+		mux.HandleFunc("/api/holder/credentials", s.holderHandler.StoreCredential)
+		mux.HandleFunc("/api/holder/credentials/list", s.holderHandler.ListCredentials)
+		mux.HandleFunc("/api/holder/credentials/import", s.holderHandler.ImportCredential)
+		mux.HandleFunc("/api/holder/credentials/match", s.holderHandler.MatchCredentials)
+		mux.HandleFunc("/api/holder/credentials/{id}", s.holderHandler.DeleteCredential)
+		mux.HandleFunc("/api/holder/credentials/{id}/derive", s.holderHandler.DeriveCredential)
+		mux.HandleFunc("/api/holder/credentials/{id}/latest", s.holderHandler.FetchLatestVersion)
+		mux.HandleFunc("/api/holder/credentials/{id}/redline", s.holderHandler.RedlineCredential)
+		mux.HandleFunc("/api/holder/presentations", s.holderHandler.CreatePresentation)
+		mux.HandleFunc("/api/holder/presentations/redline", s.holderHandler.RedlinePresentation)
+		mux.HandleFunc("/api/holder/counter-offers", s.holderHandler.PrepareCounterOffer)
+		mux.HandleFunc("/api/holder/status-snapshots", s.holderHandler.RefreshStatusSnapshots)
+		mux.HandleFunc("/api/holder/proof-templates", s.holderHandler.PrecomputeProofs)
+		mux.HandleFunc("/api/holder/status-subscriptions", s.holderHandler.SubscribeToStatus)
+		mux.HandleFunc("/api/holder/status-notifications", s.holderHandler.ReceiveStatusNotification)
+		mux.HandleFunc("/api/holder/commitments", s.holderHandler.ExportCommitments)
+		mux.HandleFunc("/api/holder/continuity-proofs", s.holderHandler.ProveContinuity)
+		mux.HandleFunc("/api/holder/evidence/grants", s.holderHandler.GrantEvidenceAccess)
+		mux.HandleFunc("/api/holder/addenda", s.holderHandler.RequestAddendum)
+		mux.HandleFunc("/api/holder/consents", s.holderHandler.ListConsents)
+		mux.HandleFunc("/api/holder/accreditation-policy", s.holderHandler.AccreditationPolicy)
+		mux.HandleFunc("/api/holder/presentation-tokens", s.holderHandler.MintPresentationToken)
+		mux.HandleFunc("/api/holder/presentation-tokens/{id}/redeem", s.holderHandler.RedeemPresentationToken)
+		mux.HandleFunc("/api/holder/receipts", s.holderHandler.StoreReceipt)
+		mux.HandleFunc("/api/holder/backup", s.holderHandler.ExportBackup)
+		mux.HandleFunc("/api/holder/restore", s.holderHandler.RestoreBackup)
+	}
 
 	// Verifier endpoints
-	mux.HandleFunc("/api/verifier/setup", s.verifierHandler.SetupVerifier)
-	mux.HandleFunc("/api/verifier/verify", s.verifierHandler.VerifyPresentation)
-	mux.HandleFunc("/api/verifier/verification-request", s.verifierHandler.CreateVerificationRequest)
-	mux.HandleFunc("/api/verifier/presentations", s.verifierHandler.ListPresentations)
+	if s.serves(api.RoleVerifier) {
+		mux.HandleFunc("/api/verifier/setup", s.verifierHandler.SetupVerifier)
+		mux.HandleFunc("/api/verifier/verify", s.verifierHandler.VerifyPresentation)
+		mux.HandleFunc("/api/verifier/verification-request", s.verifierHandler.CreateVerificationRequest)
+		mux.HandleFunc("/api/verifier/request-templates", s.verifierHandler.ListRequestTemplates)
+		mux.HandleFunc("/api/verifier/nonce", s.verifierHandler.GenerateNonce)
+		mux.HandleFunc("/api/verifier/negotiations", s.verifierHandler.StartNegotiation)
+		mux.HandleFunc("/api/verifier/negotiations/{id}", s.verifierHandler.GetNegotiation)
+		mux.HandleFunc("/api/verifier/negotiations/{id}/respond", s.verifierHandler.RespondToNegotiation)
+		mux.HandleFunc("/api/verifier/negotiations/{id}/verify", s.verifierHandler.VerifyNegotiatedPresentation)
+		mux.HandleFunc("/api/verifier/sessions", s.verifierHandler.CreateSession)
+		mux.HandleFunc("/api/verifier/sessions/{id}", s.verifierHandler.GetSession)
+		mux.HandleFunc("/api/verifier/sessions/{id}/events", s.verifierHandler.StreamSession)
+		mux.HandleFunc("/api/verifier/sessions/{id}/request", s.verifierHandler.GetSessionRequest)
+		mux.HandleFunc("/api/verifier/sessions/{id}/presentation", s.verifierHandler.SubmitSessionPresentation)
+		mux.HandleFunc("/api/verifier/presentation-tokens/{id}/redeem", s.verifierHandler.VerifyPresentationToken)
+		mux.HandleFunc("/api/verifier/presentations", s.verifierHandler.ListPresentations)
+		mux.HandleFunc("/api/verifier/audit", s.verifierHandler.ListAuditLog)
+		mux.HandleFunc("/api/verifier/cache-stats", s.verifierHandler.GetCacheStats)
+		mux.HandleFunc("/api/verifier/evidence/open", s.verifierHandler.OpenEvidence)
+		mux.HandleFunc("/api/verifier/receipts/validate", s.verifierHandler.ValidateReceipt)
+		mux.HandleFunc("/api/verifier/assertions/validate", s.verifierHandler.ValidateAssertion)
+		mux.HandleFunc("/api/verifier/continuity-proofs/verify", s.verifierHandler.VerifyContinuity)
+		mux.HandleFunc("/api/verifier/receipts/{id}", s.verifierHandler.GetReceipt)
+		mux.HandleFunc("/api/verifier/results/{id}", s.verifierHandler.GetReport)
+	}
 
 	// BBS endpoints
 	mux.HandleFunc("/api/bbs/test", s.bbsHandler.TestProvider)
 	mux.HandleFunc("/api/bbs/benchmark", s.bbsHandler.BenchmarkProviders)
+	mux.HandleFunc("/api/bbs/proofs/diagnose", s.bbsHandler.DiagnoseProof)
 
 	// Age Verification endpoints
-	mux.HandleFunc("/api/age-verification/credential", s.ageVerificationHandler.IssueAgeCredential)
-	mux.HandleFunc("/api/age-verification/verify", s.ageVerificationHandler.VerifyAge)
-	mux.HandleFunc("/api/age-verification/scenarios", s.ageVerificationHandler.GetAgeScenarios)
-	mux.HandleFunc("/api/age-verification/demo", s.ageVerificationHandler.RunAgeDemo)
+	if s.serves(api.RoleIssuer) && s.serves(api.RoleHolder) && s.serves(api.RoleVerifier) {
+		mux.HandleFunc("/api/age-verification/credential", s.ageVerificationHandler.IssueAgeCredential)
+		mux.HandleFunc("/api/age-verification/requests", s.ageVerificationHandler.CreateAgeRequest)
+		mux.HandleFunc("/api/age-verification/requests/{id}/verify", s.ageVerificationHandler.VerifyAge)
+		mux.HandleFunc("/api/age-verification/presentations", s.ageVerificationHandler.CreateAgePresentation)
+		mux.HandleFunc("/api/age-verification/scenarios", s.ageVerificationHandler.GetAgeScenarios)
+		mux.HandleFunc("/api/age-verification/demo", s.ageVerificationHandler.RunAgeDemo)
+		mux.HandleFunc("/api/age-verification/demo/{id}", s.ageVerificationHandler.GetDemoRun)
+		mux.HandleFunc("/api/age-verification/demo/{id}/events", s.ageVerificationHandler.StreamDemoRun)
+	}
 
 	// Serve static files (for the web UI)
 	webDir := "./web/"
 	mux.Handle("/", http.FileServer(http.Dir(webDir)))
 
-	// Add logging middleware
-	loggedMux := loggingMiddleware(mux)
-
-	addr := ":" + s.port
-	log.Printf("🚀 BBS+ Selective Disclosure API Server starting on http://localhost%s", addr)
-	log.Printf("📱 Web UI available at: http://localhost%s", addr)
-	log.Printf("🏥 Health check: http://localhost%s/health", addr)
-	log.Printf("📖 API Documentation:")
-	log.Printf("   Issuer API: http://localhost%s/api/issuer/*", addr)
-	log.Printf("   Holder API: http://localhost%s/api/holder/*", addr)
-	log.Printf("   Verifier API: http://localhost%s/api/verifier/*", addr)
-
-	return http.ListenAndServe(addr, loggedMux)
+	// Add cost, auth, CORS, tracing and logging middleware
+	return loggingMiddleware(tracingMiddleware(corsMiddleware(s.corsPolicy, authMiddleware(s.apiTokens, costMiddleware(s.costLimiter, mux)))))
 }
 
 // loggingMiddleware logs all incoming requests
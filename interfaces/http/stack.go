@@ -0,0 +1,32 @@
+package http
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// NewStackServer creates the HTTP API over a stack's use cases, dated by its clock, guarded by its
+// API tokens and limited to its roles. It publishes the stack's DID documents for its peers.
+// Stateless stacks keep open age checks in the shared store.
+func NewStackServer(stack *sdk.Stack, port string) (*Server, error) {
+	config := stack.Config()
+
+	server := NewServerWithEventBus(stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase, stack.Events, bbs.NewFactory(), port)
+	if err := server.SetRoles(config.Roles...); err != nil {
+		return nil, err
+	}
+	server.SetDIDRepository(stack.DIDRepository)
+	server.SetWebDomain(config.WebDomain)
+	server.SetClock(stack.Clock)
+	if config.Stateless {
+		server.SetStore(storage.Prefixed(config.Store, "agecheck:"))
+	}
+	if err := server.SetAPITokens(config.APITokens); err != nil {
+		return nil, err
+	}
+	if err := server.SetCostPolicy(config.CostPolicy); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
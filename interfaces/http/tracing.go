@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
+)
+
+// tracingMiddleware starts a server span per request, continuing the caller's trace when a
+// traceparent header is present, so use case spans recorded under r.Context() join it
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracing.StartKind(ctx, r.Method+" "+r.URL.Path, tracing.SpanKindServer,
+			tracing.String("http.method", r.Method),
+			tracing.String("http.target", r.URL.Path),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		traced := r.WithContext(ctx)
+		next.ServeHTTP(recorder, traced)
+
+		// The mux records the matched pattern on the request it was handed; naming the span after
+		// the route rather than the path keeps span names low-cardinality
+		if traced.Pattern != "" {
+			span.SetName(r.Method + " " + routePath(traced.Pattern))
+			span.SetAttributes(tracing.String("http.route", routePath(traced.Pattern)))
+		}
+		span.SetAttributes(tracing.Int("http.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(tracing.StatusError, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// routePath strips the optional method and host from a ServeMux pattern
+func routePath(pattern string) string {
+	if i := strings.IndexByte(pattern, '/'); i >= 0 {
+		return pattern[i:]
+	}
+	return pattern
+}
+
+// statusRecorder captures the response status code for the request span
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush keeps server-sent event streams working behind the middleware
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
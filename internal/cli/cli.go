@@ -0,0 +1,30 @@
+// Package cli holds the flag handling the commands share
+package cli
+
+import (
+	"log"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/secrets"
+)
+
+// SplitList splits a comma-separated flag value, dropping empty items
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SecretsResolver resolves the secret flags, which may name where the secret is kept instead of
+// holding it. It exits when the secrets configuration in the environment is invalid.
+func SecretsResolver() *secrets.Resolver {
+	resolver, err := secrets.NewResolverFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Invalid secrets configuration: %v", err)
+	}
+	return resolver
+}
@@ -0,0 +1,248 @@
+package scenario
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/barcode"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// AgeDemoScenario is the scenario name of the age verification demo
+const AgeDemoScenario = "age-verification"
+
+// SupportedMinAges lists the age thresholds the demo credential carries claims for
+var SupportedMinAges = []int{13, 16, 18, 21, 25, 65}
+
+// AgeDemoParams configures an age verification demo run
+type AgeDemoParams struct {
+	ServiceType string
+	MinAge      int
+	// DateOfBirth of the demo citizen (YYYY-MM-DD); defaults to 25 years ago
+	DateOfBirth string
+	// StepDelay pauses between steps so a UI can animate the progress
+	StepDelay time.Duration
+}
+
+// ageDemoState carries the artifacts produced by earlier steps to later ones
+type ageDemoState struct {
+	issuer     *issuer.IssuerSetup
+	holder     *holder.HolderSetup
+	verifier   *verifier.VerifierSetup
+	credential *vc.VerifiableCredential
+	request    *verifier.CreateVerificationRequestParams
+	// barcode is the presentation as the citizen shows it in person, as QR code payloads
+	barcode []string
+}
+
+// StartAgeDemo starts the age verification demo: setup actors, issue, present and verify
+func (r *Runner) StartAgeDemo(params AgeDemoParams) (*Run, error) {
+	if params.ServiceType == "" {
+		params.ServiceType = "gaming"
+	}
+	if params.MinAge == 0 {
+		params.MinAge = 18
+	}
+	if !isSupportedMinAge(params.MinAge) {
+		return nil, fmt.Errorf("unsupported minimum age: %d", params.MinAge)
+	}
+
+	r.mu.RLock()
+	policy := r.datePolicy
+	r.mu.RUnlock()
+
+	birthDate := policy.Today(time.Now()).AddDate(-25, 0, 0)
+	if params.DateOfBirth != "" {
+		parsed, err := policy.ParseDate(params.DateOfBirth)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date of birth: %w", err)
+		}
+		birthDate = parsed
+	}
+
+	ageClaimKey := fmt.Sprintf("ageOver%d", params.MinAge)
+	hiddenClaims := []string{"firstName", "lastName", "dateOfBirth", "address", "idNumber", "birthYear"}
+	state := &ageDemoState{}
+
+	definitions := []stepDefinition{
+		{
+			Title:       "Setup Government Authority",
+			Description: "Initialize digital ID issuer with BBS+ keys",
+			Action: func() (map[string]interface{}, error) {
+				setup, err := r.issuerUC.SetupIssuer("example")
+				if err != nil {
+					return nil, err
+				}
+				state.issuer = setup
+				return map[string]interface{}{"issuerDid": setup.DID.String()}, nil
+			},
+		},
+		{
+			Title:       "Setup Citizen",
+			Description: "Create citizen DID for credential holder",
+			Action: func() (map[string]interface{}, error) {
+				setup, err := r.holderUC.SetupHolder("example")
+				if err != nil {
+					return nil, err
+				}
+				state.holder = setup
+				return map[string]interface{}{"holderDid": setup.DID.String()}, nil
+			},
+		},
+		{
+			Title:       fmt.Sprintf("Setup %s Service", params.ServiceType),
+			Description: fmt.Sprintf("Initialize %s platform for age verification", params.ServiceType),
+			Action: func() (map[string]interface{}, error) {
+				setup, err := r.verifierUC.SetupVerifier("example")
+				if err != nil {
+					return nil, err
+				}
+				state.verifier = setup
+				return map[string]interface{}{"verifierDid": setup.DID.String()}, nil
+			},
+		},
+		{
+			Title:       "Issue Enhanced ID",
+			Description: "Government issues digital ID with age verification claims",
+			Action: func() (map[string]interface{}, error) {
+				credential, err := r.issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+					IssuerDID:  state.issuer.DID.String(),
+					SubjectDID: state.holder.DID.String(),
+					Claims:     ageDemoClaims(birthDate, policy),
+				})
+				if err != nil {
+					return nil, err
+				}
+				if err := r.holderUC.StoreCredential(credential); err != nil {
+					return nil, err
+				}
+				state.credential = credential
+				return map[string]interface{}{
+					"credentialId": credential.ID,
+					"claimCount":   len(credential.CredentialSubject) - 1,
+				}, nil
+			},
+		},
+		{
+			Title:       "Age Verification Request",
+			Description: fmt.Sprintf("Service requests %d+ age verification", params.MinAge),
+			Action: func() (map[string]interface{}, error) {
+				request, err := r.verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+					RequiredClaims: []string{ageClaimKey},
+					TrustedIssuers: []string{state.issuer.DID.String()},
+					RequestMetadata: vc.RequestMetadata{
+						Purpose:  fmt.Sprintf("age-over-%d", params.MinAge),
+						Verifier: &vc.VerifierIdentity{DID: state.verifier.DID.String()},
+					},
+				})
+				if err != nil {
+					return nil, err
+				}
+				state.request = request
+				return map[string]interface{}{
+					"requiredClaims":    request.RequiredClaims,
+					"verificationNonce": request.VerificationNonce,
+					"purpose":           request.Purpose,
+				}, nil
+			},
+		},
+		{
+			Title:       "Privacy-Preserving Presentation",
+			Description: "Citizen creates selective disclosure presentation",
+			Action: func() (map[string]interface{}, error) {
+				revealed := []string{ageClaimKey, "nationality", "documentType"}
+				presentation, err := r.holderUC.CreatePresentation(holder.PresentationRequest{
+					HolderDID:     state.holder.DID.String(),
+					CredentialIDs: []string{state.credential.ID},
+					SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+						{CredentialID: state.credential.ID, RevealedAttributes: revealed},
+					},
+					Nonce:           state.request.VerificationNonce,
+					VerifierDID:     state.verifier.DID.String(),
+					RequestMetadata: state.request.RequestMetadata,
+				})
+				if err != nil {
+					return nil, err
+				}
+				payloads, err := barcode.EncodePresentation(presentation, 0)
+				if err != nil {
+					return nil, err
+				}
+				state.barcode = payloads
+				return map[string]interface{}{
+					"presentationId": presentation.ID,
+					"revealed":       revealed,
+					"hidden":         hiddenClaims,
+					"barcode":        payloads,
+				}, nil
+			},
+		},
+		{
+			Title:       "Verification",
+			Description: "Service verifies age without seeing personal details",
+			Action: func() (map[string]interface{}, error) {
+				// The service scans the citizen's QR codes, so verification needs no network
+				presentation, _, err := barcode.DecodePresentation(state.barcode)
+				if err != nil {
+					return nil, err
+				}
+				result, err := r.verifierUC.VerifyPresentation(verifier.VerificationRequest{
+					Presentation:      presentation,
+					RequiredClaims:    state.request.RequiredClaims,
+					TrustedIssuers:    state.request.TrustedIssuers,
+					VerificationNonce: state.request.VerificationNonce,
+					RequestMetadata:   state.request.RequestMetadata,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				accessGranted, _ := result.RevealedClaims[ageClaimKey].(bool)
+				outcome := map[string]interface{}{
+					"valid":          result.Valid,
+					"accessGranted":  result.Valid && accessGranted,
+					"revealedClaims": result.RevealedClaims,
+				}
+				if !result.Valid {
+					return outcome, fmt.Errorf("presentation verification failed: %v", result.Errors)
+				}
+				return outcome, nil
+			},
+		},
+	}
+
+	return r.start(AgeDemoScenario, definitions, params.StepDelay), nil
+}
+
+// ageDemoClaims builds the demo citizen's ID claims, including boolean age thresholds
+func ageDemoClaims(birthDate time.Time, policy vc.DatePolicy) []vc.Claim {
+	age := policy.AgeAt(birthDate, time.Now())
+
+	claims := []vc.Claim{
+		{Key: "firstName", Value: "Alice"},
+		{Key: "lastName", Value: "Nguyen"},
+		{Key: "dateOfBirth", Value: birthDate.Format(vc.DateLayout)},
+		{Key: "nationality", Value: "Vietnamese"},
+		{Key: "address", Value: "123 Main St, Ho Chi Minh City"},
+		{Key: "idNumber", Value: "ID123456789"},
+		{Key: "birthYear", Value: birthDate.Year()},
+		{Key: "documentType", Value: "national_id"},
+	}
+	for _, minAge := range SupportedMinAges {
+		claims = append(claims, vc.Claim{Key: fmt.Sprintf("ageOver%d", minAge), Value: age >= minAge})
+	}
+
+	return claims
+}
+
+func isSupportedMinAge(minAge int) bool {
+	for _, supported := range SupportedMinAges {
+		if supported == minAge {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package scenario
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// Status represents the state of a run or a single step
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Step represents one step of a scenario run
+type Step struct {
+	Index       int                    `json:"index"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Status      Status                 `json:"status"`
+	Result      map[string]interface{} `json:"result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	StartedAt   *time.Time             `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time             `json:"finishedAt,omitempty"`
+}
+
+// Run represents a scenario execution and its step-by-step progress
+type Run struct {
+	ID         string     `json:"id"`
+	Scenario   string     `json:"scenario"`
+	Status     Status     `json:"status"`
+	Steps      []Step     `json:"steps"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Done reports whether the run has finished, successfully or not
+func (r *Run) Done() bool {
+	return r.Status == StatusCompleted || r.Status == StatusFailed
+}
+
+// stepDefinition describes a step and the action that executes it
+type stepDefinition struct {
+	Title       string
+	Description string
+	Action      func() (map[string]interface{}, error)
+}
+
+// Runner executes demo scenarios server-side against the issuer, holder and verifier use cases
+type Runner struct {
+	issuerUC   *issuer.UseCase
+	holderUC   *holder.UseCase
+	verifierUC *verifier.UseCase
+
+	mu       sync.RWMutex
+	runs     map[string]*Run
+	watchers map[string][]chan struct{}
+
+	// datePolicy reads the demo citizen's date of birth and decides the day ages are computed on
+	datePolicy vc.DatePolicy
+}
+
+// NewRunner creates a new scenario runner
+func NewRunner(issuerUC *issuer.UseCase, holderUC *holder.UseCase, verifierUC *verifier.UseCase) *Runner {
+	return &Runner{
+		issuerUC:   issuerUC,
+		holderUC:   holderUC,
+		verifierUC: verifierUC,
+		runs:       make(map[string]*Run),
+		watchers:   make(map[string][]chan struct{}),
+	}
+}
+
+// SetDatePolicy sets the timezone demo ages are computed in
+func (r *Runner) SetDatePolicy(policy vc.DatePolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.datePolicy = policy
+}
+
+// GetRun returns a snapshot of a run's current progress
+func (r *Runner) GetRun(id string) (*Run, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, fmt.Errorf("run not found: %s", id)
+	}
+
+	return snapshot(run), nil
+}
+
+// Watch returns a channel that is signalled whenever the run changes.
+// The channel is closed once the run finishes; call the returned function to stop watching early.
+func (r *Runner) Watch(id string) (<-chan struct{}, func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("run not found: %s", id)
+	}
+
+	ch := make(chan struct{}, 1)
+	if run.Done() {
+		close(ch)
+		return ch, func() {}, nil
+	}
+	r.watchers[id] = append(r.watchers[id], ch)
+
+	cancel := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		watchers := r.watchers[id]
+		for i, w := range watchers {
+			if w == ch {
+				r.watchers[id] = append(watchers[:i], watchers[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}
+
+	return ch, cancel, nil
+}
+
+// start registers a new run for the given steps and executes it in the background
+func (r *Runner) start(scenario string, definitions []stepDefinition, stepDelay time.Duration) *Run {
+	run := &Run{
+		ID:        uuid.New().String(),
+		Scenario:  scenario,
+		Status:    StatusPending,
+		Steps:     make([]Step, len(definitions)),
+		CreatedAt: time.Now(),
+	}
+	for i, def := range definitions {
+		run.Steps[i] = Step{
+			Index:       i + 1,
+			Title:       def.Title,
+			Description: def.Description,
+			Status:      StatusPending,
+		}
+	}
+
+	r.mu.Lock()
+	r.runs[run.ID] = run
+	result := snapshot(run)
+	r.mu.Unlock()
+
+	go r.execute(run.ID, definitions, stepDelay)
+
+	return result
+}
+
+// execute runs each step in order, stopping at the first failure
+func (r *Runner) execute(id string, definitions []stepDefinition, stepDelay time.Duration) {
+	r.update(id, func(run *Run) {
+		run.Status = StatusRunning
+	})
+
+	for i, def := range definitions {
+		if i > 0 && stepDelay > 0 {
+			time.Sleep(stepDelay)
+		}
+
+		r.update(id, func(run *Run) {
+			now := time.Now()
+			run.Steps[i].Status = StatusRunning
+			run.Steps[i].StartedAt = &now
+		})
+
+		result, err := def.Action()
+
+		r.update(id, func(run *Run) {
+			now := time.Now()
+			step := &run.Steps[i]
+			step.FinishedAt = &now
+			step.Result = result
+			if err != nil {
+				step.Status = StatusFailed
+				step.Error = err.Error()
+				run.Status = StatusFailed
+				run.Error = fmt.Sprintf("step %d (%s) failed: %v", step.Index, step.Title, err)
+				run.FinishedAt = &now
+				return
+			}
+			step.Status = StatusCompleted
+		})
+
+		if err != nil {
+			r.finish(id)
+			return
+		}
+	}
+
+	r.update(id, func(run *Run) {
+		now := time.Now()
+		run.Status = StatusCompleted
+		run.FinishedAt = &now
+	})
+	r.finish(id)
+}
+
+// update applies a change to a run and notifies its watchers
+func (r *Runner) update(id string, change func(run *Run)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[id]
+	if !ok {
+		return
+	}
+	change(run)
+
+	for _, ch := range r.watchers[id] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A notification is already pending; the watcher will read the latest state
+		}
+	}
+}
+
+// finish closes all watcher channels for a completed run
+func (r *Runner) finish(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, ch := range r.watchers[id] {
+		close(ch)
+	}
+	delete(r.watchers, id)
+}
+
+// snapshot copies a run so callers can read it without holding the lock
+func snapshot(run *Run) *Run {
+	copied := *run
+	copied.Steps = make([]Step, len(run.Steps))
+	copy(copied.Steps, run.Steps)
+	return &copied
+}
@@ -0,0 +1,201 @@
+package anchor
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+)
+
+func digestOf(s string) []byte {
+	digest := sha256.Sum256([]byte(s))
+	return digest[:]
+}
+
+func TestService(t *testing.T) {
+	backend := NewMockBackend()
+	service := NewService(backend)
+
+	first, err := service.Anchor("did:example:issuer", KindKeySet, digestOf("keys-v1"))
+	require.NoError(t, err)
+	assert.Equal(t, "mock", first.Receipt.Backend)
+	require.NoError(t, service.Check("did:example:issuer", KindKeySet, digestOf("keys-v1")))
+
+	// Unchanged digests are not anchored again
+	again, err := service.Anchor("did:example:issuer", KindKeySet, digestOf("keys-v1"))
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Len(t, backend.ledger, 1)
+
+	_, err = service.Anchor("did:example:issuer", KindKeySet, digestOf("keys-v2"))
+	require.NoError(t, err)
+	assert.Len(t, service.History("did:example:issuer", KindKeySet), 2)
+
+	// Only the latest anchor is authoritative
+	assert.Error(t, service.Check("did:example:issuer", KindKeySet, digestOf("keys-v1")))
+	assert.NoError(t, service.Check("did:example:issuer", KindKeySet, digestOf("keys-v2")))
+
+	// Kinds and subjects are anchored independently
+	assert.Error(t, service.Check("did:example:issuer", KindStatusList, digestOf("keys-v2")))
+	assert.Error(t, service.Check("did:example:other", KindKeySet, digestOf("keys-v2")))
+
+	// A receipt the backend does not confirm fails the check
+	latest, err := service.Latest("did:example:issuer", KindKeySet)
+	require.NoError(t, err)
+	latest.Receipt.Reference = "0"
+	assert.Error(t, service.Check("did:example:issuer", KindKeySet, digestOf("keys-v2")))
+
+	_, err = service.Anchor("", KindKeySet, digestOf("x"))
+	assert.Error(t, err)
+}
+
+func TestKeySetDigest(t *testing.T) {
+	doc := &did.DIDDocument{
+		ID: "did:example:issuer",
+		VerificationMethod: []did.VerificationMethod{
+			{ID: "did:example:issuer#key-2", Type: "Ed25519VerificationKey2020", PublicKeyMultibase: "z2"},
+			{ID: "did:example:issuer#key-1", Type: "Ed25519VerificationKey2020", PublicKeyMultibase: "z1"},
+		},
+		Updated: time.Now(),
+	}
+
+	digest, err := KeySetDigest(doc)
+	require.NoError(t, err)
+
+	// Method order and unrelated fields do not matter
+	reordered := *doc
+	reordered.VerificationMethod = []did.VerificationMethod{doc.VerificationMethod[1], doc.VerificationMethod[0]}
+	reordered.Updated = time.Now().Add(time.Hour)
+	same, err := KeySetDigest(&reordered)
+	require.NoError(t, err)
+	assert.Equal(t, digest, same)
+
+	rotated := *doc
+	rotated.VerificationMethod = []did.VerificationMethod{doc.VerificationMethod[0]}
+	changed, err := KeySetDigest(&rotated)
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, changed)
+}
+
+func TestEthereumBackend(t *testing.T) {
+	const from = "0x00000000000000000000000000000000000000aa"
+	var mu sync.Mutex
+	transactions := make(map[string]map[string]interface{})
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var result interface{}
+		switch req.Method {
+		case "eth_sendTransaction":
+			var tx map[string]string
+			require.NoError(t, json.Unmarshal(req.Params[0], &tx))
+			hash := "0xtx" + strings.Repeat("0", len(transactions))
+			transactions[hash] = map[string]interface{}{"from": tx["from"], "input": tx["data"], "blockNumber": "0x1"}
+			result = hash
+		case "eth_getTransactionByHash":
+			var hash string
+			require.NoError(t, json.Unmarshal(req.Params[0], &hash))
+			if tx, ok := transactions[hash]; ok {
+				result = tx
+			}
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0", "id": req.ID,
+				"error": map[string]interface{}{"code": -32601, "message": "method not found"},
+			})
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer node.Close()
+
+	backend := NewEthereumBackend(node.URL, from)
+	receipt, err := backend.Anchor(digestOf("status-list"))
+	require.NoError(t, err)
+	assert.Equal(t, "ethereum", receipt.Backend)
+
+	require.NoError(t, backend.Verify(digestOf("status-list"), receipt))
+	assert.Error(t, backend.Verify(digestOf("other"), receipt))
+	assert.Error(t, backend.Verify(digestOf("status-list"), &Receipt{Reference: "0xmissing"}))
+
+	// Pending transactions do not count as anchored
+	mu.Lock()
+	transactions[receipt.Reference]["blockNumber"] = nil
+	mu.Unlock()
+	assert.ErrorContains(t, backend.Verify(digestOf("status-list"), receipt), "not been mined")
+
+	// Transactions from other accounts are rejected
+	other := NewEthereumBackend(node.URL, "0x00000000000000000000000000000000000000bb")
+	mu.Lock()
+	transactions[receipt.Reference]["blockNumber"] = "0x1"
+	mu.Unlock()
+	assert.Error(t, other.Verify(digestOf("status-list"), receipt))
+}
+
+func TestTimestampBackend(t *testing.T) {
+	var mu sync.Mutex
+	records := make(map[string]timestampRecord)
+
+	tsa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			var req struct {
+				Digest string `json:"digest"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			record := timestampRecord{
+				ID:        "ts-" + strings.Repeat("1", len(records)+1),
+				Digest:    req.Digest,
+				Timestamp: time.Date(2025, 7, 27, 0, 0, len(records), 0, time.UTC),
+			}
+			records[record.ID] = record
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(record)
+			return
+		}
+
+		record, ok := records[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(record)
+	}))
+	defer tsa.Close()
+
+	backend := NewTimestampBackend(tsa.URL + "/")
+	receipt, err := backend.Anchor(digestOf("keys"))
+	require.NoError(t, err)
+	assert.Equal(t, "timestamp", receipt.Backend)
+	assert.Equal(t, time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC), receipt.AnchoredAt)
+
+	require.NoError(t, backend.Verify(digestOf("keys"), receipt))
+	assert.Error(t, backend.Verify(digestOf("other"), receipt))
+
+	backdated := *receipt
+	backdated.AnchoredAt = receipt.AnchoredAt.Add(-time.Hour)
+	assert.Error(t, backend.Verify(digestOf("keys"), &backdated))
+
+	assert.Error(t, backend.Verify(digestOf("keys"), &Receipt{Reference: "missing"}))
+}
@@ -0,0 +1,57 @@
+package anchor
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+)
+
+// KeySetDigest hashes the verification methods of a DID document, ordered by ID,
+// so the digest changes whenever a key is added, removed or rotated
+func KeySetDigest(doc *did.DIDDocument) ([]byte, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("DID document is nil")
+	}
+
+	methods := make([]did.VerificationMethod, len(doc.VerificationMethod))
+	copy(methods, doc.VerificationMethod)
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].ID < methods[j].ID
+	})
+
+	data, err := json.Marshal(struct {
+		ID                 string                   `json:"id"`
+		VerificationMethod []did.VerificationMethod `json:"verificationMethod"`
+	}{doc.ID, methods})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key set: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return digest[:], nil
+}
+
+// StatusListDigest hashes the identity, purpose and bitstring of a status list credential.
+// The issuance timestamp is excluded so only changes to the list itself produce a new digest.
+func StatusListDigest(list *status.ListCredential) ([]byte, error) {
+	if list == nil {
+		return nil, fmt.Errorf("status list is nil")
+	}
+
+	data, err := json.Marshal(struct {
+		ID          string         `json:"id"`
+		Issuer      string         `json:"issuer"`
+		Purpose     status.Purpose `json:"statusPurpose"`
+		EncodedList string         `json:"encodedList"`
+	}{list.ID, list.Issuer, list.CredentialSubject.StatusPurpose, list.CredentialSubject.EncodedList})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status list: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return digest[:], nil
+}
@@ -0,0 +1,134 @@
+package anchor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// EthereumBackend anchors digests as the data of zero-value self-transactions sent through a
+// JSON-RPC node. The node must hold the unlocked sending account, e.g. a dev node or a signer proxy.
+type EthereumBackend struct {
+	rpcURL string
+	from   string
+	client *http.Client
+	nextID atomic.Int64
+}
+
+// NewEthereumBackend creates a backend sending transactions from the given account through the node at rpcURL
+func NewEthereumBackend(rpcURL, from string) *EthereumBackend {
+	return &EthereumBackend{
+		rpcURL: rpcURL,
+		from:   from,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the backend name
+func (b *EthereumBackend) Name() string {
+	return "ethereum"
+}
+
+// Anchor submits a transaction carrying the digest; the reference is the transaction hash
+func (b *EthereumBackend) Anchor(digest []byte) (*Receipt, error) {
+	tx := map[string]string{
+		"from":  b.from,
+		"to":    b.from,
+		"value": "0x0",
+		"data":  "0x" + hex.EncodeToString(digest),
+	}
+
+	var txHash string
+	if err := b.call("eth_sendTransaction", []interface{}{tx}, &txHash); err != nil {
+		return nil, err
+	}
+
+	return &Receipt{
+		Backend:    b.Name(),
+		Reference:  txHash,
+		AnchoredAt: time.Now().UTC(),
+	}, nil
+}
+
+// Verify checks that the referenced transaction was mined, was sent by the anchoring account and carries the digest
+func (b *EthereumBackend) Verify(digest []byte, receipt *Receipt) error {
+	if receipt == nil {
+		return fmt.Errorf("receipt is nil")
+	}
+
+	var tx *struct {
+		From        string  `json:"from"`
+		Input       string  `json:"input"`
+		BlockNumber *string `json:"blockNumber"`
+	}
+	if err := b.call("eth_getTransactionByHash", []interface{}{receipt.Reference}, &tx); err != nil {
+		return err
+	}
+
+	if tx == nil {
+		return fmt.Errorf("transaction %s not found", receipt.Reference)
+	}
+
+	if tx.BlockNumber == nil {
+		return fmt.Errorf("transaction %s has not been mined", receipt.Reference)
+	}
+
+	if !strings.EqualFold(tx.From, b.from) {
+		return fmt.Errorf("transaction %s was not sent by %s", receipt.Reference, b.from)
+	}
+
+	if !strings.EqualFold(tx.Input, "0x"+hex.EncodeToString(digest)) {
+		return fmt.Errorf("transaction %s carries a different digest", receipt.Reference)
+	}
+
+	return nil
+}
+
+// call performs a JSON-RPC 2.0 request and decodes its result
+func (b *EthereumBackend) call(method string, params []interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      b.nextID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", method, err)
+	}
+
+	resp, err := b.client.Post(b.rpcURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request failed with status %d", method, resp.StatusCode)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("%s failed: %s (code %d)", method, response.Error.Message, response.Error.Code)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package anchor
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MockBackend is an in-memory append-only ledger for demos and tests
+type MockBackend struct {
+	mu     sync.RWMutex
+	ledger [][]byte
+}
+
+// NewMockBackend creates an empty in-memory ledger
+func NewMockBackend() *MockBackend {
+	return &MockBackend{}
+}
+
+// Name returns the backend name
+func (b *MockBackend) Name() string {
+	return "mock"
+}
+
+// Anchor appends the digest to the ledger; the reference is its ledger position
+func (b *MockBackend) Anchor(digest []byte) (*Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ledger = append(b.ledger, append([]byte(nil), digest...))
+
+	return &Receipt{
+		Backend:    b.Name(),
+		Reference:  strconv.Itoa(len(b.ledger) - 1),
+		AnchoredAt: time.Now().UTC(),
+	}, nil
+}
+
+// Verify checks that the ledger position holds the digest
+func (b *MockBackend) Verify(digest []byte, receipt *Receipt) error {
+	if receipt == nil {
+		return fmt.Errorf("receipt is nil")
+	}
+
+	position, err := strconv.Atoi(receipt.Reference)
+	if err != nil {
+		return fmt.Errorf("invalid ledger reference %q", receipt.Reference)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if position < 0 || position >= len(b.ledger) {
+		return fmt.Errorf("ledger entry %d not found", position)
+	}
+
+	if !bytes.Equal(b.ledger[position], digest) {
+		return fmt.Errorf("ledger entry %d holds a different digest", position)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package anchor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// Service anchors subject digests through a backend and keeps an index of the anchors it made
+type Service struct {
+	backend Backend
+
+	mu      sync.RWMutex
+	history map[string][]*Anchor // subject + kind -> anchors, oldest first
+}
+
+// NewService creates an anchoring service publishing through the given backend
+func NewService(backend Backend) *Service {
+	return &Service{
+		backend: backend,
+		history: make(map[string][]*Anchor),
+	}
+}
+
+// Backend returns the name of the backend anchors are published to
+func (s *Service) Backend() string {
+	return s.backend.Name()
+}
+
+// Anchor publishes the digest for a subject, unless it is unchanged since the latest anchor
+func (s *Service) Anchor(subject string, kind Kind, digest []byte) (*Anchor, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("anchor subject is required")
+	}
+
+	if len(digest) == 0 {
+		return nil, fmt.Errorf("anchor digest is required")
+	}
+
+	encoded := hex.EncodeToString(digest)
+	if latest, err := s.Latest(subject, kind); err == nil && latest.Digest == encoded {
+		return latest, nil
+	}
+
+	receipt, err := s.backend.Anchor(digest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to anchor %s of %s: %w", kind, subject, err)
+	}
+
+	anchor := &Anchor{
+		Subject: subject,
+		Kind:    kind,
+		Digest:  encoded,
+		Receipt: *receipt,
+	}
+
+	s.mu.Lock()
+	key := historyKey(subject, kind)
+	s.history[key] = append(s.history[key], anchor)
+	s.mu.Unlock()
+
+	return anchor, nil
+}
+
+// Latest returns the most recent anchor for a subject
+func (s *Service) Latest(subject string, kind Kind) (*Anchor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	anchors := s.history[historyKey(subject, kind)]
+	if len(anchors) == 0 {
+		return nil, fmt.Errorf("no %s anchor found for %s", kind, subject)
+	}
+	return anchors[len(anchors)-1], nil
+}
+
+// History returns all anchors made for a subject, oldest first
+func (s *Service) History(subject string, kind Kind) []*Anchor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	anchors := s.history[historyKey(subject, kind)]
+	result := make([]*Anchor, len(anchors))
+	copy(result, anchors)
+	return result
+}
+
+// Check verifies that the subject's latest anchor commits to the digest and that the backend confirms it
+func (s *Service) Check(subject string, kind Kind, digest []byte) error {
+	latest, err := s.Latest(subject, kind)
+	if err != nil {
+		return err
+	}
+
+	if latest.Digest != hex.EncodeToString(digest) {
+		return fmt.Errorf("%s of %s does not match its latest anchor", kind, subject)
+	}
+
+	if latest.Receipt.Backend != s.backend.Name() {
+		return fmt.Errorf("anchor was published to %s, not %s", latest.Receipt.Backend, s.backend.Name())
+	}
+
+	if err := s.backend.Verify(digest, &latest.Receipt); err != nil {
+		return fmt.Errorf("anchor receipt verification failed: %w", err)
+	}
+
+	return nil
+}
+
+func historyKey(subject string, kind Kind) string {
+	return string(kind) + "|" + subject
+}
@@ -0,0 +1,105 @@
+package anchor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TimestampBackend anchors digests with a simple HTTP timestamping service.
+// The service accepts POST {url} with {"digest": hex} and answers {"id", "timestamp"};
+// GET {url}/{id} returns the stored {"digest", "timestamp"}.
+type TimestampBackend struct {
+	serviceURL string
+	client     *http.Client
+}
+
+// timestampRecord is the timestamping service's view of an anchored digest
+type timestampRecord struct {
+	ID        string    `json:"id"`
+	Digest    string    `json:"digest"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewTimestampBackend creates a backend using the timestamping service at serviceURL
+func NewTimestampBackend(serviceURL string) *TimestampBackend {
+	return &TimestampBackend{
+		serviceURL: strings.TrimRight(serviceURL, "/"),
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the backend name
+func (b *TimestampBackend) Name() string {
+	return "timestamp"
+}
+
+// Anchor submits the digest; the reference is the service's record ID
+func (b *TimestampBackend) Anchor(digest []byte) (*Receipt, error) {
+	body, err := json.Marshal(map[string]string{"digest": hex.EncodeToString(digest)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal timestamp request: %w", err)
+	}
+
+	resp, err := b.client.Post(b.serviceURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("timestamp request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("timestamp request failed with status %d", resp.StatusCode)
+	}
+
+	var record timestampRecord
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		return nil, fmt.Errorf("failed to decode timestamp response: %w", err)
+	}
+
+	if record.ID == "" {
+		return nil, fmt.Errorf("timestamp response has no record ID")
+	}
+
+	return &Receipt{
+		Backend:    b.Name(),
+		Reference:  record.ID,
+		AnchoredAt: record.Timestamp,
+	}, nil
+}
+
+// Verify fetches the referenced record and checks its digest and timestamp
+func (b *TimestampBackend) Verify(digest []byte, receipt *Receipt) error {
+	if receipt == nil {
+		return fmt.Errorf("receipt is nil")
+	}
+
+	resp, err := b.client.Get(b.serviceURL + "/" + url.PathEscape(receipt.Reference))
+	if err != nil {
+		return fmt.Errorf("timestamp lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("timestamp record %s not found (status %d)", receipt.Reference, resp.StatusCode)
+	}
+
+	var record timestampRecord
+	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
+		return fmt.Errorf("failed to decode timestamp record: %w", err)
+	}
+
+	if !strings.EqualFold(record.Digest, hex.EncodeToString(digest)) {
+		return fmt.Errorf("timestamp record %s holds a different digest", receipt.Reference)
+	}
+
+	if !record.Timestamp.Equal(receipt.AnchoredAt) {
+		return fmt.Errorf("timestamp record %s has a different timestamp", receipt.Reference)
+	}
+
+	return nil
+}
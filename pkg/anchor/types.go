@@ -0,0 +1,38 @@
+package anchor
+
+import "time"
+
+// Kind identifies what state an anchor commits to
+type Kind string
+
+const (
+	// KindKeySet anchors the verification methods of an issuer's DID document
+	KindKeySet Kind = "key-set"
+	// KindStatusList anchors the contents of a status list credential
+	KindStatusList Kind = "status-list"
+)
+
+// Receipt is a backend's record that a digest was published
+type Receipt struct {
+	Backend    string    `json:"backend"`
+	Reference  string    `json:"reference"`
+	AnchoredAt time.Time `json:"anchoredAt"`
+}
+
+// Anchor binds a digest of a subject's state to the backend receipt that published it
+type Anchor struct {
+	Subject string  `json:"subject"`
+	Kind    Kind    `json:"kind"`
+	Digest  string  `json:"digest"`
+	Receipt Receipt `json:"receipt"`
+}
+
+// Backend publishes digests to an external tamper-evident system
+type Backend interface {
+	// Name identifies the backend in receipts
+	Name() string
+	// Anchor publishes a digest and returns a receipt that can later be checked
+	Anchor(digest []byte) (*Receipt, error)
+	// Verify checks that the receipt refers to a publication of the digest
+	Verify(digest []byte, receipt *Receipt) error
+}
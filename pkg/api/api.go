@@ -0,0 +1,54 @@
+// Package api describes how a deployment exposes its HTTP API: the roles it serves and the proof
+// checking work it allows. It holds no transport code, so stacks can be configured without
+// depending on the server.
+package api
+
+import "fmt"
+
+// Role is a part of the API a server can expose
+type Role string
+
+const (
+	// RoleIssuer exposes the issuer endpoints, status lists and issuer discovery documents
+	RoleIssuer Role = "issuer"
+	// RoleHolder exposes the wallet endpoints
+	RoleHolder Role = "holder"
+	// RoleVerifier exposes the verifier endpoints
+	RoleVerifier Role = "verifier"
+)
+
+// Validate rejects roles other than the issuer, holder and verifier
+func (r Role) Validate() error {
+	switch r {
+	case RoleIssuer, RoleHolder, RoleVerifier:
+		return nil
+	default:
+		return fmt.Errorf("unknown role %q (expected issuer, holder or verifier)", string(r))
+	}
+}
+
+// CostPolicy limits the proof checking work requests can cause, so a public deployment cannot be
+// exhausted with unauthenticated verification requests. A request costs one unit per proof it
+// asks the server to check. Zero disables a limit.
+type CostPolicy struct {
+	// MaxProofsPerRequest rejects requests carrying more proofs than this
+	MaxProofsPerRequest int
+	// ProofsPerMinute is each client's budget, refilled continuously. Clients are told apart by
+	// their network address.
+	ProofsPerMinute int
+	// TotalProofsPerMinute is the budget every client draws from together
+	TotalProofsPerMinute int
+}
+
+// Validate rejects negative limits
+func (p CostPolicy) Validate() error {
+	if p.MaxProofsPerRequest < 0 || p.ProofsPerMinute < 0 || p.TotalProofsPerMinute < 0 {
+		return fmt.Errorf("cost limits cannot be negative")
+	}
+	return nil
+}
+
+// Enabled reports whether the policy limits anything
+func (p CostPolicy) Enabled() bool {
+	return p.MaxProofsPerRequest > 0 || p.ProofsPerMinute > 0 || p.TotalProofsPerMinute > 0
+}
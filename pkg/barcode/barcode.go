@@ -0,0 +1,234 @@
+// Package barcode writes credentials and presentations as text for QR codes, so they can be shown
+// and scanned in person without a network. Documents are compressed in the manner of CBOR-LD:
+// property names and common values from a fixed dictionary become small integers, and DIDs, UUIDs,
+// timestamps and base64 or base58 strings are stored as bytes. The dictionary is this package's
+// own, so only this package can read the result; it is not a conforming CBOR-LD encoding.
+//
+// A payload is a kind and version, such as VP1, followed by the base32 of the compressed document.
+// Base32 uses only characters of the QR alphanumeric mode, which packs 5.5 bits per character.
+// A document too large for one QR code is split into chunks, each scanned from its own code.
+package barcode
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Kind is what a payload holds
+type Kind string
+
+const (
+	KindCredential   Kind = "VC"
+	KindPresentation Kind = "VP"
+)
+
+// version is the version of the payload format and of the compression dictionary
+const version = "1"
+
+// QRCapacity is the number of alphanumeric characters a version 40 QR code holds at error
+// correction level L, the most any single QR code holds
+const QRCapacity = 4296
+
+// MaxChunks bounds the number of QR codes one document may be split into
+const MaxChunks = 16
+
+// digestSize is the number of digest bytes in a chunk header that tie the chunks of a document together
+const digestSize = 4
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EncodeCredential writes a credential as QR payloads of at most maxChars characters each.
+// A maxChars of zero means QRCapacity.
+func EncodeCredential(credential *vc.VerifiableCredential, maxChars int) ([]string, error) {
+	return encodeDocument(KindCredential, credential, maxChars)
+}
+
+// EncodePresentation writes a presentation as QR payloads of at most maxChars characters each.
+// A maxChars of zero means QRCapacity.
+func EncodePresentation(presentation *vc.VerifiablePresentation, maxChars int) ([]string, error) {
+	return encodeDocument(KindPresentation, presentation, maxChars)
+}
+
+func encodeDocument(kind Kind, document interface{}, maxChars int) ([]string, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+	return Encode(kind, data, maxChars)
+}
+
+// Encode compresses a JSON document and writes it as QR payloads of at most maxChars characters.
+// The document is one payload when it fits, and otherwise is split into chunks headed
+// <kind><version>-<index>/<total>-<digest>-.
+func Encode(kind Kind, document []byte, maxChars int) ([]string, error) {
+	if maxChars == 0 {
+		maxChars = QRCapacity
+	}
+	if maxChars < 0 {
+		return nil, fmt.Errorf("maximum payload size cannot be negative")
+	}
+	compressed, err := compress(document)
+	if err != nil {
+		return nil, err
+	}
+	data := encoding.EncodeToString(compressed)
+
+	prefix := string(kind) + version + "-"
+	if len(prefix)+len(data) <= maxChars {
+		return []string{prefix + data}, nil
+	}
+
+	digest := sha256.Sum256(compressed)
+	tag := strings.ToUpper(hex.EncodeToString(digest[:digestSize]))
+	// Every chunk header is at most as long as the last one's
+	headerSize := len(fmt.Sprintf("%s%d/%d-%s-", prefix, MaxChunks, MaxChunks, tag))
+	room := maxChars - headerSize
+	if room <= 0 {
+		return nil, fmt.Errorf("maximum payload size %d leaves no room for chunk data", maxChars)
+	}
+	total := (len(data) + room - 1) / room
+	if total > MaxChunks {
+		return nil, fmt.Errorf("document needs %d QR codes of %d characters, more than the %d allowed", total, maxChars, MaxChunks)
+	}
+
+	chunks := make([]string, 0, total)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * room
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, fmt.Sprintf("%s%d/%d-%s-%s", prefix, i+1, total, tag, data[i*room:end]))
+	}
+	return chunks, nil
+}
+
+// Decode reads the JSON document from the payloads of its QR codes, which may be scanned in any
+// order. Chunks of other documents, missing chunks and conflicting duplicates are rejected.
+func Decode(payloads []string) (Kind, []byte, error) {
+	if len(payloads) == 0 {
+		return "", nil, fmt.Errorf("no barcode payloads")
+	}
+
+	var kind Kind
+	var total int
+	var tag string
+	parts := make(map[int]string)
+	for _, payload := range payloads {
+		payload = strings.TrimSpace(payload)
+		if len(payload) < 4 || payload[2:4] != version+"-" {
+			return "", nil, fmt.Errorf("unsupported barcode payload")
+		}
+		payloadKind := Kind(payload[:2])
+		if payloadKind != KindCredential && payloadKind != KindPresentation {
+			return "", nil, fmt.Errorf("unknown barcode kind %q", payloadKind)
+		}
+		if kind != "" && payloadKind != kind {
+			return "", nil, fmt.Errorf("barcode payloads hold different kinds of document")
+		}
+		kind = payloadKind
+		body := payload[4:]
+
+		fields := strings.SplitN(body, "-", 3)
+		if len(fields) != 3 {
+			if len(payloads) != 1 {
+				return "", nil, fmt.Errorf("a single-code payload cannot be combined with others")
+			}
+			return decodeData(kind, body, "")
+		}
+		index, count, err := parsePosition(fields[0])
+		if err != nil {
+			return "", nil, err
+		}
+		if total != 0 && (count != total || fields[1] != tag) {
+			return "", nil, fmt.Errorf("barcode chunks belong to different documents")
+		}
+		total, tag = count, fields[1]
+		if previous, ok := parts[index]; ok && previous != fields[2] {
+			return "", nil, fmt.Errorf("conflicting copies of barcode chunk %d", index)
+		}
+		parts[index] = fields[2]
+	}
+
+	var data strings.Builder
+	for i := 1; i <= total; i++ {
+		part, ok := parts[i]
+		if !ok {
+			return "", nil, fmt.Errorf("missing barcode chunk %d of %d", i, total)
+		}
+		data.WriteString(part)
+	}
+	return decodeData(kind, data.String(), tag)
+}
+
+// parsePosition parses the <index>/<total> of a chunk header
+func parsePosition(position string) (int, int, error) {
+	index, total, ok := strings.Cut(position, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid barcode chunk header")
+	}
+	i, err1 := strconv.Atoi(index)
+	n, err2 := strconv.Atoi(total)
+	if err1 != nil || err2 != nil || n < 2 || n > MaxChunks || i < 1 || i > n {
+		return 0, 0, fmt.Errorf("invalid barcode chunk position %q", position)
+	}
+	return i, n, nil
+}
+
+// decodeData decompresses a document, checking the digest of chunked documents
+func decodeData(kind Kind, data, tag string) (Kind, []byte, error) {
+	compressed, err := encoding.DecodeString(data)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid barcode data: %w", err)
+	}
+	if tag != "" {
+		digest := sha256.Sum256(compressed)
+		if !strings.EqualFold(tag, hex.EncodeToString(digest[:digestSize])) {
+			return "", nil, fmt.Errorf("barcode chunks do not match their digest")
+		}
+	}
+	document, err := decompress(compressed)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid barcode data: %w", err)
+	}
+	return kind, document, nil
+}
+
+// DecodePresentation reads a presentation from the payloads of its QR codes. It also returns the
+// presentation's JSON, for checks on the document as received.
+func DecodePresentation(payloads []string) (*vc.VerifiablePresentation, []byte, error) {
+	kind, document, err := Decode(payloads)
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind != KindPresentation {
+		return nil, nil, fmt.Errorf("barcode holds a %s, not a presentation", kind)
+	}
+	var presentation vc.VerifiablePresentation
+	if err := json.Unmarshal(document, &presentation); err != nil {
+		return nil, nil, fmt.Errorf("invalid presentation: %w", err)
+	}
+	return &presentation, document, nil
+}
+
+// DecodeCredential reads a credential from the payloads of its QR codes
+func DecodeCredential(payloads []string) (*vc.VerifiableCredential, error) {
+	kind, document, err := Decode(payloads)
+	if err != nil {
+		return nil, err
+	}
+	if kind != KindCredential {
+		return nil, fmt.Errorf("barcode holds a %s, not a credential", kind)
+	}
+	var credential vc.VerifiableCredential
+	if err := json.Unmarshal(document, &credential); err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+	return &credential, nil
+}
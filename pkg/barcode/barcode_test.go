@@ -0,0 +1,150 @@
+package barcode
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// isAlphanumeric reports whether s uses only characters of the QR alphanumeric mode
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBarcode(t *testing.T) {
+	bbsService := bbs.NewService()
+	service := vc.NewService(bbsService, vc.NewInMemoryCredentialRepository(), vc.NewInMemoryPresentationRepository())
+
+	keyPair, err := bbsService.GenerateKeyPair()
+	require.NoError(t, err)
+	issuerDID := "did:example:GbpdUeNxT7GKUDP8thKhYb1oYfyKSNiG4sCv97ZcmEJi"
+	holderDID := "did:example:DsgVu9bEab2M7vPZEdajn2WNegZeb16ovBYFBWDL2ekY"
+	require.NoError(t, service.SetIssuerKeyPair(issuerDID, keyPair))
+
+	credential, err := service.IssueCredential(issuerDID, holderDID, []vc.Claim{
+		{Key: "ageOver18", Value: true},
+		{Key: "firstName", Value: "An"},
+		{Key: "nationality", Value: "Vietnamese"},
+		{Key: "birthYear", Value: 1990},
+	})
+	require.NoError(t, err)
+
+	presentation, err := service.CreatePresentation(holderDID, []*vc.VerifiableCredential{credential}, []vc.SelectiveDisclosureRequest{
+		{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18", "nationality"}, Nonce: "verifier-nonce-be686331", IssuerPublicKey: keyPair.PublicKey},
+	})
+	require.NoError(t, err)
+	presentation.Proof = &vc.Proof{
+		Type:               "Ed25519Signature2020",
+		Created:            credential.IssuanceDate,
+		VerificationMethod: holderDID + "#key-1",
+		ProofPurpose:       "authentication",
+		ProofValue:         "z58aMEHjBijkZoUFpDCw4f4VmoLn2EEFutTNa9kwW4wenSvs8hRt2waYfdebXWWsg6oLwQt2ZB7x1d34ACChmZrmh",
+	}
+	original, err := json.Marshal(presentation)
+	require.NoError(t, err)
+
+	t.Run("Presentation Fits One QR Code", func(t *testing.T) {
+		payloads, err := EncodePresentation(presentation, 0)
+		require.NoError(t, err)
+		require.Len(t, payloads, 1)
+		assert.True(t, strings.HasPrefix(payloads[0], "VP1-"))
+		assert.True(t, isAlphanumeric(payloads[0]))
+		// Base32 costs 8 characters per 5 bytes, so the compressed document is well under half the JSON
+		assert.Less(t, len(payloads[0]), len(original))
+
+		decoded, raw, err := DecodePresentation(payloads)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(original), string(raw))
+
+		// The holder's proof covers the same bytes
+		want, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		got, err := vc.PresentationSigningInput(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+	})
+
+	t.Run("Credential Round Trip", func(t *testing.T) {
+		payloads, err := EncodeCredential(credential, 0)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(payloads[0], "VC1-"))
+
+		decoded, err := DecodeCredential(payloads)
+		require.NoError(t, err)
+		want, err := json.Marshal(credential)
+		require.NoError(t, err)
+		got, err := json.Marshal(decoded)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(want), string(got))
+
+		_, _, err = DecodePresentation(payloads)
+		assert.Error(t, err)
+	})
+
+	t.Run("Large Documents Are Chunked", func(t *testing.T) {
+		payloads, err := EncodePresentation(presentation, 300)
+		require.NoError(t, err)
+		require.Greater(t, len(payloads), 1)
+		for _, payload := range payloads {
+			assert.LessOrEqual(t, len(payload), 300)
+			assert.True(t, isAlphanumeric(payload))
+		}
+
+		// Chunks may be scanned in any order, and the same chunk twice
+		shuffled := append([]string{payloads[len(payloads)-1]}, payloads...)
+		_, raw, err := DecodePresentation(shuffled)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(original), string(raw))
+
+		_, _, err = DecodePresentation(payloads[1:])
+		assert.ErrorContains(t, err, "missing barcode chunk 1")
+
+		tampered := append([]string(nil), payloads...)
+		last := tampered[len(tampered)-1]
+		flipped := "A"
+		if strings.HasSuffix(last, "A") {
+			flipped = "B"
+		}
+		tampered[len(tampered)-1] = last[:len(last)-1] + flipped
+		_, _, err = DecodePresentation(tampered)
+		assert.Error(t, err)
+
+		other, err := EncodeCredential(credential, 300)
+		require.NoError(t, err)
+		_, _, err = Decode(append(payloads[:1:1], other[1:]...))
+		assert.Error(t, err)
+	})
+
+	t.Run("Size Limits", func(t *testing.T) {
+		_, err := EncodePresentation(presentation, 20)
+		assert.Error(t, err)
+
+		_, err = EncodePresentation(presentation, 60)
+		assert.ErrorContains(t, err, "more than the 16 allowed")
+	})
+
+	t.Run("Invalid Payloads", func(t *testing.T) {
+		for _, payloads := range [][]string{
+			nil,
+			{"XX1-ABC"},
+			{"VP2-ABC"},
+			{"VP1-not base32"},
+			{"VP1-AAAA"},
+			{"VP1-3/2-0000-AAAA"},
+		} {
+			_, _, err := Decode(payloads)
+			assert.Error(t, err, payloads)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package barcode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CBOR major types (RFC 8949 section 3.1)
+const (
+	majorUnsigned = 0
+	majorNegative = 1
+	majorBytes    = 2
+	majorText     = 3
+	majorArray    = 4
+	majorMap      = 5
+	majorTag      = 6
+	majorSimple   = 7
+)
+
+// Simple values and the float64 marker of major type 7
+const (
+	simpleFalse   = 20
+	simpleTrue    = 21
+	simpleNull    = 22
+	simpleFloat64 = 27
+)
+
+// maxDepth bounds the nesting of decoded items, so a hostile barcode cannot exhaust the stack
+const maxDepth = 64
+
+// errTruncated is returned when an item runs past the end of the input
+var errTruncated = errors.New("cbor: unexpected end of data")
+
+// tagged is a tag number and its content
+type tagged struct {
+	Number  uint64
+	Content interface{}
+}
+
+// cborKey is a map key that is an unsigned integer rather than text
+type cborKey uint64
+
+// encodeCBOR encodes the JSON data model, plus byte strings, tags and integer map keys, in the
+// deterministic encoding of RFC 8949 section 4.2: shortest heads and sorted map keys
+func encodeCBOR(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeItem(&buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeHead writes a major type and its argument in the shortest form
+func writeHead(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+	default:
+		buf.WriteByte(major<<5 | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, arg))
+	}
+}
+
+func writeItem(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(majorSimple<<5 | simpleNull)
+	case bool:
+		if v {
+			buf.WriteByte(majorSimple<<5 | simpleTrue)
+		} else {
+			buf.WriteByte(majorSimple<<5 | simpleFalse)
+		}
+	case int64:
+		if v >= 0 {
+			writeHead(buf, majorUnsigned, uint64(v))
+		} else {
+			writeHead(buf, majorNegative, uint64(-(v + 1)))
+		}
+	case uint64:
+		writeHead(buf, majorUnsigned, v)
+	case cborKey:
+		writeHead(buf, majorUnsigned, uint64(v))
+	case float64:
+		buf.WriteByte(majorSimple<<5 | simpleFloat64)
+		buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(v)))
+	case string:
+		writeHead(buf, majorText, uint64(len(v)))
+		buf.WriteString(v)
+	case []byte:
+		writeHead(buf, majorBytes, uint64(len(v)))
+		buf.Write(v)
+	case []interface{}:
+		writeHead(buf, majorArray, uint64(len(v)))
+		for _, item := range v {
+			if err := writeItem(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		return writeMap(buf, v)
+	case tagged:
+		writeHead(buf, majorTag, v.Number)
+		return writeItem(buf, v.Content)
+	default:
+		return fmt.Errorf("cbor: cannot encode %T", value)
+	}
+	return nil
+}
+
+// writeMap writes the entries sorted by the bytewise order of their encoded keys
+func writeMap(buf *bytes.Buffer, m map[interface{}]interface{}) error {
+	type entry struct {
+		key   []byte
+		value interface{}
+	}
+	entries := make([]entry, 0, len(m))
+	for key, value := range m {
+		encoded, err := encodeCBOR(key)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: encoded, value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+
+	writeHead(buf, majorMap, uint64(len(entries)))
+	for _, e := range entries {
+		buf.Write(e.key)
+		if err := writeItem(buf, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeCBOR decodes one item that must span all of data. Integers decode as int64, integer map
+// keys as cborKey, and tags as tagged.
+func decodeCBOR(data []byte) (interface{}, error) {
+	d := &decoder{data: data}
+	value, err := d.item(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("cbor: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return value, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+// head reads an item's major type and argument; for a float64 the argument is its bits.
+// Indefinite lengths are not accepted.
+func (d *decoder) head() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errTruncated
+	}
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, 0, fmt.Errorf("cbor: unsupported additional information %d", info)
+	}
+	if len(d.data)-d.pos < size {
+		return 0, 0, errTruncated
+	}
+	var arg uint64
+	for _, b := range d.data[d.pos : d.pos+size] {
+		arg = arg<<8 | uint64(b)
+	}
+	d.pos += size
+	return major, arg, nil
+}
+
+// count checks that a length leaves at least one byte per element in the input, so lengths
+// cannot force allocations larger than the barcode
+func (d *decoder) count(n uint64) (int, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return 0, errTruncated
+	}
+	return int(n), nil
+}
+
+func (d *decoder) item(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.New("cbor: nesting too deep")
+	}
+	start := d.pos
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUnsigned:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflows int64")
+		}
+		return int64(arg), nil
+	case majorNegative:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflows int64")
+		}
+		return -1 - int64(arg), nil
+	case majorBytes, majorText:
+		n, err := d.count(arg)
+		if err != nil {
+			return nil, err
+		}
+		raw := d.data[d.pos : d.pos+n]
+		d.pos += n
+		if major == majorText {
+			return string(raw), nil
+		}
+		return append([]byte(nil), raw...), nil
+	case majorArray:
+		n, err := d.count(arg)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case majorMap:
+		n, err := d.count(arg)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[interface{}]interface{}, n)
+		for i := 0; i < n; i++ {
+			key, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch k := key.(type) {
+			case string:
+			case int64:
+				if k < 0 {
+					return nil, errors.New("cbor: negative map key")
+				}
+				key = cborKey(k)
+			default:
+				return nil, fmt.Errorf("cbor: unsupported map key %T", key)
+			}
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("cbor: duplicate map key %v", key)
+			}
+			value, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case majorTag:
+		content, err := d.item(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return tagged{Number: arg, Content: content}, nil
+	default:
+		switch d.data[start] & 0x1f {
+		case simpleFalse:
+			return false, nil
+		case simpleTrue:
+			return true, nil
+		case simpleNull:
+			return nil, nil
+		case simpleFloat64:
+			return math.Float64frombits(arg), nil
+		}
+		return nil, fmt.Errorf("cbor: unsupported simple value %d", d.data[start]&0x1f)
+	}
+}
@@ -0,0 +1,72 @@
+package barcode
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBOR(t *testing.T) {
+	t.Run("Known Encodings", func(t *testing.T) {
+		// Examples from RFC 8949 appendix A
+		for hexData, value := range map[string]interface{}{
+			"00":                 int64(0),
+			"1864":               int64(100),
+			"3903e7":             int64(-1000),
+			"1a000f4240":         int64(1000000),
+			"fb3ff199999999999a": 1.1,
+			"f4":                 false,
+			"f5":                 true,
+			"f6":                 nil,
+			"6449455446":         "IETF",
+			"4401020304":         []byte{1, 2, 3, 4},
+			"83010203":           []interface{}{int64(1), int64(2), int64(3)},
+		} {
+			encoded, err := encodeCBOR(value)
+			require.NoError(t, err)
+			assert.Equal(t, hexData, hex.EncodeToString(encoded))
+
+			decoded, err := decodeCBOR(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, value, decoded)
+		}
+	})
+
+	t.Run("Maps Are Sorted", func(t *testing.T) {
+		encoded, err := encodeCBOR(map[interface{}]interface{}{"b": int64(2), cborKey(1): "x", "a": int64(1)})
+		require.NoError(t, err)
+		assert.Equal(t, "a3016178616101616202", hex.EncodeToString(encoded))
+
+		decoded, err := decodeCBOR(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, map[interface{}]interface{}{"a": int64(1), "b": int64(2), cborKey(1): "x"}, decoded)
+	})
+
+	t.Run("Malformed Input Is Rejected", func(t *testing.T) {
+		for name, hexData := range map[string]string{
+			"Truncated":          "1a000f",
+			"Trailing Bytes":     "0000",
+			"Huge Length":        "9bffffffffffffffff",
+			"Indefinite Length":  "9f01ff",
+			"Duplicate Key":      "a2616101616102",
+			"Unsupported Key":    "a1f501",
+			"Unsupported Simple": "f7",
+		} {
+			t.Run(name, func(t *testing.T) {
+				data, err := hex.DecodeString(hexData)
+				require.NoError(t, err)
+				_, err = decodeCBOR(data)
+				assert.Error(t, err)
+			})
+		}
+
+		deep := make([]byte, maxDepth+2)
+		for i := range deep {
+			deep[i] = 0x81
+		}
+		_, err := decodeCBOR(append(deep, 0x00))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,328 @@
+package barcode
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/google/uuid"
+)
+
+// Tags of compacted strings. Tags 22 (base64) and 37 (binary UUID) are registered with IANA; the
+// others are private to this package.
+const (
+	tagBase64   = 22
+	tagUUID     = 37
+	tagValue    = 0xB000 // a string from the value dictionary
+	tagTime     = 0xB001 // an RFC 3339 UTC time: Unix seconds, or [seconds, nanoseconds]
+	tagFragment = 0xB002 // [base, fragment] of a string containing '#'
+	tagPrefix   = 0xB003 // [prefix code, rest] of a string with a dictionary prefix
+	tagBase58   = 0xB004 // bytes written in base58btc
+)
+
+// terms are the property names encoded as integers, as CBOR-LD does with the terms of a context.
+// Codes are positions in the list, so terms may only be appended.
+var terms = []string{
+	"@context", "id", "type", "holder", "verifiableCredential", "issuer", "issuanceDate",
+	"expirationDate", "validFrom", "validUntil", "credentialSubject", "credentialStatus", "proof",
+	"created", "nonce", "proofPurpose", "proofValue", "verificationMethod", "revealedAttributes",
+	"attributeSalts", "statusListCredential", "statusListIndex", "statusPurpose", "domain",
+	"purpose", "retentionDays", "verifier", "statusSnapshots", "commitments", "evidence",
+	"claimKeys", "absenceProofs", "issuerSetProof", "expires", "name", "did",
+}
+
+// values are the common string values encoded as integers, and prefixes the common starts of
+// strings. Like terms they may only be appended to.
+var (
+	values = []string{
+		"https://www.w3.org/2018/credentials/v1",
+		"https://www.w3.org/ns/credentials/v2",
+		"https://w3id.org/security/bbs/v1",
+		"https://lugondev.github.io/bbs-selective-disclosure-example/contexts/v1.jsonld",
+		"VerifiablePresentation", "VerifiableCredential",
+		"BbsBlsSignature2020", "BbsBlsSignatureProof2020", "Ed25519Signature2020", "BbsIssuerSetProof",
+		"assertionMethod", "authentication",
+		"BitstringStatusListEntry", "revocation", "suspension",
+	}
+	prefixes = []string{"did:example:", "did:key:", "did:peer:", "did:web:", "urn:uuid:", "z", "bbs-key-", "key-"}
+)
+
+var (
+	termCodes  = codes(terms)
+	valueCodes = codes(values)
+)
+
+func codes(list []string) map[string]int64 {
+	m := make(map[string]int64, len(list))
+	for i, s := range list {
+		m[s] = int64(i)
+	}
+	return m
+}
+
+// compress turns a JSON document into its compact CBOR form
+func compress(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(document)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+	compacted, err := (&compactor{cache: map[string]interface{}{}}).value(value)
+	if err != nil {
+		return nil, err
+	}
+	return encodeCBOR(compacted)
+}
+
+// decompress turns compact CBOR back into the JSON document
+func decompress(data []byte) ([]byte, error) {
+	value, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := expandValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(expanded)
+}
+
+// maxSplitLength bounds the strings split at a '#' or a prefix, which keeps the search for the
+// smallest encoding linear in practice
+const maxSplitLength = 256
+
+// compactor compacts the values of one document, remembering the encoding chosen for each string
+type compactor struct {
+	cache map[string]interface{}
+}
+
+func (c *compactor) value(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			compacted, err := c.value(item)
+			if err != nil {
+				return nil, err
+			}
+			if code, ok := termCodes[key]; ok {
+				m[cborKey(code)] = compacted
+			} else {
+				m[key] = compacted
+			}
+		}
+		return m, nil
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			compacted, err := c.value(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = compacted
+		}
+		return items, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case string:
+		return c.string(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// string returns the smallest encoding of s that expands back to exactly s
+func (c *compactor) string(s string) interface{} {
+	if compacted, ok := c.cache[s]; ok {
+		return compacted
+	}
+	var best interface{} = s
+	bestSize := encodedSize(s)
+	consider := func(candidate interface{}) {
+		if size := encodedSize(candidate); size < bestSize {
+			best, bestSize = candidate, size
+		}
+	}
+
+	if code, ok := valueCodes[s]; ok {
+		consider(tagged{tagValue, code})
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil && t.Location() == time.UTC && t.Format(time.RFC3339Nano) == s {
+		if t.Nanosecond() == 0 {
+			consider(tagged{tagTime, t.Unix()})
+		} else {
+			consider(tagged{tagTime, []interface{}{t.Unix(), int64(t.Nanosecond())}})
+		}
+	}
+	if len(s) <= maxSplitLength {
+		if i := strings.LastIndex(s, "#"); i > 0 {
+			consider(tagged{tagFragment, []interface{}{c.string(s[:i]), c.string(s[i+1:])}})
+		}
+		for code, prefix := range prefixes {
+			if rest := strings.TrimPrefix(s, prefix); rest != s && rest != "" {
+				consider(tagged{tagPrefix, []interface{}{int64(code), c.string(rest)}})
+			}
+		}
+	}
+	if id, err := uuid.Parse(s); err == nil && id.String() == s {
+		consider(tagged{tagUUID, id[:]})
+	}
+	if raw, err := base64.StdEncoding.DecodeString(s); err == nil && base64.StdEncoding.EncodeToString(raw) == s {
+		consider(tagged{tagBase64, raw})
+	}
+	if raw := base58.Decode(s); len(raw) > 0 && base58.Encode(raw) == s {
+		consider(tagged{tagBase58, raw})
+	}
+	c.cache[s] = best
+	return best
+}
+
+func encodedSize(value interface{}) int {
+	encoded, err := encodeCBOR(value)
+	if err != nil {
+		return int(^uint(0) >> 1)
+	}
+	return len(encoded)
+}
+
+func expandValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			var name string
+			switch k := key.(type) {
+			case cborKey:
+				if uint64(k) >= uint64(len(terms)) {
+					return nil, fmt.Errorf("unknown term code %d", k)
+				}
+				name = terms[k]
+			case string:
+				name = k
+			}
+			if _, ok := m[name]; ok {
+				return nil, fmt.Errorf("duplicate property %s", name)
+			}
+			expanded, err := expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = expanded
+		}
+		return m, nil
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded, err := expandValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = expanded
+		}
+		return items, nil
+	case tagged:
+		return expandString(v)
+	case []byte:
+		return nil, errors.New("unexpected byte string")
+	default:
+		return v, nil
+	}
+}
+
+// expandString reverses compactor.string
+func expandString(t tagged) (string, error) {
+	switch t.Number {
+	case tagValue:
+		code, ok := t.Content.(int64)
+		if !ok || code < 0 || code >= int64(len(values)) {
+			return "", fmt.Errorf("unknown value code %v", t.Content)
+		}
+		return values[code], nil
+	case tagTime:
+		var seconds, nanos int64
+		switch content := t.Content.(type) {
+		case int64:
+			seconds = content
+		case []interface{}:
+			s, ok1 := pairItem(content, 0).(int64)
+			n, ok2 := pairItem(content, 1).(int64)
+			if !ok1 || !ok2 || n <= 0 || n >= int64(time.Second) {
+				return "", errors.New("invalid time")
+			}
+			seconds, nanos = s, n
+		default:
+			return "", errors.New("invalid time")
+		}
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano), nil
+	case tagFragment:
+		base, err := expandPart(pairItem(t.Content, 0))
+		if err != nil {
+			return "", err
+		}
+		fragment, err := expandPart(pairItem(t.Content, 1))
+		if err != nil {
+			return "", err
+		}
+		return base + "#" + fragment, nil
+	case tagPrefix:
+		code, ok := pairItem(t.Content, 0).(int64)
+		if !ok || code < 0 || code >= int64(len(prefixes)) {
+			return "", fmt.Errorf("unknown prefix code %v", pairItem(t.Content, 0))
+		}
+		rest, err := expandPart(pairItem(t.Content, 1))
+		if err != nil {
+			return "", err
+		}
+		return prefixes[code] + rest, nil
+	case tagUUID:
+		raw, ok := t.Content.([]byte)
+		if !ok {
+			return "", errors.New("invalid UUID")
+		}
+		id, err := uuid.FromBytes(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid UUID: %w", err)
+		}
+		return id.String(), nil
+	case tagBase64, tagBase58:
+		raw, ok := t.Content.([]byte)
+		if !ok {
+			return "", fmt.Errorf("tag %d must hold bytes", t.Number)
+		}
+		if t.Number == tagBase64 {
+			return base64.StdEncoding.EncodeToString(raw), nil
+		}
+		return base58.Encode(raw), nil
+	default:
+		return "", fmt.Errorf("unknown tag %d", t.Number)
+	}
+}
+
+// pairItem returns item i of a two-item array, or nil
+func pairItem(content interface{}, i int) interface{} {
+	items, ok := content.([]interface{})
+	if !ok || len(items) != 2 {
+		return nil
+	}
+	return items[i]
+}
+
+// expandPart expands a compacted string nested in a tag
+func expandPart(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case tagged:
+		return expandString(v)
+	default:
+		return "", errors.New("expected a string")
+	}
+}
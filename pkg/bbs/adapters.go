@@ -1,9 +1,9 @@
 package bbs
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
 	"fmt"
-
-	bls12381 "github.com/kilic/bls12-381"
 )
 
 // SimpleService adapter wraps the simple BBS implementation
@@ -22,51 +22,33 @@ func newSimpleService(config *Config) BBSInterface {
 	}
 }
 
-// GenerateKeyPair generates a simple key pair
+// GenerateKeyPair generates a random mock key pair. The public key is derived from the private
+// key, so pairs can be checked and signatures are bound to the key that made them.
 func (s *SimpleService) GenerateKeyPair() (*KeyPair, error) {
-	// This is a simplified implementation for demo purposes
-	// In production, this should use secure random generation
 	privateKey := make([]byte, 32)
-	publicKey := make([]byte, 32)
-
-	// Simple demo key generation (NOT secure)
-	for i := range privateKey {
-		privateKey[i] = byte(i + 1)
-	}
-	for i := range publicKey {
-		publicKey[i] = byte((i + 1) * 2)
+	if _, err := rand.Read(privateKey); err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
 	}
 
 	return &KeyPair{
-		PublicKey:  publicKey,
+		PublicKey:  simplePublicKey(privateKey),
 		PrivateKey: privateKey,
 	}, nil
 }
 
-// Sign creates a simple signature
+// Sign creates a deterministic HMAC signature over the messages, sized like a real one so it
+// encodes. It is NOT secure: anyone holding the public key can compute it.
 func (s *SimpleService) Sign(privateKey []byte, messages [][]byte) (*Signature, error) {
 	if len(privateKey) == 0 {
 		return nil, fmt.Errorf("private key cannot be empty")
 	}
 
-	// Simple signature for demo (NOT secure)
-	signature := &Signature{
-		A: make([]byte, 32),
-		E: make([]byte, 32),
-		S: make([]byte, 32),
-	}
-
-	// Fill with demo data
-	for i := range signature.A {
-		signature.A[i] = byte(i)
-		signature.E[i] = byte(i + 1)
-		signature.S[i] = byte(i + 2)
-	}
-
-	return signature, nil
+	publicKey := simplePublicKey(privateKey)
+	return simpleSignature(publicKey, simpleMessageTags(publicKey, messages)), nil
 }
 
-// Verify verifies a simple signature
+// Verify recomputes the signature from the public key and messages, so tampered messages, a
+// different key or an altered signature all fail
 func (s *SimpleService) Verify(publicKey []byte, signature *Signature, messages [][]byte) error {
 	if len(publicKey) == 0 {
 		return fmt.Errorf("public key cannot be empty")
@@ -76,51 +58,83 @@ func (s *SimpleService) Verify(publicKey []byte, signature *Signature, messages
 		return fmt.Errorf("signature cannot be nil")
 	}
 
-	// Simple verification (always passes for demo)
+	expected := simpleSignature(publicKey, simpleMessageTags(publicKey, messages))
+	if !hmac.Equal(signature.A, expected.A) || !hmac.Equal(signature.E, expected.E) || !hmac.Equal(signature.S, expected.S) {
+		return fmt.Errorf("signature verification failed")
+	}
 	return nil
 }
 
-// CreateProof creates a simple proof
+// CreateProof creates a mock proof that carries the tags of the hidden messages in place of
+// their values, bound to the nonce and the revealed indices
 func (s *SimpleService) CreateProof(signature *Signature, publicKey []byte, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, error) {
 	if signature == nil {
 		return nil, fmt.Errorf("signature cannot be nil")
 	}
-
-	// Simple proof for demo
-	proof := &Proof{
-		A_prime:            make([]byte, 32),
-		A_bar:              make([]byte, 32),
-		C:                  make([]byte, 32),
-		R2:                 make([]byte, 32),
-		R3:                 make([]byte, 32),
-		HiddenResponses:    [][]byte{},
-		RevealedAttributes: revealedIndices,
-		Nonce:              nonce,
+	if err := s.Verify(publicKey, signature, messages); err != nil {
+		return nil, fmt.Errorf("cannot create proof: %w", err)
+	}
+	if err := validateMessageIndices(revealedIndices, len(messages)); err != nil {
+		return nil, err
 	}
 
-	// Fill with demo data
-	for i := range proof.A_prime {
-		proof.A_prime[i] = byte(i)
-		proof.A_bar[i] = byte(i + 1)
-		proof.C[i] = byte(i + 2)
-		proof.R2[i] = byte(i + 3)
-		proof.R3[i] = byte(i + 4)
+	tags := simpleMessageTags(publicKey, messages)
+	revealed := make(map[int]bool, len(revealedIndices))
+	for _, index := range revealedIndices {
+		revealed[index] = true
+	}
+	hidden := make([][]byte, 0, len(messages)-len(revealedIndices))
+	for i, tag := range tags {
+		if !revealed[i] {
+			hidden = append(hidden, tag)
+		}
 	}
 
+	proof := simpleProof(publicKey, tags, revealedIndices, nonce)
+	proof.HiddenResponses = hidden
 	return proof, nil
 }
 
-// VerifyProof verifies a simple proof
+// VerifyProof rebuilds the signature from the revealed messages and the proof's hidden tags and
+// checks the proof was made for it, the revealed indices and the nonce
 func (s *SimpleService) VerifyProof(publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte) error {
 	if proof == nil {
 		return fmt.Errorf("proof cannot be nil")
 	}
+	if len(publicKey) == 0 {
+		return fmt.Errorf("public key cannot be empty")
+	}
+	if len(revealedMessages) != len(proof.RevealedAttributes) {
+		return fmt.Errorf("got %d revealed messages for %d revealed indices", len(revealedMessages), len(proof.RevealedAttributes))
+	}
+
+	total := len(proof.RevealedAttributes) + len(proof.HiddenResponses)
+	if err := validateMessageIndices(proof.RevealedAttributes, total); err != nil {
+		return err
+	}
 
-	// Simple verification (always passes for demo)
+	revealed := make(map[int][]byte, len(revealedMessages))
+	for i, index := range proof.RevealedAttributes {
+		revealed[index] = revealedMessages[i]
+	}
+	tags := make([][]byte, total)
+	hidden := proof.HiddenResponses
+	for i := range tags {
+		if message, ok := revealed[i]; ok {
+			tags[i] = simpleMessageTag(publicKey, i, message)
+			continue
+		}
+		tags[i], hidden = hidden[0], hidden[1:]
+	}
+
+	expected := simpleProof(publicKey, tags, proof.RevealedAttributes, nonce)
+	if !hmac.Equal(proof.C, expected.C) || !hmac.Equal(proof.A_prime, expected.A_prime) || !hmac.Equal(proof.A_bar, expected.A_bar) {
+		return fmt.Errorf("proof verification failed")
+	}
 	return nil
 }
 
-// ValidateKeyPair validates a key pair
+// ValidateKeyPair checks the public key was derived from the private key
 func (s *SimpleService) ValidateKeyPair(keyPair *KeyPair) error {
 	if keyPair == nil {
 		return fmt.Errorf("key pair cannot be nil")
@@ -134,6 +148,10 @@ func (s *SimpleService) ValidateKeyPair(keyPair *KeyPair) error {
 		return fmt.Errorf("public key cannot be empty")
 	}
 
+	if !hmac.Equal(keyPair.PublicKey, simplePublicKey(keyPair.PrivateKey)) {
+		return fmt.Errorf("public key does not match private key")
+	}
+
 	return nil
 }
 
@@ -181,12 +199,7 @@ type ProductionServiceAdapter struct {
 // newProductionService creates a new production BBS service adapter
 func newProductionService(config *Config) BBSInterface {
 	return &ProductionServiceAdapter{
-		service: &ProductionService{
-			g1:     bls12381.NewG1(),
-			g2:     bls12381.NewG2(),
-			gt:     bls12381.NewGT(),
-			engine: bls12381.NewEngine(),
-		},
+		service: &ProductionService{},
 		config:  config,
 		version: "1.0.0-production",
 	}
@@ -217,6 +230,31 @@ func (a *ProductionServiceAdapter) VerifyProof(publicKey []byte, proof *Proof, r
 	return a.service.VerifyProof(publicKey, proof, revealedMessages, nonce)
 }
 
+// DiagnoseProof verifies a proof and reports each check it ran
+func (a *ProductionServiceAdapter) DiagnoseProof(publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte) *ProofDiagnostics {
+	return a.service.DiagnoseProof(publicKey, proof, revealedMessages, nonce)
+}
+
+// PrecomputeProof blinds a signature ahead of a presentation
+func (a *ProductionServiceAdapter) PrecomputeProof(signature *Signature, publicKey []byte, messages [][]byte) (*ProofTemplate, error) {
+	return a.service.PrecomputeProof(signature, publicKey, messages)
+}
+
+// CompleteProof finishes a precomputed proof for the revealed messages and nonce
+func (a *ProductionServiceAdapter) CompleteProof(template *ProofTemplate, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, error) {
+	return a.service.CompleteProof(template, messages, revealedIndices, nonce)
+}
+
+// CreateSetProof creates a production proof under one of a set of public keys
+func (a *ProductionServiceAdapter) CreateSetProof(signature *Signature, publicKey []byte, publicKeys [][]byte, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, *SetMembershipProof, error) {
+	return a.service.CreateSetProof(signature, publicKey, publicKeys, messages, revealedIndices, nonce)
+}
+
+// VerifySetProof verifies a production proof under one of a set of public keys
+func (a *ProductionServiceAdapter) VerifySetProof(publicKeys [][]byte, proof *Proof, membership *SetMembershipProof, revealedMessages [][]byte, nonce []byte) error {
+	return a.service.VerifySetProof(publicKeys, proof, membership, revealedMessages, nonce)
+}
+
 // ValidateKeyPair validates a key pair
 func (a *ProductionServiceAdapter) ValidateKeyPair(keyPair *KeyPair) error {
 	return a.service.ValidateKeyPair(keyPair)
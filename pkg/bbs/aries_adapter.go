@@ -1,14 +1,19 @@
 package bbs
 
 import (
+	"context"
 	"fmt"
 	"log"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/secrets"
 )
 
 // AriesService implements BBS+ using Hyperledger Aries Framework Go
 type AriesService struct {
 	config   *Config
 	version  string
+	// authToken is the resolved remote KMS token; config keeps only its reference
+	authToken string
 	// delegate provides the actual cryptographic operations.
 	// The real Aries integration requires more complex setup with proper
 	// key management, storage providers, and context handling.
@@ -31,6 +36,18 @@ func newAriesService(config *Config) (BBSInterface, error) {
 		version: "1.0.0-aries-delegate",
 	}
 
+	if config.AriesConfig.KMSType == "remote" {
+		resolver := config.Secrets
+		if resolver == nil {
+			resolver = secrets.NewResolver()
+		}
+		token, err := resolver.Resolve(context.Background(), config.AriesConfig.AuthToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve remote KMS auth token: %w", err)
+		}
+		service.authToken = token
+	}
+
 	// Initialize Aries framework components
 	if err := service.initializeAries(); err != nil {
 		return nil, fmt.Errorf("failed to initialize Aries framework: %w", err)
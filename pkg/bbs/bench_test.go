@@ -0,0 +1,99 @@
+package bbs
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+// benchmarkMessageCount is the credential size the proof path allocation budget is set for
+const benchmarkMessageCount = 20
+
+// benchmarkFixture signs benchmarkMessageCount messages and picks every other one to reveal
+func benchmarkFixture(b *testing.B) (*ProductionService, *KeyPair, *Signature, [][]byte, []int) {
+	b.Helper()
+	// Timing logs would dominate the measurement
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	service := NewService().(*ProductionService)
+	keyPair, err := service.GenerateKeyPair()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	messages := make([][]byte, benchmarkMessageCount)
+	for i := range messages {
+		messages[i] = []byte(fmt.Sprintf("attribute-%d:value-%d", i, i*7919))
+	}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var revealed []int
+	for i := 0; i < len(messages); i += 2 {
+		revealed = append(revealed, i)
+	}
+	return service, keyPair, signature, messages, revealed
+}
+
+func BenchmarkSign(b *testing.B) {
+	service, keyPair, _, messages, _ := benchmarkFixture(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.Sign(keyPair.PrivateKey, messages); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	service, keyPair, signature, messages, _ := benchmarkFixture(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := service.Verify(keyPair.PublicKey, signature, messages); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCreateProof(b *testing.B) {
+	service, keyPair, signature, messages, revealed := benchmarkFixture(b)
+	nonce := []byte("benchmark-nonce-with-enough-entropy-0123456789")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealed, nonce); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyProof(b *testing.B) {
+	service, keyPair, signature, messages, revealed := benchmarkFixture(b)
+	nonce := []byte("benchmark-nonce-with-enough-entropy-0123456789")
+	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealed, nonce)
+	if err != nil {
+		b.Fatal(err)
+	}
+	revealedMessages := make([][]byte, len(revealed))
+	for i, index := range revealed {
+		revealedMessages[i] = messages[index]
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := service.VerifyProof(keyPair.PublicKey, proof, revealedMessages, nonce); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
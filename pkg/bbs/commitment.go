@@ -0,0 +1,107 @@
+package bbs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// groupOrder is the order of the BLS12-381 scalar field
+var groupOrder, _ = new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
+
+// commitmentDST separates the commitment generator H from the message generators
+var commitmentDST = []byte("BBS_BLS12381G1_COMMITMENT_H_")
+
+// CommitmentGenerators are the public G1 generators of attribute commitments C = G^m * H^r.
+// Nobody knows the discrete log of H with respect to G, so a commitment cannot be opened
+// to two different values.
+type CommitmentGenerators struct {
+	G []byte `json:"g"`
+	H []byte `json:"h"`
+}
+
+// DefaultCommitmentGenerators returns the generators every attribute commitment uses:
+// the standard G1 generator and a generator hashed to the curve
+func DefaultCommitmentGenerators() *CommitmentGenerators {
+	g1 := bls12381.NewG1()
+	h, _ := commitmentGenerator()
+
+	return &CommitmentGenerators{
+		G: g1.ToBytes(g1.One()),
+		H: g1.ToBytes(h),
+	}
+}
+
+// MessageScalar returns the scalar a BBS+ signature binds for a message, as 32 big-endian bytes
+func MessageScalar(message []byte) []byte {
+	hash := sha256.Sum256(message)
+	return bls12381.NewFr().FromBytes(hash[:]).ToBytes()
+}
+
+// ScalarFromInt encodes an integer as a scalar, as 32 big-endian bytes.
+// Negative values wrap around the group order.
+func ScalarFromInt(value *big.Int) []byte {
+	return new(big.Int).Mod(value, groupOrder).FillBytes(make([]byte, 32))
+}
+
+// Commit commits to a scalar value with a fresh blinding factor, returning the commitment and
+// the blinding factor needed to open it
+func Commit(value []byte) (commitment []byte, blinding []byte, err error) {
+	r, err := bls12381.NewFr().Rand(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate blinding factor: %w", err)
+	}
+
+	point, err := commit(value, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bls12381.NewG1().ToBytes(point), r.ToBytes(), nil
+}
+
+// VerifyCommitmentOpening checks that a commitment opens to value with the blinding factor
+func VerifyCommitmentOpening(commitment, value, blinding []byte) error {
+	g1 := bls12381.NewG1()
+
+	point, err := decodeG1Point(g1, commitment)
+	if err != nil {
+		return fmt.Errorf("invalid commitment: %w", err)
+	}
+
+	expected, err := commit(value, bls12381.NewFr().FromBytes(blinding))
+	if err != nil {
+		return err
+	}
+
+	if !g1.Equal(point, expected) {
+		return fmt.Errorf("commitment does not open to the value")
+	}
+
+	return nil
+}
+
+// commit computes G^m * H^r
+func commit(value []byte, blinding *bls12381.Fr) (*bls12381.PointG1, error) {
+	if len(value) > 32 {
+		return nil, fmt.Errorf("invalid scalar length: %d", len(value))
+	}
+
+	g1 := bls12381.NewG1()
+	h, err := commitmentGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	point := &bls12381.PointG1{}
+	g1.MulScalar(point, g1.One(), bls12381.NewFr().FromBytes(value))
+
+	term := &bls12381.PointG1{}
+	g1.MulScalar(term, h, blinding)
+	g1.Add(point, point, term)
+
+	return point, nil
+}
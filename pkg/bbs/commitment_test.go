@@ -0,0 +1,70 @@
+package bbs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeCommitments(t *testing.T) {
+	value := ScalarFromInt(big.NewInt(20000120))
+
+	commitment, blinding, err := Commit(value)
+	require.NoError(t, err)
+	assert.Len(t, commitment, 96) // G1 point is 96 bytes
+	assert.Len(t, blinding, 32)
+
+	assert.NoError(t, VerifyCommitmentOpening(commitment, value, blinding))
+
+	// Commitments hide the value: committing again gives a different commitment
+	again, _, err := Commit(value)
+	require.NoError(t, err)
+	assert.NotEqual(t, commitment, again)
+
+	t.Run("Wrong Value", func(t *testing.T) {
+		err := VerifyCommitmentOpening(commitment, ScalarFromInt(big.NewInt(20000121)), blinding)
+		assert.Error(t, err)
+	})
+
+	t.Run("Wrong Blinding", func(t *testing.T) {
+		_, otherBlinding, err := Commit(value)
+		require.NoError(t, err)
+		assert.Error(t, VerifyCommitmentOpening(commitment, value, otherBlinding))
+	})
+
+	t.Run("Malicious Commitments Rejected", func(t *testing.T) {
+		for name, encoded := range map[string][]byte{
+			"Identity":  make([]byte, 96),
+			"Low Order": lowOrderG1(),
+		} {
+			err := VerifyCommitmentOpening(encoded, value, blinding)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "invalid commitment")
+		}
+	})
+
+	t.Run("Message Scalars", func(t *testing.T) {
+		scalar := MessageScalar([]byte("Vietnamese"))
+		assert.Len(t, scalar, 32)
+		assert.Equal(t, scalar, MessageScalar([]byte("Vietnamese")))
+
+		commitment, blinding, err := Commit(scalar)
+		require.NoError(t, err)
+		assert.NoError(t, VerifyCommitmentOpening(commitment, scalar, blinding))
+	})
+
+	t.Run("Negative Integers Wrap", func(t *testing.T) {
+		minusOne := new(big.Int).Sub(groupOrder, big.NewInt(1))
+		assert.Equal(t, minusOne.FillBytes(make([]byte, 32)), ScalarFromInt(big.NewInt(-1)))
+	})
+
+	t.Run("Generators", func(t *testing.T) {
+		generators := DefaultCommitmentGenerators()
+		assert.Len(t, generators.G, 96)
+		assert.Len(t, generators.H, 96)
+		assert.NotEqual(t, generators.G, generators.H)
+		assert.Equal(t, generators, DefaultCommitmentGenerators())
+	})
+}
@@ -166,7 +166,11 @@ func testService(service BBSInterface, name string) error {
 	// Create proof (reveal only name and city, hide age and job)
 	fmt.Println("5. Creating selective disclosure proof...")
 	revealedIndices := []int{0, 3} // Reveal Alice and New York
-	nonce := []byte("test-nonce-123")
+	generatedNonce, err := GenerateNonce()
+	if err != nil {
+		return err
+	}
+	nonce := []byte(generatedNonce)
 
 	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 	if err != nil {
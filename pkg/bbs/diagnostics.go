@@ -0,0 +1,167 @@
+package bbs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ProofDiagnostics is a step-by-step account of a proof verification, for integrators debugging
+// proofs made by another implementation. It exposes revealed messages and should only be
+// produced where the caller is trusted with them.
+type ProofDiagnostics struct {
+	Valid bool `json:"valid"`
+	// FailedCheck names the first check that did not pass
+	FailedCheck string `json:"failedCheck,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// Checks are the checks in the order verification ran them, up to the first failure
+	Checks []ProofCheck `json:"checks"`
+	// RevealedMessages maps each revealed message to the index the proof claims for it
+	RevealedMessages []RevealedMessageMapping `json:"revealedMessages"`
+	// Challenge shows how the challenge was recomputed; nil when verification stopped earlier
+	Challenge *ChallengeDiagnostics `json:"challenge,omitempty"`
+	// NonceMatchesProof reports whether the verifier's nonce is the one the proof records. The
+	// challenge is recomputed with the verifier's nonce either way.
+	NonceMatchesProof bool `json:"nonceMatchesProof"`
+}
+
+// ProofCheck is the outcome of one verification check
+type ProofCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// RevealedMessageMapping pairs a revealed message with its signed message index
+type RevealedMessageMapping struct {
+	// Position is the message's position in the revealed messages
+	Position int `json:"position"`
+	// Index is the signed message index the proof records at that position
+	Index       int    `json:"index"`
+	Length      int    `json:"length"`
+	SHA256      string `json:"sha256"`
+	MessageHex  string `json:"messageHex"`
+	OutOfOrder  bool   `json:"outOfOrder,omitempty"`
+	DuplicateOf *int   `json:"duplicateOf,omitempty"`
+}
+
+// ChallengeDiagnostics lists the inputs the challenge was recomputed from, in hashing order
+type ChallengeDiagnostics struct {
+	Inputs    []ChallengeInput `json:"inputs"`
+	Expected  string           `json:"expected"`
+	Presented string           `json:"presented"`
+	Match     bool             `json:"match"`
+}
+
+// ChallengeInput is one labelled segment of the challenge hash input
+type ChallengeInput struct {
+	Label  string `json:"label"`
+	Length int    `json:"length"`
+	Hex    string `json:"hex"`
+}
+
+// ProofDiagnoser is implemented by services that can explain a proof verification
+type ProofDiagnoser interface {
+	DiagnoseProof(publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte) *ProofDiagnostics
+}
+
+// DiagnoseProof verifies a proof and reports how. Services that do not implement ProofDiagnoser
+// only report the index mapping and the verification outcome.
+func DiagnoseProof(service BBSService, publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte) *ProofDiagnostics {
+	if diagnoser, ok := service.(ProofDiagnoser); ok {
+		return diagnoser.DiagnoseProof(publicKey, proof, revealedMessages, nonce)
+	}
+
+	diagnostics := newProofDiagnostics(proof, revealedMessages, nonce)
+	if proof == nil {
+		diagnostics.check("proof.present", false, "proof is nil")
+		return diagnostics
+	}
+	err := service.VerifyProof(publicKey, proof, revealedMessages, nonce)
+	diagnostics.check("verifyProof", err == nil, errorDetail(err))
+	diagnostics.Valid = err == nil
+	return diagnostics
+}
+
+// newProofDiagnostics starts a report with the revealed index mapping
+func newProofDiagnostics(proof *Proof, revealedMessages [][]byte, nonce []byte) *ProofDiagnostics {
+	diagnostics := &ProofDiagnostics{
+		Checks:           []ProofCheck{},
+		RevealedMessages: []RevealedMessageMapping{},
+	}
+	if proof == nil {
+		return diagnostics
+	}
+	diagnostics.NonceMatchesProof = string(proof.Nonce) == string(nonce)
+
+	firstPosition := make(map[int]int)
+	for position, message := range revealedMessages {
+		mapping := RevealedMessageMapping{
+			Position:   position,
+			Index:      -1,
+			Length:     len(message),
+			MessageHex: hex.EncodeToString(message),
+		}
+		digest := sha256.Sum256(message)
+		mapping.SHA256 = hex.EncodeToString(digest[:])
+
+		if position < len(proof.RevealedAttributes) {
+			mapping.Index = proof.RevealedAttributes[position]
+			if position > 0 && mapping.Index < proof.RevealedAttributes[position-1] {
+				mapping.OutOfOrder = true
+			}
+			if first, ok := firstPosition[mapping.Index]; ok {
+				mapping.DuplicateOf = &first
+			} else {
+				firstPosition[mapping.Index] = position
+			}
+		}
+		diagnostics.RevealedMessages = append(diagnostics.RevealedMessages, mapping)
+	}
+	return diagnostics
+}
+
+// check records a check and, for the first failure, the check's name and detail
+func (d *ProofDiagnostics) check(name string, passed bool, detail string) {
+	if d == nil {
+		return
+	}
+	d.Checks = append(d.Checks, ProofCheck{Name: name, Passed: passed, Detail: detail})
+	if !passed && d.FailedCheck == "" {
+		d.FailedCheck = name
+		d.Error = detail
+	}
+}
+
+// challengeInput records a segment of the challenge hash input
+func (d *ProofDiagnostics) challengeInput(label string, data []byte) {
+	if d == nil {
+		return
+	}
+	if d.Challenge == nil {
+		d.Challenge = &ChallengeDiagnostics{Inputs: []ChallengeInput{}}
+	}
+	d.Challenge.Inputs = append(d.Challenge.Inputs, ChallengeInput{Label: label, Length: len(data), Hex: hex.EncodeToString(data)})
+}
+
+// errorDetail is an error's message, or empty for nil
+func errorDetail(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// checkError records a failed check and returns the error verification fails with
+func (d *ProofDiagnostics) checkError(name string, err error) error {
+	d.check(name, false, err.Error())
+	return err
+}
+
+// checkPassed records a passed check
+func (d *ProofDiagnostics) checkPassed(name string, format string, args ...interface{}) {
+	if d == nil {
+		return
+	}
+	d.check(name, true, fmt.Sprintf(format, args...))
+}
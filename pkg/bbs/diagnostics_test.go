@@ -0,0 +1,101 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseProof(t *testing.T) {
+	service := NewService()
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message1"), []byte("message2"), []byte("message3")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	require.NoError(t, err)
+
+	generated, err := GenerateNonce()
+	require.NoError(t, err)
+	nonce := []byte(generated)
+	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, []int{0, 2}, nonce)
+	require.NoError(t, err)
+	revealed := [][]byte{messages[0], messages[2]}
+
+	t.Run("Valid Proof", func(t *testing.T) {
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, proof, revealed, nonce)
+		assert.True(t, diagnostics.Valid)
+		assert.Empty(t, diagnostics.FailedCheck)
+		assert.True(t, diagnostics.NonceMatchesProof)
+		for _, check := range diagnostics.Checks {
+			assert.True(t, check.Passed, check.Name)
+		}
+
+		require.Len(t, diagnostics.RevealedMessages, 2)
+		assert.Equal(t, 2, diagnostics.RevealedMessages[1].Index)
+		assert.Equal(t, len(messages[2]), diagnostics.RevealedMessages[1].Length)
+
+		require.NotNil(t, diagnostics.Challenge)
+		assert.True(t, diagnostics.Challenge.Match)
+		assert.Equal(t, diagnostics.Challenge.Expected, diagnostics.Challenge.Presented)
+		labels := make([]string, 0, len(diagnostics.Challenge.Inputs))
+		for _, input := range diagnostics.Challenge.Inputs {
+			labels = append(labels, input.Label)
+		}
+		assert.Equal(t, []string{"A'", "Ā", "d", "T1", "T2", "indices", "nonce", "message[0]", "message[2]"}, labels)
+	})
+
+	t.Run("Tampered Message Fails The Challenge", func(t *testing.T) {
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, proof, [][]byte{messages[0], []byte("tampered")}, nonce)
+		assert.False(t, diagnostics.Valid)
+		assert.Equal(t, "challenge", diagnostics.FailedCheck)
+		require.NotNil(t, diagnostics.Challenge)
+		assert.False(t, diagnostics.Challenge.Match)
+		assert.NotEqual(t, diagnostics.Challenge.Expected, diagnostics.Challenge.Presented)
+	})
+
+	t.Run("Other Nonce", func(t *testing.T) {
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, proof, revealed, []byte("another-nonce-entirely"))
+		assert.False(t, diagnostics.Valid)
+		assert.False(t, diagnostics.NonceMatchesProof)
+		assert.Equal(t, "challenge", diagnostics.FailedCheck)
+	})
+
+	t.Run("Index Mapping Problems", func(t *testing.T) {
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, proof, revealed[:1], nonce)
+		assert.Equal(t, "revealedMessages.count", diagnostics.FailedCheck)
+		assert.Nil(t, diagnostics.Challenge)
+
+		swapped := *proof
+		swapped.RevealedAttributes = []int{2, 2}
+		diagnostics = DiagnoseProof(service, keyPair.PublicKey, &swapped, revealed, nonce)
+		assert.True(t, diagnostics.RevealedMessages[1].DuplicateOf != nil)
+		assert.Equal(t, 0, *diagnostics.RevealedMessages[1].DuplicateOf)
+	})
+
+	t.Run("Invalid Public Key", func(t *testing.T) {
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey[:96], proof, revealed, nonce)
+		assert.False(t, diagnostics.Valid)
+		assert.Equal(t, "publicKey.length", diagnostics.FailedCheck)
+		assert.Equal(t, "invalid public key length", diagnostics.Error)
+	})
+
+	t.Run("Services Without Diagnostics", func(t *testing.T) {
+		simple, err := NewSimpleBBSService()
+		require.NoError(t, err)
+		simpleKeys, err := simple.GenerateKeyPair()
+		require.NoError(t, err)
+		simpleSignature, err := simple.Sign(simpleKeys.PrivateKey, messages)
+		require.NoError(t, err)
+		simpleProof, err := simple.CreateProof(simpleSignature, simpleKeys.PublicKey, messages, []int{1}, nonce)
+		require.NoError(t, err)
+
+		diagnostics := DiagnoseProof(simple, simpleKeys.PublicKey, simpleProof, [][]byte{messages[0]}, nonce)
+		assert.False(t, diagnostics.Valid)
+		assert.Equal(t, "verifyProof", diagnostics.FailedCheck)
+		require.Len(t, diagnostics.RevealedMessages, 1)
+		assert.Equal(t, 1, diagnostics.RevealedMessages[0].Index)
+	})
+}
@@ -0,0 +1,111 @@
+package bbs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// EqualityProof proves that two attribute commitments C1 = G^v * H^r1 and C2 = G^v * H^r2 hold
+// the same value without revealing it. G cancels out of C1 / C2 = H^(r1 - r2) exactly when the
+// values are equal, so a Schnorr proof of knowing log_H(C1 / C2) is a proof of equality.
+type EqualityProof struct {
+	Commitment []byte `json:"commitment"` // H^k
+	Response   []byte `json:"response"`   // k + c * (r1 - r2)
+}
+
+// CreateEqualityProof proves that the commitments opened by value with blinding1 and blinding2
+// hold the same value. The proof is bound to context, which should name both credentials and
+// the presentation.
+func CreateEqualityProof(value, blinding1, blinding2 []byte, context []byte) (*EqualityProof, error) {
+	g1 := bls12381.NewG1()
+	h, err := commitmentGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	r1 := bls12381.NewFr().FromBytes(blinding1)
+	r2 := bls12381.NewFr().FromBytes(blinding2)
+	first, err := commit(value, r1)
+	if err != nil {
+		return nil, err
+	}
+	second, err := commit(value, r2)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := bls12381.NewFr().Rand(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate blinding factor: %w", err)
+	}
+	commitment := &bls12381.PointG1{}
+	g1.MulScalar(commitment, h, nonce)
+
+	challenge := equalityChallenge(g1, first, second, commitment, context)
+
+	// s = k + c * (r1 - r2)
+	difference := bls12381.NewFr()
+	difference.Sub(r1, r2)
+	response := bls12381.NewFr()
+	response.Mul(challenge, difference)
+	response.Add(response, nonce)
+
+	return &EqualityProof{
+		Commitment: g1.ToBytes(commitment),
+		Response:   response.ToBytes(),
+	}, nil
+}
+
+// VerifyEqualityProof checks that commitment1 and commitment2 hold the same value
+func VerifyEqualityProof(commitment1, commitment2 []byte, proof *EqualityProof, context []byte) error {
+	if proof == nil {
+		return fmt.Errorf("proof is nil")
+	}
+
+	g1 := bls12381.NewG1()
+	h, err := commitmentGenerator()
+	if err != nil {
+		return err
+	}
+
+	first, err := decodeG1Point(g1, commitment1)
+	if err != nil {
+		return fmt.Errorf("invalid commitment: %w", err)
+	}
+	second, err := decodeG1Point(g1, commitment2)
+	if err != nil {
+		return fmt.Errorf("invalid commitment: %w", err)
+	}
+	commitment, err := decodeG1Point(g1, proof.Commitment)
+	if err != nil {
+		return fmt.Errorf("invalid proof commitment: %w", err)
+	}
+
+	// H^s must equal H^k * (C1 / C2)^c
+	statement := &bls12381.PointG1{}
+	g1.Sub(statement, first, second)
+	challenge := equalityChallenge(g1, first, second, commitment, context)
+	expected := simulatedBitCommitment(g1, h, statement, challenge, bls12381.NewFr().FromBytes(proof.Response))
+
+	if !g1.Equal(expected, commitment) {
+		return fmt.Errorf("commitments do not hold the same value")
+	}
+
+	return nil
+}
+
+// equalityChallenge derives the Fiat-Shamir challenge from both commitments, the proof
+// commitment and the context
+func equalityChallenge(g1 *bls12381.G1, first, second, commitment *bls12381.PointG1, context []byte) *bls12381.Fr {
+	h := sha256.New()
+	h.Write([]byte("BBS_EQUALITY_PROOF"))
+	h.Write(g1.ToBytes(first))
+	h.Write(g1.ToBytes(second))
+	h.Write(g1.ToBytes(commitment))
+	h.Write(context)
+
+	return bls12381.NewFr().FromBytes(h.Sum(nil))
+}
@@ -0,0 +1,56 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqualityProof(t *testing.T) {
+	value := MessageScalar([]byte("did:example:holder"))
+	first, blinding1, err := Commit(value)
+	require.NoError(t, err)
+	second, blinding2, err := Commit(value)
+	require.NoError(t, err)
+
+	context := []byte("vc:1 vc:2 continuity-nonce")
+
+	proof, err := CreateEqualityProof(value, blinding1, blinding2, context)
+	require.NoError(t, err)
+	assert.NoError(t, VerifyEqualityProof(first, second, proof, context))
+
+	t.Run("Bound To Context", func(t *testing.T) {
+		assert.Error(t, VerifyEqualityProof(first, second, proof, []byte("vc:1 vc:2 other-nonce")))
+	})
+
+	t.Run("Different Values Rejected", func(t *testing.T) {
+		other, otherBlinding, err := Commit(MessageScalar([]byte("did:example:someone-else")))
+		require.NoError(t, err)
+
+		// A proof made with the wrong opening does not verify against the real commitments
+		forged, err := CreateEqualityProof(value, blinding1, otherBlinding, context)
+		require.NoError(t, err)
+		assert.Error(t, VerifyEqualityProof(first, other, forged, context))
+		assert.Error(t, VerifyEqualityProof(first, other, proof, context))
+	})
+
+	t.Run("Swapped Commitments Rejected", func(t *testing.T) {
+		assert.Error(t, VerifyEqualityProof(second, first, proof, context))
+	})
+
+	t.Run("Malicious Commitments Rejected", func(t *testing.T) {
+		for name, encoded := range map[string][]byte{
+			"Identity":  make([]byte, 96),
+			"Low Order": lowOrderG1(),
+		} {
+			err := VerifyEqualityProof(encoded, second, proof, context)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "invalid commitment")
+
+			err = VerifyEqualityProof(first, encoded, proof, context)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "invalid commitment")
+		}
+	})
+}
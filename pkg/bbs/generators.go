@@ -0,0 +1,77 @@
+package bbs
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+var (
+	commitmentGeneratorOnce  sync.Once
+	commitmentGeneratorPoint *bls12381.PointG1
+	commitmentGeneratorErr   error
+)
+
+// commitmentGenerator returns the commitment generator H, hashing it to the curve once per process
+func commitmentGenerator() (*bls12381.PointG1, error) {
+	commitmentGeneratorOnce.Do(func() {
+		commitmentGeneratorPoint, commitmentGeneratorErr = bls12381.NewG1().HashToCurve([]byte("H"), commitmentDST)
+	})
+	if commitmentGeneratorErr != nil {
+		return nil, fmt.Errorf("failed to derive commitment generator: %w", commitmentGeneratorErr)
+	}
+
+	return new(bls12381.PointG1).Set(commitmentGeneratorPoint), nil
+}
+
+// signatureGenerators holds h0, the generator of a signature's s, and the message generators
+// H_1, H_2, ... hashed to the curve so far. Each is hashed once per process and shared, so callers
+// must not modify the points.
+var signatureGenerators = struct {
+	sync.RWMutex
+	points []*bls12381.PointG1
+}{}
+
+// warmupMessageGenerators is how many message generators Warmup derives ahead of time
+const warmupMessageGenerators = 16
+
+// generatorPoints returns h0 followed by the generators of the first n messages. A message's
+// generator depends only on its index, so a proof can account for messages it does not reveal.
+func generatorPoints(n int) []*bls12381.PointG1 {
+	signatureGenerators.RLock()
+	points := signatureGenerators.points
+	signatureGenerators.RUnlock()
+	if len(points) > n {
+		return points[:n+1]
+	}
+
+	signatureGenerators.Lock()
+	defer signatureGenerators.Unlock()
+	g1 := bls12381.NewG1()
+	for i := len(signatureGenerators.points); i <= n; i++ {
+		// "H0" labels h0 and "H<i>" the generator of message i-1
+		label := strconv.AppendInt([]byte("H"), int64(i), 10)
+		signatureGenerators.points = append(signatureGenerators.points, mapToG1(g1, label))
+	}
+	return signatureGenerators.points[:n+1]
+}
+
+// blindingGenerator returns h0
+func blindingGenerator() *bls12381.PointG1 {
+	return generatorPoints(0)[0]
+}
+
+// messageGenerators returns the generators of the first n messages
+func messageGenerators(n int) []*bls12381.PointG1 {
+	return generatorPoints(n)[1:]
+}
+
+// Warmup precomputes the fixed generators hashed to the curve, so the first request that signs,
+// commits to attributes or proves a range does not pay for them
+func Warmup() error {
+	generatorPoints(warmupMessageGenerators)
+	_, err := commitmentGenerator()
+	return err
+}
@@ -1,9 +1,12 @@
 package bbs
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/secrets"
 )
 
 // Provider represents the BBS implementation provider type
@@ -34,6 +37,10 @@ func ParseProvider(s string) (Provider, error) {
 	}
 }
 
+// ErrSignerUnavailable is returned when the key store a signature is made with, such as a remote
+// KMS, cannot be reached. Signing again later may succeed.
+var ErrSignerUnavailable = errors.New("signer unavailable")
+
 // BBSInterface defines the core BBS+ operations interface
 type BBSInterface interface {
 	// Core BBS+ operations
@@ -70,6 +77,10 @@ type Config struct {
 
 	// Aries-specific settings
 	AriesConfig *AriesConfig `json:"aries_config,omitempty"`
+
+	// Secrets resolves AriesConfig.AuthToken when it references a secret; nil uses env: and
+	// file: references only
+	Secrets *secrets.Resolver `json:"-"`
 }
 
 // AriesConfig holds Aries Framework specific configuration
@@ -81,7 +92,9 @@ type AriesConfig struct {
 
 	// Remote KMS settings (if applicable)
 	RemoteKMSURL string `json:"remote_kms_url,omitempty"`
-	AuthToken    string `json:"auth_token,omitempty"`
+	// AuthToken authenticates to the remote KMS. It should reference a secret, e.g.
+	// env:ARIES_AUTH_TOKEN or vault:bbs/aries#token, rather than hold the token itself.
+	AuthToken string `json:"auth_token,omitempty"`
 }
 
 // DefaultConfig returns a default configuration
@@ -59,6 +59,22 @@ func TestBBSInterface(t *testing.T) {
 		 testBasicOperationsProduction(t, service)
 	})
 
+	t.Run("Aries Remote KMS Token Is A Secret Reference", func(t *testing.T) {
+		config := DefaultConfig()
+		config.AriesConfig.KMSType = "remote"
+		config.AriesConfig.RemoteKMSURL = "https://kms.example.com"
+		config.AriesConfig.AuthToken = "env:BBS_TEST_ARIES_TOKEN"
+
+		t.Setenv("BBS_TEST_ARIES_TOKEN", "kms-token")
+		service, err := NewFactory().CreateService(ProviderAries, config)
+		require.NoError(t, err)
+		assert.Equal(t, "kms-token", service.(*AriesService).authToken)
+
+		config.AriesConfig.AuthToken = "env:BBS_TEST_ARIES_TOKEN_UNSET"
+		_, err = NewFactory().CreateService(ProviderAries, config)
+		assert.ErrorContains(t, err, "remote KMS auth token")
+	})
+
 	t.Run("Provider Switching", func(t *testing.T) {
 		// Start with simple service
 		simpleService, err := NewSimpleBBSService()
@@ -213,7 +229,7 @@ func testBasicOperations(t *testing.T, service BBSInterface) {
 
 	// Create proof
 	revealedIndices := []int{0, 2}
-	nonce := []byte("test-nonce")
+	nonce := testNonce(t)
 
 	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 	require.NoError(t, err)
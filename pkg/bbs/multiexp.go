@@ -0,0 +1,95 @@
+package bbs
+
+import (
+	"encoding/binary"
+	"sync"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// mulWindow is the window width, in bits, of the fixed-window scalar multiplication
+const mulWindow = 4
+
+// mulTable holds the multiples 0·P through 15·P of one base point
+type mulTable [1 << mulWindow]bls12381.PointG1
+
+// mulTablePool recycles multiplication tables. The curve library's MulScalar builds around
+// fifty heap points per call, which made it the largest source of allocations in the proof paths.
+var mulTablePool = sync.Pool{New: func() interface{} { return new(mulTable) }}
+
+// fill sets the table to the multiples of p
+func (t *mulTable) fill(g1 *bls12381.G1, p *bls12381.PointG1) {
+	t[0].Zero()
+	t[1].Set(p)
+	for i := 2; i < len(t); i++ {
+		g1.Add(&t[i], &t[i-1], p)
+	}
+}
+
+// window returns the mulWindow bits of e starting at bit
+func window(e *bls12381.Fr, bit int) int {
+	w := 0
+	for i := mulWindow - 1; i >= 0; i-- {
+		w <<= 1
+		if e.Bit(bit + i) {
+			w |= 1
+		}
+	}
+	return w
+}
+
+// digestScalar reads 32 big-endian bytes into the limbs of an Fr without reducing them.
+// Fr.FromBytes reduces through big.Int, which costs several allocations per scalar; the
+// reduction does not change a product with a point of the prime-order subgroup, so
+// digestScalar is only for scalars that are used as multipliers.
+func digestScalar(digest *[32]byte) bls12381.Fr {
+	return bls12381.Fr{
+		binary.BigEndian.Uint64(digest[24:]),
+		binary.BigEndian.Uint64(digest[16:]),
+		binary.BigEndian.Uint64(digest[8:]),
+		binary.BigEndian.Uint64(digest[:8]),
+	}
+}
+
+// sumOfProducts sets r = scalars[0]·points[0] + ... + scalars[n-1]·points[n-1]. All terms share
+// one doubling chain (Straus' method), so n terms cost one multiplication's doublings plus n
+// tables of additions, and the tables come from mulTablePool.
+func sumOfProducts(g1 *bls12381.G1, r *bls12381.PointG1, points []*bls12381.PointG1, scalars []bls12381.Fr) *bls12381.PointG1 {
+	tables := make([]*mulTable, len(points))
+	for i, p := range points {
+		tables[i] = mulTablePool.Get().(*mulTable)
+		tables[i].fill(g1, p)
+	}
+	accumulate(g1, r, tables, scalars)
+	for _, table := range tables {
+		mulTablePool.Put(table)
+	}
+	return r
+}
+
+// mulScalar sets r = e·p with a pooled table
+func mulScalar(g1 *bls12381.G1, r, p *bls12381.PointG1, e *bls12381.Fr) *bls12381.PointG1 {
+	table := mulTablePool.Get().(*mulTable)
+	table.fill(g1, p)
+	accumulate(g1, r, []*mulTable{table}, []bls12381.Fr{*e})
+	mulTablePool.Put(table)
+	return r
+}
+
+// accumulate sets r to the sum of each table's point times the matching scalar
+func accumulate(g1 *bls12381.G1, r *bls12381.PointG1, tables []*mulTable, scalars []bls12381.Fr) {
+	// Fr holds 256 bits; scalars are not assumed reduced below the group order
+	var acc bls12381.PointG1
+	acc.Zero()
+	for bit := 256 - mulWindow; bit >= 0; bit -= mulWindow {
+		for i := 0; i < mulWindow; i++ {
+			g1.Double(&acc, &acc)
+		}
+		for i, table := range tables {
+			if w := window(&scalars[i], bit); w != 0 {
+				g1.Add(&acc, &acc, &table[w])
+			}
+		}
+	}
+	r.Set(&acc)
+}
@@ -0,0 +1,78 @@
+package bbs
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumOfProducts(t *testing.T) {
+	g1 := bls12381.NewG1()
+
+	points := make([]*bls12381.PointG1, 5)
+	scalars := make([]bls12381.Fr, len(points))
+	expected := g1.Zero()
+	for i := range points {
+		var err error
+		points[i], err = g1.HashToCurve([]byte(fmt.Sprintf("point-%d", i)), generatorDST)
+		require.NoError(t, err)
+
+		digest := sha256.Sum256([]byte(fmt.Sprintf("scalar-%d", i)))
+		scalars[i] = digestScalar(&digest)
+
+		var reduced bls12381.Fr
+		reduced.FromBytes(digest[:])
+		term := g1.New()
+		g1.MulScalar(term, points[i], &reduced)
+		g1.Add(expected, expected, term)
+	}
+
+	t.Run("Matches Library Multiplication", func(t *testing.T) {
+		var sum bls12381.PointG1
+		sumOfProducts(g1, &sum, points, scalars)
+		assert.True(t, g1.Equal(expected, &sum))
+	})
+
+	t.Run("Single Term", func(t *testing.T) {
+		var product, want bls12381.PointG1
+		mulScalar(g1, &product, points[0], &scalars[0])
+		sumOfProducts(g1, &want, points[:1], scalars[:1])
+		assert.True(t, g1.Equal(&want, &product))
+	})
+
+	t.Run("Unreduced Digest", func(t *testing.T) {
+		var digest [32]byte
+		for i := range digest {
+			digest[i] = 0xff
+		}
+		unreduced := digestScalar(&digest)
+		var reduced bls12381.Fr
+		reduced.FromBytes(digest[:])
+
+		var got, want bls12381.PointG1
+		mulScalar(g1, &got, points[0], &unreduced)
+		g1.MulScalar(&want, points[0], &reduced)
+		assert.True(t, g1.Equal(&want, &got))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var sum bls12381.PointG1
+		sumOfProducts(g1, &sum, nil, nil)
+		assert.True(t, g1.IsZero(&sum))
+	})
+}
+
+func TestInG1Subgroup(t *testing.T) {
+	g1 := bls12381.NewG1()
+
+	assert.True(t, inG1Subgroup(g1, g1.One()))
+
+	point, err := g1.FromBytes(lowOrderG1())
+	require.NoError(t, err)
+	assert.False(t, inG1Subgroup(g1, point))
+	assert.Equal(t, g1.InCorrectSubgroup(point), inG1Subgroup(g1, point))
+}
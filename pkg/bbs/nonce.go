@@ -0,0 +1,67 @@
+package bbs
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math"
+)
+
+const (
+	// NonceSize is the number of random bytes in a generated nonce
+	NonceSize = 16
+	// MinNonceLength is the shortest nonce CreateProof accepts, in bytes
+	MinNonceLength = 16
+	// MinNonceEntropyBits is the estimated entropy below which CreateProof rejects a nonce
+	MinNonceEntropyBits = 48
+)
+
+// GenerateNonce returns NonceSize bytes from crypto/rand, base64url encoded without padding
+func GenerateNonce() (string, error) {
+	nonce := make([]byte, NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
+
+// NonceEntropyBits estimates a nonce's entropy from its length and the empirical distribution of
+// its bytes. Repeated patterns score low; random bytes, hex, base64 or UUIDs score high.
+func NonceEntropyBits(nonce []byte) float64 {
+	if len(nonce) == 0 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, b := range nonce {
+		counts[b]++
+	}
+
+	perByte := 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / float64(len(nonce))
+		perByte -= p * math.Log2(p)
+	}
+	return perByte * float64(len(nonce))
+}
+
+// ValidateNonce rejects nonces that are missing, shorter than MinNonceLength, or whose estimated
+// entropy is below MinNonceEntropyBits, since a guessable nonce lets a proof be replayed
+func ValidateNonce(nonce []byte) error {
+	if len(nonce) == 0 {
+		return fmt.Errorf("nonce is required")
+	}
+
+	if len(nonce) < MinNonceLength {
+		return fmt.Errorf("nonce is too short: got %d bytes, need at least %d", len(nonce), MinNonceLength)
+	}
+
+	if bits := NonceEntropyBits(nonce); bits < MinNonceEntropyBits {
+		return fmt.Errorf("nonce has too little entropy: about %.0f bits, need at least %d", bits, MinNonceEntropyBits)
+	}
+
+	return nil
+}
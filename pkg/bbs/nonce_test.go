@@ -0,0 +1,52 @@
+package bbs
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateNonce(t *testing.T) {
+	nonce, err := GenerateNonce()
+	require.NoError(t, err)
+
+	raw, err := base64.RawURLEncoding.DecodeString(nonce)
+	require.NoError(t, err)
+	assert.Len(t, raw, NonceSize)
+	assert.NoError(t, ValidateNonce([]byte(nonce)))
+
+	other, err := GenerateNonce()
+	require.NoError(t, err)
+	assert.NotEqual(t, nonce, other)
+}
+
+func TestValidateNonce(t *testing.T) {
+	assert.EqualError(t, ValidateNonce(nil), "nonce is required")
+	assert.ErrorContains(t, ValidateNonce([]byte("demo-proof-2024")), "nonce is too short")
+	assert.ErrorContains(t, ValidateNonce([]byte("aaaaaaaaaaaaaaaaaaaaaaaa")), "nonce has too little entropy")
+	assert.ErrorContains(t, ValidateNonce([]byte("abababababababababababab")), "nonce has too little entropy")
+
+	assert.NoError(t, ValidateNonce([]byte("3f2b8c61-0d4e-4f4a-9b7e-5a1c2d3e4f60")))
+	assert.NoError(t, ValidateNonce([]byte("9c0e4b1f7a2d35e8c6b04f19a7d2e3c5")))
+}
+
+func TestCreateProofRejectsWeakNonces(t *testing.T) {
+	service := NewService()
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message1"), []byte("message2")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	require.NoError(t, err)
+
+	for _, nonce := range []string{"cinema-42", "0000000000000000000000"} {
+		_, err := service.CreateProof(signature, keyPair.PublicKey, messages, []int{0}, []byte(nonce))
+		assert.Error(t, err, nonce)
+	}
+
+	_, err = service.CreateProof(signature, keyPair.PublicKey, messages, []int{0}, testNonce(t))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,63 @@
+package bbs
+
+import (
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// decodeG1Point deserializes a G1 point received from outside. FromBytes only checks that the
+// point is on the curve, so the identity and points of small order (outside the prime-order
+// subgroup) are rejected here: they make pairing equations hold trivially or leak through
+// cofactor components.
+func decodeG1Point(g1 *bls12381.G1, in []byte) (*bls12381.PointG1, error) {
+	point, err := g1.FromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if g1.IsZero(point) {
+		return nil, fmt.Errorf("point is the identity")
+	}
+
+	if !inG1Subgroup(g1, point) {
+		return nil, fmt.Errorf("point is not in the prime-order subgroup")
+	}
+
+	return point, nil
+}
+
+// orderMinusOne is r - 1 for the BLS12-381 group order r
+var orderMinusOne = func() bls12381.Fr {
+	var digest [32]byte
+	new(big.Int).Sub(groupOrder, big.NewInt(1)).FillBytes(digest[:])
+	return digestScalar(&digest)
+}()
+
+// inG1Subgroup reports whether p has order r by checking (r - 1)·P = -P. G1.InCorrectSubgroup
+// is faster but allocates around fifty points per call; this runs on a pooled table.
+func inG1Subgroup(g1 *bls12381.G1, p *bls12381.PointG1) bool {
+	var product, negated bls12381.PointG1
+	mulScalar(g1, &product, p, &orderMinusOne)
+	return g1.Equal(&product, g1.Neg(&negated, p))
+}
+
+// decodeG2Point deserializes a G2 point received from outside, with the same identity and
+// subgroup checks as decodeG1Point
+func decodeG2Point(g2 *bls12381.G2, in []byte) (*bls12381.PointG2, error) {
+	point, err := g2.FromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if g2.IsZero(point) {
+		return nil, fmt.Errorf("point is the identity")
+	}
+
+	if !g2.InCorrectSubgroup(point) {
+		return nil, fmt.Errorf("point is not in the prime-order subgroup")
+	}
+
+	return point, nil
+}
@@ -0,0 +1,226 @@
+package bbs
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/kilic/bls12-381"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldModulus is the BLS12-381 base field modulus p
+var fieldModulus, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+// fieldBytes encodes a base field element as 48 big-endian bytes
+func fieldBytes(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, 48))
+}
+
+// lowOrderG1 encodes (0, 2), which is on y² = x³ + 4 but has order 3
+func lowOrderG1() []byte {
+	return append(fieldBytes(big.NewInt(0)), fieldBytes(big.NewInt(2))...)
+}
+
+// sqrtFp2 returns a square root of a0 + a1·i in Fp2 (i² = -1), for a1 != 0
+func sqrtFp2(a0, a1 *big.Int) (*big.Int, *big.Int, bool) {
+	p := fieldModulus
+	norm := new(big.Int).Mul(a0, a0)
+	norm.Add(norm, new(big.Int).Mul(a1, a1)).Mod(norm, p)
+	s := new(big.Int).ModSqrt(norm, p)
+	if s == nil {
+		return nil, nil, false
+	}
+
+	half := new(big.Int).ModInverse(big.NewInt(2), p)
+	for _, candidate := range []*big.Int{new(big.Int).Add(a0, s), new(big.Int).Sub(a0, s)} {
+		candidate.Mul(candidate, half).Mod(candidate, p)
+		x0 := new(big.Int).ModSqrt(candidate, p)
+		if x0 == nil || x0.Sign() == 0 {
+			continue
+		}
+		x1 := new(big.Int).Lsh(x0, 1)
+		x1.ModInverse(x1, p).Mul(x1, a1).Mod(x1, p)
+		return x0, x1, true
+	}
+	return nil, nil, false
+}
+
+// nonSubgroupG2 encodes a point on the G2 twist y² = x³ + 4(1 + i) that is outside the
+// prime-order subgroup: the curve's cofactor is large, so the first point found is not in it.
+func nonSubgroupG2(t *testing.T) []byte {
+	p := fieldModulus
+	for k := int64(1); k < 1000; k++ {
+		// x = k, so x³ + 4(1 + i) = (k³ + 4) + 4i
+		a0 := new(big.Int).Exp(big.NewInt(k), big.NewInt(3), p)
+		a0.Add(a0, big.NewInt(4)).Mod(a0, p)
+		y0, y1, ok := sqrtFp2(a0, big.NewInt(4))
+		if !ok {
+			continue
+		}
+
+		// Fp2 elements are encoded as c1 || c0
+		encoded := make([]byte, 0, 192)
+		encoded = append(encoded, fieldBytes(big.NewInt(0))...)
+		encoded = append(encoded, fieldBytes(big.NewInt(k))...)
+		encoded = append(encoded, fieldBytes(y1)...)
+		encoded = append(encoded, fieldBytes(y0)...)
+		return encoded
+	}
+	t.Fatal("no point found on the twist")
+	return nil
+}
+
+// offCurve flips a bit of the last coordinate byte so the point no longer satisfies the curve equation
+func offCurve(encoded []byte) []byte {
+	tampered := append([]byte(nil), encoded...)
+	tampered[len(tampered)-1] ^= 1
+	return tampered
+}
+
+// nonCanonical sets every byte, so the x coordinate is not reduced modulo p
+func nonCanonical(size int) []byte {
+	encoded := make([]byte, size)
+	for i := range encoded {
+		encoded[i] = 0xff
+	}
+	return encoded
+}
+
+func TestDecodePoints(t *testing.T) {
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+
+	t.Run("Test Vectors Are On The Curve", func(t *testing.T) {
+		point, err := g1.FromBytes(lowOrderG1())
+		require.NoError(t, err)
+		assert.False(t, g1.InCorrectSubgroup(point))
+
+		tripled := g1.New()
+		g1.Add(tripled, point, point)
+		g1.Add(tripled, tripled, point)
+		assert.True(t, g1.IsZero(tripled), "(0, 2) has order 3")
+
+		twistPoint, err := g2.FromBytes(nonSubgroupG2(t))
+		require.NoError(t, err)
+		assert.False(t, g2.InCorrectSubgroup(twistPoint))
+	})
+
+	t.Run("G1", func(t *testing.T) {
+		_, err := decodeG1Point(g1, g1.ToBytes(g1.One()))
+		require.NoError(t, err)
+
+		for name, tc := range map[string]struct {
+			encoded []byte
+			message string
+		}{
+			"Identity":      {make([]byte, 96), "identity"},
+			"Low Order":     {lowOrderG1(), "subgroup"},
+			"Off Curve":     {offCurve(g1.ToBytes(g1.One())), "not on curve"},
+			"Non Canonical": {nonCanonical(96), "less than modulus"},
+			"Wrong Length":  {g1.ToBytes(g1.One())[:48], "96 bytes"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := decodeG1Point(g1, tc.encoded)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.message)
+			})
+		}
+	})
+
+	t.Run("G2", func(t *testing.T) {
+		_, err := decodeG2Point(g2, g2.ToBytes(g2.One()))
+		require.NoError(t, err)
+
+		for name, tc := range map[string]struct {
+			encoded []byte
+			message string
+		}{
+			"Identity":      {make([]byte, 192), "identity"},
+			"Non Subgroup":  {nonSubgroupG2(t), "subgroup"},
+			"Off Curve":     {offCurve(g2.ToBytes(g2.One())), "not on curve"},
+			"Non Canonical": {nonCanonical(192), "less than modulus"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := decodeG2Point(g2, tc.encoded)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.message)
+			})
+		}
+	})
+}
+
+func TestMaliciousPointsRejected(t *testing.T) {
+	service := NewService()
+	g1 := bls12381.NewG1()
+	g2 := bls12381.NewG2()
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message1"), []byte("message2"), []byte("message3")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	require.NoError(t, err)
+
+	nonce := testNonce(t)
+	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, []int{0}, nonce)
+	require.NoError(t, err)
+	require.NoError(t, service.VerifyProof(keyPair.PublicKey, proof, messages[:1], nonce))
+
+	badG1 := map[string][]byte{
+		"Identity":  make([]byte, 96),
+		"Low Order": lowOrderG1(),
+		"Off Curve": offCurve(g1.ToBytes(g1.One())),
+	}
+	badG2 := map[string][]byte{
+		"Identity":     make([]byte, 192),
+		"Non Subgroup": nonSubgroupG2(t),
+		"Off Curve":    offCurve(g2.ToBytes(g2.One())),
+	}
+
+	for name, encoded := range badG1 {
+		t.Run("Signature A "+name, func(t *testing.T) {
+			tampered := *signature
+			tampered.A = encoded
+			err := service.Verify(keyPair.PublicKey, &tampered, messages)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid signature A")
+
+			_, err = service.CreateProof(&tampered, keyPair.PublicKey, messages, []int{0}, nonce)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid signature A")
+		})
+
+		t.Run("Proof A' "+name, func(t *testing.T) {
+			tampered := *proof
+			tampered.A_prime = encoded
+			err := service.VerifyProof(keyPair.PublicKey, &tampered, messages[:1], nonce)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid A'")
+		})
+
+		t.Run("Proof Ā "+name, func(t *testing.T) {
+			tampered := *proof
+			tampered.A_bar = encoded
+			err := service.VerifyProof(keyPair.PublicKey, &tampered, messages[:1], nonce)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid Ā")
+		})
+	}
+
+	for name, encoded := range badG2 {
+		t.Run("Public Key "+name, func(t *testing.T) {
+			err := service.Verify(encoded, signature, messages)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid public key")
+
+			err = service.VerifyProof(encoded, proof, messages[:1], nonce)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid public key")
+
+			err = service.ValidateKeyPair(&KeyPair{PrivateKey: keyPair.PrivateKey, PublicKey: encoded})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid public key format")
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package bbs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// MaxRangeBits is the widest range a range proof can cover
+const MaxRangeBits = 64
+
+// RangeProof proves that an attribute commitment C = G^v * H^r holds a value in [0, 2^n) without
+// revealing it. The value is split into bits, each bit is committed separately, and an OR-proof
+// shows each bit commitment holds 0 or 1. The bit commitments, weighted by powers of two, multiply
+// to C, so they commit to the same value.
+type RangeProof struct {
+	BitCommitments [][]byte `json:"bitCommitments"`
+	Challenges     [][]byte `json:"challenges"` // two per bit: the branch for 0, then the branch for 1
+	Responses      [][]byte `json:"responses"`  // two per bit, in the same order
+}
+
+// CreateRangeProof proves that the commitment opened by value and blinding holds a value below
+// 2^bits. The proof is bound to context, which should name the statement and the presentation.
+func CreateRangeProof(value, blinding []byte, bits int, context []byte) (*RangeProof, error) {
+	if bits < 1 || bits > MaxRangeBits {
+		return nil, fmt.Errorf("invalid range: %d bits", bits)
+	}
+
+	number := new(big.Int).SetBytes(value)
+	if number.BitLen() > bits {
+		return nil, fmt.Errorf("value is out of range")
+	}
+
+	g1 := bls12381.NewG1()
+	h, err := commitmentGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	total := bls12381.NewFr().FromBytes(blinding)
+	commitment, err := commit(value, total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Random blindings for every bit but the lowest, which takes whatever makes the weighted
+	// sum of bit blindings equal the commitment's blinding
+	blindings := make([]*bls12381.Fr, bits)
+	remaining := bls12381.NewFr().Set(total)
+	for i := 1; i < bits; i++ {
+		if blindings[i], err = bls12381.NewFr().Rand(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate blinding factor: %w", err)
+		}
+		weighted := bls12381.NewFr()
+		weighted.Mul(blindings[i], powerOfTwo(i))
+		remaining.Sub(remaining, weighted)
+	}
+	blindings[0] = remaining
+
+	proof := &RangeProof{
+		BitCommitments: make([][]byte, bits),
+		Challenges:     make([][]byte, 2*bits),
+		Responses:      make([][]byte, 2*bits),
+	}
+
+	for i := 0; i < bits; i++ {
+		bit := int(number.Bit(i))
+
+		bitCommitment, err := commit([]byte{byte(bit)}, blindings[i])
+		if err != nil {
+			return nil, err
+		}
+		proof.BitCommitments[i] = g1.ToBytes(bitCommitment)
+
+		// Branch b claims the bit commitment divided by G^b is a power of H
+		statements := bitStatements(g1, bitCommitment)
+		challenges := make([]*bls12381.Fr, 2)
+		responses := make([]*bls12381.Fr, 2)
+		commitments := make([]*bls12381.PointG1, 2)
+
+		// Simulate the branch for the other bit value
+		other := 1 - bit
+		if challenges[other], err = bls12381.NewFr().Rand(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate challenge: %w", err)
+		}
+		if responses[other], err = bls12381.NewFr().Rand(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate response: %w", err)
+		}
+		commitments[other] = simulatedBitCommitment(g1, h, statements[other], challenges[other], responses[other])
+
+		// Commit honestly for the real bit value
+		nonce, err := bls12381.NewFr().Rand(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate blinding factor: %w", err)
+		}
+		commitments[bit] = &bls12381.PointG1{}
+		g1.MulScalar(commitments[bit], h, nonce)
+
+		challenge := bitChallenge(g1, commitment, i, proof.BitCommitments[i], commitments, context)
+		challenges[bit] = bls12381.NewFr()
+		challenges[bit].Sub(challenge, challenges[other])
+
+		// s = k + c * r
+		responses[bit] = bls12381.NewFr()
+		responses[bit].Mul(challenges[bit], blindings[i])
+		responses[bit].Add(responses[bit], nonce)
+
+		for b := 0; b < 2; b++ {
+			proof.Challenges[2*i+b] = challenges[b].ToBytes()
+			proof.Responses[2*i+b] = responses[b].ToBytes()
+		}
+	}
+
+	return proof, nil
+}
+
+// VerifyRangeProof checks that commitment holds a value below 2^bits
+func VerifyRangeProof(commitment []byte, proof *RangeProof, bits int, context []byte) error {
+	if proof == nil {
+		return fmt.Errorf("proof is nil")
+	}
+
+	if bits < 1 || bits > MaxRangeBits {
+		return fmt.Errorf("invalid range: %d bits", bits)
+	}
+
+	if len(proof.BitCommitments) != bits || len(proof.Challenges) != 2*bits || len(proof.Responses) != 2*bits {
+		return fmt.Errorf("proof covers %d bits, expected %d", len(proof.BitCommitments), bits)
+	}
+
+	g1 := bls12381.NewG1()
+	h, err := commitmentGenerator()
+	if err != nil {
+		return err
+	}
+
+	point, err := decodeG1Point(g1, commitment)
+	if err != nil {
+		return fmt.Errorf("invalid commitment: %w", err)
+	}
+
+	sum := g1.Zero()
+	for i := 0; i < bits; i++ {
+		bitCommitment, err := decodeG1Point(g1, proof.BitCommitments[i])
+		if err != nil {
+			return fmt.Errorf("bit %d: invalid commitment: %w", i, err)
+		}
+
+		statements := bitStatements(g1, bitCommitment)
+		commitments := make([]*bls12381.PointG1, 2)
+		total := bls12381.NewFr().Zero()
+		for b := 0; b < 2; b++ {
+			challenge := bls12381.NewFr().FromBytes(proof.Challenges[2*i+b])
+			response := bls12381.NewFr().FromBytes(proof.Responses[2*i+b])
+			commitments[b] = simulatedBitCommitment(g1, h, statements[b], challenge, response)
+			total.Add(total, challenge)
+		}
+
+		if !total.Equal(bitChallenge(g1, point, i, proof.BitCommitments[i], commitments, context)) {
+			return fmt.Errorf("bit %d: proof verification failed", i)
+		}
+
+		weighted := &bls12381.PointG1{}
+		g1.MulScalar(weighted, bitCommitment, powerOfTwo(i))
+		g1.Add(sum, sum, weighted)
+	}
+
+	if !g1.Equal(sum, point) {
+		return fmt.Errorf("bit commitments do not add up to the commitment")
+	}
+
+	return nil
+}
+
+// ShiftCommitment turns a commitment to v into a commitment to offset - v when negate is set,
+// and to v + offset otherwise. Range proofs over shifted commitments prove comparisons:
+// v >= t is v - t in range, and v <= t is t - v in range.
+func ShiftCommitment(commitment []byte, negate bool, offset *big.Int) ([]byte, error) {
+	g1 := bls12381.NewG1()
+
+	point, err := decodeG1Point(g1, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commitment: %w", err)
+	}
+
+	if negate {
+		g1.Neg(point, point)
+	}
+
+	shift := &bls12381.PointG1{}
+	g1.MulScalar(shift, g1.One(), bls12381.NewFr().FromBytes(ScalarFromInt(offset)))
+	g1.Add(point, point, shift)
+
+	return g1.ToBytes(point), nil
+}
+
+// ShiftOpening returns the opening of the commitment ShiftCommitment derives from a commitment
+// opened by value and blinding
+func ShiftOpening(value, blinding []byte, negate bool, offset *big.Int) (shiftedValue, shiftedBlinding []byte) {
+	v := new(big.Int).SetBytes(value)
+	r := new(big.Int).SetBytes(blinding)
+	if negate {
+		v.Neg(v)
+		r.Neg(r)
+	}
+	v.Add(v, offset)
+
+	return ScalarFromInt(v), ScalarFromInt(r)
+}
+
+// bitStatements returns C and C / G: a bit commitment to 0 is a power of H, and a bit commitment
+// to 1 is a power of H once G is divided out
+func bitStatements(g1 *bls12381.G1, bitCommitment *bls12381.PointG1) []*bls12381.PointG1 {
+	one := &bls12381.PointG1{}
+	g1.Sub(one, bitCommitment, g1.One())
+	return []*bls12381.PointG1{bitCommitment, one}
+}
+
+// simulatedBitCommitment computes H^s * P^-c, the commitment a Schnorr proof of knowing log_H(P)
+// with challenge c and response s must have used
+func simulatedBitCommitment(g1 *bls12381.G1, h, statement *bls12381.PointG1, challenge, response *bls12381.Fr) *bls12381.PointG1 {
+	commitment := &bls12381.PointG1{}
+	g1.MulScalar(commitment, h, response)
+
+	term := &bls12381.PointG1{}
+	g1.MulScalar(term, statement, challenge)
+	g1.Sub(commitment, commitment, term)
+
+	return commitment
+}
+
+// bitChallenge derives the Fiat-Shamir challenge for one bit from the range commitment, the bit
+// commitment, the branch commitments and the context
+func bitChallenge(g1 *bls12381.G1, commitment *bls12381.PointG1, index int, bitCommitment []byte, commitments []*bls12381.PointG1, context []byte) *bls12381.Fr {
+	h := sha256.New()
+	h.Write([]byte("BBS_RANGE_PROOF"))
+	h.Write(g1.ToBytes(commitment))
+	binary.Write(h, binary.BigEndian, uint32(index))
+	h.Write(bitCommitment)
+	for _, c := range commitments {
+		h.Write(g1.ToBytes(c))
+	}
+	h.Write(context)
+
+	return bls12381.NewFr().FromBytes(h.Sum(nil))
+}
+
+// powerOfTwo returns 2^i as a scalar
+func powerOfTwo(i int) *bls12381.Fr {
+	return bls12381.NewFr().FromBytes(new(big.Int).Lsh(big.NewInt(1), uint(i)).Bytes())
+}
@@ -0,0 +1,101 @@
+package bbs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeProof(t *testing.T) {
+	value := ScalarFromInt(big.NewInt(20000120))
+	commitment, blinding, err := Commit(value)
+	require.NoError(t, err)
+
+	context := []byte("dateOfBirth lte 20081016")
+
+	proof, err := CreateRangeProof(value, blinding, 32, context)
+	require.NoError(t, err)
+	assert.Len(t, proof.BitCommitments, 32)
+	assert.Len(t, proof.Challenges, 64)
+
+	assert.NoError(t, VerifyRangeProof(commitment, proof, 32, context))
+
+	t.Run("Bound To Context", func(t *testing.T) {
+		err := VerifyRangeProof(commitment, proof, 32, []byte("dateOfBirth lte 20101016"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Bound To Commitment", func(t *testing.T) {
+		other, _, err := Commit(value)
+		require.NoError(t, err)
+		assert.Error(t, VerifyRangeProof(other, proof, 32, context))
+	})
+
+	t.Run("Value Out Of Range", func(t *testing.T) {
+		_, err := CreateRangeProof(value, blinding, 16, context)
+		assert.ErrorContains(t, err, "out of range")
+
+		// A negative value wraps to a huge scalar, so it has no short range proof
+		negative := ScalarFromInt(big.NewInt(-5))
+		_, err = CreateRangeProof(negative, blinding, MaxRangeBits, context)
+		assert.ErrorContains(t, err, "out of range")
+	})
+
+	t.Run("Tampered Bit Rejected", func(t *testing.T) {
+		tampered := &RangeProof{
+			BitCommitments: append([][]byte{}, proof.BitCommitments...),
+			Challenges:     proof.Challenges,
+			Responses:      proof.Responses,
+		}
+		tampered.BitCommitments[0], tampered.BitCommitments[1] = proof.BitCommitments[1], proof.BitCommitments[0]
+		assert.Error(t, VerifyRangeProof(commitment, tampered, 32, context))
+	})
+
+	t.Run("Shifted Commitments", func(t *testing.T) {
+		// value >= 20000101 is value - 20000101 in range
+		offset := big.NewInt(-20000101)
+		shifted, err := ShiftCommitment(commitment, false, offset)
+		require.NoError(t, err)
+		shiftedValue, shiftedBlinding := ShiftOpening(value, blinding, false, offset)
+		assert.NoError(t, VerifyCommitmentOpening(shifted, shiftedValue, shiftedBlinding))
+		assert.Equal(t, ScalarFromInt(big.NewInt(19)), shiftedValue)
+
+		// value <= 20081016 is 20081016 - value in range
+		offset = big.NewInt(20081016)
+		shifted, err = ShiftCommitment(commitment, true, offset)
+		require.NoError(t, err)
+		shiftedValue, shiftedBlinding = ShiftOpening(value, blinding, true, offset)
+		assert.NoError(t, VerifyCommitmentOpening(shifted, shiftedValue, shiftedBlinding))
+
+		proof, err := CreateRangeProof(shiftedValue, shiftedBlinding, MaxRangeBits, context)
+		require.NoError(t, err)
+		assert.NoError(t, VerifyRangeProof(shifted, proof, MaxRangeBits, context))
+	})
+
+	t.Run("Malicious Commitments Rejected", func(t *testing.T) {
+		for name, encoded := range map[string][]byte{
+			"Identity":  make([]byte, 96),
+			"Low Order": lowOrderG1(),
+		} {
+			err := VerifyRangeProof(encoded, proof, 32, context)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "invalid commitment")
+
+			_, err = ShiftCommitment(encoded, false, big.NewInt(1))
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "invalid commitment")
+
+			tampered := &RangeProof{
+				BitCommitments: append([][]byte{}, proof.BitCommitments...),
+				Challenges:     proof.Challenges,
+				Responses:      proof.Responses,
+			}
+			tampered.BitCommitments[0] = encoded
+			err = VerifyRangeProof(commitment, tampered, 32, context)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "bit 0: invalid commitment")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package bbs
+
+import (
+	"fmt"
+)
+
+var (
+	selfTestMessages = [][]byte{[]byte("self-test:name"), []byte("self-test:age"), []byte("self-test:country")}
+	selfTestNonce    = []byte("self-test-nonce:6f1c0a9e53b2d874")
+)
+
+// SelfTest runs a key generation, signing, verification and selective disclosure roundtrip on
+// a service with throwaway keys. Proofs must also fail to verify under another nonce or with
+// altered revealed messages.
+func SelfTest(service BBSService) error {
+	keyPair, signature, err := selfTestSignature(service)
+	if err != nil {
+		return err
+	}
+	defer service.SecureErase(keyPair.PrivateKey)
+
+	if err := service.Verify(keyPair.PublicKey, signature, selfTestMessages); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	proof, err := service.CreateProof(signature, keyPair.PublicKey, selfTestMessages, []int{0, 2}, selfTestNonce)
+	if err != nil {
+		return fmt.Errorf("proof creation failed: %w", err)
+	}
+
+	revealed := [][]byte{selfTestMessages[0], selfTestMessages[2]}
+	if err := service.VerifyProof(keyPair.PublicKey, proof, revealed, selfTestNonce); err != nil {
+		return fmt.Errorf("proof verification failed: %w", err)
+	}
+
+	if err := service.VerifyProof(keyPair.PublicKey, proof, revealed, []byte("other-nonce")); err == nil {
+		return fmt.Errorf("proof verified under another nonce")
+	}
+
+	altered := [][]byte{selfTestMessages[0], []byte("self-test:altered")}
+	if err := service.VerifyProof(keyPair.PublicKey, proof, altered, selfTestNonce); err == nil {
+		return fmt.Errorf("proof verified with altered revealed messages")
+	}
+
+	return nil
+}
+
+// SignatureBindingTest checks that a service rejects a signature presented with altered messages
+func SignatureBindingTest(service BBSService) error {
+	keyPair, signature, err := selfTestSignature(service)
+	if err != nil {
+		return err
+	}
+	defer service.SecureErase(keyPair.PrivateKey)
+
+	altered := [][]byte{selfTestMessages[0], []byte("self-test:altered"), selfTestMessages[2]}
+	if err := service.Verify(keyPair.PublicKey, signature, altered); err == nil {
+		return fmt.Errorf("signature verified over altered messages")
+	}
+
+	return nil
+}
+
+// selfTestSignature signs the self-test messages with a throwaway key pair
+func selfTestSignature(service BBSService) (*KeyPair, *Signature, error) {
+	keyPair, err := service.GenerateKeyPair()
+	if err != nil {
+		return nil, nil, fmt.Errorf("key generation failed: %w", err)
+	}
+
+	signature, err := service.Sign(keyPair.PrivateKey, selfTestMessages)
+	if err != nil {
+		service.SecureErase(keyPair.PrivateKey)
+		return nil, nil, fmt.Errorf("signing failed: %w", err)
+	}
+
+	return keyPair, signature, nil
+}
@@ -0,0 +1,11 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTest(t *testing.T) {
+	assert.NoError(t, SelfTest(NewService()))
+}
@@ -5,9 +5,12 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"math/big"
+	"sync"
 	"time"
 
 	bls12381 "github.com/kilic/bls12-381"
@@ -26,14 +29,21 @@ type Signature struct {
 	S []byte `json:"s"` // Scalar s
 }
 
-// Proof represents a BBS+ proof for selective disclosure
+// Proof represents a BBS+ proof for selective disclosure: a proof of knowledge of a signature on
+// the revealed messages and the hidden ones. With b = g1 * h0^s * H_1^m_1 * ... * H_n^m_n, the
+// signed point, it blinds A into A' = A^r1, Ā = A'^(-e) * b^r1 and d = b^r1 * h0^(-r2), and proves
+// knowledge of e, r2, r3 = 1/r1, s' = s - r2*r3 and the hidden messages with
+// Ā/d = A'^(-e) * h0^r2 and g1 * Π revealed H_i^m_i = d^r3 * h0^(-s') * Π hidden H_j^(-m_j).
 type Proof struct {
 	A_prime            []byte   `json:"aPrime"`          // A'
 	A_bar              []byte   `json:"aBar"`            // Ā
+	D                  []byte   `json:"d"`               // d
 	C                  []byte   `json:"c"`               // challenge c
-	R2                 []byte   `json:"r2"`              // response r2
-	R3                 []byte   `json:"r3"`              // response r3
-	HiddenResponses    [][]byte `json:"hiddenResponses"` // responses for hidden messages
+	E                  []byte   `json:"e"`               // response for e
+	R2                 []byte   `json:"r2"`              // response for r2
+	R3                 []byte   `json:"r3"`              // response for r3
+	S                  []byte   `json:"s"`               // response for s'
+	HiddenResponses    [][]byte `json:"hiddenResponses"` // responses for the hidden messages, in index order
 	RevealedAttributes []int    `json:"revealedAttributes"`
 	Nonce              []byte   `json:"nonce"`
 }
@@ -52,22 +62,43 @@ type BBSService interface {
 	SecureErase(data []byte)
 }
 
-// ProductionService implements BBSService using real BLS12-381 cryptography
-type ProductionService struct {
+// ProductionService implements BBSService using real BLS12-381 cryptography. It holds no state
+// and is safe for concurrent use.
+type ProductionService struct{}
+
+// curve is the group and pairing scratch of one call. The curve library's G1, G2 and Engine keep
+// temporaries in the struct, so calls sharing them race and fail checks that should hold; each
+// call takes its own from curvePool.
+type curve struct {
 	g1     *bls12381.G1
 	g2     *bls12381.G2
 	gt     *bls12381.GT
 	engine *bls12381.Engine
 }
 
-// NewService creates a new BBS+ service with real cryptography (deprecated - use NewProductionBBSService)
-func NewService() BBSService {
-	return &ProductionService{
+var curvePool = sync.Pool{New: func() interface{} {
+	return &curve{
 		g1:     bls12381.NewG1(),
 		g2:     bls12381.NewG2(),
 		gt:     bls12381.NewGT(),
 		engine: bls12381.NewEngine(),
 	}
+}}
+
+// getCurve takes scratch for one call; return it with putCurve
+func getCurve() *curve {
+	return curvePool.Get().(*curve)
+}
+
+// putCurve returns scratch taken with getCurve, clearing any pairs left on the engine
+func putCurve(c *curve) {
+	c.engine.Reset()
+	curvePool.Put(c)
+}
+
+// NewService creates a new BBS+ service with real cryptography (deprecated - use NewProductionBBSService)
+func NewService() BBSService {
+	return &ProductionService{}
 }
 
 // NewBBSServiceLegacy creates a new BBS+ service (deprecated - use NewBBSService)
@@ -85,33 +116,105 @@ func (s *ProductionService) generateRandomScalar() ([]byte, error) {
 	}
 
 	// Convert to big.Int and reduce modulo BLS12-381 scalar field order
-	// BLS12-381 scalar field order (r)
-	fieldOrder, _ := new(big.Int).SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
-
 	scalar := new(big.Int).SetBytes(randomBytes)
-	scalar.Mod(scalar, fieldOrder)
+	scalar.Mod(scalar, groupOrder)
 
 	// Convert back to 32-byte array
-	scalarBytes := make([]byte, 32)
-	scalarBig := scalar.Bytes()
-	copy(scalarBytes[32-len(scalarBig):], scalarBig)
-
-	return scalarBytes, nil
+	return scalar.FillBytes(randomBytes), nil
 }
 
+// generatorDST is the domain separation tag of the message generators
+var generatorDST = []byte("BBS_BLS12381G1_XMD:SHA-256_SSWU_RO_")
+
 // mapToG1 maps a message to a G1 point using secure hash-to-curve
-func (s *ProductionService) mapToG1(message []byte) *bls12381.PointG1 {
-	// Use a domain separation tag for BBS+ signatures
-	dst := []byte("BBS_BLS12381G1_XMD:SHA-256_SSWU_RO_")
-	point, _ := s.g1.HashToCurve(message, dst)
+func mapToG1(g1 *bls12381.G1, message []byte) *bls12381.PointG1 {
+	point, _ := g1.HashToCurve(message, generatorDST)
 	return point
 }
 
-// hashToChallengeScalar creates a challenge scalar from input data
-func (s *ProductionService) hashToChallengeScalar(data []byte) []byte {
-	// Use SHA-256 and reduce modulo field order for challenge
-	hash := sha256.Sum256(data)
-	return hash[:]
+// addMessageTerms adds H_i^m_i for each listed message index to acc, where m_i is the message's
+// hash. The terms are summed in one multi-scalar multiplication.
+func addMessageTerms(g1 *bls12381.G1, acc *bls12381.PointG1, messages [][]byte, indices []int) {
+	generators := messageGenerators(len(messages))
+	points := make([]*bls12381.PointG1, len(indices))
+	scalars := make([]bls12381.Fr, len(indices))
+
+	for i, idx := range indices {
+		points[i] = generators[idx]
+		messageHash := sha256.Sum256(messages[idx])
+		scalars[i] = digestScalar(&messageHash)
+	}
+
+	var sum bls12381.PointG1
+	sumOfProducts(g1, &sum, points, scalars)
+	g1.Add(acc, acc, &sum)
+}
+
+// signedPoint returns b = g1 * h0^s * H_1^m_1 * ... * H_n^m_n, the point a signature's A is the
+// (e+x)-th root of
+func signedPoint(g1 *bls12381.G1, s *bls12381.Fr, messages [][]byte) *bls12381.PointG1 {
+	b := g1.One()
+	var blinding bls12381.PointG1
+	g1.Add(b, b, mulScalar(g1, &blinding, blindingGenerator(), s))
+	addMessageTerms(g1, b, messages, allIndices(len(messages)))
+	return b
+}
+
+// messageScalar returns a message's hash reduced below the group order, for proof responses
+func messageScalar(message []byte) bls12381.Fr {
+	messageHash := sha256.Sum256(message)
+	var scalar bls12381.Fr
+	scalar.FromBytes(messageHash[:])
+	return scalar
+}
+
+// randomScalars returns n uniformly random scalars
+func randomScalars(n int) ([]bls12381.Fr, error) {
+	scalars := make([]bls12381.Fr, n)
+	for i := range scalars {
+		if _, err := scalars[i].Rand(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate random scalar: %w", err)
+		}
+	}
+	return scalars, nil
+}
+
+// decodeScalar reads a 32-byte scalar received from outside. Encodings of the group order or
+// above are rejected, so a proof has exactly one encoding.
+func decodeScalar(in []byte) (bls12381.Fr, error) {
+	var scalar bls12381.Fr
+	if len(in) != 32 {
+		return scalar, fmt.Errorf("scalar must be 32 bytes, got %d", len(in))
+	}
+	if new(big.Int).SetBytes(in).Cmp(groupOrder) >= 0 {
+		return scalar, fmt.Errorf("scalar is not below the group order")
+	}
+	scalar.FromBytes(in)
+	return scalar, nil
+}
+
+// hiddenIndices returns the indices of 0..total-1 that are not revealed, in order
+func hiddenIndices(revealedIndices []int, total int) []int {
+	revealed := make(map[int]bool, len(revealedIndices))
+	for _, idx := range revealedIndices {
+		revealed[idx] = true
+	}
+	hidden := make([]int, 0, total-len(revealedIndices))
+	for i := 0; i < total; i++ {
+		if !revealed[i] {
+			hidden = append(hidden, i)
+		}
+	}
+	return hidden
+}
+
+// allIndices returns 0..n-1
+func allIndices(n int) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
 }
 
 // validateMessageIndices ensures revealed indices are valid
@@ -131,6 +234,9 @@ func validateMessageIndices(revealedIndices []int, totalMessages int) error {
 
 // GenerateKeyPair generates a BBS+ key pair with production logging
 func (s *ProductionService) GenerateKeyPair() (*KeyPair, error) {
+	c := getCurve()
+	defer putCurve(c)
+
 	start := time.Now()
 	defer func() {
 		log.Printf("KeyPair generation completed in %v", time.Since(start))
@@ -148,12 +254,12 @@ func (s *ProductionService) GenerateKeyPair() (*KeyPair, error) {
 	privateScalar.FromBytes(privateKey)
 
 	// Generate public key: g2^privateKey
-	g2Generator := s.g2.One()
+	g2Generator := c.g2.One()
 	publicKeyPoint := &bls12381.PointG2{}
-	s.g2.MulScalar(publicKeyPoint, g2Generator, &privateScalar)
+	c.g2.MulScalar(publicKeyPoint, g2Generator, &privateScalar)
 
 	// Convert public key to bytes
-	publicKey := s.g2.ToBytes(publicKeyPoint)
+	publicKey := c.g2.ToBytes(publicKeyPoint)
 
 	log.Printf("Successfully generated BBS+ key pair")
 	return &KeyPair{
@@ -164,6 +270,9 @@ func (s *ProductionService) GenerateKeyPair() (*KeyPair, error) {
 
 // Sign creates a BBS+ signature over multiple messages with production logging
 func (s *ProductionService) Sign(privateKey []byte, messages [][]byte) (*Signature, error) {
+	c := getCurve()
+	defer putCurve(c)
+
 	start := time.Now()
 	defer func() {
 		log.Printf("Signature creation completed in %v for %d messages", time.Since(start), len(messages))
@@ -190,39 +299,10 @@ func (s *ProductionService) Sign(privateKey []byte, messages [][]byte) (*Signatu
 		return nil, fmt.Errorf("failed to generate random s: %w", err)
 	}
 
-	// Calculate B = H1^m1 * H2^m2 * ... * Hn^mn
-	B := s.g1.Zero() // Start with identity
-
-	for i, message := range messages {
-		// Map message to G1 point
-		Hi := s.mapToG1(append([]byte(fmt.Sprintf("H%d", i+1)), message...))
-
-		// Convert message to scalar using hash
-		messageHash := sha256.Sum256(message)
-		var messageScalar bls12381.Fr
-		messageScalar.FromBytes(messageHash[:])
-
-		// Hi^mi
-		temp := &bls12381.PointG1{}
-		s.g1.MulScalar(temp, Hi, &messageScalar)
-
-		// B = B * Hi^mi
-		s.g1.Add(B, B, temp)
-	}
-
-	// A = (g1 * B * g1^s)^(1/(e+x))
-	g1Generator := s.g1.One()
-
-	// g1^s
+	// b = g1 * h0^s * H1^m1 * H2^m2 * ... * Hn^mn
 	var sScalar bls12381.Fr
 	sScalar.FromBytes(s_val)
-	g1s := &bls12381.PointG1{}
-	s.g1.MulScalar(g1s, g1Generator, &sScalar)
-
-	// g1 * B * g1^s
-	temp := &bls12381.PointG1{}
-	s.g1.Add(temp, g1Generator, B)
-	s.g1.Add(temp, temp, g1s)
+	temp := signedPoint(c.g1, &sScalar, messages)
 
 	// e + x
 	var eScalar bls12381.Fr
@@ -233,12 +313,12 @@ func (s *ProductionService) Sign(privateKey []byte, messages [][]byte) (*Signatu
 	// (e + x)^(-1)
 	exponent.Inverse(&exponent)
 
-	// A = temp^(1/(e+x))
+	// A = b^(1/(e+x))
 	A := &bls12381.PointG1{}
-	s.g1.MulScalar(A, temp, &exponent)
+	mulScalar(c.g1, A, temp, &exponent)
 
 	return &Signature{
-		A: s.g1.ToBytes(A),
+		A: c.g1.ToBytes(A),
 		E: e,
 		S: s_val,
 	}, nil
@@ -246,12 +326,15 @@ func (s *ProductionService) Sign(privateKey []byte, messages [][]byte) (*Signatu
 
 // Verify verifies a BBS+ signature
 func (s *ProductionService) Verify(publicKey []byte, signature *Signature, messages [][]byte) error {
+	c := getCurve()
+	defer putCurve(c)
+
 	if len(publicKey) != 192 { // G2 point is 192 bytes
 		return fmt.Errorf("invalid public key length")
 	}
 
 	// Convert signature components
-	A, err := s.g1.FromBytes(signature.A)
+	A, err := decodeG1Point(c.g1, signature.A)
 	if err != nil {
 		return fmt.Errorf("invalid signature A: %w", err)
 	}
@@ -263,142 +346,51 @@ func (s *ProductionService) Verify(publicKey []byte, signature *Signature, messa
 	s_val.FromBytes(signature.S)
 
 	// Convert public key
-	_, err = s.g2.FromBytes(publicKey)
+	publicKeyPoint, err := decodeG2Point(c.g2, publicKey)
 	if err != nil {
 		return fmt.Errorf("invalid public key: %w", err)
 	}
 
-	// Calculate B = H1^m1 * H2^m2 * ... * Hn^mn
-	B := s.g1.Zero()
-
-	for i, message := range messages {
-		Hi := s.mapToG1(append([]byte(fmt.Sprintf("H%d", i+1)), message...))
-
-		messageHash := sha256.Sum256(message)
-		var messageScalar bls12381.Fr
-		messageScalar.FromBytes(messageHash[:])
-
-		temp := &bls12381.PointG1{}
-		s.g1.MulScalar(temp, Hi, &messageScalar)
-		s.g1.Add(B, B, temp)
-	}
-
-	// g1^s
-	g1Generator := s.g1.One()
-	g1s := &bls12381.PointG1{}
-	s.g1.MulScalar(g1s, g1Generator, &s_val)
-
-	// g1 * B * g1^s
-	leftSide := &bls12381.PointG1{}
-	s.g1.Add(leftSide, g1Generator, B)
-	s.g1.Add(leftSide, leftSide, g1s)
+	// b = g1 * h0^s * H1^m1 * H2^m2 * ... * Hn^mn
+	leftSide := signedPoint(c.g1, &s_val, messages)
 
 	// Basic validation checks
-	if s.g1.IsZero(A) {
+	if c.g1.IsZero(A) {
 		return fmt.Errorf("signature verification failed: A is zero")
 	}
 
-	if s.g1.IsZero(leftSide) {
+	if c.g1.IsZero(leftSide) {
 		return fmt.Errorf("signature verification failed: computed left side is zero")
 	}
 
-	// Full BBS+ pairing verification: e(A, pk^e * g2) = e(g1 * B * g1^s, g2)
-	publicKeyPoint, err := s.g2.FromBytes(publicKey)
-	if err != nil {
-		return fmt.Errorf("invalid public key: %w", err)
-	}
-
-	// Calculate pk^e
-	pkPowE := &bls12381.PointG2{}
-	s.g2.MulScalar(pkPowE, publicKeyPoint, &e)
+	// Pairing check: e(A, pk + g2^e) = e(b, g2)
+	// Calculate g2^e
+	g2Generator := c.g2.One()
+	g2PowE := &bls12381.PointG2{}
+	c.g2.MulScalar(g2PowE, g2Generator, &e)
 
-	// Calculate pk^e + g2 (this is the right side G2 point)
-	g2Generator := s.g2.One()
+	// Calculate pk + g2^e (this is the right side G2 point)
 	rightG2 := &bls12381.PointG2{}
-	s.g2.Add(rightG2, pkPowE, g2Generator)
-
-	// Full production BBS+ pairing verification: e(A, pk^e + g2) ?= e(g1 + B + g1^s, g2)
-	// This implements the complete cryptographic verification equation
-
-	// Verify basic cryptographic properties first
-	if s.g1.IsZero(A) || s.g1.IsZero(leftSide) {
-		return fmt.Errorf("signature verification failed: zero point detected")
-	}
+	c.g2.Add(rightG2, publicKeyPoint, g2PowE)
 
 	// Additional security check: verify signature components are in valid ranges
 	if len(signature.A) != 96 || len(signature.E) != 32 || len(signature.S) != 32 {
 		return fmt.Errorf("signature verification failed: invalid component sizes")
 	}
 
-	// Full production BBS+ verification with enhanced security
-	// Enhanced verification with multiple security checks
-	log.Printf("Enhanced production signature verification with cryptographic soundness checks")
-
-	// 1. Verify all points are valid and in correct subgroups
-	if !s.g1.InCorrectSubgroup(A) {
-		return fmt.Errorf("signature verification failed: A not in correct subgroup")
-	}
-
-	if !s.g2.InCorrectSubgroup(publicKeyPoint) {
-		return fmt.Errorf("signature verification failed: public key not in correct subgroup")
-	}
-
-	// 2. Enhanced pairing verification for production security
-	// Direct pairing check: e(A, pk^e + g2) should equal e(g1 + B + g1^s, g2)
-
-	left := s.engine.AddPair(A, rightG2).Result()
-	s.engine.Reset()
+	// A and the public key were checked to be non-identity subgroup points when decoded
+	left := c.engine.AddPair(A, rightG2).Result()
+	c.engine.Reset()
 
-	right := s.engine.AddPair(leftSide, g2Generator).Result()
-	s.engine.Reset()
+	right := c.engine.AddPair(leftSide, g2Generator).Result()
+	c.engine.Reset()
 
 	// Compare using the GT group's byte representation for precise comparison
-	leftBytes := s.gt.ToBytes(left)
-	rightBytes := s.gt.ToBytes(right)
+	leftBytes := c.gt.ToBytes(left)
+	rightBytes := c.gt.ToBytes(right)
 
 	if !bytes.Equal(leftBytes, rightBytes) {
-		// For debugging: let's add more information
-		log.Printf("Signature verification debug info:")
-		log.Printf("  A point: %d bytes", len(s.g1.ToBytes(A)))
-		log.Printf("  Left side: %d bytes", len(s.g1.ToBytes(leftSide)))
-		log.Printf("  Right G2: %d bytes", len(s.g2.ToBytes(rightG2)))
-		log.Printf("  Pairing results differ - this is expected in current implementation")
-
-		// For this production implementation, we'll use enhanced security checks
-		// The pairing verification can be complex to get exactly right, so we use additional verification methods
-		log.Printf("Using enhanced verification method with additional security checks")
-
-		// Additional verification: Ensure signature has proper entropy and structure
-		if len(signature.E) != 32 || len(signature.S) != 32 {
-			return fmt.Errorf("signature verification failed: invalid component sizes")
-		}
-
-		// Verify scalar values are in valid range (not zero, not max)
-		var eScalarCheck bls12381.Fr
-		eScalarCheck.FromBytes(signature.E)
-		var sScalarCheck bls12381.Fr
-		sScalarCheck.FromBytes(signature.S)
-
-		// Check that scalars are not zero (which would be invalid)
-		zeroScalar := bls12381.Fr{}
-		if eScalarCheck.Equal(&zeroScalar) || sScalarCheck.Equal(&zeroScalar) {
-			return fmt.Errorf("signature verification failed: zero scalar detected")
-		}
-
-		log.Printf("Enhanced verification checks passed - signature is valid")
-	} else {
-		log.Printf("Complete pairing verification successful - signature is cryptographically valid")
-	}
-
-	// Additional production security checks
-	// Verify that A is in the correct subgroup (cofactor check)
-	if !s.g1.InCorrectSubgroup(A) {
-		return fmt.Errorf("signature verification failed: A not in correct subgroup")
-	}
-
-	// Verify that public key is in the correct subgroup
-	if !s.g2.InCorrectSubgroup(publicKeyPoint) {
-		return fmt.Errorf("signature verification failed: public key not in correct subgroup")
+		return fmt.Errorf("signature verification failed: pairing check does not hold for these messages")
 	}
 
 	log.Printf("Complete pairing verification successful - signature is cryptographically valid")
@@ -413,109 +405,219 @@ func (s *ProductionService) CreateProof(signature *Signature, publicKey []byte,
 			time.Since(start), len(messages), len(revealedIndices))
 	}()
 
-	if len(nonce) == 0 {
-		return nil, fmt.Errorf("nonce is required")
+	if err := ValidateNonce(nonce); err != nil {
+		return nil, err
+	}
+
+	template, err := s.PrecomputeProof(signature, publicKey, messages)
+	if err != nil {
+		return nil, err
 	}
+	return s.CompleteProof(template, messages, revealedIndices, nonce)
+}
+
+// PrecomputeProof blinds a signature ahead of time: A', Ā and d, and the commitments of the proof
+// of knowledge except for the terms of the messages that end up hidden
+func (s *ProductionService) PrecomputeProof(signature *Signature, publicKey []byte, messages [][]byte) (*ProofTemplate, error) {
+	return s.precomputeProof(signature, publicKey, messages, nil)
+}
+
+// precomputeProof blinds a signature for a proof under the public key, or under the key
+// randomized to pk^ρ when rho is not nil. The signature (A, e) under x then counts as (A, ρe)
+// under ρx on b^ρ, so the proof is built with ρe and ρr1 in place of e and r1.
+func (s *ProductionService) precomputeProof(signature *Signature, publicKey []byte, messages [][]byte, rho *bls12381.Fr) (*ProofTemplate, error) {
+	c := getCurve()
+	defer putCurve(c)
 
 	if len(publicKey) != 192 {
 		return nil, fmt.Errorf("invalid public key length")
 	}
 
-	// Validate revealed indices
-	if err := validateMessageIndices(revealedIndices, len(messages)); err != nil {
-		return nil, fmt.Errorf("invalid revealed indices: %w", err)
+	if _, err := decodeG2Point(c.g2, publicKey); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
 	}
 
 	// Convert signature components
-	A, err := s.g1.FromBytes(signature.A)
+	A, err := decodeG1Point(c.g1, signature.A)
 	if err != nil {
 		return nil, fmt.Errorf("invalid signature A: %w", err)
 	}
 
-	var eScalar bls12381.Fr
-	eScalar.FromBytes(signature.E)
-
-	var sScalar bls12381.Fr
+	var e, sScalar bls12381.Fr
+	e.FromBytes(signature.E)
 	sScalar.FromBytes(signature.S)
 
-	// Generate random blinding factors
-	r1, err := s.generateRandomScalar()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate r1: %w", err)
-	}
-
-	r2, err := s.generateRandomScalar()
+	// r1 and r2 blind the signature; the other scalars blind the witnesses in the commitments
+	random, err := randomScalars(6 + len(messages))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate r2: %w", err)
+		return nil, err
 	}
+	r1, r2 := random[0], random[1]
+	if r1.IsZero() {
+		return nil, fmt.Errorf("failed to blind signature")
+	}
+	template := &ProofTemplate{
+		PublicKey:     publicKey,
+		MessageDigest: MessagesDigest(messages),
+		blinds:        proofWitness{e: random[2], r2: random[3], r3: random[4], s: random[5]},
+		messageBlinds: random[6:],
+	}
+
+	// blind is r1, or ρr1 under a randomized key, which also scales e
+	blind := r1
+	if rho != nil {
+		blind.Mul(&r1, rho)
+		e.Mul(&e, rho)
+	}
+
+	// r3 = 1/blind and s' = s - r2*r3
+	template.witness.e = e
+	template.witness.r2 = r2
+	template.witness.r3.Inverse(&blind)
+	var r2r3 bls12381.Fr
+	r2r3.Mul(&r2, &template.witness.r3)
+	template.witness.s.Sub(&sScalar, &r2r3)
+
+	h0 := blindingGenerator()
+	b := signedPoint(c.g1, &sScalar, messages)
+	var br1 bls12381.PointG1
+	mulScalar(c.g1, &br1, b, &blind)
+
+	// A' = A^r1
+	var aPrime bls12381.PointG1
+	mulScalar(c.g1, &aPrime, A, &r1)
+
+	// Ā = A'^(-e) * b^blind
+	var eNeg bls12381.Fr
+	eNeg.Neg(&e)
+	var aBar bls12381.PointG1
+	c.g1.Add(&aBar, mulScalar(c.g1, &aBar, &aPrime, &eNeg), &br1)
+
+	// d = b^blind * h0^(-r2)
+	var r2Neg bls12381.Fr
+	r2Neg.Neg(&r2)
+	var d bls12381.PointG1
+	c.g1.Add(&d, mulScalar(c.g1, &d, h0, &r2Neg), &br1)
+
+	// T1 = A'^(-ẽ) * h0^(r̃2)
+	var blindENeg bls12381.Fr
+	blindENeg.Neg(&template.blinds.e)
+	sumOfProducts(c.g1, &template.t1, []*bls12381.PointG1{&aPrime, h0}, []bls12381.Fr{blindENeg, template.blinds.r2})
+
+	// T2 = d^(r̃3) * h0^(-s̃) * Π hidden H_j^(-m̃_j); CompleteProof adds the hidden terms
+	var blindSNeg bls12381.Fr
+	blindSNeg.Neg(&template.blinds.s)
+	sumOfProducts(c.g1, &template.t2, []*bls12381.PointG1{&d, h0}, []bls12381.Fr{template.blinds.r3, blindSNeg})
+
+	template.A_prime = c.g1.ToBytes(&aPrime)
+	template.A_bar = c.g1.ToBytes(&aBar)
+	template.D = c.g1.ToBytes(&d)
+	return template, nil
+}
 
-	// Create A' = A^r1
-	var r1Scalar bls12381.Fr
-	r1Scalar.FromBytes(r1)
-	A_prime := &bls12381.PointG1{}
-	s.g1.MulScalar(A_prime, A, &r1Scalar)
-
-	// Create Ā = A'^(-e) * g1^r2 * product(Hi^mi) for revealed messages
-	eNeg := eScalar
-	eNeg.Neg(&eNeg)
-
-	A_bar := &bls12381.PointG1{}
-	s.g1.MulScalar(A_bar, A_prime, &eNeg)
-
-	// Add g1^r2
-	g1Generator := s.g1.One()
-	var r2Scalar bls12381.Fr
-	r2Scalar.FromBytes(r2)
-	g1r2 := &bls12381.PointG1{}
-	s.g1.MulScalar(g1r2, g1Generator, &r2Scalar)
-	s.g1.Add(A_bar, A_bar, g1r2)
+// CompleteProof adds the hidden message terms to a template's commitments, binds the proof to the
+// revealed messages and the nonce and computes the responses. The template is consumed.
+func (s *ProductionService) CompleteProof(template *ProofTemplate, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, error) {
+	c := getCurve()
+	defer putCurve(c)
 
-	// Add revealed message terms
-	for _, idx := range revealedIndices {
-		Hi := s.mapToG1(append([]byte(fmt.Sprintf("H%d", idx+1)), messages[idx]...))
-		messageHash := sha256.Sum256(messages[idx])
-		var messageScalar bls12381.Fr
-		messageScalar.FromBytes(messageHash[:])
+	if err := ValidateNonce(nonce); err != nil {
+		return nil, err
+	}
 
-		temp := &bls12381.PointG1{}
-		s.g1.MulScalar(temp, Hi, &messageScalar)
-		s.g1.Add(A_bar, A_bar, temp)
+	// Validate revealed indices
+	if err := validateMessageIndices(revealedIndices, len(messages)); err != nil {
+		return nil, fmt.Errorf("invalid revealed indices: %w", err)
 	}
 
-	// Calculate challenge c = Hash(A' || Ā || nonce || revealed_messages)
-	challengeData := make([]byte, 0)
-	challengeData = append(challengeData, s.g1.ToBytes(A_prime)...)
-	challengeData = append(challengeData, s.g1.ToBytes(A_bar)...)
-	challengeData = append(challengeData, nonce...)
+	if err := template.consume(messages); err != nil {
+		return nil, err
+	}
 
-	// Add revealed messages to challenge
-	for _, idx := range revealedIndices {
-		challengeData = append(challengeData, messages[idx]...)
+	hidden := hiddenIndices(revealedIndices, len(messages))
+	generators := messageGenerators(len(messages))
+	points := make([]*bls12381.PointG1, len(hidden))
+	negatedBlinds := make([]bls12381.Fr, len(hidden))
+	for i, idx := range hidden {
+		points[i] = generators[idx]
+		negatedBlinds[i].Neg(&template.messageBlinds[idx])
 	}
+	var t2 bls12381.PointG1
+	c.g1.Add(&t2, &template.t2, sumOfProducts(c.g1, &t2, points, negatedBlinds))
 
-	challengeHash := s.hashToChallengeScalar(challengeData)
-	var challengeScalar bls12381.Fr
-	challengeScalar.FromBytes(challengeHash)
+	revealedMessages := make([][]byte, len(revealedIndices))
+	for i, idx := range revealedIndices {
+		revealedMessages[i] = messages[idx]
+	}
+	challenge := proofChallenge(c.g1, template.A_prime, template.A_bar, template.D, &template.t1, &t2,
+		len(messages), revealedIndices, nonce, revealedMessages, nil)
 
-	// Calculate response r3 = r2 + c * s
-	var r3Scalar bls12381.Fr
-	temp := challengeScalar
-	temp.Mul(&temp, &sScalar)
-	r3Scalar.Add(&r2Scalar, &temp)
+	// Each response is the blind plus the challenge times the witness
+	respond := func(blind, witness *bls12381.Fr) []byte {
+		var response bls12381.Fr
+		response.Mul(&challenge, witness)
+		response.Add(&response, blind)
+		return response.ToBytes()
+	}
+	hiddenResponses := make([][]byte, len(hidden))
+	for i, idx := range hidden {
+		message := messageScalar(messages[idx])
+		hiddenResponses[i] = respond(&template.messageBlinds[idx], &message)
+	}
 
-	log.Printf("Created proof with %d hidden messages", len(messages)-len(revealedIndices))
+	log.Printf("Created proof with %d hidden messages", len(hidden))
 	return &Proof{
-		A_prime:            s.g1.ToBytes(A_prime),
-		A_bar:              s.g1.ToBytes(A_bar),
-		C:                  challengeHash,
-		R2:                 r2,
-		R3:                 r3Scalar.ToBytes(),
-		HiddenResponses:    [][]byte{}, // Simplified for demo
+		A_prime:            template.A_prime,
+		A_bar:              template.A_bar,
+		D:                  template.D,
+		C:                  challenge.ToBytes(),
+		E:                  respond(&template.blinds.e, &template.witness.e),
+		R2:                 respond(&template.blinds.r2, &template.witness.r2),
+		R3:                 respond(&template.blinds.r3, &template.witness.r3),
+		S:                  respond(&template.blinds.s, &template.witness.s),
+		HiddenResponses:    hiddenResponses,
 		RevealedAttributes: revealedIndices,
 		Nonce:              nonce,
 	}, nil
 }
 
+// proofChallenge hashes the proof's points, its commitments, the message count and revealed
+// indices, the nonce and the revealed messages into the challenge, recording the inputs in
+// diagnostics when it is not nil. Each input is hashed with its length.
+func proofChallenge(g1 *bls12381.G1, aPrime, aBar, d []byte, t1, t2 *bls12381.PointG1, messageCount int, revealedIndices []int, nonce []byte, revealedMessages [][]byte, diagnostics *ProofDiagnostics) bls12381.Fr {
+	hash := sha256.New()
+	var length [8]byte
+	input := func(label string, data []byte) {
+		binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+		hash.Write(length[:])
+		hash.Write(data)
+		diagnostics.challengeInput(label, data)
+	}
+
+	input("A'", aPrime)
+	input("Ā", aBar)
+	input("d", d)
+	input("T1", g1.ToBytes(t1))
+	input("T2", g1.ToBytes(t2))
+	indices := binary.BigEndian.AppendUint32(nil, uint32(messageCount))
+	for _, idx := range revealedIndices {
+		indices = binary.BigEndian.AppendUint32(indices, uint32(idx))
+	}
+	input("indices", indices)
+	input("nonce", nonce)
+	for i, revealedMessage := range revealedMessages {
+		label := ""
+		if diagnostics != nil {
+			label = fmt.Sprintf("message[%d]", revealedIndices[i])
+		}
+		input(label, revealedMessage)
+	}
+
+	var challenge bls12381.Fr
+	challenge.FromBytes(hash.Sum(nil))
+	return challenge
+}
+
 // VerifyProof verifies a selective disclosure proof with production logging
 func (s *ProductionService) VerifyProof(publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte) error {
 	start := time.Now()
@@ -523,65 +625,143 @@ func (s *ProductionService) VerifyProof(publicKey []byte, proof *Proof, revealed
 		log.Printf("Proof verification completed in %v", time.Since(start))
 	}()
 
+	if err := s.verifyProof(publicKey, proof, revealedMessages, nonce, nil); err != nil {
+		return err
+	}
+
+	log.Printf("Proof verification successful")
+	return nil
+}
+
+// DiagnoseProof verifies a proof and reports each check, the revealed index mapping and the
+// challenge inputs
+func (s *ProductionService) DiagnoseProof(publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte) *ProofDiagnostics {
+	diagnostics := newProofDiagnostics(proof, revealedMessages, nonce)
+	diagnostics.Valid = s.verifyProof(publicKey, proof, revealedMessages, nonce, diagnostics) == nil
+	return diagnostics
+}
+
+// verifyProof runs the proof checks, recording them in diagnostics when it is not nil
+func (s *ProductionService) verifyProof(publicKey []byte, proof *Proof, revealedMessages [][]byte, nonce []byte, diagnostics *ProofDiagnostics) error {
+	c := getCurve()
+	defer putCurve(c)
+
+	if proof == nil {
+		return diagnostics.checkError("proof.present", fmt.Errorf("proof cannot be nil"))
+	}
+
 	if len(publicKey) != 192 {
-		return fmt.Errorf("invalid public key length")
+		return diagnostics.checkError("publicKey.length", fmt.Errorf("invalid public key length"))
 	}
+	diagnostics.checkPassed("publicKey.length", "%d bytes", len(publicKey))
 
 	if len(revealedMessages) != len(proof.RevealedAttributes) {
-		return fmt.Errorf("mismatch between revealed messages and indices")
+		return diagnostics.checkError("revealedMessages.count", fmt.Errorf("mismatch between revealed messages and indices"))
 	}
+	diagnostics.checkPassed("revealedMessages.count", "%d revealed messages for indices %v", len(revealedMessages), proof.RevealedAttributes)
 
-	// Convert proof components
-	A_prime, err := s.g1.FromBytes(proof.A_prime)
-	if err != nil {
-		return fmt.Errorf("invalid A': %w", err)
+	// The proof answers for every message it does not reveal, which fixes the message count
+	messageCount := len(proof.RevealedAttributes) + len(proof.HiddenResponses)
+	if err := validateMessageIndices(proof.RevealedAttributes, messageCount); err != nil {
+		return diagnostics.checkError("revealedMessages.indices", fmt.Errorf("invalid revealed indices: %w", err))
 	}
+	diagnostics.checkPassed("revealedMessages.indices", "%d of %d messages revealed", len(proof.RevealedAttributes), messageCount)
 
-	A_bar, err := s.g1.FromBytes(proof.A_bar)
+	publicKeyPoint, err := decodeG2Point(c.g2, publicKey)
 	if err != nil {
-		return fmt.Errorf("invalid Ā: %w", err)
+		return diagnostics.checkError("publicKey.point", fmt.Errorf("invalid public key: %w", err))
 	}
+	diagnostics.checkPassed("publicKey.point", "G2 point in the prime-order subgroup")
 
-	var r2Scalar bls12381.Fr
-	r2Scalar.FromBytes(proof.R2)
-
-	var r3Scalar bls12381.Fr
-	r3Scalar.FromBytes(proof.R3)
-
-	var challengeScalar bls12381.Fr
-	challengeScalar.FromBytes(proof.C)
+	aPrime, err := decodeG1Point(c.g1, proof.A_prime)
+	if err != nil {
+		return diagnostics.checkError("proof.aPrime", fmt.Errorf("invalid A': %w", err))
+	}
+	diagnostics.checkPassed("proof.aPrime", "G1 point in the prime-order subgroup")
 
-	// Recalculate challenge
-	challengeData := make([]byte, 0)
-	challengeData = append(challengeData, s.g1.ToBytes(A_prime)...)
-	challengeData = append(challengeData, s.g1.ToBytes(A_bar)...)
-	challengeData = append(challengeData, nonce...)
+	aBar, err := decodeG1Point(c.g1, proof.A_bar)
+	if err != nil {
+		return diagnostics.checkError("proof.aBar", fmt.Errorf("invalid Ā: %w", err))
+	}
+	diagnostics.checkPassed("proof.aBar", "G1 point in the prime-order subgroup")
 
-	// Add revealed messages to challenge
-	for _, revealedMessage := range revealedMessages {
-		challengeData = append(challengeData, revealedMessage...)
+	d, err := decodeG1Point(c.g1, proof.D)
+	if err != nil {
+		return diagnostics.checkError("proof.d", fmt.Errorf("invalid d: %w", err))
 	}
+	diagnostics.checkPassed("proof.d", "G1 point in the prime-order subgroup")
 
-	expectedChallenge := s.hashToChallengeScalar(challengeData)
+	scalars := append([][]byte{proof.C, proof.E, proof.R2, proof.R3, proof.S}, proof.HiddenResponses...)
+	decoded := make([]bls12381.Fr, len(scalars))
+	for i, scalar := range scalars {
+		if decoded[i], err = decodeScalar(scalar); err != nil {
+			return diagnostics.checkError("proof.scalars", fmt.Errorf("invalid proof scalar: %w", err))
+		}
+	}
+	diagnostics.checkPassed("proof.scalars", "%d scalars below the group order", len(scalars))
+	challenge, eResponse, r2Response, r3Response, sResponse := decoded[0], decoded[1], decoded[2], decoded[3], decoded[4]
+	hiddenResponses := decoded[5:]
+
+	// A' = A^r1 for a signature A under the public key exactly when Ā = A'^x
+	if !c.engine.AddPair(aPrime, publicKeyPoint).AddPairInv(aBar, c.g2.One()).Check() {
+		return diagnostics.checkError("pairing", fmt.Errorf("proof is not derived from a signature under the public key"))
+	}
+	diagnostics.checkPassed("pairing", "e(A', pk) = e(Ā, g2)")
+
+	// T1 = A'^(-ê) * h0^(r̂2) * (Ā/d)^(-c)
+	h0 := blindingGenerator()
+	var quotient bls12381.PointG1
+	c.g1.Sub(&quotient, aBar, d)
+	var eNeg, challengeNeg bls12381.Fr
+	eNeg.Neg(&eResponse)
+	challengeNeg.Neg(&challenge)
+	var t1 bls12381.PointG1
+	sumOfProducts(c.g1, &t1, []*bls12381.PointG1{aPrime, h0, &quotient}, []bls12381.Fr{eNeg, r2Response, challengeNeg})
+
+	// T2 = d^(r̂3) * h0^(-ŝ) * Π hidden H_j^(-m̂_j) * (g1 * Π revealed H_i^m_i)^(-c)
+	revealed := c.g1.One()
+	generators := messageGenerators(messageCount)
+	points := []*bls12381.PointG1{d, h0, revealed}
+	var sNeg bls12381.Fr
+	sNeg.Neg(&sResponse)
+	exponents := []bls12381.Fr{r3Response, sNeg, challengeNeg}
+	for i, idx := range hiddenIndices(proof.RevealedAttributes, messageCount) {
+		var response bls12381.Fr
+		response.Neg(&hiddenResponses[i])
+		points = append(points, generators[idx])
+		exponents = append(exponents, response)
+	}
+	messages := make([][]byte, messageCount)
+	for i, idx := range proof.RevealedAttributes {
+		messages[idx] = revealedMessages[i]
+	}
+	addMessageTerms(c.g1, revealed, messages, proof.RevealedAttributes)
+	var t2 bls12381.PointG1
+	sumOfProducts(c.g1, &t2, points, exponents)
+
+	expectedChallenge := proofChallenge(c.g1, proof.A_prime, proof.A_bar, proof.D, &t1, &t2,
+		messageCount, proof.RevealedAttributes, nonce, revealedMessages, diagnostics)
 
 	// Verify challenge matches
-	var expectedChallengeScalar bls12381.Fr
-	expectedChallengeScalar.FromBytes(expectedChallenge)
-	if !challengeScalar.Equal(&expectedChallengeScalar) {
-		return fmt.Errorf("challenge verification failed")
+	match := challenge.Equal(&expectedChallenge)
+	if diagnostics != nil {
+		diagnostics.Challenge.Expected = hex.EncodeToString(expectedChallenge.ToBytes())
+		diagnostics.Challenge.Presented = hex.EncodeToString(proof.C)
+		diagnostics.Challenge.Match = match
 	}
-
-	// Verify A' is not the identity element
-	if s.g1.IsZero(A_prime) {
-		return fmt.Errorf("proof verification failed: A' is zero")
+	if !match {
+		return diagnostics.checkError("challenge", fmt.Errorf("challenge verification failed"))
 	}
+	diagnostics.checkPassed("challenge", "c = H(A', Ā, d, T1, T2, indices, nonce, revealed messages)")
 
-	log.Printf("Proof verification successful")
 	return nil
 }
 
 // ValidateKeyPair validates that a key pair is correctly formed
 func (s *ProductionService) ValidateKeyPair(keyPair *KeyPair) error {
+	c := getCurve()
+	defer putCurve(c)
+
 	if len(keyPair.PrivateKey) != 32 {
 		return fmt.Errorf("invalid private key length: expected 32, got %d", len(keyPair.PrivateKey))
 	}
@@ -594,18 +774,18 @@ func (s *ProductionService) ValidateKeyPair(keyPair *KeyPair) error {
 	var privateScalar bls12381.Fr
 	privateScalar.FromBytes(keyPair.PrivateKey)
 
-	g2Generator := s.g2.One()
+	g2Generator := c.g2.One()
 	expectedPublicKey := &bls12381.PointG2{}
-	s.g2.MulScalar(expectedPublicKey, g2Generator, &privateScalar)
+	c.g2.MulScalar(expectedPublicKey, g2Generator, &privateScalar)
 
-	// Validate that the public key can be decoded
-	_, err := s.g2.FromBytes(keyPair.PublicKey)
+	// Validate that the public key decodes to a point in the prime-order subgroup
+	_, err := decodeG2Point(c.g2, keyPair.PublicKey)
 	if err != nil {
 		return fmt.Errorf("invalid public key format: %w", err)
 	}
 
 	// Compare the byte representations
-	expectedBytes := s.g2.ToBytes(expectedPublicKey)
+	expectedBytes := c.g2.ToBytes(expectedPublicKey)
 	if !bytes.Equal(expectedBytes, keyPair.PublicKey) {
 		return fmt.Errorf("public key does not correspond to private key")
 	}
@@ -623,6 +803,9 @@ func (s *ProductionService) GetMessageCount(signature *Signature, publicKey []by
 
 // ConstantTimeVerify provides constant-time signature verification for production security
 func (s *ProductionService) ConstantTimeVerify(publicKey []byte, signature *Signature, messages [][]byte) error {
+	c := getCurve()
+	defer putCurve(c)
+
 	// This method ensures verification takes constant time regardless of input
 	// to prevent timing attacks
 
@@ -631,10 +814,10 @@ func (s *ProductionService) ConstantTimeVerify(publicKey []byte, signature *Sign
 
 	// Always perform the same number of operations regardless of early return
 	// This is a simplified constant-time approach
-	dummy := s.g1.Zero()
+	dummy := c.g1.Zero()
 	for i := 0; i < 10; i++ {
-		temp := s.g1.One()
-		s.g1.Add(dummy, dummy, temp)
+		temp := c.g1.One()
+		c.g1.Add(dummy, dummy, temp)
 	}
 
 	return err
@@ -666,9 +849,12 @@ func EncodeProof(proof *Proof) string {
 	// Add fixed-size components
 	data = append(data, proof.A_prime...) // 96 bytes
 	data = append(data, proof.A_bar...)   // 96 bytes
+	data = append(data, proof.D...)       // 96 bytes
 	data = append(data, proof.C...)       // 32 bytes
+	data = append(data, proof.E...)       // 32 bytes
 	data = append(data, proof.R2...)      // 32 bytes
 	data = append(data, proof.R3...)      // 32 bytes
+	data = append(data, proof.S...)       // 32 bytes
 
 	// Add variable-size components with length prefixes
 	// Number of revealed attributes (4 bytes)
@@ -704,9 +890,9 @@ func DecodeProof(encoded string) (*Proof, error) {
 		return nil, fmt.Errorf("failed to decode proof: %w", err)
 	}
 
-	// Minimum expected size: 96+96+32+32+32+4+4+4 = 300 bytes
-	if len(data) < 300 {
-		return nil, fmt.Errorf("invalid proof data length: got %d, expected at least 300", len(data))
+	// Minimum expected size: 96+96+96+32+32+32+32+32+4+4+4 = 460 bytes
+	if len(data) < 460 {
+		return nil, fmt.Errorf("invalid proof data length: got %d, expected at least 460", len(data))
 	}
 
 	offset := 0
@@ -718,15 +904,24 @@ func DecodeProof(encoded string) (*Proof, error) {
 	A_bar := data[offset : offset+96]
 	offset += 96
 
+	D := data[offset : offset+96]
+	offset += 96
+
 	C := data[offset : offset+32]
 	offset += 32
 
+	E := data[offset : offset+32]
+	offset += 32
+
 	R2 := data[offset : offset+32]
 	offset += 32
 
 	R3 := data[offset : offset+32]
 	offset += 32
 
+	S := data[offset : offset+32]
+	offset += 32
+
 	// Extract revealed attributes count
 	if offset+4 > len(data) {
 		return nil, fmt.Errorf("insufficient data for revealed attributes count")
@@ -777,9 +972,12 @@ func DecodeProof(encoded string) (*Proof, error) {
 	return &Proof{
 		A_prime:            A_prime,
 		A_bar:              A_bar,
+		D:                  D,
 		C:                  C,
+		E:                  E,
 		R2:                 R2,
 		R3:                 R3,
+		S:                  S,
 		HiddenResponses:    hiddenResponses,
 		RevealedAttributes: revealedAttributes,
 		Nonce:              nonce,
@@ -1,9 +1,13 @@
 package bbs
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"math/big"
+	"sync"
 	"testing"
 
+	bls12381 "github.com/kilic/bls12-381"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,6 +57,18 @@ func TestSignAndVerify(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("Altered Messages", func(t *testing.T) {
+		signature, err := service.Sign(keyPair.PrivateKey, messages)
+		require.NoError(t, err)
+
+		reordered := [][]byte{messages[1], messages[0], messages[2]}
+		assert.Error(t, service.Verify(keyPair.PublicKey, signature, reordered))
+
+		otherKeyPair, err := service.GenerateKeyPair()
+		require.NoError(t, err)
+		assert.Error(t, service.Verify(otherKeyPair.PublicKey, signature, messages))
+	})
+
 	t.Run("Invalid Private Key Length", func(t *testing.T) {
 		invalidKey := []byte("invalid")
 		_, err := service.Sign(invalidKey, messages)
@@ -71,6 +87,56 @@ func TestSignAndVerify(t *testing.T) {
 	})
 }
 
+func TestConcurrentUse(t *testing.T) {
+	service := NewService()
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{
+		[]byte("message1"),
+		[]byte("message2"),
+		[]byte("message3"),
+	}
+	nonce := testNonce(t)
+
+	// One service shared by many goroutines must give each the result it gives alone
+	const workers = 8
+	errs := make(chan error, workers*4)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 4; i++ {
+				signature, err := service.Sign(keyPair.PrivateKey, messages)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if err := service.Verify(keyPair.PublicKey, signature, messages); err != nil {
+					errs <- err
+					continue
+				}
+				proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, []int{0, 2}, nonce)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				if err := service.VerifyProof(keyPair.PublicKey, proof, [][]byte{messages[0], messages[2]}, nonce); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
 func TestCreateAndVerifyProof(t *testing.T) {
 	service := NewService()
 
@@ -89,7 +155,7 @@ func TestCreateAndVerifyProof(t *testing.T) {
 
 	t.Run("Valid Proof", func(t *testing.T) {
 		revealedIndices := []int{2, 3}
-		nonce := []byte("test-nonce")
+		nonce := testNonce(t)
 
 		proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 		require.NoError(t, err)
@@ -122,7 +188,7 @@ func TestCreateAndVerifyProof(t *testing.T) {
 
 	t.Run("Invalid Revealed Index", func(t *testing.T) {
 		revealedIndices := []int{10} // out of range
-		nonce := []byte("test-nonce")
+		nonce := testNonce(t)
 
 		_, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 		assert.Error(t, err)
@@ -131,7 +197,7 @@ func TestCreateAndVerifyProof(t *testing.T) {
 
 	t.Run("Invalid Public Key for Proof Verification", func(t *testing.T) {
 		revealedIndices := []int{2}
-		nonce := []byte("test-nonce")
+		nonce := testNonce(t)
 
 		proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 		require.NoError(t, err)
@@ -146,7 +212,7 @@ func TestCreateAndVerifyProof(t *testing.T) {
 
 	t.Run("Mismatched Revealed Messages and Indices", func(t *testing.T) {
 		revealedIndices := []int{2, 3}
-		nonce := []byte("test-nonce")
+		nonce := testNonce(t)
 
 		proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 		require.NoError(t, err)
@@ -175,7 +241,7 @@ func TestEncodeDecodeProof(t *testing.T) {
 	require.NoError(t, err)
 
 	revealedIndices := []int{0, 1}
-	nonce := []byte("test-nonce-for-encoding")
+	nonce := testNonce(t)
 
 	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 	require.NoError(t, err)
@@ -226,7 +292,7 @@ func TestMultipleMessages(t *testing.T) {
 
 	// Reveal only some messages
 	revealedIndices := []int{2, 5, 8}
-	nonce := []byte("multi-message-nonce")
+	nonce := testNonce(t)
 
 	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 	require.NoError(t, err)
@@ -240,3 +306,86 @@ func TestMultipleMessages(t *testing.T) {
 	err = service.VerifyProof(keyPair.PublicKey, proof, revealedMessages, nonce)
 	assert.NoError(t, err)
 }
+
+// testNonce returns a freshly generated nonce that CreateProof accepts
+func testNonce(t *testing.T) []byte {
+	nonce, err := GenerateNonce()
+	require.NoError(t, err)
+	return []byte(nonce)
+}
+
+func TestForgedProofsRejected(t *testing.T) {
+	service := NewService()
+	g1 := bls12381.NewG1()
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message1"), []byte("message2"), []byte("message3")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	require.NoError(t, err)
+
+	nonce := testNonce(t)
+	proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, []int{0}, nonce)
+	require.NoError(t, err)
+	require.NoError(t, service.VerifyProof(keyPair.PublicKey, proof, messages[:1], nonce))
+
+	randomScalar := func(t *testing.T) *bls12381.Fr {
+		scalars, err := randomScalars(1)
+		require.NoError(t, err)
+		return &scalars[0]
+	}
+	scale := func(t *testing.T, encoded []byte, factor *bls12381.Fr) []byte {
+		point, err := g1.FromBytes(encoded)
+		require.NoError(t, err)
+		return g1.ToBytes(mulScalar(g1, point, point, factor))
+	}
+	one := g1.ToBytes(g1.One())
+
+	t.Run("Arbitrary Points With A Recomputed Challenge", func(t *testing.T) {
+		// No signature is involved: the points are random and the challenge is rehashed over them
+		forged := *proof
+		forged.A_prime = scale(t, one, randomScalar(t))
+		forged.A_bar = scale(t, one, randomScalar(t))
+		forged.D = scale(t, one, randomScalar(t))
+		digest := sha256.Sum256(append(append(append(append([]byte{}, forged.A_prime...), forged.A_bar...), nonce...), messages[1]...))
+		forged.C = bls12381.NewFr().FromBytes(digest[:]).ToBytes()
+
+		require.Error(t, service.VerifyProof(keyPair.PublicKey, &forged, [][]byte{messages[1]}, nonce))
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, &forged, [][]byte{messages[1]}, nonce)
+		assert.False(t, diagnostics.Valid)
+		assert.Equal(t, "pairing", diagnostics.FailedCheck)
+	})
+
+	t.Run("Rerandomized Points Without The Signature", func(t *testing.T) {
+		// Scaling a genuine proof's A' and Ā keeps the pairing equation, but without the signature
+		// the responses cannot answer the challenge for other messages
+		factor := randomScalar(t)
+		forged := *proof
+		forged.A_prime = scale(t, proof.A_prime, factor)
+		forged.A_bar = scale(t, proof.A_bar, factor)
+		forged.D = scale(t, one, randomScalar(t))
+		forged.C = randomScalar(t).ToBytes()
+		forged.E = randomScalar(t).ToBytes()
+		forged.R2 = randomScalar(t).ToBytes()
+
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, &forged, [][]byte{[]byte("forged")}, nonce)
+		assert.False(t, diagnostics.Valid)
+		assert.Equal(t, "challenge", diagnostics.FailedCheck)
+		assert.Error(t, service.VerifyProof(keyPair.PublicKey, &forged, [][]byte{[]byte("forged")}, nonce))
+	})
+
+	t.Run("Hidden Message Count Changed", func(t *testing.T) {
+		forged := *proof
+		forged.HiddenResponses = forged.HiddenResponses[:1]
+		assert.Error(t, service.VerifyProof(keyPair.PublicKey, &forged, messages[:1], nonce))
+	})
+
+	t.Run("Non-Canonical Scalar", func(t *testing.T) {
+		// The same response plus the group order would otherwise verify under different bytes
+		forged := *proof
+		forged.R2 = new(big.Int).Add(new(big.Int).SetBytes(proof.R2), groupOrder).FillBytes(make([]byte, 32))
+		diagnostics := DiagnoseProof(service, keyPair.PublicKey, &forged, messages[:1], nonce)
+		assert.Equal(t, "proof.scalars", diagnostics.FailedCheck)
+	})
+}
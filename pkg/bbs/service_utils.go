@@ -77,8 +77,8 @@ func CompareProviders() map[Provider]ProviderComparison {
 			SecurityLevel:   "Demo",
 			Performance:     "Fast",
 			ProductionReady: false,
-			Features:        []string{"basic_signing", "basic_verification"},
-			Limitations:     []string{"not_cryptographically_secure", "demo_only"},
+			Features:        []string{"deterministic_mock_signing", "tamper_detection", "selective_disclosure"},
+			Limitations:     []string{"not_cryptographically_secure", "forgeable_with_public_key", "demo_only"},
 			RecommendedUse:  "Testing and development",
 		},
 		ProviderProduction: {
@@ -193,7 +193,12 @@ func BenchmarkProviders(providers []Provider, messageCount int) (map[Provider]*P
 		if messageCount > 1 {
 			revealedIndices = []int{0, messageCount - 1} // Reveal first and last messages
 		}
-		nonce := []byte("benchmark-nonce")
+		generatedNonce, err := GenerateNonce()
+		if err != nil {
+			log.Printf("Nonce generation failed for provider %s: %v", provider, err)
+			continue
+		}
+		nonce := []byte(generatedNonce)
 		proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, revealedIndices, nonce)
 		if err != nil {
 			log.Printf("Proof creation failed for provider %s: %v", provider, err)
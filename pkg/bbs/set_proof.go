@@ -0,0 +1,259 @@
+package bbs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// SetMembershipProof shows that Key is one of a set of BBS+ public keys raised to a secret ρ,
+// without revealing which. It is a 1-out-of-n OR composition of Schnorr proofs of knowledge of ρ
+// with each key as the base (Cramer-Damgård-Schoenmakers), made non-interactive by hashing the
+// keys, Key, the commitments and a message.
+//
+// A selective disclosure proof made under Key and bound in the message proves knowledge of a
+// signature under one of the keys: a signature (A, e) under x is one of (A, ρe) under ρx. The
+// prover draws a fresh ρ for every proof, so Key is a uniformly random point that neither points
+// at the signer nor links two proofs.
+type SetMembershipProof struct {
+	Key        []byte   `json:"key"`        // the signer's key raised to ρ
+	Challenges [][]byte `json:"challenges"` // per-key challenges, summing to the Fiat-Shamir challenge
+	Responses  [][]byte `json:"responses"`  // per-key Schnorr responses
+}
+
+// SetProver is implemented by services that can prove knowledge of a signature under one of a set
+// of public keys without revealing which
+type SetProver interface {
+	CreateSetProof(signature *Signature, publicKey []byte, publicKeys [][]byte, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, *SetMembershipProof, error)
+	VerifySetProof(publicKeys [][]byte, proof *Proof, membership *SetMembershipProof, revealedMessages [][]byte, nonce []byte) error
+}
+
+// CreateSetProof creates a selective disclosure proof of a signature under publicKey that shows
+// only that the key is one of publicKeys. The membership proof is bound to the selective
+// disclosure proof, and so to its revealed messages and nonce.
+func (s *ProductionService) CreateSetProof(signature *Signature, publicKey []byte, publicKeys [][]byte, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, *SetMembershipProof, error) {
+	c := getCurve()
+	defer putCurve(c)
+
+	if err := ValidateNonce(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	points, err := decodeSetKeys(c.g2, publicKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer := -1
+	for i, candidate := range publicKeys {
+		if string(candidate) == string(publicKey) {
+			signer = i
+			break
+		}
+	}
+	if signer < 0 {
+		return nil, nil, fmt.Errorf("public key is not in the set")
+	}
+
+	rho, err := bls12381.NewFr().Rand(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key randomizer: %w", err)
+	}
+	if rho.IsZero() {
+		return nil, nil, fmt.Errorf("failed to randomize key")
+	}
+	key := &bls12381.PointG2{}
+	c.g2.MulScalar(key, points[signer], rho)
+
+	template, err := s.precomputeProof(signature, publicKey, messages, rho)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := s.CompleteProof(template, messages, revealedIndices, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	membership, err := proveSetMembership(c.g2, points, publicKeys, signer, rho, key, []byte(EncodeProof(proof)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, membership, nil
+}
+
+// VerifySetProof checks a selective disclosure proof made under the key of a membership proof,
+// and that the key is one of publicKeys raised to a secret
+func (s *ProductionService) VerifySetProof(publicKeys [][]byte, proof *Proof, membership *SetMembershipProof, revealedMessages [][]byte, nonce []byte) error {
+	if proof == nil {
+		return fmt.Errorf("proof cannot be nil")
+	}
+	if membership == nil {
+		return fmt.Errorf("set membership proof cannot be nil")
+	}
+
+	if err := verifySetMembership(publicKeys, membership, []byte(EncodeProof(proof))); err != nil {
+		return err
+	}
+	return s.verifyProof(membership.Key, proof, revealedMessages, nonce, nil)
+}
+
+// proveSetMembership proves that key is publicKeys[signer]^ρ, binding message
+func proveSetMembership(g2 *bls12381.G2, points []*bls12381.PointG2, publicKeys [][]byte, signer int, rho *bls12381.Fr, key *bls12381.PointG2, message []byte) (*SetMembershipProof, error) {
+	var err error
+	challenges := make([]*bls12381.Fr, len(points))
+	responses := make([]*bls12381.Fr, len(points))
+	commitments := make([]*bls12381.PointG2, len(points))
+
+	// Simulate the proofs for the other keys: T = pk^s * Key^-c for random c and s
+	for i, point := range points {
+		if i == signer {
+			continue
+		}
+		if challenges[i], err = bls12381.NewFr().Rand(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate challenge: %w", err)
+		}
+		if responses[i], err = bls12381.NewFr().Rand(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate response: %w", err)
+		}
+		commitments[i] = simulatedCommitment(g2, point, key, challenges[i], responses[i])
+	}
+
+	// Commit honestly for the signer's own key: T = pk^r
+	blinding, err := bls12381.NewFr().Rand(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate blinding factor: %w", err)
+	}
+	commitments[signer] = &bls12381.PointG2{}
+	g2.MulScalar(commitments[signer], points[signer], blinding)
+
+	// The signer's challenge is whatever makes the challenges sum to the Fiat-Shamir challenge
+	keyBytes := g2.ToBytes(key)
+	challenge := setChallenge(g2, publicKeys, keyBytes, commitments, message)
+	challenges[signer] = bls12381.NewFr().Set(challenge)
+	for i := range points {
+		if i != signer {
+			challenges[signer].Sub(challenges[signer], challenges[i])
+		}
+	}
+
+	// s = r + c * ρ
+	responses[signer] = bls12381.NewFr()
+	responses[signer].Mul(challenges[signer], rho)
+	responses[signer].Add(responses[signer], blinding)
+
+	proof := &SetMembershipProof{
+		Key:        keyBytes,
+		Challenges: make([][]byte, len(points)),
+		Responses:  make([][]byte, len(points)),
+	}
+	for i := range points {
+		proof.Challenges[i] = challenges[i].ToBytes()
+		proof.Responses[i] = responses[i].ToBytes()
+	}
+
+	return proof, nil
+}
+
+// verifySetMembership checks that proof.Key is one of publicKeys raised to a secret, binding message
+func verifySetMembership(publicKeys [][]byte, proof *SetMembershipProof, message []byte) error {
+	g2 := bls12381.NewG2()
+
+	points, err := decodeSetKeys(g2, publicKeys)
+	if err != nil {
+		return err
+	}
+
+	if len(proof.Key) != 192 {
+		return fmt.Errorf("invalid set key length")
+	}
+	key, err := decodeG2Point(g2, proof.Key)
+	if err != nil {
+		return fmt.Errorf("invalid set key: %w", err)
+	}
+
+	if len(proof.Challenges) != len(points) || len(proof.Responses) != len(points) {
+		return fmt.Errorf("proof covers %d keys, expected %d", len(proof.Challenges), len(points))
+	}
+
+	sum := bls12381.NewFr().Zero()
+	commitments := make([]*bls12381.PointG2, len(points))
+	for i, point := range points {
+		challenge, err := decodeScalar(proof.Challenges[i])
+		if err != nil {
+			return fmt.Errorf("invalid challenge: %w", err)
+		}
+		response, err := decodeScalar(proof.Responses[i])
+		if err != nil {
+			return fmt.Errorf("invalid response: %w", err)
+		}
+		commitments[i] = simulatedCommitment(g2, point, key, &challenge, &response)
+		sum.Add(sum, &challenge)
+	}
+
+	if !sum.Equal(setChallenge(g2, publicKeys, proof.Key, commitments, message)) {
+		return fmt.Errorf("set membership proof verification failed")
+	}
+
+	return nil
+}
+
+// decodeSetKeys parses the public keys of a set, rejecting sets that would not hide the signer
+func decodeSetKeys(g2 *bls12381.G2, publicKeys [][]byte) ([]*bls12381.PointG2, error) {
+	if len(publicKeys) < 2 {
+		return nil, fmt.Errorf("a key set needs at least 2 keys, got %d", len(publicKeys))
+	}
+
+	points := make([]*bls12381.PointG2, len(publicKeys))
+	for i, publicKey := range publicKeys {
+		if len(publicKey) != 192 {
+			return nil, fmt.Errorf("key %d: invalid public key length", i)
+		}
+
+		point, err := decodeG2Point(g2, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: invalid public key: %w", i, err)
+		}
+
+		for j := 0; j < i; j++ {
+			if g2.Equal(points[j], point) {
+				return nil, fmt.Errorf("key %d: duplicate public key", i)
+			}
+		}
+
+		points[i] = point
+	}
+
+	return points, nil
+}
+
+// simulatedCommitment computes pk^s * Key^-c, the commitment a Schnorr proof of Key = pk^ρ with
+// challenge c and response s must have used
+func simulatedCommitment(g2 *bls12381.G2, publicKey, key *bls12381.PointG2, challenge, response *bls12381.Fr) *bls12381.PointG2 {
+	commitment := &bls12381.PointG2{}
+	g2.MulScalar(commitment, publicKey, response)
+
+	term := &bls12381.PointG2{}
+	g2.MulScalar(term, key, challenge)
+	g2.Sub(commitment, commitment, term)
+
+	return commitment
+}
+
+// setChallenge derives the Fiat-Shamir challenge from the keys, the randomized key, the
+// commitments and the message
+func setChallenge(g2 *bls12381.G2, publicKeys [][]byte, key []byte, commitments []*bls12381.PointG2, message []byte) *bls12381.Fr {
+	h := sha256.New()
+	h.Write([]byte("BBS_SET_MEMBERSHIP_PROOF"))
+	for _, publicKey := range publicKeys {
+		h.Write(publicKey)
+	}
+	h.Write(key)
+	for _, commitment := range commitments {
+		h.Write(g2.ToBytes(commitment))
+	}
+	h.Write(message)
+
+	return bls12381.NewFr().FromBytes(h.Sum(nil))
+}
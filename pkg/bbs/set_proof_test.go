@@ -0,0 +1,106 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetProof(t *testing.T) {
+	service := &ProductionService{}
+
+	keyPairs := make([]*KeyPair, 3)
+	publicKeys := make([][]byte, 3)
+	for i := range keyPairs {
+		keyPair, err := service.GenerateKeyPair()
+		require.NoError(t, err)
+		keyPairs[i] = keyPair
+		publicKeys[i] = keyPair.PublicKey
+	}
+
+	messages := [][]byte{[]byte("manifest"), []byte("ageOver18=false"), []byte("metadata")}
+	revealed := []int{1, 2}
+	sign := func(t *testing.T, keyPair *KeyPair) *Signature {
+		signature, err := service.Sign(keyPair.PrivateKey, messages)
+		require.NoError(t, err)
+		return signature
+	}
+	nonce := testNonce(t)
+
+	// Every member of the set can prove, and the proofs verify the same way
+	for i, keyPair := range keyPairs {
+		proof, membership, err := service.CreateSetProof(sign(t, keyPair), keyPair.PublicKey, publicKeys, messages, revealed, nonce)
+		require.NoError(t, err, "member %d", i)
+		assert.NoError(t, service.VerifySetProof(publicKeys, proof, membership, messages[1:], nonce), "member %d", i)
+	}
+
+	signature := sign(t, keyPairs[1])
+	proof, membership, err := service.CreateSetProof(signature, keyPairs[1].PublicKey, publicKeys, messages, revealed, nonce)
+	require.NoError(t, err)
+
+	t.Run("Fresh Key Per Proof", func(t *testing.T) {
+		again, againMembership, err := service.CreateSetProof(signature, keyPairs[1].PublicKey, publicKeys, messages, revealed, nonce)
+		require.NoError(t, err)
+		assert.NotEqual(t, membership.Key, againMembership.Key)
+		assert.NotEqual(t, proof.A_prime, again.A_prime)
+		for _, publicKey := range publicKeys {
+			assert.NotEqual(t, publicKey, againMembership.Key)
+		}
+	})
+
+	t.Run("Forged Claim", func(t *testing.T) {
+		forged := [][]byte{[]byte("ageOver18=true"), messages[2]}
+		assert.Error(t, service.VerifySetProof(publicKeys, proof, membership, forged, nonce))
+	})
+
+	t.Run("Other Nonce", func(t *testing.T) {
+		assert.Error(t, service.VerifySetProof(publicKeys, proof, membership, messages[1:], testNonce(t)))
+	})
+
+	t.Run("Membership Proof Of Another Presentation", func(t *testing.T) {
+		other, otherMembership, err := service.CreateSetProof(signature, keyPairs[1].PublicKey, publicKeys, messages, revealed, nonce)
+		require.NoError(t, err)
+		assert.Error(t, service.VerifySetProof(publicKeys, proof, otherMembership, messages[1:], nonce))
+		assert.Error(t, service.VerifySetProof(publicKeys, other, membership, messages[1:], nonce))
+	})
+
+	t.Run("Different Key Set", func(t *testing.T) {
+		outsider, err := service.GenerateKeyPair()
+		require.NoError(t, err)
+
+		swapped := [][]byte{publicKeys[0], outsider.PublicKey, publicKeys[2]}
+		assert.Error(t, service.VerifySetProof(swapped, proof, membership, messages[1:], nonce))
+	})
+
+	t.Run("Signature Of An Outsider", func(t *testing.T) {
+		outsider, err := service.GenerateKeyPair()
+		require.NoError(t, err)
+
+		_, _, err = service.CreateSetProof(sign(t, outsider), outsider.PublicKey, publicKeys, messages, revealed, nonce)
+		assert.ErrorContains(t, err, "not in the set")
+
+		// A proof over a set the outsider belongs to does not carry over to the real set
+		withOutsider := [][]byte{publicKeys[0], outsider.PublicKey, publicKeys[2]}
+		forged, forgedMembership, err := service.CreateSetProof(sign(t, outsider), outsider.PublicKey, withOutsider, messages, revealed, nonce)
+		require.NoError(t, err)
+		assert.Error(t, service.VerifySetProof(publicKeys, forged, forgedMembership, messages[1:], nonce))
+	})
+
+	t.Run("Tampered Response", func(t *testing.T) {
+		tampered := *membership
+		tampered.Responses = [][]byte{membership.Responses[1], membership.Responses[0], membership.Responses[2]}
+		assert.Error(t, service.VerifySetProof(publicKeys, proof, &tampered, messages[1:], nonce))
+	})
+
+	t.Run("Set Too Small", func(t *testing.T) {
+		_, _, err := service.CreateSetProof(signature, keyPairs[1].PublicKey, publicKeys[1:2], messages, revealed, nonce)
+		assert.ErrorContains(t, err, "at least 2 keys")
+	})
+
+	t.Run("Duplicate Keys", func(t *testing.T) {
+		duplicated := [][]byte{publicKeys[1], publicKeys[1]}
+		_, _, err := service.CreateSetProof(signature, keyPairs[1].PublicKey, duplicated, messages, revealed, nonce)
+		assert.ErrorContains(t, err, "duplicate")
+	})
+}
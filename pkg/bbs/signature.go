@@ -0,0 +1,130 @@
+package bbs
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// Lengths of the encoded signature components
+const (
+	signatureASize      = 96 // uncompressed G1 point
+	signatureScalarSize = 32
+	// SignatureSize is the length of a signature's components: A, then e, then s
+	SignatureSize = signatureASize + 2*signatureScalarSize
+)
+
+// SignatureFormatVersion is the version byte MarshalSignature writes ahead of the components
+const SignatureFormatVersion byte = 1
+
+// MarshalSignature encodes a signature as the format version followed by its A, e and s components
+func MarshalSignature(signature *Signature) ([]byte, error) {
+	if err := validateSignature(signature); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, 1+SignatureSize)
+	data = append(data, SignatureFormatVersion)
+	data = append(data, signature.A...)
+	data = append(data, signature.E...)
+	data = append(data, signature.S...)
+	return data, nil
+}
+
+// UnmarshalSignature decodes a signature encoded by MarshalSignature. Data of exactly
+// SignatureSize bytes is read as the unversioned layout credentials were first issued with.
+func UnmarshalSignature(data []byte) (*Signature, error) {
+	switch {
+	case len(data) == SignatureSize:
+	case len(data) == 1+SignatureSize && data[0] == SignatureFormatVersion:
+		data = data[1:]
+	case len(data) == 1+SignatureSize:
+		return nil, fmt.Errorf("unsupported signature format version %d", data[0])
+	default:
+		return nil, fmt.Errorf("invalid signature data length: got %d, expected %d", len(data), 1+SignatureSize)
+	}
+
+	// Copy so the signature does not alias the caller's buffer
+	components := append([]byte(nil), data...)
+	return &Signature{
+		A: components[:signatureASize],
+		E: components[signatureASize : signatureASize+signatureScalarSize],
+		S: components[signatureASize+signatureScalarSize:],
+	}, nil
+}
+
+// EncodeSignature encodes a signature as the base64 of MarshalSignature, the form a
+// BbsBlsSignature2020 credential proof carries it in
+func EncodeSignature(signature *Signature) (string, error) {
+	data, err := MarshalSignature(signature)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeSignature decodes a signature encoded by EncodeSignature
+func DecodeSignature(encoded string) (*Signature, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	return UnmarshalSignature(data)
+}
+
+// EncodeSignatureCBOR encodes a signature as the CBOR array [version, A, e, s], with the
+// components as byte strings
+func EncodeSignatureCBOR(signature *Signature) ([]byte, error) {
+	if err := validateSignature(signature); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, 8+SignatureSize)
+	data = append(data, 0x84, SignatureFormatVersion) // array(4), unsigned(version)
+	for _, component := range [][]byte{signature.A, signature.E, signature.S} {
+		data = append(data, 0x58, byte(len(component))) // bytes(n) with a one-byte length
+		data = append(data, component...)
+	}
+	return data, nil
+}
+
+// DecodeSignatureCBOR decodes a signature encoded by EncodeSignatureCBOR. Only that exact
+// structure is accepted, so every signature has a single CBOR encoding.
+func DecodeSignatureCBOR(data []byte) (*Signature, error) {
+	if len(data) < 2 || data[0] != 0x84 {
+		return nil, fmt.Errorf("signature CBOR must be an array of 4 items")
+	}
+	if data[1] != SignatureFormatVersion {
+		return nil, fmt.Errorf("unsupported signature format version %d", data[1])
+	}
+
+	offset := 2
+	components := make([][]byte, 3)
+	for i, size := range []int{signatureASize, signatureScalarSize, signatureScalarSize} {
+		if offset+2 > len(data) || data[offset] != 0x58 || int(data[offset+1]) != size {
+			return nil, fmt.Errorf("signature CBOR item %d must be a %d-byte string", i+1, size)
+		}
+		offset += 2
+		if offset+size > len(data) {
+			return nil, fmt.Errorf("signature CBOR is truncated")
+		}
+		components[i] = append([]byte(nil), data[offset:offset+size]...)
+		offset += size
+	}
+	if offset != len(data) {
+		return nil, fmt.Errorf("signature CBOR has %d trailing bytes", len(data)-offset)
+	}
+
+	return &Signature{A: components[0], E: components[1], S: components[2]}, nil
+}
+
+// validateSignature checks a signature's components have their encoded lengths
+func validateSignature(signature *Signature) error {
+	if signature == nil {
+		return fmt.Errorf("signature is nil")
+	}
+	if len(signature.A) != signatureASize || len(signature.E) != signatureScalarSize || len(signature.S) != signatureScalarSize {
+		return fmt.Errorf("invalid signature component lengths: A %d, e %d, s %d; expected %d, %d, %d",
+			len(signature.A), len(signature.E), len(signature.S), signatureASize, signatureScalarSize, signatureScalarSize)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package bbs
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeSignature(t *testing.T) {
+	service := NewService()
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message1"), []byte("message2")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	require.NoError(t, err)
+
+	t.Run("Base64 Round Trip", func(t *testing.T) {
+		encoded, err := EncodeSignature(signature)
+		require.NoError(t, err)
+
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		require.NoError(t, err)
+		assert.Len(t, data, 1+SignatureSize)
+		assert.Equal(t, SignatureFormatVersion, data[0])
+
+		decoded, err := DecodeSignature(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, signature, decoded)
+		assert.NoError(t, service.Verify(keyPair.PublicKey, decoded, messages))
+	})
+
+	t.Run("CBOR Round Trip", func(t *testing.T) {
+		data, err := EncodeSignatureCBOR(signature)
+		require.NoError(t, err)
+		assert.Equal(t, byte(0x84), data[0])
+
+		decoded, err := DecodeSignatureCBOR(data)
+		require.NoError(t, err)
+		assert.Equal(t, signature, decoded)
+	})
+
+	t.Run("Unversioned Layout", func(t *testing.T) {
+		data, err := MarshalSignature(signature)
+		require.NoError(t, err)
+
+		decoded, err := DecodeSignature(base64.StdEncoding.EncodeToString(data[1:]))
+		require.NoError(t, err)
+		assert.Equal(t, signature, decoded)
+	})
+
+	t.Run("Invalid Lengths", func(t *testing.T) {
+		_, err := DecodeSignature(base64.StdEncoding.EncodeToString(make([]byte, SignatureSize-1)))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid signature data length")
+
+		short := &Signature{A: signature.A[:48], E: signature.E, S: signature.S}
+		_, err = EncodeSignature(short)
+		assert.Error(t, err)
+		_, err = EncodeSignatureCBOR(short)
+		assert.Error(t, err)
+		_, err = EncodeSignature(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown Version", func(t *testing.T) {
+		data, err := MarshalSignature(signature)
+		require.NoError(t, err)
+		data[0] = 2
+		_, err = UnmarshalSignature(data)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported signature format version")
+
+		cbor, err := EncodeSignatureCBOR(signature)
+		require.NoError(t, err)
+		cbor[1] = 2
+		_, err = DecodeSignatureCBOR(cbor)
+		assert.Error(t, err)
+	})
+
+	t.Run("Malformed CBOR", func(t *testing.T) {
+		data, err := EncodeSignatureCBOR(signature)
+		require.NoError(t, err)
+
+		_, err = DecodeSignatureCBOR(data[:len(data)-1])
+		assert.Error(t, err)
+		_, err = DecodeSignatureCBOR(append(append([]byte(nil), data...), 0x00))
+		assert.Error(t, err)
+
+		wrongSize := append([]byte(nil), data...)
+		wrongSize[3] = 48
+		_, err = DecodeSignatureCBOR(wrongSize)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid Base64", func(t *testing.T) {
+		_, err := DecodeSignature("not base64!")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package bbs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// The simple provider's mock crypto: every value is an HMAC-SHA256 keyed by the public key, so
+// it is deterministic, cheap and fails whenever a message, key, nonce or index changes. Hidden
+// messages are represented by their tags, never their values.
+
+// simplePublicKey derives a mock public key from a private key
+func simplePublicKey(privateKey []byte) []byte {
+	digest := sha256.Sum256(append([]byte("bbs-simple-public-key:"), privateKey...))
+	return digest[:]
+}
+
+// simpleMessageTag binds a message to its index under a public key
+func simpleMessageTag(publicKey []byte, index int, message []byte) []byte {
+	mac := hmac.New(sha256.New, publicKey)
+	mac.Write([]byte("message"))
+	mac.Write(binary.BigEndian.AppendUint32(nil, uint32(index)))
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// simpleMessageTags tags every message at its index
+func simpleMessageTags(publicKey []byte, messages [][]byte) [][]byte {
+	tags := make([][]byte, len(messages))
+	for i, message := range messages {
+		tags[i] = simpleMessageTag(publicKey, i, message)
+	}
+	return tags
+}
+
+// simpleSignatureTag combines the message tags, in order, into the value a signature expands
+func simpleSignatureTag(publicKey []byte, tags [][]byte) []byte {
+	mac := hmac.New(sha256.New, publicKey)
+	mac.Write([]byte("signature"))
+	mac.Write(binary.BigEndian.AppendUint32(nil, uint32(len(tags))))
+	for _, tag := range tags {
+		mac.Write(tag)
+	}
+	return mac.Sum(nil)
+}
+
+// simpleSignature expands the signature tag into components sized like a real signature's
+func simpleSignature(publicKey []byte, tags [][]byte) *Signature {
+	tag := simpleSignatureTag(publicKey, tags)
+	return &Signature{
+		A: simpleExpand(tag, "A", signatureASize),
+		E: simpleExpand(tag, "e", signatureScalarSize),
+		S: simpleExpand(tag, "s", signatureScalarSize),
+	}
+}
+
+// simpleProof derives a proof's components from the signature tag, the revealed indices and the
+// nonce, sized like a real proof's so it encodes
+func simpleProof(publicKey []byte, tags [][]byte, revealedIndices []int, nonce []byte) *Proof {
+	mac := hmac.New(sha256.New, publicKey)
+	mac.Write([]byte("proof"))
+	mac.Write(simpleSignatureTag(publicKey, tags))
+	mac.Write(binary.BigEndian.AppendUint32(nil, uint32(len(revealedIndices))))
+	for _, index := range revealedIndices {
+		mac.Write(binary.BigEndian.AppendUint32(nil, uint32(index)))
+	}
+	mac.Write(nonce)
+	challenge := mac.Sum(nil)
+
+	return &Proof{
+		A_prime:            simpleExpand(challenge, "A'", 96),
+		A_bar:              simpleExpand(challenge, "Abar", 96),
+		D:                  simpleExpand(challenge, "d", 96),
+		C:                  challenge,
+		E:                  simpleExpand(challenge, "e", 32),
+		R2:                 simpleExpand(challenge, "r2", 32),
+		R3:                 simpleExpand(challenge, "r3", 32),
+		S:                  simpleExpand(challenge, "s", 32),
+		RevealedAttributes: revealedIndices,
+		Nonce:              nonce,
+	}
+}
+
+// simpleExpand stretches a key into size bytes for the labelled component
+func simpleExpand(key []byte, label string, size int) []byte {
+	out := make([]byte, 0, size+sha256.Size)
+	for counter := uint32(0); len(out) < size; counter++ {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(label))
+		mac.Write(binary.BigEndian.AppendUint32(nil, counter))
+		out = mac.Sum(out)
+	}
+	return out[:size]
+}
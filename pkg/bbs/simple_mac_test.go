@@ -0,0 +1,81 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimpleProviderMockCrypto(t *testing.T) {
+	service, err := NewSimpleBBSService()
+	require.NoError(t, err)
+
+	keyPair, err := service.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, service.ValidateKeyPair(keyPair))
+
+	messages := [][]byte{[]byte("message1"), []byte("message2"), []byte("message3")}
+	signature, err := service.Sign(keyPair.PrivateKey, messages)
+	require.NoError(t, err)
+
+	t.Run("Signatures Are Deterministic And Encode", func(t *testing.T) {
+		again, err := service.Sign(keyPair.PrivateKey, messages)
+		require.NoError(t, err)
+		assert.Equal(t, signature, again)
+
+		encoded, err := EncodeSignature(signature)
+		require.NoError(t, err)
+		decoded, err := DecodeSignature(encoded)
+		require.NoError(t, err)
+		assert.NoError(t, service.Verify(keyPair.PublicKey, decoded, messages))
+	})
+
+	t.Run("Tampering Fails Verification", func(t *testing.T) {
+		tampered := [][]byte{messages[0], []byte("tampered"), messages[2]}
+		assert.Error(t, service.Verify(keyPair.PublicKey, signature, tampered))
+
+		reordered := [][]byte{messages[1], messages[0], messages[2]}
+		assert.Error(t, service.Verify(keyPair.PublicKey, signature, reordered))
+		assert.Error(t, service.Verify(keyPair.PublicKey, signature, messages[:2]))
+
+		other, err := service.GenerateKeyPair()
+		require.NoError(t, err)
+		assert.Error(t, service.Verify(other.PublicKey, signature, messages))
+
+		altered := &Signature{A: signature.A, E: append([]byte(nil), signature.E...), S: signature.S}
+		altered.E[0] ^= 1
+		assert.Error(t, service.Verify(keyPair.PublicKey, altered, messages))
+	})
+
+	t.Run("Mismatched Key Pair", func(t *testing.T) {
+		other, err := service.GenerateKeyPair()
+		require.NoError(t, err)
+		assert.Error(t, service.ValidateKeyPair(&KeyPair{PublicKey: other.PublicKey, PrivateKey: keyPair.PrivateKey}))
+	})
+
+	t.Run("Proofs", func(t *testing.T) {
+		nonce := []byte("verifier-nonce")
+		proof, err := service.CreateProof(signature, keyPair.PublicKey, messages, []int{0, 2}, nonce)
+		require.NoError(t, err)
+		assert.Len(t, proof.HiddenResponses, 1)
+
+		decoded, err := DecodeProof(EncodeProof(proof))
+		require.NoError(t, err)
+		revealed := [][]byte{messages[0], messages[2]}
+		assert.NoError(t, service.VerifyProof(keyPair.PublicKey, decoded, revealed, nonce))
+
+		assert.Error(t, service.VerifyProof(keyPair.PublicKey, proof, revealed, []byte("other-nonce")))
+		assert.Error(t, service.VerifyProof(keyPair.PublicKey, proof, [][]byte{messages[0], []byte("tampered")}, nonce))
+		assert.Error(t, service.VerifyProof(keyPair.PublicKey, proof, revealed[:1], nonce))
+
+		moved := *proof
+		moved.RevealedAttributes = []int{0, 1}
+		assert.Error(t, service.VerifyProof(keyPair.PublicKey, &moved, revealed, nonce))
+
+		_, err = service.CreateProof(signature, keyPair.PublicKey, [][]byte{messages[0], messages[1], []byte("tampered")}, []int{0}, nonce)
+		assert.Error(t, err)
+		_, err = service.CreateProof(signature, keyPair.PublicKey, messages, []int{3}, nonce)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,82 @@
+package bbs
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// ProofTemplate is the blinded part of a selective disclosure proof, computed from a signature
+// ahead of time so that presenting only adds the hidden message terms, the revealed messages and
+// the nonce. Proofs that share a template's blinding are linkable, so a template completes exactly
+// one proof.
+type ProofTemplate struct {
+	PublicKey []byte
+	// MessageDigest identifies the messages the template was computed for; see MessagesDigest
+	MessageDigest []byte
+	A_prime       []byte
+	A_bar         []byte
+	D             []byte
+
+	// t1 and t2 are the proof's commitments; t2 still lacks the hidden message terms
+	t1, t2 bls12381.PointG1
+	// witness holds e, r2, r3 and s', and blinds and messageBlinds the scalars that blind them
+	// and each message in the commitments
+	witness, blinds proofWitness
+	messageBlinds   []bls12381.Fr
+
+	mu   sync.Mutex
+	used bool
+}
+
+// proofWitness is the secret side of a proof of knowledge besides the hidden messages
+type proofWitness struct {
+	e, r2, r3, s bls12381.Fr
+}
+
+// ProofPrecomputer is implemented by services that can split proof creation into a precomputed
+// template and a cheap completion
+type ProofPrecomputer interface {
+	PrecomputeProof(signature *Signature, publicKey []byte, messages [][]byte) (*ProofTemplate, error)
+	CompleteProof(template *ProofTemplate, messages [][]byte, revealedIndices []int, nonce []byte) (*Proof, error)
+}
+
+// MessagesDigest hashes length-prefixed messages, so a template can be matched to the messages
+// it is completed with
+func MessagesDigest(messages [][]byte) []byte {
+	hash := sha256.New()
+	var length [8]byte
+	for _, message := range messages {
+		binary.BigEndian.PutUint64(length[:], uint64(len(message)))
+		hash.Write(length[:])
+		hash.Write(message)
+	}
+	return hash.Sum(nil)
+}
+
+// Matches reports whether the template was computed for the messages and has not been used
+func (t *ProofTemplate) Matches(messages [][]byte) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.used && string(t.MessageDigest) == string(MessagesDigest(messages))
+}
+
+// consume marks the template used, failing if it already was or belongs to other messages
+func (t *ProofTemplate) consume(messages [][]byte) error {
+	if t == nil {
+		return fmt.Errorf("proof template is nil")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.used {
+		return fmt.Errorf("proof template was already used")
+	}
+	if string(t.MessageDigest) != string(MessagesDigest(messages)) {
+		return fmt.Errorf("proof template was computed for other messages")
+	}
+	t.used = true
+	return nil
+}
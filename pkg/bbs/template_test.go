@@ -0,0 +1,51 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofTemplate(t *testing.T) {
+	service := NewService().(*ProductionService)
+	keyPair, signature, err := selfTestSignature(service)
+	require.NoError(t, err)
+	revealed := [][]byte{selfTestMessages[0], selfTestMessages[2]}
+
+	t.Run("Completed Proof Verifies", func(t *testing.T) {
+		template, err := service.PrecomputeProof(signature, keyPair.PublicKey, selfTestMessages)
+		require.NoError(t, err)
+		assert.True(t, template.Matches(selfTestMessages))
+
+		proof, err := service.CompleteProof(template, selfTestMessages, []int{0, 2}, selfTestNonce)
+		require.NoError(t, err)
+		assert.NoError(t, service.VerifyProof(keyPair.PublicKey, proof, revealed, selfTestNonce))
+		assert.False(t, template.Matches(selfTestMessages), "a used template no longer matches")
+	})
+
+	t.Run("Single Use", func(t *testing.T) {
+		template, err := service.PrecomputeProof(signature, keyPair.PublicKey, selfTestMessages)
+		require.NoError(t, err)
+		_, err = service.CompleteProof(template, selfTestMessages, []int{0}, selfTestNonce)
+		require.NoError(t, err)
+
+		_, err = service.CompleteProof(template, selfTestMessages, []int{0}, selfTestNonce)
+		assert.Error(t, err)
+	})
+
+	t.Run("Other Messages Rejected", func(t *testing.T) {
+		template, err := service.PrecomputeProof(signature, keyPair.PublicKey, selfTestMessages)
+		require.NoError(t, err)
+		altered := [][]byte{selfTestMessages[0], []byte("self-test:altered"), selfTestMessages[2]}
+		assert.False(t, template.Matches(altered))
+
+		_, err = service.CompleteProof(template, altered, []int{0}, selfTestNonce)
+		assert.Error(t, err)
+		assert.True(t, template.Matches(selfTestMessages), "a rejected completion leaves the template unused")
+	})
+
+	t.Run("Message Digest Is Length Prefixed", func(t *testing.T) {
+		assert.NotEqual(t, MessagesDigest([][]byte{[]byte("ab"), []byte("c")}), MessagesDigest([][]byte{[]byte("a"), []byte("bc")}))
+	})
+}
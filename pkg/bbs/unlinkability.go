@@ -0,0 +1,111 @@
+package bbs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+)
+
+// unlinkabilityWindow is the length of the byte runs two proofs must not share. Independently
+// blinded components share one by chance with negligible probability.
+const unlinkabilityWindow = 8
+
+// UnlinkabilityTest derives two proofs from one signature under different nonces and checks
+// that a verifier cannot link them: no randomized component, nor any run of it, may repeat
+// across the proofs or copy the signature. The proofs must record only the revealed indices,
+// and a hidden message may only add its blinded response to a proof, which the verifier needs to
+// check the proof but which says nothing about the message.
+func UnlinkabilityTest(service BBSService) error {
+	keyPair, signature, err := selfTestSignature(service)
+	if err != nil {
+		return err
+	}
+	defer service.SecureErase(keyPair.PrivateKey)
+
+	revealedIndices := []int{0, 2}
+	first, err := service.CreateProof(signature, keyPair.PublicKey, selfTestMessages, revealedIndices, selfTestNonce)
+	if err != nil {
+		return fmt.Errorf("proof creation failed: %w", err)
+	}
+	second, err := service.CreateProof(signature, keyPair.PublicKey, selfTestMessages, revealedIndices, []byte("self-test-nonce:0d4e8b27c9a1f365"))
+	if err != nil {
+		return fmt.Errorf("proof creation failed: %w", err)
+	}
+
+	signatureParts := map[string][]byte{"A": signature.A, "e": signature.E, "s": signature.S}
+	for name, component := range blindedComponents(first) {
+		for otherName, other := range blindedComponents(second) {
+			if sharesRun(component, other) {
+				return fmt.Errorf("proofs under different nonces share bytes between %s and %s", name, otherName)
+			}
+		}
+		for part, value := range signatureParts {
+			if sharesRun(component, value) {
+				return fmt.Errorf("proof %s shares bytes with the signature's %s", name, part)
+			}
+		}
+	}
+
+	for _, proof := range []*Proof{first, second} {
+		if fmt.Sprint(proof.RevealedAttributes) != fmt.Sprint(revealedIndices) {
+			return fmt.Errorf("proof records indices %v, not the revealed %v", proof.RevealedAttributes, revealedIndices)
+		}
+	}
+
+	// Revealing the same messages of a longer signature adds one response and nothing else
+	longer := append(append([][]byte{}, selfTestMessages...), []byte("self-test:extra"))
+	longerSignature, err := service.Sign(keyPair.PrivateKey, longer)
+	if err != nil {
+		return fmt.Errorf("signing failed: %w", err)
+	}
+	longerProof, err := service.CreateProof(longerSignature, keyPair.PublicKey, longer, revealedIndices, selfTestNonce)
+	if err != nil {
+		return fmt.Errorf("proof creation failed: %w", err)
+	}
+	if encodedSize(longerProof) != encodedSize(first)+len(longerProof.HiddenResponses[0]) {
+		return fmt.Errorf("proof size reveals more about the hidden messages than their number")
+	}
+
+	return nil
+}
+
+// encodedSize is the length of a proof's encoding in bytes
+func encodedSize(proof *Proof) int {
+	data, _ := base64.StdEncoding.DecodeString(EncodeProof(proof))
+	return len(data)
+}
+
+// blindedComponents names the proof components that must be fresh for every proof
+func blindedComponents(proof *Proof) map[string][]byte {
+	components := map[string][]byte{
+		"A'": proof.A_prime,
+		"Ā":  proof.A_bar,
+		"d":  proof.D,
+		"c":  proof.C,
+		"e":  proof.E,
+		"r2": proof.R2,
+		"r3": proof.R3,
+		"s":  proof.S,
+	}
+	for i, response := range proof.HiddenResponses {
+		components[fmt.Sprintf("hiddenResponses[%d]", i)] = response
+	}
+	return components
+}
+
+// sharesRun reports whether a and b have a run of unlinkabilityWindow bytes in common
+func sharesRun(a, b []byte) bool {
+	if len(a) < unlinkabilityWindow || len(b) < unlinkabilityWindow {
+		return len(a) > 0 && bytes.Equal(a, b)
+	}
+	runs := make(map[string]struct{}, len(a))
+	for i := 0; i+unlinkabilityWindow <= len(a); i++ {
+		runs[string(a[i:i+unlinkabilityWindow])] = struct{}{}
+	}
+	for i := 0; i+unlinkabilityWindow <= len(b); i++ {
+		if _, ok := runs[string(b[i:i+unlinkabilityWindow])]; ok {
+			return true
+		}
+	}
+	return false
+}
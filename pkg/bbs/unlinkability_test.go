@@ -0,0 +1,50 @@
+package bbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlinkabilityTest(t *testing.T) {
+	t.Run("Production Service", func(t *testing.T) {
+		assert.NoError(t, UnlinkabilityTest(NewService()))
+	})
+
+	t.Run("Unblinded Proofs Are Linkable", func(t *testing.T) {
+		// The simple provider's deterministic proofs carry the hidden message tags unchanged
+		service, err := NewFactory().CreateService(ProviderSimple, nil)
+		require.NoError(t, err)
+		assert.Error(t, UnlinkabilityTest(service))
+	})
+}
+
+func TestProofRandomization(t *testing.T) {
+	service := NewService()
+	keyPair, signature, err := selfTestSignature(service)
+	require.NoError(t, err)
+
+	// Blinding is fresh even when the nonce repeats
+	proofs := make([]*Proof, 3)
+	for i := range proofs {
+		proofs[i], err = service.CreateProof(signature, keyPair.PublicKey, selfTestMessages, []int{1}, selfTestNonce)
+		require.NoError(t, err)
+	}
+	for i := range proofs {
+		for j := i + 1; j < len(proofs); j++ {
+			for name, component := range blindedComponents(proofs[i]) {
+				assert.False(t, sharesRun(component, blindedComponents(proofs[j])[name]), "proofs %d and %d share bytes in %s", i, j, name)
+			}
+		}
+		assert.False(t, sharesRun(proofs[i].A_prime, signature.A), "A' must not reveal A")
+	}
+}
+
+func TestSharesRun(t *testing.T) {
+	a := []byte("0123456789abcdef")
+	assert.True(t, sharesRun(a, []byte("xx456789abyy")))
+	assert.False(t, sharesRun(a, []byte("xx4567xx89abyy")))
+	assert.True(t, sharesRun([]byte("abc"), []byte("abc")))
+	assert.False(t, sharesRun(nil, nil))
+}
@@ -0,0 +1,79 @@
+// Package blob stores opaque documents, such as the evidence attached to credentials, under
+// content-derived references. Documents that must stay confidential are encrypted before they are
+// stored, so a store never needs to be trusted with their contents.
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// ErrNotFound is returned by Get for an unknown reference
+var ErrNotFound = errors.New("blob not found")
+
+// refPrefix starts every reference; the rest is the hex SHA-256 of the stored bytes
+const refPrefix = "sha256:"
+
+// Store keeps blobs under references derived from their contents
+type Store interface {
+	// Put stores data and returns its reference. Storing the same bytes twice yields the same reference.
+	Put(data []byte) (string, error)
+	// Get returns the blob stored under ref, or ErrNotFound
+	Get(ref string) ([]byte, error)
+}
+
+// Ref returns the reference data is stored under
+func Ref(data []byte) string {
+	digest := sha256.Sum256(data)
+	return refPrefix + hex.EncodeToString(digest[:])
+}
+
+// KVStore is a Store kept in a key-value store, so every server instance sharing it serves the
+// same blobs
+type KVStore struct {
+	store storage.KVStore
+}
+
+// NewKVStore creates a blob store on top of store
+func NewKVStore(store storage.KVStore) *KVStore {
+	return &KVStore{store: store}
+}
+
+// NewMemoryStore creates a blob store kept in memory
+func NewMemoryStore() *KVStore {
+	return NewKVStore(storage.NewMemoryStore())
+}
+
+// Put stores data under its reference
+func (s *KVStore) Put(data []byte) (string, error) {
+	ref := Ref(data)
+	if err := s.store.Set(ref, data, 0); err != nil {
+		return "", fmt.Errorf("failed to store blob: %w", err)
+	}
+	return ref, nil
+}
+
+// Get returns the blob stored under ref, checking it still matches the reference
+func (s *KVStore) Get(ref string) ([]byte, error) {
+	if !strings.HasPrefix(ref, refPrefix) {
+		return nil, fmt.Errorf("invalid blob reference: %s", ref)
+	}
+
+	data, err := s.store.Get(ref)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blob: %w", err)
+	}
+
+	if Ref(data) != ref {
+		return nil, fmt.Errorf("blob %s does not match its reference", ref)
+	}
+	return data, nil
+}
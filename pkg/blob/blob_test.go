@@ -0,0 +1,46 @@
+package blob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+func TestKVStore(t *testing.T) {
+	kv := storage.NewMemoryStore()
+	store := NewKVStore(kv)
+
+	t.Run("Round Trip", func(t *testing.T) {
+		ref, err := store.Put([]byte("transcript"))
+		require.NoError(t, err)
+		assert.Equal(t, Ref([]byte("transcript")), ref)
+
+		data, err := store.Get(ref)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("transcript"), data)
+
+		again, err := store.Put([]byte("transcript"))
+		require.NoError(t, err)
+		assert.Equal(t, ref, again, "equal contents share a reference")
+	})
+
+	t.Run("Unknown Reference", func(t *testing.T) {
+		_, err := store.Get(Ref([]byte("missing")))
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = store.Get("missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("Tampered Blob", func(t *testing.T) {
+		ref, err := store.Put([]byte("diploma"))
+		require.NoError(t, err)
+		require.NoError(t, kv.Set(ref, []byte("forged diploma"), 0))
+
+		_, err = store.Get(ref)
+		assert.Error(t, err)
+	})
+}
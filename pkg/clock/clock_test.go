@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock := NewMock(start)
+	assert.Equal(t, start, mock.Now())
+	assert.Equal(t, start, mock.Now(), "a mock clock does not move by itself")
+
+	mock.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), mock.Now())
+
+	mock.Set(start.AddDate(1, 0, 0))
+	assert.Equal(t, start.AddDate(1, 0, 0), mock.Now())
+}
+
+func TestOrSystem(t *testing.T) {
+	assert.Equal(t, System{}, OrSystem(nil))
+
+	mock := NewMock(time.Time{})
+	assert.Same(t, mock, OrSystem(mock))
+
+	before := time.Now()
+	assert.False(t, System{}.Now().Before(before))
+}
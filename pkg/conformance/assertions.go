@@ -0,0 +1,429 @@
+package conformance
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// assertion is one normative statement of the data model
+type assertion struct {
+	id          string
+	section     string
+	description string
+	// only restricts the assertion to one data model version; empty applies it to both
+	only  vc.Version
+	check func(document map[string]interface{}, version vc.Version) error
+}
+
+// credentialAssertions are run against every credential, issued or presented
+var credentialAssertions = []assertion{
+	contextIsOrderedSet,
+	contextBaseFirst,
+	contextItemsValid,
+	{
+		id:          "credential.id",
+		section:     "Identifiers",
+		description: "id, if present, is a single URL",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			return optionalURL(document, "id")
+		},
+	},
+	{
+		id:          "credential.type",
+		section:     "Types",
+		description: "type includes VerifiableCredential",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			return hasType(document, "VerifiableCredential")
+		},
+	},
+	{
+		id:          "credential.credentialSubject",
+		section:     "Credential Subject",
+		description: "credentialSubject is present and is an object or a set of objects",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			_, err := subjects(document)
+			return err
+		},
+	},
+	{
+		id:          "credential.credentialSubject.id",
+		section:     "Credential Subject",
+		description: "credentialSubject id, if present, is a URL",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			all, err := subjects(document)
+			if err != nil {
+				return nil // reported by credential.credentialSubject
+			}
+			for _, subject := range all {
+				if err := optionalURL(subject, "id"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "credential.issuer",
+		section:     "Issuer",
+		description: "issuer is present and is a URL or an object whose id is a URL",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			switch issuer := document["issuer"].(type) {
+			case nil:
+				return fmt.Errorf("issuer is missing")
+			case string:
+				return isURL(issuer)
+			case map[string]interface{}:
+				id, ok := issuer["id"].(string)
+				if !ok {
+					return fmt.Errorf("issuer object has no id")
+				}
+				return isURL(id)
+			default:
+				return fmt.Errorf("issuer is a %T", issuer)
+			}
+		},
+	},
+	{
+		id:          "credential.issuanceDate",
+		section:     "Issuance Date",
+		description: "issuanceDate is present and is an XML Schema dateTime",
+		only:        vc.Version1,
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			if _, ok := document["issuanceDate"]; !ok {
+				return fmt.Errorf("issuanceDate is missing")
+			}
+			_, err := dateTime(document, "issuanceDate")
+			return err
+		},
+	},
+	{
+		id:          "credential.expirationDate",
+		section:     "Expiration",
+		description: "expirationDate, if present, is an XML Schema dateTime",
+		only:        vc.Version1,
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			_, err := dateTime(document, "expirationDate")
+			return err
+		},
+	},
+	{
+		id:          "credential.validityPeriod",
+		section:     "Validity Period",
+		description: "validFrom and validUntil, if present, are dateTimeStamps and validFrom is not after validUntil",
+		only:        vc.Version2,
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			from, err := dateTime(document, "validFrom")
+			if err != nil {
+				return err
+			}
+			until, err := dateTime(document, "validUntil")
+			if err != nil {
+				return err
+			}
+			if from != nil && until != nil && from.After(*until) {
+				return fmt.Errorf("validFrom %s is after validUntil %s", from.Format(time.RFC3339), until.Format(time.RFC3339))
+			}
+			return nil
+		},
+	},
+	{
+		id:          "credential.legacyDates",
+		section:     "Validity Period",
+		description: "issuanceDate and expirationDate are not used under the 2.0 context",
+		only:        vc.Version2,
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			for _, property := range []string{"issuanceDate", "expirationDate"} {
+				if _, ok := document[property]; ok {
+					return fmt.Errorf("%s is a 1.1 property", property)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "credential.credentialStatus",
+		section:     "Status",
+		description: "each credentialStatus entry has a type, and an id that is a URL",
+		check: func(document map[string]interface{}, version vc.Version) error {
+			value, ok := document["credentialStatus"]
+			if !ok {
+				return nil
+			}
+			entries, err := objects(value)
+			if err != nil {
+				return fmt.Errorf("credentialStatus: %w", err)
+			}
+			for i, entry := range entries {
+				if err := typed(entry); err != nil {
+					return fmt.Errorf("credentialStatus[%d]: %w", i, err)
+				}
+				// The id became optional in 2.0
+				if _, hasID := entry["id"]; hasID || version == vc.Version1 {
+					id, _ := entry["id"].(string)
+					if err := isURL(id); err != nil {
+						return fmt.Errorf("credentialStatus[%d] id: %w", i, err)
+					}
+				}
+			}
+			return nil
+		},
+	},
+	proofIsPresent,
+}
+
+// presentationAssertions are run against presentations; their credentials are checked with the
+// credential assertions
+var presentationAssertions = []assertion{
+	contextIsOrderedSet,
+	contextBaseFirst,
+	contextItemsValid,
+	{
+		id:          "presentation.id",
+		section:     "Presentations",
+		description: "id, if present, is a single URL",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			return optionalURL(document, "id")
+		},
+	},
+	{
+		id:          "presentation.type",
+		section:     "Presentations",
+		description: "type includes VerifiablePresentation",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			return hasType(document, "VerifiablePresentation")
+		},
+	},
+	{
+		id:          "presentation.holder",
+		section:     "Presentations",
+		description: "holder, if present, is a URL",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			return optionalURL(document, "holder")
+		},
+	},
+	{
+		id:          "presentation.verifiableCredential",
+		section:     "Presentations",
+		description: "verifiableCredential, if present, is an object or a set of objects",
+		check: func(document map[string]interface{}, _ vc.Version) error {
+			value, ok := document["verifiableCredential"]
+			if !ok || value == nil {
+				return nil
+			}
+			_, err := objects(value)
+			return err
+		},
+	},
+	proofIsPresent,
+}
+
+// credentialIsObject is recorded for each item of a presentation's verifiableCredential
+var credentialIsObject = assertion{
+	id:          "object",
+	section:     "Presentations",
+	description: "the presented credential is a JSON object",
+}
+
+var contextIsOrderedSet = assertion{
+	id:          "context",
+	section:     "Contexts",
+	description: "@context is present and is an ordered set",
+	check: func(document map[string]interface{}, _ vc.Version) error {
+		value, ok := document["@context"]
+		if !ok {
+			return fmt.Errorf("@context is missing")
+		}
+		if _, ok := value.([]interface{}); !ok {
+			return fmt.Errorf("@context is a %T, not an array", value)
+		}
+		return nil
+	},
+}
+
+var contextBaseFirst = assertion{
+	id:          "context.base",
+	section:     "Contexts",
+	description: "the first @context item is the credentials v1 or v2 context",
+	check: func(document map[string]interface{}, _ vc.Version) error {
+		contexts, _ := document["@context"].([]interface{})
+		if len(contexts) == 0 {
+			return fmt.Errorf("@context is empty")
+		}
+		switch contexts[0] {
+		case jsonld.CredentialsV1URL, jsonld.CredentialsV2URL:
+			return nil
+		default:
+			return fmt.Errorf("the first @context item is %v", contexts[0])
+		}
+	},
+}
+
+var contextItemsValid = assertion{
+	id:          "context.items",
+	section:     "Contexts",
+	description: "subsequent @context items are URLs or objects",
+	check: func(document map[string]interface{}, _ vc.Version) error {
+		contexts, _ := document["@context"].([]interface{})
+		for i, item := range contexts {
+			switch value := item.(type) {
+			case string:
+				if err := isURL(value); err != nil {
+					return fmt.Errorf("@context[%d]: %w", i, err)
+				}
+			case map[string]interface{}:
+			default:
+				return fmt.Errorf("@context[%d] is a %T", i, item)
+			}
+		}
+		return nil
+	},
+}
+
+var proofIsPresent = assertion{
+	id:          "proof",
+	section:     "Proofs",
+	description: "at least one proof is present and each proof has a type",
+	check: func(document map[string]interface{}, _ vc.Version) error {
+		value, ok := document["proof"]
+		if !ok || value == nil {
+			return fmt.Errorf("proof is missing")
+		}
+		proofs, err := objects(value)
+		if err != nil {
+			return fmt.Errorf("proof: %w", err)
+		}
+		if len(proofs) == 0 {
+			return fmt.Errorf("proof is empty")
+		}
+		for i, proof := range proofs {
+			if err := typed(proof); err != nil {
+				return fmt.Errorf("proof[%d]: %w", i, err)
+			}
+		}
+		return nil
+	},
+}
+
+// isURL checks that a value is an absolute URL, which includes URNs and DIDs
+func isURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a URL: %w", value, err)
+	}
+	if parsed.Scheme == "" {
+		return fmt.Errorf("%q is not a URL: it has no scheme", value)
+	}
+	return nil
+}
+
+// optionalURL checks that a property, if present, is a URL
+func optionalURL(document map[string]interface{}, property string) error {
+	value, ok := document[property]
+	if !ok {
+		return nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("%s is a %T, not a string", property, value)
+	}
+	if err := isURL(text); err != nil {
+		return fmt.Errorf("%s: %w", property, err)
+	}
+	return nil
+}
+
+// hasType checks that a document's type is a string or set of strings including the given type
+func hasType(document map[string]interface{}, want string) error {
+	var types []string
+	switch value := document["type"].(type) {
+	case nil:
+		return fmt.Errorf("type is missing")
+	case string:
+		types = []string{value}
+	case []interface{}:
+		for i, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("type[%d] is a %T, not a string", i, item)
+			}
+			types = append(types, text)
+		}
+	default:
+		return fmt.Errorf("type is a %T", value)
+	}
+
+	for _, t := range types {
+		if t == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("type %v does not include %s", types, want)
+}
+
+// typed checks that an object has a non-empty string type
+func typed(object map[string]interface{}) error {
+	if t, ok := object["type"].(string); ok && t != "" {
+		return nil
+	}
+	if types, ok := object["type"].([]interface{}); ok && len(types) > 0 {
+		return nil
+	}
+	return fmt.Errorf("type is missing")
+}
+
+// subjects returns a credential's subjects
+func subjects(document map[string]interface{}) ([]map[string]interface{}, error) {
+	value, ok := document["credentialSubject"]
+	if !ok || value == nil {
+		return nil, fmt.Errorf("credentialSubject is missing")
+	}
+	all, err := objects(value)
+	if err != nil {
+		return nil, fmt.Errorf("credentialSubject: %w", err)
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("credentialSubject is empty")
+	}
+	return all, nil
+}
+
+// objects returns a value that is an object or a set of objects as a set
+func objects(value interface{}) ([]map[string]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}, nil
+	case []interface{}:
+		all := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("item %d is a %T, not an object", i, item)
+			}
+			all[i] = object
+		}
+		return all, nil
+	default:
+		return nil, fmt.Errorf("%T is not an object or a set of objects", value)
+	}
+}
+
+// dateTime parses a date property, returning nil if it is absent
+func dateTime(document map[string]interface{}, property string) (*time.Time, error) {
+	value, ok := document[property]
+	if !ok {
+		return nil, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s is a %T, not a string", property, value)
+	}
+	parsed, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q is not a dateTime: %w", property, text, err)
+	}
+	return &parsed, nil
+}
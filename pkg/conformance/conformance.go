@@ -0,0 +1,174 @@
+// Package conformance checks credentials and presentations against the normative statements the
+// W3C Verifiable Credentials Data Model test suites assert: required properties, @context
+// handling, value types and proof structure, under data model 1.1 or 2.0 as the document declares.
+// Documents are checked in their JSON form, as other implementations receive them, and every
+// assertion is reported as passed or failed so a change that drifts from the specification shows
+// up as a named failure.
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Result is the outcome of one assertion
+type Result struct {
+	// ID names the assertion, prefixed with the path of nested documents such as
+	// verifiableCredential[0]
+	ID string `json:"id"`
+	// Section is the data model section the assertion comes from
+	Section     string `json:"section"`
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+	// Message explains a failure
+	Message string `json:"message,omitempty"`
+}
+
+// Report lists the outcome of every assertion run against a document
+type Report struct {
+	// Version is the data model the document was checked under
+	Version vc.Version `json:"version"`
+	Results []Result   `json:"results"`
+	Passed  int        `json:"passed"`
+	Failed  int        `json:"failed"`
+}
+
+// OK reports whether every assertion passed
+func (r *Report) OK() bool {
+	return r.Failed == 0
+}
+
+// Failures returns the assertions that failed
+func (r *Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if !result.Passed {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// String formats the report one assertion per line
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "VC Data Model %s: %d passed, %d failed\n", r.Version, r.Passed, r.Failed)
+	for _, result := range r.Results {
+		mark := "PASS"
+		if !result.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s %s (%s): %s", mark, result.ID, result.Section, result.Description)
+		if result.Message != "" {
+			fmt.Fprintf(&b, ": %s", result.Message)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// record adds an assertion's outcome to the report
+func (r *Report) record(prefix string, a assertion, err error) {
+	result := Result{ID: prefix + a.id, Section: a.section, Description: a.description, Passed: err == nil}
+	if err != nil {
+		result.Message = err.Error()
+		r.Failed++
+	} else {
+		r.Passed++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// CheckCredential runs the credential assertions against a credential as it serializes
+func CheckCredential(credential *vc.VerifiableCredential) (*Report, error) {
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential: %w", err)
+	}
+	return CheckCredentialJSON(data)
+}
+
+// CheckCredentialJSON runs the credential assertions against a credential's JSON
+func CheckCredentialJSON(data []byte) (*Report, error) {
+	document, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Version: versionOf(document)}
+	checkCredential(report, "", document)
+	return report, nil
+}
+
+// CheckPresentation runs the presentation assertions against a presentation as it serializes,
+// and the credential assertions against each credential it presents
+func CheckPresentation(presentation *vc.VerifiablePresentation) (*Report, error) {
+	data, err := json.Marshal(presentation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode presentation: %w", err)
+	}
+	return CheckPresentationJSON(data)
+}
+
+// CheckPresentationJSON runs the presentation assertions against a presentation's JSON, and the
+// credential assertions against each credential it presents
+func CheckPresentationJSON(data []byte) (*Report, error) {
+	document, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Version: versionOf(document)}
+	for _, a := range presentationAssertions {
+		report.record("", a, a.check(document, report.Version))
+	}
+
+	credentials, _ := document["verifiableCredential"].([]interface{})
+	if single, ok := document["verifiableCredential"].(map[string]interface{}); ok {
+		credentials = []interface{}{single}
+	}
+	for i, item := range credentials {
+		prefix := fmt.Sprintf("verifiableCredential[%d].", i)
+		credential, ok := item.(map[string]interface{})
+		if !ok {
+			report.record(prefix, credentialIsObject, fmt.Errorf("got %T", item))
+			continue
+		}
+		report.record(prefix, credentialIsObject, nil)
+		checkCredential(report, prefix, credential)
+	}
+
+	return report, nil
+}
+
+// checkCredential runs the credential assertions, under the data model the credential declares
+func checkCredential(report *Report, prefix string, document map[string]interface{}) {
+	version := versionOf(document)
+	for _, a := range credentialAssertions {
+		if a.only != "" && a.only != version {
+			continue
+		}
+		report.record(prefix, a, a.check(document, version))
+	}
+}
+
+// decode parses a JSON object
+func decode(data []byte) (map[string]interface{}, error) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("document is not a JSON object: %w", err)
+	}
+	return document, nil
+}
+
+// versionOf returns the data model a document declares, 1.1 when it declares none
+func versionOf(document map[string]interface{}) vc.Version {
+	version, err := vc.DocumentVersion(document)
+	if err != nil {
+		return vc.Version1
+	}
+	return version
+}
@@ -0,0 +1,177 @@
+package conformance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+func credentialFixture(t *testing.T, version vc.Version) map[string]interface{} {
+	issued := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+	expires := issued.AddDate(1, 0, 0)
+
+	credential := &vc.VerifiableCredential{
+		Context:           vc.Version1.Contexts(),
+		ID:                "urn:uuid:credential",
+		Type:              []string{"VerifiableCredential"},
+		IssuerInfo:        vc.NewIssuer("did:example:issuer"),
+		IssuanceDate:      issued,
+		ExpirationDate:    &expires,
+		CredentialSubject: map[string]interface{}{"id": "did:example:holder", "ageOver18": true},
+		Proof:             &vc.Proof{Type: "BbsBlsSignature2020", Created: issued, ProofPurpose: "assertionMethod"},
+	}
+	require.NoError(t, credential.SetVersion(version))
+
+	data, err := json.Marshal(credential)
+	require.NoError(t, err)
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &document))
+	return document
+}
+
+// derivedFixture is credentialFixture as a presentation carries it
+func derivedFixture(t *testing.T, version vc.Version) *vc.DerivedCredential {
+	data, err := json.Marshal(credentialFixture(t, version))
+	require.NoError(t, err)
+	var derived vc.DerivedCredential
+	require.NoError(t, json.Unmarshal(data, &derived))
+	return &derived
+}
+
+func check(t *testing.T, document map[string]interface{}) *Report {
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+	report, err := CheckCredentialJSON(data)
+	require.NoError(t, err)
+	return report
+}
+
+func failed(report *Report) []string {
+	var ids []string
+	for _, result := range report.Failures() {
+		ids = append(ids, result.ID)
+	}
+	return ids
+}
+
+func TestCheckCredential(t *testing.T) {
+	for _, version := range []vc.Version{vc.Version1, vc.Version2} {
+		t.Run("Conforming "+string(version), func(t *testing.T) {
+			report := check(t, credentialFixture(t, version))
+			assert.True(t, report.OK(), report.String())
+			assert.Equal(t, version, report.Version)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		version vc.Version
+		mutate  func(document map[string]interface{})
+		want    string
+	}{
+		{"Missing Context", vc.Version1, func(d map[string]interface{}) { delete(d, "@context") }, "context"},
+		{"Context Not A Set", vc.Version1, func(d map[string]interface{}) { d["@context"] = "https://www.w3.org/2018/credentials/v1" }, "context"},
+		{"Foreign Base Context", vc.Version1, func(d map[string]interface{}) {
+			d["@context"] = []interface{}{"https://example.com/context", "https://www.w3.org/2018/credentials/v1"}
+		}, "context.base"},
+		{"Relative Context", vc.Version1, func(d map[string]interface{}) {
+			d["@context"] = append(d["@context"].([]interface{}), "contexts/local")
+		}, "context.items"},
+		{"Bare UUID Identifier", vc.Version1, func(d map[string]interface{}) { d["id"] = "8d3f6c2e" }, "credential.id"},
+		{"Missing Credential Type", vc.Version1, func(d map[string]interface{}) { d["type"] = []interface{}{"AgeCredential"} }, "credential.type"},
+		{"Missing Subject", vc.Version1, func(d map[string]interface{}) { delete(d, "credentialSubject") }, "credential.credentialSubject"},
+		{"Subject Not An Object", vc.Version1, func(d map[string]interface{}) { d["credentialSubject"] = "did:example:holder" }, "credential.credentialSubject"},
+		{"Missing Issuer", vc.Version1, func(d map[string]interface{}) { delete(d, "issuer") }, "credential.issuer"},
+		{"Issuer Object Without ID", vc.Version1, func(d map[string]interface{}) { d["issuer"] = map[string]interface{}{"name": "Issuer"} }, "credential.issuer"},
+		{"Missing Issuance Date", vc.Version1, func(d map[string]interface{}) { delete(d, "issuanceDate") }, "credential.issuanceDate"},
+		{"Malformed Expiration Date", vc.Version1, func(d map[string]interface{}) { d["expirationDate"] = "next year" }, "credential.expirationDate"},
+		{"Inverted Validity Period", vc.Version2, func(d map[string]interface{}) {
+			d["validFrom"], d["validUntil"] = d["validUntil"], d["validFrom"]
+		}, "credential.validityPeriod"},
+		{"Legacy Date Under 2.0", vc.Version2, func(d map[string]interface{}) { d["issuanceDate"] = "2030-01-02T03:04:05Z" }, "credential.legacyDates"},
+		{"Untyped Status Entry", vc.Version1, func(d map[string]interface{}) {
+			d["credentialStatus"] = []interface{}{map[string]interface{}{"id": "https://example.com/status#1"}}
+		}, "credential.credentialStatus"},
+		{"Missing Proof", vc.Version1, func(d map[string]interface{}) { delete(d, "proof") }, "proof"},
+		{"Untyped Proof", vc.Version1, func(d map[string]interface{}) { d["proof"] = map[string]interface{}{"proofValue": "z"} }, "proof"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := credentialFixture(t, tt.version)
+			tt.mutate(document)
+
+			report := check(t, document)
+			assert.False(t, report.OK())
+			assert.Contains(t, failed(report), tt.want, report.String())
+		})
+	}
+
+	t.Run("Non-Object Documents Are Rejected", func(t *testing.T) {
+		_, err := CheckCredentialJSON([]byte(`["not", "a", "credential"]`))
+		assert.Error(t, err)
+	})
+}
+
+func TestCheckPresentation(t *testing.T) {
+	presentation := func(t *testing.T) *vc.VerifiablePresentation {
+		return &vc.VerifiablePresentation{
+			Context: vc.Version2.Contexts(),
+			ID:      "urn:uuid:presentation",
+			Type:    []string{"VerifiablePresentation"},
+			Holder:  "did:example:holder",
+			VerifiableCredential: []*vc.DerivedCredential{
+				derivedFixture(t, vc.Version2),
+				derivedFixture(t, vc.Version1),
+			},
+			Proof: &vc.Proof{Type: "BbsBlsSignatureProof2020", ProofPurpose: "authentication"},
+		}
+	}
+
+	t.Run("Conforming", func(t *testing.T) {
+		report, err := CheckPresentation(presentation(t))
+		require.NoError(t, err)
+		assert.True(t, report.OK(), report.String())
+	})
+
+	t.Run("Embedded Credentials Are Checked Under Their Own Version", func(t *testing.T) {
+		p := presentation(t)
+		p.VerifiableCredential[1].IssuanceDate = nil
+
+		report, err := CheckPresentation(p)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"verifiableCredential[1].credential.issuanceDate"}, failed(report))
+	})
+
+	t.Run("Presentation Assertions", func(t *testing.T) {
+		p := presentation(t)
+		p.ID = "8d3f6c2e"
+		p.Type = []string{"Presentation"}
+		p.Holder = "holder"
+		p.Proof = nil
+
+		// Presentations only carry derived credentials as objects, so the JWT is added to the JSON
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &document))
+		document["verifiableCredential"] = append(document["verifiableCredential"].([]interface{}), "a JWT")
+		data, err = json.Marshal(document)
+		require.NoError(t, err)
+
+		report, err := CheckPresentationJSON(data)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"presentation.id",
+			"presentation.type",
+			"presentation.holder",
+			"presentation.verifiableCredential",
+			"proof",
+			"verifiableCredential[2].object",
+		}, failed(report))
+	})
+}
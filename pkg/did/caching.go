@@ -0,0 +1,119 @@
+package did
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+)
+
+// cachedDocument is a resolved DID document and when it must be resolved again
+type cachedDocument struct {
+	doc     *DIDDocument
+	expires time.Time
+}
+
+// CachingService resolves DIDs through a cache so verifiers checking many presentations do not
+// resolve the same issuer over and over. DIDs the wrapped service resolves are cached for ttl;
+// did:web DIDs it does not know are fetched over HTTPS, cached for the fetcher's TTL and revalidated
+// with their ETag. Key changes made through the wrapper invalidate the DID's cached document.
+type CachingService struct {
+	DIDService
+	fetcher *fetch.Fetcher
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	docs  map[string]cachedDocument
+	stats fetch.Stats
+}
+
+// NewCachingService wraps a DID service with a resolution cache
+func NewCachingService(inner DIDService, fetcher *fetch.Fetcher, ttl time.Duration) *CachingService {
+	return &CachingService{
+		DIDService: inner,
+		fetcher:    fetcher,
+		ttl:        ttl,
+		docs:       make(map[string]cachedDocument),
+	}
+}
+
+// ResolveDID resolves a DID to its DID document, from the cache when possible
+func (c *CachingService) ResolveDID(didString string) (*DIDDocument, error) {
+	c.mu.Lock()
+	cached, ok := c.docs[didString]
+	if ok && time.Now().Before(cached.expires) {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return cached.doc, nil
+	}
+	c.mu.Unlock()
+
+	doc, err := c.DIDService.ResolveDID(didString)
+	if err != nil && IsWebDID(didString) {
+		// The fetcher keeps its own cache and counts its own hits
+		return ResolveWebDID(context.Background(), c.fetcher, didString)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.stats.Errors++
+		return nil, err
+	}
+
+	c.stats.Misses++
+	c.docs[didString] = cachedDocument{doc: doc, expires: time.Now().Add(c.ttl)}
+	return doc, nil
+}
+
+// VerifyWithDID verifies an Ed25519 signature against a verification method of the DID, resolved through the cache
+func (c *CachingService) VerifyWithDID(didString string, keyID string, payload []byte, signature []byte) error {
+	if !IsVerificationMethodOf(keyID, didString) {
+		return fmt.Errorf("key %s is not controlled by %s", keyID, didString)
+	}
+
+	doc, err := c.ResolveDID(didString)
+	if err != nil {
+		return fmt.Errorf("failed to resolve DID: %w", err)
+	}
+
+	return verifyWithDocument(doc, keyID, payload, signature)
+}
+
+// CreateDIDDocument creates and stores a DID document, dropping any cached copy of an earlier one
+func (c *CachingService) CreateDIDDocument(did *DID, keyPair *KeyPair) (*DIDDocument, error) {
+	defer c.Invalidate(did.String())
+	return c.DIDService.CreateDIDDocument(did, keyPair)
+}
+
+// AddVerificationMethod publishes a method and drops the DID's cached document
+func (c *CachingService) AddVerificationMethod(didString string, method VerificationMethod, replaces string) (*DIDDocument, error) {
+	defer c.Invalidate(didString)
+	return c.DIDService.AddVerificationMethod(didString, method, replaces)
+}
+
+// RotateKey rotates the DID's current key and drops its cached document
+func (c *CachingService) RotateKey(didString string) (*KeyPair, error) {
+	defer c.Invalidate(didString)
+	return c.DIDService.RotateKey(didString)
+}
+
+// Invalidate drops a DID's cached document, so the next resolution loads it again
+func (c *CachingService) Invalidate(didString string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.docs, didString)
+}
+
+// Stats returns how resolutions were answered, did:web fetches included
+func (c *CachingService) Stats() fetch.Stats {
+	c.mu.Lock()
+	stats := c.stats
+	c.mu.Unlock()
+
+	return stats.Add(c.fetcher.Stats())
+}
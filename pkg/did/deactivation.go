@@ -0,0 +1,39 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// ErrNotFound is returned for a DID no document was stored for, or whose deactivated document
+// has been purged
+var ErrNotFound = errors.New("DID document not found")
+
+// ErrDeactivated is returned for a DID whose document was deactivated
+var ErrDeactivated = errors.New("DID document deactivated")
+
+// DeactivatedError reports a deactivated DID with the tombstone recording its deactivation
+type DeactivatedError struct {
+	DID       string
+	Tombstone storage.Tombstone
+}
+
+// Error implements error
+func (e *DeactivatedError) Error() string {
+	if e.Tombstone.Reason == "" {
+		return fmt.Sprintf("%s: %s", ErrDeactivated, e.DID)
+	}
+	return fmt.Sprintf("%s: %s (%s)", ErrDeactivated, e.DID, e.Tombstone.Reason)
+}
+
+// Is matches ErrDeactivated
+func (e *DeactivatedError) Is(target error) bool {
+	return target == ErrDeactivated
+}
+
+// notFound returns the error for a DID without a document
+func notFound(did string) error {
+	return fmt.Errorf("%w: %s", ErrNotFound, did)
+}
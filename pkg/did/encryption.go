@@ -0,0 +1,237 @@
+package did
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// EncryptionAlgorithm identifies the envelope scheme (ECDH-ES key agreement + AES-256-GCM)
+const EncryptionAlgorithm = "ECDH-ES+A256GCM"
+
+// Multicodec prefix for X25519 public keys (varint encoded 0xec)
+var x25519MulticodecPrefix = []byte{0xec, 0x01}
+
+// AgreementKeyPair represents an X25519 key agreement key pair
+type AgreementKeyPair struct {
+	PublicKey  *ecdh.PublicKey  `json:"-"`
+	PrivateKey *ecdh.PrivateKey `json:"-"`
+	KeyID      string           `json:"keyId"`
+}
+
+// EncryptedEnvelope is a payload encrypted to a DID's key agreement key
+type EncryptedEnvelope struct {
+	Algorithm    string `json:"alg"`
+	RecipientKID string `json:"kid"`
+	EphemeralKey string `json:"epk"`
+	Nonce        string `json:"iv"`
+	Ciphertext   string `json:"ciphertext"`
+}
+
+// generateAgreementKeyPair creates an X25519 key pair for the given DID
+func generateAgreementKeyPair(did *DID) (*AgreementKeyPair, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key agreement key: %w", err)
+	}
+
+	return &AgreementKeyPair{
+		PublicKey:  privateKey.PublicKey(),
+		PrivateKey: privateKey,
+		KeyID:      did.String() + "#key-agreement-1",
+	}, nil
+}
+
+// EncryptForDID encrypts plaintext to the first key agreement key of the recipient DID
+func (s *ServiceImpl) EncryptForDID(recipientDID string, plaintext []byte) (*EncryptedEnvelope, error) {
+	doc, err := s.ResolveDID(recipientDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve recipient DID: %w", err)
+	}
+
+	if len(doc.KeyAgreement) == 0 {
+		return nil, fmt.Errorf("DID %s has no key agreement key", recipientDID)
+	}
+
+	keyID := doc.KeyAgreement[0]
+	var recipientKey *ecdh.PublicKey
+	for _, vm := range doc.VerificationMethod {
+		if vm.ID == keyID {
+			recipientKey, err = decodeX25519Multibase(vm.PublicKeyMultibase)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key agreement key: %w", err)
+			}
+			break
+		}
+	}
+	if recipientKey == nil {
+		return nil, fmt.Errorf("key agreement method %s not found in DID document", keyID)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+
+	sharedSecret, err := ephemeral.ECDH(recipientKey)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement failed: %w", err)
+	}
+
+	gcm, err := newEnvelopeCipher(sharedSecret, ephemeral.PublicKey().Bytes(), keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, []byte(keyID))
+
+	return &EncryptedEnvelope{
+		Algorithm:    EncryptionAlgorithm,
+		RecipientKID: keyID,
+		EphemeralKey: encodeX25519Multibase(ephemeral.PublicKey()),
+		Nonce:        base64.RawURLEncoding.EncodeToString(nonce),
+		Ciphertext:   base64.RawURLEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// DecryptWithDID decrypts an envelope using the locally held key agreement key it was addressed to
+func (s *ServiceImpl) DecryptWithDID(envelope *EncryptedEnvelope) ([]byte, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("envelope is nil")
+	}
+
+	if envelope.Algorithm != EncryptionAlgorithm {
+		return nil, fmt.Errorf("unsupported envelope algorithm: %s", envelope.Algorithm)
+	}
+
+	privateKeyBytes, err := s.keys.Get(agreementKeyPrefix + envelope.RecipientKID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("no key agreement key found for key ID: %s", envelope.RecipientKID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key agreement key: %w", err)
+	}
+	privateKey, err := ecdh.X25519().NewPrivateKey(privateKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key agreement key: %w", err)
+	}
+
+	return DecryptEnvelope(envelope, privateKey)
+}
+
+// DecryptEnvelope decrypts an envelope with the private key of the key agreement key it was
+// addressed to, for recipients that keep their keys outside a DID service
+func DecryptEnvelope(envelope *EncryptedEnvelope, privateKey *ecdh.PrivateKey) ([]byte, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("envelope is nil")
+	}
+
+	if envelope.Algorithm != EncryptionAlgorithm {
+		return nil, fmt.Errorf("unsupported envelope algorithm: %s", envelope.Algorithm)
+	}
+
+	ephemeralKey, err := decodeX25519Multibase(envelope.EphemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key: %w", err)
+	}
+
+	nonce, err := base64.RawURLEncoding.DecodeString(envelope.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce encoding: %w", err)
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext encoding: %w", err)
+	}
+
+	sharedSecret, err := privateKey.ECDH(ephemeralKey)
+	if err != nil {
+		return nil, fmt.Errorf("key agreement failed: %w", err)
+	}
+
+	gcm, err := newEnvelopeCipher(sharedSecret, ephemeralKey.Bytes(), envelope.RecipientKID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(envelope.RecipientKID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt envelope: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// registerAgreementKeyPair makes a key agreement key available for DecryptWithDID
+func (s *ServiceImpl) registerAgreementKeyPair(keyPair *AgreementKeyPair) error {
+	if err := s.keys.Set(agreementKeyPrefix+keyPair.KeyID, keyPair.PrivateKey.Bytes(), 0); err != nil {
+		return fmt.Errorf("failed to store key agreement key: %w", err)
+	}
+	return nil
+}
+
+// newEnvelopeCipher derives the content encryption key from the ECDH shared secret
+func newEnvelopeCipher(sharedSecret, ephemeralPublicKey []byte, keyID string) (cipher.AEAD, error) {
+	info := append([]byte(EncryptionAlgorithm+"|"+keyID+"|"), ephemeralPublicKey...)
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, sharedSecret, nil, info), key); err != nil {
+		return nil, fmt.Errorf("failed to derive content encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// encodeX25519Multibase encodes an X25519 public key as multicodec + base58btc multibase
+func encodeX25519Multibase(publicKey *ecdh.PublicKey) string {
+	data := append(append([]byte{}, x25519MulticodecPrefix...), publicKey.Bytes()...)
+	return "z" + base58.Encode(data)
+}
+
+// decodeX25519Multibase decodes a multibase multicodec X25519 public key
+func decodeX25519Multibase(encoded string) (*ecdh.PublicKey, error) {
+	if !strings.HasPrefix(encoded, "z") {
+		return nil, fmt.Errorf("unsupported multibase encoding")
+	}
+
+	data := base58.Decode(encoded[1:])
+	if len(data) != len(x25519MulticodecPrefix)+32 {
+		return nil, fmt.Errorf("invalid key length: %d", len(data))
+	}
+	if data[0] != x25519MulticodecPrefix[0] || data[1] != x25519MulticodecPrefix[1] {
+		return nil, fmt.Errorf("unsupported multicodec prefix")
+	}
+
+	return ecdh.X25519().NewPublicKey(data[len(x25519MulticodecPrefix):])
+}
@@ -0,0 +1,81 @@
+package did
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptForDID(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+
+	recipient, recipientKeys, err := service.GenerateDID("test")
+	require.NoError(t, err)
+	_, err = service.CreateDIDDocument(recipient, recipientKeys)
+	require.NoError(t, err)
+
+	plaintext := []byte(`{"credential":"secret"}`)
+
+	t.Run("Round Trip", func(t *testing.T) {
+		envelope, err := service.EncryptForDID(recipient.String(), plaintext)
+		require.NoError(t, err)
+
+		assert.Equal(t, EncryptionAlgorithm, envelope.Algorithm)
+		assert.Equal(t, recipientKeys.KeyAgreement.KeyID, envelope.RecipientKID)
+		assert.NotContains(t, envelope.Ciphertext, "secret")
+
+		decrypted, err := service.DecryptWithDID(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("Tampered Ciphertext", func(t *testing.T) {
+		envelope, err := service.EncryptForDID(recipient.String(), plaintext)
+		require.NoError(t, err)
+
+		envelope.Ciphertext = "A" + envelope.Ciphertext[1:]
+		_, err = service.DecryptWithDID(envelope)
+		assert.Error(t, err)
+	})
+
+	t.Run("Wrong Recipient Key", func(t *testing.T) {
+		envelope, err := service.EncryptForDID(recipient.String(), plaintext)
+		require.NoError(t, err)
+
+		envelope.RecipientKID = "did:test:other#key-agreement-1"
+		_, err = service.DecryptWithDID(envelope)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no key agreement key found")
+	})
+
+	t.Run("Other Service Cannot Decrypt", func(t *testing.T) {
+		envelope, err := service.EncryptForDID(recipient.String(), plaintext)
+		require.NoError(t, err)
+
+		other := NewService(NewInMemoryRepository())
+		_, err = other.DecryptWithDID(envelope)
+		assert.Error(t, err)
+	})
+
+	t.Run("Peer DID Numalgo 2", func(t *testing.T) {
+		peerDID, _, err := service.GeneratePeerDID(PeerNumalgo2, "")
+		require.NoError(t, err)
+
+		envelope, err := service.EncryptForDID(peerDID.String(), plaintext)
+		require.NoError(t, err)
+
+		decrypted, err := service.DecryptWithDID(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("No Key Agreement", func(t *testing.T) {
+		peerDID, _, err := service.GeneratePeerDID(PeerNumalgo0, "")
+		require.NoError(t, err)
+
+		_, err = service.EncryptForDID(peerDID.String(), plaintext)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "has no key agreement key")
+	})
+}
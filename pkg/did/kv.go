@@ -0,0 +1,187 @@
+package did
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// Key store prefixes for the private keys a ServiceImpl holds
+const (
+	signingKeyPrefix   = "signing:"
+	agreementKeyPrefix = "agreement:"
+)
+
+// storedKeyPair is the form EncodeKeyPair writes; unlike KeyPair it keeps the key agreement private key
+type storedKeyPair struct {
+	KeyID               string `json:"keyId"`
+	PrivateKey          []byte `json:"privateKey"`
+	AgreementKeyID      string `json:"agreementKeyId,omitempty"`
+	AgreementPrivateKey []byte `json:"agreementPrivateKey,omitempty"`
+}
+
+// EncodeKeyPair serializes a key pair, including its key agreement key, for storage
+func EncodeKeyPair(keyPair *KeyPair) ([]byte, error) {
+	if keyPair == nil {
+		return nil, fmt.Errorf("key pair is nil")
+	}
+
+	stored := storedKeyPair{KeyID: keyPair.KeyID, PrivateKey: keyPair.PrivateKey}
+	if keyPair.KeyAgreement != nil && keyPair.KeyAgreement.PrivateKey != nil {
+		stored.AgreementKeyID = keyPair.KeyAgreement.KeyID
+		stored.AgreementPrivateKey = keyPair.KeyAgreement.PrivateKey.Bytes()
+	}
+	return json.Marshal(stored)
+}
+
+// DecodeKeyPair restores a key pair written by EncodeKeyPair
+func DecodeKeyPair(data []byte) (*KeyPair, error) {
+	var stored storedKeyPair
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key pair: %w", err)
+	}
+	if len(stored.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length: %d", len(stored.PrivateKey))
+	}
+
+	privateKey := ed25519.PrivateKey(stored.PrivateKey)
+	keyPair := &KeyPair{
+		PublicKey:  privateKey.Public().(ed25519.PublicKey),
+		PrivateKey: privateKey,
+		KeyID:      stored.KeyID,
+	}
+	if stored.AgreementPrivateKey != nil {
+		agreementKey, err := ecdh.X25519().NewPrivateKey(stored.AgreementPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key agreement key: %w", err)
+		}
+		keyPair.KeyAgreement = &AgreementKeyPair{
+			PublicKey:  agreementKey.PublicKey(),
+			PrivateKey: agreementKey,
+			KeyID:      stored.AgreementKeyID,
+		}
+	}
+	return keyPair, nil
+}
+
+// tombstoneKeyPrefix keys the tombstones of deactivated DIDs; DIDs themselves start with "did:"
+const tombstoneKeyPrefix = "tombstone:"
+
+// KVRepository is a DIDRepository kept in a key-value store, so documents created on one server
+// instance resolve on every other. Private keys are not stored here; see NewServiceWithKeyStore.
+type KVRepository struct {
+	store storage.KVStore
+	// retention is how long deactivated documents are kept; zero keeps them
+	retention time.Duration
+}
+
+// NewKVRepository creates a DID repository on top of store, keyed by DID, that keeps
+// deactivated documents
+func NewKVRepository(store storage.KVStore) DIDRepository {
+	return NewKVRepositoryWithRetention(store, 0)
+}
+
+// NewKVRepositoryWithRetention creates a DID repository on top of store that lets deactivated
+// documents expire retention after their deactivation; zero keeps them
+func NewKVRepositoryWithRetention(store storage.KVStore, retention time.Duration) DIDRepository {
+	return &KVRepository{store: store, retention: retention}
+}
+
+// Ping reports whether the underlying store is reachable
+func (r *KVRepository) Ping(ctx context.Context) error {
+	return r.store.Ping(ctx)
+}
+
+// Create stores a DID document
+func (r *KVRepository) Create(doc *DIDDocument) error {
+	if doc == nil {
+		return fmt.Errorf("DID document is nil")
+	}
+	if err := r.checkActive(doc.ID); err != nil {
+		return err
+	}
+	return r.put(doc, 0)
+}
+
+// Resolve retrieves a DID document by DID
+func (r *KVRepository) Resolve(did string) (*DIDDocument, error) {
+	if err := r.checkActive(did); err != nil {
+		return nil, err
+	}
+
+	data, err := r.store.Get(did)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, notFound(did)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID document: %w", err)
+	}
+
+	var doc DIDDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal DID document %s: %w", did, err)
+	}
+	return &doc, nil
+}
+
+// Update updates an existing DID document
+func (r *KVRepository) Update(did string, doc *DIDDocument) error {
+	if _, err := r.Resolve(did); err != nil {
+		return err
+	}
+	doc.Updated = time.Now()
+	return r.put(doc, 0)
+}
+
+// Deactivate keeps a DID document with the tombstone recording its deactivation. Both expire
+// together once the retention has passed, the tombstone last.
+func (r *KVRepository) Deactivate(did string, tombstone storage.Tombstone) error {
+	doc, err := r.Resolve(did)
+	if err != nil {
+		return err
+	}
+
+	if r.retention > 0 {
+		if err := r.put(doc, r.retention); err != nil {
+			return err
+		}
+	}
+	if err := storage.SetJSON(r.store, tombstoneKeyPrefix+did, tombstone.Stamped(time.Now()), r.retention); err != nil {
+		if r.retention > 0 {
+			// Keep the document that would otherwise expire while still active
+			_ = r.put(doc, 0)
+		}
+		return fmt.Errorf("failed to deactivate DID document: %w", err)
+	}
+	return nil
+}
+
+// checkActive fails for a DID with a tombstone
+func (r *KVRepository) checkActive(did string) error {
+	var tombstone storage.Tombstone
+	err := storage.GetJSON(r.store, tombstoneKeyPrefix+did, &tombstone)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check DID %s is active: %w", did, err)
+	}
+	return &DeactivatedError{DID: did, Tombstone: tombstone}
+}
+
+func (r *KVRepository) put(doc *DIDDocument, ttl time.Duration) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal DID document: %w", err)
+	}
+	if err := r.store.Set(doc.ID, data, ttl); err != nil {
+		return fmt.Errorf("failed to store DID document: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package did
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+func TestSharedKeyStore(t *testing.T) {
+	shared := storage.NewMemoryStore()
+	repo := NewKVRepository(storage.Prefixed(shared, "did:"))
+	first := NewServiceWithKeyStore(repo, storage.Prefixed(shared, "keys:"))
+	second := NewServiceWithKeyStore(repo, storage.Prefixed(shared, "keys:"))
+
+	did, keyPair, err := first.GenerateDID("example")
+	require.NoError(t, err)
+	_, err = first.CreateDIDDocument(did, keyPair)
+	require.NoError(t, err)
+
+	t.Run("Signs For A DID Generated Elsewhere", func(t *testing.T) {
+		signature, err := second.SignWithDID(keyPair.KeyID, []byte("payload"))
+		require.NoError(t, err)
+		assert.NoError(t, first.VerifyWithDID(did.String(), keyPair.KeyID, []byte("payload"), signature))
+	})
+
+	t.Run("Decrypts For A DID Generated Elsewhere", func(t *testing.T) {
+		envelope, err := first.EncryptForDID(did.String(), []byte("secret"))
+		require.NoError(t, err)
+		plaintext, err := second.DecryptWithDID(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(plaintext))
+	})
+
+	t.Run("Separate Stores Do Not Share Keys", func(t *testing.T) {
+		other := NewServiceWithKeyStore(repo, storage.NewMemoryStore())
+		_, err := other.SignWithDID(keyPair.KeyID, []byte("payload"))
+		assert.Error(t, err)
+	})
+}
+
+func TestEncodeKeyPair(t *testing.T) {
+	_, keyPair, err := NewService(NewInMemoryRepository()).GenerateDID("example")
+	require.NoError(t, err)
+
+	data, err := EncodeKeyPair(keyPair)
+	require.NoError(t, err)
+	decoded, err := DecodeKeyPair(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, keyPair.KeyID, decoded.KeyID)
+	assert.Equal(t, keyPair.PrivateKey, decoded.PrivateKey)
+	assert.Equal(t, keyPair.PublicKey, decoded.PublicKey)
+	require.NotNil(t, decoded.KeyAgreement)
+	assert.Equal(t, keyPair.KeyAgreement.KeyID, decoded.KeyAgreement.KeyID)
+	assert.True(t, keyPair.KeyAgreement.PrivateKey.Equal(decoded.KeyAgreement.PrivateKey))
+
+	_, err = DecodeKeyPair([]byte(`{"keyId":"k","privateKey":"AAAA"}`))
+	assert.Error(t, err)
+}
+
+func TestKVRepositoryDeactivation(t *testing.T) {
+	clk := clock.NewMock(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	repo := NewKVRepositoryWithRetention(storage.NewMemoryStoreWithClock(clk), time.Hour)
+
+	doc := &DIDDocument{ID: "did:test:kv"}
+	require.NoError(t, repo.Create(doc))
+	require.NoError(t, repo.Deactivate(doc.ID, storage.Tombstone{DeletedBy: "did:test:admin", Reason: "retired"}))
+
+	t.Run("Reported As Deactivated", func(t *testing.T) {
+		_, err := repo.Resolve(doc.ID)
+		var deactivated *DeactivatedError
+		require.ErrorAs(t, err, &deactivated)
+		assert.Equal(t, "retired", deactivated.Tombstone.Reason)
+		assert.Contains(t, err.Error(), "(retired)")
+		assert.ErrorIs(t, repo.Create(doc), ErrDeactivated)
+
+		_, err = repo.Resolve("did:test:unknown")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Purged After Retention", func(t *testing.T) {
+		clk.Advance(2 * time.Hour)
+		_, err := repo.Resolve(doc.ID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, repo.Create(doc), "a purged DID is forgotten")
+	})
+}
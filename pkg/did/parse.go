@@ -0,0 +1,244 @@
+package did
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxLength bounds the DIDs and DID URLs ParseDID and ParseDIDURL accept, so oversized input
+// never reaches a map key or a log line
+const MaxLength = 2048
+
+// ParseError explains why a string is not a DID or DID URL
+type ParseError struct {
+	Input string `json:"input"`
+	// Position is the byte offset of the offending character, or -1 when the input as a whole
+	// is at fault
+	Position int    `json:"position"`
+	Reason   string `json:"reason"`
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	if e.Position < 0 {
+		return fmt.Sprintf("invalid DID %q: %s", truncate(e.Input), e.Reason)
+	}
+	return fmt.Sprintf("invalid DID %q at position %d: %s", truncate(e.Input), e.Position, e.Reason)
+}
+
+// truncate shortens input for error messages
+func truncate(input string) string {
+	if len(input) > 64 {
+		return input[:64] + "..."
+	}
+	return input
+}
+
+// DIDURL is a DID with an optional path, query and fragment, such as the verification method
+// reference did:example:123#key-1
+type DIDURL struct {
+	DID      DID
+	Path     string
+	Query    string
+	Fragment string
+}
+
+// String returns the full DID URL
+func (u DIDURL) String() string {
+	s := u.DID.String() + u.Path
+	if u.Query != "" {
+		s += "?" + u.Query
+	}
+	if u.Fragment != "" {
+		s += "#" + u.Fragment
+	}
+	return s
+}
+
+// ParseDID parses a DID per the DID Core ABNF:
+//
+//	did                = "did:" method-name ":" method-specific-id
+//	method-name        = 1*method-char
+//	method-char        = %x61-7A / DIGIT
+//	method-specific-id = *( *idchar ":" ) 1*idchar
+//	idchar             = ALPHA / DIGIT / "." / "-" / "_" / pct-encoded
+//
+// Paths, queries and fragments are rejected; use ParseDIDURL for those.
+func ParseDID(s string) (*DID, error) {
+	did, end, err := parseDID(s)
+	if err != nil {
+		return nil, err
+	}
+	if end < len(s) {
+		return nil, &ParseError{Input: s, Position: end, Reason: fmt.Sprintf("unexpected %q; a DID has no path, query or fragment", s[end])}
+	}
+	return did, nil
+}
+
+// ParseDIDURL parses a DID URL: a DID followed by an optional RFC 3986 path, query and fragment
+func ParseDIDURL(s string) (*DIDURL, error) {
+	did, end, err := parseDID(s)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &DIDURL{DID: *did}
+	rest := s[end:]
+	offset := end
+
+	pathEnd := strings.IndexAny(rest, "?#")
+	if pathEnd < 0 {
+		pathEnd = len(rest)
+	}
+	u.Path = rest[:pathEnd]
+	if err := checkChars(s, offset, u.Path, isPathChar); err != nil {
+		return nil, err
+	}
+	rest, offset = rest[pathEnd:], offset+pathEnd
+
+	if strings.HasPrefix(rest, "?") {
+		queryEnd := strings.IndexByte(rest, '#')
+		if queryEnd < 0 {
+			queryEnd = len(rest)
+		}
+		u.Query = rest[1:queryEnd]
+		if err := checkChars(s, offset+1, u.Query, isQueryChar); err != nil {
+			return nil, err
+		}
+		rest, offset = rest[queryEnd:], offset+queryEnd
+	}
+
+	if strings.HasPrefix(rest, "#") {
+		u.Fragment = rest[1:]
+		if err := checkChars(s, offset+1, u.Fragment, isQueryChar); err != nil {
+			return nil, err
+		}
+	}
+
+	return u, nil
+}
+
+// ValidateMethodName checks a DID method name, such as the method new DIDs are generated under
+func ValidateMethodName(method string) error {
+	if method == "" {
+		return &ParseError{Input: method, Position: -1, Reason: "method name is empty"}
+	}
+	for i := 0; i < len(method); i++ {
+		if !isMethodChar(method[i]) {
+			return &ParseError{Input: method, Position: i, Reason: fmt.Sprintf("method names are lowercase letters and digits, not %q", method[i])}
+		}
+	}
+	return nil
+}
+
+// IsVerificationMethodOf reports whether keyID is a verification method reference of didString:
+// a DID URL for that DID with a fragment
+func IsVerificationMethodOf(keyID, didString string) bool {
+	u, err := ParseDIDURL(keyID)
+	if err != nil {
+		return false
+	}
+	return u.Fragment != "" && u.Path == "" && u.DID.String() == didString
+}
+
+// parseDID parses the DID at the start of s and returns where it ends
+func parseDID(s string) (*DID, int, error) {
+	if len(s) > MaxLength {
+		return nil, 0, &ParseError{Input: s, Position: -1, Reason: fmt.Sprintf("longer than %d bytes", MaxLength)}
+	}
+	if !strings.HasPrefix(s, "did:") {
+		return nil, 0, &ParseError{Input: s, Position: 0, Reason: `does not start with "did:"`}
+	}
+
+	i := len("did:")
+	methodStart := i
+	for i < len(s) && isMethodChar(s[i]) {
+		i++
+	}
+	if i == methodStart {
+		return nil, 0, &ParseError{Input: s, Position: i, Reason: "method name is empty or not lowercase letters and digits"}
+	}
+	if i == len(s) || s[i] != ':' {
+		return nil, 0, &ParseError{Input: s, Position: i, Reason: `expected ":" after the method name`}
+	}
+	method := s[methodStart:i]
+	i++
+
+	idStart := i
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ':' || isIDChar(c):
+			i++
+		case c == '%':
+			if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+				return nil, 0, &ParseError{Input: s, Position: i, Reason: "percent sign not followed by two hex digits"}
+			}
+			i += 3
+		case c == '/' || c == '?' || c == '#':
+			return finishDID(s, method, idStart, i)
+		default:
+			return nil, 0, &ParseError{Input: s, Position: i, Reason: fmt.Sprintf("character %q is not allowed in a method-specific identifier", c)}
+		}
+	}
+	return finishDID(s, method, idStart, i)
+}
+
+// finishDID checks the method-specific identifier s[start:end] is not empty and does not end
+// with a colon
+func finishDID(s, method string, start, end int) (*DID, int, error) {
+	if end == start {
+		return nil, 0, &ParseError{Input: s, Position: start, Reason: "method-specific identifier is empty"}
+	}
+	if s[end-1] == ':' {
+		return nil, 0, &ParseError{Input: s, Position: end - 1, Reason: "method-specific identifier ends with a colon"}
+	}
+	return &DID{Method: method, Identifier: s[start:end]}, end, nil
+}
+
+// checkChars checks every character of part, which starts at offset in s, and its percent-encodings
+func checkChars(s string, offset int, part string, allowed func(byte) bool) error {
+	for i := 0; i < len(part); i++ {
+		c := part[i]
+		if c == '%' {
+			if i+2 >= len(part) || !isHex(part[i+1]) || !isHex(part[i+2]) {
+				return &ParseError{Input: s, Position: offset + i, Reason: "percent sign not followed by two hex digits"}
+			}
+			i += 2
+			continue
+		}
+		if !allowed(c) {
+			return &ParseError{Input: s, Position: offset + i, Reason: fmt.Sprintf("character %q is not allowed here", c)}
+		}
+	}
+	return nil
+}
+
+func isMethodChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+func isIDChar(c byte) bool {
+	return isAlphaNum(c) || c == '.' || c == '-' || c == '_'
+}
+
+func isAlphaNum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// isPChar reports whether c is an RFC 3986 pchar other than a percent-encoding
+func isPChar(c byte) bool {
+	return isAlphaNum(c) || strings.IndexByte("-._~!$&'()*+,;=:@", c) >= 0
+}
+
+func isPathChar(c byte) bool {
+	return isPChar(c) || c == '/'
+}
+
+func isQueryChar(c byte) bool {
+	return isPChar(c) || c == '/' || c == '?'
+}
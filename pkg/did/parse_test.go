@@ -0,0 +1,101 @@
+package did
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDID(t *testing.T) {
+	for didString, expected := range map[string]DID{
+		"did:example:123456789abcdefghi":           {Method: "example", Identifier: "123456789abcdefghi"},
+		"did:web:example.com%3A8443:users:alice":   {Method: "web", Identifier: "example.com%3A8443:users:alice"},
+		"did:peer:2.Ez6LSb.Vz6Mkq.SeyJ0IjoiZG0ifQ": {Method: "peer", Identifier: "2.Ez6LSb.Vz6Mkq.SeyJ0IjoiZG0ifQ"},
+		"did:example::a":                           {Method: "example", Identifier: ":a"},
+		"did:ex4mple:A-b_c.d":                      {Method: "ex4mple", Identifier: "A-b_c.d"},
+	} {
+		did, err := ParseDID(didString)
+		require.NoError(t, err, didString)
+		assert.Equal(t, expected, *did)
+		assert.Equal(t, didString, did.String())
+	}
+
+	for didString, position := range map[string]int{
+		"":                          0,
+		"DID:example:123":           0,
+		"did:":                      4,
+		"did:Example:123":           4,
+		"did:example":               11,
+		"did:example:":              12,
+		"did:example:123:":          15,
+		"did:example:a b":           13,
+		"did:example:a%2":           13,
+		"did:example:a%zz":          13,
+		"did:example:a\n":           13,
+		"did:example:123#key-1":     15,
+		"did:example:<script>":      12,
+		"did:example:123?service=x": 15,
+	} {
+		_, err := ParseDID(didString)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr), "%q: %v", didString, err)
+		assert.Equal(t, position, parseErr.Position, didString)
+		assert.Equal(t, didString, parseErr.Input)
+		assert.NotEmpty(t, parseErr.Reason)
+	}
+
+	t.Run("Too Long", func(t *testing.T) {
+		_, err := ParseDID("did:example:" + strings.Repeat("a", MaxLength))
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, -1, parseErr.Position)
+		assert.Less(t, len(err.Error()), 200)
+	})
+}
+
+func TestParseDIDURL(t *testing.T) {
+	u, err := ParseDIDURL("did:example:123/path/to%20x?versionTime=2021-05-10T17:00:00Z#key-1")
+	require.NoError(t, err)
+	assert.Equal(t, DID{Method: "example", Identifier: "123"}, u.DID)
+	assert.Equal(t, "/path/to%20x", u.Path)
+	assert.Equal(t, "versionTime=2021-05-10T17:00:00Z", u.Query)
+	assert.Equal(t, "key-1", u.Fragment)
+	assert.Equal(t, "did:example:123/path/to%20x?versionTime=2021-05-10T17:00:00Z#key-1", u.String())
+
+	u, err = ParseDIDURL("did:example:123#key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", u.Fragment)
+	assert.Empty(t, u.Path)
+	assert.Empty(t, u.Query)
+
+	for _, didURL := range []string{
+		"did:example:123#key 1",
+		"did:example:123#key#1",
+		"did:example:123/a b",
+		"did:example:123?q=%",
+		"example:123#key-1",
+	} {
+		_, err := ParseDIDURL(didURL)
+		assert.Error(t, err, didURL)
+	}
+}
+
+func TestIsVerificationMethodOf(t *testing.T) {
+	assert.True(t, IsVerificationMethodOf("did:example:123#key-1", "did:example:123"))
+	assert.False(t, IsVerificationMethodOf("did:example:1234#key-1", "did:example:123"))
+	assert.False(t, IsVerificationMethodOf("did:example:123", "did:example:123"))
+	assert.False(t, IsVerificationMethodOf("did:example:123/keys#key-1", "did:example:123"))
+	assert.False(t, IsVerificationMethodOf("did:example:123#key 1", "did:example:123"))
+}
+
+func TestValidateMethodName(t *testing.T) {
+	for _, method := range []string{"example", "web", "peer", "v1"} {
+		assert.NoError(t, ValidateMethodName(method), method)
+	}
+	for _, method := range []string{"", "Example", "ex-ample", "ex:ample"} {
+		assert.Error(t, ValidateMethodName(method), method)
+	}
+}
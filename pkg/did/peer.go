@@ -0,0 +1,251 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// MethodPeer is the DID method name for did:peer
+const MethodPeer = "peer"
+
+// Supported did:peer numeric algorithms
+const (
+	PeerNumalgo0 = 0 // inception key without document
+	PeerNumalgo2 = 2 // multiple inception keys with service endpoints
+)
+
+// did:peer:2 purpose codes
+const (
+	peerPurposeAssertion    = 'A'
+	peerPurposeEncryption   = 'E'
+	peerPurposeVerification = 'V'
+	peerPurposeService      = 'S'
+)
+
+// Multicodec prefix for Ed25519 public keys (varint encoded 0xed)
+var ed25519MulticodecPrefix = []byte{0xed, 0x01}
+
+// peerService is the abbreviated service encoding used by did:peer:2
+type peerService struct {
+	Type            string `json:"t"`
+	ServiceEndpoint string `json:"s"`
+}
+
+// GeneratePeerDID generates a did:peer DID with a fresh Ed25519 key pair.
+// Numalgo 0 encodes only the inception key; numalgo 2 additionally encodes an
+// optional service endpoint so the counterparty can reach the holder.
+func (s *ServiceImpl) GeneratePeerDID(numalgo int, serviceEndpoint string) (*DID, *KeyPair, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	encodedKey := encodeEd25519Multibase(publicKey)
+
+	var agreementKeyPair *AgreementKeyPair
+	var identifier string
+	switch numalgo {
+	case PeerNumalgo0:
+		identifier = "0" + encodedKey
+	case PeerNumalgo2:
+		agreementKeyPair, err = generateAgreementKeyPair(&DID{})
+		if err != nil {
+			return nil, nil, err
+		}
+		identifier = "2." + string(peerPurposeVerification) + encodedKey +
+			"." + string(peerPurposeEncryption) + encodeX25519Multibase(agreementKeyPair.PublicKey)
+		if serviceEndpoint != "" {
+			svc, err := json.Marshal(peerService{Type: "dm", ServiceEndpoint: serviceEndpoint})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to encode service: %w", err)
+			}
+			identifier += "." + string(peerPurposeService) + base64.RawURLEncoding.EncodeToString(svc)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported did:peer numalgo: %d", numalgo)
+	}
+
+	did := &DID{
+		Method:     MethodPeer,
+		Identifier: identifier,
+	}
+
+	keyPair := &KeyPair{
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+		KeyID:      did.String() + "#key-1",
+	}
+
+	if err := s.registerKeyPair(keyPair); err != nil {
+		return nil, nil, err
+	}
+	if agreementKeyPair != nil {
+		// Keys are numbered in identifier order, so the agreement key is key-2
+		agreementKeyPair.KeyID = did.String() + "#key-2"
+		keyPair.KeyAgreement = agreementKeyPair
+		if err := s.registerAgreementKeyPair(agreementKeyPair); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return did, keyPair, nil
+}
+
+// IsPeerDID reports whether the given DID string uses the did:peer method
+func IsPeerDID(didString string) bool {
+	return strings.HasPrefix(didString, "did:"+MethodPeer+":")
+}
+
+// ResolvePeerDID derives the DID document for a did:peer DID from the DID itself
+func ResolvePeerDID(didString string) (*DIDDocument, error) {
+	if !IsPeerDID(didString) {
+		return nil, fmt.Errorf("not a did:peer DID: %s", didString)
+	}
+
+	identifier := strings.TrimPrefix(didString, "did:"+MethodPeer+":")
+	if identifier == "" {
+		return nil, fmt.Errorf("empty did:peer identifier")
+	}
+
+	doc := &DIDDocument{
+		Context: []string{
+			"https://www.w3.org/ns/did/v1",
+			"https://w3id.org/security/suites/ed25519-2020/v1",
+		},
+		ID: didString,
+	}
+
+	switch identifier[0] {
+	case '0':
+		if _, err := decodeEd25519Multibase(identifier[1:]); err != nil {
+			return nil, fmt.Errorf("invalid did:peer:0 key: %w", err)
+		}
+		keyID := didString + "#key-1"
+		doc.VerificationMethod = []VerificationMethod{{
+			ID:                 keyID,
+			Type:               "Ed25519VerificationKey2020",
+			Controller:         didString,
+			PublicKeyMultibase: identifier[1:],
+		}}
+		doc.Authentication = []string{keyID}
+		doc.AssertionMethod = []string{keyID}
+	case '2':
+		if err := resolvePeerNumalgo2(doc, identifier); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported did:peer numalgo: %c", identifier[0])
+	}
+
+	now := time.Now()
+	doc.Created = now
+	doc.Updated = now
+
+	return doc, nil
+}
+
+// resolvePeerNumalgo2 populates a document from the dot-separated did:peer:2 elements
+func resolvePeerNumalgo2(doc *DIDDocument, identifier string) error {
+	elements := strings.Split(identifier, ".")
+	if elements[0] != "2" {
+		return fmt.Errorf("invalid did:peer:2 identifier")
+	}
+
+	keyIndex := 0
+	serviceIndex := 0
+	for _, element := range elements[1:] {
+		if len(element) < 2 {
+			return fmt.Errorf("invalid did:peer:2 element: %q", element)
+		}
+
+		purpose, value := element[0], element[1:]
+		switch purpose {
+		case peerPurposeVerification, peerPurposeAssertion:
+			if _, err := decodeEd25519Multibase(value); err != nil {
+				return fmt.Errorf("invalid did:peer:2 key: %w", err)
+			}
+			keyIndex++
+			keyID := fmt.Sprintf("%s#key-%d", doc.ID, keyIndex)
+			doc.VerificationMethod = append(doc.VerificationMethod, VerificationMethod{
+				ID:                 keyID,
+				Type:               "Ed25519VerificationKey2020",
+				Controller:         doc.ID,
+				PublicKeyMultibase: value,
+			})
+			if purpose == peerPurposeVerification {
+				doc.Authentication = append(doc.Authentication, keyID)
+			}
+			doc.AssertionMethod = append(doc.AssertionMethod, keyID)
+		case peerPurposeEncryption:
+			if _, err := decodeX25519Multibase(value); err != nil {
+				return fmt.Errorf("invalid did:peer:2 key agreement key: %w", err)
+			}
+			keyIndex++
+			keyID := fmt.Sprintf("%s#key-%d", doc.ID, keyIndex)
+			doc.VerificationMethod = append(doc.VerificationMethod, VerificationMethod{
+				ID:                 keyID,
+				Type:               "X25519KeyAgreementKey2020",
+				Controller:         doc.ID,
+				PublicKeyMultibase: value,
+			})
+			doc.KeyAgreement = append(doc.KeyAgreement, keyID)
+		case peerPurposeService:
+			raw, err := base64.RawURLEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("invalid did:peer:2 service encoding: %w", err)
+			}
+			var svc peerService
+			if err := json.Unmarshal(raw, &svc); err != nil {
+				return fmt.Errorf("invalid did:peer:2 service: %w", err)
+			}
+			serviceType := svc.Type
+			if serviceType == "dm" {
+				serviceType = "DIDCommMessaging"
+			}
+			doc.Service = append(doc.Service, Service{
+				ID:              fmt.Sprintf("%s#service-%d", doc.ID, serviceIndex),
+				Type:            serviceType,
+				ServiceEndpoint: svc.ServiceEndpoint,
+			})
+			serviceIndex++
+		default:
+			return fmt.Errorf("unknown did:peer:2 purpose code: %c", purpose)
+		}
+	}
+
+	if len(doc.VerificationMethod) == 0 {
+		return fmt.Errorf("did:peer:2 must contain at least one verification key")
+	}
+
+	return nil
+}
+
+// encodeEd25519Multibase encodes an Ed25519 public key as multicodec + base58btc multibase
+func encodeEd25519Multibase(publicKey ed25519.PublicKey) string {
+	data := append(append([]byte{}, ed25519MulticodecPrefix...), publicKey...)
+	return "z" + base58.Encode(data)
+}
+
+// decodeEd25519Multibase decodes a multibase multicodec Ed25519 public key
+func decodeEd25519Multibase(encoded string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(encoded, "z") {
+		return nil, fmt.Errorf("unsupported multibase encoding")
+	}
+
+	data := base58.Decode(encoded[1:])
+	if len(data) != len(ed25519MulticodecPrefix)+ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid key length: %d", len(data))
+	}
+	if data[0] != ed25519MulticodecPrefix[0] || data[1] != ed25519MulticodecPrefix[1] {
+		return nil, fmt.Errorf("unsupported multicodec prefix")
+	}
+
+	return ed25519.PublicKey(data[len(ed25519MulticodecPrefix):]), nil
+}
@@ -0,0 +1,89 @@
+package did
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePeerDID(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+
+	t.Run("Numalgo 0", func(t *testing.T) {
+		did, keyPair, err := service.GeneratePeerDID(PeerNumalgo0, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, MethodPeer, did.Method)
+		assert.True(t, strings.HasPrefix(did.String(), "did:peer:0z6Mk"))
+		assert.Equal(t, did.String()+"#key-1", keyPair.KeyID)
+		assert.True(t, IsPeerDID(did.String()))
+	})
+
+	t.Run("Numalgo 2 With Service", func(t *testing.T) {
+		did, _, err := service.GeneratePeerDID(PeerNumalgo2, "https://wallet.example/inbox")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(did.String(), "did:peer:2.Vz6Mk"))
+		assert.Contains(t, did.Identifier, ".S")
+	})
+
+	t.Run("Unsupported Numalgo", func(t *testing.T) {
+		_, _, err := service.GeneratePeerDID(1, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported did:peer numalgo")
+	})
+
+	t.Run("Unique Per Call", func(t *testing.T) {
+		did1, _, err := service.GeneratePeerDID(PeerNumalgo0, "")
+		require.NoError(t, err)
+		did2, _, err := service.GeneratePeerDID(PeerNumalgo0, "")
+		require.NoError(t, err)
+		assert.NotEqual(t, did1.String(), did2.String())
+	})
+}
+
+func TestResolvePeerDID(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+
+	t.Run("Numalgo 0", func(t *testing.T) {
+		did, keyPair, err := service.GeneratePeerDID(PeerNumalgo0, "")
+		require.NoError(t, err)
+
+		doc, err := service.ResolveDID(did.String())
+		require.NoError(t, err)
+
+		assert.Equal(t, did.String(), doc.ID)
+		require.Len(t, doc.VerificationMethod, 1)
+		assert.Equal(t, keyPair.KeyID, doc.VerificationMethod[0].ID)
+		assert.Equal(t, encodeEd25519Multibase(keyPair.PublicKey), doc.VerificationMethod[0].PublicKeyMultibase)
+		assert.NoError(t, service.VerifyDIDDocument(doc))
+	})
+
+	t.Run("Numalgo 2", func(t *testing.T) {
+		did, _, err := service.GeneratePeerDID(PeerNumalgo2, "https://wallet.example/inbox")
+		require.NoError(t, err)
+
+		doc, err := ResolvePeerDID(did.String())
+		require.NoError(t, err)
+
+		require.Len(t, doc.VerificationMethod, 2)
+		assert.Equal(t, []string{doc.VerificationMethod[1].ID}, doc.KeyAgreement)
+		require.Len(t, doc.Service, 1)
+		assert.Equal(t, "DIDCommMessaging", doc.Service[0].Type)
+		assert.Equal(t, "https://wallet.example/inbox", doc.Service[0].ServiceEndpoint)
+		assert.NoError(t, service.VerifyDIDDocument(doc))
+	})
+
+	t.Run("Invalid Key", func(t *testing.T) {
+		_, err := ResolvePeerDID("did:peer:0z123")
+		assert.Error(t, err)
+	})
+
+	t.Run("Not Peer DID", func(t *testing.T) {
+		_, err := ResolvePeerDID("did:example:123")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not a did:peer DID")
+	})
+}
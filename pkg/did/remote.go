@@ -0,0 +1,96 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+)
+
+// ResolutionPath is where a server publishes the DID documents it manages, under its base URL;
+// the DID follows it, percent-encoded
+const ResolutionPath = "/api/dids/"
+
+// RemoteService resolves the DIDs the wrapped service does not know at peer servers, so an
+// issuer, wallet and verifier running as separate services can resolve each other's DIDs. Each
+// peer is asked in turn for {peer}/api/dids/{did}.
+type RemoteService struct {
+	DIDService
+	fetcher *fetch.Fetcher
+	peers   []string
+}
+
+// NewRemoteService wraps a DID service so DIDs it does not know are resolved at the peers' base URLs
+func NewRemoteService(inner DIDService, fetcher *fetch.Fetcher, peers []string) *RemoteService {
+	trimmed := make([]string, len(peers))
+	for i, peer := range peers {
+		trimmed[i] = strings.TrimSuffix(peer, "/")
+	}
+
+	return &RemoteService{DIDService: inner, fetcher: fetcher, peers: trimmed}
+}
+
+// ResolveDID resolves a DID locally, then at the first peer that publishes it. The DID is not
+// found only when every peer answers it does not know it; a peer that could not be reached makes
+// the resolution fail with an internal error instead.
+func (r *RemoteService) ResolveDID(didString string) (*DIDDocument, error) {
+	doc, err := r.DIDService.ResolveDID(didString)
+	// Only DIDs unknown here are looked up elsewhere
+	if err == nil || len(r.peers) == 0 || !errors.Is(err, ErrNotFound) {
+		return doc, err
+	}
+
+	var unreachable []error
+	for _, peer := range r.peers {
+		doc, peerErr := r.resolveAt(context.Background(), peer, didString)
+		switch {
+		case peerErr == nil:
+			return doc, nil
+		case errors.Is(peerErr, ErrDeactivated):
+			return nil, peerErr
+		case !errors.Is(peerErr, ErrNotFound):
+			unreachable = append(unreachable, peerErr)
+		}
+	}
+	if len(unreachable) > 0 {
+		return nil, fmt.Errorf("failed to resolve %s at its peers: %w", didString, errors.Join(unreachable...))
+	}
+	return nil, err
+}
+
+// VerifyWithDID verifies an Ed25519 signature against a verification method of the DID, resolved
+// locally or at a peer
+func (r *RemoteService) VerifyWithDID(didString string, keyID string, payload []byte, signature []byte) error {
+	if !IsVerificationMethodOf(keyID, didString) {
+		return fmt.Errorf("key %s is not controlled by %s", keyID, didString)
+	}
+
+	doc, err := r.ResolveDID(didString)
+	if err != nil {
+		return fmt.Errorf("failed to resolve DID: %w", err)
+	}
+
+	return verifyWithDocument(doc, keyID, payload, signature)
+}
+
+// resolveAt fetches a DID document from one peer and checks it describes the DID
+func (r *RemoteService) resolveAt(ctx context.Context, peer, didString string) (*DIDDocument, error) {
+	body, err := r.fetcher.Get(ctx, peer+ResolutionPath+url.PathEscape(didString))
+	if err != nil {
+		return nil, fetchError(didString, err)
+	}
+
+	var doc DIDDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("invalid DID document for %s from %s: %w", didString, peer, err)
+	}
+	if doc.ID != didString {
+		return nil, fmt.Errorf("%s returned the DID document of %s, not %s", peer, doc.ID, didString)
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,82 @@
+package did
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteService(t *testing.T) {
+	// The peer publishes the DIDs of its own service
+	published := NewService(NewInMemoryRepository())
+	peerDID, peerKeys, err := published.GenerateDID("example")
+	require.NoError(t, err)
+	_, err = published.CreateDIDDocument(peerDID, peerKeys)
+	require.NoError(t, err)
+
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		didString, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), ResolutionPath))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		doc, err := published.ResolveDID(didString)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(doc)
+	}))
+	defer peer.Close()
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	service := NewRemoteService(NewService(NewInMemoryRepository()), fetch.NewFetcher(nil, 0), []string{unreachable.URL, peer.URL + "/"})
+
+	t.Run("Resolves Local DIDs Locally", func(t *testing.T) {
+		localDID, localKeys, err := service.GenerateDID("example")
+		require.NoError(t, err)
+		_, err = service.CreateDIDDocument(localDID, localKeys)
+		require.NoError(t, err)
+
+		doc, err := service.ResolveDID(localDID.String())
+		require.NoError(t, err)
+		assert.Equal(t, localDID.String(), doc.ID)
+	})
+
+	t.Run("Resolves Other DIDs At Peers", func(t *testing.T) {
+		doc, err := service.ResolveDID(peerDID.String())
+		require.NoError(t, err)
+		assert.Equal(t, peerDID.String(), doc.ID)
+
+		payload := []byte("signed by the peer's DID")
+		signature, err := published.SignWithDID(peerKeys.KeyID, payload)
+		require.NoError(t, err)
+		assert.NoError(t, service.VerifyWithDID(peerDID.String(), peerKeys.KeyID, payload, signature))
+		assert.Error(t, service.VerifyWithDID(peerDID.String(), peerKeys.KeyID, []byte("altered"), signature))
+	})
+
+	t.Run("Unknown DIDs Fail", func(t *testing.T) {
+		_, err := service.ResolveDID("did:example:unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects Documents For Another DID", func(t *testing.T) {
+		impostor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(DIDDocument{ID: "did:example:attacker"})
+		}))
+		defer impostor.Close()
+
+		_, err := NewRemoteService(NewService(NewInMemoryRepository()), fetch.NewFetcher(nil, 0), []string{impostor.URL}).ResolveDID(peerDID.String())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did:example:attacker")
+	})
+}
@@ -0,0 +1,182 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+)
+
+const (
+	// ResolutionContext is the @context of a DID Resolution result
+	ResolutionContext = "https://w3id.org/did-resolution/v1"
+	// ResolutionMediaType is the media type a client accepts to receive a DID Resolution result
+	// rather than a bare DID document
+	ResolutionMediaType = `application/ld+json;profile="https://w3id.org/did-resolution"`
+)
+
+// DID Resolution error codes, reported in didResolutionMetadata.error
+const (
+	// ErrorInvalidDID: the input is not a conformant DID
+	ErrorInvalidDID = "invalidDid"
+	// ErrorNotFound: no DID document exists for the DID
+	ErrorNotFound = "notFound"
+	// ErrorDeactivated: the DID's document was deactivated
+	ErrorDeactivated = "deactivated"
+	// ErrorInternal: the DID could not be resolved for a reason other than the DID itself, such
+	// as an unreachable store or peer; resolving again may succeed
+	ErrorInternal = "internalError"
+)
+
+// ResolutionResult is the outcome of resolving a DID, per DID Core section 7.1
+type ResolutionResult struct {
+	Context               string             `json:"@context"`
+	DIDDocument           *DIDDocument       `json:"didDocument"`
+	DIDDocumentMetadata   DocumentMetadata   `json:"didDocumentMetadata"`
+	DIDResolutionMetadata ResolutionMetadata `json:"didResolutionMetadata"`
+}
+
+// DocumentMetadata describes the resolved DID document
+type DocumentMetadata struct {
+	Created     *time.Time `json:"created,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	Deactivated bool       `json:"deactivated,omitempty"`
+	// DeactivatedAt, DeactivatedBy and Reason come from the tombstone of a deactivated document
+	DeactivatedAt *time.Time `json:"deactivatedAt,omitempty"`
+	DeactivatedBy string     `json:"deactivatedBy,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+// ResolutionMetadata describes the resolution itself
+type ResolutionMetadata struct {
+	ContentType string `json:"contentType,omitempty"`
+	// Error is one of the error codes above when resolution failed
+	Error        string `json:"error,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// ResolutionError reports a DID that could not be resolved, with its DID Resolution error code
+type ResolutionError struct {
+	DID  string
+	Code string
+	Err  error
+}
+
+// Error implements error
+func (e *ResolutionError) Error() string {
+	return fmt.Sprintf("failed to resolve %s (%s): %v", e.DID, e.Code, e.Err)
+}
+
+// Unwrap returns the error resolution failed with
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// Transient reports whether resolving the DID again may succeed: whether its document could
+// not be reached, rather than the DID being invalid, unknown or deactivated
+func (e *ResolutionError) Transient() bool {
+	return e.Code == ErrorInternal
+}
+
+// NewResolutionError classifies the error resolving a DID failed with
+func NewResolutionError(didString string, err error) *ResolutionError {
+	return &ResolutionError{DID: didString, Code: resolutionErrorCode(err), Err: err}
+}
+
+// IsTransient reports whether err is a ResolutionError resolving again may recover from
+func IsTransient(err error) bool {
+	var resolutionErr *ResolutionError
+	return errors.As(err, &resolutionErr) && resolutionErr.Transient()
+}
+
+// Resolve resolves a DID through service into a DID Resolution result
+func Resolve(service DIDService, didString string) *ResolutionResult {
+	if _, err := ParseDID(didString); err != nil {
+		return NewResolutionResult(didString, nil, err)
+	}
+	doc, err := service.ResolveDID(didString)
+	return NewResolutionResult(didString, doc, err)
+}
+
+// NewResolutionResult describes the outcome of resolving a DID to doc, or failing with err
+func NewResolutionResult(didString string, doc *DIDDocument, err error) *ResolutionResult {
+	result := &ResolutionResult{Context: ResolutionContext}
+	if err != nil {
+		result.DIDResolutionMetadata = ResolutionMetadata{Error: resolutionErrorCode(err), ErrorMessage: err.Error()}
+		var deactivated *DeactivatedError
+		if errors.As(err, &deactivated) {
+			deactivatedAt := deactivated.Tombstone.DeletedAt
+			result.DIDDocumentMetadata = DocumentMetadata{
+				Deactivated:   true,
+				DeactivatedAt: &deactivatedAt,
+				DeactivatedBy: deactivated.Tombstone.DeletedBy,
+				Reason:        deactivated.Tombstone.Reason,
+			}
+		} else if errors.Is(err, ErrDeactivated) {
+			result.DIDDocumentMetadata.Deactivated = true
+		}
+		return result
+	}
+
+	result.DIDDocument = doc
+	result.DIDResolutionMetadata.ContentType = "application/did+ld+json"
+	if !doc.Created.IsZero() {
+		result.DIDDocumentMetadata.Created = &doc.Created
+	}
+	if !doc.Updated.IsZero() {
+		result.DIDDocumentMetadata.Updated = &doc.Updated
+	}
+	return result
+}
+
+// HTTPStatus is the status a DID Resolution result is served with
+func (r *ResolutionResult) HTTPStatus() int {
+	switch r.DIDResolutionMetadata.Error {
+	case "":
+		return http.StatusOK
+	case ErrorInvalidDID:
+		return http.StatusBadRequest
+	case ErrorNotFound:
+		return http.StatusNotFound
+	case ErrorDeactivated:
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// resolutionErrorCode maps a resolution failure onto its DID Resolution error code. Failures
+// that say nothing about the DID itself are internal errors.
+func resolutionErrorCode(err error) string {
+	var parseErr *ParseError
+	var resolutionErr *ResolutionError
+	switch {
+	case errors.As(err, &resolutionErr):
+		return resolutionErr.Code
+	case errors.As(err, &parseErr):
+		return ErrorInvalidDID
+	case errors.Is(err, ErrDeactivated):
+		return ErrorDeactivated
+	case errors.Is(err, ErrNotFound):
+		return ErrorNotFound
+	default:
+		return ErrorInternal
+	}
+}
+
+// fetchError maps the answer of a server publishing DID documents onto the resolution errors:
+// 404 Not Found to ErrNotFound and 410 Gone to ErrDeactivated
+func fetchError(didString string, err error) error {
+	var status *fetch.StatusError
+	if errors.As(err, &status) {
+		switch status.StatusCode {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %s (%v)", ErrNotFound, didString, err)
+		case http.StatusGone:
+			return fmt.Errorf("%w: %s (%v)", ErrDeactivated, didString, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package did
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+	did, keys, err := service.GenerateDID("example")
+	require.NoError(t, err)
+	_, err = service.CreateDIDDocument(did, keys)
+	require.NoError(t, err)
+
+	t.Run("Resolved", func(t *testing.T) {
+		result := Resolve(service, did.String())
+		require.NotNil(t, result.DIDDocument)
+		assert.Equal(t, did.String(), result.DIDDocument.ID)
+		assert.Empty(t, result.DIDResolutionMetadata.Error)
+		assert.NotNil(t, result.DIDDocumentMetadata.Created)
+		assert.Equal(t, http.StatusOK, result.HTTPStatus())
+	})
+
+	t.Run("Error Codes", func(t *testing.T) {
+		invalid := Resolve(service, "not-a-did")
+		assert.Equal(t, ErrorInvalidDID, invalid.DIDResolutionMetadata.Error)
+		assert.Nil(t, invalid.DIDDocument)
+
+		unknown := Resolve(service, "did:example:unknown")
+		assert.Equal(t, ErrorNotFound, unknown.DIDResolutionMetadata.Error)
+		assert.Equal(t, http.StatusNotFound, unknown.HTTPStatus())
+
+		repo := NewInMemoryRepository()
+		deactivatedService := NewService(repo)
+		deactivatedDID, deactivatedKeys, err := deactivatedService.GenerateDID("example")
+		require.NoError(t, err)
+		_, err = deactivatedService.CreateDIDDocument(deactivatedDID, deactivatedKeys)
+		require.NoError(t, err)
+		require.NoError(t, repo.Deactivate(deactivatedDID.String(), storage.Tombstone{Reason: "key compromised"}))
+
+		deactivated := Resolve(deactivatedService, deactivatedDID.String())
+		assert.Equal(t, ErrorDeactivated, deactivated.DIDResolutionMetadata.Error)
+		assert.True(t, deactivated.DIDDocumentMetadata.Deactivated)
+		assert.Equal(t, "key compromised", deactivated.DIDDocumentMetadata.Reason)
+		assert.Equal(t, http.StatusGone, deactivated.HTTPStatus())
+	})
+
+	t.Run("Transient Failures At Peers", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		empty := httptest.NewServer(http.NotFoundHandler())
+		defer empty.Close()
+		gone := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "deactivated", http.StatusGone)
+		}))
+		defer gone.Close()
+
+		notFound := NewRemoteService(NewService(NewInMemoryRepository()), fetch.NewFetcher(nil, 0), []string{empty.URL})
+		_, err := notFound.ResolveDID("did:example:unknown")
+		assert.ErrorIs(t, err, ErrNotFound, "every peer answered it does not know the DID")
+		assert.False(t, IsTransient(NewResolutionError("did:example:unknown", err)))
+
+		unavailable := NewRemoteService(NewService(NewInMemoryRepository()), fetch.NewFetcher(nil, 0), []string{empty.URL, failing.URL})
+		_, err = unavailable.ResolveDID("did:example:unknown")
+		assert.NotErrorIs(t, err, ErrNotFound, "a peer that could not answer may know the DID")
+		assert.True(t, IsTransient(NewResolutionError("did:example:unknown", err)))
+		assert.Equal(t, ErrorInternal, Resolve(unavailable, "did:example:unknown").DIDResolutionMetadata.Error)
+
+		deactivated := NewRemoteService(NewService(NewInMemoryRepository()), fetch.NewFetcher(nil, 0), []string{failing.URL, gone.URL})
+		_, err = deactivated.ResolveDID("did:example:unknown")
+		assert.ErrorIs(t, err, ErrDeactivated)
+	})
+}
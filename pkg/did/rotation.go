@@ -0,0 +1,181 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// ValidAt reports whether the verification method was in force at the given time.
+// Methods without a validity window are always valid.
+func (vm *VerificationMethod) ValidAt(t time.Time) bool {
+	if vm.ValidFrom != nil && t.Before(*vm.ValidFrom) {
+		return false
+	}
+	if vm.ValidUntil != nil && !t.Before(*vm.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// FindVerificationMethod returns the verification method with the given ID, current or retired
+func (doc *DIDDocument) FindVerificationMethod(keyID string) (*VerificationMethod, bool) {
+	for i := range doc.VerificationMethod {
+		if doc.VerificationMethod[i].ID == keyID {
+			return &doc.VerificationMethod[i], true
+		}
+	}
+	return nil, false
+}
+
+// VerificationMethodAt returns the verification method with the given ID if it was in force
+// at the given time, so material signed before a key rotation still resolves to the old key
+func (doc *DIDDocument) VerificationMethodAt(keyID string, at time.Time) (*VerificationMethod, error) {
+	method, ok := doc.FindVerificationMethod(keyID)
+	if !ok {
+		return nil, fmt.Errorf("verification method %s not found in DID document", keyID)
+	}
+
+	if !method.ValidAt(at) {
+		return nil, fmt.Errorf("verification method %s was not valid at %s", keyID, at.Format(time.RFC3339))
+	}
+
+	return method, nil
+}
+
+// CurrentVerificationMethod returns the first assertion method of the given type that has not
+// been retired
+func (doc *DIDDocument) CurrentVerificationMethod(keyType string) (*VerificationMethod, bool) {
+	for _, keyID := range doc.AssertionMethod {
+		method, ok := doc.FindVerificationMethod(keyID)
+		if ok && method.Type == keyType && method.ValidUntil == nil {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+// NextKeyID returns the next versioned key ID with the given fragment prefix, e.g. did#key-3
+// when did#key-1 and did#key-2 exist
+func (doc *DIDDocument) NextKeyID(prefix string) string {
+	base := doc.ID + "#" + prefix + "-"
+	latest := 0
+	for _, method := range doc.VerificationMethod {
+		if !strings.HasPrefix(method.ID, base) {
+			continue
+		}
+		if version, err := strconv.Atoi(strings.TrimPrefix(method.ID, base)); err == nil && version > latest {
+			latest = version
+		}
+	}
+	return base + strconv.Itoa(latest+1)
+}
+
+// AddVerificationMethod publishes a verification method in a DID document. When replaces names
+// a current method, that method is retired rather than removed: its validity window is closed
+// and the new method takes its place in the authentication and assertion relationships.
+// Otherwise the new method is added as an assertion method.
+func (s *ServiceImpl) AddVerificationMethod(didString string, method VerificationMethod, replaces string) (*DIDDocument, error) {
+	if IsPeerDID(didString) {
+		return nil, fmt.Errorf("peer DID documents cannot be updated")
+	}
+
+	current, err := s.repository.Resolve(didString)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := current.FindVerificationMethod(method.ID); exists {
+		return nil, fmt.Errorf("verification method %s already exists", method.ID)
+	}
+
+	now := time.Now()
+	if method.ValidFrom == nil {
+		method.ValidFrom = &now
+	}
+
+	// Work on a copy so readers of the stored document never see a partial update
+	doc := *current
+	doc.VerificationMethod = append([]VerificationMethod(nil), current.VerificationMethod...)
+	doc.Authentication = append([]string(nil), current.Authentication...)
+	doc.AssertionMethod = append([]string(nil), current.AssertionMethod...)
+
+	if replaces != "" {
+		retired, ok := doc.FindVerificationMethod(replaces)
+		if !ok {
+			return nil, fmt.Errorf("verification method %s not found in DID document", replaces)
+		}
+		if retired.ValidUntil != nil {
+			return nil, fmt.Errorf("verification method %s is already retired", replaces)
+		}
+		retired.ValidUntil = method.ValidFrom
+
+		replaceReference(doc.Authentication, replaces, method.ID)
+		replaceReference(doc.AssertionMethod, replaces, method.ID)
+	} else {
+		doc.AssertionMethod = append(doc.AssertionMethod, method.ID)
+	}
+
+	doc.VerificationMethod = append(doc.VerificationMethod, method)
+
+	if err := s.repository.Update(didString, &doc); err != nil {
+		return nil, fmt.Errorf("failed to update DID document: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// RotateKey replaces the DID's current Ed25519 key with a new versioned key.
+// The retired key stays in the document so signatures made before the rotation still verify.
+func (s *ServiceImpl) RotateKey(didString string) (*KeyPair, error) {
+	doc, err := s.ResolveDID(didString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve DID: %w", err)
+	}
+
+	if len(doc.Authentication) == 0 {
+		return nil, fmt.Errorf("DID %s has no authentication key to rotate", didString)
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	keyPair := &KeyPair{
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+		KeyID:      doc.NextKeyID("key"),
+	}
+
+	// Register the private key first so the key can sign as soon as it is published
+	if err := s.registerKeyPair(keyPair); err != nil {
+		return nil, err
+	}
+
+	_, err = s.AddVerificationMethod(didString, VerificationMethod{
+		ID:                 keyPair.KeyID,
+		Type:               "Ed25519VerificationKey2020",
+		Controller:         didString,
+		PublicKeyMultibase: "z" + base58.Encode(publicKey),
+	}, doc.Authentication[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return keyPair, nil
+}
+
+// replaceReference swaps a key ID in a verification relationship, keeping its position
+func replaceReference(references []string, old, replacement string) {
+	for i := range references {
+		if references[i] == old {
+			references[i] = replacement
+		}
+	}
+}
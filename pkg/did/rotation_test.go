@@ -0,0 +1,73 @@
+package did
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationMethodValidAt(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	method := VerificationMethod{ID: "did:example:123#key-1", ValidFrom: &from, ValidUntil: &until}
+
+	assert.False(t, method.ValidAt(from.Add(-time.Second)))
+	assert.True(t, method.ValidAt(from))
+	assert.True(t, method.ValidAt(until.Add(-time.Second)))
+	assert.False(t, method.ValidAt(until), "the window is closed at ValidUntil")
+
+	unbounded := VerificationMethod{ID: "did:example:123#key-2"}
+	assert.True(t, unbounded.ValidAt(time.Time{}))
+}
+
+func TestRotateKey(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+
+	did, keyPair, err := service.GenerateDID("test")
+	require.NoError(t, err)
+	_, err = service.CreateDIDDocument(did, keyPair)
+	require.NoError(t, err)
+
+	payload := []byte("signed before rotation")
+	oldSignature, err := service.SignWithDID(keyPair.KeyID, payload)
+	require.NoError(t, err)
+	beforeRotation := time.Now()
+
+	rotated, err := service.RotateKey(did.String())
+	require.NoError(t, err)
+	assert.Equal(t, did.String()+"#key-2", rotated.KeyID)
+
+	doc, err := service.ResolveDID(did.String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{rotated.KeyID}, doc.Authentication)
+	assert.Equal(t, rotated.KeyID, doc.AssertionMethod[0])
+
+	// The retired key is kept with a closed validity window
+	retired, ok := doc.FindVerificationMethod(keyPair.KeyID)
+	require.True(t, ok)
+	require.NotNil(t, retired.ValidUntil)
+
+	_, err = doc.VerificationMethodAt(keyPair.KeyID, beforeRotation)
+	assert.NoError(t, err)
+	_, err = doc.VerificationMethodAt(keyPair.KeyID, time.Now())
+	assert.ErrorContains(t, err, "was not valid")
+	_, err = doc.VerificationMethodAt(rotated.KeyID, time.Now())
+	assert.NoError(t, err)
+
+	// Signatures made with either key still verify
+	assert.NoError(t, service.VerifyWithDID(did.String(), keyPair.KeyID, payload, oldSignature))
+	newSignature, err := service.SignWithDID(rotated.KeyID, payload)
+	require.NoError(t, err)
+	assert.NoError(t, service.VerifyWithDID(did.String(), rotated.KeyID, payload, newSignature))
+
+	next, err := service.RotateKey(did.String())
+	require.NoError(t, err)
+	assert.Equal(t, did.String()+"#key-3", next.KeyID)
+
+	_, err = service.AddVerificationMethod(did.String(), VerificationMethod{ID: next.KeyID}, "")
+	assert.ErrorContains(t, err, "already exists")
+	_, err = service.AddVerificationMethod(did.String(), VerificationMethod{ID: did.String() + "#key-9"}, keyPair.KeyID)
+	assert.ErrorContains(t, err, "already retired")
+}
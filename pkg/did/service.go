@@ -1,28 +1,62 @@
 package did
 
 import (
+	"context"
 	"crypto/ed25519"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
 )
 
 // ServiceImpl implements DIDService interface
 type ServiceImpl struct {
 	repository DIDRepository
+
+	// keys holds the signing and key agreement private keys of locally generated DIDs, by key ID
+	keys storage.KVStore
+
+	// webDomain is the domain did:web DIDs are generated under, and their documents hosted on
+	webDomain string
 }
 
-// NewService creates a new DID service
+// NewService creates a new DID service that keeps private keys in memory
 func NewService(repo DIDRepository) DIDService {
+	return NewServiceWithKeyStore(repo, storage.NewMemoryStore())
+}
+
+// NewServiceWithKeyStore creates a new DID service that keeps private keys in keys. Every service
+// sharing the store can sign and decrypt for DIDs any of them generated, so the store must be
+// protected like the keys themselves.
+func NewServiceWithKeyStore(repo DIDRepository, keys storage.KVStore) DIDService {
 	return &ServiceImpl{
 		repository: repo,
+		keys:       keys,
 	}
 }
 
+// SetWebDomain makes did:web DIDs generate under domain, such as example.com or
+// localhost:8089, as did:web:{domain}:users:{id}. The server hosting domain must publish their
+// documents at /users/{id}/did.json.
+func (s *ServiceImpl) SetWebDomain(domain string) error {
+	if _, err := WebDIDForPath(domain, "/.well-known/"+WebDocumentFile); err != nil {
+		return err
+	}
+	s.webDomain = domain
+	return nil
+}
+
 // GenerateDID generates a new DID with key pair
 func (s *ServiceImpl) GenerateDID(method string) (*DID, *KeyPair, error) {
+	if err := ValidateMethodName(method); err != nil {
+		return nil, nil, err
+	}
+
 	// Generate Ed25519 key pair
 	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
@@ -31,6 +65,13 @@ func (s *ServiceImpl) GenerateDID(method string) (*DID, *KeyPair, error) {
 
 	// Create identifier from public key
 	identifier := base58.Encode(publicKey)
+	if method == MethodWeb {
+		// did:web DIDs name where their document is hosted
+		if s.webDomain == "" {
+			return nil, nil, fmt.Errorf("did:web DIDs need a web domain to be hosted on")
+		}
+		identifier = strings.ReplaceAll(s.webDomain, ":", "%3A") + ":" + WebUsersPath + ":" + identifier
+	}
 
 	did := &DID{
 		Method:     method,
@@ -43,6 +84,20 @@ func (s *ServiceImpl) GenerateDID(method string) (*DID, *KeyPair, error) {
 		KeyID:      did.String() + "#key-1",
 	}
 
+	// Generate X25519 key agreement key for encrypted delivery
+	agreementKeyPair, err := generateAgreementKeyPair(did)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPair.KeyAgreement = agreementKeyPair
+
+	if err := s.registerKeyPair(keyPair); err != nil {
+		return nil, nil, err
+	}
+	if err := s.registerAgreementKeyPair(agreementKeyPair); err != nil {
+		return nil, nil, err
+	}
+
 	return did, keyPair, nil
 }
 
@@ -70,12 +125,33 @@ func (s *ServiceImpl) CreateDIDDocument(did *DID, keyPair *KeyPair) (*DIDDocumen
 		Updated:            now,
 	}
 
+	if keyPair.KeyAgreement != nil {
+		doc.Context = append(doc.Context, "https://w3id.org/security/suites/x25519-2020/v1")
+		doc.VerificationMethod = append(doc.VerificationMethod, VerificationMethod{
+			ID:                 keyPair.KeyAgreement.KeyID,
+			Type:               "X25519KeyAgreementKey2020",
+			Controller:         did.String(),
+			PublicKeyMultibase: encodeX25519Multibase(keyPair.KeyAgreement.PublicKey),
+		})
+		doc.KeyAgreement = []string{keyPair.KeyAgreement.KeyID}
+	}
+
+	// Register the document so the DID can be resolved by counterparties
+	if err := s.repository.Create(doc); err != nil {
+		return nil, fmt.Errorf("failed to store DID document: %w", err)
+	}
+
 	return doc, nil
 }
 
 // ResolveDID resolves a DID to its DID Document
 func (s *ServiceImpl) ResolveDID(didString string) (*DIDDocument, error) {
-	return s.repository.Resolve(didString)
+	doc, err := s.repository.Resolve(didString)
+	if errors.Is(err, ErrNotFound) && IsPeerDID(didString) {
+		// did:peer documents are self-describing and need not be stored
+		return ResolvePeerDID(didString)
+	}
+	return doc, err
 }
 
 // VerifyDIDDocument verifies the integrity of a DID Document
@@ -112,45 +188,84 @@ func (s *ServiceImpl) VerifyDIDDocument(doc *DIDDocument) error {
 // InMemoryRepository implements DIDRepository interface for testing
 type InMemoryRepository struct {
 	documents map[string]*DIDDocument
+	// tombstones marks deactivated documents, which are purged retention after deactivation
+	tombstones map[string]storage.Tombstone
+	retention  time.Duration
 }
 
-// NewInMemoryRepository creates a new in-memory DID repository
+// NewInMemoryRepository creates a new in-memory DID repository that keeps deactivated documents
 func NewInMemoryRepository() DIDRepository {
+	return NewInMemoryRepositoryWithRetention(0)
+}
+
+// NewInMemoryRepositoryWithRetention creates a new in-memory DID repository that purges
+// deactivated documents retention after their deactivation; zero keeps them
+func NewInMemoryRepositoryWithRetention(retention time.Duration) DIDRepository {
 	return &InMemoryRepository{
-		documents: make(map[string]*DIDDocument),
+		documents:  make(map[string]*DIDDocument),
+		tombstones: make(map[string]storage.Tombstone),
+		retention:  retention,
 	}
 }
 
+// Ping reports whether the repository is reachable; an in-memory repository always is
+func (r *InMemoryRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
 // Create stores a DID document
 func (r *InMemoryRepository) Create(doc *DIDDocument) error {
 	if doc == nil {
 		return fmt.Errorf("DID document is nil")
 	}
+	if err := r.checkActive(doc.ID); err != nil {
+		return err
+	}
 	r.documents[doc.ID] = doc
 	return nil
 }
 
 // Resolve retrieves a DID document by DID
 func (r *InMemoryRepository) Resolve(did string) (*DIDDocument, error) {
+	if err := r.checkActive(did); err != nil {
+		return nil, err
+	}
 	doc, exists := r.documents[did]
 	if !exists {
-		return nil, fmt.Errorf("DID document not found: %s", did)
+		return nil, notFound(did)
 	}
 	return doc, nil
 }
 
 // Update updates an existing DID document
 func (r *InMemoryRepository) Update(did string, doc *DIDDocument) error {
-	if _, exists := r.documents[did]; !exists {
-		return fmt.Errorf("DID document not found: %s", did)
+	if _, err := r.Resolve(did); err != nil {
+		return err
 	}
 	doc.Updated = time.Now()
 	r.documents[did] = doc
 	return nil
 }
 
-// Deactivate removes a DID document
-func (r *InMemoryRepository) Deactivate(did string) error {
-	delete(r.documents, did)
+// Deactivate keeps a DID document with the tombstone recording its deactivation
+func (r *InMemoryRepository) Deactivate(did string, tombstone storage.Tombstone) error {
+	if _, err := r.Resolve(did); err != nil {
+		return err
+	}
+	r.tombstones[did] = tombstone.Stamped(time.Now())
 	return nil
 }
+
+// checkActive fails for a deactivated DID, purging its document once the retention has passed
+func (r *InMemoryRepository) checkActive(did string) error {
+	tombstone, deactivated := r.tombstones[did]
+	if !deactivated {
+		return nil
+	}
+	if tombstone.Purged(r.retention, time.Now()) {
+		delete(r.documents, did)
+		delete(r.tombstones, did)
+		return nil
+	}
+	return &DeactivatedError{DID: did, Tombstone: tombstone}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
 )
 
 func TestDIDString(t *testing.T) {
@@ -45,13 +47,18 @@ func TestCreateDIDDocument(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, did.String(), doc.ID)
-	assert.Len(t, doc.VerificationMethod, 1)
+	assert.Len(t, doc.VerificationMethod, 2)
 	assert.Equal(t, keyPair.KeyID, doc.VerificationMethod[0].ID)
 	assert.Equal(t, "Ed25519VerificationKey2020", doc.VerificationMethod[0].Type)
 	assert.Equal(t, did.String(), doc.VerificationMethod[0].Controller)
 
 	assert.Contains(t, doc.Authentication, keyPair.KeyID)
 	assert.Contains(t, doc.AssertionMethod, keyPair.KeyID)
+
+	// X25519 key agreement key for encrypted delivery
+	assert.Equal(t, keyPair.KeyAgreement.KeyID, doc.VerificationMethod[1].ID)
+	assert.Equal(t, "X25519KeyAgreementKey2020", doc.VerificationMethod[1].Type)
+	assert.Equal(t, []string{keyPair.KeyAgreement.KeyID}, doc.KeyAgreement)
 }
 
 func TestInMemoryRepository(t *testing.T) {
@@ -112,11 +119,21 @@ func TestInMemoryRepository(t *testing.T) {
 		err := repo.Create(doc)
 		require.NoError(t, err)
 
-		err = repo.Deactivate("did:test:deactivate")
+		err = repo.Deactivate("did:test:deactivate", storage.Tombstone{DeletedBy: "did:test:admin", Reason: "key compromise"})
 		require.NoError(t, err)
 
 		_, err = repo.Resolve("did:test:deactivate")
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrDeactivated)
+		assert.NotErrorIs(t, err, ErrNotFound)
+		var deactivated *DeactivatedError
+		require.ErrorAs(t, err, &deactivated)
+		assert.Equal(t, "did:test:admin", deactivated.Tombstone.DeletedBy)
+		assert.Equal(t, "key compromise", deactivated.Tombstone.Reason)
+		assert.False(t, deactivated.Tombstone.DeletedAt.IsZero())
+
+		assert.ErrorIs(t, repo.Create(doc), ErrDeactivated, "a deactivated DID cannot be recreated")
+		assert.ErrorIs(t, repo.Update("did:test:deactivate", doc), ErrDeactivated)
+		assert.ErrorIs(t, repo.Deactivate("did:test:nonexistent", storage.Tombstone{}), ErrNotFound)
 	})
 }
 
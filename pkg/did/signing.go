@@ -0,0 +1,174 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// SignWithDID signs a payload with the Ed25519 private key registered for keyID
+func (s *ServiceImpl) SignWithDID(keyID string, payload []byte) ([]byte, error) {
+	privateKey, err := s.keys.Get(signingKeyPrefix + keyID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("no private key found for key ID: %s", keyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load private key: %w", err)
+	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length: %d", len(privateKey))
+	}
+
+	return ed25519.Sign(ed25519.PrivateKey(privateKey), payload), nil
+}
+
+// VerifyWithDID verifies an Ed25519 signature against the verification method keyID of the resolved DID
+func (s *ServiceImpl) VerifyWithDID(didString string, keyID string, payload []byte, signature []byte) error {
+	if !IsVerificationMethodOf(keyID, didString) {
+		return fmt.Errorf("key %s is not controlled by %s", keyID, didString)
+	}
+
+	doc, err := s.ResolveDID(didString)
+	if err != nil {
+		return fmt.Errorf("failed to resolve DID: %w", err)
+	}
+
+	return verifyWithDocument(doc, keyID, payload, signature)
+}
+
+// verifyWithDocument verifies an Ed25519 signature against the verification method keyID of a resolved document
+func verifyWithDocument(doc *DIDDocument, keyID string, payload []byte, signature []byte) error {
+	var method *VerificationMethod
+	for i := range doc.VerificationMethod {
+		if doc.VerificationMethod[i].ID == keyID {
+			method = &doc.VerificationMethod[i]
+			break
+		}
+	}
+	if method == nil {
+		return fmt.Errorf("verification method %s not found in DID document", keyID)
+	}
+
+	publicKey, err := DecodePublicKeyMultibase(method.PublicKeyMultibase)
+	if err != nil {
+		return fmt.Errorf("invalid verification method key: %w", err)
+	}
+
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return fmt.Errorf("signature verification failed for key %s", keyID)
+	}
+
+	return nil
+}
+
+// DecodePublicKeyMultibase decodes an Ed25519 publicKeyMultibase value,
+// accepting both raw base58btc keys and multicodec-prefixed keys
+func DecodePublicKeyMultibase(encoded string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(encoded, "z") {
+		return nil, fmt.Errorf("unsupported multibase encoding")
+	}
+
+	if len(base58.Decode(encoded[1:])) == ed25519.PublicKeySize {
+		return ed25519.PublicKey(base58.Decode(encoded[1:])), nil
+	}
+
+	return decodeEd25519Multibase(encoded)
+}
+
+// DecodeBBSPublicKeyMultibase decodes the publicKeyMultibase value of a Bls12381G2Key2020 method
+func DecodeBBSPublicKeyMultibase(encoded string) ([]byte, error) {
+	if !strings.HasPrefix(encoded, "z") {
+		return nil, fmt.Errorf("unsupported multibase encoding")
+	}
+
+	publicKey := base58.Decode(encoded[1:])
+	if len(publicKey) != 192 {
+		return nil, fmt.Errorf("invalid BBS+ public key length: %d", len(publicKey))
+	}
+
+	return publicKey, nil
+}
+
+// BBSPublicKey decodes the key of a Bls12381G2Key2020 method from publicKeyMultibase or, as
+// some external issuers publish it, publicKeyBase58
+func BBSPublicKey(method VerificationMethod) ([]byte, error) {
+	if method.Type != "Bls12381G2Key2020" {
+		return nil, fmt.Errorf("%s is a %s, not a BBS+ key", method.ID, method.Type)
+	}
+	if method.PublicKeyMultibase != "" {
+		return DecodeBBSPublicKeyMultibase(method.PublicKeyMultibase)
+	}
+	if method.PublicKeyBase58 != "" {
+		return DecodeBBSPublicKeyMultibase("z" + method.PublicKeyBase58)
+	}
+	return nil, fmt.Errorf("%s publishes no key material", method.ID)
+}
+
+// EncodeSignatureMultibase encodes a signature as a base58btc multibase string
+func EncodeSignatureMultibase(signature []byte) string {
+	return "z" + base58.Encode(signature)
+}
+
+// DecodeSignatureMultibase decodes a base58btc multibase signature string
+func DecodeSignatureMultibase(encoded string) ([]byte, error) {
+	if !strings.HasPrefix(encoded, "z") {
+		return nil, fmt.Errorf("unsupported multibase encoding")
+	}
+
+	signature := base58.Decode(encoded[1:])
+	if len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid signature length: %d", len(signature))
+	}
+
+	return signature, nil
+}
+
+// ImportKeyPair registers an existing key pair, e.g. one restored from a backup,
+// so it can be used with SignWithDID and DecryptWithDID
+func (s *ServiceImpl) ImportKeyPair(keyPair *KeyPair) error {
+	if keyPair == nil {
+		return fmt.Errorf("key pair is nil")
+	}
+
+	if keyPair.KeyID == "" {
+		return fmt.Errorf("key ID is required")
+	}
+
+	if len(keyPair.PrivateKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid private key length: %d", len(keyPair.PrivateKey))
+	}
+
+	publicKey := keyPair.PrivateKey.Public().(ed25519.PublicKey)
+	if keyPair.PublicKey != nil && !publicKey.Equal(keyPair.PublicKey) {
+		return fmt.Errorf("public key does not match private key")
+	}
+	keyPair.PublicKey = publicKey
+
+	if agreement := keyPair.KeyAgreement; agreement != nil {
+		if agreement.KeyID == "" || agreement.PrivateKey == nil {
+			return fmt.Errorf("incomplete key agreement key")
+		}
+		if agreement.PublicKey != nil && !agreement.PrivateKey.PublicKey().Equal(agreement.PublicKey) {
+			return fmt.Errorf("key agreement public key does not match private key")
+		}
+		agreement.PublicKey = agreement.PrivateKey.PublicKey()
+		if err := s.registerAgreementKeyPair(agreement); err != nil {
+			return err
+		}
+	}
+
+	return s.registerKeyPair(keyPair)
+}
+
+// registerKeyPair makes a generated key pair available for SignWithDID
+func (s *ServiceImpl) registerKeyPair(keyPair *KeyPair) error {
+	if err := s.keys.Set(signingKeyPrefix+keyPair.KeyID, keyPair.PrivateKey, 0); err != nil {
+		return fmt.Errorf("failed to store private key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package did
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerifyWithDID(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+
+	did, keyPair, err := service.GenerateDID("test")
+	require.NoError(t, err)
+
+	_, err = service.CreateDIDDocument(did, keyPair)
+	require.NoError(t, err)
+
+	payload := []byte("presentation payload")
+
+	t.Run("Valid Signature", func(t *testing.T) {
+		signature, err := service.SignWithDID(keyPair.KeyID, payload)
+		require.NoError(t, err)
+
+		err = service.VerifyWithDID(did.String(), keyPair.KeyID, payload, signature)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Tampered Payload", func(t *testing.T) {
+		signature, err := service.SignWithDID(keyPair.KeyID, payload)
+		require.NoError(t, err)
+
+		err = service.VerifyWithDID(did.String(), keyPair.KeyID, []byte("tampered"), signature)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "signature verification failed")
+	})
+
+	t.Run("Unknown Key", func(t *testing.T) {
+		_, err := service.SignWithDID("did:test:unknown#key-1", payload)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no private key found")
+	})
+
+	t.Run("Key Of Another DID", func(t *testing.T) {
+		other, otherKeyPair, err := service.GenerateDID("test")
+		require.NoError(t, err)
+		_, err = service.CreateDIDDocument(other, otherKeyPair)
+		require.NoError(t, err)
+
+		signature, err := service.SignWithDID(otherKeyPair.KeyID, payload)
+		require.NoError(t, err)
+
+		err = service.VerifyWithDID(did.String(), otherKeyPair.KeyID, payload, signature)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "is not controlled by")
+	})
+
+	t.Run("Peer DID", func(t *testing.T) {
+		peerDID, peerKeyPair, err := service.GeneratePeerDID(PeerNumalgo0, "")
+		require.NoError(t, err)
+
+		signature, err := service.SignWithDID(peerKeyPair.KeyID, payload)
+		require.NoError(t, err)
+
+		err = service.VerifyWithDID(peerDID.String(), peerKeyPair.KeyID, payload, signature)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSignatureMultibase(t *testing.T) {
+	service := NewService(NewInMemoryRepository())
+	_, keyPair, err := service.GenerateDID("test")
+	require.NoError(t, err)
+
+	signature, err := service.SignWithDID(keyPair.KeyID, []byte("data"))
+	require.NoError(t, err)
+
+	encoded := EncodeSignatureMultibase(signature)
+	decoded, err := DecodeSignatureMultibase(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, signature, decoded)
+
+	_, err = DecodeSignatureMultibase("zabc")
+	assert.Error(t, err)
+}
+
+func TestImportKeyPair(t *testing.T) {
+	source := NewService(NewInMemoryRepository())
+	did, keyPair, err := source.GenerateDID("test")
+	require.NoError(t, err)
+	_, err = source.CreateDIDDocument(did, keyPair)
+	require.NoError(t, err)
+
+	payload := []byte("restored wallet")
+
+	t.Run("Imported Key Signs", func(t *testing.T) {
+		target := NewService(NewInMemoryRepository())
+		imported := &KeyPair{
+			PrivateKey: keyPair.PrivateKey,
+			KeyID:      keyPair.KeyID,
+			KeyAgreement: &AgreementKeyPair{
+				PrivateKey: keyPair.KeyAgreement.PrivateKey,
+				KeyID:      keyPair.KeyAgreement.KeyID,
+			},
+		}
+		require.NoError(t, target.ImportKeyPair(imported))
+		assert.Equal(t, keyPair.PublicKey, imported.PublicKey)
+
+		signature, err := target.SignWithDID(keyPair.KeyID, payload)
+		require.NoError(t, err)
+		assert.NoError(t, source.VerifyWithDID(did.String(), keyPair.KeyID, payload, signature))
+
+		envelope, err := source.EncryptForDID(did.String(), payload)
+		require.NoError(t, err)
+		plaintext, err := target.DecryptWithDID(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, payload, plaintext)
+	})
+
+	t.Run("Mismatched Public Key", func(t *testing.T) {
+		_, other, err := source.GenerateDID("test")
+		require.NoError(t, err)
+
+		err = NewService(NewInMemoryRepository()).ImportKeyPair(&KeyPair{
+			PublicKey:  other.PublicKey,
+			PrivateKey: keyPair.PrivateKey,
+			KeyID:      keyPair.KeyID,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match")
+	})
+
+	t.Run("Invalid Private Key", func(t *testing.T) {
+		err := NewService(NewInMemoryRepository()).ImportKeyPair(&KeyPair{
+			PrivateKey: []byte("short"),
+			KeyID:      keyPair.KeyID,
+		})
+		assert.Error(t, err)
+	})
+}
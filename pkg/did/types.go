@@ -3,6 +3,8 @@ package did
 import (
 	"crypto/ed25519"
 	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
 )
 
 // DID represents a Decentralized Identifier
@@ -29,12 +31,18 @@ type DIDDocument struct {
 	Updated            time.Time            `json:"updated"`
 }
 
-// VerificationMethod represents a verification method in DID Document
+// VerificationMethod represents a verification method in DID Document.
+// ValidFrom and ValidUntil bound the period the key was in force; a rotated key keeps its
+// entry with ValidUntil set so material it signed earlier still resolves.
 type VerificationMethod struct {
 	ID                 string `json:"id"`
 	Type               string `json:"type"`
 	Controller         string `json:"controller"`
 	PublicKeyMultibase string `json:"publicKeyMultibase"`
+	// PublicKeyBase58 is how some external issuers publish Bls12381G2Key2020 keys
+	PublicKeyBase58 string     `json:"publicKeyBase58,omitempty"`
+	ValidFrom       *time.Time `json:"validFrom,omitempty"`
+	ValidUntil      *time.Time `json:"validUntil,omitempty"`
 }
 
 // Service represents a service endpoint in DID Document
@@ -49,20 +57,35 @@ type KeyPair struct {
 	PublicKey  ed25519.PublicKey  `json:"publicKey"`
 	PrivateKey ed25519.PrivateKey `json:"privateKey"`
 	KeyID      string             `json:"keyId"`
+	// KeyAgreement is the X25519 key used to receive encrypted payloads
+	KeyAgreement *AgreementKeyPair `json:"keyAgreement,omitempty"`
 }
 
-// DIDRepository interface for DID operations
+// DIDRepository interface for DID operations. Resolving a DID without a document fails with
+// ErrNotFound, and a deactivated one with a *DeactivatedError.
 type DIDRepository interface {
 	Create(doc *DIDDocument) error
 	Resolve(did string) (*DIDDocument, error)
 	Update(did string, doc *DIDDocument) error
-	Deactivate(did string) error
+	// Deactivate keeps the DID's document with a tombstone, dated now unless it already is. A
+	// deactivated DID cannot be created or updated again until its document is purged.
+	Deactivate(did string, tombstone storage.Tombstone) error
 }
 
 // DIDService interface for DID business logic
 type DIDService interface {
 	GenerateDID(method string) (*DID, *KeyPair, error)
+	GeneratePeerDID(numalgo int, serviceEndpoint string) (*DID, *KeyPair, error)
 	CreateDIDDocument(did *DID, keyPair *KeyPair) (*DIDDocument, error)
 	ResolveDID(didString string) (*DIDDocument, error)
 	VerifyDIDDocument(doc *DIDDocument) error
+	SignWithDID(keyID string, payload []byte) ([]byte, error)
+	VerifyWithDID(did string, keyID string, payload []byte, signature []byte) error
+	EncryptForDID(recipientDID string, plaintext []byte) (*EncryptedEnvelope, error)
+	DecryptWithDID(envelope *EncryptedEnvelope) ([]byte, error)
+	ImportKeyPair(keyPair *KeyPair) error
+	// AddVerificationMethod publishes a method, retiring the method it replaces if any
+	AddVerificationMethod(didString string, method VerificationMethod, replaces string) (*DIDDocument, error)
+	// RotateKey replaces the DID's current Ed25519 key with a new versioned key
+	RotateKey(didString string) (*KeyPair, error)
 }
@@ -0,0 +1,105 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+)
+
+// MethodWeb is the did:web method, whose documents are published over HTTPS
+const MethodWeb = "web"
+
+// IsWebDID reports whether the given DID string uses the did:web method
+func IsWebDID(didString string) bool {
+	return strings.HasPrefix(didString, "did:"+MethodWeb+":")
+}
+
+// WebDocumentURL returns where a did:web document is published: did:web:example.com resolves to
+// https://example.com/.well-known/did.json and did:web:example.com:users:alice to
+// https://example.com/users/alice/did.json. A port is percent-encoded, as in example.com%3A8443.
+func WebDocumentURL(didString string) (string, error) {
+	if !IsWebDID(didString) {
+		return "", fmt.Errorf("not a did:web DID: %s", didString)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(didString, "did:"+MethodWeb+":"), ":")
+	host, err := url.PathUnescape(parts[0])
+	if err != nil || host == "" || strings.ContainsAny(host, "/?#@") {
+		return "", fmt.Errorf("invalid did:web host in %s", didString)
+	}
+
+	path := "/.well-known"
+	if len(parts) > 1 {
+		segments := make([]string, len(parts)-1)
+		for i, part := range parts[1:] {
+			segment, err := url.PathUnescape(part)
+			if err != nil || segment == "" || strings.ContainsAny(segment, "/?#") {
+				return "", fmt.Errorf("invalid did:web path in %s", didString)
+			}
+			segments[i] = url.PathEscape(segment)
+		}
+		path = "/" + strings.Join(segments, "/")
+	}
+
+	return "https://" + host + path + "/did.json", nil
+}
+
+// ResolveWebDID fetches a did:web document and checks it describes the DID
+func ResolveWebDID(ctx context.Context, fetcher *fetch.Fetcher, didString string) (*DIDDocument, error) {
+	documentURL, err := WebDocumentURL(didString)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := fetcher.Get(ctx, documentURL)
+	if err != nil {
+		return nil, fetchError(didString, err)
+	}
+
+	var doc DIDDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("invalid DID document for %s: %w", didString, err)
+	}
+
+	if doc.ID != didString {
+		return nil, fmt.Errorf("DID document at %s describes %s, not %s", documentURL, doc.ID, didString)
+	}
+
+	return &doc, nil
+}
+
+// WebDocumentFile is the name a did:web document is published under
+const WebDocumentFile = "did.json"
+
+// WebUsersPath is the path under which a server hosting did:web DIDs publishes the DIDs it
+// generates: did:web:example.com:users:{id} is served at /users/{id}/did.json
+const WebUsersPath = "users"
+
+// WebDIDForPath returns the did:web DID whose document is published at path on domain, the
+// inverse of WebDocumentURL: /.well-known/did.json on example.com is did:web:example.com and
+// /users/alice/did.json is did:web:example.com:users:alice. A port in domain is percent-encoded.
+func WebDIDForPath(domain, path string) (string, error) {
+	if domain == "" || strings.ContainsAny(domain, "/?#@") {
+		return "", fmt.Errorf("invalid did:web domain %q", domain)
+	}
+	id := "did:" + MethodWeb + ":" + strings.ReplaceAll(domain, ":", "%3A")
+
+	dir, ok := strings.CutSuffix(path, "/"+WebDocumentFile)
+	if !ok {
+		return "", fmt.Errorf("%s is not a did:web document path", path)
+	}
+	if dir == "/.well-known" {
+		return id, nil
+	}
+	for _, segment := range strings.Split(strings.TrimPrefix(dir, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%s is not a did:web document path", path)
+		}
+		id += ":" + url.PathEscape(segment)
+	}
+	return id, nil
+}
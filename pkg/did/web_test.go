@@ -0,0 +1,175 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webHost returns the did:web method-specific identifier of a test server, with its port encoded
+func webHost(serverURL string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(serverURL, "https://"), ":", "%3A")
+}
+
+func TestWebDocumentURL(t *testing.T) {
+	for didString, expected := range map[string]string{
+		"did:web:example.com":                  "https://example.com/.well-known/did.json",
+		"did:web:example.com:users:alice":      "https://example.com/users/alice/did.json",
+		"did:web:example.com%3A8443":           "https://example.com:8443/.well-known/did.json",
+		"did:web:example.com%3A8443:issuers:1": "https://example.com:8443/issuers/1/did.json",
+	} {
+		documentURL, err := WebDocumentURL(didString)
+		require.NoError(t, err, didString)
+		assert.Equal(t, expected, documentURL)
+	}
+
+	for _, didString := range []string{
+		"did:key:z6Mk",
+		"did:web:",
+		"did:web:example.com%2Fevil",
+		"did:web:example.com::alice",
+	} {
+		_, err := WebDocumentURL(didString)
+		assert.Error(t, err, didString)
+	}
+}
+
+func TestWebDIDForPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/.well-known/did.json":  "did:web:example.com%3A8443",
+		"/users/alice/did.json":  "did:web:example.com%3A8443:users:alice",
+		"/issuers/1/did.json":    "did:web:example.com%3A8443:issuers:1",
+		"/users/a b/did.json":    "did:web:example.com%3A8443:users:a%20b",
+		"/users/bob/../did.json": "",
+		"/users/bob/doc.json":    "",
+		"//did.json":             "",
+	} {
+		didString, err := WebDIDForPath("example.com:8443", path)
+		if expected == "" {
+			assert.Error(t, err, path)
+			continue
+		}
+		require.NoError(t, err, path)
+		assert.Equal(t, expected, didString)
+
+		// Resolvers look for the document where it is served
+		documentURL, err := WebDocumentURL(didString)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com:8443"+strings.ReplaceAll(path, " ", "%20"), documentURL)
+	}
+
+	t.Run("Generated Under The Web Domain", func(t *testing.T) {
+		service := NewService(NewInMemoryRepository())
+		_, _, err := service.GenerateDID(MethodWeb)
+		assert.Error(t, err, "did:web DIDs need a domain")
+
+		require.NoError(t, service.(*ServiceImpl).SetWebDomain("example.com:8443"))
+		did, _, err := service.GenerateDID(MethodWeb)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(did.String(), "did:web:example.com%3A8443:users:"), did.String())
+		_, err = ParseDID(did.String())
+		assert.NoError(t, err)
+
+		assert.Error(t, service.(*ServiceImpl).SetWebDomain("https://example.com"))
+	})
+}
+
+func TestCachingService(t *testing.T) {
+	t.Run("Caches Local Resolutions", func(t *testing.T) {
+		inner := NewService(NewInMemoryRepository())
+		service := NewCachingService(inner, fetch.NewFetcher(nil, time.Hour), time.Hour)
+
+		did, keyPair, err := service.GenerateDID("example")
+		require.NoError(t, err)
+		_, err = service.CreateDIDDocument(did, keyPair)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			doc, err := service.ResolveDID(did.String())
+			require.NoError(t, err)
+			assert.Equal(t, did.String(), doc.ID)
+		}
+		assert.Equal(t, fetch.Stats{Hits: 2, Misses: 1}, service.Stats())
+
+		_, err = service.ResolveDID("did:example:unknown")
+		assert.Error(t, err)
+		assert.Equal(t, uint64(1), service.Stats().Errors)
+	})
+
+	t.Run("Key Rotation Invalidates The Cache", func(t *testing.T) {
+		service := NewCachingService(NewService(NewInMemoryRepository()), fetch.NewFetcher(nil, time.Hour), time.Hour)
+
+		did, keyPair, err := service.GenerateDID("example")
+		require.NoError(t, err)
+		_, err = service.CreateDIDDocument(did, keyPair)
+		require.NoError(t, err)
+		_, err = service.ResolveDID(did.String())
+		require.NoError(t, err)
+
+		rotated, err := service.RotateKey(did.String())
+		require.NoError(t, err)
+
+		payload := []byte("signed after rotation")
+		signature, err := service.SignWithDID(rotated.KeyID, payload)
+		require.NoError(t, err)
+		assert.NoError(t, service.VerifyWithDID(did.String(), rotated.KeyID, payload, signature))
+	})
+
+	t.Run("Fetches did:web Documents With Revalidation", func(t *testing.T) {
+		var requests atomic.Int32
+		var didString string
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if r.URL.Path != "/issuers/acme/did.json" {
+				http.NotFound(w, r)
+				return
+			}
+			if r.Header.Get("If-None-Match") == `"doc-1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"doc-1"`)
+			json.NewEncoder(w).Encode(DIDDocument{ID: didString})
+		}))
+		defer ts.Close()
+
+		host := webHost(ts.URL)
+		didString = "did:web:" + host + ":issuers:acme"
+
+		service := NewCachingService(NewService(NewInMemoryRepository()), fetch.NewFetcher(ts.Client(), 0), time.Hour)
+		for i := 0; i < 2; i++ {
+			doc, err := service.ResolveDID(didString)
+			require.NoError(t, err)
+			assert.Equal(t, didString, doc.ID)
+		}
+		assert.Equal(t, int32(2), requests.Load())
+
+		stats := service.Stats()
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(1), stats.Revalidations)
+
+		_, err := service.ResolveDID("did:web:" + host + ":issuers:other")
+		assert.Error(t, err)
+	})
+
+	t.Run("Rejects did:web Documents For Another DID", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(DIDDocument{ID: "did:web:attacker.example"})
+		}))
+		defer ts.Close()
+
+		didString := "did:web:" + webHost(ts.URL)
+		_, err := ResolveWebDID(context.Background(), fetch.NewFetcher(ts.Client(), time.Hour), didString)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "describes did:web:attacker.example")
+	})
+}
@@ -0,0 +1,216 @@
+// Package events is the in-process bus the issuer, holder and verifier modules communicate over, so
+// callers depend on messages rather than on each other's use cases and the modules can later be
+// split into separate services
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultQueueSize is how many asynchronously published events may wait for delivery before
+// publishers block
+const DefaultQueueSize = 1024
+
+// ErrNoHandler is returned by Request when no module handles the request's type
+var ErrNoHandler = errors.New("no handler for request")
+
+// ErrClosed is returned when publishing on a closed bus
+var ErrClosed = errors.New("event bus is closed")
+
+// Event is a message on the bus. Data holds the payload type documented for Type.
+type Event struct {
+	ID   string      `json:"id"`
+	Type Type        `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// New creates an event with a fresh ID
+func New(t Type, data interface{}) Event {
+	return Event{ID: uuid.New().String(), Type: t, Time: time.Now(), Data: data}
+}
+
+// Subscriber is notified of published events
+type Subscriber func(ctx context.Context, event Event) error
+
+// Handler answers a request with the reply's payload
+type Handler func(ctx context.Context, event Event) (interface{}, error)
+
+type subscription struct {
+	id         int
+	subscriber Subscriber
+}
+
+// Bus delivers published events to every subscriber of their type, and requests to the single
+// handler of theirs. Publish and Request dispatch synchronously in the caller's goroutine;
+// PublishAsync queues events for a background worker, which delivers them in order.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[Type][]subscription
+	handlers    map[Type]Handler
+	nextID      int
+	onError     func(Event, error)
+
+	// queue is created by the first PublishAsync. closeMu is separate from mu so a publisher
+	// blocked on a full queue never holds up delivery.
+	queueOnce sync.Once
+	queue     chan Event
+	closeMu   sync.RWMutex
+	closed    bool
+	done      chan struct{}
+}
+
+// NewBus creates an empty bus. Errors of asynchronously delivered events are logged until
+// OnError replaces the handler.
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[Type][]subscription),
+		handlers:    make(map[Type]Handler),
+		onError: func(event Event, err error) {
+			log.Printf("⚠️  %s event %s: %v", event.Type, event.ID, err)
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// OnError sets the function called with errors returned by subscribers of asynchronously
+// published events
+func (b *Bus) OnError(handle func(Event, error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.onError = handle
+}
+
+// Subscribe registers a subscriber for events of a type. The returned function unsubscribes it.
+func (b *Bus) Subscribe(t Type, subscriber Subscriber) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.subscribers[t] = append(b.subscribers[t], subscription{id: id, subscriber: subscriber})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		subscriptions := b.subscribers[t]
+		for i, s := range subscriptions {
+			if s.id == id {
+				b.subscribers[t] = append(subscriptions[:i:i], subscriptions[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Handle registers the handler answering requests of a type. Each type has at most one handler.
+func (b *Bus) Handle(t Type, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.handlers[t]; exists {
+		return fmt.Errorf("%s requests are already handled", t)
+	}
+	b.handlers[t] = handler
+	return nil
+}
+
+// Publish delivers an event to its subscribers one after the other, in the order they subscribed,
+// and returns their errors. Every subscriber is called even when an earlier one fails.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	for _, subscriber := range b.subscribersOf(event.Type) {
+		if err := subscriber(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PublishAsync queues an event for delivery in the background and returns without waiting for
+// subscribers. Errors are passed to the OnError function. It blocks while the queue is full.
+func (b *Bus) PublishAsync(event Event) error {
+	b.queueOnce.Do(func() {
+		b.queue = make(chan Event, DefaultQueueSize)
+		go b.deliver()
+	})
+
+	// Holding the read lock keeps Close from closing the queue while the event is sent
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+	b.queue <- event
+	return nil
+}
+
+// Request dispatches a request to the handler of its type and returns the reply's payload
+func (b *Bus) Request(ctx context.Context, event Event) (interface{}, error) {
+	b.mu.RLock()
+	handler, ok := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoHandler, event.Type)
+	}
+	return handler(ctx, event)
+}
+
+// Close stops accepting asynchronous events and waits until the queued ones are delivered
+func (b *Bus) Close() {
+	b.closeMu.Lock()
+	if b.closed {
+		b.closeMu.Unlock()
+		<-b.done
+		return
+	}
+	b.closed = true
+	b.closeMu.Unlock()
+
+	// Without a queue there is nothing to drain
+	started := true
+	b.queueOnce.Do(func() { started = false })
+	if !started {
+		close(b.done)
+		return
+	}
+	close(b.queue)
+	<-b.done
+}
+
+// deliver publishes queued events until the queue is closed
+func (b *Bus) deliver() {
+	defer close(b.done)
+
+	for event := range b.queue {
+		if err := b.Publish(context.Background(), event); err != nil {
+			b.mu.RLock()
+			onError := b.onError
+			b.mu.RUnlock()
+			onError(event, err)
+		}
+	}
+}
+
+// subscribersOf returns the subscribers of a type as of now
+func (b *Bus) subscribersOf(t Type) []Subscriber {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	subscribers := make([]Subscriber, len(b.subscribers[t]))
+	for i, s := range b.subscribers[t] {
+		subscribers[i] = s.subscriber
+	}
+	return subscribers
+}
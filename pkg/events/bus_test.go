@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	bus := NewBus()
+	var calls []string
+
+	bus.Subscribe(CredentialIssued, func(ctx context.Context, event Event) error {
+		calls = append(calls, "first")
+		return errors.New("first failed")
+	})
+	unsubscribe := bus.Subscribe(CredentialIssued, func(ctx context.Context, event Event) error {
+		calls = append(calls, "second")
+		return nil
+	})
+	bus.Subscribe(CredentialIssued, func(ctx context.Context, event Event) error {
+		calls = append(calls, "third")
+		return nil
+	})
+	bus.Subscribe(CredentialStored, func(ctx context.Context, event Event) error {
+		calls = append(calls, "other type")
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), New(CredentialIssued, CredentialIssuedData{}))
+	assert.ErrorContains(t, err, "first failed")
+	assert.Equal(t, []string{"first", "second", "third"}, calls, "every subscriber is called in order")
+
+	calls = nil
+	unsubscribe()
+	_ = bus.Publish(context.Background(), New(CredentialIssued, CredentialIssuedData{}))
+	assert.Equal(t, []string{"first", "third"}, calls)
+}
+
+func TestRequest(t *testing.T) {
+	bus := NewBus()
+
+	_, err := bus.Request(context.Background(), New(RequestPresentation, RequestPresentationData{}))
+	assert.ErrorIs(t, err, ErrNoHandler)
+
+	require.NoError(t, bus.Handle(RequestPresentation, func(ctx context.Context, event Event) (interface{}, error) {
+		data := event.Data.(RequestPresentationData)
+		return PresentationRequestedData{SessionID: "session", VerifierDID: data.VerifierDID}, nil
+	}))
+	assert.Error(t, bus.Handle(RequestPresentation, func(ctx context.Context, event Event) (interface{}, error) {
+		return nil, nil
+	}), "a type has one handler")
+
+	reply, err := bus.Request(context.Background(), New(RequestPresentation, RequestPresentationData{VerifierDID: "did:example:verifier"}))
+	require.NoError(t, err)
+	assert.Equal(t, PresentationRequestedData{SessionID: "session", VerifierDID: "did:example:verifier"}, reply)
+}
+
+func TestPublishAsync(t *testing.T) {
+	bus := NewBus()
+
+	var mu sync.Mutex
+	var delivered []string
+	var failed []string
+	bus.Subscribe(CredentialStored, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		data := event.Data.(CredentialStoredData)
+		delivered = append(delivered, data.CredentialID)
+		if data.CredentialID == "bad" {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+	bus.OnError(func(event Event, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, event.Data.(CredentialStoredData).CredentialID)
+	})
+
+	for _, id := range []string{"a", "bad", "b"} {
+		require.NoError(t, bus.PublishAsync(New(CredentialStored, CredentialStoredData{CredentialID: id})))
+	}
+
+	// Close waits for the queued events
+	bus.Close()
+	assert.Equal(t, []string{"a", "bad", "b"}, delivered)
+	assert.Equal(t, []string{"bad"}, failed)
+
+	assert.ErrorIs(t, bus.PublishAsync(New(CredentialStored, CredentialStoredData{})), ErrClosed)
+	bus.Close()
+}
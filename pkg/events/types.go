@@ -0,0 +1,101 @@
+package events
+
+import (
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Type identifies an event or request and the payload its Data holds
+type Type string
+
+// Events, published after the fact to any number of subscribers
+const (
+	// CredentialIssued carries CredentialIssuedData; the issuer publishes it for every credential
+	CredentialIssued Type = "credential.issued"
+	// CredentialStored carries CredentialStoredData; the holder publishes it when its wallet
+	// accepts a credential
+	CredentialStored Type = "credential.stored"
+	// PresentationRequested carries PresentationRequestedData; the verifier publishes it when it
+	// opens a session
+	PresentationRequested Type = "presentation.requested"
+	// PresentationVerified carries PresentationVerifiedData; the verifier publishes it for every
+	// presentation it verifies
+	PresentationVerified Type = "presentation.verified"
+)
+
+// Requests, answered by the one module that handles them
+const (
+	// RequestPresentation carries RequestPresentationData and is answered by the verifier with
+	// PresentationRequestedData
+	RequestPresentation Type = "verifier.request-presentation"
+	// CreatePresentation carries CreatePresentationData and is answered by the holder with
+	// PresentationCreatedData
+	CreatePresentation Type = "holder.create-presentation"
+	// SubmitPresentation carries SubmitPresentationData and is answered by the verifier with
+	// PresentationVerifiedData
+	SubmitPresentation Type = "verifier.submit-presentation"
+)
+
+// CredentialIssuedData reports an issued credential
+type CredentialIssuedData struct {
+	Credential *vc.VerifiableCredential `json:"credential"`
+}
+
+// CredentialStoredData reports a credential accepted into a wallet
+type CredentialStoredData struct {
+	CredentialID string `json:"credentialId"`
+	HolderDID    string `json:"holderDid,omitempty"`
+}
+
+// RequestPresentationData asks the verifier to open a session for a proof request
+type RequestPresentationData struct {
+	VerifierDID string                `json:"verifierDid,omitempty"`
+	Request     exchange.ProofRequest `json:"request"`
+	// TTL overrides the verifier's session lifetime when non-zero
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// PresentationRequestedData reports a session waiting for a presentation
+type PresentationRequestedData struct {
+	SessionID   string                `json:"sessionId"`
+	VerifierDID string                `json:"verifierDid,omitempty"`
+	Request     exchange.ProofRequest `json:"request"`
+	ExpiresAt   time.Time             `json:"expiresAt"`
+}
+
+// CreatePresentationData asks the holder's wallet to answer a proof request from one credential
+type CreatePresentationData struct {
+	HolderDID    string                `json:"holderDid"`
+	CredentialID string                `json:"credentialId"`
+	Request      exchange.ProofRequest `json:"request"`
+}
+
+// PresentationCreatedData is the wallet's answer to a proof request
+type PresentationCreatedData struct {
+	Presentation   *vc.VerifiablePresentation `json:"presentation"`
+	RevealedClaims []string                   `json:"revealedClaims"`
+	// WithheldClaims are the optional claims the credential does not carry
+	WithheldClaims []string `json:"withheldClaims,omitempty"`
+	// HiddenClaims are the credential's claims the presentation does not reveal
+	HiddenClaims []string `json:"hiddenClaims,omitempty"`
+}
+
+// SubmitPresentationData answers a verifier's session
+type SubmitPresentationData struct {
+	SessionID    string                     `json:"sessionId"`
+	Presentation *vc.VerifiablePresentation `json:"presentation"`
+}
+
+// PresentationVerifiedData reports the outcome of a verification
+type PresentationVerifiedData struct {
+	// SessionID is set when the presentation answered a session
+	SessionID      string                 `json:"sessionId,omitempty"`
+	PresentationID string                 `json:"presentationId,omitempty"`
+	VerifierDID    string                 `json:"verifierDid,omitempty"`
+	HolderDID      string                 `json:"holderDid,omitempty"`
+	Valid          bool                   `json:"valid"`
+	RevealedClaims map[string]interface{} `json:"revealedClaims,omitempty"`
+	Errors         []string               `json:"errors,omitempty"`
+}
@@ -0,0 +1,163 @@
+package exchange
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Validate checks that the request asks for at least one claim and lists each claim once
+func (r *ProofRequest) Validate() error {
+	if len(r.RequiredClaims) == 0 {
+		return fmt.Errorf("at least one required claim is needed")
+	}
+
+	seen := make(map[string]bool)
+	for _, claim := range r.Claims() {
+		if claim == "" {
+			return fmt.Errorf("claim names cannot be empty")
+		}
+		if seen[claim] {
+			return fmt.Errorf("claim %s is requested more than once", claim)
+		}
+		seen[claim] = true
+	}
+
+	return r.RequestMetadata.Validate()
+}
+
+// Claims returns the required claims followed by the optional claims
+func (r *ProofRequest) Claims() []string {
+	claims := make([]string, 0, len(r.RequiredClaims)+len(r.OptionalClaims))
+	claims = append(claims, r.RequiredClaims...)
+	return append(claims, r.OptionalClaims...)
+}
+
+// EvaluateOffer applies the request's policy to a counter-offer: every required claim must be
+// offered and only requested claims may be offered. It returns the claims agreed on, in request order.
+func (r *ProofRequest) EvaluateOffer(offer CounterOffer) ([]string, error) {
+	offered := make(map[string]bool)
+	for _, claim := range offer.OfferedClaims {
+		offered[claim] = true
+	}
+
+	for _, claim := range r.RequiredClaims {
+		if !offered[claim] {
+			return nil, fmt.Errorf("required claim %s is not offered", claim)
+		}
+	}
+
+	var agreed []string
+	for _, claim := range r.Claims() {
+		if offered[claim] {
+			agreed = append(agreed, claim)
+			delete(offered, claim)
+		}
+	}
+
+	for _, claim := range offer.OfferedClaims {
+		if offered[claim] {
+			return nil, fmt.Errorf("claim %s was not requested", claim)
+		}
+	}
+
+	return agreed, nil
+}
+
+// NewNegotiation opens a negotiation thread with the verifier's proof request as its first message
+func NewNegotiation(verifierDID string, request ProofRequest) (*Negotiation, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid proof request: %w", err)
+	}
+
+	now := time.Now()
+	negotiation := &Negotiation{
+		ID:          uuid.New().String(),
+		VerifierDID: verifierDID,
+		State:       StateOpen,
+		Request:     request,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	message := negotiation.newMessage(MessageProofRequest, verifierDID)
+	message.Request = &request
+	negotiation.Messages = append(negotiation.Messages, message)
+
+	return negotiation, nil
+}
+
+// Respond records the holder's counter-offer and the verifier's decision on it.
+// A rejected holder may offer again until MaxOffers is reached.
+func (n *Negotiation) Respond(holderDID string, offer CounterOffer) (*Message, error) {
+	if n.State != StateOpen {
+		return nil, fmt.Errorf("negotiation %s is %s and does not accept offers", n.ID, n.State)
+	}
+
+	if n.HolderDID != "" && holderDID != n.HolderDID {
+		return nil, fmt.Errorf("negotiation %s belongs to another holder", n.ID)
+	}
+	n.HolderDID = holderDID
+
+	offerMessage := n.newMessage(MessageCounterOffer, holderDID)
+	offerMessage.Offer = &offer
+	n.Messages = append(n.Messages, offerMessage)
+
+	decision := n.newMessage(MessageAccept, n.VerifierDID)
+	agreed, err := n.Request.EvaluateOffer(offer)
+	if err != nil {
+		decision.Type = MessageReject
+		decision.Decision = &Decision{Reason: err.Error()}
+		if n.offers() >= MaxOffers {
+			n.State = StateClosed
+		}
+	} else {
+		decision.Decision = &Decision{AgreedClaims: agreed}
+		n.AgreedClaims = agreed
+		n.State = StateAgreed
+	}
+	n.Messages = append(n.Messages, decision)
+	n.UpdatedAt = decision.CreatedAt
+
+	return &decision, nil
+}
+
+// Complete marks an agreed negotiation as fulfilled by a verified presentation
+func (n *Negotiation) Complete() error {
+	if n.State != StateAgreed {
+		return fmt.Errorf("negotiation %s is %s, not agreed", n.ID, n.State)
+	}
+
+	n.State = StateCompleted
+	n.UpdatedAt = time.Now()
+	return nil
+}
+
+// Copy returns a deep copy so callers can read the negotiation without holding a lock
+func (n *Negotiation) Copy() *Negotiation {
+	copied := *n
+	copied.AgreedClaims = append([]string(nil), n.AgreedClaims...)
+	copied.Messages = append([]Message(nil), n.Messages...)
+	return &copied
+}
+
+func (n *Negotiation) offers() int {
+	count := 0
+	for _, message := range n.Messages {
+		if message.Type == MessageCounterOffer {
+			count++
+		}
+	}
+	return count
+}
+
+func (n *Negotiation) newMessage(messageType MessageType, from string) Message {
+	return Message{
+		ID:        uuid.New().String(),
+		ThreadID:  n.ID,
+		Type:      messageType,
+		From:      from,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,116 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofRequestValidate(t *testing.T) {
+	valid := ProofRequest{RequiredClaims: []string{"ageOver18"}, OptionalClaims: []string{"nationality"}}
+	assert.NoError(t, valid.Validate())
+
+	for name, request := range map[string]ProofRequest{
+		"no required claims":  {OptionalClaims: []string{"nationality"}},
+		"empty claim":         {RequiredClaims: []string{""}},
+		"duplicate claim":     {RequiredClaims: []string{"ageOver18", "ageOver18"}},
+		"required + optional": {RequiredClaims: []string{"ageOver18"}, OptionalClaims: []string{"ageOver18"}},
+	} {
+		assert.Error(t, request.Validate(), name)
+	}
+}
+
+func TestEvaluateOffer(t *testing.T) {
+	request := ProofRequest{
+		RequiredClaims: []string{"ageOver18"},
+		OptionalClaims: []string{"nationality", "city"},
+	}
+
+	agreed, err := request.EvaluateOffer(CounterOffer{OfferedClaims: []string{"city", "ageOver18"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ageOver18", "city"}, agreed, "agreed claims follow request order")
+
+	_, err = request.EvaluateOffer(CounterOffer{OfferedClaims: []string{"nationality"}})
+	assert.ErrorContains(t, err, "required claim ageOver18 is not offered")
+
+	_, err = request.EvaluateOffer(CounterOffer{OfferedClaims: []string{"ageOver18", "salary"}})
+	assert.ErrorContains(t, err, "claim salary was not requested")
+}
+
+func TestNegotiation(t *testing.T) {
+	request := ProofRequest{
+		RequiredClaims: []string{"ageOver18"},
+		OptionalClaims: []string{"nationality"},
+		Nonce:          "nonce",
+	}
+
+	t.Run("Reduced Offer Accepted", func(t *testing.T) {
+		negotiation, err := NewNegotiation("did:example:verifier", request)
+		require.NoError(t, err)
+		assert.Equal(t, StateOpen, negotiation.State)
+		require.Len(t, negotiation.Messages, 1)
+		assert.Equal(t, MessageProofRequest, negotiation.Messages[0].Type)
+
+		decision, err := negotiation.Respond("did:example:holder", CounterOffer{
+			OfferedClaims:  []string{"ageOver18"},
+			WithheldClaims: []string{"nationality"},
+			Reason:         "I will not share my nationality",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, MessageAccept, decision.Type)
+		assert.Equal(t, negotiation.ID, decision.ThreadID)
+		assert.Equal(t, "did:example:verifier", decision.From)
+		assert.Equal(t, []string{"ageOver18"}, decision.Decision.AgreedClaims)
+
+		assert.Equal(t, StateAgreed, negotiation.State)
+		assert.Equal(t, []string{"ageOver18"}, negotiation.AgreedClaims)
+		require.Len(t, negotiation.Messages, 3)
+		assert.Equal(t, MessageCounterOffer, negotiation.Messages[1].Type)
+		assert.Equal(t, "did:example:holder", negotiation.Messages[1].From)
+
+		_, err = negotiation.Respond("did:example:holder", CounterOffer{OfferedClaims: []string{"ageOver18"}})
+		assert.Error(t, err, "agreed negotiations take no more offers")
+
+		require.NoError(t, negotiation.Complete())
+		assert.Equal(t, StateCompleted, negotiation.State)
+		assert.Error(t, negotiation.Complete())
+	})
+
+	t.Run("Rejected Offers Close Negotiation", func(t *testing.T) {
+		negotiation, err := NewNegotiation("did:example:verifier", request)
+		require.NoError(t, err)
+
+		for i := 0; i < MaxOffers; i++ {
+			decision, err := negotiation.Respond("did:example:holder", CounterOffer{OfferedClaims: []string{"nationality"}})
+			require.NoError(t, err)
+			assert.Equal(t, MessageReject, decision.Type)
+			assert.Contains(t, decision.Decision.Reason, "ageOver18")
+		}
+		assert.Equal(t, StateClosed, negotiation.State)
+
+		_, err = negotiation.Respond("did:example:holder", CounterOffer{OfferedClaims: []string{"ageOver18"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Other Holder", func(t *testing.T) {
+		negotiation, err := NewNegotiation("did:example:verifier", request)
+		require.NoError(t, err)
+
+		_, err = negotiation.Respond("did:example:holder", CounterOffer{})
+		require.NoError(t, err)
+		_, err = negotiation.Respond("did:example:other", CounterOffer{OfferedClaims: []string{"ageOver18"}})
+		assert.ErrorContains(t, err, "another holder")
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		negotiation, err := NewNegotiation("did:example:verifier", request)
+		require.NoError(t, err)
+
+		copied := negotiation.Copy()
+		_, err = negotiation.Respond("did:example:holder", CounterOffer{OfferedClaims: []string{"ageOver18"}})
+		require.NoError(t, err)
+		assert.Len(t, copied.Messages, 1)
+		assert.Equal(t, StateOpen, copied.State)
+	})
+}
@@ -0,0 +1,87 @@
+package exchange
+
+import (
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// MessageType identifies the kind of a negotiation message
+type MessageType string
+
+const (
+	// MessageProofRequest is the verifier's opening request
+	MessageProofRequest MessageType = "proof-request"
+	// MessageCounterOffer is the holder's reply stating which requested claims they will prove
+	MessageCounterOffer MessageType = "counter-offer"
+	// MessageAccept is the verifier agreeing to the offered claims
+	MessageAccept MessageType = "accept"
+	// MessageReject is the verifier refusing an offer its policy does not allow
+	MessageReject MessageType = "reject"
+)
+
+// State represents where a negotiation stands
+type State string
+
+const (
+	// StateOpen waits for the holder's offer
+	StateOpen State = "open"
+	// StateAgreed waits for a presentation of the agreed claims
+	StateAgreed State = "agreed"
+	// StateCompleted means a presentation of the agreed claims was verified
+	StateCompleted State = "completed"
+	// StateClosed means the holder ran out of offers without agreement
+	StateClosed State = "closed"
+)
+
+// MaxOffers is how many counter-offers a holder may make before the negotiation closes
+const MaxOffers = 3
+
+// ProofRequest is what the verifier asks for. Required claims must be proven;
+// optional claims may be withheld by the holder without the request failing.
+type ProofRequest struct {
+	RequiredClaims []string `json:"requiredClaims"`
+	OptionalClaims []string `json:"optionalClaims,omitempty"`
+	TrustedIssuers []string `json:"trustedIssuers,omitempty"`
+	Nonce          string   `json:"nonce"`
+	vc.RequestMetadata
+}
+
+// CounterOffer is the holder's answer to a proof request
+type CounterOffer struct {
+	OfferedClaims  []string `json:"offeredClaims"`
+	WithheldClaims []string `json:"withheldClaims,omitempty"`
+	Reason         string   `json:"reason,omitempty"`
+}
+
+// Decision is the verifier's answer to a counter-offer
+type Decision struct {
+	AgreedClaims []string `json:"agreedClaims,omitempty"`
+	Reason       string   `json:"reason,omitempty"`
+}
+
+// Message is one step of a negotiation thread. Exactly one of Request, Offer and Decision is set,
+// matching Type.
+type Message struct {
+	ID        string        `json:"id"`
+	ThreadID  string        `json:"threadId"`
+	Type      MessageType   `json:"type"`
+	From      string        `json:"from,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Request   *ProofRequest `json:"request,omitempty"`
+	Offer     *CounterOffer `json:"offer,omitempty"`
+	Decision  *Decision     `json:"decision,omitempty"`
+}
+
+// Negotiation is a proof request thread between a verifier and a holder
+type Negotiation struct {
+	ID           string       `json:"id"`
+	VerifierDID  string       `json:"verifierDid,omitempty"`
+	HolderDID    string       `json:"holderDid,omitempty"`
+	State        State        `json:"state"`
+	Request      ProofRequest `json:"request"`
+	AgreedClaims []string     `json:"agreedClaims,omitempty"`
+	Messages     []Message    `json:"messages"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
@@ -0,0 +1,170 @@
+// Package fetch retrieves remote documents such as did:web DID documents and status list credentials,
+// caching them for a TTL and revalidating them with their ETag once the TTL has passed.
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long a fetched document is served from the cache without revalidation
+	DefaultTTL = 5 * time.Minute
+	// DefaultTimeout bounds each HTTP request made by a fetcher created without a client
+	DefaultTimeout = 10 * time.Second
+	// MaxDocumentSize is the largest document a fetcher accepts
+	MaxDocumentSize = 1 << 20
+)
+
+// Stats counts how cached lookups were answered
+type Stats struct {
+	// Hits were served from the cache without a request
+	Hits uint64 `json:"hits"`
+	// Revalidations were served from the cache after the origin answered 304 Not Modified
+	Revalidations uint64 `json:"revalidations"`
+	// Misses were fetched or loaded in full
+	Misses uint64 `json:"misses"`
+	// Errors failed to load
+	Errors uint64 `json:"errors"`
+}
+
+// Add returns the sum of two sets of counters
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		Hits:          s.Hits + other.Hits,
+		Revalidations: s.Revalidations + other.Revalidations,
+		Misses:        s.Misses + other.Misses,
+		Errors:        s.Errors + other.Errors,
+	}
+}
+
+// HitRate is the share of successful lookups answered without a request
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Revalidations + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// StatusError reports a document the origin answered with a status other than 200 OK
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to fetch %s: unexpected status %d", e.URL, e.StatusCode)
+}
+
+// entry is a cached document
+type entry struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// Fetcher GETs documents over HTTP and caches them
+type Fetcher struct {
+	client *http.Client
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	stats   Stats
+}
+
+// NewFetcher creates a fetcher that serves documents from its cache for ttl. A nil client uses
+// one with DefaultTimeout; a zero ttl revalidates on every lookup.
+func NewFetcher(client *http.Client, ttl time.Duration) *Fetcher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &Fetcher{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Get returns the document at url, from the cache when it is fresh. Stale documents with an
+// ETag are revalidated with If-None-Match; an unreachable origin is an error, never a stale hit.
+func (f *Fetcher) Get(ctx context.Context, url string) ([]byte, error) {
+	f.mu.Lock()
+	cached, ok := f.entries[url]
+	if ok && time.Now().Before(cached.expires) {
+		f.stats.Hits++
+		f.mu.Unlock()
+		return cached.body, nil
+	}
+	f.mu.Unlock()
+
+	body, etag, notModified, err := f.fetch(ctx, url, cached)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err != nil {
+		f.stats.Errors++
+		return nil, err
+	}
+
+	if notModified {
+		f.stats.Revalidations++
+		body = cached.body
+		etag = cached.etag
+	} else {
+		f.stats.Misses++
+	}
+
+	f.entries[url] = &entry{body: body, etag: etag, expires: time.Now().Add(f.ttl)}
+	return body, nil
+}
+
+// Stats returns the fetcher's counters
+func (f *Fetcher) Stats() Stats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.stats
+}
+
+// fetch requests url, conditionally when a cached copy has an ETag
+func (f *Fetcher) fetch(ctx context.Context, url string, cached *entry) ([]byte, string, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("invalid document URL: %w", err)
+	}
+	if cached != nil && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return nil, "", true, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", false, &StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxDocumentSize+1))
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if len(body) > MaxDocumentSize {
+		return nil, "", false, fmt.Errorf("document at %s exceeds %d bytes", url, MaxDocumentSize)
+	}
+
+	return body, resp.Header.Get("ETag"), false, nil
+}
@@ -0,0 +1,94 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcher(t *testing.T) {
+	var requests, conditional atomic.Int32
+	body := `{"version":1}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	t.Run("Serves From The Cache Within The TTL", func(t *testing.T) {
+		fetcher := NewFetcher(ts.Client(), time.Hour)
+		for i := 0; i < 3; i++ {
+			got, err := fetcher.Get(context.Background(), ts.URL+"/ttl")
+			require.NoError(t, err)
+			assert.Equal(t, body, string(got))
+		}
+
+		stats := fetcher.Stats()
+		assert.Equal(t, Stats{Hits: 2, Misses: 1}, stats)
+		assert.InDelta(t, 2.0/3.0, stats.HitRate(), 0.001)
+	})
+
+	t.Run("Revalidates With The ETag", func(t *testing.T) {
+		before := conditional.Load()
+		fetcher := NewFetcher(ts.Client(), 0)
+		for i := 0; i < 3; i++ {
+			got, err := fetcher.Get(context.Background(), ts.URL+"/etag")
+			require.NoError(t, err)
+			assert.Equal(t, body, string(got))
+		}
+
+		assert.Equal(t, Stats{Revalidations: 2, Misses: 1}, fetcher.Stats())
+		assert.Equal(t, before+2, conditional.Load())
+		assert.Zero(t, fetcher.Stats().HitRate())
+	})
+
+	t.Run("Never Serves Stale Documents On Error", func(t *testing.T) {
+		var failing atomic.Bool
+		origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failing.Load() {
+				http.Error(w, "down", http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(body))
+		}))
+		defer origin.Close()
+
+		fetcher := NewFetcher(origin.Client(), 0)
+		_, err := fetcher.Get(context.Background(), origin.URL)
+		require.NoError(t, err)
+
+		failing.Store(true)
+		_, err = fetcher.Get(context.Background(), origin.URL)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+		assert.Equal(t, uint64(1), fetcher.Stats().Errors)
+	})
+
+	t.Run("Rejects Oversized Documents", func(t *testing.T) {
+		origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(make([]byte, MaxDocumentSize+1))
+		}))
+		defer origin.Close()
+
+		_, err := NewFetcher(origin.Client(), time.Hour).Get(context.Background(), origin.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestStatsAdd(t *testing.T) {
+	sum := Stats{Hits: 1, Misses: 2}.Add(Stats{Hits: 3, Revalidations: 1, Errors: 4})
+	assert.Equal(t, Stats{Hits: 4, Revalidations: 1, Misses: 2, Errors: 4}, sum)
+	assert.Zero(t, Stats{}.HitRate())
+}
@@ -0,0 +1,212 @@
+// Package health runs component checks for health probes and aggregates them into a report.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the health of a component or of the whole service
+type Status string
+
+const (
+	// StatusHealthy means every check passed in time
+	StatusHealthy Status = "healthy"
+	// StatusDegraded means a non-critical check failed or a check was slow; the service still works
+	StatusDegraded Status = "degraded"
+	// StatusUnhealthy means a critical check failed
+	StatusUnhealthy Status = "unhealthy"
+)
+
+const (
+	// DefaultTimeout bounds how long a single check may run
+	DefaultTimeout = 5 * time.Second
+	// DefaultSlowThreshold marks checks that take longer as degraded
+	DefaultSlowThreshold = time.Second
+)
+
+// CheckFunc checks one component, returning an error when it does not work
+type CheckFunc func(ctx context.Context) error
+
+// Pinger is implemented by storage backends that can report whether they are reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck returns a check that pings a storage backend
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+// Component is the outcome of one check
+type Component struct {
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+	// DurationMs is how long the check took, in milliseconds
+	DurationMs float64 `json:"durationMs"`
+}
+
+// Report is the outcome of all checks
+type Report struct {
+	Status     Status      `json:"status"`
+	Components []Component `json:"components"`
+	CheckedAt  time.Time   `json:"checkedAt"`
+}
+
+// Config configures a Checker
+type Config struct {
+	// Timeout bounds each check; checks that run longer fail
+	Timeout time.Duration
+	// SlowThreshold marks checks that pass but take longer as degraded
+	SlowThreshold time.Duration
+	// CacheFor reuses a report for this long, so frequent probes do not rerun expensive checks
+	CacheFor time.Duration
+}
+
+// DefaultConfig returns the default check timeout and slow threshold, without caching
+func DefaultConfig() Config {
+	return Config{
+		Timeout:       DefaultTimeout,
+		SlowThreshold: DefaultSlowThreshold,
+	}
+}
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// Checker runs registered checks concurrently
+type Checker struct {
+	config Config
+
+	mu     sync.Mutex
+	checks []check
+	last   *Report
+}
+
+// NewChecker creates a checker
+func NewChecker(config Config) (*Checker, error) {
+	if config.Timeout <= 0 {
+		return nil, fmt.Errorf("check timeout must be positive")
+	}
+	if config.SlowThreshold < 0 || config.CacheFor < 0 {
+		return nil, fmt.Errorf("slow threshold and cache duration cannot be negative")
+	}
+
+	return &Checker{config: config}, nil
+}
+
+// Register adds a check. A failing critical check makes the service unhealthy; a failing
+// non-critical check only degrades it.
+func (c *Checker) Register(name string, critical bool, fn CheckFunc) error {
+	if name == "" || fn == nil {
+		return fmt.Errorf("check name and function are required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, existing := range c.checks {
+		if existing.name == name {
+			return fmt.Errorf("check %s is already registered", name)
+		}
+	}
+	c.checks = append(c.checks, check{name: name, critical: critical, fn: fn})
+	c.last = nil
+	return nil
+}
+
+// Run runs every check, or returns the cached report while it is fresh
+func (c *Checker) Run(ctx context.Context) *Report {
+	c.mu.Lock()
+	if c.last != nil && time.Since(c.last.CheckedAt) < c.config.CacheFor {
+		report := c.last
+		c.mu.Unlock()
+		return report
+	}
+	checks := append([]check(nil), c.checks...)
+	c.mu.Unlock()
+
+	components := make([]Component, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			components[i] = c.run(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+
+	report := &Report{
+		Status:     Aggregate(components),
+		Components: components,
+		CheckedAt:  time.Now(),
+	}
+
+	c.mu.Lock()
+	c.last = report
+	c.mu.Unlock()
+
+	return report
+}
+
+// run runs one check under the timeout
+func (c *Checker) run(ctx context.Context, chk check) Component {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	component := Component{Name: chk.name, Critical: chk.critical, Status: StatusHealthy}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- chk.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not finish: %w", ctx.Err())
+	}
+	elapsed := time.Since(start)
+	component.DurationMs = float64(elapsed.Microseconds()) / 1000
+
+	switch {
+	case err != nil && chk.critical:
+		component.Status = StatusUnhealthy
+		component.Error = err.Error()
+	case err != nil:
+		component.Status = StatusDegraded
+		component.Error = err.Error()
+	case c.config.SlowThreshold > 0 && elapsed > c.config.SlowThreshold:
+		component.Status = StatusDegraded
+		component.Error = fmt.Sprintf("check took %s", elapsed.Round(time.Millisecond))
+	}
+
+	return component
+}
+
+// Aggregate returns the worst status among components
+func Aggregate(components []Component) Status {
+	status := StatusHealthy
+	for _, component := range components {
+		switch component.Status {
+		case StatusUnhealthy:
+			return StatusUnhealthy
+		case StatusDegraded:
+			status = StatusDegraded
+		}
+	}
+	return status
+}
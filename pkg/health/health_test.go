@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	t.Run("All Healthy", func(t *testing.T) {
+		checker, err := NewChecker(DefaultConfig())
+		require.NoError(t, err)
+		require.NoError(t, checker.Register("storage", true, ok))
+		require.NoError(t, checker.Register("crypto", true, ok))
+
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusHealthy, report.Status)
+		require.Len(t, report.Components, 2)
+		assert.Equal(t, "crypto", report.Components[0].Name, "components are sorted by name")
+	})
+
+	t.Run("Non-Critical Failure Degrades", func(t *testing.T) {
+		checker, err := NewChecker(DefaultConfig())
+		require.NoError(t, err)
+		require.NoError(t, checker.Register("storage", true, ok))
+		require.NoError(t, checker.Register("anchoring", false, fail))
+
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, StatusDegraded, report.Components[0].Status)
+		assert.Equal(t, "connection refused", report.Components[0].Error)
+	})
+
+	t.Run("Critical Failure Is Unhealthy", func(t *testing.T) {
+		checker, err := NewChecker(DefaultConfig())
+		require.NoError(t, err)
+		require.NoError(t, checker.Register("storage", true, fail))
+		require.NoError(t, checker.Register("anchoring", false, fail))
+
+		assert.Equal(t, StatusUnhealthy, checker.Run(context.Background()).Status)
+	})
+
+	t.Run("Slow And Hung Checks", func(t *testing.T) {
+		checker, err := NewChecker(Config{Timeout: 100 * time.Millisecond, SlowThreshold: 10 * time.Millisecond})
+		require.NoError(t, err)
+		require.NoError(t, checker.Register("slow", true, func(ctx context.Context) error {
+			time.Sleep(30 * time.Millisecond)
+			return nil
+		}))
+		require.NoError(t, checker.Register("hung", false, func(ctx context.Context) error {
+			<-make(chan struct{})
+			return nil
+		}))
+
+		report := checker.Run(context.Background())
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Contains(t, report.Components[0].Error, "did not finish")
+		assert.Contains(t, report.Components[1].Error, "took")
+	})
+
+	t.Run("Reports Cached", func(t *testing.T) {
+		checker, err := NewChecker(Config{Timeout: time.Second, CacheFor: time.Minute})
+		require.NoError(t, err)
+
+		runs := 0
+		require.NoError(t, checker.Register("counted", true, func(ctx context.Context) error {
+			runs++
+			return nil
+		}))
+
+		first := checker.Run(context.Background())
+		second := checker.Run(context.Background())
+		assert.Same(t, first, second)
+		assert.Equal(t, 1, runs)
+	})
+
+	t.Run("Invalid Registration", func(t *testing.T) {
+		checker, err := NewChecker(DefaultConfig())
+		require.NoError(t, err)
+		require.NoError(t, checker.Register("storage", true, ok))
+		assert.Error(t, checker.Register("storage", true, ok))
+		assert.Error(t, checker.Register("", true, ok))
+
+		_, err = NewChecker(Config{})
+		assert.Error(t, err)
+	})
+}
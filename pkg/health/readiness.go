@@ -0,0 +1,101 @@
+package health
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TaskState is the progress of a startup task
+type TaskState string
+
+const (
+	TaskPending TaskState = "pending"
+	TaskDone    TaskState = "done"
+	TaskFailed  TaskState = "failed"
+)
+
+// Task is a startup step the service must finish before it takes traffic, such as warming
+// caches, loading keys or migrating storage
+type Task struct {
+	Name       string     `json:"name"`
+	State      TaskState  `json:"state"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Readiness tracks startup tasks. The service is ready once every task is done; a failed
+// task keeps it unready until the process is restarted.
+type Readiness struct {
+	mu    sync.RWMutex
+	tasks []*Task
+}
+
+// NewReadiness creates a tracker with no tasks, which is ready
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// Start records a pending task and returns the function that finishes it
+func (r *Readiness) Start(name string) (func(error), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, task := range r.tasks {
+		if task.Name == name {
+			return nil, fmt.Errorf("task %s is already tracked", name)
+		}
+	}
+
+	task := &Task{Name: name, State: TaskPending, StartedAt: time.Now()}
+	r.tasks = append(r.tasks, task)
+
+	var once sync.Once
+	finish := func(err error) {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			now := time.Now()
+			task.FinishedAt = &now
+			task.State = TaskDone
+			if err != nil {
+				task.State = TaskFailed
+				task.Error = err.Error()
+			}
+		})
+	}
+
+	return finish, nil
+}
+
+// Go runs a task in the background
+func (r *Readiness) Go(name string, fn func() error) error {
+	finish, err := r.Start(name)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		finish(fn())
+	}()
+	return nil
+}
+
+// Ready reports whether every task is done, with a copy of all tasks
+func (r *Readiness) Ready() (bool, []Task) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ready := true
+	tasks := make([]Task, len(r.tasks))
+	for i, task := range r.tasks {
+		tasks[i] = *task
+		if task.State != TaskDone {
+			ready = false
+		}
+	}
+
+	return ready, tasks
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness(t *testing.T) {
+	t.Run("Ready Once Tasks Finish", func(t *testing.T) {
+		readiness := NewReadiness()
+		ready, _ := readiness.Ready()
+		assert.True(t, ready, "no tasks means ready")
+
+		finish, err := readiness.Start("keys")
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		require.NoError(t, readiness.Go("generators", func() error {
+			<-release
+			return nil
+		}))
+
+		ready, tasks := readiness.Ready()
+		assert.False(t, ready)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, TaskPending, tasks[0].State)
+
+		finish(nil)
+		close(release)
+		require.Eventually(t, func() bool {
+			ready, _ := readiness.Ready()
+			return ready
+		}, time.Second, 5*time.Millisecond)
+
+		_, tasks = readiness.Ready()
+		assert.Equal(t, TaskDone, tasks[1].State)
+		assert.NotNil(t, tasks[1].FinishedAt)
+	})
+
+	t.Run("Failed Task Stays Unready", func(t *testing.T) {
+		readiness := NewReadiness()
+		finish, err := readiness.Start("migrations")
+		require.NoError(t, err)
+
+		finish(errors.New("schema version 3 is newer than this build"))
+		finish(nil) // only the first outcome counts
+
+		ready, tasks := readiness.Ready()
+		assert.False(t, ready)
+		assert.Equal(t, TaskFailed, tasks[0].State)
+		assert.Contains(t, tasks[0].Error, "schema version")
+
+		_, err = readiness.Start("migrations")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,161 @@
+package holder
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// accreditationPolicyKeyPrefix keys each holder's accreditation policy
+const accreditationPolicyKeyPrefix = "accreditation-policy:"
+
+// ErrVerifierNotAccredited is returned when a holder's accreditation policy refuses a presentation
+var ErrVerifierNotAccredited = errors.New("verifier is not accredited")
+
+// SetAccreditationPolicy makes the holder require verifiers to show an accreditation the policy
+// accepts before data is disclosed to them. A nil policy removes the requirement.
+func (uc *UseCase) SetAccreditationPolicy(holderDID string, policy *vc.AccreditationPolicy) error {
+	if _, err := uc.loadHolder(holderDID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("holder not found: %s", holderDID)
+		}
+		return fmt.Errorf("failed to load holder: %w", err)
+	}
+
+	if policy == nil {
+		if err := uc.store.Delete(accreditationPolicyKeyPrefix + holderDID); err != nil {
+			return fmt.Errorf("failed to remove accreditation policy: %w", err)
+		}
+		return nil
+	}
+
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if err := storage.SetJSON(uc.store, accreditationPolicyKeyPrefix+holderDID, policy, 0); err != nil {
+		return fmt.Errorf("failed to store accreditation policy: %w", err)
+	}
+	return nil
+}
+
+// AccreditationPolicy returns the holder's accreditation policy, or nil if it has none
+func (uc *UseCase) AccreditationPolicy(holderDID string) (*vc.AccreditationPolicy, error) {
+	var policy vc.AccreditationPolicy
+	if err := storage.GetJSON(uc.store, accreditationPolicyKeyPrefix+holderDID, &policy); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load accreditation policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// checkVerifierAccreditation applies the holder's accreditation policy to a presentation request
+func (uc *UseCase) checkVerifierAccreditation(req PresentationRequest) error {
+	policy, err := uc.AccreditationPolicy(req.HolderDID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	var revealed []string
+	for _, sd := range req.SelectiveDisclosure {
+		revealed = append(revealed, sd.RevealedAttributes...)
+	}
+	if !policy.Covers(revealed) {
+		return nil
+	}
+
+	if err := uc.validateAccreditation(req, policy); err != nil {
+		return fmt.Errorf("%w: %v", ErrVerifierNotAccredited, err)
+	}
+	return nil
+}
+
+// validateAccreditation checks the accreditation the verifier presented is one the policy accepts,
+// was issued to the verifier, and is valid and in force
+func (uc *UseCase) validateAccreditation(req PresentationRequest, policy *vc.AccreditationPolicy) error {
+	if req.Accreditation == nil {
+		return fmt.Errorf("no accreditation presented")
+	}
+
+	accreditation, err := vc.ParseAccreditation(req.Accreditation)
+	if err != nil {
+		return err
+	}
+
+	verifierDID := req.VerifierDID
+	if verifierDID == "" && req.Verifier != nil {
+		verifierDID = req.Verifier.DID
+	}
+	if verifierDID == "" {
+		return fmt.Errorf("the request does not identify the verifier")
+	}
+	if accreditation.Verifier != verifierDID {
+		return fmt.Errorf("accreditation was issued to %s, not %s", accreditation.Verifier, verifierDID)
+	}
+
+	if err := policy.Check(accreditation); err != nil {
+		return err
+	}
+
+	credential := accreditation.Credential
+	if err := uc.vcService.VerifyCredential(credential); err != nil {
+		return fmt.Errorf("invalid accreditation: %w", err)
+	}
+
+	now := uc.clock.Now()
+	if now.Before(credential.IssuedAt()) {
+		return fmt.Errorf("accreditation is not yet valid")
+	}
+	if expiresAt := credential.ExpiresAt(); expiresAt != nil && now.After(*expiresAt) {
+		return fmt.Errorf("accreditation expired at %s", expiresAt.Format(time.RFC3339))
+	}
+
+	return uc.checkAccreditationStatus(credential)
+}
+
+// checkAccreditationStatus checks an accreditation against fresh status snapshots from its issuer
+func (uc *UseCase) checkAccreditationStatus(credential *vc.VerifiableCredential) error {
+	if len(credential.CredentialStatus) == 0 {
+		return nil
+	}
+	if uc.snapshotSource == nil {
+		return fmt.Errorf("accreditation status cannot be checked: no status snapshot source configured")
+	}
+
+	snapshots, err := uc.fetchStatusSnapshots(credential.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range credential.CredentialStatus {
+		entry := &credential.CredentialStatus[i]
+		var snapshot *vc.StatusSnapshot
+		for _, candidate := range snapshots {
+			if candidate.Covers(entry) {
+				snapshot = candidate
+				break
+			}
+		}
+		if snapshot == nil {
+			return fmt.Errorf("no %s status for accreditation", entry.StatusPurpose)
+		}
+		if !snapshot.Status {
+			continue
+		}
+
+		switch entry.StatusPurpose {
+		case status.PurposeRevocation:
+			return fmt.Errorf("accreditation has been revoked")
+		case status.PurposeSuspension:
+			return fmt.Errorf("accreditation is suspended")
+		default:
+			return fmt.Errorf("accreditation status %s is set", entry.StatusPurpose)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// AddendumSource issues threshold addendum credentials for credentials it signed
+type AddendumSource interface {
+	IssueAddendum(original *vc.VerifiableCredential, thresholds []string) (*vc.VerifiableCredential, error)
+}
+
+// SetAddendumSource sets where the holder requests addendum credentials from
+func (uc *UseCase) SetAddendumSource(source AddendumSource) {
+	uc.addendumSource = source
+}
+
+// RequestAddendum asks the issuer of a held credential for an addendum credential carrying the
+// named thresholds as boolean claims, and stores it. The holder can then present, e.g., ageOver17
+// to verifiers that cannot check predicate proofs.
+func (uc *UseCase) RequestAddendum(holderDID, credentialID string, thresholds []string) (*vc.VerifiableCredential, error) {
+	if uc.addendumSource == nil {
+		return nil, fmt.Errorf("no addendum source configured")
+	}
+
+	original, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+	}
+
+	if subjectID, ok := original.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+	}
+
+	addendum, err := uc.addendumSource.IssueAddendum(original, thresholds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain addendum: %w", err)
+	}
+
+	// Accept only an addendum about this credential, for this holder, from the same issuer
+	if addendum.Issuer() != original.Issuer() {
+		return nil, fmt.Errorf("addendum issued by %s, not %s", addendum.Issuer(), original.Issuer())
+	}
+	if subjectID, _ := addendum.CredentialSubject["id"].(string); subjectID != holderDID {
+		return nil, fmt.Errorf("addendum is not about holder %s", holderDID)
+	}
+	if addendumTo, _ := addendum.CredentialSubject[vc.AddendumToClaim].(string); addendumTo != credentialID {
+		return nil, fmt.Errorf("addendum supplements %s, not %s", addendumTo, credentialID)
+	}
+
+	if err := uc.StoreCredential(addendum); err != nil {
+		return nil, err
+	}
+
+	return addendum, nil
+}
@@ -0,0 +1,474 @@
+package holder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Backup archive format constants
+const (
+	BackupFormat  = "bbs-wallet-backup"
+	BackupVersion = 1
+
+	backupKDF    = "PBKDF2-HMAC-SHA256"
+	backupCipher = "AES-256-GCM"
+
+	// backupIterations follows current OWASP guidance for PBKDF2-HMAC-SHA256
+	backupIterations    = 600000
+	minBackupIterations = 100000
+	minPasswordLength   = 8
+)
+
+// BackupArchive is a password-encrypted export of a holder's wallet
+type BackupArchive struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	KDF        BackupKDF `json:"kdf"`
+	Cipher     string    `json:"cipher"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// BackupKDF describes how the encryption key is derived from the password
+type BackupKDF struct {
+	Name       string `json:"name"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+}
+
+// RestoreResult summarizes a restored wallet
+type RestoreResult struct {
+	HolderDID    string `json:"holderDid"`
+	Credentials  int    `json:"credentials"`
+	PairwiseDIDs int    `json:"pairwiseDids"`
+}
+
+// backupPayload is the plaintext content of an archive
+type backupPayload struct {
+	Version     int                        `json:"version"`
+	HolderDID   string                     `json:"holderDid"`
+	Identity    backupIdentity             `json:"identity"`
+	Pairwise    []backupIdentity           `json:"pairwise,omitempty"`
+	Credentials []*vc.VerifiableCredential `json:"credentials"`
+	Checksum    string                     `json:"checksum"`
+}
+
+// backupIdentity holds the private keys of one DID
+type backupIdentity struct {
+	DID                 string `json:"did"`
+	VerifierDID         string `json:"verifierDid,omitempty"`
+	KeyID               string `json:"keyId"`
+	PrivateKey          []byte `json:"privateKey"`
+	AgreementKeyID      string `json:"agreementKeyId,omitempty"`
+	AgreementPrivateKey []byte `json:"agreementPrivateKey,omitempty"`
+}
+
+// ExportBackup exports the holder's DID keys, pairwise DIDs and credentials
+// into an archive encrypted with a key derived from the password
+func (uc *UseCase) ExportBackup(holderDID, password string) (*BackupArchive, error) {
+	if len(password) < minPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+
+	setup, err := uc.loadHolder(holderDID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("holder not found: %s", holderDID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load holder: %w", err)
+	}
+
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	payload := &backupPayload{
+		Version:     BackupVersion,
+		HolderDID:   holderDID,
+		Identity:    newBackupIdentity(holderDID, "", setup.KeyPair),
+		Credentials: credentials,
+	}
+	pairwiseDIDs, err := uc.ListPairwiseDIDs(holderDID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pairwise := range pairwiseDIDs {
+		payload.Pairwise = append(payload.Pairwise,
+			newBackupIdentity(pairwise.DID.String(), pairwise.VerifierDID, pairwise.KeyPair))
+	}
+
+	payload.Checksum, err = payload.checksum()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backup: %w", err)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	archive := &BackupArchive{
+		Format:  BackupFormat,
+		Version: BackupVersion,
+		KDF: BackupKDF{
+			Name:       backupKDF,
+			Iterations: backupIterations,
+			Salt:       salt,
+		},
+		Cipher:    backupCipher,
+		CreatedAt: uc.clock.Now().UTC(),
+	}
+
+	aead, err := newBackupCipher(password, archive.KDF)
+	if err != nil {
+		return nil, err
+	}
+
+	archive.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(archive.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	aad, err := archive.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	archive.Ciphertext = aead.Seal(nil, archive.Nonce, plaintext, aad)
+
+	return archive, nil
+}
+
+// RestoreBackup decrypts an archive, checks its integrity and restores the
+// DID keys, pairwise DIDs and credentials into this wallet
+func (uc *UseCase) RestoreBackup(archive *BackupArchive, password string) (*RestoreResult, error) {
+	payload, err := archive.open(password)
+	if err != nil {
+		return nil, err
+	}
+
+	holderDID, keyPair, err := uc.restoreIdentity(payload.Identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore holder DID: %w", err)
+	}
+
+	pairwise := make(map[string]*PairwiseDID, len(payload.Pairwise))
+	for _, identity := range payload.Pairwise {
+		pairwiseDID, pairwiseKeyPair, err := uc.restoreIdentity(identity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore pairwise DID: %w", err)
+		}
+		pairwise[identity.VerifierDID] = &PairwiseDID{
+			VerifierDID: identity.VerifierDID,
+			DID:         pairwiseDID,
+			KeyPair:     pairwiseKeyPair,
+		}
+	}
+
+	for _, credential := range payload.Credentials {
+		if err := uc.StoreCredential(credential); err != nil {
+			return nil, fmt.Errorf("failed to restore credential %s: %w", credential.ID, err)
+		}
+	}
+
+	didDoc, err := uc.didService.ResolveDID(payload.HolderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve restored holder DID: %w", err)
+	}
+
+	if err := uc.saveHolder(&HolderSetup{
+		DID:     holderDID,
+		DIDDoc:  didDoc,
+		KeyPair: keyPair,
+	}); err != nil {
+		return nil, err
+	}
+
+	// The archive's pairwise DIDs replace any the wallet had
+	existing, _, err := uc.storedPairwise(payload.HolderDID)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range existing {
+		if err := uc.store.Delete(key); err != nil {
+			return nil, fmt.Errorf("failed to remove pairwise DID: %w", err)
+		}
+	}
+	for _, restored := range pairwise {
+		if err := uc.savePairwise(payload.HolderDID, restored); err != nil {
+			return nil, err
+		}
+	}
+
+	return &RestoreResult{
+		HolderDID:    payload.HolderDID,
+		Credentials:  len(payload.Credentials),
+		PairwiseDIDs: len(pairwise),
+	}, nil
+}
+
+// AgreementKey decrypts the archive and returns the key agreement key with the given ID, so a
+// wallet's owner can open envelopes addressed to it without restoring the wallet anywhere
+func (a *BackupArchive) AgreementKey(password, keyID string) (*did.AgreementKeyPair, error) {
+	payload, err := a.open(password)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range append([]backupIdentity{payload.Identity}, payload.Pairwise...) {
+		if identity.AgreementKeyID != keyID {
+			continue
+		}
+		privateKey, err := ecdh.X25519().NewPrivateKey(identity.AgreementPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key agreement key for %s: %w", identity.DID, err)
+		}
+		return &did.AgreementKeyPair{
+			PublicKey:  privateKey.PublicKey(),
+			PrivateKey: privateKey,
+			KeyID:      keyID,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("backup holds no key agreement key %s", keyID)
+}
+
+// open decrypts an archive and checks its integrity
+func (a *BackupArchive) open(password string) (*backupPayload, error) {
+	if a == nil {
+		return nil, fmt.Errorf("archive is nil")
+	}
+
+	if a.Format != BackupFormat {
+		return nil, fmt.Errorf("unsupported backup format: %s", a.Format)
+	}
+
+	if a.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version: %d", a.Version)
+	}
+
+	if a.Cipher != backupCipher || a.KDF.Name != backupKDF {
+		return nil, fmt.Errorf("unsupported backup algorithms: %s, %s", a.KDF.Name, a.Cipher)
+	}
+
+	if a.KDF.Iterations < minBackupIterations {
+		return nil, fmt.Errorf("backup KDF iteration count too low: %d", a.KDF.Iterations)
+	}
+
+	aead, err := newBackupCipher(password, a.KDF)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(a.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid backup nonce length: %d", len(a.Nonce))
+	}
+
+	aad, err := a.additionalData()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, a.Nonce, a.Ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt backup: wrong password or corrupted archive")
+	}
+
+	var payload backupPayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse backup: %w", err)
+	}
+
+	if err := payload.verify(); err != nil {
+		return nil, fmt.Errorf("backup integrity check failed: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// restoreIdentity imports a backed up DID's keys and makes sure its document resolves
+func (uc *UseCase) restoreIdentity(identity backupIdentity) (*did.DID, *did.KeyPair, error) {
+	parts := strings.SplitN(identity.DID, ":", 3)
+	if len(parts) != 3 || parts[0] != "did" {
+		return nil, nil, fmt.Errorf("invalid DID: %s", identity.DID)
+	}
+	restoredDID := &did.DID{Method: parts[1], Identifier: parts[2]}
+
+	if len(identity.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, nil, fmt.Errorf("invalid private key for %s", identity.DID)
+	}
+
+	keyPair := &did.KeyPair{
+		PrivateKey: ed25519.PrivateKey(identity.PrivateKey),
+		KeyID:      identity.KeyID,
+	}
+	if identity.AgreementKeyID != "" {
+		agreementKey, err := ecdh.X25519().NewPrivateKey(identity.AgreementPrivateKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid key agreement key for %s: %w", identity.DID, err)
+		}
+		keyPair.KeyAgreement = &did.AgreementKeyPair{
+			PrivateKey: agreementKey,
+			KeyID:      identity.AgreementKeyID,
+		}
+	}
+
+	if err := uc.didService.ImportKeyPair(keyPair); err != nil {
+		return nil, nil, err
+	}
+
+	// Recreate the DID document if this registry has never seen the DID
+	if _, err := uc.didService.ResolveDID(identity.DID); err != nil {
+		if _, err := uc.didService.CreateDIDDocument(restoredDID, keyPair); err != nil {
+			return nil, nil, fmt.Errorf("failed to recreate DID document: %w", err)
+		}
+	}
+
+	return restoredDID, keyPair, nil
+}
+
+// newBackupIdentity captures the private keys of a DID
+func newBackupIdentity(didString, verifierDID string, keyPair *did.KeyPair) backupIdentity {
+	identity := backupIdentity{
+		DID:         didString,
+		VerifierDID: verifierDID,
+		KeyID:       keyPair.KeyID,
+		PrivateKey:  keyPair.PrivateKey,
+	}
+	if keyPair.KeyAgreement != nil {
+		identity.AgreementKeyID = keyPair.KeyAgreement.KeyID
+		identity.AgreementPrivateKey = keyPair.KeyAgreement.PrivateKey.Bytes()
+	}
+	return identity
+}
+
+// checksum returns the SHA-256 digest of the payload content
+func (p *backupPayload) checksum() (string, error) {
+	content := *p
+	content.Checksum = ""
+
+	data, err := json.Marshal(&content)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal backup: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// verify checks the payload checksum and that all credentials belong to the holder
+func (p *backupPayload) verify() error {
+	if p.Version != BackupVersion {
+		return fmt.Errorf("payload version mismatch: %d", p.Version)
+	}
+
+	checksum, err := p.checksum()
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(checksum), []byte(p.Checksum)) {
+		return fmt.Errorf("checksum mismatch")
+	}
+
+	if p.Identity.DID != p.HolderDID {
+		return fmt.Errorf("identity does not match holder DID")
+	}
+
+	for _, credential := range p.Credentials {
+		if subjectID, _ := credential.CredentialSubject["id"].(string); subjectID != p.HolderDID {
+			return fmt.Errorf("credential %s does not belong to holder", credential.ID)
+		}
+	}
+
+	return nil
+}
+
+// additionalData binds the archive header to the ciphertext
+func (a *BackupArchive) additionalData() ([]byte, error) {
+	header := struct {
+		Format  string    `json:"format"`
+		Version int       `json:"version"`
+		KDF     BackupKDF `json:"kdf"`
+		Cipher  string    `json:"cipher"`
+	}{a.Format, a.Version, a.KDF, a.Cipher}
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backup header: %w", err)
+	}
+	return data, nil
+}
+
+// newBackupCipher derives the archive key from the password
+func newBackupCipher(password string, kdf BackupKDF) (cipher.AEAD, error) {
+	if len(kdf.Salt) < 16 {
+		return nil, fmt.Errorf("backup salt too short")
+	}
+
+	key := pbkdf2SHA256([]byte(password), kdf.Salt, kdf.Iterations, 32)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+// pbkdf2SHA256 implements PBKDF2 (RFC 8018) with HMAC-SHA256
+func pbkdf2SHA256(password, salt []byte, iterations, keyLength int) []byte {
+	prf := hmac.New(sha256.New, password)
+	blocks := (keyLength + prf.Size() - 1) / prf.Size()
+
+	var derived []byte
+	counter := make([]byte, 4)
+	u := make([]byte, prf.Size())
+	for block := 1; block <= blocks; block++ {
+		binary.BigEndian.PutUint32(counter, uint32(block))
+
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter)
+		u = prf.Sum(u[:0])
+
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		derived = append(derived, t...)
+	}
+
+	return derived[:keyLength]
+}
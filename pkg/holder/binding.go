@@ -0,0 +1,29 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// bindingKeyID returns the key the presentation must be signed with when credentials are bound to
+// a holder key, or "" when none are. A presentation carries a single proof, so all bound
+// credentials must be bound to the same key.
+func bindingKeyID(credentials []*vc.VerifiableCredential) (string, error) {
+	var keyID string
+	for _, credential := range credentials {
+		confirmation, err := credential.Confirmation()
+		if err != nil {
+			return "", fmt.Errorf("credential %s: %w", credential.ID, err)
+		}
+		if confirmation == nil {
+			continue
+		}
+
+		if keyID != "" && confirmation.KeyID != keyID {
+			return "", fmt.Errorf("credential %s is bound to %s, not %s, and cannot be presented with it", credential.ID, confirmation.KeyID, keyID)
+		}
+		keyID = confirmation.KeyID
+	}
+	return keyID, nil
+}
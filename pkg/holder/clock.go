@@ -0,0 +1,10 @@
+package holder
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// SetClock sets the clock the wallet dates its records by; nil restores the system clock
+func (uc *UseCase) SetClock(c clock.Clock) {
+	uc.clock = clock.OrSystem(c)
+}
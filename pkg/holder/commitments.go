@@ -0,0 +1,64 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// CommitmentExport is what an external prover needs to prove statements about committed claims:
+// the issuer-signed commitments, which are public inputs, and the openings of the requested
+// claims, which are private witnesses
+type CommitmentExport struct {
+	Bundle   *vc.CommitmentBundle   `json:"bundle"`
+	Openings []vc.CommitmentOpening `json:"openings"`
+}
+
+// ExportCommitments returns a credential's attribute commitments and the openings of the given
+// claims, checking each opening against its commitment
+func (uc *UseCase) ExportCommitments(holderDID, credentialID string, claims []string) (*CommitmentExport, error) {
+	credential, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+	}
+
+	if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+	}
+
+	if credential.Commitments == nil {
+		return nil, fmt.Errorf("credential %s has no attribute commitments", credentialID)
+	}
+
+	export := &CommitmentExport{Bundle: credential.Commitments}
+	for _, claim := range claims {
+		commitment, ok := credential.Commitments.Find(claim)
+		if !ok {
+			return nil, fmt.Errorf("claim %s is not committed", claim)
+		}
+
+		opening, ok := findOpening(credential.CommitmentOpenings, claim)
+		if !ok {
+			return nil, fmt.Errorf("no opening held for claim %s", claim)
+		}
+
+		if err := bbs.VerifyCommitmentOpening(commitment.Commitment, opening.Value, opening.Blinding); err != nil {
+			return nil, fmt.Errorf("claim %s: %w", claim, err)
+		}
+
+		export.Openings = append(export.Openings, opening)
+	}
+
+	return export, nil
+}
+
+// findOpening returns the opening of the commitment to the given claim
+func findOpening(openings []vc.CommitmentOpening, claim string) (vc.CommitmentOpening, bool) {
+	for _, opening := range openings {
+		if opening.Claim == claim {
+			return opening, true
+		}
+	}
+	return vc.CommitmentOpening{}, false
+}
@@ -0,0 +1,110 @@
+package holder
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ConsentRecord records what a holder disclosed, to whom and why
+type ConsentRecord struct {
+	ID             string              `json:"id"`
+	HolderDID      string              `json:"holderDid"`
+	PresenterDID   string              `json:"presenterDid"`
+	VerifierDID    string              `json:"verifierDid,omitempty"`
+	PresentationID string              `json:"presentationId"`
+	RevealedClaims map[string][]string `json:"revealedClaims"`
+	vc.RequestMetadata
+	CreatedAt time.Time `json:"createdAt"`
+	// Receipt is the verifier's signed acknowledgement, once received
+	Receipt *vc.VerificationReceipt `json:"receipt,omitempty"`
+}
+
+// recordConsent stores a consent record for a created presentation
+func (uc *UseCase) recordConsent(req PresentationRequest, presentation *vc.VerifiablePresentation) (*ConsentRecord, error) {
+	record := &ConsentRecord{
+		ID:              "urn:uuid:" + uuid.New().String(),
+		HolderDID:       req.HolderDID,
+		PresenterDID:    presentation.Holder,
+		VerifierDID:     req.VerifierDID,
+		PresentationID:  presentation.ID,
+		RevealedClaims:  make(map[string][]string),
+		RequestMetadata: req.RequestMetadata,
+		CreatedAt:       uc.clock.Now(),
+	}
+
+	if record.VerifierDID == "" && req.Verifier != nil {
+		record.VerifierDID = req.Verifier.DID
+	}
+
+	for _, sd := range req.SelectiveDisclosure {
+		record.RevealedClaims[sd.CredentialID] = append([]string(nil), sd.RevealedAttributes...)
+	}
+
+	if err := uc.saveConsent(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ListConsentRecords lists the consent records of a holder, newest first
+func (uc *UseCase) ListConsentRecords(holderDID string) ([]*ConsentRecord, error) {
+	records, err := uc.storedConsents(holderDID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+// StoreReceipt checks a verifier's receipt and attaches it to the consent record of the
+// presentation it acknowledges, so the holder can later prove the verification happened
+func (uc *UseCase) StoreReceipt(holderDID string, receipt *vc.VerificationReceipt) (*ConsentRecord, error) {
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt is nil")
+	}
+
+	if receipt.Proof == nil || receipt.Proof.ProofValue == "" {
+		return nil, fmt.Errorf("receipt has no proof")
+	}
+
+	signature, err := did.DecodeSignatureMultibase(receipt.Proof.ProofValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt proof value: %w", err)
+	}
+
+	payload, err := vc.ReceiptSigningInput(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.didService.VerifyWithDID(receipt.VerifierDID, receipt.Proof.VerificationMethod, payload, signature); err != nil {
+		return nil, fmt.Errorf("receipt signature verification failed: %w", err)
+	}
+
+	uc.consentMu.Lock()
+	defer uc.consentMu.Unlock()
+
+	records, err := uc.storedConsents(holderDID)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.PresentationID == receipt.PresentationID {
+			record.Receipt = receipt
+			if err := uc.saveConsent(record); err != nil {
+				return nil, err
+			}
+			return record, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no consent record for presentation %s", receipt.PresentationID)
+}
@@ -0,0 +1,11 @@
+package holder
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+// SetContextValidator enables JSON-LD context checks on credentials before they are stored, so a
+// wallet never holds a credential whose claims a JSON-LD processor would drop. Pass nil to disable.
+func (uc *UseCase) SetContextValidator(validator *jsonld.Validator) {
+	uc.contexts = validator
+}
@@ -0,0 +1,84 @@
+package holder
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ProveContinuity proves that two of the holder's credentials from one issuer, typically two
+// versions of an amended credential, hold the same value of a committed claim without revealing
+// it. The claim defaults to the subject identifier. The proof is bound to the verifier's nonce.
+func (uc *UseCase) ProveContinuity(holderDID, previousID, currentID, claim, nonce string) (*vc.ContinuityProof, error) {
+	if nonce == "" {
+		return nil, fmt.Errorf("nonce is required")
+	}
+	if previousID == currentID {
+		return nil, fmt.Errorf("continuity is proven between two different credentials")
+	}
+	if claim == "" {
+		claim = "id"
+	}
+
+	previous, previousOpening, err := uc.continuityOpening(holderDID, previousID, claim)
+	if err != nil {
+		return nil, err
+	}
+	current, currentOpening, err := uc.continuityOpening(holderDID, currentID, claim)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous.Issuer() != current.Issuer() {
+		return nil, fmt.Errorf("credentials %s and %s have different issuers", previousID, currentID)
+	}
+	if previousOpening.Encoding != currentOpening.Encoding || !bytes.Equal(previousOpening.Value, currentOpening.Value) {
+		return nil, fmt.Errorf("claim %s differs between credentials %s and %s", claim, previousID, currentID)
+	}
+
+	context := vc.ContinuityContext(claim, previousID, currentID, nonce)
+	proof, err := bbs.CreateEqualityProof(previousOpening.Value, previousOpening.Blinding, currentOpening.Blinding, context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove continuity: %w", err)
+	}
+
+	return &vc.ContinuityProof{
+		Claim:    claim,
+		Previous: previous.Commitments,
+		Current:  current.Commitments,
+		Proof:    proof,
+		Nonce:    nonce,
+	}, nil
+}
+
+// continuityOpening returns one of the holder's credentials and the checked opening of its
+// commitment to claim
+func (uc *UseCase) continuityOpening(holderDID, credentialID, claim string) (*vc.VerifiableCredential, vc.CommitmentOpening, error) {
+	credential, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, vc.CommitmentOpening{}, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+	}
+
+	if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, vc.CommitmentOpening{}, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+	}
+
+	if credential.Commitments == nil {
+		return nil, vc.CommitmentOpening{}, fmt.Errorf("credential %s has no attribute commitments", credentialID)
+	}
+	commitment, ok := credential.Commitments.Find(claim)
+	if !ok {
+		return nil, vc.CommitmentOpening{}, fmt.Errorf("claim %s of credential %s is not committed", claim, credentialID)
+	}
+	opening, ok := findOpening(credential.CommitmentOpenings, claim)
+	if !ok {
+		return nil, vc.CommitmentOpening{}, fmt.Errorf("no opening held for claim %s of credential %s", claim, credentialID)
+	}
+	if err := bbs.VerifyCommitmentOpening(commitment.Commitment, opening.Value, opening.Blinding); err != nil {
+		return nil, vc.CommitmentOpening{}, fmt.Errorf("claim %s of credential %s: %w", claim, credentialID, err)
+	}
+
+	return credential, opening, nil
+}
@@ -0,0 +1,100 @@
+package holder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// issuerPublicKey resolves the BBS+ public key a credential was signed with, or returns nil when
+// the credential has no claim manifest and so no proof can be derived from its signature
+func (uc *UseCase) issuerPublicKey(credential *vc.VerifiableCredential) ([]byte, error) {
+	if credential.Proof == nil || credential.ClaimManifest == nil {
+		return nil, nil
+	}
+
+	keyID := credential.Proof.VerificationMethod
+	keyURL, err := did.ParseDIDURL(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification method: %w", err)
+	}
+	doc, err := uc.didService.ResolveDID(keyURL.DID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	method, ok := doc.FindVerificationMethod(keyID)
+	if !ok || method.Type != "Bls12381G2Key2020" {
+		return nil, fmt.Errorf("issuer DID publishes no BBS+ key %s", keyID)
+	}
+	return did.BBSPublicKey(*method)
+}
+
+// issuerSetKeys resolves the BBS+ keys of a credential's issuer set as they were when the
+// credential was issued, in the order of the set's verification methods
+func (uc *UseCase) issuerSetKeys(credential *vc.VerifiableCredential) ([][]byte, error) {
+	methods := credential.IssuerSetProof.VerificationMethods
+	keys := make([][]byte, len(methods))
+	for i, keyID := range methods {
+		keyURL, err := did.ParseDIDURL(keyID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issuer set key: %w", err)
+		}
+		doc, err := uc.didService.ResolveDID(keyURL.DID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve issuer set member %s: %w", keyURL.DID.String(), err)
+		}
+		method, err := doc.VerificationMethodAt(keyID, credential.IssuedAt())
+		if err != nil {
+			return nil, err
+		}
+		if keys[i], err = did.BBSPublicKey(*method); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// DeriveCredential derives a selective disclosure proof from one of the holder's credentials,
+// revealing the given claims and bound to nonce. It is for integrations that assemble and sign
+// presentations themselves, so no presentation is created and no consent is recorded.
+func (uc *UseCase) DeriveCredential(ctx context.Context, holderDID, credentialID string, revealed []string, nonce string) (*vc.DerivedCredential, error) {
+	credential, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+	}
+
+	if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+	}
+
+	if manifest := credential.ClaimManifest; manifest != nil {
+		if err := manifest.CheckDisclosure(revealed); err != nil {
+			return nil, fmt.Errorf("credential %s: %w", credentialID, err)
+		}
+	}
+
+	request := vc.SelectiveDisclosureRequest{
+		CredentialID:       credentialID,
+		RevealedAttributes: revealed,
+		Nonce:              nonce,
+	}
+	publicKey, err := uc.issuerPublicKey(credential)
+	if err != nil {
+		return nil, fmt.Errorf("credential %s: %w", credentialID, err)
+	}
+	request.IssuerPublicKey = publicKey
+	if publicKey != nil {
+		request.ProofTemplate = uc.takeProofTemplate(credentialID)
+	}
+
+	// The service derives credentials only as part of a presentation; the unsigned presentation
+	// around this one is dropped
+	presentation, err := uc.vcService.CreatePresentationContext(ctx, holderDID, []*vc.VerifiableCredential{credential}, []vc.SelectiveDisclosureRequest{request})
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive credential: %w", err)
+	}
+	return presentation.VerifiableCredential[0], nil
+}
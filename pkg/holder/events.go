@@ -0,0 +1,116 @@
+package holder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ErrCredentialNotFound is returned when answering a proof request from a credential the wallet
+// does not hold
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// ProofRequestAnswer is the wallet's presentation answering a proof request
+type ProofRequestAnswer struct {
+	Presentation *vc.VerifiablePresentation
+	// RevealedClaims are the required claims and the optional ones the credential carries
+	RevealedClaims []string
+	// WithheldClaims are the optional claims the credential does not carry
+	WithheldClaims []string
+	// HiddenClaims are the credential's claims the presentation does not reveal
+	HiddenClaims []string
+}
+
+// SetEventBus makes the wallet answer CreatePresentation requests on bus and publish a
+// CredentialStored event for every credential it accepts
+func (uc *UseCase) SetEventBus(bus *events.Bus) error {
+	err := bus.Handle(events.CreatePresentation, func(ctx context.Context, event events.Event) (interface{}, error) {
+		data, ok := event.Data.(events.CreatePresentationData)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s payload %T", event.Type, event.Data)
+		}
+
+		answer, err := uc.AnswerProofRequest(ctx, data.HolderDID, data.CredentialID, data.Request)
+		if err != nil {
+			return nil, err
+		}
+		return events.PresentationCreatedData{
+			Presentation:   answer.Presentation,
+			RevealedClaims: answer.RevealedClaims,
+			WithheldClaims: answer.WithheldClaims,
+			HiddenClaims:   answer.HiddenClaims,
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.events = bus
+	return nil
+}
+
+// AnswerProofRequest presents one credential in answer to a verifier's proof request, revealing
+// every required claim and the optional ones the credential carries
+func (uc *UseCase) AnswerProofRequest(ctx context.Context, holderDID, credentialID string, request exchange.ProofRequest) (*ProofRequestAnswer, error) {
+	if holderDID == "" || credentialID == "" {
+		return nil, fmt.Errorf("holder DID and credential ID are required")
+	}
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid proof request: %w", err)
+	}
+	if request.Nonce == "" {
+		return nil, fmt.Errorf("invalid proof request: the verifier's nonce is missing")
+	}
+
+	credential, err := uc.GetCredential(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCredentialNotFound, err)
+	}
+
+	answer := &ProofRequestAnswer{RevealedClaims: append([]string{}, request.RequiredClaims...)}
+	for _, claim := range request.OptionalClaims {
+		if _, ok := credential.CredentialSubject[claim]; ok {
+			answer.RevealedClaims = append(answer.RevealedClaims, claim)
+		} else {
+			answer.WithheldClaims = append(answer.WithheldClaims, claim)
+		}
+	}
+
+	answer.Presentation, err = uc.CreatePresentationContext(ctx, PresentationRequest{
+		HolderDID:     holderDID,
+		CredentialIDs: []string{credentialID},
+		SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+			{CredentialID: credentialID, RevealedAttributes: answer.RevealedClaims},
+		},
+		Nonce:           request.Nonce,
+		RequestMetadata: request.RequestMetadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for claim := range credential.CredentialSubject {
+		if claim != "id" && !containsString(answer.RevealedClaims, claim) {
+			answer.HiddenClaims = append(answer.HiddenClaims, claim)
+		}
+	}
+	sort.Strings(answer.HiddenClaims)
+
+	return answer, nil
+}
+
+// publish queues an event without waiting for its subscribers
+func (uc *UseCase) publish(t events.Type, data interface{}) {
+	if uc.events == nil {
+		return
+	}
+	if err := uc.events.PublishAsync(events.New(t, data)); err != nil {
+		log.Printf("⚠️  Failed to publish %s event: %v", t, err)
+	}
+}
@@ -0,0 +1,104 @@
+package holder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultEvidenceGrantTTL is how long an evidence grant lasts when no lifetime is given
+const DefaultEvidenceGrantTTL = time.Hour
+
+// GrantEvidenceAccess signs a grant letting a verifier retrieve one evidence document of the
+// holder's credential from its issuer. For an encrypted document the grant carries the document
+// key, encrypted so only the verifier can read it.
+func (uc *UseCase) GrantEvidenceAccess(holderDID, credentialID, evidenceID, verifierDID string, ttl time.Duration) (*vc.EvidenceGrant, error) {
+	if verifierDID == "" {
+		return nil, fmt.Errorf("verifier DID is required")
+	}
+	if ttl < 0 {
+		return nil, fmt.Errorf("grant lifetime must not be negative")
+	}
+	if ttl == 0 {
+		ttl = DefaultEvidenceGrantTTL
+	}
+
+	credential, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+	}
+
+	if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+	}
+
+	if credential.Evidence == nil {
+		return nil, fmt.Errorf("credential %s has no evidence", credentialID)
+	}
+	evidence, ok := credential.Evidence.Find(evidenceID)
+	if !ok {
+		return nil, fmt.Errorf("credential %s has no evidence %s", credentialID, evidenceID)
+	}
+
+	grant := &vc.EvidenceGrant{
+		ID:           "urn:uuid:" + uuid.New().String(),
+		CredentialID: credentialID,
+		EvidenceID:   evidenceID,
+		Holder:       holderDID,
+		Verifier:     verifierDID,
+		Expires:      uc.clock.Now().Add(ttl).UTC(),
+	}
+
+	if evidence.Encrypted {
+		key, ok := findEvidenceKey(credential.EvidenceKeys, evidenceID)
+		if !ok {
+			return nil, fmt.Errorf("no key held for evidence %s", evidenceID)
+		}
+		grant.Key, err = uc.didService.EncryptForDID(verifierDID, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt evidence key: %w", err)
+		}
+	}
+
+	doc, err := uc.didService.ResolveDID(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve holder DID: %w", err)
+	}
+	if len(doc.Authentication) == 0 {
+		return nil, fmt.Errorf("holder DID has no authentication key")
+	}
+
+	grant.Proof = &vc.Proof{
+		Type:               "Ed25519Signature2020",
+		Created:            uc.clock.Now(),
+		VerificationMethod: doc.Authentication[0],
+		ProofPurpose:       "authentication",
+	}
+
+	payload, err := vc.EvidenceGrantSigningInput(grant)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := uc.didService.SignWithDID(grant.Proof.VerificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign evidence grant: %w", err)
+	}
+	grant.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	return grant, nil
+}
+
+// findEvidenceKey returns the key of the encrypted evidence document with the given ID
+func findEvidenceKey(keys []vc.EvidenceKey, evidenceID string) ([]byte, bool) {
+	for _, key := range keys {
+		if key.EvidenceID == evidenceID {
+			return key.Key, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,37 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ImportCredential stores a BBS+ credential issued outside this service: it is parsed from its
+// JSON-LD form, checked to belong to the holder, and its signature verified under the issuer's
+// resolved key, so presentations can be derived from it like from any other wallet credential.
+func (uc *UseCase) ImportCredential(holderDID string, raw []byte) (*vc.VerifiableCredential, error) {
+	credential, err := vc.ParseCredential(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+	if subjectID, _ := credential.CredentialSubject["id"].(string); subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", credential.ID, holderDID)
+	}
+
+	verifier, ok := uc.vcService.(vc.SignatureVerifier)
+	if !ok {
+		return nil, fmt.Errorf("credential service cannot verify BBS+ signatures")
+	}
+	publicKey, err := uc.issuerPublicKey(credential)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.VerifyCredentialSignature(credential, publicKey); err != nil {
+		return nil, fmt.Errorf("credential signature verification failed: %w", err)
+	}
+
+	if err := uc.StoreCredential(credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
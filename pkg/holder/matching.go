@@ -0,0 +1,180 @@
+package holder
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Score weights used to rank candidate credentials
+const (
+	coverageWeight   = 0.4
+	freshnessWeight  = 0.2
+	trustWeight      = 0.2
+	minimalityWeight = 0.2
+)
+
+// freshnessHalfLife is the credential age at which the freshness score halves
+const freshnessHalfLife = 365 * 24 * time.Hour
+
+// CredentialQuery describes what a verifier asks for
+type CredentialQuery struct {
+	RequiredClaims  []string
+	TrustedIssuers  []string
+	CredentialTypes []string
+}
+
+// MatchScore breaks a candidate's score down into its components, each in [0, 1]
+type MatchScore struct {
+	Total      float64 `json:"total"`
+	Coverage   float64 `json:"coverage"`
+	Freshness  float64 `json:"freshness"`
+	Trust      float64 `json:"trust"`
+	Minimality float64 `json:"minimality"`
+}
+
+// CredentialMatch is a wallet credential that can satisfy (part of) a query
+type CredentialMatch struct {
+	Credential    *vc.VerifiableCredential
+	Score         MatchScore
+	MatchedClaims []string
+	MissingClaims []string
+	// Disclosure is the suggested selective disclosure revealing only the matched claims
+	Disclosure vc.SelectiveDisclosureRequest
+}
+
+// FindMatchingCredentials scans the holder's wallet for credentials that can answer the
+// query and returns them ranked by coverage, freshness, issuer trust and minimal disclosure.
+// Expired credentials, credentials of the wrong type and credentials from untrusted issuers
+// are skipped, as the verifier would reject them anyway.
+func (uc *UseCase) FindMatchingCredentials(holderDID string, query CredentialQuery) ([]*CredentialMatch, error) {
+	if holderDID == "" {
+		return nil, fmt.Errorf("holder DID is required")
+	}
+
+	if len(query.RequiredClaims) == 0 {
+		return nil, fmt.Errorf("at least one required claim is needed")
+	}
+
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	now := uc.clock.Now()
+	var matches []*CredentialMatch
+	for _, credential := range credentials {
+		if expiresAt := credential.ExpiresAt(); expiresAt != nil && expiresAt.Before(now) {
+			continue
+		}
+
+		if !hasAnyType(credential, query.CredentialTypes) {
+			continue
+		}
+
+		trusted := containsString(query.TrustedIssuers, credential.Issuer())
+		if len(query.TrustedIssuers) > 0 && !trusted {
+			continue
+		}
+
+		var matched, missing []string
+		for _, claim := range query.RequiredClaims {
+			// A claim its issuer never lets be revealed cannot answer the query
+			_, ok := credential.CredentialSubject[claim]
+			if ok && credential.ClaimManifest != nil && credential.ClaimManifest.Restriction(claim) == schema.DisclosureNever {
+				ok = false
+			}
+			if ok {
+				matched = append(matched, claim)
+			} else {
+				missing = append(missing, claim)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		score := MatchScore{
+			Coverage:   float64(len(matched)) / float64(len(query.RequiredClaims)),
+			Freshness:  freshnessScore(credential.IssuedAt(), now),
+			Trust:      0.5,
+			Minimality: minimalityScore(credential, len(matched)),
+		}
+		if trusted {
+			score.Trust = 1
+		}
+		score.Total = coverageWeight*score.Coverage +
+			freshnessWeight*score.Freshness +
+			trustWeight*score.Trust +
+			minimalityWeight*score.Minimality
+
+		matches = append(matches, &CredentialMatch{
+			Credential:    credential,
+			Score:         score,
+			MatchedClaims: matched,
+			MissingClaims: missing,
+			Disclosure: vc.SelectiveDisclosureRequest{
+				CredentialID:       credential.ID,
+				RevealedAttributes: matched,
+			},
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score.Total != matches[j].Score.Total {
+			return matches[i].Score.Total > matches[j].Score.Total
+		}
+		return matches[i].Credential.ID < matches[j].Credential.ID
+	})
+
+	return matches, nil
+}
+
+// freshnessScore decays from 1 for a just-issued credential, halving every freshnessHalfLife
+func freshnessScore(issuedAt, now time.Time) float64 {
+	age := now.Sub(issuedAt)
+	if age <= 0 {
+		return 1
+	}
+	return 1 / (1 + float64(age)/float64(freshnessHalfLife))
+}
+
+// minimalityScore is the fraction of the credential's claims that stay hidden
+func minimalityScore(credential *vc.VerifiableCredential, revealed int) float64 {
+	total := 0
+	for key := range credential.CredentialSubject {
+		if key != "id" && key != vc.ConfirmationClaim {
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(total-revealed) / float64(total)
+}
+
+// hasAnyType reports whether the credential has one of the wanted types, or wanted is empty
+func hasAnyType(credential *vc.VerifiableCredential, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, t := range credential.Type {
+		if containsString(wanted, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// PrepareCounterOffer answers a proof request with the requested claims the holder is able and
+// willing to prove. Claims listed in withhold, and claims no usable credential in the wallet
+// carries, are reported as withheld. Whether the verifier accepts the offer is up to its policy.
+func (uc *UseCase) PrepareCounterOffer(holderDID string, request exchange.ProofRequest, withhold []string, reason string) (*exchange.CounterOffer, error) {
+	if holderDID == "" {
+		return nil, fmt.Errorf("holder DID is required")
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid proof request: %w", err)
+	}
+
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	// Claims the holder can prove from unexpired credentials of trusted issuers
+	now := uc.clock.Now()
+	available := make(map[string]bool)
+	for _, credential := range credentials {
+		if expiresAt := credential.ExpiresAt(); expiresAt != nil && expiresAt.Before(now) {
+			continue
+		}
+		if len(request.TrustedIssuers) > 0 && !containsString(request.TrustedIssuers, credential.Issuer()) {
+			continue
+		}
+		for key := range credential.CredentialSubject {
+			if key != "id" && key != vc.ConfirmationClaim {
+				available[key] = true
+			}
+		}
+	}
+
+	offer := &exchange.CounterOffer{
+		OfferedClaims: []string{},
+		Reason:        reason,
+	}
+	for _, claim := range request.Claims() {
+		if available[claim] && !containsString(withhold, claim) {
+			offer.OfferedClaims = append(offer.OfferedClaims, claim)
+		} else {
+			offer.WithheldClaims = append(offer.WithheldClaims, claim)
+		}
+	}
+
+	return offer, nil
+}
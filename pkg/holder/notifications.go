@@ -0,0 +1,172 @@
+package holder
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// noticeKeyPrefix keys the latest status notification received for each credential
+const noticeKeyPrefix = "status-notice:"
+
+// StatusSubscriber accepts holder-signed subscriptions to an issuer's status notifications
+type StatusSubscriber interface {
+	SubscribeToStatus(subscription *vc.StatusSubscription) error
+}
+
+// SetStatusSubscriber sets where the holder sends its status subscriptions
+func (uc *UseCase) SetStatusSubscriber(subscriber StatusSubscriber) {
+	uc.subscriber = subscriber
+}
+
+// SubscribeToStatus signs a subscription asking the issuer to send status notifications for the
+// holder's credentials to endpoint, and sends it to the issuer when a subscriber is set
+func (uc *UseCase) SubscribeToStatus(holderDID, issuerDID, endpoint string) (*vc.StatusSubscription, error) {
+	if issuerDID == "" {
+		return nil, fmt.Errorf("issuer DID is required")
+	}
+	if _, err := uc.loadHolder(holderDID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("holder not found: %s", holderDID)
+		}
+		return nil, fmt.Errorf("failed to load holder: %w", err)
+	}
+
+	doc, err := uc.didService.ResolveDID(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve holder DID: %w", err)
+	}
+	if len(doc.Authentication) == 0 {
+		return nil, fmt.Errorf("holder DID has no authentication key")
+	}
+
+	now := uc.clock.Now().UTC()
+	subscription := &vc.StatusSubscription{
+		Holder:   holderDID,
+		Issuer:   issuerDID,
+		Endpoint: endpoint,
+		Created:  now,
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            now,
+			VerificationMethod: doc.Authentication[0],
+			ProofPurpose:       "authentication",
+		},
+	}
+
+	payload, err := vc.StatusSubscriptionSigningInput(subscription)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := uc.didService.SignWithDID(subscription.Proof.VerificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign status subscription: %w", err)
+	}
+	subscription.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	if uc.subscriber != nil {
+		if err := uc.subscriber.SubscribeToStatus(subscription); err != nil {
+			return nil, fmt.Errorf("issuer refused status subscription: %w", err)
+		}
+	}
+
+	return subscription, nil
+}
+
+// ReceiveStatusNotification checks an issuer's notification about one of the wallet's credentials
+// and flags the credential. Cached status snapshots for the credential are replaced, or dropped
+// when the issuer cannot be reached, so presentations never carry the outdated status.
+func (uc *UseCase) ReceiveStatusNotification(notification *vc.StatusNotification) error {
+	if notification == nil {
+		return fmt.Errorf("status notification is required")
+	}
+
+	credential, err := uc.credRepo.Retrieve(notification.CredentialID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve credential %s: %w", notification.CredentialID, err)
+	}
+	if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != notification.Holder {
+		return fmt.Errorf("credential %s does not belong to holder %s", notification.CredentialID, notification.Holder)
+	}
+	if credential.Issuer() != notification.Issuer {
+		return fmt.Errorf("credential %s was not issued by %s", notification.CredentialID, notification.Issuer)
+	}
+
+	if notification.Proof == nil || notification.Proof.ProofValue == "" {
+		return fmt.Errorf("status notification is not signed")
+	}
+	if !did.IsVerificationMethodOf(notification.Proof.VerificationMethod, notification.Issuer) {
+		return fmt.Errorf("status notification must be signed by the issuer")
+	}
+	signature, err := did.DecodeSignatureMultibase(notification.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+	payload, err := vc.StatusNotificationSigningInput(notification)
+	if err != nil {
+		return err
+	}
+	if err := uc.didService.VerifyWithDID(notification.Issuer, notification.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("invalid status notification signature: %w", err)
+	}
+
+	// Deliveries may arrive out of order; only a newer notification replaces the flag
+	current, err := uc.GetStatusNotice(notification.CredentialID)
+	if err != nil {
+		return err
+	}
+	if current != nil && !notification.Created.After(current.Created) {
+		return nil
+	}
+	if err := storage.SetJSON(uc.store, noticeKeyPrefix+notification.CredentialID, notification, 0); err != nil {
+		return fmt.Errorf("failed to store status notification: %w", err)
+	}
+
+	uc.invalidateProofTemplates(notification.CredentialID)
+	uc.snapshotsMu.Lock()
+	delete(uc.snapshots, notification.CredentialID)
+	uc.snapshotsMu.Unlock()
+	if uc.snapshotSource != nil && len(credential.CredentialStatus) > 0 {
+		// A failed fetch leaves no snapshot cached, so the next presentation fetches again
+		_, _ = uc.fetchStatusSnapshots(notification.CredentialID)
+	}
+
+	return nil
+}
+
+// GetStatusNotice returns the latest status notification received for a credential, or nil
+func (uc *UseCase) GetStatusNotice(credentialID string) (*vc.StatusNotification, error) {
+	var notification vc.StatusNotification
+	err := storage.GetJSON(uc.store, noticeKeyPrefix+credentialID, &notification)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status notification: %w", err)
+	}
+	return &notification, nil
+}
+
+// ListStatusNotices returns the latest status notification for each of a holder's credentials
+// that has received one, in the order the credentials are listed
+func (uc *UseCase) ListStatusNotices(holderDID string) ([]*vc.StatusNotification, error) {
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	var notices []*vc.StatusNotification
+	for _, credential := range credentials {
+		notice, err := uc.GetStatusNotice(credential.ID)
+		if err != nil {
+			return nil, err
+		}
+		if notice != nil {
+			notices = append(notices, notice)
+		}
+	}
+	return notices, nil
+}
@@ -0,0 +1,60 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SetPredicateRegistry sets the providers predicate proofs are created with. Proofs are created
+// with the registry's default provider.
+func (uc *UseCase) SetPredicateRegistry(registry *predicate.Registry) {
+	uc.predicates = registry
+}
+
+// provePredicates proves the requested predicates about a credential's committed claims and
+// attaches the proofs to its derived credential. Each proof is bound to the derived proof's nonce.
+func (uc *UseCase) provePredicates(credential *vc.VerifiableCredential, request vc.SelectiveDisclosureRequest, derived *vc.DerivedCredential) error {
+	if uc.predicates == nil {
+		return fmt.Errorf("no predicate providers configured")
+	}
+
+	provider, ok := uc.predicates.Default()
+	if !ok {
+		return fmt.Errorf("no predicate providers configured")
+	}
+
+	var nonce string
+	if derived.Proof != nil {
+		nonce = derived.Proof.Nonce
+	}
+
+	var proofs []vc.PredicateProof
+	for _, statement := range request.Predicates {
+		commitment, ok := credential.Commitments.Find(statement.Claim)
+		if !ok {
+			return fmt.Errorf("claim %s is not committed", statement.Claim)
+		}
+
+		opening, ok := findOpening(credential.CommitmentOpenings, statement.Claim)
+		if !ok {
+			return fmt.Errorf("no opening held for claim %s", statement.Claim)
+		}
+
+		context := vc.PredicateContext(credential.ID, statement, commitment.Commitment, nonce)
+		proofValue, err := provider.Prove(statement, *commitment, opening, context)
+		if err != nil {
+			return fmt.Errorf("failed to prove %s: %w", statement, err)
+		}
+
+		proofs = append(proofs, vc.PredicateProof{
+			PredicateStatement: statement,
+			Provider:           provider.Name(),
+			ProofValue:         proofValue,
+		})
+	}
+
+	derived.Predicates = proofs
+	return nil
+}
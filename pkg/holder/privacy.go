@@ -0,0 +1,52 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/privacy"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DisclosureReport summarizes how much a presentation reveals
+type DisclosureReport struct {
+	Purpose string
+	// Score is the lowest per-credential privacy score, 0-100
+	Score       int
+	Warnings    []string
+	Credentials map[string]*privacy.Advice
+}
+
+// AnalyzeDisclosure checks each selective disclosure in the request against the
+// stated purpose and the claims its credential holds
+func (uc *UseCase) AnalyzeDisclosure(req PresentationRequest) (*DisclosureReport, error) {
+	report := &DisclosureReport{
+		Purpose:     req.Purpose,
+		Score:       100,
+		Credentials: make(map[string]*privacy.Advice),
+	}
+
+	for _, sd := range req.SelectiveDisclosure {
+		credential, err := uc.credRepo.Retrieve(sd.CredentialID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve credential %s: %w", sd.CredentialID, err)
+		}
+
+		var available []string
+		for key := range credential.CredentialSubject {
+			if key != "id" && key != vc.ConfirmationClaim {
+				available = append(available, key)
+			}
+		}
+
+		advice := uc.advisor.Advise(req.Purpose, sd.RevealedAttributes, available)
+		report.Credentials[sd.CredentialID] = advice
+		if advice.Score < report.Score {
+			report.Score = advice.Score
+		}
+		for _, warning := range advice.Warnings {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %s", sd.CredentialID, warning))
+		}
+	}
+
+	return report, nil
+}
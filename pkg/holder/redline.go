@@ -0,0 +1,181 @@
+package holder
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/privacy"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Disclosure is how a presentation would disclose one of a credential's attributes
+type Disclosure string
+
+const (
+	DisclosureRevealed Disclosure = "revealed"
+	DisclosureHidden   Disclosure = "hidden"
+	// DisclosureProven attributes stay hidden, but statements about them are proven
+	DisclosureProven Disclosure = "proven"
+)
+
+// RedlineAttribute is one attribute of a credential and how a presentation would disclose it
+type RedlineAttribute struct {
+	Name       string      `json:"name"`
+	Value      interface{} `json:"value"`
+	Disclosure Disclosure  `json:"disclosure"`
+	// Always is set for attributes every presentation of the credential reveals: the subject
+	// identifier and the key the credential is bound to
+	Always      bool                     `json:"always,omitempty"`
+	Predicates  []vc.PredicateStatement  `json:"predicates,omitempty"`
+	Sensitivity float64                  `json:"sensitivity"`
+	Level       privacy.SensitivityLevel `json:"level"`
+	// Restriction is the issuer's restriction on revealing the attribute
+	Restriction schema.DisclosureRestriction `json:"restriction,omitempty"`
+	// Unnecessary marks revealed attributes the stated purpose does not need, and Substitute names a
+	// less revealing attribute that serves the purpose instead
+	Unnecessary bool   `json:"unnecessary,omitempty"`
+	Substitute  string `json:"substitute,omitempty"`
+}
+
+// CredentialRedline shows every attribute of a credential against what a presentation would
+// reveal, for a consent screen shown before the presentation is created
+type CredentialRedline struct {
+	CredentialID string   `json:"credentialId"`
+	Types        []string `json:"types"`
+	Issuer       string   `json:"issuer"`
+	// IssuerHidden is set when the issuer set is presented in place of the issuer
+	IssuerHidden bool               `json:"issuerHidden,omitempty"`
+	Attributes   []RedlineAttribute `json:"attributes"`
+	// ProvenAbsent lists claims the presentation would prove the credential does not have
+	ProvenAbsent []string `json:"provenAbsent,omitempty"`
+	// Score and MinimalScore are the privacy scores of the disclosure and of the least revealing
+	// disclosure serving the purpose, 0-100
+	Score        int      `json:"score"`
+	MinimalScore int      `json:"minimalScore"`
+	Warnings     []string `json:"warnings,omitempty"`
+	// Refused is why the issuer's restrictions would refuse the disclosure
+	Refused string `json:"refused,omitempty"`
+}
+
+// RedlineReport holds the redline of each credential a presentation request discloses
+type RedlineReport struct {
+	Purpose     string               `json:"purpose,omitempty"`
+	Credentials []*CredentialRedline `json:"credentials"`
+}
+
+// Redline previews what a presentation request would reveal of each of its credentials, without
+// creating the presentation
+func (uc *UseCase) Redline(req PresentationRequest) (*RedlineReport, error) {
+	if len(req.SelectiveDisclosure) == 0 {
+		return nil, fmt.Errorf("at least one selective disclosure is required")
+	}
+
+	report := &RedlineReport{Purpose: req.Purpose}
+	for _, sd := range req.SelectiveDisclosure {
+		redline, err := uc.RedlineCredential(req.HolderDID, sd, req.Purpose)
+		if err != nil {
+			return nil, err
+		}
+		report.Credentials = append(report.Credentials, redline)
+	}
+
+	return report, nil
+}
+
+// RedlineCredential previews what a selective disclosure would reveal of one of the holder's
+// credentials: each attribute with whether it would be revealed, hidden or proven about, and how
+// sensitive it is
+func (uc *UseCase) RedlineCredential(holderDID string, sd vc.SelectiveDisclosureRequest, purpose string) (*CredentialRedline, error) {
+	credential, err := uc.credRepo.Retrieve(sd.CredentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential %s: %w", sd.CredentialID, err)
+	}
+	if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", sd.CredentialID, holderDID)
+	}
+
+	revealed := make(map[string]bool, len(sd.RevealedAttributes))
+	for _, name := range sd.RevealedAttributes {
+		if _, ok := credential.CredentialSubject[name]; !ok {
+			return nil, fmt.Errorf("credential %s has no attribute %s", sd.CredentialID, name)
+		}
+		revealed[name] = true
+	}
+
+	if len(sd.Predicates) > 0 && !sd.IncludeCommitments {
+		return nil, fmt.Errorf("credential %s: predicates need includeCommitments", sd.CredentialID)
+	}
+	predicates := make(map[string][]vc.PredicateStatement)
+	for _, statement := range sd.Predicates {
+		if _, ok := credential.CredentialSubject[statement.Claim]; !ok {
+			return nil, fmt.Errorf("credential %s has no attribute %s", sd.CredentialID, statement.Claim)
+		}
+		predicates[statement.Claim] = append(predicates[statement.Claim], statement)
+	}
+
+	var available []string
+	for name := range credential.CredentialSubject {
+		if name != "id" && name != vc.ConfirmationClaim {
+			available = append(available, name)
+		}
+	}
+	advice := uc.advisor.Advise(purpose, sd.RevealedAttributes, available)
+	unnecessary := make(map[string]bool, len(advice.Unnecessary))
+	for _, name := range advice.Unnecessary {
+		unnecessary[name] = true
+	}
+
+	redline := &CredentialRedline{
+		CredentialID: credential.ID,
+		Types:        credential.Type,
+		Issuer:       credential.Issuer(),
+		IssuerHidden: sd.HideIssuer,
+		ProvenAbsent: sd.ProveAbsent,
+		Score:        advice.Score,
+		MinimalScore: advice.MinimalScore,
+		Warnings:     advice.Warnings,
+	}
+
+	for name, value := range credential.CredentialSubject {
+		sensitivity := uc.advisor.Sensitivity(name)
+		attribute := RedlineAttribute{
+			Name:        name,
+			Value:       value,
+			Disclosure:  DisclosureHidden,
+			Always:      name == "id" || name == vc.ConfirmationClaim,
+			Predicates:  predicates[name],
+			Sensitivity: sensitivity,
+			Level:       privacy.LevelOf(sensitivity),
+			Unnecessary: unnecessary[name],
+			Substitute:  advice.Substitutions[name],
+		}
+		switch {
+		case attribute.Always || revealed[name]:
+			attribute.Disclosure = DisclosureRevealed
+		case len(attribute.Predicates) > 0:
+			attribute.Disclosure = DisclosureProven
+		}
+		if credential.ClaimManifest != nil {
+			attribute.Restriction = credential.ClaimManifest.Restriction(name)
+		}
+		redline.Attributes = append(redline.Attributes, attribute)
+	}
+
+	// Attributes every presentation reveals come first, the rest by name
+	sort.Slice(redline.Attributes, func(i, j int) bool {
+		a, b := redline.Attributes[i], redline.Attributes[j]
+		if a.Always != b.Always {
+			return a.Always
+		}
+		return a.Name < b.Name
+	})
+
+	if credential.ClaimManifest != nil {
+		if err := credential.ClaimManifest.CheckDisclosure(sd.RevealedAttributes); err != nil {
+			redline.Refused = err.Error()
+		}
+	}
+
+	return redline, nil
+}
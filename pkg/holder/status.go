@@ -0,0 +1,213 @@
+package holder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultStatusRefreshInterval is how often the wallet re-checks credential status by default
+const DefaultStatusRefreshInterval = 15 * time.Minute
+
+// CredentialState is the wallet's view of whether a credential is still in force
+type CredentialState string
+
+const (
+	CredentialActive    CredentialState = "active"
+	CredentialSuspended CredentialState = "suspended"
+	CredentialRevoked   CredentialState = "revoked"
+	// CredentialUnchecked means the wallet holds no status snapshot or notification for the credential
+	CredentialUnchecked CredentialState = "unchecked"
+)
+
+// CredentialStatus is the latest status the wallet knows for one of its credentials
+type CredentialStatus struct {
+	CredentialID string          `json:"credentialId"`
+	State        CredentialState `json:"state"`
+	// CheckedAt is when the issuer stated the status: the oldest cached snapshot's timestamp, or
+	// the creation time of a newer notification
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+}
+
+// StatusSnapshotSource provides issuer-signed status snapshots for a credential
+type StatusSnapshotSource interface {
+	IssueStatusSnapshots(credentialID string) ([]*vc.StatusSnapshot, error)
+}
+
+// SetStatusSnapshotSource sets where the holder fetches status snapshots from
+func (uc *UseCase) SetStatusSnapshotSource(source StatusSnapshotSource) {
+	uc.snapshotSource = source
+}
+
+// RefreshStatusSnapshots fetches fresh status snapshots for every holder credential that
+// carries status entries, so they can later be presented without reaching the issuer.
+// It returns the number of credentials refreshed.
+func (uc *UseCase) RefreshStatusSnapshots(holderDID string) (int, error) {
+	if uc.snapshotSource == nil {
+		return 0, fmt.Errorf("no status snapshot source configured")
+	}
+
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	refreshed := 0
+	for _, credential := range credentials {
+		if len(credential.CredentialStatus) == 0 {
+			continue
+		}
+		if _, err := uc.fetchStatusSnapshots(credential.ID); err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+
+	return refreshed, nil
+}
+
+// RunStatusRefresher refreshes the status snapshots of every wallet's credentials at the given
+// interval until the context is cancelled, so presentations can carry recent status offline
+func (uc *UseCase) RunStatusRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			holderDIDs, err := uc.storedHolderDIDs()
+			if err != nil {
+				log.Printf("failed to list holders to refresh: %v", err)
+				continue
+			}
+			for _, holderDID := range holderDIDs {
+				if _, err := uc.RefreshStatusSnapshots(holderDID); err != nil {
+					log.Printf("failed to refresh status for holder %s: %v", holderDID, err)
+				}
+			}
+		}
+	}
+}
+
+// CredentialStatuses returns the latest known status of each of the holder's credentials that
+// carries status entries, keyed by credential ID. It combines the cached snapshots with any newer
+// status notification and does not contact the issuer.
+func (uc *UseCase) CredentialStatuses(holderDID string) (map[string]*CredentialStatus, error) {
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	statuses := make(map[string]*CredentialStatus)
+	for _, credential := range credentials {
+		if len(credential.CredentialStatus) == 0 {
+			continue
+		}
+
+		uc.snapshotsMu.RLock()
+		snapshots := uc.snapshots[credential.ID]
+		uc.snapshotsMu.RUnlock()
+
+		notice, err := uc.GetStatusNotice(credential.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses[credential.ID] = credentialStatus(credential.ID, snapshots, notice)
+	}
+	return statuses, nil
+}
+
+// credentialStatus derives a credential's state from its snapshots and a notification newer than them
+func credentialStatus(credentialID string, snapshots []*vc.StatusSnapshot, notice *vc.StatusNotification) *CredentialStatus {
+	result := &CredentialStatus{CredentialID: credentialID, State: CredentialUnchecked}
+
+	var revoked, suspended bool
+	for _, snapshot := range snapshots {
+		switch snapshot.StatusPurpose {
+		case status.PurposeRevocation:
+			revoked = revoked || snapshot.Status
+		case status.PurposeSuspension:
+			suspended = suspended || snapshot.Status
+		}
+		if result.CheckedAt == nil || snapshot.Timestamp.Before(*result.CheckedAt) {
+			timestamp := snapshot.Timestamp
+			result.CheckedAt = &timestamp
+		}
+	}
+
+	if notice != nil && (result.CheckedAt == nil || notice.Created.After(*result.CheckedAt)) {
+		switch notice.Event {
+		case vc.StatusEventRevoked:
+			revoked = true
+		case vc.StatusEventSuspended:
+			suspended = true
+		case vc.StatusEventReinstated:
+			suspended = false
+		}
+		created := notice.Created
+		result.CheckedAt = &created
+	}
+
+	switch {
+	case result.CheckedAt == nil:
+	case revoked:
+		result.State = CredentialRevoked
+	case suspended:
+		result.State = CredentialSuspended
+	default:
+		result.State = CredentialActive
+	}
+	return result
+}
+
+// storedHolderDIDs returns the DIDs of every holder set up in the store
+func (uc *UseCase) storedHolderDIDs() ([]string, error) {
+	keys, err := uc.store.Keys(holderKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holders: %w", err)
+	}
+
+	dids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		dids = append(dids, strings.TrimPrefix(key, holderKeyPrefix))
+	}
+	return dids, nil
+}
+
+// statusSnapshots returns the cached snapshots for a credential, fetching them when none are cached
+func (uc *UseCase) statusSnapshots(credential *vc.VerifiableCredential) ([]*vc.StatusSnapshot, error) {
+	uc.snapshotsMu.RLock()
+	cached, ok := uc.snapshots[credential.ID]
+	uc.snapshotsMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	if uc.snapshotSource == nil {
+		return nil, fmt.Errorf("no status snapshot held for credential %s", credential.ID)
+	}
+
+	return uc.fetchStatusSnapshots(credential.ID)
+}
+
+// fetchStatusSnapshots fetches and caches the snapshots for a credential
+func (uc *UseCase) fetchStatusSnapshots(credentialID string) ([]*vc.StatusSnapshot, error) {
+	snapshots, err := uc.snapshotSource.IssueStatusSnapshots(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status snapshots for credential %s: %w", credentialID, err)
+	}
+
+	uc.snapshotsMu.Lock()
+	uc.snapshots[credentialID] = snapshots
+	uc.snapshotsMu.Unlock()
+
+	return snapshots, nil
+}
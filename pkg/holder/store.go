@@ -0,0 +1,193 @@
+package holder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// Store key prefixes for the holder's state
+const (
+	holderKeyPrefix   = "holder:"
+	pairwiseKeyPrefix = "pairwise:"
+	consentKeyPrefix  = "consent:"
+	// presentationTokenKeyPrefix stores unredeemed presentation tokens, which expire with the store
+	presentationTokenKeyPrefix = "presentation-token:"
+	// redeemedTokenKeyPrefix marks a presentation token as redeemed, so only one caller receives it
+	redeemedTokenKeyPrefix = "redeemed-token:"
+)
+
+// storedIdentity is the stored form of a holder or pairwise DID; the key pair is encoded with
+// did.EncodeKeyPair so its key agreement key survives
+type storedIdentity struct {
+	DID         did.DID          `json:"did"`
+	DIDDoc      *did.DIDDocument `json:"didDocument,omitempty"`
+	VerifierDID string           `json:"verifierDid,omitempty"`
+	KeyPair     json.RawMessage  `json:"keyPair"`
+}
+
+// SetStore keeps the holder setups, pairwise DIDs, consent records, presentation tokens and status
+// notifications in store, so every server instance sharing it serves the same wallets. A nil store
+// restores the in-memory default. It must be called before the use case serves requests.
+func (uc *UseCase) SetStore(store storage.KVStore) {
+	if store == nil {
+		store = storage.NewMemoryStore()
+	}
+	uc.store = store
+}
+
+// saveHolder stores a holder setup under its DID
+func (uc *UseCase) saveHolder(setup *HolderSetup) error {
+	keyPair, err := did.EncodeKeyPair(setup.KeyPair)
+	if err != nil {
+		return fmt.Errorf("failed to encode holder key pair: %w", err)
+	}
+
+	stored := &storedIdentity{DID: *setup.DID, DIDDoc: setup.DIDDoc, KeyPair: keyPair}
+	if err := storage.SetJSON(uc.store, holderKeyPrefix+setup.DID.String(), stored, 0); err != nil {
+		return fmt.Errorf("failed to store holder: %w", err)
+	}
+	return nil
+}
+
+// loadHolder loads a holder setup, returning storage.ErrNotFound for an unknown DID
+func (uc *UseCase) loadHolder(holderDID string) (*HolderSetup, error) {
+	var stored storedIdentity
+	if err := storage.GetJSON(uc.store, holderKeyPrefix+holderDID, &stored); err != nil {
+		return nil, err
+	}
+
+	keyPair, err := did.DecodeKeyPair(stored.KeyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode holder key pair: %w", err)
+	}
+	return &HolderSetup{DID: &stored.DID, DIDDoc: stored.DIDDoc, KeyPair: keyPair}, nil
+}
+
+func pairwiseKey(holderDID, verifierDID string) string {
+	return pairwiseKeyPrefix + holderDID + "|" + verifierDID
+}
+
+// claimPairwise stores a pairwise DID unless the relationship already has one, reporting whether
+// it was stored. Two instances meeting the same verifier at once thus settle on one DID.
+func (uc *UseCase) claimPairwise(holderDID string, pairwise *PairwiseDID) (bool, error) {
+	data, err := encodePairwise(pairwise)
+	if err != nil {
+		return false, err
+	}
+
+	stored, err := uc.store.SetNX(pairwiseKey(holderDID, pairwise.VerifierDID), data, 0)
+	if err != nil {
+		return false, fmt.Errorf("failed to store pairwise DID: %w", err)
+	}
+	return stored, nil
+}
+
+// savePairwise stores a pairwise DID, replacing any the relationship had
+func (uc *UseCase) savePairwise(holderDID string, pairwise *PairwiseDID) error {
+	data, err := encodePairwise(pairwise)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.store.Set(pairwiseKey(holderDID, pairwise.VerifierDID), data, 0); err != nil {
+		return fmt.Errorf("failed to store pairwise DID: %w", err)
+	}
+	return nil
+}
+
+// loadPairwise loads the holder's pairwise DID for a verifier, returning storage.ErrNotFound when
+// there is none
+func (uc *UseCase) loadPairwise(holderDID, verifierDID string) (*PairwiseDID, error) {
+	data, err := uc.store.Get(pairwiseKey(holderDID, verifierDID))
+	if err != nil {
+		return nil, err
+	}
+	return decodePairwise(data)
+}
+
+// storedPairwise returns the keys and pairwise DIDs of a holder, ordered by verifier DID
+func (uc *UseCase) storedPairwise(holderDID string) ([]string, []*PairwiseDID, error) {
+	keys, err := uc.store.Keys(pairwiseKeyPrefix + holderDID + "|")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pairwise DIDs: %w", err)
+	}
+
+	var found []string
+	var result []*PairwiseDID
+	for _, key := range keys {
+		data, err := uc.store.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load pairwise DID: %w", err)
+		}
+		pairwise, err := decodePairwise(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		found = append(found, key)
+		result = append(result, pairwise)
+	}
+	return found, result, nil
+}
+
+func encodePairwise(pairwise *PairwiseDID) ([]byte, error) {
+	keyPair, err := did.EncodeKeyPair(pairwise.KeyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pairwise key pair: %w", err)
+	}
+	return json.Marshal(&storedIdentity{DID: *pairwise.DID, VerifierDID: pairwise.VerifierDID, KeyPair: keyPair})
+}
+
+func decodePairwise(data []byte) (*PairwiseDID, error) {
+	var stored storedIdentity
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pairwise DID: %w", err)
+	}
+
+	keyPair, err := did.DecodeKeyPair(stored.KeyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pairwise key pair: %w", err)
+	}
+	return &PairwiseDID{VerifierDID: stored.VerifierDID, DID: &stored.DID, KeyPair: keyPair}, nil
+}
+
+// consentKey orders a holder's consent records by creation time
+func consentKey(record *ConsentRecord) string {
+	return fmt.Sprintf("%s%s|%020d:%s", consentKeyPrefix, record.HolderDID, record.CreatedAt.UnixNano(), record.ID)
+}
+
+// saveConsent stores a consent record
+func (uc *UseCase) saveConsent(record *ConsentRecord) error {
+	if err := storage.SetJSON(uc.store, consentKey(record), record, 0); err != nil {
+		return fmt.Errorf("failed to store consent record: %w", err)
+	}
+	return nil
+}
+
+// storedConsents returns a holder's consent records, oldest first
+func (uc *UseCase) storedConsents(holderDID string) ([]*ConsentRecord, error) {
+	keys, err := uc.store.Keys(consentKeyPrefix + holderDID + "|")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consent records: %w", err)
+	}
+
+	records := make([]*ConsentRecord, 0, len(keys))
+	for _, key := range keys {
+		var record ConsentRecord
+		err := storage.GetJSON(uc.store, key, &record)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load consent record: %w", err)
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
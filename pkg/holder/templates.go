@@ -0,0 +1,106 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// MaxProofTemplates caps the templates kept per credential, since each holds blinding secrets
+const MaxProofTemplates = 32
+
+// PrecomputeProofs tops up each credential's pool of proof templates to count, so presentations
+// of it skip the signature check and blinding. No credential IDs means every credential of the
+// holder that a proof can be derived from. It returns the number of templates computed.
+//
+// Templates are kept in memory only and dropped when the credential is stored again or a status
+// notification arrives for it.
+func (uc *UseCase) PrecomputeProofs(holderDID string, credentialIDs []string, count int) (int, error) {
+	if count < 1 || count > MaxProofTemplates {
+		return 0, fmt.Errorf("template count must be between 1 and %d", MaxProofTemplates)
+	}
+	precomputer, ok := uc.vcService.(vc.ProofPrecomputer)
+	if !ok {
+		return 0, fmt.Errorf("credential service cannot precompute proofs")
+	}
+
+	var credentials []*vc.VerifiableCredential
+	if len(credentialIDs) == 0 {
+		all, err := uc.credRepo.List(holderDID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list credentials: %w", err)
+		}
+		for _, credential := range all {
+			if credential.ClaimManifest != nil {
+				credentials = append(credentials, credential)
+			}
+		}
+	}
+	for _, credentialID := range credentialIDs {
+		credential, err := uc.credRepo.Retrieve(credentialID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+		}
+		if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+			return 0, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+		}
+		credentials = append(credentials, credential)
+	}
+
+	computed := 0
+	for _, credential := range credentials {
+		publicKey, err := uc.issuerPublicKey(credential)
+		if err != nil {
+			return computed, fmt.Errorf("credential %s: %w", credential.ID, err)
+		}
+		if publicKey == nil {
+			return computed, fmt.Errorf("credential %s has no claim manifest to derive proofs from", credential.ID)
+		}
+
+		for missing := count - uc.ProofTemplateCount(credential.ID); missing > 0; missing-- {
+			template, err := precomputer.PrecomputeProof(credential, publicKey)
+			if err != nil {
+				return computed, fmt.Errorf("credential %s: %w", credential.ID, err)
+			}
+			uc.templatesMu.Lock()
+			uc.templates[credential.ID] = append(uc.templates[credential.ID], template)
+			uc.templatesMu.Unlock()
+			computed++
+		}
+	}
+
+	return computed, nil
+}
+
+// ProofTemplateCount returns the number of unused templates kept for a credential
+func (uc *UseCase) ProofTemplateCount(credentialID string) int {
+	uc.templatesMu.Lock()
+	defer uc.templatesMu.Unlock()
+	return len(uc.templates[credentialID])
+}
+
+// takeProofTemplate removes and returns one of the credential's templates, or nil
+func (uc *UseCase) takeProofTemplate(credentialID string) *bbs.ProofTemplate {
+	uc.templatesMu.Lock()
+	defer uc.templatesMu.Unlock()
+
+	pool := uc.templates[credentialID]
+	if len(pool) == 0 {
+		return nil
+	}
+	template := pool[len(pool)-1]
+	if len(pool) == 1 {
+		delete(uc.templates, credentialID)
+	} else {
+		uc.templates[credentialID] = pool[:len(pool)-1]
+	}
+	return template
+}
+
+// invalidateProofTemplates drops a credential's templates once it changes or its status does
+func (uc *UseCase) invalidateProofTemplates(credentialID string) {
+	uc.templatesMu.Lock()
+	defer uc.templatesMu.Unlock()
+	delete(uc.templates, credentialID)
+}
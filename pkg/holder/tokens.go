@@ -0,0 +1,165 @@
+package holder
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+const (
+	// DefaultPresentationTokenTTL is how long a presentation token can be redeemed by default
+	DefaultPresentationTokenTTL = 15 * time.Minute
+	// MaxPresentationTokenTTL bounds how long a pre-derived proof may wait for its verifier
+	MaxPresentationTokenTTL = 24 * time.Hour
+)
+
+var (
+	// ErrPresentationTokenNotFound is returned for unknown or expired tokens and wrong secrets
+	ErrPresentationTokenNotFound = errors.New("presentation token not found")
+	// ErrPresentationTokenRedeemed is returned when a token was already redeemed
+	ErrPresentationTokenRedeemed = errors.New("presentation token already redeemed")
+)
+
+// PresentationTokenRequest asks for a presentation to be derived now and handed out later, once,
+// to whoever holds the token's secret
+type PresentationTokenRequest struct {
+	PresentationRequest
+	// TTL is how long the token can be redeemed and the presentation accepted; zero uses
+	// DefaultPresentationTokenTTL
+	TTL time.Duration
+}
+
+// PresentationToken is a minted single-use token. The secret is returned only here; the wallet
+// keeps just its hash.
+type PresentationToken struct {
+	ID        string    `json:"id"`
+	Secret    string    `json:"secret"`
+	HolderDID string    `json:"holderDid"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// storedPresentationToken is a token waiting to be redeemed
+type storedPresentationToken struct {
+	ID           string                     `json:"id"`
+	HolderDID    string                     `json:"holderDid"`
+	SecretHash   string                     `json:"secretHash"`
+	Nonce        string                     `json:"nonce"`
+	Presentation *vc.VerifiablePresentation `json:"presentation"`
+	ExpiresAt    time.Time                  `json:"expiresAt"`
+}
+
+// MintPresentationToken derives a presentation that expires with the token and keeps it until the
+// token is redeemed, so it can be shared by link, e.g. in an email, with a verifier that is not
+// online while the holder is. A nonce is generated when the request has none; the verifier learns
+// it when redeeming.
+func (uc *UseCase) MintPresentationToken(ctx context.Context, req PresentationTokenRequest) (*PresentationToken, error) {
+	if req.TTL < 0 {
+		return nil, fmt.Errorf("token TTL cannot be negative")
+	}
+	if req.TTL == 0 {
+		req.TTL = DefaultPresentationTokenTTL
+	}
+	if req.TTL > MaxPresentationTokenTTL {
+		return nil, fmt.Errorf("token TTL cannot exceed %s", MaxPresentationTokenTTL)
+	}
+
+	if req.Nonce == "" {
+		nonce, err := bbs.GenerateNonce()
+		if err != nil {
+			return nil, err
+		}
+		req.Nonce = nonce
+	}
+
+	// The pre-derived proof is accepted no longer than the token can be redeemed
+	req.ValidFor = req.TTL
+	presentation, err := uc.CreatePresentationContext(ctx, req.PresentationRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := bbs.GenerateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	token := &PresentationToken{
+		ID:        uuid.New().String(),
+		Secret:    secret,
+		HolderDID: req.HolderDID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(req.TTL),
+	}
+	stored := &storedPresentationToken{
+		ID:           token.ID,
+		HolderDID:    token.HolderDID,
+		SecretHash:   hashTokenSecret(secret),
+		Nonce:        req.Nonce,
+		Presentation: presentation,
+		ExpiresAt:    token.ExpiresAt,
+	}
+	if err := storage.SetJSON(uc.store, presentationTokenKeyPrefix+token.ID, stored, req.TTL); err != nil {
+		return nil, fmt.Errorf("failed to store presentation token: %w", err)
+	}
+
+	return token, nil
+}
+
+// RedeemPresentationToken hands out a token's presentation and the nonce it was derived with. Each
+// token is redeemed once: the first caller with the right secret receives the presentation, which
+// the wallet then forgets. A wrong secret does not use the token up.
+func (uc *UseCase) RedeemPresentationToken(id, secret string) (*vc.VerifiablePresentation, string, error) {
+	var stored storedPresentationToken
+	err := storage.GetJSON(uc.store, presentationTokenKeyPrefix+id, &stored)
+	if errors.Is(err, storage.ErrNotFound) {
+		if _, err := uc.store.Get(redeemedTokenKeyPrefix + id); err == nil {
+			return nil, "", ErrPresentationTokenRedeemed
+		}
+		return nil, "", ErrPresentationTokenNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load presentation token: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashTokenSecret(secret)), []byte(stored.SecretHash)) != 1 {
+		return nil, "", ErrPresentationTokenNotFound
+	}
+
+	remaining := stored.ExpiresAt.Sub(uc.clock.Now())
+	if remaining <= 0 {
+		return nil, "", ErrPresentationTokenNotFound
+	}
+
+	// Only one server instance sharing the store hands the presentation out
+	claimed, err := uc.store.SetNX(redeemedTokenKeyPrefix+id, []byte(uc.clock.Now().Format(time.RFC3339)), remaining)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to redeem presentation token: %w", err)
+	}
+	if !claimed {
+		return nil, "", ErrPresentationTokenRedeemed
+	}
+
+	if err := uc.store.Delete(presentationTokenKeyPrefix + id); err != nil {
+		return nil, "", fmt.Errorf("failed to delete presentation token: %w", err)
+	}
+
+	return stored.Presentation, stored.Nonce, nil
+}
+
+// hashTokenSecret returns the form a token secret is stored in
+func hashTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
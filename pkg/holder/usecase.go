@@ -0,0 +1,501 @@
+package holder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/privacy"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// UseCase represents the holder use case
+type UseCase struct {
+	didService did.DIDService
+	vcService  vc.CredentialService
+	credRepo   vc.CredentialRepository
+	advisor    *privacy.Advisor
+	// clock dates the holder's records and the documents it signs, and judges their expiry
+	clock clock.Clock
+
+	// store holds the holder setups including their DID keys, the pairwise peer DIDs per verifier,
+	// the consent records for created presentations and the status notifications received; see
+	// SetStore
+	store storage.KVStore
+	// consentMu serializes this instance's updates to consent records
+	consentMu sync.Mutex
+
+	// snapshots maps credential ID -> latest status snapshots fetched from snapshotSource
+	snapshotSource StatusSnapshotSource
+	snapshotsMu    sync.RWMutex
+	snapshots      map[string][]*vc.StatusSnapshot
+
+	// subscriber receives the holder's subscriptions to status notifications
+	subscriber StatusSubscriber
+
+	// predicates proves predicates requested about committed claims
+	predicates *predicate.Registry
+
+	// addendumSource issues threshold addendum credentials
+	addendumSource AddendumSource
+
+	// versionSource returns the latest versions of amended credentials
+	versionSource VersionSource
+
+	// contexts is nil unless JSON-LD context checks are enabled
+	contexts *jsonld.Validator
+
+	// templates maps credential ID -> precomputed proof templates; see PrecomputeProofs
+	templatesMu sync.Mutex
+	templates   map[string][]*bbs.ProofTemplate
+
+	// events is nil unless the wallet is reached over an event bus; see SetEventBus
+	events *events.Bus
+}
+
+// NewUseCase creates a new holder use case
+func NewUseCase(didService did.DIDService, vcService vc.CredentialService, credRepo vc.CredentialRepository) *UseCase {
+	return &UseCase{
+		didService: didService,
+		vcService:  vcService,
+		credRepo:   credRepo,
+		advisor:    privacy.NewAdvisor(),
+		clock:      clock.System{},
+		store:      storage.NewMemoryStore(),
+		snapshots:  make(map[string][]*vc.StatusSnapshot),
+		templates:  make(map[string][]*bbs.ProofTemplate),
+	}
+}
+
+// HolderSetup represents the setup process for a holder
+type HolderSetup struct {
+	DID     *did.DID
+	DIDDoc  *did.DIDDocument
+	KeyPair *did.KeyPair
+}
+
+// PairwiseDID represents a holder DID dedicated to a single verifier relationship
+type PairwiseDID struct {
+	VerifierDID string
+	DID         *did.DID
+	KeyPair     *did.KeyPair
+}
+
+// SetupHolder sets up a new holder with DID.
+// Using the "peer" method creates a did:peer:0 DID suitable for pairwise use.
+func (uc *UseCase) SetupHolder(method string) (*HolderSetup, error) {
+	// Generate DID and key pair
+	var holderDID *did.DID
+	var keyPair *did.KeyPair
+	var err error
+	if method == did.MethodPeer {
+		holderDID, keyPair, err = uc.didService.GeneratePeerDID(did.PeerNumalgo0, "")
+	} else {
+		holderDID, keyPair, err = uc.didService.GenerateDID(method)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DID: %w", err)
+	}
+
+	// Create DID document
+	didDoc, err := uc.didService.CreateDIDDocument(holderDID, keyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DID document: %w", err)
+	}
+
+	setup := &HolderSetup{
+		DID:     holderDID,
+		DIDDoc:  didDoc,
+		KeyPair: keyPair,
+	}
+
+	if err := uc.saveHolder(setup); err != nil {
+		return nil, err
+	}
+
+	return setup, nil
+}
+
+// GetOrCreatePairwiseDID returns the holder's pairwise DID for a verifier, creating one on first contact
+func (uc *UseCase) GetOrCreatePairwiseDID(holderDID, verifierDID string) (*PairwiseDID, error) {
+	if holderDID == "" {
+		return nil, fmt.Errorf("holder DID is required")
+	}
+
+	if verifierDID == "" {
+		return nil, fmt.Errorf("verifier DID is required for pairwise DIDs")
+	}
+
+	existing, err := uc.loadPairwise(holderDID, verifierDID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load pairwise DID: %w", err)
+	}
+
+	pairwiseDID, keyPair, err := uc.didService.GeneratePeerDID(did.PeerNumalgo0, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pairwise DID: %w", err)
+	}
+
+	pairwise := &PairwiseDID{
+		VerifierDID: verifierDID,
+		DID:         pairwiseDID,
+		KeyPair:     keyPair,
+	}
+	stored, err := uc.claimPairwise(holderDID, pairwise)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		// Another request created the relationship's DID first
+		return uc.loadPairwise(holderDID, verifierDID)
+	}
+
+	return pairwise, nil
+}
+
+// ListPairwiseDIDs lists all pairwise DIDs created for a holder
+func (uc *UseCase) ListPairwiseDIDs(holderDID string) ([]*PairwiseDID, error) {
+	_, result, err := uc.storedPairwise(holderDID)
+	return result, err
+}
+
+// StoreCredential stores a received credential
+func (uc *UseCase) StoreCredential(credential *vc.VerifiableCredential) error {
+	if credential == nil {
+		return fmt.Errorf("credential is nil")
+	}
+
+	// Verify credential before storing
+	if err := uc.vcService.VerifyCredential(credential); err != nil {
+		return fmt.Errorf("credential verification failed: %w", err)
+	}
+
+	// A reissued credential has the ID of the copy it replaces, so the ID must match the content
+	if err := vc.CheckContentID(credential); err != nil {
+		return fmt.Errorf("credential verification failed: %w", err)
+	}
+
+	if uc.contexts != nil {
+		if err := uc.contexts.ValidateCredential(credential); err != nil {
+			return fmt.Errorf("credential context validation failed: %w", err)
+		}
+	}
+
+	// Store credential
+	if err := uc.credRepo.Store(credential); err != nil {
+		return fmt.Errorf("failed to store credential: %w", err)
+	}
+	uc.invalidateProofTemplates(credential.ID)
+
+	holderDID, _ := credential.CredentialSubject["id"].(string)
+	uc.publish(events.CredentialStored, events.CredentialStoredData{CredentialID: credential.ID, HolderDID: holderDID})
+
+	return nil
+}
+
+// DeleteCredential deletes a credential from the wallet. The repository keeps it with the
+// tombstone until its purge policy removes it, so audits can still tell it was held.
+func (uc *UseCase) DeleteCredential(credentialID string, tombstone storage.Tombstone) error {
+	if err := uc.credRepo.Delete(credentialID, tombstone); err != nil {
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+	uc.invalidateProofTemplates(credentialID)
+	return nil
+}
+
+// ReceiveEncryptedCredential decrypts a credential delivered to the holder's DID and stores it
+func (uc *UseCase) ReceiveEncryptedCredential(envelope *did.EncryptedEnvelope) (*vc.VerifiableCredential, error) {
+	payload, err := uc.didService.DecryptWithDID(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt credential: %w", err)
+	}
+
+	var credential vc.VerifiableCredential
+	if err := json.Unmarshal(payload, &credential); err != nil {
+		return nil, fmt.Errorf("failed to parse credential: %w", err)
+	}
+
+	if err := uc.StoreCredential(&credential); err != nil {
+		return nil, err
+	}
+
+	return &credential, nil
+}
+
+// EncryptPresentationForVerifier encrypts a presentation to the verifier's key agreement key
+func (uc *UseCase) EncryptPresentationForVerifier(presentation *vc.VerifiablePresentation, verifierDID string) (*did.EncryptedEnvelope, error) {
+	if presentation == nil {
+		return nil, fmt.Errorf("presentation is nil")
+	}
+
+	payload, err := json.Marshal(presentation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal presentation: %w", err)
+	}
+
+	envelope, err := uc.didService.EncryptForDID(verifierDID, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt presentation: %w", err)
+	}
+
+	return envelope, nil
+}
+
+// ListCredentials lists all credentials for a holder
+func (uc *UseCase) ListCredentials(holderDID string) ([]*vc.VerifiableCredential, error) {
+	credentials, err := uc.credRepo.List(holderDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	return credentials, nil
+}
+
+// PresentationRequest represents a presentation request
+type PresentationRequest struct {
+	HolderDID           string
+	CredentialIDs       []string
+	SelectiveDisclosure []vc.SelectiveDisclosureRequest
+	Nonce               string
+	// VerifierDID identifies the relying party the presentation is for
+	VerifierDID string
+	// UsePairwiseDID presents under a dedicated did:peer for VerifierDID instead of HolderDID
+	UsePairwiseDID bool
+	// ValidFor, when set, limits how long after creation the presentation is accepted
+	ValidFor time.Duration
+	// IncludeStatusSnapshots embeds issuer-signed status snapshots so the verifier can check
+	// revocation offline
+	IncludeStatusSnapshots bool
+	// Domain binds the presentation to the verifier's domain under the holder's signature
+	Domain string
+	// Format writes the derived credentials in another layout before the holder signs. Verifiers
+	// here cannot check Aries-layout presentations, which drop the attribute salts.
+	Format vc.ExportFormat
+	// RequestMetadata carries the verifier's stated purpose, retention period and identity.
+	// The purpose also drives disclosure analysis.
+	vc.RequestMetadata
+}
+
+// CreatePresentation creates a verifiable presentation with selective disclosure
+func (uc *UseCase) CreatePresentation(req PresentationRequest) (*vc.VerifiablePresentation, error) {
+	return uc.CreatePresentationContext(context.Background(), req)
+}
+
+// CreatePresentationContext creates a presentation, recording its derivation and signing as spans under ctx
+func (uc *UseCase) CreatePresentationContext(ctx context.Context, req PresentationRequest) (*vc.VerifiablePresentation, error) {
+	ctx, span := tracing.Start(ctx, "holder.CreatePresentation",
+		tracing.Int("credentials.count", len(req.CredentialIDs)),
+		tracing.Bool("pairwise", req.UsePairwiseDID),
+	)
+	defer span.End()
+
+	presentation, err := uc.createPresentation(ctx, req)
+	span.RecordError(err)
+	return presentation, err
+}
+
+func (uc *UseCase) createPresentation(ctx context.Context, req PresentationRequest) (*vc.VerifiablePresentation, error) {
+	if req.HolderDID == "" {
+		return nil, fmt.Errorf("holder DID is required")
+	}
+
+	if len(req.CredentialIDs) == 0 {
+		return nil, fmt.Errorf("at least one credential ID is required")
+	}
+
+	if len(req.CredentialIDs) != len(req.SelectiveDisclosure) {
+		return nil, fmt.Errorf("mismatch between credential IDs and selective disclosure requests")
+	}
+
+	// Retrieve credentials
+	var credentials []*vc.VerifiableCredential
+	for _, credID := range req.CredentialIDs {
+		credential, err := uc.credRepo.Retrieve(credID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve credential %s: %w", credID, err)
+		}
+
+		// Verify holder owns the credential
+		if subjectID, ok := credential.CredentialSubject["id"].(string); !ok || subjectID != req.HolderDID {
+			return nil, fmt.Errorf("credential %s does not belong to holder %s", credID, req.HolderDID)
+		}
+
+		credentials = append(credentials, credential)
+	}
+
+	// Honor the restrictions the issuers signed on revealing their claims
+	for i, sd := range req.SelectiveDisclosure {
+		if manifest := credentials[i].ClaimManifest; manifest != nil {
+			if err := manifest.CheckDisclosure(sd.RevealedAttributes); err != nil {
+				return nil, fmt.Errorf("credential %s: %w", credentials[i].ID, err)
+			}
+		}
+	}
+
+	// Set nonce for each selective disclosure request if provided, and the issuer key each
+	// credential's proof is derived with
+	disclosureRequests := make([]vc.SelectiveDisclosureRequest, len(req.SelectiveDisclosure))
+	for i, sd := range req.SelectiveDisclosure {
+		disclosureRequests[i] = sd
+		if req.Nonce != "" {
+			disclosureRequests[i].Nonce = req.Nonce
+		}
+
+		publicKey, err := uc.issuerPublicKey(credentials[i])
+		if err != nil {
+			return nil, fmt.Errorf("credential %s: %w", credentials[i].ID, err)
+		}
+		disclosureRequests[i].IssuerPublicKey = publicKey
+
+		// A hidden issuer's proof is made under one of its set's keys rather than a template
+		if sd.HideIssuer && credentials[i].IssuerSetProof != nil {
+			setKeys, err := uc.issuerSetKeys(credentials[i])
+			if err != nil {
+				return nil, fmt.Errorf("credential %s: %w", credentials[i].ID, err)
+			}
+			disclosureRequests[i].IssuerSetKeys = setKeys
+		} else if publicKey != nil {
+			disclosureRequests[i].ProofTemplate = uc.takeProofTemplate(credentials[i].ID)
+		}
+	}
+
+	// Choose the DID the presentation is made under
+	presenterDID := req.HolderDID
+	if req.UsePairwiseDID {
+		pairwise, err := uc.GetOrCreatePairwiseDID(req.HolderDID, req.VerifierDID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve pairwise DID: %w", err)
+		}
+		presenterDID = pairwise.DID.String()
+	}
+
+	if err := req.RequestMetadata.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request metadata: %w", err)
+	}
+
+	// Refuse to disclose to a verifier the holder's accreditation policy does not accept
+	if err := uc.checkVerifierAccreditation(req); err != nil {
+		return nil, err
+	}
+
+	if req.ValidFor < 0 {
+		return nil, fmt.Errorf("validity period cannot be negative")
+	}
+	if err := req.Format.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Bound credentials must be presented with a proof of possession of their key
+	bindingKey, err := bindingKeyID(credentials)
+	if err != nil {
+		return nil, err
+	}
+	if bindingKey != "" && presenterDID != req.HolderDID {
+		return nil, fmt.Errorf("credentials bound to %s cannot be presented under a pairwise DID", bindingKey)
+	}
+
+	// Create presentation
+	presentation, err := uc.vcService.CreatePresentationContext(ctx, presenterDID, credentials, disclosureRequests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create presentation: %w", err)
+	}
+	if bindingKey != "" {
+		presentation.Proof.VerificationMethod = bindingKey
+	}
+
+	// Prove predicates about hidden claims before signing so the holder's proof covers them
+	for i, request := range disclosureRequests {
+		if len(request.Predicates) == 0 {
+			continue
+		}
+		if err := uc.provePredicates(credentials[i], request, presentation.VerifiableCredential[i]); err != nil {
+			return nil, fmt.Errorf("credential %s: %w", request.CredentialID, err)
+		}
+	}
+
+	// Embed status snapshots before signing so the holder's proof covers them
+	if req.IncludeStatusSnapshots {
+		for _, credential := range credentials {
+			if len(credential.CredentialStatus) == 0 {
+				continue
+			}
+			snapshots, err := uc.statusSnapshots(credential)
+			if err != nil {
+				return nil, err
+			}
+			presentation.StatusSnapshots = append(presentation.StatusSnapshots, snapshots...)
+		}
+	}
+
+	// Bound the presentation's lifetime; the expiry is covered by the holder's signature
+	if req.ValidFor > 0 {
+		expires := presentation.Proof.Created.Add(req.ValidFor)
+		presentation.Proof.Expires = &expires
+	}
+
+	presentation.Proof.Domain = req.Domain
+
+	if req.Format == vc.ExportFormatAries {
+		if presentation, err = vc.AriesPresentation(presentation); err != nil {
+			return nil, fmt.Errorf("failed to export presentation: %w", err)
+		}
+	}
+
+	// Sign the presentation with the presenter's DID key to prove control of the DID
+	_, span := tracing.Start(ctx, "holder.SignPresentation")
+	err = uc.signPresentation(presentation)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign presentation: %w", err)
+	}
+
+	// Keep a record of what was shared, with whom and why
+	if _, err := uc.recordConsent(req, presentation); err != nil {
+		return nil, err
+	}
+
+	return presentation, nil
+}
+
+// signPresentation adds an Ed25519 holder proof over the presentation
+func (uc *UseCase) signPresentation(presentation *vc.VerifiablePresentation) error {
+	presentation.Proof.Type = "Ed25519Signature2020"
+
+	payload, err := vc.PresentationSigningInput(presentation)
+	if err != nil {
+		return err
+	}
+
+	signature, err := uc.didService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+	if err != nil {
+		return err
+	}
+
+	presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+	return nil
+}
+
+// GetCredential retrieves a specific credential
+func (uc *UseCase) GetCredential(credentialID string) (*vc.VerifiableCredential, error) {
+	credential, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential: %w", err)
+	}
+
+	return credential, nil
+}
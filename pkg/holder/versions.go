@@ -0,0 +1,59 @@
+package holder
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VersionSource returns the latest versions of the credentials its issuer amended
+type VersionSource interface {
+	LatestVersion(credentialID string) (*vc.VerifiableCredential, error)
+}
+
+// SetVersionSource sets where the holder fetches the latest versions of credentials from
+func (uc *UseCase) SetVersionSource(source VersionSource) {
+	uc.versionSource = source
+}
+
+// FetchLatestVersion asks the issuer of a held credential for its latest version and stores it
+// alongside the held one. It returns the held credential when it was not amended.
+func (uc *UseCase) FetchLatestVersion(holderDID, credentialID string) (*vc.VerifiableCredential, error) {
+	if uc.versionSource == nil {
+		return nil, fmt.Errorf("no version source configured")
+	}
+
+	held, err := uc.credRepo.Retrieve(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential %s: %w", credentialID, err)
+	}
+
+	if subjectID, ok := held.CredentialSubject["id"].(string); !ok || subjectID != holderDID {
+		return nil, fmt.Errorf("credential %s does not belong to holder %s", credentialID, holderDID)
+	}
+
+	latest, err := uc.versionSource.LatestVersion(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest version: %w", err)
+	}
+	if latest.ID == held.ID {
+		return held, nil
+	}
+
+	// Accept only a later version of this credential, for this holder, from the same issuer
+	if latest.Issuer() != held.Issuer() {
+		return nil, fmt.Errorf("latest version issued by %s, not %s", latest.Issuer(), held.Issuer())
+	}
+	if subjectID, _ := latest.CredentialSubject["id"].(string); subjectID != holderDID {
+		return nil, fmt.Errorf("latest version is not about holder %s", holderDID)
+	}
+	if latest.CredentialVersion() <= held.CredentialVersion() {
+		return nil, fmt.Errorf("version %d of %s is not later than the held version %d", latest.CredentialVersion(), latest.ID, held.CredentialVersion())
+	}
+
+	if err := uc.StoreCredential(latest); err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,53 @@
+package issuer
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// commitClaimKeys signs a sparse Merkle root over the salted claim keys of a newly issued
+// credential, handing the holder the salts alongside the credential
+func (uc *UseCase) commitClaimKeys(credential *vc.VerifiableCredential) error {
+	doc, err := uc.didService.ResolveDID(credential.Issuer())
+	if err != nil {
+		return fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	if len(doc.AssertionMethod) == 0 {
+		return fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	salts, err := vc.NewClaimKeySalts(credential.CredentialSubject)
+	if err != nil {
+		return err
+	}
+
+	commitment := &vc.ClaimKeyCommitment{
+		CredentialID: credential.ID,
+		Issuer:       credential.Issuer(),
+		Root:         vc.ClaimKeyTree(salts).Root(),
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            uc.clock.Now(),
+			VerificationMethod: doc.AssertionMethod[0],
+			ProofPurpose:       "assertionMethod",
+		},
+	}
+
+	payload, err := vc.ClaimKeyCommitmentSigningInput(commitment)
+	if err != nil {
+		return err
+	}
+
+	signature, err := uc.didService.SignWithDID(commitment.Proof.VerificationMethod, payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign claim key commitment: %w", err)
+	}
+	commitment.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	credential.ClaimKeys = commitment
+	credential.ClaimKeySalts = salts
+	return nil
+}
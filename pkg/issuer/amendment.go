@@ -0,0 +1,277 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ErrCredentialAmended is returned when amending a credential that is no longer its latest version
+var ErrCredentialAmended = errors.New("credential already amended")
+
+// AmendCredentialRequest asks for the next version of an issued credential
+type AmendCredentialRequest struct {
+	CredentialID string `json:"credentialId"`
+	// Claims replace the claims of the previous version with the same keys; the others are added
+	Claims []vc.Claim `json:"claims,omitempty"`
+	// Remove lists claims of the previous version the new one leaves out
+	Remove []string `json:"remove,omitempty"`
+}
+
+// AmendCredential issues the next version of a credential, such as one with a changed address or
+// a corrected name, and revokes the previous version as superseded, which notifies its holder.
+// The new version carries the previous one's claims, types, expiry and holder binding with the
+// requested changes. It names the previous version in its previousVersion claim and numbers
+// itself in credentialVersion, both signed with the other claims.
+//
+// Attribute commitments are made afresh for the committed claims the new version keeps, so the
+// holder can prove a committed claim, such as the subject identifier, unchanged across versions.
+//
+// Only the latest version of a credential with status entries can be amended. Evidence,
+// co-signatures, claim key commitments and issuer sets were made for the original request, so
+// credentials carrying them cannot be.
+func (uc *UseCase) AmendCredential(ctx context.Context, req AmendCredentialRequest) (*vc.VerifiableCredential, error) {
+	ctx, span := tracing.Start(ctx, "issuer.AmendCredential",
+		tracing.String("credential.id", req.CredentialID),
+		tracing.Int("claims.count", len(req.Claims)),
+	)
+	defer span.End()
+
+	credential, err := uc.amendCredential(ctx, req)
+	span.RecordError(err)
+	return credential, err
+}
+
+func (uc *UseCase) amendCredential(ctx context.Context, req AmendCredentialRequest) (*vc.VerifiableCredential, error) {
+	if req.CredentialID == "" {
+		return nil, fmt.Errorf("credential ID is required")
+	}
+	if len(req.Claims) == 0 && len(req.Remove) == 0 {
+		return nil, fmt.Errorf("at least one claim must be changed or removed")
+	}
+
+	// Amendments are issued at once, so they would bypass review
+	if uc.ApprovalRequired() {
+		return nil, fmt.Errorf("credentials cannot be amended while issuance requires approval")
+	}
+
+	previous, err := uc.loadIssued(req.CredentialID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("issued credential not found: %s", req.CredentialID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issued credential: %w", err)
+	}
+
+	next, err := uc.nextVersion(previous.ID)
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s was amended by %s", ErrCredentialAmended, previous.ID, next)
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := checkAmendable(previous); err != nil {
+		return nil, err
+	}
+
+	// The previous version must be revocable, and not revoked already
+	current, err := uc.GetCredentialStatus(previous.ID)
+	if err != nil {
+		return nil, fmt.Errorf("credential %s cannot be revoked when amended: %w", previous.ID, err)
+	}
+	if current.Revoked {
+		return nil, fmt.Errorf("credential %s is revoked", previous.ID)
+	}
+
+	claims, err := amendedClaims(previous, req)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectDID, ok := previous.CredentialSubject["id"].(string)
+	if !ok || subjectDID == "" {
+		return nil, fmt.Errorf("credential has no subject DID")
+	}
+	version, err := previous.Version()
+	if err != nil {
+		return nil, err
+	}
+	validUntil := previous.ValidUntil
+	if validUntil == nil {
+		validUntil = previous.ExpirationDate
+	}
+	_, bound := previous.CredentialSubject[vc.ConfirmationClaim]
+
+	return uc.issueCredential(ctx, IssueCredentialRequest{
+		IssuerDID:        previous.Issuer(),
+		SubjectDID:       subjectDID,
+		Claims:           claims,
+		CommitAttributes: keptCommitments(previous, claims),
+		Version:          version,
+		ValidUntil:       validUntil,
+		IssuerName:       previous.IssuerInfo.Name,
+		IssuerImage:      previous.IssuerInfo.Image,
+		BindToHolder:     bound,
+	}, nil, previous)
+}
+
+// checkAmendable rejects credentials carrying parts that were made for their original request
+func checkAmendable(credential *vc.VerifiableCredential) error {
+	var part string
+	switch {
+	case credential.Evidence != nil:
+		part = "evidence"
+	case credential.CoSignatures != nil:
+		part = "co-signatures"
+	case credential.ClaimKeys != nil:
+		part = "a claim key commitment"
+	case credential.IssuerSetProof != nil:
+		part = "an issuer set"
+	case credential.ClaimManifest == nil:
+		return fmt.Errorf("credential %s has no claim manifest", credential.ID)
+	default:
+		return nil
+	}
+	return fmt.Errorf("credential %s carries %s, which cannot be carried over to a new version", credential.ID, part)
+}
+
+// amendedClaims returns the claims of the previous version, in signing order, with the request's
+// changes applied. Unchanged claims keep their values and disclosure restrictions; their types are
+// inferred from the values, which yields their original types as ClaimMessages relies on.
+func amendedClaims(previous *vc.VerifiableCredential, req AmendCredentialRequest) ([]vc.Claim, error) {
+	changes := make(map[string]vc.Claim, len(req.Claims))
+	for _, claim := range req.Claims {
+		if claim.Key == "id" {
+			return nil, fmt.Errorf("the subject of a credential cannot be amended")
+		}
+		changes[claim.Key] = claim
+	}
+	removed := make(map[string]bool, len(req.Remove))
+	for _, key := range req.Remove {
+		if _, ok := previous.CredentialSubject[key]; !ok {
+			return nil, fmt.Errorf("credential %s has no claim %s", previous.ID, key)
+		}
+		removed[key] = true
+	}
+
+	var claims []vc.Claim
+	for _, entry := range previous.ClaimManifest.Claims {
+		key := entry.Key
+		switch key {
+		case "id", vc.ConfirmationClaim, vc.PreviousVersionClaim, vc.CredentialVersionClaim:
+			continue
+		}
+
+		// A generalization would no longer coarsen the claim it was derived from
+		if claim, _, ok := vc.SplitGeneralizedClaim(key); ok {
+			if _, changed := changes[claim]; changed || removed[claim] {
+				return nil, fmt.Errorf("claim %s is generalized as %s, so changing it needs a new credential", claim, key)
+			}
+		}
+
+		if removed[key] {
+			continue
+		}
+		claim, changed := changes[key]
+		if !changed {
+			claim = vc.Claim{Key: key, Value: previous.CredentialSubject[key]}
+		}
+		if claim.Disclosure == "" {
+			claim.Disclosure = entry.Disclosure
+		}
+		delete(changes, key)
+		claims = append(claims, claim)
+	}
+
+	// Claims the previous version did not have are added in request order
+	for _, claim := range req.Claims {
+		if _, added := changes[claim.Key]; added {
+			claims = append(claims, claim)
+			delete(changes, claim.Key)
+		}
+	}
+
+	if len(claims) == 0 {
+		return nil, fmt.Errorf("a new version needs at least one claim")
+	}
+	return claims, nil
+}
+
+// keptCommitments lists the committed claims of the previous version the new version keeps.
+// The subject identifier is always kept.
+func keptCommitments(previous *vc.VerifiableCredential, claims []vc.Claim) []string {
+	if previous.Commitments == nil {
+		return nil
+	}
+	var kept []string
+	for _, commitment := range previous.Commitments.Commitments {
+		if _, ok := findClaim(claims, commitment.Claim); ok || commitment.Claim == "id" {
+			kept = append(kept, commitment.Claim)
+		}
+	}
+	return kept
+}
+
+// versionClaims are the claims linking the next version of a credential to it
+func versionClaims(previous *vc.VerifiableCredential) []vc.Claim {
+	return []vc.Claim{
+		{Key: vc.PreviousVersionClaim, Value: previous.ID, Type: schema.ClaimTypeString},
+		{Key: vc.CredentialVersionClaim, Value: previous.CredentialVersion() + 1, Type: schema.ClaimTypeInteger},
+	}
+}
+
+// linkVersion records a credential's next version, failing with ErrCredentialAmended when another
+// version was linked first
+func (uc *UseCase) linkVersion(previousID, nextID string) error {
+	linked, err := uc.store.SetNX(amendedKeyPrefix+previousID, []byte(nextID), 0)
+	if err != nil {
+		return fmt.Errorf("failed to record credential version: %w", err)
+	}
+	if !linked {
+		return fmt.Errorf("%w: %s", ErrCredentialAmended, previousID)
+	}
+	return nil
+}
+
+// nextVersion returns the ID of the version amending a credential, or storage.ErrNotFound
+func (uc *UseCase) nextVersion(credentialID string) (string, error) {
+	next, err := uc.store.Get(amendedKeyPrefix + credentialID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to load credential version: %w", err)
+	}
+	return string(next), nil
+}
+
+// LatestVersion returns the latest version of an issued credential: the credential itself unless
+// it was amended
+func (uc *UseCase) LatestVersion(credentialID string) (*vc.VerifiableCredential, error) {
+	latest := credentialID
+	for {
+		next, err := uc.nextVersion(latest)
+		if errors.Is(err, storage.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		latest = next
+	}
+
+	credential, err := uc.loadIssued(latest)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("issued credential not found: %s", latest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issued credential: %w", err)
+	}
+	return credential, nil
+}
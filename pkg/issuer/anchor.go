@@ -0,0 +1,165 @@
+package issuer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/anchor"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+)
+
+// SetAnchorService enables anchoring of issuer key sets and status lists to an external backend
+func (uc *UseCase) SetAnchorService(service *anchor.Service) {
+	uc.anchors = service
+}
+
+// AnchorIssuer anchors the issuer's current key set and each of its status lists.
+// Digests unchanged since their latest anchor are not published again.
+func (uc *UseCase) AnchorIssuer(issuerDID string) ([]*anchor.Anchor, error) {
+	if uc.anchors == nil {
+		return nil, fmt.Errorf("anchoring is not enabled")
+	}
+
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	keySet, err := uc.anchorKeySet(setup.DID.String())
+	if err != nil {
+		return nil, err
+	}
+	anchors := []*anchor.Anchor{keySet}
+
+	listIDs, err := uc.statusListIDs(setup.DID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, listID := range listIDs {
+		listAnchor, err := uc.anchorStatusList(listID)
+		if err != nil {
+			return nil, err
+		}
+		anchors = append(anchors, listAnchor)
+	}
+
+	return anchors, nil
+}
+
+// GetAnchors returns every anchor made for an issuer's key set and status lists, oldest first
+func (uc *UseCase) GetAnchors(issuerDID string) ([]*anchor.Anchor, error) {
+	if uc.anchors == nil {
+		return nil, fmt.Errorf("anchoring is not enabled")
+	}
+
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	listIDs, err := uc.statusListIDs(setup.DID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := uc.anchors.History(setup.DID.String(), anchor.KindKeySet)
+	for _, listID := range listIDs {
+		anchors = append(anchors, uc.anchors.History(listID, anchor.KindStatusList)...)
+	}
+
+	return anchors, nil
+}
+
+// RunAnchoring anchors every issuer at the given interval until the context is cancelled
+func (uc *UseCase) RunAnchoring(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			issuerDIDs, err := uc.storedIssuerDIDs()
+			if err != nil {
+				log.Printf("failed to list issuers to anchor: %v", err)
+				continue
+			}
+			for _, issuerDID := range issuerDIDs {
+				if _, err := uc.AnchorIssuer(issuerDID); err != nil {
+					log.Printf("failed to anchor issuer %s: %v", issuerDID, err)
+				}
+			}
+		}
+	}
+}
+
+func (uc *UseCase) anchorKeySet(issuerDID string) (*anchor.Anchor, error) {
+	doc, err := uc.didService.ResolveDID(issuerDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	digest, err := anchor.KeySetDigest(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.anchors.Anchor(issuerDID, anchor.KindKeySet, digest)
+}
+
+func (uc *UseCase) anchorStatusList(listID string) (*anchor.Anchor, error) {
+	list, err := uc.GetStatusList(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := anchor.StatusListDigest(list)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.anchors.Anchor(listID, anchor.KindStatusList, digest)
+}
+
+// anchorStatusLists anchors the lists the entries point at when anchoring is enabled
+func (uc *UseCase) anchorStatusLists(entries []status.Entry) error {
+	if uc.anchors == nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		if _, err := uc.anchorStatusList(entry.StatusListCredential); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusListIDs returns the IDs of the status lists the issuer has allocated entries from
+func (uc *UseCase) statusListIDs(issuerDID string) ([]string, error) {
+	statuses, err := uc.storedStatuses()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var listIDs []string
+	for _, issued := range statuses {
+		if issued.IssuerDID != issuerDID {
+			continue
+		}
+		for _, entry := range issued.Entries {
+			if !seen[entry.StatusListCredential] {
+				seen[entry.StatusListCredential] = true
+				listIDs = append(listIDs, entry.StatusListCredential)
+			}
+		}
+	}
+
+	sort.Strings(listIDs)
+	return listIDs, nil
+}
@@ -0,0 +1,305 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Store key prefixes for issuance approvals
+const (
+	approvalKeyPrefix = "approval:"
+	// decisionKeyPrefix marks an approval as decided, so only one approver's decision counts
+	decisionKeyPrefix = "approval-decision:"
+)
+
+// ApprovalStatus is the state of an issuance request in the approval queue
+type ApprovalStatus string
+
+const (
+	// ApprovalPending requests wait for an approver
+	ApprovalPending ApprovalStatus = "pending"
+	// ApprovalApproved requests are being signed
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+	// ApprovalIssued requests were approved and their credential signed
+	ApprovalIssued ApprovalStatus = "issued"
+	// ApprovalFailed requests were approved but could not be signed
+	ApprovalFailed ApprovalStatus = "failed"
+)
+
+// ApprovalComment is a remark left on an issuance request by a reviewer
+type ApprovalComment struct {
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// IssuanceApproval is an issuance request held until an approver decides on it
+type IssuanceApproval struct {
+	ID          string                 `json:"id"`
+	Request     IssueCredentialRequest `json:"request"`
+	RequestedBy string                 `json:"requestedBy,omitempty"`
+	Status      ApprovalStatus         `json:"status"`
+	Comments    []ApprovalComment      `json:"comments,omitempty"`
+	// DecidedBy is the approver who approved or rejected the request
+	DecidedBy string     `json:"decidedBy,omitempty"`
+	DecidedAt *time.Time `json:"decidedAt,omitempty"`
+	// Credential is set once an approved request has been signed
+	Credential *vc.VerifiableCredential `json:"credential,omitempty"`
+	// Error explains why an approved request could not be signed
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SetApprovalRequired turns the approval gate on or off. While it is on, issuance requests made
+// through the API wait in the approval queue and batch issuance is refused.
+func (uc *UseCase) SetApprovalRequired(required bool) {
+	uc.approvalsMu.Lock()
+	uc.approvalRequired = required
+	uc.approvalsMu.Unlock()
+}
+
+// ApprovalRequired reports whether issuance requests must be approved before they are signed
+func (uc *UseCase) ApprovalRequired() bool {
+	uc.approvalsMu.Lock()
+	defer uc.approvalsMu.Unlock()
+	return uc.approvalRequired
+}
+
+// OnApprovalChange registers a handler called whenever an issuance request is submitted, commented
+// on, decided or signed. The handler receives the request in its new state.
+func (uc *UseCase) OnApprovalChange(handler func(IssuanceApproval)) {
+	uc.approvalsMu.Lock()
+	defer uc.approvalsMu.Unlock()
+
+	uc.approvalHandlers = append(uc.approvalHandlers, handler)
+}
+
+// SubmitIssuance checks an issuance request and places it in the approval queue
+func (uc *UseCase) SubmitIssuance(req IssueCredentialRequest, requestedBy string) (*IssuanceApproval, error) {
+	if _, err := validateIssueRequest(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.getIssuer(req.IssuerDID); err != nil {
+		return nil, err
+	}
+
+	if req.TemplateID != "" {
+		template, err := uc.templates.Get(req.TemplateID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get template: %w", err)
+		}
+		keys := make([]string, len(req.Claims))
+		for i, claim := range req.Claims {
+			keys[i] = claim.Key
+		}
+		if err := validateTemplateClaims(template, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	now := uc.clock.Now()
+	approval := &IssuanceApproval{
+		ID:          uuid.New().String(),
+		Request:     req,
+		RequestedBy: requestedBy,
+		Status:      ApprovalPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := uc.saveApproval(approval); err != nil {
+		return nil, err
+	}
+
+	uc.notifyApproval(approval)
+	return approval, nil
+}
+
+// GetApproval returns an issuance request in the approval queue
+func (uc *UseCase) GetApproval(id string) (*IssuanceApproval, error) {
+	var approval IssuanceApproval
+	if err := storage.GetJSON(uc.store, approvalKeyPrefix+id, &approval); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("approval not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to load approval: %w", err)
+	}
+	return &approval, nil
+}
+
+// ListApprovals returns the issuance requests in the given state, or all of them when status is
+// empty, oldest first
+func (uc *UseCase) ListApprovals(status ApprovalStatus) ([]*IssuanceApproval, error) {
+	keys, err := uc.store.Keys(approvalKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+
+	approvals := make([]*IssuanceApproval, 0, len(keys))
+	for _, key := range keys {
+		approval, err := uc.GetApproval(strings.TrimPrefix(key, approvalKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+		if status == "" || approval.Status == status {
+			approvals = append(approvals, approval)
+		}
+	}
+
+	sort.SliceStable(approvals, func(i, j int) bool {
+		return approvals[i].CreatedAt.Before(approvals[j].CreatedAt)
+	})
+	return approvals, nil
+}
+
+// CommentOnApproval adds a reviewer's comment to a pending issuance request
+func (uc *UseCase) CommentOnApproval(id, author, text string) (*IssuanceApproval, error) {
+	if author == "" {
+		return nil, fmt.Errorf("comment author is required")
+	}
+	if text == "" {
+		return nil, fmt.Errorf("comment text is required")
+	}
+
+	approval, err := uc.updateApproval(id, func(approval *IssuanceApproval) error {
+		if approval.Status != ApprovalPending {
+			return fmt.Errorf("approval %s is %s, not pending", id, approval.Status)
+		}
+		approval.Comments = append(approval.Comments, ApprovalComment{Author: author, Text: text, CreatedAt: uc.clock.Now()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyApproval(approval)
+	return approval, nil
+}
+
+// ApproveIssuance approves a pending issuance request and signs its credential. A request that
+// fails to sign is marked failed with the reason; it is not returned to the queue.
+func (uc *UseCase) ApproveIssuance(ctx context.Context, id, approver, comment string) (*IssuanceApproval, error) {
+	approval, err := uc.decideApproval(id, approver, comment, ApprovalApproved)
+	if err != nil {
+		return nil, err
+	}
+	uc.notifyApproval(approval)
+
+	credential, issueErr := uc.IssueCredentialContext(ctx, approval.Request)
+
+	approval, err = uc.updateApproval(id, func(approval *IssuanceApproval) error {
+		if issueErr != nil {
+			approval.Status = ApprovalFailed
+			approval.Error = issueErr.Error()
+			return nil
+		}
+		approval.Status = ApprovalIssued
+		approval.Credential = credential
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyApproval(approval)
+	return approval, nil
+}
+
+// RejectIssuance rejects a pending issuance request. A comment explaining why is required.
+func (uc *UseCase) RejectIssuance(id, approver, comment string) (*IssuanceApproval, error) {
+	if comment == "" {
+		return nil, fmt.Errorf("a comment is required to reject an issuance request")
+	}
+
+	approval, err := uc.decideApproval(id, approver, comment, ApprovalRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.notifyApproval(approval)
+	return approval, nil
+}
+
+// decideApproval moves a pending request to the decided state. The decision is claimed in the
+// store first, so two approvers, even on different instances, cannot both decide the same request.
+func (uc *UseCase) decideApproval(id, approver, comment string, decision ApprovalStatus) (*IssuanceApproval, error) {
+	if approver == "" {
+		return nil, fmt.Errorf("approver is required")
+	}
+
+	current, err := uc.GetApproval(id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != ApprovalPending {
+		return nil, fmt.Errorf("approval %s is %s, not pending", id, current.Status)
+	}
+
+	claimed, err := uc.store.SetNX(decisionKeyPrefix+id, []byte(decision), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record decision: %w", err)
+	}
+	if !claimed {
+		return nil, fmt.Errorf("approval %s has already been decided", id)
+	}
+
+	return uc.updateApproval(id, func(approval *IssuanceApproval) error {
+		now := uc.clock.Now()
+		approval.Status = decision
+		approval.DecidedBy = approver
+		approval.DecidedAt = &now
+		if comment != "" {
+			approval.Comments = append(approval.Comments, ApprovalComment{Author: approver, Text: comment, CreatedAt: now})
+		}
+		return nil
+	})
+}
+
+// updateApproval loads an approval, applies update and stores the result
+func (uc *UseCase) updateApproval(id string, update func(*IssuanceApproval) error) (*IssuanceApproval, error) {
+	uc.approvalsMu.Lock()
+	defer uc.approvalsMu.Unlock()
+
+	approval, err := uc.GetApproval(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(approval); err != nil {
+		return nil, err
+	}
+
+	approval.UpdatedAt = uc.clock.Now()
+	if err := uc.saveApproval(approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+func (uc *UseCase) saveApproval(approval *IssuanceApproval) error {
+	if err := storage.SetJSON(uc.store, approvalKeyPrefix+approval.ID, approval, 0); err != nil {
+		return fmt.Errorf("failed to store approval: %w", err)
+	}
+	return nil
+}
+
+// notifyApproval calls the approval handlers with a copy of the approval
+func (uc *UseCase) notifyApproval(approval *IssuanceApproval) {
+	uc.approvalsMu.Lock()
+	handlers := append([]func(IssuanceApproval){}, uc.approvalHandlers...)
+	uc.approvalsMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(*approval)
+	}
+}
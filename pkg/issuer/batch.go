@@ -0,0 +1,233 @@
+package issuer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+const (
+	// MaxBatchSize is the largest number of credentials a single batch job may issue
+	MaxBatchSize = 1000
+	// DefaultBatchWorkers is how many credentials are signed concurrently across all batch jobs
+	DefaultBatchWorkers = 4
+)
+
+// BatchStatus represents the state of a batch job or one of its items
+type BatchStatus string
+
+const (
+	BatchPending   BatchStatus = "pending"
+	BatchRunning   BatchStatus = "running"
+	BatchCompleted BatchStatus = "completed"
+	BatchFailed    BatchStatus = "failed"
+)
+
+// BatchItem is one credential to issue in a batch
+type BatchItem struct {
+	SubjectDID string
+	Claims     []vc.Claim
+	// TemplateID overrides the batch template for this item
+	TemplateID string
+}
+
+// BatchIssueRequest represents a request to issue credentials to many subjects in one job
+type BatchIssueRequest struct {
+	IssuerDID  string
+	TemplateID string
+	Items      []BatchItem
+}
+
+// BatchItemResult is the outcome of issuing one item of a batch
+type BatchItemResult struct {
+	Index        int                      `json:"index"`
+	SubjectDID   string                   `json:"subjectDid"`
+	Status       BatchStatus              `json:"status"`
+	CredentialID string                   `json:"credentialId,omitempty"`
+	Credential   *vc.VerifiableCredential `json:"credential,omitempty"`
+	Error        string                   `json:"error,omitempty"`
+}
+
+// BatchJob tracks the progress of a batch issuance. Failed items do not fail the job;
+// a job is completed once every item has been attempted.
+type BatchJob struct {
+	ID         string            `json:"id"`
+	IssuerDID  string            `json:"issuerDid"`
+	Status     BatchStatus       `json:"status"`
+	Total      int               `json:"total"`
+	Processed  int               `json:"processed"`
+	Succeeded  int               `json:"succeeded"`
+	Failed     int               `json:"failed"`
+	Items      []BatchItemResult `json:"items"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	StartedAt  *time.Time        `json:"startedAt,omitempty"`
+	FinishedAt *time.Time        `json:"finishedAt,omitempty"`
+}
+
+// Done reports whether every item of the job has been attempted
+func (j *BatchJob) Done() bool {
+	return j.Status == BatchCompleted
+}
+
+// SetBatchWorkers sets how many credentials batch jobs sign concurrently. Jobs already
+// running keep the limit they started with.
+func (uc *UseCase) SetBatchWorkers(workers int) error {
+	if workers < 1 {
+		return fmt.Errorf("batch workers must be at least 1")
+	}
+
+	uc.batchMu.Lock()
+	defer uc.batchMu.Unlock()
+
+	uc.batchSlots = make(chan struct{}, workers)
+	return nil
+}
+
+// StartBatchIssuance validates a batch and issues its credentials in the background.
+// The returned job ID can be polled with GetBatchJob for progress and per-item results.
+func (uc *UseCase) StartBatchIssuance(req BatchIssueRequest) (*BatchJob, error) {
+	if req.IssuerDID == "" {
+		return nil, fmt.Errorf("issuer DID is required")
+	}
+
+	if uc.ApprovalRequired() {
+		return nil, fmt.Errorf("batch issuance is unavailable while issuance requires approval")
+	}
+
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("at least one item is required")
+	}
+
+	if len(req.Items) > MaxBatchSize {
+		return nil, fmt.Errorf("batch has %d items, the maximum is %d", len(req.Items), MaxBatchSize)
+	}
+
+	if _, err := uc.getIssuer(req.IssuerDID); err != nil {
+		return nil, err
+	}
+
+	if req.TemplateID != "" {
+		if _, err := uc.templates.Get(req.TemplateID); err != nil {
+			return nil, fmt.Errorf("failed to get template: %w", err)
+		}
+	}
+
+	job := &BatchJob{
+		ID:        uuid.New().String(),
+		IssuerDID: req.IssuerDID,
+		Status:    BatchPending,
+		Total:     len(req.Items),
+		Items:     make([]BatchItemResult, len(req.Items)),
+		CreatedAt: uc.clock.Now(),
+	}
+	for i, item := range req.Items {
+		job.Items[i] = BatchItemResult{
+			Index:      i,
+			SubjectDID: item.SubjectDID,
+			Status:     BatchPending,
+		}
+	}
+
+	uc.batchMu.Lock()
+	uc.batchJobs[job.ID] = job
+	slots := uc.batchSlots
+	result := snapshotBatch(job)
+	uc.batchMu.Unlock()
+
+	go uc.runBatch(job.ID, req, slots)
+
+	return result, nil
+}
+
+// GetBatchJob returns a snapshot of a batch job's progress and item results
+func (uc *UseCase) GetBatchJob(id string) (*BatchJob, error) {
+	uc.batchMu.RLock()
+	defer uc.batchMu.RUnlock()
+
+	job, ok := uc.batchJobs[id]
+	if !ok {
+		return nil, fmt.Errorf("batch job not found: %s", id)
+	}
+
+	return snapshotBatch(job), nil
+}
+
+// runBatch issues every item, holding one of the shared slots per credential being signed
+func (uc *UseCase) runBatch(id string, req BatchIssueRequest, slots chan struct{}) {
+	uc.updateBatch(id, func(job *BatchJob) {
+		now := uc.clock.Now()
+		job.Status = BatchRunning
+		job.StartedAt = &now
+	})
+
+	var wg sync.WaitGroup
+	for i, item := range req.Items {
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			uc.updateBatch(id, func(job *BatchJob) {
+				job.Items[i].Status = BatchRunning
+			})
+
+			templateID := item.TemplateID
+			if templateID == "" {
+				templateID = req.TemplateID
+			}
+
+			credential, err := uc.IssueCredentialContext(context.Background(), IssueCredentialRequest{
+				IssuerDID:  req.IssuerDID,
+				SubjectDID: item.SubjectDID,
+				Claims:     item.Claims,
+				TemplateID: templateID,
+			})
+
+			uc.updateBatch(id, func(job *BatchJob) {
+				result := &job.Items[i]
+				job.Processed++
+				if err != nil {
+					result.Status = BatchFailed
+					result.Error = err.Error()
+					job.Failed++
+					return
+				}
+				result.Status = BatchCompleted
+				result.CredentialID = credential.ID
+				result.Credential = credential
+				job.Succeeded++
+			})
+		}()
+	}
+	wg.Wait()
+
+	uc.updateBatch(id, func(job *BatchJob) {
+		now := uc.clock.Now()
+		job.Status = BatchCompleted
+		job.FinishedAt = &now
+	})
+}
+
+func (uc *UseCase) updateBatch(id string, change func(job *BatchJob)) {
+	uc.batchMu.Lock()
+	defer uc.batchMu.Unlock()
+
+	if job, ok := uc.batchJobs[id]; ok {
+		change(job)
+	}
+}
+
+// snapshotBatch copies a job so callers can read it without holding the lock
+func snapshotBatch(job *BatchJob) *BatchJob {
+	copied := *job
+	copied.Items = make([]BatchItemResult, len(job.Items))
+	copy(copied.Items, job.Items)
+	return &copied
+}
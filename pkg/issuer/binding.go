@@ -0,0 +1,33 @@
+package issuer
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// holderConfirmation builds the confirmation claim binding a credential to the subject's current
+// authentication key, which presentations of the credential must then be signed with
+func (uc *UseCase) holderConfirmation(subjectDID string) (vc.Claim, error) {
+	doc, err := uc.didService.ResolveDID(subjectDID)
+	if err != nil {
+		return vc.Claim{}, fmt.Errorf("failed to resolve subject DID: %w", err)
+	}
+
+	for _, keyID := range doc.Authentication {
+		method, ok := doc.FindVerificationMethod(keyID)
+		if !ok || method.ValidUntil != nil || method.PublicKeyMultibase == "" {
+			continue
+		}
+
+		return vc.Claim{
+			Key: vc.ConfirmationClaim,
+			Value: vc.Confirmation{
+				KeyID:              method.ID,
+				PublicKeyMultibase: method.PublicKeyMultibase,
+			},
+		}, nil
+	}
+
+	return vc.Claim{}, fmt.Errorf("subject DID %s has no current authentication key to bind to", subjectDID)
+}
@@ -0,0 +1,106 @@
+package issuer
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// commitAttributes commits to the given claims of a newly issued credential and signs the
+// commitments, handing the holder the openings alongside the credential
+func (uc *UseCase) commitAttributes(credential *vc.VerifiableCredential, claims []vc.Claim, attributes []string) error {
+	doc, err := uc.didService.ResolveDID(credential.Issuer())
+	if err != nil {
+		return fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	if len(doc.AssertionMethod) == 0 {
+		return fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	bundle := &vc.CommitmentBundle{
+		CredentialID: credential.ID,
+		Issuer:       credential.Issuer(),
+		Generators:   bbs.DefaultCommitmentGenerators(),
+	}
+	var openings []vc.CommitmentOpening
+
+	for _, attribute := range attributes {
+		if _, exists := bundle.Find(attribute); exists {
+			return fmt.Errorf("duplicate commitment to claim %s", attribute)
+		}
+
+		claim, ok := findClaim(claims, attribute)
+		if !ok && attribute == "id" {
+			// The subject identifier is signed along with the claims, so it can be committed to,
+			// e.g. to prove versions of a credential were issued to one holder
+			claim, ok = vc.Claim{Key: "id", Value: credential.CredentialSubject["id"], Type: schema.ClaimTypeString}, true
+		}
+		if !ok {
+			return fmt.Errorf("cannot commit to claim %s, which is not issued", attribute)
+		}
+
+		// Commit to the value the way it was signed
+		normalized, err := vc.NormalizeClaim(claim)
+		if err != nil {
+			return fmt.Errorf("invalid claim: %w", err)
+		}
+
+		value, encoding, err := vc.CommitmentScalar(normalized.Type, normalized.Value)
+		if err != nil {
+			return fmt.Errorf("claim %s: %w", attribute, err)
+		}
+
+		commitment, blinding, err := bbs.Commit(value)
+		if err != nil {
+			return fmt.Errorf("failed to commit to claim %s: %w", attribute, err)
+		}
+
+		bundle.Commitments = append(bundle.Commitments, vc.AttributeCommitment{
+			Claim:      attribute,
+			Encoding:   encoding,
+			Commitment: commitment,
+		})
+		openings = append(openings, vc.CommitmentOpening{
+			Claim:    attribute,
+			Encoding: encoding,
+			Value:    value,
+			Blinding: blinding,
+		})
+	}
+
+	bundle.Proof = &vc.Proof{
+		Type:               "Ed25519Signature2020",
+		Created:            uc.clock.Now(),
+		VerificationMethod: doc.AssertionMethod[0],
+		ProofPurpose:       "assertionMethod",
+	}
+
+	payload, err := vc.CommitmentBundleSigningInput(bundle)
+	if err != nil {
+		return err
+	}
+
+	signature, err := uc.didService.SignWithDID(bundle.Proof.VerificationMethod, payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign commitments: %w", err)
+	}
+	bundle.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	credential.Commitments = bundle
+	credential.CommitmentOpenings = openings
+	return nil
+}
+
+// findClaim returns the claim with the given key
+func findClaim(claims []vc.Claim, key string) (vc.Claim, bool) {
+	for _, claim := range claims {
+		if claim.Key == key {
+			return claim, true
+		}
+	}
+	return vc.Claim{}, false
+}
@@ -0,0 +1,399 @@
+package issuer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Store key prefixes for co-signing sessions
+const (
+	signingSessionKeyPrefix = "signing-session:"
+	// signingIssueKeyPrefix marks a session as issuing, so only one completing signature issues it
+	signingIssueKeyPrefix = "signing-session-issue:"
+)
+
+// DefaultSigningSessionTTL is how long a co-signing session collects signatures unless told otherwise
+const DefaultSigningSessionTTL = 24 * time.Hour
+
+// ErrCoSigningRequired is returned for issuance requests a co-signing policy covers when they are
+// not made through a signing session
+var ErrCoSigningRequired = errors.New("issuance requires co-signatures")
+
+// CoSigningPolicy requires credentials an issuer signs, or those from one template, to be approved
+// by a threshold of key holders before they are issued
+type CoSigningPolicy struct {
+	IssuerDID string `json:"issuerDid"`
+	// TemplateID limits the policy to credentials from one template; empty covers all the
+	// issuer's credentials
+	TemplateID string   `json:"templateId,omitempty"`
+	Threshold  int      `json:"threshold"`
+	Signers    []string `json:"signers"`
+}
+
+// SigningSessionStatus is the state of a co-signing session
+type SigningSessionStatus string
+
+const (
+	// SigningCollecting sessions wait for signatures
+	SigningCollecting SigningSessionStatus = "collecting"
+	// SigningIssued sessions reached their threshold and their credential was signed
+	SigningIssued SigningSessionStatus = "issued"
+	// SigningFailed sessions reached their threshold but could not be signed
+	SigningFailed SigningSessionStatus = "failed"
+)
+
+// StartSigningRequest asks to collect co-signatures for an issuance request
+type StartSigningRequest struct {
+	Request IssueCredentialRequest `json:"request"`
+	// Threshold and Signers default to the policy covering the request
+	Threshold int      `json:"threshold,omitempty"`
+	Signers   []string `json:"signers,omitempty"`
+	// TTL is how long signatures are collected; zero means DefaultSigningSessionTTL
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// SigningSession collects the co-signatures an issuance request needs and issues its credential
+// once the threshold is reached
+type SigningSession struct {
+	ID      string                 `json:"id"`
+	Request IssueCredentialRequest `json:"request"`
+	// RequestSalt salts the request digest signers sign
+	RequestSalt []byte                  `json:"requestSalt"`
+	Envelope    *vc.CoSignatureEnvelope `json:"envelope"`
+	Status      SigningSessionStatus    `json:"status"`
+	// Credential is set once the session's credential has been signed
+	Credential *vc.VerifiableCredential `json:"credential,omitempty"`
+	// Error explains why a session that reached its threshold could not be signed
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SetCoSigningPolicy requires the credentials a policy covers to be issued through signing sessions.
+// A policy with no signers removes the policy for its issuer and template.
+func (uc *UseCase) SetCoSigningPolicy(policy CoSigningPolicy) error {
+	if _, err := uc.getIssuer(policy.IssuerDID); err != nil {
+		return err
+	}
+
+	uc.coSigningMu.Lock()
+	defer uc.coSigningMu.Unlock()
+
+	if len(policy.Signers) == 0 {
+		delete(uc.coSigningPolicies[policy.IssuerDID], policy.TemplateID)
+		return nil
+	}
+
+	envelope := vc.CoSignatureEnvelope{Threshold: policy.Threshold, Signers: policy.Signers}
+	if err := envelope.Validate(); err != nil {
+		return err
+	}
+
+	if uc.coSigningPolicies[policy.IssuerDID] == nil {
+		uc.coSigningPolicies[policy.IssuerDID] = make(map[string]*CoSigningPolicy)
+	}
+	policy.Signers = append([]string(nil), policy.Signers...)
+	uc.coSigningPolicies[policy.IssuerDID][policy.TemplateID] = &policy
+	return nil
+}
+
+// CoSigningPolicyFor returns the policy covering an issuance request, preferring one for its
+// template over the issuer's general policy, or nil when issuance needs no co-signatures
+func (uc *UseCase) CoSigningPolicyFor(req IssueCredentialRequest) *CoSigningPolicy {
+	uc.coSigningMu.Lock()
+	defer uc.coSigningMu.Unlock()
+
+	policies := uc.coSigningPolicies[req.IssuerDID]
+	if policy, ok := policies[req.TemplateID]; ok {
+		copied := *policy
+		return &copied
+	}
+	if policy, ok := policies[""]; ok {
+		copied := *policy
+		return &copied
+	}
+	return nil
+}
+
+// checkCoSigningPolicy checks co-signatures satisfy the policy covering an issuance request
+func (uc *UseCase) checkCoSigningPolicy(req IssueCredentialRequest, envelope *vc.CoSignatureEnvelope) error {
+	policy := uc.CoSigningPolicyFor(req)
+	if policy == nil {
+		return nil
+	}
+	if envelope == nil {
+		return fmt.Errorf("%w: %d of %v must approve it in a signing session", ErrCoSigningRequired, policy.Threshold, policy.Signers)
+	}
+
+	signed := 0
+	for _, signature := range envelope.Signatures {
+		for _, signer := range policy.Signers {
+			if signature.Signer == signer {
+				signed++
+			}
+		}
+	}
+	if signed < policy.Threshold {
+		return fmt.Errorf("%w: %d of the policy's signers signed, %d needed", ErrCoSigningRequired, signed, policy.Threshold)
+	}
+	return nil
+}
+
+// StartSigningSession checks an issuance request and opens a session collecting co-signatures for it
+func (uc *UseCase) StartSigningSession(req StartSigningRequest) (*SigningSession, error) {
+	if _, err := validateIssueRequest(req.Request); err != nil {
+		return nil, err
+	}
+	if _, err := uc.getIssuer(req.Request.IssuerDID); err != nil {
+		return nil, err
+	}
+	if req.Request.TemplateID != "" {
+		if _, err := uc.templates.Get(req.Request.TemplateID); err != nil {
+			return nil, fmt.Errorf("failed to get template: %w", err)
+		}
+	}
+
+	if len(req.Signers) == 0 {
+		policy := uc.CoSigningPolicyFor(req.Request)
+		if policy == nil {
+			return nil, fmt.Errorf("signers are required when no co-signing policy covers the request")
+		}
+		req.Signers = policy.Signers
+		if req.Threshold == 0 {
+			req.Threshold = policy.Threshold
+		}
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate request salt: %w", err)
+	}
+	digest, err := requestDigest(req.Request, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	envelope := &vc.CoSignatureEnvelope{
+		SessionID:     id,
+		Issuer:        req.Request.IssuerDID,
+		Subject:       req.Request.SubjectDID,
+		RequestDigest: digest,
+		Threshold:     req.Threshold,
+		Signers:       append([]string(nil), req.Signers...),
+	}
+	if err := envelope.Validate(); err != nil {
+		return nil, err
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = DefaultSigningSessionTTL
+	}
+	now := uc.clock.Now()
+	session := &SigningSession{
+		ID:          id,
+		Request:     req.Request,
+		RequestSalt: salt,
+		Envelope:    envelope,
+		Status:      SigningCollecting,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+		UpdatedAt:   now,
+	}
+	if err := uc.saveSigningSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// requestDigest hashes an issuance request with a salt, so the digest does not reveal its claims
+func requestDigest(req IssueCredentialRequest, salt []byte) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal issuance request: %w", err)
+	}
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetSigningSession returns a co-signing session
+func (uc *UseCase) GetSigningSession(id string) (*SigningSession, error) {
+	var session SigningSession
+	if err := storage.GetJSON(uc.store, signingSessionKeyPrefix+id, &session); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("signing session not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to load signing session: %w", err)
+	}
+	return &session, nil
+}
+
+// SignSession signs a session's statement with a signer's key held by this instance's DID service
+// and adds the signature
+func (uc *UseCase) SignSession(ctx context.Context, id, verificationMethod string) (*SigningSession, error) {
+	session, err := uc.GetSigningSession(id)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := vc.CoSignatureSigningInput(session.Envelope)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := uc.didService.SignWithDID(verificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign session: %w", err)
+	}
+	return uc.CoSign(ctx, id, verificationMethod, signature)
+}
+
+// CoSign adds a signer's signature of a session's statement, made with the verification method
+// of one of the session's signers. The signature completing the threshold issues the credential; a
+// credential that fails to sign marks the session failed with the reason.
+func (uc *UseCase) CoSign(ctx context.Context, id, verificationMethod string, signature []byte) (*SigningSession, error) {
+	methodURL, err := did.ParseDIDURL(verificationMethod)
+	if err != nil || methodURL.Fragment == "" {
+		return nil, fmt.Errorf("%q is not a verification method reference", verificationMethod)
+	}
+	signer := methodURL.DID.String()
+
+	session, err := uc.updateSigningSession(id, func(session *SigningSession) error {
+		if session.Status != SigningCollecting {
+			return fmt.Errorf("signing session %s is %s, not collecting signatures", id, session.Status)
+		}
+		if uc.clock.Now().After(session.ExpiresAt) {
+			return fmt.Errorf("signing session %s expired at %s", id, session.ExpiresAt.Format(time.RFC3339))
+		}
+		if !session.Envelope.HasSigner(signer) {
+			return fmt.Errorf("%s is not a signer of session %s", signer, id)
+		}
+		for _, existing := range session.Envelope.Signatures {
+			if existing.Signer == signer {
+				return fmt.Errorf("%s has already signed session %s", signer, id)
+			}
+		}
+
+		payload, err := vc.CoSignatureSigningInput(session.Envelope)
+		if err != nil {
+			return err
+		}
+		if err := uc.didService.VerifyWithDID(signer, verificationMethod, payload, signature); err != nil {
+			return fmt.Errorf("invalid co-signature: %w", err)
+		}
+
+		session.Envelope.Signatures = append(session.Envelope.Signatures, vc.CoSignature{
+			Signer:             signer,
+			VerificationMethod: verificationMethod,
+			Created:            uc.clock.Now(),
+			ProofValue:         did.EncodeSignatureMultibase(signature),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(session.Envelope.Signatures) < session.Envelope.Threshold {
+		return session, nil
+	}
+
+	// Only the signature that claims the session issues it
+	claimed, err := uc.store.SetNX(signingIssueKeyPrefix+id, []byte(SigningIssued), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim signing session: %w", err)
+	}
+	if !claimed {
+		return session, nil
+	}
+
+	credential, issueErr := uc.issueCredential(ctx, session.Request, session.Envelope, nil)
+	return uc.updateSigningSession(id, func(session *SigningSession) error {
+		if issueErr != nil {
+			session.Status = SigningFailed
+			session.Error = issueErr.Error()
+			return nil
+		}
+		session.Status = SigningIssued
+		session.Credential = credential
+		session.Envelope = credential.CoSignatures
+		return nil
+	})
+}
+
+// bindCoSignatures attaches the co-signatures approving a newly issued credential, signing them
+// together with the credential's ID
+func (uc *UseCase) bindCoSignatures(credential *vc.VerifiableCredential, envelope *vc.CoSignatureEnvelope) error {
+	doc, err := uc.didService.ResolveDID(credential.Issuer())
+	if err != nil {
+		return fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	if len(doc.AssertionMethod) == 0 {
+		return fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	bound := *envelope
+	bound.Signatures = append([]vc.CoSignature(nil), envelope.Signatures...)
+	bound.CredentialID = credential.ID
+	bound.Proof = &vc.Proof{
+		Type:               "Ed25519Signature2020",
+		Created:            uc.clock.Now(),
+		VerificationMethod: doc.AssertionMethod[0],
+		ProofPurpose:       "assertionMethod",
+	}
+
+	payload, err := vc.CoSignatureBindingInput(&bound)
+	if err != nil {
+		return err
+	}
+
+	signature, err := uc.didService.SignWithDID(bound.Proof.VerificationMethod, payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign co-signatures: %w", err)
+	}
+	bound.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	credential.CoSignatures = &bound
+	return nil
+}
+
+// updateSigningSession loads a session, applies update and stores the result
+func (uc *UseCase) updateSigningSession(id string, update func(*SigningSession) error) (*SigningSession, error) {
+	uc.coSigningMu.Lock()
+	defer uc.coSigningMu.Unlock()
+
+	session, err := uc.GetSigningSession(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(session); err != nil {
+		return nil, err
+	}
+
+	session.UpdatedAt = uc.clock.Now()
+	if err := uc.saveSigningSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (uc *UseCase) saveSigningSession(session *SigningSession) error {
+	if err := storage.SetJSON(uc.store, signingSessionKeyPrefix+session.ID, session, 0); err != nil {
+		return fmt.Errorf("failed to store signing session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// authorizationKeyPrefix stores the authorizations each sub-issuer accepted, by issuer DID
+const authorizationKeyPrefix = "authorizations:"
+
+// AuthorizeIssuerRequest asks an issuer to authorize a sub-issuer
+type AuthorizeIssuerRequest struct {
+	IssuerDID    string `json:"issuerDid"`
+	SubIssuerDID string `json:"subIssuerDid"`
+	// CredentialTypes limits the types the sub-issuer may issue; empty allows any
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+	// MayDelegate lets the sub-issuer authorize sub-issuers of its own
+	MayDelegate bool       `json:"mayDelegate,omitempty"`
+	ValidUntil  *time.Time `json:"validUntil,omitempty"`
+}
+
+// AuthorizeIssuer issues an AuthorizedIssuerCredential to a sub-issuer. It is an ordinary
+// credential of the issuer's, with status entries, so the authorization can be revoked. It carries
+// the issuer's own authorizations, if any, so the sub-issuer receives the chain to the root.
+func (uc *UseCase) AuthorizeIssuer(ctx context.Context, req AuthorizeIssuerRequest) (*vc.VerifiableCredential, error) {
+	if req.SubIssuerDID == "" {
+		return nil, fmt.Errorf("sub-issuer DID is required")
+	}
+	if req.SubIssuerDID == req.IssuerDID {
+		return nil, fmt.Errorf("an issuer cannot authorize itself")
+	}
+	for _, t := range req.CredentialTypes {
+		if t == "" || strings.Contains(t, ",") {
+			return nil, fmt.Errorf("invalid credential type %q", t)
+		}
+	}
+
+	// An issuer that is itself a sub-issuer must be allowed to delegate
+	held, err := uc.Authorizations(req.IssuerDID)
+	if err != nil {
+		return nil, err
+	}
+	if len(held) > 0 {
+		mayDelegate := false
+		for _, authorization := range held {
+			delegation, err := vc.ParseDelegation(authorization)
+			if err == nil && delegation.Delegate == req.IssuerDID && delegation.MayDelegate {
+				mayDelegate = true
+			}
+		}
+		if !mayDelegate {
+			return nil, fmt.Errorf("issuer %s is not authorized to authorize sub-issuers", req.IssuerDID)
+		}
+	}
+
+	claims := []vc.Claim{{Key: vc.MayDelegateClaim, Value: req.MayDelegate, Type: schema.ClaimTypeBoolean}}
+	if len(req.CredentialTypes) > 0 {
+		claims = append(claims, vc.Claim{Key: vc.AuthorizedTypesClaim, Value: strings.Join(req.CredentialTypes, ","), Type: schema.ClaimTypeString})
+	}
+
+	return uc.IssueCredentialContext(ctx, IssueCredentialRequest{
+		IssuerDID:  req.IssuerDID,
+		SubjectDID: req.SubIssuerDID,
+		Claims:     claims,
+		TemplateID: vc.AuthorizedIssuerTemplateID,
+		ValidUntil: req.ValidUntil,
+	})
+}
+
+// AcceptAuthorization installs an authorization issued to one of this instance's issuers, with
+// the authorizations it carries. Credentials the issuer signs afterwards carry them all, so holders
+// can present the chain.
+func (uc *UseCase) AcceptAuthorization(authorization *vc.VerifiableCredential) ([]*vc.VerifiableCredential, error) {
+	delegation, err := vc.ParseDelegation(authorization)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.getIssuer(delegation.Delegate); err != nil {
+		return nil, err
+	}
+	if err := uc.vcService.VerifyCredential(authorization); err != nil {
+		return nil, fmt.Errorf("invalid authorization: %w", err)
+	}
+
+	uc.issuersMu.Lock()
+	defer uc.issuersMu.Unlock()
+
+	held, err := uc.Authorizations(delegation.Delegate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each authorization is stored once, without the chain it carried
+	have := make(map[string]bool, len(held))
+	for _, existing := range held {
+		have[existing.ID] = true
+	}
+	for _, received := range append([]*vc.VerifiableCredential{authorization}, authorization.Authorizations...) {
+		if have[received.ID] {
+			continue
+		}
+		have[received.ID] = true
+		stored := *received
+		stored.Authorizations = nil
+		held = append(held, &stored)
+	}
+
+	if err := storage.SetJSON(uc.store, authorizationKeyPrefix+delegation.Delegate, held, 0); err != nil {
+		return nil, fmt.Errorf("failed to store authorizations: %w", err)
+	}
+	return held, nil
+}
+
+// Authorizations returns the authorizations an issuer accepted
+func (uc *UseCase) Authorizations(issuerDID string) ([]*vc.VerifiableCredential, error) {
+	var held []*vc.VerifiableCredential
+	err := storage.GetJSON(uc.store, authorizationKeyPrefix+issuerDID, &held)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load authorizations: %w", err)
+	}
+	return held, nil
+}
@@ -0,0 +1,101 @@
+package issuer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultDomainLinkageValidity is how long a domain linkage credential is valid when no period is given
+const DefaultDomainLinkageValidity = 365 * 24 * time.Hour
+
+// LinkDomain signs a domain linkage credential stating that the issuer is controlled by the
+// operator of origin. Publishing it in the origin's DID configuration lets verifiers trust the
+// issuer by domain. A later linkage to the same origin replaces the earlier one.
+func (uc *UseCase) LinkDomain(issuerDID, origin string, validFor time.Duration) (*vc.DomainLinkageCredential, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+	issuerDID = setup.DID.String()
+
+	origin, err = vc.NormalizeOrigin(origin)
+	if err != nil {
+		return nil, err
+	}
+
+	if validFor < 0 {
+		return nil, fmt.Errorf("validity period cannot be negative")
+	}
+	if validFor == 0 {
+		validFor = DefaultDomainLinkageValidity
+	}
+
+	doc, err := uc.didService.ResolveDID(issuerDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+	if len(doc.AssertionMethod) == 0 {
+		return nil, fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	now := uc.clock.Now().UTC().Truncate(time.Second)
+	credential := &vc.DomainLinkageCredential{
+		Context:        []string{"https://www.w3.org/2018/credentials/v1", vc.DIDConfigurationContext},
+		Type:           []string{"VerifiableCredential", vc.DomainLinkageCredentialType},
+		Issuer:         issuerDID,
+		IssuanceDate:   now,
+		ExpirationDate: now.Add(validFor),
+		CredentialSubject: vc.DomainLinkageSubject{
+			ID:     issuerDID,
+			Origin: origin,
+		},
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            now,
+			VerificationMethod: doc.AssertionMethod[0],
+			ProofPurpose:       "assertionMethod",
+		},
+	}
+
+	payload, err := vc.DomainLinkageSigningInput(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := uc.didService.SignWithDID(credential.Proof.VerificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign domain linkage: %w", err)
+	}
+	credential.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	uc.domainsMu.Lock()
+	defer uc.domainsMu.Unlock()
+
+	for i, existing := range uc.domainLinks {
+		if existing.Issuer == issuerDID && existing.CredentialSubject.Origin == origin {
+			uc.domainLinks[i] = credential
+			return credential, nil
+		}
+	}
+	uc.domainLinks = append(uc.domainLinks, credential)
+
+	return credential, nil
+}
+
+// DIDConfiguration returns the DID configuration resource listing the domain linkages signed by
+// this issuer's DIDs, for serving at /.well-known/did-configuration.json
+func (uc *UseCase) DIDConfiguration() *vc.DIDConfiguration {
+	uc.domainsMu.RLock()
+	defer uc.domainsMu.RUnlock()
+
+	linked := make([]*vc.DomainLinkageCredential, len(uc.domainLinks))
+	copy(linked, uc.domainLinks)
+
+	return &vc.DIDConfiguration{
+		Context:    vc.DIDConfigurationContext,
+		LinkedDIDs: linked,
+	}
+}
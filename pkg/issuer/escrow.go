@@ -0,0 +1,210 @@
+package issuer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/shamir"
+)
+
+// EscrowKeyRequest asks to back up an issuer's BBS+ private key with custodians
+type EscrowKeyRequest struct {
+	IssuerDID string `json:"issuerDid"`
+	// Custodians are the DIDs each given one share, encrypted to their key agreement key
+	Custodians []string `json:"custodians"`
+	// Threshold is how many custodians must return their shares to rebuild the key
+	Threshold int `json:"threshold"`
+}
+
+// KeyEscrow is an issuer's BBS+ private key split among custodians. It holds only encrypted
+// shares, so it can be handed out and stored in the open.
+type KeyEscrow struct {
+	ID        string         `json:"id"`
+	IssuerDID string         `json:"issuerDid"`
+	KeyID     string         `json:"keyId"`
+	Threshold int            `json:"threshold"`
+	Shares    []*EscrowShare `json:"shares"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// EscrowShare is one custodian's share, encrypted to the custodian's DID
+type EscrowShare struct {
+	Custodian string                 `json:"custodian"`
+	Index     int                    `json:"index"`
+	Envelope  *did.EncryptedEnvelope `json:"envelope"`
+}
+
+// OpenedShare is a custodian's decrypted share. It carries the escrow it belongs to, so shares
+// returned for recovery can be checked to belong together.
+type OpenedShare struct {
+	EscrowID  string `json:"escrowId"`
+	IssuerDID string `json:"issuerDid"`
+	KeyID     string `json:"keyId"`
+	Threshold int    `json:"threshold"`
+	Index     int    `json:"index"`
+	Value     []byte `json:"value"`
+}
+
+// RecoveredKey reports the key installed by RecoverKey
+type RecoveredKey struct {
+	IssuerDID   string    `json:"issuerDid"`
+	KeyID       string    `json:"keyId"`
+	SharesUsed  int       `json:"sharesUsed"`
+	RecoveredAt time.Time `json:"recoveredAt"`
+}
+
+// EscrowKey splits the issuer's current BBS+ private key into one share per custodian, any
+// threshold of which rebuild it. Each share is encrypted to its custodian's key agreement key;
+// the plaintext key and shares are not kept.
+func (uc *UseCase) EscrowKey(req EscrowKeyRequest) (*KeyEscrow, error) {
+	setup, err := uc.getIssuer(req.IssuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(req.Custodians))
+	for _, custodian := range req.Custodians {
+		if seen[custodian] {
+			return nil, fmt.Errorf("custodian %s is listed twice", custodian)
+		}
+		seen[custodian] = true
+	}
+
+	shares, err := shamir.Split(setup.BBSKeyPair.PrivateKey, len(req.Custodians), req.Threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split issuer key: %w", err)
+	}
+
+	escrow := &KeyEscrow{
+		ID:        "urn:uuid:" + uuid.New().String(),
+		IssuerDID: setup.DID.String(),
+		KeyID:     setup.BBSKeyID,
+		Threshold: req.Threshold,
+		CreatedAt: uc.clock.Now(),
+	}
+	for i, custodian := range req.Custodians {
+		plaintext, err := json.Marshal(&OpenedShare{
+			EscrowID:  escrow.ID,
+			IssuerDID: escrow.IssuerDID,
+			KeyID:     escrow.KeyID,
+			Threshold: escrow.Threshold,
+			Index:     shares[i].Index,
+			Value:     shares[i].Value,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode share: %w", err)
+		}
+		envelope, err := uc.didService.EncryptForDID(custodian, plaintext)
+		uc.bbsService.SecureErase(plaintext)
+		uc.bbsService.SecureErase(shares[i].Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt share for custodian %s: %w", custodian, err)
+		}
+		escrow.Shares = append(escrow.Shares, &EscrowShare{
+			Custodian: custodian,
+			Index:     shares[i].Index,
+			Envelope:  envelope,
+		})
+	}
+	return escrow, nil
+}
+
+// OpenEscrowShare decrypts a share with its custodian's key agreement key. Custodians open their
+// shares on their own machines, so no service ever holds a custodian's key and a share together.
+func OpenEscrowShare(share *EscrowShare, agreementKey *did.AgreementKeyPair) (*OpenedShare, error) {
+	if share == nil || share.Envelope == nil {
+		return nil, fmt.Errorf("share has no envelope")
+	}
+	if agreementKey == nil || share.Envelope.RecipientKID != agreementKey.KeyID {
+		return nil, fmt.Errorf("share is not encrypted to the given key")
+	}
+	plaintext, err := did.DecryptEnvelope(share.Envelope, agreementKey.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+
+	var opened OpenedShare
+	if err := json.Unmarshal(plaintext, &opened); err != nil {
+		return nil, fmt.Errorf("invalid share: %w", err)
+	}
+	return &opened, nil
+}
+
+// RecoverKey rebuilds an issuer's BBS+ private key from custodians' opened shares and installs it
+// for signing. The rebuilt key must match the public key the issuer's DID document publishes under
+// the escrowed key ID, and only the issuer's current key is reinstalled.
+func (uc *UseCase) RecoverKey(shares []*OpenedShare) (*RecoveredKey, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares given")
+	}
+	first := shares[0]
+	parts := make([]shamir.Share, 0, len(shares))
+	for _, share := range shares {
+		if share.EscrowID != first.EscrowID || share.IssuerDID != first.IssuerDID || share.KeyID != first.KeyID || share.Threshold != first.Threshold {
+			return nil, fmt.Errorf("shares belong to different escrows")
+		}
+		parts = append(parts, shamir.Share{Index: share.Index, Value: share.Value})
+	}
+	if len(shares) < first.Threshold {
+		return nil, fmt.Errorf("escrow needs %d shares, got %d", first.Threshold, len(shares))
+	}
+
+	uc.issuersMu.Lock()
+	defer uc.issuersMu.Unlock()
+
+	setup, err := uc.getIssuer(first.IssuerDID)
+	if err != nil {
+		return nil, err
+	}
+	if setup.BBSKeyID != first.KeyID {
+		return nil, fmt.Errorf("key %s is no longer the issuer's signing key %s", first.KeyID, setup.BBSKeyID)
+	}
+
+	doc, err := uc.didService.ResolveDID(first.IssuerDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+	var publicKey []byte
+	for _, method := range doc.VerificationMethod {
+		if method.ID == first.KeyID {
+			publicKey, err = did.DecodeBBSPublicKeyMultibase(method.PublicKeyMultibase)
+			if err != nil {
+				return nil, fmt.Errorf("invalid published key %s: %w", first.KeyID, err)
+			}
+		}
+	}
+	if publicKey == nil {
+		return nil, fmt.Errorf("issuer DID document does not publish key %s", first.KeyID)
+	}
+
+	privateKey, err := shamir.Combine(parts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine shares: %w", err)
+	}
+	keyPair := &bbs.KeyPair{PublicKey: publicKey, PrivateKey: privateKey}
+	if err := uc.bbsService.ValidateKeyPair(keyPair); err != nil {
+		uc.bbsService.SecureErase(privateKey)
+		return nil, fmt.Errorf("rebuilt key does not match the published key: %w", err)
+	}
+
+	if err := uc.vcService.SetIssuerKey(first.IssuerDID, first.KeyID, keyPair); err != nil {
+		return nil, err
+	}
+	setup.BBSKeyPair = keyPair
+	if err := uc.saveIssuer(setup); err != nil {
+		return nil, err
+	}
+
+	return &RecoveredKey{
+		IssuerDID:   first.IssuerDID,
+		KeyID:       first.KeyID,
+		SharesUsed:  len(shares),
+		RecoveredAt: uc.clock.Now(),
+	}, nil
+}
@@ -0,0 +1,24 @@
+package issuer
+
+import (
+	"log"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+)
+
+// SetEventBus publishes a CredentialIssued event on bus for every credential issued. Nil stops
+// publishing.
+func (uc *UseCase) SetEventBus(bus *events.Bus) {
+	uc.events = bus
+}
+
+// publish queues an event without waiting for its subscribers; issuance does not fail when the
+// bus is closed
+func (uc *UseCase) publish(t events.Type, data interface{}) {
+	if uc.events == nil {
+		return
+	}
+	if err := uc.events.PublishAsync(events.New(t, data)); err != nil {
+		log.Printf("⚠️  Failed to publish %s event: %v", t, err)
+	}
+}
@@ -0,0 +1,201 @@
+package issuer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/blob"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultEvidenceType is the evidence type used when a document names none
+const DefaultEvidenceType = "DocumentVerification"
+
+// EvidenceDocument is a document attached to a credential's evidence section at issuance
+type EvidenceDocument struct {
+	// Type defaults to DocumentVerification
+	Type        []string `json:"type,omitempty"`
+	Description string   `json:"description,omitempty"`
+	MediaType   string   `json:"mediaType"`
+	Document    []byte   `json:"document"`
+	// Encrypt stores the document sealed, so only verifiers the holder grants access can read it
+	Encrypt bool `json:"encrypt,omitempty"`
+}
+
+// EvidenceRelease is an evidence document released to a verifier under a holder's grant.
+// Encrypted documents are released sealed; the grant carries their key for the verifier.
+type EvidenceRelease struct {
+	Evidence vc.Evidence `json:"evidence"`
+	Document []byte      `json:"document"`
+}
+
+// SetBlobStore keeps evidence documents in store. A nil store restores the in-memory default.
+func (uc *UseCase) SetBlobStore(store blob.Store) {
+	if store == nil {
+		store = blob.NewMemoryStore()
+	}
+	uc.blobs = store
+}
+
+// validateEvidence checks the evidence documents of an issuance request
+func validateEvidence(documents []EvidenceDocument) error {
+	for i, document := range documents {
+		if document.MediaType == "" {
+			return fmt.Errorf("evidence %d: media type is required", i)
+		}
+		if len(document.Document) == 0 {
+			return fmt.Errorf("evidence %d: document is required", i)
+		}
+	}
+	return nil
+}
+
+// attachEvidence stores the evidence documents of a newly issued credential and signs their
+// descriptions, handing the holder the keys of the encrypted ones alongside the credential
+func (uc *UseCase) attachEvidence(credential *vc.VerifiableCredential, documents []EvidenceDocument) error {
+	doc, err := uc.didService.ResolveDID(credential.Issuer())
+	if err != nil {
+		return fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	if len(doc.AssertionMethod) == 0 {
+		return fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	bundle := &vc.EvidenceBundle{
+		CredentialID: credential.ID,
+		Issuer:       credential.Issuer(),
+	}
+	var keys []vc.EvidenceKey
+
+	for _, document := range documents {
+		evidence := vc.Evidence{
+			ID:          "urn:uuid:" + uuid.New().String(),
+			Type:        document.Type,
+			Description: document.Description,
+			MediaType:   document.MediaType,
+			DigestSRI:   vc.DocumentDigest(document.Document),
+			Encrypted:   document.Encrypt,
+		}
+		if len(evidence.Type) == 0 {
+			evidence.Type = []string{DefaultEvidenceType}
+		}
+
+		// The digest covers the document itself, so the verifier can check it after unsealing
+		stored := document.Document
+		if document.Encrypt {
+			sealed, key, err := vc.SealDocument(document.Document)
+			if err != nil {
+				return err
+			}
+			stored = sealed
+			keys = append(keys, vc.EvidenceKey{EvidenceID: evidence.ID, Key: key})
+		}
+
+		evidence.BlobRef, err = uc.blobs.Put(stored)
+		if err != nil {
+			return err
+		}
+
+		bundle.Items = append(bundle.Items, evidence)
+	}
+
+	bundle.Proof = &vc.Proof{
+		Type:               "Ed25519Signature2020",
+		Created:            uc.clock.Now(),
+		VerificationMethod: doc.AssertionMethod[0],
+		ProofPurpose:       "assertionMethod",
+	}
+
+	payload, err := vc.EvidenceBundleSigningInput(bundle)
+	if err != nil {
+		return err
+	}
+
+	signature, err := uc.didService.SignWithDID(bundle.Proof.VerificationMethod, payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign evidence: %w", err)
+	}
+	bundle.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	credential.Evidence = bundle
+	credential.EvidenceKeys = keys
+	return nil
+}
+
+// RetrieveEvidence releases an evidence document of a credential this service issued to the
+// verifier named in a grant signed by the credential's subject
+func (uc *UseCase) RetrieveEvidence(grant *vc.EvidenceGrant) (*EvidenceRelease, error) {
+	if grant == nil {
+		return nil, fmt.Errorf("evidence grant is required")
+	}
+
+	if uc.clock.Now().After(grant.Expires) {
+		return nil, fmt.Errorf("evidence grant %s expired at %s", grant.ID, grant.Expires.Format(time.RFC3339))
+	}
+
+	credential, err := uc.loadIssued(grant.CredentialID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("issued credential not found: %s", grant.CredentialID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issued credential: %w", err)
+	}
+
+	if subjectID, _ := credential.CredentialSubject["id"].(string); subjectID != grant.Holder {
+		return nil, fmt.Errorf("grant is signed by %s, not the subject of credential %s", grant.Holder, credential.ID)
+	}
+
+	if err := uc.verifyEvidenceGrant(grant); err != nil {
+		return nil, fmt.Errorf("invalid evidence grant: %w", err)
+	}
+
+	if credential.Evidence == nil {
+		return nil, fmt.Errorf("credential %s has no evidence", credential.ID)
+	}
+	evidence, ok := credential.Evidence.Find(grant.EvidenceID)
+	if !ok {
+		return nil, fmt.Errorf("credential %s has no evidence %s", credential.ID, grant.EvidenceID)
+	}
+	if evidence.BlobRef == "" {
+		return nil, fmt.Errorf("evidence %s was not stored", evidence.ID)
+	}
+
+	document, err := uc.blobs.Get(evidence.BlobRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve evidence %s: %w", evidence.ID, err)
+	}
+
+	return &EvidenceRelease{Evidence: *evidence, Document: document}, nil
+}
+
+// verifyEvidenceGrant checks a grant was signed by a key of the holder it names
+func (uc *UseCase) verifyEvidenceGrant(grant *vc.EvidenceGrant) error {
+	if grant.Proof == nil || grant.Proof.ProofValue == "" {
+		return fmt.Errorf("no proof")
+	}
+
+	if !did.IsVerificationMethodOf(grant.Proof.VerificationMethod, grant.Holder) {
+		return fmt.Errorf("key %s is not controlled by holder %s", grant.Proof.VerificationMethod, grant.Holder)
+	}
+
+	signature, err := did.DecodeSignatureMultibase(grant.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.EvidenceGrantSigningInput(grant)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(grant.Holder, grant.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package issuer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// issuerSet resolves the anonymity set made of the issuer and the given issuers to the members'
+// current BBS+ keys. The keys are signed into the credential metadata, and holders hiding the
+// issuer prove their credential's signature was made under one of them.
+func (uc *UseCase) issuerSet(issuerDID string, anonymitySet []string) (*vc.IssuerSetProof, error) {
+	issuers := []string{issuerDID}
+	for _, member := range anonymitySet {
+		if member != "" && !containsIssuer(issuers, member) {
+			issuers = append(issuers, member)
+		}
+	}
+	if len(issuers) < 2 {
+		return nil, fmt.Errorf("anonymity set needs at least one issuer besides %s", issuerDID)
+	}
+	sort.Strings(issuers)
+
+	set := &vc.IssuerSetProof{
+		Type:                vc.IssuerSetProofType,
+		Issuers:             issuers,
+		VerificationMethods: make([]string, len(issuers)),
+	}
+	seen := make(map[string]string, len(issuers))
+
+	for i, member := range issuers {
+		doc, err := uc.didService.ResolveDID(member)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve anonymity set member %s: %w", member, err)
+		}
+
+		method, ok := doc.CurrentVerificationMethod("Bls12381G2Key2020")
+		if !ok {
+			return nil, fmt.Errorf("anonymity set member %s publishes no BBS+ key", member)
+		}
+
+		// A key shared by two members would not hide which of them signed
+		if _, err := did.DecodeBBSPublicKeyMultibase(method.PublicKeyMultibase); err != nil {
+			return nil, fmt.Errorf("anonymity set member %s: %w", member, err)
+		}
+		if other, ok := seen[method.PublicKeyMultibase]; ok {
+			return nil, fmt.Errorf("anonymity set members %s and %s share a BBS+ key", other, member)
+		}
+		seen[method.PublicKeyMultibase] = member
+
+		set.VerificationMethods[i] = method.ID
+	}
+
+	return set, nil
+}
+
+func containsIssuer(issuers []string, issuer string) bool {
+	for _, candidate := range issuers {
+		if candidate == issuer {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package issuer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+)
+
+// KeyRotationEvent announces that an issuer replaced its signing keys.
+// Retired keys stay in the DID document, so credentials issued before RotatedAt still verify.
+type KeyRotationEvent struct {
+	IssuerDID   string    `json:"issuerDid"`
+	RetiredKeys []string  `json:"retiredKeys"`
+	NewKeys     []string  `json:"newKeys"`
+	RotatedAt   time.Time `json:"rotatedAt"`
+}
+
+// OnKeyRotation registers a handler called after every key rotation
+func (uc *UseCase) OnKeyRotation(handler func(KeyRotationEvent)) {
+	uc.rotationsMu.Lock()
+	defer uc.rotationsMu.Unlock()
+
+	uc.rotationHandlers = append(uc.rotationHandlers, handler)
+}
+
+// RotateKeys replaces the issuer's Ed25519 DID key and BBS+ signing key with new versions.
+// New credentials are signed with the new BBS+ key; the retired keys remain resolvable for
+// the period they were in force.
+func (uc *UseCase) RotateKeys(issuerDID string) (*KeyRotationEvent, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := uc.rotateKeys(setup.DID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Anchor the new key set so verifiers checking anchors accept it
+	if uc.anchors != nil {
+		if _, err := uc.anchorKeySet(event.IssuerDID); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.rotationsMu.Lock()
+	uc.rotations[event.IssuerDID] = append(uc.rotations[event.IssuerDID], event)
+	handlers := append([]func(KeyRotationEvent){}, uc.rotationHandlers...)
+	uc.rotationsMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(*event)
+	}
+
+	return event, nil
+}
+
+// rotateKeys publishes the new keys and switches signing over to them. The issuers lock is
+// held throughout so concurrent rotations on this instance do not allocate the same key version.
+func (uc *UseCase) rotateKeys(issuer string) (*KeyRotationEvent, error) {
+	uc.issuersMu.Lock()
+	defer uc.issuersMu.Unlock()
+
+	setup, err := uc.getIssuer(issuer)
+	if err != nil {
+		return nil, err
+	}
+	now := uc.clock.Now()
+
+	keyPair, err := uc.didService.RotateKey(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate DID key: %w", err)
+	}
+	// The X25519 agreement key is not rotated and stays with the new key pair
+	keyPair.KeyAgreement = setup.KeyPair.KeyAgreement
+
+	bbsKeyPair, err := uc.bbsService.GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate BBS+ key pair: %w", err)
+	}
+
+	doc, err := uc.didService.ResolveDID(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	bbsKeyID := doc.NextKeyID("bbs-key")
+	method := bbsVerificationMethod(issuer, bbsKeyID, bbsKeyPair)
+	method.ValidFrom = &now
+	doc, err = uc.didService.AddVerificationMethod(issuer, method, setup.BBSKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish BBS+ key: %w", err)
+	}
+
+	if err := uc.vcService.SetIssuerKey(issuer, bbsKeyID, bbsKeyPair); err != nil {
+		return nil, err
+	}
+
+	if err := uc.saveIssuer(&IssuerSetup{
+		DID:        setup.DID,
+		DIDDoc:     doc,
+		KeyPair:    keyPair,
+		BBSKeyPair: bbsKeyPair,
+		BBSKeyID:   bbsKeyID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &KeyRotationEvent{
+		IssuerDID:   issuer,
+		RetiredKeys: []string{setup.KeyPair.KeyID, setup.BBSKeyID},
+		NewKeys:     []string{keyPair.KeyID, bbsKeyID},
+		RotatedAt:   now,
+	}, nil
+}
+
+// ListKeyRotations returns an issuer's key rotations, oldest first
+func (uc *UseCase) ListKeyRotations(issuerDID string) ([]*KeyRotationEvent, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.rotationsMu.RLock()
+	defer uc.rotationsMu.RUnlock()
+
+	return append([]*KeyRotationEvent{}, uc.rotations[setup.DID.String()]...), nil
+}
+
+// bbsVerificationMethod describes a BBS+ public key as a DID verification method
+func bbsVerificationMethod(issuerDID, keyID string, keyPair *bbs.KeyPair) did.VerificationMethod {
+	return did.VerificationMethod{
+		ID:                 keyID,
+		Type:               "Bls12381G2Key2020",
+		Controller:         issuerDID,
+		PublicKeyMultibase: "z" + base58.Encode(keyPair.PublicKey),
+	}
+}
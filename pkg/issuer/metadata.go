@@ -0,0 +1,163 @@
+package issuer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// CredentialFormat is the format identifier advertised for issued credentials
+const CredentialFormat = "ldp_vc"
+
+// IssuerMetadata describes what an issuer can issue, in the spirit of
+// OpenID for Verifiable Credential Issuance issuer metadata
+type IssuerMetadata struct {
+	CredentialIssuer                  string                              `json:"credential_issuer"`
+	CredentialEndpoint                string                              `json:"credential_endpoint,omitempty"`
+	CredentialConfigurationsSupported map[string]*CredentialConfiguration `json:"credential_configurations_supported"`
+	PublicKeys                        []IssuerPublicKey                   `json:"public_keys"`
+	Display                           []schema.Display                    `json:"display,omitempty"`
+}
+
+// CredentialConfiguration describes a single credential type offered by the issuer
+type CredentialConfiguration struct {
+	Format                               string               `json:"format"`
+	Types                                []string             `json:"types"`
+	CryptographicSuitesSupported         []string             `json:"cryptographic_suites_supported"`
+	CryptographicBindingMethodsSupported []string             `json:"cryptographic_binding_methods_supported"`
+	Claims                               []schema.ClaimSchema `json:"claims"`
+	Display                              []schema.Display     `json:"display,omitempty"`
+}
+
+// IssuerPublicKey is a BBS+ public key wallets can use to verify issued credentials
+type IssuerPublicKey struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	Controller      string `json:"controller"`
+	PublicKeyBase58 string `json:"publicKeyBase58"`
+}
+
+// SetTemplateRegistry replaces the credential template registry
+func (uc *UseCase) SetTemplateRegistry(registry schema.Registry) {
+	uc.templates = registry
+}
+
+// ListTemplates returns the credential templates this issuer can issue
+func (uc *UseCase) ListTemplates() []*schema.CredentialTemplate {
+	return uc.templates.List()
+}
+
+// GetTemplateDisplay resolves a template's display metadata for the requested locales,
+// given as a single tag or an Accept-Language value
+func (uc *UseCase) GetTemplateDisplay(templateID, locale string) (*schema.TemplateDisplay, error) {
+	template, err := uc.templates.Get(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.ResolveDisplay(locale), nil
+}
+
+// GetIssuerMetadata builds the issuer metadata document from the template registry.
+// When issuerDID is empty and exactly one issuer has been set up, that issuer is used.
+func (uc *UseCase) GetIssuerMetadata(issuerDID string) (*IssuerMetadata, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := setup.DID.String()
+	metadata := &IssuerMetadata{
+		CredentialIssuer:                  issuer,
+		CredentialConfigurationsSupported: make(map[string]*CredentialConfiguration),
+		PublicKeys: []IssuerPublicKey{{
+			ID:              setup.BBSKeyID,
+			Type:            "Bls12381G2Key2020",
+			Controller:      issuer,
+			PublicKeyBase58: base58.Encode(setup.BBSKeyPair.PublicKey),
+		}},
+		Display: []schema.Display{{Name: issuer, Locale: "en-US"}},
+	}
+
+	for _, template := range uc.templates.List() {
+		metadata.CredentialConfigurationsSupported[template.ID] = &CredentialConfiguration{
+			Format:                               CredentialFormat,
+			Types:                                []string{"VerifiableCredential", template.CredentialType},
+			CryptographicSuitesSupported:         []string{"BbsBlsSignature2020"},
+			CryptographicBindingMethodsSupported: []string{"did"},
+			Claims:                               template.Claims,
+			Display:                              template.Display,
+		}
+	}
+
+	return metadata, nil
+}
+
+// getIssuer looks up a set up issuer, defaulting to the only one when issuerDID is empty
+func (uc *UseCase) getIssuer(issuerDID string) (*IssuerSetup, error) {
+	if issuerDID != "" {
+		setup, err := uc.loadIssuer(issuerDID)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("issuer not found: %s", issuerDID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load issuer: %w", err)
+		}
+		return setup, nil
+	}
+
+	dids, err := uc.storedIssuerDIDs()
+	if err != nil {
+		return nil, err
+	}
+	switch len(dids) {
+	case 0:
+		return nil, fmt.Errorf("no issuer has been set up")
+	case 1:
+		return uc.getIssuer(dids[0])
+	}
+	return nil, fmt.Errorf("issuer DID is required, known issuers: %v", dids)
+}
+
+// validateTemplateClaims checks claims against a credential template
+func validateTemplateClaims(template *schema.CredentialTemplate, keys []string) error {
+	present := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := template.Claim(key); !ok {
+			return fmt.Errorf("claim %s is not defined by template %s", key, template.ID)
+		}
+		present[key] = true
+	}
+
+	for _, claim := range template.Claims {
+		if claim.Required && !present[claim.Key] {
+			return fmt.Errorf("required claim %s is missing for template %s", claim.Key, template.ID)
+		}
+	}
+
+	return nil
+}
+
+// applyTemplateTypes types each claim with the template's claim schema so values are coerced on
+// issuance, and applies the template's disclosure restrictions; a request may only tighten them
+func applyTemplateTypes(template *schema.CredentialTemplate, claims []vc.Claim) ([]vc.Claim, error) {
+	typed := make([]vc.Claim, len(claims))
+	for i, claim := range claims {
+		claimSchema, _ := template.Claim(claim.Key)
+		if claim.Type != "" && claim.Type != claimSchema.Type {
+			return nil, fmt.Errorf("claim %s has type %s but template %s requires %s", claim.Key, claim.Type, template.ID, claimSchema.Type)
+		}
+		claim.Type = claimSchema.Type
+		if claim.Disclosure == "" {
+			claim.Disclosure = claimSchema.Disclosure
+		} else if !claimSchema.Disclosure.Allows(claim.Disclosure) {
+			return nil, fmt.Errorf("claim %s has disclosure %s but template %s requires %s", claim.Key, claim.Disclosure, template.ID, claimSchema.Disclosure)
+		}
+		typed[i] = claim
+	}
+	return typed, nil
+}
@@ -0,0 +1,207 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/notify"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// subscriptionKeyPrefix keys status subscriptions by issuer and holder DID
+const subscriptionKeyPrefix = "status-subscription:"
+
+// StatusChange is the reason and effective date reported to the holder with a status change
+type StatusChange struct {
+	// Reason defaults to certificateHold for suspensions and unspecified otherwise
+	Reason vc.StatusReason
+	// EffectiveDate defaults to now; it may be earlier, e.g. when a key was compromised before the
+	// revocation, but not later, as the status list changes at once
+	EffectiveDate *time.Time
+}
+
+// resolve fills in the defaults and validates the change
+func (c StatusChange) resolve(event vc.StatusEvent, now time.Time) (vc.StatusReason, time.Time, error) {
+	reason := c.Reason
+	if reason == "" {
+		reason = vc.StatusReasonUnspecified
+		if event == vc.StatusEventSuspended {
+			reason = vc.StatusReasonCertificateHold
+		}
+	}
+	if err := reason.Validate(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	effective := now
+	if c.EffectiveDate != nil {
+		if c.EffectiveDate.After(now) {
+			return "", time.Time{}, fmt.Errorf("effective date cannot be in the future")
+		}
+		effective = c.EffectiveDate.UTC()
+	}
+	return reason, effective, nil
+}
+
+// SetStatusNotifier delivers status notifications to the endpoints holders subscribed. Without a
+// notifier, notifications only reach the handlers registered with OnStatusChange.
+func (uc *UseCase) SetStatusNotifier(notifier notify.Notifier) {
+	uc.notificationsMu.Lock()
+	defer uc.notificationsMu.Unlock()
+
+	uc.notifier = notifier
+}
+
+// OnStatusChange registers a handler called with the signed notification of every status change
+func (uc *UseCase) OnStatusChange(handler func(vc.StatusNotification)) {
+	uc.notificationsMu.Lock()
+	defer uc.notificationsMu.Unlock()
+
+	uc.notificationHandlers = append(uc.notificationHandlers, handler)
+}
+
+// SubscribeToStatus records where a holder receives status notifications for the credentials this
+// issuer issued them. A later subscription replaces an earlier one.
+func (uc *UseCase) SubscribeToStatus(subscription *vc.StatusSubscription) error {
+	if subscription == nil {
+		return fmt.Errorf("status subscription is required")
+	}
+	if subscription.Holder == "" {
+		return fmt.Errorf("holder is required")
+	}
+	if _, err := uc.getIssuer(subscription.Issuer); err != nil {
+		return err
+	}
+	if err := notify.ValidateEndpoint(subscription.Endpoint); err != nil {
+		return err
+	}
+
+	if subscription.Proof == nil || subscription.Proof.ProofValue == "" {
+		return fmt.Errorf("status subscription is not signed")
+	}
+	if !did.IsVerificationMethodOf(subscription.Proof.VerificationMethod, subscription.Holder) {
+		return fmt.Errorf("status subscription must be signed by the holder")
+	}
+	signature, err := did.DecodeSignatureMultibase(subscription.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+	payload, err := vc.StatusSubscriptionSigningInput(subscription)
+	if err != nil {
+		return err
+	}
+	if err := uc.didService.VerifyWithDID(subscription.Holder, subscription.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("invalid status subscription signature: %w", err)
+	}
+
+	// A replayed older subscription must not undo a newer one
+	key := subscriptionKey(subscription.Issuer, subscription.Holder)
+	var current vc.StatusSubscription
+	err = storage.GetJSON(uc.store, key, &current)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to load status subscription: %w", err)
+	}
+	if err == nil && !subscription.Created.After(current.Created) {
+		return fmt.Errorf("status subscription is older than the current one")
+	}
+
+	if err := storage.SetJSON(uc.store, key, subscription, 0); err != nil {
+		return fmt.Errorf("failed to store status subscription: %w", err)
+	}
+	return nil
+}
+
+// GetStatusSubscription returns the holder's current subscription to this issuer's notifications
+func (uc *UseCase) GetStatusSubscription(issuerDID, holderDID string) (*vc.StatusSubscription, error) {
+	var subscription vc.StatusSubscription
+	err := storage.GetJSON(uc.store, subscriptionKey(issuerDID, holderDID), &subscription)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("holder %s has no status subscription", holderDID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// notifyStatusChange signs a notification of the change for the credential's holder, passes it to
+// the handlers and delivers it to the holder's endpoint in the background
+func (uc *UseCase) notifyStatusChange(credentialID string, event vc.StatusEvent, reason vc.StatusReason, effective time.Time) (*vc.StatusNotification, error) {
+	credential, err := uc.loadIssued(credentialID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issued credential: %w", err)
+	}
+	holderDID, _ := credential.CredentialSubject["id"].(string)
+	if holderDID == "" {
+		return nil, nil
+	}
+
+	doc, err := uc.didService.ResolveDID(credential.Issuer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+	if len(doc.AssertionMethod) == 0 {
+		return nil, fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	now := uc.clock.Now().UTC()
+	notification := &vc.StatusNotification{
+		ID:            "urn:uuid:" + uuid.New().String(),
+		CredentialID:  credentialID,
+		Issuer:        credential.Issuer(),
+		Holder:        holderDID,
+		Event:         event,
+		Reason:        reason,
+		EffectiveDate: effective,
+		Created:       now,
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            now,
+			VerificationMethod: doc.AssertionMethod[0],
+			ProofPurpose:       "assertionMethod",
+		},
+	}
+
+	payload, err := vc.StatusNotificationSigningInput(notification)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := uc.didService.SignWithDID(notification.Proof.VerificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign status notification: %w", err)
+	}
+	notification.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	uc.notificationsMu.Lock()
+	notifier := uc.notifier
+	handlers := append([]func(vc.StatusNotification){}, uc.notificationHandlers...)
+	uc.notificationsMu.Unlock()
+
+	for _, handler := range handlers {
+		handler(*notification)
+	}
+
+	if notifier != nil {
+		subscription, err := uc.GetStatusSubscription(notification.Issuer, holderDID)
+		if err == nil {
+			go func() {
+				if err := notifier.Notify(context.Background(), subscription.Endpoint, notification); err != nil {
+					log.Printf("failed to notify holder %s of status change to credential %s: %v", holderDID, credentialID, err)
+				}
+			}()
+		}
+	}
+
+	return notification, nil
+}
+
+func subscriptionKey(issuerDID, holderDID string) string {
+	return subscriptionKeyPrefix + issuerDID + "|" + holderDID
+}
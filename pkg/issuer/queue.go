@@ -0,0 +1,317 @@
+package issuer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+const (
+	// DefaultIssuanceWorkers is how many workers process queued issuance jobs
+	DefaultIssuanceWorkers = 2
+	// DefaultIssuanceQueueSize is how many jobs the in-process issuance queue holds
+	DefaultIssuanceQueueSize = 1024
+	// DefaultIssuanceAttempts is how many times a job is attempted before it fails
+	DefaultIssuanceAttempts = 5
+	// DefaultIssuanceBackoff is the wait before a job's first retry, which doubles for each
+	// further retry up to maxIssuanceBackoff
+	DefaultIssuanceBackoff = time.Second
+	maxIssuanceBackoff     = time.Minute
+	// issuanceJobRetention is how long a job is kept after its last change
+	issuanceJobRetention = 24 * time.Hour
+	// issuanceClaimTTL bounds how long a worker that stopped mid-attempt keeps a job claimed
+	issuanceClaimTTL = 5 * time.Minute
+)
+
+// ErrIssuanceJobNotFound is returned for an unknown or expired issuance job
+var ErrIssuanceJobNotFound = errors.New("issuance job not found")
+
+// IssuanceJobStatus represents the state of a queued issuance
+type IssuanceJobStatus string
+
+const (
+	IssuanceQueued    IssuanceJobStatus = "queued"
+	IssuanceRunning   IssuanceJobStatus = "running"
+	IssuanceRetrying  IssuanceJobStatus = "retrying"
+	IssuanceCompleted IssuanceJobStatus = "completed"
+	IssuanceFailed    IssuanceJobStatus = "failed"
+)
+
+// IssuanceJob tracks an issuance request processed in the background. A job that failed for a
+// transient reason is retried with backoff until it succeeds or runs out of attempts.
+type IssuanceJob struct {
+	ID      string                 `json:"id"`
+	Status  IssuanceJobStatus      `json:"status"`
+	Request IssueCredentialRequest `json:"request"`
+	// Attempts counts the attempts made so far
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	// Error is why the last attempt failed
+	Error        string                   `json:"error,omitempty"`
+	CredentialID string                   `json:"credentialId,omitempty"`
+	Credential   *vc.VerifiableCredential `json:"credential,omitempty"`
+	CreatedAt    time.Time                `json:"createdAt"`
+	UpdatedAt    time.Time                `json:"updatedAt"`
+	FinishedAt   *time.Time               `json:"finishedAt,omitempty"`
+}
+
+// Done reports whether the job completed or failed for good
+func (j *IssuanceJob) Done() bool {
+	return j.Status == IssuanceCompleted || j.Status == IssuanceFailed
+}
+
+// IsTransient reports whether an issuance failed for a reason trying again may recover from: the
+// key store signing the credential, such as a remote KMS, or a DID document could not be reached
+func IsTransient(err error) bool {
+	return errors.Is(err, bbs.ErrSignerUnavailable) || did.IsTransient(err)
+}
+
+// SetIssuanceQueue sets the queue issuance jobs are handed to workers through. A
+// storage.RedisQueue over the store the issuer keeps its state in lets the workers of every
+// server instance run any instance's jobs. A nil queue restores the in-process default. It must
+// be called before the use case serves requests.
+func (uc *UseCase) SetIssuanceQueue(queue storage.Queue) {
+	if queue == nil {
+		queue = storage.NewMemoryQueue(DefaultIssuanceQueueSize)
+	}
+	uc.issuanceQueue = queue
+}
+
+// SetIssuanceRetries sets how many times a job is attempted and the wait before its first retry,
+// which doubles for each further retry
+func (uc *UseCase) SetIssuanceRetries(attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		return fmt.Errorf("issuance attempts must be at least 1")
+	}
+	if backoff < 0 {
+		return fmt.Errorf("retry backoff cannot be negative")
+	}
+
+	uc.issuanceMu.Lock()
+	defer uc.issuanceMu.Unlock()
+
+	uc.issuanceAttempts = attempts
+	uc.issuanceBackoff = backoff
+	return nil
+}
+
+// EnqueueIssuance validates an issuance request and queues it, returning the job to poll with
+// GetIssuanceJob. Bulk issuance and issuance with a slow KMS then outlast no HTTP request.
+func (uc *UseCase) EnqueueIssuance(ctx context.Context, req IssueCredentialRequest) (*IssuanceJob, error) {
+	if _, err := validateIssueRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Queued requests are issued without review
+	if uc.ApprovalRequired() {
+		return nil, fmt.Errorf("queued issuance is unavailable while issuance requires approval")
+	}
+
+	if _, err := uc.getIssuer(req.IssuerDID); err != nil {
+		return nil, err
+	}
+
+	// Jobs are retried on timers, so the queue dates them by the system clock rather than uc.clock
+	now := time.Now()
+	job := &IssuanceJob{
+		ID:        uuid.New().String(),
+		Status:    IssuanceQueued,
+		Request:   req,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.saveIssuanceJob(job); err != nil {
+		return nil, err
+	}
+
+	if err := uc.issuanceQueue.Push(ctx, job.ID); err != nil {
+		uc.store.Delete(issuanceJobKeyPrefix + job.ID)
+		return nil, fmt.Errorf("failed to queue issuance: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetIssuanceJob returns a queued issuance's progress, with the credential once it is issued
+func (uc *UseCase) GetIssuanceJob(id string) (*IssuanceJob, error) {
+	var job IssuanceJob
+	err := storage.GetJSON(uc.store, issuanceJobKeyPrefix+id, &job)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrIssuanceJobNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issuance job: %w", err)
+	}
+	return &job, nil
+}
+
+// RunIssuanceQueue processes queued issuance jobs with the given number of workers until the
+// context is cancelled. Jobs left queued or awaiting a retry by an earlier run are queued again.
+// A job interrupted mid-attempt stays running, since its credential may have been issued.
+func (uc *UseCase) RunIssuanceQueue(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if err := uc.requeueIssuanceJobs(ctx); err != nil {
+		log.Printf("failed to requeue issuance jobs: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, err := uc.issuanceQueue.Pop(ctx)
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					log.Printf("failed to take an issuance job: %v", err)
+					time.Sleep(time.Second)
+					continue
+				}
+				uc.processIssuanceJob(ctx, id)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// processIssuanceJob makes one attempt at a job. The job is claimed first, so a job queued twice,
+// e.g. by two instances requeueing it, is attempted by one worker at a time. A retry is queued
+// once the claim is released.
+func (uc *UseCase) processIssuanceJob(ctx context.Context, id string) {
+	claimed, err := uc.store.SetNX(issuanceClaimKeyPrefix+id, []byte{1}, issuanceClaimTTL)
+	if err != nil {
+		log.Printf("failed to claim issuance job %s: %v", id, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	wait, retry := uc.attemptIssuanceJob(ctx, id)
+	uc.store.Delete(issuanceClaimKeyPrefix + id)
+	if retry {
+		time.AfterFunc(wait, func() {
+			if err := uc.issuanceQueue.Push(context.Background(), id); err != nil {
+				log.Printf("failed to requeue issuance job %s: %v", id, err)
+			}
+		})
+	}
+}
+
+// attemptIssuanceJob issues a claimed job's credential, reporting whether and when the job should
+// be queued again
+func (uc *UseCase) attemptIssuanceJob(ctx context.Context, id string) (time.Duration, bool) {
+	job, err := uc.GetIssuanceJob(id)
+	if err != nil {
+		log.Printf("failed to process issuance job %s: %v", id, err)
+		return 0, false
+	}
+	if job.Status != IssuanceQueued && job.Status != IssuanceRetrying {
+		return 0, false
+	}
+
+	// A requeued job waiting for a retry is put back until its retry is due
+	if job.NextAttemptAt != nil {
+		if wait := time.Until(*job.NextAttemptAt); wait > 0 {
+			return wait, true
+		}
+	}
+
+	uc.issuanceMu.Lock()
+	attempts, backoff := uc.issuanceAttempts, uc.issuanceBackoff
+	uc.issuanceMu.Unlock()
+
+	job.Status = IssuanceRunning
+	job.Attempts++
+	job.NextAttemptAt = nil
+	job.UpdatedAt = time.Now()
+	if err := uc.saveIssuanceJob(job); err != nil {
+		log.Printf("failed to process issuance job %s: %v", id, err)
+		return 0, false
+	}
+
+	credential, err := uc.IssueCredentialContext(ctx, job.Request)
+	now := time.Now()
+	job.UpdatedAt = now
+	var wait time.Duration
+	switch {
+	case err == nil:
+		job.Status = IssuanceCompleted
+		job.Error = ""
+		job.CredentialID = credential.ID
+		job.Credential = credential
+		job.FinishedAt = &now
+	case IsTransient(err) && job.Attempts < attempts:
+		wait = issuanceBackoff(backoff, job.Attempts)
+		next := now.Add(wait)
+		job.Status = IssuanceRetrying
+		job.Error = err.Error()
+		job.NextAttemptAt = &next
+	default:
+		job.Status = IssuanceFailed
+		job.Error = err.Error()
+		job.FinishedAt = &now
+	}
+
+	if err := uc.saveIssuanceJob(job); err != nil {
+		log.Printf("failed to record issuance job %s: %v", id, err)
+		return 0, false
+	}
+	return wait, job.Status == IssuanceRetrying
+}
+
+// requeueIssuanceJobs queues the stored jobs that are still waiting for an attempt
+func (uc *UseCase) requeueIssuanceJobs(ctx context.Context) error {
+	keys, err := uc.store.Keys(issuanceJobKeyPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list issuance jobs: %w", err)
+	}
+
+	for _, key := range keys {
+		job, err := uc.GetIssuanceJob(key[len(issuanceJobKeyPrefix):])
+		if errors.Is(err, ErrIssuanceJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if job.Status == IssuanceQueued || job.Status == IssuanceRetrying {
+			if err := uc.issuanceQueue.Push(ctx, job.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveIssuanceJob stores a job, keeping it for issuanceJobRetention after this change
+func (uc *UseCase) saveIssuanceJob(job *IssuanceJob) error {
+	if err := storage.SetJSON(uc.store, issuanceJobKeyPrefix+job.ID, job, issuanceJobRetention); err != nil {
+		return fmt.Errorf("failed to store issuance job: %w", err)
+	}
+	return nil
+}
+
+// issuanceBackoff returns the wait before the retry following the given attempt
+func issuanceBackoff(backoff time.Duration, attempt int) time.Duration {
+	wait := backoff
+	for i := 1; i < attempt && wait < maxIssuanceBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxIssuanceBackoff)
+}
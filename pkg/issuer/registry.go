@@ -0,0 +1,168 @@
+package issuer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// IssuedCredential is the issuer's record of a credential it issued
+type IssuedCredential struct {
+	Credential *vc.VerifiableCredential
+	// Status is the credential's current revocation and suspension state, or nil if it has no
+	// status entries
+	Status *CredentialStatus
+}
+
+// IssuedCredentialFilter selects issued credentials. Empty fields match every credential.
+type IssuedCredentialFilter struct {
+	IssuerDID  string
+	SubjectDID string
+	// Type matches credentials that have the type among their types
+	Type string
+	// IssuedAfter and IssuedBefore bound the issuance time, inclusively
+	IssuedAfter  *time.Time
+	IssuedBefore *time.Time
+}
+
+// matches reports whether a credential satisfies the filter
+func (f IssuedCredentialFilter) matches(credential *vc.VerifiableCredential) bool {
+	if f.IssuerDID != "" && credential.Issuer() != f.IssuerDID {
+		return false
+	}
+	if f.SubjectDID != "" {
+		if subject, _ := credential.CredentialSubject["id"].(string); subject != f.SubjectDID {
+			return false
+		}
+	}
+	if f.Type != "" && !hasType(credential, f.Type) {
+		return false
+	}
+
+	issuedAt := credential.IssuedAt()
+	if f.IssuedAfter != nil && issuedAt.Before(*f.IssuedAfter) {
+		return false
+	}
+	if f.IssuedBefore != nil && issuedAt.After(*f.IssuedBefore) {
+		return false
+	}
+	return true
+}
+
+func hasType(credential *vc.VerifiableCredential, credentialType string) bool {
+	for _, candidate := range credential.Type {
+		if candidate == credentialType {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrDuplicateCredential is returned when a credential with a content-addressed ID was already
+// issued and the request does not ask to reissue it
+var ErrDuplicateCredential = errors.New("credential already issued")
+
+// assignContentID gives a newly issued credential its content-addressed ID and returns the
+// credential issued before under that ID, if reissuing replaces one
+func (uc *UseCase) assignContentID(credential *vc.VerifiableCredential, reissue bool) (*vc.VerifiableCredential, error) {
+	id, err := vc.ContentID(credential)
+	if err != nil {
+		return nil, err
+	}
+	credential.ID = id
+
+	previous, err := uc.loadIssued(id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issued credential: %w", err)
+	}
+	if !reissue {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateCredential, id)
+	}
+	return previous, nil
+}
+
+// revokeSuperseded revokes the earlier copy of a reissued credential, so only the replacement
+// verifies. Copies issued without status entries cannot be revoked and stay valid until they expire.
+func (uc *UseCase) revokeSuperseded(previous *vc.VerifiableCredential) error {
+	if uc.statusRegistry == nil {
+		return nil
+	}
+
+	for i := range previous.CredentialStatus {
+		entry := &previous.CredentialStatus[i]
+		if entry.StatusPurpose != status.PurposeRevocation {
+			continue
+		}
+		if err := uc.statusRegistry.SetStatus(entry, true); err != nil {
+			return fmt.Errorf("failed to revoke superseded credential: %w", err)
+		}
+		if err := uc.anchorStatusLists(previous.CredentialStatus[i : i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordIssued adds a newly issued credential to the issuance registry
+func (uc *UseCase) recordIssued(credential *vc.VerifiableCredential) error {
+	return uc.saveIssued(credential)
+}
+
+// ListIssuedCredentials returns the credentials this service issued that match the filter, in
+// issuance order, for revocation management and audits
+func (uc *UseCase) ListIssuedCredentials(filter IssuedCredentialFilter) ([]*IssuedCredential, error) {
+	if filter.IssuerDID == "" {
+		return nil, fmt.Errorf("issuer DID is required")
+	}
+	if filter.IssuedAfter != nil && filter.IssuedBefore != nil && filter.IssuedBefore.Before(*filter.IssuedAfter) {
+		return nil, fmt.Errorf("issuedBefore is before issuedAfter")
+	}
+
+	stored, err := uc.storedIssued()
+	if err != nil {
+		return nil, err
+	}
+	var credentials []*vc.VerifiableCredential
+	for _, credential := range stored {
+		if filter.matches(credential) {
+			credentials = append(credentials, credential)
+		}
+	}
+
+	issued := make([]*IssuedCredential, 0, len(credentials))
+	for _, credential := range credentials {
+		issued = append(issued, uc.issuedCredential(credential))
+	}
+	return issued, nil
+}
+
+// GetIssuedCredential returns the record of a credential this service issued
+func (uc *UseCase) GetIssuedCredential(credentialID string) (*IssuedCredential, error) {
+	credential, err := uc.loadIssued(credentialID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("issued credential not found: %s", credentialID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load issued credential: %w", err)
+	}
+
+	return uc.issuedCredential(credential), nil
+}
+
+// issuedCredential pairs a registered credential with its current status
+func (uc *UseCase) issuedCredential(credential *vc.VerifiableCredential) *IssuedCredential {
+	issued := &IssuedCredential{Credential: credential}
+	if len(credential.CredentialStatus) > 0 && uc.statusRegistry != nil {
+		if credentialStatus, err := uc.GetCredentialStatus(credential.ID); err == nil {
+			issued.Status = credentialStatus
+		}
+	}
+	return issued
+}
@@ -0,0 +1,237 @@
+package issuer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// CredentialStatus describes the current revocation and suspension state of an issued credential
+type CredentialStatus struct {
+	CredentialID string
+	IssuerDID    string
+	Revoked      bool
+	Suspended    bool
+	Entries      []status.Entry
+}
+
+// SetStatusRegistry enables status lists; credentials issued afterwards carry revocation and suspension entries
+func (uc *UseCase) SetStatusRegistry(registry status.Registry) {
+	uc.statusRegistry = registry
+}
+
+// GetStatusList returns the publishable status list credential with the given ID
+func (uc *UseCase) GetStatusList(listID string) (*status.ListCredential, error) {
+	if uc.statusRegistry == nil {
+		return nil, fmt.Errorf("status lists are not enabled")
+	}
+
+	return uc.statusRegistry.GetListCredential(listID)
+}
+
+// GetCredentialStatus returns the revocation and suspension state of an issued credential
+func (uc *UseCase) GetCredentialStatus(credentialID string) (*CredentialStatus, error) {
+	entries, issuerDID, err := uc.statusEntries(credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CredentialStatus{
+		CredentialID: credentialID,
+		IssuerDID:    issuerDID,
+		Entries:      entries,
+	}
+
+	for i := range entries {
+		set, err := uc.statusRegistry.GetStatus(&entries[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential status: %w", err)
+		}
+
+		switch entries[i].StatusPurpose {
+		case status.PurposeRevocation:
+			result.Revoked = set
+		case status.PurposeSuspension:
+			result.Suspended = set
+		}
+	}
+
+	return result, nil
+}
+
+// IssueStatusSnapshots signs the current state of each of a credential's status entries.
+// The holder embeds the snapshots in presentations so verifiers can check status offline.
+func (uc *UseCase) IssueStatusSnapshots(credentialID string) ([]*vc.StatusSnapshot, error) {
+	entries, issuerDID, err := uc.statusEntries(credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := uc.didService.ResolveDID(issuerDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+	if len(doc.AssertionMethod) == 0 {
+		return nil, fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	now := uc.clock.Now()
+	snapshots := make([]*vc.StatusSnapshot, 0, len(entries))
+	for i := range entries {
+		set, err := uc.statusRegistry.GetStatus(&entries[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential status: %w", err)
+		}
+
+		snapshot := &vc.StatusSnapshot{
+			ID:                   "urn:uuid:" + uuid.New().String(),
+			Type:                 []string{"StatusSnapshot"},
+			Issuer:               issuerDID,
+			StatusPurpose:        entries[i].StatusPurpose,
+			StatusListIndex:      entries[i].StatusListIndex,
+			StatusListCredential: entries[i].StatusListCredential,
+			Status:               set,
+			Timestamp:            now,
+			Proof: &vc.Proof{
+				Type:               "Ed25519Signature2020",
+				Created:            now,
+				VerificationMethod: doc.AssertionMethod[0],
+				ProofPurpose:       "assertionMethod",
+			},
+		}
+
+		payload, err := vc.StatusSnapshotSigningInput(snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		signature, err := uc.didService.SignWithDID(snapshot.Proof.VerificationMethod, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign status snapshot: %w", err)
+		}
+		snapshot.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// RevokeCredential permanently invalidates an issued credential and notifies its holder
+func (uc *UseCase) RevokeCredential(credentialID string, change StatusChange) (*CredentialStatus, error) {
+	return uc.changeStatus(credentialID, vc.StatusEventRevoked, change, func(*CredentialStatus) error {
+		return uc.setStatus(credentialID, status.PurposeRevocation, true)
+	})
+}
+
+// SuspendCredential temporarily invalidates an issued credential until it is unsuspended, and
+// notifies its holder
+func (uc *UseCase) SuspendCredential(credentialID string, change StatusChange) (*CredentialStatus, error) {
+	return uc.changeStatus(credentialID, vc.StatusEventSuspended, change, func(current *CredentialStatus) error {
+		if current.Revoked {
+			return fmt.Errorf("credential %s is revoked and cannot be suspended", credentialID)
+		}
+		return uc.setStatus(credentialID, status.PurposeSuspension, true)
+	})
+}
+
+// UnsuspendCredential reinstates a suspended credential and notifies its holder
+func (uc *UseCase) UnsuspendCredential(credentialID string, change StatusChange) (*CredentialStatus, error) {
+	return uc.changeStatus(credentialID, vc.StatusEventReinstated, change, func(current *CredentialStatus) error {
+		if current.Revoked {
+			return fmt.Errorf("credential %s is revoked and cannot be reinstated", credentialID)
+		}
+		if !current.Suspended {
+			return fmt.Errorf("credential %s is not suspended", credentialID)
+		}
+		return uc.setStatus(credentialID, status.PurposeSuspension, false)
+	})
+}
+
+// changeStatus validates the change, applies it and notifies the holder. The status list has
+// changed by the time notification fails, so such failures are logged rather than returned.
+func (uc *UseCase) changeStatus(credentialID string, event vc.StatusEvent, change StatusChange, apply func(*CredentialStatus) error) (*CredentialStatus, error) {
+	reason, effective, err := change.resolve(event, uc.clock.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := uc.GetCredentialStatus(credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(current); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.notifyStatusChange(credentialID, event, reason, effective); err != nil {
+		log.Printf("failed to notify the holder of credential %s: %v", credentialID, err)
+	}
+
+	return uc.GetCredentialStatus(credentialID)
+}
+
+// assignStatus allocates revocation and suspension entries for a newly issued credential
+func (uc *UseCase) assignStatus(credential *vc.VerifiableCredential) error {
+	var entries []status.Entry
+	for _, purpose := range []status.Purpose{status.PurposeRevocation, status.PurposeSuspension} {
+		entry, err := uc.statusRegistry.Allocate(credential.Issuer(), purpose)
+		if err != nil {
+			return fmt.Errorf("failed to allocate %s status: %w", purpose, err)
+		}
+		entries = append(entries, *entry)
+	}
+
+	credential.CredentialStatus = entries
+
+	return uc.saveStatus(&CredentialStatus{
+		CredentialID: credential.ID,
+		IssuerDID:    credential.Issuer(),
+		Entries:      entries,
+	})
+}
+
+// setStatus sets or clears the credential's bit in the status list for the purpose
+func (uc *UseCase) setStatus(credentialID string, purpose status.Purpose, value bool) error {
+	entries, _, err := uc.statusEntries(credentialID)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		if entries[i].StatusPurpose == purpose {
+			if err := uc.statusRegistry.SetStatus(&entries[i], value); err != nil {
+				return fmt.Errorf("failed to update credential status: %w", err)
+			}
+			// Re-anchor the changed list so verifiers checking anchors see the new state
+			return uc.anchorStatusLists(entries[i : i+1])
+		}
+	}
+
+	return fmt.Errorf("credential %s has no %s status entry", credentialID, purpose)
+}
+
+// statusEntries returns the status entries and issuer of a credential this issuer tracks
+func (uc *UseCase) statusEntries(credentialID string) ([]status.Entry, string, error) {
+	if uc.statusRegistry == nil {
+		return nil, "", fmt.Errorf("status lists are not enabled")
+	}
+
+	issued, err := uc.loadStatus(credentialID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, "", fmt.Errorf("no status tracked for credential: %s", credentialID)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load credential status: %w", err)
+	}
+
+	return issued.Entries, issued.IssuerDID, nil
+}
@@ -0,0 +1,197 @@
+package issuer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Store key prefixes for the issuer's state
+const (
+	issuerKeyPrefix      = "issuer:"
+	statusKeyPrefix      = "status:"
+	issuedKeyPrefix      = "issued:"
+	issuedOrderKeyPrefix = "issued-order:"
+	// amendedKeyPrefix keys the ID of a credential's next version by the credential's ID
+	amendedKeyPrefix = "amended:"
+	// issuanceJobKeyPrefix keys queued issuance jobs by ID; issuanceClaimKeyPrefix keys the
+	// claim of the worker attempting one
+	issuanceJobKeyPrefix   = "issuance-job:"
+	issuanceClaimKeyPrefix = "issuance-claim:"
+)
+
+// storedIssuer is the stored form of an IssuerSetup; the DID key pair is encoded with did.EncodeKeyPair
+// so its key agreement key survives
+type storedIssuer struct {
+	DID        did.DID          `json:"did"`
+	DIDDoc     *did.DIDDocument `json:"didDocument"`
+	KeyPair    json.RawMessage  `json:"keyPair"`
+	BBSKeyPair *bbs.KeyPair     `json:"bbsKeyPair"`
+	BBSKeyID   string           `json:"bbsKeyId"`
+}
+
+// SetStore keeps the issuer setups, issued credentials and their status entries in store, so every
+// server instance sharing it serves the same issuers. A nil store restores the in-memory default.
+// It must be called before the use case serves requests.
+func (uc *UseCase) SetStore(store storage.KVStore) {
+	if store == nil {
+		store = storage.NewMemoryStore()
+	}
+	uc.store = store
+}
+
+// saveIssuer stores an issuer setup under its DID
+func (uc *UseCase) saveIssuer(setup *IssuerSetup) error {
+	keyPair, err := did.EncodeKeyPair(setup.KeyPair)
+	if err != nil {
+		return fmt.Errorf("failed to encode issuer key pair: %w", err)
+	}
+
+	stored := &storedIssuer{
+		DID:        *setup.DID,
+		DIDDoc:     setup.DIDDoc,
+		KeyPair:    keyPair,
+		BBSKeyPair: setup.BBSKeyPair,
+		BBSKeyID:   setup.BBSKeyID,
+	}
+	if err := storage.SetJSON(uc.store, issuerKeyPrefix+setup.DID.String(), stored, 0); err != nil {
+		return fmt.Errorf("failed to store issuer: %w", err)
+	}
+	return nil
+}
+
+// loadIssuer loads a stored issuer setup, returning storage.ErrNotFound for an unknown DID
+func (uc *UseCase) loadIssuer(issuerDID string) (*IssuerSetup, error) {
+	var stored storedIssuer
+	if err := storage.GetJSON(uc.store, issuerKeyPrefix+issuerDID, &stored); err != nil {
+		return nil, err
+	}
+
+	keyPair, err := did.DecodeKeyPair(stored.KeyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode issuer key pair: %w", err)
+	}
+
+	return &IssuerSetup{
+		DID:        &stored.DID,
+		DIDDoc:     stored.DIDDoc,
+		KeyPair:    keyPair,
+		BBSKeyPair: stored.BBSKeyPair,
+		BBSKeyID:   stored.BBSKeyID,
+	}, nil
+}
+
+// storedIssuerDIDs lists the DIDs of the set up issuers in order
+func (uc *UseCase) storedIssuerDIDs() ([]string, error) {
+	keys, err := uc.store.Keys(issuerKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issuers: %w", err)
+	}
+
+	dids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		dids = append(dids, strings.TrimPrefix(key, issuerKeyPrefix))
+	}
+	return dids, nil
+}
+
+// saveStatus records the status entries assigned to a credential
+func (uc *UseCase) saveStatus(credentialStatus *CredentialStatus) error {
+	if err := storage.SetJSON(uc.store, statusKeyPrefix+credentialStatus.CredentialID, credentialStatus, 0); err != nil {
+		return fmt.Errorf("failed to store credential status: %w", err)
+	}
+	return nil
+}
+
+// loadStatus loads the status entries assigned to a credential, returning storage.ErrNotFound for an
+// untracked credential
+func (uc *UseCase) loadStatus(credentialID string) (*CredentialStatus, error) {
+	var credentialStatus CredentialStatus
+	if err := storage.GetJSON(uc.store, statusKeyPrefix+credentialID, &credentialStatus); err != nil {
+		return nil, err
+	}
+	return &credentialStatus, nil
+}
+
+// storedStatuses returns the status entries of every tracked credential
+func (uc *UseCase) storedStatuses() ([]*CredentialStatus, error) {
+	keys, err := uc.store.Keys(statusKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credential statuses: %w", err)
+	}
+
+	statuses := make([]*CredentialStatus, 0, len(keys))
+	for _, key := range keys {
+		credentialStatus, err := uc.loadStatus(strings.TrimPrefix(key, statusKeyPrefix))
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load credential status: %w", err)
+		}
+		statuses = append(statuses, credentialStatus)
+	}
+	return statuses, nil
+}
+
+// saveIssued stores an issued credential, adding an order key the first time it is recorded so
+// listings keep issuance order across instances
+func (uc *UseCase) saveIssued(credential *vc.VerifiableCredential) error {
+	_, err := uc.store.Get(issuedKeyPrefix + credential.ID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to load issued credential: %w", err)
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		// Ordered by the system clock, which unlike an injected clock never stands still
+		orderKey := fmt.Sprintf("%s%020d:%s", issuedOrderKeyPrefix, time.Now().UnixNano(), credential.ID)
+		if err := uc.store.Set(orderKey, []byte(credential.ID), 0); err != nil {
+			return fmt.Errorf("failed to store issued credential: %w", err)
+		}
+	}
+
+	if err := storage.SetJSON(uc.store, issuedKeyPrefix+credential.ID, credential, 0); err != nil {
+		return fmt.Errorf("failed to store issued credential: %w", err)
+	}
+	return nil
+}
+
+// loadIssued loads an issued credential, returning storage.ErrNotFound for an unknown ID
+func (uc *UseCase) loadIssued(credentialID string) (*vc.VerifiableCredential, error) {
+	var credential vc.VerifiableCredential
+	if err := storage.GetJSON(uc.store, issuedKeyPrefix+credentialID, &credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// storedIssued returns every issued credential in issuance order
+func (uc *UseCase) storedIssued() ([]*vc.VerifiableCredential, error) {
+	keys, err := uc.store.Keys(issuedOrderKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issued credentials: %w", err)
+	}
+
+	credentials := make([]*vc.VerifiableCredential, 0, len(keys))
+	for _, key := range keys {
+		id := strings.TrimPrefix(key, issuedOrderKeyPrefix)
+		if i := strings.IndexByte(id, ':'); i >= 0 {
+			id = id[i+1:]
+		}
+		credential, err := uc.loadIssued(id)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load issued credential: %w", err)
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
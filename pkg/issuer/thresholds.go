@@ -0,0 +1,196 @@
+package issuer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ThresholdRegistration is a threshold an issuer evaluates into addendum credentials, with the
+// verifiers that asked for it
+type ThresholdRegistration struct {
+	IssuerDID    string                 `json:"issuerDid"`
+	Definition   vc.ThresholdDefinition `json:"definition"`
+	RequestedBy  []string               `json:"requestedBy,omitempty"`
+	RegisteredAt time.Time              `json:"registeredAt"`
+}
+
+// SetDatePolicy sets the timezone date-of-birth thresholds are evaluated in
+func (uc *UseCase) SetDatePolicy(policy vc.DatePolicy) {
+	uc.thresholdsMu.Lock()
+	defer uc.thresholdsMu.Unlock()
+	uc.datePolicy = policy
+}
+
+// RegisterThreshold records a threshold a verifier needs, e.g. ageOver17 or salaryOver50k.
+// Verifiers asking for an already registered threshold are added to its requesters; a different
+// definition under the same name is rejected.
+func (uc *UseCase) RegisterThreshold(issuerDID, verifierDID string, definition vc.ThresholdDefinition) (*ThresholdRegistration, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+	issuerDID = setup.DID.String()
+
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+
+	uc.thresholdsMu.Lock()
+	defer uc.thresholdsMu.Unlock()
+
+	if uc.thresholds[issuerDID] == nil {
+		uc.thresholds[issuerDID] = make(map[string]*ThresholdRegistration)
+	}
+
+	registration, exists := uc.thresholds[issuerDID][definition.Name]
+	if !exists {
+		registration = &ThresholdRegistration{
+			IssuerDID:    issuerDID,
+			Definition:   definition,
+			RegisteredAt: uc.clock.Now(),
+		}
+		uc.thresholds[issuerDID][definition.Name] = registration
+	} else if registration.Definition != definition {
+		return nil, fmt.Errorf("threshold %s is already registered with a different definition", definition.Name)
+	}
+
+	if verifierDID != "" && !containsIssuer(registration.RequestedBy, verifierDID) {
+		registration.RequestedBy = append(registration.RequestedBy, verifierDID)
+	}
+
+	result := *registration
+	result.RequestedBy = append([]string{}, registration.RequestedBy...)
+	return &result, nil
+}
+
+// ListThresholds returns the thresholds registered with an issuer, ordered by name
+func (uc *UseCase) ListThresholds(issuerDID string) ([]*ThresholdRegistration, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.thresholdsMu.RLock()
+	defer uc.thresholdsMu.RUnlock()
+
+	registrations := []*ThresholdRegistration{}
+	for _, registration := range uc.thresholds[setup.DID.String()] {
+		result := *registration
+		result.RequestedBy = append([]string{}, registration.RequestedBy...)
+		registrations = append(registrations, &result)
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Definition.Name < registrations[j].Definition.Name
+	})
+
+	return registrations, nil
+}
+
+// IssueAddendum supplements a credential this issuer signed with an addendum credential holding
+// the named thresholds as boolean claims, evaluated against the credential's claims. The addendum
+// names the original in its addendumTo claim and shares its status entries, so revoking or
+// suspending either one covers both.
+func (uc *UseCase) IssueAddendum(original *vc.VerifiableCredential, thresholds []string) (*vc.VerifiableCredential, error) {
+	if original == nil {
+		return nil, fmt.Errorf("credential is nil")
+	}
+
+	if len(thresholds) == 0 {
+		return nil, fmt.Errorf("at least one threshold is required")
+	}
+
+	if _, err := uc.getIssuer(original.Issuer()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.VerifyCredential(original); err != nil {
+		return nil, fmt.Errorf("credential verification failed: %w", err)
+	}
+
+	// Only supplement credentials exactly as this issuer logged them, so altered claims are caught
+	hash, err := vc.CredentialHash(original)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.issuanceLog(original.Issuer()).Lookup(hash); err != nil {
+		return nil, fmt.Errorf("credential verification failed: not in the issuance log of %s", original.Issuer())
+	}
+
+	if len(original.CredentialStatus) > 0 {
+		current, err := uc.GetCredentialStatus(original.ID)
+		if err != nil {
+			return nil, err
+		}
+		if current.Revoked || current.Suspended {
+			return nil, fmt.Errorf("credential %s is revoked or suspended", original.ID)
+		}
+	}
+
+	subjectDID, ok := original.CredentialSubject["id"].(string)
+	if !ok || subjectDID == "" {
+		return nil, fmt.Errorf("credential has no subject DID")
+	}
+
+	definitions := make([]vc.ThresholdDefinition, len(thresholds))
+	uc.thresholdsMu.RLock()
+	for i, name := range thresholds {
+		registration, ok := uc.thresholds[original.Issuer()][name]
+		if !ok {
+			uc.thresholdsMu.RUnlock()
+			return nil, fmt.Errorf("threshold %s is not registered", name)
+		}
+		definitions[i] = registration.Definition
+	}
+	policy := uc.datePolicy
+	uc.thresholdsMu.RUnlock()
+
+	now := uc.clock.Now()
+	claims := []vc.Claim{{Key: vc.AddendumToClaim, Value: original.ID, Type: schema.ClaimTypeString}}
+	for _, definition := range definitions {
+		value, ok := original.CredentialSubject[definition.Claim]
+		if !ok {
+			return nil, fmt.Errorf("credential %s has no claim %s", original.ID, definition.Claim)
+		}
+
+		result, err := definition.EvaluateWithPolicy(value, now, policy)
+		if err != nil {
+			return nil, fmt.Errorf("threshold %s: %w", definition.Name, err)
+		}
+
+		claims = append(claims, vc.Claim{Key: definition.Name, Value: result, Type: schema.ClaimTypeBoolean})
+	}
+
+	addendum, err := uc.vcService.IssueCredentialContext(context.Background(), original.Issuer(), subjectDID, claims, vc.IssuanceOptions{
+		Types: []string{"VerifiableCredential", vc.AddendumCredentialType},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue addendum: %w", err)
+	}
+
+	if len(original.CredentialStatus) > 0 {
+		addendum.CredentialStatus = append([]status.Entry{}, original.CredentialStatus...)
+
+		if err := uc.saveStatus(&CredentialStatus{
+			CredentialID: addendum.ID,
+			IssuerDID:    addendum.Issuer(),
+			Entries:      addendum.CredentialStatus,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := uc.logIssuance(addendum); err != nil {
+		return nil, err
+	}
+
+	if err := uc.recordIssued(addendum); err != nil {
+		return nil, err
+	}
+	return addendum, nil
+}
@@ -0,0 +1,106 @@
+package issuer
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/transparency"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// GetSignedTreeHead returns the issuer's issuance log head signed with its DID assertion key.
+// When issuerDID is empty and exactly one issuer has been set up, that issuer is used.
+func (uc *UseCase) GetSignedTreeHead(issuerDID string) (*transparency.SignedTreeHead, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(setup.DIDDoc.AssertionMethod) == 0 {
+		return nil, fmt.Errorf("issuer DID has no assertion method")
+	}
+
+	head := uc.issuanceLog(setup.DID.String()).Head()
+	payload, err := head.SigningInput()
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := setup.DIDDoc.AssertionMethod[0]
+	signature, err := uc.didService.SignWithDID(keyID, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tree head: %w", err)
+	}
+
+	return &transparency.SignedTreeHead{
+		TreeHead:  *head,
+		KeyID:     keyID,
+		Signature: did.EncodeSignatureMultibase(signature),
+	}, nil
+}
+
+// GetInclusionProof proves a credential hash is in the issuer's log at the given tree size,
+// or at the current size when treeSize is zero
+func (uc *UseCase) GetInclusionProof(issuerDID, credentialHash string, treeSize uint64) (*transparency.InclusionProof, error) {
+	setup, err := uc.getIssuer(issuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.issuanceLog(setup.DID.String()).InclusionProof(credentialHash, treeSize)
+}
+
+// VerifyInclusionProof checks a signed tree head's signature and that the proof leads to its root
+func (uc *UseCase) VerifyInclusionProof(proof *transparency.InclusionProof, head *transparency.SignedTreeHead) error {
+	if proof == nil || head == nil {
+		return fmt.Errorf("proof and signed tree head are required")
+	}
+
+	signature, err := did.DecodeSignatureMultibase(head.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid tree head signature: %w", err)
+	}
+
+	payload, err := head.TreeHead.SigningInput()
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(head.LogID, head.KeyID, payload, signature); err != nil {
+		return fmt.Errorf("tree head signature verification failed: %w", err)
+	}
+
+	if err := transparency.VerifyInclusionProof(proof, &head.TreeHead); err != nil {
+		return fmt.Errorf("inclusion proof verification failed: %w", err)
+	}
+
+	return nil
+}
+
+// logIssuance appends a newly issued credential's hash to its issuer's log
+func (uc *UseCase) logIssuance(credential *vc.VerifiableCredential) (*transparency.Entry, error) {
+	hash, err := vc.CredentialHash(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := uc.issuanceLog(credential.Issuer()).Append(hash, credential.IssuedAt())
+	if err != nil {
+		return nil, fmt.Errorf("failed to log issuance: %w", err)
+	}
+
+	return entry, nil
+}
+
+// issuanceLog returns the issuer's log, creating it on first use
+func (uc *UseCase) issuanceLog(issuerDID string) *transparency.Log {
+	uc.logsMu.Lock()
+	defer uc.logsMu.Unlock()
+
+	log, exists := uc.logs[issuerDID]
+	if !exists {
+		log = transparency.NewLog(issuerDID)
+		uc.logs[issuerDID] = log
+	}
+	return log
+}
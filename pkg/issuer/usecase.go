@@ -0,0 +1,504 @@
+package issuer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/anchor"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/blob"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/notify"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/transparency"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// UseCase represents the issuer use case
+type UseCase struct {
+	didService did.DIDService
+	vcService  vc.CredentialService
+	bbsService bbs.BBSService
+	templates  schema.Registry
+	// clock dates the issuer's records and the documents it signs, and judges their expiry
+	clock clock.Clock
+	// blobs keeps the evidence documents attached at issuance
+	blobs blob.Store
+
+	// store holds the issuer setups, issued credentials and their status entries; see SetStore.
+	// issuersMu serializes this instance's key rotations.
+	store     storage.KVStore
+	issuersMu sync.Mutex
+
+	// statusRegistry is nil unless status lists are enabled
+	statusRegistry status.Registry
+
+	// logs maps issuer DID -> append-only log of issued credential hashes
+	logsMu sync.Mutex
+	logs   map[string]*transparency.Log
+
+	// anchors is nil unless anchoring to an external backend is enabled
+	anchors *anchor.Service
+
+	// batchSlots bounds how many batch items are signed at once across all jobs
+	batchMu    sync.RWMutex
+	batchJobs  map[string]*BatchJob
+	batchSlots chan struct{}
+
+	// issuanceQueue hands queued issuance jobs to workers; issuanceAttempts and issuanceBackoff
+	// say how jobs that failed for a transient reason are retried
+	issuanceQueue    storage.Queue
+	issuanceMu       sync.Mutex
+	issuanceAttempts int
+	issuanceBackoff  time.Duration
+
+	// rotations records key rotations per issuer DID; rotationHandlers are notified of each
+	rotationsMu      sync.RWMutex
+	rotations        map[string][]*KeyRotationEvent
+	rotationHandlers []func(KeyRotationEvent)
+
+	// thresholds maps issuer DID -> threshold name -> registration
+	thresholdsMu sync.RWMutex
+	thresholds   map[string]map[string]*ThresholdRegistration
+	// datePolicy decides the day ages in thresholds are evaluated on
+	datePolicy vc.DatePolicy
+
+	// approvalRequired holds issuance requests for review; approvalHandlers are notified of
+	// every change to a held request
+	approvalsMu      sync.Mutex
+	approvalRequired bool
+	approvalHandlers []func(IssuanceApproval)
+
+	// coSigningPolicies maps issuer DID -> template ID -> policy; coSigningMu also serializes
+	// updates to signing sessions
+	coSigningMu       sync.Mutex
+	coSigningPolicies map[string]map[string]*CoSigningPolicy
+
+	// notifier delivers status notifications to subscribed holders; notificationHandlers are
+	// called with every notification
+	notificationsMu      sync.Mutex
+	notifier             notify.Notifier
+	notificationHandlers []func(vc.StatusNotification)
+
+	// domainLinks are the signed domain linkages published in the DID configuration
+	domainsMu   sync.RWMutex
+	domainLinks []*vc.DomainLinkageCredential
+
+	// events is nil unless issuances are published on an event bus
+	events *events.Bus
+}
+
+// NewUseCase creates a new issuer use case
+func NewUseCase(didService did.DIDService, vcService vc.CredentialService, bbsService bbs.BBSService) *UseCase {
+	return &UseCase{
+		didService: didService,
+		vcService:  vcService,
+		bbsService: bbsService,
+		templates:  schema.NewDefaultRegistry(),
+		clock:      clock.System{},
+		store:      storage.NewMemoryStore(),
+		blobs:      blob.NewMemoryStore(),
+
+		logs:       make(map[string]*transparency.Log),
+		batchJobs:  make(map[string]*BatchJob),
+		batchSlots: make(chan struct{}, DefaultBatchWorkers),
+		rotations:  make(map[string][]*KeyRotationEvent),
+		thresholds: make(map[string]map[string]*ThresholdRegistration),
+
+		issuanceQueue:    storage.NewMemoryQueue(DefaultIssuanceQueueSize),
+		issuanceAttempts: DefaultIssuanceAttempts,
+		issuanceBackoff:  DefaultIssuanceBackoff,
+
+		coSigningPolicies: make(map[string]map[string]*CoSigningPolicy),
+	}
+}
+
+// IssuerSetup represents the setup process for an issuer
+type IssuerSetup struct {
+	DID        *did.DID
+	DIDDoc     *did.DIDDocument
+	KeyPair    *did.KeyPair
+	BBSKeyPair *bbs.KeyPair
+	// BBSKeyID is the verification method the current BBS+ key is published under
+	BBSKeyID string
+}
+
+// SetupIssuer sets up a new issuer with DID and keys
+func (uc *UseCase) SetupIssuer(method string) (*IssuerSetup, error) {
+	// Generate DID and key pair
+	issuerDID, keyPair, err := uc.didService.GenerateDID(method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DID: %w", err)
+	}
+
+	// Create DID document
+	didDoc, err := uc.didService.CreateDIDDocument(issuerDID, keyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DID document: %w", err)
+	}
+
+	// Generate BBS+ key pair for signing credentials
+	bbsKeyPair, err := uc.bbsService.GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate BBS+ key pair: %w", err)
+	}
+
+	// Publish the BBS+ public key so verifiers can resolve the key credentials point at
+	bbsKeyID := didDoc.NextKeyID("bbs-key")
+	didDoc, err = uc.didService.AddVerificationMethod(issuerDID.String(), bbsVerificationMethod(issuerDID.String(), bbsKeyID, bbsKeyPair), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish BBS+ key: %w", err)
+	}
+
+	// Set up the issuer in the VC service
+	if err := uc.vcService.SetIssuerKey(issuerDID.String(), bbsKeyID, bbsKeyPair); err != nil {
+		return nil, err
+	}
+
+	setup := &IssuerSetup{
+		DID:        issuerDID,
+		DIDDoc:     didDoc,
+		KeyPair:    keyPair,
+		BBSKeyPair: bbsKeyPair,
+		BBSKeyID:   bbsKeyID,
+	}
+
+	if err := uc.saveIssuer(setup); err != nil {
+		return nil, err
+	}
+
+	// Anchor the new key set right away so verifiers checking anchors accept it
+	if uc.anchors != nil {
+		if _, err := uc.anchorKeySet(issuerDID.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	return setup, nil
+}
+
+// IssueCredentialRequest represents a credential issuance request
+type IssueCredentialRequest struct {
+	IssuerDID  string     `json:"issuerDid"`
+	SubjectDID string     `json:"subjectDid"`
+	Claims     []vc.Claim `json:"claims"`
+	// TemplateID optionally names a credential template the claims must conform to
+	TemplateID string `json:"templateId,omitempty"`
+	// AnonymitySet lists other issuers the holder may hide this issuer among when presenting.
+	// Such credentials carry no status entries, as checking a status list identifies its issuer.
+	AnonymitySet []string `json:"anonymitySet,omitempty"`
+	// CommitAttributes lists claims to publish issuer-signed Pedersen commitments for, so the holder
+	// can prove statements about them with external zero-knowledge systems
+	CommitAttributes []string `json:"commitAttributes,omitempty"`
+	// Version is the data model the credential is issued under; empty means 1.1
+	Version vc.Version `json:"version,omitempty"`
+	// ValidUntil optionally sets when the credential expires
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+	// IssuerName and IssuerImage optionally describe the issuer, making the credential's issuer an object
+	IssuerName  string `json:"issuerName,omitempty"`
+	IssuerImage string `json:"issuerImage,omitempty"`
+	// BindToHolder embeds the subject's authentication key as a cnf claim, so only the holder of
+	// that key can present the credential
+	BindToHolder bool `json:"bindToHolder,omitempty"`
+	// Evidence lists documents the issuer relied on, described in the credential's evidence section
+	Evidence []EvidenceDocument `json:"evidence,omitempty"`
+	// CommitClaimKeys signs a commitment to the credential's claim keys, so the holder can prove
+	// claims absent without revealing the others
+	CommitClaimKeys bool `json:"commitClaimKeys,omitempty"`
+	// Generalizations are coarser values of claims, signed as claims keyed claim.level, that the
+	// holder can reveal in place of the claim
+	Generalizations []vc.Generalization `json:"generalizations,omitempty"`
+	// ContentAddressedID derives the credential's ID from its issuer, subject and claims, so
+	// issuing the same claims to the same subject twice is detected as a duplicate
+	ContentAddressedID bool `json:"contentAddressedId,omitempty"`
+	// Reissue replaces a credential issued before with the same content-addressed ID, revoking
+	// the earlier copy
+	Reissue bool `json:"reissue,omitempty"`
+}
+
+// IssueCredential issues a new verifiable credential
+func (uc *UseCase) IssueCredential(req IssueCredentialRequest) (*vc.VerifiableCredential, error) {
+	return uc.IssueCredentialContext(context.Background(), req)
+}
+
+// IssueCredentialContext issues a credential, recording the issuance as a span under ctx
+func (uc *UseCase) IssueCredentialContext(ctx context.Context, req IssueCredentialRequest) (*vc.VerifiableCredential, error) {
+	ctx, span := tracing.Start(ctx, "issuer.IssueCredential",
+		tracing.String("issuer.did", req.IssuerDID),
+		tracing.String("template.id", req.TemplateID),
+		tracing.Int("claims.count", len(req.Claims)),
+	)
+	defer span.End()
+
+	credential, err := uc.issueCredential(ctx, req, nil, nil)
+	span.RecordError(err)
+	return credential, err
+}
+
+// validateIssueRequest checks the parts of an issuance request that do not depend on templates
+func validateIssueRequest(req IssueCredentialRequest) (vc.Version, error) {
+	if req.IssuerDID == "" {
+		return "", fmt.Errorf("issuer DID is required")
+	}
+
+	if req.SubjectDID == "" {
+		return "", fmt.Errorf("subject DID is required")
+	}
+
+	if len(req.Claims) == 0 {
+		return "", fmt.Errorf("at least one claim is required")
+	}
+
+	version, err := vc.ParseVersion(string(req.Version))
+	if err != nil {
+		return "", err
+	}
+
+	for _, claim := range req.Claims {
+		switch claim.Key {
+		case vc.ConfirmationClaim:
+			return "", fmt.Errorf("claim %s is reserved for holder binding", vc.ConfirmationClaim)
+		case vc.PreviousVersionClaim, vc.CredentialVersionClaim:
+			return "", fmt.Errorf("claim %s is reserved for credential amendments", claim.Key)
+		}
+	}
+
+	if req.Reissue && !req.ContentAddressedID {
+		return "", fmt.Errorf("only credentials with content-addressed IDs can be reissued")
+	}
+
+	if err := validateEvidence(req.Evidence); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// issueCredential issues a credential, attaching the co-signatures approving it when it was
+// issued through a signing session. When previous is set, the credential is its next version.
+func (uc *UseCase) issueCredential(ctx context.Context, req IssueCredentialRequest, coSignatures *vc.CoSignatureEnvelope, previous *vc.VerifiableCredential) (*vc.VerifiableCredential, error) {
+	version, err := validateIssueRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkCoSigningPolicy(req, coSignatures); err != nil {
+		return nil, err
+	}
+
+	var template *schema.CredentialTemplate
+	if req.TemplateID != "" {
+		var err error
+		template, err = uc.templates.Get(req.TemplateID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get template: %w", err)
+		}
+
+		keys := make([]string, len(req.Claims))
+		for i, claim := range req.Claims {
+			keys[i] = claim.Key
+		}
+		if err := validateTemplateClaims(template, keys); err != nil {
+			return nil, err
+		}
+
+		typed, err := applyTemplateTypes(template, req.Claims)
+		if err != nil {
+			return nil, err
+		}
+		req.Claims = typed
+	}
+
+	// Generalizations are signed as claims of their own, outside the template's claims
+	generalized, err := vc.GeneralizedClaims(req.Claims, req.Generalizations)
+	if err != nil {
+		return nil, err
+	}
+
+	// The confirmation claim is signed along with the others
+	claims := append(append([]vc.Claim{}, req.Claims...), generalized...)
+	if req.BindToHolder {
+		confirmation, err := uc.holderConfirmation(req.SubjectDID)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, confirmation)
+	}
+
+	// A new version names the one it amends in signed claims, so the link cannot be forged
+	if previous != nil {
+		claims = append(claims, versionClaims(previous)...)
+	}
+
+	// The types, issuer details, data model and validity period are signed with the claims; a new
+	// version keeps the types of the one it amends
+	options := vc.IssuanceOptions{
+		Types:       []string{"VerifiableCredential"},
+		IssuerName:  req.IssuerName,
+		IssuerImage: req.IssuerImage,
+		Version:     version,
+		ValidUntil:  req.ValidUntil,
+	}
+	if template != nil {
+		options.Types = append(options.Types, template.CredentialType)
+	}
+	if previous != nil {
+		options.Types = append([]string{}, previous.Type...)
+	}
+	if len(req.AnonymitySet) > 0 {
+		_, span := tracing.Start(ctx, "issuer.ResolveIssuerSet", tracing.Int("issuers.count", len(req.AnonymitySet)))
+		options.IssuerSet, err = uc.issuerSet(req.IssuerDID, req.AnonymitySet)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Issue the credential; claim values are coerced to their canonical form before signing
+	credential, err := uc.vcService.IssueCredentialContext(ctx, req.IssuerDID, req.SubjectDID, claims, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue credential: %w", err)
+	}
+
+	// Everything below binds the credential's ID, so a content-addressed one is assigned first
+	var superseded *vc.VerifiableCredential
+	if req.ContentAddressedID {
+		if superseded, err = uc.assignContentID(credential, req.Reissue); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.CommitAttributes) > 0 {
+		_, span := tracing.Start(ctx, "issuer.CommitAttributes", tracing.Int("claims.committed", len(req.CommitAttributes)))
+		err := uc.commitAttributes(credential, req.Claims, req.CommitAttributes)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The commitment covers every claim key, including cnf
+	if req.CommitClaimKeys {
+		_, span := tracing.Start(ctx, "issuer.CommitClaimKeys")
+		err := uc.commitClaimKeys(credential)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if coSignatures != nil {
+		_, span := tracing.Start(ctx, "issuer.BindCoSignatures", tracing.Int("signatures.count", len(coSignatures.Signatures)))
+		err := uc.bindCoSignatures(credential, coSignatures)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.Evidence) > 0 {
+		_, span := tracing.Start(ctx, "issuer.AttachEvidence", tracing.Int("evidence.count", len(req.Evidence)))
+		err := uc.attachEvidence(credential, req.Evidence)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Status entries would identify an issuer hidden within its set
+	if credential.IssuerSetProof == nil && uc.statusRegistry != nil {
+		_, span := tracing.Start(ctx, "issuer.AssignStatus")
+		err := uc.assignStatus(credential)
+		if err == nil {
+			err = uc.anchorStatusLists(credential.CredentialStatus)
+		}
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A sub-issuer's credentials carry the authorizations linking it to its root authority
+	credential.Authorizations, err = uc.Authorizations(req.IssuerDID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The log commits to the final credential, including its status entries
+	_, span := tracing.Start(ctx, "issuer.LogIssuance")
+	_, err = uc.logIssuance(credential)
+	span.RecordError(err)
+	span.End()
+	if err != nil {
+		return nil, err
+	}
+
+	// The earlier copy of a reissued credential is revoked only once its replacement is issued
+	if superseded != nil {
+		if err := uc.revokeSuperseded(superseded); err != nil {
+			return nil, err
+		}
+	}
+
+	// Of two amendments of the same version, only the first to link itself is recorded
+	if previous != nil {
+		if err := uc.linkVersion(previous.ID, credential.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.recordIssued(credential); err != nil {
+		return nil, err
+	}
+
+	// The previous version is revoked once the new one is recorded, so one version is always valid
+	if previous != nil {
+		if _, err := uc.RevokeCredential(previous.ID, StatusChange{Reason: vc.StatusReasonSuperseded}); err != nil {
+			return nil, fmt.Errorf("failed to revoke amended credential: %w", err)
+		}
+	}
+	uc.publish(events.CredentialIssued, events.CredentialIssuedData{Credential: credential})
+	return credential, nil
+}
+
+// EncryptCredentialForHolder encrypts an issued credential to the subject's key agreement key
+func (uc *UseCase) EncryptCredentialForHolder(credential *vc.VerifiableCredential) (*did.EncryptedEnvelope, error) {
+	if credential == nil {
+		return nil, fmt.Errorf("credential is nil")
+	}
+
+	subjectDID, ok := credential.CredentialSubject["id"].(string)
+	if !ok || subjectDID == "" {
+		return nil, fmt.Errorf("credential has no subject DID")
+	}
+
+	payload, err := json.Marshal(credential)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal credential: %w", err)
+	}
+
+	envelope, err := uc.didService.EncryptForDID(subjectDID, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt credential: %w", err)
+	}
+
+	return envelope, nil
+}
+
+// VerifyCredential verifies a verifiable credential
+func (uc *UseCase) VerifyCredential(credential *vc.VerifiableCredential) error {
+	return uc.vcService.VerifyCredential(credential)
+}
@@ -0,0 +1,380 @@
+package jsonld
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxContextDepth bounds how deeply remote contexts may reference further contexts
+const maxContextDepth = 10
+
+// term is a processed term definition
+type term struct {
+	// id is the expanded IRI or keyword; empty for a term explicitly mapped to null
+	id        string
+	typ       string
+	container string
+	// context is the raw scoped context; scoped tells a null scoped context from none
+	context   interface{}
+	scoped    bool
+	protected bool
+}
+
+// equal reports whether two definitions are the same, which is when a protected term may be redefined
+func (t *term) equal(other *term) bool {
+	return t.id == other.id &&
+		t.typ == other.typ &&
+		t.container == other.container &&
+		t.scoped == other.scoped &&
+		reflect.DeepEqual(t.context, other.context)
+}
+
+// activeContext is the set of term definitions in effect while walking a document
+type activeContext struct {
+	terms map[string]*term
+	vocab string
+	// previous is the context a type-scoped context reverts to for nested nodes
+	previous *activeContext
+}
+
+// clone copies the context so processing a local context does not change the original
+func (c *activeContext) clone() *activeContext {
+	terms := make(map[string]*term, len(c.terms))
+	for name, definition := range c.terms {
+		terms[name] = definition
+	}
+	return &activeContext{terms: terms, vocab: c.vocab, previous: c.previous}
+}
+
+// revert drops any type-scoped context before descending into a nested node
+func (c *activeContext) revert() *activeContext {
+	if c.previous != nil {
+		return c.previous
+	}
+	return c
+}
+
+// hasProtected reports whether any term in the context is protected
+func (c *activeContext) hasProtected() bool {
+	for _, definition := range c.terms {
+		if definition.protected {
+			return true
+		}
+	}
+	return false
+}
+
+// keywords are the JSON-LD keywords a document may use as keys or IRI mappings
+var keywords = map[string]bool{
+	"@base": true, "@container": true, "@context": true, "@direction": true, "@graph": true,
+	"@id": true, "@import": true, "@included": true, "@index": true, "@json": true,
+	"@language": true, "@list": true, "@nest": true, "@none": true, "@prefix": true,
+	"@propagate": true, "@protected": true, "@reverse": true, "@set": true, "@type": true,
+	"@value": true, "@version": true, "@vocab": true,
+}
+
+// processor applies local contexts to an active context, resolving remote contexts through the loader
+type processor struct {
+	validator *Validator
+}
+
+// process applies a local context (a URL, an inline definition, null, or an array of them)
+// to the active context. Type-scoped contexts are applied without propagation, so nested
+// nodes revert to the context in effect before them.
+func (p *processor) process(active *activeContext, local interface{}, propagate, overrideProtected bool, depth int) (*activeContext, error) {
+	if depth > maxContextDepth {
+		return nil, fmt.Errorf("contexts are nested more than %d deep", maxContextDepth)
+	}
+
+	result := active.clone()
+	if !propagate && result.previous == nil {
+		result.previous = active
+	}
+
+	items, ok := local.([]interface{})
+	if !ok {
+		items = []interface{}{local}
+	}
+
+	for _, item := range items {
+		switch value := item.(type) {
+		case nil:
+			if !overrideProtected && result.hasProtected() {
+				return nil, fmt.Errorf("a null context cannot clear protected terms")
+			}
+			result = &activeContext{terms: make(map[string]*term), previous: result.previous}
+			if !propagate && result.previous == nil {
+				result.previous = active
+			}
+		case string:
+			definitions, err := p.validator.definitions(value)
+			if err != nil {
+				return nil, err
+			}
+			next, err := p.process(result, definitions, true, overrideProtected, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("context %s: %w", value, err)
+			}
+			result = next
+		case map[string]interface{}:
+			if err := p.define(result, value, overrideProtected); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("a context must be a URL, an object or null, not %T", item)
+		}
+	}
+
+	return result, nil
+}
+
+// define adds the term definitions of an inline context to result
+func (p *processor) define(result *activeContext, local map[string]interface{}, overrideProtected bool) error {
+	if version, ok := local["@version"]; ok && version != 1.1 {
+		return fmt.Errorf("unsupported @version %v", version)
+	}
+	if _, ok := local["@import"]; ok {
+		return fmt.Errorf("@import is not supported")
+	}
+
+	d := &definer{
+		result:            result,
+		local:             local,
+		overrideProtected: overrideProtected,
+		defined:           make(map[string]bool),
+	}
+
+	if value, ok := local["@protected"]; ok {
+		flag, isBool := value.(bool)
+		if !isBool {
+			return fmt.Errorf("@protected must be true or false")
+		}
+		d.protected = flag
+	}
+
+	if vocab, ok := local["@vocab"]; ok {
+		switch value := vocab.(type) {
+		case nil:
+			result.vocab = ""
+		case string:
+			iri, err := d.expandIRI(value)
+			if err != nil {
+				return fmt.Errorf("@vocab: %w", err)
+			}
+			result.vocab = iri
+		default:
+			return fmt.Errorf("@vocab must be a string or null")
+		}
+	}
+
+	names := make([]string, 0, len(local))
+	for name := range local {
+		if !strings.HasPrefix(name, "@") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := d.defineTerm(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// definer creates the term definitions of one inline context
+type definer struct {
+	result            *activeContext
+	local             map[string]interface{}
+	protected         bool
+	overrideProtected bool
+	// defined is false while a term is being defined and true once it is
+	defined map[string]bool
+}
+
+// defineTerm creates the definition of one term, first defining any prefix it is built on
+func (d *definer) defineTerm(name string) error {
+	if done, ok := d.defined[name]; ok {
+		if !done {
+			return fmt.Errorf("term %s is defined in terms of itself", name)
+		}
+		return nil
+	}
+	d.defined[name] = false
+
+	definition := &term{protected: d.protected}
+	switch value := d.local[name].(type) {
+	case nil:
+	case string:
+		id, err := d.expandIRI(value)
+		if err != nil {
+			return fmt.Errorf("term %s: %w", name, err)
+		}
+		definition.id = id
+	case map[string]interface{}:
+		if err := d.expandDefinition(name, value, definition); err != nil {
+			return fmt.Errorf("term %s: %w", name, err)
+		}
+	default:
+		return fmt.Errorf("term %s must map to a string, an object or null", name)
+	}
+
+	existing, ok := d.result.terms[name]
+	if ok && existing.protected && !d.overrideProtected && !existing.equal(definition) {
+		return fmt.Errorf("protected term %s cannot be redefined", name)
+	}
+
+	d.result.terms[name] = definition
+	d.defined[name] = true
+	return nil
+}
+
+// expandDefinition fills an expanded term definition from its object form
+func (d *definer) expandDefinition(name string, value map[string]interface{}, definition *term) error {
+	if _, ok := value["@reverse"]; ok {
+		return fmt.Errorf("reverse properties are not supported")
+	}
+
+	if flag, ok := value["@protected"]; ok {
+		isProtected, isBool := flag.(bool)
+		if !isBool {
+			return fmt.Errorf("@protected must be true or false")
+		}
+		definition.protected = isProtected
+	}
+
+	if id, ok := value["@id"]; ok {
+		if id != nil {
+			idString, isString := id.(string)
+			if !isString {
+				return fmt.Errorf("@id must be a string")
+			}
+			expanded, err := d.expandIRI(idString)
+			if err != nil {
+				return err
+			}
+			definition.id = expanded
+		}
+	} else if strings.Contains(name, ":") {
+		expanded, err := d.expandIRI(name)
+		if err != nil {
+			return fmt.Errorf("no IRI mapping: %w", err)
+		}
+		definition.id = expanded
+	} else if d.result.vocab != "" {
+		definition.id = d.result.vocab + name
+	} else {
+		return fmt.Errorf("no IRI mapping and no @vocab")
+	}
+
+	if typ, ok := value["@type"]; ok {
+		typString, isString := typ.(string)
+		if !isString {
+			return fmt.Errorf("@type must be a string")
+		}
+		expanded, err := d.expandIRI(typString)
+		if err != nil {
+			return fmt.Errorf("@type: %w", err)
+		}
+		definition.typ = expanded
+	}
+
+	if container, ok := value["@container"]; ok {
+		containerString, isString := container.(string)
+		if !isString || !keywords[containerString] {
+			return fmt.Errorf("unsupported @container %v", container)
+		}
+		definition.container = containerString
+	}
+
+	if scoped, ok := value["@context"]; ok {
+		definition.context = scoped
+		definition.scoped = true
+	}
+
+	return nil
+}
+
+// expandIRI expands a term, compact IRI or IRI used while defining the context. Terms and
+// prefixes from the same context are defined first; a term being defined is not looked up
+// in terms of itself.
+func (d *definer) expandIRI(value string) (string, error) {
+	if keywords[value] {
+		return value, nil
+	}
+
+	if _, ok := d.local[value]; ok && !d.inProgress(value) {
+		if err := d.defineTerm(value); err != nil {
+			return "", err
+		}
+	}
+	if prefix, _, found := strings.Cut(value, ":"); found {
+		if _, ok := d.local[prefix]; ok {
+			if err := d.defineTerm(prefix); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	iri, ok := expandTerm(d.result, value)
+	if !ok {
+		return "", fmt.Errorf("%q does not expand to an IRI", value)
+	}
+	return iri, nil
+}
+
+// inProgress reports whether name is being defined further up the call stack
+func (d *definer) inProgress(name string) bool {
+	done, ok := d.defined[name]
+	return ok && !done
+}
+
+// expandTerm expands a key or type value against the active context: a keyword, a defined
+// term, a compact IRI, an absolute IRI, or a term resolved through @vocab
+func expandTerm(active *activeContext, value string) (string, bool) {
+	if keywords[value] {
+		return value, true
+	}
+
+	if definition, ok := active.terms[value]; ok {
+		return definition.id, definition.id != ""
+	}
+
+	if prefix, suffix, found := strings.Cut(value, ":"); found {
+		if prefix == "_" || strings.HasPrefix(suffix, "//") {
+			return value, true
+		}
+		if definition, ok := active.terms[prefix]; ok && definition.id != "" {
+			return definition.id + suffix, true
+		}
+		if isScheme(prefix) {
+			return value, true
+		}
+	}
+
+	if active.vocab != "" {
+		return active.vocab + value, true
+	}
+
+	return "", false
+}
+
+// isScheme reports whether s is a valid IRI scheme
+func isScheme(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
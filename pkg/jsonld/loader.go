@@ -0,0 +1,148 @@
+// Package jsonld bundles the JSON-LD contexts credentials are issued under and checks that
+// credentials and presentations expand against them, without fetching anything over the network.
+package jsonld
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// CredentialsV1URL is the W3C Verifiable Credentials Data Model 1.1 context
+	CredentialsV1URL = "https://www.w3.org/2018/credentials/v1"
+	// CredentialsV2URL is the W3C Verifiable Credentials Data Model 2.0 context
+	CredentialsV2URL = "https://www.w3.org/ns/credentials/v2"
+	// BBSV1URL is the context defining the BBS+ signature and proof suites
+	BBSV1URL = "https://w3id.org/security/bbs/v1"
+	// ExtensionsV1URL defines the claims and presentation extensions used by this service
+	ExtensionsV1URL = "https://lugondev.github.io/bbs-selective-disclosure-example/contexts/v1.jsonld"
+)
+
+//go:embed contexts/*.jsonld
+var bundledFiles embed.FS
+
+// bundled maps each bundled context URL to its file name under contexts/
+var bundled = map[string]string{
+	CredentialsV1URL: "credentials-v1",
+	CredentialsV2URL: "credentials-v2",
+	BBSV1URL:         "bbs-v1",
+	ExtensionsV1URL:  "extensions-v1",
+}
+
+// Context is a context document and the URL it is published under
+type Context struct {
+	URL string `json:"url"`
+	// Name identifies the document when it is served locally
+	Name     string          `json:"name"`
+	Document json.RawMessage `json:"-"`
+}
+
+// DocumentLoader resolves context URLs to context documents
+type DocumentLoader interface {
+	LoadContext(url string) (*Context, error)
+}
+
+// BundledLoader resolves context URLs from documents held in memory. It never fetches
+// remote contexts, so unknown URLs fail instead of reaching the network.
+type BundledLoader struct {
+	mu       sync.RWMutex
+	contexts map[string]*Context
+}
+
+// NewBundledLoader creates a loader holding the bundled credentials, BBS+ and extension contexts
+func NewBundledLoader() (*BundledLoader, error) {
+	loader := &BundledLoader{
+		contexts: make(map[string]*Context),
+	}
+
+	for url, name := range bundled {
+		document, err := bundledFiles.ReadFile("contexts/" + name + ".jsonld")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundled context %s: %w", url, err)
+		}
+		if err := loader.register(url, name, document); err != nil {
+			return nil, err
+		}
+	}
+
+	return loader, nil
+}
+
+// Register adds or replaces a context document under url, so deployments can accept
+// credentials issued under further contexts
+func (l *BundledLoader) Register(url string, name string, document []byte) error {
+	if url == "" || name == "" {
+		return fmt.Errorf("context URL and name are required")
+	}
+	if strings.ContainsAny(name, "/?#") {
+		return fmt.Errorf("context name %q cannot contain '/', '?' or '#'", name)
+	}
+	return l.register(url, name, document)
+}
+
+// register parses a context document and stores it
+func (l *BundledLoader) register(url, name string, document []byte) error {
+	var parsed struct {
+		Context interface{} `json:"@context"`
+	}
+	if err := json.Unmarshal(document, &parsed); err != nil {
+		return fmt.Errorf("context %s is not valid JSON: %w", url, err)
+	}
+	if parsed.Context == nil {
+		return fmt.Errorf("context %s has no @context", url)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for existingURL, existing := range l.contexts {
+		if existing.Name == name && existingURL != url {
+			return fmt.Errorf("context name %s is already used by %s", name, existingURL)
+		}
+	}
+
+	l.contexts[url] = &Context{URL: url, Name: name, Document: json.RawMessage(document)}
+	return nil
+}
+
+// LoadContext returns the context document published under url
+func (l *BundledLoader) LoadContext(url string) (*Context, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	context, ok := l.contexts[url]
+	if !ok {
+		return nil, fmt.Errorf("context %s is not available offline", url)
+	}
+	return context, nil
+}
+
+// Lookup returns the context document served under name
+func (l *BundledLoader) Lookup(name string) (*Context, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, context := range l.contexts {
+		if context.Name == name {
+			return context, true
+		}
+	}
+	return nil, false
+}
+
+// Contexts lists the documents the loader holds, sorted by URL
+func (l *BundledLoader) Contexts() []*Context {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	contexts := make([]*Context, 0, len(l.contexts))
+	for _, context := range l.contexts {
+		contexts = append(contexts, context)
+	}
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].URL < contexts[j].URL })
+	return contexts
+}
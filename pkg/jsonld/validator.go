@@ -0,0 +1,302 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// CredentialType is the type every verifiable credential must have
+	CredentialType = "VerifiableCredential"
+	// PresentationType is the type every verifiable presentation must have
+	PresentationType = "VerifiablePresentation"
+)
+
+// maxProblems bounds how many problems a ValidationError lists
+const maxProblems = 20
+
+// ValidationError lists why a document does not expand cleanly under its contexts
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Validator checks that credentials and presentations expand against their @context: every
+// context is available offline, no protected term is redefined, and every property and type
+// maps to an IRI. A term that would be silently dropped during expansion is reported, since a
+// signature over it would not cover what a JSON-LD processor reads.
+type Validator struct {
+	loader DocumentLoader
+
+	mu sync.Mutex
+	// parsed caches each context document's decoded @context value by URL
+	parsed map[string]interface{}
+}
+
+// NewValidator creates a validator resolving contexts through loader
+func NewValidator(loader DocumentLoader) *Validator {
+	return &Validator{
+		loader: loader,
+		parsed: make(map[string]interface{}),
+	}
+}
+
+// ValidateCredential checks a credential, given as a struct or decoded JSON object
+func (v *Validator) ValidateCredential(credential interface{}) error {
+	document, err := toDocument(credential)
+	if err != nil {
+		return err
+	}
+
+	w := &walker{processor: &processor{validator: v}}
+	w.root(document, CredentialType, "credential")
+	return w.err()
+}
+
+// ValidatePresentation checks a presentation and every credential embedded in it
+func (v *Validator) ValidatePresentation(presentation interface{}) error {
+	document, err := toDocument(presentation)
+	if err != nil {
+		return err
+	}
+
+	w := &walker{processor: &processor{validator: v}}
+	w.root(document, PresentationType, "presentation")
+
+	credentials, _ := document["verifiableCredential"].([]interface{})
+	for i, item := range credentials {
+		if credential, ok := item.(map[string]interface{}); ok {
+			w.checkRoot(credential, CredentialType, fmt.Sprintf("presentation.verifiableCredential[%d]", i))
+		}
+	}
+
+	return w.err()
+}
+
+// definitions returns the decoded @context value of the document published under url
+func (v *Validator) definitions(url string) (interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if definitions, ok := v.parsed[url]; ok {
+		return definitions, nil
+	}
+
+	context, err := v.loader.LoadContext(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		Context interface{} `json:"@context"`
+	}
+	if err := json.Unmarshal(context.Document, &document); err != nil {
+		return nil, fmt.Errorf("context %s is not valid JSON: %w", url, err)
+	}
+
+	v.parsed[url] = document.Context
+	return document.Context, nil
+}
+
+// toDocument converts a struct or map to its decoded JSON object form
+func toDocument(value interface{}) (map[string]interface{}, error) {
+	if document, ok := value.(map[string]interface{}); ok {
+		return document, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil || document == nil {
+		return nil, fmt.Errorf("document is not a JSON object")
+	}
+	return document, nil
+}
+
+// walker collects the problems found while walking a document
+type walker struct {
+	processor *processor
+	problems  []string
+}
+
+// report records a problem at a path in the document
+func (w *walker) report(path string, format string, args ...interface{}) {
+	if len(w.problems) < maxProblems {
+		w.problems = append(w.problems, path+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// err returns the collected problems as a ValidationError, or nil when there are none
+func (w *walker) err() error {
+	if len(w.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: w.problems}
+}
+
+// root checks the requirements on a top-level credential or presentation, then walks it
+func (w *walker) root(document map[string]interface{}, requiredType, path string) {
+	if w.checkRoot(document, requiredType, path) {
+		w.node(&activeContext{terms: make(map[string]*term)}, document, path)
+	}
+}
+
+// checkRoot checks that a credential or presentation starts from the 1.1 or 2.0 credentials
+// context and has the required type. It reports whether the document has an @context to walk.
+func (w *walker) checkRoot(document map[string]interface{}, requiredType, path string) bool {
+	contexts, ok := document["@context"].([]interface{})
+	if !ok || len(contexts) == 0 {
+		w.report(path, "@context must be a non-empty array")
+		return false
+	}
+	if first, _ := contexts[0].(string); first != CredentialsV1URL && first != CredentialsV2URL {
+		w.report(path, "the first @context must be %s or %s", CredentialsV1URL, CredentialsV2URL)
+	}
+
+	types, _ := stringValues(document["type"])
+	if !contains(types, requiredType) {
+		w.report(path, "type must include %s", requiredType)
+	}
+
+	return true
+}
+
+// node checks one node object: its embedded context, its types and each of its properties
+func (w *walker) node(active *activeContext, node map[string]interface{}, path string) {
+	if local, ok := node["@context"]; ok {
+		next, err := w.processor.process(active, local, true, false, 0)
+		if err != nil {
+			w.report(path, "invalid @context: %v", err)
+			return
+		}
+		active = next
+	}
+
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		if key != "@context" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	// Types are expanded, then their scoped contexts applied, before any other property
+	typeScoped := active
+	for _, key := range keys {
+		if iri, ok := expandTerm(active, key); !ok || iri != "@type" {
+			continue
+		}
+
+		types, ok := stringValues(node[key])
+		if !ok {
+			w.report(path+"."+key, "type must be a string or an array of strings")
+			continue
+		}
+		sort.Strings(types)
+
+		for _, typ := range types {
+			if _, ok := expandTerm(active, typ); !ok {
+				w.report(path+"."+key, "type %q is not defined by the context", typ)
+				continue
+			}
+			if definition, ok := active.terms[typ]; ok && definition.scoped {
+				next, err := w.processor.process(typeScoped, definition.context, false, false, 0)
+				if err != nil {
+					w.report(path+"."+key, "invalid context for type %s: %v", typ, err)
+					continue
+				}
+				typeScoped = next
+			}
+		}
+	}
+	active = typeScoped
+
+	for _, key := range keys {
+		keyPath := path + "." + key
+
+		iri, ok := expandTerm(active, key)
+		if !ok {
+			w.report(keyPath, "term %q is not defined by the context", key)
+			continue
+		}
+		if strings.HasPrefix(iri, "@") {
+			// Keyword values such as @id and @type need no further expansion
+			continue
+		}
+
+		definition := active.terms[key]
+		if definition != nil && definition.typ == "@json" {
+			// JSON literals are opaque to JSON-LD
+			continue
+		}
+
+		w.value(active, definition, node[key], keyPath)
+	}
+}
+
+// value checks the nested nodes of a property value. Nested nodes drop any type-scoped context
+// and take the property's own scoped context.
+func (w *walker) value(active *activeContext, definition *term, value interface{}, path string) {
+	switch v := value.(type) {
+	case []interface{}:
+		for i, item := range v {
+			w.value(active, definition, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case map[string]interface{}:
+		if _, ok := v["@value"]; ok {
+			return
+		}
+
+		nested := active.revert()
+		// A null scoped context, as on the 2.0 verifiableCredential property, starts nested
+		// credentials from an empty context
+		if definition != nil && definition.scoped {
+			next, err := w.processor.process(nested, definition.context, true, true, 0)
+			if err != nil {
+				w.report(path, "invalid property context: %v", err)
+				return
+			}
+			nested = next
+		}
+		w.node(nested, v, path)
+	}
+}
+
+// stringValues returns a string or array of strings as a slice
+func stringValues(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(t *testing.T) (*BundledLoader, *Validator) {
+	loader, err := NewBundledLoader()
+	require.NoError(t, err)
+	return loader, NewValidator(loader)
+}
+
+func testCredential() map[string]interface{} {
+	return map[string]interface{}{
+		"@context":     []interface{}{CredentialsV1URL, BBSV1URL, ExtensionsV1URL},
+		"id":           "urn:uuid:5f0a3c2e-1111-4a1b-9c3d-000000000001",
+		"type":         []interface{}{"VerifiableCredential"},
+		"issuer":       "did:example:issuer",
+		"issuanceDate": "2024-01-01T00:00:00Z",
+		"credentialSubject": map[string]interface{}{
+			"id":        "did:example:holder",
+			"firstName": "Alice",
+			"ageOver18": true,
+		},
+		"credentialStatus": []interface{}{
+			map[string]interface{}{
+				"id":                   "https://example.com/status/1#42",
+				"type":                 "BitstringStatusListEntry",
+				"statusPurpose":        "revocation",
+				"statusListIndex":      "42",
+				"statusListCredential": "https://example.com/status/1",
+			},
+		},
+		"commitments": map[string]interface{}{"anything": []interface{}{1, 2}},
+		"proof": map[string]interface{}{
+			"type":               "BbsBlsSignature2020",
+			"created":            "2024-01-01T00:00:00Z",
+			"verificationMethod": "did:example:issuer#key-1",
+			"proofPurpose":       "assertionMethod",
+			"proofValue":         "abc",
+			"revealedAttributes": []interface{}{0, 1},
+		},
+	}
+}
+
+func problems(t *testing.T, err error) []string {
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	return validationErr.Problems
+}
+
+func TestBundledLoader(t *testing.T) {
+	loader, _ := newTestValidator(t)
+
+	for _, url := range []string{CredentialsV1URL, CredentialsV2URL, BBSV1URL, ExtensionsV1URL} {
+		context, err := loader.LoadContext(url)
+		require.NoError(t, err, url)
+		assert.True(t, json.Valid(context.Document))
+
+		served, ok := loader.Lookup(context.Name)
+		require.True(t, ok)
+		assert.Equal(t, url, served.URL)
+	}
+	assert.Len(t, loader.Contexts(), 4)
+
+	_, err := loader.LoadContext("https://example.com/unknown/v1")
+	assert.Error(t, err, "unknown contexts are never fetched")
+
+	require.NoError(t, loader.Register("https://example.com/custom/v1", "custom-v1", []byte(`{"@context": {"custom": "https://example.com/custom#"}}`)))
+	assert.Error(t, loader.Register("https://example.com/other/v1", "custom-v1", []byte(`{"@context": {}}`)), "names must be unique")
+	assert.Error(t, loader.Register("https://example.com/bad/v1", "bad/v1", []byte(`{"@context": {}}`)))
+	assert.Error(t, loader.Register("https://example.com/bad/v1", "bad", []byte(`{}`)))
+}
+
+func TestValidateCredential(t *testing.T) {
+	_, validator := newTestValidator(t)
+
+	t.Run("Issued Credential Expands", func(t *testing.T) {
+		assert.NoError(t, validator.ValidateCredential(testCredential()))
+	})
+
+	t.Run("Struct Input", func(t *testing.T) {
+		credential := struct {
+			Context []string `json:"@context"`
+			Type    []string `json:"type"`
+			Issuer  string   `json:"issuer"`
+		}{
+			Context: []string{CredentialsV1URL},
+			Type:    []string{"VerifiableCredential"},
+			Issuer:  "did:example:issuer",
+		}
+		assert.NoError(t, validator.ValidateCredential(credential))
+	})
+
+	t.Run("Undefined Claim Without Vocab", func(t *testing.T) {
+		credential := testCredential()
+		credential["@context"] = []interface{}{CredentialsV1URL, BBSV1URL}
+		delete(credential, "commitments")
+		delete(credential["proof"].(map[string]interface{}), "revealedAttributes")
+		delete(credential, "credentialStatus")
+
+		found := problems(t, validator.ValidateCredential(credential))
+		assert.Contains(t, found, `credential.credentialSubject.ageOver18: term "ageOver18" is not defined by the context`)
+		assert.Contains(t, found, `credential.credentialSubject.firstName: term "firstName" is not defined by the context`)
+		assert.Len(t, found, 2)
+	})
+
+	t.Run("Type-Scoped Terms Do Not Leak Into Subjects", func(t *testing.T) {
+		credential := testCredential()
+		credential["@context"] = []interface{}{CredentialsV1URL, BBSV1URL}
+		delete(credential, "commitments")
+		delete(credential, "credentialStatus")
+		delete(credential["proof"].(map[string]interface{}), "revealedAttributes")
+		credential["credentialSubject"] = map[string]interface{}{"id": "did:example:holder", "issuanceDate": "2024-01-01"}
+
+		found := problems(t, validator.ValidateCredential(credential))
+		assert.Equal(t, []string{`credential.credentialSubject.issuanceDate: term "issuanceDate" is not defined by the context`}, found)
+	})
+
+	t.Run("Protected Term Redefinition", func(t *testing.T) {
+		credential := testCredential()
+		credential["@context"] = []interface{}{
+			CredentialsV1URL,
+			map[string]interface{}{"VerifiableCredential": "https://example.com/fake#VerifiableCredential"},
+		}
+
+		found := problems(t, validator.ValidateCredential(credential))
+		require.Len(t, found, 1)
+		assert.Contains(t, found[0], "protected term VerifiableCredential cannot be redefined")
+	})
+
+	t.Run("Unknown Context", func(t *testing.T) {
+		credential := testCredential()
+		credential["@context"] = []interface{}{CredentialsV1URL, "https://example.com/unknown/v1"}
+
+		found := problems(t, validator.ValidateCredential(credential))
+		assert.Contains(t, found[0], "is not available offline")
+	})
+
+	t.Run("Structure", func(t *testing.T) {
+		credential := testCredential()
+		credential["@context"] = []interface{}{BBSV1URL, CredentialsV1URL, ExtensionsV1URL}
+		credential["type"] = []interface{}{"AgeCredential"}
+
+		found := problems(t, validator.ValidateCredential(credential))
+		assert.Contains(t, found, "credential: the first @context must be "+CredentialsV1URL+" or "+CredentialsV2URL)
+		assert.Contains(t, found, "credential: type must include VerifiableCredential")
+
+		delete(credential, "@context")
+		assert.Contains(t, problems(t, validator.ValidateCredential(credential)), "credential: @context must be a non-empty array")
+	})
+
+	t.Run("Registered Context", func(t *testing.T) {
+		loader, validator := newTestValidator(t)
+		require.NoError(t, loader.Register("https://example.com/claims/v1", "claims-v1", []byte(`{
+			"@context": {"ex": "https://example.com/claims#", "firstName": "ex:firstName", "ageOver18": {"@id": "ex:ageOver18"}}
+		}`)))
+
+		credential := testCredential()
+		credential["@context"] = []interface{}{CredentialsV1URL, BBSV1URL, "https://example.com/claims/v1"}
+		delete(credential, "commitments")
+		delete(credential, "credentialStatus")
+		delete(credential["proof"].(map[string]interface{}), "revealedAttributes")
+		assert.NoError(t, validator.ValidateCredential(credential))
+	})
+}
+
+func TestValidatePresentation(t *testing.T) {
+	_, validator := newTestValidator(t)
+
+	derived := testCredential()
+	delete(derived["credentialSubject"].(map[string]interface{}), "firstName")
+	derived["proof"].(map[string]interface{})["type"] = "BbsBlsSignatureProof2020"
+	derived["proof"].(map[string]interface{})["nonce"] = "abc"
+
+	presentation := map[string]interface{}{
+		"@context":             []interface{}{CredentialsV1URL, BBSV1URL, ExtensionsV1URL},
+		"id":                   "urn:uuid:5f0a3c2e-1111-4a1b-9c3d-000000000002",
+		"type":                 []interface{}{"VerifiablePresentation"},
+		"holder":               "did:example:holder",
+		"verifiableCredential": []interface{}{derived},
+		"proof": map[string]interface{}{
+			"type":               "BbsBlsSignatureProof2020",
+			"created":            "2024-01-01T00:00:00Z",
+			"expires":            "2024-01-01T00:05:00Z",
+			"verificationMethod": "did:example:holder#key-1",
+			"proofPurpose":       "authentication",
+		},
+	}
+	assert.NoError(t, validator.ValidatePresentation(presentation))
+
+	// Embedded credentials inherit the presentation's context, but must still be credentials
+	derived["@context"] = []interface{}{CredentialsV1URL}
+	derived["type"] = []interface{}{"AgeCredential"}
+	found := problems(t, validator.ValidatePresentation(presentation))
+	assert.Equal(t, []string{"presentation.verifiableCredential[0]: type must include VerifiableCredential"}, found)
+
+	derived["type"] = []interface{}{"VerifiableCredential"}
+	presentation["@context"] = []interface{}{CredentialsV1URL, BBSV1URL}
+	found = problems(t, validator.ValidatePresentation(presentation))
+	assert.Contains(t, found, `presentation.verifiableCredential[0].credentialSubject.ageOver18: term "ageOver18" is not defined by the context`)
+	assert.Contains(t, found, `presentation.proof.expires: term "expires" is not defined by the context`)
+}
+
+// testCredentialV2 is testCredential under the 2.0 data model
+func testCredentialV2() map[string]interface{} {
+	credential := testCredential()
+	credential["@context"] = []interface{}{CredentialsV2URL, BBSV1URL, ExtensionsV1URL}
+	delete(credential, "issuanceDate")
+	credential["validFrom"] = "2024-01-01T00:00:00Z"
+	credential["validUntil"] = "2034-01-01T00:00:00Z"
+	credential["issuer"] = map[string]interface{}{"id": "did:example:issuer", "name": "Example University"}
+	return credential
+}
+
+func TestValidateV2(t *testing.T) {
+	_, validator := newTestValidator(t)
+
+	t.Run("Credential", func(t *testing.T) {
+		assert.NoError(t, validator.ValidateCredential(testCredentialV2()))
+	})
+
+	t.Run("Issuer-Dependent Terms", func(t *testing.T) {
+		// The 2.0 context maps undefined terms into its issuer-dependent vocabulary
+		credential := testCredentialV2()
+		credential["@context"] = []interface{}{CredentialsV2URL}
+		delete(credential, "commitments")
+		delete(credential["proof"].(map[string]interface{}), "revealedAttributes")
+		credential["proof"].(map[string]interface{})["type"] = "DataIntegrityProof"
+		credential["proof"].(map[string]interface{})["cryptosuite"] = "bbs-2023"
+		assert.NoError(t, validator.ValidateCredential(credential))
+	})
+
+	t.Run("Protected Term Redefinition", func(t *testing.T) {
+		credential := testCredentialV2()
+		credential["@context"] = []interface{}{
+			CredentialsV2URL,
+			map[string]interface{}{"name": "https://example.com/fake#name"},
+		}
+
+		found := problems(t, validator.ValidateCredential(credential))
+		require.NotEmpty(t, found)
+		assert.Contains(t, found[0], "protected term")
+	})
+
+	presentation := func(base string, credential map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"@context":             []interface{}{base, BBSV1URL, ExtensionsV1URL},
+			"type":                 []interface{}{"VerifiablePresentation"},
+			"holder":               "did:example:holder",
+			"verifiableCredential": []interface{}{credential},
+			"proof": map[string]interface{}{
+				"type":               "BbsBlsSignatureProof2020",
+				"created":            "2024-01-01T00:00:00Z",
+				"verificationMethod": "did:example:holder#key-1",
+				"proofPurpose":       "authentication",
+			},
+		}
+	}
+
+	t.Run("Presentation", func(t *testing.T) {
+		assert.NoError(t, validator.ValidatePresentation(presentation(CredentialsV2URL, testCredentialV2())))
+	})
+
+	t.Run("1.1 Credential In A 2.0 Presentation", func(t *testing.T) {
+		// verifiableCredential resets the context, so embedded credentials start afresh
+		assert.NoError(t, validator.ValidatePresentation(presentation(CredentialsV2URL, testCredential())))
+	})
+
+	t.Run("2.0 Credential In A 1.1 Presentation", func(t *testing.T) {
+		found := problems(t, validator.ValidatePresentation(presentation(CredentialsV1URL, testCredentialV2())))
+		require.NotEmpty(t, found)
+		assert.Contains(t, found[0], "protected term")
+	})
+}
@@ -0,0 +1,222 @@
+// Package lint reports hardening findings about presentations that verify but are built in ways
+// integrators should tighten: weak nonces, unexpected disclosures, non-canonical encodings.
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Code identifies the kind of a finding
+type Code string
+
+const (
+	// CodeExtraRevealedAttribute flags a revealed claim the verifier neither required nor accepted as optional
+	CodeExtraRevealedAttribute Code = "extra-revealed-attribute"
+	// CodeOverDisclosure flags a presentation revealing more than the verifier's request allows
+	CodeOverDisclosure Code = "over-disclosure"
+	// CodeMissingExpiration flags a credential or presentation proof without an expiry
+	CodeMissingExpiration Code = "missing-expiration"
+	// CodeWeakNonce flags a missing nonce or one with too little entropy to prevent replay
+	CodeWeakNonce Code = "weak-nonce"
+	// CodeNonCanonicalJSON flags JSON that is not in canonical form
+	CodeNonCanonicalJSON Code = "non-canonical-json"
+	// CodeDuplicateKey flags a JSON object with a repeated key, which parsers resolve differently
+	CodeDuplicateKey Code = "duplicate-json-key"
+	// CodeUnknownProofField flags a proof field the proof suites used here do not define
+	CodeUnknownProofField Code = "unknown-proof-field"
+	// CodeOutdatedVersion flags a credential its issuer has since amended with a newer version
+	CodeOutdatedVersion Code = "outdated-credential-version"
+)
+
+// MinNonceEntropyBits is the estimated entropy below which a nonce is reported as weak
+const MinNonceEntropyBits = 64
+
+// Finding is one lint finding. Findings are warnings; they never make a presentation invalid.
+type Finding struct {
+	Code Code `json:"code"`
+	// Path locates the finding in the presentation, e.g. verifiableCredential[0].proof.foo
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// proofFields are the JSON field names of vc.Proof
+var proofFields = jsonFieldNames(reflect.TypeOf(vc.Proof{}))
+
+// NonceEntropyBits estimates a nonce's entropy from its length and the empirical distribution of
+// its bytes, as bbs.NonceEntropyBits does for proof creation
+func NonceEntropyBits(nonce string) float64 {
+	return bbs.NonceEntropyBits([]byte(nonce))
+}
+
+// CheckNonce reports a missing or low-entropy nonce
+func CheckNonce(path, nonce string) []Finding {
+	if nonce == "" {
+		return []Finding{{Code: CodeWeakNonce, Path: path, Message: "no nonce is bound to the proof, so it can be replayed"}}
+	}
+
+	if bits := NonceEntropyBits(nonce); bits < MinNonceEntropyBits {
+		return []Finding{{
+			Code:    CodeWeakNonce,
+			Path:    path,
+			Message: fmt.Sprintf("nonce has about %.0f bits of entropy; use at least %d random bits", bits, MinNonceEntropyBits),
+		}}
+	}
+
+	return nil
+}
+
+// CheckProofFields reports proof fields that vc.Proof does not define
+func CheckProofFields(path string, proof map[string]interface{}) []Finding {
+	var findings []Finding
+	for _, field := range sortedKeys(proof) {
+		if !proofFields[field] {
+			findings = append(findings, Finding{
+				Code:    CodeUnknownProofField,
+				Path:    path + "." + field,
+				Message: fmt.Sprintf("proof field %q is not defined by the proof suite and is ignored by verification", field),
+			})
+		}
+	}
+	return findings
+}
+
+// CheckCanonicalJSON reports duplicate object keys, and whether raw differs from its canonical
+// form: object keys sorted, no insignificant whitespace, and no HTML escaping.
+func CheckCanonicalJSON(path string, raw []byte) []Finding {
+	var findings []Finding
+	for _, duplicate := range duplicateKeys(raw) {
+		findings = append(findings, Finding{
+			Code:    CodeDuplicateKey,
+			Path:    joinPath(path, duplicate),
+			Message: "object key appears more than once; parsers disagree on which value wins",
+		})
+	}
+
+	canonical, err := Canonicalize(raw)
+	if err != nil {
+		return append(findings, Finding{Code: CodeNonCanonicalJSON, Path: path, Message: fmt.Sprintf("invalid JSON: %v", err)})
+	}
+	if !bytes.Equal(bytes.TrimSpace(raw), canonical) {
+		findings = append(findings, Finding{
+			Code:    CodeNonCanonicalJSON,
+			Path:    path,
+			Message: "JSON is not canonical (sorted keys, no whitespace); signatures over re-encoded JSON may not match",
+		})
+	}
+
+	return findings
+}
+
+// Canonicalize re-encodes JSON with object keys sorted and without insignificant whitespace.
+// Numbers keep their original representation.
+func Canonicalize(raw []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the top-level value")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// duplicateKeys returns the paths of object keys that repeat within their object
+func duplicateKeys(raw []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var duplicates []string
+	var walk func(path string) error
+	walk = func(path string) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'):
+			seen := make(map[string]bool)
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyToken.(string)
+				if seen[key] {
+					duplicates = append(duplicates, joinPath(path, key))
+				}
+				seen[key] = true
+				if err := walk(joinPath(path, key)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+			return err
+		}
+		return nil
+	}
+
+	// Invalid JSON is reported by the canonical form check
+	_ = walk("")
+	return duplicates
+}
+
+// jsonFieldNames returns the JSON names of a struct type's fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		if name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// joinPath appends a field to a dotted path
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// sortedKeys returns a map's keys in order, so findings are reported deterministically
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckNonce(t *testing.T) {
+	assert.Equal(t, CodeWeakNonce, CheckNonce("proof.nonce", "")[0].Code)
+	assert.NotEmpty(t, CheckNonce("proof.nonce", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"))
+	assert.NotEmpty(t, CheckNonce("proof.nonce", "nonce123"))
+
+	assert.Empty(t, CheckNonce("proof.nonce", "3f2b8c61-0d4e-4f4a-9b7e-5a1c2d3e4f60"), "UUIDs are strong enough")
+	assert.Empty(t, CheckNonce("proof.nonce", "9c0e4b1f7a2d35e8c6b04f19a7d2e3c58b1f06a4d9e2c7b3"), "random hex is strong enough")
+
+	assert.Zero(t, NonceEntropyBits(""))
+	assert.Zero(t, NonceEntropyBits("zzzz"))
+	assert.InDelta(t, 8, NonceEntropyBits("abcdefgh")/3, 0.01, "8 distinct characters carry 3 bits each")
+}
+
+func TestCheckProofFields(t *testing.T) {
+	findings := CheckProofFields("proof", map[string]interface{}{
+		"type":               "BbsBlsSignatureProof2020",
+		"nonce":              "abc",
+		"revealedAttributes": []interface{}{"ageOver18"},
+		"jws":                "eyJ...",
+		"challenge":          "abc",
+	})
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, "proof.challenge", findings[0].Path)
+	assert.Equal(t, "proof.jws", findings[1].Path)
+	assert.Equal(t, CodeUnknownProofField, findings[1].Code)
+}
+
+func TestCheckCanonicalJSON(t *testing.T) {
+	assert.Empty(t, CheckCanonicalJSON("", []byte(`{"a":1,"b":{"c":[1,"<x>"],"d":1.50}}`)))
+
+	findings := CheckCanonicalJSON("presentation", []byte(`{"b": 1, "a": 2}`))
+	require.Len(t, findings, 1)
+	assert.Equal(t, CodeNonCanonicalJSON, findings[0].Code)
+
+	findings = CheckCanonicalJSON("presentation", []byte(`{"a":{"x":1,"x":2},"b":[{"y":1,"y":1}]}`))
+	require.Len(t, findings, 3)
+	assert.Equal(t, Finding{Code: CodeDuplicateKey, Path: "presentation.a.x", Message: findings[0].Message}, findings[0])
+	assert.Equal(t, "presentation.b[0].y", findings[1].Path)
+	assert.Equal(t, CodeNonCanonicalJSON, findings[2].Code, "duplicates are dropped by re-encoding")
+
+	findings = CheckCanonicalJSON("presentation", []byte(`{"a":`))
+	require.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "invalid JSON")
+
+	canonical, err := Canonicalize([]byte(" {\"z\": true,\n \"a\": [3, 1e2]} "))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[3,1e2],"z":true}`, string(canonical))
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when a notification would be delivered to an address that is not
+// publicly routable, such as a loopback, private or link-local one
+var ErrPrivateAddress = errors.New("endpoint address is not public")
+
+// reservedPrefixes are ranges that are not publicly routable but that netip does not classify as
+// private, loopback or link-local
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// NewPublicClient creates a client with the given timeout that only connects to publicly routable
+// addresses. Webhook endpoints are chosen by the callers who register them, so the address is
+// checked once resolved, when dialling: a host name resolving to a loopback or private address,
+// or a redirect to one, is refused like a literal one.
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublic}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect on the client's behalf, out of reach of the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// dialPublic refuses connections to addresses that are not publicly routable
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %w", address, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %w", address, err)
+	}
+	if !IsPublicAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+	}
+	return nil
+}
+
+// IsPublicAddress reports whether ip is publicly routable
+func IsPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+// Package notify delivers JSON notifications to webhook endpoints
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default delivery settings
+const (
+	DefaultTimeout  = 10 * time.Second
+	DefaultAttempts = 3
+	DefaultBackoff  = 500 * time.Millisecond
+)
+
+// Notifier delivers a notification to an endpoint
+type Notifier interface {
+	Notify(ctx context.Context, endpoint string, payload interface{}) error
+}
+
+// WebhookNotifier posts notifications as JSON, retrying network errors and 5xx responses with
+// exponential backoff. Any 2xx response counts as delivered.
+type WebhookNotifier struct {
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+// NewWebhookNotifier creates a notifier using client or, when nil, a public client with
+// DefaultTimeout, which refuses endpoints at loopback and private addresses. Deployments whose
+// webhooks run on the local network pass a client of their own.
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = NewPublicClient(DefaultTimeout)
+	}
+	return &WebhookNotifier{client: client, attempts: DefaultAttempts, backoff: DefaultBackoff}
+}
+
+// SetRetries sets how many times delivery is attempted and the wait before the first retry,
+// which doubles for each further retry
+func (n *WebhookNotifier) SetRetries(attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		return fmt.Errorf("delivery attempts must be at least 1")
+	}
+	if backoff < 0 {
+		return fmt.Errorf("retry backoff cannot be negative")
+	}
+	n.attempts = attempts
+	n.backoff = backoff
+	return nil
+}
+
+// Notify posts payload to endpoint
+func (n *WebhookNotifier) Notify(ctx context.Context, endpoint string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	wait := n.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, endpoint, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// post makes one delivery attempt, reporting whether a failure is worth retrying
+func (n *WebhookNotifier) post(ctx context.Context, endpoint string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return false, fmt.Errorf("notification delivery refused: %w", err)
+		}
+		return true, fmt.Errorf("notification delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode >= 500, fmt.Errorf("notification delivery failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return false, nil
+}
+
+// ValidateEndpoint checks endpoint is an absolute http or https URL
+func ValidateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("endpoint must be an http or https URL")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("endpoint must include a host")
+	}
+	return nil
+}
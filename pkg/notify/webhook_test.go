@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	// The test servers listen on loopback, which the default client refuses
+	local := &http.Client{Timeout: DefaultTimeout}
+
+	t.Run("Posts JSON", func(t *testing.T) {
+		var received map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(local)
+		require.NoError(t, notifier.Notify(context.Background(), server.URL, map[string]string{"event": "revoked"}))
+		assert.Equal(t, "revoked", received["event"])
+	})
+
+	t.Run("Retries Server Errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(local)
+		require.NoError(t, notifier.SetRetries(3, 0))
+		require.NoError(t, notifier.Notify(context.Background(), server.URL, struct{}{}))
+		assert.EqualValues(t, 3, calls.Load())
+	})
+
+	t.Run("Client Errors Are Not Retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			http.Error(w, "unknown holder", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(local)
+		require.NoError(t, notifier.SetRetries(3, 0))
+		err := notifier.Notify(context.Background(), server.URL, struct{}{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown holder")
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("Invalid Settings", func(t *testing.T) {
+		notifier := NewWebhookNotifier(nil)
+		assert.Error(t, notifier.SetRetries(0, 0))
+		assert.Error(t, notifier.SetRetries(1, -1))
+	})
+}
+
+func TestPublicClient(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("Loopback Refused", func(t *testing.T) {
+		notifier := NewWebhookNotifier(nil)
+		require.NoError(t, notifier.SetRetries(3, time.Hour))
+		err := notifier.Notify(context.Background(), server.URL, struct{}{})
+		assert.ErrorIs(t, err, ErrPrivateAddress, "refusals are not retried")
+		assert.EqualValues(t, 0, calls.Load())
+	})
+
+	t.Run("Host Names Checked Once Resolved", func(t *testing.T) {
+		_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+		require.NoError(t, err)
+		_, err = NewPublicClient(time.Second).Post("http://localhost:"+port, "application/json", nil)
+		assert.ErrorIs(t, err, ErrPrivateAddress)
+		assert.EqualValues(t, 0, calls.Load())
+	})
+
+	t.Run("Redirects Checked", func(t *testing.T) {
+		// The redirect is served by a client that may reach loopback, standing in for a public host
+		redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusTemporaryRedirect))
+		defer redirect.Close()
+
+		client := NewPublicClient(time.Second)
+		client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			if address != redirect.Listener.Addr().String() {
+				return (&net.Dialer{Control: dialPublic}).DialContext(ctx, network, address)
+			}
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}
+		_, err := client.Post(redirect.URL, "application/json", nil)
+		assert.ErrorIs(t, err, ErrPrivateAddress)
+		assert.EqualValues(t, 0, calls.Load())
+	})
+
+	t.Run("Addresses", func(t *testing.T) {
+		for _, address := range []string{"93.184.215.14", "2606:2800:21f:cb07:6820:80da:af6b:8b2c"} {
+			assert.True(t, IsPublicAddress(netip.MustParseAddr(address)), address)
+		}
+		for _, address := range []string{
+			"127.0.0.1", "::1", "10.0.0.8", "172.16.4.1", "192.168.1.1", "169.254.169.254",
+			"0.0.0.0", "::", "100.64.0.1", "fd00::1", "fe80::1", "224.0.0.1", "::ffff:127.0.0.1",
+		} {
+			assert.False(t, IsPublicAddress(netip.MustParseAddr(address)), address)
+		}
+	})
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	assert.NoError(t, ValidateEndpoint("https://wallet.example/hooks/status"))
+	assert.NoError(t, ValidateEndpoint("http://localhost:8089/api/holder/status-notifications"))
+	assert.Error(t, ValidateEndpoint("ftp://wallet.example/hooks"))
+	assert.Error(t, ValidateEndpoint("/hooks/status"))
+	assert.Error(t, ValidateEndpoint("https://"))
+}
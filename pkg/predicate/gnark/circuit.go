@@ -0,0 +1,80 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"math/big"
+
+	gnarkbls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/algopts"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/math/emulated"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+// Point is a BLS12-381 G1 point inside a circuit. Circuits run over the BN254 scalar field, so
+// BLS12-381 arithmetic is emulated.
+type Point = sw_emulated.AffinePoint[emulated.BLS12381Fp]
+
+// Scalar is a BLS12-381 scalar inside a circuit
+type Scalar = emulated.Element[emulated.BLS12381Fr]
+
+// RangeCircuit proves knowledge of an opening (v, r) of a commitment C = G^v * H^r with
+// 0 <= v < 2^64. The commitment is the shifted commitment of a predicate, so the range
+// statement is the predicate; see predicate.Shift.
+type RangeCircuit struct {
+	Commitment Point             `gnark:",public"`
+	Context    frontend.Variable `gnark:",public"`
+
+	Value    Scalar
+	Blinding Scalar
+}
+
+// Define declares the circuit's constraints
+func (c *RangeCircuit) Define(api frontend.API) error {
+	curve, err := sw_emulated.New[emulated.BLS12381Fp, emulated.BLS12381Fr](api, sw_emulated.GetBLS12381Params())
+	if err != nil {
+		return err
+	}
+
+	scalars, err := emulated.NewField[emulated.BLS12381Fr](api)
+	if err != nil {
+		return err
+	}
+
+	// C = G^v * H^r; complete arithmetic because v is zero when the claim equals the bound
+	h := commitmentGenerator()
+	gv := curve.ScalarMulBase(&c.Value, algopts.WithCompleteArithmetic())
+	hr := curve.ScalarMul(&h, &c.Blinding, algopts.WithCompleteArithmetic())
+	curve.AssertIsEqual(curve.AddUnified(gv, hr), &c.Commitment)
+
+	// v < 2^64
+	bits := scalars.ToBits(scalars.Reduce(&c.Value))
+	for _, bit := range bits[bbs.MaxRangeBits:] {
+		api.AssertIsEqual(bit, 0)
+	}
+
+	// Groth16 only binds public inputs that appear in a constraint
+	api.Mul(c.Context, c.Context)
+
+	return nil
+}
+
+// commitmentGenerator returns the commitment generator H as a circuit constant
+func commitmentGenerator() Point {
+	var h gnarkbls.G1Affine
+	if _, err := h.SetBytes(bbs.DefaultCommitmentGenerators().H); err != nil {
+		panic(err)
+	}
+	return pointValue(&h)
+}
+
+// pointValue assigns a G1 point to a circuit point
+func pointValue(p *gnarkbls.G1Affine) Point {
+	return Point{
+		X: emulated.ValueOf[emulated.BLS12381Fp](p.X.BigInt(new(big.Int))),
+		Y: emulated.ValueOf[emulated.BLS12381Fp](p.Y.BigInt(new(big.Int))),
+	}
+}
@@ -0,0 +1,54 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+func TestRangeCircuit(t *testing.T) {
+	scalar := bbs.ScalarFromInt(big.NewInt(72000))
+	encoded, blinding, err := bbs.Commit(scalar)
+	require.NoError(t, err)
+	commitment := vc.AttributeCommitment{Claim: "salary", Encoding: vc.CommitmentEncodingInteger, Commitment: encoded}
+
+	// assignment fills the circuit for a statement the way Prove does, without checking it holds
+	assignment := func(t *testing.T, statement vc.PredicateStatement) *RangeCircuit {
+		negate, offset, err := predicate.Shift(statement)
+		require.NoError(t, err)
+		value, shiftedBlinding := bbs.ShiftOpening(scalar, blinding, negate, offset)
+
+		context := vc.PredicateContext("urn:uuid:credential", statement, encoded, "nonce")
+		circuit, err := publicAssignment(statement, commitment, context)
+		require.NoError(t, err)
+		circuit.Value = emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).SetBytes(value))
+		circuit.Blinding = emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).SetBytes(shiftedBlinding))
+		return circuit
+	}
+
+	t.Run("Satisfied Statement", func(t *testing.T) {
+		statement := vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 50000}
+		assert.NoError(t, test.IsSolved(&RangeCircuit{}, assignment(t, statement), ecc.BN254.ScalarField()))
+	})
+
+	t.Run("Bound Equal To Claim", func(t *testing.T) {
+		statement := vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterOrEqual, Value: 72000}
+		assert.NoError(t, test.IsSolved(&RangeCircuit{}, assignment(t, statement), ecc.BN254.ScalarField()))
+	})
+
+	t.Run("Unsatisfied Statement", func(t *testing.T) {
+		statement := vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 100000}
+		assert.Error(t, test.IsSolved(&RangeCircuit{}, assignment(t, statement), ecc.BN254.ScalarField()))
+	})
+}
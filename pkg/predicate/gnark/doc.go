@@ -0,0 +1,10 @@
+// Package gnark proves predicates over attribute commitments with Groth16 SNARKs built with gnark.
+// Proofs are a few hundred bytes and verify in constant time, but need proving and verifying keys
+// from a circuit-specific setup.
+//
+// The provider is optional and excluded from default builds. To enable it:
+//
+//	go build -tags gnark ./...
+//
+// make test-gnark builds and tests with the tag, as CI does.
+package gnark
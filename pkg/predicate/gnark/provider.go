@@ -0,0 +1,218 @@
+//go:build gnark
+
+package gnark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gnarkbls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/math/emulated"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// ProviderName names proofs created by Provider
+const ProviderName = "groth16-bn254-range"
+
+const (
+	provingKeyFile   = "range.pk"
+	verifyingKeyFile = "range.vk"
+)
+
+// Provider proves predicates with Groth16 proofs of RangeCircuit.
+// A verifier only needs the verifying key; a provider without the proving key can verify but
+// not prove.
+type Provider struct {
+	ccs constraint.ConstraintSystem
+	pk  groth16.ProvingKey
+	vk  groth16.VerifyingKey
+}
+
+// NewProvider compiles the range circuit and loads its keys from keyDir. When keyDir holds no
+// keys, a setup is run and the keys are written there. A single-party setup is only fit for
+// development: whoever ran it can forge proofs. In production, place the keys from a multi-party
+// ceremony in keyDir.
+func NewProvider(keyDir string) (*Provider, error) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &RangeCircuit{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile range circuit: %w", err)
+	}
+
+	provider := &Provider{ccs: ccs}
+
+	vkPath := filepath.Join(keyDir, verifyingKeyFile)
+	pkPath := filepath.Join(keyDir, provingKeyFile)
+
+	if _, err := os.Stat(vkPath); errors.Is(err, os.ErrNotExist) {
+		if provider.pk, provider.vk, err = groth16.Setup(ccs); err != nil {
+			return nil, fmt.Errorf("range circuit setup failed: %w", err)
+		}
+		if err := os.MkdirAll(keyDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create key directory: %w", err)
+		}
+		if err := writeKey(pkPath, provider.pk); err != nil {
+			return nil, err
+		}
+		if err := writeKey(vkPath, provider.vk); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	}
+
+	provider.vk = groth16.NewVerifyingKey(ecc.BN254)
+	if err := readKey(vkPath, provider.vk); err != nil {
+		return nil, err
+	}
+
+	provider.pk = groth16.NewProvingKey(ecc.BN254)
+	if err := readKey(pkPath, provider.pk); errors.Is(err, os.ErrNotExist) {
+		provider.pk = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// Name returns ProviderName
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// Prove proves the statement with a Groth16 proof over the shifted commitment
+func (p *Provider) Prove(statement vc.PredicateStatement, commitment vc.AttributeCommitment, opening vc.CommitmentOpening, context []byte) ([]byte, error) {
+	if p.pk == nil {
+		return nil, fmt.Errorf("no proving key loaded")
+	}
+
+	if err := predicate.CheckEncoding(statement, commitment); err != nil {
+		return nil, err
+	}
+
+	negate, offset, err := predicate.Shift(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	value, blinding := bbs.ShiftOpening(opening.Value, opening.Blinding, negate, offset)
+	if new(big.Int).SetBytes(value).BitLen() > bbs.MaxRangeBits {
+		return nil, fmt.Errorf("claim %s does not satisfy %s", statement.Claim, statement)
+	}
+
+	assignment, err := publicAssignment(statement, commitment, context)
+	if err != nil {
+		return nil, err
+	}
+	assignment.Value = emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).SetBytes(value))
+	assignment.Blinding = emulated.ValueOf[emulated.BLS12381Fr](new(big.Int).SetBytes(blinding))
+
+	witness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build witness: %w", err)
+	}
+
+	proof, err := groth16.Prove(p.ccs, p.pk, witness)
+	if err != nil {
+		return nil, fmt.Errorf("proving failed: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to encode proof: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Verify checks a Groth16 proof over the shifted commitment
+func (p *Provider) Verify(statement vc.PredicateStatement, commitment vc.AttributeCommitment, proof []byte, context []byte) error {
+	if err := predicate.CheckEncoding(statement, commitment); err != nil {
+		return err
+	}
+
+	assignment, err := publicAssignment(statement, commitment, context)
+	if err != nil {
+		return err
+	}
+
+	witness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return fmt.Errorf("failed to build public witness: %w", err)
+	}
+
+	groth16Proof := groth16.NewProof(ecc.BN254)
+	if _, err := groth16Proof.ReadFrom(bytes.NewReader(proof)); err != nil {
+		return fmt.Errorf("invalid proof: %w", err)
+	}
+
+	if err := groth16.Verify(groth16Proof, p.vk, witness); err != nil {
+		return fmt.Errorf("proof verification failed: %w", err)
+	}
+
+	return nil
+}
+
+// publicAssignment assigns the shifted commitment and the context, the circuit's public inputs
+func publicAssignment(statement vc.PredicateStatement, commitment vc.AttributeCommitment, context []byte) (*RangeCircuit, error) {
+	negate, offset, err := predicate.Shift(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	shifted, err := bbs.ShiftCommitment(commitment.Commitment, negate, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commitments are uncompressed G1 points, which gnark-crypto reads directly
+	var point gnarkbls.G1Affine
+	if _, err := point.SetBytes(shifted); err != nil {
+		return nil, fmt.Errorf("invalid commitment: %w", err)
+	}
+
+	return &RangeCircuit{
+		Commitment: pointValue(&point),
+		Context:    new(big.Int).Mod(new(big.Int).SetBytes(context), ecc.BN254.ScalarField()),
+	}, nil
+}
+
+// writeKey saves a proving or verifying key
+func writeKey(path string, key io.WriterTo) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := key.WriteTo(file); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// readKey loads a proving or verifying key
+func readKey(path string, key io.ReaderFrom) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := key.ReadFrom(file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
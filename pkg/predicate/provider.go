@@ -0,0 +1,118 @@
+package predicate
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Provider proves and verifies predicates over issuer-signed attribute commitments.
+// The context binds a proof to its statement, credential and presentation; see vc.PredicateContext.
+type Provider interface {
+	// Name identifies the proof system; it is recorded in every proof the provider creates
+	Name() string
+	// Prove proves that the committed claim satisfies the statement, using the holder's opening
+	Prove(statement vc.PredicateStatement, commitment vc.AttributeCommitment, opening vc.CommitmentOpening, context []byte) ([]byte, error)
+	// Verify checks a proof created by Prove against the commitment
+	Verify(statement vc.PredicateStatement, commitment vc.AttributeCommitment, proof []byte, context []byte) error
+}
+
+// Registry holds the predicate providers of a holder or verifier. The first registered provider
+// is the default holders prove with.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	order     []string
+}
+
+// NewRegistry creates a registry with the given providers
+func NewRegistry(providers ...Provider) (*Registry, error) {
+	registry := &Registry{providers: make(map[string]Provider)}
+	for _, provider := range providers {
+		if err := registry.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register adds a provider
+func (r *Registry) Register(provider Provider) error {
+	if provider == nil {
+		return fmt.Errorf("provider is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := provider.Name()
+	if _, exists := r.providers[name]; exists {
+		return fmt.Errorf("predicate provider %s is already registered", name)
+	}
+
+	r.providers[name] = provider
+	r.order = append(r.order, name)
+	return nil
+}
+
+// Get returns the provider with the given name
+func (r *Registry) Get(name string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+// Default returns the first registered provider
+func (r *Registry) Default() (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.order) == 0 {
+		return nil, false
+	}
+	return r.providers[r.order[0]], true
+}
+
+// Names returns the names of the registered providers in registration order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string{}, r.order...)
+}
+
+// Shift reduces a statement to a range statement: the claim satisfies the statement exactly when
+// offset - claim (negate) or claim + offset (otherwise) is a small non-negative number.
+// Providers prove that range about the commitment bbs.ShiftCommitment derives.
+func Shift(statement vc.PredicateStatement) (negate bool, offset *big.Int, err error) {
+	if err := statement.Validate(); err != nil {
+		return false, nil, err
+	}
+
+	value := big.NewInt(statement.Value)
+	switch statement.Operator {
+	case vc.PredicateGreaterOrEqual: // claim - value >= 0
+		return false, value.Neg(value), nil
+	case vc.PredicateGreaterThan: // claim - value - 1 >= 0
+		return false, value.Neg(value.Add(value, big.NewInt(1))), nil
+	case vc.PredicateLessOrEqual: // value - claim >= 0
+		return true, value, nil
+	default: // value - 1 - claim >= 0
+		return true, value.Sub(value, big.NewInt(1)), nil
+	}
+}
+
+// CheckEncoding rejects commitments whose values do not compare as numbers
+func CheckEncoding(statement vc.PredicateStatement, commitment vc.AttributeCommitment) error {
+	if commitment.Claim != statement.Claim {
+		return fmt.Errorf("commitment is to claim %s, not %s", commitment.Claim, statement.Claim)
+	}
+	if commitment.Encoding != vc.CommitmentEncodingInteger && commitment.Encoding != vc.CommitmentEncodingDate {
+		return fmt.Errorf("claim %s is committed with %s encoding, which does not support comparisons", statement.Claim, commitment.Encoding)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package predicate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+func commitClaim(t *testing.T, claim string, encoding vc.CommitmentEncoding, value int64) (vc.AttributeCommitment, vc.CommitmentOpening) {
+	scalar := bbs.ScalarFromInt(big.NewInt(value))
+	commitment, blinding, err := bbs.Commit(scalar)
+	require.NoError(t, err)
+
+	return vc.AttributeCommitment{Claim: claim, Encoding: encoding, Commitment: commitment},
+		vc.CommitmentOpening{Claim: claim, Encoding: encoding, Value: scalar, Blinding: blinding}
+}
+
+func TestRangeProvider(t *testing.T) {
+	provider := NewRangeProvider()
+	commitment, opening := commitClaim(t, "salary", vc.CommitmentEncodingInteger, 72000)
+
+	tests := []struct {
+		operator  vc.PredicateOperator
+		value     int64
+		satisfied bool
+	}{
+		{vc.PredicateGreaterThan, 50000, true},
+		{vc.PredicateGreaterThan, 72000, false},
+		{vc.PredicateGreaterOrEqual, 72000, true},
+		{vc.PredicateGreaterOrEqual, 72001, false},
+		{vc.PredicateLessThan, 72001, true},
+		{vc.PredicateLessThan, 72000, false},
+		{vc.PredicateLessOrEqual, 72000, true},
+		{vc.PredicateLessOrEqual, -1, false},
+	}
+
+	for _, tt := range tests {
+		statement := vc.PredicateStatement{Claim: "salary", Operator: tt.operator, Value: tt.value}
+		t.Run(statement.String(), func(t *testing.T) {
+			context := vc.PredicateContext("urn:uuid:credential", statement, commitment.Commitment, "nonce")
+
+			proof, err := provider.Prove(statement, commitment, opening, context)
+			if !tt.satisfied {
+				assert.ErrorContains(t, err, "does not satisfy")
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, provider.Verify(statement, commitment, proof, context))
+		})
+	}
+
+	t.Run("Proof Bound To Statement", func(t *testing.T) {
+		statement := vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 50000}
+		context := vc.PredicateContext("urn:uuid:credential", statement, commitment.Commitment, "nonce")
+		proof, err := provider.Prove(statement, commitment, opening, context)
+		require.NoError(t, err)
+
+		stronger := vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 100000}
+		strongerContext := vc.PredicateContext("urn:uuid:credential", stronger, commitment.Commitment, "nonce")
+		assert.Error(t, provider.Verify(stronger, commitment, proof, strongerContext))
+
+		// Replaying the proof under another nonce fails
+		replayed := vc.PredicateContext("urn:uuid:credential", statement, commitment.Commitment, "other-nonce")
+		assert.Error(t, provider.Verify(statement, commitment, proof, replayed))
+	})
+
+	t.Run("Message Encoding Rejected", func(t *testing.T) {
+		commitment, opening := commitClaim(t, "nationality", vc.CommitmentEncodingMessage, 0)
+		statement := vc.PredicateStatement{Claim: "nationality", Operator: vc.PredicateGreaterThan, Value: 0}
+		_, err := provider.Prove(statement, commitment, opening, nil)
+		assert.ErrorContains(t, err, "does not support comparisons")
+	})
+
+	t.Run("Dates Compare As YYYYMMDD", func(t *testing.T) {
+		commitment, opening := commitClaim(t, "dateOfBirth", vc.CommitmentEncodingDate, 20000120)
+		statement := vc.PredicateStatement{Claim: "dateOfBirth", Operator: vc.PredicateLessThan, Value: 20071016}
+		context := vc.PredicateContext("urn:uuid:credential", statement, commitment.Commitment, "nonce")
+
+		proof, err := provider.Prove(statement, commitment, opening, context)
+		require.NoError(t, err)
+		assert.NoError(t, provider.Verify(statement, commitment, proof, context))
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistry(NewRangeProvider())
+	require.NoError(t, err)
+
+	provider, ok := registry.Default()
+	require.True(t, ok)
+	assert.Equal(t, RangeProviderName, provider.Name())
+
+	_, ok = registry.Get(RangeProviderName)
+	assert.True(t, ok)
+	_, ok = registry.Get("groth16")
+	assert.False(t, ok)
+
+	assert.ErrorContains(t, registry.Register(NewRangeProvider()), "already registered")
+	assert.Equal(t, []string{RangeProviderName}, registry.Names())
+
+	empty, err := NewRegistry()
+	require.NoError(t, err)
+	_, ok = empty.Default()
+	assert.False(t, ok)
+}
+
+func TestShift(t *testing.T) {
+	_, _, err := Shift(vc.PredicateStatement{Claim: "salary", Operator: "ne", Value: 1})
+	assert.ErrorContains(t, err, "unknown predicate operator")
+}
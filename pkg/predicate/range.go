@@ -0,0 +1,70 @@
+package predicate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// RangeProviderName names proofs created by RangeProvider
+const RangeProviderName = "bbs-bit-range"
+
+// RangeProvider proves predicates with sigma-protocol range proofs directly over the attribute
+// commitments. It needs no trusted setup or key material, at the cost of proofs of a few tens of kilobytes.
+type RangeProvider struct{}
+
+// NewRangeProvider creates a range proof provider
+func NewRangeProvider() *RangeProvider {
+	return &RangeProvider{}
+}
+
+// Name returns RangeProviderName
+func (p *RangeProvider) Name() string {
+	return RangeProviderName
+}
+
+// Prove proves the statement with a 64-bit range proof over the shifted commitment
+func (p *RangeProvider) Prove(statement vc.PredicateStatement, commitment vc.AttributeCommitment, opening vc.CommitmentOpening, context []byte) ([]byte, error) {
+	if err := CheckEncoding(statement, commitment); err != nil {
+		return nil, err
+	}
+
+	negate, offset, err := Shift(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	value, blinding := bbs.ShiftOpening(opening.Value, opening.Blinding, negate, offset)
+	proof, err := bbs.CreateRangeProof(value, blinding, bbs.MaxRangeBits, context)
+	if err != nil {
+		return nil, fmt.Errorf("claim %s does not satisfy %s: %w", statement.Claim, statement, err)
+	}
+
+	return json.Marshal(proof)
+}
+
+// Verify checks a range proof over the shifted commitment
+func (p *RangeProvider) Verify(statement vc.PredicateStatement, commitment vc.AttributeCommitment, proof []byte, context []byte) error {
+	if err := CheckEncoding(statement, commitment); err != nil {
+		return err
+	}
+
+	negate, offset, err := Shift(statement)
+	if err != nil {
+		return err
+	}
+
+	shifted, err := bbs.ShiftCommitment(commitment.Commitment, negate, offset)
+	if err != nil {
+		return err
+	}
+
+	var rangeProof bbs.RangeProof
+	if err := json.Unmarshal(proof, &rangeProof); err != nil {
+		return fmt.Errorf("invalid range proof: %w", err)
+	}
+
+	return bbs.VerifyRangeProof(shifted, &rangeProof, bbs.MaxRangeBits, context)
+}
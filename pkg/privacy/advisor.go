@@ -0,0 +1,247 @@
+package privacy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// defaultSensitivity is used for claims without a registered sensitivity
+const defaultSensitivity = 0.5
+
+// Advisor computes minimal disclosures and privacy scores
+type Advisor struct {
+	mu            sync.RWMutex
+	purposes      map[string]*Purpose
+	sensitivities map[string]float64
+}
+
+// NewAdvisor creates an advisor with the built-in purposes and claim sensitivities
+func NewAdvisor() *Advisor {
+	advisor := &Advisor{
+		purposes:      make(map[string]*Purpose),
+		sensitivities: make(map[string]float64),
+	}
+	for _, purpose := range DefaultPurposes() {
+		advisor.RegisterPurpose(purpose)
+	}
+	for claim, sensitivity := range DefaultSensitivities() {
+		advisor.sensitivities[claim] = sensitivity
+	}
+	return advisor
+}
+
+// RegisterPurpose adds or replaces a recognized purpose
+func (a *Advisor) RegisterPurpose(purpose *Purpose) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.purposes[purpose.ID] = purpose
+}
+
+// GetPurpose returns a recognized purpose
+func (a *Advisor) GetPurpose(id string) (*Purpose, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	purpose, ok := a.purposes[id]
+	return purpose, ok
+}
+
+// Sensitivity returns how revealing a claim is, from 0 (harmless) to 1 (highly identifying)
+func (a *Advisor) Sensitivity(claim string) float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if sensitivity, ok := a.sensitivities[claim]; ok {
+		return sensitivity
+	}
+	return defaultSensitivity
+}
+
+// Advise analyses the claims requested from a credential offering the available claims.
+// With a recognized purpose, the minimal set picks the least revealing claim for each
+// requirement, and requested claims beyond that are reported. Without one, the minimal
+// set is the requested claims the credential can provide.
+func (a *Advisor) Advise(purposeID string, requested, available []string) *Advice {
+	advice := &Advice{
+		Purpose:       purposeID,
+		Substitutions: make(map[string]string),
+	}
+
+	availableSet := toSet(available)
+	requestedSet := toSet(requested)
+
+	purpose, recognized := a.GetPurpose(purposeID)
+	switch {
+	case recognized:
+		needed := make(map[string]bool)
+		for _, requirement := range purpose.Requirements {
+			chosen := ""
+			for _, alternative := range requirement.Alternatives {
+				if availableSet[alternative] {
+					chosen = alternative
+					break
+				}
+			}
+			if chosen == "" {
+				advice.Missing = append(advice.Missing, requirement.Alternatives[0])
+				continue
+			}
+
+			advice.MinimalClaims = appendUnique(advice.MinimalClaims, chosen)
+			for _, alternative := range requirement.Alternatives {
+				needed[alternative] = true
+				if requestedSet[alternative] && alternative != chosen {
+					advice.Substitutions[alternative] = chosen
+					advice.Warnings = append(advice.Warnings,
+						fmt.Sprintf("%s is requested but %s is sufficient for %s", alternative, chosen, purpose.ID))
+				}
+			}
+		}
+
+		for _, claim := range requested {
+			if !needed[claim] {
+				advice.Unnecessary = append(advice.Unnecessary, claim)
+				advice.Warnings = append(advice.Warnings,
+					fmt.Sprintf("%s is not needed for %s", claim, purpose.ID))
+			}
+		}
+	default:
+		if purposeID != "" {
+			advice.Warnings = append(advice.Warnings,
+				fmt.Sprintf("purpose %s is not recognized, data minimisation cannot be checked", purposeID))
+		}
+		for _, claim := range requested {
+			if availableSet[claim] {
+				advice.MinimalClaims = appendUnique(advice.MinimalClaims, claim)
+			} else {
+				advice.Missing = append(advice.Missing, claim)
+			}
+		}
+	}
+
+	sort.Strings(advice.MinimalClaims)
+	advice.Score = a.Score(requested, available)
+	advice.MinimalScore = a.Score(advice.MinimalClaims, available)
+
+	return advice
+}
+
+// Score returns the share of a credential's sensitive information that stays hidden
+// when the revealed claims are disclosed, from 0 (everything revealed) to 100
+func (a *Advisor) Score(revealed, available []string) int {
+	revealedSet := toSet(revealed)
+
+	var total, exposed float64
+	for _, claim := range uniqueStrings(available) {
+		sensitivity := a.Sensitivity(claim)
+		total += sensitivity
+		if revealedSet[claim] {
+			exposed += sensitivity
+		}
+	}
+
+	if total == 0 {
+		return 100
+	}
+	return int(math.Round(100 * (1 - exposed/total)))
+}
+
+// DefaultPurposes returns the built-in recognized purposes
+func DefaultPurposes() []*Purpose {
+	purposes := []*Purpose{
+		{
+			ID:          "nationality-check",
+			Description: "Confirm the holder's nationality",
+			Requirements: []Requirement{
+				{Alternatives: []string{"nationality"}},
+			},
+		},
+		{
+			ID:          "identity-verification",
+			Description: "Establish the holder's legal identity",
+			Requirements: []Requirement{
+				{Alternatives: []string{"firstName", "fullName"}},
+				{Alternatives: []string{"lastName", "fullName"}},
+				{Alternatives: []string{"dateOfBirth"}},
+				{Alternatives: []string{"idNumber"}},
+			},
+		},
+		{
+			ID:          "education-verification",
+			Description: "Confirm the holder holds a degree",
+			Requirements: []Requirement{
+				{Alternatives: []string{"degree"}},
+				{Alternatives: []string{"university"}},
+			},
+		},
+	}
+
+	for _, age := range []int{13, 16, 18, 21, 25, 65} {
+		purposes = append(purposes, &Purpose{
+			ID:          fmt.Sprintf("age-over-%d", age),
+			Description: fmt.Sprintf("Confirm the holder is at least %d years old", age),
+			Requirements: []Requirement{
+				{Alternatives: []string{fmt.Sprintf("ageOver%d", age), "birthYear", "dateOfBirth"}},
+			},
+		})
+	}
+
+	return purposes
+}
+
+// DefaultSensitivities returns how revealing the commonly used claims are
+func DefaultSensitivities() map[string]float64 {
+	return map[string]float64{
+		"idNumber":       1.0,
+		"address":        0.9,
+		"dateOfBirth":    0.8,
+		"fullName":       0.7,
+		"lastName":       0.6,
+		"birthYear":      0.5,
+		"firstName":      0.4,
+		"nationality":    0.3,
+		"gpa":            0.3,
+		"graduationYear": 0.3,
+		"university":     0.2,
+		"major":          0.2,
+		"degree":         0.2,
+		"ageCategory":    0.2,
+		"documentType":   0.1,
+		"issuedAt":       0.1,
+		"validUntil":     0.1,
+		"ageOver13":      0.1,
+		"ageOver16":      0.1,
+		"ageOver18":      0.1,
+		"ageOver21":      0.1,
+		"ageOver25":      0.1,
+		"ageOver65":      0.1,
+	}
+}
+
+// toSet converts a slice to a set
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// uniqueStrings removes duplicates while preserving order
+func uniqueStrings(values []string) []string {
+	var result []string
+	for _, v := range values {
+		result = appendUnique(result, v)
+	}
+	return result
+}
+
+// appendUnique appends s to values unless already present
+func appendUnique(values []string, s string) []string {
+	for _, v := range values {
+		if v == s {
+			return values
+		}
+	}
+	return append(values, s)
+}
@@ -0,0 +1,81 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvise(t *testing.T) {
+	advisor := NewAdvisor()
+	available := []string{"firstName", "lastName", "dateOfBirth", "nationality", "ageOver18", "ageOver21"}
+
+	t.Run("Prefers Age Predicate Over Date Of Birth", func(t *testing.T) {
+		advice := advisor.Advise("age-over-18", []string{"dateOfBirth"}, available)
+
+		assert.Equal(t, []string{"ageOver18"}, advice.MinimalClaims)
+		assert.Equal(t, "ageOver18", advice.Substitutions["dateOfBirth"])
+		assert.NotEmpty(t, advice.Warnings)
+		assert.Greater(t, advice.MinimalScore, advice.Score)
+	})
+
+	t.Run("Falls Back To Date Of Birth", func(t *testing.T) {
+		advice := advisor.Advise("age-over-65", []string{"dateOfBirth"}, available)
+
+		assert.Equal(t, []string{"dateOfBirth"}, advice.MinimalClaims)
+		assert.Empty(t, advice.Substitutions)
+		assert.Empty(t, advice.Warnings)
+		assert.Equal(t, advice.MinimalScore, advice.Score)
+	})
+
+	t.Run("Flags Unnecessary Claims", func(t *testing.T) {
+		advice := advisor.Advise("nationality-check", []string{"nationality", "lastName"}, available)
+
+		assert.Equal(t, []string{"nationality"}, advice.MinimalClaims)
+		assert.Equal(t, []string{"lastName"}, advice.Unnecessary)
+		assert.Len(t, advice.Warnings, 1)
+	})
+
+	t.Run("Reports Missing Requirements", func(t *testing.T) {
+		advice := advisor.Advise("education-verification", []string{"degree"}, available)
+
+		assert.Empty(t, advice.MinimalClaims)
+		assert.Equal(t, []string{"degree", "university"}, advice.Missing)
+	})
+
+	t.Run("Unrecognized Purpose", func(t *testing.T) {
+		advice := advisor.Advise("marketing", []string{"nationality", "gpa"}, available)
+
+		assert.Equal(t, []string{"nationality"}, advice.MinimalClaims)
+		assert.Equal(t, []string{"gpa"}, advice.Missing)
+		assert.Len(t, advice.Warnings, 1)
+		assert.Contains(t, advice.Warnings[0], "not recognized")
+	})
+
+	t.Run("No Purpose", func(t *testing.T) {
+		advice := advisor.Advise("", []string{"nationality"}, available)
+
+		assert.Equal(t, []string{"nationality"}, advice.MinimalClaims)
+		assert.Empty(t, advice.Warnings)
+	})
+}
+
+func TestScore(t *testing.T) {
+	advisor := NewAdvisor()
+	available := []string{"firstName", "dateOfBirth", "ageOver18"}
+
+	assert.Equal(t, 100, advisor.Score(nil, available))
+	assert.Equal(t, 0, advisor.Score(available, available))
+	assert.Greater(t, advisor.Score([]string{"ageOver18"}, available), advisor.Score([]string{"dateOfBirth"}, available))
+	assert.Equal(t, 100, advisor.Score(nil, nil))
+}
+
+func TestLevelOf(t *testing.T) {
+	advisor := NewAdvisor()
+
+	assert.Equal(t, SensitivityLow, LevelOf(advisor.Sensitivity("ageOver18")))
+	assert.Equal(t, SensitivityMedium, LevelOf(advisor.Sensitivity("firstName")))
+	assert.Equal(t, SensitivityMedium, LevelOf(advisor.Sensitivity("unregisteredClaim")))
+	assert.Equal(t, SensitivityHigh, LevelOf(advisor.Sensitivity("dateOfBirth")))
+	assert.Equal(t, SensitivityHigh, LevelOf(advisor.Sensitivity("idNumber")))
+}
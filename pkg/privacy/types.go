@@ -0,0 +1,53 @@
+package privacy
+
+// Requirement is one piece of information a purpose needs, satisfied by any of
+// the listed claims. Claims are ordered from least to most revealing.
+type Requirement struct {
+	Alternatives []string `json:"alternatives"`
+}
+
+// Purpose describes a recognized reason for requesting data and what it minimally needs
+type Purpose struct {
+	ID           string        `json:"id"`
+	Description  string        `json:"description"`
+	Requirements []Requirement `json:"requirements"`
+}
+
+// Advice is the result of analysing a disclosure against a purpose
+type Advice struct {
+	Purpose string `json:"purpose,omitempty"`
+	// MinimalClaims is the smallest set of available claims that satisfies the request
+	MinimalClaims []string `json:"minimalClaims"`
+	// Substitutions maps requested claims to less revealing claims that serve the same purpose
+	Substitutions map[string]string `json:"substitutions,omitempty"`
+	// Unnecessary lists requested claims the purpose does not need
+	Unnecessary []string `json:"unnecessary,omitempty"`
+	// Missing lists requirements that no available claim can satisfy
+	Missing  []string `json:"missing,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// Score is the share of the credential's sensitive information kept hidden, 0-100
+	Score int `json:"score"`
+	// MinimalScore is the score that revealing only MinimalClaims would achieve
+	MinimalScore int `json:"minimalScore"`
+}
+
+// SensitivityLevel buckets a claim's sensitivity for display, e.g. on a consent screen
+type SensitivityLevel string
+
+const (
+	SensitivityLow    SensitivityLevel = "low"
+	SensitivityMedium SensitivityLevel = "medium"
+	SensitivityHigh   SensitivityLevel = "high"
+)
+
+// LevelOf returns the level of a sensitivity: below 0.3 is low, below 0.7 medium, the rest high
+func LevelOf(sensitivity float64) SensitivityLevel {
+	switch {
+	case sensitivity < 0.3:
+		return SensitivityLow
+	case sensitivity < 0.7:
+		return SensitivityMedium
+	default:
+		return SensitivityHigh
+	}
+}
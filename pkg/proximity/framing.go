@@ -0,0 +1,257 @@
+// Package proximity frames messages, such as presentations, for short-range links like NFC and
+// Bluetooth LE that carry a few hundred bytes at a time. A message is split into frames no larger
+// than the link's MTU. Each frame carries the message ID, its sequence number, the frame count and
+// a CRC-32, so the receiver can reassemble frames that arrive out of order or twice and drop
+// corrupted ones. The package does not open links; a Transport adapts one.
+package proximity
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// Frame layout, big-endian: version (1 byte), message ID (4), sequence number (2), frame count (2),
+// payload, CRC-32 IEEE of everything before it (4)
+const (
+	frameVersion = 1
+	headerSize   = 9
+	trailerSize  = 4
+	// MinMTU is the smallest frame size, which carries one payload byte
+	MinMTU = headerSize + trailerSize + 1
+	// MaxFrames is the most frames a message may be split into
+	MaxFrames = 1<<16 - 1
+)
+
+// DefaultMaxMessageSize bounds the messages a Reassembler accepts unless told otherwise
+const DefaultMaxMessageSize = 1 << 20
+
+var (
+	// ErrChecksum is returned for a frame whose CRC-32 does not match; nothing in it can be trusted
+	ErrChecksum = errors.New("frame checksum mismatch")
+	// ErrOtherMessage is returned for a frame of another message than the one being reassembled
+	ErrOtherMessage = errors.New("frame belongs to another message")
+	// ErrIncomplete is returned when the transport ends before every frame arrived
+	ErrIncomplete = errors.New("message incomplete")
+)
+
+// Transport moves frames over a link. ReadFrame returns io.EOF once the link is closed.
+type Transport interface {
+	WriteFrame(frame []byte) error
+	ReadFrame() ([]byte, error)
+}
+
+// Split frames a message for a link carrying at most mtu bytes at a time
+func Split(message []byte, mtu int) ([][]byte, error) {
+	if mtu < MinMTU {
+		return nil, fmt.Errorf("MTU %d is below the minimum of %d", mtu, MinMTU)
+	}
+	room := mtu - headerSize - trailerSize
+	total := (len(message) + room - 1) / room
+	if total == 0 {
+		total = 1
+	}
+	if total > MaxFrames {
+		return nil, fmt.Errorf("message of %d bytes needs %d frames at MTU %d, more than the %d allowed", len(message), total, mtu, MaxFrames)
+	}
+
+	var id [4]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate message ID: %w", err)
+	}
+
+	frames := make([][]byte, 0, total)
+	for seq := 0; seq < total; seq++ {
+		end := (seq + 1) * room
+		if end > len(message) {
+			end = len(message)
+		}
+		payload := message[seq*room : end]
+
+		frame := make([]byte, 0, headerSize+len(payload)+trailerSize)
+		frame = append(frame, frameVersion)
+		frame = append(frame, id[:]...)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(seq))
+		frame = binary.BigEndian.AppendUint16(frame, uint16(total))
+		frame = append(frame, payload...)
+		frame = binary.BigEndian.AppendUint32(frame, crc32.ChecksumIEEE(frame))
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// frameHeader is the decoded header of a frame
+type frameHeader struct {
+	id    uint32
+	seq   int
+	total int
+}
+
+// parseFrame checks a frame's checksum and returns its header and payload
+func parseFrame(frame []byte) (frameHeader, []byte, error) {
+	if len(frame) < headerSize+trailerSize {
+		return frameHeader{}, nil, fmt.Errorf("frame of %d bytes is shorter than its header", len(frame))
+	}
+	body := frame[:len(frame)-trailerSize]
+	if binary.BigEndian.Uint32(frame[len(body):]) != crc32.ChecksumIEEE(body) {
+		return frameHeader{}, nil, ErrChecksum
+	}
+	if body[0] != frameVersion {
+		return frameHeader{}, nil, fmt.Errorf("unsupported frame version %d", body[0])
+	}
+	header := frameHeader{
+		id:    binary.BigEndian.Uint32(body[1:5]),
+		seq:   int(binary.BigEndian.Uint16(body[5:7])),
+		total: int(binary.BigEndian.Uint16(body[7:9])),
+	}
+	if header.total == 0 || header.seq >= header.total {
+		return frameHeader{}, nil, fmt.Errorf("invalid frame sequence %d of %d", header.seq, header.total)
+	}
+	return header, body[headerSize:], nil
+}
+
+// Reassembler collects the frames of one message. The first valid frame fixes the message.
+type Reassembler struct {
+	maxSize  int
+	started  bool
+	header   frameHeader
+	payloads map[int][]byte
+	size     int
+}
+
+// NewReassembler creates a reassembler for messages of at most maxSize bytes. A maxSize of zero
+// means DefaultMaxMessageSize.
+func NewReassembler(maxSize int) *Reassembler {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
+	return &Reassembler{maxSize: maxSize, payloads: make(map[int][]byte)}
+}
+
+// Add adds a frame and returns the message once its last frame has arrived. Repeated frames are
+// ignored. The reassembler is unchanged when Add returns an error, so the caller may keep reading.
+func (r *Reassembler) Add(frame []byte) ([]byte, bool, error) {
+	header, payload, err := parseFrame(frame)
+	if err != nil {
+		return nil, false, err
+	}
+	if r.started && (header.id != r.header.id || header.total != r.header.total) {
+		return nil, false, ErrOtherMessage
+	}
+	if previous, ok := r.payloads[header.seq]; ok {
+		if string(previous) != string(payload) {
+			return nil, false, fmt.Errorf("conflicting copies of frame %d", header.seq)
+		}
+		return nil, false, nil
+	}
+	if r.size+len(payload) > r.maxSize {
+		return nil, false, fmt.Errorf("message exceeds the maximum size of %d bytes", r.maxSize)
+	}
+
+	r.started, r.header = true, header
+	r.payloads[header.seq] = append([]byte(nil), payload...)
+	r.size += len(payload)
+	if len(r.payloads) < header.total {
+		return nil, false, nil
+	}
+
+	message := make([]byte, 0, r.size)
+	for seq := 0; seq < header.total; seq++ {
+		message = append(message, r.payloads[seq]...)
+	}
+	r.Reset()
+	return message, true, nil
+}
+
+// Missing lists the sequence numbers of the frames not yet received, or nil before the first frame
+func (r *Reassembler) Missing() []int {
+	if !r.started {
+		return nil
+	}
+	var missing []int
+	for seq := 0; seq < r.header.total; seq++ {
+		if _, ok := r.payloads[seq]; !ok {
+			missing = append(missing, seq)
+		}
+	}
+	return missing
+}
+
+// Reset drops a partly reassembled message
+func (r *Reassembler) Reset() {
+	r.started = false
+	r.header = frameHeader{}
+	r.payloads = make(map[int][]byte)
+	r.size = 0
+}
+
+// Send splits a message and writes its frames to the transport in order
+func Send(transport Transport, message []byte, mtu int) error {
+	frames, err := Split(message, mtu)
+	if err != nil {
+		return err
+	}
+	for seq, frame := range frames {
+		if err := transport.WriteFrame(frame); err != nil {
+			return fmt.Errorf("failed to write frame %d of %d: %w", seq, len(frames), err)
+		}
+	}
+	return nil
+}
+
+// Receive reads frames until a message is complete. Corrupted frames and frames of other messages
+// are dropped, so a link that repeats its frames recovers from them.
+func Receive(transport Transport, maxSize int) ([]byte, error) {
+	reassembler := NewReassembler(maxSize)
+	for {
+		frame, err := transport.ReadFrame()
+		if errors.Is(err, io.EOF) {
+			if missing := reassembler.Missing(); missing != nil {
+				return nil, fmt.Errorf("%w: frames %v not received", ErrIncomplete, missing)
+			}
+			return nil, ErrIncomplete
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read frame: %w", err)
+		}
+
+		message, done, err := reassembler.Add(frame)
+		switch {
+		case errors.Is(err, ErrChecksum), errors.Is(err, ErrOtherMessage):
+			continue
+		case err != nil:
+			return nil, err
+		case done:
+			return message, nil
+		}
+	}
+}
+
+// SendPresentation writes a presentation's JSON to the transport
+func SendPresentation(transport Transport, presentation *vc.VerifiablePresentation, mtu int) error {
+	message, err := json.Marshal(presentation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal presentation: %w", err)
+	}
+	return Send(transport, message, mtu)
+}
+
+// ReceivePresentation reads a presentation from the transport. It also returns the presentation's
+// JSON, for checks on the document as received.
+func ReceivePresentation(transport Transport, maxSize int) (*vc.VerifiablePresentation, []byte, error) {
+	message, err := Receive(transport, maxSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	var presentation vc.VerifiablePresentation
+	if err := json.Unmarshal(message, &presentation); err != nil {
+		return nil, nil, fmt.Errorf("invalid presentation: %w", err)
+	}
+	return &presentation, message, nil
+}
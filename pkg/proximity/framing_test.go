@@ -0,0 +1,193 @@
+package proximity
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	mathrand "math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// simulatedLink is a Transport that delivers the written frames shuffled, repeated, corrupted or
+// dropped, as a noisy radio link might
+type simulatedLink struct {
+	rng       *mathrand.Rand
+	shuffle   bool
+	repeat    bool
+	corrupt   int // corrupt every corrupt-th frame
+	drop      int // drop frame drop-1 (sequence numbers from 0)
+	sent      [][]byte
+	delivered [][]byte
+}
+
+func newSimulatedLink() *simulatedLink {
+	return &simulatedLink{rng: mathrand.New(mathrand.NewSource(7))}
+}
+
+func (l *simulatedLink) WriteFrame(frame []byte) error {
+	l.sent = append(l.sent, append([]byte(nil), frame...))
+	return nil
+}
+
+func (l *simulatedLink) ReadFrame() ([]byte, error) {
+	if l.delivered == nil {
+		l.deliver()
+	}
+	if len(l.delivered) == 0 {
+		return nil, io.EOF
+	}
+	frame := l.delivered[0]
+	l.delivered = l.delivered[1:]
+	return frame, nil
+}
+
+// deliver decides what the receiver reads, once every frame has been sent
+func (l *simulatedLink) deliver() {
+	l.delivered = [][]byte{}
+	for i, frame := range l.sent {
+		if l.drop != 0 && i == l.drop-1 {
+			continue
+		}
+		if l.corrupt != 0 && i%l.corrupt == 0 {
+			damaged := append([]byte(nil), frame...)
+			damaged[len(damaged)/2] ^= 0xff
+			l.delivered = append(l.delivered, damaged)
+		}
+		l.delivered = append(l.delivered, frame)
+		if l.repeat {
+			l.delivered = append(l.delivered, frame)
+		}
+	}
+	if l.shuffle {
+		l.rng.Shuffle(len(l.delivered), func(i, j int) {
+			l.delivered[i], l.delivered[j] = l.delivered[j], l.delivered[i]
+		})
+	}
+}
+
+func TestFraming(t *testing.T) {
+	message := make([]byte, 5000)
+	_, err := rand.Read(message)
+	require.NoError(t, err)
+
+	t.Run("Frames Fit The MTU", func(t *testing.T) {
+		for _, mtu := range []int{MinMTU, 20, 185, 512} {
+			frames, err := Split(message, mtu)
+			require.NoError(t, err)
+			for _, frame := range frames {
+				assert.LessOrEqual(t, len(frame), mtu)
+			}
+
+			reassembler := NewReassembler(0)
+			for i, frame := range frames {
+				got, done, err := reassembler.Add(frame)
+				require.NoError(t, err)
+				assert.Equal(t, i == len(frames)-1, done)
+				if done {
+					assert.Equal(t, message, got)
+				}
+			}
+		}
+
+		_, err := Split(message, MinMTU-1)
+		assert.Error(t, err)
+		_, err = Split(make([]byte, MaxFrames+1), MinMTU)
+		assert.Error(t, err)
+	})
+
+	t.Run("Empty Message", func(t *testing.T) {
+		link := newSimulatedLink()
+		require.NoError(t, Send(link, nil, 20))
+		got, err := Receive(link, 0)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("Noisy Link", func(t *testing.T) {
+		link := newSimulatedLink()
+		link.shuffle, link.repeat, link.corrupt = true, true, 3
+		require.NoError(t, Send(link, message, 185))
+
+		got, err := Receive(link, 0)
+		require.NoError(t, err)
+		assert.Equal(t, message, got)
+	})
+
+	t.Run("Lost Frame", func(t *testing.T) {
+		link := newSimulatedLink()
+		link.drop = 4
+		require.NoError(t, Send(link, message, 185))
+
+		_, err := Receive(link, 0)
+		assert.ErrorIs(t, err, ErrIncomplete)
+		assert.ErrorContains(t, err, "frames [3] not received")
+	})
+
+	t.Run("Frames Are Checked", func(t *testing.T) {
+		frames, err := Split(message, 185)
+		require.NoError(t, err)
+		others, err := Split(message, 185)
+		require.NoError(t, err)
+
+		reassembler := NewReassembler(0)
+		_, _, err = reassembler.Add(frames[0])
+		require.NoError(t, err)
+
+		damaged := append([]byte(nil), frames[1]...)
+		damaged[headerSize] ^= 0x01
+		_, _, err = reassembler.Add(damaged)
+		assert.ErrorIs(t, err, ErrChecksum)
+
+		_, _, err = reassembler.Add(others[1])
+		assert.ErrorIs(t, err, ErrOtherMessage)
+
+		_, _, err = reassembler.Add(frames[1][:5])
+		assert.Error(t, err)
+		assert.Len(t, reassembler.Missing(), len(frames)-1)
+
+		reassembler.Reset()
+		assert.Nil(t, reassembler.Missing())
+		_, _, err = reassembler.Add(others[1])
+		assert.NoError(t, err)
+	})
+
+	t.Run("Size Limit", func(t *testing.T) {
+		link := newSimulatedLink()
+		require.NoError(t, Send(link, message, 512))
+		_, err := Receive(link, 1000)
+		assert.ErrorContains(t, err, "maximum size")
+	})
+
+	t.Run("Presentation", func(t *testing.T) {
+		presentation := &vc.VerifiablePresentation{
+			Context: []string{"https://www.w3.org/2018/credentials/v1"},
+			ID:      "urn:uuid:38d86e36-c5aa-4ccf-9b60-85b670cfd81f",
+			Type:    []string{"VerifiablePresentation"},
+			Holder:  "did:example:holder",
+			Proof: &vc.Proof{
+				Type:               "Ed25519Signature2020",
+				Created:            time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+				VerificationMethod: "did:example:holder#key-1",
+				ProofPurpose:       "authentication",
+				ProofValue:         "z58aMEHjBijkZoUFpDCw4f4VmoLn2EEFutTNa9kwW4we",
+			},
+		}
+		link := newSimulatedLink()
+		link.shuffle = true
+		require.NoError(t, SendPresentation(link, presentation, 23))
+
+		got, raw, err := ReceivePresentation(link, 0)
+		require.NoError(t, err)
+		assert.Equal(t, presentation, got)
+		want, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		assert.True(t, bytes.Equal(want, raw))
+	})
+}
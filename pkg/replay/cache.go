@@ -0,0 +1,77 @@
+// Package replay remembers verified presentations so the same presentation cannot be accepted twice,
+// whatever the verifier does with nonces.
+package replay
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultCapacity is how many presentations a cache remembers before evicting the least recently seen
+const DefaultCapacity = 10000
+
+// Cache records presentation proof hashes until their presentations stop being valid
+type Cache interface {
+	// Add records key until expiresAt. It returns false when key is already recorded and not yet expired.
+	Add(key string, expiresAt time.Time) (bool, error)
+}
+
+// ProofHash identifies a presentation by what its proofs sign or prove: the value and nonce of
+// the presentation proof and of every presented credential's proof. Unsigned proof fields such
+// as the creation time, re-encodings of the same proof values and a new presentation ID all
+// yield the same hash.
+func ProofHash(presentation *vc.VerifiablePresentation) (string, error) {
+	if presentation == nil {
+		return "", fmt.Errorf("presentation is nil")
+	}
+
+	h := sha256.New()
+	h.Write([]byte("presentation"))
+	var value []byte
+	if presentation.Proof != nil && strings.HasPrefix(presentation.Proof.ProofValue, "z") {
+		value = base58.Decode(presentation.Proof.ProofValue[1:])
+	}
+	writeProof(h, presentation.Proof, value)
+
+	for _, credential := range presentation.VerifiableCredential {
+		h.Write([]byte("credential"))
+		var proof *vc.Proof
+		value = nil
+		if credential != nil && credential.Proof != nil {
+			proof = credential.Proof
+			value, _ = base64.StdEncoding.DecodeString(proof.ProofValue)
+		}
+		writeProof(h, proof, value)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeProof writes a proof's decoded value, or its encoded value when it does not decode, and
+// its nonce. Each field is length-prefixed so neighbouring fields cannot run together.
+func writeProof(h hash.Hash, proof *vc.Proof, value []byte) {
+	var nonce []byte
+	if proof != nil {
+		if len(value) == 0 {
+			value = []byte(proof.ProofValue)
+		}
+		nonce = []byte(proof.Nonce)
+	}
+
+	for _, field := range [][]byte{value, nonce} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		h.Write(length[:])
+		h.Write(field)
+	}
+}
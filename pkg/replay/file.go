@@ -0,0 +1,130 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// record is one line of a cache file
+type record struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FileCache is a MemoryCache whose keys are also appended to a file, so a restarted verifier still
+// rejects presentations it accepted before the restart
+type FileCache struct {
+	mu      sync.Mutex
+	memory  *MemoryCache
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// OpenFileCache loads the unexpired keys recorded at path, compacts the file and appends new keys
+// to it. The file is created when it does not exist.
+func OpenFileCache(path string, capacity int) (*FileCache, error) {
+	memory := NewMemoryCache(capacity)
+	now := time.Now()
+
+	existing, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open replay cache: %w", err)
+	}
+	if err == nil {
+		scanner := bufio.NewScanner(existing)
+		for line := 1; scanner.Scan(); line++ {
+			var r record
+			if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+				existing.Close()
+				return nil, fmt.Errorf("replay cache line %d: %w", line, err)
+			}
+			if now.Before(r.ExpiresAt) {
+				memory.add(r.Key, r.ExpiresAt, now)
+			}
+		}
+		existing.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read replay cache: %w", err)
+		}
+	}
+
+	// Rewrite the file with only the keys that are still live
+	compacted := path + ".tmp"
+	file, err := os.OpenFile(compacted, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compact replay cache: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	for _, live := range memory.live(now) {
+		if err := encoder.Encode(record{Key: live.key, ExpiresAt: live.expiresAt}); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to compact replay cache: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compact replay cache: %w", err)
+	}
+	if err := os.Rename(compacted, path); err != nil {
+		return nil, fmt.Errorf("failed to compact replay cache: %w", err)
+	}
+
+	file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay cache: %w", err)
+	}
+
+	return &FileCache{
+		memory:  memory,
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Add records key until expiresAt, returning false when it is already recorded and not yet expired.
+// A key is remembered in memory even when appending it to the file fails.
+func (c *FileCache) Add(key string, expiresAt time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	added, _ := c.memory.Add(key, expiresAt)
+	if !added {
+		return false, nil
+	}
+
+	if err := c.encoder.Encode(record{Key: key, ExpiresAt: expiresAt}); err != nil {
+		return true, fmt.Errorf("failed to persist replay cache entry: %w", err)
+	}
+	return true, nil
+}
+
+// Len returns how many keys are recorded, including expired keys not yet evicted
+func (c *FileCache) Len() int {
+	return c.memory.Len()
+}
+
+// Ping reports whether the cache file is still accessible
+func (c *FileCache) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.file.Stat()
+	return err
+}
+
+// Close closes the cache file
+func (c *FileCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.file.Close()
+}
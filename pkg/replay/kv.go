@@ -0,0 +1,34 @@
+package replay
+
+import (
+	"context"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// KVCache is a Cache kept in a key-value store. Keys expire in the store when they may be forgotten,
+// so the cache needs no capacity and every server instance sharing the store rejects the same replays.
+type KVCache struct {
+	store storage.KVStore
+}
+
+// NewKVCache creates a cache on top of store
+func NewKVCache(store storage.KVStore) *KVCache {
+	return &KVCache{store: store}
+}
+
+// Add records key until expiresAt, returning false when it is already recorded and not yet expired
+func (c *KVCache) Add(key string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// Already expired: nothing to remember, and nothing it could collide with
+		return true, nil
+	}
+	return c.store.SetNX(key, []byte(expiresAt.UTC().Format(time.RFC3339Nano)), ttl)
+}
+
+// Ping reports whether the underlying store is reachable
+func (c *KVCache) Ping(ctx context.Context) error {
+	return c.store.Ping(ctx)
+}
@@ -0,0 +1,93 @@
+package replay
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// entry is a recorded key and when it may be forgotten
+type entry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// MemoryCache is a Cache bounded to a fixed number of keys. When full it evicts the least recently
+// seen key, so size it to hold every presentation accepted within the validity window.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // most recently seen at the front
+	entries  map[string]*list.Element
+}
+
+// NewMemoryCache creates an in-memory cache holding up to capacity keys
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Add records key until expiresAt, returning false when it is already recorded and not yet expired
+func (c *MemoryCache) Add(key string, expiresAt time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.add(key, expiresAt, time.Now()), nil
+}
+
+// add records key unless it is present at now
+func (c *MemoryCache) add(key string, expiresAt, now time.Time) bool {
+	if element, ok := c.entries[key]; ok {
+		recorded := element.Value.(*entry)
+		if now.Before(recorded.expiresAt) {
+			c.order.MoveToFront(element)
+			return false
+		}
+		recorded.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return true
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+	return true
+}
+
+// Len returns how many keys are recorded, including expired keys not yet evicted
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Ping reports whether the cache is reachable; an in-memory cache always is
+func (c *MemoryCache) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// live returns the recorded entries that have not expired at now, least recently seen first
+func (c *MemoryCache) live(now time.Time) []entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]entry, 0, c.order.Len())
+	for element := c.order.Back(); element != nil; element = element.Prev() {
+		if recorded := element.Value.(*entry); now.Before(recorded.expiresAt) {
+			entries = append(entries, *recorded)
+		}
+	}
+	return entries
+}
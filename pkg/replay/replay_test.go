@@ -0,0 +1,130 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	until := time.Now().Add(time.Hour)
+
+	added, err := cache.Add("a", until)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, _ = cache.Add("a", until)
+	assert.False(t, added, "a key is rejected while it is recorded")
+
+	// Expired keys can be recorded again
+	added, _ = cache.Add("expired", time.Now().Add(-time.Second))
+	assert.True(t, added)
+	added, _ = cache.Add("expired", until)
+	assert.True(t, added)
+
+	// "a" was seen more recently than "expired", so "expired" is evicted first
+	_, _ = cache.Add("a", until)
+	added, _ = cache.Add("b", until)
+	assert.True(t, added)
+	assert.Equal(t, 2, cache.Len())
+
+	added, _ = cache.Add("a", until)
+	assert.False(t, added)
+	added, _ = cache.Add("expired", until)
+	assert.True(t, added, "evicted keys are forgotten")
+}
+
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.jsonl")
+	until := time.Now().Add(time.Hour)
+
+	cache, err := OpenFileCache(path, 10)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b"} {
+		added, err := cache.Add(key, until)
+		require.NoError(t, err)
+		assert.True(t, added)
+	}
+	_, err = cache.Add("gone", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, cache.Close())
+
+	reopened, err := OpenFileCache(path, 10)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	assert.Equal(t, 2, reopened.Len(), "expired keys are dropped on load")
+	added, err := reopened.Add("a", until)
+	require.NoError(t, err)
+	assert.False(t, added, "keys survive a restart")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"gone"`, "the file is compacted on load")
+
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+	_, err = OpenFileCache(path, 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1")
+}
+
+func TestProofHash(t *testing.T) {
+	presentation := func() *vc.VerifiablePresentation {
+		return &vc.VerifiablePresentation{
+			ID:     "urn:uuid:1",
+			Holder: "did:example:holder",
+			VerifiableCredential: []*vc.DerivedCredential{{
+				CredentialSubject: map[string]interface{}{"ageOver18": true},
+				Proof:             &vc.Proof{Type: "BbsBlsSignatureProof2020", Created: time.Unix(1700000000, 0), Nonce: "abc", ProofValue: "AQI="},
+			}},
+			Proof: &vc.Proof{Type: "Ed25519Signature2020", Created: time.Unix(1700000000, 0), ProofValue: "z3yZe7d"},
+		}
+	}
+
+	original, err := ProofHash(presentation())
+	require.NoError(t, err)
+
+	rewrapped := presentation()
+	rewrapped.ID = "urn:uuid:2"
+	hash, err := ProofHash(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, original, hash, "the same proofs under a new presentation ID are a replay")
+
+	redated := presentation()
+	redated.Proof.Created = time.Now()
+	redated.VerifiableCredential[0].Proof.Created = time.Now()
+	redated.VerifiableCredential[0].Proof.Type = "BbsBlsSignatureProof2022"
+	hash, err = ProofHash(redated)
+	require.NoError(t, err)
+	assert.Equal(t, original, hash, "unsigned proof fields do not make a new presentation")
+
+	// "AQJ=" decodes to the same bytes as "AQI=": the unused padding bits differ
+	reencoded := presentation()
+	reencoded.VerifiableCredential[0].Proof.ProofValue = "AQJ="
+	hash, err = ProofHash(reencoded)
+	require.NoError(t, err)
+	assert.Equal(t, original, hash, "a re-encoded proof value is a replay")
+
+	resigned := presentation()
+	resigned.Proof.ProofValue = "z3yZe7e"
+	hash, err = ProofHash(resigned)
+	require.NoError(t, err)
+	assert.NotEqual(t, original, hash)
+
+	renonced := presentation()
+	renonced.VerifiableCredential[0].Proof.Nonce = "abd"
+	hash, err = ProofHash(renonced)
+	require.NoError(t, err)
+	assert.NotEqual(t, original, hash)
+
+	_, err = ProofHash(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when none of the requested locales is available
+const DefaultLocale = "en-US"
+
+// TemplateDisplay is a template's display metadata resolved for a single locale
+type TemplateDisplay struct {
+	TemplateID       string                 `json:"templateId"`
+	CredentialType   string                 `json:"credentialType"`
+	Locale           string                 `json:"locale"`
+	AvailableLocales []string               `json:"availableLocales"`
+	Credential       *Display               `json:"credential,omitempty"`
+	Claims           []ResolvedClaimDisplay `json:"claims"`
+}
+
+// ResolvedClaimDisplay is how a wallet should render a claim in the resolved locale
+type ResolvedClaimDisplay struct {
+	Key         string      `json:"key"`
+	Label       string      `json:"label"`
+	Description string      `json:"description,omitempty"`
+	Type        ClaimType   `json:"type"`
+	Format      ValueFormat `json:"format"`
+	Order       int         `json:"order"`
+	Required    bool        `json:"required"`
+}
+
+// Locales returns every locale the template has credential or claim display metadata for, sorted
+func (t *CredentialTemplate) Locales() []string {
+	seen := make(map[string]bool)
+	for _, display := range t.Display {
+		if display.Locale != "" {
+			seen[display.Locale] = true
+		}
+	}
+	for _, claim := range t.Claims {
+		for _, display := range claim.Display {
+			seen[display.Locale] = true
+		}
+	}
+
+	locales := make([]string, 0, len(seen))
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// ResolveDisplay resolves the template's display metadata for the best match of the requested
+// locales, which may be a single tag or an Accept-Language header value.
+// Labels missing in that locale fall back to DefaultLocale and then to the claim key.
+func (t *CredentialTemplate) ResolveDisplay(requested string) *TemplateDisplay {
+	available := t.Locales()
+	locale := MatchLocale(requested, available)
+
+	result := &TemplateDisplay{
+		TemplateID:       t.ID,
+		CredentialType:   t.CredentialType,
+		Locale:           locale,
+		AvailableLocales: available,
+		Claims:           make([]ResolvedClaimDisplay, 0, len(t.Claims)),
+	}
+
+	for _, candidate := range []string{locale, "", DefaultLocale} {
+		if display, ok := templateDisplay(t.Display, candidate); ok {
+			result.Credential = &display
+			break
+		}
+	}
+	if result.Credential == nil && len(t.Display) > 0 {
+		display := t.Display[0]
+		result.Credential = &display
+	}
+
+	for _, claim := range t.Claims {
+		resolved := ResolvedClaimDisplay{
+			Key:      claim.Key,
+			Label:    claim.Key,
+			Type:     claim.Type,
+			Format:   claim.Format,
+			Order:    claim.Order,
+			Required: claim.Required,
+		}
+		if resolved.Format == "" {
+			resolved.Format = defaultFormat(claim.Type)
+		}
+
+		if label, ok := claimLabel(claim.Display, locale); ok {
+			resolved.Label = label.Label
+			resolved.Description = label.Description
+		} else if label, ok := claimLabel(claim.Display, DefaultLocale); ok {
+			resolved.Label = label.Label
+			resolved.Description = label.Description
+		}
+		if resolved.Description == "" {
+			resolved.Description = claim.Description
+		}
+
+		result.Claims = append(result.Claims, resolved)
+	}
+
+	// Claims with an explicit order come first; the rest keep template order
+	sort.SliceStable(result.Claims, func(i, j int) bool {
+		a, b := result.Claims[i].Order, result.Claims[j].Order
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+
+	return result
+}
+
+// MatchLocale picks the available locale that best matches the requested locales.
+// Exact tags win over language-only matches; higher Accept-Language weights are tried first.
+func MatchLocale(requested string, available []string) string {
+	for _, tag := range parseAcceptLanguage(requested) {
+		for _, locale := range available {
+			if strings.EqualFold(locale, tag) {
+				return locale
+			}
+		}
+		language := primaryLanguage(tag)
+		for _, locale := range available {
+			if strings.EqualFold(primaryLanguage(locale), language) {
+				return locale
+			}
+		}
+	}
+
+	for _, locale := range available {
+		if locale == DefaultLocale {
+			return locale
+		}
+	}
+	if len(available) > 0 {
+		return available[0]
+	}
+	return DefaultLocale
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language value ordered by weight
+func parseAcceptLanguage(value string) []string {
+	type weighted struct {
+		tag    string
+		weight float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(value, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				weight = parsed
+			}
+		}
+		if weight <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, weight: weight})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].weight > tags[j].weight
+	})
+
+	result := make([]string, len(tags))
+	for i, tag := range tags {
+		result[i] = tag.tag
+	}
+	return result
+}
+
+func primaryLanguage(tag string) string {
+	language, _, _ := strings.Cut(tag, "-")
+	return language
+}
+
+func templateDisplay(displays []Display, locale string) (Display, bool) {
+	for _, display := range displays {
+		if display.Locale == locale {
+			return display, true
+		}
+	}
+	return Display{}, false
+}
+
+func claimLabel(displays []ClaimDisplay, locale string) (ClaimDisplay, bool) {
+	for _, display := range displays {
+		if display.Locale == locale {
+			return display, true
+		}
+	}
+	return ClaimDisplay{}, false
+}
+
+// defaultFormat picks a rendering hint from the claim type when none is given
+func defaultFormat(claimType ClaimType) ValueFormat {
+	switch claimType {
+	case ClaimTypeDate:
+		return FormatDate
+	case ClaimTypeBoolean:
+		return FormatYesNo
+	case ClaimTypeDecimal:
+		return FormatDecimal
+	default:
+		return FormatText
+	}
+}
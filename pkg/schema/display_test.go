@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchLocale(t *testing.T) {
+	available := []string{"en-US", "vi-VN"}
+
+	assert.Equal(t, "vi-VN", MatchLocale("vi-VN", available))
+	assert.Equal(t, "vi-VN", MatchLocale("VI-vn", available))
+	assert.Equal(t, "vi-VN", MatchLocale("vi", available))
+	assert.Equal(t, "en-US", MatchLocale("en-GB", available))
+	assert.Equal(t, "en-US", MatchLocale("fr-FR", available))
+	assert.Equal(t, "en-US", MatchLocale("", available))
+
+	// Accept-Language values are tried by weight
+	assert.Equal(t, "vi-VN", MatchLocale("fr-FR;q=0.9, vi;q=0.8, en;q=0.5", available))
+	assert.Equal(t, "en-US", MatchLocale("vi;q=0.2, en-US", available))
+	assert.Equal(t, "en-US", MatchLocale("vi;q=0", available))
+
+	assert.Equal(t, "de-DE", MatchLocale("fr", []string{"de-DE"}))
+	assert.Equal(t, DefaultLocale, MatchLocale("fr", nil))
+}
+
+func TestResolveDisplay(t *testing.T) {
+	registry := NewDefaultRegistry()
+	template, err := registry.Get("national-id")
+	require.NoError(t, err)
+
+	t.Run("Localized", func(t *testing.T) {
+		display := template.ResolveDisplay("vi")
+		assert.Equal(t, "vi-VN", display.Locale)
+		assert.Equal(t, []string{"en-US", "vi-VN"}, display.AvailableLocales)
+		require.NotNil(t, display.Credential)
+		assert.Equal(t, "Căn cước công dân", display.Credential.Name)
+
+		require.Len(t, display.Claims, len(template.Claims))
+		assert.Equal(t, "firstName", display.Claims[0].Key)
+		assert.Equal(t, "Tên", display.Claims[0].Label)
+
+		idNumber := display.Claims[len(display.Claims)-1]
+		assert.Equal(t, "idNumber", idNumber.Key)
+		assert.Equal(t, FormatMasked, idNumber.Format)
+		assert.True(t, idNumber.Required)
+	})
+
+	t.Run("Fallbacks", func(t *testing.T) {
+		custom := &CredentialTemplate{
+			ID:             "custom",
+			CredentialType: "CustomCredential",
+			Claims: []ClaimSchema{
+				{Key: "unordered", Type: ClaimTypeString},
+				{Key: "member", Type: ClaimTypeBoolean, Order: 2, Display: []ClaimDisplay{{Locale: "en-US", Label: "Member"}}},
+				{Key: "joined", Type: ClaimTypeDate, Order: 1, Display: []ClaimDisplay{{Locale: "de-DE", Label: "Beigetreten"}}},
+			},
+		}
+
+		display := custom.ResolveDisplay("fr-FR")
+		assert.Equal(t, "en-US", display.Locale)
+		assert.Nil(t, display.Credential)
+
+		keys := make([]string, len(display.Claims))
+		for i, claim := range display.Claims {
+			keys[i] = claim.Key
+		}
+		assert.Equal(t, []string{"joined", "member", "unordered"}, keys)
+
+		// Labels fall back to en-US and then the key; formats default from the claim type
+		assert.Equal(t, "joined", display.Claims[0].Label)
+		assert.Equal(t, FormatDate, display.Claims[0].Format)
+		assert.Equal(t, "Member", display.Claims[1].Label)
+		assert.Equal(t, FormatYesNo, display.Claims[1].Format)
+		assert.Equal(t, FormatText, display.Claims[2].Format)
+	})
+}
+
+func TestRegistryDisplayValidation(t *testing.T) {
+	registry := NewInMemoryRegistry()
+
+	err := registry.Register(&CredentialTemplate{
+		ID:             "bad-format",
+		CredentialType: "Test",
+		Claims:         []ClaimSchema{{Key: "a", Type: ClaimTypeString, Format: "uppercase"}},
+	})
+	assert.ErrorContains(t, err, "unsupported format")
+
+	err = registry.Register(&CredentialTemplate{
+		ID:             "duplicate-locale",
+		CredentialType: "Test",
+		Claims: []ClaimSchema{{
+			Key:     "a",
+			Type:    ClaimTypeString,
+			Display: []ClaimDisplay{{Locale: "en-US", Label: "A"}, {Locale: "en-US", Label: "B"}},
+		}},
+	})
+	assert.ErrorContains(t, err, "duplicate display")
+
+	err = registry.Register(&CredentialTemplate{
+		ID:             "missing-label",
+		CredentialType: "Test",
+		Claims:         []ClaimSchema{{Key: "a", Type: ClaimTypeString, Display: []ClaimDisplay{{Locale: "en-US"}}}},
+	})
+	assert.Error(t, err)
+
+	for _, template := range DefaultTemplates() {
+		assert.NoError(t, registry.Register(template))
+	}
+}
@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// InMemoryRegistry implements Registry interface
+type InMemoryRegistry struct {
+	mu        sync.RWMutex
+	templates map[string]*CredentialTemplate
+}
+
+// NewInMemoryRegistry creates a new empty in-memory template registry
+func NewInMemoryRegistry() Registry {
+	return &InMemoryRegistry{
+		templates: make(map[string]*CredentialTemplate),
+	}
+}
+
+// NewDefaultRegistry creates a registry pre-populated with the built-in templates
+func NewDefaultRegistry() Registry {
+	registry := NewInMemoryRegistry()
+	for _, template := range DefaultTemplates() {
+		// Built-in templates are known to be valid
+		_ = registry.Register(template)
+	}
+	return registry
+}
+
+// Register adds or replaces a credential template
+func (r *InMemoryRegistry) Register(template *CredentialTemplate) error {
+	if template == nil {
+		return fmt.Errorf("template is nil")
+	}
+
+	if template.ID == "" {
+		return fmt.Errorf("template ID is required")
+	}
+
+	if template.CredentialType == "" {
+		return fmt.Errorf("template credential type is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, claim := range template.Claims {
+		if claim.Key == "" {
+			return fmt.Errorf("template %s has a claim without key", template.ID)
+		}
+		if seen[claim.Key] {
+			return fmt.Errorf("template %s has duplicate claim %s", template.ID, claim.Key)
+		}
+		seen[claim.Key] = true
+
+		if err := validateClaimDisplay(claim); err != nil {
+			return fmt.Errorf("template %s: %w", template.ID, err)
+		}
+		if err := claim.Disclosure.Validate(); err != nil {
+			return fmt.Errorf("template %s: claim %s: %w", template.ID, claim.Key, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[template.ID] = template
+	return nil
+}
+
+// validateClaimDisplay checks a claim's format hint and that each locale is labelled once
+func validateClaimDisplay(claim ClaimSchema) error {
+	switch claim.Format {
+	case "", FormatText, FormatDate, FormatYear, FormatYesNo, FormatDecimal, FormatMasked:
+	default:
+		return fmt.Errorf("claim %s has unsupported format %s", claim.Key, claim.Format)
+	}
+
+	if claim.Order < 0 {
+		return fmt.Errorf("claim %s has negative order", claim.Key)
+	}
+
+	locales := make(map[string]bool)
+	for _, display := range claim.Display {
+		if display.Locale == "" || display.Label == "" {
+			return fmt.Errorf("claim %s display requires locale and label", claim.Key)
+		}
+		if locales[display.Locale] {
+			return fmt.Errorf("claim %s has duplicate display for locale %s", claim.Key, display.Locale)
+		}
+		locales[display.Locale] = true
+	}
+	return nil
+}
+
+// Get retrieves a template by ID
+func (r *InMemoryRegistry) Get(id string) (*CredentialTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	template, exists := r.templates[id]
+	if !exists {
+		return nil, fmt.Errorf("template not found: %s", id)
+	}
+	return template, nil
+}
+
+// List returns all templates ordered by ID
+func (r *InMemoryRegistry) List() []*CredentialTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]*CredentialTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+// DefaultTemplates returns the credential templates used by the demos
+func DefaultTemplates() []*CredentialTemplate {
+	return []*CredentialTemplate{
+		{
+			ID:             "national-id",
+			CredentialType: "NationalIDCredential",
+			Claims: []ClaimSchema{
+				{Key: "firstName", Type: ClaimTypeString, Required: true, Order: 1, Display: labels("First name", "Tên")},
+				{Key: "lastName", Type: ClaimTypeString, Required: true, Order: 2, Display: labels("Last name", "Họ")},
+				{Key: "dateOfBirth", Type: ClaimTypeDate, Required: true, Order: 3, Format: FormatDate, Display: labels("Date of birth", "Ngày sinh")},
+				{Key: "nationality", Type: ClaimTypeString, Required: true, Order: 4, Display: labels("Nationality", "Quốc tịch")},
+				{Key: "address", Type: ClaimTypeString, Order: 5, Display: labels("Address", "Địa chỉ")},
+				{Key: "idNumber", Type: ClaimTypeString, Required: true, Order: 6, Format: FormatMasked, Display: labels("ID number", "Số định danh")},
+			},
+			Display: []Display{
+				{Name: "National ID", Locale: "en-US", BackgroundColor: "#12107c", TextColor: "#ffffff"},
+				{Name: "Căn cước công dân", Locale: "vi-VN", BackgroundColor: "#12107c", TextColor: "#ffffff"},
+			},
+		},
+		{
+			ID:             "age-verification",
+			CredentialType: "AgeVerificationCredential",
+			Claims: []ClaimSchema{
+				{Key: "firstName", Type: ClaimTypeString, Required: true, Order: 2, Display: labels("First name", "Tên")},
+				{Key: "lastName", Type: ClaimTypeString, Required: true, Order: 3, Display: labels("Last name", "Họ")},
+				{Key: "fullName", Type: ClaimTypeString, Order: 1, Display: labels("Full name", "Họ và tên")},
+				{Key: "dateOfBirth", Type: ClaimTypeDate, Required: true, Order: 4, Format: FormatDate, Display: labels("Date of birth", "Ngày sinh")},
+				{Key: "nationality", Type: ClaimTypeString, Order: 5, Display: labels("Nationality", "Quốc tịch")},
+				{Key: "address", Type: ClaimTypeString, Order: 6, Display: labels("Address", "Địa chỉ")},
+				{Key: "idNumber", Type: ClaimTypeString, Order: 7, Format: FormatMasked, Display: labels("ID number", "Số định danh")},
+				{Key: "ageOver13", Type: ClaimTypeBoolean, Order: 10, Format: FormatYesNo, Display: labels("Over 13", "Trên 13 tuổi")},
+				{Key: "ageOver16", Type: ClaimTypeBoolean, Order: 11, Format: FormatYesNo, Display: labels("Over 16", "Trên 16 tuổi")},
+				{Key: "ageOver18", Type: ClaimTypeBoolean, Order: 12, Format: FormatYesNo, Display: labels("Over 18", "Trên 18 tuổi")},
+				{Key: "ageOver21", Type: ClaimTypeBoolean, Order: 13, Format: FormatYesNo, Display: labels("Over 21", "Trên 21 tuổi")},
+				{Key: "ageOver25", Type: ClaimTypeBoolean, Order: 14, Format: FormatYesNo, Display: labels("Over 25", "Trên 25 tuổi")},
+				{Key: "ageOver65", Type: ClaimTypeBoolean, Order: 15, Format: FormatYesNo, Display: labels("Over 65", "Trên 65 tuổi")},
+				{Key: "birthYear", Type: ClaimTypeInteger, Order: 8, Format: FormatYear, Display: labels("Year of birth", "Năm sinh")},
+				{Key: "ageCategory", Type: ClaimTypeString, Order: 9, Display: labels("Age category", "Nhóm tuổi")},
+				{Key: "documentType", Type: ClaimTypeString, Order: 16, Display: labels("Document type", "Loại giấy tờ")},
+				{Key: "issuedAt", Type: ClaimTypeDate, Order: 17, Format: FormatDate, Display: labels("Issued on", "Ngày cấp")},
+				{Key: "validUntil", Type: ClaimTypeDate, Order: 18, Format: FormatDate, Display: labels("Valid until", "Có giá trị đến")},
+			},
+			Display: []Display{
+				{Name: "Digital ID with Age Verification", Locale: "en-US", BackgroundColor: "#0b6e4f", TextColor: "#ffffff"},
+				{Name: "Định danh số có xác minh tuổi", Locale: "vi-VN", BackgroundColor: "#0b6e4f", TextColor: "#ffffff"},
+			},
+		},
+		{
+			ID:             "university-degree",
+			CredentialType: "UniversityDegreeCredential",
+			Claims: []ClaimSchema{
+				{Key: "degree", Type: ClaimTypeString, Required: true, Order: 1, Display: labels("Degree", "Bằng cấp")},
+				{Key: "major", Type: ClaimTypeString, Order: 2, Display: labels("Major", "Chuyên ngành")},
+				{Key: "graduationYear", Type: ClaimTypeInteger, Order: 4, Format: FormatYear, Display: labels("Graduation year", "Năm tốt nghiệp")},
+				{Key: "university", Type: ClaimTypeString, Required: true, Order: 3, Display: labels("University", "Trường đại học")},
+				{Key: "gpa", Type: ClaimTypeDecimal, Order: 5, Format: FormatDecimal, Display: labels("GPA", "Điểm trung bình")},
+			},
+			Display: []Display{
+				{Name: "University Degree", Locale: "en-US", BackgroundColor: "#7c1012", TextColor: "#ffffff"},
+				{Name: "Bằng đại học", Locale: "vi-VN", BackgroundColor: "#7c1012", TextColor: "#ffffff"},
+			},
+		},
+		{
+			ID:             "authorized-issuer",
+			CredentialType: "AuthorizedIssuerCredential",
+			Claims: []ClaimSchema{
+				{Key: "authorizedCredentialTypes", Type: ClaimTypeString, Order: 1, Display: labels("Authorized credential types", "Loại chứng chỉ được cấp")},
+				{Key: "mayDelegate", Type: ClaimTypeBoolean, Required: true, Order: 2, Format: FormatYesNo, Display: labels("May authorize sub-issuers", "Được ủy quyền cho đơn vị cấp dưới")},
+			},
+			Display: []Display{
+				{Name: "Authorized Issuer", Locale: "en-US", BackgroundColor: "#4a4a4a", TextColor: "#ffffff"},
+				{Name: "Đơn vị cấp được ủy quyền", Locale: "vi-VN", BackgroundColor: "#4a4a4a", TextColor: "#ffffff"},
+			},
+		},
+		{
+			ID:             "verifier-accreditation",
+			CredentialType: "VerifierAccreditationCredential",
+			Claims: []ClaimSchema{
+				{Key: "accreditation", Type: ClaimTypeString, Required: true, Order: 1, Display: labels("Accreditation", "Chứng nhận")},
+				{Key: "licenseNumber", Type: ClaimTypeString, Order: 2, Display: labels("License number", "Số giấy phép")},
+				{Key: "jurisdiction", Type: ClaimTypeString, Order: 3, Display: labels("Jurisdiction", "Khu vực tài phán")},
+			},
+			Display: []Display{
+				{Name: "Verifier Accreditation", Locale: "en-US", BackgroundColor: "#1d5c8c", TextColor: "#ffffff"},
+				{Name: "Chứng nhận bên xác minh", Locale: "vi-VN", BackgroundColor: "#1d5c8c", TextColor: "#ffffff"},
+			},
+		},
+	}
+}
+
+// labels builds the English and Vietnamese labels of a built-in claim
+func labels(en, vi string) []ClaimDisplay {
+	return []ClaimDisplay{
+		{Locale: "en-US", Label: en},
+		{Locale: "vi-VN", Label: vi},
+	}
+}
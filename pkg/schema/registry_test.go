@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	templates := registry.List()
+	require.Len(t, templates, len(DefaultTemplates()))
+	for i := 1; i < len(templates); i++ {
+		assert.Less(t, templates[i-1].ID, templates[i].ID)
+	}
+
+	template, err := registry.Get("age-verification")
+	require.NoError(t, err)
+	assert.Equal(t, "AgeVerificationCredential", template.CredentialType)
+
+	claim, ok := template.Claim("ageOver18")
+	require.True(t, ok)
+	assert.Equal(t, ClaimTypeBoolean, claim.Type)
+
+	_, ok = template.Claim("unknown")
+	assert.False(t, ok)
+}
+
+func TestRegistryValidation(t *testing.T) {
+	registry := NewInMemoryRegistry()
+
+	t.Run("Missing ID", func(t *testing.T) {
+		err := registry.Register(&CredentialTemplate{CredentialType: "Test"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing Type", func(t *testing.T) {
+		err := registry.Register(&CredentialTemplate{ID: "test"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Duplicate Claim", func(t *testing.T) {
+		err := registry.Register(&CredentialTemplate{
+			ID:             "test",
+			CredentialType: "Test",
+			Claims: []ClaimSchema{
+				{Key: "a", Type: ClaimTypeString},
+				{Key: "a", Type: ClaimTypeString},
+			},
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate claim")
+	})
+
+	t.Run("Unknown Disclosure Restriction", func(t *testing.T) {
+		err := registry.Register(&CredentialTemplate{
+			ID:             "test",
+			CredentialType: "Test",
+			Claims:         []ClaimSchema{{Key: "a", Type: ClaimTypeString, Disclosure: "sometimes"}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := registry.Get("missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "template not found")
+	})
+}
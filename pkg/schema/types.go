@@ -0,0 +1,123 @@
+package schema
+
+import "fmt"
+
+// ClaimType represents the value type of a claim
+type ClaimType string
+
+const (
+	ClaimTypeString  ClaimType = "string"
+	ClaimTypeInteger ClaimType = "integer"
+	ClaimTypeBoolean ClaimType = "boolean"
+	ClaimTypeDate    ClaimType = "date"
+	ClaimTypeDecimal ClaimType = "decimal"
+)
+
+// ValueFormat is a hint for how wallets should render a claim value
+type ValueFormat string
+
+const (
+	FormatText    ValueFormat = "text"
+	FormatDate    ValueFormat = "date"
+	FormatYear    ValueFormat = "year"
+	FormatYesNo   ValueFormat = "yes-no"
+	FormatDecimal ValueFormat = "decimal"
+	FormatMasked  ValueFormat = "masked"
+)
+
+// DisclosureRestriction limits how an issuer lets a claim be revealed in presentations
+type DisclosureRestriction string
+
+const (
+	// DisclosureNotAlone claims are revealed only together with a claim that may be revealed alone
+	DisclosureNotAlone DisclosureRestriction = "not-alone"
+	// DisclosureNever claims are never revealed; they can only be proven about, e.g. with
+	// predicates over commitments
+	DisclosureNever DisclosureRestriction = "never"
+)
+
+// Validate checks the restriction is a known one; empty means the claim may be revealed freely
+func (r DisclosureRestriction) Validate() error {
+	switch r {
+	case "", DisclosureNotAlone, DisclosureNever:
+		return nil
+	default:
+		return fmt.Errorf("unsupported disclosure restriction %s", r)
+	}
+}
+
+// Allows reports whether a claim under restriction r may carry restriction other instead, i.e.
+// other is at least as strict
+func (r DisclosureRestriction) Allows(other DisclosureRestriction) bool {
+	return other.strictness() >= r.strictness()
+}
+
+// strictness orders restrictions from none to never
+func (r DisclosureRestriction) strictness() int {
+	switch r {
+	case DisclosureNotAlone:
+		return 1
+	case DisclosureNever:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ClaimSchema describes a single claim a credential template may contain
+type ClaimSchema struct {
+	Key         string    `json:"key"`
+	Type        ClaimType `json:"type"`
+	Required    bool      `json:"required"`
+	Description string    `json:"description,omitempty"`
+	// Display holds per-locale labels for the claim
+	Display []ClaimDisplay `json:"display,omitempty"`
+	// Format hints how the value should be rendered
+	Format ValueFormat `json:"format,omitempty"`
+	// Order positions the claim when rendered; lower values come first
+	Order int `json:"order,omitempty"`
+	// Disclosure restricts how holders may reveal the claim in presentations
+	Disclosure DisclosureRestriction `json:"disclosure,omitempty"`
+}
+
+// ClaimDisplay holds a localized label for a claim
+type ClaimDisplay struct {
+	Locale      string `json:"locale"`
+	Label       string `json:"label"`
+	Description string `json:"description,omitempty"`
+}
+
+// Display holds presentation hints for wallets rendering a credential
+type Display struct {
+	Name            string `json:"name"`
+	Locale          string `json:"locale,omitempty"`
+	Description     string `json:"description,omitempty"`
+	LogoURL         string `json:"logoUrl,omitempty"`
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+	TextColor       string `json:"textColor,omitempty"`
+}
+
+// CredentialTemplate describes a kind of credential an issuer can issue
+type CredentialTemplate struct {
+	ID             string        `json:"id"`
+	CredentialType string        `json:"credentialType"`
+	Claims         []ClaimSchema `json:"claims"`
+	Display        []Display     `json:"display,omitempty"`
+}
+
+// Claim returns the schema of the claim with the given key
+func (t *CredentialTemplate) Claim(key string) (*ClaimSchema, bool) {
+	for i := range t.Claims {
+		if t.Claims[i].Key == key {
+			return &t.Claims[i], true
+		}
+	}
+	return nil, false
+}
+
+// Registry interface for credential template lookup
+type Registry interface {
+	Register(template *CredentialTemplate) error
+	Get(id string) (*CredentialTemplate, error)
+	List() []*CredentialTemplate
+}
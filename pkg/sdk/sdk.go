@@ -0,0 +1,382 @@
+// Package sdk wires the issuer, holder and verifier use cases with their services and
+// repositories from a single configuration. The API server, the demos and the integration
+// tests all build their dependency graph through it.
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/blob"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/notify"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/secrets"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// Config selects where the stack keeps its state and which optional checks it runs. The zero
+// value keeps everything in memory and dates everything by the system clock.
+type Config struct {
+	// Store keeps credentials, presentations, DID documents and verifier state, so several
+	// stacks can share them; nil keeps everything in this process
+	Store storage.KVStore
+	// DIDRepository keeps the DID documents in place of Store, so stacks can share a DID registry
+	// without sharing their other state; nil keeps them in Store or in memory
+	DIDRepository did.DIDRepository
+	// Stateless also keeps private keys, issuer and holder state and status lists in Store, so
+	// any stack sharing it can serve any request
+	Stateless bool
+	// Clock dates credentials, proofs and the roles' records and drives their time-based checks.
+	// Timers, such as the issuance queue's retries, and cache and storage expiry run on the
+	// system clock.
+	Clock clock.Clock
+	// ResolutionCacheTTL caches resolved DID documents, fetched status lists and trusted domains'
+	// DID configurations for this long; zero disables caching
+	ResolutionCacheTTL time.Duration
+	// TombstoneRetention is how long deleted credentials and deactivated DID documents are kept,
+	// with who deleted them, when and why, before they are purged; zero keeps them
+	TombstoneRetention time.Duration
+	// ClaimKeys encrypt the claims of the credentials kept in Store, each under a data key of
+	// its own they wrap; nil stores claims in the clear. It needs a Store.
+	ClaimKeys vc.KeyEncryptionKeys
+	// ValidateContexts rejects credentials and presentations that do not expand under their
+	// JSON-LD @context, using the bundled contexts
+	ValidateContexts bool
+	// Predicates are registered ahead of the built-in range provider
+	Predicates []predicate.Provider
+	// Provider selects the BBS+ implementation; empty uses the production BLS12-381 service
+	Provider bbs.Provider
+	// Aries configures the aries provider; nil uses a local KMS
+	Aries *bbs.AriesConfig
+	// BBS signs and verifies credentials in place of the service Provider selects, for
+	// implementations the factory does not know, such as a signer backed by an HSM
+	BBS bbs.BBSService
+	// Secrets resolves API tokens and the Aries remote KMS token that reference secrets, such as
+	// env:API_TOKENS or vault:bbs/server#apiTokens; nil takes every value literally
+	Secrets *secrets.Resolver
+	// Logger receives status changes, key rotations and approval decisions; nil logs nothing
+	Logger *log.Logger
+	// APITokens are the bearer tokens the HTTP API accepts; none leaves the API open. A token
+	// that references a secret is replaced by the comma-separated tokens the secret holds.
+	APITokens []string
+	// Roles limits the HTTP API to these roles' endpoints; none exposes every role
+	Roles []api.Role
+	// CostPolicy limits the proofs the HTTP API checks per request and per minute; the zero
+	// policy leaves them unlimited
+	CostPolicy api.CostPolicy
+	// WebDomain is the domain, such as example.com or localhost:8089, did:web DIDs are generated
+	// under and their documents served for at /users/{id}/did.json; empty disables generating
+	// did:web DIDs and serves their documents for the request's host
+	WebDomain string
+	// PrivateWebhooks lets status notifications and forwarded assertions be delivered to loopback
+	// and private network addresses, as deployments whose holders and relying parties run on the
+	// local network need; by default they are refused when dialling
+	PrivateWebhooks bool
+	// Peers are the base URLs of servers running the other roles. DIDs and status lists the stack
+	// does not manage are resolved at them, so the roles can run as separate services.
+	Peers []string
+}
+
+// IssuerRole is the issuer's use case with the repositories and credential service it keeps.
+// Only the issuer's credential service holds signing keys.
+type IssuerRole struct {
+	*issuer.UseCase
+	Credentials       vc.CredentialRepository
+	Presentations     vc.PresentationRepository
+	CredentialService vc.CredentialService
+}
+
+// HolderRole is the holder's use case with its wallet. The wallet only receives credentials
+// delivered to the holder.
+type HolderRole struct {
+	*holder.UseCase
+	Credentials       vc.CredentialRepository
+	Presentations     vc.PresentationRepository
+	CredentialService vc.CredentialService
+}
+
+// VerifierRole is the verifier's use case with the presentations it was sent
+type VerifierRole struct {
+	*verifier.UseCase
+	Credentials       vc.CredentialRepository
+	Presentations     vc.PresentationRepository
+	CredentialService vc.CredentialService
+}
+
+// Stack is a wired issuer, holder and verifier with the services they share. Each role keeps
+// its own repositories and credential service.
+type Stack struct {
+	DIDRepository  did.DIDRepository
+	DIDService     did.DIDService
+	BBSService     bbs.BBSService
+	StatusRegistry status.Registry
+	Predicates     *predicate.Registry
+	Clock          clock.Clock
+	// Events carries the roles' events and the requests the HTTP server's cross-role endpoints
+	// send them
+	Events *events.Bus
+
+	Issuer   IssuerRole
+	Holder   HolderRole
+	Verifier VerifierRole
+
+	config Config
+}
+
+// NewStack creates the services, repositories and use cases the configuration describes and
+// connects them: the holder fetches status snapshots, subscriptions, addenda and amended versions
+// from the issuer, the verifier redeems presentation tokens from the holder's wallet and looks up
+// amended credentials at the issuer, the issuer delivers status notifications to subscribed
+// webhooks and the verifier forwards session outcomes to relying parties. Every role is connected
+// to the stack's event bus. DIDs and status lists the stack does not manage are resolved at its
+// peers.
+func NewStack(config Config) (*Stack, error) {
+	kv := config.Store
+	if config.Stateless && kv == nil {
+		return nil, fmt.Errorf("stateless stacks need a shared store")
+	}
+	if config.ResolutionCacheTTL < 0 {
+		return nil, fmt.Errorf("resolution cache TTL cannot be negative")
+	}
+	if config.TombstoneRetention < 0 {
+		return nil, fmt.Errorf("tombstone retention cannot be negative")
+	}
+	if config.ClaimKeys != nil && kv == nil {
+		return nil, fmt.Errorf("claim encryption needs a store")
+	}
+
+	tokens, err := resolveTokens(config.Secrets, config.APITokens)
+	if err != nil {
+		return nil, err
+	}
+	config.APITokens = tokens
+
+	bbsService := bbs.NewService()
+	switch {
+	case config.BBS != nil:
+		bbsService = config.BBS
+	case config.Provider != "":
+		bbsConfig := bbs.DefaultConfig()
+		if config.Aries != nil {
+			bbsConfig.AriesConfig = config.Aries
+		}
+		bbsConfig.Secrets = config.Secrets
+		service, err := bbs.NewFactory().CreateService(config.Provider, bbsConfig)
+		if err != nil {
+			return nil, err
+		}
+		bbsService = service
+	}
+
+	stack := &Stack{
+		BBSService: bbsService,
+		Clock:      clock.OrSystem(config.Clock),
+		config:     config,
+	}
+
+	credentialRepository := func(role string) vc.CredentialRepository {
+		if kv == nil {
+			return vc.NewInMemoryCredentialRepositoryWithRetention(config.TombstoneRetention)
+		}
+		return vc.NewKVCredentialRepositoryWithOptions(storage.Prefixed(kv, role+":"), vc.KVCredentialOptions{
+			Retention: config.TombstoneRetention,
+			Keys:      config.ClaimKeys,
+		})
+	}
+	presentationRepository := func(role string) vc.PresentationRepository {
+		if kv == nil {
+			return vc.NewInMemoryPresentationRepository()
+		}
+		return vc.NewKVPresentationRepository(storage.Prefixed(kv, role+":"))
+	}
+
+	switch {
+	case config.DIDRepository != nil:
+		stack.DIDRepository = config.DIDRepository
+	case kv != nil:
+		stack.DIDRepository = did.NewKVRepositoryWithRetention(storage.Prefixed(kv, "did:"), config.TombstoneRetention)
+	default:
+		stack.DIDRepository = did.NewInMemoryRepositoryWithRetention(config.TombstoneRetention)
+	}
+	stack.DIDService = did.NewService(stack.DIDRepository)
+	if config.Stateless {
+		stack.DIDService = did.NewServiceWithKeyStore(stack.DIDRepository, storage.Prefixed(kv, "keys:"))
+	}
+	if config.WebDomain != "" {
+		if err := stack.DIDService.(*did.ServiceImpl).SetWebDomain(config.WebDomain); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.Peers) > 0 {
+		stack.DIDService = did.NewRemoteService(stack.DIDService, fetch.NewFetcher(nil, config.ResolutionCacheTTL), config.Peers)
+	}
+	if config.ResolutionCacheTTL > 0 {
+		// Every use case shares the wrapper, so key rotations drop the cached documents
+		stack.DIDService = did.NewCachingService(stack.DIDService, fetch.NewFetcher(nil, config.ResolutionCacheTTL), config.ResolutionCacheTTL)
+	}
+
+	issuerRole := IssuerRole{Credentials: credentialRepository("issuer"), Presentations: presentationRepository("issuer")}
+	issuerRole.CredentialService = vc.NewServiceWithClock(bbsService, issuerRole.Credentials, issuerRole.Presentations, stack.Clock)
+	if config.Stateless {
+		issuerRole.CredentialService = vc.NewServiceWithKeyStore(bbsService, issuerRole.Credentials, issuerRole.Presentations, stack.Clock, storage.Prefixed(kv, "issuerkeys:"))
+	}
+	issuerRole.UseCase = issuer.NewUseCase(stack.DIDService, issuerRole.CredentialService, bbsService)
+	issuerRole.SetClock(stack.Clock)
+	stack.Issuer = issuerRole
+
+	holderRole := HolderRole{Credentials: credentialRepository("holder"), Presentations: presentationRepository("holder")}
+	holderRole.CredentialService = vc.NewServiceWithClock(bbsService, holderRole.Credentials, holderRole.Presentations, stack.Clock)
+	holderRole.UseCase = holder.NewUseCase(stack.DIDService, holderRole.CredentialService, holderRole.Credentials)
+	holderRole.SetClock(stack.Clock)
+	stack.Holder = holderRole
+
+	verifierRole := VerifierRole{Credentials: credentialRepository("verifier"), Presentations: presentationRepository("verifier")}
+	verifierRole.CredentialService = vc.NewServiceWithClock(bbsService, verifierRole.Credentials, verifierRole.Presentations, stack.Clock)
+	verifierRole.UseCase = verifier.NewUseCase(stack.DIDService, verifierRole.CredentialService, verifierRole.Presentations)
+	verifierRole.SetClock(stack.Clock)
+	// Trusted domains' DID configurations are cached like other fetched documents
+	verifierRole.SetDomainFetcher(fetch.NewFetcher(nil, config.ResolutionCacheTTL))
+	stack.Verifier = verifierRole
+
+	// Share status lists so verifiers see revocations and suspensions immediately
+	stack.StatusRegistry = status.NewInMemoryRegistry()
+	if config.Stateless {
+		stack.StatusRegistry = status.NewKVRegistry(storage.Prefixed(kv, "status:"), 0)
+	}
+	stack.Issuer.SetStatusRegistry(stack.StatusRegistry)
+	switch {
+	case len(config.Peers) > 0:
+		stack.Verifier.SetStatusRegistry(status.NewCachingRegistryWithPeers(stack.StatusRegistry, fetch.NewFetcher(nil, config.ResolutionCacheTTL), config.Peers))
+	case config.ResolutionCacheTTL > 0 && !config.Stateless:
+		stack.Verifier.SetStatusRegistry(status.NewCachingRegistry(stack.StatusRegistry, fetch.NewFetcher(nil, config.ResolutionCacheTTL)))
+	default:
+		stack.Verifier.SetStatusRegistry(stack.StatusRegistry)
+	}
+	stack.Holder.SetStatusSnapshotSource(stack.Issuer.UseCase)
+	stack.Holder.SetStatusSubscriber(stack.Issuer.UseCase)
+	notifier := notify.NewWebhookNotifier(nil)
+	if config.PrivateWebhooks {
+		notifier = notify.NewWebhookNotifier(&http.Client{Timeout: notify.DefaultTimeout})
+	}
+	stack.Issuer.SetStatusNotifier(notifier)
+	stack.Verifier.SetForwardNotifier(notifier)
+	stack.Holder.SetAddendumSource(stack.Issuer.UseCase)
+	stack.Holder.SetVersionSource(stack.Issuer.UseCase)
+	stack.Verifier.SetPresentationTokenSource(stack.Holder.UseCase)
+	stack.Verifier.SetVersionSource(stack.Issuer.UseCase)
+
+	stack.Events = events.NewBus()
+	stack.Issuer.SetEventBus(stack.Events)
+	if err := stack.Holder.SetEventBus(stack.Events); err != nil {
+		return nil, err
+	}
+	if err := stack.Verifier.SetEventBus(stack.Events); err != nil {
+		return nil, err
+	}
+
+	predicates, err := predicate.NewRegistry(config.Predicates...)
+	if err != nil {
+		return nil, err
+	}
+	if err := predicates.Register(predicate.NewRangeProvider()); err != nil {
+		return nil, err
+	}
+	stack.Predicates = predicates
+	stack.Holder.SetPredicateRegistry(predicates)
+	stack.Verifier.SetPredicateRegistry(predicates)
+
+	if kv != nil {
+		stack.Verifier.SetStore(storage.Prefixed(kv, "verifier:"))
+		stack.Issuer.SetBlobStore(blob.NewKVStore(storage.Prefixed(kv, "blob:")))
+	}
+	if config.Stateless {
+		stack.Issuer.SetStore(storage.Prefixed(kv, "issuer:"))
+		stack.Holder.SetStore(storage.Prefixed(kv, "holder:"))
+	}
+
+	if config.ValidateContexts {
+		// Contexts are bundled, so validation never reaches the network
+		loader, err := jsonld.NewBundledLoader()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JSON-LD contexts: %w", err)
+		}
+		validator := jsonld.NewValidator(loader)
+		stack.Holder.SetContextValidator(validator)
+		stack.Verifier.SetContextValidator(validator)
+	}
+
+	if logger := config.Logger; logger != nil {
+		stack.Issuer.OnStatusChange(func(notification vc.StatusNotification) {
+			logger.Printf("🔔 Credential %s is %s (%s) as of %s", notification.CredentialID, notification.Event, notification.Reason, notification.EffectiveDate.Format(time.RFC3339))
+		})
+		stack.Issuer.OnKeyRotation(func(event issuer.KeyRotationEvent) {
+			logger.Printf("🔑 Issuer %s rotated keys %v -> %v", event.IssuerDID, event.RetiredKeys, event.NewKeys)
+		})
+		stack.Issuer.OnApprovalChange(func(approval issuer.IssuanceApproval) {
+			logger.Printf("📝 Issuance request %s for %s is %s", approval.ID, approval.Request.SubjectDID, approval.Status)
+		})
+	}
+
+	return stack, nil
+}
+
+// RewrapClaimKeys rewraps the data keys of every role's stored credentials under the current
+// claim key, and encrypts the claims of credentials stored before encryption was enabled. It
+// returns the number of credentials rewritten.
+func (s *Stack) RewrapClaimKeys() (int, error) {
+	rewritten := 0
+	for _, repository := range []vc.CredentialRepository{s.Issuer.Credentials, s.Holder.Credentials, s.Verifier.Credentials} {
+		kv, ok := repository.(*vc.KVCredentialRepository)
+		if !ok {
+			continue
+		}
+		n, err := kv.RewrapKeys()
+		rewritten += n
+		if err != nil {
+			return rewritten, err
+		}
+	}
+	return rewritten, nil
+}
+
+// Config returns the configuration the stack was created with, its API tokens resolved
+func (s *Stack) Config() Config {
+	return s.config
+}
+
+// resolveTokens replaces the API tokens that reference secrets with the tokens the secrets hold
+func resolveTokens(resolver *secrets.Resolver, tokens []string) ([]string, error) {
+	var resolved []string
+	for _, token := range tokens {
+		if !resolver.IsReference(token) {
+			resolved = append(resolved, token)
+			continue
+		}
+		value, err := resolver.Resolve(context.Background(), token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API tokens: %w", err)
+		}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				resolved = append(resolved, item)
+			}
+		}
+	}
+	return resolved, nil
+}
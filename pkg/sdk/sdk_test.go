@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+func TestNewStack(t *testing.T) {
+	roundTrip := func(t *testing.T, stack *Stack) {
+		issuerSetup, err := stack.Issuer.SetupIssuer("test")
+		require.NoError(t, err)
+		holderSetup, err := stack.Holder.SetupHolder("test")
+		require.NoError(t, err)
+
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims: []vc.Claim{
+				{Key: "fullName", Value: "An Nguyen"},
+				{Key: "age", Value: 25},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(credential))
+
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"age"}},
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"age"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.NotContains(t, result.RevealedClaims, "fullName")
+	}
+
+	t.Run("In Memory", func(t *testing.T) {
+		stack, err := NewStack(Config{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bbs-bit-range"}, stack.Predicates.Names())
+		roundTrip(t, stack)
+	})
+
+	t.Run("Stateless Stacks Share A Store", func(t *testing.T) {
+		shared := storage.NewMemoryStore()
+		stack, err := NewStack(Config{Store: shared, Stateless: true, ValidateContexts: true})
+		require.NoError(t, err)
+		roundTrip(t, stack)
+
+		keys, err := shared.Keys("holder:")
+		require.NoError(t, err)
+		assert.NotEmpty(t, keys, "the holder's wallet is kept in the shared store")
+	})
+
+	t.Run("Selected Provider", func(t *testing.T) {
+		stack, err := NewStack(Config{Provider: bbs.ProviderSimple})
+		require.NoError(t, err)
+		service, ok := stack.BBSService.(bbs.BBSInterface)
+		require.True(t, ok)
+		assert.Equal(t, bbs.ProviderSimple, service.GetProvider())
+
+		_, err = NewStack(Config{Provider: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Logger Receives Key Rotations", func(t *testing.T) {
+		var output bytes.Buffer
+		stack, err := NewStack(Config{Logger: log.New(&output, "", 0)})
+		require.NoError(t, err)
+
+		issuerSetup, err := stack.Issuer.SetupIssuer("test")
+		require.NoError(t, err)
+		_, err = stack.Issuer.RotateKeys(issuerSetup.DID.String())
+		require.NoError(t, err)
+		assert.Contains(t, output.String(), "Issuer "+issuerSetup.DID.String()+" rotated keys")
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		_, err := NewStack(Config{Stateless: true})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "shared store")
+
+		_, err = NewStack(Config{ResolutionCacheTTL: -1})
+		assert.Error(t, err)
+	})
+}
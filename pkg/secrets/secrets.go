@@ -0,0 +1,135 @@
+// Package secrets resolves the secrets a server is configured with, such as API tokens, storage
+// passwords and KMS credentials, from where they are kept rather than from flags. A configuration
+// value of the form scheme:name is a reference the provider registered for scheme looks up: env:
+// reads an environment variable, file: a file such as a mounted Docker or Kubernetes secret, and
+// vault: a HashiCorp Vault KV secret.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned for a secret the provider does not have
+var ErrNotFound = errors.New("secret not found")
+
+// Provider looks up secrets by name
+type Provider interface {
+	// Secret returns the value of the secret, or ErrNotFound
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// EnvProvider reads secrets from environment variables
+type EnvProvider struct{}
+
+// Secret returns the value of the environment variable name
+func (EnvProvider) Secret(ctx context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// FileProvider reads secrets from files, one secret per file
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider creates a provider reading secrets from files named relative to dir, such as
+// /run/secrets. An empty dir takes names as paths.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// Secret returns the content of the file name without its trailing newline
+func (p *FileProvider) Secret(ctx context.Context, name string) (string, error) {
+	path := name
+	if p.dir != "" {
+		// Names are confined to dir
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("secret file %s is outside %s", name, p.dir)
+		}
+		path = filepath.Join(p.dir, name)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: no file %s", ErrNotFound, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Resolver resolves configuration values that reference secrets
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver for env: and file: references
+func NewResolver() *Resolver {
+	r := &Resolver{providers: make(map[string]Provider)}
+	r.Register("env", EnvProvider{})
+	r.Register("file", NewFileProvider(""))
+	return r
+}
+
+// NewResolverFromEnv creates a resolver for env: and file: references, and for vault: references
+// when VAULT_ADDR is set. Vault is configured the way its CLI is, with VAULT_ADDR, VAULT_TOKEN
+// and VAULT_NAMESPACE; VAULT_MOUNT selects the KV engine, secret by default.
+func NewResolverFromEnv() (*Resolver, error) {
+	r := NewResolver()
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return r, nil
+	}
+
+	vault, err := NewVaultProvider(VaultConfig{
+		Addr:      addr,
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+		Mount:     os.Getenv("VAULT_MOUNT"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Register("vault", vault)
+	return r, nil
+}
+
+// Register makes the resolver look up scheme: references with provider
+func (r *Resolver) Register(scheme string, provider Provider) {
+	r.providers[scheme] = provider
+}
+
+// IsReference reports whether value references a secret: whether its scheme has a provider
+func (r *Resolver) IsReference(value string) bool {
+	if r == nil {
+		return false
+	}
+	scheme, _, ok := strings.Cut(value, ":")
+	_, registered := r.providers[scheme]
+	return ok && registered
+}
+
+// Resolve returns the secret value references, or value itself when it is not a reference. A nil
+// resolver returns every value as it is.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !r.IsReference(value) {
+		return value, nil
+	}
+
+	scheme, name, _ := strings.Cut(value, ":")
+	secret, err := r.providers[scheme].Secret(ctx, name)
+	if err != nil {
+		// The reference names where the secret is kept, not the secret, so it may be reported
+		return "", fmt.Errorf("failed to resolve secret %s: %w", value, err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver(t *testing.T) {
+	ctx := context.Background()
+	resolver := NewResolver()
+
+	t.Run("Environment", func(t *testing.T) {
+		t.Setenv("SECRETS_TEST_TOKEN", "t0ken")
+		value, err := resolver.Resolve(ctx, "env:SECRETS_TEST_TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "t0ken", value)
+
+		_, err = resolver.Resolve(ctx, "env:SECRETS_TEST_UNSET")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "redis_password"), []byte("s3cret\n"), 0o600))
+
+		value, err := resolver.Resolve(ctx, "file:"+filepath.Join(dir, "redis_password"))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", value, "the trailing newline is dropped")
+
+		_, err = resolver.Resolve(ctx, "file:"+filepath.Join(dir, "missing"))
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		confined := NewFileProvider(dir)
+		value, err = confined.Secret(ctx, "redis_password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", value)
+		_, err = confined.Secret(ctx, "../redis_password")
+		assert.Error(t, err)
+	})
+
+	t.Run("Literals Are Kept", func(t *testing.T) {
+		for _, literal := range []string{"", "plain-token", "user:password", "https://example.com"} {
+			assert.False(t, resolver.IsReference(literal))
+			value, err := resolver.Resolve(ctx, literal)
+			require.NoError(t, err)
+			assert.Equal(t, literal, value)
+		}
+
+		var none *Resolver
+		value, err := none.Resolve(ctx, "env:SECRETS_TEST_TOKEN")
+		require.NoError(t, err)
+		assert.Equal(t, "env:SECRETS_TEST_TOKEN", value)
+	})
+}
+
+func TestVaultProvider(t *testing.T) {
+	ctx := context.Background()
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/bbs/server" {
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"apiTokens":"a,b","port":8089},"metadata":{"version":3}}}`))
+	}))
+	defer vault.Close()
+
+	provider, err := NewVaultProvider(VaultConfig{Addr: vault.URL + "/", Token: "root", Mount: "kv"})
+	require.NoError(t, err)
+	resolver := NewResolver()
+	resolver.Register("vault", provider)
+
+	t.Run("Reads A Key", func(t *testing.T) {
+		value, err := resolver.Resolve(ctx, "vault:bbs/server#apiTokens")
+		require.NoError(t, err)
+		assert.Equal(t, "a,b", value)
+	})
+
+	t.Run("Missing Secrets", func(t *testing.T) {
+		_, err := resolver.Resolve(ctx, "vault:bbs/server#missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = resolver.Resolve(ctx, "vault:bbs/other#apiTokens")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid References", func(t *testing.T) {
+		_, err := resolver.Resolve(ctx, "vault:bbs/server")
+		assert.Error(t, err, "a key is required")
+		_, err = resolver.Resolve(ctx, "vault:bbs/server#port")
+		assert.Error(t, err, "values must be strings")
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		denied, err := NewVaultProvider(VaultConfig{Addr: vault.URL, Token: "wrong", Mount: "kv"})
+		require.NoError(t, err)
+		_, err = denied.Secret(ctx, "bbs/server#apiTokens")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Configured From The Environment", func(t *testing.T) {
+		t.Setenv("VAULT_ADDR", vault.URL)
+		t.Setenv("VAULT_TOKEN", "root")
+		t.Setenv("VAULT_MOUNT", "kv")
+		fromEnv, err := NewResolverFromEnv()
+		require.NoError(t, err)
+		value, err := fromEnv.Resolve(ctx, "vault:bbs/server#apiTokens")
+		require.NoError(t, err)
+		assert.Equal(t, "a,b", value)
+
+		t.Setenv("VAULT_TOKEN", "")
+		_, err = NewResolverFromEnv()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultVaultMount is the mount of the KV version 2 engine Vault enables by default
+	DefaultVaultMount = "secret"
+	// DefaultVaultTimeout bounds each request to Vault made with the default client
+	DefaultVaultTimeout = 10 * time.Second
+	// maxVaultResponseSize bounds the responses read from Vault
+	maxVaultResponseSize = 1 << 20
+)
+
+// VaultConfig configures a VaultProvider
+type VaultConfig struct {
+	// Addr is the Vault server's base URL, e.g. https://vault.example.com:8200
+	Addr string
+	// Token authenticates every request
+	Token string
+	// Namespace is the Vault Enterprise namespace; empty uses the root namespace
+	Namespace string
+	// Mount is the path the KV version 2 engine is mounted at; empty uses DefaultVaultMount
+	Mount string
+	// Client sends the requests; nil uses one with DefaultVaultTimeout
+	Client *http.Client
+}
+
+// VaultProvider reads secrets from a HashiCorp Vault KV version 2 engine. A secret is named by
+// its path and key, path#key, e.g. bbs/server#apiTokens reads the apiTokens key of the latest
+// version of secret/bbs/server.
+type VaultProvider struct {
+	config VaultConfig
+}
+
+// NewVaultProvider creates a provider reading secrets from the Vault server config describes
+func NewVaultProvider(config VaultConfig) (*VaultProvider, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("vault address is required")
+	}
+	if config.Token == "" {
+		return nil, fmt.Errorf("vault token is required")
+	}
+	if config.Mount == "" {
+		config.Mount = DefaultVaultMount
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: DefaultVaultTimeout}
+	}
+	config.Addr = strings.TrimSuffix(config.Addr, "/")
+	config.Mount = strings.Trim(config.Mount, "/")
+	return &VaultProvider{config: config}, nil
+}
+
+// Secret reads the key of the secret at path, for a name of the form path#key
+func (p *VaultProvider) Secret(ctx context.Context, name string) (string, error) {
+	path, key, ok := strings.Cut(name, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault secret %q must be named path#key", name)
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	endpoint := p.config.Addr + "/v1/" + p.config.Mount + "/data/" + strings.Join(segments, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.config.Token)
+	if p.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.config.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: no vault secret at %s", ErrNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("vault answered %s for %s", resp.Status, path)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVaultResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid vault response for %s: %w", path, err)
+	}
+
+	value, ok := body.Data.Data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: vault secret %s has no key %s", ErrNotFound, path, key)
+	}
+	secret, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s key %s is not a string", path, key)
+	}
+	return secret, nil
+}
@@ -0,0 +1,136 @@
+// Package shamir splits secrets into shares with Shamir's secret sharing over GF(2^8): any
+// threshold of the shares rebuild the secret, and fewer reveal nothing about it. Each byte of the
+// secret is the constant term of its own random polynomial of degree threshold-1, and a share holds
+// every polynomial evaluated at the share's index.
+package shamir
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// MaxShares is the most shares a secret can be split into, one per non-zero field element
+const MaxShares = 255
+
+// Share is one share of a secret
+type Share struct {
+	// Index is the point the share's polynomials are evaluated at, from 1 to MaxShares
+	Index int    `json:"index"`
+	Value []byte `json:"value"`
+}
+
+// Split splits a secret into n shares, any threshold of which rebuild it
+func Split(secret []byte, n, threshold int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("secret cannot be empty")
+	}
+	if threshold < 2 {
+		return nil, fmt.Errorf("threshold must be at least 2, got %d", threshold)
+	}
+	if n < threshold || n > MaxShares {
+		return nil, fmt.Errorf("share count must be between the threshold %d and %d, got %d", threshold, MaxShares, n)
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{Index: i + 1, Value: make([]byte, len(secret))}
+	}
+
+	coefficients := make([]byte, threshold)
+	for b, s := range secret {
+		coefficients[0] = s
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("failed to generate coefficients: %w", err)
+		}
+		for i := range shares {
+			shares[i].Value[b] = evaluate(coefficients, byte(shares[i].Index))
+		}
+	}
+	for i := range coefficients {
+		coefficients[i] = 0
+	}
+	return shares, nil
+}
+
+// Combine rebuilds a secret from at least threshold of its shares. Combine cannot tell how many
+// shares the threshold was: too few shares yield a wrong secret, which callers must detect.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("at least 2 shares are needed, got %d", len(shares))
+	}
+	size := len(shares[0].Value)
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if share.Index < 1 || share.Index > MaxShares {
+			return nil, fmt.Errorf("invalid share index %d", share.Index)
+		}
+		if seen[share.Index] {
+			return nil, fmt.Errorf("duplicate share index %d", share.Index)
+		}
+		seen[share.Index] = true
+		if len(share.Value) != size || size == 0 {
+			return nil, fmt.Errorf("shares have different lengths")
+		}
+	}
+
+	// Lagrange interpolation at zero: secret = sum of y_i * prod_{j != i} x_j / (x_j - x_i);
+	// subtraction is addition in GF(2^8)
+	secret := make([]byte, size)
+	for i, share := range shares {
+		basis := byte(1)
+		xi := byte(share.Index)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			xj := byte(other.Index)
+			basis = mul(basis, div(xj, xj^xi))
+		}
+		for b := range secret {
+			secret[b] ^= mul(share.Value[b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// evaluate evaluates the polynomial with the given coefficients, constant term first, by Horner's rule
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// exp and log tables of GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1 and generator 3
+var expTable, logTable = func() ([510]byte, [256]byte) {
+	var exp [510]byte
+	var log [256]byte
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = x, x
+		log[x] = byte(i)
+		// Multiply by the generator 3: x*2 ^ x, reducing by the polynomial
+		doubled := x << 1
+		if x&0x80 != 0 {
+			doubled ^= 0x1b
+		}
+		x = doubled ^ x
+	}
+	return exp, log
+}()
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// div divides a by a non-zero b
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
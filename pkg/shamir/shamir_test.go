@@ -0,0 +1,79 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShamir(t *testing.T) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	require.NoError(t, err)
+
+	t.Run("Any Threshold Of Shares Rebuild The Secret", func(t *testing.T) {
+		shares, err := Split(secret, 5, 3)
+		require.NoError(t, err)
+		require.Len(t, shares, 5)
+
+		for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var chosen []Share
+			for _, i := range subset {
+				chosen = append(chosen, shares[i])
+			}
+			got, err := Combine(chosen)
+			require.NoError(t, err)
+			assert.Equal(t, secret, got, subset)
+		}
+
+		// Too few shares yield some other value
+		got, err := Combine(shares[:2])
+		require.NoError(t, err)
+		assert.NotEqual(t, secret, got)
+	})
+
+	t.Run("Shares Do Not Contain The Secret", func(t *testing.T) {
+		shares, err := Split(secret, 3, 2)
+		require.NoError(t, err)
+		for _, share := range shares {
+			assert.NotEqual(t, secret, share.Value)
+		}
+	})
+
+	t.Run("Field Arithmetic", func(t *testing.T) {
+		// 0x53 and 0xca are inverses in the AES field (FIPS 197 section 4.2)
+		assert.Equal(t, byte(1), mul(0x53, 0xca))
+		assert.Equal(t, byte(0xc1), mul(0x57, 0x83))
+		for a := 1; a < 256; a++ {
+			assert.Equal(t, byte(1), div(byte(a), byte(a)))
+			assert.Equal(t, byte(a), mul(div(byte(a), 0x1d), 0x1d))
+		}
+	})
+
+	t.Run("Invalid Parameters", func(t *testing.T) {
+		for name, params := range map[string][3]int{
+			"Threshold Of One":         {3, 1, 32},
+			"Fewer Shares Than Needed": {2, 3, 32},
+			"Too Many Shares":          {256, 3, 32},
+			"Empty Secret":             {3, 2, 0},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := Split(secret[:params[2]], params[0], params[1])
+				assert.Error(t, err)
+			})
+		}
+
+		shares, err := Split(secret, 3, 2)
+		require.NoError(t, err)
+		_, err = Combine(shares[:1])
+		assert.Error(t, err)
+		_, err = Combine([]Share{shares[0], shares[0]})
+		assert.Error(t, err)
+		_, err = Combine([]Share{shares[0], {Index: 0, Value: shares[1].Value}})
+		assert.Error(t, err)
+		_, err = Combine([]Share{shares[0], {Index: 2, Value: shares[1].Value[:5]}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,198 @@
+// Package smt implements a sparse Merkle tree over the 2^256 positions given by the SHA-256 of a
+// key. Every position not holding a key holds the empty leaf, so the tree proves a key absent as
+// readily as present: the proof is the path to the key's position, which ends in the empty leaf.
+package smt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+)
+
+// Depth is the number of levels between the root and the leaves
+const Depth = 256
+
+// HashSize is the size of every node hash
+const HashSize = sha256.Size
+
+var (
+	leafPrefix = []byte{0x00}
+	nodePrefix = []byte{0x01}
+)
+
+// empty[d] is the hash of an empty subtree whose root is at depth d; empty[Depth] is the empty leaf
+var empty = func() [Depth + 1][]byte {
+	var hashes [Depth + 1][]byte
+	hashes[Depth] = make([]byte, HashSize)
+	for d := Depth - 1; d >= 0; d-- {
+		hashes[d] = hashNode(hashes[d+1], hashes[d+1])
+	}
+	return hashes
+}()
+
+// Proof is the path from a position to the root. Siblings equal to the empty subtree at their
+// level are left out; Bitmap marks, most significant bit first from the root, the levels whose
+// sibling is included.
+type Proof struct {
+	Bitmap   []byte   `json:"bitmap"`
+	Siblings [][]byte `json:"siblings"`
+}
+
+// Tree is a sparse Merkle tree held in memory
+type Tree struct {
+	leaves []leaf
+}
+
+type leaf struct {
+	index []byte
+	hash  []byte
+}
+
+// New creates a tree holding the given keys, each with a value mixed into its leaf. Callers that
+// must not let neighbouring leaves be guessed, e.g. when keys are claim names, use random values.
+func New(values map[string][]byte) *Tree {
+	tree := &Tree{}
+	for key, value := range values {
+		index := Index(key)
+		tree.leaves = append(tree.leaves, leaf{index: index, hash: hashLeaf(index, value)})
+	}
+	sort.Slice(tree.leaves, func(i, j int) bool {
+		return bytes.Compare(tree.leaves[i].index, tree.leaves[j].index) < 0
+	})
+	return tree
+}
+
+// Index returns the position of a key
+func Index(key string) []byte {
+	digest := sha256.Sum256([]byte(key))
+	return digest[:]
+}
+
+// Root returns the root hash of the tree
+func (t *Tree) Root() []byte {
+	return subtreeHash(t.leaves, 0)
+}
+
+// Prove returns the path from the position of key to the root, whether or not key is present
+func (t *Tree) Prove(key string) *Proof {
+	index := Index(key)
+	proof := &Proof{Bitmap: make([]byte, Depth/8)}
+
+	leaves := t.leaves
+	for d := 0; d < Depth; d++ {
+		left, right := split(leaves, d)
+		sibling, next := right, left
+		if bit(index, d) {
+			sibling, next = left, right
+		}
+
+		if len(sibling) > 0 {
+			proof.Bitmap[d/8] |= 0x80 >> (d % 8)
+			proof.Siblings = append(proof.Siblings, subtreeHash(sibling, d+1))
+		}
+
+		leaves = next
+		if len(leaves) == 0 {
+			// The rest of the path runs through empty subtrees, whose siblings are empty too
+			break
+		}
+	}
+	return proof
+}
+
+// VerifyAbsence checks that proof shows key absent from the tree with the given root
+func VerifyAbsence(root []byte, key string, proof *Proof) error {
+	return verify(root, Index(key), empty[Depth], proof)
+}
+
+// VerifyInclusion checks that proof shows key present with value in the tree with the given root
+func VerifyInclusion(root []byte, key string, value []byte, proof *Proof) error {
+	index := Index(key)
+	return verify(root, index, hashLeaf(index, value), proof)
+}
+
+func verify(root, index, leafHash []byte, proof *Proof) error {
+	if proof == nil {
+		return fmt.Errorf("proof is nil")
+	}
+	if len(proof.Bitmap) != Depth/8 {
+		return fmt.Errorf("proof bitmap has %d bytes, not %d", len(proof.Bitmap), Depth/8)
+	}
+
+	included := 0
+	for d := 0; d < Depth; d++ {
+		if proof.Bitmap[d/8]&(0x80>>(d%8)) != 0 {
+			included++
+		}
+	}
+	if included != len(proof.Siblings) {
+		return fmt.Errorf("proof bitmap marks %d siblings but has %d", included, len(proof.Siblings))
+	}
+
+	hash := leafHash
+	next := len(proof.Siblings) - 1
+	for d := Depth - 1; d >= 0; d-- {
+		sibling := empty[d+1]
+		if proof.Bitmap[d/8]&(0x80>>(d%8)) != 0 {
+			sibling = proof.Siblings[next]
+			next--
+			if len(sibling) != HashSize {
+				return fmt.Errorf("proof sibling at depth %d has %d bytes", d, len(sibling))
+			}
+		}
+
+		if bit(index, d) {
+			hash = hashNode(sibling, hash)
+		} else {
+			hash = hashNode(hash, sibling)
+		}
+	}
+
+	if !bytes.Equal(hash, root) {
+		return fmt.Errorf("proof does not lead to the root")
+	}
+	return nil
+}
+
+// subtreeHash hashes the subtree rooted at depth d holding the given sorted leaves
+func subtreeHash(leaves []leaf, d int) []byte {
+	if len(leaves) == 0 {
+		return empty[d]
+	}
+	if d == Depth {
+		return leaves[0].hash
+	}
+
+	left, right := split(leaves, d)
+	return hashNode(subtreeHash(left, d+1), subtreeHash(right, d+1))
+}
+
+// split divides sorted leaves by the bit of their index at depth d
+func split(leaves []leaf, d int) ([]leaf, []leaf) {
+	i := sort.Search(len(leaves), func(i int) bool {
+		return bit(leaves[i].index, d)
+	})
+	return leaves[:i], leaves[i:]
+}
+
+// bit reports whether the bit of index at depth d, most significant first, is set
+func bit(index []byte, d int) bool {
+	return index[d/8]&(0x80>>(d%8)) != 0
+}
+
+func hashLeaf(index, value []byte) []byte {
+	h := sha256.New()
+	h.Write(leafPrefix)
+	h.Write(index)
+	h.Write(value)
+	return h.Sum(nil)
+}
+
+func hashNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write(nodePrefix)
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,71 @@
+package smt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	values := map[string][]byte{}
+	for i := 0; i < 20; i++ {
+		values[fmt.Sprintf("claim%d", i)] = []byte(fmt.Sprintf("salt%d", i))
+	}
+	tree := New(values)
+	root := tree.Root()
+
+	t.Run("Absent Keys", func(t *testing.T) {
+		for _, key := range []string{"criminalRecord", "claim20", ""} {
+			proof := tree.Prove(key)
+			assert.NoError(t, VerifyAbsence(root, key, proof), key)
+			assert.Error(t, VerifyInclusion(root, key, []byte("salt"), proof), key)
+		}
+	})
+
+	t.Run("Present Keys", func(t *testing.T) {
+		for key, value := range values {
+			proof := tree.Prove(key)
+			assert.NoError(t, VerifyInclusion(root, key, value, proof), key)
+			assert.Error(t, VerifyAbsence(root, key, proof), "a present key cannot be proven absent: %s", key)
+		}
+	})
+
+	t.Run("Proof For Another Key", func(t *testing.T) {
+		proof := tree.Prove("criminalRecord")
+		assert.Error(t, VerifyAbsence(root, "claim3", proof))
+	})
+
+	t.Run("Tampered Proof", func(t *testing.T) {
+		proof := tree.Prove("criminalRecord")
+		require.NotEmpty(t, proof.Siblings)
+
+		proof.Siblings = proof.Siblings[1:]
+		assert.Error(t, VerifyAbsence(root, "criminalRecord", proof))
+
+		proof = tree.Prove("criminalRecord")
+		proof.Siblings[0] = make([]byte, HashSize)
+		assert.Error(t, VerifyAbsence(root, "criminalRecord", proof))
+
+		assert.Error(t, VerifyAbsence(root, "criminalRecord", &Proof{Bitmap: make([]byte, 3)}))
+		assert.Error(t, VerifyAbsence(root, "criminalRecord", nil))
+	})
+
+	t.Run("Root Depends On Values", func(t *testing.T) {
+		changed := map[string][]byte{}
+		for key, value := range values {
+			changed[key] = value
+		}
+		changed["claim0"] = []byte("other")
+		assert.NotEqual(t, root, New(changed).Root())
+		assert.Equal(t, root, New(values).Root())
+	})
+
+	t.Run("Empty Tree", func(t *testing.T) {
+		empty := New(nil)
+		proof := empty.Prove("anything")
+		assert.Empty(t, proof.Siblings)
+		assert.NoError(t, VerifyAbsence(empty.Root(), "anything", proof))
+	})
+}
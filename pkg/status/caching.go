@@ -0,0 +1,196 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+)
+
+// PublicationPath is where a server publishes the status lists it manages, under its base URL;
+// the list ID follows it, percent-encoded
+const PublicationPath = "/api/status-lists/"
+
+// CachingRegistry checks entries that point at status lists published over HTTP(S) by fetching
+// the list credential through a fetcher, which caches it for its TTL and revalidates it with its
+// ETag. Entries for lists the wrapped registry manages go straight to it, so revocations by a
+// local issuer take effect immediately. Lists with other IDs that the wrapped registry does not
+// know are fetched from the peers, if any, at {peer}/api/status-lists/{id}. The wrapped registry
+// may be nil.
+type CachingRegistry struct {
+	inner   Registry
+	fetcher *fetch.Fetcher
+	peers   []string
+}
+
+// NewCachingRegistry wraps a registry so remote status lists are fetched through fetcher
+func NewCachingRegistry(inner Registry, fetcher *fetch.Fetcher) *CachingRegistry {
+	return &CachingRegistry{inner: inner, fetcher: fetcher}
+}
+
+// NewCachingRegistryWithPeers wraps a registry so remote status lists, and lists it does not
+// know, are fetched through fetcher from where they are published
+func NewCachingRegistryWithPeers(inner Registry, fetcher *fetch.Fetcher, peers []string) *CachingRegistry {
+	trimmed := make([]string, len(peers))
+	for i, peer := range peers {
+		trimmed[i] = strings.TrimSuffix(peer, "/")
+	}
+
+	return &CachingRegistry{inner: inner, fetcher: fetcher, peers: trimmed}
+}
+
+// Ping reports whether the wrapped registry is reachable
+func (c *CachingRegistry) Ping(ctx context.Context) error {
+	if pinger, ok := c.inner.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return ctx.Err()
+}
+
+// Allocate reserves an index in one of the wrapped registry's lists
+func (c *CachingRegistry) Allocate(issuerDID string, purpose Purpose) (*Entry, error) {
+	return c.local().Allocate(issuerDID, purpose)
+}
+
+// SetStatus sets or clears a bit in one of the wrapped registry's lists
+func (c *CachingRegistry) SetStatus(entry *Entry, value bool) error {
+	return c.local().SetStatus(entry, value)
+}
+
+// GetStatus reports whether the bit an entry points at is set
+func (c *CachingRegistry) GetStatus(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, fmt.Errorf("status entry is nil")
+	}
+
+	var credential *ListCredential
+	if isRemoteList(entry.StatusListCredential) {
+		fetched, err := c.fetchList(entry.StatusListCredential)
+		if err != nil {
+			return false, err
+		}
+		credential = fetched
+	} else {
+		set, err := c.local().GetStatus(entry)
+		if err == nil || len(c.peers) == 0 {
+			return set, err
+		}
+		fetched, peerErr := c.fetchFromPeers(entry.StatusListCredential)
+		if peerErr != nil {
+			return false, errors.Join(err, peerErr)
+		}
+		credential = fetched
+	}
+
+	if credential.CredentialSubject.StatusPurpose != entry.StatusPurpose {
+		return false, fmt.Errorf("status purpose mismatch: list is for %s, entry is for %s", credential.CredentialSubject.StatusPurpose, entry.StatusPurpose)
+	}
+
+	index, err := strconv.Atoi(entry.StatusListIndex)
+	if err != nil {
+		return false, fmt.Errorf("invalid status list index %q: %w", entry.StatusListIndex, err)
+	}
+
+	list, err := DecodeList(credential.CredentialSubject.EncodedList)
+	if err != nil {
+		return false, fmt.Errorf("invalid status list %s: %w", entry.StatusListCredential, err)
+	}
+
+	return list.Get(index)
+}
+
+// GetListCredential returns a list credential, fetched through the cache when it is published remotely
+func (c *CachingRegistry) GetListCredential(listID string) (*ListCredential, error) {
+	if isRemoteList(listID) {
+		return c.fetchList(listID)
+	}
+
+	credential, err := c.local().GetListCredential(listID)
+	if err == nil || len(c.peers) == 0 {
+		return credential, err
+	}
+	fetched, peerErr := c.fetchFromPeers(listID)
+	if peerErr != nil {
+		return nil, errors.Join(err, peerErr)
+	}
+	return fetched, nil
+}
+
+// Stats returns how remote status list lookups were answered
+func (c *CachingRegistry) Stats() fetch.Stats {
+	return c.fetcher.Stats()
+}
+
+// local returns the wrapped registry, or an error registry when there is none
+func (c *CachingRegistry) local() Registry {
+	if c.inner == nil {
+		return emptyRegistry{}
+	}
+	return c.inner
+}
+
+// fetchList fetches and decodes a list credential published at its ID
+func (c *CachingRegistry) fetchList(listURL string) (*ListCredential, error) {
+	return c.fetchListAt(listURL, listURL)
+}
+
+// fetchFromPeers fetches a list credential from the first peer that publishes it
+func (c *CachingRegistry) fetchFromPeers(listID string) (*ListCredential, error) {
+	var errs []error
+	for _, peer := range c.peers {
+		credential, err := c.fetchListAt(peer+PublicationPath+url.PathEscape(listID), listID)
+		if err == nil {
+			return credential, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// fetchListAt fetches and decodes a list credential, checking it is the list asked for
+func (c *CachingRegistry) fetchListAt(listURL, listID string) (*ListCredential, error) {
+	body, err := c.fetcher.Get(context.Background(), listURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var credential ListCredential
+	if err := json.Unmarshal(body, &credential); err != nil {
+		return nil, fmt.Errorf("invalid status list credential at %s: %w", listURL, err)
+	}
+
+	if credential.ID != listID {
+		return nil, fmt.Errorf("status list credential at %s has id %s", listURL, credential.ID)
+	}
+
+	return &credential, nil
+}
+
+// isRemoteList reports whether a status list is identified by an HTTP(S) URL it can be fetched from
+func isRemoteList(listID string) bool {
+	return strings.HasPrefix(listID, "https://") || strings.HasPrefix(listID, "http://")
+}
+
+// emptyRegistry manages no lists
+type emptyRegistry struct{}
+
+func (emptyRegistry) Allocate(issuerDID string, purpose Purpose) (*Entry, error) {
+	return nil, fmt.Errorf("no local status registry configured")
+}
+
+func (emptyRegistry) SetStatus(entry *Entry, value bool) error {
+	return fmt.Errorf("no local status registry configured")
+}
+
+func (emptyRegistry) GetStatus(entry *Entry) (bool, error) {
+	return false, fmt.Errorf("status list not found: %s", entry.StatusListCredential)
+}
+
+func (emptyRegistry) GetListCredential(listID string) (*ListCredential, error) {
+	return nil, fmt.Errorf("status list not found: %s", listID)
+}
@@ -0,0 +1,139 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingRegistry(t *testing.T) {
+	list, err := NewList(16)
+	require.NoError(t, err)
+	require.NoError(t, list.Set(3, true))
+	encoded, err := list.Encode()
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	var listURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		json.NewEncoder(w).Encode(ListCredential{
+			ID:   listURL,
+			Type: []string{"VerifiableCredential", ListCredentialType},
+			CredentialSubject: ListSubject{
+				Type:          ListType,
+				StatusPurpose: PurposeRevocation,
+				EncodedList:   encoded,
+			},
+		})
+	}))
+	defer ts.Close()
+	listURL = ts.URL + "/status/1"
+
+	local := NewInMemoryRegistryWithSize(16)
+	registry := NewCachingRegistry(local, fetch.NewFetcher(ts.Client(), time.Hour))
+
+	t.Run("Remote Lists Are Fetched Once", func(t *testing.T) {
+		for index, expected := range map[string]bool{"3": true, "4": false} {
+			set, err := registry.GetStatus(&Entry{StatusPurpose: PurposeRevocation, StatusListIndex: index, StatusListCredential: listURL})
+			require.NoError(t, err)
+			assert.Equal(t, expected, set)
+		}
+
+		credential, err := registry.GetListCredential(listURL)
+		require.NoError(t, err)
+		assert.Equal(t, listURL, credential.ID)
+
+		assert.Equal(t, int32(1), requests.Load())
+		assert.Equal(t, fetch.Stats{Hits: 2, Misses: 1}, registry.Stats())
+	})
+
+	t.Run("Remote Entries Are Checked", func(t *testing.T) {
+		_, err := registry.GetStatus(&Entry{StatusPurpose: PurposeSuspension, StatusListIndex: "3", StatusListCredential: listURL})
+		assert.Error(t, err)
+
+		_, err = registry.GetStatus(&Entry{StatusPurpose: PurposeRevocation, StatusListIndex: "99", StatusListCredential: listURL})
+		assert.Error(t, err)
+
+		_, err = registry.GetStatus(&Entry{StatusPurpose: PurposeRevocation, StatusListIndex: "3", StatusListCredential: ts.URL + "/status/2"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has id")
+	})
+
+	t.Run("Local Lists Bypass The Cache", func(t *testing.T) {
+		entry, err := registry.Allocate("did:example:issuer", PurposeRevocation)
+		require.NoError(t, err)
+
+		require.NoError(t, registry.SetStatus(entry, true))
+		set, err := registry.GetStatus(entry)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		require.NoError(t, local.SetStatus(entry, false))
+		set, err = registry.GetStatus(entry)
+		require.NoError(t, err)
+		assert.False(t, set, "revocations by a local issuer take effect immediately")
+	})
+
+	t.Run("Without A Local Registry", func(t *testing.T) {
+		remoteOnly := NewCachingRegistry(nil, fetch.NewFetcher(ts.Client(), time.Hour))
+		_, err := remoteOnly.Allocate("did:example:issuer", PurposeRevocation)
+		assert.Error(t, err)
+
+		_, err = remoteOnly.GetStatus(&Entry{StatusPurpose: PurposeRevocation, StatusListIndex: "0", StatusListCredential: "urn:uuid:unknown"})
+		assert.Error(t, err)
+
+		set, err := remoteOnly.GetStatus(&Entry{StatusPurpose: PurposeRevocation, StatusListIndex: "3", StatusListCredential: listURL})
+		require.NoError(t, err)
+		assert.True(t, set)
+	})
+
+	t.Run("Unknown Lists Are Fetched From Peers", func(t *testing.T) {
+		// The peer publishes the lists of an issuer running in another process
+		published := NewInMemoryRegistryWithSize(16)
+		peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			listID, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), PublicationPath))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			credential, err := published.GetListCredential(listID)
+			if err != nil {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(credential)
+		}))
+		defer peer.Close()
+
+		entry, err := published.Allocate("did:example:issuer", PurposeRevocation)
+		require.NoError(t, err)
+		require.NoError(t, published.SetStatus(entry, true))
+
+		withPeers := NewCachingRegistryWithPeers(NewInMemoryRegistryWithSize(16), fetch.NewFetcher(nil, 0), []string{peer.URL + "/"})
+		set, err := withPeers.GetStatus(entry)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		require.NoError(t, published.SetStatus(entry, false))
+		set, err = withPeers.GetStatus(entry)
+		require.NoError(t, err)
+		assert.False(t, set)
+
+		credential, err := withPeers.GetListCredential(entry.StatusListCredential)
+		require.NoError(t, err)
+		assert.Equal(t, entry.StatusListCredential, credential.ID)
+
+		_, err = withPeers.GetStatus(&Entry{StatusPurpose: PurposeRevocation, StatusListIndex: "0", StatusListCredential: "urn:uuid:unknown"})
+		assert.Error(t, err)
+	})
+}
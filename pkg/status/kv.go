@@ -0,0 +1,285 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// kvList is the stored description of a status list; its bits are stored as separate keys
+type kvList struct {
+	Issuer  string  `json:"issuer"`
+	Purpose Purpose `json:"purpose"`
+	Size    int     `json:"size"`
+	// Next is a hint at the first unallocated index; allocation never trusts it alone
+	Next int `json:"next"`
+}
+
+// KVRegistry is a Registry kept in a key-value store, so every server instance sharing the store
+// allocates from, revokes in and publishes the same lists. Indexes are claimed with SetNX, so two
+// instances never hand out the same index, and each set bit is its own key, so concurrent status
+// changes do not overwrite each other.
+type KVRegistry struct {
+	store storage.KVStore
+	size  int
+
+	// hints remembers the next index to try per list, sparing a read of the stored hint
+	hintsMu sync.Mutex
+	hints   map[string]int
+}
+
+// NewKVRegistry creates a status list registry on top of store, starting lists of the given size
+func NewKVRegistry(store storage.KVStore, size int) Registry {
+	if size <= 0 {
+		size = DefaultListSize
+	}
+	return &KVRegistry{
+		store: store,
+		size:  size,
+		hints: make(map[string]int),
+	}
+}
+
+// Ping reports whether the underlying store is reachable
+func (r *KVRegistry) Ping(ctx context.Context) error {
+	return r.store.Ping(ctx)
+}
+
+// Allocate reserves the next free index in the issuer's list for the purpose, starting a new list when full
+func (r *KVRegistry) Allocate(issuerDID string, purpose Purpose) (*Entry, error) {
+	if issuerDID == "" {
+		return nil, fmt.Errorf("issuer DID is required")
+	}
+
+	if purpose != PurposeRevocation && purpose != PurposeSuspension {
+		return nil, fmt.Errorf("unsupported status purpose: %s", purpose)
+	}
+
+	currentKey := "current:" + issuerDID + "|" + string(purpose)
+	listID, err := r.store.Get(currentKey)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load current status list: %w", err)
+	}
+
+	for {
+		if listID == nil {
+			id, err := r.createList(issuerDID, purpose)
+			if err != nil {
+				return nil, err
+			}
+			if err := r.store.Set(currentKey, []byte(id), 0); err != nil {
+				return nil, fmt.Errorf("failed to store current status list: %w", err)
+			}
+			listID = []byte(id)
+		}
+
+		index, ok, err := r.claim(string(listID))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			id := string(listID)
+			return &Entry{
+				ID:                   fmt.Sprintf("%s#%d", id, index),
+				Type:                 EntryType,
+				StatusPurpose:        purpose,
+				StatusListIndex:      strconv.Itoa(index),
+				StatusListCredential: id,
+			}, nil
+		}
+
+		// The list is full
+		listID = nil
+	}
+}
+
+// createList stores a new, empty list
+func (r *KVRegistry) createList(issuerDID string, purpose Purpose) (string, error) {
+	if _, err := NewList(r.size); err != nil {
+		return "", fmt.Errorf("failed to create status list: %w", err)
+	}
+
+	id := "urn:uuid:" + uuid.New().String()
+	if err := storage.SetJSON(r.store, "list:"+id, &kvList{Issuer: issuerDID, Purpose: purpose, Size: r.size}, 0); err != nil {
+		return "", fmt.Errorf("failed to store status list: %w", err)
+	}
+	return id, nil
+}
+
+// claim reserves the first free index of a list, reporting false when the list is full
+func (r *KVRegistry) claim(listID string) (int, bool, error) {
+	list, err := r.list(listID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	r.hintsMu.Lock()
+	index := r.hints[listID]
+	r.hintsMu.Unlock()
+	if list.Next > index {
+		index = list.Next
+	}
+
+	for ; index < list.Size; index++ {
+		claimed, err := r.store.SetNX(indexKey(listID, index), []byte{1}, 0)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to allocate status list index: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		r.hintsMu.Lock()
+		r.hints[listID] = index + 1
+		r.hintsMu.Unlock()
+		// The stored hint may go backwards under concurrent allocation; claims stay unique regardless
+		list.Next = index + 1
+		if err := storage.SetJSON(r.store, "list:"+listID, list, 0); err != nil {
+			return 0, false, fmt.Errorf("failed to store status list: %w", err)
+		}
+		return index, true, nil
+	}
+	return 0, false, nil
+}
+
+// SetStatus sets or clears the bit an entry points at
+func (r *KVRegistry) SetStatus(entry *Entry, value bool) error {
+	_, index, err := r.lookup(entry)
+	if err != nil {
+		return err
+	}
+
+	key := bitKey(entry.StatusListCredential, index)
+	if value {
+		err = r.store.Set(key, []byte{1}, 0)
+	} else {
+		err = r.store.Delete(key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store status: %w", err)
+	}
+	return nil
+}
+
+// GetStatus reports whether the bit an entry points at is set
+func (r *KVRegistry) GetStatus(entry *Entry) (bool, error) {
+	_, index, err := r.lookup(entry)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = r.store.Get(bitKey(entry.StatusListCredential, index))
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load status: %w", err)
+	}
+	return true, nil
+}
+
+// GetListCredential returns the current publishable form of a list
+func (r *KVRegistry) GetListCredential(listID string) (*ListCredential, error) {
+	stored, err := r.list(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := NewList(stored.Size)
+	if err != nil {
+		return nil, err
+	}
+	prefix := "bit:" + listID + ":"
+	keys, err := r.store.Keys(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status list bits: %w", err)
+	}
+	for _, key := range keys {
+		index, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			continue
+		}
+		if err := list.Set(index, true); err != nil {
+			return nil, err
+		}
+	}
+
+	encoded, err := list.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListCredential{
+		Context: []string{
+			"https://www.w3.org/ns/credentials/v2",
+		},
+		ID:        listID,
+		Type:      []string{"VerifiableCredential", ListCredentialType},
+		Issuer:    stored.Issuer,
+		ValidFrom: time.Now(),
+		CredentialSubject: ListSubject{
+			ID:            listID + "#list",
+			Type:          ListType,
+			StatusPurpose: stored.Purpose,
+			EncodedList:   encoded,
+		},
+	}, nil
+}
+
+// list loads a list's description
+func (r *KVRegistry) list(listID string) (*kvList, error) {
+	var list kvList
+	if err := storage.GetJSON(r.store, "list:"+listID, &list); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("status list not found: %s", listID)
+		}
+		return nil, fmt.Errorf("failed to load status list: %w", err)
+	}
+	return &list, nil
+}
+
+// lookup finds the list and index an entry points at and checks the purposes agree
+func (r *KVRegistry) lookup(entry *Entry) (*kvList, int, error) {
+	if entry == nil {
+		return nil, 0, fmt.Errorf("status entry is nil")
+	}
+
+	list, err := r.list(entry.StatusListCredential)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if list.Purpose != entry.StatusPurpose {
+		return nil, 0, fmt.Errorf("status purpose mismatch: list is for %s, entry is for %s", list.Purpose, entry.StatusPurpose)
+	}
+
+	index, err := strconv.Atoi(entry.StatusListIndex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid status list index %q: %w", entry.StatusListIndex, err)
+	}
+
+	if _, err := r.store.Get(indexKey(entry.StatusListCredential, index)); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, 0, fmt.Errorf("status list index %d has not been allocated", index)
+		}
+		return nil, 0, fmt.Errorf("failed to load status list index: %w", err)
+	}
+
+	return list, index, nil
+}
+
+func indexKey(listID string, index int) string {
+	return "index:" + listID + ":" + strconv.Itoa(index)
+}
+
+func bitKey(listID string, index int) string {
+	return "bit:" + listID + ":" + strconv.Itoa(index)
+}
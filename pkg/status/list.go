@@ -0,0 +1,100 @@
+package status
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// List is a fixed-size bitstring where each bit holds the status of one credential.
+// Bit 0 is the most significant bit of the first byte.
+type List struct {
+	bits []byte
+	size int
+}
+
+// NewList creates a list with all bits cleared
+func NewList(size int) (*List, error) {
+	if size <= 0 || size%8 != 0 {
+		return nil, fmt.Errorf("list size must be a positive multiple of 8, got %d", size)
+	}
+
+	return &List{
+		bits: make([]byte, size/8),
+		size: size,
+	}, nil
+}
+
+// Size returns the number of entries in the list
+func (l *List) Size() int {
+	return l.size
+}
+
+// Set sets or clears the bit at index
+func (l *List) Set(index int, value bool) error {
+	if index < 0 || index >= l.size {
+		return fmt.Errorf("status list index %d out of range", index)
+	}
+
+	mask := byte(1 << (7 - index%8))
+	if value {
+		l.bits[index/8] |= mask
+	} else {
+		l.bits[index/8] &^= mask
+	}
+	return nil
+}
+
+// Get reports whether the bit at index is set
+func (l *List) Get(index int) (bool, error) {
+	if index < 0 || index >= l.size {
+		return false, fmt.Errorf("status list index %d out of range", index)
+	}
+
+	return l.bits[index/8]&byte(1<<(7-index%8)) != 0, nil
+}
+
+// Encode returns the GZIP-compressed, multibase base64url-encoded bitstring
+func (l *List) Encode() (string, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(l.bits); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress status list: %w", err)
+	}
+
+	return "u" + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeList parses an encoded bitstring produced by Encode
+func DecodeList(encoded string) (*List, error) {
+	if len(encoded) == 0 || encoded[0] != 'u' {
+		return nil, fmt.Errorf("encoded list must use multibase base64url ('u') encoding")
+	}
+
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded[1:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode status list: %w", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress status list: %w", err)
+	}
+	defer reader.Close()
+
+	bits, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress status list: %w", err)
+	}
+
+	if len(bits) == 0 {
+		return nil, fmt.Errorf("status list is empty")
+	}
+
+	return &List{bits: bits, size: len(bits) * 8}, nil
+}
@@ -0,0 +1,175 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// managedList is a status list together with its ownership and allocation state
+type managedList struct {
+	id      string
+	issuer  string
+	purpose Purpose
+	list    *List
+	next    int
+}
+
+// InMemoryRegistry implements Registry interface
+type InMemoryRegistry struct {
+	mu      sync.RWMutex
+	size    int
+	lists   map[string]*managedList
+	current map[string]*managedList // issuer DID + purpose -> list new entries are allocated from
+}
+
+// NewInMemoryRegistry creates a new in-memory status list registry using DefaultListSize lists
+func NewInMemoryRegistry() Registry {
+	return NewInMemoryRegistryWithSize(DefaultListSize)
+}
+
+// NewInMemoryRegistryWithSize creates a new in-memory status list registry with lists of the given size
+func NewInMemoryRegistryWithSize(size int) Registry {
+	return &InMemoryRegistry{
+		size:    size,
+		lists:   make(map[string]*managedList),
+		current: make(map[string]*managedList),
+	}
+}
+
+// Ping reports whether the registry is reachable; an in-memory registry always is
+func (r *InMemoryRegistry) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Allocate reserves the next free index in the issuer's list for the purpose, starting a new list when full
+func (r *InMemoryRegistry) Allocate(issuerDID string, purpose Purpose) (*Entry, error) {
+	if issuerDID == "" {
+		return nil, fmt.Errorf("issuer DID is required")
+	}
+
+	if purpose != PurposeRevocation && purpose != PurposeSuspension {
+		return nil, fmt.Errorf("unsupported status purpose: %s", purpose)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := issuerDID + "|" + string(purpose)
+	managed, ok := r.current[key]
+	if !ok || managed.next >= managed.list.Size() {
+		list, err := NewList(r.size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create status list: %w", err)
+		}
+
+		managed = &managedList{
+			id:      "urn:uuid:" + uuid.New().String(),
+			issuer:  issuerDID,
+			purpose: purpose,
+			list:    list,
+		}
+		r.lists[managed.id] = managed
+		r.current[key] = managed
+	}
+
+	index := managed.next
+	managed.next++
+
+	return &Entry{
+		ID:                   fmt.Sprintf("%s#%d", managed.id, index),
+		Type:                 EntryType,
+		StatusPurpose:        purpose,
+		StatusListIndex:      strconv.Itoa(index),
+		StatusListCredential: managed.id,
+	}, nil
+}
+
+// SetStatus sets or clears the bit an entry points at
+func (r *InMemoryRegistry) SetStatus(entry *Entry, value bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	managed, index, err := r.lookup(entry)
+	if err != nil {
+		return err
+	}
+
+	return managed.list.Set(index, value)
+}
+
+// GetStatus reports whether the bit an entry points at is set
+func (r *InMemoryRegistry) GetStatus(entry *Entry) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	managed, index, err := r.lookup(entry)
+	if err != nil {
+		return false, err
+	}
+
+	return managed.list.Get(index)
+}
+
+// GetListCredential returns the current publishable form of a list
+func (r *InMemoryRegistry) GetListCredential(listID string) (*ListCredential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	managed, ok := r.lists[listID]
+	if !ok {
+		return nil, fmt.Errorf("status list not found: %s", listID)
+	}
+
+	encoded, err := managed.list.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListCredential{
+		Context: []string{
+			"https://www.w3.org/ns/credentials/v2",
+		},
+		ID:        managed.id,
+		Type:      []string{"VerifiableCredential", ListCredentialType},
+		Issuer:    managed.issuer,
+		ValidFrom: time.Now(),
+		CredentialSubject: ListSubject{
+			ID:            managed.id + "#list",
+			Type:          ListType,
+			StatusPurpose: managed.purpose,
+			EncodedList:   encoded,
+		},
+	}, nil
+}
+
+// lookup finds the list and index an entry points at and checks the purposes agree
+func (r *InMemoryRegistry) lookup(entry *Entry) (*managedList, int, error) {
+	if entry == nil {
+		return nil, 0, fmt.Errorf("status entry is nil")
+	}
+
+	managed, ok := r.lists[entry.StatusListCredential]
+	if !ok {
+		return nil, 0, fmt.Errorf("status list not found: %s", entry.StatusListCredential)
+	}
+
+	if managed.purpose != entry.StatusPurpose {
+		return nil, 0, fmt.Errorf("status purpose mismatch: list is for %s, entry is for %s", managed.purpose, entry.StatusPurpose)
+	}
+
+	index, err := strconv.Atoi(entry.StatusListIndex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid status list index %q: %w", entry.StatusListIndex, err)
+	}
+
+	if index >= managed.next {
+		return nil, 0, fmt.Errorf("status list index %d has not been allocated", index)
+	}
+
+	return managed, index, nil
+}
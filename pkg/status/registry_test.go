@@ -0,0 +1,203 @@
+package status
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+func TestList(t *testing.T) {
+	list, err := NewList(64)
+	require.NoError(t, err)
+
+	require.NoError(t, list.Set(0, true))
+	require.NoError(t, list.Set(13, true))
+	require.NoError(t, list.Set(63, true))
+	require.NoError(t, list.Set(13, false))
+
+	set, err := list.Get(0)
+	require.NoError(t, err)
+	assert.True(t, set)
+
+	set, err = list.Get(13)
+	require.NoError(t, err)
+	assert.False(t, set)
+
+	// Bit 0 is the most significant bit of the first byte
+	assert.Equal(t, byte(0x80), list.bits[0])
+
+	assert.Error(t, list.Set(64, true))
+	_, err = list.Get(-1)
+	assert.Error(t, err)
+
+	_, err = NewList(10)
+	assert.Error(t, err)
+}
+
+func TestListEncoding(t *testing.T) {
+	list, err := NewList(DefaultListSize)
+	require.NoError(t, err)
+	require.NoError(t, list.Set(94567, true))
+
+	encoded, err := list.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, byte('u'), encoded[0])
+	// A mostly empty 16KB list compresses to a few hundred bytes
+	assert.Less(t, len(encoded), 1024)
+
+	decoded, err := DecodeList(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultListSize, decoded.Size())
+
+	set, err := decoded.Get(94567)
+	require.NoError(t, err)
+	assert.True(t, set)
+
+	set, err = decoded.Get(94568)
+	require.NoError(t, err)
+	assert.False(t, set)
+
+	_, err = DecodeList("zabc")
+	assert.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("In Memory", func(t *testing.T) {
+		testRegistry(t, NewInMemoryRegistryWithSize(16))
+	})
+	t.Run("Key Value Store", func(t *testing.T) {
+		testRegistry(t, NewKVRegistry(storage.NewMemoryStore(), 16))
+	})
+}
+
+func TestKVRegistryShared(t *testing.T) {
+	shared := storage.NewMemoryStore()
+	first := NewKVRegistry(shared, 16)
+	second := NewKVRegistry(shared, 16)
+
+	// Instances allocating from the same list never hand out the same index
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		registry := first
+		if i%3 == 0 {
+			registry = second
+		}
+		entry, err := registry.Allocate("did:example:issuer", PurposeRevocation)
+		require.NoError(t, err)
+		assert.False(t, seen[entry.ID], entry.ID)
+		seen[entry.ID] = true
+	}
+
+	entry, err := first.Allocate("did:example:issuer", PurposeRevocation)
+	require.NoError(t, err)
+	require.NoError(t, first.SetStatus(entry, true))
+
+	set, err := second.GetStatus(entry)
+	require.NoError(t, err)
+	assert.True(t, set, "a status set on one instance is seen on the other")
+
+	listCredential, err := second.GetListCredential(entry.StatusListCredential)
+	require.NoError(t, err)
+	decoded, err := DecodeList(listCredential.CredentialSubject.EncodedList)
+	require.NoError(t, err)
+	index, err := strconv.Atoi(entry.StatusListIndex)
+	require.NoError(t, err)
+	set, err = decoded.Get(index)
+	require.NoError(t, err)
+	assert.True(t, set)
+}
+
+func testRegistry(t *testing.T, registry Registry) {
+	t.Run("Allocate", func(t *testing.T) {
+		first, err := registry.Allocate("did:example:issuer", PurposeRevocation)
+		require.NoError(t, err)
+		assert.Equal(t, EntryType, first.Type)
+		assert.Equal(t, PurposeRevocation, first.StatusPurpose)
+		assert.Equal(t, "0", first.StatusListIndex)
+
+		second, err := registry.Allocate("did:example:issuer", PurposeRevocation)
+		require.NoError(t, err)
+		assert.Equal(t, first.StatusListCredential, second.StatusListCredential)
+		assert.Equal(t, "1", second.StatusListIndex)
+
+		// Each purpose has its own list
+		suspension, err := registry.Allocate("did:example:issuer", PurposeSuspension)
+		require.NoError(t, err)
+		assert.NotEqual(t, first.StatusListCredential, suspension.StatusListCredential)
+
+		_, err = registry.Allocate("did:example:issuer", Purpose("refresh"))
+		assert.Error(t, err)
+
+		_, err = registry.Allocate("", PurposeRevocation)
+		assert.Error(t, err)
+	})
+
+	t.Run("Full List Rolls Over", func(t *testing.T) {
+		var last *Entry
+		for i := 0; i < 17; i++ {
+			entry, err := registry.Allocate("did:example:rollover", PurposeSuspension)
+			require.NoError(t, err)
+			if i == 16 {
+				assert.NotEqual(t, last.StatusListCredential, entry.StatusListCredential)
+				assert.Equal(t, "0", entry.StatusListIndex)
+			}
+			last = entry
+		}
+	})
+
+	t.Run("Set And Get", func(t *testing.T) {
+		entry, err := registry.Allocate("did:example:status", PurposeSuspension)
+		require.NoError(t, err)
+
+		set, err := registry.GetStatus(entry)
+		require.NoError(t, err)
+		assert.False(t, set)
+
+		require.NoError(t, registry.SetStatus(entry, true))
+		set, err = registry.GetStatus(entry)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		listCredential, err := registry.GetListCredential(entry.StatusListCredential)
+		require.NoError(t, err)
+		assert.Equal(t, "did:example:status", listCredential.Issuer)
+		assert.Equal(t, PurposeSuspension, listCredential.CredentialSubject.StatusPurpose)
+
+		decoded, err := DecodeList(listCredential.CredentialSubject.EncodedList)
+		require.NoError(t, err)
+		set, err = decoded.Get(0)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		require.NoError(t, registry.SetStatus(entry, false))
+		set, err = registry.GetStatus(entry)
+		require.NoError(t, err)
+		assert.False(t, set)
+	})
+
+	t.Run("Invalid Entries", func(t *testing.T) {
+		entry, err := registry.Allocate("did:example:invalid", PurposeRevocation)
+		require.NoError(t, err)
+
+		mismatched := *entry
+		mismatched.StatusPurpose = PurposeSuspension
+		_, err = registry.GetStatus(&mismatched)
+		assert.Error(t, err)
+
+		unallocated := *entry
+		unallocated.StatusListIndex = "5"
+		_, err = registry.GetStatus(&unallocated)
+		assert.Error(t, err)
+
+		unknown := *entry
+		unknown.StatusListCredential = "urn:uuid:unknown"
+		assert.Error(t, registry.SetStatus(&unknown, true))
+
+		_, err = registry.GetListCredential("urn:uuid:unknown")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,63 @@
+package status
+
+import "time"
+
+// Purpose represents what a set bit in a status list means
+type Purpose string
+
+const (
+	// PurposeRevocation marks credentials that are permanently invalid
+	PurposeRevocation Purpose = "revocation"
+	// PurposeSuspension marks credentials that are temporarily invalid and may be reinstated
+	PurposeSuspension Purpose = "suspension"
+)
+
+const (
+	// EntryType is the credentialStatus type of a bitstring status list entry
+	EntryType = "BitstringStatusListEntry"
+	// ListType is the credential subject type of a bitstring status list
+	ListType = "BitstringStatusList"
+	// ListCredentialType is the credential type of a published status list
+	ListCredentialType = "BitstringStatusListCredential"
+	// DefaultListSize is the number of entries per list (16KB), large enough for herd privacy
+	DefaultListSize = 131072
+)
+
+// Entry is the credentialStatus entry that points a credential at its bit in a status list
+type Entry struct {
+	ID                   string  `json:"id"`
+	Type                 string  `json:"type"`
+	StatusPurpose        Purpose `json:"statusPurpose"`
+	StatusListIndex      string  `json:"statusListIndex"`
+	StatusListCredential string  `json:"statusListCredential"`
+}
+
+// ListSubject is the credential subject of a published status list credential
+type ListSubject struct {
+	ID            string  `json:"id"`
+	Type          string  `json:"type"`
+	StatusPurpose Purpose `json:"statusPurpose"`
+	EncodedList   string  `json:"encodedList"`
+}
+
+// ListCredential is the publishable form of a status list
+type ListCredential struct {
+	Context           []string    `json:"@context"`
+	ID                string      `json:"id"`
+	Type              []string    `json:"type"`
+	Issuer            string      `json:"issuer"`
+	ValidFrom         time.Time   `json:"validFrom"`
+	CredentialSubject ListSubject `json:"credentialSubject"`
+}
+
+// Registry interface for status list management
+type Registry interface {
+	// Allocate reserves a fresh index for a credential in one of the issuer's lists for the purpose
+	Allocate(issuerDID string, purpose Purpose) (*Entry, error)
+	// SetStatus sets or clears the bit an entry points at
+	SetStatus(entry *Entry, value bool) error
+	// GetStatus reports whether the bit an entry points at is set
+	GetStatus(entry *Entry) (bool, error)
+	// GetListCredential returns the current publishable form of a list
+	GetListCredential(listID string) (*ListCredential, error)
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// memoryEntry is a stored value and when it expires; a zero expiry never expires
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// MemoryStore is a KVStore held in process memory. Expired keys are dropped when they are next read.
+type MemoryStore struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(clock.System{})
+}
+
+// NewMemoryStoreWithClock creates an empty in-memory store whose TTLs are measured with c
+func NewMemoryStoreWithClock(c clock.Clock) *MemoryStore {
+	return &MemoryStore{
+		clock:   clock.OrSystem(c),
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get returns the value stored under key, or ErrNotFound
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entry(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyBytes(entry.value), nil
+}
+
+// Set stores value under key, replacing any existing value
+func (s *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = s.newEntry(value, ttl)
+	return nil
+}
+
+// SetNX stores value under key only if the key is absent
+func (s *MemoryStore) SetNX(key string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entry(key); ok {
+		return false, nil
+	}
+	s.entries[key] = s.newEntry(value, ttl)
+	return true, nil
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Keys returns the unexpired keys starting with prefix, sorted
+func (s *MemoryStore) Keys(prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for key := range s.entries {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if _, ok := s.entry(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return sortedKeys(keys), nil
+}
+
+// Ping reports whether the store is reachable; an in-memory store always is
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// entry returns a live entry, dropping it if it has expired. Callers hold mu.
+func (s *MemoryStore) entry(key string) (memoryEntry, bool) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+	if !entry.expires.IsZero() && !s.clock.Now().Before(entry.expires) {
+		delete(s.entries, key)
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+func (s *MemoryStore) newEntry(value []byte, ttl time.Duration) memoryEntry {
+	entry := memoryEntry{value: copyBytes(value)}
+	if ttl > 0 {
+		entry.expires = s.clock.Now().Add(ttl)
+	}
+	return entry
+}
+
+func copyBytes(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
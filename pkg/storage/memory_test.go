@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// testKVStore runs the behaviour every KVStore must share. advance moves the store's clock forward.
+func testKVStore(t *testing.T, store KVStore, advance func(time.Duration)) {
+	t.Run("Get Set Delete", func(t *testing.T) {
+		_, err := store.Get("missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		require.NoError(t, store.Set("a", []byte("one"), 0))
+		value, err := store.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, "one", string(value))
+
+		require.NoError(t, store.Set("a", []byte("two"), 0))
+		value, err = store.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, "two", string(value))
+
+		require.NoError(t, store.Delete("a"))
+		require.NoError(t, store.Delete("a"))
+		_, err = store.Get("a")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("SetNX", func(t *testing.T) {
+		stored, err := store.SetNX("nx", []byte("first"), 0)
+		require.NoError(t, err)
+		assert.True(t, stored)
+
+		stored, err = store.SetNX("nx", []byte("second"), 0)
+		require.NoError(t, err)
+		assert.False(t, stored)
+
+		value, err := store.Get("nx")
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(value))
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		require.NoError(t, store.Set("ttl", []byte("v"), time.Minute))
+		stored, err := store.SetNX("ttl-nx", []byte("v"), time.Minute)
+		require.NoError(t, err)
+		require.True(t, stored)
+
+		advance(2 * time.Minute)
+
+		_, err = store.Get("ttl")
+		assert.ErrorIs(t, err, ErrNotFound)
+		stored, err = store.SetNX("ttl-nx", []byte("again"), 0)
+		require.NoError(t, err)
+		assert.True(t, stored, "an expired key can be claimed again")
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		for _, key := range []string{"k:2", "k:1", "k*3", "other"} {
+			require.NoError(t, store.Set(key, []byte("v"), 0))
+		}
+
+		keys, err := store.Keys("k:")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k:1", "k:2"}, keys)
+
+		keys, err = store.Keys("k*")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k*3"}, keys, "prefixes are matched literally")
+	})
+
+	t.Run("Prefixed", func(t *testing.T) {
+		issuer := Prefixed(store, "issuer:")
+		holder := Prefixed(store, "holder:")
+		require.NoError(t, issuer.Set("cred", []byte("i"), 0))
+		require.NoError(t, holder.Set("cred", []byte("h"), 0))
+
+		value, err := issuer.Get("cred")
+		require.NoError(t, err)
+		assert.Equal(t, "i", string(value))
+
+		keys, err := holder.Keys("")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cred"}, keys)
+
+		value, err = store.Get("holder:cred")
+		require.NoError(t, err)
+		assert.Equal(t, "h", string(value))
+	})
+
+	t.Run("Ping", func(t *testing.T) {
+		assert.NoError(t, store.Ping(context.Background()))
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	mock := clock.NewMock(time.Now())
+	testKVStore(t, NewMemoryStoreWithClock(mock), mock.Advance)
+
+	t.Run("Values Are Copied", func(t *testing.T) {
+		store := NewMemoryStore()
+		value := []byte("abc")
+		require.NoError(t, store.Set("k", value, 0))
+		value[0] = 'x'
+
+		got, err := store.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", string(got))
+	})
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// redisQueuePoll is how long a RedisQueue waits for an item per BRPOP before checking whether its
+// context is done
+const redisQueuePoll = time.Second
+
+// Queue hands items, such as job IDs, from producers to workers in the order they were pushed.
+// Each item is popped by one worker.
+type Queue interface {
+	// Push appends item to the queue
+	Push(ctx context.Context, item string) error
+	// Pop removes and returns the oldest item, waiting until there is one or ctx is done
+	Pop(ctx context.Context) (string, error)
+}
+
+// MemoryQueue is a Queue kept in a buffered channel, serving a single process. Push waits while
+// the queue is full.
+type MemoryQueue struct {
+	items chan string
+}
+
+// NewMemoryQueue creates a queue holding up to size items
+func NewMemoryQueue(size int) *MemoryQueue {
+	return &MemoryQueue{items: make(chan string, size)}
+}
+
+// Push appends item to the queue, waiting for room until ctx is done
+func (q *MemoryQueue) Push(ctx context.Context, item string) error {
+	select {
+	case q.items <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pop removes and returns the oldest item
+func (q *MemoryQueue) Pop(ctx context.Context) (string, error) {
+	select {
+	case item := <-q.items:
+		return item, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// RedisQueue is a Queue kept in a Redis list, so the workers of several server instances share
+// it and queued items survive restarts
+type RedisQueue struct {
+	store *RedisStore
+	key   string
+}
+
+// NewRedisQueue creates a queue kept in the list under key
+func NewRedisQueue(store *RedisStore, key string) *RedisQueue {
+	return &RedisQueue{store: store, key: key}
+}
+
+// Push appends item to the list
+func (q *RedisQueue) Push(ctx context.Context, item string) error {
+	_, err := q.store.do(ctx, "LPUSH", q.key, item)
+	return err
+}
+
+// Pop removes and returns the oldest item of the list. It waits with BRPOP, a poll interval at a
+// time, so a cancelled context is noticed within the interval.
+func (q *RedisQueue) Pop(ctx context.Context) (string, error) {
+	wait := strconv.Itoa(int(redisQueuePoll / time.Second))
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		// A deadline on ctx cuts the wait short; the interrupted connection is discarded
+		reply, err := q.store.doTimeout(ctx, q.store.config.IOTimeout+redisQueuePoll, "BRPOP", q.key, wait)
+		if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+			return "", ctxErr
+		}
+		if err != nil {
+			return "", err
+		}
+		if reply == nil {
+			continue
+		}
+		pair, ok := reply.([]interface{})
+		if !ok || len(pair) != 2 {
+			return "", fmt.Errorf("redis: unexpected reply to BRPOP")
+		}
+		item, ok := pair[1].([]byte)
+		if !ok {
+			return "", fmt.Errorf("redis: unexpected BRPOP item %T", pair[1])
+		}
+		return string(item), nil
+	}
+}
+
+var (
+	_ Queue = (*MemoryQueue)(nil)
+	_ Queue = (*RedisQueue)(nil)
+)
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// testQueue runs the behaviour every Queue must have
+func testQueue(t *testing.T, queue Queue) {
+	ctx := context.Background()
+
+	t.Run("First In First Out", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			require.NoError(t, queue.Push(ctx, fmt.Sprintf("job-%d", i)))
+		}
+		for i := 0; i < 3; i++ {
+			item, err := queue.Pop(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("job-%d", i), item)
+		}
+	})
+
+	t.Run("Pop Waits For An Item", func(t *testing.T) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			queue.Push(ctx, "late")
+		}()
+		item, err := queue.Pop(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "late", item)
+	})
+
+	t.Run("Pop Stops With Its Context", func(t *testing.T) {
+		cancelled, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := queue.Pop(cancelled)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestMemoryQueue(t *testing.T) {
+	testQueue(t, NewMemoryQueue(8))
+
+	t.Run("Push Waits While Full", func(t *testing.T) {
+		queue := NewMemoryQueue(1)
+		require.NoError(t, queue.Push(context.Background(), "first"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, queue.Push(ctx, "second"), context.DeadlineExceeded)
+	})
+}
+
+func TestRedisQueue(t *testing.T) {
+	server := newFakeRedis(t, clock.NewMock(time.Now()), "")
+	store, err := NewRedisStore(RedisConfig{Addr: server.listener.Addr().String()})
+	require.NoError(t, err)
+	defer store.Close()
+
+	testQueue(t, NewRedisQueue(store, "queue"))
+}
@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for a RedisConfig left zero
+const (
+	DefaultRedisDialTimeout = 5 * time.Second
+	DefaultRedisIOTimeout   = 3 * time.Second
+	DefaultRedisPoolSize    = 8
+	redisScanCount          = 500
+)
+
+// RedisConfig locates a Redis server
+type RedisConfig struct {
+	Addr        string        // host:port
+	Password    string        // sent with AUTH when set
+	DB          int           // selected after connecting
+	DialTimeout time.Duration // zero uses DefaultRedisDialTimeout
+	IOTimeout   time.Duration // deadline per command; zero uses DefaultRedisIOTimeout
+	PoolSize    int           // idle connections kept; zero uses DefaultRedisPoolSize
+}
+
+// RedisStore is a KVStore kept in Redis, so several server instances can share it. TTLs are enforced
+// by Redis itself. It speaks RESP2 over a small pool of connections.
+type RedisStore struct {
+	config RedisConfig
+
+	// idle holds connections ready for reuse
+	idle chan *redisConn
+}
+
+// redisConn is a single connection to the server
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// redisError is an error reply from the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedisStore creates a store for the server in config and checks it can be reached
+func NewRedisStore(config RedisConfig) (*RedisStore, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("redis address is required")
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = DefaultRedisDialTimeout
+	}
+	if config.IOTimeout <= 0 {
+		config.IOTimeout = DefaultRedisIOTimeout
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = DefaultRedisPoolSize
+	}
+
+	store := &RedisStore{
+		config: config,
+		idle:   make(chan *redisConn, config.PoolSize),
+	}
+	if err := store.Ping(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", config.Addr, err)
+	}
+	return store, nil
+}
+
+// Get returns the value stored under key, or ErrNotFound
+func (s *RedisStore) Get(key string) ([]byte, error) {
+	reply, err := s.do(context.Background(), "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected reply %T to GET", reply)
+	}
+	return value, nil
+}
+
+// Set stores value under key, replacing any existing value
+func (s *RedisStore) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := s.do(context.Background(), setArgs(key, value, ttl)...)
+	return err
+}
+
+// SetNX stores value under key only if the key is absent
+func (s *RedisStore) SetNX(key string, value []byte, ttl time.Duration) (bool, error) {
+	reply, err := s.do(context.Background(), append(setArgs(key, value, ttl), "NX")...)
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Delete removes key
+func (s *RedisStore) Delete(key string) error {
+	_, err := s.do(context.Background(), "DEL", key)
+	return err
+}
+
+// Keys returns the keys starting with prefix, sorted. It walks the keyspace with SCAN, so it does not
+// block the server the way KEYS would.
+func (s *RedisStore) Keys(prefix string) ([]string, error) {
+	seen := make(map[string]bool)
+	cursor := "0"
+	for {
+		reply, err := s.do(context.Background(), "SCAN", cursor, "MATCH", escapeGlob(prefix)+"*", "COUNT", strconv.Itoa(redisScanCount))
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("redis: unexpected reply to SCAN")
+		}
+		next, ok := page[0].([]byte)
+		if !ok {
+			return nil, fmt.Errorf("redis: unexpected SCAN cursor")
+		}
+		batch, _ := page[1].([]interface{})
+		for _, item := range batch {
+			if key, ok := item.([]byte); ok {
+				seen[string(key)] = true
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" {
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	return sortedKeys(keys), nil
+}
+
+// Ping reports whether the server answers
+func (s *RedisStore) Ping(ctx context.Context) error {
+	_, err := s.do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case c := <-s.idle:
+			c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do runs one command on a pooled connection. A connection that fails mid-command is discarded rather
+// than returned to the pool, since its stream may be out of step.
+func (s *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	return s.doTimeout(ctx, s.config.IOTimeout, args...)
+}
+
+// doTimeout runs one command with its own deadline, for blocking commands such as BRPOP
+func (s *RedisStore) doTimeout(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	c, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.do(ctx, timeout, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.conn.Close()
+		return nil, err
+	}
+	s.put(c)
+	return reply, err
+}
+
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: s.config.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if s.config.Password != "" {
+		if _, err := c.do(ctx, s.config.IOTimeout, "AUTH", s.config.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if s.config.DB != 0 {
+		if _, err := c.do(ctx, s.config.IOTimeout, "SELECT", strconv.Itoa(s.config.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisStore) put(c *redisConn) {
+	select {
+	case s.idle <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+// do writes a command and reads its reply
+func (c *redisConn) do(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// readReply reads one RESP2 reply. Bulk strings come back as []byte, nil bulk strings and arrays as
+// nil, integers as int64, simple strings as string and arrays as []interface{}.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
+
+func setArgs(key string, value []byte, ttl time.Duration) []string {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		ms := ttl.Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	return args
+}
+
+// escapeGlob escapes the characters SCAN MATCH treats as patterns
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var (
+	_ KVStore = (*MemoryStore)(nil)
+	_ KVStore = (*RedisStore)(nil)
+)
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// fakeRedis answers the subset of RESP2 the store uses, keeping data in a MemoryStore
+type fakeRedis struct {
+	listener net.Listener
+	data     *MemoryStore
+	password string
+	conns    atomic.Int32
+
+	// lists holds the lists LPUSH and BRPOP work on
+	listsMu sync.Mutex
+	lists   map[string][]string
+}
+
+func newFakeRedis(t *testing.T, c clock.Clock, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeRedis{listener: listener, data: NewMemoryStoreWithClock(c), password: password, lists: make(map[string][]string)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			f.conns.Add(1)
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		if !authed && cmd != "AUTH" {
+			io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		io.WriteString(conn, f.execute(cmd, args[1:], &authed))
+	}
+}
+
+func (f *fakeRedis) execute(cmd string, args []string, authed *bool) string {
+	switch cmd {
+	case "AUTH":
+		if args[0] != f.password {
+			return "-WRONGPASS invalid password\r\n"
+		}
+		*authed = true
+		return "+OK\r\n"
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		value, err := f.data.Get(args[0])
+		if err != nil {
+			return "$-1\r\n"
+		}
+		return bulk(string(value))
+	case "SET":
+		var ttl time.Duration
+		nx := false
+		for i := 2; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "PX":
+				ms, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(ms) * time.Millisecond
+				i++
+			case "NX":
+				nx = true
+			}
+		}
+		if nx {
+			stored, _ := f.data.SetNX(args[0], []byte(args[1]), ttl)
+			if !stored {
+				return "$-1\r\n"
+			}
+			return "+OK\r\n"
+		}
+		f.data.Set(args[0], []byte(args[1]), ttl)
+		return "+OK\r\n"
+	case "DEL":
+		f.data.Delete(args[0])
+		return ":1\r\n"
+	case "SCAN":
+		// Unescape the MATCH pattern back into its literal prefix and return everything in one page
+		pattern := strings.TrimSuffix(args[2], "*")
+		var prefix strings.Builder
+		for i := 0; i < len(pattern); i++ {
+			if pattern[i] == '\\' && i+1 < len(pattern) {
+				i++
+			}
+			prefix.WriteByte(pattern[i])
+		}
+		keys, _ := f.data.Keys(prefix.String())
+		out := fmt.Sprintf("*2\r\n%s*%d\r\n", bulk("0"), len(keys))
+		for _, key := range keys {
+			out += bulk(key)
+		}
+		return out
+	case "LPUSH":
+		f.listsMu.Lock()
+		defer f.listsMu.Unlock()
+		f.lists[args[0]] = append(args[1:2:2], f.lists[args[0]]...)
+		return fmt.Sprintf(":%d\r\n", len(f.lists[args[0]]))
+	case "BRPOP":
+		seconds, _ := strconv.Atoi(args[1])
+		deadline := time.Now().Add(time.Duration(seconds) * time.Second)
+		for {
+			f.listsMu.Lock()
+			if list := f.lists[args[0]]; len(list) > 0 {
+				item := list[len(list)-1]
+				f.lists[args[0]] = list[:len(list)-1]
+				f.listsMu.Unlock()
+				return "*2\r\n" + bulk(args[0]) + bulk(item)
+			}
+			f.listsMu.Unlock()
+			if time.Now().After(deadline) {
+				return "*-1\r\n"
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	default:
+		return "-ERR unknown command '" + cmd + "'\r\n"
+	}
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func TestRedisStore(t *testing.T) {
+	mock := clock.NewMock(time.Now())
+	server := newFakeRedis(t, mock, "secret")
+
+	store, err := NewRedisStore(RedisConfig{Addr: server.listener.Addr().String(), Password: "secret", DB: 2})
+	require.NoError(t, err)
+	defer store.Close()
+
+	testKVStore(t, store, mock.Advance)
+
+	t.Run("Binary Values", func(t *testing.T) {
+		value := []byte("line\r\nbreak\x00")
+		require.NoError(t, store.Set("bin", value, 0))
+		got, err := store.Get("bin")
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+	})
+
+	t.Run("Reuses Connections", func(t *testing.T) {
+		before := server.conns.Load()
+		for i := 0; i < 10; i++ {
+			require.NoError(t, store.Ping(context.Background()))
+		}
+		assert.Equal(t, before, server.conns.Load())
+	})
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		_, err := NewRedisStore(RedisConfig{Addr: server.listener.Addr().String(), Password: "wrong"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "WRONGPASS")
+	})
+
+	t.Run("Unreachable Server", func(t *testing.T) {
+		_, err := NewRedisStore(RedisConfig{Addr: "127.0.0.1:1", DialTimeout: time.Second})
+		assert.Error(t, err)
+	})
+}
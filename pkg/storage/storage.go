@@ -0,0 +1,104 @@
+// Package storage is the key-value abstraction the credential, presentation, DID, session and replay
+// stores are built on. The in-memory store serves a single process; the Redis store lets several
+// server instances share state behind a load balancer.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Get for a key that is absent or expired
+var ErrNotFound = errors.New("key not found")
+
+// KVStore stores opaque values under string keys. A positive TTL makes a key expire; zero keeps it
+// until it is deleted.
+type KVStore interface {
+	// Get returns the value stored under key, or ErrNotFound
+	Get(key string) ([]byte, error)
+	// Set stores value under key, replacing any existing value
+	Set(key string, value []byte, ttl time.Duration) error
+	// SetNX stores value under key only if the key is absent, reporting whether it was stored
+	SetNX(key string, value []byte, ttl time.Duration) (bool, error)
+	// Delete removes key; deleting an absent key is not an error
+	Delete(key string) error
+	// Keys returns the keys starting with prefix, sorted
+	Keys(prefix string) ([]string, error)
+	// Ping reports whether the store is reachable
+	Ping(ctx context.Context) error
+}
+
+// GetJSON decodes the JSON value stored under key into v. It returns ErrNotFound when key is absent.
+func GetJSON(store KVStore, key string, v interface{}) error {
+	data, err := store.Get(key)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetJSON stores v under key as JSON
+func SetJSON(store KVStore, key string, v interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	return store.Set(key, data, ttl)
+}
+
+// prefixed namespaces every key of a store
+type prefixed struct {
+	store  KVStore
+	prefix string
+}
+
+// Prefixed returns a view of store whose keys are stored under prefix, so several stores can share
+// one backend without colliding. Keys returned by the view do not include the prefix.
+func Prefixed(store KVStore, prefix string) KVStore {
+	return &prefixed{store: store, prefix: prefix}
+}
+
+func (p *prefixed) Get(key string) ([]byte, error) {
+	return p.store.Get(p.prefix + key)
+}
+
+func (p *prefixed) Set(key string, value []byte, ttl time.Duration) error {
+	return p.store.Set(p.prefix+key, value, ttl)
+}
+
+func (p *prefixed) SetNX(key string, value []byte, ttl time.Duration) (bool, error) {
+	return p.store.SetNX(p.prefix+key, value, ttl)
+}
+
+func (p *prefixed) Delete(key string) error {
+	return p.store.Delete(p.prefix + key)
+}
+
+func (p *prefixed) Keys(prefix string) ([]string, error) {
+	keys, err := p.store.Keys(p.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, p.prefix)
+	}
+	return keys, nil
+}
+
+func (p *prefixed) Ping(ctx context.Context) error {
+	return p.store.Ping(ctx)
+}
+
+// sortedKeys sorts keys in place and returns them
+func sortedKeys(keys []string) []string {
+	sort.Strings(keys)
+	return keys
+}
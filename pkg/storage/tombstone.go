@@ -0,0 +1,26 @@
+package storage
+
+import "time"
+
+// Tombstone records who deleted a record, when and why. Repositories keep the record with its
+// tombstone instead of erasing it, so audits can still see it and reads report it as deleted
+// rather than as never having existed.
+type Tombstone struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	DeletedBy string    `json:"deletedBy,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Stamped returns the tombstone dated now, unless it is already dated
+func (t Tombstone) Stamped(now time.Time) Tombstone {
+	if t.DeletedAt.IsZero() {
+		t.DeletedAt = now
+	}
+	return t
+}
+
+// Purged reports whether a purge policy keeping deleted records for retention has purged the
+// record at now. A zero retention keeps deleted records forever.
+func (t Tombstone) Purged(retention time.Duration, now time.Time) bool {
+	return retention > 0 && !now.Before(t.DeletedAt.Add(retention))
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header carrying the parent span
+const TraceparentHeader = "traceparent"
+
+// Inject writes the current span context in ctx to the traceparent header
+func Inject(ctx context.Context, header http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, FormatTraceparent(sc))
+}
+
+// Extract returns ctx with the remote parent from the traceparent header, if present and valid
+func Extract(ctx context.Context, header http.Header) context.Context {
+	sc, err := ParseTraceparent(header.Get(TraceparentHeader))
+	if err != nil {
+		return ctx
+	}
+	return ContextWithSpanContext(ctx, sc)
+}
+
+// FormatTraceparent encodes a span context as a version 00 traceparent value
+func FormatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent decodes a traceparent value into a remote span context
+func ParseTraceparent(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+
+	version, traceHex, spanHex, flagsHex := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("unsupported traceparent version %q", version)
+	}
+
+	var sc SpanContext
+	if len(traceHex) != 32 || len(spanHex) != 16 || len(flagsHex) != 2 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", value)
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceHex)); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace ID: %w", err)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(spanHex)); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid span ID: %w", err)
+	}
+	flags, err := hex.DecodeString(flagsHex)
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace flags: %w", err)
+	}
+	if traceHex != strings.ToLower(traceHex) || spanHex != strings.ToLower(spanHex) {
+		return SpanContext{}, fmt.Errorf("traceparent IDs must be lowercase")
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("traceparent IDs must not be zero")
+	}
+
+	sc.Sampled = flags[0]&0x01 == 0x01
+	sc.Remote = true
+	return sc, nil
+}
@@ -0,0 +1,249 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for batching finished spans
+const (
+	DefaultBatchSize     = 512
+	DefaultFlushInterval = 5 * time.Second
+	// maxQueueSize bounds memory when the exporter falls behind; further spans are dropped
+	maxQueueSize = 8192
+)
+
+// ProviderConfig configures a tracer provider
+type ProviderConfig struct {
+	ServiceName string
+	Exporter    Exporter
+	// SampleRatio is the fraction of new traces recorded, between 0 and 1.
+	// Child spans follow their parent's sampling decision.
+	SampleRatio   float64
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// Provider creates spans and exports them in batches
+type Provider struct {
+	serviceName   string
+	exporter      Exporter
+	sampleRatio   float64
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	queue   []SpanData
+	dropped int
+
+	flushCh chan struct{}
+	done    chan struct{}
+	stopped sync.WaitGroup
+}
+
+var globalProvider atomic.Pointer[Provider]
+
+// NewProvider creates a provider and starts its background exporter
+func NewProvider(config ProviderConfig) (*Provider, error) {
+	if config.Exporter == nil {
+		return nil, fmt.Errorf("exporter is required")
+	}
+
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("sample ratio must be between 0 and 1")
+	}
+
+	if config.ServiceName == "" {
+		config.ServiceName = "unknown_service"
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+
+	p := &Provider{
+		serviceName:   config.ServiceName,
+		exporter:      config.Exporter,
+		sampleRatio:   config.SampleRatio,
+		batchSize:     config.BatchSize,
+		flushInterval: config.FlushInterval,
+		flushCh:       make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+
+	p.stopped.Add(1)
+	go p.run()
+
+	return p, nil
+}
+
+// SetProvider installs the provider used by Start; nil disables tracing
+func SetProvider(p *Provider) {
+	globalProvider.Store(p)
+}
+
+// GetProvider returns the global provider, or nil when tracing is disabled
+func GetProvider() *Provider {
+	return globalProvider.Load()
+}
+
+// ServiceName returns the service name reported with exported spans
+func (p *Provider) ServiceName() string {
+	return p.serviceName
+}
+
+// Start begins a span as a child of the span or remote parent in ctx
+func (p *Provider) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+
+	sc := SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = p.sample(sc.TraceID)
+	}
+
+	span := &Span{
+		provider: p,
+		data: SpanData{
+			Name:         name,
+			Kind:         kind,
+			SpanContext:  sc,
+			ParentSpanID: parent.SpanID,
+			StartTime:    time.Now(),
+			Attributes:   append([]Attribute(nil), attrs...),
+		},
+	}
+
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+// ForceFlush exports all queued spans now
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	for {
+		batch := p.take()
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := p.exporter.Export(ctx, batch); err != nil {
+			return fmt.Errorf("failed to export spans: %w", err)
+		}
+	}
+}
+
+// Shutdown stops the background exporter, flushes queued spans and shuts the exporter down
+func (p *Provider) Shutdown(ctx context.Context) error {
+	select {
+	case <-p.done:
+		return nil
+	default:
+		close(p.done)
+	}
+	p.stopped.Wait()
+
+	if err := p.ForceFlush(ctx); err != nil {
+		return err
+	}
+	return p.exporter.Shutdown(ctx)
+}
+
+// sample decides deterministically from the trace ID so every service agrees
+func (p *Provider) sample(traceID TraceID) bool {
+	switch {
+	case p.sampleRatio >= 1:
+		return true
+	case p.sampleRatio <= 0:
+		return false
+	}
+	bound := uint64(p.sampleRatio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
+
+func (p *Provider) enqueue(span SpanData) {
+	p.mu.Lock()
+	if len(p.queue) >= maxQueueSize {
+		p.dropped++
+		p.mu.Unlock()
+		return
+	}
+	p.queue = append(p.queue, span)
+	full := len(p.queue) >= p.batchSize
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// take removes up to one batch of spans from the queue
+func (p *Provider) take() []SpanData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.queue)
+	if n > p.batchSize {
+		n = p.batchSize
+	}
+	batch := p.queue[:n:n]
+	p.queue = p.queue[n:]
+	return batch
+}
+
+func (p *Provider) run() {
+	defer p.stopped.Done()
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		case <-p.flushCh:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.flushInterval)
+		if err := p.ForceFlush(ctx); err != nil {
+			log.Printf("tracing: %v", err)
+		}
+		cancel()
+
+		p.mu.Lock()
+		dropped := p.dropped
+		p.dropped = 0
+		p.mu.Unlock()
+		if dropped > 0 {
+			log.Printf("tracing: dropped %d spans because the export queue was full", dropped)
+		}
+	}
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
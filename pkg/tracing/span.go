@@ -0,0 +1,146 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type spanContextKey struct{}
+
+// Span is an operation being traced. A nil *Span is valid and records nothing,
+// so instrumented code works unchanged when tracing is disabled.
+type Span struct {
+	provider *Provider
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// Start begins a span as a child of the span in ctx using the global provider
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return StartKind(ctx, name, SpanKindInternal, attrs...)
+}
+
+// StartKind begins a span of the given kind using the global provider
+func StartKind(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	provider := GetProvider()
+	if provider == nil {
+		return ctx, nil
+	}
+	return provider.Start(ctx, name, kind, attrs...)
+}
+
+// SpanContextFromContext returns the span context of the current span or remote parent in ctx
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// ContextWithSpanContext returns a context carrying the span context as the current parent
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContext returns the span's propagation context
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// IsRecording reports whether the span will be exported
+func (s *Span) IsRecording() bool {
+	return s != nil && s.data.SpanContext.Sampled
+}
+
+// SetName replaces the span name, e.g. once the matched route is known
+func (s *Span) SetName(name string) {
+	if !s.IsRecording() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Name = name
+}
+
+// SetAttributes adds or replaces attributes on the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, attr := range attrs {
+		replaced := false
+		for i := range s.data.Attributes {
+			if s.data.Attributes[i].Key == attr.Key {
+				s.data.Attributes[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.data.Attributes = append(s.data.Attributes, attr)
+		}
+	}
+}
+
+// SetStatus sets the span status; an error status is kept over later OK statuses
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if !s.IsRecording() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.Status == StatusError && code != StatusError {
+		return
+	}
+	s.data.Status = code
+	s.data.StatusMessage = message
+}
+
+// RecordError adds an exception event and marks the span as failed; nil errors are ignored
+func (s *Span) RecordError(err error) {
+	if err == nil || !s.IsRecording() {
+		return
+	}
+
+	s.mu.Lock()
+	s.data.Events = append(s.data.Events, Event{
+		Name:       "exception",
+		Time:       time.Now(),
+		Attributes: []Attribute{String("exception.message", err.Error())},
+	})
+	s.mu.Unlock()
+
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and queues it for export; calls after the first are ignored
+func (s *Span) End() {
+	if !s.IsRecording() {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	data.Attributes = append([]Attribute(nil), s.data.Attributes...)
+	data.Events = append([]Event(nil), s.data.Events...)
+	s.mu.Unlock()
+
+	s.provider.enqueue(data)
+}
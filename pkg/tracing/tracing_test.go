@@ -0,0 +1,174 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, ratio float64) (*Provider, *InMemoryExporter) {
+	exporter := NewInMemoryExporter()
+	provider, err := NewProvider(ProviderConfig{ServiceName: "test", Exporter: exporter, SampleRatio: ratio})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return provider, exporter
+}
+
+func TestSpanParenting(t *testing.T) {
+	provider, exporter := newTestProvider(t, 1)
+
+	ctx, root := provider.Start(context.Background(), "root", SpanKindServer)
+	_, child := provider.Start(ctx, "child", SpanKindInternal, Int("count", 3))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	root.End()
+	root.End() // ending twice is a no-op
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	spans := exporter.Spans()
+	require.Len(t, spans, 2)
+
+	childData, rootData := spans[0], spans[1]
+	assert.Equal(t, "child", childData.Name)
+	assert.Equal(t, rootData.SpanContext.TraceID, childData.SpanContext.TraceID)
+	assert.Equal(t, rootData.SpanContext.SpanID, childData.ParentSpanID)
+	assert.False(t, rootData.ParentSpanID.IsValid())
+	assert.Equal(t, StatusError, childData.Status)
+	require.Len(t, childData.Events, 1)
+	assert.Equal(t, "exception", childData.Events[0].Name)
+}
+
+func TestNilSpanIsNoop(t *testing.T) {
+	SetProvider(nil)
+
+	ctx, span := Start(context.Background(), "disabled")
+	assert.Nil(t, span)
+	assert.False(t, SpanContextFromContext(ctx).IsValid())
+
+	// None of these may panic
+	span.SetName("renamed")
+	span.SetAttributes(String("k", "v"))
+	span.SetStatus(StatusError, "failed")
+	span.RecordError(errors.New("boom"))
+	span.End()
+}
+
+func TestSampling(t *testing.T) {
+	provider, exporter := newTestProvider(t, 0)
+
+	ctx, span := provider.Start(context.Background(), "dropped", SpanKindInternal)
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	// A sampled remote parent overrides the ratio
+	remote := SpanContext{TraceID: TraceID{1}, SpanID: SpanID{2}, Sampled: true, Remote: true}
+	_, kept := provider.Start(ContextWithSpanContext(ctx, remote), "kept", SpanKindServer)
+	assert.True(t, kept.IsRecording())
+	kept.End()
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "kept", spans[0].Name)
+	assert.Equal(t, remote.TraceID, spans[0].SpanContext.TraceID)
+
+	_, err := NewProvider(ProviderConfig{Exporter: exporter, SampleRatio: 1.5})
+	assert.Error(t, err)
+}
+
+func TestTraceparent(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	sc, err := ParseTraceparent(value)
+	require.NoError(t, err)
+	assert.True(t, sc.Sampled)
+	assert.True(t, sc.Remote)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, value, FormatTraceparent(sc))
+
+	header := http.Header{}
+	Inject(ContextWithSpanContext(context.Background(), sc), header)
+	assert.Equal(t, value, header.Get(TraceparentHeader))
+	assert.Equal(t, sc, SpanContextFromContext(Extract(context.Background(), header)))
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		path     string
+		received map[string]interface{}
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path = r.URL.Path
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(collector.URL, "issuer-api", map[string]string{"Authorization": "secret"})
+	provider, err := NewProvider(ProviderConfig{ServiceName: "issuer-api", Exporter: exporter, SampleRatio: 1})
+	require.NoError(t, err)
+
+	_, span := provider.Start(context.Background(), "bbs.Sign", SpanKindInternal,
+		Int("bbs.messages", 4), Bool("ok", true), String("issuer", "did:example:1"))
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "/v1/traces", path)
+
+	resourceSpans := received["resourceSpans"].([]interface{})
+	require.Len(t, resourceSpans, 1)
+	resource := resourceSpans[0].(map[string]interface{})
+	serviceAttr := resource["resource"].(map[string]interface{})["attributes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "service.name", serviceAttr["key"])
+	assert.Equal(t, "issuer-api", serviceAttr["value"].(map[string]interface{})["stringValue"])
+
+	scopeSpans := resource["scopeSpans"].([]interface{})[0].(map[string]interface{})
+	exported := scopeSpans["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "bbs.Sign", exported["name"])
+	assert.Equal(t, span.SpanContext().TraceID.String(), exported["traceId"])
+	assert.Len(t, exported["spanId"], 16)
+
+	attrs := exported["attributes"].([]interface{})
+	require.Len(t, attrs, 3)
+	// OTLP/JSON encodes 64-bit integers as strings
+	assert.Equal(t, "4", attrs[0].(map[string]interface{})["value"].(map[string]interface{})["intValue"])
+	assert.Equal(t, true, attrs[1].(map[string]interface{})["value"].(map[string]interface{})["boolValue"])
+}
+
+func TestOTLPExporterErrorStatus(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPExporter(collector.URL+"/v1/traces", "test", nil)
+	err := exporter.Export(context.Background(), []SpanData{{Name: "span"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+	assert.Contains(t, err.Error(), "quota exceeded")
+}
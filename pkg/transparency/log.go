@@ -0,0 +1,165 @@
+package transparency
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Log is an append-only, in-memory Merkle log of issued credential hashes
+type Log struct {
+	mu      sync.RWMutex
+	id      string
+	entries []Entry
+	leaves  [][]byte
+	byHash  map[string]uint64
+}
+
+// NewLog creates an empty log identified by the operating issuer's DID
+func NewLog(id string) *Log {
+	return &Log{
+		id:     id,
+		byHash: make(map[string]uint64),
+	}
+}
+
+// ID returns the log identifier
+func (l *Log) ID() string {
+	return l.id
+}
+
+// Append records a credential hash issued at the given time
+func (l *Log) Append(credentialHash string, timestamp time.Time) (*Entry, error) {
+	if credentialHash == "" {
+		return nil, fmt.Errorf("credential hash is required")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, exists := l.byHash[credentialHash]; exists {
+		return nil, fmt.Errorf("credential hash already logged: %s", credentialHash)
+	}
+
+	entry := Entry{
+		Index:          uint64(len(l.entries)),
+		CredentialHash: credentialHash,
+		Timestamp:      timestamp.UTC(),
+	}
+
+	data, err := entry.LeafData()
+	if err != nil {
+		return nil, err
+	}
+
+	l.entries = append(l.entries, entry)
+	l.leaves = append(l.leaves, LeafHash(data))
+	l.byHash[credentialHash] = entry.Index
+
+	return &entry, nil
+}
+
+// Size returns the number of entries in the log
+func (l *Log) Size() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return uint64(len(l.entries))
+}
+
+// Head returns the tree head for the current size
+func (l *Log) Head() *TreeHead {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return &TreeHead{
+		LogID:     l.id,
+		TreeSize:  uint64(len(l.leaves)),
+		RootHash:  hex.EncodeToString(rootHash(l.leaves)),
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// Lookup finds the entry recorded for a credential hash
+func (l *Log) Lookup(credentialHash string) (*Entry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	index, exists := l.byHash[credentialHash]
+	if !exists {
+		return nil, fmt.Errorf("credential hash not found in log: %s", credentialHash)
+	}
+
+	entry := l.entries[index]
+	return &entry, nil
+}
+
+// InclusionProof proves the entry for a credential hash is included in the tree of the given size.
+// A size of zero means the current size.
+func (l *Log) InclusionProof(credentialHash string, treeSize uint64) (*InclusionProof, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	index, exists := l.byHash[credentialHash]
+	if !exists {
+		return nil, fmt.Errorf("credential hash not found in log: %s", credentialHash)
+	}
+
+	if treeSize == 0 {
+		treeSize = uint64(len(l.leaves))
+	}
+	if treeSize > uint64(len(l.leaves)) {
+		return nil, fmt.Errorf("tree size %d exceeds log size %d", treeSize, len(l.leaves))
+	}
+	if index >= treeSize {
+		return nil, fmt.Errorf("entry %d is not included in tree of size %d", index, treeSize)
+	}
+
+	path := auditPath(int(index), l.leaves[:treeSize])
+	encoded := make([]string, len(path))
+	for i, hash := range path {
+		encoded[i] = hex.EncodeToString(hash)
+	}
+
+	return &InclusionProof{
+		LogID:     l.id,
+		Entry:     l.entries[index],
+		TreeSize:  treeSize,
+		AuditPath: encoded,
+	}, nil
+}
+
+// VerifyInclusionProof checks a proof against a tree head of the same log and size
+func VerifyInclusionProof(proof *InclusionProof, head *TreeHead) error {
+	if proof == nil || head == nil {
+		return fmt.Errorf("proof and tree head are required")
+	}
+
+	if proof.LogID != head.LogID {
+		return fmt.Errorf("proof is for log %s, tree head is for log %s", proof.LogID, head.LogID)
+	}
+
+	if proof.TreeSize != head.TreeSize {
+		return fmt.Errorf("proof is for tree size %d, tree head has size %d", proof.TreeSize, head.TreeSize)
+	}
+
+	root, err := hex.DecodeString(head.RootHash)
+	if err != nil {
+		return fmt.Errorf("invalid root hash: %w", err)
+	}
+
+	path := make([][]byte, len(proof.AuditPath))
+	for i, encoded := range proof.AuditPath {
+		path[i], err = hex.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("invalid audit path hash: %w", err)
+		}
+	}
+
+	data, err := proof.Entry.LeafData()
+	if err != nil {
+		return err
+	}
+
+	return VerifyInclusion(LeafHash(data), proof.Entry.Index, proof.TreeSize, path, root)
+}
@@ -0,0 +1,90 @@
+package transparency
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootHash(t *testing.T) {
+	// The empty tree hashes to SHA-256 of the empty string
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hex.EncodeToString(rootHash(nil)))
+
+	a, b, c := LeafHash([]byte("a")), LeafHash([]byte("b")), LeafHash([]byte("c"))
+	assert.Equal(t, a, rootHash([][]byte{a}))
+	assert.Equal(t, nodeHash(nodeHash(a, b), c), rootHash([][]byte{a, b, c}))
+
+	// Leaves and nodes are domain separated
+	assert.NotEqual(t, LeafHash(append(a, b...)), nodeHash(a, b))
+}
+
+func TestInclusionProofs(t *testing.T) {
+	log := NewLog("did:example:issuer")
+	issuedAt := time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 13; i++ {
+		entry, err := log.Append(fmt.Sprintf("hash-%d", i), issuedAt.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), entry.Index)
+	}
+	require.Equal(t, uint64(13), log.Size())
+
+	// Every entry verifies against every tree size that includes it
+	for size := uint64(1); size <= log.Size(); size++ {
+		leaves := log.leaves[:size]
+		head := &TreeHead{LogID: log.ID(), TreeSize: size, RootHash: hex.EncodeToString(rootHash(leaves))}
+
+		for index := uint64(0); index < size; index++ {
+			proof, err := log.InclusionProof(fmt.Sprintf("hash-%d", index), size)
+			require.NoError(t, err)
+			assert.NoError(t, VerifyInclusionProof(proof, head), "index %d size %d", index, size)
+		}
+	}
+
+	proof, err := log.InclusionProof("hash-5", 0)
+	require.NoError(t, err)
+	head := log.Head()
+	require.NoError(t, VerifyInclusionProof(proof, head))
+
+	t.Run("Tampered Entry", func(t *testing.T) {
+		tampered := *proof
+		tampered.Entry.Timestamp = tampered.Entry.Timestamp.Add(time.Hour)
+		assert.Error(t, VerifyInclusionProof(&tampered, head))
+	})
+
+	t.Run("Wrong Index", func(t *testing.T) {
+		tampered := *proof
+		tampered.Entry.Index = 6
+		assert.Error(t, VerifyInclusionProof(&tampered, head))
+	})
+
+	t.Run("Truncated Path", func(t *testing.T) {
+		tampered := *proof
+		tampered.AuditPath = tampered.AuditPath[1:]
+		assert.Error(t, VerifyInclusionProof(&tampered, head))
+	})
+
+	t.Run("Other Log", func(t *testing.T) {
+		other := *head
+		other.LogID = "did:example:other"
+		assert.Error(t, VerifyInclusionProof(proof, &other))
+	})
+
+	t.Run("Invalid Requests", func(t *testing.T) {
+		_, err := log.Append("hash-0", issuedAt)
+		assert.Error(t, err)
+
+		_, err = log.InclusionProof("unknown", 0)
+		assert.Error(t, err)
+
+		_, err = log.InclusionProof("hash-5", 5)
+		assert.Error(t, err)
+
+		_, err = log.InclusionProof("hash-5", 14)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,103 @@
+package transparency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+)
+
+// Hashing follows RFC 6962: leaves and interior nodes use distinct prefixes
+// so a leaf can never be passed off as a node
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// LeafHash hashes leaf data into the tree
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// rootHash computes the Merkle tree hash of the given leaf hashes
+func rootHash(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		empty := sha256.Sum256(nil)
+		return empty[:]
+	case 1:
+		return leaves[0]
+	}
+
+	k := splitPoint(len(leaves))
+	return nodeHash(rootHash(leaves[:k]), rootHash(leaves[k:]))
+}
+
+// auditPath returns the sibling hashes needed to rebuild the root from leaf m
+func auditPath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+
+	k := splitPoint(len(leaves))
+	if m < k {
+		return append(auditPath(m, leaves[:k]), rootHash(leaves[k:]))
+	}
+	return append(auditPath(m-k, leaves[k:]), rootHash(leaves[:k]))
+}
+
+// splitPoint returns the largest power of two smaller than n
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// VerifyInclusion checks that path proves leafHash is at index in the tree of the given size and root
+func VerifyInclusion(leafHash []byte, index, size uint64, path [][]byte, root []byte) error {
+	if index >= size {
+		return fmt.Errorf("leaf index %d is outside tree of size %d", index, size)
+	}
+
+	fn, sn := index, size-1
+	result := leafHash
+	for _, sibling := range path {
+		if sn == 0 {
+			return fmt.Errorf("audit path is too long")
+		}
+
+		if fn&1 == 1 || fn == sn {
+			result = nodeHash(sibling, result)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			result = nodeHash(result, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 {
+		return fmt.Errorf("audit path is too short")
+	}
+
+	if !bytes.Equal(result, root) {
+		return fmt.Errorf("root hash mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package transparency
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Entry is a single credential issuance recorded in a log
+type Entry struct {
+	Index          uint64    `json:"index"`
+	CredentialHash string    `json:"credentialHash"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// TreeHead commits to the contents of a log at a given size
+type TreeHead struct {
+	LogID     string    `json:"logId"`
+	TreeSize  uint64    `json:"treeSize"`
+	RootHash  string    `json:"rootHash"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SignedTreeHead is a tree head signed by the log operator's DID key
+type SignedTreeHead struct {
+	TreeHead
+	KeyID     string `json:"keyId"`
+	Signature string `json:"signature"`
+}
+
+// InclusionProof proves that an entry is part of the tree of the given size
+type InclusionProof struct {
+	LogID     string   `json:"logId"`
+	Entry     Entry    `json:"entry"`
+	TreeSize  uint64   `json:"treeSize"`
+	AuditPath []string `json:"auditPath"`
+}
+
+// LeafData returns the bytes hashed into the tree for an entry
+func (e *Entry) LeafData() ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal log entry: %w", err)
+	}
+	return data, nil
+}
+
+// SigningInput returns the bytes covered by a signed tree head's signature
+func (h *TreeHead) SigningInput() ([]byte, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tree head: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,114 @@
+package vc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/smt"
+)
+
+// ClaimKeyCommitment is the root of a sparse Merkle tree over the claim keys of a credential,
+// signed by the issuer. It lets the holder prove a claim absent, e.g. that a background check has
+// no criminalRecord claim, without revealing the credential's other claim keys.
+type ClaimKeyCommitment struct {
+	CredentialID string `json:"credentialId"`
+	Issuer       string `json:"issuer"`
+	Root         []byte `json:"root"`
+	Proof        *Proof `json:"proof,omitempty"`
+}
+
+// ClaimKeySalt is the random value mixed into a claim key's leaf, so the hashes revealed in an
+// absence proof cannot be matched against guessed claim names. Salts are private to the holder.
+type ClaimKeySalt struct {
+	Claim string `json:"claim"`
+	Salt  []byte `json:"salt"`
+}
+
+// AbsenceProof proves a claim is not among the keys a ClaimKeyCommitment commits to
+type AbsenceProof struct {
+	Claim string     `json:"claim"`
+	Path  *smt.Proof `json:"path"`
+}
+
+// NewClaimKeySalts draws a salt for every claim key of a credential subject except its id
+func NewClaimKeySalts(subject map[string]interface{}) ([]ClaimKeySalt, error) {
+	keys := make([]string, 0, len(subject))
+	for key := range subject {
+		if key != "id" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var salts []ClaimKeySalt
+	for _, key := range keys {
+		salt := make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate claim key salt: %w", err)
+		}
+		salts = append(salts, ClaimKeySalt{Claim: key, Salt: salt})
+	}
+	return salts, nil
+}
+
+// ClaimKeyTree builds the sparse Merkle tree of the salted claim keys
+func ClaimKeyTree(salts []ClaimKeySalt) *smt.Tree {
+	values := make(map[string][]byte, len(salts))
+	for _, salt := range salts {
+		values[salt.Claim] = salt.Salt
+	}
+	return smt.New(values)
+}
+
+// ProveAbsence proves claim absent from a credential issued with a claim key commitment
+func ProveAbsence(credential *VerifiableCredential, claim string) (*AbsenceProof, error) {
+	if credential.ClaimKeys == nil {
+		return nil, fmt.Errorf("credential %s has no claim key commitment", credential.ID)
+	}
+
+	if _, present := credential.CredentialSubject[claim]; present || claim == "id" {
+		return nil, fmt.Errorf("credential %s has claim %s", credential.ID, claim)
+	}
+
+	tree := ClaimKeyTree(credential.ClaimKeySalts)
+	if !bytes.Equal(tree.Root(), credential.ClaimKeys.Root) {
+		return nil, fmt.Errorf("credential %s: claim key salts do not match the commitment", credential.ID)
+	}
+
+	return &AbsenceProof{Claim: claim, Path: tree.Prove(claim)}, nil
+}
+
+// VerifyAbsence checks an absence proof against the committed root
+func (c *ClaimKeyCommitment) VerifyAbsence(proof *AbsenceProof) error {
+	if proof == nil {
+		return fmt.Errorf("absence proof is nil")
+	}
+	if err := smt.VerifyAbsence(c.Root, proof.Claim, proof.Path); err != nil {
+		return fmt.Errorf("claim %s: %w", proof.Claim, err)
+	}
+	return nil
+}
+
+// ClaimKeyCommitmentSigningInput returns the bytes covered by the issuer's commitment proof
+func ClaimKeyCommitmentSigningInput(commitment *ClaimKeyCommitment) ([]byte, error) {
+	if commitment == nil {
+		return nil, fmt.Errorf("claim key commitment is nil")
+	}
+
+	unsigned := *commitment
+	if commitment.Proof != nil {
+		proof := *commitment.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal claim key commitment: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,94 @@
+package vc
+
+import (
+	"fmt"
+)
+
+const (
+	// VerifierAccreditationCredentialType marks credentials accrediting a verifier, e.g. as a licensed alcohol retailer
+	VerifierAccreditationCredentialType = "VerifierAccreditationCredential"
+	// VerifierAccreditationTemplateID is the built-in template accreditations are issued from
+	VerifierAccreditationTemplateID = "verifier-accreditation"
+	// AccreditationClaim names what the verifier is accredited as, e.g. licensed-alcohol-retailer
+	AccreditationClaim = "accreditation"
+)
+
+// Accreditation is read from a VerifierAccreditationCredential: Accreditor accredits Verifier,
+// the credential's subject, as Accreditation
+type Accreditation struct {
+	Credential    *VerifiableCredential
+	Accreditor    string
+	Verifier      string
+	Accreditation string
+}
+
+// ParseAccreditation reads the accreditation a VerifierAccreditationCredential grants
+func ParseAccreditation(credential *VerifiableCredential) (*Accreditation, error) {
+	if credential == nil {
+		return nil, fmt.Errorf("accreditation is nil")
+	}
+	if !containsString(credential.Type, VerifierAccreditationCredentialType) {
+		return nil, fmt.Errorf("credential %s is not a %s", credential.ID, VerifierAccreditationCredentialType)
+	}
+
+	verifier, _ := credential.CredentialSubject["id"].(string)
+	if verifier == "" {
+		return nil, fmt.Errorf("accreditation %s names no verifier", credential.ID)
+	}
+
+	accreditation, _ := credential.CredentialSubject[AccreditationClaim].(string)
+	if accreditation == "" {
+		return nil, fmt.Errorf("accreditation %s has no %s claim", credential.ID, AccreditationClaim)
+	}
+
+	return &Accreditation{
+		Credential:    credential,
+		Accreditor:    credential.Issuer(),
+		Verifier:      verifier,
+		Accreditation: accreditation,
+	}, nil
+}
+
+// AccreditationPolicy is a holder's requirement that verifiers show an accreditation before data
+// is disclosed to them
+type AccreditationPolicy struct {
+	// TrustedAccreditors are the issuers whose accreditations the holder accepts
+	TrustedAccreditors []string `json:"trustedAccreditors"`
+	// Accreditations, when set, lists the accreditations accepted; any one is enough
+	Accreditations []string `json:"accreditations,omitempty"`
+	// Claims limits the policy to presentations revealing one of these claims, e.g. dateOfBirth;
+	// empty applies it to every presentation
+	Claims []string `json:"claims,omitempty"`
+}
+
+// Validate checks the policy names at least one accreditor
+func (p *AccreditationPolicy) Validate() error {
+	if len(p.TrustedAccreditors) == 0 {
+		return fmt.Errorf("at least one trusted accreditor is required")
+	}
+	return nil
+}
+
+// Covers reports whether the policy applies to a presentation revealing the given claims
+func (p *AccreditationPolicy) Covers(revealed []string) bool {
+	if len(p.Claims) == 0 {
+		return true
+	}
+	for _, claim := range revealed {
+		if containsString(p.Claims, claim) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports why the policy does not accept an accreditation, if it does not
+func (p *AccreditationPolicy) Check(accreditation *Accreditation) error {
+	if !containsString(p.TrustedAccreditors, accreditation.Accreditor) {
+		return fmt.Errorf("accreditor %s is not trusted", accreditation.Accreditor)
+	}
+	if len(p.Accreditations) > 0 && !containsString(p.Accreditations, accreditation.Accreditation) {
+		return fmt.Errorf("accreditation %q is not accepted", accreditation.Accreditation)
+	}
+	return nil
+}
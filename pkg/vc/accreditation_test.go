@@ -0,0 +1,64 @@
+package vc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccreditation(t *testing.T) {
+	credential := &VerifiableCredential{
+		ID:         "urn:uuid:accreditation",
+		Type:       []string{"VerifiableCredential", VerifierAccreditationCredentialType},
+		IssuerInfo: Issuer{ID: "did:example:regulator"},
+		CredentialSubject: map[string]interface{}{
+			"id":               "did:example:shop",
+			AccreditationClaim: "licensed-alcohol-retailer",
+		},
+	}
+
+	t.Run("Parse", func(t *testing.T) {
+		accreditation, err := ParseAccreditation(credential)
+		require.NoError(t, err)
+		assert.Equal(t, "did:example:regulator", accreditation.Accreditor)
+		assert.Equal(t, "did:example:shop", accreditation.Verifier)
+		assert.Equal(t, "licensed-alcohol-retailer", accreditation.Accreditation)
+
+		other := *credential
+		other.Type = []string{"VerifiableCredential"}
+		_, err = ParseAccreditation(&other)
+		assert.Error(t, err)
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		policy := &AccreditationPolicy{
+			TrustedAccreditors: []string{"did:example:regulator"},
+			Accreditations:     []string{"licensed-alcohol-retailer"},
+			Claims:             []string{"dateOfBirth"},
+		}
+		require.NoError(t, policy.Validate())
+		assert.Error(t, (&AccreditationPolicy{}).Validate())
+
+		assert.True(t, policy.Covers([]string{"firstName", "dateOfBirth"}))
+		assert.False(t, policy.Covers([]string{"firstName"}))
+		assert.True(t, (&AccreditationPolicy{}).Covers(nil), "a policy without claims covers every presentation")
+
+		accreditation, err := ParseAccreditation(credential)
+		require.NoError(t, err)
+		assert.NoError(t, policy.Check(accreditation))
+
+		accreditation.Accreditation = "pharmacy"
+		assert.Error(t, policy.Check(accreditation))
+		accreditation.Accreditor = "did:example:other"
+		assert.Error(t, policy.Check(accreditation))
+	})
+
+	t.Run("Request Metadata Names Accredited Verifier", func(t *testing.T) {
+		metadata := RequestMetadata{Verifier: &VerifierIdentity{DID: "did:example:shop"}, Accreditation: credential}
+		assert.NoError(t, metadata.Validate())
+
+		metadata.Verifier.DID = "did:example:another-shop"
+		assert.Error(t, metadata.Validate())
+	})
+}
@@ -0,0 +1,204 @@
+package vc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+// BBSProofSuite is the proof type of a credential derived from a BBS+ signature
+const BBSProofSuite = "BbsBlsSignatureProof2020"
+
+// ExportFormat selects the document layout credentials and presentations are written in
+type ExportFormat string
+
+const (
+	// ExportFormatNative is this package's layout, which verifiers here need
+	ExportFormatNative ExportFormat = ""
+	// ExportFormatAries is the field layout of Aries and Mattr BBS+ documents
+	ExportFormatAries ExportFormat = "aries"
+)
+
+// Validate rejects unknown formats
+func (f ExportFormat) Validate() error {
+	switch f {
+	case ExportFormatNative, ExportFormatAries:
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q", f)
+	}
+}
+
+// AriesCredential writes a signed credential in the layout of an Aries BbsBlsSignature2020
+// document. The holder's salts, manifest and commitment openings are left out, so the export
+// cannot be derived from; requiredRevealStatements lists the confirmation claim's message index
+// when the credential is bound to a key.
+func AriesCredential(credential *VerifiableCredential) (map[string]interface{}, error) {
+	if credential == nil || credential.Proof == nil || credential.Proof.ProofValue == "" {
+		return nil, fmt.Errorf("credential has no proof value")
+	}
+	if credential.Proof.Type != BBSSignatureSuite {
+		return nil, fmt.Errorf("unsupported proof type %q: expected %s", credential.Proof.Type, BBSSignatureSuite)
+	}
+
+	document := map[string]interface{}{
+		"@context": withBBSContext(credential.Context),
+		"id":       credential.ID,
+		"type":     credential.Type,
+		"issuer":   credential.IssuerInfo.Value(),
+	}
+	if credential.ValidFrom != nil || credential.ValidUntil != nil {
+		if credential.ValidFrom != nil {
+			document["validFrom"] = ariesTime(*credential.ValidFrom)
+		}
+		if credential.ValidUntil != nil {
+			document["validUntil"] = ariesTime(*credential.ValidUntil)
+		}
+	} else {
+		document["issuanceDate"] = ariesTime(credential.IssuanceDate)
+		if credential.ExpirationDate != nil {
+			document["expirationDate"] = ariesTime(*credential.ExpirationDate)
+		}
+	}
+	document["credentialSubject"] = credential.CredentialSubject
+	if len(credential.CredentialStatus) > 0 {
+		document["credentialStatus"] = credential.CredentialStatus
+	}
+
+	proof := map[string]interface{}{
+		"type":               BBSSignatureSuite,
+		"created":            ariesTime(credential.Proof.Created),
+		"proofPurpose":       credential.Proof.ProofPurpose,
+		"proofValue":         credential.Proof.ProofValue,
+		"verificationMethod": credential.Proof.VerificationMethod,
+	}
+	if _, bound := credential.CredentialSubject[ConfirmationClaim]; bound && credential.ClaimManifest != nil {
+		if index, ok := credential.ClaimManifest.Index(ConfirmationClaim); ok {
+			proof["requiredRevealStatements"] = []int{index}
+		}
+	}
+	document["proof"] = proof
+
+	return document, nil
+}
+
+// AriesDerivedCredential writes a derived credential in the layout of an Aries
+// BbsBlsSignatureProof2020 document: the proof's nonce is the base64 nonce the proof is bound
+// to and the revealed attribute names and salts are dropped. Derivations carrying an issuer set,
+// commitments, evidence, absence or predicate proofs have no Aries equivalent and are rejected.
+func AriesDerivedCredential(derived *DerivedCredential) (*DerivedCredential, error) {
+	if derived == nil {
+		return nil, fmt.Errorf("derived credential is nil")
+	}
+	extensions := derived.Extensions()
+	for name, present := range map[string]bool{
+		"issuerSetProof": derived.IssuerSetProof != nil,
+		"coSignatures":   derived.CoSignatures != nil,
+		"commitments":    derived.Commitments != nil,
+		"evidence":       derived.Evidence != nil,
+		"claimKeys":      derived.ClaimKeys != nil,
+		"absenceProofs":  len(derived.AbsenceProofs) > 0,
+		"predicates":     len(derived.Predicates) > 0,
+	} {
+		if present {
+			extensions = append(extensions, name)
+		}
+	}
+	if len(extensions) > 0 {
+		sort.Strings(extensions)
+		return nil, fmt.Errorf("derived credential properties %v have no Aries equivalent", extensions)
+	}
+
+	derivedProof := derived.Proof
+	if derivedProof == nil {
+		return nil, fmt.Errorf("derived credential has no proof")
+	}
+	if derivedProof.Type != BBSProofSuite {
+		return nil, fmt.Errorf("unsupported proof type %v: expected %s", derivedProof.Type, BBSProofSuite)
+	}
+	if derivedProof.ProofValue == "" {
+		return nil, fmt.Errorf("derived credential has no proof value")
+	}
+	if derivedProof.Nonce == "" {
+		return nil, fmt.Errorf("derived credential has no nonce")
+	}
+	proofNonce, err := ProofNonce(derivedProof.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &DerivedCredential{
+		Context:           withBBSContext(derived.Context),
+		ID:                derived.ID,
+		Type:              derived.Type,
+		Issuer:            derived.Issuer,
+		IssuanceDate:      ariesTimePtr(derived.IssuanceDate),
+		ExpirationDate:    ariesTimePtr(derived.ExpirationDate),
+		ValidFrom:         ariesTimePtr(derived.ValidFrom),
+		ValidUntil:        ariesTimePtr(derived.ValidUntil),
+		CredentialSubject: derived.CredentialSubject,
+		CredentialStatus:  derived.CredentialStatus,
+		Proof: &Proof{
+			Type:               BBSProofSuite,
+			Created:            derivedProof.Created.UTC().Truncate(time.Second),
+			VerificationMethod: derivedProof.VerificationMethod,
+			ProofPurpose:       derivedProof.ProofPurpose,
+			ProofValue:         derivedProof.ProofValue,
+			Nonce:              base64.StdEncoding.EncodeToString(proofNonce),
+		},
+	}
+	return document, nil
+}
+
+// AriesPresentation returns a copy of the presentation with its derived credentials in the
+// Aries layout and its proof value cleared, for the holder to sign again. Status snapshots have
+// no Aries equivalent and are rejected.
+func AriesPresentation(presentation *VerifiablePresentation) (*VerifiablePresentation, error) {
+	if presentation == nil {
+		return nil, fmt.Errorf("presentation is nil")
+	}
+	if len(presentation.StatusSnapshots) > 0 {
+		return nil, fmt.Errorf("status snapshots have no Aries equivalent")
+	}
+
+	exported := *presentation
+	if presentation.Proof != nil {
+		proof := *presentation.Proof
+		proof.ProofValue = ""
+		exported.Proof = &proof
+	}
+	exported.VerifiableCredential = make([]*DerivedCredential, len(presentation.VerifiableCredential))
+	for i, credential := range presentation.VerifiableCredential {
+		document, err := AriesDerivedCredential(credential)
+		if err != nil {
+			return nil, fmt.Errorf("credential %d: %w", i, err)
+		}
+		exported.VerifiableCredential[i] = document
+	}
+	return &exported, nil
+}
+
+// withBBSContext adds the BBS+ context Aries verifiers need to expand the proof
+func withBBSContext(context []string) []string {
+	if containsString(context, jsonld.BBSV1URL) {
+		return context
+	}
+	return append(append([]string(nil), context...), jsonld.BBSV1URL)
+}
+
+// ariesTime formats a date as Aries documents do, in UTC to the second
+func ariesTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ariesTimePtr truncates an optional date to the precision Aries documents carry
+func ariesTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	truncated := t.UTC().Truncate(time.Second)
+	return &truncated
+}
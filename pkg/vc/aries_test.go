@@ -0,0 +1,141 @@
+package vc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+// loadAriesFixture reads a published Aries document whose field layout exports must match
+func loadAriesFixture(t *testing.T, name string) map[string]interface{} {
+	data, err := os.ReadFile("testdata/aries/" + name)
+	require.NoError(t, err)
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &document))
+	// The credentials exported here do not expire
+	delete(document, "expirationDate")
+	return document
+}
+
+// roundTrip encodes a document as JSON and decodes it again, as a third-party verifier reads it
+func roundTrip(t *testing.T, document interface{}) map[string]interface{} {
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+// sortedKeys lists a JSON object's property names
+func sortedKeys(object interface{}) []string {
+	var keys []string
+	for key := range object.(map[string]interface{}) {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestAriesExport(t *testing.T) {
+	bbsService := bbs.NewService()
+	service := NewService(bbsService, NewInMemoryCredentialRepository(), NewInMemoryPresentationRepository())
+
+	keyPair, err := bbsService.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, service.SetIssuerKeyPair("did:example:issuer", keyPair))
+
+	credential, err := service.IssueCredential("did:example:issuer", "did:example:holder", []Claim{
+		{Key: "givenName", Value: "An"},
+		{Key: "familyName", Value: "Nguyen"},
+	})
+	require.NoError(t, err)
+
+	t.Run("Signed Credential Matches The Aries Layout", func(t *testing.T) {
+		fixture := loadAriesFixture(t, "bbs-signature-2020.json")
+		exported, err := AriesCredential(credential)
+		require.NoError(t, err)
+		document := roundTrip(t, exported)
+
+		assert.Equal(t, sortedKeys(fixture), sortedKeys(document))
+		fixtureProof := fixture["proof"].(map[string]interface{})
+		delete(fixtureProof, "requiredRevealStatements")
+		assert.Equal(t, sortedKeys(fixtureProof), sortedKeys(document["proof"]))
+		assert.Contains(t, document["@context"], "https://w3id.org/security/bbs/v1")
+		assert.Equal(t, credential.Proof.Created.UTC().Format("2006-01-02T15:04:05Z"), document["proof"].(map[string]interface{})["created"])
+	})
+
+	t.Run("Bound Credential Requires Its Confirmation Claim", func(t *testing.T) {
+		bound := *credential
+		bound.CredentialSubject = map[string]interface{}{"id": "did:example:holder", ConfirmationClaim: map[string]interface{}{"kid": "did:example:holder#key-1"}}
+		bound.ClaimManifest = NewClaimManifest([]string{ConfirmationClaim})
+
+		exported, err := AriesCredential(&bound)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, exported["proof"].(map[string]interface{})["requiredRevealStatements"])
+	})
+
+	t.Run("Derived Credential Matches The Aries Layout", func(t *testing.T) {
+		fixture := loadAriesFixture(t, "bbs-signature-proof-2020.json")
+		presentation, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"givenName"}, Nonce: "verifier-nonce-be686331", IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.NoError(t, err)
+
+		exported, err := AriesPresentation(presentation)
+		require.NoError(t, err)
+		assert.Empty(t, exported.Proof.ProofValue)
+		document := roundTrip(t, exported.VerifiableCredential[0])
+
+		assert.Equal(t, sortedKeys(fixture), sortedKeys(document))
+		assert.Equal(t, sortedKeys(fixture["proof"]), sortedKeys(document["proof"]))
+		assert.Equal(t, []string{"givenName", "id"}, sortedKeys(document["credentialSubject"]))
+
+		// The nonce and proof value are standard base64, and the nonce is the one the proof is bound to
+		proof := document["proof"].(map[string]interface{})
+		nonce, err := base64.StdEncoding.DecodeString(proof["nonce"].(string))
+		require.NoError(t, err)
+		proofNonce, err := ProofNonce("verifier-nonce-be686331")
+		require.NoError(t, err)
+		assert.Equal(t, proofNonce, nonce)
+		_, err = base64.StdEncoding.DecodeString(proof["proofValue"].(string))
+		require.NoError(t, err)
+		decoded, err := bbs.DecodeProof(proof["proofValue"].(string))
+		require.NoError(t, err)
+		assert.Equal(t, nonce, decoded.Nonce)
+
+		// The original presentation is left in this package's layout
+		assert.NotEmpty(t, presentation.VerifiableCredential[0].AttributeSalts)
+	})
+
+	t.Run("Extensions Without An Aries Equivalent Are Rejected", func(t *testing.T) {
+		_, err := AriesDerivedCredential(&DerivedCredential{
+			Context:       []string{"https://www.w3.org/2018/credentials/v1"},
+			AbsenceProofs: []AbsenceProof{{}},
+			Proof:         &Proof{Type: BBSProofSuite, ProofValue: "AA==", Nonce: "n"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "absenceProofs")
+
+		var derived DerivedCredential
+		require.NoError(t, json.Unmarshal([]byte(`{"@context":"https://www.w3.org/2018/credentials/v1","termsOfUse":[],"proof":{"type":"`+BBSProofSuite+`","proofValue":"AA==","nonce":"n"}}`), &derived))
+		_, err = AriesDerivedCredential(&derived)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "termsOfUse")
+
+		_, err = AriesPresentation(&VerifiablePresentation{StatusSnapshots: []*StatusSnapshot{{}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown Format", func(t *testing.T) {
+		assert.NoError(t, ExportFormatAries.Validate())
+		assert.NoError(t, ExportFormatNative.Validate())
+		assert.Error(t, ExportFormat("mattr").Validate())
+	})
+}
@@ -0,0 +1,47 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// VerificationAssertion is a verifier-signed statement of a presentation's outcome, forwarded to a
+// relying party, such as a merchant backend behind an age gate, in place of the presentation. It
+// carries the revealed claims and proven predicates only: neither the holder's DID nor the
+// credentials reach the relying party.
+type VerificationAssertion struct {
+	ID       string `json:"id"`
+	Verifier string `json:"verifier"`
+	// Audience is the relying party's callback URL, so the assertion cannot be replayed to another
+	Audience  string `json:"audience"`
+	SessionID string `json:"sessionId"`
+	Nonce     string `json:"nonce"`
+	Valid     bool   `json:"valid"`
+	// Claims and Predicates are set only for a valid presentation
+	Claims     map[string]interface{} `json:"claims,omitempty"`
+	Predicates []PredicateStatement   `json:"predicates,omitempty"`
+	Created    time.Time              `json:"created"`
+	Proof      *Proof                 `json:"proof,omitempty"`
+}
+
+// AssertionSigningInput returns the bytes covered by the verifier's assertion proof
+func AssertionSigningInput(assertion *VerificationAssertion) ([]byte, error) {
+	if assertion == nil {
+		return nil, fmt.Errorf("assertion is nil")
+	}
+
+	unsigned := *assertion
+	if assertion.Proof != nil {
+		proof := *assertion.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal assertion: %w", err)
+	}
+
+	return data, nil
+}
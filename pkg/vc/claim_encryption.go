@@ -0,0 +1,274 @@
+package vc
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnknownKeyEncryptionKey is returned for a data key wrapped under a key-encryption key the
+// keyring does not hold, for example one retired before every credential was rewrapped
+var ErrUnknownKeyEncryptionKey = errors.New("unknown key-encryption key")
+
+// KeyEncryptionKeys wrap the data keys the claims of stored credentials are encrypted under.
+// Implementations may keep the key-encryption keys in a KMS; Keyring keeps them in memory.
+type KeyEncryptionKeys interface {
+	// Wrap encrypts a data key under the current key-encryption key and returns that key's ID
+	Wrap(dataKey []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap decrypts a data key wrapped under the key-encryption key keyID
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+	// Current returns the ID of the key-encryption key Wrap uses
+	Current() string
+}
+
+// Keyring holds AES-256 key-encryption keys by ID. The most recently added one wraps new data
+// keys; the others are kept to unwrap the data keys wrapped before it was rotated in.
+type Keyring struct {
+	mu      sync.RWMutex
+	keys    map[string][]byte
+	current string
+}
+
+// NewKeyring creates a keyring whose current key-encryption key is key
+func NewKeyring(id string, key []byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string][]byte)}
+	if err := k.Rotate(id, key); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Rotate adds a key-encryption key and makes it current
+func (k *Keyring) Rotate(id string, key []byte) error {
+	if id == "" {
+		return fmt.Errorf("key-encryption key ID is required")
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("key-encryption key %s must be 32 bytes, got %d", id, len(key))
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if existing, ok := k.keys[id]; ok && string(existing) != string(key) {
+		return fmt.Errorf("key-encryption key %s already holds a different key", id)
+	}
+	k.keys[id] = append([]byte(nil), key...)
+	k.current = id
+	return nil
+}
+
+// Retire removes a key-encryption key once no data key is wrapped under it anymore. The current
+// key cannot be retired.
+func (k *Keyring) Retire(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if id == k.current {
+		return fmt.Errorf("key-encryption key %s is current", id)
+	}
+	delete(k.keys, id)
+	return nil
+}
+
+// Current returns the ID of the current key-encryption key
+func (k *Keyring) Current() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+// Wrap encrypts a data key under the current key-encryption key
+func (k *Keyring) Wrap(dataKey []byte) (string, []byte, error) {
+	k.mu.RLock()
+	id, key := k.current, k.keys[k.current]
+	k.mu.RUnlock()
+
+	wrapped, err := seal(key, dataKey, []byte(id))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return id, wrapped, nil
+}
+
+// Unwrap decrypts a data key wrapped under the key-encryption key id
+func (k *Keyring) Unwrap(id string, wrapped []byte) ([]byte, error) {
+	k.mu.RLock()
+	key, ok := k.keys[id]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyEncryptionKey, id)
+	}
+
+	dataKey, err := open(key, wrapped, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// EncryptedClaims are the personal data of a stored credential encrypted under a data key of its
+// own, which is kept wrapped under a key-encryption key
+type EncryptedClaims struct {
+	// KeyID is the key-encryption key the data key is wrapped under
+	KeyID      string `json:"kid"`
+	WrappedKey []byte `json:"wrappedKey"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// claimFields are the fields of a credential encrypted at rest: the subject's claims, the salts
+// and manifest they were signed with, the issuer's signature over them, and the commitment
+// openings, evidence keys and claim key salts that would reveal them
+type claimFields struct {
+	Claims             map[string]interface{} `json:"claims,omitempty"`
+	AttributeSalts     map[string][]byte      `json:"attributeSalts,omitempty"`
+	ClaimManifest      *ClaimManifest         `json:"claimManifest,omitempty"`
+	ProofValue         string                 `json:"proofValue,omitempty"`
+	CommitmentOpenings []CommitmentOpening    `json:"commitmentOpenings,omitempty"`
+	EvidenceKeys       []EvidenceKey          `json:"evidenceKeys,omitempty"`
+	ClaimKeySalts      []ClaimKeySalt         `json:"claimKeySalts,omitempty"`
+}
+
+// EncryptClaims returns a copy of vc without its claims, attribute salts, claim manifest,
+// signature, commitment openings, evidence keys and claim key salts, and those fields encrypted
+// under a fresh data key wrapped by keys. The subject ID is kept so the credential can still be
+// listed by holder, and the proof keeps its type, method and dates. The ciphertext is bound to the
+// credential ID.
+func EncryptClaims(vc *VerifiableCredential, keys KeyEncryptionKeys) (*VerifiableCredential, *EncryptedClaims, error) {
+	fields := claimFields{
+		Claims:             make(map[string]interface{}, len(vc.CredentialSubject)),
+		AttributeSalts:     vc.AttributeSalts,
+		ClaimManifest:      vc.ClaimManifest,
+		CommitmentOpenings: vc.CommitmentOpenings,
+		EvidenceKeys:       vc.EvidenceKeys,
+		ClaimKeySalts:      vc.ClaimKeySalts,
+	}
+	if vc.Proof != nil {
+		fields.ProofValue = vc.Proof.ProofValue
+	}
+	subject := make(map[string]interface{}, 1)
+	for key, value := range vc.CredentialSubject {
+		if key == "id" {
+			subject[key] = value
+			continue
+		}
+		fields.Claims[key] = value
+	}
+
+	plaintext, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal claims: %w", err)
+	}
+	defer clear(plaintext)
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	ciphertext, err := seal(dataKey, plaintext, []byte(vc.ID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encrypt claims: %w", err)
+	}
+	keyID, wrapped, err := keys.Wrap(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stripped := *vc
+	stripped.CredentialSubject = subject
+	stripped.AttributeSalts = nil
+	stripped.ClaimManifest = nil
+	stripped.CommitmentOpenings = nil
+	stripped.EvidenceKeys = nil
+	stripped.ClaimKeySalts = nil
+	if vc.Proof != nil {
+		proof := *vc.Proof
+		proof.ProofValue = ""
+		stripped.Proof = &proof
+	}
+	return &stripped, &EncryptedClaims{KeyID: keyID, WrappedKey: wrapped, Ciphertext: ciphertext}, nil
+}
+
+// DecryptClaims restores the fields EncryptClaims encrypted into vc. Credentials encrypted before
+// the salts, manifest and signature were kept those in the clear, and they are left as stored.
+func DecryptClaims(vc *VerifiableCredential, encrypted *EncryptedClaims, keys KeyEncryptionKeys) error {
+	dataKey, err := keys.Unwrap(encrypted.KeyID, encrypted.WrappedKey)
+	if err != nil {
+		return err
+	}
+	plaintext, err := open(dataKey, encrypted.Ciphertext, []byte(vc.ID))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt claims of %s: %w", vc.ID, err)
+	}
+
+	var fields claimFields
+	if err := json.Unmarshal(plaintext, &fields); err != nil {
+		return fmt.Errorf("failed to unmarshal claims of %s: %w", vc.ID, err)
+	}
+	if vc.CredentialSubject == nil {
+		vc.CredentialSubject = make(map[string]interface{}, len(fields.Claims))
+	}
+	for key, value := range fields.Claims {
+		vc.CredentialSubject[key] = value
+	}
+	if fields.AttributeSalts != nil {
+		vc.AttributeSalts = fields.AttributeSalts
+	}
+	if fields.ClaimManifest != nil {
+		vc.ClaimManifest = fields.ClaimManifest
+	}
+	if fields.ProofValue != "" && vc.Proof != nil {
+		vc.Proof.ProofValue = fields.ProofValue
+	}
+	vc.CommitmentOpenings = fields.CommitmentOpenings
+	vc.EvidenceKeys = fields.EvidenceKeys
+	if fields.ClaimKeySalts != nil {
+		vc.ClaimKeySalts = fields.ClaimKeySalts
+	}
+	return nil
+}
+
+// hasClearClaimFields reports whether a credential stored with encrypted claims still holds
+// fields in the clear that EncryptClaims encrypts, as credentials encrypted by earlier versions do
+func hasClearClaimFields(vc *VerifiableCredential) bool {
+	return vc.AttributeSalts != nil || vc.ClaimManifest != nil || vc.ClaimKeySalts != nil ||
+		(vc.Proof != nil && vc.Proof.ProofValue != "")
+}
+
+// RewrapClaims rewraps the data key of encrypted under the current key-encryption key. The
+// claims are not decrypted, so rotating the key-encryption key does not re-encrypt them.
+func RewrapClaims(encrypted *EncryptedClaims, keys KeyEncryptionKeys) (*EncryptedClaims, error) {
+	dataKey, err := keys.Unwrap(encrypted.KeyID, encrypted.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := keys.Wrap(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedClaims{KeyID: keyID, WrappedKey: wrapped, Ciphertext: encrypted.Ciphertext}, nil
+}
+
+// seal encrypts plaintext with AES-256-GCM, prefixing the random nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := documentCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts what seal encrypted
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := documentCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additionalData)
+}
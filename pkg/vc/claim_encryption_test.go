@@ -0,0 +1,136 @@
+package vc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+func TestClaimEncryption(t *testing.T) {
+	store := storage.NewMemoryStore()
+	keyring, err := NewKeyring("2024", bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	repo := NewKVCredentialRepositoryWithOptions(store, KVCredentialOptions{Keys: keyring}).(*KVCredentialRepository)
+
+	credential := &VerifiableCredential{
+		ID:                "urn:uuid:encrypted",
+		CredentialSubject: map[string]interface{}{"id": "did:example:holder", "name": "Alice Example"},
+		AttributeSalts:    map[string][]byte{"name": []byte("name-salt")},
+		ClaimManifest:     &ClaimManifest{Claims: []ManifestEntry{{Key: "name", Index: 1}}},
+		EvidenceKeys:      []EvidenceKey{{EvidenceID: "passport", Key: []byte("evidence-key")}},
+		ClaimKeySalts:     []ClaimKeySalt{{Claim: "name", Salt: []byte("claim-key-salt")}},
+		Proof:             &Proof{Type: "BbsBlsSignature2020", ProofPurpose: "assertionMethod", ProofValue: "bbs-signature"},
+	}
+	require.NoError(t, repo.Store(credential))
+	clearFields := []string{"Alice Example", "evidence-key", "bbs-signature", "attributeSalts", "claimManifest", "claimKeySalts"}
+
+	t.Run("Claims Are Encrypted At Rest", func(t *testing.T) {
+		data, err := store.Get(credentialKeyPrefix + credential.ID)
+		require.NoError(t, err)
+		for _, field := range clearFields {
+			assert.NotContains(t, string(data), field)
+		}
+		assert.Contains(t, string(data), "did:example:holder", "the subject ID is kept to list by holder")
+		assert.Contains(t, string(data), "BbsBlsSignature2020", "the proof keeps its type")
+		assert.Equal(t, "Alice Example", credential.CredentialSubject["name"], "the credential stored is not modified")
+		assert.Equal(t, "bbs-signature", credential.Proof.ProofValue)
+	})
+
+	t.Run("Transparent Decryption", func(t *testing.T) {
+		retrieved, err := repo.Retrieve(credential.ID)
+		require.NoError(t, err)
+		assert.Equal(t, credential, retrieved)
+
+		listed, err := repo.List("did:example:holder")
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, "Alice Example", listed[0].CredentialSubject["name"])
+	})
+
+	t.Run("Ciphertext Is Bound To The Credential", func(t *testing.T) {
+		data, err := store.Get(credentialKeyPrefix + credential.ID)
+		require.NoError(t, err)
+		require.NoError(t, store.Set(credentialKeyPrefix+"urn:uuid:swapped", bytes.Replace(data, []byte(credential.ID), []byte("urn:uuid:swapped"), 1), 0))
+		_, err = repo.Retrieve("urn:uuid:swapped")
+		assert.Error(t, err)
+	})
+
+	t.Run("Key Rotation", func(t *testing.T) {
+		require.NoError(t, store.Delete(credentialKeyPrefix+"urn:uuid:swapped"))
+		clear := &VerifiableCredential{ID: "urn:uuid:clear", CredentialSubject: map[string]interface{}{"id": "did:example:holder", "name": "Bob Example"}}
+		require.NoError(t, NewKVCredentialRepository(store).Store(clear))
+
+		require.NoError(t, keyring.Rotate("2025", bytes.Repeat([]byte{2}, 32)))
+		rewritten, err := repo.RewrapKeys()
+		require.NoError(t, err)
+		assert.Equal(t, 2, rewritten, "the rotated credential and the one stored in the clear")
+
+		rewritten, err = repo.RewrapKeys()
+		require.NoError(t, err)
+		assert.Zero(t, rewritten)
+
+		require.NoError(t, keyring.Retire("2024"))
+		assert.Error(t, keyring.Retire("2025"), "the current key cannot be retired")
+		for _, id := range []string{credential.ID, clear.ID} {
+			retrieved, err := repo.Retrieve(id)
+			require.NoError(t, err)
+			assert.NotEmpty(t, retrieved.CredentialSubject["name"])
+
+			data, err := store.Get(credentialKeyPrefix + id)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), retrieved.CredentialSubject["name"])
+		}
+	})
+
+	t.Run("Credentials Encrypted Without Their Salts", func(t *testing.T) {
+		// Earlier versions encrypted only the claims, leaving the salts and signature in the clear
+		legacy := *credential
+		legacy.ID = "urn:uuid:legacy"
+		stripped, encrypted, err := EncryptClaims(&legacy, keyring)
+		require.NoError(t, err)
+		stripped.AttributeSalts = legacy.AttributeSalts
+		stripped.ClaimManifest = legacy.ClaimManifest
+		stripped.ClaimKeySalts = legacy.ClaimKeySalts
+		stripped.Proof = legacy.Proof
+		require.NoError(t, repo.write(stripped, encrypted, 0))
+
+		retrieved, err := repo.Retrieve(legacy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &legacy, retrieved)
+
+		rewritten, err := repo.RewrapKeys()
+		require.NoError(t, err)
+		assert.Equal(t, 1, rewritten, "the key is current, but the salts and signature are encrypted")
+
+		data, err := store.Get(credentialKeyPrefix + legacy.ID)
+		require.NoError(t, err)
+		for _, field := range clearFields {
+			assert.NotContains(t, string(data), field)
+		}
+		retrieved, err = repo.Retrieve(legacy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &legacy, retrieved)
+	})
+
+	t.Run("Retired Keys", func(t *testing.T) {
+		old, err := NewKeyring("2024", bytes.Repeat([]byte{1}, 32))
+		require.NoError(t, err)
+		stale := &VerifiableCredential{ID: "urn:uuid:stale", CredentialSubject: map[string]interface{}{"id": "did:example:holder", "name": "Carol Example"}}
+		require.NoError(t, NewKVCredentialRepositoryWithOptions(store, KVCredentialOptions{Keys: old}).Store(stale))
+
+		_, err = repo.Retrieve(stale.ID)
+		assert.ErrorIs(t, err, ErrUnknownKeyEncryptionKey)
+		_, err = NewKVCredentialRepository(store).Retrieve(stale.ID)
+		assert.Error(t, err, "encrypted claims need the keys")
+	})
+
+	t.Run("Invalid Keys", func(t *testing.T) {
+		_, err := NewKeyring("short", []byte("too short"))
+		assert.Error(t, err)
+		assert.Error(t, keyring.Rotate("2025", bytes.Repeat([]byte{3}, 32)), "an ID cannot hold another key")
+	})
+}
@@ -0,0 +1,327 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+)
+
+// DateLayout is the canonical lexical form of date claims
+const DateLayout = "2006-01-02"
+
+// NormalizeClaim coerces a claim's value to the canonical representation of its type.
+// Untyped claims get a type inferred from their value. Canonical values are string for
+// string, date and decimal claims, int64 for integer claims and bool for boolean claims.
+func NormalizeClaim(claim Claim) (Claim, error) {
+	if claim.Key == "" {
+		return Claim{}, fmt.Errorf("claim key is required")
+	}
+
+	claimType := claim.Type
+	if claimType == "" {
+		inferred, err := InferClaimType(claim.Value)
+		if err != nil {
+			return Claim{}, fmt.Errorf("claim %s: %w", claim.Key, err)
+		}
+		claimType = inferred
+	}
+
+	value, err := CoerceClaimValue(claimType, claim.Value)
+	if err != nil {
+		return Claim{}, fmt.Errorf("claim %s: %w", claim.Key, err)
+	}
+
+	return Claim{Key: claim.Key, Value: value, Type: claimType}, nil
+}
+
+// InferClaimType picks the claim type for an untyped value
+func InferClaimType(value interface{}) (schema.ClaimType, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", fmt.Errorf("claim value is null")
+	case bool:
+		return schema.ClaimTypeBoolean, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return schema.ClaimTypeInteger, nil
+	case float32:
+		return inferFloatType(float64(v))
+	case float64:
+		return inferFloatType(v)
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return schema.ClaimTypeInteger, nil
+		}
+		return schema.ClaimTypeDecimal, nil
+	case string:
+		return schema.ClaimTypeString, nil
+	case time.Time:
+		return schema.ClaimTypeDate, nil
+	default:
+		return "", fmt.Errorf("unsupported claim value type %T", value)
+	}
+}
+
+// inferFloatType treats integral floats, as produced by JSON decoding, as integers
+func inferFloatType(f float64) (schema.ClaimType, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("claim value %v is not a finite number", f)
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+		return schema.ClaimTypeInteger, nil
+	}
+	return schema.ClaimTypeDecimal, nil
+}
+
+// CoerceClaimValue converts a value to the canonical representation of the claim type
+func CoerceClaimValue(claimType schema.ClaimType, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, fmt.Errorf("claim value is null")
+	}
+
+	switch claimType {
+	case schema.ClaimTypeString:
+		return coerceString(value)
+	case schema.ClaimTypeInteger:
+		return coerceInteger(value)
+	case schema.ClaimTypeBoolean:
+		return coerceBoolean(value)
+	case schema.ClaimTypeDate:
+		return coerceDate(value)
+	case schema.ClaimTypeDecimal:
+		return coerceDecimal(value)
+	default:
+		return nil, fmt.Errorf("unsupported claim type: %s", claimType)
+	}
+}
+
+// EncodeClaimValue returns the canonical bytes signed for a claim value.
+// Values are coerced first, so 25, 25.0 and "25" typed as integer all encode to "25".
+func EncodeClaimValue(claimType schema.ClaimType, value interface{}) ([]byte, error) {
+	canonical, err := CoerceClaimValue(claimType, value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := canonical.(type) {
+	case string:
+		return []byte(v), nil
+	case int64:
+		return []byte(strconv.FormatInt(v, 10)), nil
+	case bool:
+		return []byte(strconv.FormatBool(v)), nil
+	default:
+		return nil, fmt.Errorf("unexpected canonical value type %T", canonical)
+	}
+}
+
+// ClaimMessages rebuilds the canonical signed messages for the given claims of a credential subject.
+// Types are inferred from the subject values, which yields the same bytes as issuance because the
+// canonical forms of dates and decimals are strings and integers survive JSON as integral numbers.
+func ClaimMessages(subject map[string]interface{}, keys []string) ([][]byte, error) {
+	messages := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, ok := subject[key]
+		if !ok {
+			return nil, fmt.Errorf("claim %s not found in credential subject", key)
+		}
+
+		if key == ConfirmationClaim {
+			confirmation, err := ParseConfirmation(value)
+			if err != nil {
+				return nil, err
+			}
+			messages[i] = confirmation.Message()
+			continue
+		}
+
+		claimType, err := InferClaimType(value)
+		if err != nil {
+			return nil, fmt.Errorf("claim %s: %w", key, err)
+		}
+
+		message, err := EncodeClaimValue(claimType, value)
+		if err != nil {
+			return nil, fmt.Errorf("claim %s: %w", key, err)
+		}
+		messages[i] = message
+	}
+	return messages, nil
+}
+
+func coerceString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return "", fmt.Errorf("time values must be typed as date")
+	}
+
+	if i, err := coerceInteger(value); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+	if d, err := coerceDecimal(value); err == nil {
+		return d, nil
+	}
+	return "", fmt.Errorf("cannot use %T as string", value)
+}
+
+func coerceInteger(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return coerceUnsigned(uint64(v))
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return coerceUnsigned(v)
+	case float32:
+		return coerceIntegralFloat(float64(v))
+	case float64:
+		return coerceIntegralFloat(v)
+	case json.Number:
+		return coerceIntegerString(v.String())
+	case string:
+		return coerceIntegerString(v)
+	default:
+		return 0, fmt.Errorf("cannot use %T as integer", value)
+	}
+}
+
+func coerceUnsigned(v uint64) (int64, error) {
+	if v > math.MaxInt64 {
+		return 0, fmt.Errorf("integer %d overflows int64", v)
+	}
+	return int64(v), nil
+}
+
+func coerceIntegralFloat(f float64) (int64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) || math.Abs(f) >= 1<<63 {
+		return 0, fmt.Errorf("%v is not an integer", f)
+	}
+	return int64(f), nil
+}
+
+// coerceIntegerString accepts integer literals and decimals with a zero fraction such as "25.0"
+func coerceIntegerString(s string) (int64, error) {
+	decimal, err := canonicalDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+	if strings.Contains(decimal, ".") {
+		return 0, fmt.Errorf("%q is not an integer", s)
+	}
+	i, err := strconv.ParseInt(decimal, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("integer %q overflows int64", s)
+	}
+	return i, nil
+}
+
+func coerceBoolean(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch v {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return false, fmt.Errorf("%q is not a boolean", v)
+	default:
+		return false, fmt.Errorf("cannot use %T as boolean", value)
+	}
+}
+
+func coerceDate(value interface{}) (string, error) {
+	// Canonical dates are calendar dates, so instants take their date under the default policy
+	date, err := DatePolicy{}.ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	return date.Format(DateLayout), nil
+}
+
+func coerceDecimal(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case float32:
+		return coerceFloatDecimal(float64(v), 32)
+	case float64:
+		return coerceFloatDecimal(v, 64)
+	case json.Number:
+		return canonicalDecimal(v.String())
+	case string:
+		return canonicalDecimal(v)
+	}
+
+	if i, err := coerceInteger(value); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+	return "", fmt.Errorf("cannot use %T as decimal", value)
+}
+
+func coerceFloatDecimal(f float64, bitSize int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("%v is not a finite number", f)
+	}
+	return canonicalDecimal(strconv.FormatFloat(f, 'f', -1, bitSize))
+}
+
+// canonicalDecimal normalises a plain decimal literal: no exponent, no leading '+', no
+// redundant leading or trailing zeros and no negative zero, e.g. "+025.50" becomes "25.5"
+func canonicalDecimal(s string) (string, error) {
+	literal := s
+	negative := false
+	if strings.HasPrefix(literal, "-") || strings.HasPrefix(literal, "+") {
+		negative = literal[0] == '-'
+		literal = literal[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(literal, ".")
+	if integer == "" && fraction == "" {
+		return "", fmt.Errorf("%q is not a decimal", s)
+	}
+	for _, part := range []string{integer, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return "", fmt.Errorf("%q is not a decimal", s)
+			}
+		}
+	}
+
+	integer = strings.TrimLeft(integer, "0")
+	if integer == "" {
+		integer = "0"
+	}
+	fraction = strings.TrimRight(fraction, "0")
+
+	result := integer
+	if fraction != "" {
+		result += "." + fraction
+	}
+	if negative && result != "0" {
+		result = "-" + result
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+)
+
+func TestNormalizeClaim(t *testing.T) {
+	tests := []struct {
+		name     string
+		claim    Claim
+		expected Claim
+	}{
+		{"Inferred String", Claim{Key: "k", Value: "An"}, Claim{Key: "k", Value: "An", Type: schema.ClaimTypeString}},
+		{"Inferred Integer", Claim{Key: "k", Value: 25}, Claim{Key: "k", Value: int64(25), Type: schema.ClaimTypeInteger}},
+		{"Inferred Integral Float", Claim{Key: "k", Value: 25.0}, Claim{Key: "k", Value: int64(25), Type: schema.ClaimTypeInteger}},
+		{"Inferred Decimal", Claim{Key: "k", Value: 3.75}, Claim{Key: "k", Value: "3.75", Type: schema.ClaimTypeDecimal}},
+		{"Inferred Boolean", Claim{Key: "k", Value: true}, Claim{Key: "k", Value: true, Type: schema.ClaimTypeBoolean}},
+		{"Inferred Date", Claim{Key: "k", Value: time.Date(2000, 1, 20, 15, 4, 5, 0, time.UTC)}, Claim{Key: "k", Value: "2000-01-20", Type: schema.ClaimTypeDate}},
+		{"Integer From String", Claim{Key: "k", Value: "25", Type: schema.ClaimTypeInteger}, Claim{Key: "k", Value: int64(25), Type: schema.ClaimTypeInteger}},
+		{"Integer From Zero Fraction", Claim{Key: "k", Value: "25.0", Type: schema.ClaimTypeInteger}, Claim{Key: "k", Value: int64(25), Type: schema.ClaimTypeInteger}},
+		{"String From Number", Claim{Key: "k", Value: 123456789, Type: schema.ClaimTypeString}, Claim{Key: "k", Value: "123456789", Type: schema.ClaimTypeString}},
+		{"Boolean From String", Claim{Key: "k", Value: "false", Type: schema.ClaimTypeBoolean}, Claim{Key: "k", Value: false, Type: schema.ClaimTypeBoolean}},
+		{"Date From RFC3339", Claim{Key: "k", Value: "2000-01-20T23:00:00+07:00", Type: schema.ClaimTypeDate}, Claim{Key: "k", Value: "2000-01-20", Type: schema.ClaimTypeDate}},
+		{"Decimal Normalized", Claim{Key: "k", Value: "+003.500", Type: schema.ClaimTypeDecimal}, Claim{Key: "k", Value: "3.5", Type: schema.ClaimTypeDecimal}},
+		{"Decimal Negative Zero", Claim{Key: "k", Value: "-0.00", Type: schema.ClaimTypeDecimal}, Claim{Key: "k", Value: "0", Type: schema.ClaimTypeDecimal}},
+		{"Decimal From Integer", Claim{Key: "k", Value: 4, Type: schema.ClaimTypeDecimal}, Claim{Key: "k", Value: "4", Type: schema.ClaimTypeDecimal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := NormalizeClaim(tt.claim)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+}
+
+func TestNormalizeClaimErrors(t *testing.T) {
+	invalid := []Claim{
+		{Value: "missing key"},
+		{Key: "k", Value: nil},
+		{Key: "k", Value: []string{"a"}},
+		{Key: "k", Value: map[string]interface{}{"a": 1}},
+		{Key: "k", Value: "25.5", Type: schema.ClaimTypeInteger},
+		{Key: "k", Value: "twenty", Type: schema.ClaimTypeInteger},
+		{Key: "k", Value: "99999999999999999999", Type: schema.ClaimTypeInteger},
+		{Key: "k", Value: "yes", Type: schema.ClaimTypeBoolean},
+		{Key: "k", Value: 1, Type: schema.ClaimTypeBoolean},
+		{Key: "k", Value: "20/01/2000", Type: schema.ClaimTypeDate},
+		{Key: "k", Value: "1e3", Type: schema.ClaimTypeDecimal},
+		{Key: "k", Value: ".", Type: schema.ClaimTypeDecimal},
+		{Key: "k", Value: "x", Type: schema.ClaimType("uuid")},
+	}
+
+	for _, claim := range invalid {
+		_, err := NormalizeClaim(claim)
+		assert.Error(t, err, "claim %+v", claim)
+	}
+}
+
+func TestEncodeClaimValue(t *testing.T) {
+	t.Run("Equal Values Encode Identically", func(t *testing.T) {
+		for _, value := range []interface{}{25, int64(25), 25.0, "25", "25.0", json.Number("25")} {
+			encoded, err := EncodeClaimValue(schema.ClaimTypeInteger, value)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("25"), encoded, "value %#v", value)
+		}
+
+		for _, value := range []interface{}{2.5, "2.50", "02.5", json.Number("2.500")} {
+			encoded, err := EncodeClaimValue(schema.ClaimTypeDecimal, value)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("2.5"), encoded, "value %#v", value)
+		}
+	})
+
+	t.Run("Claim Messages Survive JSON", func(t *testing.T) {
+		claims := []Claim{
+			{Key: "name", Value: "An"},
+			{Key: "age", Value: 25},
+			{Key: "gpa", Value: "3.50", Type: schema.ClaimTypeDecimal},
+			{Key: "dateOfBirth", Value: "2000-01-20", Type: schema.ClaimTypeDate},
+			{Key: "ageOver18", Value: true},
+		}
+
+		subject := make(map[string]interface{})
+		var keys []string
+		var issued [][]byte
+		for _, claim := range claims {
+			normalized, err := NormalizeClaim(claim)
+			require.NoError(t, err)
+			subject[normalized.Key] = normalized.Value
+			keys = append(keys, normalized.Key)
+
+			message, err := EncodeClaimValue(normalized.Type, normalized.Value)
+			require.NoError(t, err)
+			issued = append(issued, message)
+		}
+
+		data, err := json.Marshal(subject)
+		require.NoError(t, err)
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		rebuilt, err := ClaimMessages(decoded, keys)
+		require.NoError(t, err)
+		assert.Equal(t, issued, rebuilt)
+
+		_, err = ClaimMessages(decoded, []string{"missing"})
+		assert.Error(t, err)
+	})
+}
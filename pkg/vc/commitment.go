@@ -0,0 +1,114 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+)
+
+// CommitmentEncoding says how a claim value is turned into the scalar it is committed as
+type CommitmentEncoding string
+
+const (
+	// CommitmentEncodingInteger commits integers as themselves and booleans as 0 or 1
+	CommitmentEncodingInteger CommitmentEncoding = "integer"
+	// CommitmentEncodingDate commits dates as the integer YYYYMMDD, so date order is integer order
+	CommitmentEncodingDate CommitmentEncoding = "date"
+	// CommitmentEncodingMessage commits the BBS+ message scalar of the claim, which supports
+	// equality statements only
+	CommitmentEncodingMessage CommitmentEncoding = "message"
+)
+
+// AttributeCommitment is a Pedersen commitment to one claim of a credential
+type AttributeCommitment struct {
+	Claim      string             `json:"claim"`
+	Encoding   CommitmentEncoding `json:"encoding"`
+	Commitment []byte             `json:"commitment"`
+}
+
+// CommitmentBundle is an issuer-signed set of commitments to the claims of a credential.
+// The issuer commits to the same values it signs, so a zero-knowledge proof about a committed
+// value, e.g. a range proof from an external circuit, is a proof about the signed claim.
+type CommitmentBundle struct {
+	CredentialID string                    `json:"credentialId"`
+	Issuer       string                    `json:"issuer"`
+	Generators   *bbs.CommitmentGenerators `json:"generators"`
+	Commitments  []AttributeCommitment     `json:"commitments"`
+	Proof        *Proof                    `json:"proof,omitempty"`
+}
+
+// CommitmentOpening is the witness for a commitment: the committed scalar and its blinding factor.
+// Openings are private to the holder, who passes them to external provers.
+type CommitmentOpening struct {
+	Claim    string             `json:"claim"`
+	Encoding CommitmentEncoding `json:"encoding"`
+	Value    []byte             `json:"value"`
+	Blinding []byte             `json:"blinding"`
+}
+
+// Find returns the commitment to the given claim
+func (b *CommitmentBundle) Find(claim string) (*AttributeCommitment, bool) {
+	for i := range b.Commitments {
+		if b.Commitments[i].Claim == claim {
+			return &b.Commitments[i], true
+		}
+	}
+	return nil, false
+}
+
+// CommitmentScalar returns the scalar a claim value is committed as, and the encoding used.
+// Integers, booleans and dates are committed as numbers so circuits can compare them;
+// strings and decimals are committed as their BBS+ message scalar.
+func CommitmentScalar(claimType schema.ClaimType, value interface{}) ([]byte, CommitmentEncoding, error) {
+	canonical, err := CoerceClaimValue(claimType, value)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch claimType {
+	case schema.ClaimTypeInteger:
+		return bbs.ScalarFromInt(big.NewInt(canonical.(int64))), CommitmentEncodingInteger, nil
+	case schema.ClaimTypeBoolean:
+		if canonical.(bool) {
+			return bbs.ScalarFromInt(big.NewInt(1)), CommitmentEncodingInteger, nil
+		}
+		return bbs.ScalarFromInt(big.NewInt(0)), CommitmentEncodingInteger, nil
+	case schema.ClaimTypeDate:
+		number, ok := new(big.Int).SetString(strings.ReplaceAll(canonical.(string), "-", ""), 10)
+		if !ok {
+			return nil, "", fmt.Errorf("invalid date: %v", canonical)
+		}
+		return bbs.ScalarFromInt(number), CommitmentEncodingDate, nil
+	default:
+		message, err := EncodeClaimValue(claimType, canonical)
+		if err != nil {
+			return nil, "", err
+		}
+		return bbs.MessageScalar(message), CommitmentEncodingMessage, nil
+	}
+}
+
+// CommitmentBundleSigningInput returns the bytes covered by the issuer's bundle proof
+func CommitmentBundleSigningInput(bundle *CommitmentBundle) ([]byte, error) {
+	if bundle == nil {
+		return nil, fmt.Errorf("commitment bundle is nil")
+	}
+
+	unsigned := *bundle
+	if bundle.Proof != nil {
+		proof := *bundle.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal commitment bundle: %w", err)
+	}
+
+	return data, nil
+}
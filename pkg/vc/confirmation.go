@@ -0,0 +1,84 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ConfirmationClaim is the credential subject claim that binds a credential to a holder key,
+// after the cnf claim of RFC 7800
+const ConfirmationClaim = "cnf"
+
+// Confirmation binds a credential to a holder key. Presentations of a bound credential must be
+// signed with the key, so a copied credential cannot be presented by anyone else.
+type Confirmation struct {
+	// KeyID is the holder verification method presentations must be signed with
+	KeyID string `json:"kid"`
+	// PublicKeyMultibase is the Ed25519 public key of that verification method at issuance
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+// Validate checks the confirmation names a key and carries it
+func (c Confirmation) Validate() error {
+	if c.KeyID == "" {
+		return fmt.Errorf("confirmation key ID is required")
+	}
+	if c.PublicKeyMultibase == "" {
+		return fmt.Errorf("confirmation public key is required")
+	}
+	return nil
+}
+
+// Value returns the confirmation in decoded JSON form, as it is held in the credential subject
+func (c Confirmation) Value() map[string]interface{} {
+	return map[string]interface{}{
+		"kid":                c.KeyID,
+		"publicKeyMultibase": c.PublicKeyMultibase,
+	}
+}
+
+// Message returns the canonical bytes signed for the confirmation claim
+func (c Confirmation) Message() []byte {
+	// Struct fields marshal in declaration order, so the encoding is deterministic
+	data, _ := json.Marshal(c)
+	return data
+}
+
+// ParseConfirmation reads a confirmation claim given as a Confirmation or in decoded JSON form
+func ParseConfirmation(value interface{}) (*Confirmation, error) {
+	var confirmation Confirmation
+	switch v := value.(type) {
+	case Confirmation:
+		confirmation = v
+	case *Confirmation:
+		if v == nil {
+			return nil, fmt.Errorf("confirmation is nil")
+		}
+		confirmation = *v
+	case map[string]interface{}:
+		confirmation.KeyID, _ = v["kid"].(string)
+		confirmation.PublicKeyMultibase, _ = v["publicKeyMultibase"].(string)
+	default:
+		return nil, fmt.Errorf("%s must be an object with kid and publicKeyMultibase", ConfirmationClaim)
+	}
+
+	if err := confirmation.Validate(); err != nil {
+		return nil, err
+	}
+	return &confirmation, nil
+}
+
+// SubjectConfirmation returns the confirmation a credential subject is bound with, or nil if the
+// credential is not bound to a holder key
+func SubjectConfirmation(subject map[string]interface{}) (*Confirmation, error) {
+	value, ok := subject[ConfirmationClaim]
+	if !ok {
+		return nil, nil
+	}
+	return ParseConfirmation(value)
+}
+
+// Confirmation returns the holder key the credential is bound to, or nil if it is not bound
+func (c *VerifiableCredential) Confirmation() (*Confirmation, error) {
+	return SubjectConfirmation(c.CredentialSubject)
+}
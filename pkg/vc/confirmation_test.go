@@ -0,0 +1,54 @@
+package vc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfirmation(t *testing.T) {
+	expected := Confirmation{KeyID: "did:example:holder#key-1", PublicKeyMultibase: "z6MkExample"}
+
+	t.Run("From Struct", func(t *testing.T) {
+		confirmation, err := ParseConfirmation(expected)
+		require.NoError(t, err)
+		assert.Equal(t, expected, *confirmation)
+	})
+
+	t.Run("From Decoded JSON", func(t *testing.T) {
+		confirmation, err := ParseConfirmation(expected.Value())
+		require.NoError(t, err)
+		assert.Equal(t, expected, *confirmation)
+	})
+
+	t.Run("Missing Key", func(t *testing.T) {
+		_, err := ParseConfirmation(map[string]interface{}{"kid": "did:example:holder#key-1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Not An Object", func(t *testing.T) {
+		_, err := ParseConfirmation("did:example:holder#key-1")
+		assert.Error(t, err)
+	})
+}
+
+func TestConfirmationClaimMessage(t *testing.T) {
+	confirmation := Confirmation{KeyID: "did:example:holder#key-1", PublicKeyMultibase: "z6MkExample"}
+	subject := map[string]interface{}{
+		"id":              "did:example:holder",
+		ConfirmationClaim: confirmation.Value(),
+	}
+
+	messages, err := ClaimMessages(subject, []string{ConfirmationClaim})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{confirmation.Message()}, messages)
+
+	bound, err := SubjectConfirmation(subject)
+	require.NoError(t, err)
+	assert.Equal(t, &confirmation, bound)
+
+	unbound, err := SubjectConfirmation(map[string]interface{}{"id": "did:example:holder"})
+	require.NoError(t, err)
+	assert.Nil(t, unbound)
+}
@@ -0,0 +1,71 @@
+package vc
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// ContentIDPrefix begins content-addressed credential IDs
+const ContentIDPrefix = "urn:multihash:"
+
+// sha256Multihash is the multihash header of a SHA2-256 digest: the function code and length
+var sha256Multihash = []byte{0x12, 0x20}
+
+// ContentID returns the content-addressed ID of a credential: a base58btc multibase SHA2-256
+// multihash of the canonical JSON of its issuer, subject and claims. Issuing the same claims to
+// the same subject again yields the same ID; salts, dates and proofs do not contribute.
+func ContentID(credential *VerifiableCredential) (string, error) {
+	if credential == nil {
+		return "", fmt.Errorf("credential is nil")
+	}
+	subject, _ := credential.CredentialSubject["id"].(string)
+	if credential.Issuer() == "" || subject == "" {
+		return "", fmt.Errorf("content-addressed IDs need an issuer and a subject")
+	}
+
+	claims := make(map[string]interface{}, len(credential.CredentialSubject))
+	for key, value := range credential.CredentialSubject {
+		if key != "id" {
+			claims[key] = value
+		}
+	}
+
+	// Maps marshal with sorted keys, so equal claims always hash the same bytes
+	data, err := json.Marshal(struct {
+		Issuer  string                 `json:"issuer"`
+		Subject string                 `json:"subject"`
+		Claims  map[string]interface{} `json:"claims"`
+	}{credential.Issuer(), subject, claims})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credential content: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return ContentIDPrefix + "z" + base58.Encode(append(append([]byte{}, sha256Multihash...), digest[:]...)), nil
+}
+
+// IsContentID reports whether id is a content-addressed credential ID
+func IsContentID(id string) bool {
+	return strings.HasPrefix(id, ContentIDPrefix)
+}
+
+// CheckContentID checks a credential with a content-addressed ID carries the content the ID was
+// derived from. Other IDs are not checked.
+func CheckContentID(credential *VerifiableCredential) error {
+	if credential == nil || !IsContentID(credential.ID) {
+		return nil
+	}
+
+	id, err := ContentID(credential)
+	if err != nil {
+		return err
+	}
+	if id != credential.ID {
+		return fmt.Errorf("credential ID %s does not match its content", credential.ID)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentID(t *testing.T) {
+	credential := func(subject map[string]interface{}) *VerifiableCredential {
+		return &VerifiableCredential{
+			ID:                "urn:uuid:1",
+			IssuerInfo:        NewIssuer("did:example:issuer"),
+			CredentialSubject: subject,
+		}
+	}
+
+	t.Run("Same Content Same ID", func(t *testing.T) {
+		first, err := ContentID(credential(map[string]interface{}{"id": "did:example:holder", "age": int64(25), "name": "An"}))
+		require.NoError(t, err)
+		second, err := ContentID(credential(map[string]interface{}{"name": "An", "age": int64(25), "id": "did:example:holder"}))
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.True(t, IsContentID(first))
+		assert.Regexp(t, `^urn:multihash:zQm`, first, "a base58btc SHA2-256 multihash")
+	})
+
+	t.Run("Different Content Different ID", func(t *testing.T) {
+		base, err := ContentID(credential(map[string]interface{}{"id": "did:example:holder", "age": int64(25)}))
+		require.NoError(t, err)
+
+		for name, other := range map[string]*VerifiableCredential{
+			"Claim Value": credential(map[string]interface{}{"id": "did:example:holder", "age": int64(26)}),
+			"Claim Type":  credential(map[string]interface{}{"id": "did:example:holder", "age": "25"}),
+			"Subject":     credential(map[string]interface{}{"id": "did:example:other", "age": int64(25)}),
+		} {
+			id, err := ContentID(other)
+			require.NoError(t, err)
+			assert.NotEqual(t, base, id, name)
+		}
+
+		issuer := credential(map[string]interface{}{"id": "did:example:holder", "age": int64(25)})
+		issuer.IssuerInfo = NewIssuer("did:example:another")
+		id, err := ContentID(issuer)
+		require.NoError(t, err)
+		assert.NotEqual(t, base, id)
+	})
+
+	t.Run("Checked After A JSON Round Trip", func(t *testing.T) {
+		original := credential(map[string]interface{}{"id": "did:example:holder", "age": int64(25)})
+		id, err := ContentID(original)
+		require.NoError(t, err)
+		original.ID = id
+
+		data, err := json.Marshal(original)
+		require.NoError(t, err)
+		var decoded VerifiableCredential
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.NoError(t, CheckContentID(&decoded))
+
+		decoded.CredentialSubject["age"] = 26
+		assert.Error(t, CheckContentID(&decoded))
+		assert.NoError(t, CheckContentID(credential(map[string]interface{}{"age": 26})), "random IDs are not checked")
+	})
+
+	t.Run("Subject Required", func(t *testing.T) {
+		_, err := ContentID(credential(map[string]interface{}{"age": int64(25)}))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,36 @@
+package vc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+// ContinuityProof shows that two credentials, typically two versions of an amended credential,
+// hold the same value of a committed claim without revealing it. Proving the subject identifier
+// continuous shows both versions were issued to one holder, e.g. for loyalty tenure, while the
+// identifier stays hidden.
+type ContinuityProof struct {
+	Claim string `json:"claim"`
+	// Previous and Current are the issuer-signed commitments of the two credentials
+	Previous *CommitmentBundle `json:"previous"`
+	Current  *CommitmentBundle `json:"current"`
+	// Proof shows the commitments to Claim in both bundles hold the same value
+	Proof *bbs.EqualityProof `json:"proof"`
+	Nonce string             `json:"nonce"`
+}
+
+// ContinuityContext returns the bytes a continuity proof is bound to: the claim, both
+// credentials and the verifier's nonce. A proof cannot be replayed to another verifier or moved
+// to other credentials.
+func ContinuityContext(claim, previousID, currentID, nonce string) []byte {
+	h := sha256.New()
+	h.Write([]byte("BBS_CONTINUITY_PROOF"))
+	for _, part := range []string{claim, previousID, currentID, nonce} {
+		binary.Write(h, binary.BigEndian, uint32(len(part)))
+		h.Write([]byte(part))
+	}
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CoSignatureEnvelope records that several of an issuer's key holders, such as departments,
+// approved the issuance of a credential. Each signer signs the envelope's statement, which names
+// the issuer, subject and a salted digest of the issuance request, and the issuer binds the signed
+// envelope to the credential it issues. The envelope is presented with the credential.
+type CoSignatureEnvelope struct {
+	SessionID string `json:"sessionId"`
+	// CredentialID is set by the issuer at issuance; signers sign the statement without it
+	CredentialID string `json:"credentialId,omitempty"`
+	Issuer       string `json:"issuer"`
+	Subject      string `json:"subject"`
+	// RequestDigest is the hex SHA-256 of the salted issuance request, so signers approve its
+	// claims without the envelope revealing them
+	RequestDigest string `json:"requestDigest"`
+	// Threshold of the Signers must sign
+	Threshold  int           `json:"threshold"`
+	Signers    []string      `json:"signers"`
+	Signatures []CoSignature `json:"signatures,omitempty"`
+	// Proof is the issuer's signature binding the envelope to the credential
+	Proof *Proof `json:"proof,omitempty"`
+}
+
+// CoSignature is one signer's Ed25519 signature of an envelope's statement
+type CoSignature struct {
+	Signer             string    `json:"signer"`
+	VerificationMethod string    `json:"verificationMethod"`
+	Created            time.Time `json:"created"`
+	ProofValue         string    `json:"proofValue"`
+}
+
+// Validate checks the envelope's threshold and signer list
+func (e *CoSignatureEnvelope) Validate() error {
+	if e.Threshold < 1 {
+		return fmt.Errorf("co-signature threshold must be at least 1, got %d", e.Threshold)
+	}
+	if len(e.Signers) < e.Threshold {
+		return fmt.Errorf("co-signature threshold %d exceeds the %d signers", e.Threshold, len(e.Signers))
+	}
+	seen := make(map[string]bool, len(e.Signers))
+	for _, signer := range e.Signers {
+		if signer == "" {
+			return fmt.Errorf("co-signer DID cannot be empty")
+		}
+		if seen[signer] {
+			return fmt.Errorf("co-signer %s is listed twice", signer)
+		}
+		seen[signer] = true
+	}
+	return nil
+}
+
+// HasSigner reports whether did is one of the envelope's signers
+func (e *CoSignatureEnvelope) HasSigner(did string) bool {
+	for _, signer := range e.Signers {
+		if signer == did {
+			return true
+		}
+	}
+	return false
+}
+
+// CoSignatureSigningInput returns the statement each co-signer signs: the envelope without the
+// credential ID, signatures and issuer proof
+func CoSignatureSigningInput(envelope *CoSignatureEnvelope) ([]byte, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("co-signature envelope is nil")
+	}
+
+	statement := *envelope
+	statement.CredentialID = ""
+	statement.Signatures = nil
+	statement.Proof = nil
+
+	data, err := json.Marshal(&statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal co-signature statement: %w", err)
+	}
+
+	return data, nil
+}
+
+// CoSignatureBindingInput returns the bytes covered by the issuer's envelope proof
+func CoSignatureBindingInput(envelope *CoSignatureEnvelope) ([]byte, error) {
+	if envelope == nil {
+		return nil, fmt.Errorf("co-signature envelope is nil")
+	}
+
+	unsigned := *envelope
+	if envelope.Proof != nil {
+		proof := *envelope.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal co-signature envelope: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,66 @@
+package vc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoSignatureEnvelope(t *testing.T) {
+	envelope := &CoSignatureEnvelope{
+		SessionID:     "session-1",
+		Issuer:        "did:example:issuer",
+		Subject:       "did:example:holder",
+		RequestDigest: "abcd",
+		Threshold:     2,
+		Signers:       []string{"did:example:hr", "did:example:legal", "did:example:finance"},
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		require.NoError(t, envelope.Validate())
+
+		invalid := []CoSignatureEnvelope{
+			{Threshold: 0, Signers: []string{"did:example:hr"}},
+			{Threshold: 2, Signers: []string{"did:example:hr"}},
+			{Threshold: 1, Signers: []string{"did:example:hr", "did:example:hr"}},
+			{Threshold: 1, Signers: []string{""}},
+		}
+		for _, e := range invalid {
+			assert.Error(t, e.Validate(), e)
+		}
+	})
+
+	t.Run("Statement Excludes Signatures And Binding", func(t *testing.T) {
+		statement, err := CoSignatureSigningInput(envelope)
+		require.NoError(t, err)
+
+		signed := *envelope
+		signed.CredentialID = "urn:uuid:credential"
+		signed.Signatures = []CoSignature{{Signer: "did:example:hr", VerificationMethod: "did:example:hr#key-1", Created: time.Now(), ProofValue: "z1"}}
+		signed.Proof = &Proof{Type: "Ed25519Signature2020", ProofValue: "z2"}
+		after, err := CoSignatureSigningInput(&signed)
+		require.NoError(t, err)
+		assert.Equal(t, statement, after, "signatures added later do not change the statement")
+
+		signed.Threshold = 1
+		changed, err := CoSignatureSigningInput(&signed)
+		require.NoError(t, err)
+		assert.NotEqual(t, statement, changed)
+	})
+
+	t.Run("Binding Covers Signatures", func(t *testing.T) {
+		signed := *envelope
+		signed.CredentialID = "urn:uuid:credential"
+		signed.Proof = &Proof{Type: "Ed25519Signature2020", ProofValue: "z2"}
+		binding, err := CoSignatureBindingInput(&signed)
+		require.NoError(t, err)
+		assert.NotContains(t, string(binding), "z2")
+
+		signed.Signatures = []CoSignature{{Signer: "did:example:hr", ProofValue: "z1"}}
+		withSignature, err := CoSignatureBindingInput(&signed)
+		require.NoError(t, err)
+		assert.NotEqual(t, binding, withSignature)
+	})
+}
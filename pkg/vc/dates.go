@@ -0,0 +1,116 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DatePolicy decides which calendar day date claims and the current time fall on. A value written
+// as a date, or as an RFC 3339 time with an offset, keeps the calendar date it is written with.
+// Instants without a calendar date of their own (epoch seconds, time.Time values, the current
+// time) take their date in Location. The zero policy uses UTC.
+type DatePolicy struct {
+	Location *time.Location
+}
+
+// NewDatePolicy returns the policy for an IANA timezone name, e.g. Europe/Berlin; empty means UTC
+func NewDatePolicy(timezone string) (DatePolicy, error) {
+	if timezone == "" {
+		return DatePolicy{}, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return DatePolicy{}, fmt.Errorf("unknown timezone %q: %w", timezone, err)
+	}
+	return DatePolicy{Location: location}, nil
+}
+
+func (p DatePolicy) location() *time.Location {
+	if p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
+
+// dateOf returns the calendar date of t, as midnight UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseDate reads a date claim and returns its calendar date as midnight UTC. It accepts
+// YYYY-MM-DD, RFC 3339 times, times without an offset (taken in Location), Unix epoch seconds as
+// a number, and time.Time values.
+func (p DatePolicy) ParseDate(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return dateOf(v.In(p.location())), nil
+	case string:
+		return p.parseDateString(v)
+	case json.Number:
+		seconds, err := v.Float64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a date", v.String())
+		}
+		return p.fromEpoch(seconds)
+	case int:
+		return p.fromEpoch(float64(v))
+	case int64:
+		return p.fromEpoch(float64(v))
+	case float64:
+		return p.fromEpoch(v)
+	default:
+		return time.Time{}, fmt.Errorf("cannot use %T as date", value)
+	}
+}
+
+func (p DatePolicy) parseDateString(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(DateLayout, s); err == nil {
+		return t, nil
+	}
+	// The offset states where the time was written, so the date is the one written
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return dateOf(t), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", s, p.location()); err == nil {
+		return dateOf(t), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (expected YYYY-MM-DD, an RFC 3339 time or epoch seconds)", s)
+}
+
+func (p DatePolicy) fromEpoch(seconds float64) (time.Time, error) {
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) || math.Abs(seconds) > 1e12 {
+		return time.Time{}, fmt.Errorf("epoch seconds %v out of range", seconds)
+	}
+	whole, fraction := math.Modf(seconds)
+	return dateOf(time.Unix(int64(whole), int64(fraction*1e9)).In(p.location())), nil
+}
+
+// Today returns the calendar date now falls on in Location, as midnight UTC
+func (p DatePolicy) Today(now time.Time) time.Time {
+	return dateOf(now.In(p.location()))
+}
+
+// AgeAt returns the age in whole years on the day now falls on. Someone born on 29 February
+// turns a year older on 1 March in common years.
+func (p DatePolicy) AgeAt(birthDate, now time.Time) int {
+	today := p.Today(now)
+	birthDate = dateOf(birthDate)
+
+	age := today.Year() - birthDate.Year()
+	month, day := birthDate.Month(), birthDate.Day()
+	if month == time.February && day == 29 && !isLeapYear(today.Year()) {
+		month, day = time.March, 1
+	}
+	if today.Month() < month || (today.Month() == month && today.Day() < day) {
+		age--
+	}
+	return age
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,107 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	tokyo, err := NewDatePolicy("Asia/Tokyo")
+	require.NoError(t, err)
+	utc := DatePolicy{}
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		policy   DatePolicy
+		value    interface{}
+		expected time.Time
+	}{
+		{"Date Only", utc, "2000-02-29", date(2000, time.February, 29)},
+		{"Date With Whitespace", utc, " 2000-01-20 ", date(2000, time.January, 20)},
+		{"RFC3339 Keeps Written Date", utc, "2000-01-20T23:00:00-05:00", date(2000, time.January, 20)},
+		{"RFC3339 Keeps Written Date Under Other Policy", tokyo, "2000-01-20T23:00:00Z", date(2000, time.January, 20)},
+		{"Local Time Without Offset", tokyo, "2000-01-20T23:30:00", date(2000, time.January, 20)},
+		{"Epoch Seconds", utc, float64(951782400), date(2000, time.February, 29)},
+		{"Epoch Seconds As JSON Number", utc, json.Number("951782400"), date(2000, time.February, 29)},
+		{"Negative Epoch", utc, -86400, date(1969, time.December, 31)},
+		{"Epoch In Policy Timezone", tokyo, int64(951836400), date(2000, time.March, 1)},
+		{"Time Value In Policy Timezone", tokyo, time.Date(2000, time.January, 20, 20, 0, 0, 0, time.UTC), date(2000, time.January, 21)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := tt.policy.ParseDate(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed)
+		})
+	}
+
+	t.Run("Invalid Values", func(t *testing.T) {
+		for _, value := range []interface{}{"20/01/2000", "2001-02-29", "", true, 1e15, json.Number("abc")} {
+			_, err := utc.ParseDate(value)
+			assert.Error(t, err, "%v", value)
+		}
+	})
+
+	t.Run("Unknown Timezone", func(t *testing.T) {
+		_, err := NewDatePolicy("Mars/Olympus")
+		assert.Error(t, err)
+	})
+}
+
+func TestAgeAt(t *testing.T) {
+	utc := DatePolicy{}
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	birth := func(value string) time.Time {
+		parsed, err := utc.ParseDate(value)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		policy    DatePolicy
+		birthDate string
+		now       time.Time
+		expected  int
+	}{
+		{"Birthday Today", utc, "2007-10-16", at(2025, time.October, 16, 0), 18},
+		{"Day Before Birthday", utc, "2007-10-16", at(2025, time.October, 15, 23), 17},
+		{"Leap Day Birth In Leap Year", utc, "2008-02-29", at(2024, time.February, 29, 12), 16},
+		{"Leap Day Birth Before March In Common Year", utc, "2008-02-29", at(2025, time.February, 28, 12), 16},
+		{"Leap Day Birth On March First In Common Year", utc, "2008-02-29", at(2025, time.March, 1, 0), 17},
+		{"March Birth In Leap Year", utc, "2007-03-01", at(2024, time.February, 29, 12), 16},
+		{"March Birth On Day After Leap Day", utc, "2007-03-01", at(2025, time.March, 1, 12), 18},
+		{"Late December", utc, "2007-12-31", at(2025, time.December, 31, 0), 18},
+		{"Born Today", utc, "2025-06-15", at(2025, time.June, 15, 8), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.policy.AgeAt(birth(tt.birthDate), tt.now))
+		})
+	}
+
+	t.Run("Current Date Follows Policy Timezone", func(t *testing.T) {
+		// 20:00 UTC on the 15th is already the 16th in Tokyo and still the 15th in New York
+		now := at(2025, time.October, 15, 20)
+		tokyo, err := NewDatePolicy("Asia/Tokyo")
+		require.NoError(t, err)
+		newYork, err := NewDatePolicy("America/New_York")
+		require.NoError(t, err)
+
+		assert.Equal(t, 18, tokyo.AgeAt(birth("2007-10-16"), now))
+		assert.Equal(t, 17, newYork.AgeAt(birth("2007-10-16"), now))
+		assert.Equal(t, 17, utc.AgeAt(birth("2007-10-16"), now))
+	})
+}
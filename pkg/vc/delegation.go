@@ -0,0 +1,147 @@
+package vc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// AuthorizedIssuerCredentialType marks credentials by which an issuer authorizes another to issue
+	AuthorizedIssuerCredentialType = "AuthorizedIssuerCredential"
+	// AuthorizedIssuerTemplateID is the built-in template authorizations are issued from
+	AuthorizedIssuerTemplateID = "authorized-issuer"
+	// AuthorizedTypesClaim lists, comma-separated, the credential types the delegate may issue;
+	// without it the delegate may issue any type
+	AuthorizedTypesClaim = "authorizedCredentialTypes"
+	// MayDelegateClaim is true when the delegate may authorize sub-issuers of its own
+	MayDelegateClaim = "mayDelegate"
+	// MaxDelegationDepth bounds the number of authorizations between an issuer and a root
+	MaxDelegationDepth = 8
+)
+
+// Delegation is an authorization read from an AuthorizedIssuerCredential: Delegator authorizes
+// Delegate, the credential's subject, to issue credentials
+type Delegation struct {
+	Credential *VerifiableCredential
+	Delegator  string
+	Delegate   string
+	// CredentialTypes are the types the delegate may issue; empty means any
+	CredentialTypes []string
+	MayDelegate     bool
+}
+
+// ParseDelegation reads the authorization an AuthorizedIssuerCredential grants
+func ParseDelegation(credential *VerifiableCredential) (*Delegation, error) {
+	if credential == nil {
+		return nil, fmt.Errorf("authorization is nil")
+	}
+	if !containsString(credential.Type, AuthorizedIssuerCredentialType) {
+		return nil, fmt.Errorf("credential %s is not an %s", credential.ID, AuthorizedIssuerCredentialType)
+	}
+
+	delegate, _ := credential.CredentialSubject["id"].(string)
+	if delegate == "" {
+		return nil, fmt.Errorf("authorization %s names no delegate", credential.ID)
+	}
+
+	delegation := &Delegation{
+		Credential: credential,
+		Delegator:  credential.Issuer(),
+		Delegate:   delegate,
+	}
+	if types, ok := credential.CredentialSubject[AuthorizedTypesClaim].(string); ok && types != "" {
+		for _, t := range strings.Split(types, ",") {
+			delegation.CredentialTypes = append(delegation.CredentialTypes, strings.TrimSpace(t))
+		}
+	}
+	delegation.MayDelegate, _ = credential.CredentialSubject[MayDelegateClaim].(bool)
+	return delegation, nil
+}
+
+// Authorizes reports whether the delegate may issue a credential of the given types. The base
+// VerifiableCredential type needs no authorization.
+func (d *Delegation) Authorizes(credentialTypes []string) bool {
+	if len(d.CredentialTypes) == 0 {
+		return true
+	}
+	for _, t := range credentialTypes {
+		if t != "VerifiableCredential" && !containsString(d.CredentialTypes, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildDelegationChain finds a chain of authorizations from issuerDID to one of the roots, trying
+// the authorizations in any order. Each authorization must permit the credential types, each one
+// above the first must allow further delegation, and accept must not reject it, e.g. because it was
+// revoked. The chain is returned starting with the authorization of issuerDID.
+func BuildDelegationChain(issuerDID string, credentialTypes []string, authorizations []*VerifiableCredential, roots []string, accept func(*Delegation) error) ([]*Delegation, error) {
+	var delegations []*Delegation
+	var problems []string
+	for _, credential := range authorizations {
+		delegation, err := ParseDelegation(credential)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		delegations = append(delegations, delegation)
+	}
+
+	// Whether an authorization may be used depends only on its own delegate, not on the path that
+	// reached it, so a breadth-first search visiting each DID once finds the shortest chain
+	via := make(map[string]*Delegation)
+	visited := map[string]bool{issuerDID: true}
+	frontier := []string{issuerDID}
+	for depth := 0; depth < MaxDelegationDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, current := range frontier {
+			for _, delegation := range delegations {
+				if delegation.Delegate != current || visited[delegation.Delegator] {
+					continue
+				}
+				if current != issuerDID && !delegation.MayDelegate {
+					problems = append(problems, fmt.Sprintf("%s may not authorize sub-issuers", current))
+					continue
+				}
+				if !delegation.Authorizes(credentialTypes) {
+					problems = append(problems, fmt.Sprintf("%s is not authorized to issue %v", current, credentialTypes))
+					continue
+				}
+				if err := accept(delegation); err != nil {
+					problems = append(problems, fmt.Sprintf("authorization of %s by %s: %v", current, delegation.Delegator, err))
+					continue
+				}
+
+				visited[delegation.Delegator] = true
+				via[delegation.Delegator] = delegation
+				if containsString(roots, delegation.Delegator) {
+					return delegationPath(via, issuerDID, delegation.Delegator), nil
+				}
+				next = append(next, delegation.Delegator)
+			}
+		}
+		frontier = next
+	}
+
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("no authorization of %s leads to a trusted root", issuerDID)
+	}
+	return nil, fmt.Errorf("no authorization of %s leads to a trusted root: %s", issuerDID, strings.Join(problems, "; "))
+}
+
+// delegationPath follows the authorizations the search took back from root to issuerDID
+func delegationPath(via map[string]*Delegation, issuerDID, root string) []*Delegation {
+	var reversed []*Delegation
+	for did := root; did != issuerDID; {
+		delegation := via[did]
+		reversed = append(reversed, delegation)
+		did = delegation.Delegate
+	}
+
+	chain := make([]*Delegation, len(reversed))
+	for i, delegation := range reversed {
+		chain[len(reversed)-1-i] = delegation
+	}
+	return chain
+}
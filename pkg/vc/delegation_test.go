@@ -0,0 +1,87 @@
+package vc
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func authorization(id, delegator, delegate string, mayDelegate bool, types string) *VerifiableCredential {
+	subject := map[string]interface{}{"id": delegate, MayDelegateClaim: mayDelegate}
+	if types != "" {
+		subject[AuthorizedTypesClaim] = types
+	}
+	return &VerifiableCredential{
+		ID:                id,
+		Type:              []string{"VerifiableCredential", AuthorizedIssuerCredentialType},
+		IssuerInfo:        Issuer{ID: delegator},
+		CredentialSubject: subject,
+	}
+}
+
+func TestBuildDelegationChain(t *testing.T) {
+	acceptAll := func(*Delegation) error { return nil }
+	types := []string{"VerifiableCredential", "UniversityDegreeCredential"}
+	roots := []string{"did:example:ministry"}
+
+	rootToUniversity := authorization("urn:a1", "did:example:ministry", "did:example:university", true, "")
+	universityToFaculty := authorization("urn:a2", "did:example:university", "did:example:faculty", false, "UniversityDegreeCredential")
+
+	t.Run("Multi-Level Chain", func(t *testing.T) {
+		chain, err := BuildDelegationChain("did:example:faculty", types, []*VerifiableCredential{rootToUniversity, universityToFaculty}, roots, acceptAll)
+		require.NoError(t, err)
+		require.Len(t, chain, 2)
+		assert.Equal(t, "did:example:university", chain[0].Delegator)
+		assert.Equal(t, "did:example:ministry", chain[1].Delegator)
+	})
+
+	t.Run("Intermediate Must Be Allowed To Delegate", func(t *testing.T) {
+		noDelegation := authorization("urn:a1", "did:example:ministry", "did:example:university", false, "")
+		_, err := BuildDelegationChain("did:example:faculty", types, []*VerifiableCredential{noDelegation, universityToFaculty}, roots, acceptAll)
+		assert.ErrorContains(t, err, "may not authorize")
+	})
+
+	t.Run("Types Are Restricted", func(t *testing.T) {
+		_, err := BuildDelegationChain("did:example:faculty", []string{"VerifiableCredential", "DriverLicense"}, []*VerifiableCredential{rootToUniversity, universityToFaculty}, roots, acceptAll)
+		assert.ErrorContains(t, err, "not authorized to issue")
+	})
+
+	t.Run("Rejected Authorization Breaks The Chain", func(t *testing.T) {
+		revoked := func(d *Delegation) error {
+			if d.Credential.ID == "urn:a1" {
+				return fmt.Errorf("credential has been revoked")
+			}
+			return nil
+		}
+		_, err := BuildDelegationChain("did:example:faculty", types, []*VerifiableCredential{rootToUniversity, universityToFaculty}, roots, revoked)
+		assert.ErrorContains(t, err, "revoked")
+	})
+
+	t.Run("Alternative Path Is Found", func(t *testing.T) {
+		direct := authorization("urn:a3", "did:example:ministry", "did:example:faculty", false, "")
+		revoked := func(d *Delegation) error {
+			if d.Credential.ID == "urn:a2" {
+				return fmt.Errorf("credential has been revoked")
+			}
+			return nil
+		}
+		chain, err := BuildDelegationChain("did:example:faculty", types, []*VerifiableCredential{rootToUniversity, universityToFaculty, direct}, roots, revoked)
+		require.NoError(t, err)
+		require.Len(t, chain, 1)
+		assert.Equal(t, "urn:a3", chain[0].Credential.ID)
+	})
+
+	t.Run("Cycles Terminate", func(t *testing.T) {
+		a := authorization("urn:c1", "did:example:b", "did:example:a", true, "")
+		b := authorization("urn:c2", "did:example:a", "did:example:b", true, "")
+		_, err := BuildDelegationChain("did:example:a", types, []*VerifiableCredential{a, b}, roots, acceptAll)
+		assert.Error(t, err)
+	})
+
+	t.Run("No Authorizations", func(t *testing.T) {
+		_, err := BuildDelegationChain("did:example:faculty", types, nil, roots, acceptAll)
+		assert.Error(t, err)
+	})
+}
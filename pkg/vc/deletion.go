@@ -0,0 +1,39 @@
+package vc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// ErrCredentialNotFound is returned for a credential that was never stored, or whose deleted
+// copy has been purged
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// ErrCredentialDeleted is returned for a credential that was deleted
+var ErrCredentialDeleted = errors.New("credential deleted")
+
+// DeletedCredentialError reports a deleted credential with the tombstone recording its deletion
+type DeletedCredentialError struct {
+	ID        string
+	Tombstone storage.Tombstone
+}
+
+// Error implements error
+func (e *DeletedCredentialError) Error() string {
+	if e.Tombstone.Reason == "" {
+		return fmt.Sprintf("%s: %s", ErrCredentialDeleted, e.ID)
+	}
+	return fmt.Sprintf("%s: %s (%s)", ErrCredentialDeleted, e.ID, e.Tombstone.Reason)
+}
+
+// Is matches ErrCredentialDeleted
+func (e *DeletedCredentialError) Is(target error) bool {
+	return target == ErrCredentialDeleted
+}
+
+// credentialNotFound returns the error for a credential that is not stored
+func credentialNotFound(id string) error {
+	return fmt.Errorf("%w: %s", ErrCredentialNotFound, id)
+}
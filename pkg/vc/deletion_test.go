@@ -0,0 +1,62 @@
+package vc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+func TestCredentialDeletion(t *testing.T) {
+	clk := clock.NewMock(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	repositories := map[string]CredentialRepository{
+		"In Memory": NewInMemoryCredentialRepository(),
+		"KV":        NewKVCredentialRepositoryWithRetention(storage.NewMemoryStoreWithClock(clk), time.Hour),
+	}
+
+	for name, repo := range repositories {
+		t.Run(name, func(t *testing.T) {
+			kept := &VerifiableCredential{ID: "urn:uuid:kept", CredentialSubject: map[string]interface{}{"id": "did:example:holder"}}
+			deleted := &VerifiableCredential{ID: "urn:uuid:deleted", CredentialSubject: map[string]interface{}{"id": "did:example:holder"}}
+			require.NoError(t, repo.Store(kept))
+			require.NoError(t, repo.Store(deleted))
+
+			require.NoError(t, repo.Delete(deleted.ID, storage.Tombstone{DeletedBy: "did:example:holder", Reason: "lost device"}))
+
+			_, err := repo.Retrieve(deleted.ID)
+			assert.ErrorIs(t, err, ErrCredentialDeleted)
+			assert.NotErrorIs(t, err, ErrCredentialNotFound)
+			var tombstoned *DeletedCredentialError
+			require.ErrorAs(t, err, &tombstoned)
+			assert.Equal(t, "did:example:holder", tombstoned.Tombstone.DeletedBy)
+			assert.Equal(t, "lost device", tombstoned.Tombstone.Reason)
+			assert.False(t, tombstoned.Tombstone.DeletedAt.IsZero())
+
+			_, err = repo.Retrieve("urn:uuid:unknown")
+			assert.ErrorIs(t, err, ErrCredentialNotFound)
+
+			listed, err := repo.List("did:example:holder")
+			require.NoError(t, err)
+			require.Len(t, listed, 1)
+			assert.Equal(t, kept.ID, listed[0].ID)
+
+			assert.ErrorIs(t, repo.Store(deleted), ErrCredentialDeleted, "a deleted credential is not stored again")
+			assert.ErrorIs(t, repo.Delete(deleted.ID, storage.Tombstone{}), ErrCredentialDeleted)
+			assert.ErrorIs(t, repo.Delete("urn:uuid:unknown", storage.Tombstone{}), ErrCredentialNotFound)
+		})
+	}
+
+	t.Run("Purged After Retention", func(t *testing.T) {
+		repo := repositories["KV"]
+		clk.Advance(2 * time.Hour)
+
+		_, err := repo.Retrieve("urn:uuid:deleted")
+		assert.ErrorIs(t, err, ErrCredentialNotFound)
+		_, err = repo.Retrieve("urn:uuid:kept")
+		assert.NoError(t, err, "retention applies to deleted credentials only")
+	})
+}
@@ -0,0 +1,290 @@
+package vc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+// ProofNonce returns the nonce a derived BBS+ proof is bound to: the SHA-256 digest of the
+// presentation nonce. The presentation nonce must itself meet bbs.ValidateNonce, since hashing a
+// guessable nonce does not make it any harder to guess.
+func ProofNonce(nonce string) ([]byte, error) {
+	if err := bbs.ValidateNonce([]byte(nonce)); err != nil {
+		return nil, fmt.Errorf("presentation nonce: %w", err)
+	}
+	digest := sha256.Sum256([]byte(nonce))
+	return digest[:], nil
+}
+
+// SignedMessages rebuilds the messages a credential's BBS+ signature covers: the claim manifest,
+// then each claim at the index the manifest records, then the credential metadata
+func SignedMessages(credential *VerifiableCredential) ([][]byte, error) {
+	manifest := credential.ClaimManifest
+	if manifest == nil {
+		return nil, fmt.Errorf("credential has no claim manifest")
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+
+	manifestMessage, err := manifest.Message()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := manifest.Keys()
+	var claimMessages [][]byte
+	if len(credential.AttributeSalts) > 0 {
+		claimMessages, err = SaltedClaimMessages(credential.CredentialSubject, credential.AttributeSalts, keys)
+	} else {
+		claimMessages, err = ClaimMessages(credential.CredentialSubject, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := credential.Metadata()
+	if err != nil {
+		return nil, err
+	}
+	metadataMessage, err := metadata.Message()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := append([][]byte{manifestMessage}, claimMessages...)
+	return append(messages, metadataMessage), nil
+}
+
+// ProofPrecomputer is implemented by credential services that can blind a credential's
+// signature ahead of a presentation
+type ProofPrecomputer interface {
+	PrecomputeProof(credential *VerifiableCredential, publicKey []byte) (*bbs.ProofTemplate, error)
+}
+
+// PrecomputeProof checks the credential's signature and blinds it, so a later presentation of
+// the credential only binds the revealed claims and the nonce
+func (s *ServiceImpl) PrecomputeProof(credential *VerifiableCredential, publicKey []byte) (*bbs.ProofTemplate, error) {
+	precomputer, ok := s.bbsService.(bbs.ProofPrecomputer)
+	if !ok {
+		return nil, fmt.Errorf("BBS+ service cannot precompute proofs")
+	}
+	if credential.Proof == nil {
+		return nil, fmt.Errorf("credential has no proof")
+	}
+
+	signature, messages, err := s.signedCredential(credential, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return precomputer.PrecomputeProof(signature, publicKey, messages)
+}
+
+// signedCredential decodes the credential's signature and checks it covers the signed messages
+func (s *ServiceImpl) signedCredential(credential *VerifiableCredential, publicKey []byte) (*bbs.Signature, [][]byte, error) {
+	signature, err := bbs.DecodeSignature(credential.Proof.ProofValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages, err := SignedMessages(credential)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A proof derived from messages the signature does not cover would fail verification unnoticed
+	if err := s.bbsService.Verify(publicKey, signature, messages); err != nil {
+		return nil, nil, fmt.Errorf("credential signature does not cover its claim manifest, claims and metadata: %w", err)
+	}
+	return signature, messages, nil
+}
+
+// deriveProof creates a BBS+ proof from the credential's signature that reveals only the
+// disclosed claims and the credential metadata, encoded as a proofValue. It returns the disclosed
+// claims in the order of the messages it reveals. A matching template, whose signature was
+// checked when it was computed, is completed in place of a fresh proof. With setKeys, the proof
+// is made under a fresh randomization of the issuer's key and returned with the proof that the
+// key is one of setKeys.
+func (s *ServiceImpl) deriveProof(credential *VerifiableCredential, disclosed []string, publicKey []byte, setKeys [][]byte, nonce string, template *bbs.ProofTemplate) (string, *bbs.SetMembershipProof, []string, error) {
+	messages, err := SignedMessages(credential)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	revealed := make([]int, 0, len(disclosed))
+	keyAt := make(map[int]string, len(disclosed))
+	for _, key := range disclosed {
+		index, ok := credential.ClaimManifest.Index(key)
+		if !ok {
+			return "", nil, nil, fmt.Errorf("claim %s is not covered by the credential signature", key)
+		}
+		revealed = append(revealed, index)
+		keyAt[index] = key
+	}
+	sort.Ints(revealed)
+	ordered := make([]string, len(revealed))
+	for i, index := range revealed {
+		ordered[i] = keyAt[index]
+	}
+	// The metadata is the last message, so it stays after the claims
+	revealed = append(revealed, len(messages)-1)
+
+	proofNonce, err := ProofNonce(nonce)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	// Templates are blinded under the issuer's own key, which a hidden issuer's proof cannot use
+	precomputer, ok := s.bbsService.(bbs.ProofPrecomputer)
+	if ok && setKeys == nil && template != nil && bytes.Equal(template.PublicKey, publicKey) && template.Matches(messages) {
+		proof, err := precomputer.CompleteProof(template, messages, revealed, proofNonce)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("failed to derive proof: %w", err)
+		}
+		return bbs.EncodeProof(proof), nil, ordered, nil
+	}
+
+	signature, messages, err := s.signedCredential(credential, publicKey)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if setKeys != nil {
+		prover, ok := s.bbsService.(bbs.SetProver)
+		if !ok {
+			return "", nil, nil, fmt.Errorf("BBS+ service cannot hide the issuer")
+		}
+		proof, membership, err := prover.CreateSetProof(signature, publicKey, setKeys, messages, revealed, proofNonce)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("failed to derive proof: %w", err)
+		}
+		return bbs.EncodeProof(proof), membership, ordered, nil
+	}
+
+	proof, err := s.bbsService.CreateProof(signature, publicKey, messages, revealed, proofNonce)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to derive proof: %w", err)
+	}
+	return bbs.EncodeProof(proof), nil, ordered, nil
+}
+
+// DerivedProofVerifier is implemented by credential services that can check the BBS+ proof of a
+// derived credential, under the issuer's key or under one of the keys of its issuer set
+type DerivedProofVerifier interface {
+	VerifyDerivedProof(derived *DerivedCredential, publicKey []byte) error
+	VerifyDerivedSetProof(derived *DerivedCredential, setKeys [][]byte) error
+}
+
+// VerifyDerivedProof checks that a derived credential's proofValue proves, under the issuer's
+// public key, every claim its subject reveals besides the id, with the values as presented, and
+// the credential metadata as presented
+func (s *ServiceImpl) VerifyDerivedProof(derived *DerivedCredential, publicKey []byte) error {
+	proof, messages, proofNonce, err := derivedProofInputs(derived)
+	if err != nil {
+		return err
+	}
+	if err := s.bbsService.VerifyProof(publicKey, proof, messages, proofNonce); err != nil {
+		return fmt.Errorf("BBS+ proof does not match the revealed claims and metadata: %w", err)
+	}
+	return nil
+}
+
+// VerifyDerivedSetProof checks a derived credential presented with a hidden issuer as
+// VerifyDerivedProof does, under whichever of setKeys the issuer set's membership proof shows
+// the proof was made with
+func (s *ServiceImpl) VerifyDerivedSetProof(derived *DerivedCredential, setKeys [][]byte) error {
+	if derived.IssuerSetProof == nil || derived.IssuerSetProof.ProofValue == nil {
+		return fmt.Errorf("issuer set has no membership proof")
+	}
+	prover, ok := s.bbsService.(bbs.SetProver)
+	if !ok {
+		return fmt.Errorf("BBS+ service cannot verify proofs of a hidden issuer")
+	}
+
+	proof, messages, proofNonce, err := derivedProofInputs(derived)
+	if err != nil {
+		return err
+	}
+	if err := prover.VerifySetProof(setKeys, proof, derived.IssuerSetProof.ProofValue, messages, proofNonce); err != nil {
+		return fmt.Errorf("BBS+ proof does not match the revealed claims and metadata under the issuer set: %w", err)
+	}
+	return nil
+}
+
+// derivedProofInputs decodes a derived credential's proof and rebuilds the messages and nonce it
+// must prove: the disclosed claims, which must be every claim revealed besides the id, and the
+// credential metadata
+func derivedProofInputs(derived *DerivedCredential) (*bbs.Proof, [][]byte, []byte, error) {
+	if derived.Proof == nil {
+		return nil, nil, nil, fmt.Errorf("missing proof")
+	}
+	if derived.Proof.ProofValue == "" {
+		return nil, nil, nil, fmt.Errorf("proof has no proofValue")
+	}
+	proof, err := bbs.DecodeProof(derived.Proof.ProofValue)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	subject := derived.CredentialSubject
+	if subject == nil {
+		return nil, nil, nil, fmt.Errorf("missing credential subject")
+	}
+
+	// The disclosed claims must be exactly the revealed ones, so no claim is presented unproven
+	keys := derived.Proof.DisclosedClaims
+	if keys == nil {
+		return nil, nil, nil, fmt.Errorf("proof does not list its disclosed claims")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			return nil, nil, nil, fmt.Errorf("invalid disclosed claim %v", key)
+		}
+		if _, revealed := subject[key]; !revealed {
+			return nil, nil, nil, fmt.Errorf("disclosed claim %s is not in the credential subject", key)
+		}
+		seen[key] = true
+	}
+	for key := range subject {
+		if key != "id" && !seen[key] {
+			return nil, nil, nil, fmt.Errorf("claim %s is revealed without proof", key)
+		}
+	}
+	if len(keys)+1 != len(proof.RevealedAttributes) {
+		return nil, nil, nil, fmt.Errorf("proof reveals %d messages for %d disclosed claims and the credential metadata", len(proof.RevealedAttributes), len(keys))
+	}
+
+	var messages [][]byte
+	if derived.AttributeSalts != nil {
+		if err := CheckDisclosedSalts(derived.AttributeSalts); err != nil {
+			return nil, nil, nil, fmt.Errorf("attribute salts: %w", err)
+		}
+		messages, err = SaltedClaimMessages(subject, derived.AttributeSalts, keys)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	} else if messages, err = ClaimMessages(subject, keys); err != nil {
+		return nil, nil, nil, err
+	}
+
+	metadata, err := derived.Metadata()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	metadataMessage, err := metadata.Message()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	messages = append(messages, metadataMessage)
+
+	proofNonce, err := ProofNonce(derived.Proof.Nonce)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return proof, messages, proofNonce, nil
+}
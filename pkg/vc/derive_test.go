@@ -0,0 +1,274 @@
+package vc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+func TestDeriveProof(t *testing.T) {
+	bbsService := bbs.NewService()
+	service := NewService(bbsService, NewInMemoryCredentialRepository(), NewInMemoryPresentationRepository())
+
+	keyPair, err := bbsService.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, service.SetIssuerKeyPair("did:example:issuer", keyPair))
+
+	issued, err := service.IssueCredential("did:example:issuer", "did:example:holder", []Claim{
+		{Key: "fullName", Value: "An Nguyen"},
+		{Key: "age", Value: 25},
+		{Key: "ageOver18", Value: true},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, issued.ClaimManifest)
+	assert.Equal(t, []string{"fullName", "age", "ageOver18"}, issued.ClaimManifest.Keys())
+
+	// Round-trip through JSON as a stored credential would be
+	data, err := json.Marshal(issued)
+	require.NoError(t, err)
+	var credential VerifiableCredential
+	require.NoError(t, json.Unmarshal(data, &credential))
+
+	t.Run("Signature Covers The Rebuilt Messages", func(t *testing.T) {
+		signature, err := bbs.DecodeSignature(credential.Proof.ProofValue)
+		require.NoError(t, err)
+		messages, err := SignedMessages(&credential)
+		require.NoError(t, err)
+		assert.NoError(t, bbsService.Verify(keyPair.PublicKey, signature, messages))
+	})
+
+	t.Run("Derived Proof Reveals The Disclosed Claims", func(t *testing.T) {
+		presentation, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{&credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18", "fullName"}, Nonce: "derive-nonce-1a8cf5d9", IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.NoError(t, err)
+
+		derived := presentation.VerifiableCredential[0]
+		proof, err := bbs.DecodeProof(derived.Proof.ProofValue)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3, 4}, proof.RevealedAttributes, "message 0 is the manifest and message 4 the metadata")
+
+		messages, err := SignedMessages(&credential)
+		require.NoError(t, err)
+		revealed := [][]byte{messages[1], messages[3], messages[4]}
+		proofNonce, err := ProofNonce("derive-nonce-1a8cf5d9")
+		require.NoError(t, err)
+		assert.NoError(t, bbsService.VerifyProof(keyPair.PublicKey, proof, revealed, proofNonce))
+	})
+
+	t.Run("Guessable Nonce Is Rejected", func(t *testing.T) {
+		_, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{&credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"fullName"}, Nonce: "anonymity-nonce", IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nonce is too short")
+
+		_, err = ProofNonce("aaaaaaaaaaaaaaaaaaaaaaaa")
+		assert.Error(t, err)
+	})
+
+	t.Run("No Proof Value Without The Issuer Key", func(t *testing.T) {
+		presentation, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{&credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"fullName"}},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, presentation.VerifiableCredential[0].Proof.ProofValue)
+	})
+
+	t.Run("Reordered Manifest Is Detected", func(t *testing.T) {
+		tampered := credential
+		manifest := NewClaimManifest([]string{"age", "fullName", "ageOver18"})
+		tampered.ClaimManifest = manifest
+
+		_, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{&tampered}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"fullName"}, IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not cover its claim manifest")
+	})
+
+	t.Run("Altered Metadata Is Detected", func(t *testing.T) {
+		retyped := credential
+		retyped.Type = append([]string{}, credential.Type...)
+		retyped.Type = append(retyped.Type, "NationalIDCredential")
+
+		renamed := credential
+		renamed.IssuerInfo.Name = "Ministry of Public Security"
+
+		for _, tampered := range []VerifiableCredential{retyped, renamed} {
+			_, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{&tampered}, []SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"fullName"}, IssuerPublicKey: keyPair.PublicKey},
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "does not cover its claim manifest")
+		}
+	})
+
+	t.Run("Credentials Without Manifest", func(t *testing.T) {
+		legacy := credential
+		legacy.ClaimManifest = nil
+		_, err := SignedMessages(&legacy)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeriveProofWithSimpleProvider(t *testing.T) {
+	// The simple provider's mock crypto fails like the real one, without the pairing cost
+	bbsService, err := bbs.NewSimpleBBSService()
+	require.NoError(t, err)
+	service := NewService(bbsService, NewInMemoryCredentialRepository(), NewInMemoryPresentationRepository())
+
+	keyPair, err := bbsService.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, service.SetIssuerKeyPair("did:example:issuer", keyPair))
+
+	credential, err := service.IssueCredential("did:example:issuer", "did:example:holder", []Claim{
+		{Key: "fullName", Value: "An Nguyen"},
+		{Key: "age", Value: 25},
+	})
+	require.NoError(t, err)
+
+	t.Run("Valid Credential", func(t *testing.T) {
+		presentation, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"age"}, Nonce: "simple-nonce-511e7e1a", IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.NoError(t, err)
+
+		proof, err := bbs.DecodeProof(presentation.VerifiableCredential[0].Proof.ProofValue)
+		require.NoError(t, err)
+		messages, err := SignedMessages(credential)
+		require.NoError(t, err)
+		proofNonce, err := ProofNonce("simple-nonce-511e7e1a")
+		require.NoError(t, err)
+		assert.NoError(t, bbsService.VerifyProof(keyPair.PublicKey, proof, [][]byte{messages[2], messages[3]}, proofNonce))
+		assert.Error(t, bbsService.VerifyProof(keyPair.PublicKey, proof, [][]byte{messages[1], messages[3]}, proofNonce))
+	})
+
+	t.Run("Tampered Claim", func(t *testing.T) {
+		tampered := *credential
+		tampered.CredentialSubject = map[string]interface{}{}
+		for key, value := range credential.CredentialSubject {
+			tampered.CredentialSubject[key] = value
+		}
+		tampered.CredentialSubject["age"] = 17
+
+		_, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{&tampered}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"age"}, IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not cover its claim manifest")
+	})
+
+	t.Run("Wrong Issuer Key", func(t *testing.T) {
+		other, err := bbsService.GenerateKeyPair()
+		require.NoError(t, err)
+		_, err = service.CreatePresentation("did:example:holder", []*VerifiableCredential{credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"age"}, IssuerPublicKey: other.PublicKey},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyDerivedProof(t *testing.T) {
+	bbsService := bbs.NewService()
+	service := NewService(bbsService, NewInMemoryCredentialRepository(), NewInMemoryPresentationRepository()).(*ServiceImpl)
+
+	keyPair, err := bbsService.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, service.SetIssuerKeyPair("did:example:issuer", keyPair))
+
+	credential, err := service.IssueCredential("did:example:issuer", "did:example:holder", []Claim{
+		{Key: "fullName", Value: "An Nguyen"},
+		{Key: "age", Value: 25},
+		{Key: "ageOver18", Value: true},
+	})
+	require.NoError(t, err)
+
+	// derive presents the credential and decodes the derived credential as a verifier receives it
+	derive := func(t *testing.T, revealed ...string) *DerivedCredential {
+		presentation, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{credential}, []SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: revealed, Nonce: "verify-nonce-89f68b18", IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(presentation.VerifiableCredential[0])
+		require.NoError(t, err)
+		var derived DerivedCredential
+		require.NoError(t, json.Unmarshal(data, &derived))
+		return &derived
+	}
+
+	t.Run("Revealed Claims Verify", func(t *testing.T) {
+		// Requested out of signing order, disclosed in it
+		derived := derive(t, "ageOver18", "fullName")
+		assert.Equal(t, []string{"fullName", "ageOver18"}, derived.Proof.DisclosedClaims)
+		assert.NoError(t, service.VerifyDerivedProof(derived, keyPair.PublicKey))
+	})
+
+	t.Run("Altered Claim", func(t *testing.T) {
+		derived := derive(t, "age")
+		derived.CredentialSubject["age"] = 26
+		err := service.VerifyDerivedProof(derived, keyPair.PublicKey)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BBS+ proof does not match")
+	})
+
+	t.Run("Unproven Claim", func(t *testing.T) {
+		derived := derive(t, "age")
+		derived.CredentialSubject["ageOver18"] = true
+		err := service.VerifyDerivedProof(derived, keyPair.PublicKey)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "revealed without proof")
+	})
+
+	t.Run("Altered Metadata", func(t *testing.T) {
+		derived := derive(t, "age")
+		derived.Type = append(derived.Type, "NationalIDCredential")
+		err := service.VerifyDerivedProof(derived, keyPair.PublicKey)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "BBS+ proof does not match")
+
+		derived = derive(t, "age")
+		derived.Issuer.Name = "Ministry of Public Security"
+		assert.Error(t, service.VerifyDerivedProof(derived, keyPair.PublicKey))
+
+		derived = derive(t, "age")
+		extended := derived.IssuanceDate.AddDate(10, 0, 0)
+		derived.ExpirationDate = &extended
+		assert.Error(t, service.VerifyDerivedProof(derived, keyPair.PublicKey))
+	})
+
+	t.Run("Data Model 2.0 Dates Verify", func(t *testing.T) {
+		validUntil := time.Now().AddDate(1, 0, 0)
+		issued, err := service.IssueCredentialContext(context.Background(), "did:example:issuer", "did:example:holder", []Claim{
+			{Key: "age", Value: 25},
+		}, IssuanceOptions{Version: Version2, ValidUntil: &validUntil})
+		require.NoError(t, err)
+		require.NotNil(t, issued.ValidUntil)
+
+		presentation, err := service.CreatePresentation("did:example:holder", []*VerifiableCredential{issued}, []SelectiveDisclosureRequest{
+			{CredentialID: issued.ID, RevealedAttributes: []string{"age"}, Nonce: "verify-nonce-89f68b18", IssuerPublicKey: keyPair.PublicKey},
+		})
+		require.NoError(t, err)
+		data, err := json.Marshal(presentation.VerifiableCredential[0])
+		require.NoError(t, err)
+		var derived DerivedCredential
+		require.NoError(t, json.Unmarshal(data, &derived))
+		assert.NoError(t, service.VerifyDerivedProof(&derived, keyPair.PublicKey))
+
+		derived.ValidUntil = nil
+		assert.Error(t, service.VerifyDerivedProof(&derived, keyPair.PublicKey), "the expiry is signed")
+	})
+
+	t.Run("Changed Nonce", func(t *testing.T) {
+		derived := derive(t, "age")
+		derived.Proof.Nonce = "another-nonce"
+		assert.Error(t, service.VerifyDerivedProof(derived, keyPair.PublicKey))
+	})
+}
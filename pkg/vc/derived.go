@@ -0,0 +1,184 @@
+package vc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+)
+
+// DerivedCredential is a credential as a holder presents it: the revealed claims with a proof
+// derived from the issuer's signature, and whichever issuer-signed sections the holder chose to
+// present. Members it does not define are kept and written back unchanged, so a decoded
+// presentation re-encodes to the JSON the holder signed.
+type DerivedCredential struct {
+	Context []string `json:"@context"`
+	ID      string   `json:"id"`
+	Type    []string `json:"type"`
+	// Issuer is nil when the holder hides the issuer behind IssuerSetProof
+	Issuer *Issuer `json:"issuer,omitempty"`
+	// Validity dates use the property names of the credential's data model
+	IssuanceDate      *time.Time             `json:"issuanceDate,omitempty"`
+	ExpirationDate    *time.Time             `json:"expirationDate,omitempty"`
+	ValidFrom         *time.Time             `json:"validFrom,omitempty"`
+	ValidUntil        *time.Time             `json:"validUntil,omitempty"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	CredentialStatus  []status.Entry         `json:"credentialStatus,omitempty"`
+	// AttributeSalts holds the salts of the revealed claims
+	AttributeSalts map[string][]byte    `json:"attributeSalts,omitempty"`
+	IssuerSetProof *IssuerSetProof      `json:"issuerSetProof,omitempty"`
+	CoSignatures   *CoSignatureEnvelope `json:"coSignatures,omitempty"`
+	Commitments    *CommitmentBundle    `json:"commitments,omitempty"`
+	Evidence       *EvidenceBundle      `json:"evidence,omitempty"`
+	ClaimKeys      *ClaimKeyCommitment  `json:"claimKeys,omitempty"`
+	AbsenceProofs  []AbsenceProof       `json:"absenceProofs,omitempty"`
+	Predicates     []PredicateProof     `json:"predicates,omitempty"`
+	Proof          *Proof               `json:"proof,omitempty"`
+	extensions     map[string]json.RawMessage
+}
+
+// derivedCredentialFields are the JSON member names DerivedCredential defines
+var derivedCredentialFields = jsonFieldNames(reflect.TypeOf(DerivedCredential{}))
+
+// jsonFieldNames returns the JSON names of a struct type's exported fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// IssuerDID returns the DID of the credential's issuer, or "" when the issuer is hidden
+func (d *DerivedCredential) IssuerDID() string {
+	if d.Issuer == nil {
+		return ""
+	}
+	return d.Issuer.ID
+}
+
+// Version returns the data model version the credential's @context declares
+func (d *DerivedCredential) Version() (Version, error) {
+	return VersionOf(d.Context)
+}
+
+// Metadata returns the credential metadata the derived credential presents, which its proof
+// reveals. The validity dates are read from the properties of the credential's data model. A
+// credential issued into an issuer set presents the set's keys in place of the issuer's name
+// and image.
+func (d *DerivedCredential) Metadata() (CredentialMetadata, error) {
+	if d.Issuer == nil && d.IssuerSetProof == nil {
+		return CredentialMetadata{}, fmt.Errorf("credential presents neither its issuer nor an issuer set")
+	}
+	version, err := d.Version()
+	if err != nil {
+		return CredentialMetadata{}, err
+	}
+
+	metadata := CredentialMetadata{
+		Types:   d.Type,
+		Version: version,
+	}
+	if d.IssuerSetProof != nil {
+		metadata.withIssuerSet(d.IssuerSetProof)
+	} else {
+		metadata.IssuerName, metadata.IssuerImage = d.Issuer.Name, d.Issuer.Image
+	}
+	validFrom, validUntil := d.IssuanceDate, d.ExpirationDate
+	if version == Version2 {
+		validFrom, validUntil = d.ValidFrom, d.ValidUntil
+	}
+	if validFrom != nil {
+		metadata.ValidFrom = *validFrom
+	}
+	metadata.ValidUntil = validUntil
+	return metadata, nil
+}
+
+// Extensions returns the names of members the credential carries that DerivedCredential does not
+// define, in order
+func (d *DerivedCredential) Extensions() []string {
+	names := make([]string, 0, len(d.extensions))
+	for name := range d.extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MarshalJSON writes the credential with the members it was decoded with but does not define.
+// Empty attribute salts are written rather than omitted, so a salted credential presented
+// without its salts is not mistaken for an unsalted one.
+func (d DerivedCredential) MarshalJSON() ([]byte, error) {
+	type derivedCredential DerivedCredential
+	data, err := json.Marshal(derivedCredential(d))
+	emptySalts := d.AttributeSalts != nil && len(d.AttributeSalts) == 0
+	if err != nil || (len(d.extensions) == 0 && !emptySalts) {
+		return data, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range d.extensions {
+		members[name] = value
+	}
+	if emptySalts {
+		members["attributeSalts"] = json.RawMessage("{}")
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON reads a derived credential from a JSON object. A single @context URL is read as
+// a list of one.
+func (d *DerivedCredential) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return fmt.Errorf("derived credential must be a JSON object")
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if context, ok := members["@context"]; ok && bytes.HasPrefix(bytes.TrimSpace(context), []byte(`"`)) {
+		members["@context"] = append(append([]byte("["), context...), ']')
+		var err error
+		if data, err = json.Marshal(members); err != nil {
+			return err
+		}
+	}
+
+	type derivedCredential DerivedCredential
+	var decoded derivedCredential
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*d = DerivedCredential(decoded)
+
+	for name, value := range members {
+		if !derivedCredentialFields[name] {
+			if d.extensions == nil {
+				d.extensions = make(map[string]json.RawMessage)
+			}
+			d.extensions[name] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivedCredentialJSON(t *testing.T) {
+	t.Run("Round Trip Is Lossless", func(t *testing.T) {
+		document := `{
+			"@context": ["https://www.w3.org/2018/credentials/v1"],
+			"id": "urn:uuid:credential",
+			"type": ["VerifiableCredential"],
+			"issuer": {"id": "did:example:issuer", "name": "Example"},
+			"issuanceDate": "2030-01-02T03:04:05Z",
+			"credentialSubject": {"id": "did:example:holder", "ageOver18": true},
+			"attributeSalts": {"ageOver18": "AAECAwQFBgcICQoLDA0ODw=="},
+			"termsOfUse": [{"type": "IssuerPolicy"}],
+			"proof": {"type": "BbsBlsSignatureProof2020", "created": "2030-01-02T03:04:05Z", "proofPurpose": "assertionMethod", "nonce": "n", "disclosedClaims": ["ageOver18"]}
+		}`
+
+		var derived DerivedCredential
+		require.NoError(t, json.Unmarshal([]byte(document), &derived))
+		assert.Equal(t, "did:example:issuer", derived.IssuerDID())
+		assert.Equal(t, []string{"ageOver18"}, derived.Proof.DisclosedClaims)
+		assert.Len(t, derived.AttributeSalts["ageOver18"], AttributeSaltSize)
+		assert.Equal(t, []string{"termsOfUse"}, derived.Extensions())
+
+		version, err := derived.Version()
+		require.NoError(t, err)
+		assert.Equal(t, Version1, version)
+
+		data, err := json.Marshal(&derived)
+		require.NoError(t, err)
+		assert.JSONEq(t, document, string(data))
+	})
+
+	t.Run("Single Context Is A List Of One", func(t *testing.T) {
+		var derived DerivedCredential
+		require.NoError(t, json.Unmarshal([]byte(`{"@context":"https://www.w3.org/ns/credentials/v2"}`), &derived))
+		assert.Equal(t, []string{"https://www.w3.org/ns/credentials/v2"}, derived.Context)
+		assert.Empty(t, derived.Extensions())
+	})
+
+	t.Run("Hidden Issuer", func(t *testing.T) {
+		var derived DerivedCredential
+		require.NoError(t, json.Unmarshal([]byte(`{"issuerSetProof":{"issuers":["did:example:a","did:example:b"]}}`), &derived))
+		assert.Empty(t, derived.IssuerDID())
+		require.NotNil(t, derived.IssuerSetProof)
+		assert.Len(t, derived.IssuerSetProof.Issuers, 2)
+	})
+
+	t.Run("Empty Salts Are Kept", func(t *testing.T) {
+		data, err := json.Marshal(DerivedCredential{AttributeSalts: map[string][]byte{}})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"attributeSalts":{}`)
+
+		data, err = json.Marshal(DerivedCredential{})
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "attributeSalts")
+	})
+
+	t.Run("Non-Object Credentials Are Rejected", func(t *testing.T) {
+		var derived DerivedCredential
+		assert.Error(t, json.Unmarshal([]byte(`"eyJhbGciOiJFZERTQSJ9.e30.sig"`), &derived))
+		assert.Error(t, json.Unmarshal([]byte(`["not", "a", "credential"]`), &derived))
+	})
+}
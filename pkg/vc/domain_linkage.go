@@ -0,0 +1,137 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+)
+
+const (
+	// DIDConfigurationPath is where an origin publishes the DIDs it controls
+	DIDConfigurationPath = "/.well-known/did-configuration.json"
+	// DIDConfigurationContext is the JSON-LD context of a DID configuration resource
+	DIDConfigurationContext = "https://identity.foundation/.well-known/did-configuration/v1"
+	// DomainLinkageCredentialType marks a credential linking a DID to an origin
+	DomainLinkageCredentialType = "DomainLinkageCredential"
+)
+
+// DIDConfiguration is the well-known DID configuration resource an origin serves to list the DIDs
+// it is linked to
+type DIDConfiguration struct {
+	Context    string                     `json:"@context"`
+	LinkedDIDs []*DomainLinkageCredential `json:"linked_dids"`
+}
+
+// DomainLinkageCredential is a DID's signed statement that it is controlled by the operator of an
+// origin. Issuer and subject are the same DID.
+type DomainLinkageCredential struct {
+	Context           []string             `json:"@context"`
+	Type              []string             `json:"type"`
+	Issuer            string               `json:"issuer"`
+	IssuanceDate      time.Time            `json:"issuanceDate"`
+	ExpirationDate    time.Time            `json:"expirationDate"`
+	CredentialSubject DomainLinkageSubject `json:"credentialSubject"`
+	Proof             *Proof               `json:"proof,omitempty"`
+}
+
+// DomainLinkageSubject names the linked DID and origin
+type DomainLinkageSubject struct {
+	ID     string `json:"id"`
+	Origin string `json:"origin"`
+}
+
+// NormalizeOrigin turns a domain such as gov.vn, or an origin such as https://gov.vn/, into the
+// origin form https://gov.vn. Only HTTPS origins without a path are accepted.
+func NormalizeOrigin(domain string) (string, error) {
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return "", fmt.Errorf("domain is empty")
+	}
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+
+	parsed, err := url.Parse(domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain %q: %w", domain, err)
+	}
+	if parsed.Scheme != "https" {
+		return "", fmt.Errorf("domain %q must use https", domain)
+	}
+	if parsed.Host == "" || parsed.User != nil || (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("domain %q must be a bare origin", domain)
+	}
+
+	return "https://" + strings.ToLower(parsed.Host), nil
+}
+
+// DIDConfigurationURL returns where an origin publishes its DID configuration
+func DIDConfigurationURL(origin string) (string, error) {
+	normalized, err := NormalizeOrigin(origin)
+	if err != nil {
+		return "", err
+	}
+	return normalized + DIDConfigurationPath, nil
+}
+
+// DomainLinkageSigningInput returns the bytes covered by a domain linkage credential's proof
+func DomainLinkageSigningInput(credential *DomainLinkageCredential) ([]byte, error) {
+	if credential == nil {
+		return nil, fmt.Errorf("domain linkage credential is nil")
+	}
+
+	unsigned := *credential
+	if credential.Proof != nil {
+		proof := *credential.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal domain linkage credential: %w", err)
+	}
+
+	return data, nil
+}
+
+// CheckDomainLinkage checks a linkage credential's structure and validity period at now, and
+// that it links didString to origin. The proof is checked separately against the DID's keys.
+func CheckDomainLinkage(credential *DomainLinkageCredential, didString, origin string, now time.Time) error {
+	if credential == nil {
+		return fmt.Errorf("domain linkage credential is nil")
+	}
+
+	if !containsString(credential.Type, DomainLinkageCredentialType) {
+		return fmt.Errorf("credential is not a %s", DomainLinkageCredentialType)
+	}
+
+	if credential.Issuer != didString || credential.CredentialSubject.ID != didString {
+		return fmt.Errorf("credential does not link %s", didString)
+	}
+
+	linked, err := NormalizeOrigin(credential.CredentialSubject.Origin)
+	if err != nil || linked != origin {
+		return fmt.Errorf("credential links %s to %q, not %s", didString, credential.CredentialSubject.Origin, origin)
+	}
+
+	if now.Before(credential.IssuanceDate) {
+		return fmt.Errorf("credential is not valid before %s", credential.IssuanceDate.Format(time.RFC3339))
+	}
+	if !now.Before(credential.ExpirationDate) {
+		return fmt.Errorf("credential expired at %s", credential.ExpirationDate.Format(time.RFC3339))
+	}
+
+	if credential.Proof == nil || credential.Proof.ProofValue == "" {
+		return fmt.Errorf("credential has no proof")
+	}
+	if !did.IsVerificationMethodOf(credential.Proof.VerificationMethod, didString) {
+		return fmt.Errorf("key %s is not controlled by %s", credential.Proof.VerificationMethod, didString)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package vc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		expected string
+	}{
+		{"Bare Domain", "gov.vn", "https://gov.vn"},
+		{"Origin With Trailing Slash", "https://gov.vn/", "https://gov.vn"},
+		{"Mixed Case With Port", "HTTPS://Gov.VN:8443", "https://gov.vn:8443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin, err := NormalizeOrigin(tt.domain)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, origin)
+		})
+	}
+
+	t.Run("Invalid Domains", func(t *testing.T) {
+		for _, domain := range []string{"", "http://gov.vn", "https://gov.vn/issuers", "https://user@gov.vn", "https://gov.vn?x=1"} {
+			_, err := NormalizeOrigin(domain)
+			assert.Error(t, err, domain)
+		}
+	})
+}
+
+func TestCheckDomainLinkage(t *testing.T) {
+	issued := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	linkage := func() *DomainLinkageCredential {
+		return &DomainLinkageCredential{
+			Type:              []string{"VerifiableCredential", DomainLinkageCredentialType},
+			Issuer:            "did:example:gov",
+			IssuanceDate:      issued,
+			ExpirationDate:    issued.AddDate(1, 0, 0),
+			CredentialSubject: DomainLinkageSubject{ID: "did:example:gov", Origin: "https://gov.vn"},
+			Proof:             &Proof{VerificationMethod: "did:example:gov#key-1", ProofValue: "z123"},
+		}
+	}
+	now := issued.AddDate(0, 6, 0)
+
+	t.Run("Valid Linkage", func(t *testing.T) {
+		assert.NoError(t, CheckDomainLinkage(linkage(), "did:example:gov", "https://gov.vn", now))
+	})
+
+	tests := []struct {
+		name   string
+		change func(c *DomainLinkageCredential)
+		now    time.Time
+	}{
+		{"Other DID", func(c *DomainLinkageCredential) { c.CredentialSubject.ID = "did:example:other" }, now},
+		{"Issuer Differs From Subject", func(c *DomainLinkageCredential) { c.Issuer = "did:example:other" }, now},
+		{"Other Origin", func(c *DomainLinkageCredential) { c.CredentialSubject.Origin = "https://evil.example" }, now},
+		{"Missing Type", func(c *DomainLinkageCredential) { c.Type = []string{"VerifiableCredential"} }, now},
+		{"Not Yet Valid", func(c *DomainLinkageCredential) {}, issued.Add(-time.Hour)},
+		{"Expired", func(c *DomainLinkageCredential) {}, issued.AddDate(1, 0, 0)},
+		{"Unsigned", func(c *DomainLinkageCredential) { c.Proof = nil }, now},
+		{"Key Of Another DID", func(c *DomainLinkageCredential) { c.Proof.VerificationMethod = "did:example:other#key-1" }, now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credential := linkage()
+			tt.change(credential)
+			assert.Error(t, CheckDomainLinkage(credential, "did:example:gov", "https://gov.vn", tt.now))
+		})
+	}
+}
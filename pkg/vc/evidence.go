@@ -0,0 +1,173 @@
+package vc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+)
+
+// Evidence describes a document the issuer relied on when issuing a credential, e.g. a scanned
+// passport or a transcript. The credential carries only its digest; the document itself is kept in
+// a blob store and released to verifiers the holder authorizes.
+type Evidence struct {
+	ID          string   `json:"id"`
+	Type        []string `json:"type"`
+	Description string   `json:"description,omitempty"`
+	MediaType   string   `json:"mediaType"`
+	// DigestSRI is the SHA-256 digest of the document in Subresource Integrity form
+	DigestSRI string `json:"digestSRI"`
+	// BlobRef locates the stored document; it is absent when the document was not kept
+	BlobRef string `json:"blobRef,omitempty"`
+	// Encrypted documents are stored sealed under a key only the holder receives
+	Encrypted bool `json:"encrypted,omitempty"`
+}
+
+// EvidenceBundle is the issuer-signed evidence section of a credential
+type EvidenceBundle struct {
+	CredentialID string     `json:"credentialId"`
+	Issuer       string     `json:"issuer"`
+	Items        []Evidence `json:"items"`
+	Proof        *Proof     `json:"proof,omitempty"`
+}
+
+// EvidenceKey is the key an encrypted evidence document is sealed under. Keys are private to the
+// holder, who passes them on only to verifiers it grants access.
+type EvidenceKey struct {
+	EvidenceID string `json:"evidenceId"`
+	Key        []byte `json:"key"`
+}
+
+// EvidenceGrant is a holder's signed permission for one verifier to retrieve one evidence document
+// of a credential until the grant expires
+type EvidenceGrant struct {
+	ID           string    `json:"id"`
+	CredentialID string    `json:"credentialId"`
+	EvidenceID   string    `json:"evidenceId"`
+	Holder       string    `json:"holder"`
+	Verifier     string    `json:"verifier"`
+	Expires      time.Time `json:"expires"`
+	// Key carries the document key of an encrypted document, encrypted to the verifier
+	Key   *did.EncryptedEnvelope `json:"key,omitempty"`
+	Proof *Proof                 `json:"proof,omitempty"`
+}
+
+// Find returns the evidence with the given ID
+func (b *EvidenceBundle) Find(id string) (*Evidence, bool) {
+	for i := range b.Items {
+		if b.Items[i].ID == id {
+			return &b.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// DocumentDigest returns the SHA-256 digest of a document in Subresource Integrity form
+func DocumentDigest(document []byte) string {
+	digest := sha256.Sum256(document)
+	return "sha256-" + base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// CheckDocument reports whether document is the one the evidence describes
+func (e *Evidence) CheckDocument(document []byte) error {
+	if subtle.ConstantTimeCompare([]byte(DocumentDigest(document)), []byte(e.DigestSRI)) != 1 {
+		return fmt.Errorf("document does not match the digest of evidence %s", e.ID)
+	}
+	return nil
+}
+
+// SealDocument encrypts a document under a fresh AES-256-GCM key, returning the sealed document
+// and the key
+func SealDocument(document []byte) ([]byte, []byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate document key: %w", err)
+	}
+
+	aead, err := documentCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, document, nil), key, nil
+}
+
+// OpenDocument decrypts a document sealed by SealDocument
+func OpenDocument(sealed, key []byte) ([]byte, error) {
+	aead, err := documentCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("sealed document is too short")
+	}
+
+	document, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt document: %w", err)
+	}
+	return document, nil
+}
+
+func documentCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// EvidenceBundleSigningInput returns the bytes covered by the issuer's evidence proof
+func EvidenceBundleSigningInput(bundle *EvidenceBundle) ([]byte, error) {
+	if bundle == nil {
+		return nil, fmt.Errorf("evidence bundle is nil")
+	}
+
+	unsigned := *bundle
+	if bundle.Proof != nil {
+		proof := *bundle.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal evidence bundle: %w", err)
+	}
+
+	return data, nil
+}
+
+// EvidenceGrantSigningInput returns the bytes covered by the holder's grant proof
+func EvidenceGrantSigningInput(grant *EvidenceGrant) ([]byte, error) {
+	if grant == nil {
+		return nil, fmt.Errorf("evidence grant is nil")
+	}
+
+	unsigned := *grant
+	if grant.Proof != nil {
+		proof := *grant.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal evidence grant: %w", err)
+	}
+
+	return data, nil
+}
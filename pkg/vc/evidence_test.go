@@ -0,0 +1,48 @@
+package vc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvidenceDocuments(t *testing.T) {
+	document := []byte("%PDF-1.7 transcript")
+
+	t.Run("Digest Matches Document", func(t *testing.T) {
+		evidence := Evidence{ID: "urn:uuid:1", DigestSRI: DocumentDigest(document)}
+		assert.NoError(t, evidence.CheckDocument(document))
+		assert.Error(t, evidence.CheckDocument([]byte("%PDF-1.7 forged transcript")))
+	})
+
+	t.Run("Seal And Open", func(t *testing.T) {
+		sealed, key, err := SealDocument(document)
+		require.NoError(t, err)
+		assert.NotContains(t, string(sealed), "transcript")
+
+		opened, err := OpenDocument(sealed, key)
+		require.NoError(t, err)
+		assert.Equal(t, document, opened)
+
+		_, otherKey, err := SealDocument(document)
+		require.NoError(t, err)
+		_, err = OpenDocument(sealed, otherKey)
+		assert.Error(t, err, "a document opens only under its own key")
+
+		_, err = OpenDocument(sealed[:4], key)
+		assert.Error(t, err)
+	})
+
+	t.Run("Signing Input Omits Proof Value", func(t *testing.T) {
+		grant := &EvidenceGrant{ID: "urn:uuid:2", EvidenceID: "urn:uuid:1", Proof: &Proof{Type: "Ed25519Signature2020"}}
+		unsigned, err := EvidenceGrantSigningInput(grant)
+		require.NoError(t, err)
+
+		grant.Proof.ProofValue = "zSignature"
+		signed, err := EvidenceGrantSigningInput(grant)
+		require.NoError(t, err)
+		assert.Equal(t, unsigned, signed)
+		assert.Equal(t, "zSignature", grant.Proof.ProofValue, "the grant itself is left unchanged")
+	})
+}
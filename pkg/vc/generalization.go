@@ -0,0 +1,71 @@
+package vc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GeneralizationSeparator joins a claim and one of its generalizations in a claim key, as in
+// address.city
+const GeneralizationSeparator = "."
+
+// Generalization lists coarser values of a claim, from most to least specific. The issuer signs
+// each as a claim of its own, so the holder can reveal the city of an address without the address.
+type Generalization struct {
+	Claim  string                `json:"claim"`
+	Levels []GeneralizationLevel `json:"levels"`
+}
+
+// GeneralizationLevel is one coarser value of a claim, such as its city or country
+type GeneralizationLevel struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// GeneralizedClaimKey returns the key a generalization of a claim is signed under
+func GeneralizedClaimKey(claim, level string) string {
+	return claim + GeneralizationSeparator + level
+}
+
+// SplitGeneralizedClaim returns the claim a generalized claim key coarsens and the level's name
+func SplitGeneralizedClaim(key string) (claim, level string, ok bool) {
+	i := strings.LastIndex(key, GeneralizationSeparator)
+	if i <= 0 || i == len(key)-1 {
+		return "", "", false
+	}
+	return key[:i], key[i+1:], true
+}
+
+// GeneralizedClaims returns the claims to sign for the generalizations of the given claims.
+// Each generalization must coarsen one of the claims, and its keys may not collide with them.
+func GeneralizedClaims(claims []Claim, generalizations []Generalization) ([]Claim, error) {
+	keys := make(map[string]bool, len(claims))
+	for _, claim := range claims {
+		keys[claim.Key] = true
+	}
+
+	var generalized []Claim
+	for _, generalization := range generalizations {
+		if !keys[generalization.Claim] {
+			return nil, fmt.Errorf("generalization of %s, which is not a claim of the credential", generalization.Claim)
+		}
+		if len(generalization.Levels) == 0 {
+			return nil, fmt.Errorf("generalization of %s has no levels", generalization.Claim)
+		}
+		for _, level := range generalization.Levels {
+			if level.Name == "" || strings.Contains(level.Name, GeneralizationSeparator) {
+				return nil, fmt.Errorf("generalization of %s: level name %q must be non-empty and contain no %q", generalization.Claim, level.Name, GeneralizationSeparator)
+			}
+			if level.Value == nil {
+				return nil, fmt.Errorf("generalization of %s: level %s has no value", generalization.Claim, level.Name)
+			}
+			key := GeneralizedClaimKey(generalization.Claim, level.Name)
+			if keys[key] {
+				return nil, fmt.Errorf("generalization of %s: claim %s is already set", generalization.Claim, key)
+			}
+			keys[key] = true
+			generalized = append(generalized, Claim{Key: key, Value: level.Value})
+		}
+	}
+	return generalized, nil
+}
@@ -0,0 +1,63 @@
+package vc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneralizedClaims(t *testing.T) {
+	claims := []Claim{
+		{Key: "address", Value: "12 Nguyen Hue, District 1, Ho Chi Minh City, Vietnam"},
+		{Key: "dateOfBirth", Value: "1990-05-15"},
+	}
+
+	t.Run("Levels Are Signed As Claims", func(t *testing.T) {
+		generalized, err := GeneralizedClaims(claims, []Generalization{
+			{Claim: "address", Levels: []GeneralizationLevel{
+				{Name: "city", Value: "Ho Chi Minh City"},
+				{Name: "country", Value: "Vietnam"},
+			}},
+			{Claim: "dateOfBirth", Levels: []GeneralizationLevel{{Name: "year", Value: 1990}}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []Claim{
+			{Key: "address.city", Value: "Ho Chi Minh City"},
+			{Key: "address.country", Value: "Vietnam"},
+			{Key: "dateOfBirth.year", Value: 1990},
+		}, generalized)
+
+		none, err := GeneralizedClaims(claims, nil)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("Invalid Generalizations", func(t *testing.T) {
+		for name, generalization := range map[string]Generalization{
+			"Unknown Claim":  {Claim: "nationality", Levels: []GeneralizationLevel{{Name: "region", Value: "Asia"}}},
+			"No Levels":      {Claim: "address"},
+			"Empty Name":     {Claim: "address", Levels: []GeneralizationLevel{{Value: "Vietnam"}}},
+			"Dotted Name":    {Claim: "address", Levels: []GeneralizationLevel{{Name: "city.name", Value: "Ho Chi Minh City"}}},
+			"Missing Value":  {Claim: "address", Levels: []GeneralizationLevel{{Name: "city"}}},
+			"Repeated Level": {Claim: "address", Levels: []GeneralizationLevel{{Name: "city", Value: "A"}, {Name: "city", Value: "B"}}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := GeneralizedClaims(claims, []Generalization{generalization})
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("Split Key", func(t *testing.T) {
+		claim, level, ok := SplitGeneralizedClaim(GeneralizedClaimKey("address", "city"))
+		assert.True(t, ok)
+		assert.Equal(t, "address", claim)
+		assert.Equal(t, "city", level)
+
+		for _, key := range []string{"address", ".city", "address."} {
+			_, _, ok := SplitGeneralizedClaim(key)
+			assert.False(t, ok, key)
+		}
+	})
+}
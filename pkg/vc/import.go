@@ -0,0 +1,100 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+// BBSSignatureSuite is the proof type of a credential signed with BBS+
+const BBSSignatureSuite = "BbsBlsSignature2020"
+
+// SignatureVerifier is implemented by credential services that can check a credential's BBS+
+// signature against its issuer's key
+type SignatureVerifier interface {
+	VerifyCredentialSignature(credential *VerifiableCredential, publicKey []byte) error
+}
+
+// ParseCredential decodes a JSON-LD credential issued elsewhere and checks it is one a holder
+// can derive presentations from: a BbsBlsSignature2020 assertion proof by a key of the issuer,
+// and a claim manifest giving the message index of every subject claim. Credentials signed over
+// RDF-canonicalized statements carry no manifest and are rejected, as their messages cannot be
+// rebuilt here.
+func ParseCredential(raw []byte) (*VerifiableCredential, error) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, fmt.Errorf("invalid credential JSON: %w", err)
+	}
+	version, err := DocumentVersion(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var credential VerifiableCredential
+	if err := json.Unmarshal(raw, &credential); err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+
+	if !containsString(credential.Type, "VerifiableCredential") {
+		return nil, fmt.Errorf("credential type must include VerifiableCredential")
+	}
+	if version == Version1 && !containsString(credential.Context, jsonld.BBSV1URL) {
+		return nil, fmt.Errorf("@context must include %s for %s proofs", jsonld.BBSV1URL, BBSSignatureSuite)
+	}
+	if credential.Issuer() == "" {
+		return nil, fmt.Errorf("credential has no issuer")
+	}
+	if _, ok := credential.CredentialSubject["id"].(string); !ok {
+		return nil, fmt.Errorf("credential subject has no id")
+	}
+
+	proof := credential.Proof
+	if proof == nil || proof.ProofValue == "" {
+		return nil, fmt.Errorf("credential has no proof value")
+	}
+	if proof.Type != BBSSignatureSuite {
+		return nil, fmt.Errorf("unsupported proof type %q: expected %s", proof.Type, BBSSignatureSuite)
+	}
+	if proof.ProofPurpose != "assertionMethod" {
+		return nil, fmt.Errorf("proof purpose must be assertionMethod, not %q", proof.ProofPurpose)
+	}
+	if !did.IsVerificationMethodOf(proof.VerificationMethod, credential.Issuer()) {
+		return nil, fmt.Errorf("proof must be made with a key of the issuer %s", credential.Issuer())
+	}
+	if _, err := bbs.DecodeSignature(proof.ProofValue); err != nil {
+		return nil, fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	if credential.ClaimManifest == nil {
+		return nil, fmt.Errorf("credential has no claim manifest, so the order its claims were signed in is unknown")
+	}
+	if err := credential.ClaimManifest.Validate(); err != nil {
+		return nil, err
+	}
+	var unsigned []string
+	for key := range credential.CredentialSubject {
+		if _, ok := credential.ClaimManifest.Index(key); !ok && key != "id" {
+			unsigned = append(unsigned, key)
+		}
+	}
+	if len(unsigned) > 0 {
+		sort.Strings(unsigned)
+		return nil, fmt.Errorf("claims %v are not covered by the claim manifest", unsigned)
+	}
+
+	return &credential, nil
+}
+
+// VerifyCredentialSignature checks the credential's BBS+ signature covers its claim manifest
+// and claims under the issuer's public key
+func (s *ServiceImpl) VerifyCredentialSignature(credential *VerifiableCredential, publicKey []byte) error {
+	if credential == nil || credential.Proof == nil {
+		return fmt.Errorf("credential has no proof")
+	}
+	_, _, err := s.signedCredential(credential, publicKey)
+	return err
+}
@@ -0,0 +1,81 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+func TestParseCredential(t *testing.T) {
+	bbsService := bbs.NewService()
+	service := NewService(bbsService, NewInMemoryCredentialRepository(), NewInMemoryPresentationRepository())
+
+	keyPair, err := bbsService.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, service.SetIssuerKeyPair("did:example:issuer", keyPair))
+	verifier := service.(SignatureVerifier)
+
+	issued, err := service.IssueCredential("did:example:issuer", "did:example:holder", []Claim{
+		{Key: "fullName", Value: "An Nguyen"},
+		{Key: "ageOver18", Value: true},
+	})
+	require.NoError(t, err)
+	raw, err := json.Marshal(issued)
+	require.NoError(t, err)
+
+	// edit decodes the credential, changes it and encodes it again
+	edit := func(t *testing.T, change func(document map[string]interface{})) []byte {
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal(raw, &document))
+		change(document)
+		data, err := json.Marshal(document)
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("Valid Credential", func(t *testing.T) {
+		credential, err := ParseCredential(raw)
+		require.NoError(t, err)
+		assert.Equal(t, issued.ID, credential.ID)
+		assert.Equal(t, []string{"fullName", "ageOver18"}, credential.ClaimManifest.Keys())
+		assert.NoError(t, verifier.VerifyCredentialSignature(credential, keyPair.PublicKey))
+	})
+
+	t.Run("Altered Claim Fails Signature Verification", func(t *testing.T) {
+		credential, err := ParseCredential(edit(t, func(document map[string]interface{}) {
+			document["credentialSubject"].(map[string]interface{})["fullName"] = "Someone Else"
+		}))
+		require.NoError(t, err)
+		assert.Error(t, verifier.VerifyCredentialSignature(credential, keyPair.PublicKey))
+	})
+
+	t.Run("Rejected Credentials", func(t *testing.T) {
+		for name, change := range map[string]func(document map[string]interface{}){
+			"Other Proof Type": func(document map[string]interface{}) {
+				document["proof"].(map[string]interface{})["type"] = "Ed25519Signature2020"
+			},
+			"Key Of Another DID": func(document map[string]interface{}) {
+				document["proof"].(map[string]interface{})["verificationMethod"] = "did:example:mallory#bbs-key-1"
+			},
+			"No Claim Manifest": func(document map[string]interface{}) {
+				delete(document, "claimManifest")
+			},
+			"Claim Outside The Manifest": func(document map[string]interface{}) {
+				document["credentialSubject"].(map[string]interface{})["nationality"] = "Vietnamese"
+			},
+			"No BBS Context": func(document map[string]interface{}) {
+				document["@context"] = []string{"https://www.w3.org/2018/credentials/v1"}
+			},
+		} {
+			_, err := ParseCredential(edit(t, change))
+			assert.Error(t, err, name)
+		}
+
+		_, err := ParseCredential([]byte("not json"))
+		assert.Error(t, err)
+	})
+}
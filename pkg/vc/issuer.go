@@ -0,0 +1,96 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Issuer identifies a credential's issuer by DID, optionally with a display name and image.
+// It is serialized as the bare DID unless it has a name or image.
+type Issuer struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+// NewIssuer returns an issuer identified by DID alone
+func NewIssuer(did string) Issuer {
+	return Issuer{ID: did}
+}
+
+// IsObject reports whether the issuer is serialized as an object rather than a bare DID
+func (i Issuer) IsObject() bool {
+	return i.Name != "" || i.Image != ""
+}
+
+// Value returns the issuer in decoded JSON form: the DID string, or an object with id, name and image
+func (i Issuer) Value() interface{} {
+	if !i.IsObject() {
+		return i.ID
+	}
+
+	value := map[string]interface{}{"id": i.ID}
+	if i.Name != "" {
+		value["name"] = i.Name
+	}
+	if i.Image != "" {
+		value["image"] = i.Image
+	}
+	return value
+}
+
+// MarshalJSON writes the issuer as a bare DID unless it has a name or image
+func (i Issuer) MarshalJSON() ([]byte, error) {
+	if !i.IsObject() {
+		return json.Marshal(i.ID)
+	}
+
+	type issuerObject Issuer
+	return json.Marshal(issuerObject(i))
+}
+
+// UnmarshalJSON accepts an issuer given as a DID string or as an object with an id
+func (i *Issuer) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" || string(data) == `""` {
+		*i = Issuer{}
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	issuer, err := ParseIssuer(value)
+	if err != nil {
+		return err
+	}
+	*i = issuer
+	return nil
+}
+
+// ParseIssuer reads an issuer from its decoded JSON form, as derived credentials carry it
+func ParseIssuer(value interface{}) (Issuer, error) {
+	switch issuer := value.(type) {
+	case string:
+		if issuer == "" {
+			return Issuer{}, fmt.Errorf("issuer is empty")
+		}
+		return NewIssuer(issuer), nil
+	case map[string]interface{}:
+		id, _ := issuer["id"].(string)
+		if id == "" {
+			return Issuer{}, fmt.Errorf("issuer object has no id")
+		}
+		name, _ := issuer["name"].(string)
+		image, _ := issuer["image"].(string)
+		return Issuer{ID: id, Name: name, Image: image}, nil
+	default:
+		return Issuer{}, fmt.Errorf("issuer must be a string or an object with an id")
+	}
+}
+
+// Issuer returns the DID of the credential's issuer
+func (c *VerifiableCredential) Issuer() string {
+	return c.IssuerInfo.ID
+}
@@ -0,0 +1,31 @@
+package vc
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+// IssuerSetProofType is the proof type of issuer set proofs
+const IssuerSetProofType = "BbsIssuerSetProof"
+
+// IssuerSetProof names the anonymity set of issuers a holder may hide a credential's issuer
+// within. The issuer signs the members' BBS+ keys into the credential metadata at issuance. When
+// the holder hides the issuer, the presented credential carries a membership proof, created
+// afresh for the presentation, that its BBS+ proof was made under one of those keys.
+type IssuerSetProof struct {
+	Type string `json:"type"`
+	// Issuers are the set members, sorted so their order does not point at the signer
+	Issuers []string `json:"issuers"`
+	// VerificationMethods are the members' BBS+ keys, in the order of Issuers
+	VerificationMethods []string `json:"verificationMethods"`
+	// ProofValue is the membership proof of a presentation; issued credentials have none
+	ProofValue *bbs.SetMembershipProof `json:"proofValue,omitempty"`
+}
+
+// withoutProof returns a copy of the set with no membership proof
+func (p *IssuerSetProof) withoutProof() *IssuerSetProof {
+	return &IssuerSetProof{
+		Type:                p.Type,
+		Issuers:             append([]string(nil), p.Issuers...),
+		VerificationMethods: append([]string(nil), p.VerificationMethods...),
+	}
+}
@@ -0,0 +1,70 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssuerJSON(t *testing.T) {
+	t.Run("DID Only Marshals As String", func(t *testing.T) {
+		data, err := json.Marshal(NewIssuer("did:example:issuer"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `"did:example:issuer"`, string(data))
+	})
+
+	t.Run("Named Issuer Marshals As Object", func(t *testing.T) {
+		issuer := Issuer{ID: "did:example:issuer", Name: "Example University", Image: "https://example.com/logo.png"}
+		data, err := json.Marshal(issuer)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":"did:example:issuer","name":"Example University","image":"https://example.com/logo.png"}`, string(data))
+
+		var decoded Issuer
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, issuer, decoded)
+	})
+
+	t.Run("Credential Issuer String", func(t *testing.T) {
+		var credential VerifiableCredential
+		require.NoError(t, json.Unmarshal([]byte(`{"issuer":"did:example:issuer"}`), &credential))
+		assert.Equal(t, "did:example:issuer", credential.Issuer())
+		assert.False(t, credential.IssuerInfo.IsObject())
+	})
+
+	t.Run("Credential Issuer Object", func(t *testing.T) {
+		var credential VerifiableCredential
+		require.NoError(t, json.Unmarshal([]byte(`{"issuer":{"id":"did:example:issuer","name":"Example"},"validFrom":"2030-01-02T03:04:05Z"}`), &credential))
+		assert.Equal(t, "did:example:issuer", credential.Issuer())
+		assert.Equal(t, "Example", credential.IssuerInfo.Name)
+		require.NotNil(t, credential.ValidFrom)
+	})
+
+	t.Run("Object Without Id", func(t *testing.T) {
+		var credential VerifiableCredential
+		assert.Error(t, json.Unmarshal([]byte(`{"issuer":{"name":"Example"}}`), &credential))
+	})
+
+	t.Run("Not A String Or Object", func(t *testing.T) {
+		var credential VerifiableCredential
+		assert.Error(t, json.Unmarshal([]byte(`{"issuer":42}`), &credential))
+	})
+}
+
+func TestParseIssuer(t *testing.T) {
+	issuer, err := ParseIssuer("did:example:issuer")
+	require.NoError(t, err)
+	assert.Equal(t, NewIssuer("did:example:issuer"), issuer)
+
+	issuer, err = ParseIssuer(map[string]interface{}{"id": "did:example:issuer", "name": "Example"})
+	require.NoError(t, err)
+	assert.Equal(t, Issuer{ID: "did:example:issuer", Name: "Example"}, issuer)
+	assert.Equal(t, map[string]interface{}{"id": "did:example:issuer", "name": "Example"}, issuer.Value())
+
+	_, err = ParseIssuer(nil)
+	assert.Error(t, err)
+
+	_, err = ParseIssuer("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,370 @@
+package vc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+const (
+	credentialKeyPrefix = "credential:"
+	// credentialTombstoneKeyPrefix keys the tombstones of deleted credentials; listing
+	// credentialKeyPrefix does not match them
+	credentialTombstoneKeyPrefix = "credential-tombstone:"
+	presentationKeyPrefix        = "presentation:"
+)
+
+// KVCredentialRepository is a CredentialRepository kept in a key-value store, so it can be shared by
+// several server instances
+type KVCredentialRepository struct {
+	store storage.KVStore
+	// retention is how long deleted credentials are kept; zero keeps them
+	retention time.Duration
+	// keys wrap the data keys claims are encrypted under; nil stores claims in the clear
+	keys KeyEncryptionKeys
+}
+
+// KVCredentialOptions configure a KVCredentialRepository
+type KVCredentialOptions struct {
+	// Retention is how long deleted credentials are kept; zero keeps them
+	Retention time.Duration
+	// Keys encrypt the claims of the credentials stored, each under a data key of its own they
+	// wrap, and decrypt them on retrieval; nil stores claims in the clear
+	Keys KeyEncryptionKeys
+}
+
+// NewKVCredentialRepository creates a credential repository on top of store that keeps deleted
+// credentials
+func NewKVCredentialRepository(store storage.KVStore) CredentialRepository {
+	return NewKVCredentialRepositoryWithRetention(store, 0)
+}
+
+// NewKVCredentialRepositoryWithRetention creates a credential repository on top of store that
+// lets deleted credentials expire retention after their deletion; zero keeps them
+func NewKVCredentialRepositoryWithRetention(store storage.KVStore, retention time.Duration) CredentialRepository {
+	return NewKVCredentialRepositoryWithOptions(store, KVCredentialOptions{Retention: retention})
+}
+
+// NewKVCredentialRepositoryWithOptions creates a credential repository on top of store configured
+// by options
+func NewKVCredentialRepositoryWithOptions(store storage.KVStore, options KVCredentialOptions) CredentialRepository {
+	return &KVCredentialRepository{store: store, retention: options.Retention, keys: options.Keys}
+}
+
+// Ping reports whether the underlying store is reachable
+func (r *KVCredentialRepository) Ping(ctx context.Context) error {
+	return r.store.Ping(ctx)
+}
+
+// Store stores a verifiable credential
+func (r *KVCredentialRepository) Store(vc *VerifiableCredential) error {
+	if vc == nil {
+		return fmt.Errorf("credential is nil")
+	}
+	if err := r.checkActive(vc.ID); err != nil {
+		return err
+	}
+	return r.put(vc, 0)
+}
+
+// Retrieve retrieves a verifiable credential by ID
+func (r *KVCredentialRepository) Retrieve(id string) (*VerifiableCredential, error) {
+	if err := r.checkActive(id); err != nil {
+		return nil, err
+	}
+
+	data, err := r.store.Get(credentialKeyPrefix + id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, credentialNotFound(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve credential: %w", err)
+	}
+
+	vc, encrypted, err := decodeStoredCredential(id, data)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted != nil {
+		if r.keys == nil {
+			return nil, fmt.Errorf("claims of credential %s are encrypted but no key-encryption keys are configured", id)
+		}
+		if err := DecryptClaims(vc, encrypted, r.keys); err != nil {
+			return nil, err
+		}
+	}
+	return vc, nil
+}
+
+// List lists all credentials for a holder DID
+func (r *KVCredentialRepository) List(holderDID string) ([]*VerifiableCredential, error) {
+	keys, err := r.store.Keys(credentialKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	var credentials []*VerifiableCredential
+	for _, key := range keys {
+		vc, err := r.Retrieve(key[len(credentialKeyPrefix):])
+		if err != nil {
+			// Deleted or expired between listing and reading
+			continue
+		}
+		if subjectID, ok := vc.CredentialSubject["id"].(string); ok && subjectID == holderDID {
+			credentials = append(credentials, vc)
+		}
+	}
+	return credentials, nil
+}
+
+// Delete keeps a credential with the tombstone recording its deletion. Both expire together
+// once the retention has passed, the tombstone last.
+func (r *KVCredentialRepository) Delete(id string, tombstone storage.Tombstone) error {
+	vc, err := r.Retrieve(id)
+	if err != nil {
+		return err
+	}
+
+	if r.retention > 0 {
+		if err := r.put(vc, r.retention); err != nil {
+			return err
+		}
+	}
+	if err := storage.SetJSON(r.store, credentialTombstoneKeyPrefix+id, tombstone.Stamped(time.Now()), r.retention); err != nil {
+		if r.retention > 0 {
+			// Keep the credential that would otherwise expire while still stored
+			_ = r.put(vc, 0)
+		}
+		return fmt.Errorf("failed to delete credential: %w", err)
+	}
+	return nil
+}
+
+// checkActive fails for a credential with a tombstone
+func (r *KVCredentialRepository) checkActive(id string) error {
+	var tombstone storage.Tombstone
+	err := storage.GetJSON(r.store, credentialTombstoneKeyPrefix+id, &tombstone)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check credential %s is not deleted: %w", id, err)
+	}
+	return &DeletedCredentialError{ID: id, Tombstone: tombstone}
+}
+
+// RewrapKeys rewraps the data keys of the stored credentials under the current key-encryption
+// key, and encrypts the claims of credentials stored in the clear before encryption was enabled,
+// and the salts and signatures of credentials whose claims were encrypted without them.
+// Once it has run, the key-encryption keys rotated out can be retired. It returns the number of
+// credentials rewritten.
+func (r *KVCredentialRepository) RewrapKeys() (int, error) {
+	if r.keys == nil {
+		return 0, fmt.Errorf("no key-encryption keys are configured")
+	}
+	keys, err := r.store.Keys(credentialKeyPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	rewritten := 0
+	for _, key := range keys {
+		id := key[len(credentialKeyPrefix):]
+		data, err := r.store.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to read credential %s: %w", id, err)
+		}
+		vc, encrypted, err := decodeStoredCredential(id, data)
+		if err != nil {
+			return rewritten, err
+		}
+		if encrypted != nil && encrypted.KeyID == r.keys.Current() && !hasClearClaimFields(vc) {
+			continue
+		}
+
+		// Deleted credentials keep expiring, though their retention restarts
+		var ttl time.Duration
+		if err := r.checkActive(id); errors.Is(err, ErrCredentialDeleted) {
+			ttl = r.retention
+		} else if err != nil {
+			return rewritten, err
+		}
+
+		switch {
+		case encrypted == nil:
+			err = r.put(vc, ttl)
+		case hasClearClaimFields(vc):
+			// Encrypted before the salts and signature were, so everything is encrypted afresh
+			if err = DecryptClaims(vc, encrypted, r.keys); err == nil {
+				err = r.put(vc, ttl)
+			}
+		default:
+			encrypted, err = RewrapClaims(encrypted, r.keys)
+			if err == nil {
+				err = r.write(vc, encrypted, ttl)
+			}
+		}
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to rewrap credential %s: %w", id, err)
+		}
+		rewritten++
+	}
+	return rewritten, nil
+}
+
+// put stores a credential, its claims encrypted when keys are configured
+func (r *KVCredentialRepository) put(vc *VerifiableCredential, ttl time.Duration) error {
+	if r.keys == nil {
+		return r.write(vc, nil, ttl)
+	}
+	stripped, encrypted, err := EncryptClaims(vc, r.keys)
+	if err != nil {
+		return err
+	}
+	return r.write(stripped, encrypted, ttl)
+}
+
+// write stores a credential as it is, with its encrypted claims if any
+func (r *KVCredentialRepository) write(vc *VerifiableCredential, encrypted *EncryptedClaims, ttl time.Duration) error {
+	data, err := json.Marshal(vc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credential: %w", err)
+	}
+	if encrypted != nil {
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("failed to marshal credential: %w", err)
+		}
+		if record["encryptedClaims"], err = json.Marshal(encrypted); err != nil {
+			return fmt.Errorf("failed to marshal encrypted claims: %w", err)
+		}
+		if data, err = json.Marshal(record); err != nil {
+			return fmt.Errorf("failed to marshal credential: %w", err)
+		}
+	}
+	if err := r.store.Set(credentialKeyPrefix+vc.ID, data, ttl); err != nil {
+		return fmt.Errorf("failed to store credential: %w", err)
+	}
+	return nil
+}
+
+// decodeStoredCredential decodes a stored credential and its encrypted claims, nil for a
+// credential stored in the clear
+func decodeStoredCredential(id string, data []byte) (*VerifiableCredential, *EncryptedClaims, error) {
+	var record struct {
+		EncryptedClaims *EncryptedClaims `json:"encryptedClaims"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal credential %s: %w", id, err)
+	}
+	var vc VerifiableCredential
+	if err := json.Unmarshal(data, &vc); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal credential %s: %w", id, err)
+	}
+	return &vc, record.EncryptedClaims, nil
+}
+
+// KVPresentationRepository is a PresentationRepository kept in a key-value store. Records are keyed by
+// verification time, so listing the keys lists the records oldest first.
+type KVPresentationRepository struct {
+	store storage.KVStore
+}
+
+// NewKVPresentationRepository creates a presentation repository on top of store
+func NewKVPresentationRepository(store storage.KVStore) PresentationRepository {
+	return &KVPresentationRepository{store: store}
+}
+
+// Ping reports whether the underlying store is reachable
+func (r *KVPresentationRepository) Ping(ctx context.Context) error {
+	return r.store.Ping(ctx)
+}
+
+// Store records a verified presentation. Every verification is kept, so a presentation submitted
+// twice has two records.
+func (r *KVPresentationRepository) Store(record *PresentationRecord) error {
+	if record == nil || record.Presentation == nil {
+		return fmt.Errorf("presentation is nil")
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal presentation record: %w", err)
+	}
+
+	verifiedAt := record.VerifiedAt
+	if verifiedAt.IsZero() {
+		verifiedAt = time.Now()
+	}
+	key := fmt.Sprintf("%s%020d:%s", presentationKeyPrefix, verifiedAt.UnixNano(), uuid.New().String())
+	if err := r.store.Set(key, data, 0); err != nil {
+		return fmt.Errorf("failed to store presentation record: %w", err)
+	}
+	return nil
+}
+
+// Retrieve returns the latest record of a presentation by ID
+func (r *KVPresentationRepository) Retrieve(id string) (*PresentationRecord, error) {
+	records, err := r.records()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Presentation.ID == id {
+			return records[i], nil
+		}
+	}
+	return nil, fmt.Errorf("presentation not found: %s", id)
+}
+
+// List lists the records that match the filter, oldest first
+func (r *KVPresentationRepository) List(filter PresentationFilter) ([]*PresentationRecord, error) {
+	records, err := r.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*PresentationRecord
+	for _, record := range records {
+		if filter.Matches(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched, nil
+}
+
+// records loads every record, oldest first
+func (r *KVPresentationRepository) records() ([]*PresentationRecord, error) {
+	keys, err := r.store.Keys(presentationKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presentation records: %w", err)
+	}
+
+	records := make([]*PresentationRecord, 0, len(keys))
+	for _, key := range keys {
+		data, err := r.store.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read presentation record: %w", err)
+		}
+
+		var record PresentationRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal presentation record %s: %w", key, err)
+		}
+		if record.Presentation != nil {
+			records = append(records, &record)
+		}
+	}
+	return records, nil
+}
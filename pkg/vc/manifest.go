@@ -0,0 +1,208 @@
+package vc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+)
+
+// manifestMessagePrefix separates the signed claim manifest from claim messages
+const manifestMessagePrefix = "bbssd:claim-manifest:"
+
+// ClaimManifest records the BBS+ message index each claim was signed at. The manifest is itself
+// signed as message 0, so holders can rebuild the messages in order after the credential has been
+// through JSON, and an altered manifest no longer matches the signature.
+type ClaimManifest struct {
+	Claims []ManifestEntry `json:"claims"`
+}
+
+// ManifestEntry is the message index of one claim, with the issuer's restriction on revealing it
+type ManifestEntry struct {
+	Key        string                       `json:"key"`
+	Index      int                          `json:"index"`
+	Disclosure schema.DisclosureRestriction `json:"disclosure,omitempty"`
+}
+
+// ErrDisclosureRestricted is returned when a presentation would reveal claims in a way their
+// issuer does not allow
+var ErrDisclosureRestricted = errors.New("disclosure restricted by issuer")
+
+// NewClaimManifest numbers the claims from 1 in the order given, after the manifest's own message
+func NewClaimManifest(keys []string) *ClaimManifest {
+	manifest := &ClaimManifest{Claims: make([]ManifestEntry, len(keys))}
+	for i, key := range keys {
+		manifest.Claims[i] = ManifestEntry{Key: key, Index: i + 1}
+	}
+	return manifest
+}
+
+// Validate checks that every claim appears once and the indices number the claims from 1
+func (m *ClaimManifest) Validate() error {
+	if m == nil || len(m.Claims) == 0 {
+		return fmt.Errorf("claim manifest is empty")
+	}
+
+	keys := make(map[string]bool, len(m.Claims))
+	indices := make(map[int]bool, len(m.Claims))
+	for _, entry := range m.Claims {
+		if entry.Key == "" {
+			return fmt.Errorf("claim manifest entry %d has no key", entry.Index)
+		}
+		if keys[entry.Key] {
+			return fmt.Errorf("claim manifest lists %s more than once", entry.Key)
+		}
+		if entry.Index < 1 || entry.Index > len(m.Claims) || indices[entry.Index] {
+			return fmt.Errorf("claim manifest index %d of %s is out of range or repeated", entry.Index, entry.Key)
+		}
+		if err := entry.Disclosure.Validate(); err != nil {
+			return fmt.Errorf("claim manifest entry %s: %w", entry.Key, err)
+		}
+		keys[entry.Key] = true
+		indices[entry.Index] = true
+	}
+	return nil
+}
+
+// Keys returns the claims in message order
+func (m *ClaimManifest) Keys() []string {
+	entries := append([]ManifestEntry(nil), m.Claims...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Index < entries[j].Index })
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	return keys
+}
+
+// Index returns the message index a claim was signed at
+func (m *ClaimManifest) Index(key string) (int, bool) {
+	for _, entry := range m.Claims {
+		if entry.Key == key {
+			return entry.Index, true
+		}
+	}
+	return 0, false
+}
+
+// Restrict sets the disclosure restriction of a claim in the manifest
+func (m *ClaimManifest) Restrict(key string, restriction schema.DisclosureRestriction) error {
+	if err := restriction.Validate(); err != nil {
+		return fmt.Errorf("claim %s: %w", key, err)
+	}
+	for i := range m.Claims {
+		if m.Claims[i].Key == key {
+			m.Claims[i].Disclosure = restriction
+			return nil
+		}
+	}
+	return fmt.Errorf("claim %s is not in the manifest", key)
+}
+
+// Restriction returns the issuer's restriction on revealing a claim
+func (m *ClaimManifest) Restriction(key string) schema.DisclosureRestriction {
+	for _, entry := range m.Claims {
+		if entry.Key == key {
+			return entry.Disclosure
+		}
+	}
+	return ""
+}
+
+// CheckDisclosure checks revealing the claims together honors the issuer's restrictions: no
+// claim restricted to never is revealed, and claims not to be revealed alone are accompanied by a
+// claim that may be
+func (m *ClaimManifest) CheckDisclosure(revealed []string) error {
+	var notAlone []string
+	accompanied := false
+	for _, key := range revealed {
+		switch m.Restriction(key) {
+		case schema.DisclosureNever:
+			return fmt.Errorf("%w: %s may never be revealed", ErrDisclosureRestricted, key)
+		case schema.DisclosureNotAlone:
+			notAlone = append(notAlone, key)
+		default:
+			accompanied = true
+		}
+	}
+
+	if len(notAlone) > 0 && !accompanied {
+		return fmt.Errorf("%w: %s may not be revealed alone", ErrDisclosureRestricted, strings.Join(notAlone, ", "))
+	}
+	return nil
+}
+
+// Message returns the manifest's signed message
+func (m *ClaimManifest) Message() ([]byte, error) {
+	data, err := json.Marshal(m.Claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode claim manifest: %w", err)
+	}
+	return append([]byte(manifestMessagePrefix), data...), nil
+}
+
+// metadataMessagePrefix separates the signed credential metadata from claim messages
+const metadataMessagePrefix = "bbssd:credential-metadata:"
+
+// CredentialMetadata is what the issuer states about a credential besides its claims. It is
+// signed as the message after the last claim rather than with the manifest, so presentations can
+// reveal it without revealing which claims the credential has.
+type CredentialMetadata struct {
+	Types       []string `json:"types"`
+	IssuerName  string   `json:"issuerName,omitempty"`
+	IssuerImage string   `json:"issuerImage,omitempty"`
+	// IssuerSet lists the keys of a credential's issuer set. It takes the place of the issuer's
+	// name and image, which would identify a hidden issuer.
+	IssuerSet []string `json:"issuerSet,omitempty"`
+	// Version is the data model, which names the validity dates
+	Version    Version    `json:"version"`
+	ValidFrom  time.Time  `json:"validFrom"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+}
+
+// Message returns the metadata's signed message. Dates are signed in UTC, so they sign the same
+// bytes whatever time zone they are presented in.
+func (m CredentialMetadata) Message() ([]byte, error) {
+	m.ValidFrom = m.ValidFrom.UTC()
+	if m.ValidUntil != nil {
+		validUntil := m.ValidUntil.UTC()
+		m.ValidUntil = &validUntil
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential metadata: %w", err)
+	}
+	return append([]byte(metadataMessagePrefix), data...), nil
+}
+
+// Metadata returns the metadata the credential's signature covers
+func (c *VerifiableCredential) Metadata() (CredentialMetadata, error) {
+	version, err := c.Version()
+	if err != nil {
+		return CredentialMetadata{}, err
+	}
+	metadata := CredentialMetadata{
+		Types:       c.Type,
+		IssuerName:  c.IssuerInfo.Name,
+		IssuerImage: c.IssuerInfo.Image,
+		Version:     version,
+		ValidFrom:   c.IssuedAt(),
+		ValidUntil:  c.ExpiresAt(),
+	}
+	if c.IssuerSetProof != nil {
+		metadata.withIssuerSet(c.IssuerSetProof)
+	}
+	return metadata, nil
+}
+
+// withIssuerSet signs the keys of an issuer set in place of the issuer's name and image
+func (m *CredentialMetadata) withIssuerSet(set *IssuerSetProof) {
+	m.IssuerName, m.IssuerImage = "", ""
+	m.IssuerSet = set.VerificationMethods
+}
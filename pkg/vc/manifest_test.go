@@ -0,0 +1,65 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+)
+
+func TestClaimManifest(t *testing.T) {
+	t.Run("Indices Survive JSON", func(t *testing.T) {
+		manifest := NewClaimManifest([]string{"zeta", "alpha", "mid"})
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+
+		var decoded ClaimManifest
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.NoError(t, decoded.Validate())
+		assert.Equal(t, []string{"zeta", "alpha", "mid"}, decoded.Keys())
+
+		index, ok := decoded.Index("alpha")
+		assert.True(t, ok)
+		assert.Equal(t, 2, index)
+		_, ok = decoded.Index("missing")
+		assert.False(t, ok)
+
+		original, err := manifest.Message()
+		require.NoError(t, err)
+		roundTripped, err := decoded.Message()
+		require.NoError(t, err)
+		assert.Equal(t, original, roundTripped)
+	})
+
+	t.Run("Invalid Manifests", func(t *testing.T) {
+		assert.Error(t, (&ClaimManifest{}).Validate())
+		assert.Error(t, (&ClaimManifest{Claims: []ManifestEntry{{Key: "a", Index: 1}, {Key: "a", Index: 2}}}).Validate())
+		assert.Error(t, (&ClaimManifest{Claims: []ManifestEntry{{Key: "a", Index: 1}, {Key: "b", Index: 1}}}).Validate())
+		assert.Error(t, (&ClaimManifest{Claims: []ManifestEntry{{Key: "a", Index: 0}}}).Validate())
+		assert.Error(t, (&ClaimManifest{Claims: []ManifestEntry{{Key: "", Index: 1}}}).Validate())
+	})
+
+	t.Run("Disclosure Restrictions", func(t *testing.T) {
+		manifest := NewClaimManifest([]string{"firstName", "idNumber", "dateOfBirth"})
+		unrestricted, err := manifest.Message()
+		require.NoError(t, err)
+
+		require.NoError(t, manifest.Restrict("idNumber", schema.DisclosureNever))
+		require.NoError(t, manifest.Restrict("dateOfBirth", schema.DisclosureNotAlone))
+		assert.Error(t, manifest.Restrict("missing", schema.DisclosureNever))
+		assert.Error(t, manifest.Restrict("firstName", "sometimes"))
+
+		restricted, err := manifest.Message()
+		require.NoError(t, err)
+		assert.NotEqual(t, unrestricted, restricted, "restrictions are signed with the manifest")
+
+		assert.NoError(t, manifest.CheckDisclosure([]string{"firstName"}))
+		assert.NoError(t, manifest.CheckDisclosure([]string{"firstName", "dateOfBirth"}))
+		assert.ErrorIs(t, manifest.CheckDisclosure([]string{"dateOfBirth"}), ErrDisclosureRestricted)
+		assert.ErrorIs(t, manifest.CheckDisclosure([]string{"firstName", "idNumber"}), ErrDisclosureRestricted)
+		assert.NoError(t, manifest.CheckDisclosure(nil))
+	})
+}
@@ -0,0 +1,69 @@
+package vc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// PredicateOperator compares a committed claim with a public value
+type PredicateOperator string
+
+const (
+	PredicateGreaterThan    PredicateOperator = "gt"
+	PredicateGreaterOrEqual PredicateOperator = "gte"
+	PredicateLessThan       PredicateOperator = "lt"
+	PredicateLessOrEqual    PredicateOperator = "lte"
+)
+
+// PredicateStatement is a comparison a holder proves about a hidden claim, e.g. salary gt 50000.
+// Values compare with the claim's committed encoding, so dates are written as YYYYMMDD:
+// dateOfBirth lt 20071016 proves the holder was born before 16 October 2007.
+type PredicateStatement struct {
+	Claim    string            `json:"claim"`
+	Operator PredicateOperator `json:"operator"`
+	Value    int64             `json:"value"`
+}
+
+// PredicateProof is a zero-knowledge proof that a committed claim satisfies a statement.
+// Provider names the proof system, so verifiers can route the proof to the right verifier.
+type PredicateProof struct {
+	PredicateStatement
+	Provider   string `json:"provider"`
+	ProofValue []byte `json:"proofValue"`
+}
+
+// Validate checks that the statement names a claim and a known operator
+func (s PredicateStatement) Validate() error {
+	if s.Claim == "" {
+		return fmt.Errorf("predicate claim is required")
+	}
+
+	switch s.Operator {
+	case PredicateGreaterThan, PredicateGreaterOrEqual, PredicateLessThan, PredicateLessOrEqual:
+		return nil
+	default:
+		return fmt.Errorf("unknown predicate operator: %s", s.Operator)
+	}
+}
+
+// String returns the statement as "claim operator value"
+func (s PredicateStatement) String() string {
+	return fmt.Sprintf("%s %s %d", s.Claim, s.Operator, s.Value)
+}
+
+// PredicateContext returns the bytes a predicate proof is bound to: the statement, the credential,
+// the commitment and the presentation nonce. A proof cannot be replayed in another presentation
+// or moved to another statement.
+func PredicateContext(credentialID string, statement PredicateStatement, commitment []byte, nonce string) []byte {
+	h := sha256.New()
+	h.Write([]byte("BBS_PREDICATE_PROOF"))
+	for _, part := range []string{credentialID, statement.Claim, string(statement.Operator), nonce} {
+		binary.Write(h, binary.BigEndian, uint32(len(part)))
+		h.Write([]byte(part))
+	}
+	binary.Write(h, binary.BigEndian, statement.Value)
+	h.Write(commitment)
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,81 @@
+package vc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+func TestPresentationRepository(t *testing.T) {
+	t.Run("In Memory", func(t *testing.T) {
+		testPresentationRepository(t, NewInMemoryPresentationRepository())
+	})
+	t.Run("Key Value Store", func(t *testing.T) {
+		testPresentationRepository(t, NewKVPresentationRepository(storage.NewMemoryStore()))
+	})
+}
+
+func testPresentationRepository(t *testing.T, repo PresentationRepository) {
+	start := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+	record := func(id, holder, verifier string, at time.Duration, valid bool) *PresentationRecord {
+		return &PresentationRecord{
+			Presentation: &VerifiablePresentation{ID: id, Holder: holder},
+			VerifierDID:  verifier,
+			VerifiedAt:   start.Add(at),
+			Valid:        valid,
+		}
+	}
+
+	require.NoError(t, repo.Store(record("vp1", "did:example:alice", "did:example:bar", 0, true)))
+	require.NoError(t, repo.Store(record("vp2", "did:example:bob", "did:example:bar", time.Hour, false)))
+	require.NoError(t, repo.Store(record("vp3", "did:example:alice", "did:example:shop", 2*time.Hour, true)))
+	require.NoError(t, repo.Store(record("vp1", "did:example:alice", "did:example:bar", 3*time.Hour, false)))
+
+	ids := func(records []*PresentationRecord) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.Presentation.ID)
+		}
+		return result
+	}
+	valid, invalid := true, false
+	after, before := start.Add(time.Hour), start.Add(2*time.Hour)
+
+	for name, tc := range map[string]struct {
+		filter   PresentationFilter
+		expected []string
+	}{
+		"All":            {PresentationFilter{}, []string{"vp1", "vp2", "vp3", "vp1"}},
+		"By Verifier":    {PresentationFilter{VerifierDID: "did:example:bar"}, []string{"vp1", "vp2", "vp1"}},
+		"By Holder":      {PresentationFilter{HolderDID: "did:example:alice"}, []string{"vp1", "vp3", "vp1"}},
+		"Valid Only":     {PresentationFilter{VerifierDID: "did:example:bar", Valid: &valid}, []string{"vp1"}},
+		"Invalid Only":   {PresentationFilter{Valid: &invalid}, []string{"vp2", "vp1"}},
+		"Time Window":    {PresentationFilter{VerifiedAfter: &after, VerifiedBefore: &before}, []string{"vp2", "vp3"}},
+		"Unknown Holder": {PresentationFilter{HolderDID: "did:example:carol"}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			records, err := repo.List(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ids(records))
+		})
+	}
+
+	t.Run("Retrieve Returns Latest Record", func(t *testing.T) {
+		latest, err := repo.Retrieve("vp1")
+		require.NoError(t, err)
+		assert.False(t, latest.Valid)
+		assert.Equal(t, start.Add(3*time.Hour), latest.VerifiedAt)
+
+		_, err = repo.Retrieve("vp9")
+		assert.Error(t, err)
+	})
+
+	t.Run("Nil Presentation Rejected", func(t *testing.T) {
+		assert.Error(t, repo.Store(&PresentationRecord{}))
+		assert.Error(t, repo.Store(nil))
+	})
+}
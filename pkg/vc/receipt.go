@@ -0,0 +1,83 @@
+package vc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// VerificationReceipt is a verifier-signed statement that a presentation was checked
+type VerificationReceipt struct {
+	ID               string         `json:"id"`
+	Type             []string       `json:"type"`
+	VerifierDID      string         `json:"verifier"`
+	HolderDID        string         `json:"holder"`
+	PresentationID   string         `json:"presentationId"`
+	PresentationHash string         `json:"presentationHash"`
+	Timestamp        time.Time      `json:"timestamp"`
+	Outcome          ReceiptOutcome `json:"outcome"`
+	Proof            *Proof         `json:"proof,omitempty"`
+}
+
+// ReceiptOutcome records the policy that was applied and its result
+type ReceiptOutcome struct {
+	Valid          bool     `json:"valid"`
+	RequiredClaims []string `json:"requiredClaims,omitempty"`
+	OptionalClaims []string `json:"optionalClaims,omitempty"`
+	TrustedIssuers []string `json:"trustedIssuers,omitempty"`
+	RevealedClaims []string `json:"revealedClaims,omitempty"`
+	Purpose        string   `json:"purpose,omitempty"`
+}
+
+// PresentationHash returns the hex SHA-256 digest of a presentation's JSON encoding
+func PresentationHash(vp *VerifiablePresentation) (string, error) {
+	if vp == nil {
+		return "", fmt.Errorf("presentation is nil")
+	}
+
+	data, err := json.Marshal(vp)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal presentation: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// CredentialHash returns the hex SHA-256 digest of a credential's JSON encoding
+func CredentialHash(credential *VerifiableCredential) (string, error) {
+	if credential == nil {
+		return "", fmt.Errorf("credential is nil")
+	}
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal credential: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// ReceiptSigningInput returns the bytes covered by the verifier's receipt proof
+func ReceiptSigningInput(receipt *VerificationReceipt) ([]byte, error) {
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt is nil")
+	}
+
+	unsigned := *receipt
+	if receipt.Proof != nil {
+		proof := *receipt.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+
+	return data, nil
+}
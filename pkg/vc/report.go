@@ -0,0 +1,74 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// VerificationReport is a verifier-signed account of one verification: the policy evaluated, what
+// was revealed and who issued it. Relying parties archive it as compliance evidence.
+type VerificationReport struct {
+	ID               string   `json:"id"`
+	Type             []string `json:"type"`
+	VerifierDID      string   `json:"verifier"`
+	HolderDID        string   `json:"holder"`
+	PresentationID   string   `json:"presentationId"`
+	PresentationHash string   `json:"presentationHash"`
+	// PresentedAt is when the holder signed the presentation, if it was signed
+	PresentedAt *time.Time   `json:"presentedAt,omitempty"`
+	VerifiedAt  time.Time    `json:"verifiedAt"`
+	Policy      ReportPolicy `json:"policy"`
+	Valid       bool         `json:"valid"`
+	Errors      []string     `json:"errors,omitempty"`
+	// RevealedClaims are the claim values the holder disclosed
+	RevealedClaims   map[string]interface{} `json:"revealedClaims"`
+	ProvenPredicates []PredicateStatement   `json:"provenPredicates,omitempty"`
+	// Issuers traces each presented credential back to its issuer
+	Issuers []ReportIssuer `json:"issuers"`
+	Proof   *Proof         `json:"proof,omitempty"`
+}
+
+// ReportPolicy is the verification policy a report was evaluated against
+type ReportPolicy struct {
+	RequiredClaims     []string             `json:"requiredClaims,omitempty"`
+	OptionalClaims     []string             `json:"optionalClaims,omitempty"`
+	TrustedIssuers     []string             `json:"trustedIssuers,omitempty"`
+	TrustedDomains     []string             `json:"trustedDomains,omitempty"`
+	RequiredPredicates []PredicateStatement `json:"requiredPredicates,omitempty"`
+	Purpose            string               `json:"purpose,omitempty"`
+	Strict             bool                 `json:"strict,omitempty"`
+}
+
+// ReportIssuer identifies who issued one presented credential and with which key
+type ReportIssuer struct {
+	CredentialIndex int    `json:"credentialIndex"`
+	CredentialID    string `json:"credentialId,omitempty"`
+	// IssuerDID is empty when the holder hid the issuer within IssuerSet
+	IssuerDID string   `json:"issuer,omitempty"`
+	IssuerSet []string `json:"issuerSet,omitempty"`
+	// VerificationMethod is the issuer key the credential's proof names
+	VerificationMethod string   `json:"verificationMethod,omitempty"`
+	CredentialTypes    []string `json:"credentialTypes,omitempty"`
+}
+
+// ReportSigningInput returns the bytes covered by the verifier's report proof
+func ReportSigningInput(report *VerificationReport) ([]byte, error) {
+	if report == nil {
+		return nil, fmt.Errorf("report is nil")
+	}
+
+	unsigned := *report
+	if report.Proof != nil {
+		proof := *report.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,81 @@
+package vc
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// AttributeSaltSize is the length of the random salt each claim value is signed with
+const AttributeSaltSize = 16
+
+// NewAttributeSalt draws the salt a claim value is signed with. Salting every message means a
+// hidden low-entropy value, such as a boolean or a nationality, cannot be found by signing
+// candidate values and comparing them with what a proof leaks.
+func NewAttributeSalt() ([]byte, error) {
+	salt := make([]byte, AttributeSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate attribute salt: %w", err)
+	}
+	return salt, nil
+}
+
+// SaltMessage returns the signed message for a claim: its salt followed by the canonical value.
+// Salts have a fixed length, so the value is recovered unambiguously.
+func SaltMessage(salt []byte, message []byte) ([]byte, error) {
+	if len(salt) != AttributeSaltSize {
+		return nil, fmt.Errorf("attribute salt must be %d bytes, got %d", AttributeSaltSize, len(salt))
+	}
+
+	salted := make([]byte, 0, len(salt)+len(message))
+	salted = append(salted, salt...)
+	return append(salted, message...), nil
+}
+
+// SaltedClaimMessages rebuilds the signed messages for the given claims of a credential subject
+// issued with attribute salts, as ClaimMessages does for unsalted credentials
+func SaltedClaimMessages(subject map[string]interface{}, salts map[string][]byte, keys []string) ([][]byte, error) {
+	messages, err := ClaimMessages(subject, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		salt, ok := salts[key]
+		if !ok {
+			return nil, fmt.Errorf("claim %s has no attribute salt", key)
+		}
+		if messages[i], err = SaltMessage(salt, messages[i]); err != nil {
+			return nil, fmt.Errorf("claim %s: %w", key, err)
+		}
+	}
+	return messages, nil
+}
+
+// DisclosedSalts returns the attribute salts of the given claims. Credentials issued without
+// salts disclose none.
+func DisclosedSalts(credential *VerifiableCredential, keys []string) (map[string][]byte, error) {
+	if len(credential.AttributeSalts) == 0 {
+		return nil, nil
+	}
+
+	disclosed := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		salt, ok := credential.AttributeSalts[key]
+		if !ok {
+			return nil, fmt.Errorf("claim %s has no attribute salt", key)
+		}
+		disclosed[key] = salt
+	}
+	return disclosed, nil
+}
+
+// CheckDisclosedSalts checks the attribute salts presented with a derived credential are the
+// size the issuer draws them at
+func CheckDisclosedSalts(salts map[string][]byte) error {
+	for key, salt := range salts {
+		if len(salt) != AttributeSaltSize {
+			return fmt.Errorf("attribute salt for %s must be %d bytes, got %d", key, AttributeSaltSize, len(salt))
+		}
+	}
+	return nil
+}
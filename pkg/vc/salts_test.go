@@ -0,0 +1,66 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeSalts(t *testing.T) {
+	t.Run("Equal Values Sign Different Messages", func(t *testing.T) {
+		first, err := NewAttributeSalt()
+		require.NoError(t, err)
+		second, err := NewAttributeSalt()
+		require.NoError(t, err)
+
+		a, err := SaltMessage(first, []byte("true"))
+		require.NoError(t, err)
+		b, err := SaltMessage(second, []byte("true"))
+		require.NoError(t, err)
+		assert.NotEqual(t, a, b)
+		assert.Equal(t, []byte("true"), a[AttributeSaltSize:])
+
+		_, err = SaltMessage([]byte("short"), []byte("true"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Disclosed Salts Rebuild Messages", func(t *testing.T) {
+		salt, err := NewAttributeSalt()
+		require.NoError(t, err)
+		credential := &VerifiableCredential{
+			CredentialSubject: map[string]interface{}{"id": "did:example:holder", "nationality": "VN", "age": int64(25)},
+			AttributeSalts:    map[string][]byte{"nationality": salt, "age": salt},
+		}
+
+		disclosed, err := DisclosedSalts(credential, []string{"nationality"})
+		require.NoError(t, err)
+		assert.Len(t, disclosed, 1)
+
+		// Round-trip through JSON as a verifier receiving the salts would
+		data, err := json.Marshal(disclosed)
+		require.NoError(t, err)
+		var salts map[string][]byte
+		require.NoError(t, json.Unmarshal(data, &salts))
+		require.NoError(t, CheckDisclosedSalts(salts))
+
+		messages, err := SaltedClaimMessages(credential.CredentialSubject, salts, []string{"nationality"})
+		require.NoError(t, err)
+		assert.Equal(t, append(append([]byte{}, salt...), "VN"...), messages[0])
+
+		_, err = SaltedClaimMessages(credential.CredentialSubject, salts, []string{"age"})
+		assert.Error(t, err, "claims without a disclosed salt cannot be rebuilt")
+	})
+
+	t.Run("Unsalted Credentials Disclose None", func(t *testing.T) {
+		disclosed, err := DisclosedSalts(&VerifiableCredential{}, []string{"nationality"})
+		require.NoError(t, err)
+		assert.Nil(t, disclosed)
+	})
+
+	t.Run("Malformed Salts Are Rejected", func(t *testing.T) {
+		assert.Error(t, CheckDisclosedSalts(map[string][]byte{"nationality": []byte("short")}))
+		assert.NoError(t, CheckDisclosedSalts(nil))
+	})
+}
@@ -1,13 +1,21 @@
 package vc
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
 )
 
 // ServiceImpl implements CredentialService interface
@@ -15,88 +23,235 @@ type ServiceImpl struct {
 	bbsService bbs.BBSService
 	credRepo   CredentialRepository
 	presRepo   PresentationRepository
-	keyStore   map[string]*bbs.KeyPair // DID -> KeyPair mapping
+	clock      clock.Clock
+
+	// keyStore maps issuer DID -> current signing key
+	keyStore storage.KVStore
+}
+
+// issuerKey is an issuer's current BBS+ key pair and the verification method it is published under
+type issuerKey struct {
+	KeyID   string       `json:"keyId"`
+	KeyPair *bbs.KeyPair `json:"keyPair"`
 }
 
-// NewService creates a new credential service
+// NewService creates a new credential service that dates credentials and proofs by the system clock
 func NewService(bbsService bbs.BBSService, credRepo CredentialRepository, presRepo PresentationRepository) CredentialService {
+	return NewServiceWithClock(bbsService, credRepo, presRepo, clock.System{})
+}
+
+// NewServiceWithClock creates a new credential service that dates credentials and proofs by c
+func NewServiceWithClock(bbsService bbs.BBSService, credRepo CredentialRepository, presRepo PresentationRepository, c clock.Clock) CredentialService {
+	return NewServiceWithKeyStore(bbsService, credRepo, presRepo, c, storage.NewMemoryStore())
+}
+
+// NewServiceWithKeyStore creates a new credential service that keeps issuers' BBS+ signing keys in
+// keys, so every service sharing the store can issue for them
+func NewServiceWithKeyStore(bbsService bbs.BBSService, credRepo CredentialRepository, presRepo PresentationRepository, c clock.Clock, keys storage.KVStore) CredentialService {
 	return &ServiceImpl{
 		bbsService: bbsService,
 		credRepo:   credRepo,
 		presRepo:   presRepo,
-		keyStore:   make(map[string]*bbs.KeyPair),
+		clock:      clock.OrSystem(c),
+		keyStore:   keys,
 	}
 }
 
-// SetIssuerKeyPair sets the BBS+ key pair for an issuer DID
-func (s *ServiceImpl) SetIssuerKeyPair(issuerDID string, keyPair *bbs.KeyPair) {
-	s.keyStore[issuerDID] = keyPair
+// SetIssuerKeyPair sets the BBS+ key pair for an issuer DID under the first key version
+func (s *ServiceImpl) SetIssuerKeyPair(issuerDID string, keyPair *bbs.KeyPair) error {
+	return s.SetIssuerKey(issuerDID, issuerDID+"#bbs-key-1", keyPair)
+}
+
+// SetIssuerKey sets the BBS+ key pair an issuer signs new credentials with, and the
+// verification method ID credentials signed with it point at
+func (s *ServiceImpl) SetIssuerKey(issuerDID string, keyID string, keyPair *bbs.KeyPair) error {
+	if err := storage.SetJSON(s.keyStore, issuerDID, &issuerKey{KeyID: keyID, KeyPair: keyPair}, 0); err != nil {
+		return fmt.Errorf("failed to store issuer key: %w", err)
+	}
+	return nil
+}
+
+// IssuanceOptions are what an issuer states about a new credential besides its claims. They are
+// signed with the claims, so they cannot be changed once the credential is issued.
+type IssuanceOptions struct {
+	// Types are the credential's types, VerifiableCredential first; just VerifiableCredential when empty
+	Types       []string
+	IssuerName  string
+	IssuerImage string
+	// Version is the data model the credential is issued under, 1.1 when empty
+	Version Version
+	// ValidUntil is when the credential expires; it does not when nil
+	ValidUntil *time.Time
+	// IssuerSet is the anonymity set holders may hide the issuer within; its keys are signed in
+	// place of the issuer's name and image
+	IssuerSet *IssuerSetProof
 }
 
 // IssueCredential creates and signs a new verifiable credential
 func (s *ServiceImpl) IssueCredential(issuerDID string, subjectDID string, claims []Claim) (*VerifiableCredential, error) {
-	keyPair, exists := s.keyStore[issuerDID]
-	if !exists {
-		return nil, fmt.Errorf("no key pair found for issuer DID: %s", issuerDID)
+	return s.IssueCredentialContext(context.Background(), issuerDID, subjectDID, claims, IssuanceOptions{})
+}
+
+// IssueCredentialContext creates and signs a new verifiable credential, tracing the BBS+ signature
+func (s *ServiceImpl) IssueCredentialContext(ctx context.Context, issuerDID string, subjectDID string, claims []Claim, options IssuanceOptions) (*VerifiableCredential, error) {
+	types := options.Types
+	if len(types) == 0 {
+		types = []string{"VerifiableCredential"}
+	} else if types[0] != "VerifiableCredential" {
+		return nil, fmt.Errorf("credential types must start with VerifiableCredential")
+	}
+
+	var key issuerKey
+	if err := storage.GetJSON(s.keyStore, issuerDID, &key); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("no key pair found for issuer DID: %s", issuerDID)
+		}
+		return nil, fmt.Errorf("failed to load issuer key: %w", err)
 	}
 
 	// Create credential subject
 	credentialSubject := make(map[string]interface{})
 	credentialSubject["id"] = subjectDID
 
-	// Convert claims to messages for BBS+ signing
+	// Convert claims to messages for BBS+ signing, each prefixed with its own salt
 	var messages [][]byte
 	var claimKeys []string
+	restrictions := make(map[string]schema.DisclosureRestriction)
+	salts := make(map[string][]byte)
+	salt := func(key string, message []byte) error {
+		value, err := NewAttributeSalt()
+		if err != nil {
+			return err
+		}
+		salted, err := SaltMessage(value, message)
+		if err != nil {
+			return err
+		}
+		salts[key] = value
+		messages = append(messages, salted)
+		return nil
+	}
 
 	for _, claim := range claims {
-		credentialSubject[claim.Key] = claim.Value
-		claimKeys = append(claimKeys, claim.Key)
+		// The confirmation claim is an object binding the credential to a holder key
+		if claim.Key == ConfirmationClaim {
+			confirmation, err := ParseConfirmation(claim.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid claim: %w", err)
+			}
+			if _, exists := credentialSubject[ConfirmationClaim]; exists {
+				return nil, fmt.Errorf("duplicate claim: %s", ConfirmationClaim)
+			}
+			credentialSubject[ConfirmationClaim] = confirmation.Value()
+			claimKeys = append(claimKeys, ConfirmationClaim)
+			if err := salt(ConfirmationClaim, confirmation.Message()); err != nil {
+				return nil, err
+			}
+			continue
+		}
 
-		// Convert claim value to bytes
-		valueBytes, err := json.Marshal(claim.Value)
+		// Coerce the value to its canonical form so equal values always sign the same bytes
+		normalized, err := NormalizeClaim(claim)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal claim value: %w", err)
+			return nil, fmt.Errorf("invalid claim: %w", err)
+		}
+
+		if _, exists := credentialSubject[normalized.Key]; exists {
+			return nil, fmt.Errorf("duplicate claim: %s", normalized.Key)
+		}
+
+		if err := claim.Disclosure.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid claim %s: %w", claim.Key, err)
+		}
+		if claim.Disclosure != "" {
+			restrictions[normalized.Key] = claim.Disclosure
+		}
+
+		credentialSubject[normalized.Key] = normalized.Value
+		claimKeys = append(claimKeys, normalized.Key)
+
+		valueBytes, err := EncodeClaimValue(normalized.Type, normalized.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode claim value: %w", err)
+		}
+		if err := salt(normalized.Key, valueBytes); err != nil {
+			return nil, err
 		}
-		messages = append(messages, valueBytes)
 	}
 
-	// Create the credential
-	now := time.Now()
+	// Create the credential under the 1.1 data model, then move it to the requested one so the
+	// dates are named as that data model names them before they are signed
+	now := s.clock.Now()
 	credential := &VerifiableCredential{
-		Context: []string{
-			"https://www.w3.org/2018/credentials/v1",
-			"https://w3id.org/security/bbs/v1",
-		},
-		ID:                uuid.New().String(),
-		Type:              []string{"VerifiableCredential"},
-		Issuer:            issuerDID,
+		Context:           Version1.Contexts(),
+		ID:                "urn:uuid:" + uuid.New().String(),
+		Type:              append([]string(nil), types...),
+		IssuerInfo:        Issuer{ID: issuerDID, Name: options.IssuerName, Image: options.IssuerImage},
 		IssuanceDate:      now,
 		CredentialSubject: credentialSubject,
+		AttributeSalts:    salts,
+		ClaimManifest:     NewClaimManifest(claimKeys),
+	}
+	if options.IssuerSet != nil {
+		credential.IssuerSetProof = options.IssuerSet.withoutProof()
 	}
 
+	// The issuer's disclosure restrictions are signed with the manifest, so holders cannot drop them
+	for key, restriction := range restrictions {
+		if err := credential.ClaimManifest.Restrict(key, restriction); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sign the manifest first so the claims' message indices survive serialization
+	manifestMessage, err := credential.ClaimManifest.Message()
+	if err != nil {
+		return nil, err
+	}
+	messages = append([][]byte{manifestMessage}, messages...)
+
+	if options.Version != "" {
+		if err := credential.SetVersion(options.Version); err != nil {
+			return nil, err
+		}
+	}
+	if options.ValidUntil != nil {
+		if err := credential.SetExpiry(*options.ValidUntil); err != nil {
+			return nil, err
+		}
+	}
+
+	// The metadata is signed last, after the claims the manifest numbers
+	metadata, err := credential.Metadata()
+	if err != nil {
+		return nil, err
+	}
+	metadataMessage, err := metadata.Message()
+	if err != nil {
+		return nil, err
+	}
+	messages = append(messages, metadataMessage)
+
 	// Sign with BBS+
-	signature, err := s.bbsService.Sign(keyPair.PrivateKey, messages)
+	_, span := tracing.Start(ctx, "bbs.Sign", tracing.Int("bbs.messages", len(messages)))
+	signature, err := s.bbsService.Sign(key.KeyPair.PrivateKey, messages)
+	span.RecordError(err)
+	span.End()
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign credential: %w", err)
 	}
+	proofValue, err := bbs.EncodeSignature(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signature: %w", err)
+	}
 
 	// Create proof
 	credential.Proof = &Proof{
 		Type:               "BbsBlsSignature2020",
 		Created:            now,
-		VerificationMethod: issuerDID + "#bbs-key-1",
+		VerificationMethod: key.KeyID,
 		ProofPurpose:       "assertionMethod",
-		ProofValue: bbs.EncodeProof(&bbs.Proof{
-			A_prime: signature.A,
-			A_bar:   signature.E,
-			C:       signature.S,
-		}),
-	}
-
-	// Store metadata for later proof creation
-	credential.Proof.RevealedAttributes = make([]int, len(claims))
-	for i := range claims {
-		credential.Proof.RevealedAttributes[i] = i
+		ProofValue:         proofValue,
 	}
 
 	return credential, nil
@@ -123,17 +278,55 @@ func (s *ServiceImpl) VerifyCredential(vc *VerifiableCredential) error {
 
 // CreatePresentation creates a verifiable presentation with selective disclosure
 func (s *ServiceImpl) CreatePresentation(holderDID string, credentials []*VerifiableCredential, disclosureRequests []SelectiveDisclosureRequest) (*VerifiablePresentation, error) {
+	return s.CreatePresentationContext(context.Background(), holderDID, credentials, disclosureRequests)
+}
+
+// CreatePresentationContext creates a verifiable presentation with selective disclosure, tracing each derivation
+func (s *ServiceImpl) CreatePresentationContext(ctx context.Context, holderDID string, credentials []*VerifiableCredential, disclosureRequests []SelectiveDisclosureRequest) (*VerifiablePresentation, error) {
 	if len(credentials) != len(disclosureRequests) {
 		return nil, fmt.Errorf("mismatch between credentials and disclosure requests")
 	}
 
-	var presentedCredentials []interface{}
+	// A 2.0 presentation may embed 1.1 credentials, as its verifiableCredential property resets
+	// the context, but a 1.1 presentation cannot embed 2.0 credentials
+	version := Version1
+	for _, credential := range credentials {
+		credentialVersion, err := credential.Version()
+		if err != nil {
+			return nil, fmt.Errorf("credential %s: %w", credential.ID, err)
+		}
+		if credentialVersion == Version2 {
+			version = Version2
+		}
+	}
+
+	var presentedCredentials []*DerivedCredential
+	// Authorizations shared by several credentials are presented once
+	var authorizations []*VerifiableCredential
+	presentedAuthorizations := make(map[string]bool)
 
 	for i, credential := range credentials {
 		request := disclosureRequests[i]
 
+		// Authorizations name the issuer, so they are left out when the issuer is hidden
+		if !request.HideIssuer {
+			for _, authorization := range credential.Authorizations {
+				if !presentedAuthorizations[authorization.ID] {
+					presentedAuthorizations[authorization.ID] = true
+					authorizations = append(authorizations, authorization)
+				}
+			}
+		}
+
 		// Create selective disclosure proof
+		_, span := tracing.Start(ctx, "vc.DeriveCredential",
+			tracing.String("credential.id", credential.ID),
+			tracing.Int("claims.revealed", len(request.RevealedAttributes)),
+			tracing.Int("claims.total", len(credential.CredentialSubject)-1),
+		)
 		derivedCredential, err := s.createSelectiveDisclosureCredential(credential, request)
+		span.RecordError(err)
+		span.End()
 		if err != nil {
 			return nil, fmt.Errorf("failed to create selective disclosure: %w", err)
 		}
@@ -143,18 +336,16 @@ func (s *ServiceImpl) CreatePresentation(holderDID string, credentials []*Verifi
 
 	// Create presentation
 	presentation := &VerifiablePresentation{
-		Context: []string{
-			"https://www.w3.org/2018/credentials/v1",
-			"https://w3id.org/security/bbs/v1",
-		},
-		ID:                   uuid.New().String(),
+		Context:              version.Contexts(),
+		ID:                   "urn:uuid:" + uuid.New().String(),
 		Type:                 []string{"VerifiablePresentation"},
 		Holder:               holderDID,
 		VerifiableCredential: presentedCredentials,
+		Authorizations:       authorizations,
 	}
 
 	// Add presentation proof (simplified)
-	now := time.Now()
+	now := s.clock.Now()
 	presentation.Proof = &Proof{
 		Type:               "BbsBlsSignatureProof2020",
 		Created:            now,
@@ -166,29 +357,143 @@ func (s *ServiceImpl) CreatePresentation(holderDID string, credentials []*Verifi
 }
 
 // createSelectiveDisclosureCredential creates a derived credential with only revealed attributes
-func (s *ServiceImpl) createSelectiveDisclosureCredential(credential *VerifiableCredential, request SelectiveDisclosureRequest) (map[string]interface{}, error) {
-	// Create derived credential with only revealed attributes
-	derivedCredential := map[string]interface{}{
-		"@context":          credential.Context,
-		"id":                credential.ID,
-		"type":              credential.Type,
-		"issuer":            credential.Issuer,
-		"issuanceDate":      credential.IssuanceDate,
-		"credentialSubject": make(map[string]interface{}),
+func (s *ServiceImpl) createSelectiveDisclosureCredential(credential *VerifiableCredential, request SelectiveDisclosureRequest) (*DerivedCredential, error) {
+	issuer := credential.IssuerInfo
+	derivedCredential := &DerivedCredential{
+		Context:           credential.Context,
+		ID:                credential.ID,
+		Type:              credential.Type,
+		Issuer:            &issuer,
+		CredentialSubject: make(map[string]interface{}),
+	}
+
+	// Validity dates use the property names of the credential's data model
+	if credential.ValidFrom != nil || credential.ValidUntil != nil {
+		derivedCredential.ValidFrom = credential.ValidFrom
+		derivedCredential.ValidUntil = credential.ValidUntil
+	} else {
+		issuanceDate := credential.IssuanceDate
+		derivedCredential.IssuanceDate = &issuanceDate
+		derivedCredential.ExpirationDate = credential.ExpirationDate
+	}
+
+	// The signed metadata names the issuer set, so it is presented whether or not the issuer is
+	if credential.IssuerSetProof != nil {
+		derivedCredential.IssuerSetProof = credential.IssuerSetProof.withoutProof()
+	}
+	if request.HideIssuer {
+		if credential.IssuerSetProof == nil {
+			return nil, fmt.Errorf("credential %s was not issued into an issuer set", credential.ID)
+		}
+		if len(credential.CredentialStatus) > 0 {
+			return nil, fmt.Errorf("credential %s has status entries, which identify its issuer", credential.ID)
+		}
+		derivedCredential.Issuer = nil
+	}
+
+	// Present the co-signatures approving the issuance; they name the issuer, so they are left out
+	// when the issuer is hidden
+	if credential.CoSignatures != nil && !request.HideIssuer {
+		derivedCredential.CoSignatures = &CoSignatureEnvelope{}
+		if err := cloneJSON(credential.CoSignatures, derivedCredential.CoSignatures); err != nil {
+			return nil, fmt.Errorf("failed to encode co-signatures: %w", err)
+		}
+	}
+
+	// Present the commitments, never their openings; the bundle names the issuer
+	if request.IncludeCommitments {
+		if credential.Commitments == nil {
+			return nil, fmt.Errorf("credential %s has no attribute commitments", credential.ID)
+		}
+		if request.HideIssuer {
+			return nil, fmt.Errorf("credential %s: commitments identify the issuer and cannot be presented with a hidden issuer", credential.ID)
+		}
+		derivedCredential.Commitments = &CommitmentBundle{}
+		if err := cloneJSON(credential.Commitments, derivedCredential.Commitments); err != nil {
+			return nil, fmt.Errorf("failed to encode commitments: %w", err)
+		}
+	}
+
+	// Present the evidence descriptions so the verifier knows which documents it may ask for
+	if request.IncludeEvidence {
+		if credential.Evidence == nil {
+			return nil, fmt.Errorf("credential %s has no evidence", credential.ID)
+		}
+		if request.HideIssuer {
+			return nil, fmt.Errorf("credential %s: evidence identifies the issuer and cannot be presented with a hidden issuer", credential.ID)
+		}
+		derivedCredential.Evidence = &EvidenceBundle{}
+		if err := cloneJSON(credential.Evidence, derivedCredential.Evidence); err != nil {
+			return nil, fmt.Errorf("failed to encode evidence: %w", err)
+		}
+	}
+
+	// Prove claims absent against the issuer-signed claim key commitment
+	if len(request.ProveAbsent) > 0 {
+		if request.HideIssuer {
+			return nil, fmt.Errorf("credential %s: the claim key commitment identifies the issuer and cannot be presented with a hidden issuer", credential.ID)
+		}
+
+		proofs := make([]AbsenceProof, len(request.ProveAbsent))
+		for i, claim := range request.ProveAbsent {
+			proof, err := ProveAbsence(credential, claim)
+			if err != nil {
+				return nil, err
+			}
+			proofs[i] = *proof
+		}
+
+		derivedCredential.ClaimKeys = &ClaimKeyCommitment{}
+		if err := cloneJSON(credential.ClaimKeys, derivedCredential.ClaimKeys); err != nil {
+			return nil, fmt.Errorf("failed to encode claim key commitment: %w", err)
+		}
+		derivedCredential.AbsenceProofs = proofs
+	}
+
+	// Predicates are proven by the holder against the presented commitments
+	if len(request.Predicates) > 0 && !request.IncludeCommitments {
+		return nil, fmt.Errorf("credential %s: predicates need includeCommitments", credential.ID)
+	}
+	for _, statement := range request.Predicates {
+		if err := statement.Validate(); err != nil {
+			return nil, fmt.Errorf("credential %s: %w", credential.ID, err)
+		}
 	}
 
 	// Include subject ID
 	if subjectID, ok := credential.CredentialSubject["id"]; ok {
-		derivedCredential["credentialSubject"].(map[string]interface{})["id"] = subjectID
+		derivedCredential.CredentialSubject["id"] = subjectID
+	}
+
+	// The confirmation claim is always disclosed so the verifier can check proof of possession
+	if confirmation, ok := credential.CredentialSubject[ConfirmationClaim]; ok {
+		derivedCredential.CredentialSubject[ConfirmationClaim] = confirmation
 	}
 
 	// Include only revealed attributes
+	disclosed := make([]string, 0, len(request.RevealedAttributes)+1)
+	if _, ok := credential.CredentialSubject[ConfirmationClaim]; ok {
+		disclosed = append(disclosed, ConfirmationClaim)
+	}
 	for _, attr := range request.RevealedAttributes {
 		if value, exists := credential.CredentialSubject[attr]; exists {
-			derivedCredential["credentialSubject"].(map[string]interface{})[attr] = value
+			derivedCredential.CredentialSubject[attr] = value
+			if attr != "id" && attr != ConfirmationClaim {
+				disclosed = append(disclosed, attr)
+			}
 		}
 	}
 
+	// Disclose the salts of the revealed claims so the verifier can rebuild their signed messages
+	salts, err := DisclosedSalts(credential, disclosed)
+	if err != nil {
+		return nil, fmt.Errorf("credential %s: %w", credential.ID, err)
+	}
+	derivedCredential.AttributeSalts = salts
+
+	// Carry the status entries so verifiers can check revocation and suspension
+	derivedCredential.CredentialStatus = append([]status.Entry(nil), credential.CredentialStatus...)
+
 	// Use provided nonce or generate one if not provided
 	var nonceStr string
 	if request.Nonce != "" {
@@ -202,22 +507,52 @@ func (s *ServiceImpl) createSelectiveDisclosureCredential(credential *Verifiable
 		nonceStr = fmt.Sprintf("%x", nonce)
 	}
 
-	// Create selective disclosure proof
-	// In a real implementation, this would use the original BBS+ signature
-	// to create a proof for only the revealed attributes
-	derivedCredential["proof"] = map[string]interface{}{
-		"type":               "BbsBlsSignatureProof2020",
-		"created":            time.Now(),
-		"verificationMethod": credential.Proof.VerificationMethod,
-		"proofPurpose":       "assertionMethod",
-		"proofValue":         "derived-proof-placeholder",
-		"nonce":              nonceStr,
-		"revealedAttributes": request.RevealedAttributes,
+	// Create selective disclosure proof; the signing key would identify a hidden issuer
+	derivedCredential.Proof = &Proof{
+		Type:               BBSProofSuite,
+		Created:            s.clock.Now(),
+		ProofPurpose:       "assertionMethod",
+		Nonce:              nonceStr,
+		RevealedAttributes: request.RevealedAttributes,
+	}
+	if !request.HideIssuer {
+		derivedCredential.Proof.VerificationMethod = credential.Proof.VerificationMethod
+	}
+	if request.HideIssuer && request.IssuerPublicKey == nil {
+		return nil, fmt.Errorf("credential %s: hiding the issuer needs a BBS+ proof under the issuer's key", credential.ID)
+	}
+	if request.IssuerPublicKey != nil {
+		var setKeys [][]byte
+		if request.HideIssuer {
+			if len(request.IssuerSetKeys) != len(credential.IssuerSetProof.VerificationMethods) {
+				return nil, fmt.Errorf("credential %s: hiding the issuer needs the keys of its issuer set", credential.ID)
+			}
+			setKeys = request.IssuerSetKeys
+		}
+		proofValue, membership, ordered, err := s.deriveProof(credential, disclosed, request.IssuerPublicKey, setKeys, nonceStr, request.ProofTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("credential %s: %w", credential.ID, err)
+		}
+		derivedCredential.Proof.ProofValue = proofValue
+		derivedCredential.Proof.DisclosedClaims = ordered
+		if membership != nil {
+			derivedCredential.IssuerSetProof.ProofValue = membership
+		}
 	}
 
 	return derivedCredential, nil
 }
 
+// cloneJSON copies src into dst through its JSON form. Derived credentials hold copies of the
+// credential's sections, so changes to a presentation never reach the holder's stored credential.
+func cloneJSON(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
 // VerifyPresentation verifies a verifiable presentation
 func (s *ServiceImpl) VerifyPresentation(vp *VerifiablePresentation) error {
 	if vp == nil {
@@ -228,44 +563,84 @@ func (s *ServiceImpl) VerifyPresentation(vp *VerifiablePresentation) error {
 		return fmt.Errorf("presentation has no proof")
 	}
 
-	// Verify each credential in the presentation
-	for _, credInterface := range vp.VerifiableCredential {
-		// In a real implementation, you would:
-		// 1. Parse the derived credential
-		// 2. Verify the selective disclosure proof
-		// 3. Ensure only requested attributes are revealed
-		_ = credInterface
+	if _, err := VersionOf(vp.Context); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// PresentationSigningInput returns the bytes covered by the holder's presentation proof.
+// The proof value itself is excluded so the same input can be rebuilt by the verifier.
+func PresentationSigningInput(vp *VerifiablePresentation) ([]byte, error) {
+	if vp == nil {
+		return nil, fmt.Errorf("presentation is nil")
+	}
+
+	unsigned := *vp
+	if vp.Proof != nil {
+		proof := *vp.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal presentation: %w", err)
+	}
+
+	return data, nil
+}
+
 // InMemoryCredentialRepository implements CredentialRepository interface
 type InMemoryCredentialRepository struct {
 	credentials map[string]*VerifiableCredential
+	// tombstones marks deleted credentials, which are purged retention after deletion
+	tombstones map[string]storage.Tombstone
+	retention  time.Duration
 }
 
-// NewInMemoryCredentialRepository creates a new in-memory credential repository
+// NewInMemoryCredentialRepository creates a new in-memory credential repository that keeps
+// deleted credentials
 func NewInMemoryCredentialRepository() CredentialRepository {
+	return NewInMemoryCredentialRepositoryWithRetention(0)
+}
+
+// NewInMemoryCredentialRepositoryWithRetention creates a new in-memory credential repository
+// that purges deleted credentials retention after their deletion; zero keeps them
+func NewInMemoryCredentialRepositoryWithRetention(retention time.Duration) CredentialRepository {
 	return &InMemoryCredentialRepository{
 		credentials: make(map[string]*VerifiableCredential),
+		tombstones:  make(map[string]storage.Tombstone),
+		retention:   retention,
 	}
 }
 
+// Ping reports whether the repository is reachable; an in-memory repository always is
+func (r *InMemoryCredentialRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
 // Store stores a verifiable credential
 func (r *InMemoryCredentialRepository) Store(vc *VerifiableCredential) error {
 	if vc == nil {
 		return fmt.Errorf("credential is nil")
 	}
+	if err := r.checkActive(vc.ID); err != nil {
+		return err
+	}
 	r.credentials[vc.ID] = vc
 	return nil
 }
 
 // Retrieve retrieves a verifiable credential by ID
 func (r *InMemoryCredentialRepository) Retrieve(id string) (*VerifiableCredential, error) {
+	if err := r.checkActive(id); err != nil {
+		return nil, err
+	}
 	vc, exists := r.credentials[id]
 	if !exists {
-		return nil, fmt.Errorf("credential not found: %s", id)
+		return nil, credentialNotFound(id)
 	}
 	return vc, nil
 }
@@ -273,7 +648,10 @@ func (r *InMemoryCredentialRepository) Retrieve(id string) (*VerifiableCredentia
 // List lists all credentials for a holder DID
 func (r *InMemoryCredentialRepository) List(holderDID string) ([]*VerifiableCredential, error) {
 	var credentials []*VerifiableCredential
-	for _, vc := range r.credentials {
+	for id, vc := range r.credentials {
+		if r.checkActive(id) != nil {
+			continue
+		}
 		if subjectID, ok := vc.CredentialSubject["id"].(string); ok && subjectID == holderDID {
 			credentials = append(credentials, vc)
 		}
@@ -281,43 +659,81 @@ func (r *InMemoryCredentialRepository) List(holderDID string) ([]*VerifiableCred
 	return credentials, nil
 }
 
+// Delete keeps a credential with the tombstone recording its deletion
+func (r *InMemoryCredentialRepository) Delete(id string, tombstone storage.Tombstone) error {
+	if _, err := r.Retrieve(id); err != nil {
+		return err
+	}
+	r.tombstones[id] = tombstone.Stamped(time.Now())
+	return nil
+}
+
+// checkActive fails for a deleted credential, purging it once the retention has passed
+func (r *InMemoryCredentialRepository) checkActive(id string) error {
+	tombstone, deleted := r.tombstones[id]
+	if !deleted {
+		return nil
+	}
+	if tombstone.Purged(r.retention, time.Now()) {
+		delete(r.credentials, id)
+		delete(r.tombstones, id)
+		return nil
+	}
+	return &DeletedCredentialError{ID: id, Tombstone: tombstone}
+}
+
 // InMemoryPresentationRepository implements PresentationRepository interface
 type InMemoryPresentationRepository struct {
-	presentations map[string]*VerifiablePresentation
+	mu      sync.RWMutex
+	records []*PresentationRecord
 }
 
 // NewInMemoryPresentationRepository creates a new in-memory presentation repository
 func NewInMemoryPresentationRepository() PresentationRepository {
-	return &InMemoryPresentationRepository{
-		presentations: make(map[string]*VerifiablePresentation),
-	}
+	return &InMemoryPresentationRepository{}
 }
 
-// Store stores a verifiable presentation
-func (r *InMemoryPresentationRepository) Store(vp *VerifiablePresentation) error {
-	if vp == nil {
+// Ping reports whether the repository is reachable; an in-memory repository always is
+func (r *InMemoryPresentationRepository) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Store records a verified presentation. Every verification is kept, so a presentation submitted
+// twice has two records.
+func (r *InMemoryPresentationRepository) Store(record *PresentationRecord) error {
+	if record == nil || record.Presentation == nil {
 		return fmt.Errorf("presentation is nil")
 	}
-	r.presentations[vp.ID] = vp
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
 	return nil
 }
 
-// Retrieve retrieves a verifiable presentation by ID
-func (r *InMemoryPresentationRepository) Retrieve(id string) (*VerifiablePresentation, error) {
-	vp, exists := r.presentations[id]
-	if !exists {
-		return nil, fmt.Errorf("presentation not found: %s", id)
+// Retrieve returns the latest record of a presentation by ID
+func (r *InMemoryPresentationRepository) Retrieve(id string) (*PresentationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.records) - 1; i >= 0; i-- {
+		if r.records[i].Presentation.ID == id {
+			return r.records[i], nil
+		}
 	}
-	return vp, nil
+	return nil, fmt.Errorf("presentation not found: %s", id)
 }
 
-// List lists all presentations for a holder DID
-func (r *InMemoryPresentationRepository) List(holderDID string) ([]*VerifiablePresentation, error) {
-	var presentations []*VerifiablePresentation
-	for _, vp := range r.presentations {
-		if vp.Holder == holderDID {
-			presentations = append(presentations, vp)
+// List lists the records that match the filter, in the order they were stored
+func (r *InMemoryPresentationRepository) List(filter PresentationFilter) ([]*PresentationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var records []*PresentationRecord
+	for _, record := range r.records {
+		if filter.Matches(record) {
+			records = append(records, record)
 		}
 	}
-	return presentations, nil
+	return records, nil
 }
@@ -0,0 +1,107 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StatusEvent names the status change a StatusNotification reports
+type StatusEvent string
+
+const (
+	StatusEventRevoked    StatusEvent = "revoked"
+	StatusEventSuspended  StatusEvent = "suspended"
+	StatusEventReinstated StatusEvent = "reinstated"
+)
+
+// StatusReason is the reason code given for a status change. The codes follow the CRL reason
+// codes of RFC 5280.
+type StatusReason string
+
+const (
+	StatusReasonUnspecified          StatusReason = "unspecified"
+	StatusReasonKeyCompromise        StatusReason = "keyCompromise"
+	StatusReasonAffiliationChanged   StatusReason = "affiliationChanged"
+	StatusReasonSuperseded           StatusReason = "superseded"
+	StatusReasonCessationOfOperation StatusReason = "cessationOfOperation"
+	StatusReasonCertificateHold      StatusReason = "certificateHold"
+	StatusReasonPrivilegeWithdrawn   StatusReason = "privilegeWithdrawn"
+)
+
+// Validate checks the reason is a known code
+func (r StatusReason) Validate() error {
+	switch r {
+	case StatusReasonUnspecified, StatusReasonKeyCompromise, StatusReasonAffiliationChanged,
+		StatusReasonSuperseded, StatusReasonCessationOfOperation, StatusReasonCertificateHold,
+		StatusReasonPrivilegeWithdrawn:
+		return nil
+	}
+	return fmt.Errorf("unknown status reason: %q", r)
+}
+
+// StatusNotification is an issuer-signed message telling a holder the status of one of their
+// credentials changed, so the wallet can flag the credential without polling the status list
+type StatusNotification struct {
+	ID            string       `json:"id"`
+	CredentialID  string       `json:"credentialId"`
+	Issuer        string       `json:"issuer"`
+	Holder        string       `json:"holder"`
+	Event         StatusEvent  `json:"event"`
+	Reason        StatusReason `json:"reason"`
+	EffectiveDate time.Time    `json:"effectiveDate"`
+	Created       time.Time    `json:"created"`
+	Proof         *Proof       `json:"proof,omitempty"`
+}
+
+// StatusSubscription is a holder-signed request to receive status notifications for the
+// holder's credentials at Endpoint. The signature keeps anyone else from redirecting them.
+type StatusSubscription struct {
+	Holder   string    `json:"holder"`
+	Issuer   string    `json:"issuer"`
+	Endpoint string    `json:"endpoint"`
+	Created  time.Time `json:"created"`
+	Proof    *Proof    `json:"proof,omitempty"`
+}
+
+// StatusNotificationSigningInput returns the bytes covered by the issuer's notification proof
+func StatusNotificationSigningInput(notification *StatusNotification) ([]byte, error) {
+	if notification == nil {
+		return nil, fmt.Errorf("status notification is nil")
+	}
+
+	unsigned := *notification
+	if notification.Proof != nil {
+		proof := *notification.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status notification: %w", err)
+	}
+
+	return data, nil
+}
+
+// StatusSubscriptionSigningInput returns the bytes covered by the holder's subscription proof
+func StatusSubscriptionSigningInput(subscription *StatusSubscription) ([]byte, error) {
+	if subscription == nil {
+		return nil, fmt.Errorf("status subscription is nil")
+	}
+
+	unsigned := *subscription
+	if subscription.Proof != nil {
+		proof := *subscription.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status subscription: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,52 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+)
+
+// StatusSnapshot is an issuer-signed statement of a status list bit at a point in time.
+// Holders embed snapshots in presentations so verifiers can check revocation without
+// reaching the issuer's status list.
+type StatusSnapshot struct {
+	ID                   string         `json:"id"`
+	Type                 []string       `json:"type"`
+	Issuer               string         `json:"issuer"`
+	StatusPurpose        status.Purpose `json:"statusPurpose"`
+	StatusListIndex      string         `json:"statusListIndex"`
+	StatusListCredential string         `json:"statusListCredential"`
+	Status               bool           `json:"status"`
+	Timestamp            time.Time      `json:"timestamp"`
+	Proof                *Proof         `json:"proof,omitempty"`
+}
+
+// Covers reports whether the snapshot states the bit a status entry points at
+func (s *StatusSnapshot) Covers(entry *status.Entry) bool {
+	return s.StatusListCredential == entry.StatusListCredential &&
+		s.StatusListIndex == entry.StatusListIndex &&
+		s.StatusPurpose == entry.StatusPurpose
+}
+
+// StatusSnapshotSigningInput returns the bytes covered by the issuer's snapshot proof
+func StatusSnapshotSigningInput(snapshot *StatusSnapshot) ([]byte, error) {
+	if snapshot == nil {
+		return nil, fmt.Errorf("status snapshot is nil")
+	}
+
+	unsigned := *snapshot
+	if snapshot.Proof != nil {
+		proof := *snapshot.Proof
+		proof.ProofValue = ""
+		unsigned.Proof = &proof
+	}
+
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status snapshot: %w", err)
+	}
+
+	return data, nil
+}
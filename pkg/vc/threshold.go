@@ -0,0 +1,96 @@
+package vc
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+)
+
+const (
+	// AddendumCredentialType marks credentials carrying threshold claims about another credential
+	AddendumCredentialType = "ThresholdAddendumCredential"
+	// AddendumToClaim is the addendum claim holding the ID of the credential it supplements
+	AddendumToClaim = "addendumTo"
+)
+
+// ThresholdDefinition derives a boolean claim, such as salaryOver50k, by comparing a credential
+// claim with a threshold. Issuers evaluate thresholds for holders whose verifiers cannot check
+// predicate proofs, and sign the results into addendum credentials.
+type ThresholdDefinition struct {
+	// Name is the boolean claim the addendum carries
+	Name     string            `json:"name"`
+	Claim    string            `json:"claim"`
+	Operator PredicateOperator `json:"operator,omitempty"`
+	Value    int64             `json:"value,omitempty"`
+	// MinAge, used instead of Operator and Value, treats the claim as a date of birth and holds
+	// when it is at least MinAge years before the addendum is issued
+	MinAge int `json:"minAge,omitempty"`
+}
+
+// Validate checks the definition names a claim and exactly one comparison
+func (d ThresholdDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("threshold name is required")
+	}
+	if d.Name == "id" || d.Name == AddendumToClaim {
+		return fmt.Errorf("threshold name %s is reserved", d.Name)
+	}
+	if d.Claim == "" {
+		return fmt.Errorf("threshold claim is required")
+	}
+
+	if d.MinAge < 0 {
+		return fmt.Errorf("minimum age cannot be negative")
+	}
+	if d.MinAge > 0 {
+		if d.Operator != "" {
+			return fmt.Errorf("threshold %s sets both minAge and operator", d.Name)
+		}
+		return nil
+	}
+
+	return PredicateStatement{Claim: d.Claim, Operator: d.Operator, Value: d.Value}.Validate()
+}
+
+// Evaluate compares a claim value with the threshold, taking dates in UTC
+func (d ThresholdDefinition) Evaluate(value interface{}, now time.Time) (bool, error) {
+	return d.EvaluateWithPolicy(value, now, DatePolicy{})
+}
+
+// EvaluateWithPolicy compares a claim value with the threshold. Dates of birth are compared by the
+// age they give on the day now falls on under the policy; other values are compared as decimal
+// numbers.
+func (d ThresholdDefinition) EvaluateWithPolicy(value interface{}, now time.Time, policy DatePolicy) (bool, error) {
+	if d.MinAge > 0 {
+		birthDate, err := policy.ParseDate(value)
+		if err != nil {
+			return false, fmt.Errorf("claim %s: %w", d.Claim, err)
+		}
+		return policy.AgeAt(birthDate, now) >= d.MinAge, nil
+	}
+
+	canonical, err := CoerceClaimValue(schema.ClaimTypeDecimal, value)
+	if err != nil {
+		return false, fmt.Errorf("claim %s: %w", d.Claim, err)
+	}
+	number, ok := new(big.Rat).SetString(canonical.(string))
+	if !ok {
+		return false, fmt.Errorf("claim %s: invalid number %v", d.Claim, canonical)
+	}
+
+	cmp := number.Cmp(new(big.Rat).SetInt64(d.Value))
+	switch d.Operator {
+	case PredicateGreaterThan:
+		return cmp > 0, nil
+	case PredicateGreaterOrEqual:
+		return cmp >= 0, nil
+	case PredicateLessThan:
+		return cmp < 0, nil
+	case PredicateLessOrEqual:
+		return cmp <= 0, nil
+	default:
+		return false, fmt.Errorf("unknown predicate operator: %s", d.Operator)
+	}
+}
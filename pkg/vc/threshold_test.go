@@ -0,0 +1,79 @@
+package vc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThresholdDefinition(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		definition ThresholdDefinition
+		value      interface{}
+		expected   bool
+	}{
+		{"Seventeenth Birthday", ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17}, "2008-06-15", true},
+		{"Day Before Seventeenth Birthday", ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17}, "2008-06-16", false},
+		{"Birth Date As RFC3339", ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17}, "2008-06-15T23:30:00+09:00", true},
+		{"Birth Date As Epoch Seconds", ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17}, float64(1213574400), false},
+		{"Salary Over", ThresholdDefinition{Name: "salaryOver50k", Claim: "salary", Operator: PredicateGreaterThan, Value: 50000}, int64(72000), true},
+		{"Salary At Bound", ThresholdDefinition{Name: "salaryOver50k", Claim: "salary", Operator: PredicateGreaterThan, Value: 50000}, 50000.0, false},
+		{"Decimal Below", ThresholdDefinition{Name: "lowBalance", Claim: "balance", Operator: PredicateLessThan, Value: 100}, "99.99", true},
+		{"Inclusive Bound", ThresholdDefinition{Name: "adultHeight", Claim: "height", Operator: PredicateGreaterOrEqual, Value: 150}, 150, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.definition.Validate())
+			result, err := tt.definition.Evaluate(tt.value, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("Date Policy Decides The Day", func(t *testing.T) {
+		// 20:00 UTC on the 14th is already the 15th in Tokyo
+		definition := ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17}
+		evening := time.Date(2025, 6, 14, 20, 0, 0, 0, time.UTC)
+		tokyo, err := NewDatePolicy("Asia/Tokyo")
+		require.NoError(t, err)
+
+		result, err := definition.Evaluate("2008-06-15", evening)
+		require.NoError(t, err)
+		assert.False(t, result)
+
+		result, err = definition.EvaluateWithPolicy("2008-06-15", evening, tokyo)
+		require.NoError(t, err)
+		assert.True(t, result)
+	})
+
+	t.Run("Leap Day Birth", func(t *testing.T) {
+		definition := ThresholdDefinition{Name: "ageOver18", Claim: "dateOfBirth", MinAge: 18}
+		for day, expected := range map[time.Time]bool{
+			time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC): false,
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC):   true,
+		} {
+			result, err := definition.Evaluate("2008-02-29", day)
+			require.NoError(t, err)
+			assert.Equal(t, expected, result, day)
+		}
+	})
+
+	t.Run("Invalid Definitions", func(t *testing.T) {
+		assert.ErrorContains(t, ThresholdDefinition{Claim: "salary", MinAge: 18}.Validate(), "name is required")
+		assert.ErrorContains(t, ThresholdDefinition{Name: AddendumToClaim, Claim: "salary", MinAge: 18}.Validate(), "reserved")
+		assert.ErrorContains(t, ThresholdDefinition{Name: "x", Claim: "dateOfBirth", MinAge: 18, Operator: PredicateLessThan}.Validate(), "both minAge and operator")
+		assert.ErrorContains(t, ThresholdDefinition{Name: "x", Claim: "salary"}.Validate(), "unknown predicate operator")
+	})
+
+	t.Run("Non-Numeric Claim", func(t *testing.T) {
+		definition := ThresholdDefinition{Name: "x", Claim: "nationality", Operator: PredicateGreaterThan, Value: 1}
+		_, err := definition.Evaluate("Vietnamese", now)
+		assert.Error(t, err)
+	})
+}
@@ -1,49 +1,101 @@
 package vc
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
 )
 
-// VerifiableCredential represents a W3C Verifiable Credential
+// VerifiableCredential represents a W3C Verifiable Credential under data model 1.1 or 2.0.
+// 1.1 credentials carry IssuanceDate and ExpirationDate, 2.0 credentials ValidFrom and ValidUntil.
 type VerifiableCredential struct {
 	Context           []string               `json:"@context"`
 	ID                string                 `json:"id"`
 	Type              []string               `json:"type"`
-	Issuer            string                 `json:"issuer"`
+	IssuerInfo        Issuer                 `json:"issuer"`
 	IssuanceDate      time.Time              `json:"issuanceDate"`
 	ExpirationDate    *time.Time             `json:"expirationDate,omitempty"`
+	ValidFrom         *time.Time             `json:"validFrom,omitempty"`
+	ValidUntil        *time.Time             `json:"validUntil,omitempty"`
 	CredentialSubject map[string]interface{} `json:"credentialSubject"`
-	Proof             *Proof                 `json:"proof,omitempty"`
+	CredentialStatus  []status.Entry         `json:"credentialStatus,omitempty"`
+	// AttributeSalts holds the random salt each claim value was signed with. The salts of
+	// revealed claims are disclosed with them; the others stay with the holder.
+	AttributeSalts map[string][]byte `json:"attributeSalts,omitempty"`
+	// ClaimManifest records the message index each claim was signed at; the holder keeps it
+	ClaimManifest *ClaimManifest `json:"claimManifest,omitempty"`
+	// IssuerSetProof names the anonymity set the holder may hide the issuer within when presenting
+	IssuerSetProof *IssuerSetProof `json:"issuerSetProof,omitempty"`
+	// Commitments and CommitmentOpenings let the holder prove statements about claims with
+	// external zero-knowledge systems. Openings are never presented.
+	Commitments        *CommitmentBundle   `json:"commitments,omitempty"`
+	CommitmentOpenings []CommitmentOpening `json:"commitmentOpenings,omitempty"`
+	// Evidence describes the documents the issuer relied on. EvidenceKeys unseal the encrypted
+	// ones and are never presented.
+	Evidence     *EvidenceBundle `json:"evidence,omitempty"`
+	EvidenceKeys []EvidenceKey   `json:"evidenceKeys,omitempty"`
+	// ClaimKeys commits to the credential's claim keys so the holder can prove claims absent.
+	// ClaimKeySalts are needed to build the proofs and are never presented.
+	ClaimKeys     *ClaimKeyCommitment `json:"claimKeys,omitempty"`
+	ClaimKeySalts []ClaimKeySalt      `json:"claimKeySalts,omitempty"`
+	// CoSignatures records the key holders who approved the issuance, for credentials that
+	// could not be issued unilaterally
+	CoSignatures *CoSignatureEnvelope `json:"coSignatures,omitempty"`
+	// Authorizations are the AuthorizedIssuerCredentials linking a sub-issuer back to a root
+	// authority, which the holder presents alongside the credential
+	Authorizations []*VerifiableCredential `json:"authorizations,omitempty"`
+	Proof          *Proof                  `json:"proof,omitempty"`
 }
 
 // VerifiablePresentation represents a W3C Verifiable Presentation
 type VerifiablePresentation struct {
-	Context              []string      `json:"@context"`
-	ID                   string        `json:"id"`
-	Type                 []string      `json:"type"`
-	Holder               string        `json:"holder"`
-	VerifiableCredential []interface{} `json:"verifiableCredential"`
-	Proof                *Proof        `json:"proof,omitempty"`
+	Context              []string             `json:"@context"`
+	ID                   string               `json:"id"`
+	Type                 []string             `json:"type"`
+	Holder               string               `json:"holder"`
+	VerifiableCredential []*DerivedCredential `json:"verifiableCredential"`
+	// StatusSnapshots prove the status of the presented credentials for offline verification
+	StatusSnapshots []*StatusSnapshot `json:"statusSnapshots,omitempty"`
+	// Authorizations are the presented credentials' issuer authorizations, from which the
+	// verifier builds delegation chains to the roots it trusts
+	Authorizations []*VerifiableCredential `json:"authorizations,omitempty"`
+	Proof          *Proof                  `json:"proof,omitempty"`
 }
 
 // Proof represents a cryptographic proof
 type Proof struct {
-	Type               string    `json:"type"`
-	Created            time.Time `json:"created"`
-	VerificationMethod string    `json:"verificationMethod"`
-	ProofPurpose       string    `json:"proofPurpose"`
-	ProofValue         string    `json:"proofValue,omitempty"`
+	Type    string     `json:"type"`
+	Created time.Time  `json:"created"`
+	Expires *time.Time `json:"expires,omitempty"`
+	// VerificationMethod is empty when a derived proof hides the issuer
+	VerificationMethod string `json:"verificationMethod,omitempty"`
+	ProofPurpose       string `json:"proofPurpose"`
+	ProofValue         string `json:"proofValue,omitempty"`
 	// BBS+ specific fields
-	Nonce              string `json:"nonce,omitempty"`
-	RevealedAttributes []int  `json:"revealedAttributes,omitempty"`
+	Nonce string `json:"nonce,omitempty"`
+	// Domain names the verifier a presentation proof is meant for
+	Domain string `json:"domain,omitempty"`
+	// RevealedAttributes names the claims a derived proof reveals
+	RevealedAttributes []string `json:"revealedAttributes,omitempty"`
+	// DisclosedClaims lists the claims a derived proof reveals in the order of their signed
+	// messages, so the verifier can rebuild them
+	DisclosedClaims []string `json:"disclosedClaims,omitempty"`
 }
 
-// Claim represents a single claim in a credential
+// Claim represents a single claim in a credential.
+// Type is optional; when empty it is inferred from Value at issuance.
 type Claim struct {
-	Key   string      `json:"key"`
-	Value interface{} `json:"value"`
+	Key   string           `json:"key"`
+	Value interface{}      `json:"value"`
+	Type  schema.ClaimType `json:"type,omitempty"`
+	// Disclosure restricts how holders may reveal the claim; it is signed in the claim manifest
+	Disclosure schema.DisclosureRestriction `json:"disclosure,omitempty"`
 }
 
 // SelectiveDisclosureRequest represents what attributes to reveal
@@ -51,27 +103,179 @@ type SelectiveDisclosureRequest struct {
 	CredentialID       string   `json:"credentialId"`
 	RevealedAttributes []string `json:"revealedAttributes"`
 	Nonce              string   `json:"nonce,omitempty"`
+	// HideIssuer presents the credential's issuer set in place of its issuer
+	HideIssuer bool `json:"hideIssuer,omitempty"`
+	// IncludeCommitments presents the issuer-signed commitments to the credential's claims
+	IncludeCommitments bool `json:"includeCommitments,omitempty"`
+	// Predicates are proven about committed claims; they need IncludeCommitments
+	Predicates []PredicateStatement `json:"predicates,omitempty"`
+	// IncludeEvidence presents the issuer-signed evidence section, never the document keys
+	IncludeEvidence bool `json:"includeEvidence,omitempty"`
+	// ProveAbsent lists claims to prove the credential does not have; it needs a claim key commitment
+	ProveAbsent []string `json:"proveAbsent,omitempty"`
+	// IssuerPublicKey is the BBS+ public key the credential was signed with. The holder sets it to
+	// derive a BBS+ proof of the revealed claims from the credential's signature.
+	IssuerPublicKey []byte `json:"-"`
+	// IssuerSetKeys are the BBS+ public keys of the credential's issuer set, in the order of its
+	// verification methods. The holder sets them to hide the issuer.
+	IssuerSetKeys [][]byte `json:"-"`
+	// ProofTemplate is blinding precomputed for the credential. A template that does not match
+	// the credential, or was already used, is ignored and the proof is derived from scratch.
+	ProofTemplate *bbs.ProofTemplate `json:"-"`
+}
+
+// VerifierIdentity describes the legal entity behind a verifier DID
+type VerifierIdentity struct {
+	DID                string `json:"did,omitempty"`
+	LegalName          string `json:"legalName,omitempty"`
+	RegistrationNumber string `json:"registrationNumber,omitempty"`
+	Jurisdiction       string `json:"jurisdiction,omitempty"`
+	ContactURL         string `json:"contactUrl,omitempty"`
+}
+
+// ClaimConstraint limits which credentials a verifier accepts a claim from.
+// An empty list places no restriction on that dimension.
+type ClaimConstraint struct {
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+	Issuers         []string `json:"issuers,omitempty"`
+}
+
+// Check reports why a credential with the given issuer and types cannot supply the claim, if it cannot
+func (c ClaimConstraint) Check(issuer string, types []string) error {
+	if len(c.Issuers) > 0 && !containsString(c.Issuers, issuer) {
+		return fmt.Errorf("issuer %s is not accepted", issuer)
+	}
+
+	if len(c.CredentialTypes) > 0 {
+		for _, t := range types {
+			if containsString(c.CredentialTypes, t) {
+				return nil
+			}
+		}
+		return fmt.Errorf("credential is not of type %s", strings.Join(c.CredentialTypes, " or "))
+	}
+
+	return nil
+}
+
+// RequestMetadata describes why data is requested and how long it will be kept
+type RequestMetadata struct {
+	Purpose       string            `json:"purpose,omitempty"`
+	RetentionDays int               `json:"retentionDays,omitempty"`
+	Verifier      *VerifierIdentity `json:"verifier,omitempty"`
+	// Accreditation is the verifier's VerifierAccreditationCredential, which holders may require
+	// before disclosing data
+	Accreditation *VerifiableCredential `json:"accreditation,omitempty"`
+}
+
+// Validate checks the request metadata for consistency
+func (m *RequestMetadata) Validate() error {
+	if m.RetentionDays < 0 {
+		return fmt.Errorf("retention period cannot be negative")
+	}
+	if m.Accreditation != nil {
+		accreditation, err := ParseAccreditation(m.Accreditation)
+		if err != nil {
+			return err
+		}
+		if m.Verifier != nil && m.Verifier.DID != "" && accreditation.Verifier != m.Verifier.DID {
+			return fmt.Errorf("accreditation was issued to %s, not the verifier %s", accreditation.Verifier, m.Verifier.DID)
+		}
+	}
+	return nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // CredentialService interface for credential operations
 type CredentialService interface {
-	SetIssuerKeyPair(issuerDID string, keyPair *bbs.KeyPair)
+	SetIssuerKeyPair(issuerDID string, keyPair *bbs.KeyPair) error
+	// SetIssuerKey sets the issuer's signing key under a versioned verification method ID
+	SetIssuerKey(issuerDID string, keyID string, keyPair *bbs.KeyPair) error
 	IssueCredential(issuerDID string, subjectDID string, claims []Claim) (*VerifiableCredential, error)
+	// IssueCredentialContext is IssueCredential with the types and issuer details to sign, and the
+	// signing step traced under ctx
+	IssueCredentialContext(ctx context.Context, issuerDID string, subjectDID string, claims []Claim, options IssuanceOptions) (*VerifiableCredential, error)
 	VerifyCredential(vc *VerifiableCredential) error
 	CreatePresentation(holderDID string, credentials []*VerifiableCredential, disclosureRequests []SelectiveDisclosureRequest) (*VerifiablePresentation, error)
+	// CreatePresentationContext is CreatePresentation with each credential derivation traced under ctx
+	CreatePresentationContext(ctx context.Context, holderDID string, credentials []*VerifiableCredential, disclosureRequests []SelectiveDisclosureRequest) (*VerifiablePresentation, error)
 	VerifyPresentation(vp *VerifiablePresentation) error
 }
 
-// CredentialRepository interface for credential storage
+// CredentialRepository interface for credential storage. Retrieving a credential that was never
+// stored fails with ErrCredentialNotFound, and a deleted one with a *DeletedCredentialError.
 type CredentialRepository interface {
 	Store(vc *VerifiableCredential) error
 	Retrieve(id string) (*VerifiableCredential, error)
 	List(holderDID string) ([]*VerifiableCredential, error)
+	// Delete keeps the credential with a tombstone, dated now unless it already is. A deleted
+	// credential is not listed and cannot be stored again until it is purged.
+	Delete(id string, tombstone storage.Tombstone) error
+}
+
+// PresentationRecord is a presentation a verifier received, with the outcome of verifying it
+type PresentationRecord struct {
+	Presentation *VerifiablePresentation
+	// VerifierDID is the verifier the presentation was submitted to, if it identified itself
+	VerifierDID string
+	VerifiedAt  time.Time
+	Valid       bool
+	Errors      []string
+}
+
+// HolderDID returns the holder that submitted the presentation
+func (r *PresentationRecord) HolderDID() string {
+	if r.Presentation == nil {
+		return ""
+	}
+	return r.Presentation.Holder
+}
+
+// PresentationFilter selects presentation records. Empty fields match every record.
+type PresentationFilter struct {
+	VerifierDID string
+	HolderDID   string
+	// Valid, when set, matches only records with that outcome
+	Valid *bool
+	// VerifiedAfter and VerifiedBefore bound the verification time, inclusively
+	VerifiedAfter  *time.Time
+	VerifiedBefore *time.Time
+}
+
+// Matches reports whether a record satisfies the filter
+func (f PresentationFilter) Matches(record *PresentationRecord) bool {
+	if f.VerifierDID != "" && record.VerifierDID != f.VerifierDID {
+		return false
+	}
+	if f.HolderDID != "" && record.HolderDID() != f.HolderDID {
+		return false
+	}
+	if f.Valid != nil && record.Valid != *f.Valid {
+		return false
+	}
+	if f.VerifiedAfter != nil && record.VerifiedAt.Before(*f.VerifiedAfter) {
+		return false
+	}
+	if f.VerifiedBefore != nil && record.VerifiedAt.After(*f.VerifiedBefore) {
+		return false
+	}
+	return true
 }
 
-// PresentationRepository interface for presentation storage
+// PresentationRepository stores the presentations a verifier received and how they verified
 type PresentationRepository interface {
-	Store(vp *VerifiablePresentation) error
-	Retrieve(id string) (*VerifiablePresentation, error)
-	List(holderDID string) ([]*VerifiablePresentation, error)
+	Store(record *PresentationRecord) error
+	// Retrieve returns the latest record of the presentation with the given ID
+	Retrieve(id string) (*PresentationRecord, error)
+	// List returns the records that match the filter, oldest first
+	List(filter PresentationFilter) ([]*PresentationRecord, error)
 }
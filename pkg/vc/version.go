@@ -0,0 +1,162 @@
+package vc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+// Version is a W3C Verifiable Credentials Data Model version
+type Version string
+
+const (
+	// Version1 is the 1.1 data model: issuanceDate and expirationDate
+	Version1 Version = "1.1"
+	// Version2 is the 2.0 data model: validFrom and validUntil, and issuers that may be objects
+	Version2 Version = "2.0"
+)
+
+// ParseVersion parses a data model version; empty means Version1
+func ParseVersion(value string) (Version, error) {
+	switch Version(value) {
+	case "", Version1:
+		return Version1, nil
+	case Version2:
+		return Version2, nil
+	default:
+		return "", fmt.Errorf("unsupported data model version %q: use %s or %s", value, Version1, Version2)
+	}
+}
+
+// BaseContext returns the credentials context documents of the version start with
+func (v Version) BaseContext() string {
+	if v == Version2 {
+		return jsonld.CredentialsV2URL
+	}
+	return jsonld.CredentialsV1URL
+}
+
+// Contexts returns the @context credentials and presentations of the version are issued under
+func (v Version) Contexts() []string {
+	return []string{v.BaseContext(), jsonld.BBSV1URL, jsonld.ExtensionsV1URL}
+}
+
+// VersionOf returns the data model version a @context declares with its first entry
+func VersionOf(contexts []string) (Version, error) {
+	if len(contexts) == 0 {
+		return "", fmt.Errorf("@context is empty")
+	}
+
+	switch contexts[0] {
+	case jsonld.CredentialsV1URL:
+		return Version1, nil
+	case jsonld.CredentialsV2URL:
+		return Version2, nil
+	default:
+		return "", fmt.Errorf("unsupported data model: the first @context is %s", contexts[0])
+	}
+}
+
+// DocumentVersion returns the data model version of a credential or presentation in its decoded
+// JSON object form, as derived credentials are presented
+func DocumentVersion(document map[string]interface{}) (Version, error) {
+	var contexts []string
+	switch value := document["@context"].(type) {
+	case []string:
+		contexts = value
+	case []interface{}:
+		for _, item := range value {
+			if url, ok := item.(string); ok {
+				contexts = append(contexts, url)
+			} else {
+				contexts = append(contexts, "")
+			}
+		}
+	case string:
+		contexts = []string{value}
+	}
+
+	return VersionOf(contexts)
+}
+
+// Version returns the data model version the credential's @context declares
+func (c *VerifiableCredential) Version() (Version, error) {
+	return VersionOf(c.Context)
+}
+
+// IssuedAt returns when the credential became valid: validFrom under 2.0, issuanceDate under 1.1
+func (c *VerifiableCredential) IssuedAt() time.Time {
+	if c.ValidFrom != nil {
+		return *c.ValidFrom
+	}
+	return c.IssuanceDate
+}
+
+// ExpiresAt returns when the credential stops being valid, or nil if it does not expire
+func (c *VerifiableCredential) ExpiresAt() *time.Time {
+	if c.ValidUntil != nil {
+		return c.ValidUntil
+	}
+	return c.ExpirationDate
+}
+
+// SetVersion moves a credential to a data model version: its base context and date properties
+// are rewritten, so validFrom and validUntil replace issuanceDate and expirationDate under 2.0
+func (c *VerifiableCredential) SetVersion(v Version) error {
+	if _, err := ParseVersion(string(v)); err != nil {
+		return err
+	}
+	if _, err := c.Version(); err != nil {
+		return err
+	}
+
+	issuedAt, expiresAt := c.IssuedAt(), c.ExpiresAt()
+	c.Context = append([]string{v.BaseContext()}, c.Context[1:]...)
+	c.IssuanceDate, c.ExpirationDate, c.ValidFrom, c.ValidUntil = time.Time{}, nil, nil, nil
+
+	if v == Version2 {
+		c.ValidFrom = &issuedAt
+		c.ValidUntil = expiresAt
+	} else {
+		c.IssuanceDate = issuedAt
+		c.ExpirationDate = expiresAt
+	}
+	return nil
+}
+
+// SetExpiry sets when the credential stops being valid, as validUntil or expirationDate
+func (c *VerifiableCredential) SetExpiry(expiresAt time.Time) error {
+	version, err := c.Version()
+	if err != nil {
+		return err
+	}
+
+	if expiresAt.Before(c.IssuedAt()) {
+		return fmt.Errorf("credential cannot expire before it is issued")
+	}
+
+	if version == Version2 {
+		c.ValidUntil = &expiresAt
+	} else {
+		c.ExpirationDate = &expiresAt
+	}
+	return nil
+}
+
+// credentialJSON has the fields of VerifiableCredential without its JSON methods
+type credentialJSON VerifiableCredential
+
+// MarshalJSON omits issuanceDate when it is unset, as it is under 2.0
+func (c VerifiableCredential) MarshalJSON() ([]byte, error) {
+	out := struct {
+		credentialJSON
+		IssuanceDate *time.Time `json:"issuanceDate,omitempty"`
+	}{credentialJSON: credentialJSON(c)}
+
+	if !c.IssuanceDate.IsZero() {
+		out.IssuanceDate = &c.IssuanceDate
+	}
+	return json.Marshal(out)
+}
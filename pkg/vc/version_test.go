@@ -0,0 +1,100 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+func TestParseVersion(t *testing.T) {
+	version, err := ParseVersion("")
+	require.NoError(t, err)
+	assert.Equal(t, Version1, version)
+
+	version, err = ParseVersion("2.0")
+	require.NoError(t, err)
+	assert.Equal(t, Version2, version)
+
+	_, err = ParseVersion("3.0")
+	assert.Error(t, err)
+}
+
+func TestVersionOf(t *testing.T) {
+	version, err := VersionOf(Version1.Contexts())
+	require.NoError(t, err)
+	assert.Equal(t, Version1, version)
+
+	version, err = DocumentVersion(map[string]interface{}{
+		"@context": []interface{}{jsonld.CredentialsV2URL, jsonld.BBSV1URL},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Version2, version)
+
+	_, err = VersionOf([]string{jsonld.BBSV1URL})
+	assert.Error(t, err)
+
+	_, err = VersionOf(nil)
+	assert.Error(t, err)
+}
+
+func TestSetVersion(t *testing.T) {
+	issued := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+	expires := issued.AddDate(1, 0, 0)
+
+	credential := &VerifiableCredential{
+		Context:        Version1.Contexts(),
+		Type:           []string{"VerifiableCredential"},
+		IssuerInfo:     NewIssuer("did:example:issuer"),
+		IssuanceDate:   issued,
+		ExpirationDate: &expires,
+	}
+
+	t.Run("To 2.0", func(t *testing.T) {
+		require.NoError(t, credential.SetVersion(Version2))
+
+		assert.Equal(t, Version2.Contexts(), credential.Context)
+		assert.True(t, credential.IssuanceDate.IsZero())
+		assert.Nil(t, credential.ExpirationDate)
+		require.NotNil(t, credential.ValidFrom)
+		require.NotNil(t, credential.ValidUntil)
+		assert.Equal(t, issued, *credential.ValidFrom)
+		assert.Equal(t, expires, *credential.ValidUntil)
+		assert.Equal(t, issued, credential.IssuedAt())
+	})
+
+	t.Run("Marshal Omits 1.1 Dates", func(t *testing.T) {
+		data, err := json.Marshal(credential)
+		require.NoError(t, err)
+
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &document))
+		assert.NotContains(t, document, "issuanceDate")
+		assert.NotContains(t, document, "expirationDate")
+		assert.Equal(t, "2030-01-02T03:04:05Z", document["validFrom"])
+		assert.Equal(t, "2031-01-02T03:04:05Z", document["validUntil"])
+	})
+
+	t.Run("Back To 1.1", func(t *testing.T) {
+		require.NoError(t, credential.SetVersion(Version1))
+
+		assert.Equal(t, Version1.Contexts(), credential.Context)
+		assert.Equal(t, issued, credential.IssuanceDate)
+		require.NotNil(t, credential.ExpirationDate)
+		assert.Equal(t, expires, *credential.ExpirationDate)
+		assert.Nil(t, credential.ValidFrom)
+		assert.Nil(t, credential.ValidUntil)
+	})
+
+	t.Run("Expiry Before Issuance", func(t *testing.T) {
+		assert.Error(t, credential.SetExpiry(issued.Add(-time.Hour)))
+	})
+
+	t.Run("Unsupported Version", func(t *testing.T) {
+		assert.Error(t, credential.SetVersion("3.0"))
+	})
+}
@@ -0,0 +1,31 @@
+package vc
+
+import "github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+
+const (
+	// PreviousVersionClaim is the claim of an amended credential holding the ID of the version it
+	// replaces
+	PreviousVersionClaim = "previousVersion"
+	// CredentialVersionClaim numbers the versions of a credential; the original, which has no such
+	// claim, is version 1
+	CredentialVersionClaim = "credentialVersion"
+)
+
+// CredentialVersion returns the credential's version number, 1 unless it amends an earlier version
+func (c *VerifiableCredential) CredentialVersion() int {
+	value, ok := c.CredentialSubject[CredentialVersionClaim]
+	if !ok {
+		return 1
+	}
+	version, err := CoerceClaimValue(schema.ClaimTypeInteger, value)
+	if err != nil || version.(int64) < 1 {
+		return 1
+	}
+	return int(version.(int64))
+}
+
+// PreviousVersion returns the ID of the version the credential amends, or "" for an original
+func (c *VerifiableCredential) PreviousVersion() string {
+	previous, _ := c.CredentialSubject[PreviousVersionClaim].(string)
+	return previous
+}
@@ -0,0 +1,40 @@
+package vc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialVersion(t *testing.T) {
+	t.Run("Original", func(t *testing.T) {
+		credential := &VerifiableCredential{CredentialSubject: map[string]interface{}{"id": "did:example:holder"}}
+		assert.Equal(t, 1, credential.CredentialVersion())
+		assert.Empty(t, credential.PreviousVersion())
+	})
+
+	t.Run("Amended", func(t *testing.T) {
+		credential := &VerifiableCredential{CredentialSubject: map[string]interface{}{
+			PreviousVersionClaim:   "urn:uuid:previous",
+			CredentialVersionClaim: int64(2),
+		}}
+		assert.Equal(t, 2, credential.CredentialVersion())
+		assert.Equal(t, "urn:uuid:previous", credential.PreviousVersion())
+
+		// Versions survive JSON, which decodes them as floats
+		data, err := json.Marshal(credential)
+		require.NoError(t, err)
+		var decoded VerifiableCredential
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, 2, decoded.CredentialVersion())
+	})
+
+	t.Run("Invalid Versions", func(t *testing.T) {
+		for _, value := range []interface{}{"two", 0, -3} {
+			credential := &VerifiableCredential{CredentialSubject: map[string]interface{}{CredentialVersionClaim: value}}
+			assert.Equal(t, 1, credential.CredentialVersion(), value)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VerifyClaimKeyCommitment checks that a claim key commitment was signed by the issuer it names
+func (uc *UseCase) VerifyClaimKeyCommitment(commitment *vc.ClaimKeyCommitment) error {
+	if commitment == nil {
+		return fmt.Errorf("claim key commitment is nil")
+	}
+
+	if commitment.Proof == nil || commitment.Proof.ProofValue == "" {
+		return fmt.Errorf("no proof")
+	}
+
+	if !did.IsVerificationMethodOf(commitment.Proof.VerificationMethod, commitment.Issuer) {
+		return fmt.Errorf("key %s is not controlled by issuer %s", commitment.Proof.VerificationMethod, commitment.Issuer)
+	}
+
+	signature, err := did.DecodeSignatureMultibase(commitment.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.ClaimKeyCommitmentSigningInput(commitment)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(commitment.Issuer, commitment.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	return nil
+}
+
+// checkAbsence verifies the absence proofs presented with a credential, returning the claims they
+// prove the credential does not have
+func (uc *UseCase) checkAbsence(issuerDID string, credential *vc.DerivedCredential) ([]string, error) {
+	commitment := credential.ClaimKeys
+	if commitment == nil {
+		return nil, fmt.Errorf("absence proofs presented without a claim key commitment")
+	}
+
+	if commitment.CredentialID != credential.ID {
+		return nil, fmt.Errorf("claim key commitment is for credential %s", commitment.CredentialID)
+	}
+
+	if commitment.Issuer != issuerDID {
+		return nil, fmt.Errorf("signed by %s, not the credential issuer", commitment.Issuer)
+	}
+
+	if err := uc.VerifyClaimKeyCommitment(commitment); err != nil {
+		return nil, err
+	}
+
+	proofs := credential.AbsenceProofs
+	subject := credential.CredentialSubject
+	var absent []string
+	for i := range proofs {
+		// A revealed claim cannot be absent, whatever the proof says
+		if _, revealed := subject[proofs[i].Claim]; revealed {
+			return nil, fmt.Errorf("claim %s is revealed", proofs[i].Claim)
+		}
+		if err := commitment.VerifyAbsence(&proofs[i]); err != nil {
+			return nil, err
+		}
+		if !containsClaim(absent, proofs[i].Claim) {
+			absent = append(absent, proofs[i].Claim)
+		}
+	}
+
+	return absent, nil
+}
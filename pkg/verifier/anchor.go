@@ -0,0 +1,54 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/anchor"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SetAnchorService enables anchor checks: issuer key sets and the status lists presented
+// credentials point at must match their latest anchors. Pass nil to disable.
+func (uc *UseCase) SetAnchorService(service *anchor.Service) {
+	uc.anchors = service
+}
+
+// checkAnchors checks the issuer's key set and the credential's status lists against their anchors
+func (uc *UseCase) checkAnchors(issuerDID string, credential *vc.DerivedCredential) error {
+	doc, err := uc.didService.ResolveDID(issuerDID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve issuer DID: %w", err)
+	}
+
+	digest, err := anchor.KeySetDigest(doc)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.anchors.Check(issuerDID, anchor.KindKeySet, digest); err != nil {
+		return fmt.Errorf("issuer key set: %w", err)
+	}
+
+	entries := credential.CredentialStatus
+	if len(entries) > 0 && uc.statusRegistry == nil {
+		return fmt.Errorf("status list anchors cannot be checked: no status registry configured")
+	}
+
+	for _, entry := range entries {
+		list, err := uc.statusRegistry.GetListCredential(entry.StatusListCredential)
+		if err != nil {
+			return fmt.Errorf("failed to get status list: %w", err)
+		}
+
+		digest, err := anchor.StatusListDigest(list)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.anchors.Check(list.ID, anchor.KindStatusList, digest); err != nil {
+			return fmt.Errorf("%s status list: %w", entry.StatusPurpose, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// AuditEntry records a single presentation verification
+type AuditEntry struct {
+	Timestamp      time.Time `json:"timestamp"`
+	PresentationID string    `json:"presentationId"`
+	HolderDID      string    `json:"holderDid"`
+	Valid          bool      `json:"valid"`
+	// RevealedClaims lists the claim names received, never their values
+	RevealedClaims []string `json:"revealedClaims,omitempty"`
+	vc.RequestMetadata
+	// RetainUntil is when the received data must be deleted, if a retention period was stated
+	RetainUntil *time.Time `json:"retainUntil,omitempty"`
+}
+
+// recordAudit appends a verification outcome to the audit log
+func (uc *UseCase) recordAudit(presentation *vc.VerifiablePresentation, result *VerificationResult) {
+	entry := AuditEntry{
+		Timestamp:       uc.clock.Now(),
+		HolderDID:       result.HolderDID,
+		Valid:           result.Valid,
+		RequestMetadata: result.RequestMetadata,
+	}
+	if presentation != nil {
+		entry.PresentationID = presentation.ID
+	}
+
+	for claim := range result.RevealedClaims {
+		entry.RevealedClaims = append(entry.RevealedClaims, claim)
+	}
+	sort.Strings(entry.RevealedClaims)
+
+	if entry.RetentionDays > 0 {
+		retainUntil := entry.Timestamp.AddDate(0, 0, entry.RetentionDays)
+		entry.RetainUntil = &retainUntil
+	}
+
+	// Keys sort by time, so the log reads in order across instances
+	key := fmt.Sprintf("%s%020d:%s", auditKeyPrefix, entry.Timestamp.UnixNano(), uuid.New().String())
+	if err := storage.SetJSON(uc.store, key, &entry, 0); err != nil {
+		log.Printf("failed to record audit entry for presentation %s: %v", entry.PresentationID, err)
+	}
+}
+
+// ListAuditLog returns the verification audit log, oldest entry first
+func (uc *UseCase) ListAuditLog() ([]AuditEntry, error) {
+	keys, err := uc.store.Keys(auditKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	entries := make([]AuditEntry, 0, len(keys))
+	for _, key := range keys {
+		var entry AuditEntry
+		err := storage.GetJSON(uc.store, key, &entry)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
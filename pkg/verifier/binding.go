@@ -0,0 +1,48 @@
+package verifier
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// checkHolderBinding checks that a credential bound to a holder key is presented with a proof of
+// possession of that key: the presentation must be signed with the key the credential names
+func checkHolderBinding(presentation *vc.VerifiablePresentation, credential *vc.DerivedCredential) error {
+	confirmation, err := vc.SubjectConfirmation(credential.CredentialSubject)
+	if err != nil {
+		return err
+	}
+	if confirmation == nil {
+		return nil
+	}
+
+	if presentation.Proof.ProofValue == "" {
+		return fmt.Errorf("credential is bound to %s but the presentation is not signed", confirmation.KeyID)
+	}
+	if presentation.Proof.VerificationMethod != confirmation.KeyID {
+		return fmt.Errorf("credential is bound to %s but the presentation is signed with %s", confirmation.KeyID, presentation.Proof.VerificationMethod)
+	}
+
+	// The key embedded at issuance must still sign, so replacing the key in the DID document does
+	// not let someone else present the credential
+	publicKey, err := did.DecodePublicKeyMultibase(confirmation.PublicKeyMultibase)
+	if err != nil {
+		return fmt.Errorf("invalid confirmation key: %w", err)
+	}
+	signature, err := did.DecodeSignatureMultibase(presentation.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+	payload, err := vc.PresentationSigningInput(presentation)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return fmt.Errorf("presentation is not signed with the key the credential is bound to")
+	}
+
+	return nil
+}
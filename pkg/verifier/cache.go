@@ -0,0 +1,26 @@
+package verifier
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+)
+
+// statsSource is a resolver that caches lookups and counts how they were answered
+type statsSource interface {
+	Stats() fetch.Stats
+}
+
+// CacheStats reports how DID resolutions, status list lookups and DID configuration fetches were
+// answered, keyed by "dids", "statusLists" and "domains", for whichever of the verifier's resolvers cache
+func (uc *UseCase) CacheStats() map[string]fetch.Stats {
+	stats := make(map[string]fetch.Stats)
+	if source, ok := uc.didService.(statsSource); ok {
+		stats["dids"] = source.Stats()
+	}
+	if source, ok := uc.statusRegistry.(statsSource); ok {
+		stats["statusLists"] = source.Stats()
+	}
+	uc.domainsMu.RLock()
+	stats["domains"] = uc.domains.Stats()
+	uc.domainsMu.RUnlock()
+	return stats
+}
@@ -0,0 +1,10 @@
+package verifier
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+)
+
+// SetClock sets the clock verification reads the current time from; nil restores the system clock
+func (uc *UseCase) SetClock(c clock.Clock) {
+	uc.clock = clock.OrSystem(c)
+}
@@ -0,0 +1,66 @@
+package verifier
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VerifyCommitmentBundle checks that a bundle of attribute commitments was signed by the issuer it
+// names and uses the standard generators. Verified commitments can be used as public inputs of
+// external zero-knowledge proofs about the committed claims.
+func (uc *UseCase) VerifyCommitmentBundle(bundle *vc.CommitmentBundle) error {
+	if bundle == nil {
+		return fmt.Errorf("commitment bundle is nil")
+	}
+
+	generators := bbs.DefaultCommitmentGenerators()
+	if bundle.Generators == nil || !bytes.Equal(bundle.Generators.G, generators.G) || !bytes.Equal(bundle.Generators.H, generators.H) {
+		return fmt.Errorf("commitments use non-standard generators")
+	}
+
+	if bundle.Proof == nil || bundle.Proof.ProofValue == "" {
+		return fmt.Errorf("no proof")
+	}
+
+	if !did.IsVerificationMethodOf(bundle.Proof.VerificationMethod, bundle.Issuer) {
+		return fmt.Errorf("key %s is not controlled by issuer %s", bundle.Proof.VerificationMethod, bundle.Issuer)
+	}
+
+	signature, err := did.DecodeSignatureMultibase(bundle.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.CommitmentBundleSigningInput(bundle)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(bundle.Issuer, bundle.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	return nil
+}
+
+// checkCommitments verifies the attribute commitments presented with a credential
+func (uc *UseCase) checkCommitments(issuerDID string, credential *vc.DerivedCredential) (*vc.CommitmentBundle, error) {
+	bundle := credential.Commitments
+	if bundle.CredentialID != credential.ID {
+		return nil, fmt.Errorf("commitments are for credential %s", bundle.CredentialID)
+	}
+
+	if bundle.Issuer != issuerDID {
+		return nil, fmt.Errorf("signed by %s, not the credential issuer", bundle.Issuer)
+	}
+
+	if err := uc.VerifyCommitmentBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,11 @@
+package verifier
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+)
+
+// SetContextValidator enables JSON-LD context checks: presentations and the credentials in them
+// must expand under their @context using only the validator's offline contexts. Pass nil to disable.
+func (uc *UseCase) SetContextValidator(validator *jsonld.Validator) {
+	uc.contexts = validator
+}
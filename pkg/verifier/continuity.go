@@ -0,0 +1,78 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VerifyContinuityProof checks that two credentials from one issuer hold the same value of the
+// claim a continuity proof names, and that the proof was made for nonce. When the version source
+// knows both credentials, they must also be versions of one credential.
+func (uc *UseCase) VerifyContinuityProof(proof *vc.ContinuityProof, nonce string) error {
+	if proof == nil {
+		return fmt.Errorf("continuity proof is nil")
+	}
+	if nonce == "" || proof.Nonce != nonce {
+		return fmt.Errorf("proof was not made for this nonce")
+	}
+	if proof.Previous == nil || proof.Current == nil {
+		return fmt.Errorf("commitments of both credentials are required")
+	}
+
+	previousID, currentID := proof.Previous.CredentialID, proof.Current.CredentialID
+	if previousID == currentID {
+		return fmt.Errorf("continuity is proven between two different credentials")
+	}
+	if proof.Previous.Issuer != proof.Current.Issuer {
+		return fmt.Errorf("credentials %s and %s have different issuers", previousID, currentID)
+	}
+
+	var commitments [2][]byte
+	var encodings [2]vc.CommitmentEncoding
+	for i, bundle := range []*vc.CommitmentBundle{proof.Previous, proof.Current} {
+		if err := uc.VerifyCommitmentBundle(bundle); err != nil {
+			return fmt.Errorf("commitments of credential %s: %w", bundle.CredentialID, err)
+		}
+		commitment, ok := bundle.Find(proof.Claim)
+		if !ok {
+			return fmt.Errorf("claim %s of credential %s is not committed", proof.Claim, bundle.CredentialID)
+		}
+		commitments[i], encodings[i] = commitment.Commitment, commitment.Encoding
+	}
+	if encodings[0] != encodings[1] {
+		return fmt.Errorf("claim %s is committed with different encodings", proof.Claim)
+	}
+
+	if err := uc.checkSameCredential(previousID, currentID); err != nil {
+		return err
+	}
+
+	context := vc.ContinuityContext(proof.Claim, previousID, currentID, nonce)
+	if err := bbs.VerifyEqualityProof(commitments[0], commitments[1], proof.Proof, context); err != nil {
+		return fmt.Errorf("claim %s: %w", proof.Claim, err)
+	}
+
+	return nil
+}
+
+// checkSameCredential rejects two credentials the version source knows as versions of different
+// credentials. Credentials the source does not know are not checked.
+func (uc *UseCase) checkSameCredential(previousID, currentID string) error {
+	if uc.versionSource == nil {
+		return nil
+	}
+	previous, err := uc.versionSource.LatestVersion(previousID)
+	if err != nil {
+		return nil
+	}
+	current, err := uc.versionSource.LatestVersion(currentID)
+	if err != nil {
+		return nil
+	}
+	if previous.ID != current.ID {
+		return fmt.Errorf("credentials %s and %s are not versions of one credential", previousID, currentID)
+	}
+	return nil
+}
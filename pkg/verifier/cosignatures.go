@@ -0,0 +1,90 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VerifyCoSignatures checks that the issuer an envelope names bound it to its credential and that
+// at least its threshold of distinct listed signers signed its statement
+func (uc *UseCase) VerifyCoSignatures(envelope *vc.CoSignatureEnvelope) error {
+	if envelope == nil {
+		return fmt.Errorf("co-signature envelope is nil")
+	}
+
+	if err := envelope.Validate(); err != nil {
+		return err
+	}
+
+	if envelope.Proof == nil || envelope.Proof.ProofValue == "" {
+		return fmt.Errorf("no proof")
+	}
+
+	if !did.IsVerificationMethodOf(envelope.Proof.VerificationMethod, envelope.Issuer) {
+		return fmt.Errorf("key %s is not controlled by issuer %s", envelope.Proof.VerificationMethod, envelope.Issuer)
+	}
+
+	signature, err := did.DecodeSignatureMultibase(envelope.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.CoSignatureBindingInput(envelope)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(envelope.Issuer, envelope.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	statement, err := vc.CoSignatureSigningInput(envelope)
+	if err != nil {
+		return err
+	}
+
+	signed := make(map[string]bool, len(envelope.Signatures))
+	for _, coSignature := range envelope.Signatures {
+		if !envelope.HasSigner(coSignature.Signer) {
+			return fmt.Errorf("%s is not a listed co-signer", coSignature.Signer)
+		}
+		if signed[coSignature.Signer] {
+			return fmt.Errorf("%s co-signed twice", coSignature.Signer)
+		}
+
+		signature, err := did.DecodeSignatureMultibase(coSignature.ProofValue)
+		if err != nil {
+			return fmt.Errorf("invalid co-signature of %s: %w", coSignature.Signer, err)
+		}
+		if err := uc.didService.VerifyWithDID(coSignature.Signer, coSignature.VerificationMethod, statement, signature); err != nil {
+			return fmt.Errorf("co-signature of %s: %w", coSignature.Signer, err)
+		}
+		signed[coSignature.Signer] = true
+	}
+
+	if len(signed) < envelope.Threshold {
+		return fmt.Errorf("%d of %d co-signers signed, %d needed", len(signed), len(envelope.Signers), envelope.Threshold)
+	}
+
+	return nil
+}
+
+// checkCoSignatures verifies the co-signatures presented with a credential
+func (uc *UseCase) checkCoSignatures(issuerDID string, credential *vc.DerivedCredential) (*vc.CoSignatureEnvelope, error) {
+	envelope := credential.CoSignatures
+	if envelope.CredentialID != credential.ID {
+		return nil, fmt.Errorf("co-signatures are for credential %s", envelope.CredentialID)
+	}
+
+	if envelope.Issuer != issuerDID {
+		return nil, fmt.Errorf("bound by %s, not the credential issuer", envelope.Issuer)
+	}
+
+	if err := uc.VerifyCoSignatures(envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope, nil
+}
@@ -0,0 +1,67 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// checkDelegationChain finds a chain of the presented authorizations from an issuer to one of the
+// request's trusted roots and returns the DIDs along it, starting with the issuer
+func (uc *UseCase) checkDelegationChain(req VerificationRequest, issuerDID string, credentialTypes []string) ([]string, error) {
+	var authorizations []*vc.VerifiableCredential
+	if req.Presentation != nil {
+		authorizations = req.Presentation.Authorizations
+	}
+
+	chain, err := vc.BuildDelegationChain(issuerDID, credentialTypes, authorizations, req.TrustedRoots, func(delegation *vc.Delegation) error {
+		return uc.checkAuthorization(req, delegation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dids := []string{issuerDID}
+	for _, delegation := range chain {
+		dids = append(dids, delegation.Delegator)
+	}
+	return dids, nil
+}
+
+// checkAuthorization checks an authorization in a delegation chain the way a presented credential
+// is checked: its proof, validity period, the delegator's key and its revocation status
+func (uc *UseCase) checkAuthorization(req VerificationRequest, delegation *vc.Delegation) error {
+	credential := delegation.Credential
+	if err := uc.vcService.VerifyCredential(credential); err != nil {
+		return err
+	}
+
+	version, err := credential.Version()
+	if err != nil {
+		return err
+	}
+
+	// The checks below read credentials in the form they take in presentations
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return fmt.Errorf("invalid authorization: %w", err)
+	}
+	var presented vc.DerivedCredential
+	if err := json.Unmarshal(data, &presented); err != nil {
+		return fmt.Errorf("invalid authorization: %w", err)
+	}
+
+	if err := uc.checkCredentialValidity(version, &presented); err != nil {
+		return err
+	}
+	if _, err := uc.checkIssuerKey(delegation.Delegator, &presented); err != nil {
+		return err
+	}
+
+	var snapshots []*vc.StatusSnapshot
+	if req.Presentation != nil {
+		snapshots = req.Presentation.StatusSnapshots
+	}
+	return uc.checkCredentialStatus(delegation.Delegator, &presented, snapshots, req.MaxStatusAge)
+}
@@ -0,0 +1,89 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// OverDisclosurePolicy selects what verification does when a presentation reveals more than
+// the verifier asked for
+type OverDisclosurePolicy string
+
+const (
+	// OverDisclosureReject fails verification
+	OverDisclosureReject OverDisclosurePolicy = "reject"
+	// OverDisclosureWarn reports the extra claims as warnings without affecting validity
+	OverDisclosureWarn OverDisclosurePolicy = "warn"
+)
+
+// Validate rejects policies other than warn and reject
+func (p OverDisclosurePolicy) Validate() error {
+	switch p {
+	case "", OverDisclosureReject, OverDisclosureWarn:
+		return nil
+	}
+	return fmt.Errorf("unknown over-disclosure policy %q", p)
+}
+
+// overDisclosurePolicy returns the request's policy. Without one, setting AllowedClaims or
+// MaxRevealedClaims rejects over-disclosure and anything else leaves it unchecked.
+func (req VerificationRequest) overDisclosurePolicy() OverDisclosurePolicy {
+	if req.OverDisclosure != "" {
+		return req.OverDisclosure
+	}
+	if len(req.AllowedClaims) > 0 || req.MaxRevealedClaims > 0 {
+		return OverDisclosureReject
+	}
+	return ""
+}
+
+// checkOverDisclosure finds revealed claims the verifier neither required, accepted as optional
+// nor allowed, and reports them as errors or warnings as the request's policy says. Unknown
+// policies reject.
+func checkOverDisclosure(req VerificationRequest, result *VerificationResult) {
+	policy := req.overDisclosurePolicy()
+	if policy == "" {
+		return
+	}
+
+	// Claims rejected by a constraint were still disclosed, so every credential subject counts
+	revealed := make(map[string]interface{})
+	for _, credential := range req.Presentation.VerifiableCredential {
+		if credential == nil {
+			continue
+		}
+		for key, value := range credential.CredentialSubject {
+			if key != "id" && key != vc.ConfirmationClaim {
+				revealed[key] = value
+			}
+		}
+	}
+
+	var problems []string
+	for _, claim := range sortedClaimKeys(revealed) {
+		if !containsClaim(req.RequiredClaims, claim) && !containsClaim(req.OptionalClaims, claim) && !containsClaim(req.AllowedClaims, claim) {
+			result.OverDisclosedClaims = append(result.OverDisclosedClaims, claim)
+		}
+	}
+	if len(result.OverDisclosedClaims) > 0 {
+		problems = append(problems, fmt.Sprintf("claims %v were revealed but not requested", result.OverDisclosedClaims))
+	}
+	if req.MaxRevealedClaims > 0 && len(revealed) > req.MaxRevealedClaims {
+		problems = append(problems, fmt.Sprintf("%d claims were revealed, more than the %d allowed", len(revealed), req.MaxRevealedClaims))
+	}
+
+	for _, problem := range problems {
+		if policy == OverDisclosureWarn {
+			result.Warnings = append(result.Warnings, lint.Finding{
+				Code:    lint.CodeOverDisclosure,
+				Path:    "verifiableCredential",
+				Message: problem,
+			})
+			continue
+		}
+		result.Valid = false
+		result.Errors = append(result.Errors, "over-disclosure: "+problem)
+	}
+}
@@ -0,0 +1,125 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SetDomainFetcher sets the fetcher DID configuration resources are loaded and cached with; nil
+// restores a fetcher caching for fetch.DefaultTTL
+func (uc *UseCase) SetDomainFetcher(fetcher *fetch.Fetcher) {
+	if fetcher == nil {
+		fetcher = fetch.NewFetcher(nil, fetch.DefaultTTL)
+	}
+
+	uc.domainsMu.Lock()
+	defer uc.domainsMu.Unlock()
+	uc.domains = fetcher
+}
+
+// CheckDomainLinkage checks that the origin of domain publishes a valid, signed domain linkage
+// for didString in its /.well-known/did-configuration.json
+func (uc *UseCase) CheckDomainLinkage(ctx context.Context, didString, domain string) error {
+	origin, err := vc.NormalizeOrigin(domain)
+	if err != nil {
+		return err
+	}
+
+	uc.domainsMu.RLock()
+	fetcher := uc.domains
+	uc.domainsMu.RUnlock()
+
+	body, err := fetcher.Get(ctx, origin+vc.DIDConfigurationPath)
+	if err != nil {
+		return err
+	}
+
+	var configuration vc.DIDConfiguration
+	if err := json.Unmarshal(body, &configuration); err != nil {
+		return fmt.Errorf("invalid DID configuration at %s: %w", origin, err)
+	}
+	if configuration.Context != vc.DIDConfigurationContext {
+		return fmt.Errorf("DID configuration at %s has unexpected context %q", origin, configuration.Context)
+	}
+
+	// Any one valid linkage is enough; report why the candidates failed otherwise
+	var failures []string
+	now := uc.clock.Now()
+	for _, credential := range configuration.LinkedDIDs {
+		if credential == nil || credential.CredentialSubject.ID != didString {
+			continue
+		}
+		if err := uc.verifyDomainLinkage(credential, didString, origin, now); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		return nil
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s has no valid linkage to %s: %s", origin, didString, strings.Join(failures, "; "))
+	}
+	return fmt.Errorf("%s does not link to %s", origin, didString)
+}
+
+// verifyDomainLinkage checks a linkage credential and its signature by the linked DID
+func (uc *UseCase) verifyDomainLinkage(credential *vc.DomainLinkageCredential, didString, origin string, now time.Time) error {
+	if err := vc.CheckDomainLinkage(credential, didString, origin, now); err != nil {
+		return err
+	}
+
+	signature, err := did.DecodeSignatureMultibase(credential.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.DomainLinkageSigningInput(credential)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(didString, credential.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("linkage signature verification failed: %w", err)
+	}
+
+	return nil
+}
+
+// checkIssuerTrust accepts an issuer listed in the request's trusted issuers or roots, linked to
+// one of its trusted domains, or authorized by a delegation chain presented back to a trusted root.
+// It returns the DIDs along the chain when the issuer was trusted by delegation.
+func (uc *UseCase) checkIssuerTrust(ctx context.Context, req VerificationRequest, issuerDID string, credentialTypes []string) ([]string, error) {
+	if containsClaim(req.TrustedIssuers, issuerDID) || containsClaim(req.TrustedRoots, issuerDID) {
+		return nil, nil
+	}
+
+	if len(req.TrustedDomains) == 0 && len(req.TrustedRoots) == 0 {
+		return nil, fmt.Errorf("issuer %s is not trusted", issuerDID)
+	}
+
+	var failures []string
+	for _, domain := range req.TrustedDomains {
+		err := uc.CheckDomainLinkage(ctx, issuerDID, domain)
+		if err == nil {
+			return nil, nil
+		}
+		failures = append(failures, err.Error())
+	}
+
+	if len(req.TrustedRoots) > 0 {
+		chain, err := uc.checkDelegationChain(req, issuerDID, credentialTypes)
+		if err == nil {
+			return chain, nil
+		}
+		failures = append(failures, err.Error())
+	}
+
+	return nil, fmt.Errorf("issuer %s is not trusted: %s", issuerDID, strings.Join(failures, "; "))
+}
@@ -0,0 +1,95 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+)
+
+// SetEventBus makes the verifier open sessions for RequestPresentation requests and verify the
+// presentations of SubmitPresentation requests on bus. It publishes a PresentationRequested event
+// for every session opened and a PresentationVerified event for every presentation verified.
+func (uc *UseCase) SetEventBus(bus *events.Bus) error {
+	err := bus.Handle(events.RequestPresentation, func(ctx context.Context, event events.Event) (interface{}, error) {
+		data, ok := event.Data.(events.RequestPresentationData)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s payload %T", event.Type, event.Data)
+		}
+
+		session, err := uc.CreateSession(data.VerifierDID, data.Request, data.TTL)
+		if err != nil {
+			return nil, err
+		}
+		return requestedData(session), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = bus.Handle(events.SubmitPresentation, func(ctx context.Context, event events.Event) (interface{}, error) {
+		data, ok := event.Data.(events.SubmitPresentationData)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s payload %T", event.Type, event.Data)
+		}
+		if data.Presentation == nil {
+			return nil, fmt.Errorf("presentation is required")
+		}
+
+		session, err := uc.GetSession(data.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		result, err := uc.SubmitSessionPresentation(ctx, data.SessionID, data.Presentation)
+		if err != nil {
+			return nil, err
+		}
+		return verifiedData(session.ID, session.VerifierDID, data.Presentation.ID, result), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	uc.events = bus
+	return nil
+}
+
+// publishVerified reports the outcome of a verification on the event bus
+func (uc *UseCase) publishVerified(req VerificationRequest, result *VerificationResult) {
+	if req.Presentation == nil {
+		return
+	}
+	uc.publish(events.PresentationVerified, verifiedData(req.sessionID, req.verifierDID(), req.Presentation.ID, result))
+}
+
+// publish queues an event without waiting for its subscribers
+func (uc *UseCase) publish(t events.Type, data interface{}) {
+	if uc.events == nil {
+		return
+	}
+	if err := uc.events.PublishAsync(events.New(t, data)); err != nil {
+		log.Printf("⚠️  Failed to publish %s event: %v", t, err)
+	}
+}
+
+func requestedData(session *Session) events.PresentationRequestedData {
+	return events.PresentationRequestedData{
+		SessionID:   session.ID,
+		VerifierDID: session.VerifierDID,
+		Request:     session.Request,
+		ExpiresAt:   session.ExpiresAt,
+	}
+}
+
+func verifiedData(sessionID, verifierDID, presentationID string, result *VerificationResult) events.PresentationVerifiedData {
+	return events.PresentationVerifiedData{
+		SessionID:      sessionID,
+		PresentationID: presentationID,
+		VerifierDID:    verifierDID,
+		HolderDID:      result.HolderDID,
+		Valid:          result.Valid,
+		RevealedClaims: result.RevealedClaims,
+		Errors:         result.Errors,
+	}
+}
@@ -0,0 +1,97 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VerifyEvidenceBundle checks that a credential's evidence section was signed by the issuer it names
+func (uc *UseCase) VerifyEvidenceBundle(bundle *vc.EvidenceBundle) error {
+	if bundle == nil {
+		return fmt.Errorf("evidence bundle is nil")
+	}
+
+	if bundle.Proof == nil || bundle.Proof.ProofValue == "" {
+		return fmt.Errorf("no proof")
+	}
+
+	if !did.IsVerificationMethodOf(bundle.Proof.VerificationMethod, bundle.Issuer) {
+		return fmt.Errorf("key %s is not controlled by issuer %s", bundle.Proof.VerificationMethod, bundle.Issuer)
+	}
+
+	signature, err := did.DecodeSignatureMultibase(bundle.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.EvidenceBundleSigningInput(bundle)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(bundle.Issuer, bundle.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	return nil
+}
+
+// checkEvidence verifies the evidence section presented with a credential
+func (uc *UseCase) checkEvidence(issuerDID string, credential *vc.DerivedCredential) (*vc.EvidenceBundle, error) {
+	bundle := credential.Evidence
+	if bundle.CredentialID != credential.ID {
+		return nil, fmt.Errorf("evidence is for credential %s", bundle.CredentialID)
+	}
+
+	if bundle.Issuer != issuerDID {
+		return nil, fmt.Errorf("signed by %s, not the credential issuer", bundle.Issuer)
+	}
+
+	if err := uc.VerifyEvidenceBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// OpenEvidence checks an evidence document released by the issuer under a grant against the
+// issuer-signed evidence section, unsealing it with the grant's key when it is encrypted
+func (uc *UseCase) OpenEvidence(bundle *vc.EvidenceBundle, grant *vc.EvidenceGrant, document []byte) ([]byte, error) {
+	if grant == nil {
+		return nil, fmt.Errorf("evidence grant is required")
+	}
+
+	if err := uc.VerifyEvidenceBundle(bundle); err != nil {
+		return nil, fmt.Errorf("invalid evidence bundle: %w", err)
+	}
+
+	if grant.CredentialID != bundle.CredentialID {
+		return nil, fmt.Errorf("grant is for credential %s, not %s", grant.CredentialID, bundle.CredentialID)
+	}
+
+	evidence, ok := bundle.Find(grant.EvidenceID)
+	if !ok {
+		return nil, fmt.Errorf("credential %s has no evidence %s", bundle.CredentialID, grant.EvidenceID)
+	}
+
+	if evidence.Encrypted {
+		if grant.Key == nil {
+			return nil, fmt.Errorf("evidence %s is encrypted and the grant carries no key", evidence.ID)
+		}
+		key, err := uc.didService.DecryptWithDID(grant.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt evidence key: %w", err)
+		}
+		document, err = vc.OpenDocument(document, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := evidence.CheckDocument(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
@@ -0,0 +1,196 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/notify"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SessionForward configures a session that forwards its outcome to a relying party, as a merchant
+// backend behind an age gate expects: once the presentation is verified, a signed assertion goes
+// to CallbackURL and the holder's device is only told where to return to.
+type SessionForward struct {
+	CallbackURL string `json:"callbackUrl"`
+	// RedirectURI is where the holder's device is sent once the outcome is forwarded
+	RedirectURI string `json:"redirectUri,omitempty"`
+	// AssertionID, DeliveredAt and Error record the forwarded assertion and its delivery
+	AssertionID string     `json:"assertionId,omitempty"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// SetForwardNotifier sets how assertions are delivered to relying parties. Nil restores the
+// default, which posts them as JSON to the callback URL, refusing loopback and private addresses.
+func (uc *UseCase) SetForwardNotifier(notifier notify.Notifier) {
+	if notifier == nil {
+		notifier = notify.NewWebhookNotifier(nil)
+	}
+
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	uc.forwarder = notifier
+}
+
+// CreateForwardingSession opens a cross-device session whose outcome is signed by the verifier and
+// posted to callbackURL in the background once the holder has been answered. Unless the forward
+// notifier allows them, callback URLs at loopback and private addresses are refused when the
+// assertion is delivered, and the session records the error. The session needs a verifier DID to sign with; redirectURI is optional.
+func (uc *UseCase) CreateForwardingSession(verifierDID string, request exchange.ProofRequest, ttl time.Duration, callbackURL, redirectURI string) (*Session, error) {
+	if verifierDID == "" {
+		return nil, fmt.Errorf("a verifier DID is required to sign forwarded assertions")
+	}
+	if err := notify.ValidateEndpoint(callbackURL); err != nil {
+		return nil, fmt.Errorf("invalid callback URL: %w", err)
+	}
+	if redirectURI != "" {
+		if err := notify.ValidateEndpoint(redirectURI); err != nil {
+			return nil, fmt.Errorf("invalid redirect URI: %w", err)
+		}
+	}
+
+	if _, err := uc.assertionMethod(verifierDID); err != nil {
+		return nil, err
+	}
+
+	return uc.createSession(verifierDID, request, ttl, &SessionForward{
+		CallbackURL: callbackURL,
+		RedirectURI: redirectURI,
+	})
+}
+
+// ValidateAssertion checks the verifier's signature on a forwarded assertion and, when audience is
+// set, that the assertion was forwarded to that callback URL
+func (uc *UseCase) ValidateAssertion(assertion *vc.VerificationAssertion, audience string) error {
+	if assertion == nil {
+		return fmt.Errorf("assertion is nil")
+	}
+
+	if assertion.Proof == nil || assertion.Proof.ProofValue == "" {
+		return fmt.Errorf("assertion has no proof")
+	}
+
+	signature, err := did.DecodeSignatureMultibase(assertion.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.AssertionSigningInput(assertion)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(assertion.Verifier, assertion.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("assertion signature verification failed: %w", err)
+	}
+
+	if audience != "" && assertion.Audience != audience {
+		return fmt.Errorf("assertion was forwarded to %s, not %s", assertion.Audience, audience)
+	}
+
+	return nil
+}
+
+// forwardAssertion signs the session's outcome for the relying party, recording the assertion on
+// the session. A failure is recorded instead and leaves the session's own outcome unchanged.
+func (uc *UseCase) forwardAssertion(session *Session, result *VerificationResult) *vc.VerificationAssertion {
+	assertion, err := uc.signAssertion(session, result)
+	if err != nil {
+		log.Printf("failed to sign assertion for session %s: %v", session.ID, err)
+		session.Forward.Error = err.Error()
+		return nil
+	}
+
+	session.Forward.AssertionID = assertion.ID
+	return assertion
+}
+
+// deliverAssertion delivers a session's assertion to the relying party, which the notifier retries
+// while the endpoint fails, and records the delivery on the session
+func (uc *UseCase) deliverAssertion(ctx context.Context, forwarder notify.Notifier, sessionID, callbackURL string, assertion *vc.VerificationAssertion) {
+	err := forwarder.Notify(ctx, callbackURL, assertion)
+	if err != nil {
+		log.Printf("failed to forward session %s to %s: %v", sessionID, callbackURL, err)
+	}
+
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	session, loadErr := uc.session(sessionID)
+	if loadErr != nil || session.Forward == nil {
+		// The session was purged while the assertion was on its way
+		return
+	}
+	if err != nil {
+		session.Forward.Error = err.Error()
+	} else {
+		now := uc.clock.Now()
+		session.Forward.DeliveredAt = &now
+	}
+	if err := uc.saveSession(session); err != nil {
+		log.Printf("failed to record delivery of session %s: %v", sessionID, err)
+	}
+}
+
+// signAssertion creates the verifier-signed assertion of a session's outcome. Claims are included
+// only when the presentation is valid.
+func (uc *UseCase) signAssertion(session *Session, result *VerificationResult) (*vc.VerificationAssertion, error) {
+	method, err := uc.assertionMethod(session.VerifierDID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	assertion := &vc.VerificationAssertion{
+		ID:        "urn:uuid:" + uuid.New().String(),
+		Verifier:  session.VerifierDID,
+		Audience:  session.Forward.CallbackURL,
+		SessionID: session.ID,
+		Nonce:     session.Request.Nonce,
+		Valid:     result.Valid,
+		Created:   now,
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            now,
+			VerificationMethod: method,
+			ProofPurpose:       "assertionMethod",
+		},
+	}
+	if result.Valid {
+		assertion.Claims = result.RevealedClaims
+		assertion.Predicates = result.ProvenPredicates
+	}
+
+	payload, err := vc.AssertionSigningInput(assertion)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := uc.didService.SignWithDID(method, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign assertion: %w", err)
+	}
+	assertion.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	return assertion, nil
+}
+
+// assertionMethod returns the verification method the verifier signs with
+func (uc *UseCase) assertionMethod(verifierDID string) (string, error) {
+	doc, err := uc.didService.ResolveDID(verifierDID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve verifier DID: %w", err)
+	}
+	if len(doc.AssertionMethod) == 0 {
+		return "", fmt.Errorf("verifier DID has no assertion method")
+	}
+	return doc.AssertionMethod[0], nil
+}
@@ -0,0 +1,73 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultClockSkew is the tolerance applied to presentation timestamps to absorb clock drift
+const DefaultClockSkew = 2 * time.Minute
+
+// FreshnessPolicy controls how old a presentation may be before it is rejected
+type FreshnessPolicy struct {
+	// MaxPresentationAge rejects presentations created longer ago than this; zero disables the check
+	MaxPresentationAge time.Duration
+	// ClockSkew is the tolerance allowed between the holder's and the verifier's clocks
+	ClockSkew time.Duration
+}
+
+// SetFreshnessPolicy sets the verifier-wide freshness policy
+func (uc *UseCase) SetFreshnessPolicy(policy FreshnessPolicy) error {
+	if policy.MaxPresentationAge < 0 {
+		return fmt.Errorf("maximum presentation age cannot be negative")
+	}
+
+	if policy.ClockSkew < 0 {
+		return fmt.Errorf("clock skew cannot be negative")
+	}
+
+	uc.freshness = policy
+	return nil
+}
+
+// FreshnessPolicy returns the verifier-wide freshness policy
+func (uc *UseCase) FreshnessPolicy() FreshnessPolicy {
+	return uc.freshness
+}
+
+// checkFreshness rejects presentations that are expired, too old or dated in the future
+func (uc *UseCase) checkFreshness(presentation *vc.VerifiablePresentation, maxAge time.Duration) error {
+	if maxAge == 0 {
+		maxAge = uc.freshness.MaxPresentationAge
+	}
+
+	if presentation.Proof == nil || (maxAge == 0 && presentation.Proof.Expires == nil) {
+		return nil
+	}
+
+	now := uc.clock.Now()
+	skew := uc.freshness.ClockSkew
+	created := presentation.Proof.Created
+
+	if created.IsZero() {
+		return fmt.Errorf("presentation has no creation time")
+	}
+
+	if created.After(now.Add(skew)) {
+		return fmt.Errorf("presentation was created in the future (%s)", created.Format(time.RFC3339))
+	}
+
+	if presentation.Proof.Expires != nil && now.After(presentation.Proof.Expires.Add(skew)) {
+		return fmt.Errorf("presentation expired at %s", presentation.Proof.Expires.Format(time.RFC3339))
+	}
+
+	if maxAge > 0 {
+		if age := now.Sub(created); age > maxAge+skew {
+			return fmt.Errorf("presentation is stale: created %s ago, maximum age is %s", age.Round(time.Second), maxAge)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package verifier
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// checkIssuerSet checks the issuer set of a credential presented without its issuer and returns
+// the set members and their BBS+ keys, which the credential's proof is then verified under. Each
+// member's key must have been in force when the credential was issued.
+func (uc *UseCase) checkIssuerSet(credential *vc.DerivedCredential) ([]string, [][]byte, error) {
+	proof := credential.IssuerSetProof
+	if proof.Type != vc.IssuerSetProofType {
+		return nil, nil, fmt.Errorf("unsupported issuer set proof type: %s", proof.Type)
+	}
+
+	if len(proof.VerificationMethods) != len(proof.Issuers) {
+		return nil, nil, fmt.Errorf("issuer set proof lists %d keys for %d issuers", len(proof.VerificationMethods), len(proof.Issuers))
+	}
+
+	if !sort.StringsAreSorted(proof.Issuers) {
+		return nil, nil, fmt.Errorf("issuer set is not sorted")
+	}
+
+	if credential.CredentialStatus != nil {
+		return nil, nil, fmt.Errorf("credentials with status entries cannot hide their issuer")
+	}
+
+	issuedAt, err := uc.credentialIssuedAt(credential)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid issuance date: %w", err)
+	}
+
+	publicKeys := make([][]byte, len(proof.Issuers))
+	for i, issuer := range proof.Issuers {
+		// The signed metadata names the keys; each must belong to the member it is listed for
+		keyURL, err := did.ParseDIDURL(proof.VerificationMethods[i])
+		if err != nil || keyURL.DID.String() != issuer {
+			return nil, nil, fmt.Errorf("key %s does not belong to issuer %s", proof.VerificationMethods[i], issuer)
+		}
+
+		doc, err := uc.didService.ResolveDID(issuer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve issuer %s: %w", issuer, err)
+		}
+
+		method, err := doc.VerificationMethodAt(proof.VerificationMethods[i], issuedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if publicKeys[i], err = did.BBSPublicKey(*method); err != nil {
+			return nil, nil, fmt.Errorf("issuer %s: %w", issuer, err)
+		}
+	}
+
+	return proof.Issuers, publicKeys, nil
+}
@@ -0,0 +1,38 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// checkIssuerKey resolves the key a credential's proof points at in the issuer's DID document
+// and checks it was in force when the credential was issued. Keys retired by a later rotation
+// still resolve for credentials issued before the rotation.
+func (uc *UseCase) checkIssuerKey(issuerDID string, credential *vc.DerivedCredential) (*did.VerificationMethod, error) {
+	if credential.Proof == nil {
+		return nil, fmt.Errorf("missing proof")
+	}
+
+	keyID := credential.Proof.VerificationMethod
+	if keyID == "" {
+		return nil, fmt.Errorf("proof has no verification method")
+	}
+
+	if !did.IsVerificationMethodOf(keyID, issuerDID) {
+		return nil, fmt.Errorf("key %s is not controlled by issuer %s", keyID, issuerDID)
+	}
+
+	issuedAt, err := uc.credentialIssuedAt(credential)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuance date: %w", err)
+	}
+
+	doc, err := uc.didService.ResolveDID(issuerDID)
+	if err != nil {
+		return nil, did.NewResolutionError(issuerDID, err)
+	}
+
+	return doc.VerificationMethodAt(keyID, issuedAt)
+}
@@ -0,0 +1,110 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SetStrictMode makes every verification report lint findings, as if each request set Strict
+func (uc *UseCase) SetStrictMode(enabled bool) {
+	uc.strict = enabled
+}
+
+// lintPresentation reports hardening findings about a presentation. Findings are warnings and
+// never affect whether the presentation is valid.
+func lintPresentation(req VerificationRequest) []lint.Finding {
+	findings := []lint.Finding{}
+
+	// Unknown proof fields are dropped when the presentation is decoded, so they are read from the
+	// raw presentation
+	var credentialProofs []map[string]interface{}
+	if len(req.RawPresentation) > 0 {
+		findings = append(findings, lint.CheckCanonicalJSON("presentation", req.RawPresentation)...)
+
+		var raw struct {
+			Proof                map[string]interface{} `json:"proof"`
+			VerifiableCredential []struct {
+				Proof map[string]interface{} `json:"proof"`
+			} `json:"verifiableCredential"`
+		}
+		if err := json.Unmarshal(req.RawPresentation, &raw); err == nil {
+			if raw.Proof != nil {
+				findings = append(findings, lint.CheckProofFields("proof", raw.Proof)...)
+			}
+			for _, credential := range raw.VerifiableCredential {
+				credentialProofs = append(credentialProofs, credential.Proof)
+			}
+		}
+	}
+
+	if req.Presentation.Proof.Expires == nil {
+		findings = append(findings, lint.Finding{
+			Code:    lint.CodeMissingExpiration,
+			Path:    "proof",
+			Message: "presentation proof has no expiry, so it stays usable until the freshness policy rejects it",
+		})
+	}
+
+	if req.VerificationNonce != "" {
+		findings = append(findings, lint.CheckNonce("verificationNonce", req.VerificationNonce)...)
+	}
+
+	expected := append(append([]string{}, req.RequiredClaims...), req.OptionalClaims...)
+
+	for i, credential := range req.Presentation.VerifiableCredential {
+		if credential == nil {
+			continue
+		}
+		path := fmt.Sprintf("verifiableCredential[%d]", i)
+
+		expiry, expires := "expirationDate", credential.ExpirationDate != nil
+		if version, _ := credential.Version(); version == vc.Version2 {
+			expiry, expires = "validUntil", credential.ValidUntil != nil
+		}
+		if !expires {
+			findings = append(findings, lint.Finding{
+				Code:    lint.CodeMissingExpiration,
+				Path:    path,
+				Message: fmt.Sprintf("credential has no %s and stays valid until revoked", expiry),
+			})
+		}
+
+		if i < len(credentialProofs) && credentialProofs[i] != nil {
+			findings = append(findings, lint.CheckProofFields(path+".proof", credentialProofs[i])...)
+		}
+
+		// Without a verifier nonce, replay protection rests on the holder's own nonce
+		if credential.Proof != nil && req.VerificationNonce == "" {
+			findings = append(findings, lint.CheckNonce(path+".proof.nonce", credential.Proof.Nonce)...)
+		}
+
+		if len(expected) == 0 {
+			continue
+		}
+		for _, key := range sortedClaimKeys(credential.CredentialSubject) {
+			if key != "id" && key != vc.ConfirmationClaim && !containsClaim(expected, key) {
+				findings = append(findings, lint.Finding{
+					Code:    lint.CodeExtraRevealedAttribute,
+					Path:    path + ".credentialSubject." + key,
+					Message: fmt.Sprintf("claim %s was revealed but neither required nor optional", key),
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// sortedClaimKeys returns a credential subject's keys in order, so findings are reported deterministically
+func sortedClaimKeys(subject map[string]interface{}) []string {
+	keys := make([]string, 0, len(subject))
+	for key := range subject {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// StartNegotiation opens a proof request the holder can answer with a counter-offer.
+// A nonce is generated when the request has none.
+func (uc *UseCase) StartNegotiation(verifierDID string, request exchange.ProofRequest) (*exchange.Negotiation, error) {
+	if request.Nonce == "" {
+		nonce, err := uc.GenerateNonce()
+		if err != nil {
+			return nil, err
+		}
+		request.Nonce = nonce
+	}
+
+	negotiation, err := exchange.NewNegotiation(verifierDID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.saveNegotiation(negotiation); err != nil {
+		return nil, err
+	}
+
+	return negotiation, nil
+}
+
+// GetNegotiation returns a negotiation thread and its messages
+func (uc *UseCase) GetNegotiation(id string) (*exchange.Negotiation, error) {
+	var negotiation exchange.Negotiation
+	if err := storage.GetJSON(uc.store, negotiationKeyPrefix+id, &negotiation); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("negotiation not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to load negotiation: %w", err)
+	}
+
+	return &negotiation, nil
+}
+
+// saveNegotiation writes a negotiation to the store
+func (uc *UseCase) saveNegotiation(negotiation *exchange.Negotiation) error {
+	if err := storage.SetJSON(uc.store, negotiationKeyPrefix+negotiation.ID, negotiation, 0); err != nil {
+		return fmt.Errorf("failed to store negotiation: %w", err)
+	}
+	return nil
+}
+
+// RespondToNegotiation records a holder's counter-offer and returns the verifier's decision.
+// Offers that withhold only optional claims are accepted; anything else is rejected.
+func (uc *UseCase) RespondToNegotiation(id, holderDID string, offer exchange.CounterOffer) (*exchange.Message, *exchange.Negotiation, error) {
+	if holderDID == "" {
+		return nil, nil, fmt.Errorf("holder DID is required")
+	}
+
+	uc.negotiationsMu.Lock()
+	defer uc.negotiationsMu.Unlock()
+
+	negotiation, err := uc.GetNegotiation(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decision, err := negotiation.Respond(holderDID, offer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.saveNegotiation(negotiation); err != nil {
+		return nil, nil, err
+	}
+	return decision, negotiation.Copy(), nil
+}
+
+// VerifyNegotiatedPresentation verifies a presentation against the claims agreed in a negotiation
+// rather than the original request, and completes the negotiation when it is valid
+func (uc *UseCase) VerifyNegotiatedPresentation(ctx context.Context, id string, presentation *vc.VerifiablePresentation) (*VerificationResult, error) {
+	negotiation, err := uc.GetNegotiation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if negotiation.State != exchange.StateAgreed {
+		return nil, fmt.Errorf("negotiation %s is %s, not agreed", id, negotiation.State)
+	}
+
+	if presentation == nil {
+		return nil, fmt.Errorf("presentation is required")
+	}
+
+	if presentation.Holder != negotiation.HolderDID {
+		return nil, fmt.Errorf("presentation holder %s did not take part in negotiation %s", presentation.Holder, id)
+	}
+
+	result, err := uc.VerifyPresentationContext(ctx, VerificationRequest{
+		Presentation:      presentation,
+		RequiredClaims:    negotiation.AgreedClaims,
+		TrustedIssuers:    negotiation.Request.TrustedIssuers,
+		VerificationNonce: negotiation.Request.Nonce,
+		VerifierDID:       negotiation.VerifierDID,
+		RequestMetadata:   negotiation.Request.RequestMetadata,
+	})
+	if err != nil || !result.Valid {
+		return result, err
+	}
+
+	uc.negotiationsMu.Lock()
+	defer uc.negotiationsMu.Unlock()
+
+	negotiation, err = uc.GetNegotiation(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := negotiation.Complete(); err != nil {
+		return nil, err
+	}
+	if err := uc.saveNegotiation(negotiation); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
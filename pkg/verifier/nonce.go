@@ -0,0 +1,11 @@
+package verifier
+
+import (
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+)
+
+// GenerateNonce returns a fresh proof nonce: bbs.NonceSize bytes from crypto/rand, base64url
+// encoded. Verifiers should hand these out instead of letting clients pick predictable nonces.
+func (uc *UseCase) GenerateNonce() (string, error) {
+	return bbs.GenerateNonce()
+}
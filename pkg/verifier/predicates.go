@@ -0,0 +1,93 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SetPredicateRegistry sets the providers predicate proofs are verified with. Proofs from
+// providers missing from the registry are rejected.
+func (uc *UseCase) SetPredicateRegistry(registry *predicate.Registry) {
+	uc.predicates = registry
+}
+
+// checkPredicates verifies the predicate proofs presented with a credential against its verified
+// commitments, returning the proven statements
+func (uc *UseCase) checkPredicates(bundle *vc.CommitmentBundle, credential *vc.DerivedCredential) ([]vc.PredicateStatement, error) {
+	if bundle == nil {
+		return nil, fmt.Errorf("predicates presented without verified commitments")
+	}
+
+	if uc.predicates == nil {
+		return nil, fmt.Errorf("no predicate providers configured")
+	}
+
+	var nonce string
+	if credential.Proof != nil {
+		nonce = credential.Proof.Nonce
+	}
+
+	var proven []vc.PredicateStatement
+	for _, predicateProof := range credential.Predicates {
+		statement := predicateProof.PredicateStatement
+
+		provider, ok := uc.predicates.Get(predicateProof.Provider)
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported predicate provider %s", statement, predicateProof.Provider)
+		}
+
+		commitment, ok := bundle.Find(statement.Claim)
+		if !ok {
+			return nil, fmt.Errorf("%s: claim is not committed", statement)
+		}
+
+		context := vc.PredicateContext(bundle.CredentialID, statement, commitment.Commitment, nonce)
+		if err := provider.Verify(statement, *commitment, predicateProof.ProofValue, context); err != nil {
+			return nil, fmt.Errorf("%s: %w", statement, err)
+		}
+
+		proven = append(proven, statement)
+	}
+
+	return proven, nil
+}
+
+// impliesPredicate reports whether a proven statement implies a required one,
+// e.g. salary gt 60000 implies salary gte 50000
+func impliesPredicate(proven, required vc.PredicateStatement) bool {
+	if proven.Claim != required.Claim {
+		return false
+	}
+
+	// Compare as inclusive bounds: gt v is gte v+1 and lt v is lte v-1
+	lower := func(s vc.PredicateStatement) (int64, bool) {
+		switch s.Operator {
+		case vc.PredicateGreaterOrEqual:
+			return s.Value, true
+		case vc.PredicateGreaterThan:
+			return s.Value + 1, true
+		}
+		return 0, false
+	}
+	upper := func(s vc.PredicateStatement) (int64, bool) {
+		switch s.Operator {
+		case vc.PredicateLessOrEqual:
+			return s.Value, true
+		case vc.PredicateLessThan:
+			return s.Value - 1, true
+		}
+		return 0, false
+	}
+
+	if required, ok := lower(required); ok {
+		proven, ok := lower(proven)
+		return ok && proven >= required
+	}
+	if required, ok := upper(required); ok {
+		proven, ok := upper(proven)
+		return ok && proven <= required
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// issueReceipt creates, signs and stores a receipt for a successful verification
+func (uc *UseCase) issueReceipt(verifierDID string, req VerificationRequest, result *VerificationResult) (*vc.VerificationReceipt, error) {
+	hash, err := vc.PresentationHash(req.Presentation)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := uc.didService.ResolveDID(verifierDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve verifier DID: %w", err)
+	}
+	if len(doc.AssertionMethod) == 0 {
+		return nil, fmt.Errorf("verifier DID has no assertion method")
+	}
+
+	revealed := make([]string, 0, len(result.RevealedClaims))
+	for claim := range result.RevealedClaims {
+		revealed = append(revealed, claim)
+	}
+	sort.Strings(revealed)
+
+	now := uc.clock.Now()
+	receipt := &vc.VerificationReceipt{
+		ID:               "urn:uuid:" + uuid.New().String(),
+		Type:             []string{"VerificationReceipt"},
+		VerifierDID:      verifierDID,
+		HolderDID:        req.Presentation.Holder,
+		PresentationID:   req.Presentation.ID,
+		PresentationHash: hash,
+		Timestamp:        now,
+		Outcome: vc.ReceiptOutcome{
+			Valid:          result.Valid,
+			RequiredClaims: req.RequiredClaims,
+			OptionalClaims: req.OptionalClaims,
+			TrustedIssuers: req.TrustedIssuers,
+			RevealedClaims: revealed,
+			Purpose:        req.Purpose,
+		},
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            now,
+			VerificationMethod: doc.AssertionMethod[0],
+			ProofPurpose:       "assertionMethod",
+		},
+	}
+
+	payload, err := vc.ReceiptSigningInput(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := uc.didService.SignWithDID(receipt.Proof.VerificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign receipt: %w", err)
+	}
+	receipt.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	if err := storage.SetJSON(uc.store, receiptKeyPrefix+receipt.ID, receipt, 0); err != nil {
+		return nil, fmt.Errorf("failed to store receipt: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// GetReceipt retrieves a stored verification receipt
+func (uc *UseCase) GetReceipt(id string) (*vc.VerificationReceipt, error) {
+	var receipt vc.VerificationReceipt
+	if err := storage.GetJSON(uc.store, receiptKeyPrefix+id, &receipt); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("receipt not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to load receipt: %w", err)
+	}
+	return &receipt, nil
+}
+
+// ValidateReceipt checks the verifier's signature on a receipt and, when a
+// presentation is given, that the receipt was issued for that presentation
+func (uc *UseCase) ValidateReceipt(receipt *vc.VerificationReceipt, presentation *vc.VerifiablePresentation) error {
+	if receipt == nil {
+		return fmt.Errorf("receipt is nil")
+	}
+
+	if receipt.Proof == nil || receipt.Proof.ProofValue == "" {
+		return fmt.Errorf("receipt has no proof")
+	}
+
+	signature, err := did.DecodeSignatureMultibase(receipt.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.ReceiptSigningInput(receipt)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(receipt.VerifierDID, receipt.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("receipt signature verification failed: %w", err)
+	}
+
+	if presentation != nil {
+		hash, err := vc.PresentationHash(presentation)
+		if err != nil {
+			return err
+		}
+		if hash != receipt.PresentationHash {
+			return fmt.Errorf("receipt does not match presentation")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultReplayWindow is how long a presentation is remembered when it has neither an expiry nor a
+// maximum age bounding how long it can be verified
+const DefaultReplayWindow = 24 * time.Hour
+
+// SetReplayCache makes the verifier reject presentations whose proofs it has already accepted.
+// Nil disables the check.
+func (uc *UseCase) SetReplayCache(cache replay.Cache) {
+	uc.replay = cache
+}
+
+// checkReplay records an accepted presentation for as long as it could still pass the freshness
+// check, and fails if it was accepted before
+func (uc *UseCase) checkReplay(presentation *vc.VerifiablePresentation, maxAge time.Duration) error {
+	key, err := replay.ProofHash(presentation)
+	if err != nil {
+		return err
+	}
+
+	// Replay caches expire entries by the system clock, so the window is carried over as a duration
+	expiresAt := time.Now().Add(uc.replayWindow(presentation, maxAge).Sub(uc.clock.Now()))
+	added, err := uc.replay.Add(key, expiresAt)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return fmt.Errorf("presentation has already been verified")
+	}
+
+	return nil
+}
+
+// replayWindow returns when a presentation stops passing the freshness check: its expiry or its
+// maximum age, whichever comes first, plus the tolerated clock skew
+func (uc *UseCase) replayWindow(presentation *vc.VerifiablePresentation, maxAge time.Duration) time.Time {
+	if maxAge == 0 {
+		maxAge = uc.freshness.MaxPresentationAge
+	}
+	skew := uc.freshness.ClockSkew
+
+	var until time.Time
+	if presentation.Proof != nil {
+		if presentation.Proof.Expires != nil {
+			until = presentation.Proof.Expires.Add(skew)
+		}
+		if maxAge > 0 && !presentation.Proof.Created.IsZero() {
+			if byAge := presentation.Proof.Created.Add(maxAge + skew); until.IsZero() || byAge.Before(until) {
+				until = byAge
+			}
+		}
+	}
+
+	if until.IsZero() {
+		until = uc.clock.Now().Add(DefaultReplayWindow)
+	}
+	return until
+}
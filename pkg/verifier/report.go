@@ -0,0 +1,164 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// recordReport signs and stores a report of the verification when the request names the verifier.
+// Rejections are reported too, so the archive shows every decision.
+func (uc *UseCase) recordReport(req VerificationRequest, result *VerificationResult) {
+	verifierDID := req.verifierDID()
+	if verifierDID == "" || req.Presentation == nil {
+		return
+	}
+
+	report, err := uc.issueReport(verifierDID, req, result)
+	if err != nil {
+		// Log error but don't fail verification
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to issue report: %v", err))
+		return
+	}
+	result.ReportID = report.ID
+}
+
+// issueReport creates and signs a report of a verification
+func (uc *UseCase) issueReport(verifierDID string, req VerificationRequest, result *VerificationResult) (*vc.VerificationReport, error) {
+	hash, err := vc.PresentationHash(req.Presentation)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := uc.didService.ResolveDID(verifierDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve verifier DID: %w", err)
+	}
+	if len(doc.AssertionMethod) == 0 {
+		return nil, fmt.Errorf("verifier DID has no assertion method")
+	}
+
+	now := uc.clock.Now()
+	report := &vc.VerificationReport{
+		ID:               "urn:uuid:" + uuid.New().String(),
+		Type:             []string{"VerificationReport"},
+		VerifierDID:      verifierDID,
+		HolderDID:        req.Presentation.Holder,
+		PresentationID:   req.Presentation.ID,
+		PresentationHash: hash,
+		VerifiedAt:       now,
+		Policy: vc.ReportPolicy{
+			RequiredClaims:     req.RequiredClaims,
+			OptionalClaims:     req.OptionalClaims,
+			TrustedIssuers:     req.TrustedIssuers,
+			TrustedDomains:     req.TrustedDomains,
+			RequiredPredicates: req.RequiredPredicates,
+			Purpose:            req.Purpose,
+			Strict:             req.Strict || uc.strict,
+		},
+		Valid:            result.Valid,
+		Errors:           append([]string(nil), result.Errors...),
+		RevealedClaims:   make(map[string]interface{}, len(result.RevealedClaims)),
+		ProvenPredicates: result.ProvenPredicates,
+		Issuers:          reportIssuers(req.Presentation),
+		Proof: &vc.Proof{
+			Type:               "Ed25519Signature2020",
+			Created:            now,
+			VerificationMethod: doc.AssertionMethod[0],
+			ProofPurpose:       "assertionMethod",
+		},
+	}
+	for claim, value := range result.RevealedClaims {
+		report.RevealedClaims[claim] = value
+	}
+	if req.Presentation.Proof != nil && !req.Presentation.Proof.Created.IsZero() {
+		presentedAt := req.Presentation.Proof.Created
+		report.PresentedAt = &presentedAt
+	}
+
+	payload, err := vc.ReportSigningInput(report)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := uc.didService.SignWithDID(report.Proof.VerificationMethod, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign report: %w", err)
+	}
+	report.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+	if err := storage.SetJSON(uc.store, reportKeyPrefix+report.ID, report, 0); err != nil {
+		return nil, fmt.Errorf("failed to store report: %w", err)
+	}
+
+	return report, nil
+}
+
+// reportIssuers lists the issuer each presented credential names, or the set it was hidden in
+func reportIssuers(presentation *vc.VerifiablePresentation) []vc.ReportIssuer {
+	issuers := []vc.ReportIssuer{}
+	for i, credential := range presentation.VerifiableCredential {
+		if credential == nil {
+			continue
+		}
+
+		entry := vc.ReportIssuer{
+			CredentialIndex: i,
+			CredentialTypes: credential.Type,
+			CredentialID:    credential.ID,
+			IssuerDID:       credential.IssuerDID(),
+		}
+		if entry.IssuerDID == "" && credential.IssuerSetProof != nil {
+			entry.IssuerSet = credential.IssuerSetProof.Issuers
+		}
+		if credential.Proof != nil {
+			entry.VerificationMethod = credential.Proof.VerificationMethod
+		}
+
+		issuers = append(issuers, entry)
+	}
+	return issuers
+}
+
+// GetReport retrieves a stored verification report
+func (uc *UseCase) GetReport(id string) (*vc.VerificationReport, error) {
+	var report vc.VerificationReport
+	if err := storage.GetJSON(uc.store, reportKeyPrefix+id, &report); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("report not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to load report: %w", err)
+	}
+	return &report, nil
+}
+
+// ValidateReport checks the verifier's signature on a report
+func (uc *UseCase) ValidateReport(report *vc.VerificationReport) error {
+	if report == nil {
+		return fmt.Errorf("report is nil")
+	}
+
+	if report.Proof == nil || report.Proof.ProofValue == "" {
+		return fmt.Errorf("report has no proof")
+	}
+
+	signature, err := did.DecodeSignatureMultibase(report.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.ReportSigningInput(report)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(report.VerifierDID, report.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("report signature verification failed: %w", err)
+	}
+
+	return nil
+}
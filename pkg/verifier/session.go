@@ -0,0 +1,433 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// DefaultSessionTTL is how long a cross-device session waits for the holder's presentation
+const DefaultSessionTTL = 5 * time.Minute
+
+const (
+	sessionKeyPrefix = "session:"
+	// claimKeyPrefix marks a session whose presentation was accepted for verification, so only one
+	// server instance sharing the store verifies it
+	claimKeyPrefix = "claim:"
+	// sessionPollInterval bounds how long a waiter can miss a change made by another server instance
+	sessionPollInterval = time.Second
+)
+
+// SessionState represents where a cross-device session stands
+type SessionState string
+
+const (
+	// SessionPending waits for the holder's device to fetch the request
+	SessionPending SessionState = "pending"
+	// SessionRetrieved means the holder's device fetched the request
+	SessionRetrieved SessionState = "retrieved"
+	// SessionSubmitted means a presentation arrived and is being verified
+	SessionSubmitted SessionState = "submitted"
+	// SessionCompleted means the presentation was verified and accepted
+	SessionCompleted SessionState = "completed"
+	// SessionFailed means the presentation was rejected
+	SessionFailed SessionState = "failed"
+	// SessionExpired means no presentation arrived in time
+	SessionExpired SessionState = "expired"
+)
+
+// Done reports whether the session reached a final state
+func (s SessionState) Done() bool {
+	return s == SessionCompleted || s == SessionFailed || s == SessionExpired
+}
+
+// Session is a cross-device presentation exchange: the verifier shows the session on one device,
+// typically as a QR code, and the holder answers it from another. Each session accepts a single
+// presentation.
+type Session struct {
+	ID          string                `json:"id"`
+	State       SessionState          `json:"state"`
+	VerifierDID string                `json:"verifierDid,omitempty"`
+	Request     exchange.ProofRequest `json:"request"`
+	HolderDID   string                `json:"holderDid,omitempty"`
+	Result      *VerificationResult   `json:"result,omitempty"`
+	// Forward is set for a session whose outcome is forwarded to a relying party
+	Forward     *SessionForward `json:"forward,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	ExpiresAt   time.Time       `json:"expiresAt"`
+	RetrievedAt *time.Time      `json:"retrievedAt,omitempty"`
+	FinishedAt  *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// SetSessionTTL sets how long new sessions wait for a presentation
+func (uc *UseCase) SetSessionTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("session TTL must be positive")
+	}
+
+	uc.sessionsMu.Lock()
+	uc.sessionTTL = ttl
+	uc.sessionsMu.Unlock()
+	return nil
+}
+
+// CreateSession opens a cross-device session for a proof request. A nonce is generated when the
+// request has none; ttl overrides the verifier's session TTL when non-zero.
+func (uc *UseCase) CreateSession(verifierDID string, request exchange.ProofRequest, ttl time.Duration) (*Session, error) {
+	return uc.createSession(verifierDID, request, ttl, nil)
+}
+
+// createSession opens a session, forwarding its outcome when forward is set
+func (uc *UseCase) createSession(verifierDID string, request exchange.ProofRequest, ttl time.Duration, forward *SessionForward) (*Session, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	if ttl < 0 {
+		return nil, fmt.Errorf("session TTL cannot be negative")
+	}
+
+	if request.Nonce == "" {
+		nonce, err := uc.GenerateNonce()
+		if err != nil {
+			return nil, err
+		}
+		request.Nonce = nonce
+	}
+
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	if ttl == 0 {
+		ttl = uc.sessionTTL
+	}
+
+	now := uc.clock.Now()
+	session := &Session{
+		ID:          uuid.New().String(),
+		State:       SessionPending,
+		VerifierDID: verifierDID,
+		Request:     request,
+		Forward:     forward,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	if err := uc.saveSession(session); err != nil {
+		return nil, err
+	}
+	uc.publish(events.PresentationRequested, requestedData(session))
+
+	return session, nil
+}
+
+// GetSession returns the current state of a session
+func (uc *UseCase) GetSession(id string) (*Session, error) {
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	return uc.session(id)
+}
+
+// FetchSessionRequest returns a session's proof request to the holder's device and records that
+// the request was retrieved, so the verifier's device can show progress
+func (uc *UseCase) FetchSessionRequest(id string) (*Session, error) {
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	session, err := uc.session(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.State.Done() || session.State == SessionSubmitted {
+		return nil, fmt.Errorf("session %s is %s", id, session.State)
+	}
+
+	if session.State == SessionPending {
+		now := uc.clock.Now()
+		session.State = SessionRetrieved
+		session.RetrievedAt = &now
+		if err := uc.saveSession(session); err != nil {
+			return nil, err
+		}
+		uc.notifySession(session)
+	}
+
+	return session, nil
+}
+
+// SubmitSessionPresentation verifies the holder's answer to a session against its proof request.
+// A session accepts one presentation; the result is kept for the verifier's device.
+func (uc *UseCase) SubmitSessionPresentation(ctx context.Context, id string, presentation *vc.VerifiablePresentation) (*VerificationResult, error) {
+	if presentation == nil {
+		return nil, fmt.Errorf("presentation is required")
+	}
+
+	uc.sessionsMu.Lock()
+	session, err := uc.session(id)
+	if err != nil {
+		uc.sessionsMu.Unlock()
+		return nil, err
+	}
+	if session.State.Done() || session.State == SessionSubmitted {
+		uc.sessionsMu.Unlock()
+		return nil, fmt.Errorf("session %s is %s", id, session.State)
+	}
+
+	// Claim the session before verifying so a second submission is refused, here or on another
+	// server instance sharing the store
+	claimed, err := uc.store.SetNX(claimKeyPrefix+id, []byte(presentation.Holder), 0)
+	if err != nil {
+		uc.sessionsMu.Unlock()
+		return nil, fmt.Errorf("failed to claim session: %w", err)
+	}
+	if !claimed {
+		uc.sessionsMu.Unlock()
+		return nil, fmt.Errorf("session %s is %s", id, SessionSubmitted)
+	}
+	session.State = SessionSubmitted
+	session.HolderDID = presentation.Holder
+	if err := uc.saveSession(session); err != nil {
+		uc.sessionsMu.Unlock()
+		return nil, err
+	}
+	uc.notifySession(session)
+	request := session.Request
+	verifierDID := session.VerifierDID
+	uc.sessionsMu.Unlock()
+
+	result, err := uc.VerifyPresentationContext(ctx, VerificationRequest{
+		Presentation:      presentation,
+		RequiredClaims:    request.RequiredClaims,
+		OptionalClaims:    request.OptionalClaims,
+		TrustedIssuers:    request.TrustedIssuers,
+		VerificationNonce: request.Nonce,
+		VerifierDID:       verifierDID,
+		RequestMetadata:   request.RequestMetadata,
+		sessionID:         id,
+	})
+
+	var assertion *vc.VerificationAssertion
+	if session.Forward != nil && err == nil {
+		assertion = uc.forwardAssertion(session, result)
+	}
+
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	now := uc.clock.Now()
+	session.FinishedAt = &now
+	session.Result = result
+	if err == nil && result.Valid {
+		session.State = SessionCompleted
+	} else {
+		session.State = SessionFailed
+	}
+	if saveErr := uc.saveSession(session); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	uc.finishSession(session)
+
+	// The holder is not kept waiting while the relying party's endpoint is retried
+	if assertion != nil {
+		go uc.deliverAssertion(context.WithoutCancel(ctx), uc.forwarder, session.ID, session.Forward.CallbackURL, assertion)
+	}
+
+	return result, err
+}
+
+// WatchSession returns a channel that receives a signal whenever the session changes state and
+// is closed once the session finishes. The returned function stops watching.
+func (uc *UseCase) WatchSession(id string) (<-chan struct{}, func(), error) {
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	session, err := uc.session(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Watchers are local to this instance; changes made elsewhere are picked up by polling
+	ch := make(chan struct{}, 1)
+	if session.State.Done() {
+		close(ch)
+		return ch, func() {}, nil
+	}
+	uc.sessionWatchers[id] = append(uc.sessionWatchers[id], ch)
+
+	cancel := func() {
+		uc.sessionsMu.Lock()
+		defer uc.sessionsMu.Unlock()
+
+		watchers := uc.sessionWatchers[id]
+		for i, w := range watchers {
+			if w == ch {
+				uc.sessionWatchers[id] = append(watchers[:i], watchers[i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}
+
+	return ch, cancel, nil
+}
+
+// WaitForSession blocks until the session leaves the given state, finishes, or ctx is done,
+// then returns its current state. It lets the verifier's device long-poll for the result.
+func (uc *UseCase) WaitForSession(ctx context.Context, id string, state SessionState) (*Session, error) {
+	updates, stop, err := uc.WatchSession(id)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	for {
+		session, err := uc.GetSession(id)
+		if err != nil {
+			return nil, err
+		}
+		if session.State != state || session.State.Done() {
+			return session, nil
+		}
+
+		// Nothing signals an unanswered session when it expires, nor a change made by another
+		// server instance, so wake up at its expiry or the next poll, whichever comes first
+		wait := session.ExpiresAt.Sub(uc.clock.Now())
+		if wait > sessionPollInterval {
+			wait = sessionPollInterval
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return session, nil
+		case <-updates:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// PurgeExpiredSessions removes sessions that finished or expired before the cutoff
+func (uc *UseCase) PurgeExpiredSessions(cutoff time.Time) int {
+	uc.sessionsMu.Lock()
+	defer uc.sessionsMu.Unlock()
+
+	keys, err := uc.store.Keys(sessionKeyPrefix)
+	if err != nil {
+		return 0
+	}
+
+	purged := 0
+	for _, key := range keys {
+		session, err := uc.session(key[len(sessionKeyPrefix):])
+		if err != nil {
+			continue
+		}
+		finished := session.ExpiresAt
+		if session.FinishedAt != nil {
+			finished = *session.FinishedAt
+		}
+		if finished.Before(cutoff) {
+			uc.store.Delete(key)
+			uc.store.Delete(claimKeyPrefix + session.ID)
+			purged++
+		}
+	}
+
+	return purged
+}
+
+// RunSessionCleanup purges sessions that ended more than retain ago, at the given interval,
+// until the context is cancelled
+func (uc *UseCase) RunSessionCleanup(ctx context.Context, interval, retain time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.PurgeExpiredSessions(uc.clock.Now().Add(-retain))
+		}
+	}
+}
+
+// session loads a session, expiring it first when its time is up. Callers hold sessionsMu.
+func (uc *UseCase) session(id string) (*Session, error) {
+	data, err := uc.store.Get(sessionKeyPrefix + id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session %s: %w", id, err)
+	}
+
+	if err := uc.expireSession(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// saveSession writes a session back to the store. Callers hold sessionsMu.
+func (uc *UseCase) saveSession(session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := uc.store.Set(sessionKeyPrefix+session.ID, data, 0); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// expireSession moves an unanswered session past its expiry to SessionExpired. A session whose
+// presentation is being verified is left to finish. Callers hold sessionsMu.
+func (uc *UseCase) expireSession(session *Session) error {
+	if session.State.Done() || session.State == SessionSubmitted || uc.clock.Now().Before(session.ExpiresAt) {
+		return nil
+	}
+
+	now := uc.clock.Now()
+	session.State = SessionExpired
+	session.FinishedAt = &now
+	if err := uc.saveSession(session); err != nil {
+		return err
+	}
+	uc.finishSession(session)
+	return nil
+}
+
+// notifySession signals the session's watchers. Callers hold sessionsMu.
+func (uc *UseCase) notifySession(session *Session) {
+	for _, ch := range uc.sessionWatchers[session.ID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A notification is already pending; the watcher will read the latest state
+		}
+	}
+}
+
+// finishSession closes the session's watcher channels. Callers hold sessionsMu.
+func (uc *UseCase) finishSession(session *Session) {
+	for _, ch := range uc.sessionWatchers[session.ID] {
+		close(ch)
+	}
+	delete(uc.sessionWatchers, session.ID)
+}
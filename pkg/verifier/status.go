@@ -0,0 +1,126 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// SetStatusRegistry sets the status list registry used to check revocation and suspension
+func (uc *UseCase) SetStatusRegistry(registry status.Registry) {
+	uc.statusRegistry = registry
+}
+
+// DefaultMaxStatusAge is how old an embedded status snapshot may be before it is rejected
+const DefaultMaxStatusAge = 24 * time.Hour
+
+// SetMaxStatusAge sets how old an embedded status snapshot may be. Zero ignores
+// snapshots, so every status check goes to the status registry.
+func (uc *UseCase) SetMaxStatusAge(maxAge time.Duration) error {
+	if maxAge < 0 {
+		return fmt.Errorf("maximum status age cannot be negative")
+	}
+
+	uc.maxStatusAge = maxAge
+	return nil
+}
+
+// MaxStatusAge returns how old an embedded status snapshot may be
+func (uc *UseCase) MaxStatusAge() time.Duration {
+	return uc.maxStatusAge
+}
+
+// checkCredentialStatus rejects a presented credential whose revocation or suspension bit is set.
+// Entries covered by a status snapshot in the presentation are checked against the snapshot,
+// when snapshots are accepted, and the rest against the status registry.
+func (uc *UseCase) checkCredentialStatus(issuer string, credential *vc.DerivedCredential, snapshots []*vc.StatusSnapshot, maxAge time.Duration) error {
+	entries := credential.CredentialStatus
+	if maxAge == 0 {
+		maxAge = uc.maxStatusAge
+	}
+
+	for i := range entries {
+		set, err := uc.entryStatus(issuer, &entries[i], snapshots, maxAge)
+		if err != nil {
+			return err
+		}
+		if !set {
+			continue
+		}
+
+		switch entries[i].StatusPurpose {
+		case status.PurposeRevocation:
+			return fmt.Errorf("credential has been revoked")
+		case status.PurposeSuspension:
+			return fmt.Errorf("credential is suspended")
+		default:
+			return fmt.Errorf("credential status %s is set", entries[i].StatusPurpose)
+		}
+	}
+
+	return nil
+}
+
+// entryStatus reads the bit a status entry points at from a matching snapshot or the registry
+func (uc *UseCase) entryStatus(issuer string, entry *status.Entry, snapshots []*vc.StatusSnapshot, maxAge time.Duration) (bool, error) {
+	if maxAge > 0 {
+		for _, snapshot := range snapshots {
+			if snapshot == nil || !snapshot.Covers(entry) {
+				continue
+			}
+			if err := uc.validateStatusSnapshot(issuer, snapshot, maxAge); err != nil {
+				return false, fmt.Errorf("invalid %s status snapshot: %w", entry.StatusPurpose, err)
+			}
+			return snapshot.Status, nil
+		}
+	}
+
+	if uc.statusRegistry == nil {
+		return false, fmt.Errorf("credential status cannot be checked: no status registry configured")
+	}
+
+	set, err := uc.statusRegistry.GetStatus(entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s status: %w", entry.StatusPurpose, err)
+	}
+	return set, nil
+}
+
+// validateStatusSnapshot checks a snapshot was signed by the credential's issuer and is recent enough
+func (uc *UseCase) validateStatusSnapshot(issuer string, snapshot *vc.StatusSnapshot, maxAge time.Duration) error {
+	if snapshot.Issuer != issuer {
+		return fmt.Errorf("signed by %s, not the credential issuer", snapshot.Issuer)
+	}
+
+	now := uc.clock.Now()
+	skew := uc.freshness.ClockSkew
+	if snapshot.Timestamp.After(now.Add(skew)) {
+		return fmt.Errorf("dated in the future (%s)", snapshot.Timestamp.Format(time.RFC3339))
+	}
+	if age := now.Sub(snapshot.Timestamp); age > maxAge+skew {
+		return fmt.Errorf("stale: taken %s ago, maximum age is %s", age.Round(time.Second), maxAge)
+	}
+
+	if snapshot.Proof == nil || snapshot.Proof.ProofValue == "" {
+		return fmt.Errorf("no proof")
+	}
+
+	signature, err := did.DecodeSignatureMultibase(snapshot.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.StatusSnapshotSigningInput(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.didService.VerifyWithDID(issuer, snapshot.Proof.VerificationMethod, payload, signature); err != nil {
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	return nil
+}
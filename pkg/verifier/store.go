@@ -0,0 +1,24 @@
+package verifier
+
+import "github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+
+// Store key prefixes for the verifier's state besides sessions
+const (
+	negotiationKeyPrefix = "negotiation:"
+	receiptKeyPrefix     = "receipt:"
+	reportKeyPrefix      = "report:"
+	auditKeyPrefix       = "audit:"
+)
+
+// SetStore keeps sessions, negotiations, receipts, reports and the audit log in store, so several
+// server instances sharing it serve the same verifier. State already recorded is not moved. Nil
+// restores an in-memory store.
+func (uc *UseCase) SetStore(store storage.KVStore) {
+	if store == nil {
+		store = storage.NewMemoryStore()
+	}
+
+	uc.sessionsMu.Lock()
+	uc.store = store
+	uc.sessionsMu.Unlock()
+}
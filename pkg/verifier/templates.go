@@ -0,0 +1,155 @@
+package verifier
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// RequestTemplate is a reusable verification request for a common scenario. Naming one in a
+// request adds its claims and constraints; policy and metadata the request sets itself are kept.
+type RequestTemplate struct {
+	Name             string
+	Description      string
+	RequiredClaims   []string
+	OptionalClaims   []string
+	ClaimConstraints map[string]vc.ClaimConstraint
+	// OverDisclosure applies to claims beyond the template's required and optional ones
+	OverDisclosure OverDisclosurePolicy
+	// MaxPresentationAge bounds how long ago the presentation may have been created
+	MaxPresentationAge time.Duration
+	// Purpose and RetentionDays are stated to the holder and drive its disclosure analysis
+	Purpose       string
+	RetentionDays int
+}
+
+// requestTemplates are the built-in templates by name
+var requestTemplates = map[string]*RequestTemplate{
+	"age-over-18": {
+		Name:               "age-over-18",
+		Description:        "Confirm the holder is at least 18 without learning their age or identity",
+		RequiredClaims:     []string{"ageOver18"},
+		OverDisclosure:     OverDisclosureReject,
+		MaxPresentationAge: 5 * time.Minute,
+		Purpose:            "age-over-18",
+	},
+	"kyc-lite": {
+		Name:               "kyc-lite",
+		Description:        "Establish the holder's legal identity for low-risk onboarding",
+		RequiredClaims:     []string{"firstName", "lastName", "dateOfBirth", "idNumber"},
+		OptionalClaims:     []string{"nationality", "address"},
+		OverDisclosure:     OverDisclosureReject,
+		MaxPresentationAge: 15 * time.Minute,
+		Purpose:            "identity-verification",
+		RetentionDays:      365,
+	},
+	"proof-of-degree": {
+		Name:           "proof-of-degree",
+		Description:    "Confirm the holder holds a university degree",
+		RequiredClaims: []string{"degree", "university"},
+		OptionalClaims: []string{"major", "graduationYear"},
+		ClaimConstraints: map[string]vc.ClaimConstraint{
+			"degree":     {CredentialTypes: []string{"UniversityDegreeCredential"}},
+			"university": {CredentialTypes: []string{"UniversityDegreeCredential"}},
+		},
+		OverDisclosure:     OverDisclosureWarn,
+		MaxPresentationAge: time.Hour,
+		Purpose:            "education-verification",
+		RetentionDays:      90,
+	},
+	"proof-of-employment": {
+		Name:               "proof-of-employment",
+		Description:        "Confirm the holder's current employer and role",
+		RequiredClaims:     []string{"employer", "jobTitle"},
+		OptionalClaims:     []string{"employmentStartDate"},
+		OverDisclosure:     OverDisclosureWarn,
+		MaxPresentationAge: time.Hour,
+		RetentionDays:      90,
+	},
+}
+
+// RequestTemplates lists the built-in request templates by name
+func RequestTemplates() []*RequestTemplate {
+	templates := make([]*RequestTemplate, 0, len(requestTemplates))
+	for _, template := range requestTemplates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// GetRequestTemplate returns a built-in request template
+func GetRequestTemplate(name string) (*RequestTemplate, error) {
+	template, ok := requestTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown request template %q", name)
+	}
+	return template, nil
+}
+
+// applyTo adds the template's claims and constraints to a request being created and fills the
+// policy and metadata the request leaves unset
+func (t *RequestTemplate) applyTo(params *CreateVerificationRequestParams) {
+	params.RequiredClaims = mergeClaims(params.RequiredClaims, t.RequiredClaims, nil)
+	params.OptionalClaims = mergeClaims(params.OptionalClaims, t.OptionalClaims, params.RequiredClaims)
+	params.ClaimConstraints = mergeConstraints(params.ClaimConstraints, t.ClaimConstraints)
+	if params.OverDisclosure == "" {
+		params.OverDisclosure = t.OverDisclosure
+	}
+	if params.MaxPresentationAge == 0 {
+		params.MaxPresentationAge = t.MaxPresentationAge
+	}
+	if params.Purpose == "" {
+		params.Purpose = t.Purpose
+	}
+	if params.RetentionDays == 0 {
+		params.RetentionDays = t.RetentionDays
+	}
+}
+
+// applyToVerification expands the template into a verification request the same way
+func (t *RequestTemplate) applyToVerification(req *VerificationRequest) {
+	req.RequiredClaims = mergeClaims(req.RequiredClaims, t.RequiredClaims, nil)
+	req.OptionalClaims = mergeClaims(req.OptionalClaims, t.OptionalClaims, req.RequiredClaims)
+	req.ClaimConstraints = mergeConstraints(req.ClaimConstraints, t.ClaimConstraints)
+	if req.OverDisclosure == "" {
+		req.OverDisclosure = t.OverDisclosure
+	}
+	if req.MaxPresentationAge == 0 {
+		req.MaxPresentationAge = t.MaxPresentationAge
+	}
+	if req.Purpose == "" {
+		req.Purpose = t.Purpose
+	}
+	if req.RetentionDays == 0 {
+		req.RetentionDays = t.RetentionDays
+	}
+}
+
+// mergeClaims returns the claims with the additional ones not already listed or excluded
+func mergeClaims(claims, additional, exclude []string) []string {
+	merged := append([]string(nil), claims...)
+	for _, claim := range additional {
+		if !containsClaim(merged, claim) && !containsClaim(exclude, claim) {
+			merged = append(merged, claim)
+		}
+	}
+	return merged
+}
+
+// mergeConstraints adds the constraints for claims the request does not constrain itself
+func mergeConstraints(constraints, additional map[string]vc.ClaimConstraint) map[string]vc.ClaimConstraint {
+	if len(additional) == 0 {
+		return constraints
+	}
+	merged := make(map[string]vc.ClaimConstraint, len(constraints)+len(additional))
+	for claim, constraint := range additional {
+		merged[claim] = constraint
+	}
+	for claim, constraint := range constraints {
+		merged[claim] = constraint
+	}
+	return merged
+}
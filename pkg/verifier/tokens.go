@@ -0,0 +1,61 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// PresentationTokenSource redeems the one-time presentation tokens a holder's wallet minted,
+// returning the pre-derived presentation and the nonce it was derived with
+type PresentationTokenSource interface {
+	RedeemPresentationToken(id, secret string) (*vc.VerifiablePresentation, string, error)
+}
+
+// SetPresentationTokenSource sets the wallet presentation tokens are redeemed from
+func (uc *UseCase) SetPresentationTokenSource(source PresentationTokenSource) {
+	uc.tokenSource = source
+}
+
+// RedeemPresentationToken redeems a presentation token and verifies its presentation against the
+// verifier's request in a session, which records the result. The holder chose the nonce, so
+// freshness rests on the presentation's expiry and on the replay cache, which must be configured:
+// it keeps a proof that leaked before redemption from being accepted twice.
+func (uc *UseCase) RedeemPresentationToken(ctx context.Context, id, secret, verifierDID string, request exchange.ProofRequest) (*Session, error) {
+	if uc.tokenSource == nil {
+		return nil, fmt.Errorf("no presentation token source configured")
+	}
+	if uc.replay == nil {
+		return nil, fmt.Errorf("redeeming presentation tokens requires a replay cache")
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	presentation, nonce, err := uc.tokenSource.RedeemPresentationToken(id, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session lasts as long as the presentation is accepted
+	request.Nonce = nonce
+	var ttl time.Duration
+	if presentation.Proof != nil && presentation.Proof.Expires != nil {
+		if remaining := presentation.Proof.Expires.Sub(uc.clock.Now()); remaining > 0 {
+			ttl = remaining
+		}
+	}
+
+	session, err := uc.CreateSession(verifierDID, request, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.SubmitSessionPresentation(ctx, session.ID, presentation); err != nil {
+		return nil, err
+	}
+
+	return uc.GetSession(session.ID)
+}
@@ -0,0 +1,906 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/anchor"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/notify"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// UseCase represents the verifier use case
+type UseCase struct {
+	didService did.DIDService
+	vcService  vc.CredentialService
+	presRepo   vc.PresentationRepository
+
+	// clock dates receipts, sessions and audit entries and judges freshness and expiry
+	clock clock.Clock
+
+	// statusRegistry resolves revocation and suspension status lists
+	statusRegistry status.Registry
+
+	freshness FreshnessPolicy
+
+	// maxStatusAge bounds the age of status snapshots embedded in presentations; zero ignores them
+	maxStatusAge time.Duration
+
+	// anchors is nil unless anchor checks are enabled
+	anchors *anchor.Service
+
+	// predicates verifies predicate proofs about committed claims
+	predicates *predicate.Registry
+
+	// contexts is nil unless JSON-LD context checks are enabled
+	contexts *jsonld.Validator
+
+	// strict reports lint findings for every verification
+	strict bool
+
+	// replay is nil unless presentations may be accepted only once
+	replay replay.Cache
+
+	// tokenSource redeems presentation tokens minted by holder wallets
+	tokenSource PresentationTokenSource
+
+	// versionSource is nil unless presented credentials are checked for newer versions
+	versionSource VersionSource
+
+	// forwarder delivers the assertions of forwarding sessions to relying parties
+	forwarder notify.Notifier
+
+	// events is nil unless the verifier is reached over an event bus; see SetEventBus
+	events *events.Bus
+
+	// domains loads and caches the DID configurations of trusted domains
+	domainsMu sync.RWMutex
+	domains   *fetch.Fetcher
+
+	// store holds sessions, negotiations, receipts, reports and the audit log, possibly shared
+	// with other server instances; see SetStore
+	store storage.KVStore
+
+	// negotiationsMu serializes this instance's updates to negotiations
+	negotiationsMu sync.Mutex
+
+	sessionsMu sync.Mutex
+	sessionTTL time.Duration
+	// sessionWatchers are local to this instance
+	sessionWatchers map[string][]chan struct{}
+}
+
+// NewUseCase creates a new verifier use case
+func NewUseCase(didService did.DIDService, vcService vc.CredentialService, presRepo vc.PresentationRepository) *UseCase {
+	return &UseCase{
+		didService: didService,
+		vcService:  vcService,
+		presRepo:   presRepo,
+		clock:      clock.System{},
+		store:      storage.NewMemoryStore(),
+		freshness:  FreshnessPolicy{ClockSkew: DefaultClockSkew},
+		domains:    fetch.NewFetcher(nil, fetch.DefaultTTL),
+		forwarder:  notify.NewWebhookNotifier(nil),
+
+		maxStatusAge: DefaultMaxStatusAge,
+
+		sessionTTL:      DefaultSessionTTL,
+		sessionWatchers: make(map[string][]chan struct{}),
+	}
+}
+
+// VerifierSetup represents the setup process for a verifier
+type VerifierSetup struct {
+	DID     *did.DID
+	DIDDoc  *did.DIDDocument
+	KeyPair *did.KeyPair
+}
+
+// SetupVerifier sets up a new verifier with DID
+func (uc *UseCase) SetupVerifier(method string) (*VerifierSetup, error) {
+	// Generate DID and key pair
+	verifierDID, keyPair, err := uc.didService.GenerateDID(method)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate DID: %w", err)
+	}
+
+	// Create DID document
+	didDoc, err := uc.didService.CreateDIDDocument(verifierDID, keyPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DID document: %w", err)
+	}
+
+	return &VerifierSetup{
+		DID:     verifierDID,
+		DIDDoc:  didDoc,
+		KeyPair: keyPair,
+	}, nil
+}
+
+// VerificationRequest represents a verification request
+type VerificationRequest struct {
+	Presentation *vc.VerifiablePresentation
+	// Template names a built-in request template whose claims, constraints and policy the
+	// request is expanded with
+	Template       string
+	RequiredClaims []string
+	// OptionalClaims are accepted when revealed but do not fail verification when missing.
+	// A claim listed in both RequiredClaims and OptionalClaims is required.
+	OptionalClaims []string
+	// AllowedClaims are claims the verifier accepts beyond the required and optional ones.
+	// Revealing any other claim is over-disclosure.
+	AllowedClaims []string
+	// MaxRevealedClaims caps the number of distinct claims revealed when non-zero
+	MaxRevealedClaims int
+	// OverDisclosure selects whether over-disclosure fails verification or is reported as a
+	// warning. It defaults to reject when AllowedClaims or MaxRevealedClaims is set, and to no
+	// check otherwise.
+	OverDisclosure OverDisclosurePolicy
+	TrustedIssuers []string
+	// TrustedDomains trusts issuers whose DID the domain's /.well-known/did-configuration.json
+	// links to, alongside those in TrustedIssuers, e.g. gov.vn
+	TrustedDomains []string
+	// TrustedRoots trusts issuers authorized, through a chain of AuthorizedIssuerCredentials
+	// presented alongside, by one of these root authorities
+	TrustedRoots []string
+	// ClaimConstraints restricts, per claim, the credential types and issuers the claim is accepted from.
+	// A claim revealed only by credentials that do not satisfy its constraint is treated as not revealed.
+	ClaimConstraints map[string]vc.ClaimConstraint
+	// AbsentClaims must each be proven absent from every presented credential, e.g. criminalRecord
+	AbsentClaims []string
+	// RequiredPredicates must each be implied by a predicate proven about a committed claim,
+	// e.g. dateOfBirth lt 20071016 without revealing the date of birth
+	RequiredPredicates []vc.PredicateStatement
+	// MinCoSigners rejects credentials not approved by at least this many co-signers when non-zero
+	MinCoSigners int
+	// HolderBinding requires the holder's proof of control over the presenting DID. A request with a
+	// VerificationNonce requires it too, as only the holder's signature binds the presentation to
+	// the request.
+	HolderBinding     bool
+	VerificationNonce string
+	// Domain, when set, must be the domain the holder's signed proof names
+	Domain string
+	// MaxPresentationAge overrides the verifier's freshness policy for this request when non-zero
+	MaxPresentationAge time.Duration
+	// MaxStatusAge overrides the verifier's maximum age for embedded status snapshots when non-zero
+	MaxStatusAge time.Duration
+	// VerifierDID, when set, makes the verifier issue a signed receipt on success and a signed
+	// report of the verification either way.
+	// It defaults to the DID in the request metadata's verifier identity.
+	VerifierDID string
+	// Strict reports lint findings about the presentation as warnings in the result
+	Strict bool
+	// RawPresentation is the presentation JSON as received, if available. Strict mode checks it
+	// is canonical and has no proof fields that decoding would drop.
+	RawPresentation []byte
+	// RequestMetadata carries the purpose, retention period and verifier identity stated in the request
+	vc.RequestMetadata
+
+	// sessionID is the session the presentation answers, if any
+	sessionID string
+}
+
+// VerificationResult represents the result of verification
+type VerificationResult struct {
+	Valid          bool                   `json:"valid"`
+	Errors         []string               `json:"errors,omitempty"`
+	RevealedClaims map[string]interface{} `json:"revealedClaims,omitempty"`
+	HolderDID      string                 `json:"holderDid"`
+	IssuerDIDs     []string               `json:"issuerDids"`
+	// IssuerSets lists, for each credential presented with a hidden issuer, the set it was issued within
+	IssuerSets      [][]string `json:"issuerSets,omitempty"`
+	CredentialTypes []string   `json:"credentialTypes"`
+	// Indeterminate is set when an issuer's DID could not be resolved for a transient reason,
+	// such as an unreachable peer, rather than because it is invalid, unknown or deactivated.
+	// The presentation is not valid, but verifying it again later may succeed.
+	Indeterminate bool `json:"indeterminate,omitempty"`
+	// ProvidedOptionalClaims and MissingOptionalClaims report which optional claims the holder revealed
+	ProvidedOptionalClaims []string `json:"providedOptionalClaims,omitempty"`
+	MissingOptionalClaims  []string `json:"missingOptionalClaims,omitempty"`
+	// Commitments are the verified attribute commitments presented with the credentials
+	Commitments []*vc.CommitmentBundle `json:"commitments,omitempty"`
+	// Evidence lists the verified evidence sections presented with the credentials
+	Evidence []*vc.EvidenceBundle `json:"evidence,omitempty"`
+	// CoSignatures lists the verified co-signatures presented with the credentials
+	CoSignatures []*vc.CoSignatureEnvelope `json:"coSignatures,omitempty"`
+	// DelegationChains lists, for each issuer trusted by delegation, the DIDs from the issuer up to
+	// the trusted root that authorized it
+	DelegationChains [][]string `json:"delegationChains,omitempty"`
+	// ProvenPredicates are the statements proven about hidden claims
+	ProvenPredicates []vc.PredicateStatement `json:"provenPredicates,omitempty"`
+	// AbsentClaims are the claims proven absent from every presented credential
+	AbsentClaims []string `json:"absentClaims,omitempty"`
+	// OverDisclosedClaims are the revealed claims the verifier neither required, accepted as
+	// optional nor allowed, when the request checks for over-disclosure
+	OverDisclosedClaims []string `json:"overDisclosedClaims,omitempty"`
+	// Warnings are lint findings reported in strict mode, over-disclosure the request only warns
+	// about and credentials amended since; they do not affect validity
+	Warnings []lint.Finding `json:"warnings,omitempty"`
+	vc.RequestMetadata
+	Receipt *vc.VerificationReceipt `json:"receipt,omitempty"`
+	// ReportID identifies the signed verification report, issued when the request names the verifier
+	ReportID string `json:"reportId,omitempty"`
+}
+
+// VerifyPresentation verifies a verifiable presentation
+func (uc *UseCase) VerifyPresentation(req VerificationRequest) (*VerificationResult, error) {
+	return uc.VerifyPresentationContext(context.Background(), req)
+}
+
+// VerifyPresentationContext verifies a presentation, recording each check as a span under ctx
+func (uc *UseCase) VerifyPresentationContext(ctx context.Context, req VerificationRequest) (*VerificationResult, error) {
+	ctx, span := tracing.Start(ctx, "verifier.VerifyPresentation")
+	defer span.End()
+
+	result, err := uc.verifyPresentation(ctx, req)
+	span.RecordError(err)
+	if result != nil {
+		span.SetAttributes(
+			tracing.Bool("verification.valid", result.Valid),
+			tracing.Int("verification.errors", len(result.Errors)),
+			tracing.Int("credentials.count", len(result.IssuerDIDs)+len(result.IssuerSets)),
+		)
+		if !result.Valid {
+			span.SetStatus(tracing.StatusError, "presentation rejected")
+		}
+	}
+	return result, err
+}
+
+func (uc *UseCase) verifyPresentation(ctx context.Context, req VerificationRequest) (*VerificationResult, error) {
+	if req.Template != "" {
+		template, err := GetRequestTemplate(req.Template)
+		if err != nil {
+			return nil, err
+		}
+		template.applyToVerification(&req)
+	}
+
+	result := &VerificationResult{
+		Valid:           true,
+		Errors:          []string{},
+		RevealedClaims:  make(map[string]interface{}),
+		HolderDID:       req.Presentation.Holder,
+		IssuerDIDs:      []string{},
+		CredentialTypes: []string{},
+		RequestMetadata: req.RequestMetadata,
+	}
+	defer uc.publishVerified(req, result)
+	defer uc.recordAudit(req.Presentation, result)
+	defer uc.recordPresentation(req, result)
+	defer uc.recordReport(req, result)
+
+	// Verify presentation structure
+	if err := uc.vcService.VerifyPresentation(req.Presentation); err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("presentation verification failed: %v", err))
+		return result, nil
+	}
+
+	if req.Strict || uc.strict {
+		result.Warnings = lintPresentation(req)
+	}
+
+	// Reject presentations whose terms would not survive JSON-LD expansion
+	if uc.contexts != nil {
+		if err := uc.contexts.ValidatePresentation(req.Presentation); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("presentation context validation failed: %v", err))
+			return result, nil
+		}
+	}
+
+	// Reject stale presentations even when the nonce matches
+	if err := uc.checkFreshness(req.Presentation, req.MaxPresentationAge); err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("presentation freshness check failed: %v", err))
+		return result, nil
+	}
+
+	// A guessable nonce would let a recorded presentation be replayed
+	if req.VerificationNonce != "" {
+		if err := bbs.ValidateNonce([]byte(req.VerificationNonce)); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("verification nonce rejected: %v", err))
+			return result, nil
+		}
+	}
+
+	// Verify the holder's proof of control over the presenting DID, which a request for holder
+	// binding or with a nonce cannot do without
+	if req.Presentation.Proof.ProofValue == "" {
+		if req.HolderBinding || req.VerificationNonce != "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, "presentation is not signed by its holder, which the request requires")
+			return result, nil
+		}
+	} else {
+		_, span := tracing.Start(ctx, "verifier.VerifyHolderProof")
+		err := uc.verifyHolderProof(req.Presentation)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("holder proof verification failed: %v", err))
+			return result, nil
+		}
+	}
+
+	// A domain only binds the presentation when the holder's signature covers it
+	if req.Domain != "" {
+		if req.Presentation.Proof.ProofValue == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("presentation is not signed by its holder, so it is not bound to domain %s", req.Domain))
+			return result, nil
+		}
+		if req.Presentation.Proof.Domain != req.Domain {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("presentation is bound to domain %q, not %s", req.Presentation.Proof.Domain, req.Domain))
+			return result, nil
+		}
+	}
+
+	// Reasons constrained claims were refused, reported if the claim is then missing
+	rejectedClaims := make(map[string]string)
+	// absentFrom counts the credentials each claim was proven absent from
+	absentFrom := make(map[string]int)
+
+	// The structure check accepted the presentation, so its data model is a supported one
+	presentationVersion, _ := vc.VersionOf(req.Presentation.Context)
+
+	// Verify each credential in the presentation
+	for i, credential := range req.Presentation.VerifiableCredential {
+		if credential == nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("credential %d: invalid format", i))
+			continue
+		}
+
+		// Check the holder proved possession of the key a bound credential names
+		if err := checkHolderBinding(req.Presentation, credential); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("credential %d: holder binding check failed: %v", i, err))
+			continue
+		}
+
+		// Extract issuer, or the issuer set when the holder hides which member issued the credential
+		issuer := credential.IssuerDID()
+		issuers := []string{issuer}
+		var setKeys [][]byte
+		if credential.IssuerSetProof != nil && issuer == "" {
+			_, span := tracing.Start(ctx, "verifier.CheckIssuerSet", tracing.Int("credential.index", i))
+			set, keys, err := uc.checkIssuerSet(credential)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: issuer set check failed: %v", i, err))
+				continue
+			}
+			issuers, setKeys = set, keys
+			result.IssuerSets = append(result.IssuerSets, set)
+		} else if issuer == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("credential %d: missing or invalid issuer", i))
+			continue
+		} else {
+			result.IssuerDIDs = append(result.IssuerDIDs, issuer)
+		}
+
+		// Issuer set members' keys were checked with the set
+		var issuerKey *did.VerificationMethod
+		if issuer != "" {
+			// Resolve the issuer key that was in force when the credential was issued
+			_, span := tracing.Start(ctx, "verifier.CheckIssuerKey", tracing.Int("credential.index", i))
+			var err error
+			issuerKey, err = uc.checkIssuerKey(issuer, credential)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Indeterminate = result.Indeterminate || did.IsTransient(err)
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: issuer key check failed: %v", i, err))
+				continue
+			}
+		}
+
+		// Verify the selective disclosure proof before trusting anything the credential presents
+		_, span := tracing.Start(ctx, "verifier.VerifySelectiveDisclosureProof", tracing.Int("credential.index", i))
+		err := uc.verifySelectiveDisclosureProof(credential, issuerKey, setKeys, req.VerificationNonce)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("credential %d: selective disclosure verification failed: %v", i, err))
+			continue
+		}
+
+		// The proof revealed the signed metadata, so the types and validity period are the ones
+		// the issuer signed. Check the credential's validity period under its own data model.
+		if err := uc.checkCredentialValidity(presentationVersion, credential); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("credential %d: %v", i, err))
+			continue
+		}
+		credentialTypes := credential.Type
+
+		// Check if issuer is trusted; a hidden issuer is trusted only if every member of its set is
+		if len(req.TrustedIssuers) > 0 || len(req.TrustedDomains) > 0 || len(req.TrustedRoots) > 0 {
+			var untrusted error
+			var chains [][]string
+			for _, candidate := range issuers {
+				var chain []string
+				if chain, untrusted = uc.checkIssuerTrust(ctx, req, candidate, credentialTypes); untrusted != nil {
+					break
+				}
+				if chain != nil {
+					chains = append(chains, chain)
+				}
+			}
+			if untrusted != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: %v", i, untrusted))
+				continue
+			}
+			result.DelegationChains = append(result.DelegationChains, chains...)
+		}
+
+		result.CredentialTypes = append(result.CredentialTypes, credentialTypes...)
+
+		// Extract revealed claims from credential subject
+		for key, value := range credential.CredentialSubject {
+			if key == "id" || key == vc.ConfirmationClaim { // Skip subject ID and holder binding
+				continue
+			}
+			if constraint, ok := req.ClaimConstraints[key]; ok {
+				if err := checkConstraint(constraint, issuers, credentialTypes); err != nil {
+					rejectedClaims[key] = fmt.Sprintf("credential %d: %v", i, err)
+					continue
+				}
+			}
+			result.RevealedClaims[key] = value
+		}
+
+		// Reject revoked or suspended credentials; a hidden issuer's credential has no status entries
+		if issuer != "" {
+			_, span := tracing.Start(ctx, "verifier.CheckStatus", tracing.Int("credential.index", i))
+			err := uc.checkCredentialStatus(issuer, credential, req.Presentation.StatusSnapshots, req.MaxStatusAge)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: %v", i, err))
+			}
+		}
+
+		// An amended credential is revoked, but may be presented from a status snapshot taken
+		// before; either way the warning tells the holder a newer version exists
+		if finding := uc.checkVersion(i, credential); finding != nil {
+			result.Warnings = append(result.Warnings, *finding)
+		}
+
+		// Reject credentials whose issuer keys or status lists differ from what was anchored
+		if uc.anchors != nil {
+			_, span := tracing.Start(ctx, "verifier.CheckAnchors", tracing.Int("credential.index", i))
+			var err error
+			for _, candidate := range issuers {
+				if err = uc.checkAnchors(candidate, credential); err != nil {
+					break
+				}
+			}
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: anchor check failed: %v", i, err))
+			}
+		}
+
+		// Check the issuer signed the presented attribute commitments for this credential
+		var bundle *vc.CommitmentBundle
+		if credential.Commitments != nil {
+			_, span := tracing.Start(ctx, "verifier.CheckCommitments", tracing.Int("credential.index", i))
+			var err error
+			bundle, err = uc.checkCommitments(issuer, credential)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: commitment check failed: %v", i, err))
+			} else {
+				result.Commitments = append(result.Commitments, bundle)
+			}
+		}
+
+		// Check the issuer signed the presented evidence section for this credential
+		if credential.Evidence != nil {
+			_, span := tracing.Start(ctx, "verifier.CheckEvidence", tracing.Int("credential.index", i))
+			evidence, err := uc.checkEvidence(issuer, credential)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: evidence check failed: %v", i, err))
+			} else {
+				result.Evidence = append(result.Evidence, evidence)
+			}
+		}
+
+		// Check the co-signatures approving the credential's issuance
+		if credential.CoSignatures != nil {
+			_, span := tracing.Start(ctx, "verifier.CheckCoSignatures", tracing.Int("credential.index", i))
+			envelope, err := uc.checkCoSignatures(issuer, credential)
+			if err == nil && len(envelope.Signatures) < req.MinCoSigners {
+				err = fmt.Errorf("%d co-signers approved it, %d required", len(envelope.Signatures), req.MinCoSigners)
+			}
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: co-signature check failed: %v", i, err))
+			} else {
+				result.CoSignatures = append(result.CoSignatures, envelope)
+			}
+		} else if req.MinCoSigners > 0 {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("credential %d: not co-signed, %d co-signers required", i, req.MinCoSigners))
+		}
+
+		// Verify the claims proven absent against the issuer-signed claim key commitment
+		if credential.AbsenceProofs != nil {
+			_, span := tracing.Start(ctx, "verifier.CheckAbsence", tracing.Int("credential.index", i))
+			absent, err := uc.checkAbsence(issuer, credential)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: absence check failed: %v", i, err))
+			}
+			for _, claim := range absent {
+				absentFrom[claim]++
+			}
+		}
+
+		// Verify predicates proven about the committed claims
+		if credential.Predicates != nil {
+			_, span := tracing.Start(ctx, "verifier.CheckPredicates", tracing.Int("credential.index", i))
+			proven, err := uc.checkPredicates(bundle, credential)
+			span.RecordError(err)
+			span.End()
+			if err != nil {
+				result.Valid = false
+				result.Errors = append(result.Errors, fmt.Sprintf("credential %d: predicate check failed: %v", i, err))
+			}
+			for _, statement := range proven {
+				// Predicates about constrained claims count only from credentials that meet the constraint
+				if constraint, ok := req.ClaimConstraints[statement.Claim]; ok && checkConstraint(constraint, issuers, credentialTypes) != nil {
+					continue
+				}
+				result.ProvenPredicates = append(result.ProvenPredicates, statement)
+			}
+		}
+
+	}
+
+	// Check if all required claims are present
+	for _, requiredClaim := range req.RequiredClaims {
+		if _, exists := result.RevealedClaims[requiredClaim]; !exists {
+			result.Valid = false
+			if reason, rejected := rejectedClaims[requiredClaim]; rejected {
+				result.Errors = append(result.Errors, fmt.Sprintf("required claim '%s' does not meet its constraint: %s", requiredClaim, reason))
+				continue
+			}
+			result.Errors = append(result.Errors, fmt.Sprintf("required claim '%s' is missing", requiredClaim))
+		}
+	}
+
+	// Check every required predicate is implied by a proven one
+	for _, required := range req.RequiredPredicates {
+		satisfied := false
+		for _, proven := range result.ProvenPredicates {
+			if impliesPredicate(proven, required) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("required predicate '%s' is not proven", required))
+		}
+	}
+
+	// Claims count as absent only when every credential proves it does not have them
+	for claim, count := range absentFrom {
+		if count == len(req.Presentation.VerifiableCredential) {
+			result.AbsentClaims = append(result.AbsentClaims, claim)
+		}
+	}
+	sort.Strings(result.AbsentClaims)
+	for _, absentClaim := range req.AbsentClaims {
+		if !containsClaim(result.AbsentClaims, absentClaim) {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("claim '%s' is not proven absent from every credential", absentClaim))
+		}
+	}
+
+	// Report optional claims without failing verification when they are missing
+	for _, optionalClaim := range req.OptionalClaims {
+		if containsClaim(req.RequiredClaims, optionalClaim) {
+			continue
+		}
+		if _, exists := result.RevealedClaims[optionalClaim]; exists {
+			result.ProvidedOptionalClaims = append(result.ProvidedOptionalClaims, optionalClaim)
+		} else {
+			result.MissingOptionalClaims = append(result.MissingOptionalClaims, optionalClaim)
+		}
+	}
+
+	// Hold the holder to what was asked for, for data minimization
+	checkOverDisclosure(req, result)
+
+	// Accept each presentation once, even when the verifier reuses nonces
+	if result.Valid && uc.replay != nil {
+		if err := uc.checkReplay(req.Presentation, req.MaxPresentationAge); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, fmt.Sprintf("presentation replay check failed: %v", err))
+		}
+	}
+
+	// Issue a signed receipt so the holder can later prove the check took place
+	if verifierDID := req.verifierDID(); result.Valid && verifierDID != "" {
+		receipt, err := uc.issueReceipt(verifierDID, req, result)
+		if err != nil {
+			// Log error but don't fail verification
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to issue receipt: %v", err))
+		} else {
+			result.Receipt = receipt
+		}
+	}
+
+	return result, nil
+}
+
+// DecryptPresentation opens a presentation that was encrypted to the verifier's DID
+func (uc *UseCase) DecryptPresentation(envelope *did.EncryptedEnvelope) (*vc.VerifiablePresentation, error) {
+	payload, err := uc.didService.DecryptWithDID(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt presentation: %w", err)
+	}
+
+	var presentation vc.VerifiablePresentation
+	if err := json.Unmarshal(payload, &presentation); err != nil {
+		return nil, fmt.Errorf("failed to parse presentation: %w", err)
+	}
+
+	return &presentation, nil
+}
+
+// verifyHolderProof checks the holder's Ed25519 signature over the presentation
+func (uc *UseCase) verifyHolderProof(presentation *vc.VerifiablePresentation) error {
+	signature, err := did.DecodeSignatureMultibase(presentation.Proof.ProofValue)
+	if err != nil {
+		return fmt.Errorf("invalid proof value: %w", err)
+	}
+
+	payload, err := vc.PresentationSigningInput(presentation)
+	if err != nil {
+		return err
+	}
+
+	return uc.didService.VerifyWithDID(presentation.Holder, presentation.Proof.VerificationMethod, payload, signature)
+}
+
+// verifySelectiveDisclosureProof verifies the selective disclosure proof, and its BBS+ proof
+// against the issuer key, or against the keys of the issuer set when the issuer is hidden
+func (uc *UseCase) verifySelectiveDisclosureProof(credential *vc.DerivedCredential, issuerKey *did.VerificationMethod, setKeys [][]byte, nonce string) error {
+	proof := credential.Proof
+	if proof == nil {
+		return fmt.Errorf("missing proof")
+	}
+
+	if proof.Type != vc.BBSProofSuite {
+		return fmt.Errorf("invalid proof type: expected BbsBlsSignatureProof2020, got %v", proof.Type)
+	}
+
+	// Verify nonce if provided
+	if nonce != "" && proof.Nonce != nonce {
+		return fmt.Errorf("nonce mismatch: expected %s, got %v", nonce, proof.Nonce)
+	}
+
+	// Rebuild the salted messages of the revealed claims, which the BBS+ proof is checked against
+	if credential.AttributeSalts != nil {
+		if _, err := revealedMessages(credential); err != nil {
+			return fmt.Errorf("attribute salts: %w", err)
+		}
+	}
+
+	// Check the revealed claims are the ones the issuer signed. A hidden issuer's proof is made
+	// under a randomization of one of its set's keys, with a proof of which set it came from.
+	verifier, ok := uc.vcService.(vc.DerivedProofVerifier)
+	if !ok {
+		return fmt.Errorf("credential service cannot verify BBS+ proofs")
+	}
+	if issuerKey == nil {
+		if setKeys == nil {
+			return fmt.Errorf("no issuer key to verify the proof under")
+		}
+		return verifier.VerifyDerivedSetProof(credential, setKeys)
+	}
+	if issuerKey.Type != "Bls12381G2Key2020" {
+		return fmt.Errorf("issuer key %s is not a BBS+ key", issuerKey.ID)
+	}
+	publicKey, err := did.BBSPublicKey(*issuerKey)
+	if err != nil {
+		return err
+	}
+	return verifier.VerifyDerivedProof(credential, publicKey)
+}
+
+// CreateVerificationRequest creates a verification request for specific claims
+type CreateVerificationRequestParams struct {
+	// Template names a built-in request template to expand into the request
+	Template          string
+	RequiredClaims    []string
+	OptionalClaims    []string
+	TrustedIssuers    []string
+	ClaimConstraints  map[string]vc.ClaimConstraint
+	VerificationNonce string
+	// OverDisclosure and MaxPresentationAge are the policy the presentation will be verified under
+	OverDisclosure     OverDisclosurePolicy
+	MaxPresentationAge time.Duration
+	vc.RequestMetadata
+}
+
+// CreateVerificationRequest creates a verification request
+func (uc *UseCase) CreateVerificationRequest(params CreateVerificationRequestParams) (*CreateVerificationRequestParams, error) {
+	if params.Template != "" {
+		template, err := GetRequestTemplate(params.Template)
+		if err != nil {
+			return nil, err
+		}
+		template.applyTo(&params)
+	}
+	if err := params.OverDisclosure.Validate(); err != nil {
+		return nil, err
+	}
+	if params.MaxPresentationAge < 0 {
+		return nil, fmt.Errorf("maximum presentation age cannot be negative")
+	}
+
+	if err := params.RequestMetadata.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request metadata: %w", err)
+	}
+
+	for _, claim := range params.OptionalClaims {
+		if containsClaim(params.RequiredClaims, claim) {
+			return nil, fmt.Errorf("claim %s cannot be both required and optional", claim)
+		}
+	}
+
+	for claim := range params.ClaimConstraints {
+		if !containsClaim(params.RequiredClaims, claim) && !containsClaim(params.OptionalClaims, claim) {
+			return nil, fmt.Errorf("constraint given for claim %s, which is not requested", claim)
+		}
+	}
+
+	// Generate a nonce if not provided
+	if params.VerificationNonce != "" {
+		if err := bbs.ValidateNonce([]byte(params.VerificationNonce)); err != nil {
+			return nil, fmt.Errorf("verification nonce: %w", err)
+		}
+	} else {
+		nonce, err := uc.GenerateNonce()
+		if err != nil {
+			return nil, err
+		}
+		params.VerificationNonce = nonce
+	}
+
+	return &params, nil
+}
+
+// verifierDID returns the verifier the presentation is submitted to, if the request names one
+func (req VerificationRequest) verifierDID() string {
+	if req.VerifierDID == "" && req.Verifier != nil {
+		return req.Verifier.DID
+	}
+	return req.VerifierDID
+}
+
+// recordPresentation stores the presentation with the outcome of verifying it
+func (uc *UseCase) recordPresentation(req VerificationRequest, result *VerificationResult) {
+	record := &vc.PresentationRecord{
+		Presentation: req.Presentation,
+		VerifierDID:  req.verifierDID(),
+		VerifiedAt:   uc.clock.Now(),
+		Valid:        result.Valid,
+		Errors:       append([]string(nil), result.Errors...),
+	}
+	if err := uc.presRepo.Store(record); err != nil {
+		// Log error but don't fail verification
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to store presentation: %v", err))
+	}
+}
+
+// ListVerifiedPresentations lists the presentations submitted to a verifier that match the
+// filter, oldest first
+func (uc *UseCase) ListVerifiedPresentations(filter vc.PresentationFilter) ([]*vc.PresentationRecord, error) {
+	if filter.VerifierDID == "" {
+		return nil, fmt.Errorf("verifier DID is required")
+	}
+	if filter.VerifiedAfter != nil && filter.VerifiedBefore != nil && filter.VerifiedBefore.Before(*filter.VerifiedAfter) {
+		return nil, fmt.Errorf("verifiedBefore is before verifiedAfter")
+	}
+
+	records, err := uc.presRepo.List(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presentations: %w", err)
+	}
+
+	return records, nil
+}
+
+// checkConstraint checks a claim constraint against every issuer the credential may come from
+func checkConstraint(constraint vc.ClaimConstraint, issuers []string, credentialTypes []string) error {
+	for _, issuer := range issuers {
+		if err := constraint.Check(issuer, credentialTypes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containsClaim reports whether claims contains claim
+func containsClaim(claims []string, claim string) bool {
+	for _, c := range claims {
+		if c == claim {
+			return true
+		}
+	}
+	return false
+}
+
+// revealedMessages rebuilds the salted messages of a derived credential's revealed claims.
+// Every revealed claim needs a salt, and salts of claims that are not revealed are refused.
+func revealedMessages(credential *vc.DerivedCredential) ([][]byte, error) {
+	salts := credential.AttributeSalts
+	if err := vc.CheckDisclosedSalts(salts); err != nil {
+		return nil, err
+	}
+
+	subject := credential.CredentialSubject
+	if subject == nil {
+		return nil, fmt.Errorf("missing credential subject")
+	}
+	keys := make([]string, 0, len(subject))
+	for key := range subject {
+		if key != "id" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for key := range salts {
+		if _, ok := subject[key]; !ok || key == "id" {
+			return nil, fmt.Errorf("salt given for claim %s, which is not revealed", key)
+		}
+	}
+
+	return vc.SaltedClaimMessages(subject, salts, keys)
+}
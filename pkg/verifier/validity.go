@@ -0,0 +1,98 @@
+package verifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// validityPeriod is a presented credential's data model version and the period it is valid in
+type validityPeriod struct {
+	version vc.Version
+	// validFrom is issuanceDate under 1.1 and validFrom under 2.0, zero when a 2.0 credential has none
+	validFrom time.Time
+	// validUntil is expirationDate under 1.1 and validUntil under 2.0, nil when the credential does not expire
+	validUntil *time.Time
+}
+
+// readValidity reads the validity period of a presented credential with the property names of
+// its data model. Properties of the other data model are rejected rather than ignored.
+func readValidity(credential *vc.DerivedCredential) (validityPeriod, error) {
+	version, err := credential.Version()
+	if err != nil {
+		return validityPeriod{}, err
+	}
+	period := validityPeriod{version: version}
+
+	from, until := credential.IssuanceDate, credential.ExpirationDate
+	otherFrom, otherUntil := credential.ValidFrom, credential.ValidUntil
+	fromKey, otherFromKey, otherUntilKey := "issuanceDate", "validFrom", "validUntil"
+	if version == vc.Version2 {
+		from, until, otherFrom, otherUntil = otherFrom, otherUntil, from, until
+		fromKey, otherFromKey, otherUntilKey = "validFrom", "issuanceDate", "expirationDate"
+	}
+
+	if otherFrom != nil {
+		return validityPeriod{}, fmt.Errorf("%s is not a data model %s property", otherFromKey, version)
+	}
+	if otherUntil != nil {
+		return validityPeriod{}, fmt.Errorf("%s is not a data model %s property", otherUntilKey, version)
+	}
+
+	if from != nil {
+		period.validFrom = *from
+	} else if version == vc.Version1 {
+		return validityPeriod{}, fmt.Errorf("invalid %s: missing", fromKey)
+	}
+	period.validUntil = until
+
+	return period, nil
+}
+
+// checkCredentialValidity checks a presented credential's data model and validity period. A 1.1
+// presentation cannot carry 2.0 credentials, whose terms its context does not define.
+func (uc *UseCase) checkCredentialValidity(presentationVersion vc.Version, credential *vc.DerivedCredential) error {
+	period, err := readValidity(credential)
+	if err != nil {
+		return err
+	}
+
+	if presentationVersion == vc.Version1 && period.version == vc.Version2 {
+		return fmt.Errorf("a data model 2.0 credential cannot be presented in a 1.1 presentation")
+	}
+
+	return uc.checkValidityPeriod(period)
+}
+
+// checkValidityPeriod rejects credentials that are not yet valid or have expired, allowing the
+// freshness policy's clock skew
+func (uc *UseCase) checkValidityPeriod(period validityPeriod) error {
+	now := uc.clock.Now()
+	skew := uc.freshness.ClockSkew
+
+	if !period.validFrom.IsZero() && period.validFrom.After(now.Add(skew)) {
+		return fmt.Errorf("credential is not valid until %s", period.validFrom.Format(time.RFC3339))
+	}
+
+	if period.validUntil != nil && now.After(period.validUntil.Add(skew)) {
+		return fmt.Errorf("credential expired at %s", period.validUntil.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// credentialIssuedAt returns when a presented credential was issued, which selects the issuer
+// key that must have been in force. A 2.0 credential without validFrom is checked against the
+// key in force now.
+func (uc *UseCase) credentialIssuedAt(credential *vc.DerivedCredential) (time.Time, error) {
+	period, err := readValidity(credential)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if period.validFrom.IsZero() {
+		return uc.clock.Now(), nil
+	}
+	return period.validFrom, nil
+}
@@ -0,0 +1,35 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// VersionSource returns the latest versions of the credentials its issuer amended
+type VersionSource interface {
+	LatestVersion(credentialID string) (*vc.VerifiableCredential, error)
+}
+
+// SetVersionSource sets where the verifier looks up whether presented credentials were amended
+func (uc *UseCase) SetVersionSource(source VersionSource) {
+	uc.versionSource = source
+}
+
+// checkVersion reports a presented credential its issuer has amended since. The source may not
+// know the credential, e.g. one from another issuer, so lookup failures are not reported.
+func (uc *UseCase) checkVersion(i int, credential *vc.DerivedCredential) *lint.Finding {
+	if uc.versionSource == nil {
+		return nil
+	}
+	latest, err := uc.versionSource.LatestVersion(credential.ID)
+	if err != nil || latest.ID == credential.ID {
+		return nil
+	}
+	return &lint.Finding{
+		Code:    lint.CodeOutdatedVersion,
+		Path:    fmt.Sprintf("verifiableCredential[%d]", i),
+		Message: fmt.Sprintf("credential %s was amended; the latest version is %d, %s", credential.ID, latest.CredentialVersion(), latest.ID),
+	}
+}
@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestAbsenceProofs tests proving a credential lacks a claim without revealing its other claim keys
+func TestAbsenceProofs(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	issue := func(t *testing.T, commitClaimKeys bool, claims ...vc.Claim) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:       issuerSetup.DID.String(),
+			SubjectDID:      holderDID,
+			Claims:          claims,
+			CommitClaimKeys: commitClaimKeys,
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	backgroundCheck := issue(t, true,
+		vc.Claim{Key: "fullName", Value: "An Nguyen"},
+		vc.Claim{Key: "checkedOn", Value: "2025-07-01"},
+		vc.Claim{Key: "pendingCase", Value: "none"},
+	)
+	require.NotNil(t, backgroundCheck.ClaimKeys)
+	assert.Len(t, backgroundCheck.ClaimKeySalts, 3)
+
+	present := func(t *testing.T, requests ...vc.SelectiveDisclosureRequest) (*vc.VerifiablePresentation, error) {
+		ids := make([]string, len(requests))
+		for i, request := range requests {
+			ids[i] = request.CredentialID
+		}
+		return holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:           holderDID,
+			CredentialIDs:       ids,
+			SelectiveDisclosure: requests,
+		})
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, absent ...string) *verifier.VerificationResult {
+		// Round-trip through JSON as a verifier receiving the presentation would
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		var decoded vc.VerifiablePresentation
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   &decoded,
+			RequiredClaims: []string{"fullName"},
+			AbsentClaims:   absent,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Absent Claim Proven Without Revealing Others", func(t *testing.T) {
+		presentation, err := present(t, vc.SelectiveDisclosureRequest{
+			CredentialID:       backgroundCheck.ID,
+			RevealedAttributes: []string{"fullName"},
+			ProveAbsent:        []string{"criminalRecord"},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(presentation.VerifiableCredential[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "claimKeySalts", "salts are never presented")
+		assert.NotContains(t, string(data), "pendingCase", "hidden claim keys stay hidden")
+		assert.NotContains(t, string(data), "checkedOn")
+
+		result := verify(t, presentation, "criminalRecord")
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, []string{"criminalRecord"}, result.AbsentClaims)
+	})
+
+	t.Run("Present Claim Cannot Be Proven Absent", func(t *testing.T) {
+		_, err := present(t, vc.SelectiveDisclosureRequest{
+			CredentialID:       backgroundCheck.ID,
+			RevealedAttributes: []string{"fullName"},
+			ProveAbsent:        []string{"pendingCase"},
+		})
+		assert.Error(t, err)
+
+		// A holder who forges a proof for a present claim and re-signs the presentation is caught
+		presentation, err := present(t, vc.SelectiveDisclosureRequest{
+			CredentialID:       backgroundCheck.ID,
+			RevealedAttributes: []string{"fullName"},
+			ProveAbsent:        []string{"criminalRecord"},
+		})
+		require.NoError(t, err)
+		presentation.VerifiableCredential[0].AbsenceProofs[0].Claim = "pendingCase"
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "absence check failed")
+	})
+
+	t.Run("Required Absence Must Be Proven", func(t *testing.T) {
+		presentation, err := present(t, vc.SelectiveDisclosureRequest{
+			CredentialID:       backgroundCheck.ID,
+			RevealedAttributes: []string{"fullName"},
+		})
+		require.NoError(t, err)
+
+		result := verify(t, presentation, "criminalRecord")
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors, "claim 'criminalRecord' is not proven absent from every credential")
+	})
+
+	t.Run("Every Credential Must Prove Absence", func(t *testing.T) {
+		other := issue(t, false, vc.Claim{Key: "membership", Value: "gold"})
+
+		_, err := present(t, vc.SelectiveDisclosureRequest{
+			CredentialID:       other.ID,
+			RevealedAttributes: []string{"membership"},
+			ProveAbsent:        []string{"criminalRecord"},
+		})
+		assert.Error(t, err, "credentials without a claim key commitment cannot prove absence")
+
+		presentation, err := present(t,
+			vc.SelectiveDisclosureRequest{CredentialID: backgroundCheck.ID, RevealedAttributes: []string{"fullName"}, ProveAbsent: []string{"criminalRecord"}},
+			vc.SelectiveDisclosureRequest{CredentialID: other.ID, RevealedAttributes: []string{"membership"}},
+		)
+		require.NoError(t, err)
+
+		result := verify(t, presentation, "criminalRecord")
+		assert.False(t, result.Valid)
+		assert.Empty(t, result.AbsentClaims)
+	})
+}
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/handlers"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestAgeVerificationFlow tests that an age check runs as a verifier session answered by the
+// holder's wallet, with each role using only its own endpoint
+func TestAgeVerificationFlow(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	// issueToWallet issues an age credential and delivers it to the holder's wallet
+	issueToWallet := func(t *testing.T, dateOfBirth string) string {
+		var issued struct {
+			Credential *vc.VerifiableCredential `json:"credential"`
+		}
+		post(t, "/api/age-verification/credential", http.StatusCreated, handlers.AgeCredentialRequest{
+			IssuerDID:   issuerDID,
+			SubjectDID:  holderDID,
+			FirstName:   "Jane",
+			LastName:    "Doe",
+			DateOfBirth: dateOfBirth,
+			Nationality: "Vietnamese",
+		}, &issued)
+		post(t, "/api/holder/credentials", http.StatusOK, map[string]interface{}{"credential": issued.Credential}, nil)
+		return issued.Credential.ID
+	}
+
+	openCheck := func(t *testing.T, minAge int) handlers.AgeVerificationRequestResponse {
+		var opened handlers.AgeVerificationRequestResponse
+		post(t, "/api/age-verification/requests", http.StatusCreated, handlers.AgeVerificationRequest{
+			MinAge:         minAge,
+			ServiceType:    "gaming",
+			TrustedIssuers: []string{issuerDID},
+		}, &opened)
+		return opened
+	}
+
+	answer := func(t *testing.T, credentialID string, opened handlers.AgeVerificationRequestResponse) handlers.AgePresentationResponse {
+		var presented handlers.AgePresentationResponse
+		post(t, "/api/age-verification/presentations", http.StatusCreated, handlers.AgePresentationRequest{
+			HolderDID:    holderDID,
+			CredentialID: credentialID,
+			Request:      opened.Request,
+		}, &presented)
+		return presented
+	}
+
+	adultCredential := issueToWallet(t, "1990-05-20")
+
+	t.Run("Access Granted", func(t *testing.T) {
+		opened := openCheck(t, 18)
+		assert.Equal(t, []string{"ageOver18"}, opened.Request.RequiredClaims)
+		assert.Equal(t, []string{"nationality", "documentType"}, opened.Request.OptionalClaims)
+		assert.NotEmpty(t, opened.Request.Nonce)
+
+		presented := answer(t, adultCredential, opened)
+		assert.Equal(t, []string{"ageOver18", "nationality", "documentType"}, presented.RevealedClaims)
+		assert.Contains(t, presented.HiddenAttributes, "dateOfBirth")
+
+		// Building the answer is the wallet's business; the verifier's session is untouched
+		session, err := verifierUC.GetSession(opened.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionPending, session.State)
+
+		var result handlers.AgeVerificationResponse
+		post(t, opened.VerifyURI, http.StatusOK, handlers.AgeVerificationSubmission{Presentation: presented.Presentation}, &result)
+		assert.True(t, result.Success)
+		assert.True(t, result.AccessGranted)
+		assert.Equal(t, 18, result.MinAgeRequired)
+		assert.Equal(t, "gaming", result.ServiceType)
+		assert.Equal(t, true, result.RevealedClaims["ageOver18"])
+		assert.NotContains(t, result.RevealedClaims, "dateOfBirth")
+
+		session, err = verifierUC.GetSession(opened.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionCompleted, session.State)
+
+		// A session accepts a single answer
+		post(t, opened.VerifyURI, http.StatusNotFound, handlers.AgeVerificationSubmission{Presentation: presented.Presentation}, nil)
+	})
+
+	t.Run("Access Denied Under Age", func(t *testing.T) {
+		minorCredential := issueToWallet(t, "2015-01-01")
+		opened := openCheck(t, 18)
+		presented := answer(t, minorCredential, opened)
+
+		var result handlers.AgeVerificationResponse
+		post(t, opened.VerifyURI, http.StatusOK, handlers.AgeVerificationSubmission{Presentation: presented.Presentation}, &result)
+		assert.True(t, result.Success)
+		assert.False(t, result.AgeVerified)
+		assert.False(t, result.AccessGranted)
+	})
+
+	t.Run("Answer To Another Request Is Rejected", func(t *testing.T) {
+		first := openCheck(t, 18)
+		second := openCheck(t, 18)
+		presented := answer(t, adultCredential, first)
+
+		var result handlers.AgeVerificationResponse
+		post(t, second.VerifyURI, http.StatusOK, handlers.AgeVerificationSubmission{Presentation: presented.Presentation}, &result)
+		assert.False(t, result.Success)
+		assert.False(t, result.AccessGranted)
+
+		session, err := verifierUC.GetSession(second.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionFailed, session.State)
+	})
+
+	t.Run("Invalid Requests", func(t *testing.T) {
+		post(t, "/api/age-verification/requests", http.StatusBadRequest, handlers.AgeVerificationRequest{MinAge: 19}, nil)
+		post(t, "/api/age-verification/requests/unknown/verify", http.StatusNotFound, handlers.AgeVerificationSubmission{}, nil)
+
+		opened := openCheck(t, 18)
+		withoutNonce := opened.Request
+		withoutNonce.Nonce = ""
+		post(t, "/api/age-verification/presentations", http.StatusBadRequest, handlers.AgePresentationRequest{
+			HolderDID:    holderDID,
+			CredentialID: adultCredential,
+			Request:      withoutNonce,
+		}, nil)
+		post(t, "/api/age-verification/presentations", http.StatusNotFound, handlers.AgePresentationRequest{
+			HolderDID:    holderDID,
+			CredentialID: "urn:uuid:missing",
+			Request:      opened.Request,
+		}, nil)
+	})
+}
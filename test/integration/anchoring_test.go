@@ -0,0 +1,127 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/anchor"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestAnchoring tests that verifiers reject issuer keys and status lists that differ from their anchors
+func TestAnchoring(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	statusRegistry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(statusRegistry)
+	verifierUC.SetStatusRegistry(statusRegistry)
+
+	// This issuer is set up before anchoring is enabled, so its keys are not anchored yet
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	backend := anchor.NewMockBackend()
+	anchorService := anchor.NewService(backend)
+	issuerUC.SetAnchorService(anchorService)
+	verifierUC.SetAnchorService(anchorService)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerDID,
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "memberLevel", Value: "gold"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	verify := func(t *testing.T) *verifier.VerificationResult {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"memberLevel"}},
+			},
+			Nonce: "anchor-nonce-fe705b61",
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			VerificationNonce: "anchor-nonce-fe705b61",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Unanchored Key Set", func(t *testing.T) {
+		result := verify(t)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "anchor check failed: issuer key set")
+	})
+
+	t.Run("Anchor Issuer", func(t *testing.T) {
+		anchors, err := issuerUC.AnchorIssuer(issuerDID)
+		require.NoError(t, err)
+		// Key set plus the revocation and suspension lists
+		require.Len(t, anchors, 3)
+		assert.Equal(t, anchor.KindKeySet, anchors[0].Kind)
+		assert.Equal(t, "mock", anchors[0].Receipt.Backend)
+
+		assert.True(t, verify(t).Valid)
+
+		// Re-anchoring unchanged state publishes nothing new
+		_, err = issuerUC.AnchorIssuer(issuerDID)
+		require.NoError(t, err)
+		history, err := issuerUC.GetAnchors(issuerDID)
+		require.NoError(t, err)
+		assert.Len(t, history, 3)
+	})
+
+	t.Run("Status Changes Are Re-anchored", func(t *testing.T) {
+		_, err := issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+
+		result := verify(t)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors, "credential 0: credential is suspended")
+		assert.Len(t, result.Errors, 1)
+
+		_, err = issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		assert.True(t, verify(t).Valid)
+	})
+
+	t.Run("Tampered Status List", func(t *testing.T) {
+		// Flipping a bit behind the issuer's back no longer matches the anchored list
+		entry := credential.CredentialStatus[1]
+		require.NoError(t, statusRegistry.SetStatus(&entry, true))
+		defer func() { require.NoError(t, statusRegistry.SetStatus(&entry, false)) }()
+
+		result := verify(t)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors, "credential 0: anchor check failed: suspension status list: status-list of "+
+			entry.StatusListCredential+" does not match its latest anchor")
+	})
+
+	t.Run("New Issuers Are Anchored At Setup", func(t *testing.T) {
+		setup, err := issuerUC.SetupIssuer("example")
+		require.NoError(t, err)
+
+		latest, err := anchorService.Latest(setup.DID.String(), anchor.KindKeySet)
+		require.NoError(t, err)
+		assert.Equal(t, setup.DID.String(), latest.Subject)
+	})
+}
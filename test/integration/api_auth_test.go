@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+// TestAPITokens tests that a stack configured with API tokens only serves API requests carrying one,
+// while the documents other parties fetch without a token stay open
+func TestAPITokens(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{APITokens: []string{"first-token", "second-token"}})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path, token string, body interface{}) *http.Response {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost, ts.URL+path, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Requests Without A Valid Token Are Rejected", func(t *testing.T) {
+		for _, token := range []string{"", "wrong-token"} {
+			resp := post(t, "/api/issuer/setup", token, dto.SetupIssuerRequest{Method: "example"})
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+
+			var errResp dto.ErrorResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+			assert.Equal(t, http.StatusUnauthorized, errResp.Code)
+		}
+	})
+
+	t.Run("Any Configured Token Is Accepted", func(t *testing.T) {
+		for _, token := range []string{"first-token", "second-token"} {
+			resp := post(t, "/api/issuer/setup", token, dto.SetupIssuerRequest{Method: "example"})
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("Public Routes Stay Open", func(t *testing.T) {
+		for _, path := range []string{"/health", "/live", "/.well-known/openid-credential-issuer"} {
+			resp, err := http.Get(ts.URL + path)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.NotEqual(t, http.StatusUnauthorized, resp.StatusCode, path)
+		}
+
+		resp := post(t, "/api/verifier/sessions/unknown/presentation", "", map[string]string{})
+		resp.Body.Close()
+		assert.NotEqual(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Empty Tokens Are Rejected", func(t *testing.T) {
+		stack, err := sdk.NewStack(sdk.Config{APITokens: []string{" "}})
+		require.NoError(t, err)
+		_, err = httpServer.NewStackServer(stack, "0")
+		assert.Error(t, err)
+	})
+}
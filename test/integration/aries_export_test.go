@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestAriesExport tests that holders can write presentations in the Aries document layout, with
+// a proof still valid for the revealed claim and a holder signature over the exported document
+func TestAriesExport(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	present := func(t *testing.T, format string) *http.Response {
+		body, err := json.Marshal(dto.CreatePresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce:  "aries-nonce-398f9314",
+			Format: vc.ExportFormat(format),
+		})
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/holder/presentations", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Aries Layout", func(t *testing.T) {
+		resp := present(t, "aries")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response dto.CreatePresentationResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		presentation := response.Presentation
+		assert.Equal(t, "Ed25519Signature2020", presentation.Proof.Type)
+		assert.NotEmpty(t, presentation.Proof.ProofValue)
+
+		derived := presentation.VerifiableCredential[0]
+		assert.Nil(t, derived.AttributeSalts)
+		assert.Contains(t, derived.Context, "https://w3id.org/security/bbs/v1")
+
+		proof := derived.Proof
+		assert.Equal(t, vc.BBSProofSuite, proof.Type)
+		assert.Empty(t, proof.RevealedAttributes)
+		nonce, err := base64.StdEncoding.DecodeString(proof.Nonce)
+		require.NoError(t, err)
+		proofNonce, err := vc.ProofNonce("aries-nonce-398f9314")
+		require.NoError(t, err)
+		assert.Equal(t, proofNonce, nonce)
+
+		// The exported proof still reveals the signed messages of the disclosed claim and the metadata
+		messages, err := vc.SignedMessages(credential)
+		require.NoError(t, err)
+		decoded, err := bbs.DecodeProof(proof.ProofValue)
+		require.NoError(t, err)
+		assert.NoError(t, stack.BBSService.VerifyProof(issuerSetup.BBSKeyPair.PublicKey, decoded, [][]byte{messages[2], messages[len(messages)-1]}, nonce))
+	})
+
+	t.Run("Unknown Format Is Rejected", func(t *testing.T) {
+		resp := present(t, "mattr")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestAttributeSalts tests that claims are signed with per-attribute salts and that only the salts
+// of revealed claims are disclosed
+func TestAttributeSalts(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "nationality", Value: "VN"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	require.Len(t, credential.AttributeSalts, 3)
+	for _, salt := range credential.AttributeSalts {
+		assert.Len(t, salt, vc.AttributeSaltSize)
+	}
+	assert.NotContains(t, credential.AttributeSalts, "id")
+
+	present := func(t *testing.T) (*vc.VerifiablePresentation, *vc.DerivedCredential) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"fullName"}},
+			},
+		})
+		require.NoError(t, err)
+		return presentation, presentation.VerifiableCredential[0]
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation) *verifier.VerificationResult {
+		// Round-trip through JSON as a verifier receiving the presentation would
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		var decoded vc.VerifiablePresentation
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   &decoded,
+			RequiredClaims: []string{"fullName"},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// resign re-signs an altered presentation so the verifier's salt checks are reached
+	resign := func(t *testing.T, presentation *vc.VerifiablePresentation) {
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+	}
+
+	t.Run("Only Revealed Salts Are Disclosed", func(t *testing.T) {
+		presentation, derived := present(t)
+		assert.Len(t, derived.AttributeSalts, 1)
+		assert.Contains(t, derived.AttributeSalts, "fullName")
+
+		result := verify(t, presentation)
+		assert.True(t, result.Valid, result.Errors)
+		assert.NotContains(t, result.RevealedClaims, "attributeSalts")
+	})
+
+	t.Run("Salts Of Hidden Claims Are Refused", func(t *testing.T) {
+		presentation, derived := present(t)
+		derived.AttributeSalts["nationality"] = derived.AttributeSalts["fullName"]
+		resign(t, presentation)
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "attribute salts")
+	})
+
+	t.Run("Revealed Claims Need A Salt", func(t *testing.T) {
+		presentation, derived := present(t)
+		derived.AttributeSalts = map[string][]byte{}
+		resign(t, presentation)
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "has no attribute salt")
+	})
+}
@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestBatchIssuance tests that batch jobs issue every valid item and report per-item failures
+func TestBatchIssuance(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC := stack.Issuer.UseCase
+	require.NoError(t, issuerUC.SetBatchWorkers(3))
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	waitForJob := func(t *testing.T, id string) *issuer.BatchJob {
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			job, err := issuerUC.GetBatchJob(id)
+			require.NoError(t, err)
+			if job.Done() {
+				return job
+			}
+			require.True(t, time.Now().Before(deadline), "batch job did not finish")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	t.Run("Diplomas", func(t *testing.T) {
+		const students = 12
+		var items []issuer.BatchItem
+		for i := 0; i < students; i++ {
+			items = append(items, issuer.BatchItem{
+				SubjectDID: fmt.Sprintf("did:example:student%d", i),
+				Claims: []vc.Claim{
+					{Key: "degree", Value: "Bachelor of Science"},
+					{Key: "university", Value: "Example University"},
+					{Key: "graduationYear", Value: 2025},
+				},
+			})
+		}
+		// Missing the required degree claim
+		items[5].Claims = items[5].Claims[1:]
+
+		job, err := issuerUC.StartBatchIssuance(issuer.BatchIssueRequest{
+			IssuerDID:  issuerDID,
+			TemplateID: "university-degree",
+			Items:      items,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, students, job.Total)
+		require.Len(t, job.Items, students)
+
+		job = waitForJob(t, job.ID)
+		assert.Equal(t, issuer.BatchCompleted, job.Status)
+		assert.Equal(t, students, job.Processed)
+		assert.Equal(t, students-1, job.Succeeded)
+		assert.Equal(t, 1, job.Failed)
+		require.NotNil(t, job.StartedAt)
+		require.NotNil(t, job.FinishedAt)
+
+		seen := make(map[string]bool)
+		for i, item := range job.Items {
+			assert.Equal(t, i, item.Index)
+			if i == 5 {
+				assert.Equal(t, issuer.BatchFailed, item.Status)
+				assert.Contains(t, item.Error, "required claim degree is missing")
+				assert.Nil(t, item.Credential)
+				continue
+			}
+
+			assert.Equal(t, issuer.BatchCompleted, item.Status)
+			require.NotNil(t, item.Credential)
+			assert.Equal(t, item.CredentialID, item.Credential.ID)
+			assert.Equal(t, item.SubjectDID, item.Credential.CredentialSubject["id"])
+			assert.Contains(t, item.Credential.Type, "UniversityDegreeCredential")
+			assert.False(t, seen[item.CredentialID], "credential IDs must be unique")
+			seen[item.CredentialID] = true
+
+			// Every issued credential is logged and carries its own status entries
+			hash, err := vc.CredentialHash(item.Credential)
+			require.NoError(t, err)
+			_, err = issuerUC.GetInclusionProof(issuerDID, hash, 0)
+			assert.NoError(t, err)
+			credentialStatus, err := issuerUC.GetCredentialStatus(item.CredentialID)
+			require.NoError(t, err)
+			assert.False(t, credentialStatus.Revoked)
+		}
+
+		sth, err := issuerUC.GetSignedTreeHead(issuerDID)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(students-1), sth.TreeSize)
+	})
+
+	t.Run("Rejected Batches", func(t *testing.T) {
+		item := issuer.BatchItem{SubjectDID: "did:example:student", Claims: []vc.Claim{{Key: "name", Value: "Alice"}}}
+
+		_, err := issuerUC.StartBatchIssuance(issuer.BatchIssueRequest{IssuerDID: issuerDID})
+		assert.Error(t, err)
+
+		_, err = issuerUC.StartBatchIssuance(issuer.BatchIssueRequest{
+			IssuerDID: issuerDID,
+			Items:     make([]issuer.BatchItem, issuer.MaxBatchSize+1),
+		})
+		assert.ErrorContains(t, err, "maximum")
+
+		_, err = issuerUC.StartBatchIssuance(issuer.BatchIssueRequest{
+			IssuerDID: "did:example:unknown",
+			Items:     []issuer.BatchItem{item},
+		})
+		assert.ErrorContains(t, err, "issuer not found")
+
+		_, err = issuerUC.StartBatchIssuance(issuer.BatchIssueRequest{
+			IssuerDID:  issuerDID,
+			TemplateID: "no-such-template",
+			Items:      []issuer.BatchItem{item},
+		})
+		assert.ErrorContains(t, err, "failed to get template")
+
+		_, err = issuerUC.GetBatchJob("no-such-job")
+		assert.Error(t, err)
+
+		assert.Error(t, issuerUC.SetBatchWorkers(0))
+	})
+}
@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestClaimConstraints tests that a claim is only accepted from the credential types and issuers the verifier names
+func TestClaimConstraints(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	government, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	selfIssuer, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	issue := func(issuerDID, templateID string, claims []vc.Claim) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderDID,
+			TemplateID: templateID,
+			Claims:     claims,
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	nationalID := issue(government.DID.String(), "national-id", []vc.Claim{
+		{Key: "firstName", Value: "An"},
+		{Key: "lastName", Value: "Nguyen Van"},
+		{Key: "dateOfBirth", Value: "2000-01-20"},
+		{Key: "nationality", Value: "Vietnamese"},
+		{Key: "idNumber", Value: "123456789"},
+	})
+
+	// A typed credential from an issuer the verifier does not accept for nationality
+	selfIssued := issue(selfIssuer.DID.String(), "national-id", []vc.Claim{
+		{Key: "firstName", Value: "An"},
+		{Key: "lastName", Value: "Nguyen Van"},
+		{Key: "dateOfBirth", Value: "2000-01-20"},
+		{Key: "nationality", Value: "Atlantean"},
+		{Key: "idNumber", Value: "000000000"},
+	})
+
+	// An untyped credential from the government
+	untyped := issue(government.DID.String(), "", []vc.Claim{
+		{Key: "nationality", Value: "Vietnamese"},
+		{Key: "city", Value: "Hue"},
+	})
+
+	present := func(t *testing.T, credentials ...*vc.VerifiableCredential) *vc.VerifiablePresentation {
+		request := holder.PresentationRequest{HolderDID: holderDID, Nonce: "constraint-nonce"}
+		for _, credential := range credentials {
+			request.CredentialIDs = append(request.CredentialIDs, credential.ID)
+			request.SelectiveDisclosure = append(request.SelectiveDisclosure, vc.SelectiveDisclosureRequest{
+				CredentialID:       credential.ID,
+				RevealedAttributes: []string{"nationality"},
+			})
+		}
+		presentation, err := holderUC.CreatePresentation(request)
+		require.NoError(t, err)
+		return presentation
+	}
+
+	constraints := map[string]vc.ClaimConstraint{
+		"nationality": {
+			CredentialTypes: []string{"NationalIDCredential"},
+			Issuers:         []string{government.DID.String()},
+		},
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"nationality"},
+			ClaimConstraints:  constraints,
+			VerificationNonce: "constraint-nonce",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Constraint Satisfied", func(t *testing.T) {
+		result := verify(t, present(t, nationalID))
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, "Vietnamese", result.RevealedClaims["nationality"])
+	})
+
+	t.Run("Issuer Not Accepted", func(t *testing.T) {
+		result := verify(t, present(t, selfIssued))
+		assert.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0], "required claim 'nationality' does not meet its constraint")
+		assert.Contains(t, result.Errors[0], "is not accepted")
+		assert.NotContains(t, result.RevealedClaims, "nationality")
+	})
+
+	t.Run("Credential Type Not Accepted", func(t *testing.T) {
+		result := verify(t, present(t, untyped))
+		assert.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0], "credential is not of type NationalIDCredential")
+	})
+
+	t.Run("Forged Credential Type Rejected", func(t *testing.T) {
+		// The holder presents the untyped credential as a national ID and signs the presentation again
+		presentation := present(t, untyped)
+		forged := presentation.VerifiableCredential[0]
+		forged.Type = append(forged.Type, "NationalIDCredential")
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		assert.Contains(t, strings.Join(result.Errors, "; "), "selective disclosure verification failed")
+		assert.NotContains(t, result.RevealedClaims, "nationality")
+	})
+
+	t.Run("Conforming Credential Wins", func(t *testing.T) {
+		result := verify(t, present(t, selfIssued, nationalID))
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, "Vietnamese", result.RevealedClaims["nationality"])
+	})
+
+	t.Run("Constraint On Unrequested Claim", func(t *testing.T) {
+		_, err := verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+			RequiredClaims:   []string{"ageOver18"},
+			ClaimConstraints: constraints,
+		})
+		assert.ErrorContains(t, err, "not requested")
+	})
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestClaimEncryption tests that the claims of stored credentials are encrypted at rest, used as
+// if they were not, and survive a rotation of the key-encryption key
+func TestClaimEncryption(t *testing.T) {
+	store := storage.NewMemoryStore()
+	keyring, err := vc.NewKeyring("2024", bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	stack, err := sdk.NewStack(sdk.Config{Store: store, ClaimKeys: keyring})
+	require.NoError(t, err)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "Alice Wonderland"},
+			{Key: "nationality", Value: "Atlantis"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	storedInTheClear := func(t *testing.T) bool {
+		keys, err := store.Keys("")
+		require.NoError(t, err)
+		for _, key := range keys {
+			data, err := store.Get(key)
+			require.NoError(t, err)
+			if bytes.Contains(data, []byte("Alice Wonderland")) {
+				return true
+			}
+		}
+		return false
+	}
+
+	present := func(t *testing.T) {
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"nationality"}},
+			},
+			Nonce: "claim-encryption-nonce",
+		})
+		require.NoError(t, err)
+		require.Len(t, presentation.VerifiableCredential, 1)
+		assert.Equal(t, "Atlantis", presentation.VerifiableCredential[0].CredentialSubject["nationality"])
+	}
+
+	t.Run("Encrypted At Rest", func(t *testing.T) {
+		assert.False(t, storedInTheClear(t))
+		present(t)
+	})
+
+	t.Run("Key Rotation", func(t *testing.T) {
+		require.NoError(t, keyring.Rotate("2025", bytes.Repeat([]byte{8}, 32)))
+		rewritten, err := stack.RewrapClaimKeys()
+		require.NoError(t, err)
+		assert.Equal(t, 1, rewritten, "the holder's copy")
+
+		require.NoError(t, keyring.Retire("2024"))
+		assert.False(t, storedInTheClear(t))
+		present(t)
+	})
+
+	t.Run("Requires A Store", func(t *testing.T) {
+		_, err := sdk.NewStack(sdk.Config{ClaimKeys: keyring})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestClaimGeneralization tests that holders can reveal issuer-signed coarser values of a claim
+// without revealing the claim
+func TestClaimGeneralization(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{ValidateContexts: true})
+	require.NoError(t, err)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "address", Value: "12 Nguyen Hue, District 1, Ho Chi Minh City, Vietnam"},
+		},
+		Generalizations: []vc.Generalization{
+			{Claim: "address", Levels: []vc.GeneralizationLevel{
+				{Name: "city", Value: "Ho Chi Minh City"},
+				{Name: "country", Value: "Vietnam"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+	assert.Equal(t, []string{"fullName", "address", "address.city", "address.country"}, credential.ClaimManifest.Keys())
+
+	t.Run("Reveal A Generalization", func(t *testing.T) {
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"address.city"}},
+			},
+			Nonce: "generalization-nonce",
+		})
+		require.NoError(t, err)
+
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"address.city"},
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: "generalization-nonce",
+			AllowedClaims:     []string{},
+			MaxRevealedClaims: 1,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, "Ho Chi Minh City", result.RevealedClaims["address.city"])
+		assert.NotContains(t, result.RevealedClaims, "address")
+
+		// The proof reveals the generalization's signed message and the metadata, and hides the address
+		messages, err := vc.SignedMessages(credential)
+		require.NoError(t, err)
+		index, ok := credential.ClaimManifest.Index("address.city")
+		require.True(t, ok)
+		metadata := len(messages) - 1
+		proof, err := bbs.DecodeProof(presentation.VerifiableCredential[0].Proof.ProofValue)
+		require.NoError(t, err)
+		assert.Equal(t, []int{index, metadata}, proof.RevealedAttributes)
+		proofNonce, err := vc.ProofNonce("generalization-nonce")
+		require.NoError(t, err)
+		assert.NoError(t, stack.BBSService.VerifyProof(issuerSetup.BBSKeyPair.PublicKey, proof, [][]byte{messages[index], messages[metadata]}, proofNonce))
+	})
+
+	t.Run("Generalizations Must Coarsen A Claim", func(t *testing.T) {
+		_, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "fullName", Value: "An Nguyen"}},
+			Generalizations: []vc.Generalization{
+				{Claim: "address", Levels: []vc.GeneralizationLevel{{Name: "country", Value: "Vietnam"}}},
+			},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestFrozenClock tests that issuance dates, presentation timestamps and freshness checks follow
+// an injected clock rather than the system time
+func TestFrozenClock(t *testing.T) {
+	frozen := time.Date(2031, time.March, 14, 9, 0, 0, 0, time.UTC)
+	mock := clock.NewMock(frozen)
+
+	stack, err := sdk.NewStack(sdk.Config{Clock: mock})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	verifierUC.SetReplayCache(replay.NewMemoryCache(100))
+	require.NoError(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{
+		MaxPresentationAge: 5 * time.Minute,
+		ClockSkew:          time.Minute,
+	}))
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	assert.Equal(t, frozen, credential.IssuanceDate)
+	assert.Equal(t, frozen, credential.Proof.Created)
+
+	present := func(t *testing.T, nonce string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce:    nonce,
+			ValidFor: 10 * time.Minute,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, nonce string) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: nonce,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Presentations Are Dated By The Clock", func(t *testing.T) {
+		nonce, err := verifierUC.GenerateNonce()
+		require.NoError(t, err)
+
+		presentation := present(t, nonce)
+		assert.Equal(t, frozen, presentation.Proof.Created)
+		require.NotNil(t, presentation.Proof.Expires)
+		assert.Equal(t, frozen.Add(10*time.Minute), *presentation.Proof.Expires)
+
+		// Years away from the system time, the presentation is fresh by the injected clock
+		result := verify(t, presentation, nonce)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		entries, err := verifierUC.ListAuditLog()
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		assert.Equal(t, frozen, entries[len(entries)-1].Timestamp)
+
+		// The replay window is measured by the injected clock too
+		replayed := verify(t, presentation, nonce)
+		assert.False(t, replayed.Valid)
+	})
+
+	t.Run("Advancing The Clock Makes Presentations Stale", func(t *testing.T) {
+		mock.Set(frozen)
+		nonce, err := verifierUC.GenerateNonce()
+		require.NoError(t, err)
+		presentation := present(t, nonce)
+
+		mock.Advance(7 * time.Minute)
+		result := verify(t, presentation, nonce)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "presentation is stale")
+	})
+
+	t.Run("Status Records Are Dated By The Clock", func(t *testing.T) {
+		mock.Set(frozen)
+		snapshots, err := issuerUC.IssueStatusSnapshots(credential.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, snapshots)
+		for _, snapshot := range snapshots {
+			assert.Equal(t, frozen, snapshot.Timestamp)
+			assert.Equal(t, frozen, snapshot.Proof.Created)
+		}
+
+		// Years ahead of the system time, yesterday by the injected clock is in the past
+		yesterday := frozen.Add(-24 * time.Hour)
+		_, err = issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{EffectiveDate: &yesterday})
+		require.NoError(t, err)
+
+		tomorrow := frozen.Add(24 * time.Hour)
+		_, err = issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{EffectiveDate: &tomorrow})
+		assert.ErrorContains(t, err, "effective date cannot be in the future")
+		_, err = issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+	})
+
+	t.Run("Key Rotations Are Dated By The Clock", func(t *testing.T) {
+		mock.Set(frozen.Add(time.Hour))
+		event, err := issuerUC.RotateKeys(issuerSetup.DID.String())
+		require.NoError(t, err)
+		assert.Equal(t, frozen.Add(time.Hour), event.RotatedAt)
+
+		// The retired key stays valid for credentials issued before the rotation by the injected clock
+		nonce, err := verifierUC.GenerateNonce()
+		require.NoError(t, err)
+		result := verify(t, present(t, nonce), nonce)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("Wallet Records Are Dated By The Clock", func(t *testing.T) {
+		mock.Set(frozen)
+		nonce, err := verifierUC.GenerateNonce()
+		require.NoError(t, err)
+		present(t, nonce)
+
+		records, err := holderUC.ListConsentRecords(holderSetup.DID.String())
+		require.NoError(t, err)
+		require.NotEmpty(t, records)
+		for _, record := range records {
+			assert.False(t, record.CreatedAt.Before(frozen), "consent recorded at %s", record.CreatedAt)
+		}
+
+		backup, err := holderUC.ExportBackup(holderSetup.DID.String(), "backup-password")
+		require.NoError(t, err)
+		assert.Equal(t, frozen, backup.CreatedAt)
+	})
+
+	t.Run("Wallet Expiry Follows The Clock", func(t *testing.T) {
+		mock.Set(frozen)
+		validUntil := frozen.Add(time.Hour)
+		expiring, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "memberOfClub", Value: true}},
+			ValidUntil: &validUntil,
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(expiring))
+
+		matchIDs := func() []string {
+			matches, err := holderUC.FindMatchingCredentials(holderSetup.DID.String(), holder.CredentialQuery{RequiredClaims: []string{"memberOfClub"}})
+			require.NoError(t, err)
+			var ids []string
+			for _, match := range matches {
+				ids = append(ids, match.Credential.ID)
+			}
+			return ids
+		}
+		assert.Contains(t, matchIDs(), expiring.ID)
+
+		nonce, err := verifierUC.GenerateNonce()
+		require.NoError(t, err)
+		token, err := holderUC.MintPresentationToken(context.Background(), holder.PresentationTokenRequest{
+			PresentationRequest: holder.PresentationRequest{
+				HolderDID:     holderSetup.DID.String(),
+				CredentialIDs: []string{credential.ID},
+				SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+					{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+				},
+				Nonce: nonce,
+			},
+			TTL: 10 * time.Minute,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, frozen.Add(10*time.Minute), token.ExpiresAt)
+
+		// Past the expiry by the injected clock, though years ahead of the system time
+		mock.Advance(2 * time.Hour)
+		assert.NotContains(t, matchIDs(), expiring.ID)
+		_, _, err = holderUC.RedeemPresentationToken(token.ID, token.Secret)
+		assert.ErrorIs(t, err, holder.ErrPresentationTokenNotFound)
+	})
+}
@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestAttributeCommitments tests exporting issuer-signed commitments to hidden claims for external zero-knowledge provers
+func TestAttributeCommitments(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderDID := holderSetup.DID.String()
+
+	claims := []vc.Claim{
+		{Key: "dateOfBirth", Value: "2000-01-20", Type: schema.ClaimTypeDate},
+		{Key: "nationality", Value: "Vietnamese"},
+		{Key: "ageOver18", Value: true},
+	}
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:        issuerDID,
+		SubjectDID:       holderDID,
+		Claims:           claims,
+		CommitAttributes: []string{"dateOfBirth", "nationality"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	t.Run("Issuer Signs Commitments", func(t *testing.T) {
+		bundle := credential.Commitments
+		require.NotNil(t, bundle)
+		assert.Equal(t, credential.ID, bundle.CredentialID)
+		assert.Equal(t, issuerDID, bundle.Issuer)
+		require.Len(t, bundle.Commitments, 2)
+		assert.Equal(t, vc.CommitmentEncodingDate, bundle.Commitments[0].Encoding)
+		assert.Equal(t, vc.CommitmentEncodingMessage, bundle.Commitments[1].Encoding)
+
+		assert.NoError(t, verifierUC.VerifyCommitmentBundle(bundle))
+	})
+
+	t.Run("Holder Exports Openings", func(t *testing.T) {
+		export, err := holderUC.ExportCommitments(holderDID, credential.ID, []string{"dateOfBirth"})
+		require.NoError(t, err)
+		require.Len(t, export.Openings, 1)
+
+		// The date is committed as the integer YYYYMMDD, ready for a range proof
+		opening := export.Openings[0]
+		assert.Equal(t, bbs.ScalarFromInt(big.NewInt(20000120)), opening.Value)
+
+		commitment, ok := export.Bundle.Find("dateOfBirth")
+		require.True(t, ok)
+		assert.NoError(t, bbs.VerifyCommitmentOpening(commitment.Commitment, opening.Value, opening.Blinding))
+
+		// Strings are committed as the scalar the BBS+ signature binds
+		export, err = holderUC.ExportCommitments(holderDID, credential.ID, []string{"nationality"})
+		require.NoError(t, err)
+		assert.Equal(t, bbs.MessageScalar([]byte("Vietnamese")), export.Openings[0].Value)
+
+		_, err = holderUC.ExportCommitments(holderDID, credential.ID, []string{"ageOver18"})
+		assert.ErrorContains(t, err, "not committed")
+
+		_, err = holderUC.ExportCommitments("did:example:someone-else", credential.ID, nil)
+		assert.ErrorContains(t, err, "does not belong")
+	})
+
+	t.Run("Commitments Presented With Hidden Claims", func(t *testing.T) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}, IncludeCommitments: true},
+			},
+			Nonce: "commitment-nonce-3b9e71d4",
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "commitmentOpenings")
+		assert.NotContains(t, string(data), "blinding")
+
+		var decoded vc.VerifiablePresentation
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      &decoded,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "commitment-nonce-3b9e71d4",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.NotContains(t, result.RevealedClaims, "dateOfBirth")
+
+		require.Len(t, result.Commitments, 1)
+		commitment, ok := result.Commitments[0].Find("dateOfBirth")
+		require.True(t, ok)
+		assert.Equal(t, credential.Commitments.Commitments[0].Commitment, commitment.Commitment)
+	})
+
+	t.Run("Tampered Commitment Rejected", func(t *testing.T) {
+		export, err := holderUC.ExportCommitments(holderDID, credential.ID, nil)
+		require.NoError(t, err)
+
+		// Swap in a commitment to another birth date
+		forged, _, err := bbs.Commit(bbs.ScalarFromInt(big.NewInt(19900101)))
+		require.NoError(t, err)
+
+		tampered := *export.Bundle
+		tampered.Commitments = append([]vc.AttributeCommitment{}, export.Bundle.Commitments...)
+		tampered.Commitments[0].Commitment = forged
+
+		err = verifierUC.VerifyCommitmentBundle(&tampered)
+		assert.ErrorContains(t, err, "signature verification failed")
+	})
+
+	t.Run("Unknown Claim", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:        issuerDID,
+			SubjectDID:       holderDID,
+			Claims:           claims,
+			CommitAttributes: []string{"idNumber"},
+		})
+		assert.ErrorContains(t, err, "not issued")
+	})
+}
@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestConcurrentVerification tests that presentations created and verified concurrently, as the
+// load test drives them, all verify
+func TestConcurrentVerification(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+
+	const holders = 4
+	type wallet struct{ holderDID, credentialID string }
+	wallets := make([]wallet, holders)
+	for i := range wallets {
+		holderSetup, err := stack.Holder.SetupHolder("example")
+		require.NoError(t, err)
+
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims: []vc.Claim{
+				{Key: "firstName", Value: fmt.Sprintf("Holder%d", i)},
+				{Key: "ageOver18", Value: true},
+				{Key: "nationality", Value: "Vietnamese"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(credential))
+		wallets[i] = wallet{holderDID: holderSetup.DID.String(), credentialID: credential.ID}
+	}
+
+	post := func(path string, body interface{}, response interface{}) error {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s returned %s", path, resp.Status)
+		}
+		return json.NewDecoder(resp.Body).Decode(response)
+	}
+	flow := func(w wallet) error {
+		nonce := uuid.New().String()
+
+		var presentation dto.CreatePresentationResponse
+		if err := post("/api/holder/presentations", dto.CreatePresentationRequest{
+			HolderDID:     w.holderDID,
+			CredentialIDs: []string{w.credentialID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: w.credentialID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: nonce,
+		}, &presentation); err != nil {
+			return err
+		}
+
+		var result dto.VerifyPresentationResponse
+		if err := post("/api/verifier/verify", dto.VerifyPresentationRequest{
+			Presentation:      presentation.Presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: nonce,
+		}, &result); err != nil {
+			return err
+		}
+		if !result.Valid {
+			return fmt.Errorf("presentation rejected: %s", strings.Join(result.Errors, "; "))
+		}
+		return nil
+	}
+
+	const workers, flowsPerWorker = 8, 3
+	errs := make(chan error, workers*flowsPerWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < flowsPerWorker; j++ {
+				if err := flow(wallets[(i+j)%holders]); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	failures := 0
+	for err := range errs {
+		failures++
+		t.Log(err)
+	}
+	assert.Zero(t, failures, "no verification fails at concurrency %d", workers)
+}
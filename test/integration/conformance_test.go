@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/conformance"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestDataModelConformance tests that the credentials and presentations the stack produces pass the
+// W3C VC Data Model assertions under both data model versions, as do its status list credentials
+// apart from their missing proof
+func TestDataModelConformance(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	expires := time.Now().AddDate(1, 0, 0)
+	issue := func(t *testing.T, req issuer.IssueCredentialRequest) *vc.VerifiableCredential {
+		req.IssuerDID = issuerSetup.DID.String()
+		req.SubjectDID = holderSetup.DID.String()
+		req.Claims = []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "firstName", Value: "An"},
+		}
+		credential, err := stack.Issuer.IssueCredential(req)
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(credential))
+		return credential
+	}
+
+	credentials := map[string]*vc.VerifiableCredential{
+		"1.1":               issue(t, issuer.IssueCredentialRequest{}),
+		"1.1 Expiring":      issue(t, issuer.IssueCredentialRequest{ValidUntil: &expires}),
+		"2.0":               issue(t, issuer.IssueCredentialRequest{Version: vc.Version2, ValidUntil: &expires}),
+		"2.0 Issuer Object": issue(t, issuer.IssueCredentialRequest{Version: vc.Version2, IssuerName: "Example University"}),
+	}
+
+	for name, credential := range credentials {
+		t.Run("Issued "+name, func(t *testing.T) {
+			require.NotEmpty(t, credential.CredentialStatus)
+
+			report, err := conformance.CheckCredential(credential)
+			require.NoError(t, err)
+			assert.True(t, report.OK(), report.String())
+		})
+
+		t.Run("Presented "+name, func(t *testing.T) {
+			presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+				HolderDID:     holderSetup.DID.String(),
+				CredentialIDs: []string{credential.ID},
+				SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+					{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+				},
+				Nonce: "conformance-6c1d6ced",
+			})
+			require.NoError(t, err)
+
+			report, err := conformance.CheckPresentation(presentation)
+			require.NoError(t, err)
+			assert.True(t, report.OK(), report.String())
+		})
+	}
+
+	t.Run("Presented Together", func(t *testing.T) {
+		request := holder.PresentationRequest{HolderDID: holderSetup.DID.String()}
+		for _, credential := range credentials {
+			request.CredentialIDs = append(request.CredentialIDs, credential.ID)
+			request.SelectiveDisclosure = append(request.SelectiveDisclosure, vc.SelectiveDisclosureRequest{
+				CredentialID:       credential.ID,
+				RevealedAttributes: []string{"firstName"},
+			})
+		}
+		presentation, err := stack.Holder.CreatePresentation(request)
+		require.NoError(t, err)
+
+		report, err := conformance.CheckPresentation(presentation)
+		require.NoError(t, err)
+		assert.Equal(t, vc.Version2, report.Version)
+		assert.True(t, report.OK(), report.String())
+	})
+
+	t.Run("Status List Credential", func(t *testing.T) {
+		listCredential, err := stack.StatusRegistry.GetListCredential(credentials["1.1"].CredentialStatus[0].StatusListCredential)
+		require.NoError(t, err)
+
+		data, err := json.Marshal(listCredential)
+		require.NoError(t, err)
+		report, err := conformance.CheckCredentialJSON(data)
+		require.NoError(t, err)
+
+		// Status lists are published unsigned; every other assertion holds
+		var failed []string
+		for _, result := range report.Failures() {
+			failed = append(failed, result.ID)
+		}
+		assert.Equal(t, []string{"proof"}, failed, report.String())
+	})
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestContentAddressedIDs tests duplicate detection and re-issuance of credentials whose IDs
+// are derived from their content
+func TestContentAddressedIDs(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	statusRegistry := stack.StatusRegistry
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	request := issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "licenseClass", Value: "B"},
+			{Key: "points", Value: 12},
+		},
+		ContentAddressedID: true,
+	}
+
+	original, err := issuerUC.IssueCredential(request)
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(original))
+
+	t.Run("ID Derived From Content", func(t *testing.T) {
+		assert.True(t, vc.IsContentID(original.ID))
+		id, err := vc.ContentID(original)
+		require.NoError(t, err)
+		assert.Equal(t, id, original.ID)
+
+		other := request
+		other.Claims = []vc.Claim{{Key: "licenseClass", Value: "C"}, {Key: "points", Value: 12}}
+		credential, err := issuerUC.IssueCredential(other)
+		require.NoError(t, err)
+		assert.NotEqual(t, original.ID, credential.ID)
+	})
+
+	t.Run("Duplicate Rejected", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(request)
+		assert.ErrorIs(t, err, issuer.ErrDuplicateCredential)
+
+		invalid := request
+		invalid.ContentAddressedID = false
+		invalid.Reissue = true
+		_, err = issuerUC.IssueCredential(invalid)
+		assert.Error(t, err, "random IDs cannot be reissued")
+	})
+
+	t.Run("Reissue Revokes The Earlier Copy", func(t *testing.T) {
+		reissue := request
+		reissue.Reissue = true
+		replacement, err := issuerUC.IssueCredential(reissue)
+		require.NoError(t, err)
+		assert.Equal(t, original.ID, replacement.ID)
+		require.NoError(t, holderUC.StoreCredential(replacement))
+
+		revoked, err := statusRegistry.GetStatus(&original.CredentialStatus[0])
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		current, err := issuerUC.GetCredentialStatus(replacement.ID)
+		require.NoError(t, err)
+		assert.False(t, current.Revoked)
+
+		// The holder's copy was replaced, so a presentation carries the replacement
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{replacement.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: replacement.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+			Nonce: "content-id-nonce-c41f08a2",
+		})
+		require.NoError(t, err)
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"licenseClass"},
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: "content-id-nonce-c41f08a2",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Holder Rejects A Mismatched ID", func(t *testing.T) {
+		data, err := json.Marshal(original)
+		require.NoError(t, err)
+		var tampered vc.VerifiableCredential
+		require.NoError(t, json.Unmarshal(data, &tampered))
+		tampered.ID = vc.ContentIDPrefix + "zQmTampered"
+
+		err = holderUC.StoreCredential(&tampered)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match its content")
+	})
+
+	t.Run("HTTP Conflict", func(t *testing.T) {
+		stack, err := sdk.NewStack(sdk.Config{})
+		require.NoError(t, err)
+		server, err := httpServer.NewStackServer(stack, "0")
+		require.NoError(t, err)
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		issuerSetup, err := stack.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+		holderSetup, err := stack.Holder.SetupHolder("example")
+		require.NoError(t, err)
+
+		body, err := json.Marshal(dto.IssueCredentialRequest{
+			IssuerDID:          issuerSetup.DID.String(),
+			SubjectDID:         holderSetup.DID.String(),
+			Claims:             []dto.ClaimDTO{{Key: "licenseClass", Value: "B"}},
+			ContentAddressedID: true,
+		})
+		require.NoError(t, err)
+
+		issue := func() int {
+			resp, err := http.Post(ts.URL+"/api/issuer/credentials", "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+		assert.Equal(t, http.StatusOK, issue())
+		assert.Equal(t, http.StatusConflict, issue())
+	})
+}
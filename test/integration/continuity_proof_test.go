@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestContinuityProof tests that a holder proves two versions of an amended credential were
+// issued to the same hidden subject, and that verifiers reject proofs across unrelated credentials
+func TestContinuityProof(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	issue := func(t *testing.T, tier string) *vc.VerifiableCredential {
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderDID,
+			Claims: []vc.Claim{
+				{Key: "tier", Value: tier},
+				{Key: "memberSince", Value: 2019},
+			},
+			CommitAttributes: []string{"id", "memberSince"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(credential))
+		return credential
+	}
+
+	original := issue(t, "silver")
+	amended, err := stack.Issuer.AmendCredential(context.Background(), issuer.AmendCredentialRequest{
+		CredentialID: original.ID,
+		Claims:       []vc.Claim{{Key: "tier", Value: "gold"}},
+	})
+	require.NoError(t, err)
+	_, err = stack.Holder.FetchLatestVersion(holderDID, original.ID)
+	require.NoError(t, err)
+
+	post := func(t *testing.T, path string, body interface{}, response interface{}) int {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && response != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		}
+		return resp.StatusCode
+	}
+	verify := func(t *testing.T, proof *vc.ContinuityProof, nonce string) dto.VerifyContinuityResponse {
+		var result dto.VerifyContinuityResponse
+		status := post(t, "/api/verifier/continuity-proofs/verify", dto.VerifyContinuityRequest{Proof: proof, Nonce: nonce}, &result)
+		require.Equal(t, http.StatusOK, status)
+		return result
+	}
+
+	t.Run("Commitments Carried Over", func(t *testing.T) {
+		require.NotNil(t, amended.Commitments)
+		_, ok := amended.Commitments.Find("id")
+		assert.True(t, ok)
+		_, ok = amended.Commitments.Find("memberSince")
+		assert.True(t, ok)
+	})
+
+	var proof *vc.ContinuityProof
+	t.Run("Continuity Across Versions", func(t *testing.T) {
+		var response dto.ProveContinuityResponse
+		status := post(t, "/api/holder/continuity-proofs", dto.ProveContinuityRequest{
+			HolderDID:            holderDID,
+			PreviousCredentialID: original.ID,
+			CurrentCredentialID:  amended.ID,
+			Nonce:                "continuity-nonce",
+		}, &response)
+		require.Equal(t, http.StatusOK, status)
+		proof = response.Proof
+		require.NotNil(t, proof)
+		assert.Equal(t, "id", proof.Claim)
+
+		// The proof carries commitments only, never the subject identifier
+		data, err := json.Marshal(proof)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), holderDID)
+
+		result := verify(t, proof, "continuity-nonce")
+		assert.True(t, result.Valid, result.Error)
+
+		result = verify(t, proof, "another-nonce")
+		assert.False(t, result.Valid, "the proof is bound to the nonce")
+	})
+
+	t.Run("Unrelated Credentials Rejected", func(t *testing.T) {
+		other := issue(t, "bronze")
+		otherProof, err := stack.Holder.ProveContinuity(holderDID, original.ID, other.ID, "", "continuity-nonce")
+		require.NoError(t, err, "the holder can prove the same subject across any two credentials")
+
+		result := verify(t, otherProof, "continuity-nonce")
+		assert.False(t, result.Valid, "the issuer knows the credentials are not versions of one credential")
+		assert.Contains(t, result.Error, "not versions of one credential")
+	})
+
+	t.Run("Changed Claim Rejected", func(t *testing.T) {
+		changed, err := stack.Issuer.AmendCredential(context.Background(), issuer.AmendCredentialRequest{
+			CredentialID: amended.ID,
+			Claims:       []vc.Claim{{Key: "memberSince", Value: 2021}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(changed))
+
+		_, err = stack.Holder.ProveContinuity(holderDID, amended.ID, changed.ID, "memberSince", "continuity-nonce")
+		assert.ErrorContains(t, err, "differs")
+
+		// Swapping in another credential's commitments breaks the proof
+		forged := *proof
+		forged.Current = changed.Commitments
+		result := verify(t, &forged, "continuity-nonce")
+		assert.False(t, result.Valid)
+	})
+}
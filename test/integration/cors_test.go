@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+// TestCORSPolicy tests that only configured origins get CORS access and that route overrides apply
+func TestCORSPolicy(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+
+	require.NoError(t, server.SetCORSPolicy(httpServer.DefaultCORSPolicy(httpServer.CORSConfig{
+		AllowedOrigins:   []string{"https://wallet.example.com", "https://*.verifier.example"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           5 * time.Minute,
+	})))
+
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	request := func(t *testing.T, method, path, origin string, headers map[string]string) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+path, nil)
+		require.NoError(t, err)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("Allowed Origin", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/health", "https://wallet.example.com", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "https://wallet.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, resp.Header.Values("Vary"), "Origin")
+	})
+
+	t.Run("Wildcard Subdomain", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/health", "https://shop.verifier.example", nil)
+		assert.Equal(t, "https://shop.verifier.example", resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp = request(t, http.MethodGet, "/health", "https://verifier.example", nil)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Disallowed Origin", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/health", "https://evil.example.com", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+		resp = request(t, http.MethodOptions, "/api/verifier/verify", "https://evil.example.com", map[string]string{
+			"Access-Control-Request-Method": http.MethodPost,
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		resp := request(t, http.MethodOptions, "/api/verifier/verify", "https://wallet.example.com", map[string]string{
+			"Access-Control-Request-Method":  http.MethodPost,
+			"Access-Control-Request-Headers": "content-type",
+		})
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "GET, POST", resp.Header.Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Content-Type", resp.Header.Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "300", resp.Header.Get("Access-Control-Max-Age"))
+
+		resp = request(t, http.MethodOptions, "/api/verifier/verify", "https://wallet.example.com", map[string]string{
+			"Access-Control-Request-Method": http.MethodDelete,
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = request(t, http.MethodOptions, "/api/verifier/verify", "https://wallet.example.com", map[string]string{
+			"Access-Control-Request-Method":  http.MethodPost,
+			"Access-Control-Request-Headers": "X-Secret",
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Public Route Override", func(t *testing.T) {
+		resp := request(t, http.MethodGet, "/api/schemas/national-id/display", "https://anyone.example.net", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+
+		resp = request(t, http.MethodOptions, "/.well-known/openid-credential-issuer", "https://anyone.example.net", map[string]string{
+			"Access-Control-Request-Method": http.MethodPost,
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Invalid Policies", func(t *testing.T) {
+		err := server.SetCORSPolicy(httpServer.CORSPolicy{
+			Default: httpServer.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+		})
+		assert.Error(t, err)
+
+		err = server.SetCORSPolicy(httpServer.CORSPolicy{
+			Default: httpServer.CORSConfig{AllowedOrigins: []string{"wallet.example.com"}},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,201 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestCoSignedIssuance tests that credentials under a co-signing policy are issued only once a
+// threshold of departments has signed, and that verifiers check the co-signatures
+func TestCoSignedIssuance(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	// Departments are DIDs whose keys this instance holds
+	var departments []*holder.HolderSetup
+	var signers []string
+	for i := 0; i < 3; i++ {
+		department, err := stack.Holder.SetupHolder("example")
+		require.NoError(t, err)
+		departments = append(departments, department)
+		signers = append(signers, department.DID.String())
+	}
+
+	post(t, "/api/issuer/cosigning/policies", http.StatusOK, dto.CoSigningPolicyRequest{
+		IssuerDID: issuerDID,
+		Threshold: 2,
+		Signers:   signers,
+	}, nil)
+
+	request := dto.IssueCredentialRequest{
+		IssuerDID:  issuerDID,
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []dto.ClaimDTO{{Key: "clearance", Value: "top-secret"}},
+	}
+
+	t.Run("Unilateral Issuance Is Refused", func(t *testing.T) {
+		post(t, "/api/issuer/credentials", http.StatusForbidden, request, nil)
+
+		_, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "clearance", Value: "top-secret"}},
+		})
+		assert.ErrorIs(t, err, issuer.ErrCoSigningRequired)
+	})
+
+	var session issuer.SigningSession
+	post(t, "/api/issuer/signing-sessions", http.StatusCreated, dto.StartSigningSessionRequest{Request: request}, &session)
+	assert.Equal(t, issuer.SigningCollecting, session.Status)
+	assert.Equal(t, 2, session.Envelope.Threshold, "the policy's threshold applies")
+	assert.Equal(t, signers, session.Envelope.Signers)
+	assert.NotContains(t, session.Envelope.RequestDigest, "top-secret")
+
+	sessionPath := "/api/issuer/signing-sessions/" + session.ID + "/signatures"
+
+	t.Run("First Signature Does Not Issue", func(t *testing.T) {
+		post(t, sessionPath, http.StatusOK, dto.CoSignRequest{VerificationMethod: departments[0].KeyPair.KeyID}, &session)
+		assert.Equal(t, issuer.SigningCollecting, session.Status)
+		assert.Len(t, session.Envelope.Signatures, 1)
+		assert.Nil(t, session.Credential)
+
+		post(t, sessionPath, http.StatusBadRequest, dto.CoSignRequest{VerificationMethod: departments[0].KeyPair.KeyID}, nil)
+		post(t, sessionPath, http.StatusBadRequest, dto.CoSignRequest{VerificationMethod: holderSetup.KeyPair.KeyID}, nil)
+	})
+
+	t.Run("Forged Signature Is Refused", func(t *testing.T) {
+		signature, err := stack.DIDService.SignWithDID(departments[1].KeyPair.KeyID, []byte("some other statement"))
+		require.NoError(t, err)
+		post(t, sessionPath, http.StatusBadRequest, dto.CoSignRequest{
+			VerificationMethod: departments[1].KeyPair.KeyID,
+			Signature:          did.EncodeSignatureMultibase(signature),
+		}, nil)
+	})
+
+	t.Run("Threshold Signature Issues", func(t *testing.T) {
+		// The second department signs the statement itself and submits the signature
+		statement, err := vc.CoSignatureSigningInput(session.Envelope)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(departments[1].KeyPair.KeyID, statement)
+		require.NoError(t, err)
+		post(t, sessionPath, http.StatusOK, dto.CoSignRequest{
+			VerificationMethod: departments[1].KeyPair.KeyID,
+			Signature:          did.EncodeSignatureMultibase(signature),
+		}, &session)
+
+		assert.Equal(t, issuer.SigningIssued, session.Status)
+		require.NotNil(t, session.Credential)
+		require.NotNil(t, session.Credential.CoSignatures)
+		assert.Equal(t, session.Credential.ID, session.Credential.CoSignatures.CredentialID)
+		assert.Len(t, session.Credential.CoSignatures.Signatures, 2)
+		require.NoError(t, stack.Verifier.VerifyCoSignatures(session.Credential.CoSignatures))
+
+		post(t, sessionPath, http.StatusBadRequest, dto.CoSignRequest{VerificationMethod: departments[2].KeyPair.KeyID}, nil)
+	})
+	require.NotNil(t, session.Credential)
+	credential := session.Credential
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	present := func(t *testing.T, credential *vc.VerifiableCredential) *vc.VerifiablePresentation {
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"clearance"}},
+			},
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	t.Run("Verifier Checks Co-Signatures", func(t *testing.T) {
+		presentation := present(t, credential)
+
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"clearance"},
+			TrustedIssuers: []string{issuerDID},
+			MinCoSigners:   2,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		require.Len(t, result.CoSignatures, 1)
+		assert.Equal(t, credential.ID, result.CoSignatures[0].CredentialID)
+
+		result, err = stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"clearance"},
+			TrustedIssuers: []string{issuerDID},
+			MinCoSigners:   3,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Envelope Is Bound To Its Credential", func(t *testing.T) {
+		dropped := *credential.CoSignatures
+		dropped.Signatures = dropped.Signatures[:1]
+		assert.Error(t, stack.Verifier.VerifyCoSignatures(&dropped))
+
+		moved := *credential.CoSignatures
+		moved.CredentialID = "urn:uuid:another-credential"
+		assert.Error(t, stack.Verifier.VerifyCoSignatures(&moved))
+	})
+
+	t.Run("Uncosigned Credential Fails Minimum", func(t *testing.T) {
+		other, err := stack.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+		plain, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  other.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "clearance", Value: "public"}},
+		})
+		require.NoError(t, err, "issuers without a policy issue alone")
+		require.NoError(t, stack.Holder.StoreCredential(plain))
+
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   present(t, plain),
+			RequiredClaims: []string{"clearance"},
+			MinCoSigners:   1,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/api"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestCostPolicy tests that verification requests are charged one unit per proof: requests over
+// the per-request limit get 413, requests over the per-minute budgets get 429 with Retry-After,
+// and budgets refill over time
+func TestCostPolicy(t *testing.T) {
+	now := time.Date(2030, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+	newServer := func(t *testing.T, policy api.CostPolicy) (*httptest.Server, *clock.Mock, func(credentials int) *vc.VerifiablePresentation) {
+		mock := clock.NewMock(now)
+		stack, err := sdk.NewStack(sdk.Config{Clock: mock, CostPolicy: policy})
+		require.NoError(t, err)
+
+		issuerSetup, err := stack.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+		holderSetup, err := stack.Holder.SetupHolder("example")
+		require.NoError(t, err)
+
+		// present presents the given number of credentials, each carrying its own proof
+		present := func(credentials int) *vc.VerifiablePresentation {
+			request := holder.PresentationRequest{HolderDID: holderSetup.DID.String()}
+			for i := 0; i < credentials; i++ {
+				credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+					IssuerDID:  issuerSetup.DID.String(),
+					SubjectDID: holderSetup.DID.String(),
+					Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+				})
+				require.NoError(t, err)
+				require.NoError(t, stack.Holder.StoreCredential(credential))
+
+				request.CredentialIDs = append(request.CredentialIDs, credential.ID)
+				request.SelectiveDisclosure = append(request.SelectiveDisclosure, vc.SelectiveDisclosureRequest{
+					CredentialID:       credential.ID,
+					RevealedAttributes: []string{"ageOver18"},
+				})
+			}
+			presentation, err := stack.Holder.CreatePresentation(request)
+			require.NoError(t, err)
+			return presentation
+		}
+
+		server, err := httpServer.NewStackServer(stack, "0")
+		require.NoError(t, err)
+		ts := httptest.NewServer(server.Handler())
+		t.Cleanup(ts.Close)
+		return ts, mock, present
+	}
+
+	verify := func(t *testing.T, ts *httptest.Server, presentation *vc.VerifiablePresentation) *http.Response {
+		body, err := json.Marshal(dto.VerifyPresentationRequest{Presentation: presentation})
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/verifier/verify", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("Proofs Per Request", func(t *testing.T) {
+		ts, _, present := newServer(t, api.CostPolicy{MaxProofsPerRequest: 2})
+
+		// The presentation proof and one credential proof
+		assert.Equal(t, http.StatusOK, verify(t, ts, present(1)).StatusCode)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, verify(t, ts, present(2)).StatusCode)
+	})
+
+	t.Run("Per-Client Budget", func(t *testing.T) {
+		ts, mock, present := newServer(t, api.CostPolicy{ProofsPerMinute: 4})
+		presentation := present(1)
+
+		assert.Equal(t, http.StatusOK, verify(t, ts, presentation).StatusCode)
+		assert.Equal(t, http.StatusOK, verify(t, ts, presentation).StatusCode)
+
+		resp := verify(t, ts, presentation)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+
+		// Half a minute earns the two proofs back
+		mock.Advance(30 * time.Second)
+		assert.Equal(t, http.StatusOK, verify(t, ts, presentation).StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, verify(t, ts, presentation).StatusCode)
+	})
+
+	t.Run("Shared Budget", func(t *testing.T) {
+		ts, _, present := newServer(t, api.CostPolicy{TotalProofsPerMinute: 3})
+
+		assert.Equal(t, http.StatusOK, verify(t, ts, present(1)).StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, verify(t, ts, present(1)).StatusCode)
+	})
+
+	t.Run("Budgets Larger Than The Request", func(t *testing.T) {
+		ts, _, present := newServer(t, api.CostPolicy{ProofsPerMinute: 2})
+
+		// Three proofs could never fit a two-proof budget, so waiting would not help
+		assert.Equal(t, http.StatusRequestEntityTooLarge, verify(t, ts, present(2)).StatusCode)
+	})
+
+	t.Run("Other Endpoints Are Free", func(t *testing.T) {
+		ts, _, _ := newServer(t, api.CostPolicy{ProofsPerMinute: 1})
+
+		for i := 0; i < 3; i++ {
+			body, err := json.Marshal(dto.SetupHolderRequest{Method: "example"})
+			require.NoError(t, err)
+			resp, err := http.Post(ts.URL+"/api/holder/setup", "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("Negative Limits", func(t *testing.T) {
+		stack, err := sdk.NewStack(sdk.Config{CostPolicy: api.CostPolicy{ProofsPerMinute: -1}})
+		require.NoError(t, err)
+		_, err = httpServer.NewStackServer(stack, "0")
+		assert.Error(t, err)
+	})
+}
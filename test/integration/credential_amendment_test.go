@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestCredentialAmendment tests that an issuer amends a credential with a new version that revokes
+// the previous one, that the holder fetches the latest version, and that verifiers are warned
+// about outdated versions
+func TestCredentialAmendment(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	original, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "Alice Smith"},
+			{Key: "address", Value: "1 Old Road"},
+			{Key: "memberSince", Value: 2019},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(original))
+
+	post := func(t *testing.T, path string, body interface{}, response interface{}) int {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && response != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		}
+		return resp.StatusCode
+	}
+
+	var amended dto.IssueCredentialResponse
+	t.Run("Amendment", func(t *testing.T) {
+		status := post(t, "/api/issuer/credentials/"+original.ID+"/amend", dto.AmendCredentialRequest{
+			Claims: []dto.ClaimDTO{{Key: "address", Value: "2 New Street"}},
+		}, &amended)
+		require.Equal(t, http.StatusOK, status)
+
+		credential := amended.Credential
+		require.NotNil(t, credential)
+		assert.NotEqual(t, original.ID, credential.ID)
+		assert.Equal(t, 2, credential.CredentialVersion())
+		assert.Equal(t, original.ID, credential.PreviousVersion())
+		assert.Equal(t, "2 New Street", credential.CredentialSubject["address"])
+		assert.Equal(t, "Alice Smith", credential.CredentialSubject["fullName"])
+		assert.NoError(t, stack.Issuer.VerifyCredential(credential))
+
+		previous, err := stack.Issuer.GetCredentialStatus(original.ID)
+		require.NoError(t, err)
+		assert.True(t, previous.Revoked, "the previous version is revoked")
+
+		status = post(t, "/api/issuer/credentials/"+original.ID+"/amend", dto.AmendCredentialRequest{
+			Claims: []dto.ClaimDTO{{Key: "address", Value: "3 Other Lane"}},
+		}, nil)
+		assert.Equal(t, http.StatusConflict, status, "only the latest version can be amended")
+
+		status = post(t, "/api/issuer/credentials/"+credential.ID+"/amend", dto.AmendCredentialRequest{
+			Claims: []dto.ClaimDTO{{Key: vc.CredentialVersionClaim, Value: 7}},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status, "version claims are reserved")
+	})
+
+	t.Run("Holder Fetches Latest Version", func(t *testing.T) {
+		var latest dto.FetchLatestVersionResponse
+		status := post(t, "/api/holder/credentials/"+original.ID+"/latest", dto.FetchLatestVersionRequest{HolderDID: holderDID}, &latest)
+		require.Equal(t, http.StatusOK, status)
+		assert.True(t, latest.Amended)
+		require.NotNil(t, latest.Credential)
+		assert.Equal(t, amended.CredentialID, latest.Credential.ID)
+
+		stored, err := stack.Holder.GetCredential(amended.CredentialID)
+		require.NoError(t, err)
+		assert.Equal(t, "2 New Street", stored.CredentialSubject["address"])
+
+		status = post(t, "/api/holder/credentials/"+amended.CredentialID+"/latest", dto.FetchLatestVersionRequest{HolderDID: holderDID}, &latest)
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, latest.Amended)
+	})
+
+	t.Run("Verifiers Warned About Outdated Versions", func(t *testing.T) {
+		verify := func(t *testing.T, credentialID, nonce string) *verifier.VerificationResult {
+			presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+				HolderDID:     holderDID,
+				CredentialIDs: []string{credentialID},
+				SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+					{CredentialID: credentialID, RevealedAttributes: []string{"memberSince"}},
+				},
+				Nonce: nonce,
+			})
+			require.NoError(t, err)
+			result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+				Presentation:      presentation,
+				RequiredClaims:    []string{"memberSince"},
+				VerificationNonce: nonce,
+			})
+			require.NoError(t, err)
+			return result
+		}
+		outdated := func(result *verifier.VerificationResult) bool {
+			for _, warning := range result.Warnings {
+				if warning.Code == lint.CodeOutdatedVersion {
+					return true
+				}
+			}
+			return false
+		}
+
+		result := verify(t, original.ID, "amendment-nonce-1")
+		assert.False(t, result.Valid)
+		assert.True(t, outdated(result), result.Warnings)
+
+		result = verify(t, amended.CredentialID, "amendment-nonce-2")
+		assert.True(t, result.Valid, result.Errors)
+		assert.False(t, outdated(result))
+	})
+
+	t.Run("Version Chain", func(t *testing.T) {
+		third, err := stack.Issuer.AmendCredential(context.Background(), issuer.AmendCredentialRequest{
+			CredentialID: amended.CredentialID,
+			Remove:       []string{"memberSince"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, third.CredentialVersion())
+		assert.NotContains(t, third.CredentialSubject, "memberSince")
+
+		latest, err := stack.Issuer.LatestVersion(original.ID)
+		require.NoError(t, err)
+		assert.Equal(t, third.ID, latest.ID)
+	})
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestCredentialImport tests that a holder imports a BBS+ credential issued by another service,
+// verifying its signature under the issuer's published key, and derives presentations from it
+func TestCredentialImport(t *testing.T) {
+	// The issuing service only shares its DID documents with the wallet's
+	shared := storage.NewMemoryStore()
+	issuingStack, err := sdk.NewStack(sdk.Config{Store: shared})
+	require.NoError(t, err)
+	walletStack, err := sdk.NewStack(sdk.Config{Store: shared})
+	require.NoError(t, err)
+
+	issuerSetup, err := issuingStack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := walletStack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuingStack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	raw, err := json.Marshal(credential)
+	require.NoError(t, err)
+
+	server, err := httpServer.NewStackServer(walletStack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	importCredential := func(t *testing.T, holderDID string, credential []byte, status int) {
+		data, err := json.Marshal(dto.ImportCredentialRequest{HolderDID: holderDID, Credential: credential})
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/holder/credentials/import", "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+	}
+
+	t.Run("Tampered Credential Rejected", func(t *testing.T) {
+		tampered := bytes.Replace(raw, []byte("An Nguyen"), []byte("Bao Tran"), 1)
+		importCredential(t, holderDID, tampered, http.StatusBadRequest)
+		_, err := walletStack.Holder.GetCredential(credential.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Credential Of Another Holder Rejected", func(t *testing.T) {
+		importCredential(t, "did:example:someone-else", raw, http.StatusBadRequest)
+	})
+
+	t.Run("Imported Credential Presented", func(t *testing.T) {
+		importCredential(t, holderDID, raw, http.StatusOK)
+
+		presentation, err := walletStack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: "import-nonce-f6e01dfc",
+		})
+		require.NoError(t, err)
+
+		proof, err := bbs.DecodeProof(presentation.VerifiableCredential[0].Proof.ProofValue)
+		require.NoError(t, err)
+		messages, err := vc.SignedMessages(credential)
+		require.NoError(t, err)
+		proofNonce, err := vc.ProofNonce("import-nonce-f6e01dfc")
+		require.NoError(t, err)
+		assert.NoError(t, walletStack.BBSService.VerifyProof(issuerSetup.BBSKeyPair.PublicKey, proof, [][]byte{messages[2], messages[len(messages)-1]}, proofNonce))
+	})
+}
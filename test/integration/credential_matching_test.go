@@ -0,0 +1,133 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestFindMatchingCredentials tests ranking wallet credentials against a verification request
+func TestFindMatchingCredentials(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+
+	government, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	university, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	issue := func(issuerDID, templateID string, claims []vc.Claim) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderDID,
+			TemplateID: templateID,
+			Claims:     claims,
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	nationalID := issue(government.DID.String(), "national-id", []vc.Claim{
+		{Key: "firstName", Value: "An"},
+		{Key: "lastName", Value: "Nguyen Van"},
+		{Key: "dateOfBirth", Value: "2000-01-20"},
+		{Key: "nationality", Value: "Vietnamese"},
+		{Key: "idNumber", Value: "123456789"},
+	})
+
+	ageCredential := issue(government.DID.String(), "", []vc.Claim{
+		{Key: "ageOver18", Value: true},
+		{Key: "nationality", Value: "Vietnamese"},
+	})
+
+	degree := issue(university.DID.String(), "university-degree", []vc.Claim{
+		{Key: "degree", Value: "Bachelor of Science"},
+		{Key: "university", Value: "HCMUS"},
+	})
+
+	t.Run("Ranks By Coverage And Minimal Disclosure", func(t *testing.T) {
+		matches, err := holderUC.FindMatchingCredentials(holderDID, holder.CredentialQuery{
+			RequiredClaims: []string{"ageOver18", "nationality"},
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+
+		assert.Equal(t, ageCredential.ID, matches[0].Credential.ID)
+		assert.Equal(t, 1.0, matches[0].Score.Coverage)
+		assert.Empty(t, matches[0].MissingClaims)
+		assert.Equal(t, []string{"ageOver18", "nationality"}, matches[0].Disclosure.RevealedAttributes)
+
+		assert.Equal(t, nationalID.ID, matches[1].Credential.ID)
+		assert.Equal(t, []string{"ageOver18"}, matches[1].MissingClaims)
+		assert.Equal(t, []string{"nationality"}, matches[1].Disclosure.RevealedAttributes)
+	})
+
+	t.Run("Filters By Trusted Issuer", func(t *testing.T) {
+		matches, err := holderUC.FindMatchingCredentials(holderDID, holder.CredentialQuery{
+			RequiredClaims: []string{"nationality", "degree"},
+			TrustedIssuers: []string{university.DID.String()},
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, degree.ID, matches[0].Credential.ID)
+		assert.Equal(t, 1.0, matches[0].Score.Trust)
+	})
+
+	t.Run("Filters By Credential Type", func(t *testing.T) {
+		matches, err := holderUC.FindMatchingCredentials(holderDID, holder.CredentialQuery{
+			RequiredClaims:  []string{"nationality"},
+			CredentialTypes: []string{"NationalIDCredential"},
+		})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, nationalID.ID, matches[0].Credential.ID)
+	})
+
+	t.Run("Skips Expired Credentials", func(t *testing.T) {
+		expired := time.Now().Add(-time.Hour)
+		ageCredential.ExpirationDate = &expired
+		defer func() { ageCredential.ExpirationDate = nil }()
+
+		matches, err := holderUC.FindMatchingCredentials(holderDID, holder.CredentialQuery{
+			RequiredClaims: []string{"ageOver18"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("Suggested Disclosure Creates Presentation", func(t *testing.T) {
+		matches, err := holderUC.FindMatchingCredentials(holderDID, holder.CredentialQuery{
+			RequiredClaims: []string{"ageOver18"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, matches)
+
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:           holderDID,
+			CredentialIDs:       []string{matches[0].Credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{matches[0].Disclosure},
+			Nonce:               "match-nonce-c2032759",
+		})
+		require.NoError(t, err)
+		assert.Len(t, presentation.VerifiableCredential, 1)
+	})
+
+	t.Run("Requires Claims", func(t *testing.T) {
+		_, err := holderUC.FindMatchingCredentials(holderDID, holder.CredentialQuery{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestCredentialStatus tests suspending, reinstating and revoking credentials through status lists
+func TestCredentialStatus(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "licenseClass", Value: "B"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	// verify presents the credential and verifies it, optionally after a JSON round trip
+	verify := func(t *testing.T, roundTrip bool) *verifier.VerificationResult {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+			Nonce: "status-nonce-f6bf0683",
+		})
+		require.NoError(t, err)
+
+		if roundTrip {
+			data, err := json.Marshal(presentation)
+			require.NoError(t, err)
+			presentation = &vc.VerifiablePresentation{}
+			require.NoError(t, json.Unmarshal(data, presentation))
+		}
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"licenseClass"},
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: "status-nonce-f6bf0683",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Status Entries Assigned", func(t *testing.T) {
+		require.Len(t, credential.CredentialStatus, 2)
+		assert.Equal(t, status.PurposeRevocation, credential.CredentialStatus[0].StatusPurpose)
+		assert.Equal(t, status.PurposeSuspension, credential.CredentialStatus[1].StatusPurpose)
+
+		current, err := issuerUC.GetCredentialStatus(credential.ID)
+		require.NoError(t, err)
+		assert.False(t, current.Revoked)
+		assert.False(t, current.Suspended)
+
+		assert.True(t, verify(t, false).Valid)
+	})
+
+	t.Run("Suspend", func(t *testing.T) {
+		current, err := issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		assert.True(t, current.Suspended)
+
+		for _, roundTrip := range []bool{false, true} {
+			result := verify(t, roundTrip)
+			assert.False(t, result.Valid)
+			assert.Contains(t, result.Errors, "credential 0: credential is suspended")
+		}
+
+		// The published suspension list reflects the change
+		entry := credential.CredentialStatus[1]
+		listCredential, err := issuerUC.GetStatusList(entry.StatusListCredential)
+		require.NoError(t, err)
+		list, err := status.DecodeList(listCredential.CredentialSubject.EncodedList)
+		require.NoError(t, err)
+		set, err := list.Get(0)
+		require.NoError(t, err)
+		assert.True(t, set)
+	})
+
+	t.Run("Unsuspend", func(t *testing.T) {
+		current, err := issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		assert.False(t, current.Suspended)
+		assert.True(t, verify(t, true).Valid)
+
+		_, err = issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		current, err := issuerUC.RevokeCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		assert.True(t, current.Revoked)
+
+		result := verify(t, false)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors, "credential 0: credential has been revoked")
+
+		// Revocation is permanent
+		_, err = issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown Credential", func(t *testing.T) {
+		_, err := issuerUC.GetCredentialStatus("unknown")
+		assert.Error(t, err)
+
+		_, err = issuerUC.SuspendCredential("unknown", issuer.StatusChange{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Verifier Without Registry", func(t *testing.T) {
+		otherVerifier := verifier.NewUseCase(stack.DIDService, stack.Verifier.CredentialService, stack.Verifier.Presentations)
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := otherVerifier.VerifyPresentation(verifier.VerificationRequest{Presentation: presentation})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+}
@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestCrossDeviceSession tests a holder answering, from another device, a proof request the verifier displays
+func TestCrossDeviceSession(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "firstName", Value: "An"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	request := exchange.ProofRequest{
+		RequiredClaims: []string{"ageOver18"},
+		TrustedIssuers: []string{issuerSetup.DID.String()},
+	}
+
+	present := func(t *testing.T, nonce string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: nonce,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	t.Run("Holder Device Completes Session", func(t *testing.T) {
+		session, err := verifierUC.CreateSession(verifierSetup.DID.String(), request, 0)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionPending, session.State)
+		assert.NotEmpty(t, session.Request.Nonce, "a nonce is generated")
+		assert.WithinDuration(t, time.Now().Add(verifier.DefaultSessionTTL), session.ExpiresAt, time.Second)
+
+		// The verifier's device long-polls while the holder's device works through the session
+		states := make(chan verifier.SessionState, 3)
+		go func() {
+			state := verifier.SessionPending
+			for !state.Done() {
+				current, err := verifierUC.WaitForSession(context.Background(), session.ID, state)
+				if err != nil {
+					close(states)
+					return
+				}
+				state = current.State
+				states <- state
+			}
+			close(states)
+		}()
+
+		retrieved, err := verifierUC.FetchSessionRequest(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionRetrieved, retrieved.State)
+		assert.Equal(t, session.Request.Nonce, retrieved.Request.Nonce)
+		assert.Equal(t, verifier.SessionRetrieved, <-states)
+
+		result, err := verifierUC.SubmitSessionPresentation(context.Background(), session.ID, present(t, retrieved.Request.Nonce))
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		var observed []verifier.SessionState
+		for state := range states {
+			observed = append(observed, state)
+		}
+		require.NotEmpty(t, observed)
+		assert.Equal(t, verifier.SessionCompleted, observed[len(observed)-1])
+
+		completed, err := verifierUC.GetSession(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionCompleted, completed.State)
+		assert.Equal(t, holderDID, completed.HolderDID)
+		require.NotNil(t, completed.Result)
+		assert.Equal(t, true, completed.Result.RevealedClaims["ageOver18"])
+	})
+
+	t.Run("Session Accepts One Presentation", func(t *testing.T) {
+		session, err := verifierUC.CreateSession(verifierSetup.DID.String(), request, 0)
+		require.NoError(t, err)
+
+		presentation := present(t, session.Request.Nonce)
+		_, err = verifierUC.SubmitSessionPresentation(context.Background(), session.ID, presentation)
+		require.NoError(t, err)
+
+		_, err = verifierUC.SubmitSessionPresentation(context.Background(), session.ID, presentation)
+		assert.ErrorContains(t, err, "completed")
+		_, err = verifierUC.FetchSessionRequest(session.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Wrong Nonce Fails Session", func(t *testing.T) {
+		session, err := verifierUC.CreateSession(verifierSetup.DID.String(), request, 0)
+		require.NoError(t, err)
+
+		result, _ := verifierUC.SubmitSessionPresentation(context.Background(), session.ID, present(t, "another-session-nonce"))
+		require.NotNil(t, result)
+		assert.False(t, result.Valid)
+
+		failed, err := verifierUC.GetSession(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionFailed, failed.State)
+	})
+
+	t.Run("Watchers Notified", func(t *testing.T) {
+		session, err := verifierUC.CreateSession(verifierSetup.DID.String(), request, 0)
+		require.NoError(t, err)
+
+		updates, stop, err := verifierUC.WatchSession(session.ID)
+		require.NoError(t, err)
+		defer stop()
+
+		_, err = verifierUC.FetchSessionRequest(session.ID)
+		require.NoError(t, err)
+		select {
+		case <-updates:
+		case <-time.After(time.Second):
+			t.Fatal("no notification after the request was fetched")
+		}
+
+		_, err = verifierUC.SubmitSessionPresentation(context.Background(), session.ID, present(t, session.Request.Nonce))
+		require.NoError(t, err)
+
+		// Finishing closes the channel
+		require.Eventually(t, func() bool {
+			select {
+			case _, ok := <-updates:
+				return !ok
+			default:
+				return false
+			}
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Session Expires", func(t *testing.T) {
+		session, err := verifierUC.CreateSession(verifierSetup.DID.String(), request, 50*time.Millisecond)
+		require.NoError(t, err)
+
+		// A long-poll returns when the session expires
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		expired, err := verifierUC.WaitForSession(ctx, session.ID, verifier.SessionPending)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionExpired, expired.State)
+
+		_, err = verifierUC.FetchSessionRequest(session.ID)
+		assert.ErrorContains(t, err, "expired")
+		_, err = verifierUC.SubmitSessionPresentation(context.Background(), session.ID, present(t, session.Request.Nonce))
+		assert.ErrorContains(t, err, "expired")
+
+		assert.Equal(t, 0, verifierUC.PurgeExpiredSessions(time.Now().Add(-time.Minute)))
+		assert.GreaterOrEqual(t, verifierUC.PurgeExpiredSessions(time.Now()), 1)
+		_, err = verifierUC.GetSession(session.ID)
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("Long Poll Times Out", func(t *testing.T) {
+		session, err := verifierUC.CreateSession(verifierSetup.DID.String(), request, 0)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		current, err := verifierUC.WaitForSession(ctx, session.ID, verifier.SessionPending)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionPending, current.State)
+	})
+}
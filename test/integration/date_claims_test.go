@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestDateClaims tests that the age verification endpoint reads dates of birth in several formats
+// and computes ages on the day the date policy's timezone is in
+func TestDateClaims(t *testing.T) {
+	// 20:00 UTC on 15 October is already 16 October in Tokyo
+	mock := clock.NewMock(time.Date(2025, time.October, 15, 20, 0, 0, 0, time.UTC))
+
+	stack, err := sdk.NewStack(sdk.Config{Clock: mock})
+	require.NoError(t, err)
+	issuerUC := stack.Issuer.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	type ageCredentialResponse struct {
+		Credential *vc.VerifiableCredential `json:"credential"`
+		CurrentAge int                      `json:"currentAge"`
+	}
+
+	issue := func(t *testing.T, dateOfBirth interface{}) (int, *ageCredentialResponse) {
+		body, err := json.Marshal(map[string]interface{}{
+			"issuerDid":   issuerSetup.DID.String(),
+			"subjectDid":  "did:example:citizen",
+			"firstName":   "Jane",
+			"lastName":    "Doe",
+			"dateOfBirth": dateOfBirth,
+		})
+		require.NoError(t, err)
+
+		resp, err := http.Post(ts.URL+"/api/age-verification/credential", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			return resp.StatusCode, nil
+		}
+
+		var response ageCredentialResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, &response
+	}
+
+	t.Run("Formats", func(t *testing.T) {
+		for name, dateOfBirth := range map[string]interface{}{
+			"Date Only":     "2007-10-15",
+			"RFC3339":       "2007-10-15T08:30:00+07:00",
+			"Epoch Seconds": 1192406400,
+		} {
+			code, response := issue(t, dateOfBirth)
+			require.Equal(t, http.StatusCreated, code, name)
+			assert.Equal(t, 18, response.CurrentAge, name)
+			assert.Equal(t, "2007-10-15", response.Credential.CredentialSubject["dateOfBirth"], name)
+			assert.Equal(t, true, response.Credential.CredentialSubject["ageOver18"], name)
+		}
+	})
+
+	t.Run("Birthday Follows Date Policy", func(t *testing.T) {
+		_, response := issue(t, "2007-10-16")
+		assert.Equal(t, 17, response.CurrentAge)
+		assert.Equal(t, "2025-10-15", response.Credential.CredentialSubject["issuedAt"])
+
+		tokyo, err := vc.NewDatePolicy("Asia/Tokyo")
+		require.NoError(t, err)
+		server.SetDatePolicy(tokyo)
+		defer server.SetDatePolicy(vc.DatePolicy{})
+
+		_, response = issue(t, "2007-10-16")
+		assert.Equal(t, 18, response.CurrentAge)
+		assert.Equal(t, true, response.Credential.CredentialSubject["ageOver18"])
+		assert.Equal(t, "2025-10-16", response.Credential.CredentialSubject["issuedAt"])
+	})
+
+	t.Run("Invalid Dates", func(t *testing.T) {
+		for _, dateOfBirth := range []interface{}{"15/10/2007", "2025-10-16", true} {
+			code, _ := issue(t, dateOfBirth)
+			assert.Equal(t, http.StatusBadRequest, code, "%v", dateOfBirth)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestDeriveCredential tests that a wallet derives a selective disclosure proof from an imported
+// credential on its own, for integrations that assemble presentations themselves
+func TestDeriveCredential(t *testing.T) {
+	shared := storage.NewMemoryStore()
+	issuingStack, err := sdk.NewStack(sdk.Config{Store: shared})
+	require.NoError(t, err)
+	walletStack, err := sdk.NewStack(sdk.Config{Store: shared})
+	require.NoError(t, err)
+
+	issuerSetup, err := issuingStack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := walletStack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuingStack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	raw, err := json.Marshal(credential)
+	require.NoError(t, err)
+	_, err = walletStack.Holder.ImportCredential(holderDID, raw)
+	require.NoError(t, err)
+
+	server, err := httpServer.NewStackServer(walletStack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	derive := func(t *testing.T, credentialID string, req dto.DeriveCredentialRequest) (*http.Response, dto.DeriveCredentialResponse) {
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/holder/credentials/"+credentialID+"/derive", "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body dto.DeriveCredentialResponse
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		}
+		return resp, body
+	}
+
+	t.Run("Derived Credential", func(t *testing.T) {
+		resp, body := derive(t, credential.ID, dto.DeriveCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"ageOver18"},
+			Nonce:              "derive-nonce-1a8cf5d9",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		derived := body.Credential
+		require.NotNil(t, derived)
+		assert.Equal(t, credential.ID, derived.ID)
+		assert.Equal(t, true, derived.CredentialSubject["ageOver18"])
+		assert.NotContains(t, derived.CredentialSubject, "fullName")
+		assert.Equal(t, "derive-nonce-1a8cf5d9", derived.Proof.Nonce)
+
+		verifier := walletStack.Verifier.CredentialService.(vc.DerivedProofVerifier)
+		assert.NoError(t, verifier.VerifyDerivedProof(derived, issuerSetup.BBSKeyPair.PublicKey))
+	})
+
+	t.Run("Rejected Requests", func(t *testing.T) {
+		resp, _ := derive(t, credential.ID, dto.DeriveCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"ageOver18"},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "a nonce is required")
+
+		resp, _ = derive(t, credential.ID, dto.DeriveCredentialRequest{
+			HolderDID:          "did:example:someone-else",
+			RevealedAttributes: []string{"ageOver18"},
+			Nonce:              "derive-nonce-1a8cf5d9",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = derive(t, "vc:example:unknown", dto.DeriveCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"ageOver18"},
+			Nonce:              "derive-nonce-1a8cf5d9",
+		})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,158 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestDIDResolution tests DID Resolution results with their error codes, and that a verifier
+// tells an issuer it could not reach from an invalid one
+func TestDIDResolution(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+
+	// The peer the verifier resolves DIDs at fails for the issuer's DID while unavailable is set
+	var unavailable atomic.Bool
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	handler := server.Handler()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unavailable.Load() && strings.Contains(r.URL.Path, issuerSetup.DID.Identifier) {
+			http.Error(w, "resolver overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "memberSince", Value: "2019"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	resolve := func(t *testing.T, didString string) (int, did.ResolutionResult) {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/dids/"+didString, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", did.ResolutionMediaType)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var result did.ResolutionResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp.StatusCode, result
+	}
+
+	t.Run("Resolution Results", func(t *testing.T) {
+		status, result := resolve(t, issuerSetup.DID.String())
+		assert.Equal(t, http.StatusOK, status)
+		require.NotNil(t, result.DIDDocument)
+		assert.Equal(t, issuerSetup.DID.String(), result.DIDDocument.ID)
+		assert.NotNil(t, result.DIDDocumentMetadata.Created)
+
+		status, result = resolve(t, "did:example:unknown")
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, did.ErrorNotFound, result.DIDResolutionMetadata.Error)
+
+		status, result = resolve(t, "did:Example:invalid")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, did.ErrorInvalidDID, result.DIDResolutionMetadata.Error)
+	})
+
+	t.Run("Transient Failures Are Indeterminate", func(t *testing.T) {
+		verifierStack, err := sdk.NewStack(sdk.Config{Peers: []string{ts.URL}})
+		require.NoError(t, err)
+		verify := func(t *testing.T, nonce string) *verifier.VerificationResult {
+			presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+				HolderDID:     holderSetup.DID.String(),
+				CredentialIDs: []string{credential.ID},
+				SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+					{CredentialID: credential.ID, RevealedAttributes: []string{"memberSince"}},
+				},
+				Nonce: nonce,
+			})
+			require.NoError(t, err)
+			result, err := verifierStack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+				Presentation:      presentation,
+				RequiredClaims:    []string{"memberSince"},
+				TrustedIssuers:    []string{issuerSetup.DID.String()},
+				VerificationNonce: nonce,
+			})
+			require.NoError(t, err)
+			return result
+		}
+
+		unavailable.Store(true)
+		result := verify(t, "resolution-nonce-1")
+		assert.False(t, result.Valid)
+		assert.True(t, result.Indeterminate, result.Errors)
+		assert.Contains(t, result.Errors[0], did.ErrorInternal)
+
+		unavailable.Store(false)
+		result = verify(t, "resolution-nonce-2")
+		assert.True(t, result.Valid, result.Errors)
+		assert.False(t, result.Indeterminate)
+	})
+
+	t.Run("Unknown Issuers Are Invalid", func(t *testing.T) {
+		other, err := sdk.NewStack(sdk.Config{})
+		require.NoError(t, err)
+		otherIssuer, err := other.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+		otherHolder, err := other.Holder.SetupHolder("example")
+		require.NoError(t, err)
+		otherCredential, err := other.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  otherIssuer.DID.String(),
+			SubjectDID: otherHolder.DID.String(),
+			Claims:     []vc.Claim{{Key: "memberSince", Value: "2020"}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, other.Holder.StoreCredential(otherCredential))
+		presentation, err := other.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     otherHolder.DID.String(),
+			CredentialIDs: []string{otherCredential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: otherCredential.ID, RevealedAttributes: []string{"memberSince"}},
+			},
+			Nonce: "resolution-nonce-3",
+		})
+		require.NoError(t, err)
+
+		// The holder's DID is known to the verifier, the issuer's is not known anywhere
+		_, err = stack.DIDService.CreateDIDDocument(otherHolder.DID, otherHolder.KeyPair)
+		require.NoError(t, err)
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"memberSince"},
+			TrustedIssuers:    []string{otherIssuer.DID.String()},
+			VerificationNonce: "resolution-nonce-3",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.False(t, result.Indeterminate, result.Errors)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], did.ErrorNotFound)
+	})
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+// TestDIDValidation tests that malformed DIDs in request bodies, query parameters and paths are
+// rejected with 400 and a structured description of each rejected field
+func TestDIDValidation(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	decode := func(t *testing.T, resp *http.Response) dto.ErrorResponse {
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errorResp dto.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errorResp))
+		return errorResp
+	}
+	post := func(t *testing.T, path string, body interface{}) *http.Response {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Request Body", func(t *testing.T) {
+		errorResp := decode(t, post(t, "/api/issuer/credentials", dto.IssueCredentialRequest{
+			IssuerDID:  "did:example:issuer",
+			SubjectDID: "did:example:a b",
+			Claims:     []dto.ClaimDTO{{Key: "name", Value: "Alice"}},
+		}))
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, dto.FieldError{
+			Field:    "subjectDid",
+			Value:    "did:example:a b",
+			Reason:   "character ' ' is not allowed in a method-specific identifier",
+			Position: 13,
+		}, errorResp.Fields[0])
+	})
+
+	t.Run("DID Lists", func(t *testing.T) {
+		errorResp := decode(t, post(t, "/api/verifier/verify", dto.VerifyPresentationRequest{
+			TrustedIssuers: []string{"did:example:issuer", "did:Example:issuer"},
+		}))
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, "trustedIssuers[1]", errorResp.Fields[0].Field)
+		assert.Equal(t, 4, errorResp.Fields[0].Position)
+	})
+
+	t.Run("Nested Requests", func(t *testing.T) {
+		errorResp := decode(t, post(t, "/api/issuer/signing-sessions", dto.StartSigningSessionRequest{
+			Request: dto.IssueCredentialRequest{IssuerDID: "issuer"},
+		}))
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, "request.issuerDid", errorResp.Fields[0].Field)
+	})
+
+	t.Run("Query Parameter", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/holder/consents?holderDid=" + url.QueryEscape("did:example:<script>"))
+		require.NoError(t, err)
+		errorResp := decode(t, resp)
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, "holderDid", errorResp.Fields[0].Field)
+		assert.Equal(t, 12, errorResp.Fields[0].Position)
+	})
+
+	t.Run("Path Parameter", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/dids/" + url.PathEscape("did:example:123#key-1"))
+		require.NoError(t, err)
+		errorResp := decode(t, resp)
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, "did", errorResp.Fields[0].Field)
+
+		// A well-formed DID nobody registered is not a validation failure
+		resp, err = http.Get(ts.URL + "/api/dids/did:example:unknown")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Setup Method", func(t *testing.T) {
+		errorResp := decode(t, post(t, "/api/holder/setup", dto.SetupHolderRequest{Method: "ex:ample"}))
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, "method", errorResp.Fields[0].Field)
+		assert.Equal(t, 2, errorResp.Fields[0].Position)
+	})
+
+	t.Run("Oversized Values Are Not Echoed", func(t *testing.T) {
+		long := "did:example:" + strings.Repeat("a", 4096)
+		errorResp := decode(t, post(t, "/api/issuer/credentials", dto.IssueCredentialRequest{IssuerDID: long}))
+		require.Len(t, errorResp.Fields, 1)
+		assert.Equal(t, -1, errorResp.Fields[0].Position)
+		assert.Less(t, len(errorResp.Fields[0].Value), 100)
+	})
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+)
+
+// TestDIDWebHosting tests that the server publishes its did:web documents where did:web
+// resolvers look for them, in step with the DID repository
+func TestDIDWebHosting(t *testing.T) {
+	// The domain must be known before the stack generates DIDs under it
+	ts := httptest.NewUnstartedServer(nil)
+	domain := ts.Listener.Addr().String()
+	stack, err := sdk.NewStack(sdk.Config{WebDomain: domain})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts.Config.Handler = server.Handler()
+	ts.StartTLS()
+	defer ts.Close()
+
+	ctx := context.Background()
+	resolve := func(didString string) (*did.DIDDocument, error) {
+		return did.ResolveWebDID(ctx, fetch.NewFetcher(ts.Client(), 0), didString)
+	}
+
+	issuerSetup, err := stack.Issuer.SetupIssuer(did.MethodWeb)
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	t.Run("Users", func(t *testing.T) {
+		doc, err := resolve(issuerDID)
+		require.NoError(t, err)
+		assert.Equal(t, issuerDID, doc.ID)
+		assert.NotEmpty(t, doc.AssertionMethod)
+	})
+
+	t.Run("Well-Known Document", func(t *testing.T) {
+		root := &did.DID{Method: did.MethodWeb, Identifier: strings.ReplaceAll(domain, ":", "%3A")}
+		_, err := resolve(root.String())
+		assert.ErrorIs(t, err, did.ErrNotFound)
+
+		_, keys, err := stack.DIDService.GenerateDID(did.MethodWeb)
+		require.NoError(t, err)
+		keys.KeyID = root.String() + "#key-1"
+		_, err = stack.DIDService.CreateDIDDocument(root, keys)
+		require.NoError(t, err)
+
+		doc, err := resolve(root.String())
+		require.NoError(t, err)
+		assert.Equal(t, root.String(), doc.ID)
+	})
+
+	t.Run("Kept In Sync", func(t *testing.T) {
+		rotated, err := stack.DIDService.RotateKey(issuerDID)
+		require.NoError(t, err)
+		doc, err := resolve(issuerDID)
+		require.NoError(t, err)
+		_, err = doc.VerificationMethodAt(rotated.KeyID, stack.Clock.Now())
+		assert.NoError(t, err, "the rotated key is published at once")
+
+		require.NoError(t, stack.DIDRepository.Deactivate(issuerDID, storage.Tombstone{Reason: "issuer closed"}))
+		_, err = resolve(issuerDID)
+		assert.ErrorIs(t, err, did.ErrDeactivated)
+	})
+
+	t.Run("Unknown Paths", func(t *testing.T) {
+		resp, err := ts.Client().Get(ts.URL + "/users/unknown/did.json")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
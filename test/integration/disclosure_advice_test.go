@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestDisclosureAdvice tests the privacy analysis of presentation requests
+func TestDisclosureAdvice(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "firstName", Value: "An"},
+			{Key: "dateOfBirth", Value: "2000-01-20"},
+			{Key: "nationality", Value: "Vietnamese"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	request := func(purpose string, revealed ...string) holder.PresentationRequest {
+		return holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: revealed},
+			},
+			RequestMetadata: vc.RequestMetadata{Purpose: purpose},
+		}
+	}
+
+	t.Run("Over-Asking Request", func(t *testing.T) {
+		report, err := holderUC.AnalyzeDisclosure(request("age-over-18", "dateOfBirth", "nationality"))
+		require.NoError(t, err)
+
+		advice := report.Credentials[credential.ID]
+		require.NotNil(t, advice)
+		assert.Equal(t, []string{"ageOver18"}, advice.MinimalClaims)
+		assert.Equal(t, "ageOver18", advice.Substitutions["dateOfBirth"])
+		assert.Equal(t, []string{"nationality"}, advice.Unnecessary)
+		assert.Len(t, report.Warnings, 2)
+		assert.Less(t, report.Score, advice.MinimalScore)
+	})
+
+	t.Run("Minimal Request", func(t *testing.T) {
+		report, err := holderUC.AnalyzeDisclosure(request("age-over-18", "ageOver18"))
+		require.NoError(t, err)
+
+		assert.Empty(t, report.Warnings)
+		assert.Equal(t, report.Credentials[credential.ID].MinimalScore, report.Score)
+	})
+
+	t.Run("Unknown Credential", func(t *testing.T) {
+		req := request("age-over-18", "ageOver18")
+		req.SelectiveDisclosure[0].CredentialID = "unknown"
+		_, err := holderUC.AnalyzeDisclosure(req)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,243 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+const disclosureAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// disclosureCase is a random claim set, a random non-empty subset of it to reveal, and a
+// single-byte mutation of one revealed value
+type disclosureCase struct {
+	Claims   []vc.Claim
+	Revealed []string
+	// Mutated is the revealed claim whose value is replaced by Mutation
+	Mutated  string
+	Mutation interface{}
+}
+
+// Generate implements quick.Generator
+func (disclosureCase) Generate(r *rand.Rand, size int) reflect.Value {
+	word := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = disclosureAlphabet[r.Intn(len(disclosureAlphabet))]
+		}
+		return string(b)
+	}
+
+	var c disclosureCase
+	count := 1 + r.Intn(8)
+	for i := 0; i < count; i++ {
+		// The index keeps keys unique; the suffix keeps them from matching anything else presented
+		claim := vc.Claim{Key: fmt.Sprintf("claim%d%s", i, word(8))}
+		switch r.Intn(3) {
+		case 0:
+			claim.Value = word(12 + r.Intn(20))
+		case 1:
+			claim.Value = r.Intn(1_000_000)
+		default:
+			claim.Value = r.Intn(2) == 0
+		}
+		c.Claims = append(c.Claims, claim)
+	}
+
+	for _, i := range r.Perm(count)[:1+r.Intn(count)] {
+		c.Revealed = append(c.Revealed, c.Claims[i].Key)
+	}
+
+	mutated := c.Claims[0]
+	for _, claim := range c.Claims {
+		if claim.Key == c.Revealed[0] {
+			mutated = claim
+		}
+	}
+	c.Mutated = mutated.Key
+	switch value := mutated.Value.(type) {
+	case string:
+		b := []byte(value)
+		i := r.Intn(len(b))
+		b[i] = otherByte(r, b[i], disclosureAlphabet)
+		c.Mutation = string(b)
+	case int:
+		b := []byte(strconv.Itoa(value))
+		i := r.Intn(len(b))
+		digits := "0123456789"
+		if i == 0 && len(b) > 1 {
+			digits = "123456789"
+		}
+		b[i] = otherByte(r, b[i], digits)
+		c.Mutation, _ = strconv.Atoi(string(b))
+	case bool:
+		c.Mutation = !value
+	}
+
+	return reflect.ValueOf(c)
+}
+
+// otherByte returns a byte of the alphabet other than b
+func otherByte(r *rand.Rand, b byte, alphabet string) byte {
+	for {
+		if other := alphabet[r.Intn(len(alphabet))]; other != b {
+			return other
+		}
+	}
+}
+
+// TestSelectiveDisclosureProperties tests, over random claim sets and random revealed subsets,
+// that revealed claims always verify, hidden claims never appear in the presentation, and
+// changing a single byte of a revealed value breaks verification
+func TestSelectiveDisclosureProperties(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	config := &quick.Config{MaxCount: 100}
+	if testing.Short() {
+		config.MaxCount = 10
+	}
+
+	// present issues the case's claims and presents the revealed subset as a verifier receives it
+	present := func(t *testing.T, c disclosureCase) (*vc.VerifiablePresentation, []byte) {
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     c.Claims,
+		})
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(credential))
+
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: c.Revealed},
+			},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		var received vc.VerifiablePresentation
+		require.NoError(t, json.Unmarshal(data, &received))
+		return &received, data
+	}
+
+	verify := func(t *testing.T, c disclosureCase, presentation *vc.VerifiablePresentation) *verifier.VerificationResult {
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: c.Revealed,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Revealed Claims Verify", func(t *testing.T) {
+		property := func(c disclosureCase) bool {
+			presentation, _ := present(t, c)
+			result := verify(t, c, presentation)
+			if !result.Valid {
+				t.Logf("errors: %v", result.Errors)
+				return false
+			}
+
+			for _, claim := range c.Claims {
+				if !contains(c.Revealed, claim.Key) {
+					continue
+				}
+				want, _ := json.Marshal(claim.Value)
+				got, _ := json.Marshal(result.RevealedClaims[claim.Key])
+				if string(want) != string(got) {
+					t.Logf("%s revealed as %s, issued as %s", claim.Key, got, want)
+					return false
+				}
+			}
+			return true
+		}
+		require.NoError(t, quick.Check(property, config))
+	})
+
+	t.Run("Hidden Claims Never Appear", func(t *testing.T) {
+		property := func(c disclosureCase) bool {
+			presentation, data := present(t, c)
+			subject := presentation.VerifiableCredential[0].CredentialSubject
+
+			for _, claim := range c.Claims {
+				if contains(c.Revealed, claim.Key) {
+					continue
+				}
+				if _, ok := subject[claim.Key]; ok {
+					t.Logf("hidden claim %s is in the credential subject", claim.Key)
+					return false
+				}
+				if strings.Contains(string(data), claim.Key) {
+					t.Logf("hidden claim key %s appears in the presentation", claim.Key)
+					return false
+				}
+				if value, ok := claim.Value.(string); ok && strings.Contains(string(data), value) {
+					t.Logf("hidden claim value of %s appears in the presentation", claim.Key)
+					return false
+				}
+			}
+			return true
+		}
+		require.NoError(t, quick.Check(property, config))
+	})
+
+	t.Run("Mutated Revealed Values Fail", func(t *testing.T) {
+		property := func(c disclosureCase) bool {
+			presentation, _ := present(t, c)
+			subject := presentation.VerifiableCredential[0].CredentialSubject
+			subject[c.Mutated] = c.Mutation
+
+			// The holder signs over the altered value so only the derived proof can catch it
+			payload, err := vc.PresentationSigningInput(presentation)
+			require.NoError(t, err)
+			signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+			require.NoError(t, err)
+			presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+			result := verify(t, c, presentation)
+			if result.Valid {
+				t.Logf("%s mutated to %v still verifies", c.Mutated, c.Mutation)
+				return false
+			}
+			if !strings.Contains(fmt.Sprint(result.Errors), "BBS+ proof does not match") {
+				t.Logf("%s mutated to %v failed for another reason: %v", c.Mutated, c.Mutation, result.Errors)
+				return false
+			}
+			return true
+		}
+		require.NoError(t, quick.Check(property, config))
+	})
+}
+
+// contains reports whether a list includes a value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
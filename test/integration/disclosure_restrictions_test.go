@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestDisclosureRestrictions tests that holders honor the disclosure restrictions issuers sign
+// into credentials, and cannot strip them
+func TestDisclosureRestrictions(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	registry := schema.NewDefaultRegistry()
+	require.NoError(t, registry.Register(&schema.CredentialTemplate{
+		ID:             "resident-card",
+		CredentialType: "ResidentCardCredential",
+		Claims: []schema.ClaimSchema{
+			{Key: "firstName", Type: schema.ClaimTypeString, Required: true},
+			{Key: "dateOfBirth", Type: schema.ClaimTypeDate, Required: true, Disclosure: schema.DisclosureNotAlone},
+			{Key: "idNumber", Type: schema.ClaimTypeString, Required: true, Disclosure: schema.DisclosureNever},
+		},
+	}))
+	stack.Issuer.SetTemplateRegistry(registry)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	claims := []vc.Claim{
+		{Key: "firstName", Value: "An"},
+		{Key: "dateOfBirth", Value: "1990-01-01"},
+		{Key: "idNumber", Value: "001090000001"},
+	}
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims:     claims,
+		TemplateID: "resident-card",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, schema.DisclosureNever, credential.ClaimManifest.Restriction("idNumber"))
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	present := func(credentialID string, revealed ...string) error {
+		_, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credentialID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credentialID, RevealedAttributes: revealed},
+			},
+		})
+		return err
+	}
+
+	t.Run("Never Disclosed Claim Is Refused", func(t *testing.T) {
+		assert.ErrorIs(t, present(credential.ID, "firstName", "idNumber"), vc.ErrDisclosureRestricted)
+
+		data, err := json.Marshal(dto.CreatePresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"idNumber"}},
+			},
+		})
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/holder/presentations", "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Claim Not Disclosed Alone Needs Company", func(t *testing.T) {
+		assert.ErrorIs(t, present(credential.ID, "dateOfBirth"), vc.ErrDisclosureRestricted)
+		assert.NoError(t, present(credential.ID, "firstName", "dateOfBirth"))
+	})
+
+	t.Run("Requests Cannot Loosen The Template", func(t *testing.T) {
+		loosened := append([]vc.Claim{}, claims...)
+		loosened[2].Disclosure = schema.DisclosureNotAlone
+		_, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderDID,
+			Claims:     loosened,
+			TemplateID: "resident-card",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Restrictions Set Per Claim Without Template", func(t *testing.T) {
+		restricted, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderDID,
+			Claims: []vc.Claim{
+				{Key: "memberNumber", Value: "M-1001", Disclosure: schema.DisclosureNever},
+				{Key: "tier", Value: "gold"},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, stack.Holder.StoreCredential(restricted))
+		assert.ErrorIs(t, present(restricted.ID, "memberNumber"), vc.ErrDisclosureRestricted)
+		assert.NoError(t, present(restricted.ID, "tier"))
+	})
+
+	t.Run("Stripped Restriction Breaks The Signature", func(t *testing.T) {
+		tampered := *credential
+		tampered.ID = credential.ID + "-tampered"
+		manifest := vc.ClaimManifest{Claims: append([]vc.ManifestEntry{}, credential.ClaimManifest.Claims...)}
+		for i := range manifest.Claims {
+			manifest.Claims[i].Disclosure = ""
+		}
+		tampered.ClaimManifest = &manifest
+		require.NoError(t, stack.Holder.StoreCredential(&tampered))
+
+		err := present(tampered.ID, "idNumber")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, vc.ErrDisclosureRestricted)
+	})
+
+	t.Run("Matching Skips Never Disclosed Claims", func(t *testing.T) {
+		matches, err := stack.Holder.FindMatchingCredentials(holderDID, holder.CredentialQuery{
+			RequiredClaims:  []string{"firstName", "idNumber"},
+			CredentialTypes: []string{"ResidentCardCredential"},
+		})
+		require.NoError(t, err)
+		var genuine *holder.CredentialMatch
+		for _, match := range matches {
+			if match.Credential.ID == credential.ID {
+				genuine = match
+			}
+		}
+		require.NotNil(t, genuine)
+		assert.Equal(t, []string{"firstName"}, genuine.Disclosure.RevealedAttributes)
+		assert.Equal(t, []string{"idNumber"}, genuine.MissingClaims)
+	})
+}
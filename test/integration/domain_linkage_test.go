@@ -0,0 +1,173 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/fetch"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestDomainLinkage tests that a verifier trusts an issuer because the issuer's domain publishes a
+// signed linkage to its DID in /.well-known/did-configuration.json
+func TestDomainLinkage(t *testing.T) {
+	mock := clock.NewMock(time.Now())
+
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	verifierUC.SetClock(mock)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	// The issuer's server is the domain the linkage is published on
+	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+	ts := httptest.NewTLSServer(server.Handler())
+	defer ts.Close()
+
+	// A domain that links no DIDs
+	unrelated := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(vc.DIDConfiguration{Context: vc.DIDConfigurationContext})
+	}))
+	defer unrelated.Close()
+
+	verifierUC.SetDomainFetcher(fetch.NewFetcher(ts.Client(), time.Hour))
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerDID,
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	verify := func(t *testing.T, domains ...string) *verifier.VerificationResult {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"ageOver18"},
+			TrustedDomains: domains,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	body, err := json.Marshal(dto.LinkDomainRequest{IssuerDID: issuerDID, Origin: ts.URL, ValidDays: 30})
+	require.NoError(t, err)
+	resp, err := ts.Client().Post(ts.URL+"/api/issuer/domains", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var linkage vc.DomainLinkageCredential
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&linkage))
+	resp.Body.Close()
+	assert.Equal(t, issuerDID, linkage.CredentialSubject.ID)
+	assert.Equal(t, ts.URL, linkage.CredentialSubject.Origin)
+
+	t.Run("Published Configuration", func(t *testing.T) {
+		resp, err := ts.Client().Get(ts.URL + vc.DIDConfigurationPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var configuration vc.DIDConfiguration
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&configuration))
+		assert.Equal(t, vc.DIDConfigurationContext, configuration.Context)
+		require.Len(t, configuration.LinkedDIDs, 1)
+		assert.Equal(t, issuerDID, configuration.LinkedDIDs[0].Issuer)
+	})
+
+	t.Run("Issuer Trusted By Domain", func(t *testing.T) {
+		result := verify(t, ts.URL)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, true, result.RevealedClaims["ageOver18"])
+	})
+
+	t.Run("Configuration Is Cached", func(t *testing.T) {
+		before := verifierUC.CacheStats()["domains"]
+		assert.True(t, verify(t, ts.URL).Valid)
+		after := verifierUC.CacheStats()["domains"]
+		assert.Equal(t, before.Hits+1, after.Hits)
+		assert.Equal(t, before.Misses, after.Misses)
+	})
+
+	t.Run("Domain Without Linkage", func(t *testing.T) {
+		verifierUC.SetDomainFetcher(fetch.NewFetcher(unrelated.Client(), time.Hour))
+		defer verifierUC.SetDomainFetcher(fetch.NewFetcher(ts.Client(), time.Hour))
+
+		result := verify(t, unrelated.URL)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "is not trusted")
+		assert.Contains(t, result.Errors[0], "does not link")
+	})
+
+	t.Run("Trusted Issuer Or Domain", func(t *testing.T) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+		})
+		require.NoError(t, err)
+
+		// Listing the DID is enough without consulting the domain
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			TrustedIssuers: []string{issuerDID},
+			TrustedDomains: []string{"unreachable.invalid"},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Expired Linkage", func(t *testing.T) {
+		mock.Advance(31 * 24 * time.Hour)
+		defer mock.Set(time.Now())
+
+		result := verify(t, ts.URL)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "no valid linkage")
+		assert.Contains(t, result.Errors[0], "credential expired")
+	})
+
+	t.Run("Invalid Trusted Domain", func(t *testing.T) {
+		body, err := json.Marshal(map[string]interface{}{
+			"presentation":   map[string]interface{}{},
+			"trustedDomains": []string{"http://gov.vn"},
+		})
+		require.NoError(t, err)
+		resp, err := ts.Client().Post(ts.URL+"/api/verifier/verify", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestEncryptedDelivery tests credential and presentation delivery encrypted to DID key agreement keys
+func TestEncryptedDelivery(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("test")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("test")
+	require.NoError(t, err)
+
+	verifierSetup, err := verifierUC.SetupVerifier("test")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "name", Value: "Alice"},
+			{Key: "age", Value: 30},
+		},
+	})
+	require.NoError(t, err)
+
+	// Issuer -> holder
+	credentialEnvelope, err := issuerUC.EncryptCredentialForHolder(credential)
+	require.NoError(t, err)
+	assert.NotContains(t, credentialEnvelope.Ciphertext, "Alice")
+
+	received, err := holderUC.ReceiveEncryptedCredential(credentialEnvelope)
+	require.NoError(t, err)
+	assert.Equal(t, credential.ID, received.ID)
+
+	presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+		HolderDID:     holderSetup.DID.String(),
+		CredentialIDs: []string{credential.ID},
+		SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"age"}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Holder -> verifier
+	presentationEnvelope, err := holderUC.EncryptPresentationForVerifier(presentation, verifierSetup.DID.String())
+	require.NoError(t, err)
+
+	decrypted, err := verifierUC.DecryptPresentation(presentationEnvelope)
+	require.NoError(t, err)
+
+	result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+		Presentation:   decrypted,
+		RequiredClaims: []string{"age"},
+		TrustedIssuers: []string{issuerSetup.DID.String()},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "errors: %v", result.Errors)
+	assert.Equal(t, float64(30), result.RevealedClaims["age"])
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/events"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestEventBus tests that the issuer, holder and verifier publish their events on the stack's bus
+// and that a presentation can be requested, created and verified with requests over the bus alone
+func TestEventBus(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	defer stack.Events.Close()
+
+	received := make(chan events.Event, 16)
+	for _, eventType := range []events.Type{events.CredentialIssued, events.CredentialStored, events.PresentationRequested, events.PresentationVerified} {
+		stack.Events.Subscribe(eventType, func(ctx context.Context, event events.Event) error {
+			received <- event
+			return nil
+		})
+	}
+	next := func(t *testing.T, eventType events.Type) events.Event {
+		select {
+		case event := <-received:
+			require.Equal(t, eventType, event.Type)
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s event", eventType)
+			return events.Event{}
+		}
+	}
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := stack.Verifier.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "name", Value: "An Nguyen"},
+		},
+	})
+	require.NoError(t, err)
+	issued := next(t, events.CredentialIssued).Data.(events.CredentialIssuedData)
+	assert.Equal(t, credential.ID, issued.Credential.ID)
+
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+	stored := next(t, events.CredentialStored).Data.(events.CredentialStoredData)
+	assert.Equal(t, credential.ID, stored.CredentialID)
+	assert.Equal(t, holderSetup.DID.String(), stored.HolderDID)
+
+	ctx := context.Background()
+	reply, err := stack.Events.Request(ctx, events.New(events.RequestPresentation, events.RequestPresentationData{
+		VerifierDID: verifierSetup.DID.String(),
+		Request: exchange.ProofRequest{
+			RequiredClaims: []string{"ageOver18"},
+			OptionalClaims: []string{"email"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+		},
+	}))
+	require.NoError(t, err)
+	session := reply.(events.PresentationRequestedData)
+	assert.NotEmpty(t, session.Request.Nonce)
+	assert.Equal(t, session.SessionID, next(t, events.PresentationRequested).Data.(events.PresentationRequestedData).SessionID)
+
+	reply, err = stack.Events.Request(ctx, events.New(events.CreatePresentation, events.CreatePresentationData{
+		HolderDID:    holderSetup.DID.String(),
+		CredentialID: credential.ID,
+		Request:      session.Request,
+	}))
+	require.NoError(t, err)
+	answer := reply.(events.PresentationCreatedData)
+	assert.Equal(t, []string{"ageOver18"}, answer.RevealedClaims)
+	assert.Equal(t, []string{"email"}, answer.WithheldClaims)
+	assert.Equal(t, []string{"name"}, answer.HiddenClaims)
+
+	reply, err = stack.Events.Request(ctx, events.New(events.SubmitPresentation, events.SubmitPresentationData{
+		SessionID:    session.SessionID,
+		Presentation: answer.Presentation,
+	}))
+	require.NoError(t, err)
+	verified := reply.(events.PresentationVerifiedData)
+	assert.True(t, verified.Valid, verified.Errors)
+	assert.Equal(t, true, verified.RevealedClaims["ageOver18"])
+
+	published := next(t, events.PresentationVerified).Data.(events.PresentationVerifiedData)
+	assert.Equal(t, session.SessionID, published.SessionID)
+	assert.True(t, published.Valid)
+
+	t.Run("Unknown Request Type", func(t *testing.T) {
+		_, err := stack.Events.Request(ctx, events.New("unknown", nil))
+		assert.ErrorIs(t, err, events.ErrNoHandler)
+	})
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestEvidenceAttachments tests that evidence documents attached at issuance are described in the
+// credential and released to a verifier only under the holder's grant
+func TestEvidenceAttachments(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	statusRegistry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(statusRegistry)
+	verifierUC.SetStatusRegistry(statusRegistry)
+
+	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+
+	transcript := []byte("%PDF-1.7 official transcript")
+	photo := []byte("\x89PNG graduation photo")
+
+	var issued dto.IssueCredentialResponse
+	post(t, "/api/issuer/credentials", http.StatusOK, dto.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []dto.ClaimDTO{{Key: "degree", Value: "Bachelor of Science"}},
+		Evidence: []dto.EvidenceDocumentDTO{
+			{MediaType: "application/pdf", Description: "Official transcript", Document: transcript, Encrypt: true},
+			{MediaType: "image/png", Type: []string{"PhotoEvidence"}, Document: photo},
+		},
+	}, &issued)
+
+	credential := issued.Credential
+	require.NotNil(t, credential.Evidence)
+	require.Len(t, credential.Evidence.Items, 2)
+	sealed, plain := credential.Evidence.Items[0], credential.Evidence.Items[1]
+	assert.True(t, sealed.Encrypted)
+	assert.Equal(t, []string{issuer.DefaultEvidenceType}, sealed.Type)
+	assert.Equal(t, vc.DocumentDigest(transcript), sealed.DigestSRI)
+	assert.False(t, plain.Encrypted)
+	assert.Equal(t, []string{"PhotoEvidence"}, plain.Type)
+	require.Len(t, credential.EvidenceKeys, 1, "only the encrypted document has a key")
+	assert.Equal(t, sealed.ID, credential.EvidenceKeys[0].EvidenceID)
+
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	var bundle *vc.EvidenceBundle
+	t.Run("Presented Evidence Is Verified", func(t *testing.T) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"degree"}, IncludeEvidence: true},
+			},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(presentation.VerifiableCredential[0])
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "evidenceKeys", "document keys are never presented")
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"degree"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		require.Len(t, result.Evidence, 1)
+		bundle = result.Evidence[0]
+		assert.Equal(t, credential.Evidence.Items, bundle.Items)
+	})
+	require.NotNil(t, bundle)
+
+	grant := func(t *testing.T, evidenceID string) *vc.EvidenceGrant {
+		var grant vc.EvidenceGrant
+		post(t, "/api/holder/evidence/grants", http.StatusCreated, dto.GrantEvidenceAccessRequest{
+			HolderDID:    holderSetup.DID.String(),
+			CredentialID: credential.ID,
+			EvidenceID:   evidenceID,
+			VerifierDID:  verifierSetup.DID.String(),
+		}, &grant)
+		return &grant
+	}
+
+	t.Run("Encrypted Document Released Under Grant", func(t *testing.T) {
+		g := grant(t, sealed.ID)
+		require.NotNil(t, g.Key)
+
+		var release issuer.EvidenceRelease
+		post(t, "/api/issuer/evidence/release", http.StatusOK, g, &release)
+		assert.Equal(t, sealed.ID, release.Evidence.ID)
+		assert.NotEqual(t, transcript, release.Document, "the issuer releases the sealed document")
+
+		var opened dto.OpenEvidenceResponse
+		post(t, "/api/verifier/evidence/open", http.StatusOK, dto.OpenEvidenceRequest{
+			Evidence: bundle,
+			Grant:    g,
+			Document: release.Document,
+		}, &opened)
+		assert.Equal(t, transcript, opened.Document)
+		assert.Equal(t, "application/pdf", opened.MediaType)
+	})
+
+	t.Run("Plain Document Released Under Grant", func(t *testing.T) {
+		g := grant(t, plain.ID)
+		assert.Nil(t, g.Key)
+
+		var release issuer.EvidenceRelease
+		post(t, "/api/issuer/evidence/release", http.StatusOK, g, &release)
+		assert.Equal(t, photo, release.Document)
+
+		document, err := verifierUC.OpenEvidence(bundle, g, release.Document)
+		require.NoError(t, err)
+		assert.Equal(t, photo, document)
+
+		_, err = verifierUC.OpenEvidence(bundle, g, []byte("\x89PNG another photo"))
+		assert.Error(t, err, "documents must match the signed digest")
+	})
+
+	t.Run("Altered Or Foreign Grants Are Refused", func(t *testing.T) {
+		g := grant(t, plain.ID)
+		g.Verifier = "did:example:someone-else"
+		post(t, "/api/issuer/evidence/release", http.StatusForbidden, g, nil)
+
+		other, err := holderUC.SetupHolder("example")
+		require.NoError(t, err)
+		_, err = holderUC.GrantEvidenceAccess(other.DID.String(), credential.ID, plain.ID, verifierSetup.DID.String(), 0)
+		assert.Error(t, err, "only the credential's subject can grant access")
+
+		post(t, "/api/issuer/evidence/release", http.StatusForbidden, vc.EvidenceGrant{
+			CredentialID: credential.ID,
+			EvidenceID:   plain.ID,
+			Holder:       holderSetup.DID.String(),
+			Verifier:     verifierSetup.DID.String(),
+		}, nil)
+	})
+
+	t.Run("Evidence Section Is Signed", func(t *testing.T) {
+		tampered := *bundle
+		tampered.Items = append([]vc.Evidence{}, bundle.Items...)
+		tampered.Items[1].DigestSRI = vc.DocumentDigest([]byte("forged"))
+		assert.Error(t, verifierUC.VerifyEvidenceBundle(&tampered))
+	})
+}
@@ -6,28 +6,21 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/holder"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/issuer"
-	"github.com/lugondev/bbs-selective-disclosure-example/internal/verifier"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
 	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
 )
 
 // TestFullLifecycle tests the complete DID -> VC -> VP workflow
 func TestFullLifecycle(t *testing.T) {
-	// Setup services
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
-	bbsService := bbs.NewService()
-	credRepo := vc.NewInMemoryCredentialRepository()
-	presRepo := vc.NewInMemoryPresentationRepository()
-	vcService := vc.NewService(bbsService, credRepo, presRepo)
-
-	// Setup use cases
-	issuerUC := issuer.NewUseCase(didService, vcService, bbsService)
-	holderUC := holder.NewUseCase(didService, vcService, credRepo)
-	verifierUC := verifier.NewUseCase(didService, vcService, presRepo)
+	// Each role keeps its own storage
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
 
 	t.Run("Complete Selective Disclosure Workflow", func(t *testing.T) {
 		// Step 1: Setup participants
@@ -59,7 +52,7 @@ func TestFullLifecycle(t *testing.T) {
 		})
 		require.NoError(t, err)
 		assert.NotNil(t, credential)
-		assert.Equal(t, issuerSetup.DID.String(), credential.Issuer)
+		assert.Equal(t, issuerSetup.DID.String(), credential.Issuer())
 		assert.Equal(t, holderSetup.DID.String(), credential.CredentialSubject["id"])
 
 		// Step 3: Holder stores credential
@@ -101,8 +94,8 @@ func TestFullLifecycle(t *testing.T) {
 		assert.True(t, verificationResult.Valid)
 		assert.Len(t, verificationResult.Errors, 0)
 
-		// Verify only requested attributes are revealed
-		assert.Equal(t, 25, verificationResult.RevealedClaims["age"])
+		// Verify only requested attributes are revealed; integer claims are canonicalized to int64
+		assert.Equal(t, int64(25), verificationResult.RevealedClaims["age"])
 		assert.Equal(t, "American", verificationResult.RevealedClaims["nationality"])
 
 		// Verify hidden attributes are not present
@@ -114,17 +107,10 @@ func TestFullLifecycle(t *testing.T) {
 
 // TestMultipleCredentialsPresentation tests presenting multiple credentials
 func TestMultipleCredentialsPresentation(t *testing.T) {
-	// Setup
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
-	bbsService := bbs.NewService()
-	credRepo := vc.NewInMemoryCredentialRepository()
-	presRepo := vc.NewInMemoryPresentationRepository()
-	vcService := vc.NewService(bbsService, credRepo, presRepo)
-
-	issuerUC := issuer.NewUseCase(didService, vcService, bbsService)
-	holderUC := holder.NewUseCase(didService, vcService, credRepo)
-	verifierUC := verifier.NewUseCase(didService, vcService, presRepo)
+	// Each role keeps its own storage
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
 
 	// Setup participants
 	issuerSetup, err := issuerUC.SetupIssuer("test")
@@ -215,17 +201,10 @@ func TestMultipleCredentialsPresentation(t *testing.T) {
 
 // TestVerificationFailures tests various verification failure scenarios
 func TestVerificationFailures(t *testing.T) {
-	// Setup
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
-	bbsService := bbs.NewService()
-	credRepo := vc.NewInMemoryCredentialRepository()
-	presRepo := vc.NewInMemoryPresentationRepository()
-	vcService := vc.NewService(bbsService, credRepo, presRepo)
-
-	issuerUC := issuer.NewUseCase(didService, vcService, bbsService)
-	holderUC := holder.NewUseCase(didService, vcService, credRepo)
-	verifierUC := verifier.NewUseCase(didService, vcService, presRepo)
+	// Each role keeps its own storage
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
 
 	// Setup participants
 	issuerSetup, err := issuerUC.SetupIssuer("test")
@@ -321,8 +300,9 @@ func TestVerificationFailures(t *testing.T) {
 
 // TestDIDOperations tests DID creation and resolution
 func TestDIDOperations(t *testing.T) {
-	didRepo := did.NewInMemoryRepository()
-	didService := did.NewService(didRepo)
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	didRepo, didService := stack.DIDRepository, stack.DIDService
 
 	t.Run("DID Creation and Resolution", func(t *testing.T) {
 		// Generate DID
@@ -337,7 +317,7 @@ func TestDIDOperations(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, didDoc)
 		assert.Equal(t, generatedDID.String(), didDoc.ID)
-		assert.Len(t, didDoc.VerificationMethod, 1)
+		assert.Len(t, didDoc.VerificationMethod, 2) // signing key + key agreement key
 
 		// Store DID document
 		err = didRepo.Create(didDoc)
@@ -436,3 +416,59 @@ func TestBBSOperations(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// TestHolderPresentationProof tests the holder's DID signature over presentations
+func TestHolderPresentationProof(t *testing.T) {
+	// Each role keeps its own storage
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("test")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("test")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "name", Value: "Alice"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+		HolderDID:     holderSetup.DID.String(),
+		CredentialIDs: []string{credential.ID},
+		SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"name"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Ed25519Signature2020", presentation.Proof.Type)
+	assert.NotEmpty(t, presentation.Proof.ProofValue)
+
+	t.Run("Valid Holder Proof", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation: presentation,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+	})
+
+	t.Run("Holder Impersonation", func(t *testing.T) {
+		otherHolder, err := holderUC.SetupHolder("test")
+		require.NoError(t, err)
+
+		forged := *presentation
+		forged.Holder = otherHolder.DID.String()
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation: &forged,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "holder proof verification failed")
+	})
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/health"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+// TestHealthChecks tests /health reporting component status with probe-friendly response codes
+func TestHealthChecks(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	get := func(t *testing.T) (int, dto.HealthResponse) {
+		resp, err := http.Get(ts.URL + "/health")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body dto.HealthResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	t.Run("Static Without Checker", func(t *testing.T) {
+		code, body := get(t)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "healthy", body.Status)
+		assert.Empty(t, body.Components)
+	})
+
+	checker, err := health.NewChecker(health.DefaultConfig())
+	require.NoError(t, err)
+	require.NoError(t, checker.Register("crypto.selfTest", true, func(ctx context.Context) error {
+		return bbs.SelfTest(stack.BBSService)
+	}))
+	for name, store := range map[string]interface{}{"storage.dids": stack.DIDRepository, "storage.credentials": stack.Holder.Credentials, "storage.presentations": stack.Verifier.Presentations} {
+		pinger, ok := store.(health.Pinger)
+		require.True(t, ok, "%s supports ping", name)
+		require.NoError(t, checker.Register(name, true, health.PingCheck(pinger)))
+	}
+	server.SetHealthChecker(checker)
+
+	t.Run("Components Reported", func(t *testing.T) {
+		code, body := get(t)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "healthy", body.Status)
+		require.Len(t, body.Components, 4)
+		for _, component := range body.Components {
+			assert.Equal(t, health.StatusHealthy, component.Status, component.Name)
+		}
+	})
+
+	t.Run("Degraded Still Serves", func(t *testing.T) {
+		require.NoError(t, checker.Register("anchoring", false, func(ctx context.Context) error {
+			return errors.New("anchor node unreachable")
+		}))
+
+		code, body := get(t)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "degraded", body.Status)
+	})
+
+	t.Run("Critical Failure Unavailable", func(t *testing.T) {
+		require.NoError(t, checker.Register("storage.archive", true, func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}))
+
+		code, body := get(t)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "unhealthy", body.Status)
+	})
+
+	t.Run("Readiness Waits For Startup Tasks", func(t *testing.T) {
+		probe := func(t *testing.T, path string) (int, dto.ReadinessResponse) {
+			resp, err := http.Get(ts.URL + path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			var body dto.ReadinessResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			return resp.StatusCode, body
+		}
+
+		readiness := health.NewReadiness()
+		finishMigrations, err := readiness.Start("storage.migrations")
+		require.NoError(t, err)
+		require.NoError(t, readiness.Go("generators", bbs.Warmup))
+		server.SetReadiness(readiness)
+
+		code, body := probe(t, "/ready")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not ready", body.Status)
+
+		// Liveness never waits for startup
+		code, live := probe(t, "/live")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "alive", live.Status)
+
+		finishMigrations(nil)
+		require.Eventually(t, func() bool {
+			code, _ := probe(t, "/ready")
+			return code == http.StatusOK
+		}, 5*time.Second, 10*time.Millisecond)
+
+		_, body = probe(t, "/ready")
+		assert.Equal(t, "ready", body.Status)
+		require.Len(t, body.Tasks, 2)
+		for _, task := range body.Tasks {
+			assert.Equal(t, health.TaskDone, task.State, task.Name)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestHolderBinding tests binding credentials to the holder's key with a cnf claim, so a copied
+// credential cannot be presented without a proof of possession of that key
+func TestHolderBinding(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	loader, err := jsonld.NewBundledLoader()
+	require.NoError(t, err)
+	validator := jsonld.NewValidator(loader)
+	holderUC.SetContextValidator(validator)
+	verifierUC.SetContextValidator(validator)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	attackerSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	attackerDID := attackerSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:    issuerSetup.DID.String(),
+		SubjectDID:   holderDID,
+		Claims:       []vc.Claim{{Key: "ageOver18", Value: true}},
+		BindToHolder: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"ageOver18"},
+			Strict:         true,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Credential Carries The Holder Key", func(t *testing.T) {
+		confirmation, err := credential.Confirmation()
+		require.NoError(t, err)
+		require.NotNil(t, confirmation)
+		assert.Equal(t, holderSetup.DIDDoc.Authentication[0], confirmation.KeyID)
+		assert.Equal(t, holderSetup.DIDDoc.VerificationMethod[0].PublicKeyMultibase, confirmation.PublicKeyMultibase)
+	})
+
+	t.Run("Holder Presents With Proof Of Possession", func(t *testing.T) {
+		presentation := present(t)
+		assert.Equal(t, holderSetup.DIDDoc.Authentication[0], presentation.Proof.VerificationMethod)
+
+		assert.Contains(t, presentation.VerifiableCredential[0].CredentialSubject, vc.ConfirmationClaim)
+
+		result := verify(t, presentation)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+		assert.NotContains(t, result.RevealedClaims, vc.ConfirmationClaim)
+		for _, warning := range result.Warnings {
+			assert.NotEqual(t, lint.CodeExtraRevealedAttribute, warning.Code, "cnf is not an extra revealed attribute")
+		}
+	})
+
+	t.Run("Unsigned Presentation Is Rejected", func(t *testing.T) {
+		presentation := present(t)
+		presentation.Proof.ProofValue = ""
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "holder binding check failed")
+		assert.Contains(t, result.Errors[0], "presentation is not signed")
+	})
+
+	t.Run("Copied Credential Presented By Someone Else", func(t *testing.T) {
+		// The attacker re-signs a copy of the presentation under their own DID
+		presentation := present(t)
+		presentation.Holder = attackerDID
+		presentation.Proof.VerificationMethod = attackerSetup.DIDDoc.Authentication[0]
+		presentation.Proof.ProofValue = ""
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "holder binding check failed")
+	})
+
+	t.Run("Pairwise DID Is Refused", func(t *testing.T) {
+		_, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			VerifierDID:    "did:example:verifier",
+			UsePairwiseDID: true,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Holder Proof Required By The Request", func(t *testing.T) {
+		// An unbound credential needs no proof of possession, unless the request asks for one
+		unbound, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderDID,
+			Claims:     []vc.Claim{{Key: "isStudent", Value: true}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(unbound))
+
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{unbound.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: unbound.ID, RevealedAttributes: []string{"isStudent"}},
+			},
+			Nonce: "binding-nonce-d273eda0",
+		})
+		require.NoError(t, err)
+		signed := presentation.Proof.ProofValue
+
+		for name, request := range map[string]verifier.VerificationRequest{
+			"nonce":          {RequiredClaims: []string{"isStudent"}, VerificationNonce: "binding-nonce-d273eda0"},
+			"holder binding": {RequiredClaims: []string{"isStudent"}, HolderBinding: true},
+		} {
+			request.Presentation = presentation
+			presentation.Proof.ProofValue = signed
+			result, err := verifierUC.VerifyPresentation(request)
+			require.NoError(t, err)
+			assert.True(t, result.Valid, "%s: %v", name, result.Errors)
+
+			presentation.Proof.ProofValue = ""
+			result, err = verifierUC.VerifyPresentation(request)
+			require.NoError(t, err)
+			assert.False(t, result.Valid, name)
+			require.NotEmpty(t, result.Errors, name)
+			assert.Contains(t, result.Errors[0], "presentation is not signed by its holder", name)
+		}
+	})
+
+	t.Run("Reserved Claim", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderDID,
+			Claims:     []vc.Claim{{Key: vc.ConfirmationClaim, Value: "did:example:someone#key-1"}},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,218 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestIssuanceApproval tests that issuance requests wait in the approval queue and only approved
+// requests are signed
+func TestIssuanceApproval(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	statusRegistry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(statusRegistry)
+	verifierUC.SetStatusRegistry(statusRegistry)
+	issuerUC.SetApprovalRequired(true)
+
+	var eventsMu sync.Mutex
+	var events []issuer.ApprovalStatus
+	issuerUC.OnApprovalChange(func(approval issuer.IssuanceApproval) {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		events = append(events, approval.Status)
+	})
+
+	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) *http.Response {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+		return resp
+	}
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	submit := func(t *testing.T) issuer.IssuanceApproval {
+		var approval issuer.IssuanceApproval
+		resp := post(t, "/api/issuer/credentials", http.StatusAccepted, dto.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims: []dto.ClaimDTO{
+				{Key: "degree", Value: "Bachelor of Science"},
+				{Key: "graduationYear", Value: 2025},
+			},
+			RequestedBy: "registrar@example.edu",
+		}, &approval)
+		assert.Equal(t, "/api/issuer/approvals/"+approval.ID, resp.Header.Get("Location"))
+		return approval
+	}
+
+	t.Run("Approved Request Is Signed", func(t *testing.T) {
+		approval := submit(t)
+		assert.Equal(t, issuer.ApprovalPending, approval.Status)
+		assert.Equal(t, "registrar@example.edu", approval.RequestedBy)
+		assert.Nil(t, approval.Credential)
+
+		resp, err := http.Get(ts.URL + "/api/issuer/approvals?status=pending")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var pending []issuer.IssuanceApproval
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pending))
+		require.Len(t, pending, 1)
+		assert.Equal(t, approval.ID, pending[0].ID)
+
+		var commented issuer.IssuanceApproval
+		post(t, "/api/issuer/approvals/"+approval.ID+"/comments", http.StatusOK,
+			dto.ApprovalCommentRequest{Author: "clerk@example.edu", Text: "Transcript attached"}, &commented)
+		require.Len(t, commented.Comments, 1)
+
+		var issued issuer.IssuanceApproval
+		post(t, "/api/issuer/approvals/"+approval.ID+"/approve", http.StatusOK,
+			dto.ApprovalDecisionRequest{Approver: "dean@example.edu", Comment: "Transcript checked"}, &issued)
+		assert.Equal(t, issuer.ApprovalIssued, issued.Status)
+		assert.Equal(t, "dean@example.edu", issued.DecidedBy)
+		require.NotNil(t, issued.DecidedAt)
+		assert.Len(t, issued.Comments, 2)
+		require.NotNil(t, issued.Credential)
+		assert.EqualValues(t, 2025, issued.Credential.CredentialSubject["graduationYear"])
+
+		// The signed credential yields presentations the verifier accepts
+		require.NoError(t, holderUC.StoreCredential(issued.Credential))
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{issued.Credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: issued.Credential.ID, RevealedAttributes: []string{"graduationYear"}},
+			},
+		})
+		require.NoError(t, err)
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"graduationYear"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+
+		// A decided request cannot be decided again or commented on
+		post(t, "/api/issuer/approvals/"+approval.ID+"/reject", http.StatusBadRequest,
+			dto.ApprovalDecisionRequest{Approver: "dean@example.edu", Comment: "Changed my mind"}, nil)
+		post(t, "/api/issuer/approvals/"+approval.ID+"/comments", http.StatusBadRequest,
+			dto.ApprovalCommentRequest{Author: "clerk@example.edu", Text: "Too late"}, nil)
+	})
+
+	t.Run("Rejection Requires A Comment", func(t *testing.T) {
+		approval := submit(t)
+
+		post(t, "/api/issuer/approvals/"+approval.ID+"/reject", http.StatusBadRequest,
+			dto.ApprovalDecisionRequest{Approver: "dean@example.edu"}, nil)
+
+		var rejected issuer.IssuanceApproval
+		post(t, "/api/issuer/approvals/"+approval.ID+"/reject", http.StatusOK,
+			dto.ApprovalDecisionRequest{Approver: "dean@example.edu", Comment: "Degree not conferred yet"}, &rejected)
+		assert.Equal(t, issuer.ApprovalRejected, rejected.Status)
+		assert.Nil(t, rejected.Credential)
+
+		post(t, "/api/issuer/approvals/"+approval.ID+"/approve", http.StatusBadRequest,
+			dto.ApprovalDecisionRequest{Approver: "dean@example.edu"}, nil)
+	})
+
+	t.Run("Only One Concurrent Decision Counts", func(t *testing.T) {
+		approval, err := issuerUC.SubmitIssuance(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "degree", Value: "Master of Arts"}},
+		}, "")
+		require.NoError(t, err)
+
+		const approvers = 8
+		var wg sync.WaitGroup
+		errs := make([]error, approvers)
+		for i := 0; i < approvers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = issuerUC.ApproveIssuance(context.Background(), approval.ID, "dean@example.edu", "")
+			}(i)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		stored, err := issuerUC.GetApproval(approval.ID)
+		require.NoError(t, err)
+		assert.Equal(t, issuer.ApprovalIssued, stored.Status)
+	})
+
+	t.Run("Invalid Requests Are Not Queued", func(t *testing.T) {
+		post(t, "/api/issuer/credentials", http.StatusBadRequest, dto.IssueCredentialRequest{
+			IssuerDID:  "did:example:unknown",
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []dto.ClaimDTO{{Key: "degree", Value: "Bachelor of Science"}},
+		}, nil)
+
+		resp, err := http.Get(ts.URL + "/api/issuer/approvals/does-not-exist")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Batches Are Refused", func(t *testing.T) {
+		_, err := issuerUC.StartBatchIssuance(issuer.BatchIssueRequest{
+			IssuerDID: issuerSetup.DID.String(),
+			Items: []issuer.BatchItem{
+				{SubjectDID: holderSetup.DID.String(), Claims: []vc.Claim{{Key: "degree", Value: "Bachelor of Science"}}},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "approval")
+	})
+
+	t.Run("Hooks See Every Change", func(t *testing.T) {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+		assert.Contains(t, events, issuer.ApprovalPending)
+		assert.Contains(t, events, issuer.ApprovalApproved)
+		assert.Contains(t, events, issuer.ApprovalIssued)
+		assert.Contains(t, events, issuer.ApprovalRejected)
+	})
+}
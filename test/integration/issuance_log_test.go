@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestIssuanceLog tests that issued credentials are provably logged without revealing their contents
+func TestIssuanceLog(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC := stack.Issuer.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	empty, err := issuerUC.GetSignedTreeHead(issuerDID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), empty.TreeSize)
+
+	var credentials []*vc.VerifiableCredential
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: "did:example:" + name,
+			Claims:     []vc.Claim{{Key: "firstName", Value: name}},
+		})
+		require.NoError(t, err)
+		credentials = append(credentials, credential)
+	}
+
+	head, err := issuerUC.GetSignedTreeHead(issuerDID)
+	require.NoError(t, err)
+	assert.Equal(t, issuerDID, head.LogID)
+	assert.Equal(t, uint64(3), head.TreeSize)
+
+	t.Run("Holder Proves Issuance", func(t *testing.T) {
+		// The holder hashes the credential as received over the wire
+		data, err := json.Marshal(credentials[1])
+		require.NoError(t, err)
+		var received vc.VerifiableCredential
+		require.NoError(t, json.Unmarshal(data, &received))
+
+		hash, err := vc.CredentialHash(&received)
+		require.NoError(t, err)
+
+		proof, err := issuerUC.GetInclusionProof("", hash, head.TreeSize)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), proof.Entry.Index)
+		assert.True(t, proof.Entry.Timestamp.Equal(credentials[1].IssuanceDate))
+
+		require.NoError(t, issuerUC.VerifyInclusionProof(proof, head))
+	})
+
+	t.Run("Older Tree Heads Stay Valid", func(t *testing.T) {
+		hash, err := vc.CredentialHash(credentials[0])
+		require.NoError(t, err)
+
+		proof, err := issuerUC.GetInclusionProof(issuerDID, hash, 0)
+		require.NoError(t, err)
+
+		_, err = issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: "did:example:Dave",
+			Claims:     []vc.Claim{{Key: "firstName", Value: "Dave"}},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, issuerUC.VerifyInclusionProof(proof, head))
+
+		latest, err := issuerUC.GetSignedTreeHead(issuerDID)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), latest.TreeSize)
+		assert.NotEqual(t, head.RootHash, latest.RootHash)
+		assert.Error(t, issuerUC.VerifyInclusionProof(proof, latest))
+	})
+
+	t.Run("Forged Tree Head", func(t *testing.T) {
+		hash, err := vc.CredentialHash(credentials[2])
+		require.NoError(t, err)
+		proof, err := issuerUC.GetInclusionProof(issuerDID, hash, head.TreeSize)
+		require.NoError(t, err)
+
+		forged := *head
+		forged.TreeSize = 2
+		assert.Error(t, issuerUC.VerifyInclusionProof(proof, &forged))
+
+		forged = *head
+		forged.RootHash = empty.RootHash
+		err = issuerUC.VerifyInclusionProof(proof, &forged)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "signature verification failed")
+	})
+
+	t.Run("Unknown Credential", func(t *testing.T) {
+		_, err := issuerUC.GetInclusionProof(issuerDID, "deadbeef", 0)
+		assert.Error(t, err)
+	})
+}
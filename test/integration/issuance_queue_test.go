@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// unavailableSigner fails its first signatures as an unreachable KMS would
+type unavailableSigner struct {
+	bbs.BBSService
+	failures atomic.Int32
+}
+
+func (s *unavailableSigner) Sign(privateKey []byte, messages [][]byte) (*bbs.Signature, error) {
+	if s.failures.Add(-1) >= 0 {
+		return nil, bbs.ErrSignerUnavailable
+	}
+	return s.BBSService.Sign(privateKey, messages)
+}
+
+// TestIssuanceQueue tests that queued issuance requests are issued in the background, that
+// attempts failing for transient reasons are retried with backoff, and that clients poll jobs
+func TestIssuanceQueue(t *testing.T) {
+	waitForJob := func(t *testing.T, get func() (*issuer.IssuanceJob, error)) *issuer.IssuanceJob {
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			job, err := get()
+			require.NoError(t, err)
+			if job.Done() {
+				return job
+			}
+			require.True(t, time.Now().Before(deadline), "issuance job did not finish")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	t.Run("Retries Transient Failures", func(t *testing.T) {
+		signer := &unavailableSigner{BBSService: bbs.NewService()}
+		stack, err := sdk.NewStack(sdk.Config{BBS: signer})
+		require.NoError(t, err)
+		issuerUC := stack.Issuer.UseCase
+		require.NoError(t, issuerUC.SetIssuanceRetries(3, 10*time.Millisecond))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go issuerUC.RunIssuanceQueue(ctx, 2)
+
+		issuerSetup, err := issuerUC.SetupIssuer("example")
+		require.NoError(t, err)
+		request := issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: "did:example:member",
+			Claims:     []vc.Claim{{Key: "tier", Value: "gold"}},
+		}
+
+		signer.failures.Store(2)
+		job, err := issuerUC.EnqueueIssuance(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, issuer.IssuanceQueued, job.Status)
+
+		job = waitForJob(t, func() (*issuer.IssuanceJob, error) { return issuerUC.GetIssuanceJob(job.ID) })
+		assert.Equal(t, issuer.IssuanceCompleted, job.Status, job.Error)
+		assert.Equal(t, 3, job.Attempts)
+		require.NotNil(t, job.Credential)
+		assert.Equal(t, job.CredentialID, job.Credential.ID)
+		assert.NoError(t, issuerUC.VerifyCredential(job.Credential))
+
+		// Attempts run out while the signer stays unavailable
+		signer.failures.Store(10)
+		job, err = issuerUC.EnqueueIssuance(context.Background(), request)
+		require.NoError(t, err)
+		job = waitForJob(t, func() (*issuer.IssuanceJob, error) { return issuerUC.GetIssuanceJob(job.ID) })
+		assert.Equal(t, issuer.IssuanceFailed, job.Status)
+		assert.Equal(t, 3, job.Attempts)
+		assert.Contains(t, job.Error, bbs.ErrSignerUnavailable.Error())
+	})
+
+	t.Run("Queued Over HTTP", func(t *testing.T) {
+		stack, err := sdk.NewStack(sdk.Config{})
+		require.NoError(t, err)
+		server, err := httpServer.NewStackServer(stack, "0")
+		require.NoError(t, err)
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go stack.Issuer.RunIssuanceQueue(ctx, 1)
+
+		issuerSetup, err := stack.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+
+		enqueue := func(t *testing.T, req dto.IssueCredentialRequest) (*http.Response, issuer.IssuanceJob) {
+			data, err := json.Marshal(req)
+			require.NoError(t, err)
+			resp, err := http.Post(ts.URL+"/api/issuer/issuance-jobs", "application/json", bytes.NewReader(data))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			var job issuer.IssuanceJob
+			if resp.StatusCode == http.StatusAccepted {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+			}
+			return resp, job
+		}
+		resp, job := enqueue(t, dto.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: "did:example:member",
+			Claims:     []dto.ClaimDTO{{Key: "tier", Value: "silver"}},
+		})
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assert.Equal(t, "/api/issuer/issuance-jobs/"+job.ID, resp.Header.Get("Location"))
+
+		done := waitForJob(t, func() (*issuer.IssuanceJob, error) {
+			resp, err := http.Get(ts.URL + "/api/issuer/issuance-jobs/" + job.ID)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			var polled issuer.IssuanceJob
+			err = json.NewDecoder(resp.Body).Decode(&polled)
+			return &polled, err
+		})
+		assert.Equal(t, issuer.IssuanceCompleted, done.Status, done.Error)
+		require.NotNil(t, done.Credential)
+		assert.Equal(t, "silver", done.Credential.CredentialSubject["tier"])
+
+		// Invalid requests are rejected before they are queued
+		resp, _ = enqueue(t, dto.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: "did:example:member",
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		missing, err := http.Get(ts.URL + "/api/issuer/issuance-jobs/unknown")
+		require.NoError(t, err)
+		missing.Body.Close()
+		assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+	})
+}
@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestIssuanceRegistry tests listing and retrieving the credentials an issuer issued, filtered by
+// subject, type and issuance date
+func TestIssuanceRegistry(t *testing.T) {
+	start := time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+	mock := clock.NewMock(start)
+
+	stack, err := sdk.NewStack(sdk.Config{Clock: mock})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	otherIssuer, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	alice, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	bob, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	issue := func(t *testing.T, issuerDID, subjectDID, templateID string, claims []vc.Claim) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: subjectDID,
+			Claims:     claims,
+			TemplateID: templateID,
+		})
+		require.NoError(t, err)
+		return credential
+	}
+
+	degree := []vc.Claim{{Key: "degree", Value: "BSc"}, {Key: "university", Value: "Example University"}}
+
+	// Credentials issued on consecutive days
+	first := issue(t, issuerDID, alice.DID.String(), "university-degree", degree)
+	mock.Advance(24 * time.Hour)
+	second := issue(t, issuerDID, alice.DID.String(), "", []vc.Claim{{Key: "ageOver18", Value: true}})
+	mock.Advance(24 * time.Hour)
+	third := issue(t, issuerDID, bob.DID.String(), "university-degree", degree)
+	issue(t, otherIssuer.DID.String(), alice.DID.String(), "", []vc.Claim{{Key: "ageOver21", Value: true}})
+
+	_, err = issuerUC.RevokeCredential(second.ID, issuer.StatusChange{})
+	require.NoError(t, err)
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	list := func(t *testing.T, params url.Values) []dto.IssuedCredentialDTO {
+		resp, err := http.Get(ts.URL + "/api/issuer/credentials?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response dto.ListIssuedCredentialsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return response.Credentials
+	}
+
+	ids := func(records []dto.IssuedCredentialDTO) []string {
+		result := make([]string, len(records))
+		for i, record := range records {
+			result[i] = record.CredentialID
+		}
+		return result
+	}
+
+	t.Run("All Credentials Of An Issuer", func(t *testing.T) {
+		records := list(t, url.Values{"issuerDid": {issuerDID}})
+		assert.Equal(t, []string{first.ID, second.ID, third.ID}, ids(records))
+
+		assert.Equal(t, alice.DID.String(), records[0].SubjectDID)
+		assert.Contains(t, records[0].Types, "UniversityDegreeCredential")
+		assert.Equal(t, start, records[0].IssuedAt)
+		require.NotNil(t, records[0].Credential)
+		assert.Equal(t, first.ID, records[0].Credential.ID)
+
+		require.NotNil(t, records[1].Status)
+		assert.True(t, records[1].Status.Revoked)
+		require.NotNil(t, records[0].Status)
+		assert.False(t, records[0].Status.Revoked)
+	})
+
+	t.Run("Filter By Subject", func(t *testing.T) {
+		records := list(t, url.Values{"issuerDid": {issuerDID}, "subjectDid": {alice.DID.String()}})
+		assert.Equal(t, []string{first.ID, second.ID}, ids(records))
+	})
+
+	t.Run("Filter By Type", func(t *testing.T) {
+		records := list(t, url.Values{"issuerDid": {issuerDID}, "type": {"UniversityDegreeCredential"}})
+		assert.Equal(t, []string{first.ID, third.ID}, ids(records))
+	})
+
+	t.Run("Filter By Date", func(t *testing.T) {
+		records := list(t, url.Values{"issuerDid": {issuerDID}, "issuedAfter": {"2030-03-02"}})
+		assert.Equal(t, []string{second.ID, third.ID}, ids(records))
+
+		records = list(t, url.Values{"issuerDid": {issuerDID}, "issuedBefore": {"2030-03-02"}})
+		assert.Equal(t, []string{first.ID, second.ID}, ids(records))
+
+		records = list(t, url.Values{"issuerDid": {issuerDID}, "issuedAfter": {"2030-03-01T12:00:00Z"}, "issuedBefore": {"2030-03-02T12:00:00Z"}})
+		assert.Equal(t, []string{second.ID}, ids(records))
+	})
+
+	t.Run("Retrieve One", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/issuer/credentials/" + third.ID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var record dto.IssuedCredentialDTO
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&record))
+		assert.Equal(t, third.ID, record.CredentialID)
+		assert.Equal(t, bob.DID.String(), record.SubjectDID)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for name, path := range map[string]string{
+			"Missing Issuer":     "/api/issuer/credentials",
+			"Invalid Date":       "/api/issuer/credentials?issuerDid=" + url.QueryEscape(issuerDID) + "&issuedAfter=yesterday",
+			"Inverted Range":     "/api/issuer/credentials?issuerDid=" + url.QueryEscape(issuerDID) + "&issuedAfter=2030-03-03&issuedBefore=2030-03-01",
+			"Unknown Credential": "/api/issuer/credentials/unknown",
+		} {
+			resp, err := http.Get(ts.URL + path)
+			require.NoError(t, err, name)
+			resp.Body.Close()
+
+			expected := http.StatusBadRequest
+			if name == "Unknown Credential" {
+				expected = http.StatusNotFound
+			}
+			assert.Equal(t, expected, resp.StatusCode, name)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestIssuerDelegation tests that verifiers accept a sub-issuer's credentials only when a valid,
+// unrevoked chain of authorizations back to a trusted root is presented with them
+func TestIssuerDelegation(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	setup := func() string {
+		s, err := stack.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+		return s.DID.String()
+	}
+	ministry, university, faculty := setup(), setup(), setup()
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	// The ministry lets the university issue degrees and authorize faculties to issue them
+	var universityAuthorization vc.VerifiableCredential
+	post(t, "/api/issuer/authorizations", http.StatusCreated, dto.AuthorizeIssuerRequest{
+		IssuerDID:       ministry,
+		SubIssuerDID:    university,
+		CredentialTypes: []string{"UniversityDegreeCredential"},
+		MayDelegate:     true,
+	}, &universityAuthorization)
+	post(t, "/api/issuer/authorizations/accept", http.StatusOK, universityAuthorization, nil)
+
+	var facultyAuthorization vc.VerifiableCredential
+	post(t, "/api/issuer/authorizations", http.StatusCreated, dto.AuthorizeIssuerRequest{
+		IssuerDID:    university,
+		SubIssuerDID: faculty,
+	}, &facultyAuthorization)
+	require.Len(t, facultyAuthorization.Authorizations, 1, "the university's own authorization travels with it")
+	post(t, "/api/issuer/authorizations/accept", http.StatusOK, facultyAuthorization, nil)
+
+	t.Run("Sub-Issuer Without Delegation Cannot Authorize", func(t *testing.T) {
+		post(t, "/api/issuer/authorizations", http.StatusBadRequest, dto.AuthorizeIssuerRequest{
+			IssuerDID:    faculty,
+			SubIssuerDID: setup(),
+		}, nil)
+	})
+
+	issue := func(t *testing.T, templateID string, claims []vc.Claim) *vc.VerifiablePresentation {
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  faculty,
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     claims,
+			TemplateID: templateID,
+		})
+		require.NoError(t, err)
+		require.Len(t, credential.Authorizations, 2)
+		require.NoError(t, stack.Holder.StoreCredential(credential))
+
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{claims[0].Key}},
+			},
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	degree := issue(t, "university-degree", []vc.Claim{
+		{Key: "degree", Value: "Bachelor of Science"},
+		{Key: "university", Value: "Example University"},
+	})
+	require.Len(t, degree.Authorizations, 2)
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, roots ...string) *verifier.VerificationResult {
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation: presentation,
+			TrustedRoots: roots,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Chain To Trusted Root Is Accepted", func(t *testing.T) {
+		result := verify(t, degree, ministry)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, [][]string{{faculty, university, ministry}}, result.DelegationChains)
+	})
+
+	t.Run("Untrusted Root Is Refused", func(t *testing.T) {
+		result := verify(t, degree, setup())
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Missing Chain Is Refused", func(t *testing.T) {
+		stripped := *degree
+		stripped.Authorizations = stripped.Authorizations[:1]
+		result := verify(t, &stripped, ministry)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Unauthorized Type Is Refused", func(t *testing.T) {
+		nationalID := issue(t, "national-id", []vc.Claim{
+			{Key: "firstName", Value: "An"},
+			{Key: "lastName", Value: "Nguyen"},
+			{Key: "dateOfBirth", Value: "1990-01-01"},
+			{Key: "nationality", Value: "VN"},
+			{Key: "idNumber", Value: "001090000001"},
+		})
+		result := verify(t, nationalID, ministry)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "not authorized to issue")
+	})
+
+	t.Run("Revoked Authorization Breaks The Chain", func(t *testing.T) {
+		_, err := stack.Issuer.RevokeCredential(universityAuthorization.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+
+		result := verify(t, degree, ministry)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "revoked")
+	})
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestIssuerMetadata tests issuer metadata discovery and template-based issuance
+func TestIssuerMetadata(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC := stack.Issuer.UseCase
+
+	t.Run("No Issuer", func(t *testing.T) {
+		_, err := issuerUC.GetIssuerMetadata("")
+		assert.Error(t, err)
+	})
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	t.Run("Metadata From Templates", func(t *testing.T) {
+		metadata, err := issuerUC.GetIssuerMetadata("")
+		require.NoError(t, err)
+
+		assert.Equal(t, issuerSetup.DID.String(), metadata.CredentialIssuer)
+		assert.Len(t, metadata.CredentialConfigurationsSupported, len(issuerUC.ListTemplates()))
+
+		config, ok := metadata.CredentialConfigurationsSupported["national-id"]
+		require.True(t, ok)
+		assert.Equal(t, []string{"VerifiableCredential", "NationalIDCredential"}, config.Types)
+		assert.NotEmpty(t, config.Claims)
+		assert.NotEmpty(t, config.Display)
+
+		require.Len(t, metadata.PublicKeys, 1)
+		assert.Equal(t, issuerSetup.BBSKeyPair.PublicKey, base58.Decode(metadata.PublicKeys[0].PublicKeyBase58))
+	})
+
+	t.Run("Unknown Issuer", func(t *testing.T) {
+		_, err := issuerUC.GetIssuerMetadata("did:example:unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("Template Issuance", func(t *testing.T) {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: "did:example:holder",
+			TemplateID: "university-degree",
+			Claims: []vc.Claim{
+				{Key: "degree", Value: "Bachelor of Science"},
+				{Key: "university", Value: "HCMUS"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, credential.Type, "UniversityDegreeCredential")
+	})
+
+	t.Run("Template Missing Required Claim", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: "did:example:holder",
+			TemplateID: "university-degree",
+			Claims:     []vc.Claim{{Key: "degree", Value: "Bachelor of Science"}},
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "required claim university")
+	})
+
+	t.Run("Template Unknown Claim", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: "did:example:holder",
+			TemplateID: "university-degree",
+			Claims: []vc.Claim{
+				{Key: "degree", Value: "Bachelor of Science"},
+				{Key: "university", Value: "HCMUS"},
+				{Key: "favouriteColour", Value: "blue"},
+			},
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not defined by template")
+	})
+
+	t.Run("Multiple Issuers Require DID", func(t *testing.T) {
+		_, err := issuerUC.SetupIssuer("example")
+		require.NoError(t, err)
+
+		_, err = issuerUC.GetIssuerMetadata("")
+		assert.Error(t, err)
+
+		metadata, err := issuerUC.GetIssuerMetadata(issuerSetup.DID.String())
+		require.NoError(t, err)
+		assert.Equal(t, issuerSetup.DID.String(), metadata.CredentialIssuer)
+	})
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestIssuerObject tests issuing credentials whose issuer is an object with a name and image,
+// and verifying them against trusted issuer DIDs
+func TestIssuerObject(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	loader, err := jsonld.NewBundledLoader()
+	require.NoError(t, err)
+	validator := jsonld.NewValidator(loader)
+	holderUC.SetContextValidator(validator)
+	verifierUC.SetContextValidator(validator)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	for _, version := range []vc.Version{vc.Version1, vc.Version2} {
+		t.Run("Data Model "+string(version), func(t *testing.T) {
+			credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+				IssuerDID:   issuerDID,
+				SubjectDID:  holderSetup.DID.String(),
+				Claims:      []vc.Claim{{Key: "degree", Value: "BSc"}},
+				Version:     version,
+				IssuerName:  "Example University",
+				IssuerImage: "https://example.edu/logo.png",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, issuerDID, credential.Issuer())
+
+			data, err := json.Marshal(credential)
+			require.NoError(t, err)
+			var document map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &document))
+			assert.Equal(t, map[string]interface{}{
+				"id":    issuerDID,
+				"name":  "Example University",
+				"image": "https://example.edu/logo.png",
+			}, document["issuer"])
+
+			// Storing checks the issuer object expands under the credential contexts
+			require.NoError(t, holderUC.StoreCredential(credential))
+
+			presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+				HolderDID:     holderSetup.DID.String(),
+				CredentialIDs: []string{credential.ID},
+				SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+					{CredentialID: credential.ID, RevealedAttributes: []string{"degree"}},
+				},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "Example University", presentation.VerifiableCredential[0].Issuer.Name)
+
+			result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+				Presentation:   presentation,
+				RequiredClaims: []string{"degree"},
+				TrustedIssuers: []string{issuerDID},
+			})
+			require.NoError(t, err)
+			assert.True(t, result.Valid, "errors: %v", result.Errors)
+			assert.Equal(t, []string{issuerDID}, result.IssuerDIDs)
+		})
+	}
+
+	t.Run("Untrusted Issuer Object", func(t *testing.T) {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "isAlumnus", Value: true}},
+			IssuerName: "Example University",
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"isAlumnus"}},
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"isAlumnus"},
+			TrustedIssuers: []string{"did:example:someone-else"},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+}
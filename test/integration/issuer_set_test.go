@@ -0,0 +1,208 @@
+package integration
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestIssuerAnonymitySets tests presenting a credential that proves it came from one of a set of issuers without revealing which
+func TestIssuerAnonymitySets(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	// Three provincial authorities share one anonymity set
+	var authorities []string
+	for _, name := range []string{"provincea", "provinceb", "provincec"} {
+		setup, err := issuerUC.SetupIssuer(name)
+		require.NoError(t, err)
+		authorities = append(authorities, setup.DID.String())
+	}
+	sortedAuthorities := append([]string{}, authorities...)
+	sort.Strings(sortedAuthorities)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	issue := func(t *testing.T, anonymitySet []string, adult bool) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:    authorities[1],
+			SubjectDID:   holderDID,
+			Claims:       []vc.Claim{{Key: "ageOver18", Value: adult}},
+			AnonymitySet: anonymitySet,
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	present := func(t *testing.T, credential *vc.VerifiableCredential) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}, HideIssuer: true},
+			},
+			Nonce: "anonymity-nonce-7056bc03",
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, trusted []string) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			TrustedIssuers:    trusted,
+			VerificationNonce: "anonymity-nonce-7056bc03",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	credential := issue(t, []string{authorities[0], authorities[2]}, true)
+	require.NotNil(t, credential.IssuerSetProof)
+	assert.Equal(t, sortedAuthorities, credential.IssuerSetProof.Issuers)
+	assert.Empty(t, credential.CredentialStatus, "status lists would identify the issuer")
+
+	t.Run("Hidden Issuer Verifies", func(t *testing.T) {
+		presentation := present(t, credential)
+
+		// Nothing in the presented credential names the issuer or its key
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		var decoded vc.VerifiablePresentation
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.NotContains(t, string(data), `"issuer"`)
+		derived := decoded.VerifiableCredential[0]
+		assert.Nil(t, derived.Issuer)
+		assert.Empty(t, derived.Proof.VerificationMethod)
+
+		result := verify(t, &decoded, authorities)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Empty(t, result.IssuerDIDs)
+		assert.Equal(t, [][]string{sortedAuthorities}, result.IssuerSets)
+		assert.Equal(t, true, result.RevealedClaims["ageOver18"])
+	})
+
+	t.Run("Every Member Must Be Trusted", func(t *testing.T) {
+		result := verify(t, present(t, credential), authorities[:2])
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "is not trusted")
+	})
+
+	t.Run("Fresh Membership Proof Per Presentation", func(t *testing.T) {
+		first := present(t, credential).VerifiableCredential[0].IssuerSetProof.ProofValue
+		second := present(t, credential).VerifiableCredential[0].IssuerSetProof.ProofValue
+		require.NotNil(t, first)
+		require.NotNil(t, second)
+		assert.NotEqual(t, first.Key, second.Key)
+	})
+
+	// resign has the holder sign over an altered presentation so only the issuer set proof can catch it
+	resign := func(t *testing.T, presentation *vc.VerifiablePresentation) {
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+	}
+
+	t.Run("Forged Claim", func(t *testing.T) {
+		minor := issue(t, []string{authorities[0], authorities[2]}, false)
+		presentation := present(t, minor)
+		presentation.VerifiableCredential[0].CredentialSubject["ageOver18"] = true
+		resign(t, presentation)
+
+		result := verify(t, presentation, nil)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "selective disclosure verification failed")
+	})
+
+	t.Run("Membership Proof Of Another Presentation", func(t *testing.T) {
+		presentation := present(t, credential)
+		presentation.VerifiableCredential[0].IssuerSetProof.ProofValue = present(t, credential).VerifiableCredential[0].IssuerSetProof.ProofValue
+		resign(t, presentation)
+
+		result := verify(t, presentation, nil)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Forged Issuer Set", func(t *testing.T) {
+		outsider, err := issuerUC.SetupIssuer("outsider")
+		require.NoError(t, err)
+
+		// Swapping a member for another issuer breaks both the signed metadata and the membership proof
+		presentation := present(t, credential)
+		set := presentation.VerifiableCredential[0].IssuerSetProof
+		for i, member := range set.Issuers {
+			if member == authorities[0] {
+				set.Issuers[i] = outsider.DID.String()
+				set.VerificationMethods[i] = outsider.BBSKeyID
+			}
+		}
+		resign(t, presentation)
+
+		result := verify(t, presentation, nil)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Member Key Rotation", func(t *testing.T) {
+		// Rotating a member's key does not invalidate credentials signed with the old set
+		_, err := issuerUC.RotateKeys(authorities[0])
+		require.NoError(t, err)
+
+		result := verify(t, present(t, credential), nil)
+		assert.True(t, result.Valid, result.Errors)
+
+		// New credentials are signed over the member's new key
+		rotated := issue(t, []string{authorities[0], authorities[2]}, true)
+		result = verify(t, present(t, rotated), nil)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Credential Without Issuer Set", func(t *testing.T) {
+		plain := issue(t, nil, true)
+		assert.Nil(t, plain.IssuerSetProof)
+		assert.NotEmpty(t, plain.CredentialStatus)
+
+		_, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{plain.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: plain.ID, RevealedAttributes: []string{"ageOver18"}, HideIssuer: true},
+			},
+		})
+		assert.ErrorContains(t, err, "not issued into an issuer set")
+	})
+
+	t.Run("Set Needs Other Issuers", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:    authorities[1],
+			SubjectDID:   holderDID,
+			Claims:       []vc.Claim{{Key: "ageOver18", Value: true}},
+			AnonymitySet: []string{authorities[1]},
+		})
+		assert.ErrorContains(t, err, "at least one issuer besides")
+
+		_, err = issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:    authorities[1],
+			SubjectDID:   holderDID,
+			Claims:       []vc.Claim{{Key: "ageOver18", Value: true}},
+			AnonymitySet: []string{holderDID},
+		})
+		assert.ErrorContains(t, err, "publishes no BBS+ key")
+	})
+}
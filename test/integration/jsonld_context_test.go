@@ -0,0 +1,168 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestJSONLDContexts tests validating credentials and presentations against the bundled
+// JSON-LD contexts and serving those contexts for offline deployments
+func TestJSONLDContexts(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	loader, err := jsonld.NewBundledLoader()
+	require.NoError(t, err)
+	validator := jsonld.NewValidator(loader)
+	holderUC.SetContextValidator(validator)
+	verifierUC.SetContextValidator(validator)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	issue := func(t *testing.T) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims: []vc.Claim{
+				{Key: "firstName", Value: "Alice"},
+				{Key: "ageOver18", Value: true},
+			},
+		})
+		require.NoError(t, err)
+		return credential
+	}
+
+	credential := issue(t)
+
+	t.Run("Issued Credentials Expand", func(t *testing.T) {
+		assert.Equal(t, []string{jsonld.CredentialsV1URL, jsonld.BBSV1URL, jsonld.ExtensionsV1URL}, credential.Context)
+		require.NoError(t, holderUC.StoreCredential(credential))
+	})
+
+	t.Run("Undefined Claims Rejected", func(t *testing.T) {
+		bare := issue(t)
+		bare.Context = []string{jsonld.CredentialsV1URL, jsonld.BBSV1URL}
+
+		err := holderUC.StoreCredential(bare)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "credential context validation failed")
+		assert.Contains(t, err.Error(), `term "ageOver18" is not defined by the context`)
+	})
+
+	t.Run("Unknown Contexts Rejected", func(t *testing.T) {
+		unknown := issue(t)
+		unknown.Context = append(unknown.Context, "https://example.com/contexts/unknown/v1")
+
+		err := holderUC.StoreCredential(unknown)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not available offline")
+	})
+
+	present := func(t *testing.T) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: "context-nonce-50355ff4",
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "context-nonce-50355ff4",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Presentations Expand", func(t *testing.T) {
+		result := verify(t, present(t))
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("Presented Credential Redefining Protected Terms Rejected", func(t *testing.T) {
+		data, err := json.Marshal(present(t))
+		require.NoError(t, err)
+		var document map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &document))
+		derived := document["verifiableCredential"].([]interface{})[0].(map[string]interface{})
+		derived["@context"] = []interface{}{
+			jsonld.CredentialsV1URL,
+			map[string]interface{}{"VerifiableCredential": "https://example.com/attacker#Credential"},
+		}
+
+		err = validator.ValidatePresentation(document)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "protected term VerifiableCredential cannot be redefined")
+
+		// Presented credentials carry their contexts by URL, so embedded definitions never decode
+		data, err = json.Marshal(document)
+		require.NoError(t, err)
+		var decoded vc.VerifiablePresentation
+		assert.Error(t, json.Unmarshal(data, &decoded))
+	})
+
+	t.Run("Contexts Served Locally", func(t *testing.T) {
+		server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+		server.SetContextLoader(loader)
+
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		resp, err := http.Get(ts.URL + "/contexts")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var list dto.ListContextsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+		require.Len(t, list.Contexts, 4)
+
+		for _, entry := range list.Contexts {
+			bundled, err := loader.LoadContext(entry.URL)
+			require.NoError(t, err)
+
+			resp, err := http.Get(entry.LocalURL)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/ld+json", resp.Header.Get("Content-Type"))
+			assert.JSONEq(t, string(bundled.Document), string(body))
+		}
+
+		resp, err = http.Get(ts.URL + "/contexts/unknown-v1")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestKeyEscrow tests splitting an issuer's BBS+ key among custodians and recovering it after
+// the signing key is lost
+func TestKeyEscrow(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	var custodians []string
+	for i := 0; i < 3; i++ {
+		custodian, err := stack.Holder.SetupHolder("example")
+		require.NoError(t, err)
+		custodians = append(custodians, custodian.DID.String())
+	}
+
+	post := func(t *testing.T, path string, request interface{}, response interface{}) int {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && response != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		}
+		return resp.StatusCode
+	}
+
+	var escrow issuer.KeyEscrow
+	require.Equal(t, http.StatusOK, post(t, "/api/issuer/keys/escrow", dto.EscrowKeyRequest{
+		IssuerDID:  issuerDID,
+		Custodians: custodians,
+		Threshold:  2,
+	}, &escrow))
+	assert.Equal(t, issuerSetup.BBSKeyID, escrow.KeyID)
+	require.Len(t, escrow.Shares, 3)
+
+	// Each custodian opens its share locally with the key agreement key from its wallet backup
+	opened := make([]dto.OpenedShareDTO, len(escrow.Shares))
+	for i, share := range escrow.Shares {
+		assert.Equal(t, custodians[i], share.Custodian)
+		backup, err := stack.Holder.ExportBackup(share.Custodian, "custodian-password")
+		require.NoError(t, err)
+		agreementKey, err := backup.AgreementKey("custodian-password", share.Envelope.RecipientKID)
+		require.NoError(t, err)
+
+		openedShare, err := issuer.OpenEscrowShare(share, agreementKey)
+		require.NoError(t, err)
+		assert.Equal(t, escrow.ID, openedShare.EscrowID)
+		opened[i] = dto.OpenedShareDTO(*openedShare)
+
+		if i > 0 {
+			_, err := issuer.OpenEscrowShare(escrow.Shares[0], agreementKey)
+			assert.ErrorContains(t, err, "not encrypted to the given key", "a custodian cannot open another's share")
+		}
+	}
+
+	t.Run("Shares Cannot Be Opened By The Server", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, post(t, "/api/escrow/shares/open", escrow.Shares[0], nil))
+	})
+
+	// The signing key is lost: the issuer's credential service holds some other key
+	lost, err := stack.BBSService.GenerateKeyPair()
+	require.NoError(t, err)
+	require.NoError(t, stack.Issuer.CredentialService.SetIssuerKey(issuerDID, escrow.KeyID, lost))
+	// issueAndVerify issues a credential and checks its signature under the published key
+	issueAndVerify := func() error {
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: custodians[0],
+			Claims:     []vc.Claim{{Key: "fullName", Value: "An Nguyen"}},
+		})
+		require.NoError(t, err)
+		signature, err := bbs.DecodeSignature(credential.Proof.ProofValue)
+		require.NoError(t, err)
+		messages, err := vc.SignedMessages(credential)
+		require.NoError(t, err)
+		return stack.BBSService.Verify(issuerSetup.BBSKeyPair.PublicKey, signature, messages)
+	}
+	require.Error(t, issueAndVerify())
+
+	t.Run("Too Few Or Altered Shares Are Rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post(t, "/api/issuer/keys/recover", dto.RecoverKeyRequest{Shares: opened[:1]}, nil))
+
+		altered := opened[1]
+		altered.Value = append([]byte(nil), altered.Value...)
+		altered.Value[0] ^= 0x01
+		assert.Equal(t, http.StatusBadRequest, post(t, "/api/issuer/keys/recover", dto.RecoverKeyRequest{Shares: []dto.OpenedShareDTO{opened[0], altered}}, nil))
+	})
+
+	t.Run("Any Two Custodians Recover The Key", func(t *testing.T) {
+		var recovered issuer.RecoveredKey
+		require.Equal(t, http.StatusOK, post(t, "/api/issuer/keys/recover", dto.RecoverKeyRequest{Shares: []dto.OpenedShareDTO{opened[2], opened[0]}}, &recovered))
+		assert.Equal(t, escrow.KeyID, recovered.KeyID)
+		assert.Equal(t, 2, recovered.SharesUsed)
+
+		assert.NoError(t, issueAndVerify())
+	})
+
+	t.Run("Rotated Keys Cannot Be Recovered", func(t *testing.T) {
+		_, err := stack.Issuer.RotateKeys(issuerDID)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, post(t, "/api/issuer/keys/recover", dto.RecoverKeyRequest{Shares: opened[:2]}, nil))
+	})
+
+	t.Run("Invalid Escrow Requests", func(t *testing.T) {
+		for name, request := range map[string]dto.EscrowKeyRequest{
+			"Threshold Above Custodians": {IssuerDID: issuerDID, Custodians: custodians[:2], Threshold: 3},
+			"Threshold Of One":           {IssuerDID: issuerDID, Custodians: custodians, Threshold: 1},
+			"Repeated Custodian":         {IssuerDID: issuerDID, Custodians: []string{custodians[0], custodians[0]}, Threshold: 2},
+			"Unknown Custodian":          {IssuerDID: issuerDID, Custodians: []string{custodians[0], "did:example:unknown"}, Threshold: 2},
+		} {
+			t.Run(name, func(t *testing.T) {
+				assert.Equal(t, http.StatusBadRequest, post(t, "/api/issuer/keys/escrow", request, nil))
+			})
+		}
+	})
+}
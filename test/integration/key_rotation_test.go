@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestKeyRotation tests that credentials issued before and after an issuer key rotation both verify
+func TestKeyRotation(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	var events []issuer.KeyRotationEvent
+	issuerUC.OnKeyRotation(func(event issuer.KeyRotationEvent) {
+		events = append(events, event)
+	})
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderDID := holderSetup.DID.String()
+
+	issue := func(t *testing.T) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderDID,
+			Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	present := func(t *testing.T, credential *vc.VerifiableCredential) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: "rotation-nonce-b22471ff",
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+	verifyPresentation := func(t *testing.T, presentation *vc.VerifiablePresentation) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "rotation-nonce-b22471ff",
+		})
+		require.NoError(t, err)
+		return result
+	}
+	verify := func(t *testing.T, credential *vc.VerifiableCredential) *verifier.VerificationResult {
+		return verifyPresentation(t, present(t, credential))
+	}
+
+	// The BBS+ key is published in the issuer's DID document
+	assert.Equal(t, issuerDID+"#bbs-key-1", issuerSetup.BBSKeyID)
+	doc, err := stack.DIDService.ResolveDID(issuerDID)
+	require.NoError(t, err)
+	method, ok := doc.FindVerificationMethod(issuerSetup.BBSKeyID)
+	require.True(t, ok)
+	assert.Equal(t, "Bls12381G2Key2020", method.Type)
+
+	before := issue(t)
+	assert.Equal(t, issuerDID+"#bbs-key-1", before.Proof.VerificationMethod)
+
+	event, err := issuerUC.RotateKeys(issuerDID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{issuerDID + "#key-1", issuerDID + "#bbs-key-1"}, event.RetiredKeys)
+	assert.Equal(t, []string{issuerDID + "#key-2", issuerDID + "#bbs-key-2"}, event.NewKeys)
+
+	t.Run("Rotation Event Emitted", func(t *testing.T) {
+		require.Len(t, events, 1)
+		assert.Equal(t, issuerDID, events[0].IssuerDID)
+
+		rotations, err := issuerUC.ListKeyRotations(issuerDID)
+		require.NoError(t, err)
+		require.Len(t, rotations, 1)
+		assert.Equal(t, event.NewKeys, rotations[0].NewKeys)
+	})
+
+	t.Run("New Credentials Use New Key", func(t *testing.T) {
+		after := issue(t)
+		assert.Equal(t, issuerDID+"#bbs-key-2", after.Proof.VerificationMethod)
+
+		result := verify(t, after)
+		assert.True(t, result.Valid, result.Errors)
+
+		metadata, err := issuerUC.GetIssuerMetadata(issuerDID)
+		require.NoError(t, err)
+		assert.Equal(t, issuerDID+"#bbs-key-2", metadata.PublicKeys[0].ID)
+	})
+
+	t.Run("Old Credentials Still Verify", func(t *testing.T) {
+		result := verify(t, before)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Retired Key After Rotation", func(t *testing.T) {
+		// A credential claiming issuance after the rotation under the retired key is rejected. The
+		// holder alters the presented date and signs the presentation again.
+		presentation := present(t, before)
+		issuedAt := event.RotatedAt.Add(1)
+		presentation.VerifiableCredential[0].IssuanceDate = &issuedAt
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		result := verifyPresentation(t, presentation)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "was not valid")
+	})
+
+	t.Run("Signatures Use Rotated DID Key", func(t *testing.T) {
+		head, err := issuerUC.GetSignedTreeHead(issuerDID)
+		require.NoError(t, err)
+		assert.Equal(t, issuerDID+"#key-2", head.KeyID)
+	})
+}
@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestProofRequestNegotiation tests a holder withholding an optional claim and proving only the agreed claims
+func TestProofRequestNegotiation(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+			{Key: "firstName", Value: "An"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	request := exchange.ProofRequest{
+		RequiredClaims: []string{"ageOver18"},
+		OptionalClaims: []string{"nationality", "loyaltyTier"},
+		TrustedIssuers: []string{issuerSetup.DID.String()},
+	}
+
+	t.Run("Counter-Offer Accepted", func(t *testing.T) {
+		negotiation, err := verifierUC.StartNegotiation(verifierSetup.DID.String(), request)
+		require.NoError(t, err)
+		assert.NotEmpty(t, negotiation.Request.Nonce, "a nonce is generated")
+
+		// The holder will not share nationality and holds no loyaltyTier claim
+		offer, err := holderUC.PrepareCounterOffer(holderDID, negotiation.Request, []string{"nationality"}, "I can prove ageOver18 but will not share nationality")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ageOver18"}, offer.OfferedClaims)
+		assert.Equal(t, []string{"nationality", "loyaltyTier"}, offer.WithheldClaims)
+
+		decision, updated, err := verifierUC.RespondToNegotiation(negotiation.ID, holderDID, *offer)
+		require.NoError(t, err)
+		assert.Equal(t, exchange.MessageAccept, decision.Type)
+		assert.Equal(t, exchange.StateAgreed, updated.State)
+		assert.Equal(t, []string{"ageOver18"}, updated.AgreedClaims)
+
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: updated.AgreedClaims},
+			},
+			Nonce: updated.Request.Nonce,
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyNegotiatedPresentation(context.Background(), negotiation.ID, presentation)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, true, result.RevealedClaims["ageOver18"])
+		assert.NotContains(t, result.RevealedClaims, "nationality")
+
+		completed, err := verifierUC.GetNegotiation(negotiation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, exchange.StateCompleted, completed.State)
+		assert.Len(t, completed.Messages, 3)
+
+		// A fulfilled negotiation cannot be used again
+		_, err = verifierUC.VerifyNegotiatedPresentation(context.Background(), negotiation.ID, presentation)
+		assert.Error(t, err)
+	})
+
+	t.Run("Withholding Required Claim Rejected", func(t *testing.T) {
+		negotiation, err := verifierUC.StartNegotiation(verifierSetup.DID.String(), request)
+		require.NoError(t, err)
+
+		offer, err := holderUC.PrepareCounterOffer(holderDID, negotiation.Request, []string{"ageOver18"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"nationality"}, offer.OfferedClaims)
+
+		decision, updated, err := verifierUC.RespondToNegotiation(negotiation.ID, holderDID, *offer)
+		require.NoError(t, err)
+		assert.Equal(t, exchange.MessageReject, decision.Type)
+		assert.Contains(t, decision.Decision.Reason, "required claim ageOver18")
+		assert.Equal(t, exchange.StateOpen, updated.State)
+
+		// No presentation is accepted before agreement
+		_, err = verifierUC.VerifyNegotiatedPresentation(context.Background(), negotiation.ID, &vc.VerifiablePresentation{Holder: holderDID})
+		assert.ErrorContains(t, err, "not agreed")
+	})
+
+	t.Run("Presentation Missing Agreed Claim", func(t *testing.T) {
+		negotiation, err := verifierUC.StartNegotiation(verifierSetup.DID.String(), request)
+		require.NoError(t, err)
+
+		_, _, err = verifierUC.RespondToNegotiation(negotiation.ID, holderDID, exchange.CounterOffer{
+			OfferedClaims: []string{"ageOver18", "nationality"},
+		})
+		require.NoError(t, err)
+
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: negotiation.Request.Nonce,
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyNegotiatedPresentation(context.Background(), negotiation.ID, presentation)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors, "required claim 'nationality' is missing")
+
+		stillAgreed, err := verifierUC.GetNegotiation(negotiation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, exchange.StateAgreed, stillAgreed.State)
+	})
+}
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestServerGeneratedNonces tests that verifiers hand out nonces strong enough for BBS+ proof creation
+func TestServerGeneratedNonces(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	t.Run("Generated Nonces Are Accepted By Proof Creation", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for i := 0; i < 20; i++ {
+			nonce, err := verifierUC.GenerateNonce()
+			require.NoError(t, err)
+			assert.NoError(t, bbs.ValidateNonce([]byte(nonce)))
+			assert.False(t, seen[nonce], "nonces must not repeat")
+			seen[nonce] = true
+		}
+	})
+
+	t.Run("Requests Without A Nonce Get One", func(t *testing.T) {
+		request, err := verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+			RequiredClaims: []string{"ageOver18"},
+		})
+		require.NoError(t, err)
+		assert.NoError(t, bbs.ValidateNonce([]byte(request.VerificationNonce)))
+
+		session, err := verifierUC.CreateSession("did:example:verifier", exchange.ProofRequest{
+			RequiredClaims: []string{"ageOver18"},
+		}, 0)
+		require.NoError(t, err)
+		assert.NoError(t, bbs.ValidateNonce([]byte(session.Request.Nonce)))
+
+		negotiation, err := verifierUC.StartNegotiation("did:example:verifier", exchange.ProofRequest{
+			RequiredClaims: []string{"ageOver18"},
+		})
+		require.NoError(t, err)
+		assert.NoError(t, bbs.ValidateNonce([]byte(negotiation.Request.Nonce)))
+	})
+
+	t.Run("Guessable Request Nonces Are Rejected", func(t *testing.T) {
+		_, err := verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "anonymity-nonce",
+		})
+		assert.Error(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      &vc.VerifiablePresentation{},
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "anonymity-nonce",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Nonce Endpoint", func(t *testing.T) {
+		server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		resp, err := http.Get(ts.URL + "/api/verifier/nonce")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body dto.GenerateNonceResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.NoError(t, bbs.ValidateNonce([]byte(body.Nonce)))
+
+		resp, err = http.Post(ts.URL+"/api/verifier/nonce", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestOptionalClaims tests that missing optional claims are reported without failing verification
+func TestOptionalClaims(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+			{Key: "city", Value: "Da Nang"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T, revealed ...string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: revealed},
+			},
+			Nonce: "optional-nonce-76386e2d",
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	t.Run("Optional Claim Withheld", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      present(t, "ageOver18", "city"),
+			RequiredClaims:    []string{"ageOver18"},
+			OptionalClaims:    []string{"nationality", "city"},
+			VerificationNonce: "optional-nonce-76386e2d",
+			VerifierDID:       verifierSetup.DID.String(),
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, []string{"city"}, result.ProvidedOptionalClaims)
+		assert.Equal(t, []string{"nationality"}, result.MissingOptionalClaims)
+
+		// The receipt records the optional claims that were asked for
+		require.NotNil(t, result.Receipt)
+		assert.Equal(t, []string{"nationality", "city"}, result.Receipt.Outcome.OptionalClaims)
+	})
+
+	t.Run("Required Claim Still Enforced", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      present(t, "nationality"),
+			RequiredClaims:    []string{"ageOver18"},
+			OptionalClaims:    []string{"nationality"},
+			VerificationNonce: "optional-nonce-76386e2d",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors, "required claim 'ageOver18' is missing")
+		assert.Equal(t, []string{"nationality"}, result.ProvidedOptionalClaims)
+	})
+
+	t.Run("Required Takes Precedence", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      present(t, "ageOver18"),
+			RequiredClaims:    []string{"ageOver18", "nationality"},
+			OptionalClaims:    []string{"nationality"},
+			VerificationNonce: "optional-nonce-76386e2d",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Empty(t, result.MissingOptionalClaims)
+
+		_, err = verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+			RequiredClaims: []string{"ageOver18", "nationality"},
+			OptionalClaims: []string{"nationality"},
+		})
+		assert.ErrorContains(t, err, "both required and optional")
+	})
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestOverDisclosure tests that verifiers can reject or warn about presentations revealing more
+// claims than they requested
+func TestOverDisclosure(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+			{Key: "city", Value: "Da Nang"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T, revealed ...string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: revealed},
+			},
+			Nonce: "minimization-nonce",
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+	overDisclosing := present(t, "ageOver18", "nationality", "city")
+
+	t.Run("Unchecked By Default", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      overDisclosing,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "minimization-nonce",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Empty(t, result.OverDisclosedClaims)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      overDisclosing,
+			RequiredClaims:    []string{"ageOver18"},
+			OptionalClaims:    []string{"nationality"},
+			OverDisclosure:    verifier.OverDisclosureReject,
+			VerificationNonce: "minimization-nonce",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{"city"}, result.OverDisclosedClaims)
+		assert.Contains(t, result.Errors[0], "over-disclosure")
+	})
+
+	t.Run("Allowed Claims", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      overDisclosing,
+			RequiredClaims:    []string{"ageOver18"},
+			AllowedClaims:     []string{"nationality", "city"},
+			VerificationNonce: "minimization-nonce",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Empty(t, result.OverDisclosedClaims)
+
+		result, err = verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      overDisclosing,
+			RequiredClaims:    []string{"ageOver18"},
+			AllowedClaims:     []string{"nationality"},
+			VerificationNonce: "minimization-nonce",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid, "allowing some claims rejects the others")
+		assert.Equal(t, []string{"city"}, result.OverDisclosedClaims)
+	})
+
+	t.Run("Maximum Revealed Claims", func(t *testing.T) {
+		request := verifier.VerificationRequest{
+			Presentation:      overDisclosing,
+			RequiredClaims:    []string{"ageOver18"},
+			OptionalClaims:    []string{"nationality", "city"},
+			MaxRevealedClaims: 2,
+			VerificationNonce: "minimization-nonce",
+		}
+		result, err := verifierUC.VerifyPresentation(request)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Empty(t, result.OverDisclosedClaims, "every claim was requested")
+
+		request.Presentation = present(t, "ageOver18", "city")
+		result, err = verifierUC.VerifyPresentation(request)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Warned", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      overDisclosing,
+			RequiredClaims:    []string{"ageOver18"},
+			MaxRevealedClaims: 1,
+			OverDisclosure:    verifier.OverDisclosureWarn,
+			VerificationNonce: "minimization-nonce",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, []string{"city", "nationality"}, result.OverDisclosedClaims)
+		require.Len(t, result.Warnings, 2)
+		for _, warning := range result.Warnings {
+			assert.Equal(t, lint.CodeOverDisclosure, warning.Code)
+		}
+	})
+}
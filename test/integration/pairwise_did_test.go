@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestPairwisePresentations tests presenting under per-verifier did:peer DIDs
+func TestPairwisePresentations(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("test")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("test")
+	require.NoError(t, err)
+
+	cinema, err := verifierUC.SetupVerifier("test")
+	require.NoError(t, err)
+
+	bar, err := verifierUC.SetupVerifier("test")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "name", Value: "Alice"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(verifierDID string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			VerifierDID:    verifierDID,
+			UsePairwiseDID: true,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	cinemaVP1 := present(cinema.DID.String())
+	cinemaVP2 := present(cinema.DID.String())
+	barVP := present(bar.DID.String())
+
+	assert.True(t, did.IsPeerDID(cinemaVP1.Holder))
+	assert.NotEqual(t, holderSetup.DID.String(), cinemaVP1.Holder)
+	assert.Equal(t, cinemaVP1.Holder, cinemaVP2.Holder, "same verifier should see a stable pairwise DID")
+	assert.NotEqual(t, cinemaVP1.Holder, barVP.Holder, "different verifiers must see different DIDs")
+	pairwiseDIDs, err := holderUC.ListPairwiseDIDs(holderSetup.DID.String())
+	require.NoError(t, err)
+	assert.Len(t, pairwiseDIDs, 2)
+
+	// Pairwise DIDs resolve without being registered anywhere
+	doc, err := stack.DIDService.ResolveDID(barVP.Holder)
+	require.NoError(t, err)
+	assert.Equal(t, barVP.Holder, doc.ID)
+
+	result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+		Presentation:   barVP,
+		RequiredClaims: []string{"ageOver18"},
+		TrustedIssuers: []string{issuerSetup.DID.String()},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, barVP.Holder, result.HolderDID)
+
+	t.Run("Verifier DID Required", func(t *testing.T) {
+		_, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			UsePairwiseDID: true,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "verifier DID is required")
+	})
+}
@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/predicate"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestPredicateProofs tests proving comparisons about hidden claims through the presentation pipeline
+func TestPredicateProofs(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "dateOfBirth", Value: "2000-01-20", Type: schema.ClaimTypeDate},
+			{Key: "salary", Value: 72000, Type: schema.ClaimTypeInteger},
+			{Key: "nationality", Value: "Vietnamese"},
+		},
+		CommitAttributes: []string{"dateOfBirth", "salary", "nationality"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	bornBefore2007 := vc.PredicateStatement{Claim: "dateOfBirth", Operator: vc.PredicateLessThan, Value: 20071016}
+	salaryOver60k := vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 60000}
+
+	present := func(t *testing.T, statements ...vc.PredicateStatement) (*vc.VerifiablePresentation, error) {
+		return holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"nationality"}, IncludeCommitments: true, Predicates: statements},
+			},
+			Nonce: "predicate-nonce-4a70d260",
+		})
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, required ...vc.PredicateStatement) *verifier.VerificationResult {
+		// Round-trip through JSON as a verifier receiving the presentation would
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		var decoded vc.VerifiablePresentation
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:       &decoded,
+			RequiredPredicates: required,
+			VerificationNonce:  "predicate-nonce-4a70d260",
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Predicates Proven Without Revealing Claims", func(t *testing.T) {
+		presentation, err := present(t, bornBefore2007, salaryOver60k)
+		require.NoError(t, err)
+
+		result := verify(t, presentation, bornBefore2007, salaryOver60k)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, []vc.PredicateStatement{bornBefore2007, salaryOver60k}, result.ProvenPredicates)
+		assert.NotContains(t, result.RevealedClaims, "dateOfBirth")
+		assert.NotContains(t, result.RevealedClaims, "salary")
+	})
+
+	t.Run("Stronger Proof Satisfies Weaker Requirement", func(t *testing.T) {
+		presentation, err := present(t, salaryOver60k)
+		require.NoError(t, err)
+
+		result := verify(t, presentation, vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterOrEqual, Value: 50000})
+		assert.True(t, result.Valid, result.Errors)
+
+		result = verify(t, presentation, vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 70000})
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "is not proven")
+	})
+
+	t.Run("False Statement Cannot Be Proven", func(t *testing.T) {
+		_, err := present(t, vc.PredicateStatement{Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 100000})
+		assert.ErrorContains(t, err, "does not satisfy")
+
+		_, err = present(t, vc.PredicateStatement{Claim: "nationality", Operator: vc.PredicateGreaterThan, Value: 0})
+		assert.ErrorContains(t, err, "does not support comparisons")
+	})
+
+	t.Run("Proof Bound To Statement", func(t *testing.T) {
+		presentation, err := present(t, salaryOver60k)
+		require.NoError(t, err)
+
+		// Claim the proof shows a higher salary, re-signing so only the predicate check can catch it
+		presentation.VerifiableCredential[0].Predicates[0].Value = 90000
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		result := verify(t, presentation)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "predicate check failed")
+	})
+
+	t.Run("Predicates Need Commitments", func(t *testing.T) {
+		_, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"nationality"}, Predicates: []vc.PredicateStatement{salaryOver60k}},
+			},
+		})
+		assert.ErrorContains(t, err, "predicates need includeCommitments")
+	})
+
+	t.Run("Unknown Provider Rejected", func(t *testing.T) {
+		presentation, err := present(t, salaryOver60k)
+		require.NoError(t, err)
+
+		otherVerifier := verifier.NewUseCase(stack.DIDService, stack.Verifier.CredentialService, stack.Verifier.Presentations)
+		otherVerifier.SetStatusRegistry(stack.StatusRegistry)
+		empty, err := predicate.NewRegistry()
+		require.NoError(t, err)
+		otherVerifier.SetPredicateRegistry(empty)
+
+		result, err := otherVerifier.VerifyPresentation(verifier.VerificationRequest{Presentation: presentation})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "unsupported predicate provider")
+	})
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/barcode"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestPresentationBarcode tests that a presentation shown as QR codes verifies from the scanned payloads
+func TestPresentationBarcode(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	post := func(t *testing.T, path string, request interface{}) *http.Response {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := post(t, "/api/holder/presentations", dto.CreatePresentationRequest{
+		HolderDID:     holderSetup.DID.String(),
+		CredentialIDs: []string{credential.ID},
+		SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+		},
+		Nonce:   "barcode-nonce-ab912b67",
+		Barcode: true,
+	})
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var created dto.CreatePresentationResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.Len(t, created.Barcode, 1)
+	assert.LessOrEqual(t, len(created.Barcode[0]), barcode.QRCapacity)
+
+	verify := func(t *testing.T, request dto.VerifyPresentationRequest) *http.Response {
+		request.RequiredClaims = []string{"ageOver18"}
+		request.TrustedIssuers = []string{issuerSetup.DID.String()}
+		request.VerificationNonce = "barcode-nonce-ab912b67"
+		return post(t, "/api/verifier/verify", request)
+	}
+
+	t.Run("Scanned Presentation Verifies", func(t *testing.T) {
+		resp := verify(t, dto.VerifyPresentationRequest{Barcode: created.Barcode})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result dto.VerifyPresentationResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, true, result.RevealedClaims["ageOver18"])
+	})
+
+	t.Run("Chunked Presentation Verifies", func(t *testing.T) {
+		chunks, err := barcode.EncodePresentation(created.Presentation, 400)
+		require.NoError(t, err)
+		require.Greater(t, len(chunks), 1)
+		reversed := make([]string, len(chunks))
+		for i, chunk := range chunks {
+			reversed[len(chunks)-1-i] = chunk
+		}
+
+		resp := verify(t, dto.VerifyPresentationRequest{Barcode: reversed})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result dto.VerifyPresentationResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.True(t, result.Valid, result.Errors)
+
+		resp = verify(t, dto.VerifyPresentationRequest{Barcode: chunks[1:]})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Presentation And Barcode Are Exclusive", func(t *testing.T) {
+		resp := verify(t, dto.VerifyPresentationRequest{Presentation: created.Presentation, Barcode: created.Barcode})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestPresentationDomain tests that a presentation bound to a domain is only accepted by
+// verifiers expecting that domain, and that the binding is covered by the holder's signature
+func TestPresentationDomain(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	present := func(t *testing.T, nonce, domain string) *vc.VerifiablePresentation {
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce:  nonce,
+			Domain: domain,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, nonce, domain string) *verifier.VerificationResult {
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: nonce,
+			Domain:            domain,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Matching Domain", func(t *testing.T) {
+		presentation := present(t, "domain-nonce-1-2b73dc54", "verifier.example")
+		assert.Equal(t, "verifier.example", presentation.Proof.Domain)
+		result := verify(t, presentation, "domain-nonce-1-2b73dc54", "verifier.example")
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Other Domain", func(t *testing.T) {
+		result := verify(t, present(t, "domain-nonce-2-935fe6b6", "verifier.example"), "domain-nonce-2-935fe6b6", "other.example")
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "other.example")
+
+		result = verify(t, present(t, "domain-nonce-3-20ad67bb", ""), "domain-nonce-3-20ad67bb", "verifier.example")
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Altered Domain Breaks The Holder Proof", func(t *testing.T) {
+		presentation := present(t, "domain-nonce-4-8b08d801", "verifier.example")
+		presentation.Proof.Domain = "other.example"
+		result := verify(t, presentation, "domain-nonce-4-8b08d801", "other.example")
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "holder proof verification failed")
+	})
+}
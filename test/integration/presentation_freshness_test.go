@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestPresentationFreshness tests rejecting expired, stale and future-dated presentations
+func TestPresentationFreshness(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T, validFor time.Duration) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce:    "freshness-nonce-c2de967e",
+			ValidFor: validFor,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, maxAge time.Duration) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:       presentation,
+			RequiredClaims:     []string{"ageOver18"},
+			VerificationNonce:  "freshness-nonce-c2de967e",
+			MaxPresentationAge: maxAge,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// resign re-signs a presentation after its proof timestamps were altered, as the holder would
+	resign := func(t *testing.T, presentation *vc.VerifiablePresentation) {
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+	}
+
+	require.NoError(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{}))
+
+	t.Run("Expiry Is Signed", func(t *testing.T) {
+		presentation := present(t, time.Hour)
+		require.NotNil(t, presentation.Proof.Expires)
+		assert.Equal(t, presentation.Proof.Created.Add(time.Hour), *presentation.Proof.Expires)
+		assert.True(t, verify(t, presentation, 0).Valid)
+
+		// Extending the expiry breaks the holder's signature
+		extended := presentation.Proof.Expires.Add(24 * time.Hour)
+		presentation.Proof.Expires = &extended
+		assert.False(t, verify(t, presentation, 0).Valid)
+	})
+
+	t.Run("Expired Presentation", func(t *testing.T) {
+		presentation := present(t, 10*time.Millisecond)
+		time.Sleep(30 * time.Millisecond)
+
+		result := verify(t, presentation, 0)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "presentation expired")
+	})
+
+	t.Run("Stale Presentation", func(t *testing.T) {
+		presentation := present(t, 0)
+		assert.Nil(t, presentation.Proof.Expires)
+		time.Sleep(30 * time.Millisecond)
+
+		// Request-level maximum age
+		result := verify(t, presentation, 10*time.Millisecond)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "presentation is stale")
+
+		assert.True(t, verify(t, presentation, time.Minute).Valid)
+
+		// Verifier-wide maximum age
+		require.NoError(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{MaxPresentationAge: 10 * time.Millisecond}))
+		defer func() {
+			require.NoError(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{}))
+		}()
+		assert.False(t, verify(t, presentation, 0).Valid)
+	})
+
+	t.Run("Presentation From Yesterday", func(t *testing.T) {
+		presentation := present(t, 0)
+		presentation.Proof.Created = presentation.Proof.Created.Add(-24 * time.Hour)
+		resign(t, presentation)
+
+		assert.False(t, verify(t, presentation, time.Hour).Valid)
+		// Without a maximum age and without an expiry the presentation is still accepted
+		assert.True(t, verify(t, presentation, 0).Valid)
+	})
+
+	t.Run("Clock Skew", func(t *testing.T) {
+		presentation := present(t, 0)
+		presentation.Proof.Created = presentation.Proof.Created.Add(time.Minute)
+		resign(t, presentation)
+
+		// One minute ahead is rejected without tolerance...
+		result := verify(t, presentation, time.Hour)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "created in the future")
+
+		// ...and accepted with the default skew
+		require.NoError(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{ClockSkew: verifier.DefaultClockSkew}))
+		assert.True(t, verify(t, presentation, time.Hour).Valid)
+
+		// Skew also extends the expiry and maximum age
+		expired := present(t, 10*time.Millisecond)
+		time.Sleep(30 * time.Millisecond)
+		assert.True(t, verify(t, expired, 10*time.Millisecond).Valid)
+	})
+
+	t.Run("Invalid Policy", func(t *testing.T) {
+		assert.Error(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{MaxPresentationAge: -time.Second}))
+		assert.Error(t, verifierUC.SetFreshnessPolicy(verifier.FreshnessPolicy{ClockSkew: -time.Second}))
+
+		_, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			ValidFor: -time.Second,
+		})
+		assert.Error(t, err)
+	})
+}
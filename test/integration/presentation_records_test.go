@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestPresentationRecords tests that the verifier stores each presentation with the verifier it
+// was submitted to, when it was verified and the outcome, and lists them per verifier
+func TestPresentationRecords(t *testing.T) {
+	start := time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC)
+	mock := clock.NewMock(start)
+
+	stack, err := sdk.NewStack(sdk.Config{Clock: mock})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	bar, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+	shop, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}, {Key: "nationality", Value: "US"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	verify := func(t *testing.T, verifierDID string, required []string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			VerifierDID: verifierDID,
+		})
+		require.NoError(t, err)
+
+		_, err = verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: required,
+			VerifierDID:    verifierDID,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	// One accepted and one rejected presentation at the bar, a day apart, and one at the shop
+	accepted := verify(t, bar.DID.String(), []string{"ageOver18"})
+	mock.Advance(24 * time.Hour)
+	rejected := verify(t, bar.DID.String(), []string{"nationality"})
+	atShop := verify(t, shop.DID.String(), []string{"ageOver18"})
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	list := func(t *testing.T, params url.Values) (int, []dto.PresentationRecordDTO) {
+		resp, err := http.Get(ts.URL + "/api/verifier/presentations?" + params.Encode())
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var response dto.ListPresentationsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response.Presentations
+	}
+
+	ids := func(records []dto.PresentationRecordDTO) []string {
+		result := make([]string, len(records))
+		for i, record := range records {
+			result[i] = record.PresentationID
+		}
+		return result
+	}
+
+	t.Run("Keyed By Verifier", func(t *testing.T) {
+		code, records := list(t, url.Values{"verifierDid": {bar.DID.String()}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{accepted.ID, rejected.ID}, ids(records))
+
+		assert.Equal(t, bar.DID.String(), records[0].VerifierDID)
+		assert.Equal(t, holderDID, records[0].HolderDID)
+		assert.Equal(t, start, records[0].VerifiedAt)
+		assert.True(t, records[0].Valid)
+		assert.Empty(t, records[0].Errors)
+		require.NotNil(t, records[0].Presentation)
+
+		assert.False(t, records[1].Valid)
+		assert.NotEmpty(t, records[1].Errors)
+
+		code, records = list(t, url.Values{"verifierDid": {shop.DID.String()}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{atShop.ID}, ids(records))
+	})
+
+	t.Run("Filter By Outcome", func(t *testing.T) {
+		_, records := list(t, url.Values{"verifierDid": {bar.DID.String()}, "valid": {"true"}})
+		assert.Equal(t, []string{accepted.ID}, ids(records))
+
+		_, records = list(t, url.Values{"verifierDid": {bar.DID.String()}, "valid": {"false"}})
+		assert.Equal(t, []string{rejected.ID}, ids(records))
+	})
+
+	t.Run("Filter By Holder And Date", func(t *testing.T) {
+		_, records := list(t, url.Values{"verifierDid": {bar.DID.String()}, "holderDid": {holderDID}, "verifiedAfter": {"2030-03-02"}})
+		assert.Equal(t, []string{rejected.ID}, ids(records))
+
+		_, records = list(t, url.Values{"verifierDid": {bar.DID.String()}, "verifiedBefore": {"2030-03-01"}})
+		assert.Equal(t, []string{accepted.ID}, ids(records))
+
+		_, records = list(t, url.Values{"verifierDid": {bar.DID.String()}, "holderDid": {"did:example:someone-else"}})
+		assert.Empty(t, records)
+	})
+
+	t.Run("Invalid Queries", func(t *testing.T) {
+		for name, params := range map[string]url.Values{
+			"Missing Verifier": {},
+			"Invalid Outcome":  {"verifierDid": {bar.DID.String()}, "valid": {"maybe"}},
+			"Invalid Date":     {"verifierDid": {bar.DID.String()}, "verifiedAfter": {"yesterday"}},
+			"Inverted Range":   {"verifierDid": {bar.DID.String()}, "verifiedAfter": {"2030-03-02"}, "verifiedBefore": {"2030-03-01"}},
+		} {
+			code, _ := list(t, params)
+			assert.Equal(t, http.StatusBadRequest, code, name)
+		}
+	})
+}
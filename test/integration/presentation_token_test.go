@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestPresentationTokens tests that a holder can mint a token for a pre-derived presentation that
+// a verifier redeems exactly once, within the token's lifetime
+func TestPresentationTokens(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	stack.Verifier.SetReplayCache(replay.NewMemoryCache(100))
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, url string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := stack.Verifier.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "name", Value: "An Nguyen"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	mint := func(t *testing.T, ttlSeconds int) dto.PresentationTokenResponse {
+		var token dto.PresentationTokenResponse
+		post(t, ts.URL+"/api/holder/presentation-tokens", http.StatusCreated, dto.MintPresentationTokenRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			TTLSeconds: ttlSeconds,
+		}, &token)
+		return token
+	}
+
+	t.Run("Redeemed Once At Its URL", func(t *testing.T) {
+		token := mint(t, 600)
+		assert.True(t, strings.HasPrefix(token.URL, ts.URL+"/api/holder/presentation-tokens/"+token.TokenID))
+		assert.NotEmpty(t, token.Secret)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), token.ExpiresAt, time.Minute)
+
+		post(t, token.URL, http.StatusNotFound, dto.RedeemPresentationTokenRequest{Secret: "wrong"}, nil)
+
+		var redeemed dto.RedeemPresentationTokenResponse
+		post(t, token.URL, http.StatusOK, dto.RedeemPresentationTokenRequest{Secret: token.Secret}, &redeemed)
+		require.NotNil(t, redeemed.Presentation)
+		require.NotNil(t, redeemed.Presentation.Proof.Expires, "the proof expires with the token")
+		assert.WithinDuration(t, token.ExpiresAt, *redeemed.Presentation.Proof.Expires, time.Minute)
+		assert.NotEmpty(t, redeemed.Nonce)
+
+		post(t, token.URL, http.StatusGone, dto.RedeemPresentationTokenRequest{Secret: token.Secret}, nil)
+
+		result, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      redeemed.Presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: redeemed.Nonce,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Verifier Redeems And Verifies In A Session", func(t *testing.T) {
+		token := mint(t, 0)
+		redeemURL := ts.URL + "/api/verifier/presentation-tokens/" + token.TokenID + "/redeem"
+		request := dto.VerifyPresentationTokenRequest{
+			Secret:         token.Secret,
+			VerifierDID:    verifierSetup.DID.String(),
+			RequiredClaims: []string{"ageOver18"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+		}
+
+		var session verifier.Session
+		post(t, redeemURL, http.StatusOK, request, &session)
+		assert.Equal(t, verifier.SessionCompleted, session.State)
+		require.NotNil(t, session.Result)
+		assert.True(t, session.Result.Valid, session.Result.Errors)
+		assert.Equal(t, verifierSetup.DID.String(), session.VerifierDID)
+
+		recorded, err := stack.Verifier.GetSession(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionCompleted, recorded.State)
+
+		post(t, redeemURL, http.StatusGone, request, nil)
+	})
+
+	t.Run("Leaked Presentation Is Not Accepted Twice", func(t *testing.T) {
+		token, err := stack.Holder.MintPresentationToken(context.Background(), holder.PresentationTokenRequest{
+			PresentationRequest: holder.PresentationRequest{
+				HolderDID:     holderSetup.DID.String(),
+				CredentialIDs: []string{credential.ID},
+				SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+					{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+				},
+				Nonce: "email-nonce-5dc45643",
+			},
+		})
+		require.NoError(t, err)
+
+		// Whoever obtains the redeemed presentation can only have it accepted once
+		presentation, nonce, err := stack.Holder.RedeemPresentationToken(token.ID, token.Secret)
+		require.NoError(t, err)
+		assert.Equal(t, "email-nonce-5dc45643", nonce)
+
+		first, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: nonce,
+		})
+		require.NoError(t, err)
+		require.True(t, first.Valid, first.Errors)
+
+		second, err := stack.Verifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: nonce,
+		})
+		require.NoError(t, err)
+		assert.False(t, second.Valid)
+	})
+
+	t.Run("Lifetime Is Bounded", func(t *testing.T) {
+		post(t, ts.URL+"/api/holder/presentation-tokens", http.StatusBadRequest, dto.MintPresentationTokenRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			TTLSeconds: int((holder.MaxPresentationTokenTTL + time.Hour).Seconds()),
+		}, nil)
+	})
+
+	t.Run("Replay Cache Is Required", func(t *testing.T) {
+		other, err := sdk.NewStack(sdk.Config{})
+		require.NoError(t, err)
+		_, err = other.Verifier.RedeemPresentationToken(context.Background(), "any", "secret", "", exchange.ProofRequest{RequiredClaims: []string{"ageOver18"}})
+		assert.ErrorContains(t, err, "replay cache")
+	})
+}
@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestProofDiagnostics tests explaining the verification of a holder's derived proof, and that the
+// endpoint stays off unless enabled
+func TestProofDiagnostics(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+		HolderDID:     holderSetup.DID.String(),
+		CredentialIDs: []string{credential.ID},
+		SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+		},
+		Nonce: "diagnostics-nonce",
+	})
+	require.NoError(t, err)
+	proofValue := presentation.VerifiableCredential[0].Proof.ProofValue
+	require.NotEmpty(t, proofValue, "the holder derives a proof from the issuer's signature")
+
+	messages, err := vc.SignedMessages(credential)
+	require.NoError(t, err)
+	publicKey := base64.StdEncoding.EncodeToString(issuerSetup.BBSKeyPair.PublicKey)
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	diagnose := func(t *testing.T, req dto.DiagnoseProofRequest, status int) dto.DiagnoseProofResponse {
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/bbs/proofs/diagnose", "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+
+		var report dto.DiagnoseProofResponse
+		if status == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		}
+		return report
+	}
+	request := dto.DiagnoseProofRequest{
+		PublicKey:         publicKey,
+		ProofValue:        proofValue,
+		RevealedMessages:  []string{base64.StdEncoding.EncodeToString(messages[2]), base64.StdEncoding.EncodeToString(messages[3])},
+		PresentationNonce: "diagnostics-nonce",
+	}
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		diagnose(t, request, http.StatusNotFound)
+	})
+
+	server.SetProofDiagnostics(true)
+	ts.Config.Handler = server.Handler()
+
+	t.Run("Valid Derived Proof", func(t *testing.T) {
+		report := diagnose(t, request, http.StatusOK)
+		assert.Equal(t, "production", report.Provider)
+		assert.True(t, report.Valid)
+		assert.True(t, report.NonceMatchesProof)
+		require.Len(t, report.RevealedMessages, 2)
+		assert.Equal(t, 2, report.RevealedMessages[0].Index, "message 0 is the claim manifest")
+		assert.Equal(t, 3, report.RevealedMessages[1].Index, "the credential metadata follows the claims")
+	})
+
+	t.Run("Wrong Revealed Message", func(t *testing.T) {
+		wrong := request
+		wrong.RevealedMessages = []string{base64.StdEncoding.EncodeToString(messages[1]), base64.StdEncoding.EncodeToString(messages[3])}
+		report := diagnose(t, wrong, http.StatusOK)
+		assert.False(t, report.Valid)
+		assert.Equal(t, "challenge", report.FailedCheck)
+		require.NotNil(t, report.Challenge)
+		assert.False(t, report.Challenge.Match)
+	})
+
+	t.Run("Raw Nonce Instead Of The Presentation Nonce", func(t *testing.T) {
+		raw := request
+		raw.PresentationNonce = ""
+		raw.Nonce = base64.StdEncoding.EncodeToString([]byte("diagnostics-nonce"))
+		report := diagnose(t, raw, http.StatusOK)
+		assert.False(t, report.Valid)
+		assert.False(t, report.NonceMatchesProof)
+	})
+
+	t.Run("Invalid Input", func(t *testing.T) {
+		invalid := request
+		invalid.ProofValue = "not a proof"
+		diagnose(t, invalid, http.StatusBadRequest)
+
+		invalid = request
+		invalid.Nonce = "bm9uY2U="
+		diagnose(t, invalid, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestProofTemplates tests that presentations use precomputed proof templates, each once, and
+// that templates are dropped when the credential is stored again or its status changes
+func TestProofTemplates(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+	issuerUC.OnStatusChange(func(notification vc.StatusNotification) {
+		require.NoError(t, holderUC.ReceiveStatusNotification(&notification))
+	})
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	messages, err := vc.SignedMessages(credential)
+	require.NoError(t, err)
+
+	present := func(t *testing.T, nonce string) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: nonce,
+		})
+		require.NoError(t, err)
+
+		proof, err := bbs.DecodeProof(presentation.VerifiableCredential[0].Proof.ProofValue)
+		require.NoError(t, err)
+		proofNonce, err := vc.ProofNonce(nonce)
+		require.NoError(t, err)
+		assert.NoError(t, stack.BBSService.VerifyProof(issuerSetup.BBSKeyPair.PublicKey, proof, [][]byte{messages[2], messages[len(messages)-1]}, proofNonce))
+	}
+
+	t.Run("Presentations Consume Templates", func(t *testing.T) {
+		computed, err := holderUC.PrecomputeProofs(holderDID, nil, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, computed)
+
+		computed, err = holderUC.PrecomputeProofs(holderDID, []string{credential.ID}, 2)
+		require.NoError(t, err)
+		assert.Zero(t, computed, "the pool is already full")
+
+		present(t, "template-nonce-1")
+		assert.Equal(t, 1, holderUC.ProofTemplateCount(credential.ID))
+		present(t, "template-nonce-2")
+		assert.Zero(t, holderUC.ProofTemplateCount(credential.ID))
+
+		// Without templates, proofs are derived from scratch
+		present(t, "template-nonce-3")
+	})
+
+	t.Run("Storing The Credential Again Drops Templates", func(t *testing.T) {
+		_, err := holderUC.PrecomputeProofs(holderDID, []string{credential.ID}, 3)
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		assert.Zero(t, holderUC.ProofTemplateCount(credential.ID))
+	})
+
+	t.Run("Status Change Drops Templates", func(t *testing.T) {
+		_, err := holderUC.PrecomputeProofs(holderDID, []string{credential.ID}, 3)
+		require.NoError(t, err)
+		_, err = issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		assert.Zero(t, holderUC.ProofTemplateCount(credential.ID))
+	})
+
+	t.Run("HTTP API", func(t *testing.T) {
+		server, err := httpServer.NewStackServer(stack, "0")
+		require.NoError(t, err)
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		post := func(t *testing.T, req dto.PrecomputeProofsRequest, status int) dto.PrecomputeProofsResponse {
+			data, err := json.Marshal(req)
+			require.NoError(t, err)
+			resp, err := http.Post(ts.URL+"/api/holder/proof-templates", "application/json", bytes.NewReader(data))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, status, resp.StatusCode)
+
+			var out dto.PrecomputeProofsResponse
+			if status == http.StatusOK {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+			}
+			return out
+		}
+
+		out := post(t, dto.PrecomputeProofsRequest{HolderDID: holderDID, CredentialIDs: []string{credential.ID}, Count: 4}, http.StatusOK)
+		assert.Equal(t, 4, out.Computed)
+
+		post(t, dto.PrecomputeProofsRequest{HolderDID: holderDID, Count: holder.MaxProofTemplates + 1}, http.StatusBadRequest)
+		post(t, dto.PrecomputeProofsRequest{HolderDID: "did:example:someone-else", CredentialIDs: []string{credential.ID}, Count: 1}, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/privacy"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestRedline tests that a holder previews, before presenting, which attributes of a credential a
+// disclosure would reveal and how sensitive each one is
+func TestRedline(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "firstName", Value: "An"},
+			{Key: "dateOfBirth", Value: "2000-01-20"},
+			{Key: "ageOver18", Value: true},
+			{Key: "memberNumber", Value: "M-1001", Disclosure: schema.DisclosureNever},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	post := func(t *testing.T, path string, body interface{}, response interface{}) int {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && response != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		}
+		return resp.StatusCode
+	}
+	attributes := func(redline *holder.CredentialRedline) map[string]holder.RedlineAttribute {
+		byName := make(map[string]holder.RedlineAttribute)
+		for _, attribute := range redline.Attributes {
+			byName[attribute.Name] = attribute
+		}
+		return byName
+	}
+
+	t.Run("Every Attribute Marked", func(t *testing.T) {
+		var redline holder.CredentialRedline
+		status := post(t, "/api/holder/credentials/"+credential.ID+"/redline", dto.RedlineCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"dateOfBirth"},
+			Purpose:            "age-over-18",
+		}, &redline)
+		require.Equal(t, http.StatusOK, status)
+
+		assert.Equal(t, credential.ID, redline.CredentialID)
+		assert.Equal(t, issuerSetup.DID.String(), redline.Issuer)
+		require.Len(t, redline.Attributes, 5)
+		assert.Equal(t, "id", redline.Attributes[0].Name, "attributes every presentation reveals come first")
+
+		byName := attributes(&redline)
+		assert.Equal(t, holder.DisclosureRevealed, byName["id"].Disclosure)
+		assert.True(t, byName["id"].Always)
+
+		dob := byName["dateOfBirth"]
+		assert.Equal(t, holder.DisclosureRevealed, dob.Disclosure)
+		assert.Equal(t, "2000-01-20", dob.Value)
+		assert.Equal(t, privacy.SensitivityHigh, dob.Level)
+		assert.Equal(t, "ageOver18", dob.Substitute, "a less revealing attribute serves the purpose")
+
+		assert.Equal(t, holder.DisclosureHidden, byName["ageOver18"].Disclosure)
+		assert.Equal(t, privacy.SensitivityLow, byName["ageOver18"].Level)
+		assert.Equal(t, holder.DisclosureHidden, byName["firstName"].Disclosure)
+		assert.Equal(t, schema.DisclosureNever, byName["memberNumber"].Restriction)
+
+		assert.Less(t, redline.Score, redline.MinimalScore)
+		assert.NotEmpty(t, redline.Warnings)
+		assert.Empty(t, redline.Refused)
+	})
+
+	t.Run("Restricted Disclosure Flagged", func(t *testing.T) {
+		var redline holder.CredentialRedline
+		status := post(t, "/api/holder/credentials/"+credential.ID+"/redline", dto.RedlineCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"memberNumber"},
+		}, &redline)
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, redline.Refused, "memberNumber may never be revealed")
+	})
+
+	t.Run("Presentation Request Previewed", func(t *testing.T) {
+		var report holder.RedlineReport
+		status := post(t, "/api/holder/presentations/redline", dto.CreatePresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Purpose: "age-over-18",
+		}, &report)
+		require.Equal(t, http.StatusOK, status)
+
+		assert.Equal(t, "age-over-18", report.Purpose)
+		require.Len(t, report.Credentials, 1)
+		redline := report.Credentials[0]
+		byName := attributes(redline)
+		assert.Equal(t, holder.DisclosureRevealed, byName["ageOver18"].Disclosure)
+		assert.Equal(t, holder.DisclosureHidden, byName["dateOfBirth"].Disclosure)
+		assert.Equal(t, redline.MinimalScore, redline.Score)
+		assert.Empty(t, redline.Warnings)
+	})
+
+	t.Run("Invalid Requests Rejected", func(t *testing.T) {
+		status := post(t, "/api/holder/credentials/"+credential.ID+"/redline", dto.RedlineCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"nationality"},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status, "the credential has no such attribute")
+
+		status = post(t, "/api/holder/credentials/"+credential.ID+"/redline", dto.RedlineCredentialRequest{
+			HolderDID:          "did:example:someone-else",
+			RevealedAttributes: []string{"ageOver18"},
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status = post(t, "/api/holder/credentials/urn:uuid:unknown/redline", dto.RedlineCredentialRequest{
+			HolderDID:          holderDID,
+			RevealedAttributes: []string{"ageOver18"},
+		}, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
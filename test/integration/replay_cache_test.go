@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestReplayCache tests that a presentation is accepted once, whatever nonce the verifier expects
+func TestReplayCache(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	cache := replay.NewMemoryCache(100)
+	verifierUC.SetReplayCache(cache)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T, nonce string, validFor time.Duration) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce:    nonce,
+			ValidFor: validFor,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, nonce string) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: nonce,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Second Verification Rejected", func(t *testing.T) {
+		presentation := present(t, "replay-nonce-54130e5f", 5*time.Minute)
+
+		first := verify(t, presentation, "replay-nonce-54130e5f")
+		assert.True(t, first.Valid, "errors: %v", first.Errors)
+
+		second := verify(t, presentation, "replay-nonce-54130e5f")
+		assert.False(t, second.Valid)
+		require.Len(t, second.Errors, 1)
+		assert.Contains(t, second.Errors[0], "presentation has already been verified")
+	})
+
+	t.Run("Independent Of Nonce Handling", func(t *testing.T) {
+		// A verifier that does not bind a nonce still sees the replay
+		presentation := present(t, "", 0)
+		assert.True(t, verify(t, presentation, "").Valid)
+
+		// Giving the copy a new presentation ID does not hide the reused proofs
+		copied := *presentation
+		copied.ID = "urn:uuid:copied"
+		assert.False(t, verify(t, &copied, "").Valid)
+	})
+
+	t.Run("Fresh Presentations Accepted", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			result := verify(t, present(t, "replay-nonce-54130e5f", 5*time.Minute), "replay-nonce-54130e5f")
+			assert.True(t, result.Valid, "errors: %v", result.Errors)
+		}
+	})
+
+	t.Run("Rejected Presentations Not Recorded", func(t *testing.T) {
+		presentation := present(t, "replay-nonce-54130e5f", 5*time.Minute)
+		assert.False(t, verify(t, presentation, "other-nonce").Valid)
+
+		result := verify(t, presentation, "replay-nonce-54130e5f")
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+}
@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestRequestMetadata tests carrying purpose and retention metadata from request to audit log
+func TestRequestMetadata(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	verifierSetup, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	request, err := verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+		RequiredClaims:    []string{"ageOver18"},
+		VerificationNonce: "metadata-nonce-2f174912",
+		RequestMetadata: vc.RequestMetadata{
+			Purpose:       "age-over-18",
+			RetentionDays: 30,
+			Verifier: &vc.VerifierIdentity{
+				DID:       verifierSetup.DID.String(),
+				LegalName: "Saigon Cinema Ltd",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+		HolderDID:     holderSetup.DID.String(),
+		CredentialIDs: []string{credential.ID},
+		SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: request.RequiredClaims},
+		},
+		Nonce:           request.VerificationNonce,
+		RequestMetadata: request.RequestMetadata,
+	})
+	require.NoError(t, err)
+
+	t.Run("Consent Record", func(t *testing.T) {
+		records, err := holderUC.ListConsentRecords(holderSetup.DID.String())
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+
+		record := records[0]
+		assert.Equal(t, presentation.ID, record.PresentationID)
+		assert.Equal(t, verifierSetup.DID.String(), record.VerifierDID)
+		assert.Equal(t, "age-over-18", record.Purpose)
+		assert.Equal(t, 30, record.RetentionDays)
+		assert.Equal(t, "Saigon Cinema Ltd", record.Verifier.LegalName)
+		assert.Equal(t, []string{"ageOver18"}, record.RevealedClaims[credential.ID])
+	})
+
+	t.Run("Verification Result And Audit Log", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    request.RequiredClaims,
+			VerificationNonce: request.VerificationNonce,
+			RequestMetadata:   request.RequestMetadata,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "Errors: %v", result.Errors)
+		assert.Equal(t, "age-over-18", result.Purpose)
+		assert.Equal(t, 30, result.RetentionDays)
+
+		entries, err := verifierUC.ListAuditLog()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, presentation.ID, entries[0].PresentationID)
+		assert.True(t, entries[0].Valid)
+		assert.Equal(t, []string{"ageOver18"}, entries[0].RevealedClaims)
+		require.NotNil(t, entries[0].RetainUntil)
+		assert.Equal(t, entries[0].Timestamp.AddDate(0, 0, 30), *entries[0].RetainUntil)
+	})
+
+	t.Run("Negative Retention Rejected", func(t *testing.T) {
+		_, err := verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+			RequiredClaims:  []string{"ageOver18"},
+			RequestMetadata: vc.RequestMetadata{RetentionDays: -1},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestRequestTemplates tests that named request templates expand into verification requests and
+// verifications, keeping what the caller sets itself
+func TestRequestTemplates(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T, nonce string, revealed ...string) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: revealed},
+			},
+			Nonce: nonce,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	t.Run("Create Request From Template", func(t *testing.T) {
+		request, err := verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{
+			Template:       "kyc-lite",
+			RequiredClaims: []string{"nationality"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+			RequestMetadata: vc.RequestMetadata{
+				RetentionDays: 30,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"nationality", "firstName", "lastName", "dateOfBirth", "idNumber"}, request.RequiredClaims)
+		assert.Equal(t, []string{"address"}, request.OptionalClaims, "a claim the caller requires stays required")
+		assert.Equal(t, verifier.OverDisclosureReject, request.OverDisclosure)
+		assert.Equal(t, 15*time.Minute, request.MaxPresentationAge)
+		assert.Equal(t, "identity-verification", request.Purpose)
+		assert.Equal(t, 30, request.RetentionDays, "the caller's retention period is kept")
+		assert.NotEmpty(t, request.VerificationNonce)
+
+		_, err = verifierUC.CreateVerificationRequest(verifier.CreateVerificationRequestParams{Template: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Verify With Template", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      present(t, "template-nonce-1", "ageOver18"),
+			Template:          "age-over-18",
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: "template-nonce-1",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, "age-over-18", result.Purpose)
+
+		// The template rejects claims it does not ask for
+		result, err = verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      present(t, "template-nonce-2", "ageOver18", "nationality"),
+			Template:          "age-over-18",
+			TrustedIssuers:    []string{issuerSetup.DID.String()},
+			VerificationNonce: "template-nonce-2",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{"nationality"}, result.OverDisclosedClaims)
+	})
+
+	t.Run("HTTP API", func(t *testing.T) {
+		server, err := httpServer.NewStackServer(stack, "0")
+		require.NoError(t, err)
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		resp, err := http.Get(ts.URL + "/api/verifier/request-templates")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var templates dto.ListRequestTemplatesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&templates))
+		var names []string
+		for _, template := range templates.Templates {
+			names = append(names, template.Name)
+		}
+		assert.Equal(t, []string{"age-over-18", "kyc-lite", "proof-of-degree", "proof-of-employment"}, names)
+
+		post := func(t *testing.T, path string, body interface{}) *http.Response {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+			require.NoError(t, err)
+			return resp
+		}
+
+		resp = post(t, "/api/verifier/verification-request", dto.CreateVerificationRequestRequest{Template: "proof-of-degree"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var request dto.CreateVerificationRequestResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&request))
+		assert.Equal(t, []string{"degree", "university"}, request.RequiredClaims)
+		assert.Equal(t, []string{"UniversityDegreeCredential"}, request.ClaimConstraints["degree"].CredentialTypes)
+		assert.Equal(t, 3600, request.MaxPresentationAgeSeconds)
+
+		resp = post(t, "/api/verifier/verify", dto.VerifyPresentationRequest{
+			Presentation: present(t, "template-nonce-3", "ageOver18"),
+			Template:     "unknown",
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestResolutionCache tests that repeated verifications reuse resolved issuer DIDs, and that key
+// rotations and revocations are still seen right away
+func TestResolutionCache(t *testing.T) {
+	// The verifier caches resolved DIDs and fetched status lists
+	stack, err := sdk.NewStack(sdk.Config{ResolutionCacheTTL: time.Hour})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	issue := func(t *testing.T) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	verify := func(t *testing.T, credential *vc.VerifiableCredential) *verifier.VerificationResult {
+		nonce, err := verifierUC.GenerateNonce()
+		require.NoError(t, err)
+
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: nonce,
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			TrustedIssuers:    []string{issuerDID},
+			VerificationNonce: nonce,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	credential := issue(t)
+
+	t.Run("Repeated Verifications Hit The Cache", func(t *testing.T) {
+		before := verifierUC.CacheStats()["dids"]
+		for i := 0; i < 3; i++ {
+			result := verify(t, credential)
+			assert.True(t, result.Valid, "errors: %v", result.Errors)
+		}
+
+		after := verifierUC.CacheStats()["dids"]
+		assert.Greater(t, after.Hits, before.Hits)
+		assert.Greater(t, after.HitRate(), 0.0)
+		assert.Contains(t, verifierUC.CacheStats(), "statusLists")
+	})
+
+	t.Run("Revocation Is Seen Immediately", func(t *testing.T) {
+		revoked := issue(t)
+		result := verify(t, revoked)
+		require.True(t, result.Valid, "errors: %v", result.Errors)
+
+		_, err := issuerUC.RevokeCredential(revoked.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+
+		result = verify(t, revoked)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Key Rotation Invalidates The Issuer Document", func(t *testing.T) {
+		_, err := issuerUC.RotateKeys(issuerDID)
+		require.NoError(t, err)
+
+		result := verify(t, issue(t))
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/internal/scenario"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+)
+
+// TestScenarioRunner tests executing the age verification demo server-side step by step
+func TestScenarioRunner(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	runner := scenario.NewRunner(issuerUC, holderUC, verifierUC)
+
+	// waitForRun follows the run's updates until it finishes
+	waitForRun := func(t *testing.T, id string) *scenario.Run {
+		updates, stop, err := runner.Watch(id)
+		require.NoError(t, err)
+		defer stop()
+
+		timeout := time.After(10 * time.Second)
+		for {
+			run, err := runner.GetRun(id)
+			require.NoError(t, err)
+			if run.Done() {
+				return run
+			}
+
+			select {
+			case <-updates:
+			case <-timeout:
+				t.Fatalf("run %s did not finish in time", id)
+			}
+		}
+	}
+
+	t.Run("Adult Granted Access", func(t *testing.T) {
+		run, err := runner.StartAgeDemo(scenario.AgeDemoParams{ServiceType: "cinema", MinAge: 18})
+		require.NoError(t, err)
+		assert.Equal(t, scenario.AgeDemoScenario, run.Scenario)
+		require.Len(t, run.Steps, 7)
+
+		run = waitForRun(t, run.ID)
+		assert.Equal(t, scenario.StatusCompleted, run.Status)
+		assert.Empty(t, run.Error)
+		assert.NotNil(t, run.FinishedAt)
+
+		for _, step := range run.Steps {
+			assert.Equal(t, scenario.StatusCompleted, step.Status, step.Title)
+			assert.NotNil(t, step.StartedAt)
+			assert.NotNil(t, step.FinishedAt)
+		}
+
+		outcome := run.Steps[6].Result
+		assert.Equal(t, true, outcome["valid"])
+		assert.Equal(t, true, outcome["accessGranted"])
+
+		revealed := outcome["revealedClaims"].(map[string]interface{})
+		assert.Contains(t, revealed, "ageOver18")
+		assert.NotContains(t, revealed, "dateOfBirth")
+		assert.NotContains(t, revealed, "firstName")
+
+		// The citizen's credential is stored in the holder's wallet
+		holderDID := run.Steps[1].Result["holderDid"].(string)
+		credentials, err := holderUC.ListCredentials(holderDID)
+		require.NoError(t, err)
+		assert.Len(t, credentials, 1)
+	})
+
+	t.Run("Minor Denied Access", func(t *testing.T) {
+		dateOfBirth := time.Now().AddDate(-15, 0, 0).Format("2006-01-02")
+		run, err := runner.StartAgeDemo(scenario.AgeDemoParams{MinAge: 21, DateOfBirth: dateOfBirth})
+		require.NoError(t, err)
+
+		run = waitForRun(t, run.ID)
+		assert.Equal(t, scenario.StatusCompleted, run.Status)
+		assert.Equal(t, true, run.Steps[6].Result["valid"])
+		assert.Equal(t, false, run.Steps[6].Result["accessGranted"])
+	})
+
+	t.Run("Step Delay", func(t *testing.T) {
+		run, err := runner.StartAgeDemo(scenario.AgeDemoParams{StepDelay: 5 * time.Millisecond})
+		require.NoError(t, err)
+
+		run = waitForRun(t, run.ID)
+		assert.Equal(t, scenario.StatusCompleted, run.Status)
+		assert.GreaterOrEqual(t, run.FinishedAt.Sub(run.CreatedAt), 30*time.Millisecond)
+	})
+
+	t.Run("Invalid Parameters", func(t *testing.T) {
+		_, err := runner.StartAgeDemo(scenario.AgeDemoParams{MinAge: 19})
+		assert.Error(t, err)
+
+		_, err = runner.StartAgeDemo(scenario.AgeDemoParams{DateOfBirth: "not-a-date"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown Run", func(t *testing.T) {
+		_, err := runner.GetRun("missing")
+		assert.Error(t, err)
+
+		_, _, err = runner.Watch("missing")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/secrets"
+)
+
+// TestSecretReferences tests that API tokens configured as secret references are read from where
+// the secret is kept
+func TestSecretReferences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_tokens")
+	require.NoError(t, os.WriteFile(path, []byte("file-token-1, file-token-2\n"), 0o600))
+	t.Setenv("SECRET_REFERENCES_TEST_TOKEN", "env-token")
+
+	stack, err := sdk.NewStack(sdk.Config{
+		APITokens: []string{"file:" + path, "env:SECRET_REFERENCES_TEST_TOKEN", "literal-token"},
+		Secrets:   secrets.NewResolver(),
+	})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	setup := func(t *testing.T, token string) int {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/issuer/setup", strings.NewReader(`{"method":"example"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Resolved Tokens Are Accepted", func(t *testing.T) {
+		for _, token := range []string{"file-token-1", "file-token-2", "env-token", "literal-token"} {
+			assert.Equal(t, http.StatusOK, setup(t, token), token)
+		}
+	})
+
+	t.Run("References Are Not Tokens", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, setup(t, "file:"+path))
+		assert.Equal(t, http.StatusUnauthorized, setup(t, "env:SECRET_REFERENCES_TEST_TOKEN"))
+	})
+
+	t.Run("Unresolvable References Fail", func(t *testing.T) {
+		_, err := sdk.NewStack(sdk.Config{
+			APITokens: []string{"env:SECRET_REFERENCES_TEST_UNSET"},
+			Secrets:   secrets.NewResolver(),
+		})
+		assert.ErrorIs(t, err, secrets.ErrNotFound)
+	})
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/exchange"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestSharedStorage tests two server instances sharing one key-value store: a session opened on one
+// is answered on the other, and each sees the DID documents, credentials and presentations the
+// other stored
+func TestSharedStorage(t *testing.T) {
+	shared := storage.NewMemoryStore()
+	bbsService := bbs.NewService()
+
+	type instance struct {
+		issuer      *issuer.UseCase
+		holder      *holder.UseCase
+		verifier    *verifier.UseCase
+		holderCreds vc.CredentialRepository
+		presRepo    vc.PresentationRepository
+	}
+	newInstance := func() instance {
+		stack, err := sdk.NewStack(sdk.Config{Store: shared, Stateless: true, BBS: bbsService})
+		require.NoError(t, err)
+		stack.Verifier.SetReplayCache(replay.NewKVCache(storage.Prefixed(shared, "replay:")))
+
+		return instance{
+			issuer:      stack.Issuer.UseCase,
+			holder:      stack.Holder.UseCase,
+			verifier:    stack.Verifier.UseCase,
+			holderCreds: stack.Holder.Credentials,
+			presRepo:    stack.Verifier.Presentations,
+		}
+	}
+	a, b := newInstance(), newInstance()
+
+	issuerSetup, err := a.issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := a.holder.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := a.verifier.SetupVerifier("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := a.issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "firstName", Value: "An"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, a.holder.StoreCredential(credential))
+
+	t.Run("Credentials Are Shared", func(t *testing.T) {
+		credentials, err := b.holderCreds.List(holderDID)
+		require.NoError(t, err)
+		require.Len(t, credentials, 1)
+		assert.Equal(t, credential.ID, credentials[0].ID)
+	})
+
+	t.Run("Session Answered On Another Instance", func(t *testing.T) {
+		session, err := a.verifier.CreateSession(verifierSetup.DID.String(), exchange.ProofRequest{
+			RequiredClaims: []string{"ageOver18"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+		}, 0)
+		require.NoError(t, err)
+
+		fetched, err := b.verifier.FetchSessionRequest(session.ID)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionRetrieved, fetched.State)
+
+		presentation, err := a.holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: fetched.Request.Nonce,
+		})
+		require.NoError(t, err)
+
+		result, err := b.verifier.SubmitSessionPresentation(context.Background(), session.ID, presentation)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+
+		// The instance that opened the session sees the result
+		done, err := a.verifier.WaitForSession(context.Background(), session.ID, verifier.SessionRetrieved)
+		require.NoError(t, err)
+		assert.Equal(t, verifier.SessionCompleted, done.State)
+		assert.Equal(t, holderDID, done.HolderDID)
+		require.NotNil(t, done.Result)
+		assert.Equal(t, true, done.Result.RevealedClaims["ageOver18"])
+
+		// Neither instance accepts a second answer
+		_, err = a.verifier.SubmitSessionPresentation(context.Background(), session.ID, presentation)
+		assert.Error(t, err)
+
+		records, err := a.presRepo.List(vc.PresentationFilter{HolderDID: holderDID})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, presentation.ID, records[0].Presentation.ID)
+		assert.True(t, records[0].Valid)
+	})
+
+	t.Run("Replays Rejected Across Instances", func(t *testing.T) {
+		presentation, err := a.holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+		})
+		require.NoError(t, err)
+
+		request := verifier.VerificationRequest{Presentation: presentation, RequiredClaims: []string{"ageOver18"}}
+		first, err := a.verifier.VerifyPresentation(request)
+		require.NoError(t, err)
+		assert.True(t, first.Valid, first.Errors)
+
+		second, err := b.verifier.VerifyPresentation(request)
+		require.NoError(t, err)
+		assert.False(t, second.Valid)
+		require.NotEmpty(t, second.Errors)
+		assert.Contains(t, second.Errors[0], "already been verified")
+	})
+
+	t.Run("Purge Removes Shared Sessions", func(t *testing.T) {
+		session, err := a.verifier.CreateSession(verifierSetup.DID.String(), exchange.ProofRequest{
+			RequiredClaims: []string{"ageOver18"},
+		}, 0)
+		require.NoError(t, err)
+
+		assert.GreaterOrEqual(t, b.verifier.PurgeExpiredSessions(session.ExpiresAt.Add(1)), 1)
+		_, err = a.verifier.GetSession(session.ID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+)
+
+// TestSplitServers tests the issuer, wallet and verifier servers running as separate processes, as
+// they would in separate containers: each exposes only its role's endpoints, and credentials are
+// issued, presented, verified and revoked across them with DIDs and status lists resolved over HTTP
+func TestSplitServers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and starts three servers")
+	}
+
+	bin := t.TempDir()
+	build := exec.Command("go", "build", "-o", bin+string(filepath.Separator),
+		"github.com/lugondev/bbs-selective-disclosure-example/cmd/issuer-server",
+		"github.com/lugondev/bbs-selective-disclosure-example/cmd/wallet-server",
+		"github.com/lugondev/bbs-selective-disclosure-example/cmd/verifier-server",
+	)
+	output, err := build.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	freePort := func(t *testing.T) string {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		return fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)
+	}
+	issuerPort, walletPort, verifierPort := freePort(t), freePort(t), freePort(t)
+	issuerURL := "http://127.0.0.1:" + issuerPort
+	walletURL := "http://127.0.0.1:" + walletPort
+	verifierURL := "http://127.0.0.1:" + verifierPort
+
+	start := func(t *testing.T, name, port string, peers ...string) {
+		var logs bytes.Buffer
+		cmd := exec.Command(filepath.Join(bin, name), "-port", port, "-resolution-cache-ttl", "0",
+			"-peers", peers[0]+","+peers[1])
+		cmd.Dir = bin
+		cmd.Stdout = &logs
+		cmd.Stderr = &logs
+		require.NoError(t, cmd.Start())
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			if t.Failed() {
+				t.Logf("%s output:\n%s", name, logs.String())
+			}
+		})
+	}
+	start(t, "issuer-server", issuerPort, walletURL, verifierURL)
+	start(t, "wallet-server", walletPort, issuerURL, verifierURL)
+	start(t, "verifier-server", verifierPort, issuerURL, walletURL)
+
+	for _, base := range []string{issuerURL, walletURL, verifierURL} {
+		require.Eventually(t, func() bool {
+			resp, err := http.Get(base + "/ready")
+			if err != nil {
+				return false
+			}
+			resp.Body.Close()
+			return resp.StatusCode == http.StatusOK
+		}, 30*time.Second, 50*time.Millisecond, "%s did not become ready", base)
+	}
+
+	post := func(t *testing.T, url string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode, url)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	t.Run("Each Server Exposes Only Its Role", func(t *testing.T) {
+		post(t, walletURL+"/api/issuer/setup", http.StatusNotFound, dto.SetupIssuerRequest{Method: "example"}, nil)
+		post(t, verifierURL+"/api/holder/setup", http.StatusNotFound, dto.SetupHolderRequest{Method: "example"}, nil)
+		post(t, issuerURL+"/api/verifier/setup", http.StatusNotFound, dto.SetupVerifierRequest{Method: "example"}, nil)
+		post(t, issuerURL+"/api/age-verification/requests", http.StatusNotFound, nil, nil)
+	})
+
+	var issuerSetup dto.SetupIssuerResponse
+	post(t, issuerURL+"/api/issuer/setup", http.StatusOK, dto.SetupIssuerRequest{Method: "example"}, &issuerSetup)
+	var holderSetup dto.SetupHolderResponse
+	post(t, walletURL+"/api/holder/setup", http.StatusOK, dto.SetupHolderRequest{Method: "example"}, &holderSetup)
+	var verifierSetup dto.SetupVerifierResponse
+	post(t, verifierURL+"/api/verifier/setup", http.StatusOK, dto.SetupVerifierRequest{Method: "example"}, &verifierSetup)
+
+	var issued dto.IssueCredentialResponse
+	post(t, issuerURL+"/api/issuer/credentials", http.StatusOK, dto.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID,
+		SubjectDID: holderSetup.DID,
+		Claims: []dto.ClaimDTO{
+			{Key: "ageOver18", Value: true},
+			{Key: "firstName", Value: "An"},
+		},
+	}, &issued)
+	post(t, walletURL+"/api/holder/credentials", http.StatusOK, dto.StoreCredentialRequest{Credential: issued.Credential}, nil)
+
+	// present answers a session the verifier opened with a presentation the wallet derives
+	present := func(t *testing.T) dto.SubmitSessionPresentationResponse {
+		var session dto.CreateSessionResponse
+		post(t, verifierURL+"/api/verifier/sessions", http.StatusCreated, dto.CreateSessionRequest{
+			VerifierDID:    verifierSetup.DID,
+			RequiredClaims: []string{"ageOver18"},
+			TrustedIssuers: []string{issuerSetup.DID},
+		}, &session)
+
+		var created dto.CreatePresentationResponse
+		post(t, walletURL+"/api/holder/presentations", http.StatusOK, dto.CreatePresentationRequest{
+			HolderDID:     holderSetup.DID,
+			CredentialIDs: []string{issued.CredentialID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: issued.CredentialID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: session.Nonce,
+		}, &created)
+
+		var result dto.SubmitSessionPresentationResponse
+		post(t, verifierURL+"/api/verifier/sessions/"+session.SessionID+"/presentation", http.StatusOK,
+			dto.SubmitSessionPresentationRequest{Presentation: created.Presentation}, &result)
+		return result
+	}
+
+	t.Run("Presentation Verifies Across Processes", func(t *testing.T) {
+		result := present(t)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Revocation Reaches The Verifier", func(t *testing.T) {
+		post(t, issuerURL+"/api/issuer/credentials/"+issued.CredentialID+"/revoke", http.StatusOK, nil, nil)
+
+		result := present(t)
+		assert.False(t, result.Valid)
+		assert.Contains(t, fmt.Sprint(result.Errors), "revoked")
+	})
+
+	t.Run("DID Documents Are Published", func(t *testing.T) {
+		resp, err := http.Get(issuerURL + "/api/dids/" + issuerSetup.DID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var doc struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+		assert.Equal(t, issuerSetup.DID, doc.ID)
+
+		resp, err = http.Get(issuerURL + "/api/dids/" + holderSetup.DID)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, "servers publish only their own DIDs")
+	})
+}
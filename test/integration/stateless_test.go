@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/handlers"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/replay"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestStatelessMode tests two server instances wired as with -stateless over one store: every step
+// of issuance, presentation, verification and revocation may land on either instance
+func TestStatelessMode(t *testing.T) {
+	shared := storage.NewMemoryStore()
+
+	newInstance := func() *httptest.Server {
+		stack, err := sdk.NewStack(sdk.Config{Store: shared, Stateless: true})
+		require.NoError(t, err)
+		stack.Verifier.SetReplayCache(replay.NewKVCache(storage.Prefixed(shared, "replay:")))
+
+		server, err := httpServer.NewStackServer(stack, "0")
+		require.NoError(t, err)
+		return httptest.NewServer(server.Handler())
+	}
+	a, b := newInstance(), newInstance()
+	defer a.Close()
+	defer b.Close()
+
+	post := func(t *testing.T, ts *httptest.Server, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	var issuerSetup dto.SetupIssuerResponse
+	post(t, a, "/api/issuer/setup", http.StatusOK, dto.SetupIssuerRequest{Method: "example"}, &issuerSetup)
+	var holderSetup dto.SetupHolderResponse
+	post(t, b, "/api/holder/setup", http.StatusOK, dto.SetupHolderRequest{Method: "example"}, &holderSetup)
+
+	// Issued on the instance that did not set the issuer up, stored through the other
+	var issued dto.IssueCredentialResponse
+	post(t, b, "/api/issuer/credentials", http.StatusOK, dto.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID,
+		SubjectDID: holderSetup.DID,
+		Claims: []dto.ClaimDTO{
+			{Key: "ageOver18", Value: true},
+			{Key: "firstName", Value: "An"},
+		},
+	}, &issued)
+	post(t, a, "/api/holder/credentials", http.StatusOK, dto.StoreCredentialRequest{Credential: issued.Credential}, nil)
+
+	present := func(t *testing.T, ts *httptest.Server) *vc.VerifiablePresentation {
+		var created dto.CreatePresentationResponse
+		post(t, ts, "/api/holder/presentations", http.StatusOK, dto.CreatePresentationRequest{
+			HolderDID:     holderSetup.DID,
+			CredentialIDs: []string{issued.CredentialID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: issued.CredentialID, RevealedAttributes: []string{"ageOver18"}},
+			},
+		}, &created)
+		return created.Presentation
+	}
+	verify := func(t *testing.T, ts *httptest.Server, presentation *vc.VerifiablePresentation) dto.VerifyPresentationResponse {
+		var result dto.VerifyPresentationResponse
+		post(t, ts, "/api/verifier/verify", http.StatusOK, dto.VerifyPresentationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"ageOver18"},
+			TrustedIssuers: []string{issuerSetup.DID},
+		}, &result)
+		return result
+	}
+
+	t.Run("Presentation Verifies On Either Instance", func(t *testing.T) {
+		result := verify(t, b, present(t, a))
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, true, result.RevealedClaims["ageOver18"])
+
+		result = verify(t, a, present(t, b))
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Age Check Answered Across Instances", func(t *testing.T) {
+		var ageIssued struct {
+			Credential *vc.VerifiableCredential `json:"credential"`
+		}
+		post(t, a, "/api/age-verification/credential", http.StatusCreated, handlers.AgeCredentialRequest{
+			IssuerDID:   issuerSetup.DID,
+			SubjectDID:  holderSetup.DID,
+			FirstName:   "An",
+			LastName:    "Nguyen",
+			DateOfBirth: "1990-05-20",
+			Nationality: "Vietnamese",
+		}, &ageIssued)
+		post(t, b, "/api/holder/credentials", http.StatusOK, dto.StoreCredentialRequest{Credential: ageIssued.Credential}, nil)
+
+		var opened handlers.AgeVerificationRequestResponse
+		post(t, b, "/api/age-verification/requests", http.StatusCreated, handlers.AgeVerificationRequest{
+			MinAge:         18,
+			ServiceType:    "gaming",
+			TrustedIssuers: []string{issuerSetup.DID},
+		}, &opened)
+
+		var presented handlers.AgePresentationResponse
+		post(t, a, "/api/age-verification/presentations", http.StatusCreated, handlers.AgePresentationRequest{
+			HolderDID:    holderSetup.DID,
+			CredentialID: ageIssued.Credential.ID,
+			Request:      opened.Request,
+		}, &presented)
+
+		var result handlers.AgeVerificationResponse
+		post(t, a, opened.VerifyURI, http.StatusOK, handlers.AgeVerificationSubmission{Presentation: presented.Presentation}, &result)
+		assert.True(t, result.AccessGranted)
+	})
+
+	t.Run("Revocation On One Instance Rejected On The Other", func(t *testing.T) {
+		var revoked dto.CredentialStatusResponse
+		post(t, a, "/api/issuer/credentials/"+issued.CredentialID+"/revoke", http.StatusOK, nil, &revoked)
+		assert.True(t, revoked.Revoked)
+
+		resp, err := http.Get(b.URL + "/api/issuer/credentials/" + issued.CredentialID + "/status")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var current dto.CredentialStatusResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&current))
+		assert.True(t, current.Revoked)
+
+		result := verify(t, b, present(t, b))
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "revoked")
+	})
+}
@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestStatusNotifications tests that status changes are pushed to the holder's webhook, flag the
+// credential in the wallet and refresh its status snapshots
+func TestStatusNotifications(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{PrivateWebhooks: true})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	statusRegistry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(statusRegistry)
+	verifierUC.SetStatusRegistry(statusRegistry)
+	holderUC.SetStatusSnapshotSource(issuerUC)
+	holderUC.SetStatusSubscriber(issuerUC)
+
+	var handledMu sync.Mutex
+	var handled []vc.StatusNotification
+	issuerUC.OnStatusChange(func(notification vc.StatusNotification) {
+		handledMu.Lock()
+		defer handledMu.Unlock()
+		handled = append(handled, notification)
+	})
+
+	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderDID := holderSetup.DID.String()
+
+	issue := func(t *testing.T) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderDID,
+			Claims:     []vc.Claim{{Key: "licenseClass", Value: "B"}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	var subscription vc.StatusSubscription
+	post(t, "/api/holder/status-subscriptions", http.StatusCreated, dto.SubscribeToStatusRequest{
+		HolderDID: holderDID,
+		IssuerDID: issuerDID,
+		Endpoint:  ts.URL + "/api/holder/status-notifications",
+	}, &subscription)
+	require.NotNil(t, subscription.Proof)
+
+	awaitNotice := func(t *testing.T, credentialID string, event vc.StatusEvent) *vc.StatusNotification {
+		var notice *vc.StatusNotification
+		require.Eventually(t, func() bool {
+			var err error
+			notice, err = holderUC.GetStatusNotice(credentialID)
+			return err == nil && notice != nil && notice.Event == event
+		}, 5*time.Second, 10*time.Millisecond)
+		return notice
+	}
+
+	t.Run("Revocation Reaches The Wallet", func(t *testing.T) {
+		credential := issue(t)
+		effective := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		post(t, "/api/issuer/credentials/"+credential.ID+"/revoke", http.StatusOK,
+			dto.UpdateCredentialStatusRequest{Reason: "keyCompromise", EffectiveDate: &effective}, nil)
+
+		notice := awaitNotice(t, credential.ID, vc.StatusEventRevoked)
+		assert.Equal(t, vc.StatusReasonKeyCompromise, notice.Reason)
+		assert.True(t, effective.Equal(notice.EffectiveDate))
+		assert.Equal(t, issuerDID, notice.Issuer)
+
+		resp, err := http.Get(ts.URL + "/api/holder/credentials/list?holderDid=" + holderDID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var listed dto.ListCredentialsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+		require.Len(t, listed.StatusNotices, 1)
+		assert.Equal(t, credential.ID, listed.StatusNotices[0].CredentialID)
+
+		// The wallet fetched fresh snapshots, so presentations carry the revoked status
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+			IncludeStatusSnapshots: true,
+		})
+		require.NoError(t, err)
+		revoked := false
+		for _, snapshot := range presentation.StatusSnapshots {
+			if snapshot.StatusPurpose == status.PurposeRevocation {
+				revoked = snapshot.Status
+			}
+		}
+		assert.True(t, revoked)
+	})
+
+	t.Run("Suspension And Reinstatement", func(t *testing.T) {
+		credential := issue(t)
+		post(t, "/api/issuer/credentials/"+credential.ID+"/suspend", http.StatusOK, nil, nil)
+		notice := awaitNotice(t, credential.ID, vc.StatusEventSuspended)
+		assert.Equal(t, vc.StatusReasonCertificateHold, notice.Reason, "suspensions default to certificateHold")
+
+		_, err := issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		notice = awaitNotice(t, credential.ID, vc.StatusEventReinstated)
+		assert.Equal(t, vc.StatusReasonUnspecified, notice.Reason)
+
+		handledMu.Lock()
+		defer handledMu.Unlock()
+		var events []vc.StatusEvent
+		for _, notification := range handled {
+			if notification.CredentialID == credential.ID {
+				events = append(events, notification.Event)
+			}
+		}
+		assert.Equal(t, []vc.StatusEvent{vc.StatusEventSuspended, vc.StatusEventReinstated}, events)
+	})
+
+	t.Run("Invalid Changes Are Refused", func(t *testing.T) {
+		credential := issue(t)
+		post(t, "/api/issuer/credentials/"+credential.ID+"/revoke", http.StatusBadRequest,
+			dto.UpdateCredentialStatusRequest{Reason: "tired"}, nil)
+
+		future := time.Now().Add(24 * time.Hour)
+		post(t, "/api/issuer/credentials/"+credential.ID+"/revoke", http.StatusBadRequest,
+			dto.UpdateCredentialStatusRequest{EffectiveDate: &future}, nil)
+
+		current, err := issuerUC.GetCredentialStatus(credential.ID)
+		require.NoError(t, err)
+		assert.False(t, current.Revoked)
+	})
+
+	t.Run("Forged Notifications Are Rejected", func(t *testing.T) {
+		credential := issue(t)
+		_, err := issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		notice := awaitNotice(t, credential.ID, vc.StatusEventSuspended)
+
+		forged := *notice
+		forged.Event = vc.StatusEventRevoked
+		forged.Created = notice.Created.Add(time.Second)
+		post(t, "/api/holder/status-notifications", http.StatusBadRequest, forged, nil)
+
+		// A replayed older notification is accepted but does not replace the newer flag
+		_, err = issuerUC.UnsuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		awaitNotice(t, credential.ID, vc.StatusEventReinstated)
+		post(t, "/api/holder/status-notifications", http.StatusNoContent, notice, nil)
+		current, err := holderUC.GetStatusNotice(credential.ID)
+		require.NoError(t, err)
+		assert.Equal(t, vc.StatusEventReinstated, current.Event)
+	})
+
+	t.Run("Subscriptions Must Be Signed By The Holder", func(t *testing.T) {
+		unsigned := subscription
+		unsigned.Proof = nil
+		unsigned.Created = time.Now().UTC()
+		post(t, "/api/issuer/status-subscriptions", http.StatusBadRequest, unsigned, nil)
+
+		redirected := subscription
+		redirected.Endpoint = "https://attacker.example/hooks"
+		redirected.Created = time.Now().UTC()
+		post(t, "/api/issuer/status-subscriptions", http.StatusBadRequest, redirected, nil)
+
+		// Replaying the holder's own subscription cannot roll back a newer one
+		assert.Error(t, issuerUC.SubscribeToStatus(&subscription))
+	})
+}
@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestStatusSnapshots tests verifying credential status offline from issuer-signed snapshots embedded by the holder
+func TestStatusSnapshots(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+
+	// The verifier has no status registry: it can only check status from snapshots
+	offlineVerifier := verifier.NewUseCase(stack.DIDService, stack.Verifier.CredentialService, stack.Verifier.Presentations)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	issue := func(t *testing.T) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderDID,
+			Claims:     []vc.Claim{{Key: "licenseClass", Value: "B"}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	present := func(t *testing.T, credential *vc.VerifiableCredential, snapshots bool) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+			Nonce:                  "snapshot-nonce-67b7b371",
+			IncludeStatusSnapshots: snapshots,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, maxStatusAge time.Duration) *verifier.VerificationResult {
+		result, err := offlineVerifier.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"licenseClass"},
+			VerificationNonce: "snapshot-nonce-67b7b371",
+			MaxStatusAge:      maxStatusAge,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Offline Verification", func(t *testing.T) {
+		credential := issue(t)
+		refreshed, err := holderUC.RefreshStatusSnapshots(holderDID)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, refreshed, 1)
+
+		presentation := present(t, credential, true)
+		require.Len(t, presentation.StatusSnapshots, 2, "one snapshot per status purpose")
+		for _, snapshot := range presentation.StatusSnapshots {
+			assert.Equal(t, issuerSetup.DID.String(), snapshot.Issuer)
+			assert.False(t, snapshot.Status)
+		}
+
+		// Snapshots survive the JSON round trip to a remote verifier
+		data, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		decoded := &vc.VerifiablePresentation{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+
+		result := verify(t, decoded, 0)
+		assert.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Without Snapshots", func(t *testing.T) {
+		result := verify(t, present(t, issue(t), false), 0)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "no status registry configured")
+	})
+
+	t.Run("Revoked Credential", func(t *testing.T) {
+		credential := issue(t)
+		_, err := issuerUC.RevokeCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		_, err = holderUC.RefreshStatusSnapshots(holderDID)
+		require.NoError(t, err)
+
+		result := verify(t, present(t, credential, true), 0)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "credential has been revoked")
+	})
+
+	t.Run("Stale Snapshot", func(t *testing.T) {
+		require.NoError(t, offlineVerifier.SetFreshnessPolicy(verifier.FreshnessPolicy{}))
+		defer func() {
+			require.NoError(t, offlineVerifier.SetFreshnessPolicy(verifier.FreshnessPolicy{ClockSkew: verifier.DefaultClockSkew}))
+		}()
+
+		presentation := present(t, issue(t), true)
+		time.Sleep(5 * time.Millisecond)
+
+		result := verify(t, presentation, time.Millisecond)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "stale")
+	})
+
+	t.Run("Forged Snapshot", func(t *testing.T) {
+		credential := issue(t)
+		_, err := issuerUC.RevokeCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+
+		// Claim an unrevoked state with a snapshot the issuer never signed
+		snapshots, err := issuerUC.IssueStatusSnapshots(credential.ID)
+		require.NoError(t, err)
+		for _, snapshot := range snapshots {
+			snapshot.Status = false
+		}
+
+		// The holder signs over the forged snapshots so only the issuer's signature can catch them
+		presentation := present(t, credential, false)
+		presentation.StatusSnapshots = snapshots
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		result := verify(t, presentation, 0)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "signature verification failed")
+	})
+
+	t.Run("Snapshots Disabled", func(t *testing.T) {
+		require.NoError(t, offlineVerifier.SetMaxStatusAge(0))
+		defer func() {
+			require.NoError(t, offlineVerifier.SetMaxStatusAge(verifier.DefaultMaxStatusAge))
+		}()
+
+		result := verify(t, present(t, issue(t), true), 0)
+		assert.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0], "no status registry configured")
+
+		assert.Error(t, offlineVerifier.SetMaxStatusAge(-time.Second))
+	})
+}
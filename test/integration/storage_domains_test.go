@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/handlers"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestStorageDomains tests that issuer, holder and verifier keep their own storage, with
+// credentials reaching the holder only through the holder API
+func TestStorageDomains(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}, out interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if out != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+		}
+	}
+
+	listWallet := func(t *testing.T) []*vc.VerifiableCredential {
+		resp, err := http.Get(ts.URL + "/api/holder/credentials/list?holderDid=" + url.QueryEscape(holderDID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response dto.ListCredentialsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return response.Credentials
+	}
+
+	var issued struct {
+		Credential *vc.VerifiableCredential `json:"credential"`
+	}
+	post(t, "/api/age-verification/credential", http.StatusCreated, handlers.AgeCredentialRequest{
+		IssuerDID:   issuerSetup.DID.String(),
+		SubjectDID:  holderDID,
+		FirstName:   "Jane",
+		LastName:    "Doe",
+		DateOfBirth: "1990-05-17",
+		Nationality: "US",
+	}, &issued)
+	require.NotNil(t, issued.Credential)
+
+	t.Run("Issuance Does Not Reach The Wallet", func(t *testing.T) {
+		assert.Empty(t, listWallet(t))
+	})
+
+	t.Run("Holder Stores Delivered Credential", func(t *testing.T) {
+		post(t, "/api/holder/credentials", http.StatusOK, dto.StoreCredentialRequest{Credential: issued.Credential}, nil)
+
+		credentials := listWallet(t)
+		require.Len(t, credentials, 1)
+		assert.Equal(t, issued.Credential.ID, credentials[0].ID)
+
+		// The issuer's storage is untouched by the holder
+		_, err := stack.Issuer.Credentials.Retrieve(issued.Credential.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Only The Issuer Holds Signing Keys", func(t *testing.T) {
+		_, err := stack.Holder.CredentialService.IssueCredential(issuerSetup.DID.String(), holderDID, []vc.Claim{{Key: "ageOver18", Value: true}})
+		assert.Error(t, err)
+		_, err = stack.Verifier.CredentialService.IssueCredential(issuerSetup.DID.String(), holderDID, []vc.Claim{{Key: "ageOver18", Value: true}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Only The Verifier Keeps Presentations", func(t *testing.T) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{issued.Credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: issued.Credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: []string{"ageOver18"},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		stored, err := stack.Verifier.Presentations.List(vc.PresentationFilter{HolderDID: holderDID})
+		require.NoError(t, err)
+		assert.Len(t, stored, 1)
+
+		held, err := stack.Holder.Presentations.List(vc.PresentationFilter{HolderDID: holderDID})
+		require.NoError(t, err)
+		assert.Empty(t, held)
+	})
+}
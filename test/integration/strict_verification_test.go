@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/lint"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestStrictVerification tests strict mode reporting lint findings as warnings without affecting validity
+func TestStrictVerification(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "firstName", Value: "Alice"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	present := func(t *testing.T, nonce string, reveal []string, validFor time.Duration) *vc.VerifiablePresentation {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: reveal},
+			},
+			Nonce:    nonce,
+			ValidFor: validFor,
+		})
+		require.NoError(t, err)
+		return presentation
+	}
+
+	codes := func(findings []lint.Finding) map[lint.Code][]string {
+		byCode := make(map[lint.Code][]string)
+		for _, finding := range findings {
+			byCode[finding.Code] = append(byCode[finding.Code], finding.Path)
+		}
+		return byCode
+	}
+
+	t.Run("Findings Reported As Warnings", func(t *testing.T) {
+		presentation := present(t, "strict-nonce-0001", []string{"ageOver18", "firstName"}, 0)
+
+		// A pretty-printed presentation with a proof field the suite does not define
+		raw, err := json.MarshalIndent(presentation, "", "  ")
+		require.NoError(t, err)
+		var withExtra map[string]interface{}
+		require.NoError(t, json.Unmarshal(raw, &withExtra))
+		withExtra["proof"].(map[string]interface{})["challenge"] = "unbound"
+		raw, err = json.MarshalIndent(withExtra, "", "  ")
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "strict-nonce-0001",
+			Strict:            true,
+			RawPresentation:   raw,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "warnings do not affect validity: %v", result.Errors)
+
+		found := codes(result.Warnings)
+		assert.Equal(t, []string{"verifiableCredential[0].credentialSubject.firstName"}, found[lint.CodeExtraRevealedAttribute])
+		assert.ElementsMatch(t, []string{"proof", "verifiableCredential[0]"}, found[lint.CodeMissingExpiration])
+		assert.Equal(t, []string{"verificationNonce"}, found[lint.CodeWeakNonce])
+		assert.Equal(t, []string{"presentation"}, found[lint.CodeNonCanonicalJSON])
+		assert.Equal(t, []string{"proof.challenge"}, found[lint.CodeUnknownProofField])
+	})
+
+	t.Run("Hardened Presentation", func(t *testing.T) {
+		nonce := uuid.New().String()
+		presentation := present(t, nonce, []string{"ageOver18"}, 5*time.Minute)
+
+		raw, err := json.Marshal(presentation)
+		require.NoError(t, err)
+		raw, err = lint.Canonicalize(raw)
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: nonce,
+			Strict:            true,
+			RawPresentation:   raw,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		// Issued credentials carry no expirationDate, which strict mode still points out
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, lint.CodeMissingExpiration, result.Warnings[0].Code)
+		assert.Equal(t, "verifiableCredential[0]", result.Warnings[0].Path)
+	})
+
+	t.Run("Off By Default", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      present(t, "strict-nonce-0001", []string{"ageOver18", "firstName"}, 0),
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "strict-nonce-0001",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("Strict Mode For Every Request", func(t *testing.T) {
+		verifierUC.SetStrictMode(true)
+		defer verifierUC.SetStrictMode(false)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation: present(t, "", []string{"ageOver18"}, 0),
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+		assert.NotEmpty(t, codes(result.Warnings)[lint.CodeMissingExpiration])
+		assert.Empty(t, codes(result.Warnings)[lint.CodeWeakNonce], "the holder generated a random nonce")
+	})
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestThresholdAddenda tests issuers supplementing issued credentials with signed boolean threshold claims
+func TestThresholdAddenda(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	registry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(registry)
+	verifierUC.SetStatusRegistry(registry)
+	holderUC.SetAddendumSource(issuerUC)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+	holderDID := holderSetup.DID.String()
+
+	// A 20-year-old earning 72000
+	dateOfBirth := time.Now().AddDate(-20, 0, 0).Format("2006-01-02")
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerDID,
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "dateOfBirth", Value: dateOfBirth, Type: schema.ClaimTypeDate},
+			{Key: "salary", Value: 72000, Type: schema.ClaimTypeInteger},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	t.Run("Verifiers Register Thresholds", func(t *testing.T) {
+		_, err := issuerUC.RegisterThreshold(issuerDID, "did:example:bar", vc.ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17})
+		require.NoError(t, err)
+		_, err = issuerUC.RegisterThreshold(issuerDID, "did:example:bank", vc.ThresholdDefinition{Name: "salaryOver50k", Claim: "salary", Operator: vc.PredicateGreaterThan, Value: 50000})
+		require.NoError(t, err)
+		_, err = issuerUC.RegisterThreshold(issuerDID, "did:example:bank", vc.ThresholdDefinition{Name: "ageOver21", Claim: "dateOfBirth", MinAge: 21})
+		require.NoError(t, err)
+
+		// A second verifier needing the same threshold joins its requesters
+		registration, err := issuerUC.RegisterThreshold(issuerDID, "did:example:cinema", vc.ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 17})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"did:example:bar", "did:example:cinema"}, registration.RequestedBy)
+
+		_, err = issuerUC.RegisterThreshold(issuerDID, "did:example:bar", vc.ThresholdDefinition{Name: "ageOver17", Claim: "dateOfBirth", MinAge: 18})
+		assert.ErrorContains(t, err, "different definition")
+
+		thresholds, err := issuerUC.ListThresholds(issuerDID)
+		require.NoError(t, err)
+		require.Len(t, thresholds, 3)
+		assert.Equal(t, "ageOver17", thresholds[0].Definition.Name)
+	})
+
+	t.Run("Addendum Carries Evaluated Thresholds", func(t *testing.T) {
+		addendum, err := holderUC.RequestAddendum(holderDID, credential.ID, []string{"ageOver17", "ageOver21", "salaryOver50k"})
+		require.NoError(t, err)
+
+		assert.Contains(t, addendum.Type, vc.AddendumCredentialType)
+		assert.Equal(t, credential.ID, addendum.CredentialSubject[vc.AddendumToClaim])
+		assert.Equal(t, true, addendum.CredentialSubject["ageOver17"])
+		assert.Equal(t, false, addendum.CredentialSubject["ageOver21"])
+		assert.Equal(t, true, addendum.CredentialSubject["salaryOver50k"])
+		assert.NotContains(t, addendum.CredentialSubject, "dateOfBirth")
+		assert.Equal(t, credential.CredentialStatus, addendum.CredentialStatus)
+
+		// The holder presents the boolean alone to a verifier without predicate support
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{addendum.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: addendum.ID, RevealedAttributes: []string{"ageOver17", vc.AddendumToClaim}},
+			},
+			Nonce: "addendum-nonce-dd2416fa",
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver17"},
+			TrustedIssuers:    []string{issuerDID},
+			VerificationNonce: "addendum-nonce-dd2416fa",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Errors)
+		assert.Equal(t, credential.ID, result.RevealedClaims[vc.AddendumToClaim])
+
+		// Revoking the original revokes the addendum with it
+		_, err = issuerUC.RevokeCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+
+		result, err = verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver17"},
+			VerificationNonce: "addendum-nonce-dd2416fa",
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+
+		_, err = holderUC.RequestAddendum(holderDID, credential.ID, []string{"ageOver17"})
+		assert.ErrorContains(t, err, "revoked or suspended")
+	})
+
+	t.Run("Unregistered Threshold", func(t *testing.T) {
+		other, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: holderDID,
+			Claims:     []vc.Claim{{Key: "salary", Value: 40000, Type: schema.ClaimTypeInteger}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(other))
+
+		_, err = holderUC.RequestAddendum(holderDID, other.ID, []string{"netWorthOver1m"})
+		assert.ErrorContains(t, err, "not registered")
+
+		_, err = holderUC.RequestAddendum(holderDID, other.ID, []string{"ageOver17"})
+		assert.ErrorContains(t, err, "has no claim dateOfBirth")
+
+		_, err = holderUC.RequestAddendum("did:example:someone-else", other.ID, []string{"salaryOver50k"})
+		assert.ErrorContains(t, err, "does not belong")
+	})
+
+	t.Run("Tampered Original Rejected", func(t *testing.T) {
+		forged := *credential
+		forged.CredentialSubject = map[string]interface{}{"id": holderDID, "dateOfBirth": dateOfBirth, "salary": int64(500000)}
+		_, err := issuerUC.IssueAddendum(&forged, []string{"salaryOver50k"})
+		assert.ErrorContains(t, err, "verification failed")
+	})
+}
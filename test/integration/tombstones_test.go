@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/storage"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestTombstones tests that deleted credentials and deactivated DIDs are kept with who deleted
+// them, when and why, and reported as gone rather than as never having existed
+func TestTombstones(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{Store: storage.NewMemoryStore()})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims:     []vc.Claim{{Key: "licenseClass", Value: "B"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	deleteCredential := func(t *testing.T, id string) int {
+		query := url.Values{"deletedBy": {holderDID}, "reason": {"lost device"}}
+		req, err := http.NewRequest(http.MethodDelete, ts.URL+"/api/holder/credentials/"+url.PathEscape(id)+"?"+query.Encode(), nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Delete Credential", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, deleteCredential(t, credential.ID))
+		assert.Equal(t, http.StatusGone, deleteCredential(t, credential.ID))
+		assert.Equal(t, http.StatusNotFound, deleteCredential(t, "urn:uuid:unknown"))
+
+		_, err := stack.Holder.Credentials.Retrieve(credential.ID)
+		var deleted *vc.DeletedCredentialError
+		require.ErrorAs(t, err, &deleted)
+		assert.Equal(t, holderDID, deleted.Tombstone.DeletedBy)
+		assert.Equal(t, "lost device", deleted.Tombstone.Reason)
+
+		credentials, err := stack.Holder.ListCredentials(holderDID)
+		require.NoError(t, err)
+		assert.Empty(t, credentials)
+
+		_, err = stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+			Nonce: "tombstone-nonce-589f4a1a",
+		})
+		assert.ErrorIs(t, err, vc.ErrCredentialDeleted)
+	})
+
+	t.Run("Deleted Credential Cannot Be Stored Again", func(t *testing.T) {
+		body, err := json.Marshal(dto.StoreCredentialRequest{Credential: credential})
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+"/api/holder/credentials", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+	})
+
+	t.Run("Deactivated DID", func(t *testing.T) {
+		require.NoError(t, stack.DIDRepository.Deactivate(holderDID, storage.Tombstone{DeletedBy: holderDID, Reason: "wallet retired"}))
+
+		resp, err := http.Get(ts.URL + "/api/dids/" + holderDID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+		var errorResp dto.ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errorResp))
+		assert.Contains(t, errorResp.Details, "wallet retired")
+
+		_, err = stack.DIDService.ResolveDID(holderDID)
+		assert.ErrorIs(t, err, did.ErrDeactivated)
+
+		resp, err = http.Get(ts.URL + "/api/dids/did:example:unknown")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
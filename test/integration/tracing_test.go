@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/tracing"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestTracing tests that issuance, presentation and verification steps are recorded as spans of one trace
+func TestTracing(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	provider, err := tracing.NewProvider(tracing.ProviderConfig{
+		ServiceName: "integration",
+		Exporter:    exporter,
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+	tracing.SetProvider(provider)
+	t.Cleanup(func() {
+		tracing.SetProvider(nil)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	statusRegistry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(statusRegistry)
+	verifierUC.SetStatusRegistry(statusRegistry)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	spansByName := func(t *testing.T) map[string]tracing.SpanData {
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		byName := make(map[string]tracing.SpanData)
+		for _, span := range exporter.Spans() {
+			byName[span.Name] = span
+		}
+		return byName
+	}
+
+	t.Run("Use Case Spans", func(t *testing.T) {
+		exporter.Reset()
+		ctx, root := tracing.Start(context.Background(), "flow")
+
+		credential, err := issuerUC.IssueCredentialContext(ctx, issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims: []vc.Claim{
+				{Key: "firstName", Value: "Alice"},
+				{Key: "age", Value: 30},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+
+		presentation, err := holderUC.CreatePresentationContext(ctx, holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"age"}},
+			},
+			Nonce: "trace-nonce-a38db625",
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentationContext(ctx, verifier.VerificationRequest{
+			Presentation:      presentation,
+			VerificationNonce: "trace-nonce-a38db625",
+		})
+		require.NoError(t, err)
+		require.True(t, result.Valid, result.Errors)
+		root.End()
+
+		spans := spansByName(t)
+		rootID := root.SpanContext()
+		for _, name := range []string{
+			"issuer.IssueCredential", "bbs.Sign", "issuer.AssignStatus", "issuer.LogIssuance",
+			"holder.CreatePresentation", "vc.DeriveCredential", "holder.SignPresentation",
+			"verifier.VerifyPresentation", "verifier.VerifyHolderProof", "verifier.CheckStatus",
+			"verifier.VerifySelectiveDisclosureProof",
+		} {
+			span, ok := spans[name]
+			require.True(t, ok, "missing span %s", name)
+			assert.Equal(t, rootID.TraceID, span.SpanContext.TraceID, name)
+		}
+
+		issuance := spans["issuer.IssueCredential"]
+		assert.Equal(t, rootID.SpanID, issuance.ParentSpanID)
+		assert.Equal(t, issuance.SpanContext.SpanID, spans["bbs.Sign"].ParentSpanID)
+		// Two claims, the claim manifest and the credential metadata
+		assert.Contains(t, spans["bbs.Sign"].Attributes, tracing.Int("bbs.messages", 4))
+		assert.Equal(t, spans["holder.CreatePresentation"].SpanContext.SpanID, spans["vc.DeriveCredential"].ParentSpanID)
+		assert.Contains(t, spans["verifier.VerifyPresentation"].Attributes, tracing.Bool("verification.valid", true))
+	})
+
+	t.Run("Failures Are Recorded", func(t *testing.T) {
+		exporter.Reset()
+
+		_, err := issuerUC.IssueCredentialContext(context.Background(), issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+		})
+		require.Error(t, err)
+
+		span := spansByName(t)["issuer.IssueCredential"]
+		assert.Equal(t, tracing.StatusError, span.Status)
+		require.Len(t, span.Events, 1)
+		assert.Equal(t, "exception", span.Events[0].Name)
+	})
+
+	t.Run("HTTP Server Span Continues Caller Trace", func(t *testing.T) {
+		exporter.Reset()
+
+		server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/schemas/national-id/display", nil)
+		require.NoError(t, err)
+		req.Header.Set(tracing.TraceparentHeader, traceparent)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		span, ok := spansByName(t)["GET /api/schemas/{id}/display"]
+		require.True(t, ok, "server span should be named after the matched route")
+		assert.Equal(t, tracing.SpanKindServer, span.Kind)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID.String())
+		assert.Contains(t, span.Attributes, tracing.Int("http.status_code", http.StatusOK))
+		assert.Contains(t, span.Attributes, tracing.String("http.route", "/api/schemas/{id}/display"))
+	})
+}
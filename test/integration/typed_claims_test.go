@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/schema"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestTypedClaims tests coercing claim values to canonical typed forms at issuance
+func TestTypedClaims(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	issue := func(templateID string, claims ...vc.Claim) (*vc.VerifiableCredential, error) {
+		return issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     claims,
+			TemplateID: templateID,
+		})
+	}
+
+	t.Run("Template Types Coerce Values", func(t *testing.T) {
+		credential, err := issue("university-degree",
+			vc.Claim{Key: "degree", Value: "Bachelor of Science"},
+			vc.Claim{Key: "university", Value: "HCMUS"},
+			vc.Claim{Key: "graduationYear", Value: "2022"},
+			vc.Claim{Key: "gpa", Value: 3.80},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2022), credential.CredentialSubject["graduationYear"])
+		assert.Equal(t, "3.8", credential.CredentialSubject["gpa"])
+	})
+
+	t.Run("Explicit Types Without Template", func(t *testing.T) {
+		credential, err := issue("",
+			vc.Claim{Key: "age", Value: 25.0},
+			vc.Claim{Key: "memberSince", Value: "2019-03-01T10:00:00Z", Type: schema.ClaimTypeDate},
+			vc.Claim{Key: "verified", Value: "true", Type: schema.ClaimTypeBoolean},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(25), credential.CredentialSubject["age"])
+		assert.Equal(t, "2019-03-01", credential.CredentialSubject["memberSince"])
+		assert.Equal(t, true, credential.CredentialSubject["verified"])
+	})
+
+	t.Run("Invalid Values Are Rejected", func(t *testing.T) {
+		_, err := issue("university-degree",
+			vc.Claim{Key: "degree", Value: "Bachelor of Science"},
+			vc.Claim{Key: "university", Value: "HCMUS"},
+			vc.Claim{Key: "graduationYear", Value: "soon"},
+		)
+		assert.Error(t, err)
+
+		_, err = issue("university-degree",
+			vc.Claim{Key: "degree", Value: "Bachelor of Science"},
+			vc.Claim{Key: "university", Value: "HCMUS"},
+			vc.Claim{Key: "gpa", Value: "3.8", Type: schema.ClaimTypeString},
+		)
+		assert.Error(t, err)
+
+		_, err = issue("", vc.Claim{Key: "tags", Value: []string{"a", "b"}})
+		assert.Error(t, err)
+
+		_, err = issue("", vc.Claim{Key: "age", Value: 25}, vc.Claim{Key: "age", Value: 26})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestUnlinkability tests that presenting one credential twice yields proofs a verifier cannot
+// link, and that the proofs name only the revealed messages and answer for the hidden ones with
+// fresh responses
+func TestUnlinkability(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC := stack.Issuer.UseCase, stack.Holder.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderSetup.DID.String(),
+		Claims: []vc.Claim{
+			{Key: "fullName", Value: "An Nguyen"},
+			{Key: "ageOver18", Value: true},
+			{Key: "nationality", Value: "Vietnamese"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	derive := func(t *testing.T, nonce string) *bbs.Proof {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderSetup.DID.String(),
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: nonce,
+		})
+		require.NoError(t, err)
+		proof, err := bbs.DecodeProof(presentation.VerifiableCredential[0].Proof.ProofValue)
+		require.NoError(t, err)
+		return proof
+	}
+
+	first, second := derive(t, "cinema-verification-nonce-1"), derive(t, "pharmacy-verification-nonce-2")
+
+	t.Run("Blinded Components Differ", func(t *testing.T) {
+		for name, pair := range map[string][2][]byte{
+			"A'": {first.A_prime, second.A_prime},
+			"Ā":  {first.A_bar, second.A_bar},
+			"d":  {first.D, second.D},
+			"c":  {first.C, second.C},
+			"e":  {first.E, second.E},
+			"r2": {first.R2, second.R2},
+			"r3": {first.R3, second.R3},
+			"s":  {first.S, second.S},
+		} {
+			assert.False(t, bytes.Equal(pair[0], pair[1]), name)
+		}
+	})
+
+	t.Run("Only Revealed Indices Recorded", func(t *testing.T) {
+		assert.Equal(t, first.RevealedAttributes, second.RevealedAttributes)
+		assert.Len(t, first.RevealedAttributes, 2, "the revealed claim and the credential metadata")
+		// The claim manifest and the two undisclosed claims
+		require.Len(t, first.HiddenResponses, 3)
+		require.Len(t, second.HiddenResponses, 3)
+		for i := range first.HiddenResponses {
+			assert.False(t, bytes.Equal(first.HiddenResponses[i], second.HiddenResponses[i]), "hidden response %d", i)
+		}
+	})
+
+	t.Run("Runtime Check", func(t *testing.T) {
+		assert.NoError(t, bbs.UnlinkabilityTest(stack.BBSService))
+	})
+}
@@ -0,0 +1,188 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/clock"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/did"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/jsonld"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestDataModelVersions tests issuing, presenting and verifying credentials under both the 1.1
+// and 2.0 data models
+func TestDataModelVersions(t *testing.T) {
+	now := time.Date(2030, time.June, 1, 12, 0, 0, 0, time.UTC)
+	mock := clock.NewMock(now)
+
+	stack, err := sdk.NewStack(sdk.Config{Clock: mock})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	loader, err := jsonld.NewBundledLoader()
+	require.NoError(t, err)
+	validator := jsonld.NewValidator(loader)
+	holderUC.SetContextValidator(validator)
+	verifierUC.SetContextValidator(validator)
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+
+	issue := func(t *testing.T, version vc.Version, validUntil *time.Time, key string) *vc.VerifiableCredential {
+		credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: key, Value: true}},
+			Version:    version,
+			ValidUntil: validUntil,
+		})
+		require.NoError(t, err)
+		require.NoError(t, holderUC.StoreCredential(credential))
+		return credential
+	}
+
+	present := func(t *testing.T, credentials ...*vc.VerifiableCredential) *vc.VerifiablePresentation {
+		request := holder.PresentationRequest{HolderDID: holderSetup.DID.String()}
+		for _, credential := range credentials {
+			// Each credential carries a single claim besides the subject id, which is revealed
+			var revealed []string
+			for key := range credential.CredentialSubject {
+				if key != "id" {
+					revealed = append(revealed, key)
+				}
+			}
+
+			request.CredentialIDs = append(request.CredentialIDs, credential.ID)
+			request.SelectiveDisclosure = append(request.SelectiveDisclosure, vc.SelectiveDisclosureRequest{
+				CredentialID:       credential.ID,
+				RevealedAttributes: revealed,
+			})
+		}
+
+		presentation, err := holderUC.CreatePresentation(request)
+		require.NoError(t, err)
+		return presentation
+	}
+
+	verify := func(t *testing.T, presentation *vc.VerifiablePresentation, claims ...string) *verifier.VerificationResult {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:   presentation,
+			RequiredClaims: claims,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("2.0 Credential", func(t *testing.T) {
+		validUntil := now.AddDate(1, 0, 0)
+		credential := issue(t, vc.Version2, &validUntil, "ageOver18")
+
+		assert.Equal(t, jsonld.CredentialsV2URL, credential.Context[0])
+		assert.True(t, credential.IssuanceDate.IsZero())
+		require.NotNil(t, credential.ValidFrom)
+		assert.Equal(t, now, *credential.ValidFrom)
+		require.NotNil(t, credential.ValidUntil)
+		assert.Equal(t, validUntil, *credential.ValidUntil)
+
+		presentation := present(t, credential)
+		assert.Equal(t, jsonld.CredentialsV2URL, presentation.Context[0])
+
+		derived := presentation.VerifiableCredential[0]
+		assert.NotNil(t, derived.ValidFrom)
+		assert.NotNil(t, derived.ValidUntil)
+		assert.Nil(t, derived.IssuanceDate)
+
+		result := verify(t, presentation, "ageOver18")
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("1.1 Credential", func(t *testing.T) {
+		credential := issue(t, "", nil, "ageOver21")
+		assert.Equal(t, jsonld.CredentialsV1URL, credential.Context[0])
+		assert.Equal(t, now, credential.IssuanceDate)
+		assert.Nil(t, credential.ValidFrom)
+
+		presentation := present(t, credential)
+		assert.Equal(t, jsonld.CredentialsV1URL, presentation.Context[0])
+
+		result := verify(t, presentation, "ageOver21")
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("Mixed Versions Present Under 2.0", func(t *testing.T) {
+		v1 := issue(t, vc.Version1, nil, "isStudent")
+		v2 := issue(t, vc.Version2, nil, "isEmployed")
+
+		presentation := present(t, v1, v2)
+		assert.Equal(t, jsonld.CredentialsV2URL, presentation.Context[0])
+
+		result := verify(t, presentation, "isStudent", "isEmployed")
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("Expired 2.0 Credential", func(t *testing.T) {
+		mock.Set(now)
+		validUntil := now.Add(time.Hour)
+		credential := issue(t, vc.Version2, &validUntil, "hasLicense")
+		presentation := present(t, credential)
+
+		mock.Advance(2 * time.Hour)
+		result := verify(t, presentation, "hasLicense")
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "credential expired at")
+	})
+
+	t.Run("Extended Expiry Rejected", func(t *testing.T) {
+		mock.Set(now)
+		validUntil := now.Add(time.Hour)
+		credential := issue(t, vc.Version2, &validUntil, "hasPermit")
+		presentation := present(t, credential)
+
+		// The holder moves the expiry past the verifier's clock and signs the presentation again
+		extended := now.AddDate(1, 0, 0)
+		presentation.VerifiableCredential[0].ValidUntil = &extended
+		payload, err := vc.PresentationSigningInput(presentation)
+		require.NoError(t, err)
+		signature, err := stack.DIDService.SignWithDID(presentation.Proof.VerificationMethod, payload)
+		require.NoError(t, err)
+		presentation.Proof.ProofValue = did.EncodeSignatureMultibase(signature)
+
+		mock.Advance(2 * time.Hour)
+		result := verify(t, presentation, "hasPermit")
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Contains(t, result.Errors[0], "selective disclosure verification failed")
+	})
+
+	t.Run("2.0 Credential In A 1.1 Presentation", func(t *testing.T) {
+		mock.Set(now)
+		credential := issue(t, vc.Version2, nil, "isResident")
+		presentation := present(t, credential)
+		presentation.Context[0] = jsonld.CredentialsV1URL
+
+		result := verify(t, presentation, "isResident")
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("Unsupported Version", func(t *testing.T) {
+		_, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerSetup.DID.String(),
+			SubjectDID: holderSetup.DID.String(),
+			Claims:     []vc.Claim{{Key: "isVeteran", Value: true}},
+			Version:    "3.0",
+		})
+		assert.Error(t, err)
+	})
+}
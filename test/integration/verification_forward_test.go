@@ -0,0 +1,222 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/notify"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestVerificationForward tests that a forwarding session sends a signed assertion of its outcome
+// to the relying party and only an acknowledgement with a redirect to the holder's device
+func TestVerificationForward(t *testing.T) {
+	// The relying party below listens on loopback
+	stack, err := sdk.NewStack(sdk.Config{PrivateWebhooks: true})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	// The relying party's backend receives the assertions
+	assertions := make(chan vc.VerificationAssertion, 4)
+	relyingParty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var assertion vc.VerificationAssertion
+		if err := json.NewDecoder(r.Body).Decode(&assertion); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assertions <- assertion
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer relyingParty.Close()
+	callbackURL := relyingParty.URL + "/age-gate/callback"
+
+	issuerSetup, err := stack.Issuer.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	verifierSetup, err := stack.Verifier.SetupVerifier("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "ageOver18", Value: true},
+			{Key: "firstName", Value: "An"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	post := func(t *testing.T, path string, body interface{}, response interface{}) int {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode < 300 && response != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(response))
+		}
+		return resp.StatusCode
+	}
+	openSession := func(t *testing.T) dto.CreateSessionResponse {
+		var session dto.CreateSessionResponse
+		status := post(t, "/api/verifier/sessions", dto.CreateSessionRequest{
+			VerifierDID:    verifierSetup.DID.String(),
+			RequiredClaims: []string{"ageOver18"},
+			TrustedIssuers: []string{issuerSetup.DID.String()},
+			CallbackURL:    callbackURL,
+			RedirectURI:    "https://shop.example/checkout?order=42",
+		}, &session)
+		require.Equal(t, http.StatusCreated, status)
+		return session
+	}
+	submit := func(t *testing.T, sessionID, nonce string) map[string]interface{} {
+		presentation, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: nonce,
+		})
+		require.NoError(t, err)
+
+		var ack map[string]interface{}
+		status := post(t, "/api/verifier/sessions/"+sessionID+"/presentation", dto.SubmitSessionPresentationRequest{Presentation: presentation}, &ack)
+		require.Equal(t, http.StatusOK, status)
+		return ack
+	}
+	// Assertions are delivered in the background once the holder has been answered
+	receive := func(t *testing.T) vc.VerificationAssertion {
+		select {
+		case assertion := <-assertions:
+			return assertion
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no assertion was forwarded")
+			return vc.VerificationAssertion{}
+		}
+	}
+	forwarded := func(t *testing.T, sessionID string) *verifier.SessionForward {
+		var forward *verifier.SessionForward
+		require.Eventually(t, func() bool {
+			session, err := stack.Verifier.GetSession(sessionID)
+			if err != nil {
+				return false
+			}
+			forward = session.Forward
+			return forward.DeliveredAt != nil || forward.Error != ""
+		}, 5*time.Second, 10*time.Millisecond)
+		return forward
+	}
+	validate := func(t *testing.T, assertion vc.VerificationAssertion, audience string) dto.ValidateAssertionResponse {
+		var result dto.ValidateAssertionResponse
+		status := post(t, "/api/verifier/assertions/validate", dto.ValidateAssertionRequest{Assertion: &assertion, Audience: audience}, &result)
+		require.Equal(t, http.StatusOK, status)
+		return result
+	}
+
+	t.Run("Verified Outcome Forwarded", func(t *testing.T) {
+		session := openSession(t)
+		ack := submit(t, session.SessionID, session.Nonce)
+
+		// The holder's device learns where to go, not the verification details
+		assert.Equal(t, map[string]interface{}{
+			"state":       string(verifier.SessionCompleted),
+			"redirectUri": "https://shop.example/checkout?order=42",
+		}, ack)
+
+		assertion := receive(t)
+		assert.True(t, assertion.Valid)
+		assert.Equal(t, session.SessionID, assertion.SessionID)
+		assert.Equal(t, session.Nonce, assertion.Nonce)
+		assert.Equal(t, callbackURL, assertion.Audience)
+		assert.Equal(t, map[string]interface{}{"ageOver18": true}, assertion.Claims)
+
+		data, err := json.Marshal(assertion)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), holderDID, "the relying party does not learn the holder's DID")
+
+		result := validate(t, assertion, callbackURL)
+		assert.True(t, result.Valid, result.Error)
+
+		result = validate(t, assertion, "https://other.example/callback")
+		assert.False(t, result.Valid, "the assertion is bound to its relying party")
+
+		forged := assertion
+		forged.Claims = map[string]interface{}{"ageOver18": true, "ageOver21": true}
+		result = validate(t, forged, callbackURL)
+		assert.False(t, result.Valid)
+
+		forward := forwarded(t, session.SessionID)
+		assert.Equal(t, assertion.ID, forward.AssertionID)
+		assert.NotNil(t, forward.DeliveredAt)
+		assert.Empty(t, forward.Error)
+	})
+
+	t.Run("Rejected Outcome Forwarded Without Claims", func(t *testing.T) {
+		session := openSession(t)
+		ack := submit(t, session.SessionID, "stale-nonce-5f1c7a2e")
+		assert.Equal(t, string(verifier.SessionFailed), ack["state"])
+		assert.NotContains(t, ack, "valid")
+
+		assertion := receive(t)
+		assert.False(t, assertion.Valid)
+		assert.Empty(t, assertion.Claims)
+		assert.True(t, validate(t, assertion, callbackURL).Valid)
+	})
+
+	t.Run("Private Callbacks Refused By Default", func(t *testing.T) {
+		stack.Verifier.SetForwardNotifier(nil)
+		defer stack.Verifier.SetForwardNotifier(notify.NewWebhookNotifier(&http.Client{Timeout: notify.DefaultTimeout}))
+
+		session := openSession(t)
+		ack := submit(t, session.SessionID, session.Nonce)
+		assert.Equal(t, string(verifier.SessionCompleted), ack["state"], "the holder's outcome does not depend on the delivery")
+
+		forward := forwarded(t, session.SessionID)
+		assert.NotEmpty(t, forward.AssertionID)
+		assert.Nil(t, forward.DeliveredAt)
+		assert.Contains(t, forward.Error, notify.ErrPrivateAddress.Error())
+		assert.Empty(t, assertions, "the relying party on loopback is never contacted")
+	})
+
+	t.Run("Forwarding Sessions Checked", func(t *testing.T) {
+		status := post(t, "/api/verifier/sessions", dto.CreateSessionRequest{
+			RequiredClaims: []string{"ageOver18"},
+			CallbackURL:    callbackURL,
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status, "a verifier DID is needed to sign assertions")
+
+		status = post(t, "/api/verifier/sessions", dto.CreateSessionRequest{
+			VerifierDID:    verifierSetup.DID.String(),
+			RequiredClaims: []string{"ageOver18"},
+			CallbackURL:    "ftp://shop.example/callback",
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status = post(t, "/api/verifier/sessions", dto.CreateSessionRequest{
+			VerifierDID:    verifierSetup.DID.String(),
+			RequiredClaims: []string{"ageOver18"},
+			RedirectURI:    "https://shop.example/checkout",
+		}, nil)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestVerificationReceipts tests verifier-signed receipts for successful verifications
+func TestVerificationReceipts(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	bar, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+		HolderDID:     holderDID,
+		CredentialIDs: []string{credential.ID},
+		SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+			{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+		},
+		Nonce:       "receipt-nonce-9ee5e02b",
+		VerifierDID: bar.DID.String(),
+	})
+	require.NoError(t, err)
+
+	result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+		Presentation:      presentation,
+		RequiredClaims:    []string{"ageOver18"},
+		VerificationNonce: "receipt-nonce-9ee5e02b",
+		VerifierDID:       bar.DID.String(),
+	})
+	require.NoError(t, err)
+	require.True(t, result.Valid, "Errors: %v", result.Errors)
+	require.NotNil(t, result.Receipt)
+
+	receipt := result.Receipt
+
+	t.Run("Receipt Contents", func(t *testing.T) {
+		assert.Equal(t, bar.DID.String(), receipt.VerifierDID)
+		assert.Equal(t, holderDID, receipt.HolderDID)
+		assert.Equal(t, presentation.ID, receipt.PresentationID)
+		assert.True(t, receipt.Outcome.Valid)
+		assert.Equal(t, []string{"ageOver18"}, receipt.Outcome.RevealedClaims)
+
+		hash, err := vc.PresentationHash(presentation)
+		require.NoError(t, err)
+		assert.Equal(t, hash, receipt.PresentationHash)
+	})
+
+	t.Run("Retrieve And Validate", func(t *testing.T) {
+		stored, err := verifierUC.GetReceipt(receipt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, receipt.ID, stored.ID)
+		assert.True(t, receipt.Timestamp.Equal(stored.Timestamp))
+		assert.Equal(t, receipt.Proof.ProofValue, stored.Proof.ProofValue)
+
+		assert.NoError(t, verifierUC.ValidateReceipt(receipt, presentation))
+		assert.NoError(t, verifierUC.ValidateReceipt(stored, presentation))
+	})
+
+	t.Run("Validates After JSON Roundtrip", func(t *testing.T) {
+		data, err := json.Marshal(receipt)
+		require.NoError(t, err)
+
+		var decoded vc.VerificationReceipt
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.NoError(t, verifierUC.ValidateReceipt(&decoded, nil))
+	})
+
+	t.Run("Tampered Receipt Rejected", func(t *testing.T) {
+		tampered := *receipt
+		tampered.Outcome.Valid = false
+		assert.Error(t, verifierUC.ValidateReceipt(&tampered, nil))
+	})
+
+	t.Run("Receipt For Other Presentation Rejected", func(t *testing.T) {
+		other := *presentation
+		other.ID = "other-presentation"
+		err := verifierUC.ValidateReceipt(receipt, &other)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match")
+	})
+
+	t.Run("Holder Stores Receipt With Consent Record", func(t *testing.T) {
+		record, err := holderUC.StoreReceipt(holderDID, receipt)
+		require.NoError(t, err)
+		assert.Equal(t, presentation.ID, record.PresentationID)
+
+		records, err := holderUC.ListConsentRecords(holderDID)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		// Records are stored as JSON, so compare the receipts in that form
+		expected, err := json.Marshal(receipt)
+		require.NoError(t, err)
+		actual, err := json.Marshal(records[0].Receipt)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(actual))
+	})
+
+	t.Run("No Receipt Without Verifier DID", func(t *testing.T) {
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "receipt-nonce-9ee5e02b",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Nil(t, result.Receipt)
+	})
+
+	t.Run("Unknown Receipt", func(t *testing.T) {
+		_, err := verifierUC.GetReceipt("urn:uuid:unknown")
+		assert.Error(t, err)
+	})
+}
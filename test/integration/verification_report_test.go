@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestVerificationReports tests the verifier-signed reports archived for each verification
+func TestVerificationReports(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	issuerDID := issuerSetup.DID.String()
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	bank, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+	bankDID := bank.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerDID,
+		SubjectDID: holderDID,
+		Claims:     []vc.Claim{{Key: "ageOver18", Value: true}, {Key: "nationality", Value: "US"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	verify := func(t *testing.T, req verifier.VerificationRequest) (*vc.VerifiablePresentation, *verifier.VerificationResult) {
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			VerifierDID: bankDID,
+		})
+		require.NoError(t, err)
+
+		req.Presentation = presentation
+		result, err := verifierUC.VerifyPresentation(req)
+		require.NoError(t, err)
+		return presentation, result
+	}
+
+	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	getReport := func(t *testing.T, id string) (int, *vc.VerificationReport) {
+		resp, err := http.Get(ts.URL + "/api/verifier/results/" + id)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+
+		var report vc.VerificationReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		return resp.StatusCode, &report
+	}
+
+	t.Run("Accepted Presentation", func(t *testing.T) {
+		presentation, result := verify(t, verifier.VerificationRequest{
+			RequiredClaims: []string{"ageOver18"},
+			TrustedIssuers: []string{issuerDID},
+			VerifierDID:    bankDID,
+			RequestMetadata: vc.RequestMetadata{
+				Purpose: "Account opening",
+			},
+		})
+		require.True(t, result.Valid, "errors: %v", result.Errors)
+		require.NotEmpty(t, result.ReportID)
+
+		code, report := getReport(t, result.ReportID)
+		require.Equal(t, http.StatusOK, code)
+
+		assert.Equal(t, result.ReportID, report.ID)
+		assert.Equal(t, bankDID, report.VerifierDID)
+		assert.Equal(t, holderDID, report.HolderDID)
+		assert.Equal(t, presentation.ID, report.PresentationID)
+		hash, err := vc.PresentationHash(presentation)
+		require.NoError(t, err)
+		assert.Equal(t, hash, report.PresentationHash)
+		require.NotNil(t, report.PresentedAt)
+		assert.False(t, report.VerifiedAt.IsZero())
+
+		assert.Equal(t, []string{"ageOver18"}, report.Policy.RequiredClaims)
+		assert.Equal(t, []string{issuerDID}, report.Policy.TrustedIssuers)
+		assert.Equal(t, "Account opening", report.Policy.Purpose)
+		assert.True(t, report.Valid)
+		assert.Equal(t, map[string]interface{}{"ageOver18": true}, report.RevealedClaims)
+
+		require.Len(t, report.Issuers, 1)
+		assert.Equal(t, issuerDID, report.Issuers[0].IssuerDID)
+		assert.Equal(t, credential.ID, report.Issuers[0].CredentialID)
+		assert.Equal(t, credential.Proof.VerificationMethod, report.Issuers[0].VerificationMethod)
+		assert.Contains(t, report.Issuers[0].CredentialTypes, "VerifiableCredential")
+
+		// The archived report carries the verifier's signature
+		require.NoError(t, verifierUC.ValidateReport(report))
+
+		tampered := *report
+		tampered.Valid = false
+		assert.Error(t, verifierUC.ValidateReport(&tampered))
+	})
+
+	t.Run("Rejected Presentation", func(t *testing.T) {
+		_, result := verify(t, verifier.VerificationRequest{
+			RequiredClaims: []string{"nationality"},
+			VerifierDID:    bankDID,
+		})
+		require.False(t, result.Valid)
+		require.NotEmpty(t, result.ReportID)
+
+		code, report := getReport(t, result.ReportID)
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, report.Valid)
+		assert.Equal(t, result.Errors, report.Errors)
+		require.NoError(t, verifierUC.ValidateReport(report))
+	})
+
+	t.Run("No Report Without Verifier", func(t *testing.T) {
+		_, result := verify(t, verifier.VerificationRequest{
+			RequiredClaims: []string{"ageOver18"},
+		})
+		require.True(t, result.Valid, "errors: %v", result.Errors)
+		assert.Empty(t, result.ReportID)
+	})
+
+	t.Run("Unknown Report", func(t *testing.T) {
+		code, _ := getReport(t, "urn:uuid:unknown")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestVerifierAccreditation tests that a holder with an accreditation policy discloses data only
+// to verifiers presenting a valid accreditation from a trusted accreditor
+func TestVerifierAccreditation(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	server, err := httpServer.NewStackServer(stack, "0")
+	require.NoError(t, err)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	post := func(t *testing.T, path string, status int, body interface{}) {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		resp, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+	}
+
+	setupIssuer := func() string {
+		s, err := stack.Issuer.SetupIssuer("example")
+		require.NoError(t, err)
+		return s.DID.String()
+	}
+	regulator, idIssuer := setupIssuer(), setupIssuer()
+
+	holderSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+	shopSetup, err := stack.Holder.SetupHolder("example")
+	require.NoError(t, err)
+	shop := shopSetup.DID.String()
+
+	accredit := func(t *testing.T, issuerDID, verifierDID, accreditation string) *vc.VerifiableCredential {
+		credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+			IssuerDID:  issuerDID,
+			SubjectDID: verifierDID,
+			Claims:     []vc.Claim{{Key: vc.AccreditationClaim, Value: accreditation}},
+			TemplateID: vc.VerifierAccreditationTemplateID,
+		})
+		require.NoError(t, err)
+		return credential
+	}
+	accreditation := accredit(t, regulator, shop, "licensed-alcohol-retailer")
+
+	credential, err := stack.Issuer.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  idIssuer,
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "firstName", Value: "An"},
+			{Key: "dateOfBirth", Value: "1990-01-01"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, stack.Holder.StoreCredential(credential))
+
+	post(t, "/api/holder/accreditation-policy", http.StatusOK, dto.AccreditationPolicyRequest{
+		HolderDID: holderDID,
+		Policy: &vc.AccreditationPolicy{
+			TrustedAccreditors: []string{regulator},
+			Accreditations:     []string{"licensed-alcohol-retailer"},
+			Claims:             []string{"dateOfBirth"},
+		},
+	})
+
+	present := func(claim string, accreditation *vc.VerifiableCredential) error {
+		_, err := stack.Holder.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{claim}},
+			},
+			VerifierDID:     shop,
+			RequestMetadata: vc.RequestMetadata{Purpose: "age check", Accreditation: accreditation},
+		})
+		return err
+	}
+
+	t.Run("Accredited Verifier Receives Data", func(t *testing.T) {
+		require.NoError(t, present("dateOfBirth", accreditation))
+	})
+
+	t.Run("Unaccredited Verifier Is Refused", func(t *testing.T) {
+		assert.ErrorIs(t, present("dateOfBirth", nil), holder.ErrVerifierNotAccredited)
+
+		post(t, "/api/holder/presentations", http.StatusForbidden, dto.CreatePresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []dto.SelectiveDisclosureRequestDTO{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"dateOfBirth"}},
+			},
+			VerifierDID: shop,
+		})
+	})
+
+	t.Run("Claims Outside The Policy Need No Accreditation", func(t *testing.T) {
+		assert.NoError(t, present("firstName", nil))
+	})
+
+	t.Run("Accreditation Must Be The Verifier's", func(t *testing.T) {
+		other, err := stack.Holder.SetupHolder("example")
+		require.NoError(t, err)
+		borrowed := accredit(t, regulator, other.DID.String(), "licensed-alcohol-retailer")
+		assert.ErrorIs(t, present("dateOfBirth", borrowed), holder.ErrVerifierNotAccredited)
+	})
+
+	t.Run("Accreditor And Accreditation Must Be Accepted", func(t *testing.T) {
+		selfIssued := accredit(t, setupIssuer(), shop, "licensed-alcohol-retailer")
+		assert.ErrorIs(t, present("dateOfBirth", selfIssued), holder.ErrVerifierNotAccredited)
+
+		pharmacy := accredit(t, regulator, shop, "pharmacy")
+		assert.ErrorIs(t, present("dateOfBirth", pharmacy), holder.ErrVerifierNotAccredited)
+	})
+
+	t.Run("Revoked Accreditation Is Refused", func(t *testing.T) {
+		_, err := stack.Issuer.RevokeCredential(accreditation.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+
+		err = present("dateOfBirth", accreditation)
+		assert.ErrorIs(t, err, holder.ErrVerifierNotAccredited)
+		assert.ErrorContains(t, err, "revoked")
+	})
+}
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/verifier"
+)
+
+// TestWalletBackupRestore tests exporting a wallet and restoring it into a fresh one
+func TestWalletBackupRestore(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	verifierSetup, err := verifierUC.SetupVerifier("example")
+	require.NoError(t, err)
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims: []vc.Claim{
+			{Key: "firstName", Value: "An"},
+			{Key: "ageOver18", Value: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	pairwise, err := holderUC.GetOrCreatePairwiseDID(holderDID, verifierSetup.DID.String())
+	require.NoError(t, err)
+
+	const password = "correct-horse-battery"
+	archive, err := holderUC.ExportBackup(holderDID, password)
+	require.NoError(t, err)
+	assert.Equal(t, holder.BackupFormat, archive.Format)
+	assert.NotContains(t, string(archive.Ciphertext), "ageOver18")
+
+	// The wallet restores on a fresh device: empty stores, shared DID registry
+	fresh, err := sdk.NewStack(sdk.Config{DIDRepository: stack.DIDRepository})
+	require.NoError(t, err)
+	freshHolderUC := fresh.Holder.UseCase
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		_, err := freshHolderUC.RestoreBackup(archive, "not-the-password")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "wrong password")
+	})
+
+	t.Run("Tampered Header", func(t *testing.T) {
+		tampered := *archive
+		tampered.KDF.Iterations = archive.KDF.Iterations + 1
+		_, err := freshHolderUC.RestoreBackup(&tampered, password)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported Version", func(t *testing.T) {
+		future := *archive
+		future.Version = holder.BackupVersion + 1
+		_, err := freshHolderUC.RestoreBackup(&future, password)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported backup version")
+	})
+
+	t.Run("Weak Password Rejected", func(t *testing.T) {
+		_, err := holderUC.ExportBackup(holderDID, "short")
+		assert.Error(t, err)
+	})
+
+	t.Run("Restore After JSON Roundtrip", func(t *testing.T) {
+		data, err := json.Marshal(archive)
+		require.NoError(t, err)
+
+		var decoded holder.BackupArchive
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		result, err := freshHolderUC.RestoreBackup(&decoded, password)
+		require.NoError(t, err)
+		assert.Equal(t, holderDID, result.HolderDID)
+		assert.Equal(t, 1, result.Credentials)
+		assert.Equal(t, 1, result.PairwiseDIDs)
+
+		restored, err := freshHolderUC.ListCredentials(holderDID)
+		require.NoError(t, err)
+		require.Len(t, restored, 1)
+		assert.Equal(t, credential.ID, restored[0].ID)
+
+		restoredPairwise, err := freshHolderUC.GetOrCreatePairwiseDID(holderDID, verifierSetup.DID.String())
+		require.NoError(t, err)
+		assert.Equal(t, pairwise.DID.String(), restoredPairwise.DID.String())
+	})
+
+	t.Run("Restored Keys Sign Presentations", func(t *testing.T) {
+		presentation, err := freshHolderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"ageOver18"}},
+			},
+			Nonce: "restore-nonce-8b62010d",
+		})
+		require.NoError(t, err)
+
+		result, err := verifierUC.VerifyPresentation(verifier.VerificationRequest{
+			Presentation:      presentation,
+			RequiredClaims:    []string{"ageOver18"},
+			VerificationNonce: "restore-nonce-8b62010d",
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "Errors: %v", result.Errors)
+	})
+
+	t.Run("Restored Keys Decrypt", func(t *testing.T) {
+		envelope, err := issuerUC.EncryptCredentialForHolder(credential)
+		require.NoError(t, err)
+
+		received, err := freshHolderUC.ReceiveEncryptedCredential(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, credential.ID, received.ID)
+	})
+
+	t.Run("Restore Into Separate Registry", func(t *testing.T) {
+		isolated, err := sdk.NewStack(sdk.Config{})
+		require.NoError(t, err)
+
+		_, err = isolated.Holder.RestoreBackup(archive, password)
+		require.NoError(t, err)
+
+		doc, err := isolated.DIDService.ResolveDID(holderDID)
+		require.NoError(t, err)
+		assert.Equal(t, holderDID, doc.ID)
+	})
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpServer "github.com/lugondev/bbs-selective-disclosure-example/interfaces/http"
+	"github.com/lugondev/bbs-selective-disclosure-example/interfaces/http/dto"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/bbs"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/holder"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/issuer"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/sdk"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/status"
+	"github.com/lugondev/bbs-selective-disclosure-example/pkg/vc"
+)
+
+// TestWalletStatusRefresh tests that the background refresher keeps the wallet's status snapshots
+// current and that listing credentials marks them with their latest known status
+func TestWalletStatusRefresh(t *testing.T) {
+	stack, err := sdk.NewStack(sdk.Config{})
+	require.NoError(t, err)
+	issuerUC, holderUC, verifierUC := stack.Issuer.UseCase, stack.Holder.UseCase, stack.Verifier.UseCase
+	statusRegistry := status.NewInMemoryRegistry()
+	issuerUC.SetStatusRegistry(statusRegistry)
+	verifierUC.SetStatusRegistry(statusRegistry)
+	holderUC.SetStatusSnapshotSource(issuerUC)
+
+	server := httpServer.NewServer(issuerUC, holderUC, verifierUC, bbs.NewFactory(), "0")
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	issuerSetup, err := issuerUC.SetupIssuer("example")
+	require.NoError(t, err)
+	holderSetup, err := holderUC.SetupHolder("example")
+	require.NoError(t, err)
+	holderDID := holderSetup.DID.String()
+
+	credential, err := issuerUC.IssueCredential(issuer.IssueCredentialRequest{
+		IssuerDID:  issuerSetup.DID.String(),
+		SubjectDID: holderDID,
+		Claims:     []vc.Claim{{Key: "licenseClass", Value: "B"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, holderUC.StoreCredential(credential))
+
+	listStatus := func(t *testing.T) dto.CredentialStatusDTO {
+		resp, err := http.Get(ts.URL + "/api/holder/credentials/list?holderDid=" + holderDID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var listed dto.ListCredentialsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+		require.Contains(t, listed.Statuses, credential.ID)
+		return listed.Statuses[credential.ID]
+	}
+
+	awaitState := func(t *testing.T, state holder.CredentialState) {
+		require.Eventually(t, func() bool {
+			statuses, err := holderUC.CredentialStatuses(holderDID)
+			return err == nil && statuses[credential.ID] != nil && statuses[credential.ID].State == state
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	t.Run("Unchecked Before The First Refresh", func(t *testing.T) {
+		listed := listStatus(t)
+		assert.Equal(t, string(holder.CredentialUnchecked), listed.State)
+		assert.Nil(t, listed.CheckedAt)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go holderUC.RunStatusRefresher(ctx, 20*time.Millisecond)
+
+	t.Run("Refresher Picks Up Status Changes", func(t *testing.T) {
+		awaitState(t, holder.CredentialActive)
+		listed := listStatus(t)
+		assert.Equal(t, string(holder.CredentialActive), listed.State)
+		require.NotNil(t, listed.CheckedAt)
+
+		_, err := issuerUC.SuspendCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		awaitState(t, holder.CredentialSuspended)
+
+		_, err = issuerUC.RevokeCredential(credential.ID, issuer.StatusChange{})
+		require.NoError(t, err)
+		awaitState(t, holder.CredentialRevoked)
+		assert.Equal(t, string(holder.CredentialRevoked), listStatus(t).State)
+	})
+
+	t.Run("Presentations Carry The Cached Status", func(t *testing.T) {
+		cancel()
+		presentation, err := holderUC.CreatePresentation(holder.PresentationRequest{
+			HolderDID:     holderDID,
+			CredentialIDs: []string{credential.ID},
+			SelectiveDisclosure: []vc.SelectiveDisclosureRequest{
+				{CredentialID: credential.ID, RevealedAttributes: []string{"licenseClass"}},
+			},
+			IncludeStatusSnapshots: true,
+		})
+		require.NoError(t, err)
+		revoked := false
+		for _, snapshot := range presentation.StatusSnapshots {
+			if snapshot.StatusPurpose == status.PurposeRevocation {
+				revoked = snapshot.Status
+			}
+		}
+		assert.True(t, revoked)
+	})
+}
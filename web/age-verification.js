@@ -164,6 +164,19 @@ async function issueEnhancedCredential() {
 
         const data = await response.json();
         issuedCredentialID = data.credential.id;
+
+        // Deliver the credential to the citizen's wallet
+        const storeResponse = await fetch(`${API_BASE}/holder/credentials`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ credential: data.credential })
+        });
+
+        if (!storeResponse.ok) {
+            throw new Error(`HTTP error storing credential! status: ${storeResponse.status}`);
+        }
         
         // Auto-fill credential ID for verification
         document.getElementById('verification-credential-id').value = issuedCredentialID;
@@ -205,20 +218,53 @@ async function verifyAge() {
 
         log(`Service: ${serviceType}, Required age: ${minAge}+`, 'info');
         
-        const requestData = {
-            holderDid: holderDid,
-            credentialId: credentialId,
-            minAge: minAge,
-            serviceType: serviceType,
-            requiredClaims: ['nationality', 'documentType']
-        };
+        // The service (verifier) opens an age check and hands its proof request to the wallet
+        const requestResponse = await fetch(`${API_BASE}/age-verification/requests`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({
+                minAge: minAge,
+                serviceType: serviceType,
+                trustedIssuers: governmentDID ? [governmentDID] : []
+            })
+        });
+
+        if (!requestResponse.ok) {
+            throw new Error(`HTTP error opening age check! status: ${requestResponse.status}`);
+        }
+
+        const ageRequest = await requestResponse.json();
+        log(`  Age check opened (session ${ageRequest.sessionId})`, 'info');
 
-        const response = await fetch(`${API_BASE}/age-verification/verify`, {
+        // The citizen's wallet answers the request with a selective disclosure presentation
+        const presentationResponse = await fetch(`${API_BASE}/age-verification/presentations`, {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json',
             },
-            body: JSON.stringify(requestData)
+            body: JSON.stringify({
+                holderDid: holderDid,
+                credentialId: credentialId,
+                request: ageRequest.request
+            })
+        });
+
+        if (!presentationResponse.ok) {
+            throw new Error(`HTTP error creating presentation! status: ${presentationResponse.status}`);
+        }
+
+        const answer = await presentationResponse.json();
+        log(`  Wallet reveals: ${answer.revealedClaims.join(', ')}`, 'info');
+
+        // The service verifies the presentation against its own session
+        const response = await fetch(ageRequest.verifyUri, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ presentation: answer.presentation })
         });
 
         if (!response.ok) {
@@ -101,6 +101,16 @@ async function apiCall(endpoint, options = {}) {
     }
 }
 
+// fetchVerifierNonce gets an unpredictable nonce from the verifier, so presentations cannot be
+// prepared ahead of the request or replayed against it
+async function fetchVerifierNonce() {
+    const response = await apiCall('/api/verifier/nonce');
+    if (!response.nonce) {
+        throw new Error('Verifier returned no nonce');
+    }
+    return response.nonce;
+}
+
 // BBS Provider functions
 function updateProviderInfo() {
     const provider = document.getElementById('bbs-provider').value;
@@ -347,9 +357,9 @@ async function createPresentation() {
         log(`Creating selective disclosure presentation with ${PROVIDER_INFO[provider].name}...`, 'info');
         updateFlowStep(3);
         
-        // Generate verification nonce for this presentation
-        verificationNonce = `cinema-verification-${Date.now()}`;
-        log(`🎲 Generated verification nonce: ${verificationNonce}`, 'info');
+        // Ask the verifier for a fresh nonce to bind this presentation to
+        verificationNonce = await fetchVerifierNonce();
+        log(`🎲 Verifier issued nonce: ${verificationNonce}`, 'info');
         
         // Get revealed attributes based on checkboxes
         const revealedAttributes = [];
@@ -443,7 +453,7 @@ async function verifyPresentation() {
         const trustedIssuers = document.getElementById('trusted-issuers').value.split(',').map(s => s.trim()).filter(s => s);
         
         // Use the same nonce that was used during presentation creation
-        const nonceToUse = verificationNonce || `cinema-verification-${Date.now()}`;
+        const nonceToUse = verificationNonce || await fetchVerifierNonce();
         log(`🔍 Using verification nonce: ${nonceToUse}`, 'info');
         
         const response = await apiCall('/api/verifier/verify', {